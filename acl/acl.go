@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	iradix "github.com/hashicorp/go-immutable-radix"
+	glob "github.com/ryanuber/go-glob"
 )
 
 // ManagementACL is a singleton used for management tokens
@@ -44,6 +45,9 @@ type ACL struct {
 	// namespaces maps a namespace to a capabilitySet
 	namespaces *iradix.Tree
 
+	// variables maps a namespace to the path policies of its variables
+	variables map[string][]*VariablesPathPolicy
+
 	agent    string
 	node     string
 	operator string
@@ -73,12 +77,26 @@ func NewACL(management bool, policies []*Policy) (*ACL, error) {
 	}
 
 	// Create the ACL object
-	acl := &ACL{}
+	acl := &ACL{
+		variables: make(map[string][]*VariablesPathPolicy),
+	}
 	nsTxn := iradix.New().Txn()
 
 	for _, policy := range policies {
 	NAMESPACES:
 		for _, ns := range policy.Namespaces {
+			// Collect the variables path policies, the short hand policy
+			// applying to the variables at all paths
+			if ns.Variables != nil {
+				acl.variables[ns.Name] = append(acl.variables[ns.Name], ns.Variables.Paths...)
+			}
+			if ns.Policy != "" {
+				acl.variables[ns.Name] = append(acl.variables[ns.Name], &VariablesPathPolicy{
+					PathSpec:     "*",
+					Capabilities: expandVariablesPolicy(ns.Policy),
+				})
+			}
+
 			// Check for existing capabilities
 			var capabilities capabilitySet
 			raw, ok := nsTxn.Get([]byte(ns.Name))
@@ -171,6 +189,59 @@ func (a *ACL) AllowNamespace(ns string) bool {
 	return !capabilities.Check(PolicyDeny)
 }
 
+// AllowVariableOperation checks if a given operation is allowed on the
+// variable at the given path of a namespace
+func (a *ACL) AllowVariableOperation(ns, path, op string) bool {
+	// Hot path management tokens
+	if a.management {
+		return true
+	}
+
+	// A denied namespace denies all its variables
+	if raw, ok := a.namespaces.Get([]byte(ns)); ok && raw.(capabilitySet).Check(NamespaceCapabilityDeny) {
+		return false
+	}
+
+	// Deny on any matching path takes precedence
+	allowed := false
+	for _, p := range a.variables[ns] {
+		if !glob.Glob(p.PathSpec, path) {
+			continue
+		}
+		for _, cap := range p.Capabilities {
+			switch cap {
+			case VariablesCapabilityDeny:
+				return false
+			case op:
+				allowed = true
+			}
+		}
+	}
+	return allowed
+}
+
+// AllowVariableSearch checks if variables of a namespace may be listed. The
+// variables listed must still be filtered by AllowVariableOperation.
+func (a *ACL) AllowVariableSearch(ns string) bool {
+	// Hot path management tokens
+	if a.management {
+		return true
+	}
+
+	if raw, ok := a.namespaces.Get([]byte(ns)); ok && raw.(capabilitySet).Check(NamespaceCapabilityDeny) {
+		return false
+	}
+
+	for _, p := range a.variables[ns] {
+		for _, cap := range p.Capabilities {
+			if cap == VariablesCapabilityList {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // AllowAgentRead checks if read operations are allowed for an agent
 func (a *ACL) AllowAgentRead() bool {
 	switch {
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCapabilitySet(t *testing.T) {
@@ -262,3 +263,57 @@ func TestAllowNamespace(t *testing.T) {
 		})
 	}
 }
+
+func TestAllowVariableOperation(t *testing.T) {
+	policy := `
+namespace "default" {
+  variables {
+    path "project/*" {
+      capabilities = ["list", "read", "write"]
+    }
+    path "project/secret" {
+      capabilities = ["deny"]
+    }
+  }
+}
+namespace "other" {
+  policy = "read"
+}
+namespace "denied" {
+  policy = "deny"
+}
+`
+	tests := []struct {
+		Namespace string
+		Path      string
+		Op        string
+		Allow     bool
+	}{
+		{"default", "project/web", VariablesCapabilityRead, true},
+		{"default", "project/web", VariablesCapabilityWrite, true},
+		{"default", "project/web", VariablesCapabilityDestroy, false},
+		{"default", "project/secret", VariablesCapabilityRead, false},
+		{"default", "other/web", VariablesCapabilityRead, false},
+		{"other", "project/web", VariablesCapabilityRead, true},
+		{"other", "project/web", VariablesCapabilityWrite, false},
+		{"denied", "project/web", VariablesCapabilityRead, false},
+		{"unknown", "project/web", VariablesCapabilityRead, false},
+	}
+
+	p, err := Parse(policy)
+	require.NoError(t, err)
+	acl, err := NewACL(false, []*Policy{p})
+	require.NoError(t, err)
+
+	for _, tc := range tests {
+		t.Run(tc.Namespace+"/"+tc.Path+"/"+tc.Op, func(t *testing.T) {
+			require.Equal(t, tc.Allow, acl.AllowVariableOperation(tc.Namespace, tc.Path, tc.Op))
+		})
+	}
+
+	require.True(t, acl.AllowVariableSearch("default"))
+	require.True(t, acl.AllowVariableSearch("other"))
+	require.False(t, acl.AllowVariableSearch("denied"))
+	require.False(t, acl.AllowVariableSearch("unknown"))
+	require.True(t, ManagementACL.AllowVariableOperation("default", "project/secret", VariablesCapabilityDestroy))
+}
@@ -25,12 +25,30 @@ const (
 	NamespaceCapabilityListJobs         = "list-jobs"
 	NamespaceCapabilityReadJob          = "read-job"
 	NamespaceCapabilitySubmitJob        = "submit-job"
+	NamespaceCapabilityScaleJob         = "scale-job"
 	NamespaceCapabilityDispatchJob      = "dispatch-job"
 	NamespaceCapabilityReadLogs         = "read-logs"
 	NamespaceCapabilityReadFS           = "read-fs"
+	NamespaceCapabilityAllocExec        = "alloc-exec"
+	NamespaceCapabilityAllocNodeExec    = "alloc-node-exec"
+	NamespaceCapabilityAllocLifecycle   = "alloc-lifecycle"
+	NamespaceCapabilityReadVolume       = "read-volume"
+	NamespaceCapabilityWriteVolume      = "write-volume"
 	NamespaceCapabilitySentinelOverride = "sentinel-override"
 )
 
+const (
+	// The following are the capabilities that can be granted on the
+	// variables below a path of a namespace. If the deny capability is
+	// present on a matching path, it takes precedence and denies all
+	// operations.
+	VariablesCapabilityList    = "list"
+	VariablesCapabilityRead    = "read"
+	VariablesCapabilityWrite   = "write"
+	VariablesCapabilityDestroy = "destroy"
+	VariablesCapabilityDeny    = "deny"
+)
+
 var (
 	validNamespace = regexp.MustCompile("^[a-zA-Z0-9-]{1,128}$")
 )
@@ -60,6 +78,19 @@ type NamespacePolicy struct {
 	Name         string `hcl:",key"`
 	Policy       string
 	Capabilities []string
+	Variables    *VariablesPolicy `hcl:"variables"`
+}
+
+// VariablesPolicy is the policy for the variables of a namespace
+type VariablesPolicy struct {
+	Paths []*VariablesPathPolicy `hcl:"path,expand"`
+}
+
+// VariablesPathPolicy grants capabilities on the variables whose path
+// matches a glob pattern
+type VariablesPathPolicy struct {
+	PathSpec     string `hcl:",key"`
+	Capabilities []string
 }
 
 type AgentPolicy struct {
@@ -92,8 +123,9 @@ func isPolicyValid(policy string) bool {
 func isNamespaceCapabilityValid(cap string) bool {
 	switch cap {
 	case NamespaceCapabilityDeny, NamespaceCapabilityListJobs, NamespaceCapabilityReadJob,
-		NamespaceCapabilitySubmitJob, NamespaceCapabilityDispatchJob, NamespaceCapabilityReadLogs,
-		NamespaceCapabilityReadFS:
+		NamespaceCapabilitySubmitJob, NamespaceCapabilityScaleJob, NamespaceCapabilityDispatchJob,
+		NamespaceCapabilityReadLogs, NamespaceCapabilityReadFS, NamespaceCapabilityAllocExec,
+		NamespaceCapabilityAllocNodeExec, NamespaceCapabilityAllocLifecycle, NamespaceCapabilityReadVolume, NamespaceCapabilityWriteVolume:
 		return true
 	// Separate the enterprise-only capabilities
 	case NamespaceCapabilitySentinelOverride:
@@ -103,6 +135,41 @@ func isNamespaceCapabilityValid(cap string) bool {
 	}
 }
 
+// isVariablesCapabilityValid ensures the given capability is valid for a
+// variables path policy
+func isVariablesCapabilityValid(cap string) bool {
+	switch cap {
+	case VariablesCapabilityList, VariablesCapabilityRead, VariablesCapabilityWrite,
+		VariablesCapabilityDestroy, VariablesCapabilityDeny:
+		return true
+	default:
+		return false
+	}
+}
+
+// expandVariablesPolicy provides the equivalent set of capabilities on the
+// variables of a namespace for a namespace policy
+func expandVariablesPolicy(policy string) []string {
+	switch policy {
+	case PolicyDeny:
+		return []string{VariablesCapabilityDeny}
+	case PolicyRead:
+		return []string{
+			VariablesCapabilityList,
+			VariablesCapabilityRead,
+		}
+	case PolicyWrite:
+		return []string{
+			VariablesCapabilityList,
+			VariablesCapabilityRead,
+			VariablesCapabilityWrite,
+			VariablesCapabilityDestroy,
+		}
+	default:
+		return nil
+	}
+}
+
 // expandNamespacePolicy provides the equivalent set of capabilities for
 // a namespace policy
 func expandNamespacePolicy(policy string) []string {
@@ -113,15 +180,21 @@ func expandNamespacePolicy(policy string) []string {
 		return []string{
 			NamespaceCapabilityListJobs,
 			NamespaceCapabilityReadJob,
+			NamespaceCapabilityReadVolume,
 		}
 	case PolicyWrite:
 		return []string{
 			NamespaceCapabilityListJobs,
 			NamespaceCapabilityReadJob,
 			NamespaceCapabilitySubmitJob,
+			NamespaceCapabilityScaleJob,
 			NamespaceCapabilityDispatchJob,
 			NamespaceCapabilityReadLogs,
 			NamespaceCapabilityReadFS,
+			NamespaceCapabilityAllocExec,
+			NamespaceCapabilityAllocLifecycle,
+			NamespaceCapabilityReadVolume,
+			NamespaceCapabilityWriteVolume,
 		}
 	default:
 		return nil
@@ -164,6 +237,19 @@ func Parse(rules string) (*Policy, error) {
 			}
 		}
 
+		if ns.Variables != nil {
+			for _, path := range ns.Variables.Paths {
+				if path.PathSpec == "" {
+					return nil, fmt.Errorf("Invalid missing variable path in namespace %#v", ns)
+				}
+				for _, cap := range path.Capabilities {
+					if !isVariablesCapabilityValid(cap) {
+						return nil, fmt.Errorf("Invalid variable capability '%s': %#v", cap, path)
+					}
+				}
+			}
+		}
+
 		// Expand the short hand policy to the capabilities and
 		// add to any existing capabilities
 		if ns.Policy != "" {
@@ -30,6 +30,7 @@ func TestParse(t *testing.T) {
 						Capabilities: []string{
 							NamespaceCapabilityListJobs,
 							NamespaceCapabilityReadJob,
+							NamespaceCapabilityReadVolume,
 						},
 					},
 				},
@@ -68,6 +69,7 @@ func TestParse(t *testing.T) {
 						Capabilities: []string{
 							NamespaceCapabilityListJobs,
 							NamespaceCapabilityReadJob,
+							NamespaceCapabilityReadVolume,
 						},
 					},
 					{
@@ -77,9 +79,14 @@ func TestParse(t *testing.T) {
 							NamespaceCapabilityListJobs,
 							NamespaceCapabilityReadJob,
 							NamespaceCapabilitySubmitJob,
+							NamespaceCapabilityScaleJob,
 							NamespaceCapabilityDispatchJob,
 							NamespaceCapabilityReadLogs,
 							NamespaceCapabilityReadFS,
+							NamespaceCapabilityAllocExec,
+							NamespaceCapabilityAllocLifecycle,
+							NamespaceCapabilityReadVolume,
+							NamespaceCapabilityWriteVolume,
 						},
 					},
 					{
@@ -197,6 +204,58 @@ func TestParse(t *testing.T) {
 				},
 			},
 		},
+		{
+			`
+			namespace "default" {
+				variables {
+					path "project/*" {
+						capabilities = ["list", "read"]
+					}
+					path "project/secret" {
+						capabilities = ["deny"]
+					}
+				}
+			}
+			`,
+			"",
+			&Policy{
+				Namespaces: []*NamespacePolicy{
+					{
+						Name: "default",
+						Variables: &VariablesPolicy{
+							Paths: []*VariablesPathPolicy{
+								{
+									PathSpec: "project/*",
+									Capabilities: []string{
+										VariablesCapabilityList,
+										VariablesCapabilityRead,
+									},
+								},
+								{
+									PathSpec: "project/secret",
+									Capabilities: []string{
+										VariablesCapabilityDeny,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			`
+			namespace "default" {
+				variables {
+					path "project/*" {
+						capabilities = ["submit-job"]
+					}
+				}
+			}
+			`,
+			"Invalid variable capability",
+			nil,
+		},
 	}
 
 	for idx, tc := range tcases {
@@ -2,8 +2,10 @@ package api
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestACLPolicies_ListUpsert(t *testing.T) {
@@ -235,3 +237,176 @@ func TestACLTokens_Delete(t *testing.T) {
 	assert.Nil(t, err)
 	assertWriteMeta(t, wm)
 }
+
+func TestACLRoles_CRUD(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	c, s, _ := makeACLClient(t, nil, nil)
+	defer s.Stop()
+	ar := c.ACLRoles()
+
+	// Listing when nothing exists returns empty
+	result, qm, err := ar.List(nil)
+	require.NoError(err)
+	require.Equal(uint64(1), qm.LastIndex)
+	require.Len(result, 0)
+
+	// Roles bundle existing policies
+	_, err = c.ACLPolicies().Upsert(&ACLPolicy{
+		Name:  "readonly",
+		Rules: `namespace "default" { policy = "read" }`,
+	}, nil)
+	require.NoError(err)
+
+	role := &ACLRole{
+		Name:     "ops",
+		Policies: []string{"readonly"},
+	}
+	out, wm, err := ar.Upsert(role, nil)
+	require.NoError(err)
+	assertWriteMeta(t, wm)
+	require.Equal("ops", out.Name)
+	require.NotZero(out.CreateIndex)
+
+	result, qm, err = ar.List(nil)
+	require.NoError(err)
+	assertQueryMeta(t, qm)
+	require.Len(result, 1)
+
+	info, qm, err := ar.Info("ops", nil)
+	require.NoError(err)
+	assertQueryMeta(t, qm)
+	require.Equal([]string{"readonly"}, info.Policies)
+
+	// Create an expiring token linked to the role
+	token, _, err := c.ACLTokens().Create(&ACLToken{
+		Name:          "ops",
+		Type:          "client",
+		Roles:         []string{"ops"},
+		ExpirationTTL: time.Hour,
+	}, nil)
+	require.NoError(err)
+	require.Equal([]string{"ops"}, token.Roles)
+	require.NotNil(token.ExpirationTime)
+	require.WithinDuration(token.CreateTime.Add(time.Hour), *token.ExpirationTime, time.Second)
+
+	wm, err = ar.Delete("ops", nil)
+	require.NoError(err)
+	assertWriteMeta(t, wm)
+
+	result, _, err = ar.List(nil)
+	require.NoError(err)
+	require.Len(result, 0)
+}
+
+func TestACLAuthMethods_CRUD(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	c, s, _ := makeACLClient(t, nil, nil)
+	defer s.Stop()
+	am := c.ACLAuthMethods()
+
+	// Listing when nothing exists returns empty
+	result, qm, err := am.List(nil)
+	require.NoError(err)
+	require.Equal(uint64(1), qm.LastIndex)
+	require.Len(result, 0)
+
+	// Create an auth method
+	method := &ACLAuthMethod{
+		Name:          "okta",
+		Type:          ACLAuthMethodTypeOIDC,
+		TokenLocality: ACLAuthMethodTokenLocalityLocal,
+		MaxTokenTTL:   time.Hour,
+		Config: &ACLAuthMethodConfig{
+			OIDCDiscoveryURL:    "https://example.okta.com",
+			OIDCClientID:        "nomad",
+			AllowedRedirectURIs: []string{"http://localhost:4649/oidc/callback"},
+		},
+	}
+	out, wm, err := am.Create(method, nil)
+	require.NoError(err)
+	assertWriteMeta(t, wm)
+	require.Equal("okta", out.Name)
+	require.NotZero(out.CreateIndex)
+
+	// Update it
+	method.MaxTokenTTL = 2 * time.Hour
+	out, wm, err = am.Update(method, nil)
+	require.NoError(err)
+	assertWriteMeta(t, wm)
+	require.Equal(2*time.Hour, out.MaxTokenTTL)
+
+	result, qm, err = am.List(nil)
+	require.NoError(err)
+	assertQueryMeta(t, qm)
+	require.Len(result, 1)
+
+	info, qm, err := am.Info("okta", nil)
+	require.NoError(err)
+	assertQueryMeta(t, qm)
+	require.Equal(method.Config, info.Config)
+
+	// Bind users of the method to a policy
+	br := c.ACLBindingRules()
+	rule, wm, err := br.Create(&ACLBindingRule{
+		AuthMethod: "okta",
+		Selector:   `"engineering" in list.groups`,
+		BindType:   ACLBindingRuleBindTypePolicy,
+		BindName:   "engineering-read",
+	}, nil)
+	require.NoError(err)
+	assertWriteMeta(t, wm)
+	require.NotEmpty(rule.ID)
+
+	rule.Description = "engineering"
+	rule, _, err = br.Update(rule, nil)
+	require.NoError(err)
+	require.Equal("engineering", rule.Description)
+
+	rules, _, err := br.List(nil)
+	require.NoError(err)
+	require.Len(rules, 1)
+
+	ruleInfo, _, err := br.Info(rule.ID, nil)
+	require.NoError(err)
+	require.Equal(rule.Selector, ruleInfo.Selector)
+
+	wm, err = br.Delete(rule.ID, nil)
+	require.NoError(err)
+	assertWriteMeta(t, wm)
+
+	wm, err = am.Delete("okta", nil)
+	require.NoError(err)
+	assertWriteMeta(t, wm)
+
+	result, _, err = am.List(nil)
+	require.NoError(err)
+	require.Len(result, 0)
+}
+
+func TestACLAuth_GetAuthURL(t *testing.T) {
+	t.Parallel()
+	c, s, _ := makeACLClient(t, nil, nil)
+	defer s.Stop()
+
+	// Logging in requires an auth method
+	_, _, err := c.ACLAuth().GetAuthURL(&ACLOIDCAuthURLRequest{
+		RedirectURI: "http://localhost:4649/oidc/callback",
+		ClientNonce: "nonce",
+		State:       "state",
+	}, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "no default ACL auth method")
+}
+
+func TestACLAuth_Login(t *testing.T) {
+	t.Parallel()
+	c, s, _ := makeACLClient(t, nil, nil)
+	defer s.Stop()
+
+	// Logging in requires a login token
+	_, _, err := c.ACLAuth().Login(&ACLLoginRequest{}, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "missing login token")
+}
@@ -1,9 +1,12 @@
 package api
 
 import (
+	"bufio"
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"net/url"
+	"strconv"
 )
 
 // Agent encapsulates an API client which talks to Nomad's
@@ -237,6 +240,104 @@ func (a *Agent) Health() (*AgentHealthResponse, error) {
 	return nil, fmt.Errorf("unable to unmarshal response with status %d: %v", resp.StatusCode, err)
 }
 
+// Monitor streams the logs of the agent at or above the given log level until
+// stopCh is closed. The log channel is closed once the stream ends. The logs of
+// the agent of a client node are streamed when the node_id parameter of the
+// query options is set.
+func (a *Agent) Monitor(logLevel string, stopCh <-chan struct{}, q *QueryOptions) (<-chan string, <-chan error) {
+	errCh := make(chan error, 1)
+
+	if q == nil {
+		q = &QueryOptions{}
+	}
+	if q.Params == nil {
+		q.Params = make(map[string]string)
+	}
+	q.Params["log_level"] = logLevel
+
+	r, err := a.client.rawQuery("/v1/agent/monitor", q)
+	if err != nil {
+		errCh <- err
+		return nil, errCh
+	}
+
+	// Close the stream once stopped to unblock the reader
+	doneCh := make(chan struct{})
+	go func() {
+		select {
+		case <-stopCh:
+		case <-doneCh:
+		}
+		r.Close()
+	}()
+
+	logCh := make(chan string, 64)
+	go func() {
+		defer close(doneCh)
+		defer close(logCh)
+
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			select {
+			case logCh <- scanner.Text():
+			case <-stopCh:
+				return
+			}
+		}
+
+		if err := scanner.Err(); err != nil {
+			select {
+			case <-stopCh:
+			default:
+				errCh <- err
+			}
+		}
+	}()
+
+	return logCh, errCh
+}
+
+// CPUProfile returns a CPU profile of the agent collected over the given
+// number of seconds, in the format expected by pprof.
+func (a *Agent) CPUProfile(seconds int, q *QueryOptions) ([]byte, error) {
+	return a.pprofRequest("profile", map[string]string{"seconds": strconv.Itoa(seconds)}, q)
+}
+
+// Trace returns an execution trace of the agent collected over the given
+// number of seconds, in the format expected by "go tool trace".
+func (a *Agent) Trace(seconds int, q *QueryOptions) ([]byte, error) {
+	return a.pprofRequest("trace", map[string]string{"seconds": strconv.Itoa(seconds)}, q)
+}
+
+// Lookup returns the named runtime profile of the agent, such as "goroutine"
+// or "heap". A debug level greater than zero returns the profile as text.
+func (a *Agent) Lookup(profile string, debug int, q *QueryOptions) ([]byte, error) {
+	return a.pprofRequest(profile, map[string]string{"debug": strconv.Itoa(debug)}, q)
+}
+
+func (a *Agent) pprofRequest(profile string, params map[string]string, q *QueryOptions) ([]byte, error) {
+	var qo QueryOptions
+	if q != nil {
+		qo = *q
+	}
+	qo.Params = make(map[string]string, len(params))
+	if q != nil {
+		for k, v := range q.Params {
+			qo.Params[k] = v
+		}
+	}
+	for k, v := range params {
+		qo.Params[k] = v
+	}
+
+	r, err := a.client.rawQuery("/v1/agent/pprof/"+profile, &qo)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return ioutil.ReadAll(r)
+}
+
 // joinResponse is used to decode the response we get while
 // sending a member join request.
 type joinResponse struct {
@@ -305,4 +406,16 @@ type AgentHealth struct {
 
 	// Message describes why the agent is unhealthy
 	Message string `json:"message"`
+
+	// Checks are the readiness checks of the agent, by name
+	Checks map[string]*AgentHealthCheck `json:"checks,omitempty"`
+}
+
+// AgentHealthCheck is the result of a readiness check of the Client or Server.
+type AgentHealthCheck struct {
+	// Ok is false if the check failed
+	Ok bool `json:"ok"`
+
+	// Message describes the result of the check
+	Message string `json:"message"`
 }
@@ -65,6 +65,63 @@ func (a *Allocations) GC(alloc *Allocation, q *QueryOptions) error {
 	return err
 }
 
+// Restart restarts the named task of an allocation, or all its tasks if
+// taskName is empty.
+func (a *Allocations) Restart(alloc *Allocation, taskName string, q *QueryOptions) error {
+	req := AllocationRestartRequest{
+		TaskName: taskName,
+	}
+
+	var resp struct{}
+	_, err := a.client.putQuery("/v1/client/allocation/"+alloc.ID+"/restart", &req, &resp, q)
+	return err
+}
+
+// Stop stops an allocation and reschedules it. The returned evaluation ID
+// can be used to monitor the rescheduling.
+func (a *Allocations) Stop(alloc *Allocation, q *WriteOptions) (*AllocStopResponse, error) {
+	var resp AllocStopResponse
+	wm, err := a.client.write("/v1/allocation/"+alloc.ID+"/stop", nil, &resp, q)
+	if err != nil {
+		return nil, err
+	}
+	resp.WriteMeta = *wm
+	return &resp, nil
+}
+
+// Signal sends a signal to the named task of an allocation, or to all its
+// tasks if task is empty.
+func (a *Allocations) Signal(alloc *Allocation, q *QueryOptions, task, signal string) error {
+	req := AllocSignalRequest{
+		Signal: signal,
+		Task:   task,
+	}
+
+	var resp struct{}
+	_, err := a.client.putQuery("/v1/client/allocation/"+alloc.ID+"/signal", &req, &resp, q)
+	return err
+}
+
+// AllocationRestartRequest is used to restart a task of an allocation.
+type AllocationRestartRequest struct {
+	TaskName string
+}
+
+// AllocSignalRequest is used to signal a task of an allocation.
+type AllocSignalRequest struct {
+	Task   string
+	Signal string
+}
+
+// AllocStopResponse is the response to an allocation stop request.
+type AllocStopResponse struct {
+	// EvalID is the ID of the evaluation created to reschedule the
+	// allocation
+	EvalID string
+
+	WriteMeta
+}
+
 // Allocation is used for serialization of allocations.
 type Allocation struct {
 	ID                    string
@@ -94,6 +151,7 @@ type Allocation struct {
 	RescheduleTracker     *RescheduleTracker
 	PreemptedAllocations  []string
 	PreemptedByAllocation string
+	NetworkStatus         *AllocNetworkStatus
 	CreateIndex           uint64
 	ModifyIndex           uint64
 	AllocModifyIndex      uint64
@@ -117,6 +175,7 @@ type AllocationMetric struct {
 	AllocationTime    time.Duration
 	CoalescedFailures int
 	ScoreMetaData     []*NodeScoreMeta
+	NodeFailures      []*NodeFailureMeta
 }
 
 // NodeScoreMeta is used to serialize node scoring metadata
@@ -127,6 +186,15 @@ type NodeScoreMeta struct {
 	NormScore float64
 }
 
+// NodeFailureMeta is used to serialize the reason a node was rejected for a
+// placement. Either Constraint or Dimension is set.
+type NodeFailureMeta struct {
+	NodeID     string
+	NodeName   string
+	Constraint string
+	Dimension  string
+}
+
 // AllocationListStub is used to return a subset of an allocation
 // during list operations.
 type AllocationListStub struct {
@@ -153,6 +221,13 @@ type AllocationListStub struct {
 	ModifyTime         int64
 }
 
+// AllocNetworkStatus captures the network namespace the client configured for
+// an allocation and the address the network assigned it.
+type AllocNetworkStatus struct {
+	InterfaceName string
+	Address       string
+}
+
 // AllocDeploymentStatus captures the status of the allocation as part of the
 // deployment. This can include things like if the allocation has been marked as
 // healthy.
@@ -175,7 +250,8 @@ type AllocatedTaskResources struct {
 }
 
 type AllocatedSharedResources struct {
-	DiskMB int64
+	DiskMB   int64
+	Networks []*NetworkResource
 }
 
 type AllocatedCpuResources struct {
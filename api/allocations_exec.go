@@ -0,0 +1,230 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+
+	"github.com/gorilla/websocket"
+)
+
+// TerminalSize is the size of the terminal of an exec session in characters.
+type TerminalSize struct {
+	Height int `json:"height,omitempty"`
+	Width  int `json:"width,omitempty"`
+}
+
+// ExecStreamingIOOperation is a write to or the close of a stream of an exec
+// session. Data and Close are mutually exclusive.
+type ExecStreamingIOOperation struct {
+	Data  []byte `json:"data,omitempty"`
+	Close bool   `json:"close,omitempty"`
+}
+
+// ExecStreamingInput is a message sent to an exec session.
+type ExecStreamingInput struct {
+	Stdin   *ExecStreamingIOOperation `json:"stdin,omitempty"`
+	TTYSize *TerminalSize             `json:"tty_size,omitempty"`
+}
+
+// ExecStreamingExitResult is the exit result of the command of an exec
+// session.
+type ExecStreamingExitResult struct {
+	ExitCode int `json:"exit_code"`
+}
+
+// ExecStreamingOutput is a message received from an exec session.
+type ExecStreamingOutput struct {
+	Stdout *ExecStreamingIOOperation `json:"stdout,omitempty"`
+	Stderr *ExecStreamingIOOperation `json:"stderr,omitempty"`
+
+	Exited bool                     `json:"exited,omitempty"`
+	Result *ExecStreamingExitResult `json:"result,omitempty"`
+}
+
+// Exec is used to execute a command inside a running task of the allocation.
+// The stdin of the command is read from stdin until it is closed and its
+// output is written to stdout and stderr. If tty is set, a pseudo-terminal is
+// allocated for the command and its size is updated with the sizes received
+// from terminalSizeCh. Exec blocks until the command exits and returns its
+// exit code.
+func (a *Allocations) Exec(ctx context.Context,
+	alloc *Allocation, task string, tty bool, command []string,
+	stdin io.Reader, stdout, stderr io.Writer,
+	terminalSizeCh <-chan TerminalSize, q *QueryOptions) (exitCode int, err error) {
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	conn, err := a.execConn(alloc, task, tty, command, q)
+	if err != nil {
+		return -2, err
+	}
+	defer conn.Close()
+
+	// Close the connection if the caller cancels the session
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+
+	// gorilla websockets support a single concurrent writer
+	var sendLock sync.Mutex
+	send := func(in *ExecStreamingInput) error {
+		sendLock.Lock()
+		defer sendLock.Unlock()
+		return conn.WriteJSON(in)
+	}
+
+	errCh := make(chan error, 2)
+	go execSendStdin(stdin, send, errCh)
+	if tty && terminalSizeCh != nil {
+		go execSendResizes(ctx, terminalSizeCh, send, errCh)
+	}
+
+	resultCh := make(chan *ExecStreamingExitResult, 1)
+	go func() {
+		res, err := execReceive(conn, stdout, stderr)
+		if err != nil {
+			errCh <- err
+			return
+		}
+		resultCh <- res
+	}()
+
+	select {
+	case res := <-resultCh:
+		return res.ExitCode, nil
+	case err := <-errCh:
+		return -2, err
+	case <-ctx.Done():
+		return -2, ctx.Err()
+	}
+}
+
+// execConn opens the websocket of an exec session, connecting to the client
+// running the allocation directly if possible and through the servers
+// otherwise.
+func (a *Allocations) execConn(alloc *Allocation, task string, tty bool, command []string, q *QueryOptions) (*websocket.Conn, error) {
+	cmd, err := json.Marshal(command)
+	if err != nil {
+		return nil, err
+	}
+
+	var qo QueryOptions
+	if q != nil {
+		qo = *q
+	}
+	params := make(map[string]string, len(qo.Params)+3)
+	for k, v := range qo.Params {
+		params[k] = v
+	}
+	params["task"] = task
+	params["command"] = string(cmd)
+	params["tty"] = strconv.FormatBool(tty)
+	qo.Params = params
+
+	reqPath := fmt.Sprintf("/v1/client/allocation/%s/exec", alloc.ID)
+
+	nodeClient, err := a.client.GetNodeClientWithTimeout(alloc.NodeID, ClientConnTimeout, q)
+	if err == nil {
+		conn, _, err := nodeClient.websocket(reqPath, &qo)
+		if err == nil {
+			return conn, nil
+		}
+
+		// Only fall back to the servers if the client can not be reached
+		if _, ok := err.(net.Error); !ok {
+			return nil, err
+		}
+	}
+
+	conn, _, err := a.client.websocket(reqPath, &qo)
+	return conn, err
+}
+
+// execSendStdin sends the stdin of an exec session until it is closed.
+func execSendStdin(stdin io.Reader, send func(*ExecStreamingInput) error, errCh chan<- error) {
+	if stdin == nil {
+		return
+	}
+
+	buf := make([]byte, 2048)
+	for {
+		n, err := stdin.Read(buf)
+		if n > 0 {
+			data := make([]byte, n)
+			copy(data, buf[:n])
+			in := &ExecStreamingInput{Stdin: &ExecStreamingIOOperation{Data: data}}
+			if err := send(in); err != nil {
+				errCh <- fmt.Errorf("failed to send stdin: %v", err)
+				return
+			}
+		}
+
+		if err == io.EOF {
+			in := &ExecStreamingInput{Stdin: &ExecStreamingIOOperation{Close: true}}
+			if err := send(in); err != nil {
+				errCh <- fmt.Errorf("failed to close stdin: %v", err)
+			}
+			return
+		} else if err != nil {
+			errCh <- fmt.Errorf("failed to read stdin: %v", err)
+			return
+		}
+	}
+}
+
+// execSendResizes sends the terminal size changes of an exec session.
+func execSendResizes(ctx context.Context, terminalSizeCh <-chan TerminalSize,
+	send func(*ExecStreamingInput) error, errCh chan<- error) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case size, ok := <-terminalSizeCh:
+			if !ok {
+				return
+			}
+
+			in := &ExecStreamingInput{TTYSize: &size}
+			if err := send(in); err != nil {
+				errCh <- fmt.Errorf("failed to send terminal size: %v", err)
+				return
+			}
+		}
+	}
+}
+
+// execReceive writes the output of an exec session until the command exits
+// and returns its exit result.
+func execReceive(conn *websocket.Conn, stdout, stderr io.Writer) (*ExecStreamingExitResult, error) {
+	for {
+		var out ExecStreamingOutput
+		if err := conn.ReadJSON(&out); err != nil {
+			if closeErr, ok := err.(*websocket.CloseError); ok && closeErr.Code != websocket.CloseNormalClosure {
+				return nil, fmt.Errorf("%s", closeErr.Text)
+			}
+			return nil, err
+		}
+
+		if out.Stdout != nil && len(out.Stdout.Data) > 0 {
+			if _, err := stdout.Write(out.Stdout.Data); err != nil {
+				return nil, fmt.Errorf("failed to write stdout: %v", err)
+			}
+		}
+		if out.Stderr != nil && len(out.Stderr.Data) > 0 {
+			if _, err := stderr.Write(out.Stderr.Data); err != nil {
+				return nil, fmt.Errorf("failed to write stderr: %v", err)
+			}
+		}
+
+		if out.Exited && out.Result != nil {
+			return out.Result, nil
+		}
+	}
+}
@@ -3,6 +3,7 @@ package api
 import (
 	"bytes"
 	"compress/gzip"
+	"context"
 	"crypto/tls"
 	"encoding/json"
 	"fmt"
@@ -15,6 +16,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/gorilla/websocket"
 	"github.com/hashicorp/go-cleanhttp"
 	rootcerts "github.com/hashicorp/go-rootcerts"
 )
@@ -50,11 +52,45 @@ type QueryOptions struct {
 	// If set, used as prefix for resource list searches
 	Prefix string
 
+	// Filter is an expression the objects returned by list searches must
+	// match.
+	Filter string
+
+	// PerPage is the maximum number of objects returned by list searches.
+	PerPage int32
+
+	// NextToken is the token of the page of a list search to return, as
+	// returned in the QueryMeta of the previous page.
+	NextToken string
+
 	// Set HTTP parameters on the query.
 	Params map[string]string
 
 	// AuthToken is the secret ID of an ACL token
 	AuthToken string
+
+	// ctx is the context of the query, set using WithContext
+	ctx context.Context
+}
+
+// Context returns the context of the query, which defaults to the background
+// context.
+func (o *QueryOptions) Context() context.Context {
+	if o != nil && o.ctx != nil {
+		return o.ctx
+	}
+	return context.Background()
+}
+
+// WithContext returns a copy of the query options using the context, which
+// cancels the query, and its retries, once done.
+func (o *QueryOptions) WithContext(ctx context.Context) *QueryOptions {
+	o2 := new(QueryOptions)
+	if o != nil {
+		*o2 = *o
+	}
+	o2.ctx = ctx
+	return o2
 }
 
 // WriteOptions are used to parameterize a write
@@ -68,6 +104,29 @@ type WriteOptions struct {
 
 	// AuthToken is the secret ID of an ACL token
 	AuthToken string
+
+	// ctx is the context of the write, set using WithContext
+	ctx context.Context
+}
+
+// Context returns the context of the write, which defaults to the background
+// context.
+func (o *WriteOptions) Context() context.Context {
+	if o != nil && o.ctx != nil {
+		return o.ctx
+	}
+	return context.Background()
+}
+
+// WithContext returns a copy of the write options using the context, which
+// cancels the write, and its retries, once done.
+func (o *WriteOptions) WithContext(ctx context.Context) *WriteOptions {
+	o2 := new(WriteOptions)
+	if o != nil {
+		*o2 = *o
+	}
+	o2.ctx = ctx
+	return o2
 }
 
 // QueryMeta is used to return meta data about a query
@@ -85,6 +144,10 @@ type QueryMeta struct {
 
 	// How long did the request take
 	RequestTime time.Duration
+
+	// NextToken is the token of the next page of a paginated list search.
+	// It is empty on the last page.
+	NextToken string
 }
 
 // WriteMeta is used to return meta data about a write
@@ -133,6 +196,19 @@ type Config struct {
 	// TLSConfig provides the various TLS related configurations for the http
 	// client
 	TLSConfig *TLSConfig
+
+	// Retry configures the retries of the requests failing with transient
+	// errors, such as connection errors or leader elections. Requests aren't
+	// retried if nil.
+	Retry *RetryConfig
+
+	// MaxIdleConnsPerHost is the maximum number of idle connections to the
+	// agent kept open to be reused. Connections aren't reused if zero.
+	MaxIdleConnsPerHost int
+
+	// IdleConnTimeout is how long the idle connections are kept open, when
+	// they are reused. Defaults to 90 seconds.
+	IdleConnTimeout time.Duration
 }
 
 // ClientConfig copies the configuration with a new client address, region, and
@@ -152,6 +228,10 @@ func (c *Config) ClientConfig(region, address string, tlsEnabled bool) *Config {
 		HttpAuth:   c.HttpAuth,
 		WaitTime:   c.WaitTime,
 		TLSConfig:  c.TLSConfig.Copy(),
+		Retry:      c.Retry.Copy(),
+
+		MaxIdleConnsPerHost: c.MaxIdleConnsPerHost,
+		IdleConnTimeout:     c.IdleConnTimeout,
 	}
 
 	// Update the tls server name for connecting to a client
@@ -332,6 +412,29 @@ func (c *Config) ConfigureTLS() error {
 	return nil
 }
 
+// configureTransport applies the connection reuse configuration to the HTTP
+// client.
+func (c *Config) configureTransport() error {
+	if c.MaxIdleConnsPerHost <= 0 {
+		return nil
+	}
+
+	transport, ok := c.httpClient.Transport.(*http.Transport)
+	if !ok {
+		return fmt.Errorf("unexpected HTTP transport: %T", c.httpClient.Transport)
+	}
+
+	transport.DisableKeepAlives = false
+	transport.MaxIdleConnsPerHost = c.MaxIdleConnsPerHost
+	if transport.MaxIdleConns != 0 && transport.MaxIdleConns < c.MaxIdleConnsPerHost {
+		transport.MaxIdleConns = c.MaxIdleConnsPerHost
+	}
+	if c.IdleConnTimeout > 0 {
+		transport.IdleConnTimeout = c.IdleConnTimeout
+	}
+	return nil
+}
+
 // Client provides a client to the Nomad API
 type Client struct {
 	config Config
@@ -357,6 +460,11 @@ func NewClient(config *Config) (*Client, error) {
 		return nil, err
 	}
 
+	// Configure the reuse of the connections
+	if err := config.configureTransport(); err != nil {
+		return nil, err
+	}
+
 	client := &Client{
 		config: *config,
 	}
@@ -447,6 +555,7 @@ type request struct {
 	token  string
 	body   io.Reader
 	obj    interface{}
+	ctx    context.Context
 }
 
 // setQueryOptions is used to annotate the request with
@@ -464,6 +573,7 @@ func (r *request) setQueryOptions(q *QueryOptions) {
 	if q.AuthToken != "" {
 		r.token = q.AuthToken
 	}
+	r.ctx = q.ctx
 	if q.AllowStale {
 		r.params.Set("stale", "")
 	}
@@ -476,6 +586,15 @@ func (r *request) setQueryOptions(q *QueryOptions) {
 	if q.Prefix != "" {
 		r.params.Set("prefix", q.Prefix)
 	}
+	if q.Filter != "" {
+		r.params.Set("filter", q.Filter)
+	}
+	if q.PerPage != 0 {
+		r.params.Set("per_page", strconv.FormatInt(int64(q.PerPage), 10))
+	}
+	if q.NextToken != "" {
+		r.params.Set("next_token", q.NextToken)
+	}
 	for k, v := range q.Params {
 		r.params.Set(k, v)
 	}
@@ -501,6 +620,7 @@ func (r *request) setWriteOptions(q *WriteOptions) {
 	if q.AuthToken != "" {
 		r.token = q.AuthToken
 	}
+	r.ctx = q.ctx
 }
 
 // toHTTP converts the request to an HTTP request
@@ -522,6 +642,9 @@ func (r *request) toHTTP() (*http.Request, error) {
 	if err != nil {
 		return nil, err
 	}
+	if r.ctx != nil {
+		req = req.WithContext(r.ctx)
+	}
 
 	// Optionally configure HTTP basic authentication
 	if r.url.User != nil {
@@ -604,39 +727,65 @@ func (m *multiCloser) Read(p []byte) (int, error) {
 	return m.reader.Read(p)
 }
 
-// doRequest runs a request with our client
+// doRequest runs a request with our client, retrying it according to the
+// retry configuration. The returned duration is the one of the last attempt.
 func (c *Client) doRequest(r *request) (time.Duration, *http.Response, error) {
 	req, err := r.toHTTP()
 	if err != nil {
 		return 0, nil, err
 	}
-	start := time.Now()
-	resp, err := c.config.httpClient.Do(req)
-	diff := time.Now().Sub(start)
 
-	// If the response is compressed, we swap the body's reader.
-	if resp != nil && resp.Header != nil {
-		var reader io.ReadCloser
-		switch resp.Header.Get("Content-Encoding") {
-		case "gzip":
-			greader, err := gzip.NewReader(resp.Body)
-			if err != nil {
+	for attempt := 0; ; attempt++ {
+		start := time.Now()
+		resp, err := c.config.httpClient.Do(req)
+		diff := time.Now().Sub(start)
+
+		if resp != nil {
+			if err := decompressBody(resp); err != nil {
+				resp.Body.Close()
 				return 0, nil, err
 			}
+		}
 
-			// The gzip reader doesn't close the wrapped reader so we use
-			// multiCloser.
-			reader = &multiCloser{
-				reader:       greader,
-				inorderClose: []io.Closer{greader, resp.Body},
-			}
-		default:
-			reader = resp.Body
+		wait, retry := c.config.Retry.shouldRetry(attempt, req, resp, err)
+		if !retry {
+			return diff, resp, err
+		}
+		if resp != nil {
+			resp.Body.Close()
+		}
+
+		if err := sleepWithContext(req.Context(), wait); err != nil {
+			return 0, nil, err
 		}
-		resp.Body = reader
+		if req, err = rewindRequest(req); err != nil {
+			return 0, nil, err
+		}
+	}
+}
+
+// decompressBody swaps the body's reader of the response if it is
+// compressed.
+func decompressBody(resp *http.Response) error {
+	if resp.Header == nil {
+		return nil
 	}
 
-	return diff, resp, err
+	switch resp.Header.Get("Content-Encoding") {
+	case "gzip":
+		greader, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return err
+		}
+
+		// The gzip reader doesn't close the wrapped reader so we use
+		// multiCloser.
+		resp.Body = &multiCloser{
+			reader:       greader,
+			inorderClose: []io.Closer{greader, resp.Body},
+		}
+	}
+	return nil
 }
 
 // rawQuery makes a GET request to the specified endpoint but returns just the
@@ -655,6 +804,59 @@ func (c *Client) rawQuery(endpoint string, q *QueryOptions) (io.ReadCloser, erro
 	return resp.Body, nil
 }
 
+// websocket makes a websocket request to the specified endpoint, inheriting
+// the address, TLS and authentication configuration of the client.
+func (c *Client) websocket(endpoint string, q *QueryOptions) (*websocket.Conn, *http.Response, error) {
+	transport, ok := c.config.httpClient.Transport.(*http.Transport)
+	if !ok {
+		return nil, nil, fmt.Errorf("unsupported HTTP transport: %T", c.config.httpClient.Transport)
+	}
+
+	dialer := websocket.Dialer{
+		ReadBufferSize:   4096,
+		WriteBufferSize:  4096,
+		HandshakeTimeout: c.config.httpClient.Timeout,
+		NetDial:          transport.Dial,
+		Proxy:            transport.Proxy,
+		TLSClientConfig:  transport.TLSClientConfig,
+	}
+
+	// Build the request for its headers and parameters
+	r, err := c.newRequest("GET", endpoint)
+	if err != nil {
+		return nil, nil, err
+	}
+	r.setQueryOptions(q)
+
+	req, err := r.toHTTP()
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Header.Del("Accept-Encoding")
+
+	wsURL := *req.URL
+	switch wsURL.Scheme {
+	case "http":
+		wsURL.Scheme = "ws"
+	case "https":
+		wsURL.Scheme = "wss"
+	default:
+		return nil, nil, fmt.Errorf("unsupported scheme: %v", wsURL.Scheme)
+	}
+
+	conn, resp, err := dialer.Dial(wsURL.String(), req.Header)
+
+	// The response is more informative than the handshake error
+	if resp != nil && resp.StatusCode != http.StatusSwitchingProtocols {
+		var buf bytes.Buffer
+		io.Copy(&buf, resp.Body)
+		resp.Body.Close()
+		return nil, nil, fmt.Errorf("Unexpected response code: %d (%s)", resp.StatusCode, buf.Bytes())
+	}
+
+	return conn, resp, err
+}
+
 // query is used to do a GET request against an endpoint
 // and deserialize the response into an interface using
 // standard Nomad conventions.
@@ -782,6 +984,9 @@ func parseQueryMeta(resp *http.Response, q *QueryMeta) error {
 	default:
 		q.KnownLeader = false
 	}
+
+	// Parse the X-Nomad-NextToken
+	q.NextToken = header.Get("X-Nomad-NextToken")
 	return nil
 }
 
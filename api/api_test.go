@@ -174,6 +174,9 @@ func TestSetQueryOptions(t *testing.T) {
 		WaitIndex:  1000,
 		WaitTime:   100 * time.Second,
 		AuthToken:  "foobar",
+		Filter:     `Status == "running"`,
+		PerPage:    10,
+		NextToken:  "abc",
 	}
 	r.setQueryOptions(q)
 
@@ -195,6 +198,15 @@ func TestSetQueryOptions(t *testing.T) {
 	if r.token != "foobar" {
 		t.Fatalf("bad: %v", r.token)
 	}
+	if r.params.Get("filter") != `Status == "running"` {
+		t.Fatalf("bad: %v", r.params)
+	}
+	if r.params.Get("per_page") != "10" {
+		t.Fatalf("bad: %v", r.params)
+	}
+	if r.params.Get("next_token") != "abc" {
+		t.Fatalf("bad: %v", r.params)
+	}
 }
 
 func TestSetWriteOptions(t *testing.T) {
@@ -257,6 +269,7 @@ func TestParseQueryMeta(t *testing.T) {
 	resp.Header.Set("X-Nomad-Index", "12345")
 	resp.Header.Set("X-Nomad-LastContact", "80")
 	resp.Header.Set("X-Nomad-KnownLeader", "true")
+	resp.Header.Set("X-Nomad-NextToken", "abc")
 
 	qm := &QueryMeta{}
 	if err := parseQueryMeta(resp, qm); err != nil {
@@ -272,6 +285,9 @@ func TestParseQueryMeta(t *testing.T) {
 	if !qm.KnownLeader {
 		t.Fatalf("Bad: %v", qm)
 	}
+	if qm.NextToken != "abc" {
+		t.Fatalf("Bad: %v", qm)
+	}
 }
 
 func TestParseWriteMeta(t *testing.T) {
@@ -23,7 +23,7 @@ func TestCompose(t *testing.T) {
 				{
 					CIDR:          "0.0.0.0/0",
 					MBits:         helper.IntToPtr(100),
-					ReservedPorts: []Port{{"", 80}, {"", 443}},
+					ReservedPorts: []Port{{Value: 80}, {Value: 443}},
 				},
 			},
 		})
@@ -115,8 +115,8 @@ func TestCompose(t *testing.T) {
 									CIDR:  "0.0.0.0/0",
 									MBits: helper.IntToPtr(100),
 									ReservedPorts: []Port{
-										{"", 80},
-										{"", 443},
+										{Value: 80},
+										{Value: 443},
 									},
 								},
 							},
@@ -11,5 +11,6 @@ const (
 	Nodes       Context = "nodes"
 	Namespaces  Context = "namespaces"
 	Quotas      Context = "quotas"
+	Plugins     Context = "plugins"
 	All         Context = "all"
 )
@@ -79,6 +79,20 @@ func (d *Deployments) Pause(deploymentID string, pause bool, q *WriteOptions) (*
 	return &resp, wm, nil
 }
 
+// Unblock is used to mark a blocked deployment of a multiregion job as
+// successful, for example when a peer region failed.
+func (d *Deployments) Unblock(deploymentID string, q *WriteOptions) (*DeploymentUpdateResponse, *WriteMeta, error) {
+	var resp DeploymentUpdateResponse
+	req := &DeploymentUnblockRequest{
+		DeploymentID: deploymentID,
+	}
+	wm, err := d.client.write("/v1/deployment/unblock/"+deploymentID, req, &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, wm, nil
+}
+
 // PromoteAll is used to promote all canaries in the given deployment
 func (d *Deployments) PromoteAll(deploymentID string, q *WriteOptions) (*DeploymentUpdateResponse, *WriteMeta, error) {
 	var resp DeploymentUpdateResponse
@@ -161,6 +175,10 @@ type Deployment struct {
 	// status.
 	StatusDescription string
 
+	// IsMultiregion marks the deployment of a multiregion job, which is
+	// coordinated with the deployments of the peer regions.
+	IsMultiregion bool
+
 	CreateIndex uint64
 	ModifyIndex uint64
 }
@@ -169,6 +187,7 @@ type Deployment struct {
 type DeploymentState struct {
 	PlacedCanaries    []string
 	AutoRevert        bool
+	AutoPromote       bool
 	ProgressDeadline  time.Duration
 	RequireProgressBy time.Time
 	Promoted          bool
@@ -257,6 +276,12 @@ type DeploymentFailRequest struct {
 	WriteRequest
 }
 
+// DeploymentUnblockRequest is used to unblock a particular deployment
+type DeploymentUnblockRequest struct {
+	DeploymentID string
+	WriteRequest
+}
+
 // SingleDeploymentResponse is used to respond with a single deployment
 type SingleDeploymentResponse struct {
 	Deployment *Deployment
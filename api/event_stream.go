@@ -0,0 +1,172 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+// Topic is the topic of cluster events, such as Job or Node.
+type Topic string
+
+const (
+	TopicJob        Topic = "Job"
+	TopicAllocation Topic = "Allocation"
+	TopicDeployment Topic = "Deployment"
+	TopicEvaluation Topic = "Evaluation"
+	TopicNode       Topic = "Node"
+
+	// TopicAll matches the events of all topics.
+	TopicAll Topic = "*"
+)
+
+// Event is a change of the state of the cluster.
+type Event struct {
+	Topic      Topic
+	Type       string
+	Key        string
+	Namespace  string
+	FilterKeys []string
+	Index      uint64
+	Payload    map[string]interface{}
+}
+
+// Job returns the job of the payload of a Job event. The job is nil once
+// purged.
+func (e *Event) Job() (*Job, error) {
+	var out *Job
+	return out, e.decodePayload("Job", &out)
+}
+
+// Allocation returns the allocation of the payload of an Allocation event.
+func (e *Event) Allocation() (*AllocationListStub, error) {
+	var out *AllocationListStub
+	return out, e.decodePayload("Allocation", &out)
+}
+
+// Deployment returns the deployment of the payload of a Deployment event.
+func (e *Event) Deployment() (*Deployment, error) {
+	var out *Deployment
+	return out, e.decodePayload("Deployment", &out)
+}
+
+// Evaluation returns the evaluation of the payload of an Evaluation event.
+func (e *Event) Evaluation() (*Evaluation, error) {
+	var out *Evaluation
+	return out, e.decodePayload("Evaluation", &out)
+}
+
+// Node returns the node of the payload of a Node event. The node is nil once
+// deregistered.
+func (e *Event) Node() (*NodeListStub, error) {
+	var out *NodeListStub
+	return out, e.decodePayload("Node", &out)
+}
+
+// decodePayload decodes the object of the payload under the key.
+func (e *Event) decodePayload(key string, out interface{}) error {
+	raw, ok := e.Payload[key]
+	if !ok {
+		return fmt.Errorf("event %q has no %s payload", e.Type, key)
+	}
+	buf, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(buf, out)
+}
+
+// Events is the batch of events emitted by the change applied at Index. Err
+// is set if the stream failed.
+type Events struct {
+	Index  uint64
+	Events []Event
+	Err    error
+}
+
+// IsHeartbeat returns whether the batch is a heartbeat of an idle stream.
+func (e *Events) IsHeartbeat() bool {
+	return e.Index == 0 && len(e.Events) == 0
+}
+
+// EventStream is used to stream the cluster events.
+type EventStream struct {
+	client *Client
+}
+
+// EventStream returns a handle on the event stream endpoint.
+func (c *Client) EventStream() *EventStream {
+	return &EventStream{client: c}
+}
+
+// Stream streams the events of the topics published after the index until the
+// context is canceled. The topics map to the keys of the events to stream for
+// each, the "*" key matching all the keys of a topic. All the events are
+// streamed if no topics are given, and only the new events if the index is
+// zero. The channel is closed once the stream ends, after a batch holding the
+// error if the stream failed.
+func (e *EventStream) Stream(ctx context.Context, topics map[Topic][]string, index uint64, q *QueryOptions) (<-chan *Events, error) {
+	params := url.Values{}
+	for topic, keys := range topics {
+		for _, key := range keys {
+			params.Add("topic", fmt.Sprintf("%s:%s", topic, key))
+		}
+	}
+
+	opts := &QueryOptions{}
+	if q != nil {
+		*opts = *q
+	}
+	opts.WaitIndex = index
+
+	path := "/v1/event/stream"
+	if len(params) != 0 {
+		path += "?" + params.Encode()
+	}
+	r, err := e.client.rawQuery(path, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Close the stream once canceled to unblock the decoder
+	doneCh := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-doneCh:
+		}
+		r.Close()
+	}()
+
+	eventsCh := make(chan *Events, 10)
+	go func() {
+		defer close(doneCh)
+		defer close(eventsCh)
+
+		dec := json.NewDecoder(r)
+		for {
+			var events Events
+			if err := dec.Decode(&events); err != nil {
+				if ctx.Err() == nil {
+					select {
+					case eventsCh <- &Events{Err: err}:
+					case <-ctx.Done():
+					}
+				}
+				return
+			}
+			if events.IsHeartbeat() {
+				continue
+			}
+
+			select {
+			case eventsCh <- &events:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return eventsCh, nil
+}
@@ -0,0 +1,57 @@
+package api
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventStream(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	c, s := makeClient(t, nil, nil)
+	defer s.Stop()
+
+	// Register a job
+	job := testJob()
+	resp, _, err := c.Jobs().Register(job, nil)
+	require.NoError(err)
+
+	// Stream the events of the job from before its registration
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	topics := map[Topic][]string{TopicJob: {*job.ID}}
+	eventsCh, err := c.EventStream().Stream(ctx, topics, resp.JobModifyIndex-1, nil)
+	require.NoError(err)
+
+	select {
+	case events := <-eventsCh:
+		require.NoError(events.Err)
+		require.Len(events.Events, 1)
+		event := events.Events[0]
+		require.Equal(TopicJob, event.Topic)
+		require.Equal("JobRegistered", event.Type)
+		require.Equal(resp.JobModifyIndex, event.Index)
+
+		out, err := event.Job()
+		require.NoError(err)
+		require.Equal(*job.ID, *out.ID)
+
+		_, err = event.Node()
+		require.Error(err)
+	case <-time.After(time.Duration(testutil.TestMultiplier()*5) * time.Second):
+		t.Fatal("timeout waiting for events")
+	}
+
+	// The channel is closed once canceled
+	cancel()
+	select {
+	case _, ok := <-eventsCh:
+		require.False(ok)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for the stream to end")
+	}
+}
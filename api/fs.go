@@ -40,6 +40,19 @@ func (s *StreamFrame) IsHeartbeat() bool {
 	return len(s.Data) == 0 && s.FileEvent == "" && s.File == "" && s.Offset == 0
 }
 
+// LogFrame is a frame of the logs of a task, streamed in the JSON format
+type LogFrame struct {
+	// File and Offset are the log file the data was read from and the offset
+	// following the data, from which the logs can be resumed.
+	File   string
+	Index  int64
+	Offset int64
+
+	Data      string
+	FileEvent string
+	Timestamp time.Time
+}
+
 // AllocFS is used to introspect an allocation directory on a Nomad client
 type AllocFS struct {
 	client *Client
@@ -282,21 +295,10 @@ func (a *AllocFS) Logs(alloc *Allocation, follow bool, task, logType, origin str
 	q.Params["origin"] = origin
 	q.Params["offset"] = strconv.FormatInt(offset, 10)
 
-	reqPath := fmt.Sprintf("/v1/client/fs/logs/%s", alloc.ID)
-	r, err := nodeClient.rawQuery(reqPath, q)
+	r, err := a.logsQuery(nodeClient, alloc, q)
 	if err != nil {
-		// There was a networking error when talking directly to the client.
-		if _, ok := err.(net.Error); !ok {
-			errCh <- err
-			return nil, errCh
-		}
-
-		// Try via the server
-		r, err = a.client.rawQuery(reqPath, q)
-		if err != nil {
-			errCh <- err
-			return nil, errCh
-		}
+		errCh <- err
+		return nil, errCh
 	}
 
 	// Create the output channel
@@ -437,3 +439,101 @@ func (f *FrameReader) Close() error {
 	f.closed = true
 	return nil
 }
+
+// LogFrames streams the logs of a task as LogFrames, blocking on EOF.
+// The parameters are:
+// * allocation: the allocation to stream from.
+// * follow: Whether the logs should be followed.
+// * task: the tasks name to stream logs for.
+// * logType: Either "stdout" or "stderr"
+// * file: The log file to resume streaming from, or empty for the first one.
+// * offset: The offset in the log file to start streaming data at.
+// * cancel: A channel that when closed, streaming will end.
+//
+// Streaming is resumed from the File and Offset of the last received frame,
+// even if the logs have been rotated since.
+//
+// The return value is a channel that will emit LogFrames as they are read.
+// The chan will be closed when follow=false and the end of the logs is
+// reached.
+//
+// Unexpected (non-EOF) errors will be sent on the error chan.
+func (a *AllocFS) LogFrames(alloc *Allocation, follow bool, task, logType, file string,
+	offset int64, cancel <-chan struct{}, q *QueryOptions) (<-chan *LogFrame, <-chan error) {
+
+	errCh := make(chan error, 1)
+
+	nodeClient, err := a.client.GetNodeClientWithTimeout(alloc.NodeID, ClientConnTimeout, q)
+	if err != nil {
+		errCh <- err
+		return nil, errCh
+	}
+
+	if q == nil {
+		q = &QueryOptions{}
+	}
+	if q.Params == nil {
+		q.Params = make(map[string]string)
+	}
+
+	q.Params["follow"] = strconv.FormatBool(follow)
+	q.Params["task"] = task
+	q.Params["type"] = logType
+	q.Params["format"] = "json"
+	q.Params["offset"] = strconv.FormatInt(offset, 10)
+	if file != "" {
+		q.Params["file"] = file
+	}
+
+	r, err := a.logsQuery(nodeClient, alloc, q)
+	if err != nil {
+		errCh <- err
+		return nil, errCh
+	}
+
+	frames := make(chan *LogFrame, 10)
+
+	go func() {
+		defer r.Close()
+
+		dec := json.NewDecoder(r)
+		for {
+			select {
+			case <-cancel:
+				return
+			default:
+			}
+
+			var frame LogFrame
+			if err := dec.Decode(&frame); err != nil {
+				if err == io.EOF || err == io.ErrClosedPipe {
+					close(frames)
+				} else {
+					errCh <- err
+				}
+				return
+			}
+
+			frames <- &frame
+		}
+	}()
+
+	return frames, errCh
+}
+
+// logsQuery makes the logs query of the allocation directly to its node, or
+// through the servers if the node can't be reached.
+func (a *AllocFS) logsQuery(nodeClient *Client, alloc *Allocation, q *QueryOptions) (io.ReadCloser, error) {
+	reqPath := fmt.Sprintf("/v1/client/fs/logs/%s", alloc.ID)
+	r, err := nodeClient.rawQuery(reqPath, q)
+	if err != nil {
+		// There was a networking error when talking directly to the client.
+		if _, ok := err.(net.Error); !ok {
+			return nil, err
+		}
+
+		// Try via the server
+		return a.client.rawQuery(reqPath, q)
+	}
+	return r, nil
+}
@@ -0,0 +1,67 @@
+package api
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// HostVolumes is used to create and delete host volumes on the nodes at
+// runtime.
+type HostVolumes struct {
+	client *Client
+}
+
+// HostVolumes returns a handle on the host volume endpoints.
+func (c *Client) HostVolumes() *HostVolumes {
+	return &HostVolumes{client: c}
+}
+
+// HostVolumeCreateRequest is used to create a host volume on a node.
+type HostVolumeCreateRequest struct {
+	// NodeID is the node to create the volume on, which defaults to the
+	// local node of the agent.
+	NodeID string
+
+	// Provisioner is the name of the provisioner creating the volume,
+	// which defaults to the builtin "mkdir" provisioner.
+	Provisioner string
+
+	// Volume is the volume to create. Its ExternalID is set to the path of
+	// the volume on the host.
+	Volume *Volume
+}
+
+// HostVolumeCreateResponse is used to return the created host volume.
+type HostVolumeCreateResponse struct {
+	Volume *Volume
+}
+
+// Create provisions a host volume on the node and registers it.
+func (h *HostVolumes) Create(req *HostVolumeCreateRequest, q *WriteOptions) (*Volume, *WriteMeta, error) {
+	if req == nil || req.Volume == nil || req.Volume.ID == "" {
+		return nil, nil, fmt.Errorf("missing volume ID")
+	}
+	var resp HostVolumeCreateResponse
+	wm, err := h.client.write("/v1/client/host-volume/create", req, &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp.Volume, wm, nil
+}
+
+// Delete deregisters a host volume created on the node and removes it with
+// the provisioner that created it.
+func (h *HostVolumes) Delete(nodeID, id string, q *WriteOptions) (*WriteMeta, error) {
+	if id == "" {
+		return nil, fmt.Errorf("missing volume ID")
+	}
+	path := "/v1/client/host-volume/delete?volume_id=" + url.QueryEscape(id)
+	if nodeID != "" {
+		path += "&node_id=" + url.QueryEscape(nodeID)
+	}
+	wm, err := h.client.write(path, nil, nil, q)
+	if err != nil {
+		return nil, err
+	}
+	return wm, nil
+}
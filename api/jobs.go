@@ -19,11 +19,19 @@ const (
 	// JobTypeBatch indicates a short-lived process
 	JobTypeBatch = "batch"
 
+	// JobTypeSysBatch indicates a short-lived process that runs once on every
+	// eligible node
+	JobTypeSysBatch = "sysbatch"
+
 	// PeriodicSpecCron is used for a cron spec.
 	PeriodicSpecCron = "cron"
 
 	// DefaultNamespace is the default namespace.
 	DefaultNamespace = "default"
+
+	// AllNamespacesNamespace is the namespace of the list requests returning
+	// the objects of all the namespaces the token may access.
+	AllNamespacesNamespace = "*"
 )
 
 const (
@@ -42,6 +50,14 @@ type JobsParseRequest struct {
 	// JobHCL is an hcl jobspec
 	JobHCL string
 
+	// HCLv1 is a flag as to if the jobspec is parsed with the HCLv1 parser
+	// instead of the HCL2 one
+	HCLv1 bool `json:"hclv1,omitempty"`
+
+	// Variables is the content of an HCL2 variable file setting the values
+	// of the variables of the jobspec
+	Variables string
+
 	// Canonicalize is a flag as to if the server should return default values
 	// for unset fields
 	Canonicalize bool
@@ -55,11 +71,17 @@ func (c *Client) Jobs() *Jobs {
 // Parse is used to convert the HCL repesentation of a Job to JSON server side.
 // To parse the HCL client side see package github.com/hashicorp/nomad/jobspec
 func (j *Jobs) ParseHCL(jobHCL string, canonicalize bool) (*Job, error) {
-	var job Job
 	req := &JobsParseRequest{
 		JobHCL:       jobHCL,
 		Canonicalize: canonicalize,
 	}
+	return j.ParseHCLOpts(req)
+}
+
+// ParseHCLOpts is used to convert the HCL representation of a Job to JSON
+// server side, with the parser and variables described by the request.
+func (j *Jobs) ParseHCLOpts(req *JobsParseRequest) (*Job, error) {
+	var job Job
 	_, err := j.client.write("/v1/jobs/parse", req, &job, nil)
 	return &job, err
 }
@@ -316,6 +338,41 @@ func (j *Jobs) Dispatch(jobID string, meta map[string]string,
 	return &resp, wm, nil
 }
 
+// DispatchIdempotent is used to dispatch a parameterized job with an
+// idempotency token. If a job was already dispatched from the parameterized
+// job with the same token, it is returned instead of dispatching a new one.
+func (j *Jobs) DispatchIdempotent(jobID string, meta map[string]string,
+	payload []byte, idempotencyToken string, q *WriteOptions) (*JobDispatchResponse, *WriteMeta, error) {
+	var resp JobDispatchResponse
+	req := &JobDispatchRequest{
+		JobID:            jobID,
+		Meta:             meta,
+		Payload:          payload,
+		IdempotencyToken: idempotencyToken,
+	}
+	wm, err := j.client.write("/v1/job/"+jobID+"/dispatch", req, &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, wm, nil
+}
+
+// DispatchBatch is used to dispatch many instances of a parameterized job at
+// once. The dispatched jobs are registered atomically.
+func (j *Jobs) DispatchBatch(jobID string, dispatches []*JobDispatchItem,
+	q *WriteOptions) (*JobBatchDispatchResponse, *WriteMeta, error) {
+	var resp JobBatchDispatchResponse
+	req := &JobBatchDispatchRequest{
+		JobID:      jobID,
+		Dispatches: dispatches,
+	}
+	wm, err := j.client.write("/v1/job/"+jobID+"/dispatch/batch", req, &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, wm, nil
+}
+
 // Revert is used to revert the given job to the passed version. If
 // enforceVersion is set, the job is only reverted if the current version is at
 // the passed version.
@@ -352,6 +409,45 @@ func (j *Jobs) Stable(jobID string, version uint64, stable bool,
 	return &resp, wm, nil
 }
 
+// Scale is used to set the count of a task group of a job. The message, error
+// flag and metadata are recorded with the scaling event. A nil count only
+// records the scaling event.
+func (j *Jobs) Scale(jobID, group string, count *int, message string, isError bool,
+	meta map[string]interface{}, q *WriteOptions) (*JobRegisterResponse, *WriteMeta, error) {
+
+	var count64 *int64
+	if count != nil {
+		count64 = helper.Int64ToPtr(int64(*count))
+	}
+	req := &ScalingRequest{
+		Count: count64,
+		Target: map[string]string{
+			"Job":   jobID,
+			"Group": group,
+		},
+		Error:   isError,
+		Message: message,
+		Meta:    meta,
+	}
+	var resp JobRegisterResponse
+	wm, err := j.client.write("/v1/job/"+jobID+"/scale", req, &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, wm, nil
+}
+
+// ScaleStatus is used to get the scaling status of the task groups of a job,
+// including their most recent scaling events.
+func (j *Jobs) ScaleStatus(jobID string, q *QueryOptions) (*JobScaleStatusResponse, *QueryMeta, error) {
+	var resp JobScaleStatusResponse
+	qm, err := j.client.query("/v1/job/"+jobID+"/scale", &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, qm, nil
+}
+
 // periodicForceResponse is used to deserialize a force response
 type periodicForceResponse struct {
 	EvalID string
@@ -359,28 +455,32 @@ type periodicForceResponse struct {
 
 // UpdateStrategy defines a task groups update strategy.
 type UpdateStrategy struct {
-	Stagger          *time.Duration `mapstructure:"stagger"`
-	MaxParallel      *int           `mapstructure:"max_parallel"`
-	HealthCheck      *string        `mapstructure:"health_check"`
-	MinHealthyTime   *time.Duration `mapstructure:"min_healthy_time"`
-	HealthyDeadline  *time.Duration `mapstructure:"healthy_deadline"`
-	ProgressDeadline *time.Duration `mapstructure:"progress_deadline"`
-	AutoRevert       *bool          `mapstructure:"auto_revert"`
-	Canary           *int           `mapstructure:"canary"`
+	Stagger            *time.Duration `mapstructure:"stagger"`
+	MaxParallel        *int           `mapstructure:"max_parallel"`
+	MaxParallelPercent *int           `mapstructure:"max_parallel_percent"`
+	HealthCheck        *string        `mapstructure:"health_check"`
+	MinHealthyTime     *time.Duration `mapstructure:"min_healthy_time"`
+	HealthyDeadline    *time.Duration `mapstructure:"healthy_deadline"`
+	ProgressDeadline   *time.Duration `mapstructure:"progress_deadline"`
+	AutoRevert         *bool          `mapstructure:"auto_revert"`
+	AutoPromote        *bool          `mapstructure:"auto_promote"`
+	Canary             *int           `mapstructure:"canary"`
 }
 
 // DefaultUpdateStrategy provides a baseline that can be used to upgrade
 // jobs with the old policy or for populating field defaults.
 func DefaultUpdateStrategy() *UpdateStrategy {
 	return &UpdateStrategy{
-		Stagger:          helper.TimeToPtr(30 * time.Second),
-		MaxParallel:      helper.IntToPtr(1),
-		HealthCheck:      helper.StringToPtr("checks"),
-		MinHealthyTime:   helper.TimeToPtr(10 * time.Second),
-		HealthyDeadline:  helper.TimeToPtr(5 * time.Minute),
-		ProgressDeadline: helper.TimeToPtr(10 * time.Minute),
-		AutoRevert:       helper.BoolToPtr(false),
-		Canary:           helper.IntToPtr(0),
+		Stagger:            helper.TimeToPtr(30 * time.Second),
+		MaxParallel:        helper.IntToPtr(1),
+		MaxParallelPercent: helper.IntToPtr(0),
+		HealthCheck:        helper.StringToPtr("checks"),
+		MinHealthyTime:     helper.TimeToPtr(10 * time.Second),
+		HealthyDeadline:    helper.TimeToPtr(5 * time.Minute),
+		ProgressDeadline:   helper.TimeToPtr(10 * time.Minute),
+		AutoRevert:         helper.BoolToPtr(false),
+		AutoPromote:        helper.BoolToPtr(false),
+		Canary:             helper.IntToPtr(0),
 	}
 }
 
@@ -399,6 +499,10 @@ func (u *UpdateStrategy) Copy() *UpdateStrategy {
 		copy.MaxParallel = helper.IntToPtr(*u.MaxParallel)
 	}
 
+	if u.MaxParallelPercent != nil {
+		copy.MaxParallelPercent = helper.IntToPtr(*u.MaxParallelPercent)
+	}
+
 	if u.HealthCheck != nil {
 		copy.HealthCheck = helper.StringToPtr(*u.HealthCheck)
 	}
@@ -419,6 +523,10 @@ func (u *UpdateStrategy) Copy() *UpdateStrategy {
 		copy.AutoRevert = helper.BoolToPtr(*u.AutoRevert)
 	}
 
+	if u.AutoPromote != nil {
+		copy.AutoPromote = helper.BoolToPtr(*u.AutoPromote)
+	}
+
 	if u.Canary != nil {
 		copy.Canary = helper.IntToPtr(*u.Canary)
 	}
@@ -439,6 +547,10 @@ func (u *UpdateStrategy) Merge(o *UpdateStrategy) {
 		u.MaxParallel = helper.IntToPtr(*o.MaxParallel)
 	}
 
+	if o.MaxParallelPercent != nil {
+		u.MaxParallelPercent = helper.IntToPtr(*o.MaxParallelPercent)
+	}
+
 	if o.HealthCheck != nil {
 		u.HealthCheck = helper.StringToPtr(*o.HealthCheck)
 	}
@@ -459,6 +571,10 @@ func (u *UpdateStrategy) Merge(o *UpdateStrategy) {
 		u.AutoRevert = helper.BoolToPtr(*o.AutoRevert)
 	}
 
+	if o.AutoPromote != nil {
+		u.AutoPromote = helper.BoolToPtr(*o.AutoPromote)
+	}
+
 	if o.Canary != nil {
 		u.Canary = helper.IntToPtr(*o.Canary)
 	}
@@ -471,6 +587,10 @@ func (u *UpdateStrategy) Canonicalize() {
 		u.MaxParallel = d.MaxParallel
 	}
 
+	if u.MaxParallelPercent == nil {
+		u.MaxParallelPercent = d.MaxParallelPercent
+	}
+
 	if u.Stagger == nil {
 		u.Stagger = d.Stagger
 	}
@@ -495,6 +615,10 @@ func (u *UpdateStrategy) Canonicalize() {
 		u.AutoRevert = d.AutoRevert
 	}
 
+	if u.AutoPromote == nil {
+		u.AutoPromote = d.AutoPromote
+	}
+
 	if u.Canary == nil {
 		u.Canary = d.Canary
 	}
@@ -514,6 +638,10 @@ func (u *UpdateStrategy) Empty() bool {
 		return false
 	}
 
+	if u.MaxParallelPercent != nil && *u.MaxParallelPercent != 0 {
+		return false
+	}
+
 	if u.HealthCheck != nil && *u.HealthCheck != "" {
 		return false
 	}
@@ -534,6 +662,10 @@ func (u *UpdateStrategy) Empty() bool {
 		return false
 	}
 
+	if u.AutoPromote != nil && *u.AutoPromote {
+		return false
+	}
+
 	if u.Canary != nil && *u.Canary != 0 {
 		return false
 	}
@@ -597,39 +729,73 @@ type ParameterizedJobConfig struct {
 	MetaOptional []string `mapstructure:"meta_optional"`
 }
 
+// GCConfig overrides the garbage collection thresholds of the servers for a
+// job or for the jobs of a namespace. A zero threshold is not overridden.
+type GCConfig struct {
+	JobThreshold   time.Duration `mapstructure:"job_threshold"`
+	EvalThreshold  time.Duration `mapstructure:"eval_threshold"`
+	AllocThreshold time.Duration `mapstructure:"alloc_threshold"`
+}
+
+// Multiregion is used to register a job in multiple regions and to
+// coordinate the rollout of its deployments across the regions.
+type Multiregion struct {
+	Strategy *MultiregionStrategy
+	Regions  []*MultiregionRegion
+}
+
+// MultiregionStrategy is the rollout strategy of a multiregion job.
+type MultiregionStrategy struct {
+	MaxParallel int    `mapstructure:"max_parallel"`
+	OnFailure   string `mapstructure:"on_failure"`
+}
+
+// MultiregionRegion describes a region of a multiregion job and the values
+// that override those of the job in the region.
+type MultiregionRegion struct {
+	Name        string
+	Count       int
+	Datacenters []string
+	Meta        map[string]string
+}
+
 // Job is used to serialize a job.
 type Job struct {
-	Stop              *bool
-	Region            *string
-	Namespace         *string
-	ID                *string
-	ParentID          *string
-	Name              *string
-	Type              *string
-	Priority          *int
-	AllAtOnce         *bool `mapstructure:"all_at_once"`
-	Datacenters       []string
-	Constraints       []*Constraint
-	Affinities        []*Affinity
-	TaskGroups        []*TaskGroup
-	Update            *UpdateStrategy
-	Spreads           []*Spread
-	Periodic          *PeriodicConfig
-	ParameterizedJob  *ParameterizedJobConfig
-	Dispatched        bool
-	Payload           []byte
-	Reschedule        *ReschedulePolicy
-	Migrate           *MigrateStrategy
-	Meta              map[string]string
-	VaultToken        *string `mapstructure:"vault_token"`
-	Status            *string
-	StatusDescription *string
-	Stable            *bool
-	Version           *uint64
-	SubmitTime        *int64
-	CreateIndex       *uint64
-	ModifyIndex       *uint64
-	JobModifyIndex    *uint64
+	Stop                     *bool
+	Region                   *string
+	Namespace                *string
+	ID                       *string
+	ParentID                 *string
+	Name                     *string
+	Type                     *string
+	Priority                 *int
+	AllAtOnce                *bool `mapstructure:"all_at_once"`
+	Datacenters              []string
+	NodePool                 *string `mapstructure:"node_pool"`
+	Constraints              []*Constraint
+	Affinities               []*Affinity
+	TaskGroups               []*TaskGroup
+	Update                   *UpdateStrategy
+	Spreads                  []*Spread
+	Periodic                 *PeriodicConfig
+	ParameterizedJob         *ParameterizedJobConfig
+	Multiregion              *Multiregion
+	GC                       *GCConfig
+	Dispatched               bool
+	DispatchIdempotencyToken string
+	Payload                  []byte
+	Reschedule               *ReschedulePolicy
+	Migrate                  *MigrateStrategy
+	Meta                     map[string]string
+	VaultToken               *string `mapstructure:"vault_token"`
+	Status                   *string
+	StatusDescription        *string
+	Stable                   *bool
+	Version                  *uint64
+	SubmitTime               *int64
+	CreateIndex              *uint64
+	ModifyIndex              *uint64
+	JobModifyIndex           *uint64
 }
 
 // IsPeriodic returns whether a job is periodic.
@@ -670,6 +836,9 @@ func (j *Job) Canonicalize() {
 	if j.Type == nil {
 		j.Type = helper.StringToPtr("service")
 	}
+	if j.NodePool == nil {
+		j.NodePool = helper.StringToPtr(DefaultNodePool)
+	}
 	if j.AllAtOnce == nil {
 		j.AllAtOnce = helper.BoolToPtr(false)
 	}
@@ -755,12 +924,14 @@ type TaskGroupSummary struct {
 	Running  int
 	Starting int
 	Lost     int
+	Unknown  int
 }
 
 // JobListStub is used to return a subset of information about
 // jobs during list operations.
 type JobListStub struct {
 	ID                string
+	Namespace         string
 	ParentID          string
 	Name              string
 	Type              string
@@ -1028,9 +1199,10 @@ type DesiredUpdates struct {
 }
 
 type JobDispatchRequest struct {
-	JobID   string
-	Payload []byte
-	Meta    map[string]string
+	JobID            string
+	Payload          []byte
+	Meta             map[string]string
+	IdempotencyToken string
 }
 
 type JobDispatchResponse struct {
@@ -1041,6 +1213,72 @@ type JobDispatchResponse struct {
 	WriteMeta
 }
 
+// JobBatchDispatchRequest is used to dispatch many instances of a
+// parameterized job at once.
+type JobBatchDispatchRequest struct {
+	JobID      string
+	Dispatches []*JobDispatchItem
+}
+
+// JobDispatchItem is a single dispatch of a batch.
+type JobDispatchItem struct {
+	Payload          []byte
+	Meta             map[string]string
+	IdempotencyToken string
+}
+
+// JobBatchDispatchResponse contains the result of each dispatch of a batch, in
+// the order of the request.
+type JobBatchDispatchResponse struct {
+	Dispatches []*JobDispatchResponse
+	WriteMeta
+}
+
+// ScalingRequest is the payload of a job scale request.
+type ScalingRequest struct {
+	Count   *int64
+	Target  map[string]string
+	Message string
+	Error   bool
+	Meta    map[string]interface{}
+}
+
+// ScalingEvent is a scaling request of a task group: when it happened, who
+// requested it and why.
+type ScalingEvent struct {
+	Time          int64
+	Count         *int64
+	PreviousCount int64
+	Message       string
+	Error         bool
+	Meta          map[string]interface{}
+	AccessorID    string
+	EvalID        *string
+	CreateIndex   uint64
+}
+
+// JobScaleStatusResponse is the scaling status of the task groups of a job.
+type JobScaleStatusResponse struct {
+	JobID          string
+	Namespace      string
+	JobCreateIndex uint64
+	JobModifyIndex uint64
+	JobStopped     bool
+	TaskGroups     map[string]TaskGroupScaleStatus
+}
+
+// TaskGroupScaleStatus is the scaling status of a task group: its desired
+// count, the number of its allocations in each state and its most recent
+// scaling events.
+type TaskGroupScaleStatus struct {
+	Desired   int
+	Placed    int
+	Running   int
+	Healthy   int
+	Unhealthy int
+	Events    []ScalingEvent
+}
+
 // JobVersionsResponse is used for a job get versions request
 type JobVersionsResponse struct {
 	Versions []*Job
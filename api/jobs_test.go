@@ -139,6 +139,7 @@ func TestJobs_Canonicalize(t *testing.T) {
 				ParentID:          helper.StringToPtr(""),
 				Priority:          helper.IntToPtr(50),
 				AllAtOnce:         helper.BoolToPtr(false),
+				NodePool:          helper.StringToPtr(DefaultNodePool),
 				VaultToken:        helper.StringToPtr(""),
 				Status:            helper.StringToPtr(""),
 				StatusDescription: helper.StringToPtr(""),
@@ -210,6 +211,7 @@ func TestJobs_Canonicalize(t *testing.T) {
 				ParentID:          helper.StringToPtr("lol"),
 				Priority:          helper.IntToPtr(50),
 				AllAtOnce:         helper.BoolToPtr(false),
+				NodePool:          helper.StringToPtr(DefaultNodePool),
 				VaultToken:        helper.StringToPtr(""),
 				Stop:              helper.BoolToPtr(false),
 				Stable:            helper.BoolToPtr(false),
@@ -344,6 +346,7 @@ func TestJobs_Canonicalize(t *testing.T) {
 				Region:            helper.StringToPtr("global"),
 				Type:              helper.StringToPtr("service"),
 				AllAtOnce:         helper.BoolToPtr(false),
+				NodePool:          helper.StringToPtr(DefaultNodePool),
 				VaultToken:        helper.StringToPtr(""),
 				Stop:              helper.BoolToPtr(false),
 				Stable:            helper.BoolToPtr(false),
@@ -355,14 +358,16 @@ func TestJobs_Canonicalize(t *testing.T) {
 				JobModifyIndex:    helper.Uint64ToPtr(0),
 				Datacenters:       []string{"dc1"},
 				Update: &UpdateStrategy{
-					Stagger:          helper.TimeToPtr(30 * time.Second),
-					MaxParallel:      helper.IntToPtr(1),
-					HealthCheck:      helper.StringToPtr("checks"),
-					MinHealthyTime:   helper.TimeToPtr(10 * time.Second),
-					HealthyDeadline:  helper.TimeToPtr(5 * time.Minute),
-					ProgressDeadline: helper.TimeToPtr(10 * time.Minute),
-					AutoRevert:       helper.BoolToPtr(false),
-					Canary:           helper.IntToPtr(0),
+					Stagger:            helper.TimeToPtr(30 * time.Second),
+					MaxParallel:        helper.IntToPtr(1),
+					MaxParallelPercent: helper.IntToPtr(0),
+					HealthCheck:        helper.StringToPtr("checks"),
+					MinHealthyTime:     helper.TimeToPtr(10 * time.Second),
+					HealthyDeadline:    helper.TimeToPtr(5 * time.Minute),
+					ProgressDeadline:   helper.TimeToPtr(10 * time.Minute),
+					AutoRevert:         helper.BoolToPtr(false),
+					AutoPromote:        helper.BoolToPtr(false),
+					Canary:             helper.IntToPtr(0),
 				},
 				TaskGroups: []*TaskGroup{
 					{
@@ -389,14 +394,16 @@ func TestJobs_Canonicalize(t *testing.T) {
 						},
 
 						Update: &UpdateStrategy{
-							Stagger:          helper.TimeToPtr(30 * time.Second),
-							MaxParallel:      helper.IntToPtr(1),
-							HealthCheck:      helper.StringToPtr("checks"),
-							MinHealthyTime:   helper.TimeToPtr(10 * time.Second),
-							HealthyDeadline:  helper.TimeToPtr(5 * time.Minute),
-							ProgressDeadline: helper.TimeToPtr(10 * time.Minute),
-							AutoRevert:       helper.BoolToPtr(false),
-							Canary:           helper.IntToPtr(0),
+							Stagger:            helper.TimeToPtr(30 * time.Second),
+							MaxParallel:        helper.IntToPtr(1),
+							MaxParallelPercent: helper.IntToPtr(0),
+							HealthCheck:        helper.StringToPtr("checks"),
+							MinHealthyTime:     helper.TimeToPtr(10 * time.Second),
+							HealthyDeadline:    helper.TimeToPtr(5 * time.Minute),
+							ProgressDeadline:   helper.TimeToPtr(10 * time.Minute),
+							AutoRevert:         helper.BoolToPtr(false),
+							AutoPromote:        helper.BoolToPtr(false),
+							Canary:             helper.IntToPtr(0),
 						},
 						Migrate: DefaultMigrateStrategy(),
 						Tasks: []*Task{
@@ -431,6 +438,7 @@ func TestJobs_Canonicalize(t *testing.T) {
 										CanaryTags:  []string{"canary", "global", "cache"},
 										PortLabel:   "db",
 										AddressMode: "auto",
+										Provider:    "consul",
 										Checks: []ServiceCheck{
 											{
 												Name:     "alive",
@@ -493,6 +501,7 @@ func TestJobs_Canonicalize(t *testing.T) {
 				Type:              helper.StringToPtr("service"),
 				Priority:          helper.IntToPtr(50),
 				AllAtOnce:         helper.BoolToPtr(false),
+				NodePool:          helper.StringToPtr(DefaultNodePool),
 				VaultToken:        helper.StringToPtr(""),
 				Stop:              helper.BoolToPtr(false),
 				Stable:            helper.BoolToPtr(false),
@@ -564,6 +573,7 @@ func TestJobs_Canonicalize(t *testing.T) {
 				ParentID:          helper.StringToPtr("lol"),
 				Priority:          helper.IntToPtr(50),
 				AllAtOnce:         helper.BoolToPtr(false),
+				NodePool:          helper.StringToPtr(DefaultNodePool),
 				VaultToken:        helper.StringToPtr(""),
 				Stop:              helper.BoolToPtr(false),
 				Stable:            helper.BoolToPtr(false),
@@ -574,14 +584,16 @@ func TestJobs_Canonicalize(t *testing.T) {
 				ModifyIndex:       helper.Uint64ToPtr(0),
 				JobModifyIndex:    helper.Uint64ToPtr(0),
 				Update: &UpdateStrategy{
-					Stagger:          helper.TimeToPtr(1 * time.Second),
-					MaxParallel:      helper.IntToPtr(1),
-					HealthCheck:      helper.StringToPtr("checks"),
-					MinHealthyTime:   helper.TimeToPtr(10 * time.Second),
-					HealthyDeadline:  helper.TimeToPtr(6 * time.Minute),
-					ProgressDeadline: helper.TimeToPtr(7 * time.Minute),
-					AutoRevert:       helper.BoolToPtr(false),
-					Canary:           helper.IntToPtr(0),
+					Stagger:            helper.TimeToPtr(1 * time.Second),
+					MaxParallel:        helper.IntToPtr(1),
+					MaxParallelPercent: helper.IntToPtr(0),
+					HealthCheck:        helper.StringToPtr("checks"),
+					MinHealthyTime:     helper.TimeToPtr(10 * time.Second),
+					HealthyDeadline:    helper.TimeToPtr(6 * time.Minute),
+					ProgressDeadline:   helper.TimeToPtr(7 * time.Minute),
+					AutoRevert:         helper.BoolToPtr(false),
+					AutoPromote:        helper.BoolToPtr(false),
+					Canary:             helper.IntToPtr(0),
 				},
 				TaskGroups: []*TaskGroup{
 					{
@@ -607,14 +619,16 @@ func TestJobs_Canonicalize(t *testing.T) {
 							Unlimited:     helper.BoolToPtr(true),
 						},
 						Update: &UpdateStrategy{
-							Stagger:          helper.TimeToPtr(2 * time.Second),
-							MaxParallel:      helper.IntToPtr(2),
-							HealthCheck:      helper.StringToPtr("manual"),
-							MinHealthyTime:   helper.TimeToPtr(1 * time.Second),
-							HealthyDeadline:  helper.TimeToPtr(6 * time.Minute),
-							ProgressDeadline: helper.TimeToPtr(7 * time.Minute),
-							AutoRevert:       helper.BoolToPtr(true),
-							Canary:           helper.IntToPtr(1),
+							Stagger:            helper.TimeToPtr(2 * time.Second),
+							MaxParallel:        helper.IntToPtr(2),
+							MaxParallelPercent: helper.IntToPtr(0),
+							HealthCheck:        helper.StringToPtr("manual"),
+							MinHealthyTime:     helper.TimeToPtr(1 * time.Second),
+							HealthyDeadline:    helper.TimeToPtr(6 * time.Minute),
+							ProgressDeadline:   helper.TimeToPtr(7 * time.Minute),
+							AutoRevert:         helper.BoolToPtr(true),
+							AutoPromote:        helper.BoolToPtr(false),
+							Canary:             helper.IntToPtr(1),
 						},
 						Migrate: DefaultMigrateStrategy(),
 						Tasks: []*Task{
@@ -649,14 +663,16 @@ func TestJobs_Canonicalize(t *testing.T) {
 							Unlimited:     helper.BoolToPtr(true),
 						},
 						Update: &UpdateStrategy{
-							Stagger:          helper.TimeToPtr(1 * time.Second),
-							MaxParallel:      helper.IntToPtr(1),
-							HealthCheck:      helper.StringToPtr("checks"),
-							MinHealthyTime:   helper.TimeToPtr(10 * time.Second),
-							HealthyDeadline:  helper.TimeToPtr(6 * time.Minute),
-							ProgressDeadline: helper.TimeToPtr(7 * time.Minute),
-							AutoRevert:       helper.BoolToPtr(false),
-							Canary:           helper.IntToPtr(0),
+							Stagger:            helper.TimeToPtr(1 * time.Second),
+							MaxParallel:        helper.IntToPtr(1),
+							MaxParallelPercent: helper.IntToPtr(0),
+							HealthCheck:        helper.StringToPtr("checks"),
+							MinHealthyTime:     helper.TimeToPtr(10 * time.Second),
+							HealthyDeadline:    helper.TimeToPtr(6 * time.Minute),
+							ProgressDeadline:   helper.TimeToPtr(7 * time.Minute),
+							AutoRevert:         helper.BoolToPtr(false),
+							AutoPromote:        helper.BoolToPtr(false),
+							Canary:             helper.IntToPtr(0),
 						},
 						Migrate: DefaultMigrateStrategy(),
 						Tasks: []*Task{
@@ -1476,3 +1492,45 @@ func TestJobs_Summary_WithACL(t *testing.T) {
 	// Check that the result is what we expect
 	assert.Equal(*job.ID, result.JobID)
 }
+
+func TestJobs_Scale(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	c, s := makeClient(t, nil, nil)
+	defer s.Stop()
+	jobs := c.Jobs()
+
+	job := testJob()
+	_, wm, err := jobs.Register(job, nil)
+	require.NoError(err)
+	assertWriteMeta(t, wm)
+	group := *job.TaskGroups[0].Name
+
+	// Scale the task group
+	resp, wm, err := jobs.Scale(*job.ID, group, helper.IntToPtr(2), "scaling up", false,
+		map[string]interface{}{"source": "test"}, nil)
+	require.NoError(err)
+	require.NotEmpty(resp.EvalID)
+	assertWriteMeta(t, wm)
+
+	// Record an error
+	resp, _, err = jobs.Scale(*job.ID, group, nil, "failed to scale", true, nil, nil)
+	require.NoError(err)
+	require.Empty(resp.EvalID)
+
+	// Query the scaling status
+	status, qm, err := jobs.ScaleStatus(*job.ID, nil)
+	require.NoError(err)
+	assertQueryMeta(t, qm)
+	tgStatus := status.TaskGroups[group]
+	require.Equal(2, tgStatus.Desired)
+	require.Len(tgStatus.Events, 2)
+	require.True(tgStatus.Events[0].Error)
+	require.Equal("failed to scale", tgStatus.Events[0].Message)
+	require.EqualValues(2, *tgStatus.Events[1].Count)
+	require.Equal("test", tgStatus.Events[1].Meta["source"])
+
+	// Scaling an unknown task group fails
+	_, _, err = jobs.Scale(*job.ID, "missing", helper.IntToPtr(2), "", false, nil, nil)
+	require.Error(err)
+}
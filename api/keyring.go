@@ -0,0 +1,71 @@
+package api
+
+import (
+	"fmt"
+)
+
+// Keyring is used to manage the root keys variables are encrypted with.
+type Keyring struct {
+	client *Client
+}
+
+// Keyring returns a new handle on the root keyring.
+func (c *Client) Keyring() *Keyring {
+	return &Keyring{client: c}
+}
+
+// List is used to list the metadata of the root keys.
+func (k *Keyring) List(q *QueryOptions) ([]*RootKeyMeta, *QueryMeta, error) {
+	var resp []*RootKeyMeta
+	qm, err := k.client.query("/v1/operator/keyring/keys", &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, qm, nil
+}
+
+// Rotate is used to create a new active root key. A full rotation also
+// rewraps the data keys of all the variables with the new root key, so that
+// the previous root keys can be removed.
+func (k *Keyring) Rotate(full bool, q *WriteOptions) (*KeyringRotateResponse, *WriteMeta, error) {
+	path := "/v1/operator/keyring/rotate"
+	if full {
+		path += "?full=true"
+	}
+	var resp KeyringRotateResponse
+	wm, err := k.client.write(path, nil, &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, wm, nil
+}
+
+// Delete is used to remove an inactive root key no variable is encrypted
+// with anymore.
+func (k *Keyring) Delete(keyID string, q *WriteOptions) (*WriteMeta, error) {
+	if keyID == "" {
+		return nil, fmt.Errorf("missing root key ID")
+	}
+	wm, err := k.client.delete("/v1/operator/keyring/key/"+keyID, nil, q)
+	if err != nil {
+		return nil, err
+	}
+	return wm, nil
+}
+
+// RootKeyMeta is the metadata of a root key.
+type RootKeyMeta struct {
+	KeyID       string
+	Algorithm   string
+	Active      bool
+	Wrapped     bool
+	CreateIndex uint64
+	ModifyIndex uint64
+	CreateTime  int64
+}
+
+// KeyringRotateResponse is the response of a root key rotation.
+type KeyringRotateResponse struct {
+	Key       *RootKeyMeta
+	Rewrapped int
+}
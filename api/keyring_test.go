@@ -0,0 +1,50 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyring_RotateDelete(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	c, s := makeClient(t, nil, nil)
+	defer s.Stop()
+	keyring := c.Keyring()
+
+	// The leader creates the first root key
+	var keys []*RootKeyMeta
+	testutil.WaitForResult(func() (bool, error) {
+		var err error
+		keys, _, err = keyring.List(nil)
+		if err != nil {
+			return false, err
+		}
+		return len(keys) == 1, nil
+	}, func(err error) {
+		t.Fatalf("root key not created: %v", err)
+	})
+	require.True(keys[0].Active)
+
+	// The active key can't be removed
+	_, err := keyring.Delete(keys[0].KeyID, nil)
+	require.Error(err)
+
+	// Rotate the key and remove the previous one
+	resp, wm, err := keyring.Rotate(true, nil)
+	require.NoError(err)
+	assertWriteMeta(t, wm)
+	require.True(resp.Key.Active)
+	require.NotEqual(keys[0].KeyID, resp.Key.KeyID)
+
+	_, err = keyring.Delete(keys[0].KeyID, nil)
+	require.NoError(err)
+
+	keys, qm, err := keyring.List(nil)
+	require.NoError(err)
+	assertQueryMeta(t, qm)
+	require.Len(keys, 1)
+	require.Equal(resp.Key.KeyID, keys[0].KeyID)
+}
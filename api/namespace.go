@@ -70,6 +70,7 @@ type Namespace struct {
 	Name        string
 	Description string
 	Quota       string
+	GC          *GCConfig
 	CreateIndex uint64
 	ModifyIndex uint64
 }
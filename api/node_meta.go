@@ -0,0 +1,67 @@
+package api
+
+// NodeMeta is used to read and update the metadata of the nodes
+type NodeMeta struct {
+	client *Client
+}
+
+// NodeMeta returns a handle on the node metadata endpoints.
+func (c *Client) NodeMeta() *NodeMeta {
+	return &NodeMeta{client: c}
+}
+
+// NodeMetaApplyRequest is used to set and unset the dynamic metadata of a
+// node.
+type NodeMetaApplyRequest struct {
+	// NodeID is the node to update, which defaults to the local node of the
+	// agent.
+	NodeID string
+
+	// Meta maps the keys to set to their value, and the keys to unset to nil.
+	Meta map[string]*string
+}
+
+// NodeMetaResponse is used to return the metadata of a node.
+type NodeMetaResponse struct {
+	// Meta is the effective metadata of the node, which is the static
+	// metadata overridden by the dynamic one.
+	Meta map[string]string
+
+	// Static is the metadata set by the configuration of the client
+	Static map[string]string
+
+	// Dynamic is the metadata set at runtime, which is the dynamic metadata
+	// of the node
+	Dynamic map[string]string
+}
+
+// Read returns the metadata of the node, which defaults to the local node of
+// the agent if nodeID is empty.
+func (n *NodeMeta) Read(nodeID string, q *QueryOptions) (*NodeMetaResponse, error) {
+	if q == nil {
+		q = &QueryOptions{}
+	}
+	if nodeID != "" {
+		if q.Params == nil {
+			q.Params = make(map[string]string)
+		}
+		q.Params["node_id"] = nodeID
+	}
+
+	var resp NodeMetaResponse
+	if _, err := n.client.query("/v1/client/metadata", &resp, q); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Apply sets and unsets the dynamic metadata of the node through its client,
+// which persists it. The metadata is the same as updated by
+// Nodes().UpdateDynamicMeta.
+func (n *NodeMeta) Apply(req *NodeMetaApplyRequest, q *WriteOptions) (*NodeMetaResponse, error) {
+	var resp NodeMetaResponse
+	if _, err := n.client.write("/v1/client/metadata", req, &resp, q); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
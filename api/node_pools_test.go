@@ -0,0 +1,45 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodePools_CRUD(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	c, s := makeClient(t, nil, nil)
+	defer s.Stop()
+	pools := c.NodePools()
+
+	// Register a node pool
+	pool := &NodePool{
+		Name:        "gpu",
+		Description: "nodes with GPUs",
+		Meta:        map[string]string{"owner": "ml"},
+	}
+	wm, err := pools.Register(pool, nil)
+	require.NoError(err)
+	assertWriteMeta(t, wm)
+
+	// Query it back out
+	out, qm, err := pools.Info("gpu", nil)
+	require.NoError(err)
+	assertQueryMeta(t, qm)
+	require.Equal(pool.Description, out.Description)
+	require.Equal(pool.Meta, out.Meta)
+
+	resp, _, err := pools.PrefixList("gp", nil)
+	require.NoError(err)
+	require.Len(resp, 1)
+
+	// Delete it
+	wm, err = pools.Delete("gpu", nil)
+	require.NoError(err)
+	assertWriteMeta(t, wm)
+
+	_, _, err = pools.Info("gpu", nil)
+	require.Error(err)
+	require.Contains(err.Error(), "not found")
+}
@@ -267,6 +267,7 @@ func (n *Nodes) monitorDrainAllocs(ctx context.Context, nodeID string, ignoreSys
 
 	q := QueryOptions{AllowStale: true}
 	initial := make(map[string]*Allocation, 4)
+	lastRunning := -1
 
 	for {
 		allocs, meta, err := n.Allocations(nodeID, &q)
@@ -325,7 +326,7 @@ func (n *Nodes) monitorDrainAllocs(ctx context.Context, nodeID string, ignoreSys
 			}
 
 			// Track how many allocs are still running
-			if ignoreSys && a.Job.Type != nil && *a.Job.Type == structs.JobTypeSystem {
+			if ignoreSys && a.Job.Type != nil && (*a.Job.Type == structs.JobTypeSystem || *a.Job.Type == structs.JobTypeSysBatch) {
 				continue
 			}
 
@@ -344,6 +345,17 @@ func (n *Nodes) monitorDrainAllocs(ctx context.Context, nodeID string, ignoreSys
 			}
 			return
 		}
+
+		// Report the progress of the drain whenever it changes
+		if runningAllocs != lastRunning {
+			lastRunning = runningAllocs
+			msg := Messagef(MonitorMsgLevelNormal, "%d allocation(s) remaining on node %q", runningAllocs, nodeID)
+			select {
+			case allocCh <- msg:
+			case <-ctx.Done():
+				return
+			}
+		}
 	}
 }
 
@@ -383,6 +395,46 @@ func (n *Nodes) ToggleEligibility(nodeID string, eligible bool, q *WriteOptions)
 	return &resp, nil
 }
 
+// NodeUpdateDynamicMetaRequest is used to update the dynamic metadata of a
+// node.
+type NodeUpdateDynamicMetaRequest struct {
+	NodeID string
+
+	// Set is the set of keys to add or overwrite
+	Set map[string]string
+
+	// Unset is the set of keys to remove
+	Unset []string
+}
+
+// NodeDynamicMetaUpdateResponse is used to respond to a node dynamic metadata
+// update
+type NodeDynamicMetaUpdateResponse struct {
+	NodeModifyIndex uint64
+	EvalIDs         []string
+	EvalCreateIndex uint64
+	WriteMeta
+}
+
+// UpdateDynamicMeta is used to set and unset keys of the dynamic metadata of
+// the node. Constraints and affinities may target the dynamic metadata using
+// ${dynamic_meta.<key>}.
+func (n *Nodes) UpdateDynamicMeta(nodeID string, set map[string]string, unset []string, q *WriteOptions) (*NodeDynamicMetaUpdateResponse, error) {
+	req := &NodeUpdateDynamicMetaRequest{
+		NodeID: nodeID,
+		Set:    set,
+		Unset:  unset,
+	}
+
+	var resp NodeDynamicMetaUpdateResponse
+	wm, err := n.client.write("/v1/node/"+nodeID+"/meta", req, &resp, q)
+	if err != nil {
+		return nil, err
+	}
+	resp.WriteMeta = *wm
+	return &resp, nil
+}
+
 // Allocations is used to return the allocations associated with a node.
 func (n *Nodes) Allocations(nodeID string, q *QueryOptions) ([]*Allocation, *QueryMeta, error) {
 	var resp []*Allocation
@@ -394,6 +446,18 @@ func (n *Nodes) Allocations(nodeID string, q *QueryOptions) ([]*Allocation, *Que
 	return resp, qm, nil
 }
 
+// Events is used to query the retained events of a node, oldest first. The
+// events can be filtered by subsystem by setting the "subsystem" query
+// parameter.
+func (n *Nodes) Events(nodeID string, q *QueryOptions) ([]*NodeEvent, *QueryMeta, error) {
+	var resp []*NodeEvent
+	qm, err := n.client.query("/v1/node/"+nodeID+"/events", &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, qm, nil
+}
+
 // ForceEvaluate is used to force-evaluate an existing node.
 func (n *Nodes) ForceEvaluate(nodeID string, q *WriteOptions) (string, *WriteMeta, error) {
 	var resp nodeEvalResponse
@@ -452,7 +516,9 @@ type Node struct {
 	ReservedResources     *NodeReservedResources
 	Links                 map[string]string
 	Meta                  map[string]string
+	DynamicMeta           map[string]string
 	NodeClass             string
+	NodePool              string
 	Drain                 bool
 	DrainStrategy         *DrainStrategy
 	SchedulingEligibility string
@@ -461,10 +527,21 @@ type Node struct {
 	StatusUpdatedAt       int64
 	Events                []*NodeEvent
 	Drivers               map[string]*DriverInfo
+	HostVolumes           map[string]*HostVolumeInfo
 	CreateIndex           uint64
 	ModifyIndex           uint64
 }
 
+// HostVolumeInfo is used to return the configuration and health of a host
+// volume exposed by a node.
+type HostVolumeInfo struct {
+	Name              string
+	Path              string
+	ReadOnly          bool
+	Healthy           bool
+	HealthDescription string
+}
+
 type NodeResources struct {
 	Cpu      NodeCpuResources
 	Memory   NodeMemoryResources
@@ -556,10 +633,12 @@ func (d *DrainStrategy) String() string {
 }
 
 const (
-	NodeEventSubsystemDrain     = "Drain"
-	NodeEventSubsystemDriver    = "Driver"
-	NodeEventSubsystemHeartbeat = "Heartbeat"
-	NodeEventSubsystemCluster   = "Cluster"
+	NodeEventSubsystemDrain       = "Drain"
+	NodeEventSubsystemDriver      = "Driver"
+	NodeEventSubsystemHeartbeat   = "Heartbeat"
+	NodeEventSubsystemCluster     = "Cluster"
+	NodeEventSubsystemFingerprint = "Fingerprint"
+	NodeEventSubsystemStorage     = "Storage"
 )
 
 // NodeEvent is a single unit representing a node’s state change
@@ -706,6 +785,7 @@ type NodeListStub struct {
 	Datacenter            string
 	Name                  string
 	NodeClass             string
+	NodePool              string
 	Version               string
 	Drain                 bool
 	SchedulingEligibility string
@@ -1,6 +1,16 @@
 package api
 
-import "strconv"
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"time"
+)
 
 // Operator can be used to perform low-level operator tasks for Nomad.
 type Operator struct {
@@ -109,11 +119,96 @@ func (op *Operator) RaftRemovePeerByID(id string, q *WriteOptions) error {
 	return nil
 }
 
+// Snapshot is used to take a snapshot of the state of the cluster. The
+// returned reader streams the snapshot archive and must be closed. Reading
+// the archive fails if it doesn't match the checksum sent by the server.
+func (op *Operator) Snapshot(q *QueryOptions) (io.ReadCloser, error) {
+	r, err := op.c.newRequest("GET", "/v1/operator/snapshot")
+	if err != nil {
+		return nil, err
+	}
+	r.setQueryOptions(q)
+	_, resp, err := requireOK(op.c.doRequest(r))
+	if err != nil {
+		return nil, err
+	}
+
+	digest := resp.Header.Get("Digest")
+	if !strings.HasPrefix(digest, "sha-256=") {
+		return resp.Body, nil
+	}
+	return &checksumReader{
+		ReadCloser: resp.Body,
+		hash:       sha256.New(),
+		expected:   strings.TrimPrefix(digest, "sha-256="),
+	}, nil
+}
+
+// SnapshotRestore is used to restore a snapshot of the state of the cluster
+// read from the reader.
+func (op *Operator) SnapshotRestore(in io.Reader, q *WriteOptions) (*WriteMeta, error) {
+	r, err := op.c.newRequest("PUT", "/v1/operator/snapshot")
+	if err != nil {
+		return nil, err
+	}
+	r.setWriteOptions(q)
+	r.body = in
+	rtt, resp, err := requireOK(op.c.doRequest(r))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	wm := &WriteMeta{RequestTime: rtt}
+	parseWriteMeta(resp, wm)
+	return wm, nil
+}
+
+// checksumReader verifies the SHA-256 checksum of the data read once the end
+// of the data is reached.
+type checksumReader struct {
+	io.ReadCloser
+	hash     hash.Hash
+	expected string
+}
+
+func (r *checksumReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.hash.Write(p[:n])
+	if err == io.EOF {
+		if sum := base64.StdEncoding.EncodeToString(r.hash.Sum(nil)); sum != r.expected {
+			return n, fmt.Errorf("snapshot checksum mismatch: expected %q, got %q", r.expected, sum)
+		}
+	}
+	return n, err
+}
+
+// SchedulerAlgorithm is an enum string that encapsulates the valid options
+// for the algorithm used to score nodes for placement.
+type SchedulerAlgorithm string
+
+const (
+	SchedulerAlgorithmBinpack SchedulerAlgorithm = "binpack"
+	SchedulerAlgorithmSpread  SchedulerAlgorithm = "spread"
+)
+
 type SchedulerConfiguration struct {
+	// SchedulerAlgorithm lets you select between available scheduling
+	// algorithms.
+	SchedulerAlgorithm SchedulerAlgorithm
+
 	// PreemptionConfig specifies whether to enable eviction of lower
 	// priority jobs to place higher priority jobs.
 	PreemptionConfig PreemptionConfig
 
+	// NumSchedulers overrides the number of scheduling workers run by each
+	// server. Zero keeps the num_schedulers of each server's configuration.
+	NumSchedulers int
+
+	// DisableEvalPriority dequeues ready evaluations in the order they were
+	// created instead of by the priority of their job.
+	DisableEvalPriority bool
+
 	// CreateIndex/ModifyIndex store the create/modify indexes of this configuration.
 	CreateIndex uint64
 	ModifyIndex uint64
@@ -139,7 +234,9 @@ type SchedulerSetConfigurationResponse struct {
 
 // PreemptionConfig specifies whether preemption is enabled based on scheduler type
 type PreemptionConfig struct {
-	SystemSchedulerEnabled bool
+	SystemSchedulerEnabled  bool
+	ServiceSchedulerEnabled bool
+	BatchSchedulerEnabled   bool
 }
 
 // SchedulerGetConfiguration is used to query the current Scheduler configuration.
@@ -174,3 +271,47 @@ func (op *Operator) SchedulerCASConfiguration(conf *SchedulerConfiguration, q *W
 
 	return &out, wm, nil
 }
+
+// EvalBrokerStats is the state of the eval broker of the leader, along with
+// the tunables it was configured with.
+type EvalBrokerStats struct {
+	TotalReady                   int
+	TotalUnacked                 int
+	TotalBlocked                 int
+	TotalWaiting                 int
+	ByScheduler                  map[string]*EvalBrokerSchedulerStats
+	NackTimeout                  time.Duration
+	DeliveryLimit                int
+	InitialNackReenqueueDelay    time.Duration
+	SubsequentNackReenqueueDelay time.Duration
+}
+
+// EvalBrokerSchedulerStats is the state of the queue of a scheduler in the
+// eval broker.
+type EvalBrokerSchedulerStats struct {
+	Ready           int
+	Unacked         int
+	Nacked          int
+	OldestReadyWait time.Duration
+}
+
+// EvalBrokerStats is used to query the stats of the eval broker of the
+// leader.
+func (op *Operator) EvalBrokerStats(q *QueryOptions) (*EvalBrokerStats, *QueryMeta, error) {
+	var resp EvalBrokerStats
+	qm, err := op.c.query("/v1/operator/eval-broker", &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, qm, nil
+}
+
+// Metrics returns the JSON encoded metrics of the agent.
+func (op *Operator) Metrics(q *QueryOptions) ([]byte, error) {
+	r, err := op.c.rawQuery("/v1/metrics", q)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return ioutil.ReadAll(r)
+}
@@ -22,12 +22,16 @@ type AutopilotConfiguration struct {
 	// be behind before being considered unhealthy.
 	MaxTrailingLogs uint64
 
+	// MinQuorum is the minimum number of alive servers the cluster must have
+	// for dead servers to be removed. Zero disables the minimum.
+	MinQuorum uint
+
 	// ServerStabilizationTime is the minimum amount of time a server must be
 	// in a stable, healthy state before it can be added to the cluster. Only
 	// applicable with Raft protocol version 3 or higher.
 	ServerStabilizationTime time.Duration
 
-	// (Enterprise-only) EnableRedundancyZones specifies whether to enable redundancy zones.
+	// EnableRedundancyZones specifies whether to enable redundancy zones.
 	EnableRedundancyZones bool
 
 	// (Enterprise-only) DisableUpgradeMigration will disable Autopilot's upgrade migration
@@ -1,6 +1,8 @@
 package api
 
 import (
+	"bytes"
+	"io/ioutil"
 	"strings"
 	"testing"
 
@@ -121,3 +123,32 @@ func TestAPI_OperatorSchedulerCASConfiguration(t *testing.T) {
 		require.True(resp.Updated)
 	}
 }
+
+func TestAPI_OperatorSnapshotSaveRestore(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	c, s := makeClient(t, nil, nil)
+	defer s.Stop()
+
+	// Wait for the leader to take the snapshot
+	operator := c.Operator()
+	var snap []byte
+	retry.Run(t, func(r *retry.R) {
+		in, err := operator.Snapshot(nil)
+		r.Check(err)
+		defer in.Close()
+
+		snap, err = ioutil.ReadAll(in)
+		r.Check(err)
+	})
+	require.NotEmpty(snap)
+
+	// Restore the snapshot
+	wm, err := operator.SnapshotRestore(bytes.NewReader(snap), nil)
+	require.NoError(err)
+	require.NotZero(wm.LastIndex)
+
+	// Invalid snapshots fail to restore
+	_, err = operator.SnapshotRestore(strings.NewReader("foo"), nil)
+	require.Error(err)
+}
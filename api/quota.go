@@ -122,10 +122,15 @@ type QuotaLimit struct {
 
 	// RegionLimit is the quota limit that applies to any allocation within a
 	// referencing namespace in the region. A value of zero is treated as
-	// unlimited and a negative value is treated as fully disallowed. This is
-	// useful for once we support GPUs
+	// unlimited and a negative value is treated as fully disallowed. Only
+	// CPU, MemoryMB and DiskMB may be limited.
 	RegionLimit *Resources
 
+	// AllocLimit limits the number of non-terminal allocations within the
+	// referencing namespaces in the region. A value of zero is treated as
+	// unlimited and a negative value is treated as fully disallowed.
+	AllocLimit int `mapstructure:"alloc_limit"`
+
 	// Hash is the hash of the object and is used to make replication efficient.
 	Hash []byte
 }
@@ -11,6 +11,13 @@ type Resources struct {
 	IOPS     *int
 	Networks []*NetworkResource
 	Devices  []*RequestedDevice
+	NUMA     *NUMAResource
+	IOWeight *int       `mapstructure:"io_weight"`
+	IOLimits []*IOLimit `mapstructure:"io_limit"`
+
+	CPUHardLimit *bool `mapstructure:"cpu_hard_limit"`
+	CPUCFSPeriod *int  `mapstructure:"cpu_cfs_period"`
+	Cores        *int
 }
 
 // Canonicalize will supply missing values in the cases
@@ -32,6 +39,9 @@ func (r *Resources) Canonicalize() {
 	for _, d := range r.Devices {
 		d.Canonicalize()
 	}
+	if r.NUMA != nil {
+		r.NUMA.Canonicalize()
+	}
 }
 
 // DefaultResources is a small resources object that contains the
@@ -82,16 +92,38 @@ func (r *Resources) Merge(other *Resources) {
 	if len(other.Devices) != 0 {
 		r.Devices = other.Devices
 	}
+	if other.NUMA != nil {
+		r.NUMA = other.NUMA
+	}
+	if other.IOWeight != nil {
+		r.IOWeight = other.IOWeight
+	}
+	if len(other.IOLimits) != 0 {
+		r.IOLimits = other.IOLimits
+	}
+	if other.CPUHardLimit != nil {
+		r.CPUHardLimit = other.CPUHardLimit
+	}
+	if other.CPUCFSPeriod != nil {
+		r.CPUCFSPeriod = other.CPUCFSPeriod
+	}
+	if other.Cores != nil {
+		r.Cores = other.Cores
+	}
 }
 
 type Port struct {
-	Label string
-	Value int `mapstructure:"static"`
+	Label       string
+	Value       int    `mapstructure:"static"`
+	To          int    `mapstructure:"to"`
+	HostNetwork string `mapstructure:"host_network"`
+	HostIP      string `mapstructure:"-"`
 }
 
 // NetworkResource is used to describe required network
 // resources of a given task.
 type NetworkResource struct {
+	Mode          string
 	Device        string
 	CIDR          string
 	IP            string
@@ -191,6 +223,10 @@ type RequestedDevice struct {
 	// Affinities are a set of affinites to apply when selecting the device
 	// to use.
 	Affinities []*Affinity
+
+	// Shared allows the devices to be time-shared with other shared
+	// requests. Only low priority batch jobs may share devices.
+	Shared bool
 }
 
 func (d *RequestedDevice) Canonicalize() {
@@ -198,3 +234,32 @@ func (d *RequestedDevice) Canonicalize() {
 		d.Count = helper.Uint64ToPtr(1)
 	}
 }
+
+// NUMAResource is used to request the placement of a task on a single NUMA
+// node of a client.
+type NUMAResource struct {
+	// Affinity is one of "none" or "require". When set to "require", the CPU
+	// and memory of the task are provided by a single NUMA node.
+	Affinity *string
+}
+
+func (n *NUMAResource) Canonicalize() {
+	if n.Affinity == nil {
+		n.Affinity = helper.StringToPtr("none")
+	}
+}
+
+// IOLimit is used to throttle the block I/O of a task against a single block
+// device. Limits left at zero are unlimited.
+type IOLimit struct {
+	// Device is the path of the block device on the client.
+	Device string
+
+	// ReadBps and WriteBps limit the bandwidth in bytes per second.
+	ReadBps  int64 `mapstructure:"read_bps"`
+	WriteBps int64 `mapstructure:"write_bps"`
+
+	// ReadIOps and WriteIOps limit the number of operations per second.
+	ReadIOps  int64 `mapstructure:"read_iops"`
+	WriteIOps int64 `mapstructure:"write_iops"`
+}
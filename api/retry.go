@@ -0,0 +1,180 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultRetryMinBackoff is the default wait before the first retry of a
+	// request.
+	defaultRetryMinBackoff = 100 * time.Millisecond
+
+	// defaultRetryMaxBackoff is the default maximum wait between the retries
+	// of a request.
+	defaultRetryMaxBackoff = 5 * time.Second
+
+	// noLeaderError is the error of the requests failing while the servers
+	// elect a leader.
+	noLeaderError = "No cluster leader"
+)
+
+// RetryConfig configures the retries of the requests failing with transient
+// errors.
+//
+// Requests are retried when the connection to the agent fails, when they are
+// rate limited, or when the servers have no leader. Reads are also retried on
+// other connection errors and on the 502, 503 and 504 status codes, since
+// they are idempotent. Requests whose body can't be sent again, such as
+// snapshot restores, are never retried.
+type RetryConfig struct {
+	// MaxRetries is the maximum number of times a request is retried.
+	MaxRetries int
+
+	// MinBackoff is the wait before the first retry, which is doubled on
+	// each of the following ones. Defaults to 100 milliseconds.
+	MinBackoff time.Duration
+
+	// MaxBackoff is the maximum wait between retries. Defaults to 5 seconds.
+	MaxBackoff time.Duration
+}
+
+// DefaultRetryConfig returns a retry configuration retrying the requests up to
+// 3 times.
+func DefaultRetryConfig() *RetryConfig {
+	return &RetryConfig{
+		MaxRetries: 3,
+		MinBackoff: defaultRetryMinBackoff,
+		MaxBackoff: defaultRetryMaxBackoff,
+	}
+}
+
+func (r *RetryConfig) Copy() *RetryConfig {
+	if r == nil {
+		return nil
+	}
+
+	nr := new(RetryConfig)
+	*nr = *r
+	return nr
+}
+
+// shouldRetry returns whether the attempt of the request should be retried
+// given its response or error, and how long to wait before doing so. The
+// response's body is preserved if the request isn't retried.
+func (r *RetryConfig) shouldRetry(attempt int, req *http.Request, resp *http.Response, err error) (time.Duration, bool) {
+	if r == nil || attempt >= r.MaxRetries || req.Context().Err() != nil {
+		return 0, false
+	}
+
+	// The body must be sent again
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return 0, false
+	}
+
+	idempotent := req.Method == "GET" || req.Method == "HEAD"
+	var retryAfter time.Duration
+	switch {
+	case err != nil:
+		// Requests failing to connect weren't sent
+		if !idempotent && !isDialError(err) {
+			return 0, false
+		}
+	case resp.StatusCode == http.StatusTooManyRequests:
+		if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+			retryAfter = time.Duration(secs) * time.Second
+		}
+	case resp.StatusCode == http.StatusInternalServerError:
+		if !isNoLeaderResponse(resp) {
+			return 0, false
+		}
+	case resp.StatusCode == http.StatusBadGateway,
+		resp.StatusCode == http.StatusServiceUnavailable,
+		resp.StatusCode == http.StatusGatewayTimeout:
+		if !idempotent {
+			return 0, false
+		}
+	default:
+		return 0, false
+	}
+
+	wait := r.backoff(attempt)
+	if retryAfter > wait {
+		wait = retryAfter
+	}
+	return wait, true
+}
+
+// backoff returns the jittered exponential wait before retrying the attempt.
+func (r *RetryConfig) backoff(attempt int) time.Duration {
+	min, max := r.MinBackoff, r.MaxBackoff
+	if min <= 0 {
+		min = defaultRetryMinBackoff
+	}
+	if max <= 0 {
+		max = defaultRetryMaxBackoff
+	}
+
+	wait := max
+	if attempt < 32 {
+		if exp := min << uint(attempt); exp > 0 && exp < max {
+			wait = exp
+		}
+	}
+	return wait/2 + time.Duration(rand.Int63n(int64(wait/2)+1))
+}
+
+// isDialError returns whether the error is a failure to connect to the agent.
+func isDialError(err error) bool {
+	if urlErr, ok := err.(*url.Error); ok {
+		err = urlErr.Err
+	}
+	opErr, ok := err.(*net.OpError)
+	return ok && opErr.Op == "dial"
+}
+
+// isNoLeaderResponse returns whether the response is an error of the servers
+// having no leader. The response's body is read, and replaced to be read
+// again.
+func isNoLeaderResponse(resp *http.Response) bool {
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return err == nil && strings.Contains(string(body), noLeaderError)
+}
+
+// rewindRequest returns a copy of the request whose body can be sent again.
+func rewindRequest(req *http.Request) (*http.Request, error) {
+	if req.GetBody == nil {
+		return req, nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	next := req.WithContext(req.Context())
+	next.Body = body
+	return next, nil
+}
+
+// sleepWithContext waits for the duration, or until the context is done.
+func sleepWithContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
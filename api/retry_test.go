@@ -0,0 +1,196 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// failingServer returns a server failing the first failures requests with
+// the status code and message, and the number of requests it received.
+func failingServer(failures int32, code int, msg string) (*httptest.Server, *int32) {
+	var count int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&count, 1) <= failures {
+			http.Error(w, msg, code)
+			return
+		}
+		w.Header().Set("X-Nomad-Index", "1")
+		json.NewEncoder(w).Encode(struct{ Done bool }{true})
+	}))
+	return srv, &count
+}
+
+func retryClient(t *testing.T, addr string, retry *RetryConfig) *Client {
+	conf := DefaultConfig()
+	conf.Address = addr
+	conf.Retry = retry
+
+	client, err := NewClient(conf)
+	require.NoError(t, err)
+	return client
+}
+
+func TestClient_Retry(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	retry := &RetryConfig{
+		MaxRetries: 3,
+		MinBackoff: time.Millisecond,
+		MaxBackoff: 10 * time.Millisecond,
+	}
+
+	// Leader elections are retried, for reads and writes
+	srv, count := failingServer(2, http.StatusInternalServerError, "rpc error: No cluster leader")
+	defer srv.Close()
+	client := retryClient(t, srv.URL, retry)
+
+	var out struct{ Done bool }
+	_, err := client.query("/", &out, nil)
+	require.NoError(err)
+	require.True(out.Done)
+	require.EqualValues(3, atomic.LoadInt32(count))
+
+	atomic.StoreInt32(count, 0)
+	out.Done = false
+	_, err = client.write("/", struct{ S string }{"input"}, &out, nil)
+	require.NoError(err)
+	require.True(out.Done)
+	require.EqualValues(3, atomic.LoadInt32(count))
+
+	// Retries are bounded
+	atomic.StoreInt32(count, -10)
+	_, err = client.query("/", &out, nil)
+	require.Error(err)
+	require.Contains(err.Error(), "No cluster leader")
+	require.EqualValues(-6, atomic.LoadInt32(count))
+
+	// Requests aren't retried by default
+	atomic.StoreInt32(count, 0)
+	_, err = retryClient(t, srv.URL, nil).query("/", &out, nil)
+	require.Error(err)
+	require.EqualValues(1, atomic.LoadInt32(count))
+}
+
+func TestClient_Retry_Unavailable(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	srv, count := failingServer(1, http.StatusServiceUnavailable, "unavailable")
+	defer srv.Close()
+	client := retryClient(t, srv.URL, &RetryConfig{MaxRetries: 1, MinBackoff: time.Millisecond})
+
+	// Reads are retried
+	var out struct{ Done bool }
+	_, err := client.query("/", &out, nil)
+	require.NoError(err)
+	require.EqualValues(2, atomic.LoadInt32(count))
+
+	// Writes may have been applied
+	atomic.StoreInt32(count, 0)
+	_, err = client.write("/", struct{ S string }{"input"}, &out, nil)
+	require.Error(err)
+	require.Contains(err.Error(), "503")
+	require.EqualValues(1, atomic.LoadInt32(count))
+
+	// Other errors aren't transient
+	srv2, count2 := failingServer(1, http.StatusInternalServerError, "bad")
+	defer srv2.Close()
+	_, err = retryClient(t, srv2.URL, DefaultRetryConfig()).query("/", &out, nil)
+	require.Error(err)
+	require.EqualValues(1, atomic.LoadInt32(count2))
+}
+
+func TestClient_Retry_Dial(t *testing.T) {
+	t.Parallel()
+
+	// Closing the server leaves its address refusing connections
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+
+	client := retryClient(t, srv.URL, &RetryConfig{MaxRetries: 2, MinBackoff: time.Millisecond})
+	_, err := client.write("/", struct{ S string }{"input"}, nil, nil)
+	require.Error(t, err)
+	require.True(t, isDialError(err))
+}
+
+func TestClient_Context(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	srv, _ := failingServer(100, http.StatusInternalServerError, "No cluster leader")
+	defer srv.Close()
+	client := retryClient(t, srv.URL, &RetryConfig{MaxRetries: 100, MinBackoff: time.Hour})
+
+	// Canceling the context stops the retries
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	var out struct{ Done bool }
+	_, err := client.query("/", &out, (&QueryOptions{}).WithContext(ctx))
+	require.Equal(context.DeadlineExceeded, err)
+	require.True(time.Since(start) < 10*time.Second)
+
+	// Canceled contexts fail the requests
+	q := &WriteOptions{Region: "foo"}
+	wq := q.WithContext(ctx)
+	require.Equal(context.Background(), q.Context())
+	require.Equal(ctx, wq.Context())
+	require.Equal("foo", wq.Region)
+
+	_, err = client.write("/", struct{ S string }{"input"}, &out, wq)
+	require.Error(err)
+	require.Contains(err.Error(), "context deadline exceeded")
+}
+
+func TestRetryConfig_Backoff(t *testing.T) {
+	t.Parallel()
+	r := &RetryConfig{MinBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+
+	for attempt, max := range []time.Duration{
+		100 * time.Millisecond,
+		200 * time.Millisecond,
+		400 * time.Millisecond,
+		800 * time.Millisecond,
+		time.Second,
+		time.Second,
+	} {
+		wait := r.backoff(attempt)
+		require.True(t, wait >= max/2 && wait <= max, "attempt %d: %v", attempt, wait)
+	}
+	require.True(t, r.backoff(100) <= time.Second)
+}
+
+func TestConfig_MaxIdleConnsPerHost(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	// Connections aren't reused by default
+	client, err := NewClient(DefaultConfig())
+	require.NoError(err)
+	transport := client.config.httpClient.Transport.(*http.Transport)
+	require.True(transport.DisableKeepAlives)
+
+	conf := DefaultConfig()
+	conf.MaxIdleConnsPerHost = 10
+	conf.IdleConnTimeout = time.Minute
+	client, err = NewClient(conf)
+	require.NoError(err)
+	transport = client.config.httpClient.Transport.(*http.Transport)
+	require.False(transport.DisableKeepAlives)
+	require.Equal(10, transport.MaxIdleConnsPerHost)
+	require.Equal(time.Minute, transport.IdleConnTimeout)
+
+	// Node clients inherit the configuration
+	nodeConf := conf.ClientConfig("global", "127.0.0.1:4646", false)
+	require.Equal(10, nodeConf.MaxIdleConnsPerHost)
+	require.Equal(time.Minute, nodeConf.IdleConnTimeout)
+}
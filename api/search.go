@@ -26,6 +26,20 @@ func (s *Search) PrefixSearch(prefix string, context contexts.Context, q *QueryO
 	return &resp, qm, nil
 }
 
+// FuzzySearch returns the objects of a particular context whose name contains
+// the text.
+func (s *Search) FuzzySearch(text string, context contexts.Context, q *QueryOptions) (*FuzzySearchResponse, *QueryMeta, error) {
+	var resp FuzzySearchResponse
+	req := &FuzzySearchRequest{Text: text, Context: context}
+
+	qm, err := s.client.putQuery("/v1/search/fuzzy", req, &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &resp, qm, nil
+}
+
 type SearchRequest struct {
 	Prefix  string
 	Context contexts.Context
@@ -37,3 +51,23 @@ type SearchResponse struct {
 	Truncations map[contexts.Context]bool
 	QueryMeta
 }
+
+type FuzzySearchRequest struct {
+	Text    string
+	Context contexts.Context
+	QueryOptions
+}
+
+// FuzzyMatch is an object matched by a fuzzy search. The scope is the path of
+// identifiers locating the object, such as the namespace and ID of an
+// allocation matched by its name.
+type FuzzyMatch struct {
+	ID    string
+	Scope []string
+}
+
+type FuzzySearchResponse struct {
+	Matches     map[contexts.Context][]FuzzyMatch
+	Truncations map[contexts.Context]bool
+	QueryMeta
+}
@@ -0,0 +1,88 @@
+package api
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// Services is used to query the services registered with the Nomad servers.
+type Services struct {
+	client *Client
+}
+
+// Services returns a new handle on the services.
+func (c *Client) Services() *Services {
+	return &Services{client: c}
+}
+
+// List is used to list the services of the namespace, along with the number
+// of their registered and healthy instances.
+func (s *Services) List(q *QueryOptions) ([]*ServiceRegistrationListStub, *QueryMeta, error) {
+	var resp []*ServiceRegistrationListStub
+	qm, err := s.client.query("/v1/services", &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, qm, nil
+}
+
+// ListByJob is used to list the services of a job.
+func (s *Services) ListByJob(jobID string, q *QueryOptions) ([]*ServiceRegistrationListStub, *QueryMeta, error) {
+	var resp []*ServiceRegistrationListStub
+	qm, err := s.client.query("/v1/services?job="+url.QueryEscape(jobID), &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, qm, nil
+}
+
+// Get is used to read the registered instances of a service.
+func (s *Services) Get(name string, q *QueryOptions) ([]*ServiceRegistration, *QueryMeta, error) {
+	return s.get(name, "", q)
+}
+
+// GetByJob is used to read the registered instances of a service of a job.
+func (s *Services) GetByJob(name, jobID string, q *QueryOptions) ([]*ServiceRegistration, *QueryMeta, error) {
+	return s.get(name, "?job="+url.QueryEscape(jobID), q)
+}
+
+func (s *Services) get(name, params string, q *QueryOptions) ([]*ServiceRegistration, *QueryMeta, error) {
+	if name == "" {
+		return nil, nil, fmt.Errorf("missing service name")
+	}
+	var resp []*ServiceRegistration
+	qm, err := s.client.query("/v1/service/"+url.PathEscape(name)+params, &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, qm, nil
+}
+
+// ServiceRegistrationListStub is the summary of the registered instances of
+// a service.
+type ServiceRegistrationListStub struct {
+	ServiceName string
+	Namespace   string
+	Tags        []string
+	Instances   int
+	Healthy     int
+}
+
+// ServiceRegistration is a registered instance of a service using the nomad
+// provider.
+type ServiceRegistration struct {
+	ID          string
+	ServiceName string
+	Namespace   string
+	NodeID      string
+	Datacenter  string
+	JobID       string
+	AllocID     string
+	Task        string
+	Tags        []string
+	Address     string
+	Port        int
+	Health      string
+	CreateIndex uint64
+	ModifyIndex uint64
+}
@@ -0,0 +1,37 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestServices_List(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	c, s := makeClient(t, nil, nil)
+	defer s.Stop()
+	services := c.Services()
+
+	// No services are registered
+	list, qm, err := services.List(nil)
+	require.NoError(err)
+	assertQueryMeta(t, qm)
+	require.Empty(list)
+
+	list, _, err = services.ListByJob("example", nil)
+	require.NoError(err)
+	require.Empty(list)
+
+	instances, _, err := services.Get("web", nil)
+	require.NoError(err)
+	require.Empty(instances)
+
+	instances, _, err = services.GetByJob("web", "example", nil)
+	require.NoError(err)
+	require.Empty(instances)
+
+	// The service name is required
+	_, _, err = services.Get("", nil)
+	require.Error(err)
+}
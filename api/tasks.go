@@ -19,6 +19,8 @@ type MemoryStats struct {
 	MaxUsage       uint64
 	KernelUsage    uint64
 	KernelMaxUsage uint64
+	Usage          uint64
+	BalloonActual  uint64
 	Measured       []string
 }
 
@@ -33,11 +35,21 @@ type CpuStats struct {
 	Measured         []string
 }
 
+// IOStats holds block I/O usage related stats
+type IOStats struct {
+	ReadBytes  uint64
+	WriteBytes uint64
+	ReadOps    uint64
+	WriteOps   uint64
+	Measured   []string
+}
+
 // ResourceUsage holds information related to cpu and memory stats
 type ResourceUsage struct {
 	MemoryStats *MemoryStats
 	CpuStats    *CpuStats
 	DeviceStats []*DeviceGroupStats
+	IOStats     *IOStats
 }
 
 // TaskResourceUsage holds aggregated resource usage of all processes in a Task
@@ -188,7 +200,7 @@ func NewDefaultReschedulePolicy(jobType string) *ReschedulePolicy {
 			Unlimited:     helper.BoolToPtr(structs.DefaultBatchJobReschedulePolicy.Unlimited),
 		}
 
-	case "system":
+	case "system", "sysbatch":
 		dp = &ReschedulePolicy{
 			Attempts:      helper.IntToPtr(0),
 			Interval:      helper.TimeToPtr(0),
@@ -344,6 +356,7 @@ type Service struct {
 	CanaryTags   []string `mapstructure:"canary_tags"`
 	PortLabel    string   `mapstructure:"port"`
 	AddressMode  string   `mapstructure:"address_mode"`
+	Provider     string
 	Checks       []ServiceCheck
 	CheckRestart *CheckRestart `mapstructure:"check_restart"`
 }
@@ -358,6 +371,11 @@ func (s *Service) Canonicalize(t *Task, tg *TaskGroup, job *Job) {
 		s.AddressMode = "auto"
 	}
 
+	// Default to registering with Consul
+	if s.Provider == "" {
+		s.Provider = "consul"
+	}
+
 	// Canonicalize CheckRestart on Checks and merge Service.CheckRestart
 	// into each check.
 	for i, check := range s.Checks {
@@ -468,6 +486,34 @@ type TaskGroup struct {
 	Update           *UpdateStrategy
 	Migrate          *MigrateStrategy
 	Meta             map[string]string
+
+	// MaxClientDisconnect is the duration allocations of the group may
+	// remain unknown on a disconnected client before they are replaced.
+	MaxClientDisconnect *time.Duration `mapstructure:"max_client_disconnect"`
+
+	// Volumes are the volumes required by the group, keyed by name.
+	Volumes map[string]*VolumeRequest
+
+	// Networks are the networks shared by the tasks of the group. Only the
+	// network mode may be set.
+	Networks []*NetworkResource
+}
+
+// VolumeRequest is a volume required by a task group. Host volume requests
+// restrict the group to the nodes exposing a healthy host volume named
+// after the source.
+type VolumeRequest struct {
+	Name     string
+	Type     string
+	Source   string
+	ReadOnly bool `mapstructure:"read_only"`
+}
+
+// Canonicalize sets the default type of the volume request.
+func (v *VolumeRequest) Canonicalize() {
+	if v.Type == "" {
+		v.Type = "host"
+	}
 }
 
 // NewTaskGroup creates a new TaskGroup.
@@ -488,6 +534,9 @@ func (g *TaskGroup) Canonicalize(job *Job) {
 	for _, t := range g.Tasks {
 		t.Canonicalize(g, job)
 	}
+	for _, v := range g.Volumes {
+		v.Canonicalize()
+	}
 	if g.EphemeralDisk == nil {
 		g.EphemeralDisk = DefaultEphemeralDisk()
 	} else {
@@ -520,7 +569,7 @@ func (g *TaskGroup) Canonicalize(job *Job) {
 		g.ReschedulePolicy = jobReschedule
 	}
 	// Only use default reschedule policy for non system jobs
-	if g.ReschedulePolicy == nil && *job.Type != "system" {
+	if g.ReschedulePolicy == nil && *job.Type != "system" && *job.Type != "sysbatch" {
 		g.ReschedulePolicy = NewDefaultReschedulePolicy(*job.Type)
 	}
 	if g.ReschedulePolicy != nil {
@@ -655,8 +704,10 @@ type Task struct {
 	Templates       []*Template
 	DispatchPayload *DispatchPayloadConfig
 	Leader          bool
+	Checkpoint      bool
 	ShutdownDelay   time.Duration `mapstructure:"shutdown_delay"`
 	KillSignal      string        `mapstructure:"kill_signal"`
+	Actions         []*Action
 }
 
 func (t *Task) Canonicalize(tg *TaskGroup, job *Job) {
@@ -717,6 +768,14 @@ func (a *TaskArtifact) Canonicalize() {
 	}
 }
 
+// Action is a named command of a task that can be run on demand inside the
+// allocations of the task.
+type Action struct {
+	Name    string
+	Command string
+	Args    []string
+}
+
 type Template struct {
 	SourcePath   *string        `mapstructure:"source"`
 	DestPath     *string        `mapstructure:"destination"`
@@ -876,6 +935,9 @@ const (
 	TaskRestartSignal          = "Restart Signaled"
 	TaskLeaderDead             = "Leader Task Dead"
 	TaskBuildingTaskDir        = "Building Task Directory"
+	TaskCheckpointed           = "Checkpointed"
+	TaskCheckpointFailed       = "Checkpoint Failed"
+	TaskOOMKilled              = "OOM Killed"
 )
 
 // TaskEvent is an event that effects the state of a task and contains meta-data
@@ -0,0 +1,121 @@
+package api
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Variables is used to query the variables endpoints.
+type Variables struct {
+	client *Client
+}
+
+// Variables returns a new handle on the variables.
+func (c *Client) Variables() *Variables {
+	return &Variables{client: c}
+}
+
+// List is used to list the metadata of the variables of the namespace. The
+// prefix of the query options filters the variables by path.
+func (v *Variables) List(q *QueryOptions) ([]*VariableMetadata, *QueryMeta, error) {
+	var resp []*VariableMetadata
+	qm, err := v.client.query("/v1/vars", &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, qm, nil
+}
+
+// PrefixList is used to list the metadata of the variables whose path starts
+// with the prefix.
+func (v *Variables) PrefixList(prefix string, q *QueryOptions) ([]*VariableMetadata, *QueryMeta, error) {
+	if q == nil {
+		q = &QueryOptions{Prefix: prefix}
+	} else {
+		q.Prefix = prefix
+	}
+
+	return v.List(q)
+}
+
+// Read is used to read the variable at the given path.
+func (v *Variables) Read(path string, q *QueryOptions) (*Variable, *QueryMeta, error) {
+	if path == "" {
+		return nil, nil, fmt.Errorf("missing variable path")
+	}
+	var resp Variable
+	qm, err := v.client.query("/v1/var/"+path, &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, qm, nil
+}
+
+// Put is used to create or update a variable.
+func (v *Variables) Put(variable *Variable, q *WriteOptions) (*Variable, *WriteMeta, error) {
+	return v.put(variable, "", q)
+}
+
+// CheckedPut is used to create or update a variable only if its modify index
+// is the check index. A check index of zero creates the variable only if it
+// doesn't exist.
+func (v *Variables) CheckedPut(variable *Variable, checkIndex uint64, q *WriteOptions) (*Variable, *WriteMeta, error) {
+	return v.put(variable, "?cas="+strconv.FormatUint(checkIndex, 10), q)
+}
+
+func (v *Variables) put(variable *Variable, params string, q *WriteOptions) (*Variable, *WriteMeta, error) {
+	if variable == nil || variable.Path == "" {
+		return nil, nil, fmt.Errorf("missing variable path")
+	}
+	var resp Variable
+	wm, err := v.client.write("/v1/var/"+variable.Path+params, variable, &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, wm, nil
+}
+
+// Delete is used to delete the variable at the given path.
+func (v *Variables) Delete(path string, q *WriteOptions) (*WriteMeta, error) {
+	return v.delete(path, "", q)
+}
+
+// CheckedDelete is used to delete the variable at the given path only if its
+// modify index is the check index.
+func (v *Variables) CheckedDelete(path string, checkIndex uint64, q *WriteOptions) (*WriteMeta, error) {
+	return v.delete(path, "?cas="+strconv.FormatUint(checkIndex, 10), q)
+}
+
+func (v *Variables) delete(path, params string, q *WriteOptions) (*WriteMeta, error) {
+	if path == "" {
+		return nil, fmt.Errorf("missing variable path")
+	}
+	wm, err := v.client.delete("/v1/var/"+path+params, nil, q)
+	if err != nil {
+		return nil, err
+	}
+	return wm, nil
+}
+
+// VariableMetadata is the metadata of a variable, returned when listing
+// variables.
+type VariableMetadata struct {
+	Namespace   string
+	Path        string
+	CreateIndex uint64
+	ModifyIndex uint64
+	CreateTime  int64
+	ModifyTime  int64
+}
+
+// Variable is a set of key/value items stored encrypted at a path of a
+// namespace.
+type Variable struct {
+	Namespace   string
+	Path        string
+	CreateIndex uint64
+	ModifyIndex uint64
+	CreateTime  int64
+	ModifyTime  int64
+	Items       map[string]string
+}
@@ -0,0 +1,51 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVariables_CRUD(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	c, s := makeClient(t, nil, nil)
+	defer s.Stop()
+	variables := c.Variables()
+
+	// Create a variable
+	variable := &Variable{
+		Path:  "project/web",
+		Items: map[string]string{"user": "admin"},
+	}
+	out, wm, err := variables.CheckedPut(variable, 0, nil)
+	require.NoError(err)
+	assertWriteMeta(t, wm)
+	require.Equal("default", out.Namespace)
+	require.NotZero(out.ModifyIndex)
+
+	// The variable already exists
+	_, _, err = variables.CheckedPut(variable, 0, nil)
+	require.Error(err)
+	require.Contains(err.Error(), "409")
+
+	// Query it back out
+	read, qm, err := variables.Read("project/web", nil)
+	require.NoError(err)
+	assertQueryMeta(t, qm)
+	require.Equal(variable.Items, read.Items)
+
+	resp, _, err := variables.PrefixList("project", nil)
+	require.NoError(err)
+	require.Len(resp, 1)
+	require.Equal("project/web", resp[0].Path)
+
+	// Delete it
+	wm, err = variables.Delete("project/web", nil)
+	require.NoError(err)
+	assertWriteMeta(t, wm)
+
+	_, _, err = variables.Read("project/web", nil)
+	require.Error(err)
+	require.Contains(err.Error(), "not found")
+}
@@ -0,0 +1,188 @@
+package api
+
+import (
+	"fmt"
+	"net/url"
+)
+
+const (
+	// VolumeTypeHost is the type of volumes backed by a path on the host of
+	// a single node.
+	VolumeTypeHost = "host"
+
+	// VolumeTypeCSI is the type of volumes backed by the external storage
+	// of a CSI plugin.
+	VolumeTypeCSI = "csi"
+)
+
+const (
+	// The access modes restrict the nodes and allocations that can claim a
+	// volume at the same time.
+	VolumeAccessModeSingleNodeReader = "single-node-reader-only"
+	VolumeAccessModeSingleNodeWriter = "single-node-writer"
+	VolumeAccessModeMultiNodeReader  = "multi-node-reader-only"
+	VolumeAccessModeMultiNodeSingle  = "multi-node-single-writer"
+	VolumeAccessModeMultiNodeMulti   = "multi-node-multi-writer"
+)
+
+const (
+	// VolumeAttachmentModeFilesystem mounts the volume as a file system.
+	VolumeAttachmentModeFilesystem = "file-system"
+
+	// VolumeAttachmentModeBlockDevice exposes the volume as a block device.
+	VolumeAttachmentModeBlockDevice = "block-device"
+)
+
+const (
+	// VolumeClaimRead and VolumeClaimWrite are the modes an allocation can
+	// claim a volume with.
+	VolumeClaimRead  = "read"
+	VolumeClaimWrite = "write"
+)
+
+// Volumes is used to query the volume endpoints.
+type Volumes struct {
+	client *Client
+}
+
+// Volumes returns a new handle on the volumes.
+func (c *Client) Volumes() *Volumes {
+	return &Volumes{client: c}
+}
+
+// List is used to list the volumes of the namespace, optionally only those
+// of a type.
+func (v *Volumes) List(volumeType string, q *QueryOptions) ([]*VolumeListStub, *QueryMeta, error) {
+	path := "/v1/volumes"
+	if volumeType != "" {
+		path += "?type=" + url.QueryEscape(volumeType)
+	}
+	var resp []*VolumeListStub
+	qm, err := v.client.query(path, &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, qm, nil
+}
+
+// PrefixList is used to list the volumes whose ID has the prefix.
+func (v *Volumes) PrefixList(prefix string, q *QueryOptions) ([]*VolumeListStub, *QueryMeta, error) {
+	if q == nil {
+		q = &QueryOptions{Prefix: prefix}
+	} else {
+		q.Prefix = prefix
+	}
+
+	return v.List("", q)
+}
+
+// Info is used to query a single volume by its ID.
+func (v *Volumes) Info(id string, q *QueryOptions) (*Volume, *QueryMeta, error) {
+	if id == "" {
+		return nil, nil, fmt.Errorf("missing volume ID")
+	}
+	var resp Volume
+	qm, err := v.client.query("/v1/volume/"+url.PathEscape(id), &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, qm, nil
+}
+
+// Register is used to register or update a volume that was provisioned
+// out-of-band.
+func (v *Volumes) Register(volume *Volume, q *WriteOptions) (*WriteMeta, error) {
+	if volume == nil || volume.ID == "" {
+		return nil, fmt.Errorf("missing volume ID")
+	}
+	wm, err := v.client.write("/v1/volume/"+url.PathEscape(volume.ID), volume, nil, q)
+	if err != nil {
+		return nil, err
+	}
+	return wm, nil
+}
+
+// Create is used to create a new volume. It fails if the volume already
+// exists.
+func (v *Volumes) Create(volume *Volume, q *WriteOptions) (*WriteMeta, error) {
+	if volume == nil || volume.ID == "" {
+		return nil, fmt.Errorf("missing volume ID")
+	}
+	wm, err := v.client.write("/v1/volume/"+url.PathEscape(volume.ID)+"/create", volume, nil, q)
+	if err != nil {
+		return nil, err
+	}
+	return wm, nil
+}
+
+// Delete is used to delete a volume.
+func (v *Volumes) Delete(id string, q *WriteOptions) (*WriteMeta, error) {
+	if id == "" {
+		return nil, fmt.Errorf("missing volume ID")
+	}
+	wm, err := v.client.delete("/v1/volume/"+url.PathEscape(id), nil, q)
+	if err != nil {
+		return nil, err
+	}
+	return wm, nil
+}
+
+// Detach is used to release the claims of a volume on a node.
+func (v *Volumes) Detach(id, nodeID string, q *WriteOptions) (*WriteMeta, error) {
+	if id == "" {
+		return nil, fmt.Errorf("missing volume ID")
+	}
+	if nodeID == "" {
+		return nil, fmt.Errorf("missing node ID")
+	}
+	path := fmt.Sprintf("/v1/volume/%s/detach?node=%s", url.PathEscape(id), url.QueryEscape(nodeID))
+	wm, err := v.client.delete(path, nil, q)
+	if err != nil {
+		return nil, err
+	}
+	return wm, nil
+}
+
+// Volume is an external storage volume registered with Nomad.
+type Volume struct {
+	ID             string            `mapstructure:"id"`
+	Name           string            `mapstructure:"name"`
+	Namespace      string            `mapstructure:"namespace"`
+	Type           string            `mapstructure:"type"`
+	PluginID       string            `mapstructure:"plugin_id"`
+	NodeID         string            `mapstructure:"node_id"`
+	ExternalID     string            `mapstructure:"external_id"`
+	AccessMode     string            `mapstructure:"access_mode"`
+	AttachmentMode string            `mapstructure:"attachment_mode"`
+	CapacityMin    int64             `mapstructure:"capacity_min"`
+	CapacityMax    int64             `mapstructure:"capacity_max"`
+	Parameters     map[string]string `mapstructure:"parameters"`
+	Context        map[string]string `mapstructure:"context"`
+	Claims         map[string]*VolumeClaim
+	CreateIndex    uint64
+	ModifyIndex    uint64
+}
+
+// VolumeClaim is the claim of an allocation on a volume.
+type VolumeClaim struct {
+	AllocID string
+	NodeID  string
+	Mode    string
+}
+
+// VolumeListStub is the summary of a volume, returned when listing volumes.
+type VolumeListStub struct {
+	ID             string
+	Name           string
+	Namespace      string
+	Type           string
+	PluginID       string
+	NodeID         string
+	ExternalID     string
+	AccessMode     string
+	AttachmentMode string
+	ReadClaims     int
+	WriteClaims    int
+	CreateIndex    uint64
+	ModifyIndex    uint64
+}
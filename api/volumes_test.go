@@ -0,0 +1,59 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVolumes_CRUD(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	c, s := makeClient(t, nil, nil)
+	defer s.Stop()
+	volumes := c.Volumes()
+
+	// Register a volume
+	volume := &Volume{
+		ID:         "database",
+		Type:       VolumeTypeCSI,
+		PluginID:   "ebs",
+		ExternalID: "vol-0123456789",
+	}
+	wm, err := volumes.Register(volume, nil)
+	require.NoError(err)
+	assertWriteMeta(t, wm)
+
+	// Creating it again fails
+	_, err = volumes.Create(volume, nil)
+	require.Error(err)
+	require.Contains(err.Error(), "already exists")
+
+	// Query it back out
+	out, qm, err := volumes.Info("database", nil)
+	require.NoError(err)
+	assertQueryMeta(t, qm)
+	require.Equal(volume.ExternalID, out.ExternalID)
+	require.Equal(VolumeAccessModeSingleNodeWriter, out.AccessMode)
+
+	resp, _, err := volumes.PrefixList("data", nil)
+	require.NoError(err)
+	require.Len(resp, 1)
+
+	resp, _, err = volumes.List(VolumeTypeHost, nil)
+	require.NoError(err)
+	require.Empty(resp)
+
+	// Detach and delete it
+	wm, err = volumes.Detach("database", "node", nil)
+	require.NoError(err)
+	assertWriteMeta(t, wm)
+
+	wm, err = volumes.Delete("database", nil)
+	require.NoError(err)
+	assertWriteMeta(t, wm)
+
+	_, _, err = volumes.Info("database", nil)
+	require.Error(err)
+	require.Contains(err.Error(), "not found")
+}
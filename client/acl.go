@@ -21,6 +21,10 @@ const (
 	// tokenCacheSize is the number of ACL tokens to keep cached. Tokens have a fetching cost,
 	// so we keep the hot tokens cached to reduce the lookups.
 	tokenCacheSize = 64
+
+	// roleCacheSize is the number of ACL roles to keep cached. Roles have a fetching cost,
+	// so we keep the hot roles cached to reduce the ACL token resolution time.
+	roleCacheSize = 64
 )
 
 // clientACLResolver holds the state required for client resolution
@@ -34,6 +38,9 @@ type clientACLResolver struct {
 
 	// tokenCache is used to maintain the fetched token objects
 	tokenCache *lru.TwoQueueCache
+
+	// roleCache is used to maintain the fetched role objects
+	roleCache *lru.TwoQueueCache
 }
 
 // init is used to setup the client resolver state
@@ -52,13 +59,18 @@ func (c *clientACLResolver) init() error {
 	if err != nil {
 		return err
 	}
+	c.roleCache, err = lru.New2Q(roleCacheSize)
+	if err != nil {
+		return err
+	}
 	return nil
 }
 
-// cachedACLValue is used to manage ACL Token or Policy TTLs
+// cachedACLValue is used to manage ACL Token, Policy or Role TTLs
 type cachedACLValue struct {
 	Token     *structs.ACLToken
 	Policy    *structs.ACLPolicy
+	Role      *structs.ACLRole
 	CacheTime time.Time
 }
 
@@ -90,8 +102,16 @@ func (c *Client) ResolveToken(secretID string) (*acl.ACL, error) {
 		return acl.ManagementACL, nil
 	}
 
-	// Resolve the policies
-	policies, err := c.resolvePolicies(token.SecretID, token.Policies)
+	// Resolve the policies, including the ones of the roles of the token
+	policyNames := token.Policies
+	if len(token.Roles) != 0 {
+		roles, err := c.resolveRoles(token.SecretID, token.Roles)
+		if err != nil {
+			return nil, err
+		}
+		policyNames = rolesPolicyNames(token.Policies, roles)
+	}
+	policies, err := c.resolvePolicies(token.SecretID, policyNames)
 	if err != nil {
 		return nil, err
 	}
@@ -104,6 +124,24 @@ func (c *Client) ResolveToken(secretID string) (*acl.ACL, error) {
 	return aclObj, nil
 }
 
+// ResolveSecretToken is used to translate an ACL Token Secret ID into the ACL
+// token, nil if ACLs are disabled, or an error.
+func (c *Client) ResolveSecretToken(secretID string) (*structs.ACLToken, error) {
+	// Fast-path if ACLs are disabled
+	if !c.config.ACLEnabled {
+		return nil, nil
+	}
+
+	token, err := c.resolveTokenValue(secretID)
+	if err != nil {
+		return nil, err
+	}
+	if token == nil {
+		return nil, structs.ErrTokenNotFound
+	}
+	return token, nil
+}
+
 // resolveTokenValue is used to translate a secret ID into an ACL token with caching
 // We use a local cache up to the TTL limit, and then resolve via a server. If we cannot
 // reach a server, but have a cached value we extend the TTL to gracefully handle outages.
@@ -113,10 +151,15 @@ func (c *Client) resolveTokenValue(secretID string) (*structs.ACLToken, error) {
 		return structs.AnonymousACLToken, nil
 	}
 
-	// Lookup the token in the cache
+	// Lookup the token in the cache. Tokens that expired since they were
+	// cached are rejected like the servers reject them.
 	raw, ok := c.tokenCache.Get(secretID)
 	if ok {
 		cached := raw.(*cachedACLValue)
+		if cached.Token != nil && cached.Token.IsExpired(time.Now()) {
+			c.tokenCache.Remove(secretID)
+			return nil, structs.ErrTokenNotFound
+		}
 		if cached.Age() <= c.config.ACLTokenTTL {
 			return cached.Token, nil
 		}
@@ -217,3 +260,91 @@ func (c *Client) resolvePolicies(secretID string, policies []string) ([]*structs
 	// Return the valid policies
 	return out, nil
 }
+
+// resolveRoles is used to translate a set of named ACL roles into the objects.
+// Roles are cached like policies, for the policy TTL, and the cache TTL is
+// ignored if a server cannot be reached.
+func (c *Client) resolveRoles(secretID string, roles []string) ([]*structs.ACLRole, error) {
+	var out []*structs.ACLRole
+	var expired []*structs.ACLRole
+	var missing []string
+
+	// Scan the cache for each role
+	for _, roleName := range roles {
+		raw, ok := c.roleCache.Get(roleName)
+		if !ok {
+			missing = append(missing, roleName)
+			continue
+		}
+
+		// Check if the cached value is valid or expired
+		cached := raw.(*cachedACLValue)
+		if cached.Age() <= c.config.ACLPolicyTTL {
+			out = append(out, cached.Role)
+		} else {
+			expired = append(expired, cached.Role)
+		}
+	}
+
+	// Hot-path if we have no missing or expired roles
+	if len(missing)+len(expired) == 0 {
+		return out, nil
+	}
+
+	// Lookup the missing and expired roles
+	fetch := missing
+	for _, r := range expired {
+		fetch = append(fetch, r.Name)
+	}
+	req := structs.ACLRolesRequest{
+		Names: fetch,
+		QueryOptions: structs.QueryOptions{
+			Region:     c.Region(),
+			AuthToken:  secretID,
+			AllowStale: true,
+		},
+	}
+	var resp structs.ACLRolesResponse
+	if err := c.RPC("ACL.GetRoles", &req, &resp); err != nil {
+		// If we encounter an error but have cached roles, mask the error and extend the cache
+		if len(missing) == 0 {
+			c.logger.Warn("failed to resolve roles, using expired cached value", "error", err)
+			out = append(out, expired...)
+			return out, nil
+		}
+		return nil, err
+	}
+
+	// Handle each output
+	for _, role := range resp.Roles {
+		c.roleCache.Add(role.Name, &cachedACLValue{
+			Role:      role,
+			CacheTime: time.Now(),
+		})
+		out = append(out, role)
+	}
+
+	// Return the valid roles
+	return out, nil
+}
+
+// rolesPolicyNames returns the names of the given policies along with the
+// policies of the roles, without duplicates.
+func rolesPolicyNames(policies []string, roles []*structs.ACLRole) []string {
+	out := make([]string, 0, len(policies))
+	seen := make(map[string]struct{})
+	add := func(names []string) {
+		for _, name := range names {
+			if _, ok := seen[name]; !ok {
+				seen[name] = struct{}{}
+				out = append(out, name)
+			}
+		}
+	}
+
+	add(policies)
+	for _, role := range roles {
+		add(role.Policies)
+	}
+	return out
+}
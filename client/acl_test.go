@@ -2,6 +2,7 @@ package client
 
 import (
 	"testing"
+	"time"
 
 	"github.com/hashicorp/nomad/acl"
 	"github.com/hashicorp/nomad/client/config"
@@ -59,6 +60,20 @@ func TestClient_ACL_resolveTokenValue(t *testing.T) {
 	if out1 != out3 {
 		t.Fatalf("bad caching")
 	}
+
+	// Cached tokens that have since expired are rejected, whether the cached
+	// value is fresh or stale
+	expired := mock.ACLToken()
+	expiredAt := time.Now().Add(-time.Second)
+	expired.ExpirationTime = &expiredAt
+	for _, cacheTime := range []time.Time{time.Now(), time.Now().Add(-time.Hour)} {
+		c1.tokenCache.Add(expired.SecretID, &cachedACLValue{
+			Token:     expired,
+			CacheTime: cacheTime,
+		})
+		_, err = c1.resolveTokenValue(expired.SecretID)
+		assert.Equal(t, structs.ErrTokenNotFound, err)
+	}
 }
 
 func TestClient_ACL_resolvePolicies(t *testing.T) {
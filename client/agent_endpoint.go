@@ -0,0 +1,98 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/command/agent/monitor"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/ugorji/go/codec"
+)
+
+// Agent endpoint is used for streaming the logs of the agent of the client.
+type Agent struct {
+	c *Client
+}
+
+func NewAgentEndpoint(c *Client) *Agent {
+	a := &Agent{c}
+	a.c.streamingRpcs.Register("Agent.Monitor", a.monitor)
+	return a
+}
+
+// monitor streams the logs of the agent at or above the requested level until
+// the stream is closed.
+func (a *Agent) monitor(conn io.ReadWriteCloser) {
+	defer metrics.MeasureSince([]string{"client", "agent", "monitor"}, time.Now())
+	defer conn.Close()
+
+	// Decode the arguments
+	var args cstructs.MonitorRequest
+	decoder := codec.NewDecoder(conn, structs.MsgpackHandle)
+	encoder := codec.NewEncoder(conn, structs.MsgpackHandle)
+
+	if err := decoder.Decode(&args); err != nil {
+		handleStreamResultError(err, helper.Int64ToPtr(500), encoder)
+		return
+	}
+
+	// Check agent read permissions
+	if aclObj, err := a.c.ResolveToken(args.AuthToken); err != nil {
+		handleStreamResultError(err, nil, encoder)
+		return
+	} else if aclObj != nil && !aclObj.AllowAgentRead() {
+		handleStreamResultError(structs.ErrPermissionDenied, helper.Int64ToPtr(403), encoder)
+		return
+	}
+
+	logLevel := args.LogLevel
+	if logLevel == "" {
+		logLevel = "INFO"
+	}
+	m, ok := monitor.New(logLevel, 512)
+	if !ok {
+		handleStreamResultError(fmt.Errorf("Unknown log level: %s", logLevel), helper.Int64ToPtr(400), encoder)
+		return
+	}
+
+	source := a.c.config.LogSource
+	if source == nil {
+		handleStreamResultError(fmt.Errorf("Agent logs are not available"), helper.Int64ToPtr(500), encoder)
+		return
+	}
+	source.RegisterHandler(m)
+	defer source.DeregisterHandler(m)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Create a goroutine to detect the remote side closing
+	go func() {
+		for {
+			if _, err := conn.Read(nil); err != nil {
+				cancel()
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-a.c.shutdownCh:
+			return
+		case line := <-m.LogCh():
+			resp := cstructs.StreamErrWrapper{Payload: []byte(line + "\n")}
+			if err := encoder.Encode(resp); err != nil {
+				return
+			}
+			encoder.Reset(conn)
+		}
+	}
+}
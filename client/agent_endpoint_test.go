@@ -0,0 +1,135 @@
+package client
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/client/config"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/command/agent/monitor"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+	"github.com/ugorji/go/codec"
+)
+
+// testLogSource is a LogSource whose logs are emitted by the test
+type testLogSource struct {
+	mu       sync.Mutex
+	handlers map[monitor.LogHandler]struct{}
+}
+
+func newTestLogSource() *testLogSource {
+	return &testLogSource{handlers: make(map[monitor.LogHandler]struct{})}
+}
+
+func (s *testLogSource) RegisterHandler(h monitor.LogHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[h] = struct{}{}
+}
+
+func (s *testLogSource) DeregisterHandler(h monitor.LogHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.handlers, h)
+}
+
+func (s *testLogSource) numHandlers() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.handlers)
+}
+
+func (s *testLogSource) log(line string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for h := range s.handlers {
+		h.HandleLog(line)
+	}
+}
+
+func TestAgent_Monitor(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	// Start a client
+	source := newTestLogSource()
+	c, cleanup := TestClient(t, func(c *config.Config) {
+		c.LogSource = source
+	})
+	defer cleanup()
+
+	req := &cstructs.MonitorRequest{
+		LogLevel:     "warn",
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+
+	// Get the handler
+	handler, err := c.StreamingRpcHandler("Agent.Monitor")
+	require.Nil(err)
+
+	// Create a pipe
+	p1, p2 := net.Pipe()
+	defer p1.Close()
+	defer p2.Close()
+
+	errCh := make(chan error)
+	streamMsg := make(chan *cstructs.StreamErrWrapper)
+
+	// Start the handler
+	go handler(p2)
+
+	// Start the decoder
+	go func() {
+		decoder := codec.NewDecoder(p1, structs.MsgpackHandle)
+		for {
+			var msg cstructs.StreamErrWrapper
+			if err := decoder.Decode(&msg); err != nil {
+				if err == io.EOF || strings.Contains(err.Error(), "closed") {
+					return
+				}
+				errCh <- fmt.Errorf("error decoding: %v", err)
+			}
+
+			streamMsg <- &msg
+		}
+	}()
+
+	// Send the request
+	encoder := codec.NewEncoder(p1, structs.MsgpackHandle)
+	require.Nil(encoder.Encode(req))
+
+	// Wait for the monitor to be registered before logging
+	testutil.WaitForResult(func() (bool, error) {
+		return source.numHandlers() == 1, fmt.Errorf("monitor not registered")
+	}, func(err error) {
+		t.Fatal(err)
+	})
+	source.log("[INFO ] client: dropped")
+	source.log("[WARN ] client: kept")
+
+	timeout := time.After(3 * time.Second)
+	select {
+	case <-timeout:
+		t.Fatal("timeout")
+	case err := <-errCh:
+		t.Fatal(err)
+	case msg := <-streamMsg:
+		require.Nil(msg.Error)
+		require.Equal("[WARN ] client: kept\n", string(msg.Payload))
+	}
+
+	// Closing the stream deregisters the monitor
+	p1.Close()
+	testutil.WaitForResult(func() (bool, error) {
+		return source.numHandlers() == 0, fmt.Errorf("monitor not deregistered")
+	}, func(err error) {
+		t.Fatal(err)
+	})
+}
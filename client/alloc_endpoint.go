@@ -1,12 +1,25 @@
 package client
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
 	"time"
 
 	metrics "github.com/armon/go-metrics"
 	"github.com/hashicorp/nomad/acl"
 	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/helper"
 	nstructs "github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/plugins/drivers"
+	"github.com/ugorji/go/codec"
+)
+
+var (
+	cmdNotPresentErr = errors.New("must provide a command")
 )
 
 // Allocations endpoint is used for interacting with client allocations
@@ -14,6 +27,12 @@ type Allocations struct {
 	c *Client
 }
 
+func NewAllocationsEndpoint(c *Client) *Allocations {
+	a := &Allocations{c}
+	a.c.streamingRpcs.Register("Allocations.Exec", a.exec)
+	return a
+}
+
 // GarbageCollectAll is used to garbage collect all allocations on a client.
 func (a *Allocations) GarbageCollectAll(args *nstructs.NodeSpecificRequest, reply *nstructs.GenericResponse) error {
 	defer metrics.MeasureSince([]string{"client", "allocations", "garbage_collect_all"}, time.Now())
@@ -48,6 +67,49 @@ func (a *Allocations) GarbageCollect(args *nstructs.AllocSpecificRequest, reply
 	return nil
 }
 
+// Restart is used to restart a task of an allocation, or all its tasks.
+func (a *Allocations) Restart(args *cstructs.AllocRestartRequest, reply *nstructs.GenericResponse) error {
+	defer metrics.MeasureSince([]string{"client", "allocations", "restart"}, time.Now())
+
+	alloc, err := a.c.GetAllocByID(args.AllocID)
+	if err != nil {
+		return err
+	}
+
+	// Check alloc-lifecycle permissions in the namespace of the allocation
+	if aclObj, err := a.c.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNsOp(alloc.Namespace, acl.NamespaceCapabilityAllocLifecycle) {
+		return nstructs.ErrPermissionDenied
+	}
+
+	return a.c.RestartAllocation(args.AllocID, args.TaskName)
+}
+
+// Signal is used to send a signal to a task of an allocation, or to all its
+// tasks.
+func (a *Allocations) Signal(args *cstructs.AllocSignalRequest, reply *nstructs.GenericResponse) error {
+	defer metrics.MeasureSince([]string{"client", "allocations", "signal"}, time.Now())
+
+	alloc, err := a.c.GetAllocByID(args.AllocID)
+	if err != nil {
+		return err
+	}
+
+	// Check alloc-lifecycle permissions in the namespace of the allocation
+	if aclObj, err := a.c.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNsOp(alloc.Namespace, acl.NamespaceCapabilityAllocLifecycle) {
+		return nstructs.ErrPermissionDenied
+	}
+
+	if args.Signal == "" {
+		return errors.New("missing signal")
+	}
+
+	return a.c.SignalAllocation(args.AllocID, args.Task, args.Signal)
+}
+
 // Stats is used to collect allocation statistics
 func (a *Allocations) Stats(args *cstructs.AllocStatsRequest, reply *cstructs.AllocStatsResponse) error {
 	defer metrics.MeasureSince([]string{"client", "allocations", "stats"}, time.Now())
@@ -73,3 +135,195 @@ func (a *Allocations) Stats(args *cstructs.AllocStatsRequest, reply *cstructs.Al
 	reply.Stats = stats
 	return nil
 }
+
+// exec is used to execute an interactive command within a running task. The
+// caller sends an AllocExecRequest followed by ExecStreamingInput messages
+// and receives StreamErrWrapper messages whose payload is a JSON encoded
+// ExecStreamingOutput, the last of which holds the exit result of the command.
+func (a *Allocations) exec(conn io.ReadWriteCloser) {
+	defer metrics.MeasureSince([]string{"client", "allocations", "exec"}, time.Now())
+	defer conn.Close()
+
+	decoder := codec.NewDecoder(conn, nstructs.MsgpackHandle)
+	encoder := codec.NewEncoder(conn, nstructs.MsgpackHandle)
+
+	if code, err := a.execImpl(encoder, decoder); err != nil {
+		handleStreamResultError(err, code, encoder)
+	}
+}
+
+func (a *Allocations) execImpl(encoder *codec.Encoder, decoder *codec.Decoder) (*int64, error) {
+	// Decode the arguments
+	var req cstructs.AllocExecRequest
+	if err := decoder.Decode(&req); err != nil {
+		return helper.Int64ToPtr(500), err
+	}
+
+	// Validate the arguments
+	if req.AllocID == "" {
+		return helper.Int64ToPtr(400), allocIDNotPresentErr
+	}
+	if req.Task == "" {
+		return helper.Int64ToPtr(400), taskNotPresentErr
+	}
+	if len(req.Cmd) == 0 {
+		return helper.Int64ToPtr(400), cmdNotPresentErr
+	}
+
+	alloc, err := a.c.GetAllocByID(req.AllocID)
+	if err != nil {
+		code := helper.Int64ToPtr(500)
+		if nstructs.IsErrUnknownAllocation(err) {
+			code = helper.Int64ToPtr(404)
+		}
+		return code, err
+	}
+
+	// Check alloc-exec permissions in the namespace of the allocation
+	aclObj, err := a.c.ResolveToken(req.QueryOptions.AuthToken)
+	if err != nil {
+		return nil, err
+	} else if aclObj != nil && !aclObj.AllowNsOp(alloc.Namespace, acl.NamespaceCapabilityAllocExec) {
+		return nil, nstructs.ErrPermissionDenied
+	}
+
+	allocState, err := a.c.GetAllocState(req.AllocID)
+	if err != nil {
+		code := helper.Int64ToPtr(500)
+		if nstructs.IsErrUnknownAllocation(err) {
+			code = helper.Int64ToPtr(404)
+		}
+		return code, err
+	}
+
+	// Check that the task is there and running
+	taskState := allocState.TaskStates[req.Task]
+	if taskState == nil {
+		return helper.Int64ToPtr(400), fmt.Errorf("unknown task name %q", req.Task)
+	}
+	if taskState.State != nstructs.TaskStateRunning {
+		return helper.Int64ToPtr(404), fmt.Errorf("task %q is not running", req.Task)
+	}
+
+	// The commands of the tasks of drivers without filesystem isolation run
+	// directly on the node, which requires the alloc-node-exec capability.
+	// It is also required if the capabilities of the driver are unknown.
+	caps, err := a.c.GetTaskDriverCapabilities(req.AllocID, req.Task)
+	if err != nil {
+		return helper.Int64ToPtr(500), err
+	}
+	if (caps == nil || caps.FSIsolation == cstructs.FSIsolationNone) && aclObj != nil &&
+		!aclObj.AllowNsOp(alloc.Namespace, acl.NamespaceCapabilityAllocNodeExec) {
+		return nil, nstructs.ErrPermissionDenied
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The output of the command is sent by the stdout and stderr writers
+	// concurrently
+	var sendLock sync.Mutex
+	send := func(out *cstructs.ExecStreamingOutput) error {
+		buf, err := json.Marshal(out)
+		if err != nil {
+			return err
+		}
+
+		sendLock.Lock()
+		defer sendLock.Unlock()
+		return encoder.Encode(&cstructs.StreamErrWrapper{Payload: buf})
+	}
+
+	stdinReader, stdinWriter := io.Pipe()
+	defer stdinReader.Close()
+	resizeCh := make(chan drivers.TerminalSize, 1)
+	go a.execRecv(cancel, decoder, stdinWriter, resizeCh)
+
+	opts := &drivers.ExecOptions{
+		Command:  req.Cmd,
+		Tty:      req.Tty,
+		Stdin:    stdinReader,
+		Stdout:   &execStreamWriter{send: send},
+		Stderr:   &execStreamWriter{send: send, stderr: true},
+		ResizeCh: resizeCh,
+	}
+	res, err := a.c.ExecTask(ctx, req.AllocID, req.Task, opts)
+	if err != nil {
+		return helper.Int64ToPtr(500), err
+	}
+	if res.Err != nil {
+		return helper.Int64ToPtr(500), res.Err
+	}
+
+	result := &cstructs.ExecStreamingOutput{
+		Exited: true,
+		Result: &cstructs.ExecStreamingExitResult{ExitCode: res.ExitCode},
+	}
+	if err := send(result); err != nil {
+		return helper.Int64ToPtr(500), err
+	}
+	return nil, nil
+}
+
+// execRecv forwards the input of the caller of an exec session to the command
+// until the caller disconnects, at which point the session is cancelled.
+func (a *Allocations) execRecv(cancel context.CancelFunc, decoder *codec.Decoder,
+	stdin *io.PipeWriter, resizeCh chan drivers.TerminalSize) {
+	defer cancel()
+
+	for {
+		var in cstructs.ExecStreamingInput
+		if err := decoder.Decode(&in); err != nil {
+			stdin.CloseWithError(err)
+			return
+		}
+
+		if in.Stdin != nil {
+			if len(in.Stdin.Data) > 0 {
+				if _, err := stdin.Write(in.Stdin.Data); err != nil {
+					return
+				}
+			}
+			if in.Stdin.Close {
+				stdin.Close()
+			}
+		}
+
+		if in.TTYSize != nil {
+			// Only the latest terminal size is relevant
+			select {
+			case <-resizeCh:
+			default:
+			}
+			resizeCh <- drivers.TerminalSize{
+				Height: in.TTYSize.Height,
+				Width:  in.TTYSize.Width,
+			}
+		}
+	}
+}
+
+// execStreamWriter sends the stdout or stderr of the command of an exec
+// session to the caller.
+type execStreamWriter struct {
+	send   func(*cstructs.ExecStreamingOutput) error
+	stderr bool
+}
+
+func (w *execStreamWriter) Write(p []byte) (int, error) {
+	if err := w.send(w.output(&cstructs.ExecStreamingIOOperation{Data: p})); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (w *execStreamWriter) Close() error {
+	return w.send(w.output(&cstructs.ExecStreamingIOOperation{Close: true}))
+}
+
+func (w *execStreamWriter) output(op *cstructs.ExecStreamingIOOperation) *cstructs.ExecStreamingOutput {
+	if w.stderr {
+		return &cstructs.ExecStreamingOutput{Stderr: op}
+	}
+	return &cstructs.ExecStreamingOutput{Stdout: op}
+}
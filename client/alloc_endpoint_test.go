@@ -1,16 +1,24 @@
 package client
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
+	"net"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/nomad/acl"
 	"github.com/hashicorp/nomad/client/config"
 	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad"
 	"github.com/hashicorp/nomad/nomad/mock"
 	nstructs "github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/testutil"
 	"github.com/stretchr/testify/require"
+	"github.com/ugorji/go/codec"
 )
 
 func TestAllocations_GarbageCollectAll(t *testing.T) {
@@ -267,3 +275,330 @@ func TestAllocations_Stats_ACL(t *testing.T) {
 		require.True(nstructs.IsErrUnknownAllocation(err))
 	}
 }
+
+func TestAllocations_Exec_ACL(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	// Start a server
+	s, root := nomad.TestACLServer(t, nil)
+	defer s.Shutdown()
+	testutil.WaitForLeader(t, s.RPC)
+
+	client, cleanup := TestClient(t, func(c *config.Config) {
+		c.ACLEnabled = true
+		c.Servers = []string{s.GetConfig().RPCAddr.String()}
+	})
+	defer cleanup()
+
+	// Create a bad token
+	policyBad := mock.NamespacePolicy(nstructs.DefaultNamespace, "", []string{acl.NamespaceCapabilityReadFS})
+	tokenBad := mock.CreatePolicyAndToken(t, s.State(), 1005, "invalid", policyBad)
+
+	policyGood := mock.NamespacePolicy(nstructs.DefaultNamespace, "",
+		[]string{acl.NamespaceCapabilityAllocExec})
+	tokenGood := mock.CreatePolicyAndToken(t, s.State(), 1009, "valid2", policyGood)
+
+	// Permissions are checked in the namespace of the allocation, so unknown
+	// allocations are reported before permissions are checked. Denials are
+	// covered by TestAllocations_Exec_NodeExecACL.
+	cases := []struct {
+		Name          string
+		Token         string
+		ExpectedError string
+	}{
+		{
+			Name:          "bad token",
+			Token:         tokenBad.SecretID,
+			ExpectedError: nstructs.ErrUnknownAllocationPrefix,
+		},
+		{
+			Name:          "good token",
+			Token:         tokenGood.SecretID,
+			ExpectedError: nstructs.ErrUnknownAllocationPrefix,
+		},
+		{
+			Name:          "root token",
+			Token:         root.SecretID,
+			ExpectedError: nstructs.ErrUnknownAllocationPrefix,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			// Make the request with bad allocation id
+			req := &cstructs.AllocExecRequest{
+				AllocID: uuid.Generate(),
+				Task:    "web",
+				Cmd:     []string{"ls"},
+				QueryOptions: nstructs.QueryOptions{
+					Namespace: nstructs.DefaultNamespace,
+					Region:    "global",
+					AuthToken: c.Token,
+				},
+			}
+
+			msgs, closeFn := execStream(t, client, req, nil)
+			defer closeFn()
+
+			timeout := time.After(5 * time.Second)
+
+		OUTER:
+			for {
+				select {
+				case <-timeout:
+					t.Fatal("timeout")
+				case msg, ok := <-msgs:
+					require.True(ok, "stream closed without an error")
+					if msg.Error == nil {
+						continue
+					}
+
+					if strings.Contains(msg.Error.Error(), c.ExpectedError) {
+						break OUTER
+					} else {
+						t.Fatalf("Bad error: %v", msg.Error)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestAllocations_Exec_NodeExecACL(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	// Start a server and client
+	s, root := nomad.TestACLServer(t, nil)
+	defer s.Shutdown()
+	testutil.WaitForLeader(t, s.RPC)
+
+	client, cleanup := TestClient(t, func(c *config.Config) {
+		c.ACLEnabled = true
+		c.Servers = []string{s.GetConfig().RPCAddr.String()}
+	})
+	defer cleanup()
+
+	// The tasks of the mock driver have no filesystem isolation
+	job := mock.BatchJob()
+	job.TaskGroups[0].Count = 1
+	job.TaskGroups[0].Tasks[0].Config = map[string]interface{}{
+		"run_for": "20s",
+	}
+	rpc := func(method string, args, reply interface{}) error {
+		switch req := args.(type) {
+		case *nstructs.JobRegisterRequest:
+			req.AuthToken = root.SecretID
+		case *nstructs.JobSpecificRequest:
+			req.AuthToken = root.SecretID
+		}
+		return s.RPC(method, args, reply)
+	}
+	alloc := testutil.WaitForRunning(t, rpc, job)[0]
+
+	policyBad := mock.NamespacePolicy(nstructs.DefaultNamespace, "", []string{acl.NamespaceCapabilityReadFS})
+	tokenBad := mock.CreatePolicyAndToken(t, s.State(), 1003, "invalid", policyBad)
+
+	policyOther := mock.NamespacePolicy("other", "",
+		[]string{acl.NamespaceCapabilityAllocExec, acl.NamespaceCapabilityAllocNodeExec})
+	tokenOther := mock.CreatePolicyAndToken(t, s.State(), 1004, "other", policyOther)
+
+	policyExec := mock.NamespacePolicy(nstructs.DefaultNamespace, "",
+		[]string{acl.NamespaceCapabilityAllocExec})
+	tokenExec := mock.CreatePolicyAndToken(t, s.State(), 1005, "exec", policyExec)
+
+	policyNodeExec := mock.NamespacePolicy(nstructs.DefaultNamespace, "",
+		[]string{acl.NamespaceCapabilityAllocExec, acl.NamespaceCapabilityAllocNodeExec})
+	tokenNodeExec := mock.CreatePolicyAndToken(t, s.State(), 1009, "node-exec", policyNodeExec)
+
+	cases := []struct {
+		Name      string
+		Token     string
+		Namespace string
+		Denied    bool
+	}{
+		{
+			Name:      "bad token",
+			Token:     tokenBad.SecretID,
+			Namespace: nstructs.DefaultNamespace,
+			Denied:    true,
+		},
+		{
+			// Permissions are checked in the namespace of the allocation,
+			// not the one of the request
+			Name:      "other namespace token",
+			Token:     tokenOther.SecretID,
+			Namespace: "other",
+			Denied:    true,
+		},
+		{
+			Name:      "alloc-exec token",
+			Token:     tokenExec.SecretID,
+			Namespace: nstructs.DefaultNamespace,
+			Denied:    true,
+		},
+		{
+			Name:      "alloc-node-exec token",
+			Token:     tokenNodeExec.SecretID,
+			Namespace: nstructs.DefaultNamespace,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			req := &cstructs.AllocExecRequest{
+				AllocID: alloc.ID,
+				Task:    job.TaskGroups[0].Tasks[0].Name,
+				Cmd:     []string{"cat"},
+				QueryOptions: nstructs.QueryOptions{
+					Namespace: c.Namespace,
+					Region:    "global",
+					AuthToken: c.Token,
+				},
+			}
+			var input []*cstructs.ExecStreamingInput
+			if !c.Denied {
+				input = append(input, &cstructs.ExecStreamingInput{
+					Stdin: &cstructs.ExecStreamingIOOperation{Close: true},
+				})
+			}
+			msgs, closeFn := execStream(t, client, req, input)
+			defer closeFn()
+
+			timeout := time.After(10 * time.Second)
+			for {
+				select {
+				case <-timeout:
+					t.Fatal("timeout")
+				case msg, ok := <-msgs:
+					require.True(ok, "stream closed before the command exited")
+					if c.Denied {
+						require.NotNil(msg.Error)
+						require.Contains(msg.Error.Error(), nstructs.ErrPermissionDenied.Error())
+						return
+					}
+					require.Nil(msg.Error)
+
+					var out cstructs.ExecStreamingOutput
+					require.NoError(json.Unmarshal(msg.Payload, &out))
+					if out.Exited {
+						return
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestAllocations_Exec(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	// Start a server and client
+	s := nomad.TestServer(t, nil)
+	defer s.Shutdown()
+	testutil.WaitForLeader(t, s.RPC)
+
+	client, cleanup := TestClient(t, func(c *config.Config) {
+		c.Servers = []string{s.GetConfig().RPCAddr.String()}
+	})
+	defer cleanup()
+
+	job := mock.BatchJob()
+	job.TaskGroups[0].Count = 1
+	job.TaskGroups[0].Tasks[0].Config = map[string]interface{}{
+		"run_for": "20s",
+	}
+
+	// Wait for alloc to be running
+	alloc := testutil.WaitForRunning(t, s.RPC, job)[0]
+
+	req := &cstructs.AllocExecRequest{
+		AllocID: alloc.ID,
+		Task:    job.TaskGroups[0].Tasks[0].Name,
+		Cmd:     []string{"cat"},
+		QueryOptions: nstructs.QueryOptions{
+			Region: "global",
+		},
+	}
+
+	// The mock driver echoes stdin to stdout until stdin is closed
+	input := []*cstructs.ExecStreamingInput{
+		{Stdin: &cstructs.ExecStreamingIOOperation{Data: []byte("hello from stdin")}},
+		{Stdin: &cstructs.ExecStreamingIOOperation{Close: true}},
+	}
+	msgs, closeFn := execStream(t, client, req, input)
+	defer closeFn()
+
+	var stdout []byte
+	timeout := time.After(10 * time.Second)
+	for {
+		select {
+		case <-timeout:
+			t.Fatal("timeout")
+		case msg, ok := <-msgs:
+			require.True(ok, "stream closed before the command exited")
+			require.Nil(msg.Error)
+
+			var out cstructs.ExecStreamingOutput
+			require.NoError(json.Unmarshal(msg.Payload, &out))
+			if out.Stdout != nil {
+				stdout = append(stdout, out.Stdout.Data...)
+			}
+			if out.Exited {
+				require.NotNil(out.Result)
+				require.Equal(0, out.Result.ExitCode)
+				require.Equal("hello from stdin", string(stdout))
+				return
+			}
+		}
+	}
+}
+
+// execStream starts an exec session through the streaming RPC handler of the
+// client, sends the given input and returns the received messages and a
+// function closing the session.
+func execStream(t *testing.T, client *Client, req *cstructs.AllocExecRequest,
+	input []*cstructs.ExecStreamingInput) (<-chan *cstructs.StreamErrWrapper, func()) {
+
+	// Get the handler
+	handler, err := client.StreamingRpcHandler("Allocations.Exec")
+	require.Nil(t, err)
+
+	// Create a pipe
+	p1, p2 := net.Pipe()
+	streamMsg := make(chan *cstructs.StreamErrWrapper, 10)
+
+	// Start the handler
+	go handler(p2)
+
+	// Start the decoder
+	go func() {
+		defer close(streamMsg)
+		decoder := codec.NewDecoder(p1, nstructs.MsgpackHandle)
+		for {
+			var msg cstructs.StreamErrWrapper
+			if err := decoder.Decode(&msg); err != nil {
+				if err != io.EOF && !strings.Contains(err.Error(), "closed") {
+					t.Logf("error decoding: %v", err)
+				}
+				return
+			}
+
+			streamMsg <- &msg
+		}
+	}()
+
+	// Send the request and the input
+	encoder := codec.NewEncoder(p1, nstructs.MsgpackHandle)
+	require.Nil(t, encoder.Encode(req))
+	for _, in := range input {
+		require.Nil(t, encoder.Encode(in))
+	}
+
+	return streamMsg, func() {
+		p1.Close()
+		p2.Close()
+	}
+}
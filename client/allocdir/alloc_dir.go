@@ -55,6 +55,11 @@ var (
 	// directory
 	TaskSecrets = "secrets"
 
+	// TaskCheckpoint is the name of the directory inside the task's local
+	// directory that drivers store checkpoints of the task in. Keeping it
+	// in the local directory migrates it along with the task's data.
+	TaskCheckpoint = ".checkpoint"
+
 	// TaskDirs is the set of directories created in each tasks directory.
 	TaskDirs = map[string]os.FileMode{TmpDirName: os.ModeSticky | 0777}
 )
@@ -40,6 +40,10 @@ type TaskDir struct {
 	// <task_dir>/secrets/
 	SecretsDir string
 
+	// CheckpointDir is the path drivers store checkpoints of the task in
+	// <task_dir>/local/.checkpoint/
+	CheckpointDir string
+
 	logger hclog.Logger
 }
 
@@ -60,6 +64,7 @@ func newTaskDir(logger hclog.Logger, allocDir, taskName string) *TaskDir {
 		SharedTaskDir:  filepath.Join(taskDir, SharedAllocName),
 		LocalDir:       filepath.Join(taskDir, TaskLocal),
 		SecretsDir:     filepath.Join(taskDir, TaskSecrets),
+		CheckpointDir:  filepath.Join(taskDir, TaskLocal, TaskCheckpoint),
 		logger:         logger,
 	}
 }
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	log "github.com/hashicorp/go-hclog"
+	multierror "github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/nomad/client/allocdir"
 	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
 	"github.com/hashicorp/nomad/client/allocrunner/state"
@@ -17,12 +18,14 @@ import (
 	"github.com/hashicorp/nomad/client/consul"
 	"github.com/hashicorp/nomad/client/devicemanager"
 	cinterfaces "github.com/hashicorp/nomad/client/interfaces"
+	"github.com/hashicorp/nomad/client/lib/cgutil"
 	cstate "github.com/hashicorp/nomad/client/state"
 	cstructs "github.com/hashicorp/nomad/client/structs"
 	"github.com/hashicorp/nomad/client/vaultclient"
 	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/plugins/device"
+	"github.com/hashicorp/nomad/plugins/drivers"
 	"github.com/hashicorp/nomad/plugins/shared/loader"
 )
 
@@ -56,6 +59,9 @@ type allocRunner struct {
 	// vaultClient is the used to manage Vault tokens
 	vaultClient vaultclient.VaultClient
 
+	// rpcClient is used to make RPC calls to the servers
+	rpcClient cinterfaces.RPCer
+
 	// waitCh is closed when the Run() loop has exited
 	waitCh chan struct{}
 
@@ -109,6 +115,9 @@ type allocRunner struct {
 	// devicemanager is used to mount devices as well as lookup device
 	// statistics
 	devicemanager devicemanager.Manager
+
+	// cpusetManager tracks the cores dedicated to tasks
+	cpusetManager *cgutil.CpusetManager
 }
 
 // NewAllocRunner returns a new allocation runner.
@@ -125,6 +134,7 @@ func NewAllocRunner(config *Config) (*allocRunner, error) {
 		clientConfig:             config.ClientConfig,
 		consulClient:             config.Consul,
 		vaultClient:              config.Vault,
+		rpcClient:                config.RPCClient,
 		tasks:                    make(map[string]*taskrunner.TaskRunner, len(tg.Tasks)),
 		waitCh:                   make(chan struct{}),
 		state:                    &state.State{},
@@ -136,6 +146,7 @@ func NewAllocRunner(config *Config) (*allocRunner, error) {
 		prevAllocWatcher:         config.PrevAllocWatcher,
 		pluginSingletonLoader:    config.PluginSingletonLoader,
 		devicemanager:            config.DeviceManager,
+		cpusetManager:            config.CpusetManager,
 	}
 
 	// Create the logger based on the allocation ID
@@ -171,9 +182,11 @@ func (ar *allocRunner) initTaskRunners(tasks []*structs.Task) error {
 			StateUpdater:          ar,
 			Consul:                ar.consulClient,
 			Vault:                 ar.vaultClient,
+			RPCClient:             ar.rpcClient,
 			PluginSingletonLoader: ar.pluginSingletonLoader,
 			DeviceStatsReporter:   ar.deviceStatsReporter,
 			DeviceManager:         ar.devicemanager,
+			CpusetManager:         ar.cpusetManager,
 		}
 
 		// Create, but do not Run, the task runner
@@ -477,6 +490,10 @@ func (ar *allocRunner) clientAlloc(taskStates map[string]*structs.TaskState) *st
 		a.DeploymentStatus = d.Copy()
 	}
 
+	if n := ar.state.NetworkStatus; n != nil {
+		a.NetworkStatus = n.Copy()
+	}
+
 	// Compute the ClientStatus
 	if ar.state.ClientStatus != "" {
 		// The client status is being forced
@@ -720,6 +737,83 @@ func (ar *allocRunner) StatsReporter() interfaces.AllocStatsReporter {
 	return ar
 }
 
+// ExecTask runs an interactive command within the named task and blocks until
+// the command exits or ctx is cancelled.
+func (ar *allocRunner) ExecTask(ctx context.Context, taskName string, opts *drivers.ExecOptions) (*drivers.ExitResult, error) {
+	tr, ok := ar.tasks[taskName]
+	if !ok {
+		return nil, fmt.Errorf("unknown task name %q", taskName)
+	}
+	return tr.ExecStreaming(ctx, opts)
+}
+
+// GetTaskDriverCapabilities returns the capabilities of the driver of the
+// named task of the allocation.
+func (ar *allocRunner) GetTaskDriverCapabilities(taskName string) (*drivers.Capabilities, error) {
+	tr, ok := ar.tasks[taskName]
+	if !ok {
+		return nil, fmt.Errorf("unknown task name %q", taskName)
+	}
+	return tr.DriverCapabilities(), nil
+}
+
+// RestartTask restarts the named task of the allocation and blocks until it
+// has been killed to be restarted.
+func (ar *allocRunner) RestartTask(taskName string, event *structs.TaskEvent) error {
+	tr, ok := ar.tasks[taskName]
+	if !ok {
+		return fmt.Errorf("Could not find task runner for task: %s", taskName)
+	}
+	return tr.Restart(context.TODO(), event, false)
+}
+
+// RestartAll restarts all the running tasks of the allocation concurrently
+// and blocks until they have been killed to be restarted.
+func (ar *allocRunner) RestartAll(event *structs.TaskEvent) error {
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var merr multierror.Error
+	for name, tr := range ar.tasks {
+		wg.Add(1)
+		go func(name string, tr *taskrunner.TaskRunner) {
+			defer wg.Done()
+			err := tr.Restart(context.TODO(), event.Copy(), false)
+			if err == nil || err == taskrunner.ErrTaskNotRunning {
+				return
+			}
+			mu.Lock()
+			merr.Errors = append(merr.Errors, fmt.Errorf("failed to restart task %s: %v", name, err))
+			mu.Unlock()
+		}(name, tr)
+	}
+	wg.Wait()
+	return merr.ErrorOrNil()
+}
+
+// Signal sends the signal to the named task of the allocation, or to all its
+// running tasks if taskName is empty.
+func (ar *allocRunner) Signal(taskName, signal string) error {
+	event := structs.NewTaskEvent(structs.TaskSignaling).
+		SetTaskSignalReason(fmt.Sprintf("User requested signal %s", signal))
+
+	if taskName != "" {
+		tr, ok := ar.tasks[taskName]
+		if !ok {
+			return fmt.Errorf("Task not found")
+		}
+		return tr.Signal(event, signal)
+	}
+
+	var merr multierror.Error
+	for name, tr := range ar.tasks {
+		err := tr.Signal(event.Copy(), signal)
+		if err != nil && err != taskrunner.ErrTaskNotRunning {
+			merr.Errors = append(merr.Errors, fmt.Errorf("failed to signal task %s: %v", name, err))
+		}
+	}
+	return merr.ErrorOrNil()
+}
+
 // LatestAllocStats returns the latest stats for an allocation. If taskFilter
 // is set, only stats for that task -- if it exists -- are returned.
 func (ar *allocRunner) LatestAllocStats(taskFilter string) (*cstructs.AllocResourceUsage, error) {
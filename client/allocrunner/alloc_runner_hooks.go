@@ -7,7 +7,9 @@ import (
 
 	multierror "github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	cstructs "github.com/hashicorp/nomad/client/structs"
 	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/plugins/drivers"
 )
 
 // allocHealthSetter is a shim to allow the alloc health watcher hook to set
@@ -66,6 +68,50 @@ func (a *allocHealthSetter) SetHealth(healthy, isDeploy bool, trackerTaskEvents
 	a.ar.allocBroadcaster.Send(calloc)
 }
 
+// allocNetworkSetter is a shim to allow the network hook to set the network
+// of the alloc and its tasks without full access to the alloc runner state
+type allocNetworkSetter struct {
+	ar *allocRunner
+}
+
+// SetNetwork sets the network namespace of the alloc's tasks and the status
+// of its network. The network is used as the network of tasks whose driver
+// doesn't create a network of its own.
+//
+// Only for use by the network hook.
+func (a *allocNetworkSetter) SetNetwork(spec *drivers.NetworkIsolationSpec, net *cstructs.DriverNetwork, status *structs.AllocNetworkStatus) {
+	for _, tr := range a.ar.tasks {
+		tr.SetNetworkIsolation(spec, net)
+	}
+
+	a.ar.stateLock.Lock()
+	a.ar.state.NetworkStatus = status
+	a.ar.stateLock.Unlock()
+}
+
+// NetworkManager returns the driver that must create the network namespace
+// of the alloc, or nil if none of the alloc's tasks use such a driver. Tasks
+// of a single alloc may not use different drivers that must create it.
+//
+// Only for use by the network hook.
+func (a *allocNetworkSetter) NetworkManager() (drivers.DriverNetworkManager, error) {
+	var manager drivers.DriverNetworkManager
+	var managerDriver string
+	for _, tr := range a.ar.tasks {
+		nm, ok := tr.DriverNetworkManager()
+		if !ok {
+			continue
+		}
+
+		driver := tr.Task().Driver
+		if manager != nil && driver != managerDriver {
+			return nil, fmt.Errorf("drivers %q and %q both must create the network namespace of the allocation", managerDriver, driver)
+		}
+		manager, managerDriver = nm, driver
+	}
+	return manager, nil
+}
+
 // initRunnerHooks intializes the runners hooks.
 func (ar *allocRunner) initRunnerHooks() {
 	hookLogger := ar.logger.Named("runner_hook")
@@ -73,10 +119,14 @@ func (ar *allocRunner) initRunnerHooks() {
 	// create health setting shim
 	hs := &allocHealthSetter{ar}
 
+	// create network setting shim
+	ns := &allocNetworkSetter{ar}
+
 	// Create the alloc directory hook. This is run first to ensure the
 	// directory path exists for other hooks.
 	ar.runnerHooks = []interfaces.RunnerHook{
 		newAllocDirHook(hookLogger, ar.allocDir),
+		newNetworkHook(hookLogger, ar.Alloc(), ns, ar.clientConfig),
 		newDiskMigrationHook(hookLogger, ar.prevAllocWatcher, ar.allocDir),
 		newAllocHealthWatcherHook(hookLogger, ar.Alloc(), hs, ar.Listener(), ar.consulClient),
 	}
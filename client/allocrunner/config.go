@@ -7,6 +7,7 @@ import (
 	"github.com/hashicorp/nomad/client/consul"
 	"github.com/hashicorp/nomad/client/devicemanager"
 	"github.com/hashicorp/nomad/client/interfaces"
+	"github.com/hashicorp/nomad/client/lib/cgutil"
 	cstate "github.com/hashicorp/nomad/client/state"
 	"github.com/hashicorp/nomad/client/vaultclient"
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -33,6 +34,10 @@ type Config struct {
 	// Vault is the Vault client to use to retrieve Vault tokens
 	Vault vaultclient.VaultClient
 
+	// RPCClient is used to make RPC calls to the servers, such as reading
+	// the variables of tasks
+	RPCClient interfaces.RPCer
+
 	// StateUpdater is used to emit updated task state
 	StateUpdater interfaces.AllocStateHandler
 
@@ -53,4 +58,7 @@ type Config struct {
 	// DeviceManager is used to mount devices as well as lookup device
 	// statistics
 	DeviceManager devicemanager.Manager
+
+	// CpusetManager tracks the cores dedicated to tasks
+	CpusetManager *cgutil.CpusetManager
 }
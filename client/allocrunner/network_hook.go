@@ -0,0 +1,320 @@
+package allocrunner
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/client/lib/cni"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/plugins/drivers"
+)
+
+// networkInterfaceName is the name of the interface of allocations in their
+// network namespace.
+const networkInterfaceName = "eth0"
+
+// networkSetter is able to set the network of an allocation.
+type networkSetter interface {
+	// SetNetwork sets the network namespace the tasks must run in, the
+	// network of tasks whose driver doesn't create one and the status of the
+	// network of the allocation
+	SetNetwork(spec *drivers.NetworkIsolationSpec, net *cstructs.DriverNetwork, status *structs.AllocNetworkStatus)
+
+	// NetworkManager returns the driver that must create the network
+	// namespace of the allocation or nil if the client creates it.
+	NetworkManager() (drivers.DriverNetworkManager, error)
+}
+
+// networkHook creates the network namespace of allocations in a bridge or CNI
+// network and adds it to the network before the tasks are started. The
+// namespace is removed from the network and destroyed once the tasks have
+// exited.
+type networkHook struct {
+	alloc         *structs.Allocation
+	networkSetter networkSetter
+
+	// cniPath are the directories the CNI plugins are searched in
+	cniPath []string
+
+	// cniConfigDir is the directory of the CNI network configurations
+	cniConfigDir string
+
+	// bridgeName and bridgeSubnet are the name and the subnet of the bridge
+	// of allocations in bridge mode
+	bridgeName   string
+	bridgeSubnet string
+
+	// hookLock is held by hook methods to serialize the creation and the
+	// destruction of the network namespace
+	hookLock sync.Mutex
+
+	// spec is the network namespace of the allocation. It is nil until the
+	// namespace is created or restored.
+	spec *drivers.NetworkIsolationSpec
+
+	// result is the result of adding the allocation to its network. It is
+	// nil when the namespace was restored or not created.
+	result *cni.Result
+
+	logger log.Logger
+}
+
+func newNetworkHook(logger log.Logger, alloc *structs.Allocation, ns networkSetter,
+	cfg *config.Config) *networkHook {
+
+	h := &networkHook{
+		alloc:         alloc,
+		networkSetter: ns,
+		cniPath:       filepath.SplitList(cfg.CNIPath),
+		cniConfigDir:  cfg.CNIConfigDir,
+		bridgeName:    cfg.BridgeNetworkName,
+		bridgeSubnet:  cfg.BridgeNetworkSubnet,
+	}
+	h.logger = logger.Named(h.Name())
+	return h
+}
+
+func (h *networkHook) Name() string {
+	return "network"
+}
+
+// groupNetworks returns the networks of the task group of the allocation.
+func (h *networkHook) groupNetworks() structs.Networks {
+	tg := h.alloc.Job.LookupTaskGroup(h.alloc.TaskGroup)
+	if tg == nil {
+		return nil
+	}
+	return tg.Networks
+}
+
+// isolated returns whether the allocation has a network namespace of its own.
+func (h *networkHook) isolated() bool {
+	networks := h.groupNetworks()
+	_, ok := networks.CNINetwork()
+	return ok || networks.Bridge() != nil
+}
+
+// network returns the bridge or CNI network the allocation is added to.
+func (h *networkHook) network() (*cni.Network, error) {
+	networks := h.groupNetworks()
+	if networks.Bridge() != nil {
+		return cni.NewBridgeNetwork(h.bridgeName, h.bridgeSubnet), nil
+	}
+	name, _ := networks.CNINetwork()
+	return h.loadNetwork(name)
+}
+
+// netNSPath returns the path of the network namespace of the allocation when
+// it is created by the client.
+func (h *networkHook) netNSPath() string {
+	return filepath.Join(cni.NetNSDir, h.alloc.ID)
+}
+
+// portMappings returns the mappings of the host ports of the allocation to
+// the ports its tasks listen on in their network namespace, and the ports
+// tasks listen on by label.
+func (h *networkHook) portMappings() ([]cni.PortMapping, map[string]int) {
+	if h.alloc.AllocatedResources == nil {
+		return nil, nil
+	}
+
+	var mappings []cni.PortMapping
+	portMap := make(map[string]int)
+	for _, n := range h.alloc.AllocatedResources.Shared.Networks {
+		ports := append(append([]structs.Port{}, n.ReservedPorts...), n.DynamicPorts...)
+		for _, p := range ports {
+			to := p.To
+			if to == 0 {
+				to = p.Value
+			}
+			portMap[p.Label] = to
+			for _, proto := range []string{"tcp", "udp"} {
+				mappings = append(mappings, cni.PortMapping{
+					HostPort:      p.Value,
+					ContainerPort: to,
+					Protocol:      proto,
+					HostIP:        p.HostIP,
+				})
+			}
+		}
+	}
+	return mappings, portMap
+}
+
+// runtimeConf returns the runtime configuration of the allocation in its
+// network.
+func (h *networkHook) runtimeConf(path string) *cni.RuntimeConf {
+	mappings, _ := h.portMappings()
+	return &cni.RuntimeConf{
+		ContainerID:  h.alloc.ID,
+		NetNS:        path,
+		IfName:       networkInterfaceName,
+		PortMappings: mappings,
+	}
+}
+
+// loadNetwork loads the configuration of a CNI network from the configuration
+// directory of the client.
+func (h *networkHook) loadNetwork(name string) (*cni.Network, error) {
+	networks, err := cni.LoadNetworks(h.cniConfigDir)
+	if err != nil {
+		// Invalid configurations of other networks are not fatal
+		h.logger.Warn("failed to load some CNI networks", "error", err)
+	}
+	network, ok := networks[name]
+	if !ok {
+		return nil, fmt.Errorf("CNI network %q is not configured", name)
+	}
+	return network, nil
+}
+
+// createNetNS creates the network namespace of the allocation, either with
+// the driver that must create it or in NetNSDir. The returned bool is false
+// if the namespace already existed.
+func (h *networkHook) createNetNS() (*drivers.NetworkIsolationSpec, bool, error) {
+	manager, err := h.networkSetter.NetworkManager()
+	if err != nil {
+		return nil, false, err
+	}
+	if manager != nil {
+		return manager.CreateNetwork(h.alloc.ID)
+	}
+
+	path := h.netNSPath()
+	if _, err := os.Stat(path); err == nil {
+		return &drivers.NetworkIsolationSpec{Path: path}, false, nil
+	}
+	if _, err := cni.CreateNetNS(h.alloc.ID); err != nil {
+		return nil, false, err
+	}
+	return &drivers.NetworkIsolationSpec{Path: path}, true, nil
+}
+
+// destroyNetNS destroys a network namespace created by createNetNS. The spec
+// may be nil if the namespace was created before the client restarted.
+func (h *networkHook) destroyNetNS(spec *drivers.NetworkIsolationSpec) error {
+	manager, err := h.networkSetter.NetworkManager()
+	if err != nil {
+		return err
+	}
+	if manager != nil {
+		return manager.DestroyNetwork(h.alloc.ID, spec)
+	}
+	return cni.RemoveNetNS(h.netNSPath())
+}
+
+func (h *networkHook) Prerun(ctx context.Context) error {
+	if !h.isolated() {
+		return nil
+	}
+
+	h.hookLock.Lock()
+	defer h.hookLock.Unlock()
+
+	network, err := h.network()
+	if err != nil {
+		return err
+	}
+
+	spec, created, err := h.createNetNS()
+	if err != nil {
+		return fmt.Errorf("failed to create network namespace: %v", err)
+	}
+
+	var addr string
+	if created {
+		result, err := network.Add(ctx, h.cniPath, h.runtimeConf(spec.Path))
+		if err != nil {
+			if derr := h.destroyNetNS(spec); derr != nil {
+				h.logger.Warn("failed to destroy network namespace", "path", spec.Path, "error", derr)
+			}
+			return fmt.Errorf("failed to add allocation to network %q: %v", network.Name, err)
+		}
+		h.result = result
+		addr = result.Address(networkInterfaceName)
+	} else {
+		// The namespace of a restored allocation is already in the network
+		addr, err = cni.InterfaceAddress(spec.Path, networkInterfaceName)
+		if err != nil {
+			return fmt.Errorf("failed to restore network of allocation: %v", err)
+		}
+	}
+	h.spec = spec
+
+	net := &cstructs.DriverNetwork{
+		IP:            addr,
+		AutoAdvertise: addr != "",
+	}
+	if h.groupNetworks().Bridge() != nil {
+		// Tasks in bridge mode are reached through the ports mapped on the
+		// host so the host address is advertised
+		_, net.PortMap = h.portMappings()
+		net.AutoAdvertise = false
+	}
+
+	h.logger.Debug("allocation added to network", "network", network.Name, "address", addr)
+	h.networkSetter.SetNetwork(spec, net, &structs.AllocNetworkStatus{
+		InterfaceName: networkInterfaceName,
+		Address:       addr,
+	})
+	return nil
+}
+
+func (h *networkHook) Postrun() error {
+	return h.teardown()
+}
+
+func (h *networkHook) Destroy() error {
+	return h.teardown()
+}
+
+// teardown removes the allocation from its network and destroys its network
+// namespace. It is a no-op if the namespace doesn't exist.
+func (h *networkHook) teardown() error {
+	if !h.isolated() {
+		return nil
+	}
+
+	h.hookLock.Lock()
+	defer h.hookLock.Unlock()
+
+	manager, err := h.networkSetter.NetworkManager()
+	if err != nil {
+		return err
+	}
+
+	spec := h.spec
+	if manager == nil {
+		path := h.netNSPath()
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			return nil
+		}
+		spec = &drivers.NetworkIsolationSpec{Path: path}
+	}
+
+	// Namespaces created by drivers are only known once restored, the
+	// driver is still asked to destroy them
+	var delErr error
+	if spec != nil {
+		if network, err := h.network(); err != nil {
+			delErr = err
+		} else if err := network.Del(context.Background(), h.cniPath, h.runtimeConf(spec.Path), h.result); err != nil {
+			delErr = fmt.Errorf("failed to remove allocation from network %q: %v", network.Name, err)
+		}
+	}
+
+	// The namespace is destroyed even if the plugins failed to release the
+	// network so it isn't leaked
+	if err := h.destroyNetNS(spec); err != nil {
+		return err
+	}
+	h.spec = nil
+	h.result = nil
+	return delErr
+}
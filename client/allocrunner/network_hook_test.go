@@ -0,0 +1,169 @@
+package allocrunner
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	"github.com/hashicorp/nomad/client/config"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/plugins/drivers"
+	"github.com/stretchr/testify/require"
+)
+
+// statically assert network hook implements the expected interfaces
+var _ interfaces.RunnerPrerunHook = (*networkHook)(nil)
+var _ interfaces.RunnerPostrunHook = (*networkHook)(nil)
+var _ interfaces.RunnerDestroyHook = (*networkHook)(nil)
+
+// mockNetworkSetter implements networkSetter by recording the network
+type mockNetworkSetter struct {
+	spec    *drivers.NetworkIsolationSpec
+	net     *cstructs.DriverNetwork
+	status  *structs.AllocNetworkStatus
+	manager drivers.DriverNetworkManager
+}
+
+func (m *mockNetworkSetter) SetNetwork(spec *drivers.NetworkIsolationSpec, net *cstructs.DriverNetwork, status *structs.AllocNetworkStatus) {
+	m.spec = spec
+	m.net = net
+	m.status = status
+}
+
+func (m *mockNetworkSetter) NetworkManager() (drivers.DriverNetworkManager, error) {
+	return m.manager, nil
+}
+
+// mockNetworkManager implements drivers.DriverNetworkManager by recording the
+// namespaces it creates and destroys
+type mockNetworkManager struct {
+	created   []string
+	destroyed []string
+}
+
+func (m *mockNetworkManager) CreateNetwork(allocID string) (*drivers.NetworkIsolationSpec, bool, error) {
+	m.created = append(m.created, allocID)
+	return &drivers.NetworkIsolationSpec{Path: "/proc/1/ns/net"}, true, nil
+}
+
+func (m *mockNetworkManager) DestroyNetwork(allocID string, spec *drivers.NetworkIsolationSpec) error {
+	m.destroyed = append(m.destroyed, allocID)
+	return nil
+}
+
+func testNetworkHookConfig(dir string) *config.Config {
+	cfg := config.DefaultConfig()
+	cfg.CNIPath = dir
+	cfg.CNIConfigDir = dir
+	return cfg
+}
+
+// TestNetworkHook_HostNetwork asserts the hook is a noop for allocations
+// without a network of their own.
+func TestNetworkHook_HostNetwork(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	alloc := mock.Alloc()
+	ns := &mockNetworkSetter{}
+	h := newNetworkHook(testlog.HCLogger(t), alloc, ns, testNetworkHookConfig("/missing"))
+
+	require.NoError(h.Prerun(context.Background()))
+	require.NoError(h.Postrun())
+	require.NoError(h.Destroy())
+	require.Nil(ns.spec)
+	require.Nil(ns.status)
+}
+
+// TestNetworkHook_MissingNetwork asserts the hook fails allocations whose CNI
+// network isn't configured on the client.
+func TestNetworkHook_MissingNetwork(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "nomadtest-cni")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	alloc := mock.Alloc()
+	alloc.Job.TaskGroups[0].Networks = structs.Networks{
+		{Mode: structs.NetworkModeCNIPrefix + "mynet"},
+	}
+	ns := &mockNetworkSetter{}
+	h := newNetworkHook(testlog.HCLogger(t), alloc, ns, testNetworkHookConfig(dir))
+
+	err = h.Prerun(context.Background())
+	require.Error(err)
+	require.Contains(err.Error(), `CNI network "mynet" is not configured`)
+	require.Nil(ns.spec)
+}
+
+// TestNetworkHook_Bridge asserts allocations in bridge mode are added to the
+// bridge network of the client in the namespace created by their driver and
+// their ports are mapped.
+func TestNetworkHook_Bridge(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test plugins are shell scripts")
+	}
+	t.Parallel()
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "nomadtest-cni")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	// The plugins record their configuration and print the same result
+	script := "#!/bin/sh\ncat > \"$(dirname $0)/$CNI_COMMAND-$(basename $0).json\"\n" +
+		"if [ \"$CNI_COMMAND\" = \"ADD\" ]; then\n" +
+		"  echo '{\"cniVersion\": \"0.4.0\", \"ips\": [{\"version\": \"4\", \"address\": \"172.26.64.2/20\"}]}'\nfi\n"
+	for _, plugin := range []string{"bridge", "portmap"} {
+		require.NoError(ioutil.WriteFile(filepath.Join(dir, plugin), []byte(script), 0755))
+	}
+
+	alloc := mock.Alloc()
+	alloc.Job.TaskGroups[0].Networks = structs.Networks{
+		{Mode: structs.NetworkModeBridge},
+	}
+	alloc.AllocatedResources.Shared.Networks = structs.Networks{
+		{
+			Mode: structs.NetworkModeBridge,
+			IP:   "192.168.0.100",
+			DynamicPorts: []structs.Port{
+				{Label: "http", Value: 25000, To: 8080},
+				{Label: "admin", Value: 25001, HostNetwork: "private", HostIP: "10.0.0.5"},
+			},
+		},
+	}
+	manager := &mockNetworkManager{}
+	ns := &mockNetworkSetter{manager: manager}
+	h := newNetworkHook(testlog.HCLogger(t), alloc, ns, testNetworkHookConfig(dir))
+
+	require.NoError(h.Prerun(context.Background()))
+	require.Equal([]string{alloc.ID}, manager.created)
+	require.Equal("/proc/1/ns/net", ns.spec.Path)
+	require.Equal("172.26.64.2", ns.status.Address)
+
+	// Tasks listen on the mapped ports and the host address is advertised
+	require.Equal(map[string]int{"http": 8080, "admin": 25001}, ns.net.PortMap)
+	require.False(ns.net.AutoAdvertise)
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, "ADD-portmap.json"))
+	require.NoError(err)
+	require.Contains(string(data), `{"hostPort":25000,"containerPort":8080,"protocol":"tcp"}`)
+	require.Contains(string(data), `{"hostPort":25000,"containerPort":8080,"protocol":"udp"}`)
+
+	// Ports bound to a host network are only mapped on its address
+	require.Contains(string(data), `{"hostPort":25001,"containerPort":25001,"protocol":"tcp","hostIP":"10.0.0.5"}`)
+
+	require.NoError(h.Postrun())
+	require.Equal([]string{alloc.ID}, manager.destroyed)
+	_, err = os.Stat(filepath.Join(dir, "DEL-bridge.json"))
+	require.NoError(err)
+}
@@ -18,6 +18,10 @@ type State struct {
 	// DeploymentStatus captures the status of the deployment
 	DeploymentStatus *structs.AllocDeploymentStatus
 
+	// NetworkStatus captures the network of the allocation when it has its
+	// own network namespace
+	NetworkStatus *structs.AllocNetworkStatus
+
 	// TaskStates is a snapshot of task states.
 	TaskStates map[string]*structs.TaskState
 }
@@ -56,6 +60,7 @@ func (s *State) Copy() *State {
 		ClientStatus:      s.ClientStatus,
 		ClientDescription: s.ClientDescription,
 		DeploymentStatus:  s.DeploymentStatus.Copy(),
+		NetworkStatus:     s.NetworkStatus.Copy(),
 		TaskStates:        taskStates,
 	}
 }
@@ -8,6 +8,7 @@ import (
 	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
 	"github.com/hashicorp/nomad/client/allocrunner/taskrunner/getter"
 	ti "github.com/hashicorp/nomad/client/allocrunner/taskrunner/interfaces"
+	"github.com/hashicorp/nomad/client/config"
 	"github.com/hashicorp/nomad/nomad/structs"
 )
 
@@ -15,11 +16,22 @@ import (
 type artifactHook struct {
 	eventEmitter ti.EventEmitter
 	logger       log.Logger
+
+	// getArtifact downloads an artifact, in a sandboxed process unless the
+	// sandbox is disabled by the client
+	getArtifact func(context.Context, getter.EnvReplacer, *structs.TaskArtifact, string) error
 }
 
-func newArtifactHook(e ti.EventEmitter, logger log.Logger) *artifactHook {
+func newArtifactHook(e ti.EventEmitter, conf *config.Config, logger log.Logger) *artifactHook {
 	h := &artifactHook{
 		eventEmitter: e,
+		getArtifact: func(_ context.Context, env getter.EnvReplacer, a *structs.TaskArtifact, dir string) error {
+			return getter.GetArtifact(env, a, dir)
+		},
+	}
+	if conf.ReadBoolDefault("artifact.sandbox", true) {
+		timeout := conf.ReadDurationDefault("artifact.sandbox_timeout", getter.DefaultSandboxTimeout)
+		h.getArtifact = getter.NewSandbox(conf.Read("artifact.sandbox_user"), timeout).GetArtifact
 	}
 	h.logger = logger.Named(h.Name())
 	return h
@@ -38,8 +50,7 @@ func (h *artifactHook) Prestart(ctx context.Context, req *interfaces.TaskPrestar
 	h.eventEmitter.EmitEvent(structs.NewTaskEvent(structs.TaskDownloadingArtifacts))
 
 	for _, artifact := range req.Task.Artifacts {
-		//XXX add ctx to GetArtifact to allow cancelling long downloads
-		if err := getter.GetArtifact(req.TaskEnv, artifact, req.TaskDir.Dir); err != nil {
+		if err := h.getArtifact(ctx, req.TaskEnv, artifact, req.TaskDir.Dir); err != nil {
 			wrapped := fmt.Errorf("failed to download artifact %q: %v", artifact.GetterSource, err)
 			h.logger.Debug(wrapped.Error())
 			h.eventEmitter.EmitEvent(structs.NewTaskEvent(structs.TaskArtifactDownloadFailed).SetDownloadError(wrapped))
@@ -0,0 +1,55 @@
+package taskrunner
+
+import (
+	"context"
+	"fmt"
+
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	"github.com/hashicorp/nomad/client/lib/cgutil"
+	"github.com/hashicorp/nomad/lib/cpuset"
+)
+
+// cpusetHook records the cores the scheduler dedicated to the task with the
+// cpuset manager of the client so that they are never dedicated to another
+// task, and releases them once the task will not run again.
+type cpusetHook struct {
+	logger log.Logger
+	cm     *cgutil.CpusetManager
+	taskID string
+}
+
+func newCpusetHook(cm *cgutil.CpusetManager, taskID string, logger log.Logger) *cpusetHook {
+	h := &cpusetHook{
+		cm:     cm,
+		taskID: taskID,
+	}
+	h.logger = logger.Named(h.Name())
+	return h
+}
+
+func (*cpusetHook) Name() string {
+	return "cpuset"
+}
+
+func (h *cpusetHook) Prestart(ctx context.Context, req *interfaces.TaskPrestartRequest, resp *interfaces.TaskPrestartResponse) error {
+	// The cores are reserved again every time the task is started or
+	// restored, so the hook is never marked as done.
+	if h.cm == nil || req.TaskResources == nil || len(req.TaskResources.Cpu.ReservedCores) == 0 {
+		return nil
+	}
+
+	cores := cpuset.New(req.TaskResources.Cpu.ReservedCores...)
+	if err := h.cm.Reserve(h.taskID, cores); err != nil {
+		return fmt.Errorf("failed to reserve cores %s: %v", cores, err)
+	}
+	h.logger.Trace("reserved cores", "cores", cores)
+	return nil
+}
+
+func (h *cpusetHook) Stop(ctx context.Context, req *interfaces.TaskStopRequest, resp *interfaces.TaskStopResponse) error {
+	if h.cm != nil {
+		h.cm.Release(h.taskID)
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package taskrunner
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	cstructs "github.com/hashicorp/nomad/client/structs"
@@ -61,6 +62,26 @@ func (h *DriverHandle) Exec(timeout time.Duration, cmd string, args []string) ([
 	return res.Stdout, res.ExitResult.ExitCode, res.ExitResult.Err
 }
 
+// ExecStreaming runs an interactive command within the task if the driver
+// supports streaming exec.
+func (h *DriverHandle) ExecStreaming(ctx context.Context, opts *drivers.ExecOptions) (*drivers.ExitResult, error) {
+	driver, ok := h.driver.(drivers.ExecTaskStreamingDriver)
+	if !ok {
+		return nil, fmt.Errorf("exec is not supported by the task driver")
+	}
+	return driver.ExecTaskStreaming(ctx, h.taskID, opts)
+}
+
+// Checkpoint checkpoints the task if the driver supports it, returning whether
+// a checkpoint was taken.
+func (h *DriverHandle) Checkpoint() (bool, error) {
+	driver, ok := h.driver.(drivers.CheckpointDriver)
+	if !ok {
+		return false, nil
+	}
+	return driver.CheckpointTask(h.taskID)
+}
+
 func (h *DriverHandle) Network() *cstructs.DriverNetwork {
 	return h.net
 }
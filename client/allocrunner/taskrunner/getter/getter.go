@@ -88,27 +88,58 @@ func getGetterUrl(taskEnv EnvReplacer, artifact *structs.TaskArtifact) (string,
 	return url, nil
 }
 
-// GetArtifact downloads an artifact into the specified task directory.
-func GetArtifact(taskEnv EnvReplacer, artifact *structs.TaskArtifact, taskDir string) error {
+// Request is a download of an artifact. It is passed to the sandboxed getter
+// process as JSON.
+type Request struct {
+	// Source is the go-getter URL of the artifact.
+	Source string
+
+	// Dest is the path the artifact is downloaded to.
+	Dest string
+
+	// Mode is the getter mode of the artifact.
+	Mode string
+}
+
+// newRequest returns the request to download an artifact into the specified
+// task directory.
+func newRequest(taskEnv EnvReplacer, artifact *structs.TaskArtifact, taskDir string) (*Request, error) {
 	url, err := getGetterUrl(taskEnv, artifact)
 	if err != nil {
-		return newGetError(artifact.GetterSource, err, false)
+		return nil, err
 	}
 
-	// Download the artifact
-	dest := filepath.Join(taskDir, artifact.RelativeDest)
+	return &Request{
+		Source: url,
+		Dest:   filepath.Join(taskDir, artifact.RelativeDest),
+		Mode:   artifact.GetterMode,
+	}, nil
+}
 
+// Fetch downloads the artifact of the request in the current process.
+func Fetch(req *Request) error {
 	// Convert from string getter mode to go-getter const
 	mode := gg.ClientModeAny
-	switch artifact.GetterMode {
+	switch req.Mode {
 	case structs.GetterModeFile:
 		mode = gg.ClientModeFile
 	case structs.GetterModeDir:
 		mode = gg.ClientModeDir
 	}
 
-	if err := getClient(url, mode, dest).Get(); err != nil {
-		return newGetError(url, err, true)
+	return getClient(req.Source, mode, req.Dest).Get()
+}
+
+// GetArtifact downloads an artifact into the specified task directory.
+func GetArtifact(taskEnv EnvReplacer, artifact *structs.TaskArtifact, taskDir string) error {
+	req, err := newRequest(taskEnv, artifact, taskDir)
+	if err != nil {
+		return newGetError(artifact.GetterSource, err, false)
+	}
+
+	// Download the artifact
+	if err := Fetch(req); err != nil {
+		return newGetError(req.Source, err, true)
 	}
 
 	return nil
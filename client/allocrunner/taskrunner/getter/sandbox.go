@@ -0,0 +1,298 @@
+package getter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/nomad/helper/discover"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// DefaultSandboxUser is the user sandboxed downloads run as when the
+	// client runs as root. It must be dedicated to the sandbox: tasks running
+	// as the same user could otherwise access downloads in progress.
+	DefaultSandboxUser = "nomad-artifact"
+
+	// DefaultSandboxTimeout is the time after which sandboxed downloads are
+	// killed.
+	DefaultSandboxTimeout = 30 * time.Minute
+
+	// sandboxStagingPrefix is the prefix of the staging directories created
+	// in the task directory.
+	sandboxStagingPrefix = ".nomad-artifact-"
+
+	// sandboxDownload is the name the sandboxed process downloads the
+	// artifact to inside its staging directory.
+	sandboxDownload = "artifact"
+
+	// maxSandboxOutput bounds the output of the sandboxed process included
+	// in download errors.
+	maxSandboxOutput = 4096
+)
+
+// sandboxEnv are the environment variables passed from the client to the
+// sandboxed process. The remaining environment of the client, which may hold
+// credentials, is not passed.
+var sandboxEnv = []string{
+	"PATH",
+	"HTTP_PROXY",
+	"HTTPS_PROXY",
+	"NO_PROXY",
+	"http_proxy",
+	"https_proxy",
+	"no_proxy",
+}
+
+// Sandbox downloads artifacts in a child process, isolating the client from
+// malicious artifact sources. When the client runs as root the process runs
+// as a dedicated unprivileged user. The process may only write to a private
+// staging directory in the task directory, whose contents are moved into
+// place once the download succeeds.
+type Sandbox struct {
+	// User is the user the process runs as if the client runs as root.
+	User string
+
+	// Timeout is the time after which the process is killed.
+	Timeout time.Duration
+}
+
+// NewSandbox returns a Sandbox running downloads as the given user, or as
+// DefaultSandboxUser if empty, and killing them after the given timeout, or
+// DefaultSandboxTimeout if zero.
+func NewSandbox(user string, timeout time.Duration) *Sandbox {
+	if user == "" {
+		user = DefaultSandboxUser
+	}
+	if timeout <= 0 {
+		timeout = DefaultSandboxTimeout
+	}
+	return &Sandbox{User: user, Timeout: timeout}
+}
+
+// GetArtifact downloads an artifact into the specified task directory. The
+// download is killed if the context is cancelled.
+func (s *Sandbox) GetArtifact(ctx context.Context, taskEnv EnvReplacer, artifact *structs.TaskArtifact, taskDir string) error {
+	req, err := newRequest(taskEnv, artifact, taskDir)
+	if err != nil {
+		return newGetError(artifact.GetterSource, err, false)
+	}
+
+	if err := s.fetch(ctx, req, taskDir); err != nil {
+		return newGetError(req.Source, err, true)
+	}
+	return nil
+}
+
+// fetch runs the sandboxed process downloading the artifact of the request
+// and moves the artifact to its destination.
+func (s *Sandbox) fetch(ctx context.Context, req *Request, taskDir string) error {
+	bin, err := discover.NomadExecutable()
+	if err != nil {
+		return fmt.Errorf("unable to find the nomad binary: %v", err)
+	}
+
+	cred, err := sandboxCredential(s.User)
+	if err != nil {
+		return err
+	}
+
+	// The staging directory is created in the task directory owned by the
+	// client, and is only reachable by its random name
+	dir, err := ioutil.TempDir(taskDir, sandboxStagingPrefix)
+	if err != nil {
+		return fmt.Errorf("failed to create staging directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	if err := os.Chmod(dir, 0711); err != nil {
+		return err
+	}
+
+	staging := filepath.Join(dir, "staging")
+	if err := os.Mkdir(staging, 0700); err != nil {
+		return fmt.Errorf("failed to create staging directory: %v", err)
+	}
+	if err := chownSandbox(staging, cred); err != nil {
+		return err
+	}
+
+	download := filepath.Join(staging, sandboxDownload)
+	input, err := json.Marshal(&Request{
+		Source: req.Source,
+		Dest:   download,
+		Mode:   req.Mode,
+	})
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.Timeout)
+	defer cancel()
+
+	var output bytes.Buffer
+	cmd := exec.CommandContext(ctx, bin, "artifact-getter")
+	cmd.Dir = staging
+	cmd.Env = sandboxEnviron(staging)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	setSandboxCredential(cmd, cred)
+
+	if err := cmd.Run(); err != nil {
+		if ctxErr := ctx.Err(); ctxErr == context.DeadlineExceeded {
+			return fmt.Errorf("artifact getter timed out after %v", s.Timeout)
+		} else if ctxErr != nil {
+			return fmt.Errorf("artifact getter cancelled: %v", ctxErr)
+		}
+		msg := strings.TrimSpace(output.String())
+		if len(msg) > maxSandboxOutput {
+			msg = msg[len(msg)-maxSandboxOutput:]
+		}
+		if msg == "" {
+			return fmt.Errorf("artifact getter failed: %v", err)
+		}
+		return fmt.Errorf("%s", msg)
+	}
+
+	return moveArtifact(download, req.Dest, cred != nil)
+}
+
+// sandboxEnviron returns the environment of the sandboxed process, whose home
+// and temporary directories are the staging directory.
+func sandboxEnviron(staging string) []string {
+	env := []string{
+		"HOME=" + staging,
+		"TMPDIR=" + staging,
+
+		// Never prompt for credentials and only allow the git transports
+		// go-getter supports, excluding the ext transport that runs
+		// arbitrary commands
+		"GIT_TERMINAL_PROMPT=0",
+		"GIT_ALLOW_PROTOCOL=http:https:ssh:git",
+	}
+	for _, k := range sandboxEnv {
+		if v, ok := os.LookupEnv(k); ok {
+			env = append(env, k+"="+v)
+		}
+	}
+	return env
+}
+
+// moveArtifact moves the downloaded file or directory at src to dst, merging
+// directories with the contents already at dst. If chown is set the moved
+// files are handed back to the client.
+func moveArtifact(src, dst string, chown bool) error {
+	fi, err := os.Lstat(src)
+	if err != nil {
+		return fmt.Errorf("failed to find downloaded artifact: %v", err)
+	}
+
+	// Directories are merged into existing ones entry by entry
+	if fi.IsDir() {
+		if dfi, err := os.Lstat(dst); err == nil && dfi.IsDir() {
+			entries, err := ioutil.ReadDir(src)
+			if err != nil {
+				return err
+			}
+			for _, e := range entries {
+				if err := moveArtifact(filepath.Join(src, e.Name()), filepath.Join(dst, e.Name()), chown); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}
+
+	if chown {
+		if err := chownTree(src); err != nil {
+			return err
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	if err := os.Rename(src, dst); err == nil {
+		return nil
+	}
+
+	// The staging directory may be on another filesystem than the task
+	// directory, in which case the artifact is copied
+	if err := copyTree(src, dst); err != nil {
+		return fmt.Errorf("failed to move artifact: %v", err)
+	}
+	return nil
+}
+
+// chownTree hands all files below path back to the client without following
+// symlinks.
+func chownTree(path string) error {
+	uid, gid := os.Getuid(), os.Getgid()
+	return filepath.Walk(path, func(p string, _ os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := os.Lchown(p, uid, gid); err != nil {
+			return fmt.Errorf("failed to change owner of %q: %v", p, err)
+		}
+		return nil
+	})
+}
+
+// copyTree copies the file, symlink or directory at src to dst, preserving
+// file modes and without following symlinks.
+func copyTree(src, dst string) error {
+	return filepath.Walk(src, func(p string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, p)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		switch {
+		case fi.IsDir():
+			return os.MkdirAll(target, fi.Mode().Perm())
+		case fi.Mode()&os.ModeSymlink != 0:
+			link, err := os.Readlink(p)
+			if err != nil {
+				return err
+			}
+			os.Remove(target)
+			return os.Symlink(link, target)
+		case fi.Mode().IsRegular():
+			return copyFile(p, target, fi.Mode().Perm())
+		default:
+			return fmt.Errorf("unsupported file type of %q", rel)
+		}
+	})
+}
+
+// copyFile copies the regular file at src to dst.
+func copyFile(src, dst string, mode os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,174 @@
+package getter
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/user"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/helper/discover"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+// testSandboxUser returns the user to sandbox the downloads of the tests as,
+// which is only looked up when the tests run as root.
+func testSandboxUser(t *testing.T) string {
+	if _, err := discover.NomadExecutable(); err != nil {
+		t.Skip("nomad binary not found")
+	}
+	if os.Geteuid() != 0 {
+		return ""
+	}
+	if _, err := user.Lookup("nobody"); err != nil {
+		t.Skip("sandbox user nobody not found")
+	}
+	return "nobody"
+}
+
+func TestSandbox_GetArtifact(t *testing.T) {
+	sandboxUser := testSandboxUser(t)
+	require := require.New(t)
+
+	// Create the test server hosting the file to download
+	ts := httptest.NewServer(http.FileServer(http.Dir(filepath.Dir("./test-fixtures/"))))
+	defer ts.Close()
+
+	taskDir, err := ioutil.TempDir("", "nomad-test")
+	require.NoError(err)
+	defer os.RemoveAll(taskDir)
+	require.NoError(os.Chmod(taskDir, 0711))
+
+	artifact := &structs.TaskArtifact{
+		GetterSource: fmt.Sprintf("%s/%s", ts.URL, "test.sh"),
+		GetterOptions: map[string]string{
+			"checksum": "md5:bce963762aa2dbfed13caf492a45fb72",
+		},
+		RelativeDest: "local/",
+	}
+
+	s := NewSandbox(sandboxUser, 0)
+	require.NoError(s.GetArtifact(context.Background(), taskEnv, artifact, taskDir))
+
+	fi, err := os.Stat(filepath.Join(taskDir, "local", "test.sh"))
+	require.NoError(err)
+	require.True(fi.Mode().IsRegular())
+
+	// Download failures are reported with the output of the sandbox
+	artifact.GetterOptions["checksum"] = "md5:00000000000000000000000000000000"
+	err = s.GetArtifact(context.Background(), taskEnv, artifact, taskDir)
+	require.Error(err)
+	require.Contains(err.Error(), "Checksums did not match")
+	require.True(err.(*GetError).IsRecoverable())
+
+	// The staging directories are removed from the task directory
+	staging, err := filepath.Glob(filepath.Join(taskDir, sandboxStagingPrefix+"*"))
+	require.NoError(err)
+	require.Empty(staging)
+}
+
+func TestSandbox_GetArtifact_Timeout(t *testing.T) {
+	sandboxUser := testSandboxUser(t)
+	require := require.New(t)
+
+	// The test server never answers
+	done := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-done
+	}))
+	defer ts.Close()
+	defer close(done)
+
+	taskDir, err := ioutil.TempDir("", "nomad-test")
+	require.NoError(err)
+	defer os.RemoveAll(taskDir)
+	require.NoError(os.Chmod(taskDir, 0711))
+
+	artifact := &structs.TaskArtifact{
+		GetterSource: fmt.Sprintf("%s/%s", ts.URL, "test.sh"),
+		RelativeDest: "local/",
+	}
+
+	s := NewSandbox(sandboxUser, 500*time.Millisecond)
+	err = s.GetArtifact(context.Background(), taskEnv, artifact, taskDir)
+	require.Error(err)
+	require.Contains(err.Error(), "timed out")
+}
+
+func TestSandbox_Environ(t *testing.T) {
+	os.Setenv("NOMAD_TEST_SANDBOX_SECRET", "secret")
+	defer os.Unsetenv("NOMAD_TEST_SANDBOX_SECRET")
+
+	env := sandboxEnviron("/tmp/staging")
+	require.Contains(t, env, "HOME=/tmp/staging")
+	require.Contains(t, env, "GIT_ALLOW_PROTOCOL=http:https:ssh:git")
+	for _, e := range env {
+		require.NotContains(t, e, "NOMAD_TEST_SANDBOX_SECRET")
+	}
+}
+
+func TestMoveArtifact(t *testing.T) {
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "nomad-test")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	// An existing destination directory is merged with the download
+	src := filepath.Join(dir, "src")
+	dst := filepath.Join(dir, "dst")
+	require.NoError(os.MkdirAll(filepath.Join(src, "sub"), 0755))
+	require.NoError(os.MkdirAll(filepath.Join(dst, "sub"), 0755))
+	require.NoError(ioutil.WriteFile(filepath.Join(src, "sub", "new"), []byte("new"), 0644))
+	require.NoError(ioutil.WriteFile(filepath.Join(dst, "sub", "old"), []byte("old"), 0644))
+	require.NoError(os.Symlink("new", filepath.Join(src, "link")))
+
+	require.NoError(moveArtifact(src, dst, false))
+
+	raw, err := ioutil.ReadFile(filepath.Join(dst, "sub", "new"))
+	require.NoError(err)
+	require.Equal("new", string(raw))
+	_, err = os.Stat(filepath.Join(dst, "sub", "old"))
+	require.NoError(err)
+	link, err := os.Readlink(filepath.Join(dst, "link"))
+	require.NoError(err)
+	require.Equal("new", link)
+
+	// Files are moved to their destination path
+	file := filepath.Join(dir, "file")
+	require.NoError(ioutil.WriteFile(file, []byte("file"), 0600))
+	require.NoError(moveArtifact(file, filepath.Join(dir, "a", "b"), false))
+	raw, err = ioutil.ReadFile(filepath.Join(dir, "a", "b"))
+	require.NoError(err)
+	require.Equal("file", string(raw))
+}
+
+func TestCopyTree(t *testing.T) {
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "nomad-test")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	src := filepath.Join(dir, "src")
+	require.NoError(os.MkdirAll(filepath.Join(src, "sub"), 0755))
+	require.NoError(ioutil.WriteFile(filepath.Join(src, "sub", "run.sh"), []byte("#!/bin/sh"), 0755))
+	require.NoError(os.Symlink("/etc/passwd", filepath.Join(src, "link")))
+
+	dst := filepath.Join(dir, "dst")
+	require.NoError(copyTree(src, dst))
+
+	fi, err := os.Stat(filepath.Join(dst, "sub", "run.sh"))
+	require.NoError(err)
+	require.Equal(os.FileMode(0755), fi.Mode().Perm())
+
+	fi, err = os.Lstat(filepath.Join(dst, "link"))
+	require.NoError(err)
+	require.True(fi.Mode()&os.ModeSymlink != 0)
+}
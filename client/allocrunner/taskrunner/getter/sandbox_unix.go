@@ -0,0 +1,61 @@
+// +build !windows
+
+package getter
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"os/user"
+	"strconv"
+	"syscall"
+)
+
+// sandboxCredential returns the credential of the given user if the client
+// runs as root, and nil otherwise as the sandboxed process can then not run
+// with less privileges than the client.
+func sandboxCredential(username string) (*syscall.Credential, error) {
+	if os.Geteuid() != 0 {
+		return nil, nil
+	}
+
+	u, err := user.Lookup(username)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up sandbox user %q: %v", username, err)
+	}
+	uid, err := strconv.ParseUint(u.Uid, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid uid of sandbox user %q: %v", username, err)
+	}
+	gid, err := strconv.ParseUint(u.Gid, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid gid of sandbox user %q: %v", username, err)
+	}
+	if uid == 0 || gid == 0 {
+		return nil, fmt.Errorf("sandbox user %q must not be root", username)
+	}
+
+	return &syscall.Credential{
+		Uid: uint32(uid),
+		Gid: uint32(gid),
+	}, nil
+}
+
+// chownSandbox hands the staging directory to the sandbox user.
+func chownSandbox(path string, cred *syscall.Credential) error {
+	if cred == nil {
+		return nil
+	}
+	if err := os.Chown(path, int(cred.Uid), int(cred.Gid)); err != nil {
+		return fmt.Errorf("failed to change owner of staging directory: %v", err)
+	}
+	return nil
+}
+
+// setSandboxCredential runs the command as the sandbox user.
+func setSandboxCredential(cmd *exec.Cmd, cred *syscall.Credential) {
+	if cred == nil {
+		return
+	}
+	cmd.SysProcAttr = &syscall.SysProcAttr{Credential: cred}
+}
@@ -0,0 +1,19 @@
+package getter
+
+import (
+	"os/exec"
+)
+
+// sandboxCredential returns nil as the sandboxed process runs as the client
+// user on Windows.
+func sandboxCredential(string) (interface{}, error) {
+	return nil, nil
+}
+
+// chownSandbox is a noop on Windows.
+func chownSandbox(string, interface{}) error {
+	return nil
+}
+
+// setSandboxCredential is a noop on Windows.
+func setSandboxCredential(*exec.Cmd, interface{}) {}
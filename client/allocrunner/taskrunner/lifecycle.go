@@ -83,10 +83,24 @@ func (tr *TaskRunner) Kill(ctx context.Context, event *structs.TaskEvent) error
 	// Run the hooks prior to killing the task
 	tr.killing()
 
+	// Checkpoint the task if it is being migrated so it can be restored on
+	// the node it is migrated to.
+	if tr.Alloc().DesiredTransition.ShouldMigrate() {
+		tr.checkpointTask(handle)
+	}
+
 	// Tell the restart tracker that the task has been killed so it doesn't
 	// attempt to restart it.
 	tr.restartTracker.SetKilled()
 
+	// Leave remote tasks recovered by the replacement of a lost allocation
+	// running and only stop supervising them.
+	if tr.shouldDetach() {
+		tr.detach()
+		tr.UpdateState(structs.TaskStateDead, structs.NewTaskEvent(structs.TaskDetached))
+		return nil
+	}
+
 	// Kill the task using an exponential backoff in-case of failures.
 	killErr := tr.killTask(handle)
 	if killErr != nil {
@@ -123,3 +137,17 @@ func (tr *TaskRunner) Kill(ctx context.Context, event *structs.TaskEvent) error
 
 	return nil
 }
+
+// ExecStreaming runs an interactive command within the task and blocks until
+// the command exits or ctx is cancelled.
+func (tr *TaskRunner) ExecStreaming(ctx context.Context, opts *drivers.ExecOptions) (*drivers.ExitResult, error) {
+	// Grab the handle
+	handle := tr.getDriverHandle()
+
+	// Check it is running
+	if handle == nil {
+		return nil, ErrTaskNotRunning
+	}
+
+	return handle.ExecStreaming(ctx, opts)
+}
@@ -0,0 +1,242 @@
+package taskrunner
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	cinterfaces "github.com/hashicorp/nomad/client/interfaces"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/client/taskenv"
+	agentconsul "github.com/hashicorp/nomad/command/agent/consul"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// nomadServicesHookConfig is the configuration of the nomad services hook
+type nomadServicesHookConfig struct {
+	alloc  *structs.Allocation
+	task   *structs.Task
+	node   *structs.Node
+	region string
+	rpc    cinterfaces.RPCer
+	logger hclog.Logger
+}
+
+// nomadServicesHook registers the services of the task using the nomad
+// provider with the servers while the task runs.
+type nomadServicesHook struct {
+	config   *nomadServicesHookConfig
+	allocID  string
+	taskName string
+	logger   hclog.Logger
+
+	// The following fields may be updated
+	driverNet *cstructs.DriverNetwork
+	canary    bool
+	services  []*structs.Service
+	networks  structs.Networks
+	taskEnv   *taskenv.TaskEnv
+
+	// registered is whether the services are registered
+	registered bool
+
+	// Since Update() may be called concurrently with any other hook all
+	// hook methods must be fully serialized
+	mu sync.Mutex
+}
+
+func newNomadServicesHook(config *nomadServicesHookConfig) *nomadServicesHook {
+	h := &nomadServicesHook{
+		config:   config,
+		allocID:  config.alloc.ID,
+		taskName: config.task.Name,
+		services: filterServices(config.task.Services, structs.ServiceProviderNomad),
+	}
+
+	h.networks = taskNetworks(config.alloc, config.task.Name)
+
+	if config.alloc.DeploymentStatus != nil && config.alloc.DeploymentStatus.Canary {
+		h.canary = true
+	}
+
+	h.logger = config.logger.Named(h.Name())
+	return h
+}
+
+func (*nomadServicesHook) Name() string {
+	return "nomad_services"
+}
+
+func (h *nomadServicesHook) Poststart(ctx context.Context, req *interfaces.TaskPoststartRequest, _ *interfaces.TaskPoststartResponse) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// Store the TaskEnv for interpolating now and when Updating
+	h.driverNet = req.DriverNetwork
+	h.taskEnv = req.TaskEnv
+
+	return h.register()
+}
+
+func (h *nomadServicesHook) Update(ctx context.Context, req *interfaces.TaskUpdateRequest, _ *interfaces.TaskUpdateResponse) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	task := req.Alloc.LookupTask(h.taskName)
+	if task == nil {
+		return fmt.Errorf("task %q not found in updated alloc", h.taskName)
+	}
+
+	networks := taskNetworks(req.Alloc, h.taskName)
+
+	// Update hook fields
+	h.canary = req.Alloc.DeploymentStatus != nil && req.Alloc.DeploymentStatus.Canary
+	h.taskEnv = req.TaskEnv
+	h.services = filterServices(task.Services, structs.ServiceProviderNomad)
+	h.networks = networks
+
+	// Services are only registered once the task started
+	if !h.registered {
+		return nil
+	}
+
+	// Replace the registrations, as the services may have been renamed
+	h.deregister()
+	return h.register()
+}
+
+func (h *nomadServicesHook) Killing(ctx context.Context, req *interfaces.TaskKillRequest, resp *interfaces.TaskKillResponse) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// Deregister before killing task
+	h.deregister()
+	return nil
+}
+
+func (h *nomadServicesHook) Exited(context.Context, *interfaces.TaskExitedRequest, *interfaces.TaskExitedResponse) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.deregister()
+	return nil
+}
+
+// register registers the services of the task with the servers.
+func (h *nomadServicesHook) register() error {
+	if len(h.services) == 0 {
+		return nil
+	}
+
+	regs, err := h.registrations()
+	if err != nil {
+		return err
+	}
+
+	args := structs.ServiceRegistrationUpsertRequest{
+		NodeID:   h.config.node.ID,
+		SecretID: h.config.node.SecretID,
+		Services: regs,
+		WriteRequest: structs.WriteRequest{
+			Region: h.config.region,
+		},
+	}
+	var reply structs.GenericResponse
+	if err := h.config.rpc.RPC("ServiceRegistration.Upsert", &args, &reply); err != nil {
+		return fmt.Errorf("failed to register services: %v", err)
+	}
+
+	h.registered = true
+	return nil
+}
+
+// deregister deletes the registrations of the services of the task.
+func (h *nomadServicesHook) deregister() {
+	if !h.registered {
+		return
+	}
+
+	args := structs.ServiceRegistrationDeleteRequest{
+		NodeID:   h.config.node.ID,
+		SecretID: h.config.node.SecretID,
+		AllocID:  h.allocID,
+		Task:     h.taskName,
+		WriteRequest: structs.WriteRequest{
+			Region: h.config.region,
+		},
+	}
+	var reply structs.GenericResponse
+	if err := h.config.rpc.RPC("ServiceRegistration.Delete", &args, &reply); err != nil {
+		// The servers delete the registrations of the allocation once
+		// it's terminal
+		h.logger.Warn("failed to deregister services", "error", err)
+		return
+	}
+	h.registered = false
+}
+
+// registrations returns the registrations of the interpolated services of
+// the task.
+func (h *nomadServicesHook) registrations() ([]*structs.ServiceRegistration, error) {
+	services := interpolateServices(h.taskEnv, h.services)
+	regs := make([]*structs.ServiceRegistration, len(services))
+	for i, service := range services {
+		addrMode := service.AddressMode
+		if addrMode == "" {
+			addrMode = structs.AddressModeAuto
+		}
+		ip, port, err := agentconsul.GetAddress(addrMode, service.PortLabel, h.networks, h.driverNet)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get address for service %q: %v", service.Name, err)
+		}
+		if ip == "" {
+			ip = h.config.node.Attributes["unique.network.ip-address"]
+		}
+
+		tags := service.Tags
+		if h.canary && len(service.CanaryTags) > 0 {
+			tags = service.CanaryTags
+		}
+
+		regs[i] = &structs.ServiceRegistration{
+			ID:          structs.ServiceRegistrationID(h.allocID, h.taskName, service),
+			ServiceName: service.Name,
+			AllocID:     h.allocID,
+			Task:        h.taskName,
+			Tags:        tags,
+			Address:     ip,
+			Port:        port,
+		}
+	}
+	return regs, nil
+}
+
+// filterServices returns the services registered with the provider, services
+// without provider being registered with Consul.
+func filterServices(services []*structs.Service, provider string) []*structs.Service {
+	var filtered []*structs.Service
+	for _, service := range services {
+		p := service.Provider
+		if p == "" {
+			p = structs.ServiceProviderConsul
+		}
+		if p == provider {
+			filtered = append(filtered, service)
+		}
+	}
+	return filtered
+}
+
+// taskNetworks returns the networks of a task, including the networks shared
+// by the tasks of its group.
+func taskNetworks(alloc *structs.Allocation, taskName string) structs.Networks {
+	var networks structs.Networks
+	if res := alloc.TaskResources[taskName]; res != nil {
+		networks = append(networks, res.Networks...)
+	}
+	if alloc.AllocatedResources != nil {
+		networks = append(networks, alloc.AllocatedResources.Shared.Networks...)
+	}
+	return networks
+}
@@ -0,0 +1,157 @@
+package taskrunner
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	"github.com/hashicorp/nomad/client/taskenv"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+// Statically assert the nomad services hook implements the expected interfaces
+var _ interfaces.TaskPoststartHook = (*nomadServicesHook)(nil)
+var _ interfaces.TaskUpdateHook = (*nomadServicesHook)(nil)
+var _ interfaces.TaskKillHook = (*nomadServicesHook)(nil)
+var _ interfaces.TaskExitedHook = (*nomadServicesHook)(nil)
+
+// mockServiceRegistrationRPC records the ServiceRegistration calls
+type mockServiceRegistrationRPC struct {
+	upserts []*structs.ServiceRegistrationUpsertRequest
+	deletes []*structs.ServiceRegistrationDeleteRequest
+}
+
+func (m *mockServiceRegistrationRPC) RPC(method string, args interface{}, reply interface{}) error {
+	switch method {
+	case "ServiceRegistration.Upsert":
+		m.upserts = append(m.upserts, args.(*structs.ServiceRegistrationUpsertRequest))
+	case "ServiceRegistration.Delete":
+		m.deletes = append(m.deletes, args.(*structs.ServiceRegistrationDeleteRequest))
+	default:
+		return fmt.Errorf("unexpected method %q", method)
+	}
+	return nil
+}
+
+// TestTaskRunner_NomadServicesHook asserts the services using the nomad
+// provider are registered once the task started and deregistered once it
+// exited.
+func TestTaskRunner_NomadServicesHook(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	node := mock.Node()
+	alloc := mock.Alloc()
+	alloc.NodeID = node.ID
+	task := alloc.Job.TaskGroups[0].Tasks[0]
+	task.Services = []*structs.Service{
+		{
+			Name:      "${NOMAD_TASK_NAME}-frontend",
+			PortLabel: "http",
+			Tags:      []string{"primary"},
+			Provider:  structs.ServiceProviderNomad,
+		},
+		{
+			Name:      "${NOMAD_TASK_NAME}-admin",
+			PortLabel: "admin",
+		},
+	}
+
+	rpc := &mockServiceRegistrationRPC{}
+	h := newNomadServicesHook(&nomadServicesHookConfig{
+		alloc:  alloc,
+		task:   task,
+		node:   node,
+		region: "global",
+		rpc:    rpc,
+		logger: testlog.HCLogger(t),
+	})
+
+	// Only the frontend service is registered
+	env := taskenv.NewBuilder(node, alloc, task, "global").Build()
+	req := interfaces.TaskPoststartRequest{TaskEnv: env}
+	require.NoError(h.Poststart(context.Background(), &req, nil))
+	require.Len(rpc.upserts, 1)
+	upsert := rpc.upserts[0]
+	require.Equal(node.ID, upsert.NodeID)
+	require.Equal(node.SecretID, upsert.SecretID)
+	require.Len(upsert.Services, 1)
+	service := upsert.Services[0]
+	require.Equal("web-frontend", service.ServiceName)
+	require.Equal(alloc.ID, service.AllocID)
+	require.Equal(task.Name, service.Task)
+	require.Equal([]string{"primary"}, service.Tags)
+	require.Equal("192.168.0.100", service.Address)
+	require.Equal(9876, service.Port)
+
+	// The registrations are deleted once the task exited, and only once
+	require.NoError(h.Exited(context.Background(), nil, nil))
+	require.NoError(h.Exited(context.Background(), nil, nil))
+	require.Len(rpc.deletes, 1)
+	require.Equal(alloc.ID, rpc.deletes[0].AllocID)
+	require.Equal(task.Name, rpc.deletes[0].Task)
+}
+
+// TestTaskRunner_NomadServicesHook_Update asserts the services are
+// registered again when the task is updated after it started.
+func TestTaskRunner_NomadServicesHook_Update(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	node := mock.Node()
+	alloc := mock.Alloc()
+	task := alloc.Job.TaskGroups[0].Tasks[0]
+	task.Services = []*structs.Service{{
+		Name:      "frontend",
+		PortLabel: "http",
+		Provider:  structs.ServiceProviderNomad,
+	}}
+
+	rpc := &mockServiceRegistrationRPC{}
+	h := newNomadServicesHook(&nomadServicesHookConfig{
+		alloc:  alloc,
+		task:   task,
+		node:   node,
+		region: "global",
+		rpc:    rpc,
+		logger: testlog.HCLogger(t),
+	})
+	env := taskenv.NewBuilder(node, alloc, task, "global").Build()
+
+	// Updates before the task started don't register the services
+	updated := alloc.Copy()
+	updated.Job.TaskGroups[0].Tasks[0].Services[0].Name = "backend"
+	update := interfaces.TaskUpdateRequest{Alloc: updated, TaskEnv: env}
+	require.NoError(h.Update(context.Background(), &update, nil))
+	require.Empty(rpc.upserts)
+
+	require.NoError(h.Poststart(context.Background(), &interfaces.TaskPoststartRequest{TaskEnv: env}, nil))
+	require.Len(rpc.upserts, 1)
+	require.Equal("backend", rpc.upserts[0].Services[0].ServiceName)
+
+	updated = updated.Copy()
+	updated.Job.TaskGroups[0].Tasks[0].Services[0].Name = "api"
+	update.Alloc = updated
+	require.NoError(h.Update(context.Background(), &update, nil))
+	require.Len(rpc.deletes, 1)
+	require.Len(rpc.upserts, 2)
+	require.Equal("api", rpc.upserts[1].Services[0].ServiceName)
+}
+
+func TestTaskRunner_FilterServices(t *testing.T) {
+	t.Parallel()
+
+	services := []*structs.Service{
+		{Name: "default"},
+		{Name: "consul", Provider: structs.ServiceProviderConsul},
+		{Name: "nomad", Provider: structs.ServiceProviderNomad},
+	}
+	consul := filterServices(services, structs.ServiceProviderConsul)
+	require.Equal(t, []*structs.Service{services[0], services[1]}, consul)
+	nomad := filterServices(services, structs.ServiceProviderNomad)
+	require.Equal(t, []*structs.Service{services[2]}, nomad)
+}
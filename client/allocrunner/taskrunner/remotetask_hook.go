@@ -0,0 +1,78 @@
+package taskrunner
+
+import (
+	"context"
+
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/plugins/drivers"
+)
+
+// remoteTaskHook recovers tasks running on a remote task driver from the task
+// handle propagated to the replacement of a lost allocation, so the task is
+// supervised again instead of being started a second time.
+type remoteTaskHook struct {
+	tr *TaskRunner
+
+	logger hclog.Logger
+}
+
+func newRemoteTaskHook(tr *TaskRunner, logger hclog.Logger) *remoteTaskHook {
+	h := &remoteTaskHook{
+		tr: tr,
+	}
+	h.logger = logger.Named(h.Name())
+	return h
+}
+
+func (*remoteTaskHook) Name() string {
+	return "remote_task"
+}
+
+func (h *remoteTaskHook) Prestart(ctx context.Context, req *interfaces.TaskPrestartRequest, resp *interfaces.TaskPrestartResponse) error {
+	// The task was restored locally so there is nothing to recover
+	if h.tr.getDriverHandle() != nil {
+		return nil
+	}
+
+	h.tr.stateLock.RLock()
+	th := drivers.NewTaskHandleFromState(h.tr.state)
+	h.tr.stateLock.RUnlock()
+
+	// No handle was propagated from a previous allocation
+	if th == nil {
+		return nil
+	}
+
+	// The task config is unique per allocation so build it for this one
+	th.Config = h.tr.buildTaskConfig()
+
+	if err := h.tr.driver.RecoverTask(th); err != nil {
+		// Starting a new task is preferred over failing this one as retrying
+		// the recovery is unlikely to help.
+		h.logger.Error("error recovering remote task; starting a new task", "error", err)
+		h.tr.setStateHandle(nil)
+		return nil
+	}
+
+	var net *cstructs.DriverNetwork
+	if status, err := h.tr.driver.InspectTask(th.Config.ID); err != nil {
+		h.logger.Warn("error inspecting recovered remote task", "error", err)
+	} else {
+		net = status.NetworkOverride
+	}
+
+	h.tr.stateLock.Lock()
+	h.tr.localState.TaskHandle = th
+	h.tr.localState.DriverNetwork = net
+	h.tr.stateLock.Unlock()
+
+	if err := h.tr.persistLocalState(); err != nil {
+		h.logger.Warn("error persisting local task state; may be unable to restore after a Nomad restart", "error", err)
+	}
+
+	h.tr.setDriverHandle(NewDriverHandle(h.tr.driver, th.Config.ID, h.tr.Task(), net))
+	h.logger.Info("recovered remote task", "task_id", th.Config.ID)
+	return nil
+}
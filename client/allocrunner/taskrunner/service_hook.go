@@ -53,14 +53,12 @@ func newServiceHook(c serviceHookConfig) *serviceHook {
 		consul:    c.consul,
 		allocID:   c.alloc.ID,
 		taskName:  c.task.Name,
-		services:  c.task.Services,
+		services:  filterServices(c.task.Services, structs.ServiceProviderConsul),
 		restarter: c.restarter,
 		delay:     c.task.ShutdownDelay,
 	}
 
-	if res := c.alloc.TaskResources[c.task.Name]; res != nil {
-		h.networks = res.Networks
-	}
+	h.networks = taskNetworks(c.alloc, c.task.Name)
 
 	if c.alloc.DeploymentStatus != nil && c.alloc.DeploymentStatus.Canary {
 		h.canary = true
@@ -103,10 +101,7 @@ func (h *serviceHook) Update(ctx context.Context, req *interfaces.TaskUpdateRequ
 		canary = req.Alloc.DeploymentStatus.Canary
 	}
 
-	var networks structs.Networks
-	if res := req.Alloc.TaskResources[h.taskName]; res != nil {
-		networks = res.Networks
-	}
+	networks := taskNetworks(req.Alloc, h.taskName)
 
 	task := req.Alloc.LookupTask(h.taskName)
 	if task == nil {
@@ -116,7 +111,7 @@ func (h *serviceHook) Update(ctx context.Context, req *interfaces.TaskUpdateRequ
 	// Update service hook fields
 	h.delay = task.ShutdownDelay
 	h.taskEnv = req.TaskEnv
-	h.services = task.Services
+	h.services = filterServices(task.Services, structs.ServiceProviderConsul)
 	h.networks = networks
 	h.canary = canary
 
@@ -0,0 +1,395 @@
+package taskrunner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	cinterfaces "github.com/hashicorp/nomad/client/interfaces"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// TaskAPISocket is the name of the unix socket of the task API in the
+	// secrets directory of the task.
+	TaskAPISocket = "api.sock"
+)
+
+// driverDefaultUsers maps the drivers that run tasks as an unprivileged user
+// when the task doesn't set one to that user.
+var driverDefaultUsers = map[string]string{
+	"exec": "nobody",
+	"java": "nobody",
+}
+
+// taskAPIHookConfig is the configuration of the task API hook
+type taskAPIHookConfig struct {
+	alloc  *structs.Allocation
+	task   string
+	node   *structs.Node
+	region string
+	logDir string
+	rpc    cinterfaces.RPCer
+	logger hclog.Logger
+}
+
+// taskAPIHook serves the task API on a unix socket in the secrets directory of
+// the task. The API is restricted to the identity of the task, the services of
+// its namespace, its variables and the logs of the tasks of its allocation, so
+// that tasks don't need network access to the HTTP API of the client.
+type taskAPIHook struct {
+	config *taskAPIHookConfig
+	logger hclog.Logger
+
+	// srv is the server of the socket, set while it is running
+	srv *http.Server
+	mu  sync.Mutex
+}
+
+func newTaskAPIHook(config *taskAPIHookConfig) *taskAPIHook {
+	h := &taskAPIHook{
+		config: config,
+	}
+	h.logger = config.logger.Named(h.Name())
+	return h
+}
+
+func (*taskAPIHook) Name() string {
+	return "task_api"
+}
+
+func (h *taskAPIHook) Prestart(ctx context.Context, req *interfaces.TaskPrestartRequest, resp *interfaces.TaskPrestartResponse) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// The server keeps running across the restarts of the task
+	if h.srv != nil {
+		return nil
+	}
+
+	// Remove the socket left by a previous agent, as the hook is run again
+	// when the task is restored
+	path := filepath.Join(req.TaskDir.SecretsDir, TaskAPISocket)
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		h.logger.Error("failed to remove stale socket", "path", path, "error", err)
+		return nil
+	}
+
+	// The task API is optional for tasks, so failing to serve it only
+	// logs an error
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		h.logger.Error("failed to listen on socket", "path", path, "error", err)
+		return nil
+	}
+
+	// Only the user of the task may connect, as the API is not
+	// authenticated
+	if err := setSocketOwner(path, taskUser(req.Task)); err != nil {
+		ln.Close()
+		h.logger.Error("failed to set socket permissions", "path", path, "error", err)
+		return nil
+	}
+
+	h.srv = &http.Server{Handler: h.handler()}
+	go func(srv *http.Server) {
+		if err := srv.Serve(ln); err != nil && err != http.ErrServerClosed {
+			h.logger.Error("failed to serve task API", "error", err)
+		}
+	}(h.srv)
+	return nil
+}
+
+// taskUser returns the user the driver runs the task as, or an empty string if
+// it runs the task as the client user or the default user of its image.
+func taskUser(task *structs.Task) string {
+	if task.User != "" {
+		return task.User
+	}
+	return driverDefaultUsers[task.Driver]
+}
+
+func (h *taskAPIHook) Stop(ctx context.Context, req *interfaces.TaskStopRequest, resp *interfaces.TaskStopResponse) error {
+	h.close()
+	return nil
+}
+
+// Shutdown closes the socket when the agent shuts down. It is served again
+// once the task is restored.
+func (h *taskAPIHook) Shutdown() {
+	h.close()
+}
+
+// close stops serving the task API, if it's running.
+func (h *taskAPIHook) close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.srv == nil {
+		return
+	}
+	if err := h.srv.Close(); err != nil {
+		h.logger.Warn("failed to close task API", "error", err)
+	}
+	h.srv = nil
+}
+
+// handler returns the handler of the task API.
+func (h *taskAPIHook) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/identity", h.wrap(h.identity))
+	mux.HandleFunc("/v1/variables", h.wrap(h.variables))
+	mux.HandleFunc("/v1/service/", h.wrap(h.service))
+	mux.HandleFunc("/v1/logs/", h.logs)
+	return mux
+}
+
+// taskAPIError is an error of the task API with its status code.
+type taskAPIError struct {
+	code int
+	err  error
+}
+
+func (e *taskAPIError) Error() string {
+	return e.err.Error()
+}
+
+// wrap returns the handler encoding the object returned by the endpoint as
+// JSON, or writing its error.
+func (h *taskAPIHook) wrap(endpoint func(*http.Request) (interface{}, error)) http.HandlerFunc {
+	return func(resp http.ResponseWriter, req *http.Request) {
+		if req.Method != "GET" {
+			http.Error(resp, "Invalid method", http.StatusMethodNotAllowed)
+			return
+		}
+
+		obj, err := endpoint(req)
+		if err != nil {
+			code := http.StatusInternalServerError
+			switch e := err.(type) {
+			case *taskAPIError:
+				code = e.code
+			default:
+				if structs.IsErrPermissionDenied(err) {
+					code = http.StatusForbidden
+				}
+			}
+			http.Error(resp, err.Error(), code)
+			return
+		}
+
+		resp.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(resp).Encode(obj); err != nil {
+			h.logger.Warn("failed to encode response", "path", req.URL.Path, "error", err)
+		}
+	}
+}
+
+// TaskIdentity is the identity of the task returned by the task API.
+type TaskIdentity struct {
+	AllocID    string
+	AllocName  string
+	JobID      string
+	Namespace  string
+	TaskGroup  string
+	Task       string
+	NodeID     string
+	NodeName   string
+	Datacenter string
+	Region     string
+}
+
+func (h *taskAPIHook) identity(*http.Request) (interface{}, error) {
+	alloc := h.config.alloc
+	node := h.config.node
+	return &TaskIdentity{
+		AllocID:    alloc.ID,
+		AllocName:  alloc.Name,
+		JobID:      alloc.JobID,
+		Namespace:  alloc.Namespace,
+		TaskGroup:  alloc.TaskGroup,
+		Task:       h.config.task,
+		NodeID:     node.ID,
+		NodeName:   node.Name,
+		Datacenter: node.Datacenter,
+		Region:     h.config.region,
+	}, nil
+}
+
+func (h *taskAPIHook) variables(*http.Request) (interface{}, error) {
+	args := structs.VariablesTaskReadRequest{
+		NodeID:   h.config.node.ID,
+		SecretID: h.config.node.SecretID,
+		AllocID:  h.config.alloc.ID,
+		Task:     h.config.task,
+		QueryOptions: structs.QueryOptions{
+			Region:     h.config.region,
+			AllowStale: true,
+		},
+	}
+	var reply structs.VariablesTaskReadResponse
+	if err := h.config.rpc.RPC("Variables.TaskRead", &args, &reply); err != nil {
+		return nil, err
+	}
+	return reply.Items, nil
+}
+
+func (h *taskAPIHook) service(req *http.Request) (interface{}, error) {
+	name := strings.TrimPrefix(req.URL.Path, "/v1/service/")
+	if name == "" {
+		return nil, &taskAPIError{http.StatusBadRequest, fmt.Errorf("missing service name")}
+	}
+
+	args := structs.ServiceRegistrationByNameRequest{
+		ServiceName: name,
+		JobID:       req.URL.Query().Get("job"),
+		NodeID:      h.config.node.ID,
+		SecretID:    h.config.node.SecretID,
+		AllocID:     h.config.alloc.ID,
+		QueryOptions: structs.QueryOptions{
+			Region:     h.config.region,
+			AllowStale: true,
+		},
+	}
+	var reply structs.ServiceRegistrationByNameResponse
+	if err := h.config.rpc.RPC("ServiceRegistration.GetService", &args, &reply); err != nil {
+		return nil, err
+	}
+	if reply.Services == nil {
+		reply.Services = make([]*structs.ServiceRegistration, 0)
+	}
+	return reply.Services, nil
+}
+
+// logs writes the current log file of a task of the allocation, starting at
+// the offset.
+func (h *taskAPIHook) logs(resp http.ResponseWriter, req *http.Request) {
+	if req.Method != "GET" {
+		http.Error(resp, "Invalid method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Only the tasks of the group of the allocation have logs, which also
+	// keeps the path of the log files within the log directory
+	task := strings.TrimPrefix(req.URL.Path, "/v1/logs/")
+	if h.config.alloc.LookupTask(task) == nil {
+		http.Error(resp, fmt.Sprintf("unknown task name %q", task), http.StatusNotFound)
+		return
+	}
+
+	query := req.URL.Query()
+	logType := query.Get("type")
+	if logType == "" {
+		logType = "stdout"
+	}
+	if logType != "stdout" && logType != "stderr" {
+		http.Error(resp, `log type must be "stdout" or "stderr"`, http.StatusBadRequest)
+		return
+	}
+
+	var offset int64
+	if raw := query.Get("offset"); raw != "" {
+		var err error
+		if offset, err = strconv.ParseInt(raw, 10, 64); err != nil || offset < 0 {
+			http.Error(resp, fmt.Sprintf("invalid offset %q", raw), http.StatusBadRequest)
+			return
+		}
+	}
+
+	path, err := currentLogFile(h.config.logDir, task, logType)
+	if err != nil {
+		http.Error(resp, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if path == "" {
+		http.Error(resp, fmt.Sprintf("no %s logs for task %q", logType, task), http.StatusNotFound)
+		return
+	}
+
+	f, err := openLogFile(h.config.logDir, path)
+	if err != nil {
+		http.Error(resp, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer f.Close()
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		http.Error(resp, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	resp.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	resp.Header().Set("X-Nomad-Log-File", filepath.Base(path))
+	io.Copy(resp, f)
+}
+
+// currentLogFile returns the path of the log file of the task with the highest
+// index, the one being written to, or an empty path if there is none.
+func currentLogFile(logDir, task, logType string) (string, error) {
+	prefix := fmt.Sprintf("%s.%s.", task, logType)
+	matches, err := filepath.Glob(filepath.Join(logDir, prefix+"*"))
+	if err != nil {
+		return "", err
+	}
+
+	path, current := "", -1
+	for _, m := range matches {
+		idx, err := strconv.Atoi(strings.TrimPrefix(filepath.Base(m), prefix))
+		if err != nil {
+			continue
+		}
+
+		// The log directory is writable by tasks, so skip anything they
+		// may have planted in place of a log file, such as symlinks
+		if fi, err := os.Lstat(m); err != nil || !fi.Mode().IsRegular() {
+			continue
+		}
+
+		if idx > current {
+			path, current = m, idx
+		}
+	}
+	return path, nil
+}
+
+// openLogFile opens the log file at path, which must be a regular file
+// directly within the log directory. As tasks may write to the log directory,
+// the opened file is checked to be the file that was found there, and not the
+// target of a symlink swapped in after the file was looked up.
+func openLogFile(logDir, path string) (*os.File, error) {
+	if filepath.Dir(filepath.Clean(path)) != filepath.Clean(logDir) {
+		return nil, fmt.Errorf("log file %q is not within the log directory", path)
+	}
+
+	before, err := os.Lstat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !before.Mode().IsRegular() {
+		return nil, fmt.Errorf("log file %q is not a regular file", path)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	after, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	if !os.SameFile(before, after) {
+		f.Close()
+		return nil, fmt.Errorf("log file %q changed while being opened", path)
+	}
+	return f, nil
+}
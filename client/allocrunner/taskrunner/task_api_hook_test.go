@@ -0,0 +1,197 @@
+package taskrunner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/hashicorp/nomad/client/allocdir"
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+// Statically assert the task API hook implements the expected interfaces
+var _ interfaces.TaskPrestartHook = (*taskAPIHook)(nil)
+var _ interfaces.TaskStopHook = (*taskAPIHook)(nil)
+var _ interfaces.ShutdownHook = (*taskAPIHook)(nil)
+
+// mockTaskAPIRPC answers the calls of the task API with fixed replies
+type mockTaskAPIRPC struct {
+	items    map[string]string
+	services []*structs.ServiceRegistration
+	args     *structs.ServiceRegistrationByNameRequest
+}
+
+func (m *mockTaskAPIRPC) RPC(method string, args interface{}, reply interface{}) error {
+	switch method {
+	case "Variables.TaskRead":
+		reply.(*structs.VariablesTaskReadResponse).Items = m.items
+	case "ServiceRegistration.GetService":
+		m.args = args.(*structs.ServiceRegistrationByNameRequest)
+		if m.args.ServiceName == "secret" {
+			return structs.ErrPermissionDenied
+		}
+		reply.(*structs.ServiceRegistrationByNameResponse).Services = m.services
+	default:
+		return fmt.Errorf("unexpected method %q", method)
+	}
+	return nil
+}
+
+// unixClient returns an HTTP client connecting to the unix socket.
+func unixClient(path string) *http.Client {
+	return &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", path)
+			},
+		},
+	}
+}
+
+// TestTaskRunner_TaskAPIHook asserts the task API is served on the socket in
+// the secrets directory of the task.
+func TestTaskRunner_TaskAPIHook(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "taskapi")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+	logDir := filepath.Join(dir, "logs")
+	require.NoError(os.Mkdir(logDir, 0755))
+
+	node := mock.Node()
+	alloc := mock.Alloc()
+	alloc.NodeID = node.ID
+	task := alloc.Job.TaskGroups[0].Tasks[0]
+	rpc := &mockTaskAPIRPC{
+		items: map[string]string{"password": "hunter2"},
+		services: []*structs.ServiceRegistration{
+			{ServiceName: "db", Address: "10.0.0.1", Port: 5432},
+		},
+	}
+	h := newTaskAPIHook(&taskAPIHookConfig{
+		alloc:  alloc,
+		task:   task.Name,
+		node:   node,
+		region: "global",
+		logDir: logDir,
+		rpc:    rpc,
+		logger: testlog.HCLogger(t),
+	})
+
+	// A stale socket is replaced
+	path := filepath.Join(dir, TaskAPISocket)
+	require.NoError(ioutil.WriteFile(path, nil, 0600))
+
+	req := &interfaces.TaskPrestartRequest{
+		Task:    task,
+		TaskDir: &allocdir.TaskDir{SecretsDir: dir, LogDir: logDir},
+	}
+	resp := &interfaces.TaskPrestartResponse{}
+	require.NoError(h.Prestart(context.Background(), req, resp))
+	require.False(resp.Done)
+	defer h.Shutdown()
+
+	fi, err := os.Stat(path)
+	require.NoError(err)
+	require.Equal(os.ModeSocket, fi.Mode()&os.ModeSocket)
+	if runtime.GOOS != "windows" {
+		require.Equal(os.FileMode(0600), fi.Mode().Perm())
+	}
+
+	client := unixClient(path)
+	get := func(path string) (int, []byte) {
+		resp, err := client.Get("http://task" + path)
+		require.NoError(err)
+		defer resp.Body.Close()
+		body, err := ioutil.ReadAll(resp.Body)
+		require.NoError(err)
+		return resp.StatusCode, body
+	}
+
+	// Identity
+	code, body := get("/v1/identity")
+	require.Equal(200, code)
+	var identity TaskIdentity
+	require.NoError(json.Unmarshal(body, &identity))
+	require.Equal(alloc.ID, identity.AllocID)
+	require.Equal(alloc.Namespace, identity.Namespace)
+	require.Equal(task.Name, identity.Task)
+	require.Equal(node.ID, identity.NodeID)
+	require.Equal("global", identity.Region)
+
+	// Variables
+	code, body = get("/v1/variables")
+	require.Equal(200, code)
+	var items map[string]string
+	require.NoError(json.Unmarshal(body, &items))
+	require.Equal("hunter2", items["password"])
+
+	// Services are read with the node secret
+	code, body = get("/v1/service/db")
+	require.Equal(200, code)
+	var services []*structs.ServiceRegistration
+	require.NoError(json.Unmarshal(body, &services))
+	require.Len(services, 1)
+	require.Equal(5432, services[0].Port)
+	require.Equal(node.SecretID, rpc.args.SecretID)
+	require.Equal(alloc.ID, rpc.args.AllocID)
+
+	code, _ = get("/v1/service/secret")
+	require.Equal(403, code)
+
+	// Logs of the current log file
+	stdout := func(idx int) string {
+		return filepath.Join(logDir, fmt.Sprintf("%s.stdout.%d", task.Name, idx))
+	}
+	code, _ = get("/v1/logs/" + task.Name)
+	require.Equal(404, code)
+	require.NoError(ioutil.WriteFile(stdout(0), []byte("old"), 0644))
+	require.NoError(ioutil.WriteFile(stdout(1), []byte("hello world"), 0644))
+	code, body = get("/v1/logs/" + task.Name + "?offset=6")
+	require.Equal(200, code)
+	require.Equal("world", string(body))
+
+	// Symlinks planted in the log directory are not followed
+	if runtime.GOOS != "windows" {
+		secret := filepath.Join(dir, "secret")
+		require.NoError(ioutil.WriteFile(secret, []byte("host secret"), 0600))
+		require.NoError(os.Symlink(secret, stdout(99999)))
+		code, body = get("/v1/logs/" + task.Name)
+		require.Equal(200, code)
+		require.Equal("hello world", string(body))
+
+		_, err = openLogFile(logDir, stdout(99999))
+		require.Error(err)
+	}
+
+	// Logs of other tasks and invalid requests are rejected
+	code, _ = get("/v1/logs/../../etc/passwd")
+	require.Equal(404, code)
+	code, _ = get("/v1/logs/" + task.Name + "?type=foo")
+	require.Equal(400, code)
+
+	r, err := client.Post("http://task/v1/identity", "application/json", nil)
+	require.NoError(err)
+	r.Body.Close()
+	require.Equal(405, r.StatusCode)
+
+	// Restarts of the task keep the socket, which is closed on stop
+	require.NoError(h.Prestart(context.Background(), req, resp))
+	require.NoError(h.Stop(context.Background(), nil, nil))
+	_, err = unixClient(path).Get("http://task/v1/identity")
+	require.Error(err)
+}
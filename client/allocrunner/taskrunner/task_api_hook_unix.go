@@ -0,0 +1,60 @@
+// +build !windows
+
+package taskrunner
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"strconv"
+	"strings"
+)
+
+// setSocketOwner restricts the socket of the task API to the user the task
+// runs as, or to the client user if the username is empty.
+func setSocketOwner(path, username string) error {
+	if err := os.Chmod(path, 0600); err != nil {
+		return err
+	}
+
+	// Only root can hand the socket to another user
+	if username == "" || os.Geteuid() != 0 {
+		return nil
+	}
+
+	uid, gid, err := lookupTaskUser(username)
+	if err != nil {
+		return err
+	}
+	return os.Chown(path, uid, gid)
+}
+
+// lookupTaskUser returns the uid and gid of the named user, or of the uid or
+// uid:gid pair given in place of a name for users only defined in the images
+// of containers.
+func lookupTaskUser(username string) (int, int, error) {
+	if u, err := user.Lookup(username); err == nil {
+		uid, err := strconv.Atoi(u.Uid)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid uid of user %q: %v", username, err)
+		}
+		gid, err := strconv.Atoi(u.Gid)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid gid of user %q: %v", username, err)
+		}
+		return uid, gid, nil
+	}
+
+	parts := strings.SplitN(username, ":", 2)
+	uid, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, 0, fmt.Errorf("unknown user %q", username)
+	}
+	gid := uid
+	if len(parts) == 2 {
+		if gid, err = strconv.Atoi(parts[1]); err != nil {
+			return 0, 0, fmt.Errorf("unknown group of user %q", username)
+		}
+	}
+	return uid, gid, nil
+}
@@ -0,0 +1,89 @@
+// +build !windows
+
+package taskrunner
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"testing"
+
+	"github.com/hashicorp/nomad/client/allocdir"
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTaskRunner_TaskAPIHook_DefaultUser asserts the socket of a task that
+// doesn't set a user is owned by the default user of its driver.
+func TestTaskRunner_TaskAPIHook_DefaultUser(t *testing.T) {
+	t.Parallel()
+	if syscall.Geteuid() != 0 {
+		t.Skip("Must be root to change the owner of the socket")
+	}
+	require := require.New(t)
+
+	nobody, err := user.Lookup("nobody")
+	if err != nil {
+		t.Skip("No nobody user")
+	}
+	uid, err := strconv.Atoi(nobody.Uid)
+	require.NoError(err)
+
+	dir, err := ioutil.TempDir("", "taskapi")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	alloc := mock.Alloc()
+	task := alloc.Job.TaskGroups[0].Tasks[0]
+	task.Driver = "exec"
+	task.User = ""
+	h := newTaskAPIHook(&taskAPIHookConfig{
+		alloc:  alloc,
+		task:   task.Name,
+		node:   mock.Node(),
+		region: "global",
+		logDir: dir,
+		rpc:    &mockTaskAPIRPC{},
+		logger: testlog.HCLogger(t),
+	})
+
+	req := &interfaces.TaskPrestartRequest{
+		Task:    task,
+		TaskDir: &allocdir.TaskDir{SecretsDir: dir, LogDir: dir},
+	}
+	resp := &interfaces.TaskPrestartResponse{}
+	require.NoError(h.Prestart(context.Background(), req, resp))
+	defer h.Shutdown()
+
+	fi, err := os.Stat(filepath.Join(dir, TaskAPISocket))
+	require.NoError(err)
+	require.Equal(os.FileMode(0600), fi.Mode().Perm())
+	require.Equal(uint32(uid), fi.Sys().(*syscall.Stat_t).Uid)
+}
+
+// TestTaskRunner_TaskAPIHook_TaskUser asserts the socket is handed to the user
+// the driver runs the task as.
+func TestTaskRunner_TaskAPIHook_TaskUser(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	task := mock.Job().TaskGroups[0].Tasks[0]
+	task.Driver = "exec"
+	task.User = ""
+	require.Equal("nobody", taskUser(task))
+
+	task.User = "alice"
+	require.Equal("alice", taskUser(task))
+
+	// Drivers running tasks as the client user or the user of their image
+	// leave the socket to the client user
+	task.Driver = "raw_exec"
+	task.User = ""
+	require.Empty(taskUser(task))
+}
@@ -0,0 +1,6 @@
+package taskrunner
+
+// setSocketOwner is a noop on Windows.
+func setSocketOwner(path, username string) error {
+	return nil
+}
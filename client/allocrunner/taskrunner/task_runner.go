@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"runtime"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -21,10 +23,12 @@ import (
 	"github.com/hashicorp/nomad/client/consul"
 	"github.com/hashicorp/nomad/client/devicemanager"
 	cinterfaces "github.com/hashicorp/nomad/client/interfaces"
+	"github.com/hashicorp/nomad/client/lib/cgutil"
 	cstate "github.com/hashicorp/nomad/client/state"
 	cstructs "github.com/hashicorp/nomad/client/structs"
 	"github.com/hashicorp/nomad/client/taskenv"
 	"github.com/hashicorp/nomad/client/vaultclient"
+	"github.com/hashicorp/nomad/lib/cpuset"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/plugins/base"
 	"github.com/hashicorp/nomad/plugins/drivers"
@@ -110,12 +114,26 @@ type TaskRunner struct {
 	// state
 	waitCh chan struct{}
 
+	// detachCh is closed when a remote task is detached to stop waiting on
+	// it without killing it
+	detachCh   chan struct{}
+	detachOnce sync.Once
+
 	// driver is the driver for the task.
 	driver drivers.DriverPlugin
 
 	// driverCapabilities is the set capabilities the driver supports
 	driverCapabilities *drivers.Capabilities
 
+	// networkIsolation is the network namespace of the allocation the task
+	// must run in and allocNetwork is the network of the allocation. Both
+	// are set by the alloc runner before the task is run and are nil when
+	// the allocation has no network of its own. Must acquire networkLock to
+	// access.
+	networkIsolation *drivers.NetworkIsolationSpec
+	allocNetwork     *cstructs.DriverNetwork
+	networkLock      sync.Mutex
+
 	// taskSchema is the hcl spec for the task driver configuration
 	taskSchema hcldec.Spec
 
@@ -152,6 +170,9 @@ type TaskRunner struct {
 	// vaultClient is the client to use to derive and renew Vault tokens
 	vaultClient vaultclient.VaultClient
 
+	// rpcClient is used to make RPC calls to the servers
+	rpcClient cinterfaces.RPCer
+
 	// vaultToken is the current Vault token. It should be accessed with the
 	// getter.
 	vaultToken     string
@@ -180,6 +201,9 @@ type TaskRunner struct {
 	// devicemanager is used to mount devices as well as lookup device
 	// statistics
 	devicemanager devicemanager.Manager
+
+	// cpusetManager tracks the cores dedicated to tasks
+	cpusetManager *cgutil.CpusetManager
 }
 
 type Config struct {
@@ -193,6 +217,10 @@ type Config struct {
 	// Vault is the client to use to derive and renew Vault tokens
 	Vault vaultclient.VaultClient
 
+	// RPCClient is used to make RPC calls to the servers, such as reading
+	// the variables of the task
+	RPCClient cinterfaces.RPCer
+
 	// StateDB is used to store and restore state.
 	StateDB cstate.StateDB
 
@@ -209,6 +237,9 @@ type Config struct {
 	// DeviceManager is used to mount devices as well as lookup device
 	// statistics
 	DeviceManager devicemanager.Manager
+
+	// CpusetManager tracks the cores dedicated to tasks
+	CpusetManager *cgutil.CpusetManager
 }
 
 func NewTaskRunner(config *Config) (*TaskRunner, error) {
@@ -243,6 +274,7 @@ func NewTaskRunner(config *Config) (*TaskRunner, error) {
 		envBuilder:            envBuilder,
 		consulClient:          config.Consul,
 		vaultClient:           config.Vault,
+		rpcClient:             config.RPCClient,
 		state:                 tstate,
 		localState:            state.NewLocalState(),
 		stateDB:               config.StateDB,
@@ -254,8 +286,10 @@ func NewTaskRunner(config *Config) (*TaskRunner, error) {
 		ctxCancel:             trCancel,
 		triggerUpdateCh:       make(chan struct{}, triggerUpdateChCap),
 		waitCh:                make(chan struct{}),
+		detachCh:              make(chan struct{}),
 		pluginSingletonLoader: config.PluginSingletonLoader,
 		devicemanager:         config.DeviceManager,
+		cpusetManager:         config.CpusetManager,
 	}
 
 	// Create the logger based on the allocation ID
@@ -402,6 +436,9 @@ MAIN:
 					if result != nil {
 						tr.handleTaskExitResult(result)
 					}
+				case <-tr.detachCh:
+					// The remote task was detached and is left running
+					result = nil
 				case <-tr.ctx.Done():
 					// TaskRunner was told to exit immediately
 					return
@@ -411,6 +448,9 @@ MAIN:
 
 		// Clear the handle
 		tr.clearDriverHandle()
+		if tr.remoteTask() {
+			tr.setStateHandle(nil)
+		}
 
 		// Store the wait result on the restart tracker
 		tr.restartTracker.SetExitResult(result)
@@ -440,12 +480,7 @@ MAIN:
 	// If task terminated, update server. All other exit conditions (eg
 	// killed or out of restarts) will perform their own server updates.
 	if result != nil {
-		event := structs.NewTaskEvent(structs.TaskTerminated).
-			SetExitCode(result.ExitCode).
-			SetSignal(result.Signal).
-			SetOOMKilled(result.OOMKilled).
-			SetExitMessage(result.Err)
-		tr.UpdateState(structs.TaskStateDead, event)
+		tr.UpdateState(structs.TaskStateDead, exitResultEvent(result))
 	}
 
 	// Run the stop hooks
@@ -457,17 +492,26 @@ MAIN:
 }
 
 func (tr *TaskRunner) handleTaskExitResult(result *drivers.ExitResult) {
-	event := structs.NewTaskEvent(structs.TaskTerminated).
+	tr.EmitEvent(exitResultEvent(result))
+
+	if result.OOMKilled && !tr.clientConfig.DisableTaggedMetrics {
+		metrics.IncrCounterWithLabels([]string{"client", "allocs", "oom_killed"}, 1, tr.baseLabels)
+	}
+}
+
+// exitResultEvent returns the task event for a task that exited with the
+// given result. Tasks killed by the OOM killer get a distinct event type.
+func exitResultEvent(result *drivers.ExitResult) *structs.TaskEvent {
+	eventType := structs.TaskTerminated
+	if result.OOMKilled {
+		eventType = structs.TaskOOMKilled
+	}
+
+	return structs.NewTaskEvent(eventType).
 		SetExitCode(result.ExitCode).
 		SetSignal(result.Signal).
 		SetOOMKilled(result.OOMKilled).
 		SetExitMessage(result.Err)
-
-	tr.EmitEvent(event)
-
-	if !tr.clientConfig.DisableTaggedMetrics {
-		metrics.IncrCounterWithLabels([]string{"client", "allocs", "oom_killed"}, 1, tr.baseLabels)
-	}
 }
 
 // handleUpdates runs update hooks when triggerUpdateCh is ticked and exits
@@ -555,6 +599,10 @@ func (tr *TaskRunner) runDriver() error {
 
 	//XXX Evaluate and encode driver config
 
+	if taskConfig.NetworkIsolation != nil && !tr.driverCapabilities.NetworkIsolation {
+		return fmt.Errorf("driver %q does not support network isolation", tr.Task().Driver)
+	}
+
 	// If there's already a task handle (eg from a Restore) there's nothing
 	// to do except update state.
 	if tr.getDriverHandle() != nil {
@@ -574,6 +622,12 @@ func (tr *TaskRunner) runDriver() error {
 		return fmt.Errorf("driver start failed: %v", err)
 	}
 
+	// Tasks in the network of their allocation are reachable at its address
+	// unless the driver created a network of its own
+	if net == nil {
+		net = tr.getAllocNetwork()
+	}
+
 	tr.stateLock.Lock()
 	tr.localState.TaskHandle = handle
 	tr.localState.DriverNetwork = net
@@ -588,6 +642,12 @@ func (tr *TaskRunner) runDriver() error {
 
 	tr.setDriverHandle(NewDriverHandle(tr.driver, taskConfig.ID, tr.Task(), net))
 
+	// Report the handle of remote tasks to the servers so the replacement of
+	// a lost allocation can recover the task
+	if tr.remoteTask() {
+		tr.setStateHandle(handle.StateHandle())
+	}
+
 	// Emit an event that we started
 	tr.UpdateState(structs.TaskStateRunning, structs.NewTaskEvent(structs.TaskStarted))
 	return nil
@@ -658,6 +718,53 @@ func (tr *TaskRunner) initDriver() error {
 	return nil
 }
 
+// remoteTask returns true if the task runs on a remote task driver.
+func (tr *TaskRunner) remoteTask() bool {
+	return tr.driverCapabilities != nil && tr.driverCapabilities.RemoteTasks
+}
+
+// shouldDetach returns true if the task runs on a remote task driver and its
+// allocation was lost and replaced. The replacement recovers the task from
+// its handle, so it must be left running when this allocation is stopped.
+func (tr *TaskRunner) shouldDetach() bool {
+	if !tr.remoteTask() {
+		return false
+	}
+
+	alloc := tr.Alloc()
+	return alloc.ClientStatus == structs.AllocClientStatusLost && alloc.NextAllocation != ""
+}
+
+// detach stops supervising a remote task without killing it. The handle is
+// removed from the local state so the task isn't recovered after a restart.
+func (tr *TaskRunner) detach() {
+	tr.detachOnce.Do(func() {
+		tr.stateLock.Lock()
+		tr.localState.TaskHandle = nil
+		tr.localState.DriverNetwork = nil
+		tr.stateLock.Unlock()
+
+		if err := tr.persistLocalState(); err != nil {
+			tr.logger.Warn("error persisting local task state", "error", err)
+		}
+
+		tr.logger.Info("detached remote task")
+		close(tr.detachCh)
+	})
+}
+
+// setStateHandle sets the handle of a remote task in the task state and
+// persists it. It is sent to the servers with the next state update.
+func (tr *TaskRunner) setStateHandle(handle *structs.TaskHandle) {
+	tr.stateLock.Lock()
+	defer tr.stateLock.Unlock()
+
+	tr.state.TaskHandle = handle
+	if err := tr.stateDB.PutTaskState(tr.allocID, tr.taskName, tr.state); err != nil {
+		tr.logger.Warn("error persisting task state", "error", err)
+	}
+}
+
 // killTask kills the task handle. In the case that killing fails,
 // killTask will retry with an exponential backoff and will give up at a
 // given limit. Returns an error if the task could not be killed.
@@ -686,6 +793,46 @@ func (tr *TaskRunner) killTask(handle *DriverHandle) error {
 	return err
 }
 
+// checkpointTask checkpoints the task if it enables checkpointing and its
+// driver supports it. Failures are reported as task events but do not prevent
+// the task from being killed.
+func (tr *TaskRunner) checkpointTask(handle *DriverHandle) {
+	if !tr.Task().Checkpoint {
+		return
+	}
+
+	if tr.driverCapabilities == nil || !tr.driverCapabilities.Checkpoint {
+		tr.logger.Warn("not checkpointing task as its driver does not support it")
+		tr.EmitEvent(structs.NewTaskEvent(structs.TaskCheckpointFailed).
+			SetMessage("Task driver does not support checkpointing"))
+		return
+	}
+
+	// The checkpoint is stored in the task's local directory, so it only
+	// reaches the node the task is migrated to if the ephemeral disk does.
+	alloc := tr.Alloc()
+	tg := alloc.Job.LookupTaskGroup(alloc.TaskGroup)
+	if tg == nil || tg.EphemeralDisk == nil || !tg.EphemeralDisk.Sticky || !tg.EphemeralDisk.Migrate {
+		tr.logger.Warn("not checkpointing task as its ephemeral disk does not migrate")
+		tr.EmitEvent(structs.NewTaskEvent(structs.TaskCheckpointFailed).
+			SetMessage("Ephemeral disk must be sticky with migrate enabled to checkpoint task"))
+		return
+	}
+
+	checkpointed, err := handle.Checkpoint()
+	if err != nil {
+		tr.logger.Error("failed to checkpoint task", "error", err)
+		tr.EmitEvent(structs.NewTaskEvent(structs.TaskCheckpointFailed).
+			SetMessage(fmt.Sprintf("Failed to checkpoint task: %v", err)))
+		return
+	}
+
+	if checkpointed {
+		tr.logger.Info("checkpointed task")
+		tr.EmitEvent(structs.NewTaskEvent(structs.TaskCheckpointed))
+	}
+}
+
 // persistLocalState persists local state to disk synchronously.
 func (tr *TaskRunner) persistLocalState() error {
 	tr.stateLock.RLock()
@@ -700,17 +847,43 @@ func (tr *TaskRunner) buildTaskConfig() *drivers.TaskConfig {
 	task := tr.Task()
 	alloc := tr.Alloc()
 
+	// Tasks with dedicated cores get the CPU shares of their cores
+	cpuShares := int64(task.Resources.CPU)
+	if tr.taskResources != nil && len(tr.taskResources.Cpu.ReservedCores) != 0 {
+		cpuShares = tr.taskResources.Cpu.CpuShares
+	}
+
+	linuxResources := &drivers.LinuxResources{
+		MemoryLimitBytes: int64(task.Resources.MemoryMB) * 1024 * 1024,
+		CPUShares:        cpuShares,
+		PercentTicks:     float64(cpuShares) / float64(tr.clientConfig.Node.NodeResources.Cpu.CpuShares),
+		IOWeight:         int64(task.Resources.IOWeight),
+	}
+	for _, l := range task.Resources.IOLimits {
+		linuxResources.IOLimits = append(linuxResources.IOLimits, &drivers.IOLimit{
+			Device:    l.Device,
+			ReadBps:   l.ReadBps,
+			WriteBps:  l.WriteBps,
+			ReadIOps:  l.ReadIOps,
+			WriteIOps: l.WriteIOps,
+		})
+	}
+	if task.Resources.CPUHardLimit {
+		linuxResources.CPUPeriod, linuxResources.CPUQuota = cpuBandwidth(linuxResources.PercentTicks, task.Resources.CPUCFSPeriod)
+	}
+	numaNode := tr.numaNode()
+	if numaNode != nil {
+		linuxResources.CpusetMems = strconv.Itoa(numaNode.ID)
+	}
+	linuxResources.CpusetCPUs = tr.cpusetCPUs(numaNode)
+
 	return &drivers.TaskConfig{
 		ID:      fmt.Sprintf("%s/%s/%d", alloc.ID, task.Name, tr.restartTracker.GetCount()),
 		Name:    task.Name,
 		JobName: alloc.Job.Name,
 		Resources: &drivers.Resources{
 			NomadResources: task.Resources,
-			LinuxResources: &drivers.LinuxResources{
-				MemoryLimitBytes: int64(task.Resources.MemoryMB) * 1024 * 1024,
-				CPUShares:        int64(task.Resources.CPU),
-				PercentTicks:     float64(task.Resources.CPU) / float64(tr.clientConfig.Node.NodeResources.Cpu.CpuShares),
-			},
+			LinuxResources: linuxResources,
 		},
 		Devices:    tr.hookResources.getDevices(),
 		Mounts:     tr.hookResources.getMounts(),
@@ -720,9 +893,87 @@ func (tr *TaskRunner) buildTaskConfig() *drivers.TaskConfig {
 		StdoutPath: tr.logmonHookConfig.stdoutFifo,
 		StderrPath: tr.logmonHookConfig.stderrFifo,
 		AllocID:    tr.allocID,
+
+		NetworkIsolation: tr.getNetworkIsolation(),
 	}
 }
 
+// cpuBandwidth returns the CFS period and quota in microseconds capping a task
+// at the given share of the client's CPU. cfs_quota_us is the time per core,
+// so the share is multiplied by the number of cores.
+func cpuBandwidth(percentTicks float64, period int) (int64, int64) {
+	if period == 0 {
+		period = structs.DefaultCPUCFSPeriod
+	}
+
+	quota := int64(percentTicks*float64(period)) * int64(runtime.NumCPU())
+
+	// The kernel rejects quotas below 1ms
+	if quota < structs.MinCPUCFSPeriod {
+		quota = structs.MinCPUCFSPeriod
+	}
+	return int64(period), quota
+}
+
+// cpusetCPUs returns the cores the task is restricted to in cpuset list
+// format or an empty string if the task may run on any core. Tasks with
+// dedicated cores run on them, all other tasks run on the shared cores of
+// the client, further restricted to the cores of their NUMA node if bound to
+// one.
+func (tr *TaskRunner) cpusetCPUs(numaNode *structs.NodeNumaNode) string {
+	if tr.taskResources != nil && len(tr.taskResources.Cpu.ReservedCores) != 0 {
+		return cpuset.New(tr.taskResources.Cpu.ReservedCores...).String()
+	}
+
+	if tr.cpusetManager == nil || !tr.cpusetManager.Restricted() {
+		if numaNode != nil {
+			return numaNode.Cores
+		}
+		return ""
+	}
+
+	shared := tr.cpusetManager.Shared()
+	if numaNode == nil {
+		return shared.String()
+	}
+
+	numaCores, err := cpuset.Parse(numaNode.Cores)
+	if err != nil {
+		tr.logger.Warn("failed to parse cores of NUMA node", "numa_node", numaNode.ID, "error", err)
+		return shared.String()
+	}
+	if cores := shared.Intersect(numaCores); cores.Size() != 0 {
+		return cores.String()
+	}
+
+	// All cores of the NUMA node are reservable, so only its memory is
+	// local to the task
+	tr.logger.Warn("NUMA node has no shared cores", "numa_node", numaNode.ID)
+	return shared.String()
+}
+
+// numaNode returns the NUMA node the scheduler bound the task to or nil if
+// the task is not bound to a NUMA node of this client.
+func (tr *TaskRunner) numaNode() *structs.NodeNumaNode {
+	alloc := tr.Alloc()
+	if alloc.AllocatedResources == nil {
+		return nil
+	}
+
+	taskResources, ok := alloc.AllocatedResources.Tasks[tr.taskName]
+	if !ok || taskResources.Numa == nil {
+		return nil
+	}
+
+	for _, numaNode := range tr.clientConfig.Node.NodeResources.NumaNodes {
+		if numaNode.ID == taskResources.Numa.NodeID {
+			return numaNode
+		}
+	}
+	tr.logger.Warn("task is bound to unknown NUMA node", "numa_node", taskResources.Numa.NodeID)
+	return nil
+}
+
 // Restore task runner state. Called by AllocRunner.Restore after NewTaskRunner
 // but before Run so no locks need to be acquired.
 func (tr *TaskRunner) Restore() error {
@@ -765,6 +1016,15 @@ func (tr *TaskRunner) restoreHandle(taskHandle *drivers.TaskHandle, net *cstruct
 			return
 		}
 
+		// Remote tasks keep running independently of the client, so an
+		// error reaching them must not destroy them. The remote task hook
+		// attempts to recover the task again before it is restarted.
+		if tr.remoteTask() {
+			tr.logger.Error("error recovering remote task; will retry before restarting",
+				"error", err, "task_id", taskHandle.Config.ID)
+			return
+		}
+
 		tr.logger.Error("error recovering task; cleaning up",
 			"error", err, "task_id", taskHandle.Config.ID)
 
@@ -1014,7 +1274,7 @@ func (tr *TaskRunner) UpdateStats(ru *cstructs.TaskResourceUsage) {
 	}
 }
 
-//TODO Remove Backwardscompat or use tr.Alloc()?
+// TODO Remove Backwardscompat or use tr.Alloc()?
 func (tr *TaskRunner) setGaugeForMemory(ru *cstructs.TaskResourceUsage) {
 	if !tr.clientConfig.DisableTaggedMetrics {
 		metrics.SetGaugeWithLabels([]string{"client", "allocs", "memory", "rss"},
@@ -1031,6 +1291,10 @@ func (tr *TaskRunner) setGaugeForMemory(ru *cstructs.TaskResourceUsage) {
 			float32(ru.ResourceUsage.MemoryStats.KernelUsage), tr.baseLabels)
 		metrics.SetGaugeWithLabels([]string{"client", "allocs", "memory", "kernel_max_usage"},
 			float32(ru.ResourceUsage.MemoryStats.KernelMaxUsage), tr.baseLabels)
+		metrics.SetGaugeWithLabels([]string{"client", "allocs", "memory", "usage"},
+			float32(ru.ResourceUsage.MemoryStats.Usage), tr.baseLabels)
+		metrics.SetGaugeWithLabels([]string{"client", "allocs", "memory", "balloon_actual"},
+			float32(ru.ResourceUsage.MemoryStats.BalloonActual), tr.baseLabels)
 	}
 
 	if tr.clientConfig.BackwardsCompatibleMetrics {
@@ -1043,7 +1307,7 @@ func (tr *TaskRunner) setGaugeForMemory(ru *cstructs.TaskResourceUsage) {
 	}
 }
 
-//TODO Remove Backwardscompat or use tr.Alloc()?
+// TODO Remove Backwardscompat or use tr.Alloc()?
 func (tr *TaskRunner) setGaugeForCPU(ru *cstructs.TaskResourceUsage) {
 	if !tr.clientConfig.DisableTaggedMetrics {
 		metrics.SetGaugeWithLabels([]string{"client", "allocs", "cpu", "total_percent"},
@@ -1,7 +1,9 @@
 package taskrunner
 
 import (
+	cstructs "github.com/hashicorp/nomad/client/structs"
 	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/plugins/drivers"
 )
 
 func (tr *TaskRunner) Alloc() *structs.Allocation {
@@ -33,6 +35,42 @@ func (tr *TaskRunner) Task() *structs.Task {
 	return tr.task
 }
 
+// SetNetworkIsolation sets the network namespace the task must run in and
+// the network of its allocation. It must be called before the task is run.
+func (tr *TaskRunner) SetNetworkIsolation(spec *drivers.NetworkIsolationSpec, net *cstructs.DriverNetwork) {
+	tr.networkLock.Lock()
+	defer tr.networkLock.Unlock()
+	tr.networkIsolation = spec
+	tr.allocNetwork = net
+}
+
+func (tr *TaskRunner) getNetworkIsolation() *drivers.NetworkIsolationSpec {
+	tr.networkLock.Lock()
+	defer tr.networkLock.Unlock()
+	return tr.networkIsolation.Copy()
+}
+
+func (tr *TaskRunner) getAllocNetwork() *cstructs.DriverNetwork {
+	tr.networkLock.Lock()
+	defer tr.networkLock.Unlock()
+	return tr.allocNetwork.Copy()
+}
+
+// DriverCapabilities returns the capabilities of the driver of the task.
+func (tr *TaskRunner) DriverCapabilities() *drivers.Capabilities {
+	return tr.driverCapabilities
+}
+
+// DriverNetworkManager returns the driver of the task if it must create the
+// network namespace of the allocation.
+func (tr *TaskRunner) DriverNetworkManager() (drivers.DriverNetworkManager, bool) {
+	if tr.driverCapabilities == nil || !tr.driverCapabilities.MustCreateNetwork {
+		return nil, false
+	}
+	nm, ok := tr.driver.(drivers.DriverNetworkManager)
+	return nm, ok
+}
+
 func (tr *TaskRunner) TaskState() *structs.TaskState {
 	tr.stateLock.Lock()
 	defer tr.stateLock.Unlock()
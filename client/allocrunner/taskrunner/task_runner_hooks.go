@@ -61,9 +61,35 @@ func (tr *TaskRunner) initHooks() {
 		newTaskDirHook(tr, hookLogger),
 		newLogMonHook(tr.logmonHookConfig, hookLogger),
 		newDispatchHook(tr.Alloc(), hookLogger),
-		newArtifactHook(tr, hookLogger),
+		newArtifactHook(tr, tr.clientConfig, hookLogger),
 		newStatsHook(tr, tr.clientConfig.StatsCollectionInterval, hookLogger),
 		newDeviceHook(tr.devicemanager, hookLogger),
+		newCpusetHook(tr.cpusetManager, fmt.Sprintf("%s/%s", tr.allocID, tr.taskName), hookLogger),
+	}
+
+	// If the task uses variables, add the hook reading them
+	if tr.rpcClient != nil && taskUsesVariables(task) {
+		tr.runnerHooks = append(tr.runnerHooks, newVariablesHook(&variablesHookConfig{
+			alloc:      tr.Alloc(),
+			node:       tr.clientConfig.Node,
+			region:     tr.clientConfig.Region,
+			rpc:        tr.rpcClient,
+			envBuilder: tr.envBuilder,
+			logger:     hookLogger,
+		}))
+	}
+
+	// If the task API is enabled, add the hook serving it
+	if tr.rpcClient != nil && tr.clientConfig.EnableTaskAPI {
+		tr.runnerHooks = append(tr.runnerHooks, newTaskAPIHook(&taskAPIHookConfig{
+			alloc:  tr.Alloc(),
+			task:   task.Name,
+			node:   tr.clientConfig.Node,
+			region: tr.clientConfig.Region,
+			logDir: tr.taskDir.LogDir,
+			rpc:    tr.rpcClient,
+			logger: hookLogger,
+		}))
 	}
 
 	// If Vault is enabled, add the hook
@@ -92,6 +118,20 @@ func (tr *TaskRunner) initHooks() {
 		}))
 	}
 
+	// If there are services registered with the servers, add the hook. It is
+	// added before the Consul services hook so the services are deregistered
+	// before waiting for the shutdown delay.
+	if tr.rpcClient != nil && len(filterServices(task.Services, structs.ServiceProviderNomad)) != 0 {
+		tr.runnerHooks = append(tr.runnerHooks, newNomadServicesHook(&nomadServicesHookConfig{
+			alloc:  tr.Alloc(),
+			task:   tr.Task(),
+			node:   tr.clientConfig.Node,
+			region: tr.clientConfig.Region,
+			rpc:    tr.rpcClient,
+			logger: hookLogger,
+		}))
+	}
+
 	// If there are any services, add the hook
 	if len(task.Services) != 0 {
 		tr.runnerHooks = append(tr.runnerHooks, newServiceHook(serviceHookConfig{
@@ -102,6 +142,11 @@ func (tr *TaskRunner) initHooks() {
 			logger:    hookLogger,
 		}))
 	}
+
+	// If the task runs on a remote task driver, add the hook recovering it
+	if tr.remoteTask() {
+		tr.runnerHooks = append(tr.runnerHooks, newRemoteTaskHook(tr, hookLogger))
+	}
 }
 
 // prestart is used to run the runners prestart hooks.
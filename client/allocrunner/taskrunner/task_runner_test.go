@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"path/filepath"
+	"runtime"
 	"testing"
 	"time"
 
@@ -16,9 +17,11 @@ import (
 	"github.com/hashicorp/nomad/client/vaultclient"
 	mockdriver "github.com/hashicorp/nomad/drivers/mock"
 	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/plugins/device"
+	"github.com/hashicorp/nomad/plugins/drivers"
 	"github.com/hashicorp/nomad/plugins/shared/catalog"
 	"github.com/hashicorp/nomad/plugins/shared/singleton"
 	"github.com/hashicorp/nomad/testutil"
@@ -349,3 +352,73 @@ func TestTaskRunner_Restore_HookEnv(t *testing.T) {
 	require.Contains(env, "mock_hook")
 	require.Equal("1", env["mock_hook"])
 }
+
+// TestTaskRunner_ExitResultEvent asserts tasks killed by the OOM killer are
+// reported with a distinct event.
+func TestTaskRunner_ExitResultEvent(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	event := exitResultEvent(&drivers.ExitResult{ExitCode: 1})
+	require.Equal(structs.TaskTerminated, event.Type)
+	require.Equal(1, event.ExitCode)
+	require.Equal("false", event.Details["oom_killed"])
+
+	event = exitResultEvent(&drivers.ExitResult{ExitCode: 137, Signal: 9, OOMKilled: true})
+	require.Equal(structs.TaskOOMKilled, event.Type)
+	require.Equal(137, event.ExitCode)
+	require.Equal(9, event.Signal)
+	require.Equal("true", event.Details["oom_killed"])
+
+	event.PopulateEventDisplayMessage()
+	require.Contains(event.DisplayMessage, "OOM killer")
+	require.Contains(event.DisplayMessage, "Exit Code: 137")
+}
+
+// TestTaskRunner_CPUBandwidth asserts the CFS quota of hard limited tasks is
+// their share of all cores over the requested period.
+func TestTaskRunner_CPUBandwidth(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	numCores := int64(runtime.NumCPU())
+
+	period, quota := cpuBandwidth(0.5, 0)
+	require.EqualValues(structs.DefaultCPUCFSPeriod, period)
+	require.Equal(50000*numCores, quota)
+
+	period, quota = cpuBandwidth(0.25, 400000)
+	require.EqualValues(400000, period)
+	require.Equal(100000*numCores, quota)
+
+	// Tiny shares are raised to the minimum quota
+	_, quota = cpuBandwidth(0.000001, 0)
+	require.EqualValues(structs.MinCPUCFSPeriod, quota)
+}
+
+// TestTaskRunner_ShouldDetach asserts only remote tasks of lost and replaced
+// allocations are detached.
+func TestTaskRunner_ShouldDetach(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	alloc := mock.Alloc()
+	alloc.ClientStatus = structs.AllocClientStatusLost
+	alloc.NextAllocation = uuid.Generate()
+
+	tr := &TaskRunner{
+		alloc:              alloc,
+		driverCapabilities: &drivers.Capabilities{},
+	}
+	require.False(tr.shouldDetach())
+
+	tr.driverCapabilities.RemoteTasks = true
+	require.True(tr.shouldDetach())
+
+	alloc.NextAllocation = ""
+	require.False(tr.shouldDetach())
+
+	alloc.NextAllocation = uuid.Generate()
+	alloc.ClientStatus = structs.AllocClientStatusComplete
+	require.False(tr.shouldDetach())
+}
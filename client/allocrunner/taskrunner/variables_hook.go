@@ -0,0 +1,83 @@
+package taskrunner
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	cinterfaces "github.com/hashicorp/nomad/client/interfaces"
+	"github.com/hashicorp/nomad/client/taskenv"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// variablesHookConfig is the configuration of the variables hook
+type variablesHookConfig struct {
+	alloc      *structs.Allocation
+	node       *structs.Node
+	region     string
+	rpc        cinterfaces.RPCer
+	envBuilder *taskenv.Builder
+	logger     hclog.Logger
+}
+
+// variablesHook reads the variables of the task from the servers and makes
+// their items available to the interpolation of the task and its templates.
+type variablesHook struct {
+	config *variablesHookConfig
+	logger hclog.Logger
+}
+
+func newVariablesHook(config *variablesHookConfig) *variablesHook {
+	h := &variablesHook{
+		config: config,
+	}
+	h.logger = config.logger.Named(h.Name())
+	return h
+}
+
+func (*variablesHook) Name() string {
+	return "variables"
+}
+
+func (h *variablesHook) Prestart(ctx context.Context, req *interfaces.TaskPrestartRequest, resp *interfaces.TaskPrestartResponse) error {
+	args := structs.VariablesTaskReadRequest{
+		NodeID:   h.config.node.ID,
+		SecretID: h.config.node.SecretID,
+		AllocID:  h.config.alloc.ID,
+		Task:     req.Task.Name,
+		QueryOptions: structs.QueryOptions{
+			Region:     h.config.region,
+			AllowStale: true,
+		},
+	}
+	var reply structs.VariablesTaskReadResponse
+	if err := h.config.rpc.RPC("Variables.TaskRead", &args, &reply); err != nil {
+		// Servers not supporting variables have no variables to read
+		if strings.Contains(err.Error(), "can't find service") {
+			h.logger.Debug("servers do not support variables")
+			return nil
+		}
+		return structs.NewRecoverableError(err, true)
+	}
+
+	h.config.envBuilder.SetVariables(reply.Items)
+	return nil
+}
+
+// taskUsesVariables returns whether the environment, templates or driver
+// config of the task reference the items of its variables.
+func taskUsesVariables(task *structs.Task) bool {
+	for _, v := range task.Env {
+		if strings.Contains(v, taskenv.VariablePrefix) {
+			return true
+		}
+	}
+	for _, tmpl := range task.Templates {
+		if tmpl.SourcePath != "" || strings.Contains(tmpl.EmbeddedTmpl, taskenv.VariablePrefix) {
+			return true
+		}
+	}
+	return strings.Contains(fmt.Sprintf("%v", task.Config), taskenv.VariablePrefix)
+}
@@ -0,0 +1,107 @@
+package taskrunner
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	"github.com/hashicorp/nomad/client/taskenv"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+// Statically assert the variables hook implements the expected interfaces
+var _ interfaces.TaskPrestartHook = (*variablesHook)(nil)
+
+// mockVariablesRPC answers Variables.TaskRead calls with fixed items or error
+type mockVariablesRPC struct {
+	items map[string]string
+	err   error
+	args  *structs.VariablesTaskReadRequest
+}
+
+func (m *mockVariablesRPC) RPC(method string, args interface{}, reply interface{}) error {
+	if method != "Variables.TaskRead" {
+		return fmt.Errorf("unexpected method %q", method)
+	}
+	if m.err != nil {
+		return m.err
+	}
+	m.args = args.(*structs.VariablesTaskReadRequest)
+	reply.(*structs.VariablesTaskReadResponse).Items = m.items
+	return nil
+}
+
+func testVariablesHook(t *testing.T, rpc *mockVariablesRPC) (*variablesHook, *taskenv.Builder, *structs.Allocation) {
+	node := mock.Node()
+	alloc := mock.Alloc()
+	task := alloc.Job.TaskGroups[0].Tasks[0]
+	envBuilder := taskenv.NewBuilder(node, alloc, task, "global")
+	h := newVariablesHook(&variablesHookConfig{
+		alloc:      alloc,
+		node:       node,
+		region:     "global",
+		rpc:        rpc,
+		envBuilder: envBuilder,
+		logger:     testlog.HCLogger(t),
+	})
+	return h, envBuilder, alloc
+}
+
+// TestTaskRunner_VariablesHook asserts the items of the variables of the task
+// are available for interpolation.
+func TestTaskRunner_VariablesHook(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	rpc := &mockVariablesRPC{items: map[string]string{"password": "hunter2"}}
+	h, envBuilder, alloc := testVariablesHook(t, rpc)
+	task := alloc.Job.TaskGroups[0].Tasks[0]
+
+	req := interfaces.TaskPrestartRequest{Task: task}
+	resp := interfaces.TaskPrestartResponse{}
+	require.NoError(h.Prestart(context.Background(), &req, &resp))
+	require.False(resp.Done)
+
+	require.Equal(alloc.ID, rpc.args.AllocID)
+	require.Equal(task.Name, rpc.args.Task)
+	require.Equal("hunter2", envBuilder.Build().ReplaceEnv("${nomad_var.password}"))
+}
+
+// TestTaskRunner_VariablesHook_Errors asserts servers not supporting
+// variables are tolerated while other errors are recoverable.
+func TestTaskRunner_VariablesHook_Errors(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	rpc := &mockVariablesRPC{err: fmt.Errorf("rpc: can't find service Variables.TaskRead")}
+	h, _, alloc := testVariablesHook(t, rpc)
+	req := interfaces.TaskPrestartRequest{Task: alloc.Job.TaskGroups[0].Tasks[0]}
+	resp := interfaces.TaskPrestartResponse{}
+	require.NoError(h.Prestart(context.Background(), &req, &resp))
+
+	rpc.err = fmt.Errorf("no path to region")
+	err := h.Prestart(context.Background(), &req, &resp)
+	require.Error(err)
+	require.True(structs.IsRecoverable(err))
+}
+
+// TestTaskRunner_VariablesHook_TaskUsesVariables asserts the hook is only
+// needed by tasks referencing variables.
+func TestTaskRunner_VariablesHook_TaskUsesVariables(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	task := mock.Job().TaskGroups[0].Tasks[0]
+	require.False(taskUsesVariables(task))
+
+	task.Env["PASSWORD"] = "${nomad_var.password}"
+	require.True(taskUsesVariables(task))
+
+	task = mock.Job().TaskGroups[0].Tasks[0]
+	task.Templates = []*structs.Template{{EmbeddedTmpl: `{{ env "nomad_var.password" }}`}}
+	require.True(taskUsesVariables(task))
+}
@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io/ioutil"
@@ -27,6 +28,9 @@ import (
 	"github.com/hashicorp/nomad/client/config"
 	consulApi "github.com/hashicorp/nomad/client/consul"
 	"github.com/hashicorp/nomad/client/devicemanager"
+	"github.com/hashicorp/nomad/client/fingerprint"
+	"github.com/hashicorp/nomad/client/hostvolume"
+	"github.com/hashicorp/nomad/client/lib/cgutil"
 	"github.com/hashicorp/nomad/client/servers"
 	"github.com/hashicorp/nomad/client/state"
 	"github.com/hashicorp/nomad/client/stats"
@@ -38,9 +42,11 @@ import (
 	hstats "github.com/hashicorp/nomad/helper/stats"
 	"github.com/hashicorp/nomad/helper/tlsutil"
 	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/lib/cpuset"
 	"github.com/hashicorp/nomad/nomad/structs"
 	nconfig "github.com/hashicorp/nomad/nomad/structs/config"
 	"github.com/hashicorp/nomad/plugins/device"
+	"github.com/hashicorp/nomad/plugins/drivers"
 	vaultapi "github.com/hashicorp/vault/api"
 	"github.com/shirou/gopsutil/host"
 )
@@ -116,6 +122,11 @@ type AllocRunner interface {
 	Restore() error
 	Run()
 	StatsReporter() interfaces.AllocStatsReporter
+	ExecTask(ctx context.Context, taskName string, opts *drivers.ExecOptions) (*drivers.ExitResult, error)
+	GetTaskDriverCapabilities(taskName string) (*drivers.Capabilities, error)
+	RestartTask(taskName string, event *structs.TaskEvent) error
+	RestartAll(event *structs.TaskEvent) error
+	Signal(taskName, signal string) error
 	Update(*structs.Allocation)
 	WaitCh() <-chan struct{}
 }
@@ -134,6 +145,11 @@ type Client struct {
 	configCopy *config.Config
 	configLock sync.RWMutex
 
+	// staticMeta is the metadata of the node set by the configuration, which
+	// the dynamic metadata of the node overrides in its Meta. It is guarded
+	// by configLock.
+	staticMeta map[string]string
+
 	logger    hclog.Logger
 	rpcLogger hclog.Logger
 
@@ -204,6 +220,21 @@ type Client struct {
 	// vaultClient is used to interact with Vault for token and secret renewals
 	vaultClient vaultclient.VaultClient
 
+	// hostVolumes provisions the host volumes created at runtime
+	hostVolumes *hostvolume.Manager
+
+	// hostVolumeChecker checks the health of the configured host volumes
+	hostVolumeChecker *hostvolume.HealthChecker
+
+	// terminationDraining is whether the client started draining its node
+	// ahead of the termination of its instance. It is protected by the
+	// configLock.
+	terminationDraining bool
+
+	// diskWatcher garbage collects allocations and marks the node ineligible
+	// under disk pressure
+	diskWatcher *diskWatcher
+
 	// garbageCollector is used to garbage collect terminal allocations present
 	// in the node automatically
 	garbageCollector *AllocGarbageCollector
@@ -219,6 +250,9 @@ type Client struct {
 	// devicemanger is responsible for managing device plugins.
 	devicemanager devicemanager.Manager
 
+	// cpusetManager tracks the cores dedicated to tasks
+	cpusetManager *cgutil.CpusetManager
+
 	// baseLabels are used when emitting tagged metrics. All client metrics will
 	// have these tags, and optionally more.
 	baseLabels []metrics.Label
@@ -266,6 +300,7 @@ func NewClient(cfg *config.Config, consulCatalog consul.CatalogAPI, consulServic
 		triggerDiscoveryCh:   make(chan struct{}),
 		triggerNodeUpdate:    make(chan struct{}, 8),
 		triggerEmitNodeEvent: make(chan *structs.NodeEvent, 8),
+		hostVolumeChecker:    hostvolume.NewHealthChecker(hostVolumeCheckTimeout),
 	}
 
 	// Initialize the server manager
@@ -304,6 +339,22 @@ func NewClient(cfg *config.Config, consulCatalog consul.CatalogAPI, consulServic
 		return nil, fmt.Errorf("fingerprinting failed: %v", err)
 	}
 
+	// Reserve the resources of the system cgroup slice now that the compute
+	// of the node is known
+	if err := c.reserveCgroupSlice(); err != nil {
+		return nil, err
+	}
+
+	// Setup the cpuset manager tracking the cores dedicated to tasks
+	reservableCores, err := cpuset.Parse(cfg.ReservableCores)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse reservable cores: %v", err)
+	}
+	c.cpusetManager, err = cgutil.NewCpusetManager(cpuset.Host(), reservableCores)
+	if err != nil {
+		return nil, fmt.Errorf("invalid reservable cores: %v", err)
+	}
+
 	// Setup the device manager
 	devConfig := &devicemanager.Config{
 		Logger:        c.logger,
@@ -332,6 +383,19 @@ func NewClient(cfg *config.Config, consulCatalog consul.CatalogAPI, consulServic
 	c.garbageCollector = NewAllocGarbageCollector(c.logger, statsCollector, c, gcConfig)
 	go c.garbageCollector.Run()
 
+	// Setup the provisioners of host volumes
+	c.hostVolumes = hostvolume.NewManager(c.logger, cfg.HostVolumesDir, cfg.HostVolumePluginDir)
+
+	// Watch the alloc dir for disk pressure between garbage collections
+	diskConfig := &diskWatcherConfig{
+		Interval:              cfg.DiskWatchInterval,
+		GCDiskUsageThreshold:  cfg.GCDiskUsageThreshold,
+		GCInodeUsageThreshold: cfg.GCInodeUsageThreshold,
+		IneligibleThreshold:   cfg.DiskIneligibleThreshold,
+	}
+	c.diskWatcher = newDiskWatcher(c.logger, statsCollector, c.garbageCollector,
+		c.setSchedulingEligibility, diskConfig, c.shutdownCh)
+
 	// Set the preconfigured list of static servers
 	c.configLock.RLock()
 	if len(c.configCopy.Servers) > 0 {
@@ -378,6 +442,12 @@ func NewClient(cfg *config.Config, consulCatalog consul.CatalogAPI, consulServic
 	// Begin syncing allocations to the server
 	c.shutdownGroup.Go(c.allocSync)
 
+	// Begin watching for disk pressure
+	c.shutdownGroup.Go(c.diskWatcher.run)
+
+	// Begin checking the health of the host volumes
+	c.shutdownGroup.Go(c.watchHostVolumes)
+
 	// Start the client! Don't use the shutdownGroup as run handles
 	// shutdowns manually to prevent updates from being applied during
 	// shutdown.
@@ -416,7 +486,7 @@ func (c *Client) init() error {
 	c.logger.Info("using state directory", "state_dir", c.config.StateDir)
 
 	// Open the state database
-	db, err := state.GetStateDBFactory(c.config.DevMode)(c.config.StateDir)
+	db, err := state.GetStateDBFactory(c.config.DevMode)(c.logger, c.config.StateDir)
 	if err != nil {
 		return fmt.Errorf("failed to open state database: %v", err)
 	}
@@ -499,10 +569,14 @@ func (c *Client) Reload(newConfig *config.Config) error {
 	return nil
 }
 
-// Leave is used to prepare the client to leave the cluster
+// Leave is used to prepare the client to leave the cluster. If the client is
+// configured to drain on shutdown it drains itself and waits for its
+// allocations to migrate.
 func (c *Client) Leave() error {
-	// TODO
-	return nil
+	if c.config.DrainOnShutdown == nil {
+		return nil
+	}
+	return c.drainSelf()
 }
 
 // GetConfig returns the config of the client
@@ -634,6 +708,21 @@ func (c *Client) Node() *structs.Node {
 	return c.configCopy.Node
 }
 
+// Registered returns whether the node has registered with the servers and
+// its heartbeat hasn't expired since.
+func (c *Client) Registered() bool {
+	c.configLock.RLock()
+	ready := c.config.Node.Status == structs.NodeStatusReady
+	c.configLock.RUnlock()
+	if !ready {
+		return false
+	}
+
+	c.heartbeatLock.Lock()
+	defer c.heartbeatLock.Unlock()
+	return time.Since(c.lastHeartbeat) <= c.heartbeatTTL
+}
+
 // StatsReporter exposes the various APIs related resource usage of a Nomad
 // client
 func (c *Client) StatsReporter() ClientStatsReporter {
@@ -735,6 +824,78 @@ func (c *Client) GetAllocFS(allocID string) (allocdir.AllocDirFS, error) {
 	return ar.GetAllocDir(), nil
 }
 
+// ExecTask runs an interactive command within a task of an allocation on this
+// client. It returns an unknown allocation error if the allocation does not
+// exist.
+func (c *Client) ExecTask(ctx context.Context, allocID, taskName string, opts *drivers.ExecOptions) (*drivers.ExitResult, error) {
+	c.allocLock.RLock()
+	ar, ok := c.allocs[allocID]
+	c.allocLock.RUnlock()
+	if !ok {
+		return nil, structs.NewErrUnknownAllocation(allocID)
+	}
+
+	return ar.ExecTask(ctx, taskName, opts)
+}
+
+// GetTaskDriverCapabilities returns the capabilities of the driver of the
+// named task of an allocation.
+func (c *Client) GetTaskDriverCapabilities(allocID, taskName string) (*drivers.Capabilities, error) {
+	c.allocLock.RLock()
+	ar, ok := c.allocs[allocID]
+	c.allocLock.RUnlock()
+	if !ok {
+		return nil, structs.NewErrUnknownAllocation(allocID)
+	}
+
+	return ar.GetTaskDriverCapabilities(taskName)
+}
+
+// RestartAllocation restarts the named task of an allocation, or all its
+// tasks if taskName is empty.
+func (c *Client) RestartAllocation(allocID, taskName string) error {
+	c.allocLock.RLock()
+	ar, ok := c.allocs[allocID]
+	c.allocLock.RUnlock()
+	if !ok {
+		return structs.NewErrUnknownAllocation(allocID)
+	}
+
+	event := structs.NewTaskEvent(structs.TaskRestartSignal).
+		SetRestartReason("User requested restart")
+
+	if taskName != "" {
+		return ar.RestartTask(taskName, event)
+	}
+	return ar.RestartAll(event)
+}
+
+// SignalAllocation sends a signal to the named task of an allocation, or to
+// all its tasks if taskName is empty.
+func (c *Client) SignalAllocation(allocID, taskName, signal string) error {
+	c.allocLock.RLock()
+	ar, ok := c.allocs[allocID]
+	c.allocLock.RUnlock()
+	if !ok {
+		return structs.NewErrUnknownAllocation(allocID)
+	}
+
+	return ar.Signal(taskName, signal)
+}
+
+// GetAllocByID returns the allocation with the given ID running on this
+// client, or an unknown allocation error.
+func (c *Client) GetAllocByID(allocID string) (*structs.Allocation, error) {
+	c.allocLock.RLock()
+	ar, ok := c.allocs[allocID]
+	c.allocLock.RUnlock()
+	if !ok {
+		return nil, structs.NewErrUnknownAllocation(allocID)
+	}
+
+	return ar.Alloc(), nil
+}
+
 // GetAllocState returns a copy of an allocation's state on this client. It
 // returns either an AllocState or an unknown allocation error.
 func (c *Client) GetAllocState(allocID string) (*arstate.State, error) {
@@ -871,10 +1032,12 @@ func (c *Client) restoreState() error {
 			DeviceStatsReporter:   c,
 			Consul:                c.consulService,
 			Vault:                 c.vaultClient,
+			RPCClient:             c,
 			PrevAllocWatcher:      prevAllocWatcher,
 			PluginLoader:          c.config.PluginLoader,
 			PluginSingletonLoader: c.config.PluginSingletonLoader,
 			DeviceManager:         c.devicemanager,
+			CpusetManager:         c.cpusetManager,
 		}
 		c.configLock.RUnlock()
 
@@ -1072,6 +1235,9 @@ func (c *Client) setupNode() error {
 	if node.Datacenter == "" {
 		node.Datacenter = "dc1"
 	}
+	if node.NodePool == "" {
+		node.NodePool = structs.NodePoolDefault
+	}
 	if node.Name == "" {
 		node.Name, _ = os.Hostname()
 	}
@@ -1079,9 +1245,83 @@ func (c *Client) setupNode() error {
 		node.Name = node.ID
 	}
 	node.Status = structs.NodeStatusInit
+
+	// Expose the configured host volumes with their initial health
+	node.HostVolumes = structs.CopyMapClientHostVolumeConfig(c.config.HostVolumes)
+	for _, volume := range node.HostVolumes {
+		volume.Healthy, volume.HealthDescription = c.hostVolumeHealth(volume)
+	}
+
+	// Restore the dynamic metadata of the node and merge it over the
+	// configured metadata. The servers retain the dynamic metadata they know
+	// of when the node re-registers, so it is only used if they lost the
+	// node, for example after it was garbage collected.
+	dynamicMeta, err := c.stateDB.GetNodeMeta()
+	if err != nil {
+		return fmt.Errorf("failed to restore node metadata: %v", err)
+	}
+	c.staticMeta = helper.CopyMapStringString(node.Meta)
+	node.DynamicMeta = dynamicMeta
+	node.Meta = mergeNodeMeta(c.staticMeta, dynamicMeta)
+	return nil
+}
+
+// persistDynamicMeta applies an update of the dynamic metadata committed by
+// the servers to the node and persists it, so that the node re-registers with
+// it merged over its configured metadata.
+func (c *Client) persistDynamicMeta(set map[string]string, unset []string) error {
+	c.configLock.Lock()
+	defer c.configLock.Unlock()
+
+	dynamic := helper.CopyMapStringString(c.config.Node.DynamicMeta)
+	if dynamic == nil {
+		dynamic = make(map[string]string, len(set))
+	}
+	for k, v := range set {
+		dynamic[k] = v
+	}
+	for _, k := range unset {
+		delete(dynamic, k)
+	}
+	if len(dynamic) == 0 {
+		dynamic = nil
+	}
+
+	if err := c.stateDB.PutNodeMeta(dynamic); err != nil {
+		return fmt.Errorf("failed to persist node metadata: %v", err)
+	}
+	c.config.Node.DynamicMeta = dynamic
+	c.config.Node.Meta = mergeNodeMeta(c.staticMeta, dynamic)
+	c.updateNodeLocked()
 	return nil
 }
 
+// mergeNodeMeta returns the metadata of the node, which is the static
+// metadata overridden by the dynamic one.
+func mergeNodeMeta(static, dynamic map[string]string) map[string]string {
+	meta := make(map[string]string, len(static)+len(dynamic))
+	for k, v := range static {
+		meta[k] = v
+	}
+	for k, v := range dynamic {
+		meta[k] = v
+	}
+	return meta
+}
+
+// nodeMeta returns the metadata of the node along with its static and
+// dynamic parts.
+func (c *Client) nodeMeta() *cstructs.NodeMetaResponse {
+	c.configLock.RLock()
+	defer c.configLock.RUnlock()
+
+	return &cstructs.NodeMetaResponse{
+		Meta:    helper.CopyMapStringString(c.config.Node.Meta),
+		Static:  helper.CopyMapStringString(c.staticMeta),
+		Dynamic: helper.CopyMapStringString(c.config.Node.DynamicMeta),
+	}
+}
+
 // updateNodeFromFingerprint updates the node with the result of
 // fingerprinting the node from the diff that was created
 func (c *Client) updateNodeFromFingerprint(response *cstructs.FingerprintResponse) *structs.Node {
@@ -1090,6 +1330,11 @@ func (c *Client) updateNodeFromFingerprint(response *cstructs.FingerprintRespons
 
 	nodeHasChanged := false
 
+	// changedAttrs are the attributes of the node whose value changed or
+	// which were removed. New attributes aren't reported as they are mostly
+	// set by the initial fingerprint.
+	var changedAttrs []string
+
 	for name, newVal := range response.Attributes {
 		oldVal := c.config.Node.Attributes[name]
 		if oldVal == newVal {
@@ -1097,6 +1342,9 @@ func (c *Client) updateNodeFromFingerprint(response *cstructs.FingerprintRespons
 		}
 
 		nodeHasChanged = true
+		if oldVal != "" {
+			changedAttrs = append(changedAttrs, name)
+		}
 		if newVal == "" {
 			delete(c.config.Node.Attributes, name)
 		} else {
@@ -1104,6 +1352,9 @@ func (c *Client) updateNodeFromFingerprint(response *cstructs.FingerprintRespons
 		}
 	}
 
+	// Drain the node ahead of the termination of its instance
+	c.handleTerminationNotice(response.Attributes[fingerprint.TerminationTimeAttribute])
+
 	// update node links and resources from the diff created from
 	// fingerprinting
 	for name, newVal := range response.Links {
@@ -1131,6 +1382,14 @@ func (c *Client) updateNodeFromFingerprint(response *cstructs.FingerprintRespons
 		c.config.Node.NodeResources.Merge(response.NodeResources)
 	}
 
+	if len(changedAttrs) != 0 {
+		sort.Strings(changedAttrs)
+		c.triggerNodeEvent(structs.NewNodeEvent().
+			SetSubsystem(structs.NodeEventSubsystemFingerprint).
+			SetMessage("Node fingerprint changed").
+			AddDetail("attributes", strings.Join(changedAttrs, ",")))
+	}
+
 	if nodeHasChanged {
 		c.updateNodeLocked()
 	}
@@ -1169,10 +1428,13 @@ func (c *Client) updateNodeFromDriver(name string, info *structs.DriverInfo) *st
 			hasChanged = true
 			if info.HealthDescription != "" {
 				event := &structs.NodeEvent{
-					Subsystem: "Driver",
+					Subsystem: structs.NodeEventSubsystemDriver,
 					Message:   info.HealthDescription,
 					Timestamp: time.Now(),
-					Details:   map[string]string{"driver": name},
+					Details: map[string]string{
+						"driver":  name,
+						"healthy": strconv.FormatBool(info.Healthy),
+					},
 				}
 				c.triggerNodeEvent(event)
 			}
@@ -1579,6 +1841,10 @@ func (c *Client) updateNodeStatus() error {
 		if haveHeartbeated {
 			c.logger.Warn("missed heartbeat",
 				"req_latency", end.Sub(start), "heartbeat_ttl", oldTTL, "since_last_heartbeat", time.Since(last))
+
+			// The servers may have marked our allocations unknown while we
+			// were disconnected, so report their current state again.
+			go c.resyncAllocs()
 		}
 	}
 
@@ -1614,6 +1880,21 @@ func (c *Client) updateNodeStatus() error {
 	return nil
 }
 
+// resyncAllocs sends the current state of every allocation to the servers so
+// that allocations marked unknown while the client was disconnected are
+// reconciled instead of replaced.
+func (c *Client) resyncAllocs() {
+	for _, ar := range c.getAllocRunners() {
+		state := ar.AllocState()
+		alloc := ar.Alloc().Copy()
+		alloc.ClientStatus = state.ClientStatus
+		alloc.ClientDescription = state.ClientDescription
+		alloc.TaskStates = state.TaskStates
+		alloc.DeploymentStatus = state.DeploymentStatus
+		c.AllocStateUpdated(alloc)
+	}
+}
+
 // AllocStateUpdated asynchronously updates the server with the current state
 // of an allocations and its tasks.
 func (c *Client) AllocStateUpdated(alloc *structs.Allocation) {
@@ -2050,12 +2331,14 @@ func (c *Client) addAlloc(alloc *structs.Allocation, migrateToken string) error
 		StateDB:               c.stateDB,
 		Consul:                c.consulService,
 		Vault:                 c.vaultClient,
+		RPCClient:             c,
 		StateUpdater:          c,
 		DeviceStatsReporter:   c,
 		PrevAllocWatcher:      prevAllocWatcher,
 		PluginLoader:          c.config.PluginLoader,
 		PluginSingletonLoader: c.config.PluginSingletonLoader,
 		DeviceManager:         c.devicemanager,
+		CpusetManager:         c.cpusetManager,
 	}
 	c.configLock.RUnlock()
 
@@ -24,6 +24,7 @@ import (
 	psstructs "github.com/hashicorp/nomad/plugins/shared/structs"
 	"github.com/hashicorp/nomad/testutil"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	ctestutil "github.com/hashicorp/nomad/client/testutil"
 )
@@ -370,6 +371,41 @@ func TestClient_Register(t *testing.T) {
 	})
 }
 
+func TestClient_Leave_DrainOnShutdown(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1, _ := testServer(t, nil)
+	defer s1.Shutdown()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	c1, cleanup := TestClient(t, func(c *config.Config) {
+		c.RPCHandler = s1
+		c.DrainOnShutdown = &nconfig.DrainConfig{Deadline: 10 * time.Second}
+	})
+	defer cleanup()
+
+	req := structs.NodeSpecificRequest{
+		NodeID:       c1.Node().ID,
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var out structs.SingleNodeResponse
+	testutil.WaitForResult(func() (bool, error) {
+		if err := s1.RPC("Node.GetNode", &req, &out); err != nil {
+			return false, err
+		}
+		return out.Node != nil, fmt.Errorf("missing reg")
+	}, func(err error) {
+		t.Fatalf("err: %v", err)
+	})
+
+	// Leaving drains the node and waits for the drain to complete
+	require.NoError(c1.Leave())
+
+	require.NoError(s1.RPC("Node.GetNode", &req, &out))
+	require.Nil(out.Node.DrainStrategy)
+	require.Equal(structs.NodeSchedulingIneligible, out.Node.SchedulingEligibility)
+}
+
 func TestClient_Heartbeat(t *testing.T) {
 	t.Parallel()
 	s1, _ := testServer(t, func(c *nomad.Config) {
@@ -1029,11 +1065,18 @@ func TestClient_UpdateNodeFromDevicesAccumulates(t *testing.T) {
 	// initial check
 	expectedResources := &structs.NodeResources{
 		// computed through test client initialization
-		Networks: client.configCopy.Node.NodeResources.Networks,
-		Disk:     client.configCopy.Node.NodeResources.Disk,
+		Networks:  client.configCopy.Node.NodeResources.Networks,
+		Disk:      client.configCopy.Node.NodeResources.Disk,
+		NumaNodes: client.configCopy.Node.NodeResources.NumaNodes,
 
 		// injected
-		Cpu:    structs.NodeCpuResources{CpuShares: 123},
+		Cpu: structs.NodeCpuResources{
+			CpuShares: 123,
+
+			// computed through test client initialization
+			TotalCpuCores:      client.configCopy.Node.NodeResources.Cpu.TotalCpuCores,
+			ReservableCpuCores: client.configCopy.Node.NodeResources.Cpu.ReservableCpuCores,
+		},
 		Memory: structs.NodeMemoryResources{MemoryMB: 1024},
 		Devices: []*structs.NodeDeviceResource{
 			{
@@ -1066,11 +1109,18 @@ func TestClient_UpdateNodeFromDevicesAccumulates(t *testing.T) {
 
 	expectedResources2 := &structs.NodeResources{
 		// computed through test client initialization
-		Networks: client.configCopy.Node.NodeResources.Networks,
-		Disk:     client.configCopy.Node.NodeResources.Disk,
+		Networks:  client.configCopy.Node.NodeResources.Networks,
+		Disk:      client.configCopy.Node.NodeResources.Disk,
+		NumaNodes: client.configCopy.Node.NodeResources.NumaNodes,
 
 		// injected
-		Cpu:    structs.NodeCpuResources{CpuShares: 123},
+		Cpu: structs.NodeCpuResources{
+			CpuShares: 123,
+
+			// computed through test client initialization
+			TotalCpuCores:      client.configCopy.Node.NodeResources.Cpu.TotalCpuCores,
+			ReservableCpuCores: client.configCopy.Node.NodeResources.Cpu.ReservableCpuCores,
+		},
 		Memory: structs.NodeMemoryResources{MemoryMB: 2048},
 		Devices: []*structs.NodeDeviceResource{
 			{
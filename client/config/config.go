@@ -10,6 +10,7 @@ import (
 
 	log "github.com/hashicorp/go-hclog"
 
+	"github.com/hashicorp/nomad/command/agent/monitor"
 	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/nomad/structs/config"
@@ -77,9 +78,36 @@ type Config struct {
 	// AllocDir is where we store data for allocations
 	AllocDir string
 
+	// HostVolumesDir is where the builtin provisioner creates the host
+	// volumes created at runtime
+	HostVolumesDir string
+
+	// HostVolumePluginDir is the directory of the provisioner plugins of
+	// host volumes. Empty disables plugins.
+	HostVolumePluginDir string
+
+	// CNIPath is the list of directories, separated by colons, searched for
+	// the CNI plugins configuring the networks of allocations
+	CNIPath string
+
+	// CNIConfigDir is the directory of the CNI network configurations
+	CNIConfigDir string
+
+	// BridgeNetworkName is the name of the bridge of the allocations in
+	// bridge networks
+	BridgeNetworkName string
+
+	// BridgeNetworkSubnet is the subnet the addresses of the allocations in
+	// bridge networks are assigned from
+	BridgeNetworkSubnet string
+
 	// LogOutput is the destination for logs
 	LogOutput io.Writer
 
+	// LogSource is the source of the logs of the agent streamed to operators
+	// monitoring the client
+	LogSource monitor.LogSource
+
 	// Logger provides a logger to thhe client
 	Logger log.Logger
 
@@ -101,6 +129,10 @@ type Config struct {
 	// determined dynamically.
 	MemoryMB int
 
+	// ReservableCores is the set of cores, in cpuset list format, that may be
+	// dedicated to tasks. Tasks without dedicated cores never run on them.
+	ReservableCores string
+
 	// MaxKillTimeout allows capping the user-specifiable KillTimeout. If the
 	// task's KillTimeout is greater than the MaxKillTimeout, MaxKillTimeout is
 	// used.
@@ -178,6 +210,21 @@ type Config struct {
 	// before garbage collection is triggered.
 	GCMaxAllocs int
 
+	// DiskWatchInterval is the interval at which the client checks the
+	// usage of the alloc dir to detect disk pressure between garbage
+	// collections.
+	DiskWatchInterval time.Duration
+
+	// DiskIneligibleThreshold is the disk usage threshold given as a percent
+	// beyond which the Nomad client marks its node ineligible for
+	// scheduling. Zero disables marking the node ineligible.
+	DiskIneligibleThreshold float64
+
+	// ReservedCgroupSlice is the cgroup or systemd slice, relative to the
+	// cgroup root, whose CPU quota and memory limit are reserved on the node
+	// in place of the static reserved CPU and memory.
+	ReservedCgroupSlice string
+
 	// LogLevel is the level of the logs to putout
 	LogLevel string
 
@@ -185,6 +232,30 @@ type Config struct {
 	// random UUID.
 	NoHostUUID bool
 
+	// EnableTaskAPI exposes the task API on a unix socket in the secrets
+	// directory of every task.
+	EnableTaskAPI bool
+
+	// Fingerprinters are the external fingerprinters run periodically to
+	// contribute attributes and resources to the node.
+	Fingerprinters []*config.FingerprinterConfig
+
+	// HostVolumes are the host volumes exposed by the client, keyed by name.
+	HostVolumes map[string]*structs.ClientHostVolumeConfig
+
+	// HostNetworks are the named host networks the ports of jobs can be
+	// bound to, keyed by name.
+	HostNetworks map[string]*structs.ClientHostNetworkConfig
+
+	// DrainOnShutdown, if set, makes the client drain itself when it leaves
+	// the cluster and wait for the drain to complete.
+	DrainOnShutdown *config.DrainConfig
+
+	// DrainOnTermination, if set, makes the client drain itself when its
+	// cloud provider issues a termination notice for its instance. The
+	// deadline of the drain is bounded by the termination time.
+	DrainOnTermination *config.DrainConfig
+
 	// ACLEnabled controls if ACL enforcement and management is enabled.
 	ACLEnabled bool
 
@@ -225,6 +296,11 @@ func (c *Config) Copy() *Config {
 	nc.Options = helper.CopyMapStringString(nc.Options)
 	nc.ConsulConfig = c.ConsulConfig.Copy()
 	nc.VaultConfig = c.VaultConfig.Copy()
+	nc.Fingerprinters = config.FingerprinterSetMerge(nil, c.Fingerprinters)
+	nc.HostVolumes = structs.CopyMapClientHostVolumeConfig(c.HostVolumes)
+	nc.HostNetworks = structs.CopyMapClientHostNetworkConfig(c.HostNetworks)
+	nc.DrainOnShutdown = c.DrainOnShutdown.Copy()
+	nc.DrainOnTermination = c.DrainOnTermination.Copy()
 	return nc
 }
 
@@ -244,10 +320,15 @@ func DefaultConfig() *Config {
 		GCDiskUsageThreshold:       80,
 		GCInodeUsageThreshold:      70,
 		GCMaxAllocs:                50,
+		DiskWatchInterval:          10 * time.Second,
 		NoHostUUID:                 true,
 		DisableTaggedMetrics:       false,
 		BackwardsCompatibleMetrics: false,
 		RPCHoldTimeout:             5 * time.Second,
+		CNIPath:                    "/opt/cni/bin",
+		CNIConfigDir:               "/opt/cni/config",
+		BridgeNetworkName:          "nomad",
+		BridgeNetworkSubnet:        "172.26.64.0/20",
 	}
 }
 
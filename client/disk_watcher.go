@@ -0,0 +1,155 @@
+package client
+
+import (
+	"fmt"
+	"time"
+
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/client/stats"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// defaultDiskWatchInterval is the interval at which the disk watcher checks
+// the usage of the alloc dir if none is configured.
+const defaultDiskWatchInterval = 10 * time.Second
+
+// diskWatcherConfig configures the thresholds of the disk watcher
+type diskWatcherConfig struct {
+	// Interval is the interval at which the usage of the alloc dir is checked
+	Interval time.Duration
+
+	// GCDiskUsageThreshold and GCInodeUsageThreshold are the usage percents
+	// beyond which garbage collection is triggered
+	GCDiskUsageThreshold  float64
+	GCInodeUsageThreshold float64
+
+	// IneligibleThreshold is the disk usage percent beyond which the node is
+	// marked ineligible. Zero disables marking the node ineligible.
+	IneligibleThreshold float64
+}
+
+// gcTrigger is used by the disk watcher to trigger garbage collection and is
+// generally fulfilled by the AllocGarbageCollector.
+type gcTrigger interface {
+	Trigger()
+}
+
+// diskWatcher watches the usage of the alloc dir between the periodic garbage
+// collections. Under disk pressure it garbage collects terminal allocations
+// ahead of schedule and marks the node ineligible while the usage is over the
+// ineligible threshold.
+type diskWatcher struct {
+	config *diskWatcherConfig
+
+	// statsCollector collects the usage of the alloc dir
+	statsCollector stats.NodeStatsCollector
+
+	// gc is triggered when the usage is over the gc thresholds
+	gc gcTrigger
+
+	// setEligibility updates the scheduling eligibility of the node
+	setEligibility func(eligible bool) error
+
+	// ineligible is whether the watcher marked the node ineligible. Only
+	// the watcher restores the eligibility it took away.
+	ineligible bool
+
+	shutdownCh <-chan struct{}
+	logger     hclog.Logger
+}
+
+// newDiskWatcher returns a disk watcher. Must call run() in a goroutine to
+// start watching.
+func newDiskWatcher(logger hclog.Logger, statsCollector stats.NodeStatsCollector, gc gcTrigger,
+	setEligibility func(bool) error, config *diskWatcherConfig, shutdownCh <-chan struct{}) *diskWatcher {
+	if config.Interval <= 0 {
+		config.Interval = defaultDiskWatchInterval
+	}
+
+	return &diskWatcher{
+		config:         config,
+		statsCollector: statsCollector,
+		gc:             gc,
+		setEligibility: setEligibility,
+		shutdownCh:     shutdownCh,
+		logger:         logger.Named("disk_watcher"),
+	}
+}
+
+// run checks the usage of the alloc dir every interval until shutdown.
+func (w *diskWatcher) run() {
+	ticker := time.NewTicker(w.config.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-w.shutdownCh:
+			return
+		}
+
+		if err := w.check(); err != nil {
+			w.logger.Warn("failed to check disk pressure", "error", err)
+		}
+	}
+}
+
+// check collects the usage of the alloc dir and reacts to disk pressure.
+func (w *diskWatcher) check() error {
+	if err := w.statsCollector.Collect(); err != nil {
+		return fmt.Errorf("failed to collect disk usage: %v", err)
+	}
+
+	diskStats := w.statsCollector.Stats().AllocDirStats
+	if diskStats == nil {
+		return nil
+	}
+
+	if diskStats.UsedPercent > w.config.GCDiskUsageThreshold ||
+		diskStats.InodesUsedPercent > w.config.GCInodeUsageThreshold {
+		w.logger.Debug("triggering garbage collection due to disk pressure",
+			"disk_used_percent", diskStats.UsedPercent, "inodes_used_percent", diskStats.InodesUsedPercent)
+		w.gc.Trigger()
+	}
+
+	if w.config.IneligibleThreshold == 0 {
+		return nil
+	}
+
+	pressure := diskStats.UsedPercent > w.config.IneligibleThreshold
+	if pressure == w.ineligible {
+		return nil
+	}
+
+	if err := w.setEligibility(!pressure); err != nil {
+		return fmt.Errorf("failed to update node eligibility: %v", err)
+	}
+	w.ineligible = pressure
+
+	if pressure {
+		w.logger.Warn("marked node ineligible due to disk pressure",
+			"disk_used_percent", diskStats.UsedPercent, "threshold", w.config.IneligibleThreshold)
+	} else {
+		w.logger.Info("marked node eligible as disk pressure subsided",
+			"disk_used_percent", diskStats.UsedPercent, "threshold", w.config.IneligibleThreshold)
+	}
+	return nil
+}
+
+// setSchedulingEligibility updates the scheduling eligibility of the node of
+// the client, authenticating with its secret ID.
+func (c *Client) setSchedulingEligibility(eligible bool) error {
+	req := &structs.NodeUpdateEligibilityRequest{
+		NodeID:      c.NodeID(),
+		Eligibility: structs.NodeSchedulingIneligible,
+		WriteRequest: structs.WriteRequest{
+			Region:    c.Region(),
+			AuthToken: c.secretNodeID(),
+		},
+	}
+	if eligible {
+		req.Eligibility = structs.NodeSchedulingEligible
+	}
+
+	var resp structs.NodeEligibilityUpdateResponse
+	return c.RPC("Node.UpdateEligibility", req, &resp)
+}
@@ -0,0 +1,112 @@
+package client
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/nomad/client/stats"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDiskStatsCollector reports a fixed usage of the alloc dir
+type fakeDiskStatsCollector struct {
+	usedPercent       float64
+	inodesUsedPercent float64
+}
+
+func (f *fakeDiskStatsCollector) Collect() error {
+	return nil
+}
+
+func (f *fakeDiskStatsCollector) Stats() *stats.HostStats {
+	return &stats.HostStats{
+		AllocDirStats: &stats.DiskStats{
+			UsedPercent:       f.usedPercent,
+			InodesUsedPercent: f.inodesUsedPercent,
+		},
+	}
+}
+
+// fakeGCTrigger counts the triggered garbage collections
+type fakeGCTrigger struct {
+	triggered int
+}
+
+func (f *fakeGCTrigger) Trigger() {
+	f.triggered++
+}
+
+func TestDiskWatcher_Check(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	collector := &fakeDiskStatsCollector{usedPercent: 50, inodesUsedPercent: 50}
+	gc := &fakeGCTrigger{}
+	var updates []bool
+	var updateErr error
+	setEligibility := func(eligible bool) error {
+		if updateErr != nil {
+			return updateErr
+		}
+		updates = append(updates, eligible)
+		return nil
+	}
+	config := &diskWatcherConfig{
+		GCDiskUsageThreshold:  80,
+		GCInodeUsageThreshold: 70,
+		IneligibleThreshold:   90,
+	}
+	w := newDiskWatcher(testlog.HCLogger(t), collector, gc, setEligibility, config, nil)
+	require.Equal(defaultDiskWatchInterval, w.config.Interval)
+
+	// No pressure
+	require.NoError(w.check())
+	require.Zero(gc.triggered)
+	require.Empty(updates)
+
+	// Inode pressure triggers GC
+	collector.inodesUsedPercent = 75
+	require.NoError(w.check())
+	require.Equal(1, gc.triggered)
+	require.Empty(updates)
+
+	// Failing to mark the node ineligible is retried on the next check
+	collector.usedPercent = 95
+	updateErr = fmt.Errorf("no servers")
+	require.Error(w.check())
+	require.Empty(updates)
+
+	updateErr = nil
+	require.NoError(w.check())
+	require.Equal([]bool{false}, updates)
+
+	// The node is only marked ineligible once
+	require.NoError(w.check())
+	require.Equal([]bool{false}, updates)
+	require.Equal(4, gc.triggered)
+
+	// The node is marked eligible once the pressure subsides
+	collector.usedPercent = 85
+	require.NoError(w.check())
+	require.Equal([]bool{false, true}, updates)
+}
+
+func TestDiskWatcher_Check_IneligibleDisabled(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	collector := &fakeDiskStatsCollector{usedPercent: 99}
+	gc := &fakeGCTrigger{}
+	setEligibility := func(bool) error {
+		t.Fatalf("unexpected eligibility update")
+		return nil
+	}
+	config := &diskWatcherConfig{
+		GCDiskUsageThreshold:  80,
+		GCInodeUsageThreshold: 70,
+	}
+	w := newDiskWatcher(testlog.HCLogger(t), collector, gc, setEligibility, config, nil)
+	require.NoError(w.check())
+	require.Equal(1, gc.triggered)
+}
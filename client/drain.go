@@ -0,0 +1,156 @@
+package client
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// DrainWaitGrace is the time a client draining on shutdown waits past the
+// drain deadline for the allocations that were stopped at the deadline to
+// exit.
+const DrainWaitGrace = time.Minute
+
+// drainQueryTime is the time a blocking query for the drain status of the
+// node waits for changes, bounding how late the drain timeout is noticed.
+const drainQueryTime = 30 * time.Second
+
+// drainSelf drains the node of the client and blocks until the drain is
+// complete, the deadline of the drain plus DrainWaitGrace passed or the client
+// is shut down. The client keeps running its allocations while it waits so
+// they are stopped as the servers migrate them.
+func (c *Client) drainSelf() error {
+	drain := c.config.DrainOnShutdown
+	c.logger.Info("draining node before shutdown", "deadline", drain.Deadline)
+
+	index, err := c.startDrain(drain.Deadline, drain.IgnoreSystemJobs)
+	if err != nil {
+		return err
+	}
+
+	timeout := time.NewTimer(drain.Deadline + DrainWaitGrace)
+	defer timeout.Stop()
+
+	nodeReq := &structs.NodeSpecificRequest{
+		NodeID:   c.NodeID(),
+		SecretID: c.secretNodeID(),
+		QueryOptions: structs.QueryOptions{
+			Region:        c.Region(),
+			AuthToken:     c.secretNodeID(),
+			MinQueryIndex: index,
+			MaxQueryTime:  drainQueryTime,
+			AllowStale:    true,
+		},
+	}
+	for {
+		// The drainer removes the drain strategy of the node once all of its
+		// allocations are migrated or stopped
+		var nodeResp structs.SingleNodeResponse
+		err := c.RPC("Node.GetNode", nodeReq, &nodeResp)
+		if err != nil {
+			c.logger.Warn("failed to query node drain status", "error", err)
+			select {
+			case <-time.After(c.retryIntv(registerRetryIntv)):
+				continue
+			case <-timeout.C:
+				return fmt.Errorf("timed out waiting for node drain to complete")
+			case <-c.shutdownCh:
+				return nil
+			}
+		}
+
+		if nodeResp.Node == nil || nodeResp.Node.DrainStrategy == nil {
+			c.logger.Info("node drain complete")
+			return nil
+		}
+		nodeReq.MinQueryIndex = nodeResp.Index
+
+		select {
+		case <-timeout.C:
+			return fmt.Errorf("timed out waiting for node drain to complete")
+		case <-c.shutdownCh:
+			return nil
+		default:
+		}
+	}
+}
+
+// startDrain drains the node of the client, authenticating with its secret ID,
+// and returns the index of the node update.
+func (c *Client) startDrain(deadline time.Duration, ignoreSystemJobs bool) (uint64, error) {
+	req := &structs.NodeUpdateDrainRequest{
+		NodeID: c.NodeID(),
+		DrainStrategy: &structs.DrainStrategy{
+			DrainSpec: structs.DrainSpec{
+				Deadline:         deadline,
+				IgnoreSystemJobs: ignoreSystemJobs,
+			},
+		},
+		WriteRequest: structs.WriteRequest{
+			Region:    c.Region(),
+			AuthToken: c.secretNodeID(),
+		},
+	}
+	var resp structs.NodeDrainUpdateResponse
+	if err := c.RPC("Node.UpdateDrain", req, &resp); err != nil {
+		return 0, fmt.Errorf("failed to drain node: %v", err)
+	}
+	return resp.NodeModifyIndex, nil
+}
+
+// handleTerminationNotice starts draining the node once the fingerprinters
+// report the termination time of its instance, if the client is configured
+// to drain on termination. The configLock must be held.
+func (c *Client) handleTerminationNotice(value string) {
+	if value == "" || c.config.DrainOnTermination == nil || c.terminationDraining {
+		return
+	}
+
+	terminationTime, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		c.logger.Warn("invalid instance termination time", "termination_time", value, "error", err)
+		return
+	}
+
+	c.terminationDraining = true
+	c.triggerNodeEvent(structs.NewNodeEvent().
+		SetSubsystem(structs.NodeEventSubsystemDrain).
+		SetMessage("Instance termination notice received, draining node").
+		AddDetail("termination_time", value))
+	go c.drainOnTermination(terminationTime)
+}
+
+// drainOnTermination drains the node ahead of the termination of its
+// instance, retrying until the drain succeeds, the termination time passes
+// or the client is shut down. The deadline of the drain is bounded by the
+// termination time so allocations are stopped before the instance is.
+func (c *Client) drainOnTermination(terminationTime time.Time) {
+	drain := c.config.DrainOnTermination
+
+	for {
+		untilTermination := time.Until(terminationTime)
+		if untilTermination <= 0 {
+			c.logger.Warn("instance terminated before the node could be drained")
+			return
+		}
+
+		deadline := drain.Deadline
+		if untilTermination < deadline {
+			deadline = untilTermination
+		}
+
+		c.logger.Info("draining node before instance termination", "termination_time", terminationTime, "deadline", deadline)
+		_, err := c.startDrain(deadline, drain.IgnoreSystemJobs)
+		if err == nil {
+			return
+		}
+		c.logger.Warn("failed to drain node before instance termination", "error", err)
+
+		select {
+		case <-time.After(c.retryIntv(registerRetryIntv)):
+		case <-c.shutdownCh:
+			return
+		}
+	}
+}
@@ -5,6 +5,7 @@ package fingerprint
 import (
 	"fmt"
 
+	"github.com/hashicorp/nomad/client/lib/cgutil"
 	cstructs "github.com/hashicorp/nomad/client/structs"
 	"github.com/opencontainers/runc/libcontainer/cgroups"
 )
@@ -16,6 +17,11 @@ const (
 // FindCgroupMountpointDir is used to find the cgroup mount point on a Linux
 // system.
 func FindCgroupMountpointDir() (string, error) {
+	// The v1 lookup ignores the unified hierarchy
+	if cgutil.UseV2 {
+		return cgutil.CgroupRoot, nil
+	}
+
 	mount, err := cgroups.FindCgroupMountpointDir()
 	if err != nil {
 		switch e := err.(type) {
@@ -51,6 +57,7 @@ func (f *CGroupFingerprint) Fingerprint(req *cstructs.FingerprintRequest, resp *
 	}
 
 	resp.AddAttribute("unique.cgroup.mountpoint", mount)
+	resp.AddAttribute("unique.cgroup.version", cgroupVersion())
 	resp.Detected = true
 
 	if f.lastState == cgroupUnavailable {
@@ -59,3 +66,11 @@ func (f *CGroupFingerprint) Fingerprint(req *cstructs.FingerprintRequest, resp *
 	f.lastState = cgroupAvailable
 	return nil
 }
+
+// cgroupVersion returns the version of the cgroup hierarchy used by the host
+func cgroupVersion() string {
+	if cgutil.UseV2 {
+		return "v2"
+	}
+	return "v1"
+}
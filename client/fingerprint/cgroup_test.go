@@ -84,6 +84,9 @@ func TestCGroupFingerprint(t *testing.T) {
 		if a, ok := response.Attributes["unique.cgroup.mountpoint"]; !ok {
 			t.Fatalf("unable to find attribute: %s", a)
 		}
+		if v := response.Attributes["unique.cgroup.version"]; v != cgroupVersion() {
+			t.Fatalf("expected cgroup version %q, got %q", cgroupVersion(), v)
+		}
 	}
 
 	{
@@ -0,0 +1,73 @@
+package fingerprint
+
+import (
+	"path/filepath"
+	"time"
+
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/client/lib/cni"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// cniInterval is the interval at which the CNI network configurations are
+// reloaded
+const cniInterval = 15 * time.Second
+
+// CNIFingerprint is used to fingerprint the CNI networks configured on the
+// client, which task groups select with their network mode, and whether the
+// plugins of bridge networks are installed.
+type CNIFingerprint struct {
+	logger log.Logger
+
+	// networks are the networks of the last fingerprint, whose attributes
+	// are removed once their configuration is
+	networks map[string]struct{}
+}
+
+// NewCNIFingerprint returns a new CNI network fingerprinter
+func NewCNIFingerprint(logger log.Logger) Fingerprint {
+	return &CNIFingerprint{
+		logger:   logger.Named("cni"),
+		networks: make(map[string]struct{}),
+	}
+}
+
+func (f *CNIFingerprint) Periodic() (bool, time.Duration) {
+	return true, cniInterval
+}
+
+func (f *CNIFingerprint) Fingerprint(req *cstructs.FingerprintRequest, resp *cstructs.FingerprintResponse) error {
+	networks, err := cni.LoadNetworks(req.Config.CNIConfigDir)
+	if err != nil {
+		// Invalid configurations are skipped
+		f.logger.Warn("failed to load CNI network configurations", "error", err)
+	}
+
+	for name := range f.networks {
+		if _, ok := networks[name]; !ok {
+			resp.RemoveAttribute(structs.CNINetworkAttributePrefix + name)
+			delete(f.networks, name)
+		}
+	}
+	for name, network := range networks {
+		version := network.CNIVersion
+		if version == "" {
+			version = "unknown"
+		}
+		resp.AddAttribute(structs.CNINetworkAttributePrefix+name, version)
+		f.networks[name] = struct{}{}
+	}
+
+	// Bridge networks are configured by the client with the plugins found in
+	// the CNI path
+	bridge := len(cni.MissingPlugins(filepath.SplitList(req.Config.CNIPath), cni.BridgePlugins)) == 0
+	if bridge {
+		resp.AddAttribute(structs.BridgeNetworkAttribute, "1")
+	} else {
+		resp.RemoveAttribute(structs.BridgeNetworkAttribute)
+	}
+
+	resp.Detected = len(networks) != 0 || bridge
+	return nil
+}
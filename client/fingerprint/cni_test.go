@@ -0,0 +1,79 @@
+package fingerprint
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/nomad/client/config"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCNIFingerprint(t *testing.T) {
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "nomadtest-cni")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+	conf := filepath.Join(dir, "10-mynet.conf")
+	require.NoError(ioutil.WriteFile(conf, []byte(`{"cniVersion": "0.4.0", "name": "mynet", "type": "bridge"}`), 0644))
+
+	f := NewCNIFingerprint(testlog.HCLogger(t))
+	fingerprint := func() *cstructs.FingerprintResponse {
+		request := &cstructs.FingerprintRequest{
+			Config: &config.Config{CNIConfigDir: dir},
+			Node:   &structs.Node{Attributes: make(map[string]string)},
+		}
+		var response cstructs.FingerprintResponse
+		require.NoError(f.Fingerprint(request, &response))
+		return &response
+	}
+
+	response := fingerprint()
+	require.True(response.Detected)
+	require.Equal("0.4.0", response.Attributes["plugins.cni.network.mynet"])
+
+	// The attributes of removed networks are removed
+	require.NoError(os.Remove(conf))
+	response = fingerprint()
+	require.False(response.Detected)
+	v, ok := response.Attributes["plugins.cni.network.mynet"]
+	require.True(ok)
+	require.Empty(v)
+	v, ok = response.Attributes["plugins.cni.bridge"]
+	require.True(ok)
+	require.Empty(v)
+}
+
+func TestCNIFingerprint_Bridge(t *testing.T) {
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "nomadtest-cni")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	f := NewCNIFingerprint(testlog.HCLogger(t))
+	request := &cstructs.FingerprintRequest{
+		Config: &config.Config{CNIPath: "/missing:" + dir},
+		Node:   &structs.Node{Attributes: make(map[string]string)},
+	}
+
+	// The bridge plugins are required
+	for _, p := range []string{"bridge", "host-local"} {
+		require.NoError(ioutil.WriteFile(filepath.Join(dir, p), nil, 0755))
+	}
+	var response cstructs.FingerprintResponse
+	require.NoError(f.Fingerprint(request, &response))
+	require.False(response.Detected)
+	require.Empty(response.Attributes["plugins.cni.bridge"])
+
+	require.NoError(ioutil.WriteFile(filepath.Join(dir, "portmap"), nil, 0755))
+	response = cstructs.FingerprintResponse{}
+	require.NoError(f.Fingerprint(request, &response))
+	require.True(response.Detected)
+	require.Equal("1", response.Attributes["plugins.cni.bridge"])
+}
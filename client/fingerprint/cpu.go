@@ -4,8 +4,10 @@ import (
 	"fmt"
 
 	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/client/lib/numa"
 	cstructs "github.com/hashicorp/nomad/client/structs"
 	"github.com/hashicorp/nomad/helper/stats"
+	"github.com/hashicorp/nomad/lib/cpuset"
 	"github.com/hashicorp/nomad/nomad/structs"
 )
 
@@ -31,8 +33,11 @@ func (f *CPUFingerprint) Fingerprint(req *cstructs.FingerprintRequest, resp *cst
 
 		resp.NodeResources = &structs.NodeResources{
 			Cpu: structs.NodeCpuResources{
-				CpuShares: int64(totalCompute),
+				CpuShares:          int64(totalCompute),
+				TotalCpuCores:      uint16(cpuset.Host().Size()),
+				ReservableCpuCores: f.reservableCores(cfg.ReservableCores),
 			},
+			NumaNodes: f.numaNodes(int64(totalCompute)),
 		}
 	}
 
@@ -75,7 +80,54 @@ func (f *CPUFingerprint) Fingerprint(req *cstructs.FingerprintRequest, resp *cst
 
 	resp.AddAttribute("cpu.totalcompute", fmt.Sprintf("%d", tt))
 	setResourcesCPU(tt)
+	if numaNodes := len(resp.NodeResources.NumaNodes); numaNodes > 0 {
+		resp.AddAttribute("cpu.numa_nodes", fmt.Sprintf("%d", numaNodes))
+	}
 	resp.Detected = true
 
 	return nil
 }
+
+// reservableCores returns the cores that may be dedicated to tasks. The
+// configuration is validated when the client is created.
+func (f *CPUFingerprint) reservableCores(cores string) []uint16 {
+	reservable, err := cpuset.Parse(cores)
+	if err != nil {
+		f.logger.Warn("failed to parse reservable cores", "reservable_cores", cores, "error", err)
+		return nil
+	}
+	if reservable.Size() == 0 {
+		return nil
+	}
+	return reservable.ToSlice()
+}
+
+// numaNodes returns the NUMA topology of the host. The total compute is
+// divided between the NUMA nodes in proportion to their cores.
+func (f *CPUFingerprint) numaNodes(totalCompute int64) []*structs.NodeNumaNode {
+	nodes, err := numa.Scan()
+	if err != nil {
+		f.logger.Warn("failed to detect NUMA topology", "error", err)
+		return nil
+	}
+	if len(nodes) == 0 {
+		return nil
+	}
+
+	cores := cpuset.New()
+	for _, node := range nodes {
+		cores = cores.Union(node.Cores)
+	}
+
+	numaNodes := make([]*structs.NodeNumaNode, len(nodes))
+	for i, node := range nodes {
+		numaNodes[i] = &structs.NodeNumaNode{
+			ID:        node.ID,
+			Cores:     node.Cores.String(),
+			CpuShares: totalCompute * int64(node.Cores.Size()) / int64(cores.Size()),
+			MemoryMB:  node.MemoryMB,
+		}
+		f.logger.Debug("detected NUMA node", "id", node.ID, "cores", numaNodes[i].Cores, "memory_mb", node.MemoryMB)
+	}
+	return numaNodes
+}
@@ -0,0 +1,309 @@
+package fingerprint
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	cleanhttp "github.com/hashicorp/go-cleanhttp"
+	log "github.com/hashicorp/go-hclog"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/helper/useragent"
+)
+
+const (
+	// DEFAULT_AZURE_URL is where the Azure metadata server normally resides.
+	DEFAULT_AZURE_URL = "http://169.254.169.254/metadata/"
+
+	// AzureMetadataTimeout is the timeout used when contacting the Azure
+	// metadata service
+	AzureMetadataTimeout = 2 * time.Second
+
+	// TerminationCheckInterval is the interval at which the termination
+	// fingerprinters poll the metadata service for a termination notice.
+	TerminationCheckInterval = 5 * time.Second
+
+	// PreemptibleAttribute is set on the nodes running on spot or preemptible
+	// instances, which the cloud provider may terminate at any time.
+	PreemptibleAttribute = "platform.preemptible"
+
+	// TerminationTimeAttribute is set to the time, in RFC3339 format, at
+	// which the cloud provider terminates the instance of the node once it
+	// issued a termination notice.
+	TerminationTimeAttribute = "unique.platform.termination-time"
+
+	// gceTerminationNotice and azureTerminationNotice are the times GCE and
+	// Azure give preemptible instances between the notice and their
+	// termination when the notice doesn't include it.
+	gceTerminationNotice   = 30 * time.Second
+	azureTerminationNotice = 30 * time.Second
+)
+
+// terminationNotifier checks the metadata service of a cloud provider for
+// the termination notice of a spot or preemptible instance.
+type terminationNotifier interface {
+	// preemptible returns whether the instance may be terminated by the
+	// cloud provider.
+	preemptible(client *http.Client) bool
+
+	// notice returns the time the instance is terminated at and whether a
+	// termination notice was issued.
+	notice(client *http.Client) (time.Time, bool, error)
+}
+
+// TerminationFingerprint is used to fingerprint the imminent termination of
+// spot and preemptible instances. It polls the metadata service of the cloud
+// provider for a termination notice and reports the termination time, which
+// the client drains the node ahead of.
+type TerminationFingerprint struct {
+	client   *http.Client
+	notifier terminationNotifier
+	logger   log.Logger
+
+	// detected is whether the instance is preemptible, checked on the first
+	// fingerprint
+	detected *bool
+
+	// terminationTime is the termination time of the first notice, kept as
+	// notices without a time would otherwise move it on every check
+	terminationTime time.Time
+}
+
+func newTerminationFingerprint(logger log.Logger, name string, timeout time.Duration, notifier terminationNotifier) Fingerprint {
+	return &TerminationFingerprint{
+		client: &http.Client{
+			Timeout:   timeout,
+			Transport: cleanhttp.DefaultTransport(),
+		},
+		notifier: notifier,
+		logger:   logger.Named(name),
+	}
+}
+
+// NewEnvAWSSpotFingerprint is used to create a fingerprint of the spot
+// instance termination notices of AWS
+func NewEnvAWSSpotFingerprint(logger log.Logger) Fingerprint {
+	metadataURL := os.Getenv("AWS_ENV_URL")
+	if metadataURL == "" {
+		metadataURL = DEFAULT_AWS_URL
+	}
+	return newTerminationFingerprint(logger, "env_aws_spot", AwsMetadataTimeout, &awsSpotNotifier{metadataURL})
+}
+
+// NewEnvGCEPreemptibleFingerprint is used to create a fingerprint of the
+// preemption notices of GCE
+func NewEnvGCEPreemptibleFingerprint(logger log.Logger) Fingerprint {
+	metadataURL := os.Getenv("GCE_ENV_URL")
+	if metadataURL == "" {
+		metadataURL = DEFAULT_GCE_URL
+	}
+	return newTerminationFingerprint(logger, "env_gce_preemptible", GceMetadataTimeout, &gcePreemptibleNotifier{metadataURL})
+}
+
+// NewEnvAzureSpotFingerprint is used to create a fingerprint of the
+// preemption scheduled events of Azure
+func NewEnvAzureSpotFingerprint(logger log.Logger) Fingerprint {
+	metadataURL := os.Getenv("AZURE_ENV_URL")
+	if metadataURL == "" {
+		metadataURL = DEFAULT_AZURE_URL
+	}
+	return newTerminationFingerprint(logger, "env_azure_spot", AzureMetadataTimeout, &azureSpotNotifier{metadataURL: metadataURL})
+}
+
+func (f *TerminationFingerprint) Periodic() (bool, time.Duration) {
+	return true, TerminationCheckInterval
+}
+
+func (f *TerminationFingerprint) Fingerprint(req *cstructs.FingerprintRequest, resp *cstructs.FingerprintResponse) error {
+	if f.detected == nil {
+		// Check if we should tighten the timeout
+		if req.Config.ReadBoolDefault(TightenNetworkTimeoutsConfig, false) {
+			f.client.Timeout = 1 * time.Millisecond
+		}
+
+		detected := f.notifier.preemptible(f.client)
+		f.detected = &detected
+	}
+	if !*f.detected {
+		return nil
+	}
+
+	resp.Detected = true
+	resp.AddAttribute(PreemptibleAttribute, "true")
+
+	if !f.terminationTime.IsZero() {
+		resp.AddAttribute(TerminationTimeAttribute, f.terminationTime.Format(time.RFC3339))
+		return nil
+	}
+
+	terminationTime, ok, err := f.notifier.notice(f.client)
+	if err != nil {
+		// Keep the attributes as they are until the next check
+		f.logger.Debug("error checking termination notice", "error", err)
+		return nil
+	}
+	if !ok {
+		resp.RemoveAttribute(TerminationTimeAttribute)
+		return nil
+	}
+
+	f.terminationTime = terminationTime.UTC()
+	f.logger.Warn("instance termination notice received", "termination_time", f.terminationTime)
+	resp.AddAttribute(TerminationTimeAttribute, f.terminationTime.Format(time.RFC3339))
+	return nil
+}
+
+// getMetadata returns the body of a metadata endpoint and whether it exists.
+func getMetadata(client *http.Client, url string, header http.Header) (string, bool, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return "", false, err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.Header.Set("User-Agent", useragent.String())
+
+	res, err := client.Do(req)
+	if err != nil {
+		return "", false, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return "", false, nil
+	}
+	if res.StatusCode != http.StatusOK {
+		return "", false, ReqError{res.StatusCode}
+	}
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return "", false, err
+	}
+	return strings.TrimSpace(string(body)), true, nil
+}
+
+// awsSpotNotifier checks the instance action of AWS spot instances, set two
+// minutes before the instance is stopped or terminated.
+type awsSpotNotifier struct {
+	metadataURL string
+}
+
+func (n *awsSpotNotifier) preemptible(client *http.Client) bool {
+	lifecycle, ok, err := getMetadata(client, n.metadataURL+"instance-life-cycle", nil)
+	return err == nil && ok && lifecycle == "spot"
+}
+
+func (n *awsSpotNotifier) notice(client *http.Client) (time.Time, bool, error) {
+	body, ok, err := getMetadata(client, n.metadataURL+"spot/instance-action", nil)
+	if err != nil || !ok {
+		return time.Time{}, false, err
+	}
+
+	var action struct {
+		Action string `json:"action"`
+		Time   string `json:"time"`
+	}
+	if err := json.Unmarshal([]byte(body), &action); err != nil {
+		return time.Time{}, false, fmt.Errorf("failed to decode spot instance action: %v", err)
+	}
+	t, err := time.Parse(time.RFC3339, action.Time)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("failed to parse spot instance action time: %v", err)
+	}
+	return t, true, nil
+}
+
+// gcePreemptibleNotifier checks the preempted flag of GCE preemptible
+// instances, set 30 seconds before the instance is stopped.
+type gcePreemptibleNotifier struct {
+	metadataURL string
+}
+
+var gceMetadataHeader = http.Header{"Metadata-Flavor": []string{"Google"}}
+
+func (n *gcePreemptibleNotifier) preemptible(client *http.Client) bool {
+	preemptible, ok, err := getMetadata(client, n.metadataURL+"scheduling/preemptible", gceMetadataHeader)
+	return err == nil && ok && preemptible == "TRUE"
+}
+
+func (n *gcePreemptibleNotifier) notice(client *http.Client) (time.Time, bool, error) {
+	preempted, ok, err := getMetadata(client, n.metadataURL+"preempted", gceMetadataHeader)
+	if err != nil || !ok || preempted != "TRUE" {
+		return time.Time{}, false, err
+	}
+	return time.Now().Add(gceTerminationNotice), true, nil
+}
+
+// azureSpotNotifier checks the scheduled events of Azure spot instances for
+// their preemption, scheduled at least 30 seconds ahead.
+type azureSpotNotifier struct {
+	metadataURL string
+
+	// name is the name of the instance, which the scheduled events refer to
+	name string
+}
+
+var azureMetadataHeader = http.Header{"Metadata": []string{"true"}}
+
+func (n *azureSpotNotifier) preemptible(client *http.Client) bool {
+	priority, ok, err := getMetadata(client, n.metadataURL+"instance/compute/priority?api-version=2019-06-01&format=text", azureMetadataHeader)
+	if err != nil || !ok || (priority != "Spot" && priority != "Low") {
+		return false
+	}
+
+	name, _, err := getMetadata(client, n.metadataURL+"instance/compute/name?api-version=2019-06-01&format=text", azureMetadataHeader)
+	if err != nil {
+		return false
+	}
+	n.name = name
+	return true
+}
+
+func (n *azureSpotNotifier) notice(client *http.Client) (time.Time, bool, error) {
+	body, ok, err := getMetadata(client, n.metadataURL+"scheduledevents?api-version=2019-08-01", azureMetadataHeader)
+	if err != nil || !ok {
+		return time.Time{}, false, err
+	}
+
+	var events struct {
+		Events []struct {
+			EventType string
+			Resources []string
+			NotBefore string
+		}
+	}
+	if err := json.Unmarshal([]byte(body), &events); err != nil {
+		return time.Time{}, false, fmt.Errorf("failed to decode scheduled events: %v", err)
+	}
+
+	for _, e := range events.Events {
+		if e.EventType != "Preempt" || !n.isResource(e.Resources) {
+			continue
+		}
+		if t, err := time.Parse(time.RFC1123, e.NotBefore); err == nil {
+			return t, true, nil
+		}
+		return time.Now().Add(azureTerminationNotice), true, nil
+	}
+	return time.Time{}, false, nil
+}
+
+// isResource returns whether the resources of a scheduled event include the
+// instance.
+func (n *azureSpotNotifier) isResource(resources []string) bool {
+	if len(resources) == 0 || n.name == "" {
+		return true
+	}
+	for _, r := range resources {
+		if r == n.name {
+			return true
+		}
+	}
+	return false
+}
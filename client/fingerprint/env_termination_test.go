@@ -0,0 +1,157 @@
+package fingerprint
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/client/config"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+// testMetadata is a metadata server whose routes can be changed while it
+// runs.
+type testMetadata struct {
+	routes map[string]string
+	l      sync.Mutex
+}
+
+func (m *testMetadata) set(uri, body string) {
+	m.l.Lock()
+	defer m.l.Unlock()
+	m.routes[uri] = body
+}
+
+func (m *testMetadata) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.l.Lock()
+	body, ok := m.routes[r.URL.RequestURI()]
+	m.l.Unlock()
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	fmt.Fprintln(w, body)
+}
+
+func testMetadataServer(routes map[string]string) (*testMetadata, *httptest.Server) {
+	m := &testMetadata{routes: routes}
+	return m, httptest.NewServer(m)
+}
+
+func testTerminationFingerprint(t *testing.T, f Fingerprint) *cstructs.FingerprintResponse {
+	request := &cstructs.FingerprintRequest{
+		Config: &config.Config{},
+		Node:   &structs.Node{Attributes: make(map[string]string)},
+	}
+	var response cstructs.FingerprintResponse
+	require.NoError(t, f.Fingerprint(request, &response))
+	return &response
+}
+
+func TestTerminationFingerprint_AWS(t *testing.T) {
+	require := require.New(t)
+
+	routes := map[string]string{
+		"/latest/meta-data/instance-life-cycle": "spot",
+	}
+	m, ts := testMetadataServer(routes)
+	defer ts.Close()
+	os.Setenv("AWS_ENV_URL", ts.URL+"/latest/meta-data/")
+	defer os.Unsetenv("AWS_ENV_URL")
+
+	f := NewEnvAWSSpotFingerprint(testlog.HCLogger(t))
+	periodic, interval := f.Periodic()
+	require.True(periodic)
+	require.Equal(TerminationCheckInterval, interval)
+
+	// A spot instance without a notice
+	response := testTerminationFingerprint(t, f)
+	require.True(response.Detected)
+	require.Equal("true", response.Attributes[PreemptibleAttribute])
+	v, ok := response.Attributes[TerminationTimeAttribute]
+	require.True(ok)
+	require.Empty(v)
+
+	// A spot instance with a notice
+	m.set("/latest/meta-data/spot/instance-action", `{"action": "terminate", "time": "2019-05-01T10:02:00Z"}`)
+	response = testTerminationFingerprint(t, f)
+	require.Equal("2019-05-01T10:02:00Z", response.Attributes[TerminationTimeAttribute])
+}
+
+func TestTerminationFingerprint_AWS_OnDemand(t *testing.T) {
+	_, ts := testMetadataServer(map[string]string{
+		"/latest/meta-data/instance-life-cycle":  "on-demand",
+		"/latest/meta-data/spot/instance-action": `{"action": "terminate", "time": "2019-05-01T10:02:00Z"}`,
+	})
+	defer ts.Close()
+	os.Setenv("AWS_ENV_URL", ts.URL+"/latest/meta-data/")
+	defer os.Unsetenv("AWS_ENV_URL")
+
+	response := testTerminationFingerprint(t, NewEnvAWSSpotFingerprint(testlog.HCLogger(t)))
+	require.False(t, response.Detected)
+	require.Empty(t, response.Attributes)
+}
+
+func TestTerminationFingerprint_GCE(t *testing.T) {
+	require := require.New(t)
+
+	routes := map[string]string{
+		"/computeMetadata/v1/instance/scheduling/preemptible": "TRUE",
+		"/computeMetadata/v1/instance/preempted":              "FALSE",
+	}
+	m, ts := testMetadataServer(routes)
+	defer ts.Close()
+	os.Setenv("GCE_ENV_URL", ts.URL+"/computeMetadata/v1/instance/")
+	defer os.Unsetenv("GCE_ENV_URL")
+
+	f := NewEnvGCEPreemptibleFingerprint(testlog.HCLogger(t))
+	response := testTerminationFingerprint(t, f)
+	require.True(response.Detected)
+	require.Empty(response.Attributes[TerminationTimeAttribute])
+
+	// The termination time of the first notice is kept
+	m.set("/computeMetadata/v1/instance/preempted", "TRUE")
+	response = testTerminationFingerprint(t, f)
+	first := response.Attributes[TerminationTimeAttribute]
+	terminationTime, err := time.Parse(time.RFC3339, first)
+	require.NoError(err)
+	require.WithinDuration(time.Now().Add(gceTerminationNotice), terminationTime, 5*time.Second)
+
+	response = testTerminationFingerprint(t, f)
+	require.Equal(first, response.Attributes[TerminationTimeAttribute])
+}
+
+func TestTerminationFingerprint_Azure(t *testing.T) {
+	require := require.New(t)
+
+	routes := map[string]string{
+		"/metadata/instance/compute/priority?api-version=2019-06-01&format=text": "Spot",
+		"/metadata/instance/compute/name?api-version=2019-06-01&format=text":     "vm1",
+		"/metadata/scheduledevents?api-version=2019-08-01":                       `{"Events": []}`,
+	}
+	m, ts := testMetadataServer(routes)
+	defer ts.Close()
+	os.Setenv("AZURE_ENV_URL", ts.URL+"/metadata/")
+	defer os.Unsetenv("AZURE_ENV_URL")
+
+	f := NewEnvAzureSpotFingerprint(testlog.HCLogger(t))
+	response := testTerminationFingerprint(t, f)
+	require.True(response.Detected)
+	require.Empty(response.Attributes[TerminationTimeAttribute])
+
+	// Events of other instances are ignored
+	m.set("/metadata/scheduledevents?api-version=2019-08-01", `{"Events": [{"EventType": "Preempt", "Resources": ["vm2"], "NotBefore": "Wed, 01 May 2019 10:02:00 GMT"}]}`)
+	response = testTerminationFingerprint(t, f)
+	require.Empty(response.Attributes[TerminationTimeAttribute])
+
+	m.set("/metadata/scheduledevents?api-version=2019-08-01", `{"Events": [{"EventType": "Preempt", "Resources": ["vm1"], "NotBefore": "Wed, 01 May 2019 10:02:00 GMT"}]}`)
+	response = testTerminationFingerprint(t, f)
+	require.Equal("2019-05-01T10:02:00Z", response.Attributes[TerminationTimeAttribute])
+}
@@ -0,0 +1,138 @@
+package fingerprint
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"time"
+
+	log "github.com/hashicorp/go-hclog"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+)
+
+// ExternalFingerprintOutput is the JSON object an external fingerprinter
+// writes to its standard output.
+type ExternalFingerprintOutput struct {
+	// Attributes are the node attributes detected. They are prefixed with the
+	// name of the fingerprinter.
+	Attributes map[string]string `json:"attributes"`
+
+	// Resources override the resources of the node when set.
+	Resources *ExternalFingerprintResources `json:"resources"`
+}
+
+// ExternalFingerprintResources are the resources an external fingerprinter
+// may report. Zero values leave the resource detected by the client as is.
+type ExternalFingerprintResources struct {
+	CPU      int `json:"cpu"`
+	MemoryMB int `json:"memory_mb"`
+	DiskMB   int `json:"disk_mb"`
+}
+
+// ExternalFingerprint runs a command configured by the operator and turns its
+// output into node attributes and resources, so that sites can expose facts
+// the built-in fingerprinters don't detect.
+type ExternalFingerprint struct {
+	logger log.Logger
+	config *config.FingerprinterConfig
+
+	// lastAttributes are the attributes reported by the previous run, which
+	// are removed from the node when the command stops reporting them.
+	lastAttributes map[string]struct{}
+}
+
+// NewExternalFingerprint is used to create a fingerprint running the command
+// of the given fingerprinter.
+func NewExternalFingerprint(c *config.FingerprinterConfig, logger log.Logger) Fingerprint {
+	return &ExternalFingerprint{
+		logger: logger.Named("external").With("fingerprinter", c.Name),
+		config: c,
+	}
+}
+
+func (f *ExternalFingerprint) Fingerprint(req *cstructs.FingerprintRequest, resp *cstructs.FingerprintResponse) error {
+	out, err := f.run()
+	if err != nil {
+		// A failing command must not prevent the client from starting, so
+		// the error is logged and the attributes it reported are removed.
+		f.logger.Warn("external fingerprinter failed", "error", err)
+		for attr := range f.lastAttributes {
+			resp.RemoveAttribute(attr)
+		}
+		f.lastAttributes = nil
+		return nil
+	}
+
+	attrs := make(map[string]struct{}, len(out.Attributes))
+	for k, v := range out.Attributes {
+		attr := f.config.Name + "." + k
+		resp.AddAttribute(attr, v)
+		attrs[attr] = struct{}{}
+	}
+	for attr := range f.lastAttributes {
+		if _, ok := attrs[attr]; !ok {
+			resp.RemoveAttribute(attr)
+		}
+	}
+	f.lastAttributes = attrs
+
+	if r := out.Resources; r != nil {
+		resp.Resources = &structs.Resources{
+			CPU:      r.CPU,
+			MemoryMB: r.MemoryMB,
+			DiskMB:   r.DiskMB,
+		}
+		resp.NodeResources = &structs.NodeResources{
+			Cpu: structs.NodeCpuResources{
+				CpuShares: int64(r.CPU),
+			},
+			Memory: structs.NodeMemoryResources{
+				MemoryMB: int64(r.MemoryMB),
+			},
+			Disk: structs.NodeDiskResources{
+				DiskMB: int64(r.DiskMB),
+			},
+		}
+		if r.CPU > 0 {
+			resp.AddAttribute("cpu.totalcompute", strconv.Itoa(r.CPU))
+		}
+		if r.MemoryMB > 0 {
+			resp.AddAttribute("memory.totalbytes", strconv.FormatInt(int64(r.MemoryMB)*1024*1024, 10))
+		}
+	}
+
+	resp.Detected = true
+	return nil
+}
+
+// run runs the command and decodes its output.
+func (f *ExternalFingerprint) run() (*ExternalFingerprintOutput, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), f.config.Timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, f.config.Command, f.config.Args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("command timed out after %v", f.config.Timeout)
+		}
+		return nil, fmt.Errorf("command failed: %v: %s", err, bytes.TrimSpace(stderr.Bytes()))
+	}
+
+	var out ExternalFingerprintOutput
+	if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+		return nil, fmt.Errorf("failed to decode output: %v", err)
+	}
+	return &out, nil
+}
+
+func (f *ExternalFingerprint) Periodic() (bool, time.Duration) {
+	return true, f.config.Interval
+}
@@ -0,0 +1,73 @@
+package fingerprint
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/client/config"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/structs"
+	nconfig "github.com/hashicorp/nomad/nomad/structs/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExternalFingerprint(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("external fingerprinter test requires a shell")
+	}
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "nomad-external-fingerprint")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	// The script prints the output file, so that the test can change it
+	outFile := filepath.Join(dir, "out.json")
+	script := filepath.Join(dir, "fingerprint.sh")
+	require.NoError(ioutil.WriteFile(script, []byte("#!/bin/sh\ncat "+outFile+"\n"), 0755))
+
+	c := &nconfig.FingerprinterConfig{Name: "raid", Command: script}
+	c.Canonicalize()
+	fp := NewExternalFingerprint(c, testlog.HCLogger(t))
+
+	periodic, interval := fp.Periodic()
+	require.True(periodic)
+	require.Equal(time.Minute, interval)
+
+	fingerprint := func() *cstructs.FingerprintResponse {
+		request := &cstructs.FingerprintRequest{Config: new(config.Config), Node: new(structs.Node)}
+		var response cstructs.FingerprintResponse
+		require.NoError(fp.Fingerprint(request, &response))
+		return &response
+	}
+
+	// Attributes are prefixed and resources are reported
+	require.NoError(ioutil.WriteFile(outFile, []byte(`{
+		"attributes": {"healthy": "true", "disks": "4"},
+		"resources": {"disk_mb": 2048}
+	}`), 0644))
+	response := fingerprint()
+	require.True(response.Detected)
+	assertNodeAttributeEquals(t, response.Attributes, "raid.healthy", "true")
+	assertNodeAttributeEquals(t, response.Attributes, "raid.disks", "4")
+	require.EqualValues(2048, response.NodeResources.Disk.DiskMB)
+	require.Zero(response.NodeResources.Cpu.CpuShares)
+
+	// Attributes no longer reported are removed
+	require.NoError(ioutil.WriteFile(outFile, []byte(`{"attributes": {"healthy": "false"}}`), 0644))
+	response = fingerprint()
+	assertNodeAttributeEquals(t, response.Attributes, "raid.healthy", "false")
+	assertNodeAttributeEquals(t, response.Attributes, "raid.disks", "")
+	require.Nil(response.NodeResources)
+
+	// A failing command removes all its attributes without failing
+	require.NoError(ioutil.WriteFile(outFile, []byte(`not json`), 0644))
+	response = fingerprint()
+	require.False(response.Detected)
+	assertNodeAttributeEquals(t, response.Attributes, "raid.healthy", "")
+}
@@ -45,8 +45,11 @@ var (
 	// This should run after the host fingerprinters as they may override specific
 	// node resources with more detailed information.
 	envFingerprinters = map[string]Factory{
-		"env_aws": NewEnvAWSFingerprint,
-		"env_gce": NewEnvGCEFingerprint,
+		"env_aws":             NewEnvAWSFingerprint,
+		"env_aws_spot":        NewEnvAWSSpotFingerprint,
+		"env_azure_spot":      NewEnvAzureSpotFingerprint,
+		"env_gce":             NewEnvGCEFingerprint,
+		"env_gce_preemptible": NewEnvGCEPreemptibleFingerprint,
 	}
 )
 
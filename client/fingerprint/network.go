@@ -3,6 +3,7 @@ package fingerprint
 import (
 	"fmt"
 	"net"
+	"sort"
 
 	log "github.com/hashicorp/go-hclog"
 	sockaddr "github.com/hashicorp/go-sockaddr"
@@ -108,6 +109,17 @@ func (f *NetworkFingerprint) Fingerprint(req *cstructs.FingerprintRequest, resp
 		f.logger.Debug("detected interface IP", "interface", intf.Name, "IP", nwResource.IP)
 	}
 
+	// Resolve the host networks of the client
+	hostNetworks, err := f.hostNetworks(cfg.HostNetworks)
+	if err != nil {
+		return fmt.Errorf("Error while detecting host networks during fingerprinting: %v", err)
+	}
+	for _, n := range hostNetworks {
+		f.logger.Debug("detected host network", "name", n.Name, "interface", n.Device, "IP", n.IP)
+		resp.AddAttribute(structs.HostNetworkAttributePrefix+n.Name, n.Device)
+	}
+	resp.NodeResources.HostNetworks = hostNetworks
+
 	// Deprecated, setting the first IP as unique IP for the node
 	if len(nwResources) > 0 {
 		resp.AddAttribute("unique.network.ip-address", nwResources[0].IP)
@@ -173,6 +185,105 @@ func (f *NetworkFingerprint) createNetworkResources(throughput int, intf *net.In
 	return nwResources, nil
 }
 
+// hostNetworks resolves the host networks of the client to the first address
+// of their interface, or of any interface that is up if unset, within their
+// CIDR block. Host networks that can't be resolved are skipped so jobs
+// requiring them aren't placed on the node.
+func (f *NetworkFingerprint) hostNetworks(configs map[string]*structs.ClientHostNetworkConfig) ([]*structs.NodeHostNetwork, error) {
+	if len(configs) == 0 {
+		return nil, nil
+	}
+
+	names := make([]string, 0, len(configs))
+	for name := range configs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var all []net.Interface
+	hostNetworks := make([]*structs.NodeHostNetwork, 0, len(configs))
+	for _, name := range names {
+		cfg := configs[name]
+
+		var cidr *net.IPNet
+		if cfg.CIDR != "" {
+			var err error
+			if _, cidr, err = net.ParseCIDR(cfg.CIDR); err != nil {
+				return nil, fmt.Errorf("host network %q has an invalid cidr: %v", name, err)
+			}
+		}
+
+		var intfs []net.Interface
+		if cfg.Interface != "" {
+			intf, err := f.interfaceDetector.InterfaceByName(cfg.Interface)
+			if err != nil {
+				f.logger.Warn("failed to find interface of host network", "name", name, "interface", cfg.Interface, "error", err)
+				continue
+			}
+			intfs = []net.Interface{*intf}
+		} else {
+			if all == nil {
+				var err error
+				if all, err = f.interfaceDetector.Interfaces(); err != nil {
+					return nil, err
+				}
+			}
+			for _, intf := range all {
+				if intf.Flags&net.FlagUp != 0 {
+					intfs = append(intfs, intf)
+				}
+			}
+		}
+
+		hostNetwork := f.resolveHostNetwork(intfs, cidr)
+		if hostNetwork == nil {
+			f.logger.Warn("failed to find address of host network", "name", name, "interface", cfg.Interface, "cidr", cfg.CIDR)
+			continue
+		}
+		hostNetwork.Name = name
+		hostNetwork.ReservedPorts = cfg.ReservedPorts
+		hostNetworks = append(hostNetworks, hostNetwork)
+	}
+
+	return hostNetworks, nil
+}
+
+// resolveHostNetwork returns the first address of the interfaces within the
+// CIDR block, if given, or nil if there is none.
+func (f *NetworkFingerprint) resolveHostNetwork(intfs []net.Interface, cidr *net.IPNet) *structs.NodeHostNetwork {
+	for i := range intfs {
+		intf := &intfs[i]
+		addrs, err := f.interfaceDetector.Addrs(intf)
+		if err != nil {
+			f.logger.Debug("failed to get addresses of interface", "interface", intf.Name, "error", err)
+			continue
+		}
+
+		for _, addr := range addrs {
+			var ip net.IP
+			switch v := (addr).(type) {
+			case *net.IPNet:
+				ip = v.IP
+			case *net.IPAddr:
+				ip = v.IP
+			}
+
+			if ip == nil || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() {
+				continue
+			}
+			if cidr != nil && !cidr.Contains(ip) {
+				continue
+			}
+
+			return &structs.NodeHostNetwork{
+				Device: intf.Name,
+				IP:     ip.String(),
+			}
+		}
+	}
+	return nil
+}
+
 // Returns the interface with the name passed by user. If the name is blank, we
 // use the interface attached to the default route.
 func (f *NetworkFingerprint) findInterface(deviceName string) (*net.Interface, error) {
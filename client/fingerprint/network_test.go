@@ -10,6 +10,7 @@ import (
 	cstructs "github.com/hashicorp/nomad/client/structs"
 	"github.com/hashicorp/nomad/helper/testlog"
 	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
 )
 
 // Set skipOnlineTestEnvVar to a non-empty value to skip network tests.  Useful
@@ -438,3 +439,41 @@ func TestNetworkFingerPrint_LinkLocal_Disallowed(t *testing.T) {
 		t.Fatalf("should not apply attributes")
 	}
 }
+
+func TestNetworkFingerPrint_HostNetworks(t *testing.T) {
+	require := require.New(t)
+
+	f := &NetworkFingerprint{logger: testlog.HCLogger(t), interfaceDetector: &NetworkInterfaceDetectorMultipleInterfaces{}}
+	node := &structs.Node{
+		Attributes: make(map[string]string),
+	}
+	cfg := &config.Config{
+		NetworkSpeed:     100,
+		NetworkInterface: "eth0",
+		HostNetworks: map[string]*structs.ClientHostNetworkConfig{
+			// Resolved on the interface even if it is down
+			"public": {Name: "public", Interface: "eth1", CIDR: "2003:db8::/32", ReservedPorts: "22"},
+			// Resolved on the first interface that is up within the CIDR
+			"private": {Name: "private", CIDR: "100.64.0.0/10"},
+			// No address within the CIDR
+			"missing": {Name: "missing", CIDR: "10.0.0.0/8"},
+			// No such interface
+			"other": {Name: "other", Interface: "eth9"},
+		},
+	}
+
+	request := &cstructs.FingerprintRequest{Config: cfg, Node: node}
+	var response cstructs.FingerprintResponse
+	require.NoError(f.Fingerprint(request, &response))
+	require.True(response.Detected)
+
+	require.Equal([]*structs.NodeHostNetwork{
+		{Name: "private", Device: "eth0", IP: "100.64.0.0"},
+		{Name: "public", Device: "eth1", IP: "2003:db8::", ReservedPorts: "22"},
+	}, response.NodeResources.HostNetworks)
+
+	require.Equal("eth0", response.Attributes[structs.HostNetworkAttributePrefix+"private"])
+	require.Equal("eth1", response.Attributes[structs.HostNetworkAttributePrefix+"public"])
+	require.NotContains(response.Attributes, structs.HostNetworkAttributePrefix+"missing")
+	require.NotContains(response.Attributes, structs.HostNetworkAttributePrefix+"other")
+}
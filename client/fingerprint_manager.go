@@ -11,6 +11,7 @@ import (
 	"github.com/hashicorp/nomad/client/fingerprint"
 	cstructs "github.com/hashicorp/nomad/client/structs"
 	"github.com/hashicorp/nomad/nomad/structs"
+	nconfig "github.com/hashicorp/nomad/nomad/structs/config"
 	"github.com/hashicorp/nomad/plugins/base"
 	"github.com/hashicorp/nomad/plugins/drivers"
 	"github.com/hashicorp/nomad/plugins/shared/loader"
@@ -116,6 +117,10 @@ func (fp *FingerprintManager) Run() error {
 		return err
 	}
 
+	if err := fp.setupExternalFingerprinters(cfg.Fingerprinters); err != nil {
+		return err
+	}
+
 	if len(skippedFingerprints) != 0 {
 		fp.logger.Debug("fingerprint modules skipped due to white/blacklist",
 			"skipped_fingerprinters", skippedFingerprints)
@@ -190,6 +195,28 @@ func (fm *FingerprintManager) setupFingerprinters(fingerprints []string) error {
 	return nil
 }
 
+// setupExternalFingerprinters runs the external fingerprinters of the
+// configuration once and then periodically
+func (fm *FingerprintManager) setupExternalFingerprinters(fingerprinters []*nconfig.FingerprinterConfig) error {
+	for _, c := range fingerprinters {
+		c = c.Copy()
+		c.Canonicalize()
+		if err := c.Validate(); err != nil {
+			return err
+		}
+
+		name := "external." + c.Name
+		f := fingerprint.NewExternalFingerprint(c, fm.logger)
+		if _, err := fm.fingerprint(name, f); err != nil {
+			return err
+		}
+
+		_, period := f.Periodic()
+		go fm.runFingerprint(f, period, name)
+	}
+	return nil
+}
+
 // setupDrivers is used to fingerprint the node to see if these drivers are
 // supported
 func (fm *FingerprintManager) setupDrivers(driverNames []string) error {
@@ -3,6 +3,7 @@ package client
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"math"
@@ -32,6 +33,7 @@ var (
 	taskNotPresentErr    = fmt.Errorf("must provide task name")
 	logTypeNotPresentErr = fmt.Errorf("must provide log type (stdout/stderr)")
 	invalidOrigin        = fmt.Errorf("origin must be start or end")
+	invalidLogFormat     = fmt.Errorf("format must be frames, json or plain")
 )
 
 const (
@@ -84,7 +86,7 @@ func NewFileSystemEndpoint(c *Client) *FileSystem {
 // handleStreamResultError is a helper for sending an error with a potential
 // error code. The transmission of the error is ignored if the error has been
 // generated by the closing of the underlying transport.
-func (f *FileSystem) handleStreamResultError(err error, code *int64, encoder *codec.Encoder) {
+func handleStreamResultError(err error, code *int64, encoder *codec.Encoder) {
 	// Nothing to do as the conn is closed
 	if err == io.EOF || strings.Contains(err.Error(), "closed") {
 		return
@@ -155,26 +157,26 @@ func (f *FileSystem) stream(conn io.ReadWriteCloser) {
 	encoder := codec.NewEncoder(conn, structs.MsgpackHandle)
 
 	if err := decoder.Decode(&req); err != nil {
-		f.handleStreamResultError(err, helper.Int64ToPtr(500), encoder)
+		handleStreamResultError(err, helper.Int64ToPtr(500), encoder)
 		return
 	}
 
 	// Check read permissions
 	if aclObj, err := f.c.ResolveToken(req.QueryOptions.AuthToken); err != nil {
-		f.handleStreamResultError(err, nil, encoder)
+		handleStreamResultError(err, nil, encoder)
 		return
 	} else if aclObj != nil && !aclObj.AllowNsOp(req.Namespace, acl.NamespaceCapabilityReadFS) {
-		f.handleStreamResultError(structs.ErrPermissionDenied, nil, encoder)
+		handleStreamResultError(structs.ErrPermissionDenied, nil, encoder)
 		return
 	}
 
 	// Validate the arguments
 	if req.AllocID == "" {
-		f.handleStreamResultError(allocIDNotPresentErr, helper.Int64ToPtr(400), encoder)
+		handleStreamResultError(allocIDNotPresentErr, helper.Int64ToPtr(400), encoder)
 		return
 	}
 	if req.Path == "" {
-		f.handleStreamResultError(pathNotPresentErr, helper.Int64ToPtr(400), encoder)
+		handleStreamResultError(pathNotPresentErr, helper.Int64ToPtr(400), encoder)
 		return
 	}
 	switch req.Origin {
@@ -182,7 +184,7 @@ func (f *FileSystem) stream(conn io.ReadWriteCloser) {
 	case "":
 		req.Origin = "start"
 	default:
-		f.handleStreamResultError(invalidOrigin, helper.Int64ToPtr(400), encoder)
+		handleStreamResultError(invalidOrigin, helper.Int64ToPtr(400), encoder)
 		return
 	}
 
@@ -193,18 +195,18 @@ func (f *FileSystem) stream(conn io.ReadWriteCloser) {
 			code = helper.Int64ToPtr(404)
 		}
 
-		f.handleStreamResultError(err, code, encoder)
+		handleStreamResultError(err, code, encoder)
 		return
 	}
 
 	// Calculate the offset
 	fileInfo, err := fs.Stat(req.Path)
 	if err != nil {
-		f.handleStreamResultError(err, helper.Int64ToPtr(400), encoder)
+		handleStreamResultError(err, helper.Int64ToPtr(400), encoder)
 		return
 	}
 	if fileInfo.IsDir {
-		f.handleStreamResultError(
+		handleStreamResultError(
 			fmt.Errorf("file %q is a directory", req.Path),
 			helper.Int64ToPtr(400), encoder)
 		return
@@ -312,7 +314,7 @@ OUTER:
 	}
 
 	if streamErr != nil {
-		f.handleStreamResultError(streamErr, helper.Int64ToPtr(500), encoder)
+		handleStreamResultError(streamErr, helper.Int64ToPtr(500), encoder)
 		return
 	}
 }
@@ -328,36 +330,36 @@ func (f *FileSystem) logs(conn io.ReadWriteCloser) {
 	encoder := codec.NewEncoder(conn, structs.MsgpackHandle)
 
 	if err := decoder.Decode(&req); err != nil {
-		f.handleStreamResultError(err, helper.Int64ToPtr(500), encoder)
+		handleStreamResultError(err, helper.Int64ToPtr(500), encoder)
 		return
 	}
 
 	// Check read permissions
 	if aclObj, err := f.c.ResolveToken(req.QueryOptions.AuthToken); err != nil {
-		f.handleStreamResultError(err, nil, encoder)
+		handleStreamResultError(err, nil, encoder)
 		return
 	} else if aclObj != nil {
 		readfs := aclObj.AllowNsOp(req.QueryOptions.Namespace, acl.NamespaceCapabilityReadFS)
 		logs := aclObj.AllowNsOp(req.QueryOptions.Namespace, acl.NamespaceCapabilityReadLogs)
 		if !readfs && !logs {
-			f.handleStreamResultError(structs.ErrPermissionDenied, nil, encoder)
+			handleStreamResultError(structs.ErrPermissionDenied, nil, encoder)
 			return
 		}
 	}
 
 	// Validate the arguments
 	if req.AllocID == "" {
-		f.handleStreamResultError(allocIDNotPresentErr, helper.Int64ToPtr(400), encoder)
+		handleStreamResultError(allocIDNotPresentErr, helper.Int64ToPtr(400), encoder)
 		return
 	}
 	if req.Task == "" {
-		f.handleStreamResultError(taskNotPresentErr, helper.Int64ToPtr(400), encoder)
+		handleStreamResultError(taskNotPresentErr, helper.Int64ToPtr(400), encoder)
 		return
 	}
 	switch req.LogType {
 	case "stdout", "stderr":
 	default:
-		f.handleStreamResultError(logTypeNotPresentErr, helper.Int64ToPtr(400), encoder)
+		handleStreamResultError(logTypeNotPresentErr, helper.Int64ToPtr(400), encoder)
 		return
 	}
 	switch req.Origin {
@@ -365,9 +367,30 @@ func (f *FileSystem) logs(conn io.ReadWriteCloser) {
 	case "":
 		req.Origin = "start"
 	default:
-		f.handleStreamResultError(invalidOrigin, helper.Int64ToPtr(400), encoder)
+		handleStreamResultError(invalidOrigin, helper.Int64ToPtr(400), encoder)
 		return
 	}
+	switch req.Format {
+	case cstructs.LogFormatFrames, cstructs.LogFormatJSON, cstructs.LogFormatPlain:
+	case "":
+		req.Format = cstructs.LogFormatFrames
+	default:
+		handleStreamResultError(invalidLogFormat, helper.Int64ToPtr(400), encoder)
+		return
+	}
+	if req.PlainText {
+		req.Format = cstructs.LogFormatPlain
+	}
+	if req.File != "" {
+		req.File = filepath.Base(req.File)
+		if _, ok := logFileIndex(req.File, req.Task, req.LogType); !ok {
+			handleStreamResultError(
+				fmt.Errorf("%q is not a %s log file of task %q", req.File, req.LogType, req.Task),
+				helper.Int64ToPtr(400),
+				encoder)
+			return
+		}
+	}
 
 	fs, err := f.c.GetAllocFS(req.AllocID)
 	if err != nil {
@@ -376,7 +399,7 @@ func (f *FileSystem) logs(conn io.ReadWriteCloser) {
 			code = helper.Int64ToPtr(404)
 		}
 
-		f.handleStreamResultError(err, code, encoder)
+		handleStreamResultError(err, code, encoder)
 		return
 	}
 
@@ -387,14 +410,14 @@ func (f *FileSystem) logs(conn io.ReadWriteCloser) {
 			code = helper.Int64ToPtr(404)
 		}
 
-		f.handleStreamResultError(err, code, encoder)
+		handleStreamResultError(err, code, encoder)
 		return
 	}
 
 	// Check that the task is there
 	taskState := allocState.TaskStates[req.Task]
 	if taskState == nil {
-		f.handleStreamResultError(
+		handleStreamResultError(
 			fmt.Errorf("unknown task name %q", req.Task),
 			helper.Int64ToPtr(400),
 			encoder)
@@ -402,7 +425,7 @@ func (f *FileSystem) logs(conn io.ReadWriteCloser) {
 	}
 
 	if taskState.StartedAt.IsZero() {
-		f.handleStreamResultError(
+		handleStreamResultError(
 			fmt.Errorf("task %q not started yet. No logs available", req.Task),
 			helper.Int64ToPtr(404),
 			encoder)
@@ -418,7 +441,7 @@ func (f *FileSystem) logs(conn io.ReadWriteCloser) {
 	// Start streaming
 	go func() {
 		if err := f.logsImpl(ctx, req.Follow, req.PlainText,
-			req.Offset, req.Origin, req.Task, req.LogType, fs, frames); err != nil {
+			req.Offset, req.Origin, req.File, req.Task, req.LogType, fs, frames); err != nil {
 			select {
 			case errCh <- err:
 			case <-ctx.Done():
@@ -467,9 +490,19 @@ OUTER:
 			}
 
 			var resp cstructs.StreamErrWrapper
-			if req.PlainText {
+			switch req.Format {
+			case cstructs.LogFormatPlain:
 				resp.Payload = frame.Data
-			} else {
+			case cstructs.LogFormatJSON:
+				// Heartbeats are sent with an empty payload to detect
+				// the closing of the connection.
+				if !frame.IsHeartbeat() {
+					if resp.Payload, err = encodeLogFrame(frame, req.Task, req.LogType); err != nil {
+						streamErr = err
+						break OUTER
+					}
+				}
+			default:
 				if err = frameCodec.Encode(frame); err != nil {
 					streamErr = err
 					break OUTER
@@ -494,16 +527,37 @@ OUTER:
 		if codedErr, ok := streamErr.(interface{ Code() int }); ok {
 			code = int64(codedErr.Code())
 		}
-		f.handleStreamResultError(streamErr, &code, encoder)
+		handleStreamResultError(streamErr, &code, encoder)
 		return
 	}
 }
 
+// encodeLogFrame encodes a stream frame of a log file as a newline terminated
+// JSON LogFrame.
+func encodeLogFrame(frame *sframer.StreamFrame, task, logType string) ([]byte, error) {
+	logFrame := cstructs.LogFrame{
+		File:      filepath.Base(frame.File),
+		Offset:    frame.Offset,
+		Data:      string(frame.Data),
+		FileEvent: frame.FileEvent,
+		Timestamp: time.Now().UTC(),
+	}
+	logFrame.Index, _ = logFileIndex(logFrame.File, task, logType)
+
+	buf, err := json.Marshal(&logFrame)
+	if err != nil {
+		return nil, err
+	}
+	return append(buf, '\n'), nil
+}
+
 // logsImpl is used to stream the logs of a the given task. Output is sent on
 // the passed frames channel and the method will return on EOF if follow is not
-// true otherwise when the context is cancelled or on an error.
+// true otherwise when the context is cancelled or on an error. If file is set,
+// streaming resumes at the offset of that log file, or at the start of the
+// next one if it has been rotated out.
 func (f *FileSystem) logsImpl(ctx context.Context, follow, plain bool, offset int64,
-	origin, task, logType string,
+	origin, file, task, logType string,
 	fs allocdir.AllocDirFS, frames chan<- *sframer.StreamFrame) error {
 
 	// Create the framer
@@ -526,6 +580,17 @@ func (f *FileSystem) logsImpl(ctx context.Context, follow, plain bool, offset in
 		return invalidOrigin
 	}
 
+	// resumeIdx is the index of the log file to resume from, if any
+	resumeIdx := int64(-1)
+	if file != "" {
+		idx, ok := logFileIndex(file, task, logType)
+		if !ok {
+			return fmt.Errorf("%q is not a %s log file of task %q", file, logType, task)
+		}
+		resumeIdx = idx
+		nextIdx = idx
+	}
+
 	for {
 		// Logic for picking next file is:
 		// 1) List log files
@@ -549,7 +614,17 @@ func (f *FileSystem) logsImpl(ctx context.Context, follow, plain bool, offset in
 			maxIndex = idx
 		}
 
-		logEntry, idx, openOffset, err := findClosest(entries, nextIdx, offset, task, logType)
+		var logEntry *cstructs.AllocFileInfo
+		var idx, openOffset int64
+		if resumeIdx >= 0 {
+			// The offset applies within the resumed file only
+			logEntry, idx, _, err = findClosest(entries, nextIdx, 0, task, logType)
+			if err == nil && idx == resumeIdx {
+				openOffset = offset
+			}
+		} else {
+			logEntry, idx, openOffset, err = findClosest(entries, nextIdx, offset, task, logType)
+		}
 		if err != nil {
 			return err
 		}
@@ -609,6 +684,7 @@ func (f *FileSystem) logsImpl(ctx context.Context, follow, plain bool, offset in
 		// Since we successfully streamed, update the overall offset/idx.
 		offset = int64(0)
 		nextIdx = idx + 1
+		resumeIdx = -1
 	}
 }
 
@@ -644,6 +720,11 @@ func (f *FileSystem) streamFile(ctx context.Context, offset int64, path string,
 	// read and reach EOF.
 	var changes *watch.FileChanges
 
+	// lastRead is set once the stream is cancelled at EOF, to read what was
+	// written since the last read before returning, such as the end of a log
+	// file being rotated.
+	lastRead := false
+
 	// Start streaming the data
 	bufSize := int64(streamFrameSize)
 	if limit > 0 && limit < streamFrameSize {
@@ -680,6 +761,9 @@ OUTER:
 		if readErr == nil {
 			continue
 		}
+		if lastRead {
+			return nil
+		}
 
 		// If EOF is hit, wait for a change to the file
 		if changes == nil {
@@ -730,11 +814,12 @@ OUTER:
 			case <-ctx.Done():
 				return nil
 			case err, ok := <-eofCancelCh:
-				if !ok {
-					return nil
+				if ok && err != nil {
+					return err
 				}
 
-				return err
+				lastRead = true
+				continue OUTER
 			}
 		}
 	}
@@ -840,6 +925,21 @@ func logIndexes(entries []*cstructs.AllocFileInfo, task, logType string) (indexT
 	return indexTupleArray(indexes), nil
 }
 
+// logFileIndex returns the rotation index of the log file of the task and log
+// type with the given name, and whether the name is one of these log files.
+func logFileIndex(name, task, logType string) (int64, bool) {
+	idxStr := strings.TrimPrefix(name, fmt.Sprintf("%s.%s.", task, logType))
+	if idxStr == name {
+		return 0, false
+	}
+
+	idx, err := strconv.ParseInt(idxStr, 10, 64)
+	if err != nil || idx < 0 {
+		return 0, false
+	}
+	return idx, true
+}
+
 // notFoundErr is returned when a log is requested but cannot be found.
 // Implements agent.HTTPCodedError but does not reference it to avoid circular
 // imports.
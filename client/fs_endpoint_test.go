@@ -2,6 +2,7 @@ package client
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -1863,7 +1864,7 @@ func TestFS_logsImpl_NoFollow(t *testing.T) {
 	go func() {
 		if err := c.endpoints.FileSystem.logsImpl(
 			context.Background(), false, false, 0,
-			OriginStart, task, logType, ad, frames); err != nil {
+			OriginStart, "", task, logType, ad, frames); err != nil {
 			t.Fatalf("logs() failed: %v", err)
 		}
 	}()
@@ -1937,7 +1938,7 @@ func TestFS_logsImpl_Follow(t *testing.T) {
 	// Start streaming logs
 	go c.endpoints.FileSystem.logsImpl(
 		context.Background(), true, false, 0,
-		OriginStart, task, logType, ad, frames)
+		OriginStart, "", task, logType, ad, frames)
 
 	select {
 	case <-firstResultCh:
@@ -1957,3 +1958,80 @@ func TestFS_logsImpl_Follow(t *testing.T) {
 		t.Fatalf("did not receive data: got %q", string(received))
 	}
 }
+
+func TestFS_logsImpl_Resume(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	c, cleanup := TestClient(t, nil)
+	defer cleanup()
+
+	// Get a temp alloc dir and create the log dir
+	ad := tempAllocDir(t)
+	defer os.RemoveAll(ad.AllocDir)
+
+	logDir := filepath.Join(ad.SharedDir, allocdir.LogDirName)
+	require.NoError(os.MkdirAll(logDir, 0777))
+
+	// Create a series of log files in the temp dir, the first one having
+	// been rotated out
+	task := "foo"
+	logType := "stdout"
+	for i, data := range []string{"345", "678"} {
+		logFile := fmt.Sprintf("%s.%s.%d", task, logType, i+1)
+		require.NoError(ioutil.WriteFile(filepath.Join(logDir, logFile), []byte(data), 0666))
+	}
+
+	cases := []struct {
+		file     string
+		offset   int64
+		expected string
+	}{
+		{"foo.stdout.1", 1, "45678"},
+		{"foo.stdout.2", 2, "8"},
+		{"foo.stdout.0", 2, "345678"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.file, func(t *testing.T) {
+			frames := make(chan *sframer.StreamFrame, 32)
+			err := c.endpoints.FileSystem.logsImpl(
+				context.Background(), false, false, tc.offset,
+				OriginStart, tc.file, task, logType, ad, frames)
+			require.NoError(err)
+
+			// The frames are closed once streaming ends
+			var received []byte
+			for frame := range frames {
+				received = append(received, frame.Data...)
+			}
+			require.Equal(tc.expected, string(received))
+		})
+	}
+}
+
+func TestFS_encodeLogFrame(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	buf, err := encodeLogFrame(&sframer.StreamFrame{
+		File:   "alloc/logs/web.stderr.3",
+		Offset: 10,
+		Data:   []byte("hello\n"),
+	}, "web", "stderr")
+	require.NoError(err)
+	require.True(strings.HasSuffix(string(buf), "\n"))
+
+	var frame cstructs.LogFrame
+	require.NoError(json.Unmarshal(buf, &frame))
+	require.Equal("web.stderr.3", frame.File)
+	require.EqualValues(3, frame.Index)
+	require.EqualValues(10, frame.Offset)
+	require.Equal("hello\n", frame.Data)
+	require.False(frame.Timestamp.IsZero())
+
+	_, ok := logFileIndex("web.stdout.3", "web", "stderr")
+	require.False(ok)
+	_, ok = logFileIndex("web.stderr.foo", "web", "stderr")
+	require.False(ok)
+}
@@ -0,0 +1,203 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/client/hostvolume"
+	"github.com/hashicorp/nomad/client/structs"
+	nstructs "github.com/hashicorp/nomad/nomad/structs"
+)
+
+// HostVolume endpoint is used for creating and deleting host volumes on the
+// node at runtime
+type HostVolume struct {
+	c *Client
+}
+
+// Create is used to provision a host volume on the node and register it with
+// the servers.
+func (v *HostVolume) Create(args *structs.HostVolumeCreateRequest, reply *structs.HostVolumeCreateResponse) error {
+	defer metrics.MeasureSince([]string{"client", "host_volume", "create"}, time.Now())
+
+	// Check for write-volume permissions
+	ns := args.RequestNamespace()
+	if aclObj, err := v.c.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNsOp(ns, acl.NamespaceCapabilityWriteVolume) {
+		return nstructs.ErrPermissionDenied
+	}
+
+	// Verify the arguments
+	if args.Volume == nil {
+		return errors.New("missing volume")
+	}
+	volume := args.Volume.Copy()
+	volume.Type = nstructs.VolumeTypeHost
+	volume.Namespace = ns
+	volume.NodeID = v.c.NodeID()
+	volume.Canonicalize()
+	if err := volume.Validate(); err != nil {
+		return fmt.Errorf("volume invalid: %v", err)
+	}
+
+	created, err := v.c.createHostVolume(volume, args.Provisioner, args.AuthToken)
+	if err != nil {
+		return err
+	}
+	reply.Volume = created
+	return nil
+}
+
+// Delete is used to deregister a host volume created on the node and remove
+// it. Volumes that are still claimed by non-terminal allocations can not be
+// deleted.
+func (v *HostVolume) Delete(args *structs.HostVolumeDeleteRequest, reply *nstructs.GenericResponse) error {
+	defer metrics.MeasureSince([]string{"client", "host_volume", "delete"}, time.Now())
+
+	// Check for write-volume permissions
+	ns := args.RequestNamespace()
+	if aclObj, err := v.c.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNsOp(ns, acl.NamespaceCapabilityWriteVolume) {
+		return nstructs.ErrPermissionDenied
+	}
+
+	// Verify the arguments
+	if args.VolumeID == "" {
+		return errors.New("missing volume ID")
+	}
+
+	index, err := v.c.deleteHostVolume(ns, args.VolumeID, args.AuthToken)
+	if err != nil {
+		return err
+	}
+	reply.Index = index
+	return nil
+}
+
+// createHostVolume provisions the volume with the named provisioner and
+// registers it. The volume is removed again if it can't be registered.
+func (c *Client) createHostVolume(volume *nstructs.Volume, name, authToken string) (*nstructs.Volume, error) {
+	if name == "" {
+		name = hostvolume.ProvisionerMkdir
+	}
+	provisioner, err := c.hostVolumes.Provisioner(name)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := provisioner.Create(&hostvolume.CreateRequest{
+		ID:          volume.ID,
+		Namespace:   volume.Namespace,
+		CapacityMin: volume.CapacityMin,
+		CapacityMax: volume.CapacityMax,
+		Parameters:  volume.Parameters,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	volume.ExternalID = resp.Path
+	if resp.CapacityBytes > 0 {
+		volume.CapacityMin = resp.CapacityBytes
+		volume.CapacityMax = resp.CapacityBytes
+	}
+	if volume.Context == nil {
+		volume.Context = make(map[string]string, 1)
+	}
+	volume.Context[hostvolume.ContextProvisioner] = name
+
+	req := &nstructs.VolumeRegisterRequest{
+		Volumes: []*nstructs.Volume{volume},
+		WriteRequest: nstructs.WriteRequest{
+			Region:    c.Region(),
+			Namespace: volume.Namespace,
+			AuthToken: authToken,
+		},
+	}
+	var regResp nstructs.GenericResponse
+	if err := c.RPC("Volume.Create", req, &regResp); err != nil {
+		deleteReq := &hostvolume.DeleteRequest{
+			ID:         volume.ID,
+			Namespace:  volume.Namespace,
+			Path:       resp.Path,
+			Parameters: volume.Parameters,
+		}
+		if deleteErr := provisioner.Delete(deleteReq); deleteErr != nil {
+			c.logger.Error("failed to remove host volume after failing to register it",
+				"volume_id", volume.ID, "path", resp.Path, "error", deleteErr)
+		}
+		return nil, fmt.Errorf("failed to register host volume: %v", err)
+	}
+
+	c.logger.Info("created host volume", "volume_id", volume.ID, "namespace", volume.Namespace,
+		"provisioner", name, "path", volume.ExternalID)
+	volume.CreateIndex = regResp.Index
+	volume.ModifyIndex = regResp.Index
+	return volume, nil
+}
+
+// deleteHostVolume deregisters a host volume created on the node and removes
+// it with the provisioner that created it.
+func (c *Client) deleteHostVolume(namespace, id, authToken string) (uint64, error) {
+	getReq := &nstructs.VolumeSpecificRequest{
+		VolumeID: id,
+		QueryOptions: nstructs.QueryOptions{
+			Region:    c.Region(),
+			Namespace: namespace,
+			AuthToken: authToken,
+		},
+	}
+	var getResp nstructs.SingleVolumeResponse
+	if err := c.RPC("Volume.GetVolume", getReq, &getResp); err != nil {
+		return 0, err
+	}
+	volume := getResp.Volume
+	if volume == nil {
+		return 0, fmt.Errorf("volume %q not found", id)
+	}
+	if volume.Type != nstructs.VolumeTypeHost || volume.NodeID != c.NodeID() {
+		return 0, fmt.Errorf("volume %q is not a host volume of node %q", id, c.NodeID())
+	}
+	name, ok := volume.Context[hostvolume.ContextProvisioner]
+	if !ok {
+		return 0, fmt.Errorf("volume %q was not created by the client and must be deleted with the volume API", id)
+	}
+	provisioner, err := c.hostVolumes.Provisioner(name)
+	if err != nil {
+		return 0, err
+	}
+
+	// Deregister the volume first so no allocation claims it while it is
+	// removed
+	deleteReq := &nstructs.VolumeDeleteRequest{
+		VolumeIDs: []string{id},
+		WriteRequest: nstructs.WriteRequest{
+			Region:    c.Region(),
+			Namespace: namespace,
+			AuthToken: authToken,
+		},
+	}
+	var deleteResp nstructs.GenericResponse
+	if err := c.RPC("Volume.Delete", deleteReq, &deleteResp); err != nil {
+		return 0, err
+	}
+
+	err = provisioner.Delete(&hostvolume.DeleteRequest{
+		ID:         volume.ID,
+		Namespace:  volume.Namespace,
+		Path:       volume.ExternalID,
+		Parameters: volume.Parameters,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("volume %q was deregistered but not removed: %v", id, err)
+	}
+
+	c.logger.Info("deleted host volume", "volume_id", id, "namespace", namespace,
+		"provisioner", name, "path", volume.ExternalID)
+	return deleteResp.Index, nil
+}
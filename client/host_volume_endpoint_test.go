@@ -0,0 +1,91 @@
+package client
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/client/hostvolume"
+	"github.com/hashicorp/nomad/client/structs"
+	nstructs "github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHostVolume_CreateDelete(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1, addr := testServer(t, nil)
+	defer s1.Shutdown()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	dir, err := ioutil.TempDir("", "nomadtest-hostvolumes")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	c1, cleanup := TestClient(t, func(c *config.Config) {
+		c.Servers = []string{addr}
+		c.HostVolumesDir = dir
+	})
+	defer cleanup()
+
+	// Create the volume
+	req := &structs.HostVolumeCreateRequest{
+		Volume: &nstructs.Volume{
+			ID:         "data",
+			Name:       "data",
+			Parameters: map[string]string{"mode": "0750"},
+		},
+		QueryOptions: nstructs.QueryOptions{
+			Region:    "global",
+			Namespace: nstructs.DefaultNamespace,
+		},
+	}
+	var resp structs.HostVolumeCreateResponse
+	require.NoError(c1.ClientRPC("HostVolume.Create", req, &resp))
+
+	path := filepath.Join(dir, nstructs.DefaultNamespace, "data")
+	require.NotNil(resp.Volume)
+	require.Equal(nstructs.VolumeTypeHost, resp.Volume.Type)
+	require.Equal(c1.NodeID(), resp.Volume.NodeID)
+	require.Equal(path, resp.Volume.ExternalID)
+	require.Equal(hostvolume.ProvisionerMkdir, resp.Volume.Context[hostvolume.ContextProvisioner])
+
+	fi, err := os.Stat(path)
+	require.NoError(err)
+	require.True(fi.IsDir())
+	require.Equal(os.FileMode(0750), fi.Mode().Perm())
+
+	// The volume is registered
+	volume, err := s1.State().VolumeByID(nil, nstructs.DefaultNamespace, "data")
+	require.NoError(err)
+	require.NotNil(volume)
+	require.Equal(path, volume.ExternalID)
+
+	// Creating the volume again fails without removing it
+	err = c1.ClientRPC("HostVolume.Create", req, &resp)
+	require.Error(err)
+	_, err = os.Stat(path)
+	require.NoError(err)
+
+	// Delete the volume
+	deleteReq := &structs.HostVolumeDeleteRequest{
+		VolumeID: "data",
+		QueryOptions: nstructs.QueryOptions{
+			Region:    "global",
+			Namespace: nstructs.DefaultNamespace,
+		},
+	}
+	var deleteResp nstructs.GenericResponse
+	require.NoError(c1.ClientRPC("HostVolume.Delete", deleteReq, &deleteResp))
+	require.NotZero(deleteResp.Index)
+
+	_, err = os.Stat(path)
+	require.True(os.IsNotExist(err))
+	volume, err = s1.State().VolumeByID(nil, nstructs.DefaultNamespace, "data")
+	require.NoError(err)
+	require.Nil(volume)
+}
@@ -0,0 +1,93 @@
+package client
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// hostVolumeCheckInterval is the interval at which the health of the
+	// host volumes of the node is checked.
+	hostVolumeCheckInterval = 30 * time.Second
+
+	// hostVolumeCheckTimeout is the time after which a volume whose health
+	// check hasn't completed is reported unhealthy.
+	hostVolumeCheckTimeout = 10 * time.Second
+)
+
+// hostVolumeHealth returns the health of a host volume and its description.
+func (c *Client) hostVolumeHealth(volume *structs.ClientHostVolumeConfig) (bool, string) {
+	if err := c.hostVolumeChecker.Check(volume.Path, volume.ReadOnly, volume.Mountpoint); err != nil {
+		return false, err.Error()
+	}
+	return true, "healthy"
+}
+
+// watchHostVolumes checks the health of the host volumes of the node every
+// interval until shutdown.
+func (c *Client) watchHostVolumes() {
+	ticker := time.NewTicker(hostVolumeCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-c.shutdownCh:
+			return
+		}
+
+		c.checkHostVolumes()
+	}
+}
+
+// checkHostVolumes checks the health of the host volumes of the node and
+// updates the node when it changes.
+func (c *Client) checkHostVolumes() {
+	// Check the volumes without holding the lock as checking a volume on an
+	// unresponsive mount may block
+	c.configLock.RLock()
+	volumes := structs.CopyMapClientHostVolumeConfig(c.config.Node.HostVolumes)
+	c.configLock.RUnlock()
+	if len(volumes) == 0 {
+		return
+	}
+
+	for _, volume := range volumes {
+		volume.Healthy, volume.HealthDescription = c.hostVolumeHealth(volume)
+	}
+
+	c.configLock.Lock()
+	defer c.configLock.Unlock()
+
+	var hasChanged bool
+	for name, volume := range volumes {
+		current, ok := c.config.Node.HostVolumes[name]
+		if !ok {
+			continue
+		}
+		if current.Healthy == volume.Healthy && current.HealthDescription == volume.HealthDescription {
+			continue
+		}
+
+		hasChanged = true
+		current.Healthy = volume.Healthy
+		current.HealthDescription = volume.HealthDescription
+
+		if volume.Healthy {
+			c.logger.Info("host volume is healthy", "host_volume", name)
+		} else {
+			c.logger.Warn("host volume is unhealthy", "host_volume", name, "reason", volume.HealthDescription)
+		}
+
+		c.triggerNodeEvent(structs.NewNodeEvent().
+			SetSubsystem(structs.NodeEventSubsystemStorage).
+			SetMessage(volume.HealthDescription).
+			AddDetail("host_volume", name).
+			AddDetail("healthy", strconv.FormatBool(volume.Healthy)))
+	}
+
+	if hasChanged {
+		c.updateNodeLocked()
+	}
+}
@@ -0,0 +1,47 @@
+package client
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_CheckHostVolumes(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "nomadtest-hostvolume")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "data")
+	require.NoError(os.Mkdir(path, 0755))
+
+	c, cleanup := TestClient(t, func(c *config.Config) {
+		c.HostVolumes = map[string]*structs.ClientHostVolumeConfig{
+			"data": {Name: "data", Path: path},
+		}
+	})
+	defer cleanup()
+
+	// The volume is healthy on startup
+	volume := c.Node().HostVolumes["data"]
+	require.NotNil(volume)
+	require.True(volume.Healthy)
+
+	// Removing the path makes the volume unhealthy
+	require.NoError(os.Remove(path))
+	c.checkHostVolumes()
+	volume = c.Node().HostVolumes["data"]
+	require.False(volume.Healthy)
+	require.Contains(volume.HealthDescription, "does not exist")
+
+	// Restoring the path makes it healthy again
+	require.NoError(os.Mkdir(path, 0755))
+	c.checkHostVolumes()
+	require.True(c.Node().HostVolumes["data"].Healthy)
+}
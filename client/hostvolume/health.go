@@ -0,0 +1,116 @@
+package hostvolume
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+)
+
+// CheckHealth returns an error if the host volume at the path is unhealthy,
+// that is if the path is not a directory, if it must be a mountpoint and no
+// filesystem is mounted there or, unless the volume is read only, if the
+// directory is not writable.
+func CheckHealth(path string, readOnly, mountpoint bool) error {
+	fi, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("host volume path %q does not exist", path)
+		}
+		return fmt.Errorf("failed to stat host volume path %q: %v", path, err)
+	}
+	if !fi.IsDir() {
+		return fmt.Errorf("host volume path %q is not a directory", path)
+	}
+
+	// Without its filesystem the path is an empty directory of the parent
+	// filesystem, which tasks must not write to in place of the volume
+	if mountpoint {
+		mounted, err := isMountpoint(path)
+		if err != nil {
+			return fmt.Errorf("failed to check the mount of host volume path %q: %v", path, err)
+		}
+		if !mounted {
+			return fmt.Errorf("host volume path %q is not a mountpoint", path)
+		}
+	}
+
+	if readOnly {
+		return nil
+	}
+
+	f, err := ioutil.TempFile(path, ".nomad-health-")
+	if err != nil {
+		return fmt.Errorf("host volume path %q is not writable: %v", path, err)
+	}
+	f.Close()
+	if err := os.Remove(f.Name()); err != nil {
+		return fmt.Errorf("failed to remove health check file of host volume path %q: %v", path, err)
+	}
+	return nil
+}
+
+// healthCheck is a health check of a host volume in progress.
+type healthCheck struct {
+	done chan struct{}
+	err  error
+}
+
+// healthCheckKey identifies the health checks of a host volume.
+type healthCheckKey struct {
+	path       string
+	readOnly   bool
+	mountpoint bool
+}
+
+// HealthChecker checks the health of host volumes with a timeout, so that a
+// check blocked on an unresponsive mount, such as a stale NFS mount, doesn't
+// block its caller. A blocked check is not started again until it returns.
+type HealthChecker struct {
+	timeout time.Duration
+
+	inflight map[healthCheckKey]*healthCheck
+	lock     sync.Mutex
+}
+
+// NewHealthChecker returns a HealthChecker whose checks time out after the
+// given duration.
+func NewHealthChecker(timeout time.Duration) *HealthChecker {
+	return &HealthChecker{
+		timeout:  timeout,
+		inflight: make(map[healthCheckKey]*healthCheck),
+	}
+}
+
+// Check returns an error if the host volume at the path is unhealthy, or if
+// checking its health doesn't complete within the timeout. A check of the
+// same volume that is still in progress is waited for instead of starting a
+// new one.
+func (h *HealthChecker) Check(path string, readOnly, mountpoint bool) error {
+	key := healthCheckKey{path: path, readOnly: readOnly, mountpoint: mountpoint}
+
+	h.lock.Lock()
+	check, ok := h.inflight[key]
+	if !ok {
+		check = &healthCheck{done: make(chan struct{})}
+		h.inflight[key] = check
+		go func() {
+			check.err = CheckHealth(path, readOnly, mountpoint)
+			h.lock.Lock()
+			delete(h.inflight, key)
+			h.lock.Unlock()
+			close(check.done)
+		}()
+	}
+	h.lock.Unlock()
+
+	timer := time.NewTimer(h.timeout)
+	defer timer.Stop()
+	select {
+	case <-check.done:
+		return check.err
+	case <-timer.C:
+		return fmt.Errorf("health check of host volume path %q timed out after %v", path, h.timeout)
+	}
+}
@@ -0,0 +1,63 @@
+package hostvolume
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckHealth(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "nomadtest-hostvolume")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	// A writable directory is healthy
+	require.NoError(CheckHealth(dir, false, false))
+	files, err := ioutil.ReadDir(dir)
+	require.NoError(err)
+	require.Empty(files)
+
+	// A missing path is unhealthy
+	err = CheckHealth(filepath.Join(dir, "missing"), true, false)
+	require.Error(err)
+	require.Contains(err.Error(), "does not exist")
+
+	// A file is unhealthy
+	file := filepath.Join(dir, "file")
+	require.NoError(ioutil.WriteFile(file, nil, 0644))
+	err = CheckHealth(file, true, false)
+	require.Error(err)
+	require.Contains(err.Error(), "not a directory")
+
+	// A directory that must be a mountpoint is unhealthy without a mount
+	if runtime.GOOS != "windows" {
+		err = CheckHealth(dir, true, true)
+		require.Error(err)
+		require.Contains(err.Error(), "not a mountpoint")
+		require.NoError(CheckHealth("/", true, true))
+	}
+}
+
+func TestHealthChecker(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "nomadtest-hostvolume")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	h := NewHealthChecker(10 * time.Second)
+	require.NoError(h.Check(dir, false, false))
+	err = h.Check(filepath.Join(dir, "missing"), false, false)
+	require.Error(err)
+	require.Contains(err.Error(), "does not exist")
+	require.Empty(h.inflight)
+}
@@ -0,0 +1,92 @@
+// +build !windows
+
+package hostvolume
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// mountInfoPath is the mount table of the client process on Linux.
+const mountInfoPath = "/proc/self/mountinfo"
+
+// isMountpoint returns whether a filesystem is mounted at the path. The path
+// is a mountpoint if it is on another device than its parent. Bind mounts of
+// a directory of the same device are found in the mount table where there is
+// one.
+func isMountpoint(path string) (bool, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return false, err
+	}
+	path = filepath.Clean(path)
+
+	var st, parent syscall.Stat_t
+	if err := syscall.Stat(path, &st); err != nil {
+		return false, err
+	}
+	if err := syscall.Stat(filepath.Dir(path), &parent); err != nil {
+		return false, err
+	}
+	if st.Dev != parent.Dev || st.Ino == parent.Ino {
+		return true, nil
+	}
+
+	return inMountTable(path)
+}
+
+// inMountTable returns whether the path is the mountpoint of an entry of the
+// mount table, or false if there is no mount table.
+func inMountTable(path string) (bool, error) {
+	f, err := os.Open(mountInfoPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	defer f.Close()
+
+	// The mountpoint is the fifth field of each line, with spaces and other
+	// special characters escaped as octal sequences
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 5 {
+			continue
+		}
+		if unescapeMountPath(fields[4]) == path {
+			return true, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return false, fmt.Errorf("failed to read mount table: %v", err)
+	}
+	return false, nil
+}
+
+// unescapeMountPath unescapes the octal sequences of a path of the mount
+// table.
+func unescapeMountPath(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+4 <= len(s) {
+			if c, err := strconv.ParseUint(s[i+1:i+4], 8, 8); err == nil {
+				b.WriteByte(byte(c))
+				i += 3
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
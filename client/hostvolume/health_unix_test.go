@@ -0,0 +1,15 @@
+// +build !windows
+
+package hostvolume
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnescapeMountPath(t *testing.T) {
+	t.Parallel()
+	require.Equal(t, "/mnt/my data", unescapeMountPath(`/mnt/my\040data`))
+	require.Equal(t, "/mnt/data", unescapeMountPath("/mnt/data"))
+}
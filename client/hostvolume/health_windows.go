@@ -0,0 +1,7 @@
+package hostvolume
+
+// isMountpoint always returns true on Windows, where the mounts of host
+// volumes are not checked.
+func isMountpoint(path string) (bool, error) {
+	return true, nil
+}
@@ -0,0 +1,60 @@
+package hostvolume
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// mkdirProvisioner is the builtin provisioner, creating the volume as a
+// directory in the host volumes directory. The mode of the directory can be
+// set with the "mode" parameter.
+type mkdirProvisioner struct {
+	dir string
+}
+
+func (p *mkdirProvisioner) Create(req *CreateRequest) (*CreateResponse, error) {
+	if p.dir == "" {
+		return nil, fmt.Errorf("no host volumes directory configured")
+	}
+
+	mode := os.FileMode(0700)
+	if m, ok := req.Parameters["mode"]; ok {
+		parsed, err := strconv.ParseUint(m, 8, 32)
+		if err != nil || parsed > 0777 {
+			return nil, fmt.Errorf("invalid mode %q: must be an octal permission", m)
+		}
+		mode = os.FileMode(parsed)
+	}
+
+	path := volumePath(p.dir, req.Namespace, req.ID)
+	if err := os.MkdirAll(filepath.Dir(path), 0711); err != nil {
+		return nil, fmt.Errorf("failed to create host volumes directory: %v", err)
+	}
+	if err := os.Mkdir(path, mode); err != nil {
+		if os.IsExist(err) {
+			return nil, fmt.Errorf("host volume directory %q already exists", path)
+		}
+		return nil, fmt.Errorf("failed to create host volume directory: %v", err)
+	}
+
+	// Set the mode explicitly as Mkdir applies the umask
+	if err := os.Chmod(path, mode); err != nil {
+		os.Remove(path)
+		return nil, fmt.Errorf("failed to set mode of host volume directory: %v", err)
+	}
+
+	return &CreateResponse{Path: path}, nil
+}
+
+func (p *mkdirProvisioner) Delete(req *DeleteRequest) error {
+	// Only remove directories created by the provisioner
+	if req.Path != volumePath(p.dir, req.Namespace, req.ID) {
+		return fmt.Errorf("host volume path %q was not created by the %s provisioner", req.Path, ProvisionerMkdir)
+	}
+	if err := os.RemoveAll(req.Path); err != nil {
+		return fmt.Errorf("failed to remove host volume directory: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package hostvolume
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"time"
+
+	hclog "github.com/hashicorp/go-hclog"
+)
+
+// pluginTimeout bounds how long a provisioner plugin may run, leaving time to
+// format and mount a device.
+const pluginTimeout = 5 * time.Minute
+
+const (
+	// The plugin operations, passed as the first argument of the plugin.
+	pluginOpCreate = "create"
+	pluginOpDelete = "delete"
+)
+
+// pluginProvisioner runs an executable of the host volume plugin directory to
+// provision volumes. The plugin is called with the operation as its first
+// argument and the request in the environment:
+//
+//	DHV_OPERATION          "create" or "delete"
+//	DHV_VOLUME_ID          the ID of the volume
+//	DHV_NAMESPACE          the namespace of the volume
+//	DHV_VOLUMES_DIR        the host volumes directory of the client
+//	DHV_CAPACITY_MIN_BYTES the minimum capacity of the volume
+//	DHV_CAPACITY_MAX_BYTES the maximum capacity of the volume
+//	DHV_PARAMETERS         the parameters of the volume as a JSON object
+//	DHV_PATH               the path of the volume, on delete
+//
+// On create the plugin must write a JSON object with the "path" of the
+// volume and optionally its capacity in "bytes" to stdout.
+type pluginProvisioner struct {
+	name       string
+	path       string
+	volumesDir string
+	logger     hclog.Logger
+}
+
+func (p *pluginProvisioner) Create(req *CreateRequest) (*CreateResponse, error) {
+	env := p.env(pluginOpCreate, req.ID, req.Namespace, req.Parameters)
+	env = append(env,
+		"DHV_CAPACITY_MIN_BYTES="+strconv.FormatInt(req.CapacityMin, 10),
+		"DHV_CAPACITY_MAX_BYTES="+strconv.FormatInt(req.CapacityMax, 10),
+	)
+
+	stdout, err := p.run(pluginOpCreate, env)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp CreateResponse
+	if err := json.Unmarshal(stdout, &resp); err != nil {
+		return nil, fmt.Errorf("failed to decode output of provisioner %q: %v", p.name, err)
+	}
+	if resp.Path == "" {
+		return nil, fmt.Errorf("provisioner %q returned no path", p.name)
+	}
+	return &resp, nil
+}
+
+func (p *pluginProvisioner) Delete(req *DeleteRequest) error {
+	env := p.env(pluginOpDelete, req.ID, req.Namespace, req.Parameters)
+	env = append(env, "DHV_PATH="+req.Path)
+
+	_, err := p.run(pluginOpDelete, env)
+	return err
+}
+
+// env returns the environment shared by the plugin operations.
+func (p *pluginProvisioner) env(op, id, namespace string, params map[string]string) []string {
+	if params == nil {
+		params = map[string]string{}
+	}
+	encoded, _ := json.Marshal(params)
+	return append(os.Environ(),
+		"DHV_OPERATION="+op,
+		"DHV_VOLUME_ID="+id,
+		"DHV_NAMESPACE="+namespace,
+		"DHV_VOLUMES_DIR="+p.volumesDir,
+		"DHV_PARAMETERS="+string(encoded),
+	)
+}
+
+// run runs the plugin for the operation and returns its stdout.
+func (p *pluginProvisioner) run(op string, env []string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), pluginTimeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, p.path, op)
+	cmd.Env = env
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	p.logger.Debug("running host volume provisioner", "operation", op)
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("provisioner %q timed out after %v", p.name, pluginTimeout)
+		}
+		return nil, fmt.Errorf("provisioner %q failed to %s volume: %v: %s",
+			p.name, op, err, bytes.TrimSpace(stderr.Bytes()))
+	}
+	return stdout.Bytes(), nil
+}
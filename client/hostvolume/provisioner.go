@@ -0,0 +1,139 @@
+// Package hostvolume provisions the host volumes created on a client at
+// runtime. A provisioner creates the directory of a volume on the host, and
+// may format and mount a device for it, before the volume is registered with
+// the servers.
+package hostvolume
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	hclog "github.com/hashicorp/go-hclog"
+)
+
+const (
+	// ProvisionerMkdir is the name of the builtin provisioner, which creates
+	// a directory in the host volumes directory of the client.
+	ProvisionerMkdir = "mkdir"
+
+	// ContextProvisioner is the key of the volume context the name of the
+	// provisioner that created a host volume is stored under, so the volume
+	// can be deleted by the same provisioner.
+	ContextProvisioner = "provisioner"
+)
+
+var (
+	// validProvisionerName is used to validate the name of a provisioner
+	// plugin, which is the name of an executable in the plugin directory
+	validProvisionerName = regexp.MustCompile("^[a-zA-Z0-9-_.]{1,128}$")
+)
+
+// CreateRequest is the request to provision a host volume.
+type CreateRequest struct {
+	// ID and Namespace identify the volume.
+	ID        string
+	Namespace string
+
+	// CapacityMin and CapacityMax are the range of the capacity of the
+	// volume in bytes. Zero means no limit.
+	CapacityMin int64
+	CapacityMax int64
+
+	// Parameters are passed to the provisioner.
+	Parameters map[string]string
+}
+
+// CreateResponse is the response of provisioning a host volume.
+type CreateResponse struct {
+	// Path is the path of the volume on the host.
+	Path string `json:"path"`
+
+	// CapacityBytes is the capacity of the volume, if known.
+	CapacityBytes int64 `json:"bytes"`
+}
+
+// DeleteRequest is the request to delete a host volume.
+type DeleteRequest struct {
+	// ID and Namespace identify the volume.
+	ID        string
+	Namespace string
+
+	// Path is the path of the volume on the host.
+	Path string
+
+	// Parameters are the parameters the volume was created with.
+	Parameters map[string]string
+}
+
+// Provisioner creates and deletes host volumes.
+type Provisioner interface {
+	// Create provisions the volume and returns its path on the host.
+	Create(req *CreateRequest) (*CreateResponse, error)
+
+	// Delete removes the volume and its data.
+	Delete(req *DeleteRequest) error
+}
+
+// Manager looks up the provisioners of the host volumes of a client.
+type Manager struct {
+	// volumesDir is the directory the builtin provisioner creates the
+	// volumes in.
+	volumesDir string
+
+	// pluginDir is the directory the provisioner plugins are executables of.
+	pluginDir string
+
+	logger hclog.Logger
+}
+
+// NewManager returns a manager of the provisioners creating volumes in
+// volumesDir, and of the plugins found in pluginDir. An empty pluginDir
+// disables plugins.
+func NewManager(logger hclog.Logger, volumesDir, pluginDir string) *Manager {
+	return &Manager{
+		volumesDir: volumesDir,
+		pluginDir:  pluginDir,
+		logger:     logger.Named("host_volumes"),
+	}
+}
+
+// Provisioner returns the provisioner of the given name. An empty name
+// returns the builtin mkdir provisioner.
+func (m *Manager) Provisioner(name string) (Provisioner, error) {
+	if name == "" || name == ProvisionerMkdir {
+		return &mkdirProvisioner{dir: m.volumesDir}, nil
+	}
+
+	if !validProvisionerName.MatchString(name) {
+		return nil, fmt.Errorf("invalid provisioner name %q", name)
+	}
+	if m.pluginDir == "" {
+		return nil, fmt.Errorf("unknown provisioner %q: no host volume plugin directory configured", name)
+	}
+
+	path := filepath.Join(m.pluginDir, name)
+	fi, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("unknown provisioner %q", name)
+		}
+		return nil, fmt.Errorf("failed to find provisioner %q: %v", name, err)
+	}
+	if fi.IsDir() || fi.Mode()&0111 == 0 {
+		return nil, fmt.Errorf("provisioner %q is not an executable", name)
+	}
+
+	return &pluginProvisioner{
+		name:       name,
+		path:       path,
+		volumesDir: m.volumesDir,
+		logger:     m.logger.With("provisioner", name),
+	}, nil
+}
+
+// volumePath returns the path of a volume created in the volumes directory.
+func volumePath(volumesDir, namespace, id string) string {
+	return filepath.Join(volumesDir, namespace, id)
+}
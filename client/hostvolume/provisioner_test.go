@@ -0,0 +1,166 @@
+package hostvolume
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManager_Provisioner(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	pluginDir, err := ioutil.TempDir("", "nomadtest-hostvolume")
+	require.NoError(err)
+	defer os.RemoveAll(pluginDir)
+	require.NoError(ioutil.WriteFile(filepath.Join(pluginDir, "exec"), []byte("#!/bin/sh\n"), 0755))
+	require.NoError(ioutil.WriteFile(filepath.Join(pluginDir, "noexec"), []byte("#!/bin/sh\n"), 0644))
+
+	m := NewManager(testlog.HCLogger(t), "/tmp/volumes", pluginDir)
+
+	p, err := m.Provisioner("")
+	require.NoError(err)
+	require.IsType(&mkdirProvisioner{}, p)
+
+	p, err = m.Provisioner(ProvisionerMkdir)
+	require.NoError(err)
+	require.IsType(&mkdirProvisioner{}, p)
+
+	if runtime.GOOS != "windows" {
+		p, err = m.Provisioner("exec")
+		require.NoError(err)
+		require.IsType(&pluginProvisioner{}, p)
+
+		_, err = m.Provisioner("noexec")
+		require.Error(err)
+		require.Contains(err.Error(), "not an executable")
+	}
+
+	_, err = m.Provisioner("unknown")
+	require.Error(err)
+	require.Contains(err.Error(), "unknown provisioner")
+
+	_, err = m.Provisioner("../exec")
+	require.Error(err)
+	require.Contains(err.Error(), "invalid provisioner name")
+
+	// Plugins are disabled without a plugin directory
+	m = NewManager(testlog.HCLogger(t), "/tmp/volumes", "")
+	_, err = m.Provisioner("exec")
+	require.Error(err)
+	require.Contains(err.Error(), "no host volume plugin directory")
+}
+
+func TestMkdirProvisioner(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "nomadtest-hostvolume")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	p := &mkdirProvisioner{dir: dir}
+	resp, err := p.Create(&CreateRequest{ID: "data", Namespace: "default"})
+	require.NoError(err)
+	require.Equal(filepath.Join(dir, "default", "data"), resp.Path)
+
+	fi, err := os.Stat(resp.Path)
+	require.NoError(err)
+	require.True(fi.IsDir())
+	if runtime.GOOS != "windows" {
+		require.Equal(os.FileMode(0700), fi.Mode().Perm())
+	}
+
+	// The volume can't be created twice
+	_, err = p.Create(&CreateRequest{ID: "data", Namespace: "default"})
+	require.Error(err)
+	require.Contains(err.Error(), "already exists")
+
+	// The mode must be an octal permission
+	_, err = p.Create(&CreateRequest{ID: "other", Namespace: "default", Parameters: map[string]string{"mode": "rwx"}})
+	require.Error(err)
+	require.Contains(err.Error(), "invalid mode")
+
+	// Only paths created by the provisioner are removed
+	err = p.Delete(&DeleteRequest{ID: "data", Namespace: "default", Path: dir})
+	require.Error(err)
+	_, err = os.Stat(resp.Path)
+	require.NoError(err)
+
+	require.NoError(p.Delete(&DeleteRequest{ID: "data", Namespace: "default", Path: resp.Path}))
+	_, err = os.Stat(resp.Path)
+	require.True(os.IsNotExist(err))
+}
+
+func TestPluginProvisioner(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Plugin test requires a shell")
+	}
+	t.Parallel()
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "nomadtest-hostvolume")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	// The plugin creates the volume in the volumes directory and records
+	// the parameters it was called with
+	script := `#!/bin/sh
+set -e
+path="$DHV_VOLUMES_DIR/$DHV_NAMESPACE-$DHV_VOLUME_ID"
+case "$1" in
+create)
+	mkdir -p "$path"
+	echo "$DHV_PARAMETERS" > "$path/params"
+	echo "{\"path\": \"$path\", \"bytes\": $DHV_CAPACITY_MAX_BYTES}"
+	;;
+delete)
+	[ "$DHV_PATH" = "$path" ]
+	rm -rf "$DHV_PATH"
+	;;
+*)
+	echo "unknown operation $1" >&2
+	exit 1
+	;;
+esac
+`
+	pluginDir := filepath.Join(dir, "plugins")
+	volumesDir := filepath.Join(dir, "volumes")
+	require.NoError(os.MkdirAll(pluginDir, 0755))
+	require.NoError(os.MkdirAll(volumesDir, 0755))
+	require.NoError(ioutil.WriteFile(filepath.Join(pluginDir, "test"), []byte(script), 0755))
+
+	m := NewManager(testlog.HCLogger(t), volumesDir, pluginDir)
+	p, err := m.Provisioner("test")
+	require.NoError(err)
+
+	resp, err := p.Create(&CreateRequest{
+		ID:          "data",
+		Namespace:   "default",
+		CapacityMax: 1024,
+		Parameters:  map[string]string{"fs": "ext4"},
+	})
+	require.NoError(err)
+	path := filepath.Join(volumesDir, "default-data")
+	require.Equal(path, resp.Path)
+	require.EqualValues(1024, resp.CapacityBytes)
+
+	params, err := ioutil.ReadFile(filepath.Join(path, "params"))
+	require.NoError(err)
+	require.JSONEq(`{"fs":"ext4"}`, string(params))
+
+	// Errors of the plugin are returned
+	err = p.Delete(&DeleteRequest{ID: "data", Namespace: "default", Path: volumesDir})
+	require.Error(err)
+	_, err = os.Stat(path)
+	require.NoError(err)
+
+	require.NoError(p.Delete(&DeleteRequest{ID: "data", Namespace: "default", Path: path}))
+	_, err = os.Stat(path)
+	require.True(os.IsNotExist(err))
+}
@@ -0,0 +1,109 @@
+// +build linux
+
+package cgutil
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// CgroupRoot is where the unified hierarchy is mounted on v2 hosts
+	CgroupRoot = "/sys/fs/cgroup"
+
+	// DefaultCgroupParent is the cgroup below the root that cgroups created
+	// for tasks are placed in
+	DefaultCgroupParent = "nomad"
+)
+
+// controllers are the v2 controllers enabled for the cgroups of tasks
+var controllers = []string{"cpu", "cpuset", "io", "memory", "pids"}
+
+// UseV2 is true if the host only mounts the v2 unified hierarchy
+var UseV2 = IsCgroup2UnifiedMode()
+
+// IsCgroup2UnifiedMode returns whether the cgroup filesystem mounted at
+// CgroupRoot is the v2 unified hierarchy.
+func IsCgroup2UnifiedMode() bool {
+	var st unix.Statfs_t
+	if err := unix.Statfs(CgroupRoot, &st); err != nil {
+		return false
+	}
+	return st.Type == unix.CGROUP2_SUPER_MAGIC
+}
+
+// CgroupPath returns the path of the cgroup named name below parent in the
+// unified hierarchy.
+func CgroupPath(parent, name string) string {
+	if parent == "" {
+		parent = DefaultCgroupParent
+	}
+	return filepath.Join(CgroupRoot, parent, name)
+}
+
+// CreateCgroup creates the cgroup at path and enables the controllers used
+// for tasks on each of its ancestors, so that limits can be set on it.
+func CreateCgroup(path string) error {
+	if err := os.MkdirAll(path, 0755); err != nil {
+		return fmt.Errorf("failed to create cgroup %q: %v", path, err)
+	}
+	return enableControllers(CgroupRoot, path)
+}
+
+// enableControllers enables the task controllers in the subtree of each
+// cgroup between root and path. Controllers the parent of a cgroup does not
+// provide are skipped, as the kernel would reject them.
+func enableControllers(root, path string) error {
+	rel, err := filepath.Rel(root, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return fmt.Errorf("cgroup %q is not below %q", path, root)
+	}
+
+	dir := root
+	for _, elem := range strings.Split(rel, string(filepath.Separator)) {
+		if elem == "." {
+			break
+		}
+
+		available, err := readControllers(filepath.Join(dir, "cgroup.controllers"))
+		if err != nil {
+			return err
+		}
+
+		var enable []string
+		for _, c := range controllers {
+			if available[c] {
+				enable = append(enable, "+"+c)
+			}
+		}
+		if len(enable) != 0 {
+			subtree := filepath.Join(dir, "cgroup.subtree_control")
+			if err := ioutil.WriteFile(subtree, []byte(strings.Join(enable, " ")), 0644); err != nil {
+				return fmt.Errorf("failed to enable controllers in %q: %v", dir, err)
+			}
+		}
+
+		dir = filepath.Join(dir, elem)
+	}
+	return nil
+}
+
+// readControllers returns the set of controllers listed in the given
+// cgroup.controllers file.
+func readControllers(file string) (map[string]bool, error) {
+	raw, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cgroup controllers: %v", err)
+	}
+
+	available := make(map[string]bool)
+	for _, c := range strings.Fields(string(raw)) {
+		available[c] = true
+	}
+	return available, nil
+}
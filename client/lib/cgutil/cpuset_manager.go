@@ -0,0 +1,85 @@
+package cgutil
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/hashicorp/nomad/lib/cpuset"
+)
+
+// CpusetManager tracks the cores dedicated to tasks on the client. The cores
+// of the host are statically split in two: the reservable cores, that the
+// scheduler may dedicate to tasks, and the shared cores that all other tasks
+// are restricted to. Dedicated cores are therefore never used by tasks of any
+// driver that did not ask for them.
+type CpusetManager struct {
+	// host is the set of all cores on the host
+	host cpuset.CPUSet
+
+	// reservable is the subset of host cores that may be dedicated to tasks
+	reservable cpuset.CPUSet
+
+	// reserved maps task IDs to the cores dedicated to them
+	reserved map[string]cpuset.CPUSet
+	lock     sync.Mutex
+}
+
+// NewCpusetManager returns a CpusetManager for the given host cores. The
+// reservable cores must be a strict subset of the host cores.
+func NewCpusetManager(host, reservable cpuset.CPUSet) (*CpusetManager, error) {
+	if !reservable.IsSubsetOf(host) {
+		return nil, fmt.Errorf("reservable cores %q include cores not present on the host (%s)", reservable, host)
+	}
+	if reservable.Size() != 0 && reservable.Equals(host) {
+		return nil, fmt.Errorf("reservable cores %q must leave at least one core shared", reservable)
+	}
+
+	return &CpusetManager{
+		host:       host,
+		reservable: reservable,
+		reserved:   map[string]cpuset.CPUSet{},
+	}, nil
+}
+
+// Reserve records the cores chosen by the scheduler as dedicated to the given
+// task. An error is returned if a core is not reservable or is dedicated to
+// another task. Reserving the same cores again for a task is a no-op so tasks
+// may reserve their cores each time they are started or restored.
+func (m *CpusetManager) Reserve(taskID string, cores cpuset.CPUSet) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if !cores.IsSubsetOf(m.reservable) {
+		return fmt.Errorf("cores %q are not reservable (%s)", cores, m.reservable)
+	}
+
+	for id, other := range m.reserved {
+		if id == taskID {
+			continue
+		}
+		if shared := cores.Intersect(other); shared.Size() != 0 {
+			return fmt.Errorf("cores %q are already dedicated to another task", shared)
+		}
+	}
+
+	m.reserved[taskID] = cores
+	return nil
+}
+
+// Release returns the cores of the given task to the reservable pool.
+func (m *CpusetManager) Release(taskID string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	delete(m.reserved, taskID)
+}
+
+// Shared returns the cores that tasks without dedicated cores run on.
+func (m *CpusetManager) Shared() cpuset.CPUSet {
+	return m.host.Difference(m.reservable)
+}
+
+// Restricted returns whether tasks without dedicated cores must be restricted
+// to the shared cores, which is the case when any core is reservable.
+func (m *CpusetManager) Restricted() bool {
+	return m.reservable.Size() != 0
+}
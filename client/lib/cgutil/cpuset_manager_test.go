@@ -0,0 +1,63 @@
+package cgutil
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/lib/cpuset"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCpusetManager(t *testing.T) {
+	require := require.New(t)
+
+	m, err := NewCpusetManager(cpuset.New(0, 1, 2, 3), cpuset.New(2, 3))
+	require.NoError(err)
+	require.True(m.Restricted())
+	require.Equal("0-1", m.Shared().String())
+
+	require.NoError(m.Reserve("a", cpuset.New(2)))
+
+	// Reserving the same cores again is a no-op
+	require.NoError(m.Reserve("a", cpuset.New(2)))
+
+	// Cores dedicated to another task can not be reserved
+	err = m.Reserve("b", cpuset.New(2, 3))
+	require.Error(err)
+	require.Contains(err.Error(), "already dedicated")
+
+	// Shared cores can not be reserved
+	err = m.Reserve("b", cpuset.New(1))
+	require.Error(err)
+	require.Contains(err.Error(), "not reservable")
+
+	require.NoError(m.Reserve("b", cpuset.New(3)))
+
+	m.Release("a")
+	require.NoError(m.Reserve("c", cpuset.New(2)))
+
+	// The shared cores do not depend on the reservations
+	require.Equal("0-1", m.Shared().String())
+}
+
+func TestCpusetManager_Unrestricted(t *testing.T) {
+	require := require.New(t)
+
+	m, err := NewCpusetManager(cpuset.New(0, 1), cpuset.New())
+	require.NoError(err)
+	require.False(m.Restricted())
+	require.Equal("0-1", m.Shared().String())
+	require.Error(m.Reserve("a", cpuset.New(0)))
+}
+
+func TestCpusetManager_Invalid(t *testing.T) {
+	require := require.New(t)
+
+	// At least one core must remain shared
+	_, err := NewCpusetManager(cpuset.New(0, 1), cpuset.New(0, 1))
+	require.Error(err)
+	require.Contains(err.Error(), "at least one core shared")
+
+	_, err = NewCpusetManager(cpuset.New(0, 1), cpuset.New(1, 2))
+	require.Error(err)
+	require.Contains(err.Error(), "not present on the host")
+}
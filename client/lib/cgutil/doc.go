@@ -0,0 +1,9 @@
+// Package cgutil implements the parts of cgroup management that differ between
+// the legacy v1 hierarchies and the v2 unified hierarchy, such as creating and
+// delegating cgroups, applying resource limits and reading usage statistics.
+//
+// The vendored libcontainer only understands v1 hierarchies, so on hosts
+// running the unified hierarchy Manager is used in place of its cgroupfs
+// manager. Statistics are returned in the libcontainer format so callers can
+// handle both versions the same way.
+package cgutil
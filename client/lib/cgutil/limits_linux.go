@@ -0,0 +1,131 @@
+// +build linux
+
+package cgutil
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// unlimitedMemory is the value above which a v1 memory limit is considered
+// unset. The kernel reports an unset limit as the largest page aligned int64.
+const unlimitedMemory = 1 << 62
+
+// Limits are the CPU and memory limits of a cgroup. Zero values mean the
+// limit is not set.
+type Limits struct {
+	// CPUCores is the number of cores the cgroup may use, from its CPU quota
+	// and period.
+	CPUCores float64
+
+	// MemoryBytes is the maximum memory usage of the cgroup.
+	MemoryBytes uint64
+}
+
+// SliceLimits returns the limits of the cgroup or systemd slice named slice,
+// such as "system.slice", relative to the cgroup root. Both the v1
+// hierarchies and the v2 unified hierarchy are supported.
+func SliceLimits(slice string) (*Limits, error) {
+	return sliceLimits(CgroupRoot, UseV2, slice)
+}
+
+func sliceLimits(root string, v2 bool, slice string) (*Limits, error) {
+	slice = strings.TrimPrefix(filepath.Clean("/"+slice), "/")
+	if slice == "" {
+		return nil, fmt.Errorf("cgroup slice must not be the cgroup root")
+	}
+
+	cpuDir := filepath.Join(root, "cpu", slice)
+	memDir := filepath.Join(root, "memory", slice)
+	if v2 {
+		cpuDir = filepath.Join(root, slice)
+		memDir = cpuDir
+	}
+	if _, err := os.Stat(cpuDir); err != nil {
+		return nil, fmt.Errorf("failed to find cgroup slice %q: %v", slice, err)
+	}
+
+	limits := &Limits{}
+	var err error
+	if v2 {
+		limits.CPUCores, err = readCPUMax(cpuDir)
+		if err != nil {
+			return nil, err
+		}
+		err = readUint(memDir, "memory.max", &limits.MemoryBytes)
+		return limits, err
+	}
+
+	limits.CPUCores, err = readCFSQuota(cpuDir)
+	if err != nil {
+		return nil, err
+	}
+	if err := readUint(memDir, "memory.limit_in_bytes", &limits.MemoryBytes); err != nil {
+		return nil, err
+	}
+	if limits.MemoryBytes >= unlimitedMemory {
+		limits.MemoryBytes = 0
+	}
+	return limits, nil
+}
+
+// readCPUMax parses cpu.max, which holds the quota and period of the cgroup
+// in microseconds, such as "200000 100000" or "max 100000".
+func readCPUMax(dir string) (float64, error) {
+	raw, err := ioutil.ReadFile(filepath.Join(dir, "cpu.max"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to read cpu.max: %v", err)
+	}
+
+	fields := strings.Fields(string(raw))
+	if len(fields) != 2 {
+		return 0, fmt.Errorf("failed to parse cpu.max: %q", strings.TrimSpace(string(raw)))
+	}
+	if fields[0] == "max" {
+		return 0, nil
+	}
+	quota, err := strconv.ParseUint(fields[0], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse cpu.max: %v", err)
+	}
+	period, err := strconv.ParseUint(fields[1], 10, 64)
+	if err != nil || period == 0 {
+		return 0, fmt.Errorf("failed to parse cpu.max: invalid period %q", fields[1])
+	}
+	return float64(quota) / float64(period), nil
+}
+
+// readCFSQuota reads the v1 CFS quota and period of the cgroup. A quota of -1
+// means no quota is set.
+func readCFSQuota(dir string) (float64, error) {
+	raw, err := ioutil.ReadFile(filepath.Join(dir, "cpu.cfs_quota_us"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to read cpu.cfs_quota_us: %v", err)
+	}
+	quota, err := strconv.ParseInt(strings.TrimSpace(string(raw)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse cpu.cfs_quota_us: %v", err)
+	}
+	if quota <= 0 {
+		return 0, nil
+	}
+
+	var period uint64
+	if err := readUint(dir, "cpu.cfs_period_us", &period); err != nil {
+		return 0, err
+	}
+	if period == 0 {
+		return 0, fmt.Errorf("failed to parse cpu.cfs_period_us: period is zero")
+	}
+	return float64(quota) / float64(period), nil
+}
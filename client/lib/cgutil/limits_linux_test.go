@@ -0,0 +1,82 @@
+package cgutil
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSliceLimits_V2(t *testing.T) {
+	require := require.New(t)
+
+	root, err := ioutil.TempDir("", "cgutil")
+	require.NoError(err)
+	defer os.RemoveAll(root)
+
+	dir := filepath.Join(root, "system.slice")
+	require.NoError(os.MkdirAll(dir, 0755))
+	writeCgroupFiles(t, dir, map[string]string{
+		"cpu.max":    "150000 100000\n",
+		"memory.max": "2147483648\n",
+	})
+
+	limits, err := sliceLimits(root, true, "/system.slice")
+	require.NoError(err)
+	require.Equal(1.5, limits.CPUCores)
+	require.EqualValues(2<<30, limits.MemoryBytes)
+
+	// Unset limits are zero
+	writeCgroupFiles(t, dir, map[string]string{
+		"cpu.max":    "max 100000\n",
+		"memory.max": "max\n",
+	})
+	limits, err = sliceLimits(root, true, "system.slice")
+	require.NoError(err)
+	require.Zero(limits.CPUCores)
+	require.Zero(limits.MemoryBytes)
+
+	_, err = sliceLimits(root, true, "missing.slice")
+	require.Error(err)
+	_, err = sliceLimits(root, true, "/")
+	require.Error(err)
+}
+
+func TestSliceLimits_V1(t *testing.T) {
+	require := require.New(t)
+
+	root, err := ioutil.TempDir("", "cgutil")
+	require.NoError(err)
+	defer os.RemoveAll(root)
+
+	cpuDir := filepath.Join(root, "cpu", "system.slice")
+	memDir := filepath.Join(root, "memory", "system.slice")
+	require.NoError(os.MkdirAll(cpuDir, 0755))
+	require.NoError(os.MkdirAll(memDir, 0755))
+	writeCgroupFiles(t, cpuDir, map[string]string{
+		"cpu.cfs_quota_us":  "50000\n",
+		"cpu.cfs_period_us": "100000\n",
+	})
+	writeCgroupFiles(t, memDir, map[string]string{
+		"memory.limit_in_bytes": "536870912\n",
+	})
+
+	limits, err := sliceLimits(root, false, "system.slice")
+	require.NoError(err)
+	require.Equal(0.5, limits.CPUCores)
+	require.EqualValues(512<<20, limits.MemoryBytes)
+
+	// Unset limits are zero
+	writeCgroupFiles(t, cpuDir, map[string]string{
+		"cpu.cfs_quota_us": "-1\n",
+	})
+	writeCgroupFiles(t, memDir, map[string]string{
+		"memory.limit_in_bytes": "9223372036854771712\n",
+	})
+	limits, err = sliceLimits(root, false, "system.slice")
+	require.NoError(err)
+	require.Zero(limits.CPUCores)
+	require.Zero(limits.MemoryBytes)
+}
@@ -0,0 +1,270 @@
+// +build linux
+
+package cgutil
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/opencontainers/runc/libcontainer/cgroups"
+	"github.com/opencontainers/runc/libcontainer/configs"
+)
+
+// defaultCpuPeriod is the CFS period used when only a quota is given
+const defaultCpuPeriod = 100000
+
+// Manager implements the libcontainer cgroups.Manager interface for a single
+// cgroup of the v2 unified hierarchy.
+type Manager struct {
+	Cgroups *configs.Cgroup
+	Path    string
+}
+
+// NewManager returns a Manager for the given cgroup configuration. It matches
+// the signature of libcontainer's NewCgroupsManager so it can be used by a
+// container factory. Paths, when restoring a container, hold the path of the
+// cgroup under the empty key as returned by GetPaths.
+func NewManager(cg *configs.Cgroup, paths map[string]string) cgroups.Manager {
+	m := &Manager{Cgroups: cg}
+	switch {
+	case paths[""] != "":
+		m.Path = paths[""]
+	case cg == nil:
+	case cg.Paths[""] != "":
+		m.Path = cg.Paths[""]
+	case cg.Path != "":
+		m.Path = filepath.Join(CgroupRoot, cg.Path)
+	default:
+		m.Path = CgroupPath(cg.Parent, cg.Name)
+	}
+	return m
+}
+
+// Apply creates the cgroup and moves the process with the given pid into it.
+// A pid of -1 only creates the cgroup.
+func (m *Manager) Apply(pid int) error {
+	if m.Path == "" {
+		return fmt.Errorf("no cgroup path configured")
+	}
+	if err := CreateCgroup(m.Path); err != nil {
+		return err
+	}
+	return cgroups.WriteCgroupProc(m.Path, pid)
+}
+
+// GetPids returns the pids of the processes in the cgroup.
+func (m *Manager) GetPids() ([]int, error) {
+	return cgroups.GetPids(m.Path)
+}
+
+// GetAllPids returns the pids of the processes in the cgroup and all of its
+// sub-cgroups.
+func (m *Manager) GetAllPids() ([]int, error) {
+	return cgroups.GetAllPids(m.Path)
+}
+
+// GetStats returns the resource usage of the cgroup.
+func (m *Manager) GetStats() (*cgroups.Stats, error) {
+	return Stats(m.Path)
+}
+
+// Freeze freezes or thaws the processes in the cgroup. Freezing waits for
+// the kernel to report the cgroup as frozen.
+func (m *Manager) Freeze(state configs.FreezerState) error {
+	return Freeze(m.Path, state)
+}
+
+// Destroy removes the cgroup along with any sub-cgroups. The cgroup must not
+// contain any processes.
+func (m *Manager) Destroy() error {
+	return cgroups.RemovePaths(map[string]string{"": m.Path})
+}
+
+// GetPaths returns the path of the cgroup under the empty key, as the unified
+// hierarchy has no per subsystem paths.
+func (m *Manager) GetPaths() map[string]string {
+	return map[string]string{"": m.Path}
+}
+
+// Set applies the resource limits of the container to the cgroup, converting
+// the v1 values of the configuration to their v2 equivalents.
+func (m *Manager) Set(container *configs.Config) error {
+	if container.Cgroups == nil || container.Cgroups.Resources == nil {
+		return nil
+	}
+	r := container.Cgroups.Resources
+
+	if r.Memory != 0 {
+		if err := writeFile(m.Path, "memory.max", limitValue(r.Memory)); err != nil {
+			return err
+		}
+	}
+	if r.MemoryReservation != 0 {
+		if err := writeFile(m.Path, "memory.low", limitValue(r.MemoryReservation)); err != nil {
+			return err
+		}
+	}
+	// The unified hierarchy has no swappiness, so a swappiness of 0 is
+	// enforced by not allowing any swap
+	if r.MemorySwappiness != nil && *r.MemorySwappiness == 0 {
+		if err := writeFile(m.Path, "memory.swap.max", "0"); err != nil {
+			return err
+		}
+	}
+
+	if r.CpuShares != 0 {
+		if err := writeFile(m.Path, "cpu.weight", strconv.FormatUint(ConvertCPUSharesToWeight(r.CpuShares), 10)); err != nil {
+			return err
+		}
+	}
+	if r.CpuQuota != 0 || r.CpuPeriod != 0 {
+		period := r.CpuPeriod
+		if period == 0 {
+			period = defaultCpuPeriod
+		}
+		quota := "max"
+		if r.CpuQuota > 0 {
+			quota = strconv.FormatInt(r.CpuQuota, 10)
+		}
+		if err := writeFile(m.Path, "cpu.max", fmt.Sprintf("%s %d", quota, period)); err != nil {
+			return err
+		}
+	}
+	if r.CpusetCpus != "" {
+		if err := writeFile(m.Path, "cpuset.cpus", r.CpusetCpus); err != nil {
+			return err
+		}
+	}
+	if r.CpusetMems != "" {
+		if err := writeFile(m.Path, "cpuset.mems", r.CpusetMems); err != nil {
+			return err
+		}
+	}
+
+	if r.BlkioWeight != 0 {
+		if err := writeFile(m.Path, "io.weight", strconv.FormatUint(ConvertBlkioWeightToIOWeight(r.BlkioWeight), 10)); err != nil {
+			return err
+		}
+	}
+	// io.max takes the limits of a single device per write
+	for _, line := range ioMaxLines(r) {
+		if err := writeFile(m.Path, "io.max", line); err != nil {
+			return err
+		}
+	}
+
+	if r.PidsLimit != 0 {
+		if err := writeFile(m.Path, "pids.max", limitValue(r.PidsLimit)); err != nil {
+			return err
+		}
+	}
+
+	if r.Freezer != configs.Undefined {
+		return m.Freeze(r.Freezer)
+	}
+	return nil
+}
+
+// ConvertCPUSharesToWeight converts v1 CPU shares, ranging from 2 to 262144,
+// to a v2 CPU weight, ranging from 1 to 10000.
+func ConvertCPUSharesToWeight(shares uint64) uint64 {
+	if shares < 2 {
+		shares = 2
+	}
+	if shares > 262144 {
+		shares = 262144
+	}
+	return 1 + ((shares-2)*9999)/262142
+}
+
+// ioMaxLines merges the v1 throttle devices of the resources into one io.max
+// line per device, such as "8:0 rbps=1048576 wiops=100".
+func ioMaxLines(r *configs.Resources) []string {
+	var devices []string
+	limits := make(map[string][]string)
+	add := func(key string, throttles []*configs.ThrottleDevice) {
+		for _, t := range throttles {
+			dev := fmt.Sprintf("%d:%d", t.Major, t.Minor)
+			if _, ok := limits[dev]; !ok {
+				devices = append(devices, dev)
+			}
+			limits[dev] = append(limits[dev], fmt.Sprintf("%s=%d", key, t.Rate))
+		}
+	}
+	add("rbps", r.BlkioThrottleReadBpsDevice)
+	add("wbps", r.BlkioThrottleWriteBpsDevice)
+	add("riops", r.BlkioThrottleReadIOPSDevice)
+	add("wiops", r.BlkioThrottleWriteIOPSDevice)
+
+	lines := make([]string, len(devices))
+	for i, dev := range devices {
+		lines[i] = dev + " " + strings.Join(limits[dev], " ")
+	}
+	return lines
+}
+
+// ConvertBlkioWeightToIOWeight converts a v1 block IO weight, ranging from 10
+// to 1000, to a v2 IO weight, ranging from 1 to 10000.
+func ConvertBlkioWeightToIOWeight(weight uint16) uint64 {
+	w := uint64(weight)
+	if w < 10 {
+		w = 10
+	}
+	if w > 1000 {
+		w = 1000
+	}
+	return 1 + ((w-10)*9999)/990
+}
+
+// Freeze freezes or thaws the processes in the cgroup at path.
+func Freeze(path string, state configs.FreezerState) error {
+	var value string
+	switch state {
+	case configs.Frozen:
+		value = "1"
+	case configs.Thawed:
+		value = "0"
+	default:
+		return nil
+	}
+
+	if err := writeFile(path, "cgroup.freeze", value); err != nil {
+		return err
+	}
+	if state == configs.Thawed {
+		return nil
+	}
+
+	// Freezing completes asynchronously once all processes of the cgroup
+	// are stopped
+	for i := 0; i < 100; i++ {
+		raw, err := ioutil.ReadFile(filepath.Join(path, "cgroup.events"))
+		if err != nil {
+			return fmt.Errorf("failed to read cgroup events: %v", err)
+		}
+		if parseKeyValues(string(raw))["frozen"] == 1 {
+			return nil
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return fmt.Errorf("timed out freezing cgroup %q", path)
+}
+
+// limitValue formats a limit, where a negative value means unlimited.
+func limitValue(v int64) string {
+	if v < 0 {
+		return "max"
+	}
+	return strconv.FormatInt(v, 10)
+}
+
+func writeFile(dir, file, value string) error {
+	if err := ioutil.WriteFile(filepath.Join(dir, file), []byte(value), 0644); err != nil {
+		return fmt.Errorf("failed to set %s to %q: %v", file, strings.TrimSpace(value), err)
+	}
+	return nil
+}
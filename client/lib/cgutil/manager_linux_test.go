@@ -0,0 +1,146 @@
+package cgutil
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/opencontainers/runc/libcontainer/configs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManager_Set(t *testing.T) {
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "cgutil")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	var swappiness uint64
+	cfg := &configs.Config{
+		Cgroups: &configs.Cgroup{
+			Resources: &configs.Resources{
+				Memory:           256 * 1024 * 1024,
+				MemorySwappiness: &swappiness,
+				CpuShares:        1024,
+				CpuQuota:         50000,
+				CpusetCpus:       "0-1",
+				BlkioWeight:      500,
+				PidsLimit:        -1,
+			},
+		},
+	}
+
+	m := NewManager(nil, map[string]string{"": dir})
+	require.NoError(m.Set(cfg))
+	require.Equal(map[string]string{"": dir}, m.GetPaths())
+
+	expected := map[string]string{
+		"memory.max":      "268435456",
+		"memory.swap.max": "0",
+		"cpu.weight":      "39",
+		"cpu.max":         "50000 100000",
+		"cpuset.cpus":     "0-1",
+		"io.weight":       "4950",
+		"pids.max":        "max",
+	}
+	for file, value := range expected {
+		raw, err := ioutil.ReadFile(filepath.Join(dir, file))
+		require.NoError(err, file)
+		require.Equal(value, string(raw), file)
+	}
+
+	_, err = os.Stat(filepath.Join(dir, "cpuset.mems"))
+	require.True(os.IsNotExist(err))
+}
+
+func TestIOMaxLines(t *testing.T) {
+	r := &configs.Resources{
+		BlkioThrottleReadBpsDevice:   []*configs.ThrottleDevice{configs.NewThrottleDevice(8, 0, 1048576)},
+		BlkioThrottleWriteIOPSDevice: []*configs.ThrottleDevice{configs.NewThrottleDevice(8, 0, 100), configs.NewThrottleDevice(8, 16, 10)},
+	}
+
+	require.Equal(t, []string{
+		"8:0 rbps=1048576 wiops=100",
+		"8:16 wiops=10",
+	}, ioMaxLines(r))
+}
+
+func TestNewManager_Path(t *testing.T) {
+	cases := []struct {
+		name     string
+		cg       *configs.Cgroup
+		paths    map[string]string
+		expected string
+	}{
+		{
+			name:     "restored",
+			cg:       &configs.Cgroup{Path: "nomad/a"},
+			paths:    map[string]string{"": "/sys/fs/cgroup/other"},
+			expected: "/sys/fs/cgroup/other",
+		},
+		{
+			name:     "explicit",
+			cg:       &configs.Cgroup{Paths: map[string]string{"": "/sys/fs/cgroup/x"}},
+			expected: "/sys/fs/cgroup/x",
+		},
+		{
+			name:     "relative",
+			cg:       &configs.Cgroup{Path: "nomad/a"},
+			expected: "/sys/fs/cgroup/nomad/a",
+		},
+		{
+			name:     "name",
+			cg:       &configs.Cgroup{Name: "b"},
+			expected: "/sys/fs/cgroup/nomad/b",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			m := NewManager(c.cg, c.paths).(*Manager)
+			require.Equal(t, c.expected, m.Path)
+		})
+	}
+}
+
+func TestEnableControllers(t *testing.T) {
+	require := require.New(t)
+
+	root, err := ioutil.TempDir("", "cgutil")
+	require.NoError(err)
+	defer os.RemoveAll(root)
+
+	parent := filepath.Join(root, "nomad")
+	path := filepath.Join(parent, "task")
+	require.NoError(os.MkdirAll(path, 0755))
+	writeCgroupFiles(t, root, map[string]string{
+		"cgroup.controllers": "cpuset cpu io memory hugetlb pids rdma\n",
+	})
+	writeCgroupFiles(t, parent, map[string]string{
+		"cgroup.controllers": "cpu memory\n",
+	})
+
+	require.NoError(enableControllers(root, path))
+
+	raw, err := ioutil.ReadFile(filepath.Join(root, "cgroup.subtree_control"))
+	require.NoError(err)
+	require.Equal("+cpu +cpuset +io +memory +pids", string(raw))
+
+	raw, err = ioutil.ReadFile(filepath.Join(parent, "cgroup.subtree_control"))
+	require.NoError(err)
+	require.Equal("+cpu +memory", string(raw))
+
+	_, err = os.Stat(filepath.Join(path, "cgroup.subtree_control"))
+	require.True(os.IsNotExist(err))
+
+	require.Error(enableControllers(root, "/elsewhere"))
+}
+
+func TestConvertCPUSharesToWeight(t *testing.T) {
+	require.EqualValues(t, 1, ConvertCPUSharesToWeight(2))
+	require.EqualValues(t, 39, ConvertCPUSharesToWeight(1024))
+	require.EqualValues(t, 10000, ConvertCPUSharesToWeight(262144))
+	require.EqualValues(t, 1, ConvertCPUSharesToWeight(0))
+}
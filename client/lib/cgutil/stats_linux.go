@@ -0,0 +1,180 @@
+// +build linux
+
+package cgutil
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/opencontainers/runc/libcontainer/cgroups"
+)
+
+// Stats returns the resource usage of the cgroup at path in the unified
+// hierarchy. The values are converted to the units and keys of the v1
+// statistics, so "rss" and "cache" are available in the memory stats and CPU
+// times are reported in nanoseconds. Controllers not enabled for the cgroup
+// are skipped.
+func Stats(path string) (*cgroups.Stats, error) {
+	stats := cgroups.NewStats()
+
+	cpu, err := readKeyValues(path, "cpu.stat")
+	if err != nil {
+		return nil, err
+	}
+	if cpu != nil {
+		// cpu.stat is reported in microseconds
+		stats.CpuStats.CpuUsage.TotalUsage = cpu["usage_usec"] * 1000
+		stats.CpuStats.CpuUsage.UsageInUsermode = cpu["user_usec"] * 1000
+		stats.CpuStats.CpuUsage.UsageInKernelmode = cpu["system_usec"] * 1000
+		stats.CpuStats.ThrottlingData.Periods = cpu["nr_periods"]
+		stats.CpuStats.ThrottlingData.ThrottledPeriods = cpu["nr_throttled"]
+		stats.CpuStats.ThrottlingData.ThrottledTime = cpu["throttled_usec"] * 1000
+	}
+
+	mem, err := readKeyValues(path, "memory.stat")
+	if err != nil {
+		return nil, err
+	}
+	if mem != nil {
+		for k, v := range mem {
+			stats.MemoryStats.Stats[k] = v
+		}
+		stats.MemoryStats.Stats["rss"] = mem["anon"]
+		stats.MemoryStats.Stats["cache"] = mem["file"]
+		stats.MemoryStats.Cache = mem["file"]
+
+		kernel, ok := mem["kernel"]
+		if !ok {
+			kernel = mem["slab"] + mem["kernel_stack"]
+		}
+		stats.MemoryStats.KernelUsage.Usage = kernel
+	}
+
+	if err := readIOStats(path, &stats.BlkioStats); err != nil {
+		return nil, err
+	}
+
+	for file, dst := range map[string]*uint64{
+		"memory.current":      &stats.MemoryStats.Usage.Usage,
+		"memory.peak":         &stats.MemoryStats.Usage.MaxUsage,
+		"memory.swap.current": &stats.MemoryStats.SwapUsage.Usage,
+		"pids.current":        &stats.PidsStats.Current,
+	} {
+		if err := readUint(path, file, dst); err != nil {
+			return nil, err
+		}
+	}
+
+	return stats, nil
+}
+
+// readIOStats parses io.stat into the recursive service bytes and serviced
+// operations of the v1 block I/O stats. Each line of io.stat holds the
+// counters of a device, such as "8:0 rbytes=4096 wbytes=0 rios=1 wios=0".
+func readIOStats(dir string, stats *cgroups.BlkioStats) error {
+	raw, err := ioutil.ReadFile(filepath.Join(dir, "io.stat"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read io.stat: %v", err)
+	}
+
+	for _, line := range strings.Split(string(raw), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+
+		var major, minor uint64
+		if _, err := fmt.Sscanf(fields[0], "%d:%d", &major, &minor); err != nil {
+			continue
+		}
+
+		for _, kv := range fields[1:] {
+			parts := strings.SplitN(kv, "=", 2)
+			if len(parts) != 2 {
+				continue
+			}
+			v, err := strconv.ParseUint(parts[1], 10, 64)
+			if err != nil {
+				continue
+			}
+
+			entry := cgroups.BlkioStatEntry{Major: major, Minor: minor, Value: v}
+			switch parts[0] {
+			case "rbytes":
+				entry.Op = "Read"
+				stats.IoServiceBytesRecursive = append(stats.IoServiceBytesRecursive, entry)
+			case "wbytes":
+				entry.Op = "Write"
+				stats.IoServiceBytesRecursive = append(stats.IoServiceBytesRecursive, entry)
+			case "rios":
+				entry.Op = "Read"
+				stats.IoServicedRecursive = append(stats.IoServicedRecursive, entry)
+			case "wios":
+				entry.Op = "Write"
+				stats.IoServicedRecursive = append(stats.IoServicedRecursive, entry)
+			}
+		}
+	}
+	return nil
+}
+
+// readKeyValues parses a flat keyed file such as cpu.stat. A nil map is
+// returned if the file does not exist.
+func readKeyValues(dir, file string) (map[string]uint64, error) {
+	raw, err := ioutil.ReadFile(filepath.Join(dir, file))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %v", file, err)
+	}
+	return parseKeyValues(string(raw)), nil
+}
+
+// parseKeyValues parses lines of space separated keys and values, skipping
+// lines whose value is not a number.
+func parseKeyValues(s string) map[string]uint64 {
+	values := make(map[string]uint64)
+	for _, line := range strings.Split(s, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		v, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		values[fields[0]] = v
+	}
+	return values
+}
+
+// readUint reads a single value file into dst. A missing file or a value of
+// "max" leaves dst unchanged.
+func readUint(dir, file string, dst *uint64) error {
+	raw, err := ioutil.ReadFile(filepath.Join(dir, file))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read %s: %v", file, err)
+	}
+
+	s := strings.TrimSpace(string(raw))
+	if s == "max" {
+		return nil
+	}
+	v, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %v", file, err)
+	}
+	*dst = v
+	return nil
+}
@@ -0,0 +1,81 @@
+package cgutil
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/opencontainers/runc/libcontainer/cgroups"
+	"github.com/stretchr/testify/require"
+)
+
+func writeCgroupFiles(t *testing.T, dir string, files map[string]string) {
+	for name, content := range files {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+}
+
+func TestStats(t *testing.T) {
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "cgutil")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	writeCgroupFiles(t, dir, map[string]string{
+		"cpu.stat":            "usage_usec 300\nuser_usec 200\nsystem_usec 100\nnr_periods 7\nnr_throttled 3\nthrottled_usec 40\n",
+		"memory.stat":         "anon 4096\nfile 8192\nkernel_stack 16\nslab 32\n",
+		"memory.current":      "12288\n",
+		"memory.swap.current": "1024\n",
+		"pids.current":        "5\n",
+		"io.stat":             "8:0 rbytes=4096 wbytes=1024 rios=2 wios=1 dbytes=0 dios=0\n",
+	})
+
+	stats, err := Stats(dir)
+	require.NoError(err)
+
+	cpu := stats.CpuStats
+	require.EqualValues(300000, cpu.CpuUsage.TotalUsage)
+	require.EqualValues(200000, cpu.CpuUsage.UsageInUsermode)
+	require.EqualValues(100000, cpu.CpuUsage.UsageInKernelmode)
+	require.EqualValues(7, cpu.ThrottlingData.Periods)
+	require.EqualValues(3, cpu.ThrottlingData.ThrottledPeriods)
+	require.EqualValues(40000, cpu.ThrottlingData.ThrottledTime)
+
+	mem := stats.MemoryStats
+	require.EqualValues(4096, mem.Stats["rss"])
+	require.EqualValues(8192, mem.Stats["cache"])
+	require.EqualValues(48, mem.KernelUsage.Usage)
+	require.EqualValues(12288, mem.Usage.Usage)
+	require.EqualValues(0, mem.Usage.MaxUsage)
+	require.EqualValues(1024, mem.SwapUsage.Usage)
+	require.EqualValues(5, stats.PidsStats.Current)
+
+	blkio := stats.BlkioStats
+	require.Equal([]cgroups.BlkioStatEntry{
+		{Major: 8, Minor: 0, Op: "Read", Value: 4096},
+		{Major: 8, Minor: 0, Op: "Write", Value: 1024},
+	}, blkio.IoServiceBytesRecursive)
+	require.Equal([]cgroups.BlkioStatEntry{
+		{Major: 8, Minor: 0, Op: "Read", Value: 2},
+		{Major: 8, Minor: 0, Op: "Write", Value: 1},
+	}, blkio.IoServicedRecursive)
+}
+
+func TestStats_MissingControllers(t *testing.T) {
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "cgutil")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	writeCgroupFiles(t, dir, map[string]string{
+		"cpu.stat": "usage_usec 10\n",
+	})
+
+	stats, err := Stats(dir)
+	require.NoError(err)
+	require.EqualValues(10000, stats.CpuStats.CpuUsage.TotalUsage)
+	require.Empty(stats.MemoryStats.Stats)
+}
@@ -0,0 +1,52 @@
+package cni
+
+// BridgePlugins are the plugins of bridge networks, which must be found in the
+// CNI path of clients running allocations in bridge mode.
+var BridgePlugins = []string{"bridge", "host-local", "portmap"}
+
+// NewBridgeNetwork returns the network of the allocations in bridge mode. The
+// allocations are attached to a bridge of the host, with an address of the
+// subnet and their outgoing traffic masqueraded. The ports mapped from the
+// host are forwarded with iptables rules by the portmap plugin.
+func NewBridgeNetwork(bridge, subnet string) *Network {
+	return &Network{
+		Name:       bridge,
+		CNIVersion: "0.4.0",
+		plugins: []map[string]interface{}{
+			{
+				"type":             "bridge",
+				"bridge":           bridge,
+				"isDefaultGateway": true,
+				"forceAddress":     true,
+				"ipMasq":           true,
+				"hairpinMode":      true,
+				"ipam": map[string]interface{}{
+					"type": "host-local",
+					"ranges": [][]map[string]interface{}{
+						{{"subnet": subnet}},
+					},
+					"routes": []map[string]interface{}{
+						{"dst": "0.0.0.0/0"},
+					},
+				},
+			},
+			{
+				"type": "portmap",
+				"capabilities": map[string]interface{}{
+					"portMappings": true,
+				},
+				"snat": true,
+			},
+		},
+	}
+}
+
+// MissingPlugins returns the plugins missing from the directories of path.
+func MissingPlugins(path []string, plugins []string) (missing []string) {
+	for _, p := range plugins {
+		if _, err := findPlugin(p, path); err != nil {
+			missing = append(missing, p)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,287 @@
+// Package cni invokes the plugins of CNI networks to configure the network
+// namespaces of allocations, as described by the Container Network Interface
+// specification.
+package cni
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/containernetworking/cni/pkg/types"
+	multierror "github.com/hashicorp/go-multierror"
+)
+
+const (
+	// CommandAdd and CommandDel are the commands of the plugins adding a
+	// container to a network and removing it from the network.
+	CommandAdd = "ADD"
+	CommandDel = "DEL"
+
+	// NetNSDir is the directory the network namespaces are bind mounted in,
+	// which is shared with the ip-netns tool.
+	NetNSDir = "/var/run/netns"
+)
+
+// Network is the configuration of a CNI network, loaded from a .conf or
+// .conflist file.
+type Network struct {
+	// Name is the name of the network
+	Name string
+
+	// CNIVersion is the version of the specification the configuration
+	// follows
+	CNIVersion string
+
+	// plugins are the configurations of the plugins of the network, in the
+	// order they add a container to the network
+	plugins []map[string]interface{}
+}
+
+// RuntimeConf is the runtime configuration of a container joining a network.
+type RuntimeConf struct {
+	// ContainerID is the ID of the container
+	ContainerID string
+
+	// NetNS is the path of the network namespace of the container
+	NetNS string
+
+	// IfName is the name of the interface created in the network namespace
+	IfName string
+
+	// PortMappings are the ports mapped from the host into the network
+	// namespace, passed to the plugins with the portMappings capability
+	PortMappings []PortMapping
+}
+
+// PortMapping maps a port of the host to a port in a network namespace. The
+// port is mapped on all the addresses of the host unless HostIP is set.
+type PortMapping struct {
+	HostPort      int    `json:"hostPort"`
+	ContainerPort int    `json:"containerPort"`
+	Protocol      string `json:"protocol"`
+	HostIP        string `json:"hostIP,omitempty"`
+}
+
+// LoadNetworks loads the network configurations of a directory keyed by their
+// name. Files are loaded in lexical order and the first configuration of a
+// network is used. A missing directory has no networks.
+func LoadNetworks(dir string) (map[string]*Network, error) {
+	files, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, f := range files {
+		switch filepath.Ext(f.Name()) {
+		case ".conf", ".conflist", ".json":
+			if !f.IsDir() {
+				names = append(names, f.Name())
+			}
+		}
+	}
+	sort.Strings(names)
+
+	networks := make(map[string]*Network, len(names))
+	var mErr multierror.Error
+	for _, name := range names {
+		network, err := LoadNetwork(filepath.Join(dir, name))
+		if err != nil {
+			mErr.Errors = append(mErr.Errors, err)
+			continue
+		}
+		if _, ok := networks[network.Name]; !ok {
+			networks[network.Name] = network
+		}
+	}
+	return networks, mErr.ErrorOrNil()
+}
+
+// LoadNetwork loads a network configuration. Files with the .conflist
+// extension configure a list of plugins, others a single plugin.
+func LoadNetwork(path string) (*Network, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var network *Network
+	if filepath.Ext(path) == ".conflist" {
+		network, err = parseConfList(data)
+	} else {
+		network, err = parseConf(data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load CNI network %q: %v", path, err)
+	}
+	return network, nil
+}
+
+func parseConfList(data []byte) (*Network, error) {
+	var list struct {
+		CNIVersion string                   `json:"cniVersion"`
+		Name       string                   `json:"name"`
+		Plugins    []map[string]interface{} `json:"plugins"`
+	}
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, err
+	}
+	if list.Name == "" {
+		return nil, fmt.Errorf("missing network name")
+	}
+	if len(list.Plugins) == 0 {
+		return nil, fmt.Errorf("missing plugins")
+	}
+	for i, p := range list.Plugins {
+		if t, _ := p["type"].(string); t == "" {
+			return nil, fmt.Errorf("plugin %d is missing its type", i+1)
+		}
+	}
+	return &Network{
+		Name:       list.Name,
+		CNIVersion: list.CNIVersion,
+		plugins:    list.Plugins,
+	}, nil
+}
+
+func parseConf(data []byte) (*Network, error) {
+	var conf types.NetConf
+	if err := json.Unmarshal(data, &conf); err != nil {
+		return nil, err
+	}
+	if conf.Name == "" {
+		return nil, fmt.Errorf("missing network name")
+	}
+	if conf.Type == "" {
+		return nil, fmt.Errorf("missing plugin type")
+	}
+
+	var plugin map[string]interface{}
+	if err := json.Unmarshal(data, &plugin); err != nil {
+		return nil, err
+	}
+	version, _ := plugin["cniVersion"].(string)
+	return &Network{
+		Name:       conf.Name,
+		CNIVersion: version,
+		plugins:    []map[string]interface{}{plugin},
+	}, nil
+}
+
+// Add adds a container to the network by running the ADD command of its
+// plugins in order, each getting the result of the previous one. The plugins
+// are searched in the directories of path.
+func (n *Network) Add(ctx context.Context, path []string, rt *RuntimeConf) (*Result, error) {
+	var prevResult json.RawMessage
+	for _, plugin := range n.plugins {
+		out, err := n.execPlugin(ctx, path, CommandAdd, plugin, prevResult, rt)
+		if err != nil {
+			return nil, err
+		}
+		prevResult = out
+	}
+	return parseResult(prevResult)
+}
+
+// Del removes a container from the network by running the DEL command of its
+// plugins in reverse order. The result of adding the container is passed to
+// the plugins if known. All plugins are run even if one fails, to release as
+// much of the network as possible.
+func (n *Network) Del(ctx context.Context, path []string, rt *RuntimeConf, prev *Result) error {
+	var prevResult json.RawMessage
+	if prev != nil {
+		prevResult = prev.raw
+	}
+
+	var mErr multierror.Error
+	for i := len(n.plugins) - 1; i >= 0; i-- {
+		if _, err := n.execPlugin(ctx, path, CommandDel, n.plugins[i], prevResult, rt); err != nil {
+			mErr.Errors = append(mErr.Errors, err)
+		}
+	}
+	return mErr.ErrorOrNil()
+}
+
+// execPlugin runs a command of a plugin and returns its output.
+func (n *Network) execPlugin(ctx context.Context, path []string, command string,
+	plugin map[string]interface{}, prevResult json.RawMessage, rt *RuntimeConf) ([]byte, error) {
+
+	pluginType, _ := plugin["type"].(string)
+	bin, err := findPlugin(pluginType, path)
+	if err != nil {
+		return nil, err
+	}
+
+	// The plugins of a list share the name and version of the network
+	conf := make(map[string]interface{}, len(plugin)+3)
+	for k, v := range plugin {
+		conf[k] = v
+	}
+	conf["name"] = n.Name
+	conf["cniVersion"] = n.CNIVersion
+	if len(prevResult) != 0 {
+		conf["prevResult"] = prevResult
+	}
+	if rc := runtimeConfig(plugin, rt); len(rc) != 0 {
+		conf["runtimeConfig"] = rc
+	}
+	stdin, err := json.Marshal(conf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode configuration of plugin %q: %v", pluginType, err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, bin)
+	cmd.Env = append(os.Environ(),
+		"CNI_COMMAND="+command,
+		"CNI_CONTAINERID="+rt.ContainerID,
+		"CNI_NETNS="+rt.NetNS,
+		"CNI_IFNAME="+rt.IfName,
+		"CNI_PATH="+strings.Join(path, string(os.PathListSeparator)),
+	)
+	cmd.Stdin = bytes.NewReader(stdin)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		// Plugins report their errors on stdout
+		var perr types.Error
+		if jerr := json.Unmarshal(stdout.Bytes(), &perr); jerr == nil && perr.Msg != "" {
+			return nil, fmt.Errorf("plugin %q failed to %s: %v", pluginType, command, &perr)
+		}
+		return nil, fmt.Errorf("plugin %q failed to %s: %v: %s", pluginType, command, err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
+
+// runtimeConfig returns the runtime configuration passed to a plugin, made of
+// the capabilities it declares in its configuration.
+func runtimeConfig(plugin map[string]interface{}, rt *RuntimeConf) map[string]interface{} {
+	caps, _ := plugin["capabilities"].(map[string]interface{})
+	rc := make(map[string]interface{})
+	if enabled, _ := caps["portMappings"].(bool); enabled && len(rt.PortMappings) != 0 {
+		rc["portMappings"] = rt.PortMappings
+	}
+	return rc
+}
+
+// findPlugin returns the path of the executable of a plugin.
+func findPlugin(pluginType string, path []string) (string, error) {
+	for _, dir := range path {
+		bin := filepath.Join(dir, pluginType)
+		if fi, err := os.Stat(bin); err == nil && fi.Mode().IsRegular() {
+			return bin, nil
+		}
+	}
+	return "", fmt.Errorf("failed to find plugin %q in %v", pluginType, path)
+}
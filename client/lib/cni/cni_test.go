@@ -0,0 +1,211 @@
+package cni
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testPlugin is a plugin recording its environment and configuration in the
+// directory of the log and printing its result.
+const testPlugin = `#!/bin/sh
+cat > "$LOG_DIR/$CNI_COMMAND-$(basename $0).json"
+env | grep ^CNI_ | sort > "$LOG_DIR/$CNI_COMMAND-$(basename $0).env"
+if [ "$CNI_COMMAND" = "ADD" ]; then
+  echo '%s'
+fi
+`
+
+func writeTestPlugin(t *testing.T, dir, name, result string) {
+	script := []byte(fmt.Sprintf(testPlugin, result))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), script, 0755))
+}
+
+func TestLoadNetworks(t *testing.T) {
+	require := require.New(t)
+	dir, err := ioutil.TempDir("", "nomadtest-cni")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	write := func(name, content string) {
+		require.NoError(ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+	write("10-mynet.conf", `{"cniVersion": "0.3.1", "name": "mynet", "type": "bridge"}`)
+	write("20-list.conflist", `{"cniVersion": "0.4.0", "name": "list", "plugins": [{"type": "bridge"}, {"type": "portmap"}]}`)
+	write("30-mynet.json", `{"cniVersion": "0.4.0", "name": "mynet", "type": "macvlan"}`)
+	write("README", `not a network`)
+
+	networks, err := LoadNetworks(dir)
+	require.NoError(err)
+	require.Len(networks, 2)
+
+	// The first configuration of a network is used
+	require.Equal("0.3.1", networks["mynet"].CNIVersion)
+	require.Equal("bridge", networks["mynet"].plugins[0]["type"])
+	require.Len(networks["list"].plugins, 2)
+
+	// Invalid configurations are reported but don't hide the others
+	write("40-invalid.conflist", `{"name": "invalid", "plugins": [{}]}`)
+	networks, err = LoadNetworks(dir)
+	require.Error(err)
+	require.Contains(err.Error(), "missing its type")
+	require.Len(networks, 2)
+
+	// A missing directory has no networks
+	networks, err = LoadNetworks(filepath.Join(dir, "missing"))
+	require.NoError(err)
+	require.Empty(networks)
+}
+
+func TestNetwork_AddDel(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test plugins are shell scripts")
+	}
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "nomadtest-cni")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+	os.Setenv("LOG_DIR", dir)
+	defer os.Unsetenv("LOG_DIR")
+
+	writeTestPlugin(t, dir, "bridge", `{"cniVersion": "0.4.0", "interfaces": [{"name": "cni0"}, {"name": "eth0", "sandbox": "/var/run/netns/test"}], "ips": [{"version": "4", "address": "10.0.0.1/24", "interface": 0}, {"version": "4", "address": "10.0.0.5/24", "interface": 1}]}`)
+	writeTestPlugin(t, dir, "portmap", `{"cniVersion": "0.4.0", "interfaces": [{"name": "cni0"}, {"name": "eth0", "sandbox": "/var/run/netns/test"}], "ips": [{"version": "4", "address": "10.0.0.1/24", "interface": 0}, {"version": "4", "address": "10.0.0.5/24", "interface": 1}]}`)
+
+	network, err := parseConfList([]byte(`{"cniVersion": "0.4.0", "name": "mynet", "plugins": [{"type": "bridge", "bridge": "cni0"}, {"type": "portmap"}]}`))
+	require.NoError(err)
+
+	rt := &RuntimeConf{
+		ContainerID: "alloc1",
+		NetNS:       "/var/run/netns/test",
+		IfName:      "eth0",
+	}
+	result, err := network.Add(context.Background(), []string{"/missing", dir}, rt)
+	require.NoError(err)
+	require.Equal("10.0.0.5", result.Address("eth0"))
+
+	// The plugins get the runtime configuration in their environment
+	env, err := ioutil.ReadFile(filepath.Join(dir, "ADD-bridge.env"))
+	require.NoError(err)
+	require.Equal("CNI_COMMAND=ADD\nCNI_CONTAINERID=alloc1\nCNI_IFNAME=eth0\nCNI_NETNS=/var/run/netns/test\nCNI_PATH=/missing:"+dir+"\n", string(env))
+
+	// The plugins get the name and version of the network and the result of
+	// the previous plugin
+	var conf map[string]interface{}
+	data, err := ioutil.ReadFile(filepath.Join(dir, "ADD-bridge.json"))
+	require.NoError(err)
+	require.NoError(json.Unmarshal(data, &conf))
+	require.Equal("mynet", conf["name"])
+	require.Equal("0.4.0", conf["cniVersion"])
+	require.Equal("cni0", conf["bridge"])
+	require.NotContains(conf, "prevResult")
+
+	data, err = ioutil.ReadFile(filepath.Join(dir, "ADD-portmap.json"))
+	require.NoError(err)
+	require.NoError(json.Unmarshal(data, &conf))
+	require.Contains(conf, "prevResult")
+
+	require.NoError(network.Del(context.Background(), []string{dir}, rt, result))
+	data, err = ioutil.ReadFile(filepath.Join(dir, "DEL-bridge.json"))
+	require.NoError(err)
+	require.NoError(json.Unmarshal(data, &conf))
+	require.Contains(conf, "prevResult")
+}
+
+func TestNewBridgeNetwork(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test plugins are shell scripts")
+	}
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "nomadtest-cni")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+	os.Setenv("LOG_DIR", dir)
+	defer os.Unsetenv("LOG_DIR")
+
+	path := []string{dir}
+	require.Equal(BridgePlugins, MissingPlugins(path, BridgePlugins))
+	writeTestPlugin(t, dir, "bridge", `{"cniVersion": "0.4.0", "ips": [{"version": "4", "address": "172.26.64.2/20"}]}`)
+	writeTestPlugin(t, dir, "portmap", `{"cniVersion": "0.4.0", "ips": [{"version": "4", "address": "172.26.64.2/20"}]}`)
+	require.Equal([]string{"host-local"}, MissingPlugins(path, BridgePlugins))
+
+	network := NewBridgeNetwork("nomad", "172.26.64.0/20")
+	rt := &RuntimeConf{
+		ContainerID:  "alloc1",
+		NetNS:        "/var/run/netns/test",
+		IfName:       "eth0",
+		PortMappings: []PortMapping{{HostPort: 25000, ContainerPort: 8080, Protocol: "tcp"}},
+	}
+	result, err := network.Add(context.Background(), path, rt)
+	require.NoError(err)
+	require.Equal("172.26.64.2", result.Address("eth0"))
+
+	// The bridge is configured with the subnet
+	var conf map[string]interface{}
+	data, err := ioutil.ReadFile(filepath.Join(dir, "ADD-bridge.json"))
+	require.NoError(err)
+	require.NoError(json.Unmarshal(data, &conf))
+	require.Equal("nomad", conf["bridge"])
+	require.Contains(string(data), `"subnet":"172.26.64.0/20"`)
+	require.NotContains(conf, "runtimeConfig")
+
+	// Only the portmap plugin gets the port mappings
+	conf = nil
+	data, err = ioutil.ReadFile(filepath.Join(dir, "ADD-portmap.json"))
+	require.NoError(err)
+	require.NoError(json.Unmarshal(data, &conf))
+	require.Equal(map[string]interface{}{
+		"portMappings": []interface{}{
+			map[string]interface{}{"hostPort": 25000.0, "containerPort": 8080.0, "protocol": "tcp"},
+		},
+	}, conf["runtimeConfig"])
+}
+
+func TestNetwork_Add_Error(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test plugins are shell scripts")
+	}
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "nomadtest-cni")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	script := "#!/bin/sh\necho '{\"code\": 11, \"msg\": \"no addresses left\"}'\nexit 1\n"
+	require.NoError(ioutil.WriteFile(filepath.Join(dir, "failing"), []byte(script), 0755))
+
+	network, err := parseConf([]byte(`{"cniVersion": "0.4.0", "name": "mynet", "type": "failing"}`))
+	require.NoError(err)
+
+	rt := &RuntimeConf{ContainerID: "alloc1", NetNS: "/var/run/netns/test", IfName: "eth0"}
+	_, err = network.Add(context.Background(), []string{dir}, rt)
+	require.Error(err)
+	require.Contains(err.Error(), "no addresses left")
+
+	// Missing plugins are reported
+	_, err = network.Add(context.Background(), []string{filepath.Join(dir, "missing")}, rt)
+	require.Error(err)
+	require.Contains(err.Error(), `failed to find plugin "failing"`)
+}
+
+func TestResult_Address(t *testing.T) {
+	result, err := parseResult([]byte(`{"cniVersion": "0.2.0", "ip4": {"ip": "10.1.0.3/16"}}`))
+	require.NoError(t, err)
+	require.Equal(t, "10.1.0.3", result.Address("eth0"))
+
+	result, err = parseResult([]byte(`{"cniVersion": "0.4.0", "ips": [{"version": "4", "address": "10.1.0.4/16"}]}`))
+	require.NoError(t, err)
+	require.Equal(t, "10.1.0.4", result.Address("eth0"))
+
+	result, err = parseResult(nil)
+	require.NoError(t, err)
+	require.Empty(t, result.Address("eth0"))
+}
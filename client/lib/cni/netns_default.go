@@ -0,0 +1,23 @@
+// +build !linux
+
+package cni
+
+import "errors"
+
+// errNetNSUnsupported is returned on platforms without network namespaces
+var errNetNSUnsupported = errors.New("network namespaces are only supported on Linux")
+
+// CreateNetNS is only supported on Linux.
+func CreateNetNS(name string) (string, error) {
+	return "", errNetNSUnsupported
+}
+
+// RemoveNetNS is only supported on Linux.
+func RemoveNetNS(path string) error {
+	return errNetNSUnsupported
+}
+
+// InterfaceAddress is only supported on Linux.
+func InterfaceAddress(path, ifName string) (string, error) {
+	return "", errNetNSUnsupported
+}
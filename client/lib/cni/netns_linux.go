@@ -0,0 +1,120 @@
+package cni
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+)
+
+// CreateNetNS creates a network namespace kept alive by a bind mount named
+// after name in NetNSDir and returns its path.
+func CreateNetNS(name string) (string, error) {
+	if err := os.MkdirAll(NetNSDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create network namespace directory: %v", err)
+	}
+
+	path := filepath.Join(NetNSDir, name)
+	f, err := os.OpenFile(path, os.O_RDONLY|os.O_CREATE|os.O_EXCL, 0444)
+	if err != nil {
+		return "", fmt.Errorf("failed to create network namespace mount point: %v", err)
+	}
+	f.Close()
+
+	// Unsharing moves the thread into the new namespace. The thread is never
+	// unlocked so it is terminated with the goroutine instead of being reused
+	// in the namespace.
+	errCh := make(chan error, 1)
+	go func() {
+		runtime.LockOSThread()
+		errCh <- bindNewNetNS(path)
+	}()
+	if err := <-errCh; err != nil {
+		os.Remove(path)
+		return "", err
+	}
+	return path, nil
+}
+
+func bindNewNetNS(path string) error {
+	if err := unix.Unshare(unix.CLONE_NEWNET); err != nil {
+		return fmt.Errorf("failed to create network namespace: %v", err)
+	}
+
+	// The loopback interface of new namespaces is down
+	lo, err := netlink.LinkByName("lo")
+	if err != nil {
+		return fmt.Errorf("failed to find loopback interface: %v", err)
+	}
+	if err := netlink.LinkSetUp(lo); err != nil {
+		return fmt.Errorf("failed to bring up loopback interface: %v", err)
+	}
+
+	threadNS := fmt.Sprintf("/proc/%d/task/%d/ns/net", os.Getpid(), unix.Gettid())
+	if err := unix.Mount(threadNS, path, "none", unix.MS_BIND, ""); err != nil {
+		return fmt.Errorf("failed to bind mount network namespace: %v", err)
+	}
+	return nil
+}
+
+// RemoveNetNS unmounts and removes a network namespace created by
+// CreateNetNS. Removing a missing namespace is not an error.
+func RemoveNetNS(path string) error {
+	if err := unix.Unmount(path, unix.MNT_DETACH); err != nil && err != unix.EINVAL && err != unix.ENOENT {
+		return fmt.Errorf("failed to unmount network namespace: %v", err)
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove network namespace: %v", err)
+	}
+	return nil
+}
+
+// InterfaceAddress returns the first IP address of an interface of a network
+// namespace.
+func InterfaceAddress(path, ifName string) (string, error) {
+	ns, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open network namespace: %v", err)
+	}
+	defer ns.Close()
+
+	type result struct {
+		addr string
+		err  error
+	}
+	resultCh := make(chan result, 1)
+	go func() {
+		// As when creating a namespace, the thread is terminated with the
+		// goroutine instead of being reused in the namespace
+		runtime.LockOSThread()
+		addr, err := interfaceAddress(int(ns.Fd()), ifName)
+		resultCh <- result{addr, err}
+	}()
+	r := <-resultCh
+	return r.addr, r.err
+}
+
+func interfaceAddress(nsFd int, ifName string) (string, error) {
+	if err := unix.Setns(nsFd, unix.CLONE_NEWNET); err != nil {
+		return "", fmt.Errorf("failed to enter network namespace: %v", err)
+	}
+
+	iface, err := net.InterfaceByName(ifName)
+	if err != nil {
+		return "", err
+	}
+	addrs, err := iface.Addrs()
+	if err != nil {
+		return "", err
+	}
+	for _, addr := range addrs {
+		if ipNet, ok := addr.(*net.IPNet); ok && ipNet.IP.IsGlobalUnicast() {
+			return ipNet.IP.String(), nil
+		}
+	}
+	return "", fmt.Errorf("interface %q has no address", ifName)
+}
@@ -0,0 +1,92 @@
+package cni
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+)
+
+// Result is the result of adding a container to a network.
+type Result struct {
+	// Interfaces are the interfaces created by the plugins
+	Interfaces []*Interface `json:"interfaces"`
+
+	// IPs are the addresses assigned by the IPAM plugin
+	IPs []*IPConfig `json:"ips"`
+
+	// IP4 is the IPv4 address of the results of specification versions
+	// prior to 0.3.0
+	IP4 *IPConfig `json:"ip4"`
+
+	// raw is the result as returned by the last plugin
+	raw json.RawMessage
+}
+
+// Interface is an interface created by a plugin.
+type Interface struct {
+	Name    string `json:"name"`
+	Sandbox string `json:"sandbox"`
+}
+
+// IPConfig is an address assigned to an interface.
+type IPConfig struct {
+	// Address is the address in CIDR notation
+	Address string `json:"address"`
+
+	// Interface is the index of the interface of the address in the
+	// interfaces of the result
+	Interface *int `json:"interface"`
+
+	// IP is the address of IP4 results
+	IP string `json:"ip"`
+}
+
+func parseResult(data []byte) (*Result, error) {
+	var r Result
+	if len(data) != 0 {
+		if err := json.Unmarshal(data, &r); err != nil {
+			return nil, fmt.Errorf("failed to decode plugin result: %v", err)
+		}
+	}
+	r.raw = data
+	return &r, nil
+}
+
+// Address returns the IP address assigned to the interface of a container,
+// falling back to the first address of the result when none refers to it.
+// An empty string is returned if no address was assigned.
+func (r *Result) Address(ifName string) string {
+	var first string
+	for _, ip := range r.IPs {
+		addr := stripPrefix(ip.Address)
+		if addr == "" {
+			continue
+		}
+		if first == "" {
+			first = addr
+		}
+		if i := ip.Interface; i != nil && *i >= 0 && *i < len(r.Interfaces) {
+			if iface := r.Interfaces[*i]; iface.Name == ifName && iface.Sandbox != "" {
+				return addr
+			}
+		}
+	}
+	if first != "" {
+		return first
+	}
+	if r.IP4 != nil {
+		return stripPrefix(r.IP4.IP)
+	}
+	return ""
+}
+
+// stripPrefix returns the address of a CIDR notation.
+func stripPrefix(cidr string) string {
+	if ip, _, err := net.ParseCIDR(cidr); err == nil {
+		return ip.String()
+	}
+	if ip := net.ParseIP(cidr); ip != nil {
+		return ip.String()
+	}
+	return ""
+}
@@ -0,0 +1,114 @@
+// Package numa detects the NUMA topology of the host.
+package numa
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/nomad/lib/cpuset"
+)
+
+// sysfsNodePath is the sysfs directory listing the NUMA nodes of the host
+const sysfsNodePath = "/sys/devices/system/node"
+
+// Node is a NUMA node of the host.
+type Node struct {
+	// ID is the ID of the NUMA node
+	ID int
+
+	// Cores is the set of CPU cores local to the NUMA node
+	Cores cpuset.CPUSet
+
+	// MemoryMB is the memory local to the NUMA node
+	MemoryMB int64
+}
+
+// Scan returns the NUMA nodes of the host ordered by their ID. If the host
+// does not expose its NUMA topology, no nodes are returned.
+func Scan() ([]*Node, error) {
+	if runtime.GOOS != "linux" {
+		return nil, nil
+	}
+	return scan(sysfsNodePath)
+}
+
+func scan(root string) ([]*Node, error) {
+	dirs, err := filepath.Glob(filepath.Join(root, "node[0-9]*"))
+	if err != nil {
+		return nil, err
+	}
+
+	var nodes []*Node
+	for _, dir := range dirs {
+		id, err := strconv.Atoi(strings.TrimPrefix(filepath.Base(dir), "node"))
+		if err != nil {
+			continue
+		}
+
+		raw, err := ioutil.ReadFile(filepath.Join(dir, "cpulist"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read cores of NUMA node %d: %v", id, err)
+		}
+		cores, err := cpuset.Parse(string(raw))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse cores of NUMA node %d: %v", id, err)
+		}
+
+		// NUMA nodes without cores only provide memory and can not host
+		// tasks
+		if cores.Size() == 0 {
+			continue
+		}
+
+		memoryMB, err := nodeMemoryMB(filepath.Join(dir, "meminfo"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read memory of NUMA node %d: %v", id, err)
+		}
+
+		nodes = append(nodes, &Node{
+			ID:       id,
+			Cores:    cores,
+			MemoryMB: memoryMB,
+		})
+	}
+
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].ID < nodes[j].ID
+	})
+	return nodes, nil
+}
+
+// nodeMemoryMB parses the total memory of a NUMA node from its meminfo file,
+// which contains lines such as "Node 0 MemTotal:       16333676 kB".
+func nodeMemoryMB(path string) (int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 4 || fields[2] != "MemTotal:" {
+			continue
+		}
+
+		kb, err := strconv.ParseInt(fields[3], 10, 64)
+		if err != nil {
+			return 0, err
+		}
+		return kb / 1024, nil
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+	return 0, fmt.Errorf("missing MemTotal")
+}
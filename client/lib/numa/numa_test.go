@@ -0,0 +1,57 @@
+package numa
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/nomad/lib/cpuset"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScan(t *testing.T) {
+	require := require.New(t)
+	root, err := ioutil.TempDir("", "numa")
+	require.NoError(err)
+	defer os.RemoveAll(root)
+
+	writeNode := func(name, cpulist, meminfo string) {
+		dir := filepath.Join(root, name)
+		require.NoError(os.MkdirAll(dir, 0755))
+		require.NoError(ioutil.WriteFile(filepath.Join(dir, "cpulist"), []byte(cpulist), 0644))
+		require.NoError(ioutil.WriteFile(filepath.Join(dir, "meminfo"), []byte(meminfo), 0644))
+	}
+	writeNode("node1", "8-15,24-31\n", "Node 1 MemTotal:       16777216 kB\nNode 1 MemFree:        1024 kB\n")
+	writeNode("node0", "0-7,16-23\n", "Node 0 MemTotal:       8388608 kB\nNode 0 MemFree:        1024 kB\n")
+
+	// Memory only nodes are skipped
+	writeNode("node2", "\n", "Node 2 MemTotal:       8388608 kB\n")
+
+	// Other entries are ignored
+	require.NoError(ioutil.WriteFile(filepath.Join(root, "online"), []byte("0-2"), 0644))
+
+	nodes, err := scan(root)
+	require.NoError(err)
+	require.Len(nodes, 2)
+
+	require.Equal(0, nodes[0].ID)
+	require.Equal(int64(8192), nodes[0].MemoryMB)
+	expected, _ := cpuset.Parse("0-7,16-23")
+	require.True(expected.Equals(nodes[0].Cores))
+
+	require.Equal(1, nodes[1].ID)
+	require.Equal(int64(16384), nodes[1].MemoryMB)
+	expected, _ = cpuset.Parse("8-15,24-31")
+	require.True(expected.Equals(nodes[1].Cores))
+}
+
+func TestScan_NoTopology(t *testing.T) {
+	root, err := ioutil.TempDir("", "numa")
+	require.NoError(t, err)
+	defer os.RemoveAll(root)
+
+	nodes, err := scan(root)
+	require.NoError(t, err)
+	require.Empty(t, nodes)
+}
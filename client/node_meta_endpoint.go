@@ -0,0 +1,44 @@
+package client
+
+import (
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// NodeMeta endpoint is used for reading and updating the dynamic metadata of
+// the node
+type NodeMeta struct {
+	c *Client
+}
+
+// Read is used to retrieve the metadata of the node.
+func (n *NodeMeta) Read(args *structs.NodeSpecificRequest, reply *cstructs.NodeMetaResponse) error {
+	defer metrics.MeasureSince([]string{"client", "node_meta", "read"}, time.Now())
+
+	// Check node read permissions
+	if aclObj, err := n.c.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNodeRead() {
+		return structs.ErrPermissionDenied
+	}
+
+	*reply = *n.c.nodeMeta()
+	return nil
+}
+
+// Apply is used to set and unset the dynamic metadata of the node. The update
+// is forwarded to the servers, which own the metadata, and the result is
+// persisted so the client can restore it if the servers lose the node.
+func (n *NodeMeta) Apply(args *structs.NodeUpdateDynamicMetaRequest, reply *structs.NodeDynamicMetaUpdateResponse) error {
+	defer metrics.MeasureSince([]string{"client", "node_meta", "apply"}, time.Now())
+
+	args.NodeID = n.c.NodeID()
+	if err := n.c.RPC("Node.UpdateDynamicMeta", args, reply); err != nil {
+		return err
+	}
+
+	return n.c.persistDynamicMeta(args.Set, args.Unset)
+}
@@ -0,0 +1,101 @@
+package client
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/nomad/client/config"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodeMeta_Apply(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1, addr := testServer(t, nil)
+	defer s1.Shutdown()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	client, cleanup := TestClient(t, func(c *config.Config) {
+		// Persist the client state
+		c.DevMode = false
+		c.Servers = []string{addr}
+		c.Node.Meta = map[string]string{"static": "1"}
+	})
+	defer cleanup()
+
+	// Wait for the node to register
+	testutil.WaitForResult(func() (bool, error) {
+		node, err := s1.State().NodeByID(nil, client.NodeID())
+		if err != nil {
+			return false, err
+		}
+		if node == nil {
+			return false, fmt.Errorf("node not registered")
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("err: %v", err)
+	})
+
+	// Set two keys then unset one
+	req := &structs.NodeUpdateDynamicMetaRequest{
+		Set:          map[string]string{"rack": "r1", "zone": "a"},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.NodeDynamicMetaUpdateResponse
+	require.NoError(client.ClientRPC("NodeMeta.Apply", req, &resp))
+
+	req = &structs.NodeUpdateDynamicMetaRequest{
+		Unset:        []string{"zone"},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	require.NoError(client.ClientRPC("NodeMeta.Apply", req, &resp))
+
+	// The servers own the dynamic metadata, which the client merges over the
+	// configured metadata of the node
+	expected := map[string]string{"rack": "r1"}
+	node, err := s1.State().NodeByID(nil, client.NodeID())
+	require.NoError(err)
+	require.Equal(expected, node.DynamicMeta)
+	require.Equal(map[string]string{"static": "1", "rack": "r1"}, client.Node().Meta)
+
+	// The node re-registers with its merged metadata once the node update
+	// interval elapses
+	testutil.WaitForResultRetries(1000*testutil.TestMultiplier(), func() (bool, error) {
+		node, err := s1.State().NodeByID(nil, client.NodeID())
+		if err != nil {
+			return false, err
+		}
+		if node.Meta["rack"] != "r1" || node.Meta["zone"] != "" {
+			return false, fmt.Errorf("node meta not updated: %v", node.Meta)
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("err: %v", err)
+	})
+
+	// The client persists its copy
+	meta, err := client.stateDB.GetNodeMeta()
+	require.NoError(err)
+	require.Equal(expected, meta)
+	require.Equal(expected, client.Node().DynamicMeta)
+
+	// Reading returns the effective metadata along with both parts
+	var readResp cstructs.NodeMetaResponse
+	require.NoError(client.ClientRPC("NodeMeta.Read", &structs.NodeSpecificRequest{}, &readResp))
+	require.Equal(map[string]string{"static": "1", "rack": "r1"}, readResp.Meta)
+	require.Equal(map[string]string{"static": "1"}, readResp.Static)
+	require.Equal(expected, readResp.Dynamic)
+
+	// Invalid requests are rejected by the servers and not persisted
+	req = &structs.NodeUpdateDynamicMetaRequest{
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	err = client.ClientRPC("NodeMeta.Apply", req, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "at least one")
+}
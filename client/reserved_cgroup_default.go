@@ -0,0 +1,17 @@
+// +build !linux
+
+package client
+
+import "fmt"
+
+// reserveCgroupSlice fails if a reserved cgroup slice is configured, as
+// cgroups are only available on Linux.
+func (c *Client) reserveCgroupSlice() error {
+	c.configLock.RLock()
+	defer c.configLock.RUnlock()
+
+	if c.config.ReservedCgroupSlice != "" {
+		return fmt.Errorf("reserving a cgroup slice is only supported on Linux")
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+// +build linux
+
+package client
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/nomad/client/lib/cgutil"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// reserveCgroupSlice reserves the CPU quota and memory limit of the
+// configured cgroup slice on the node, in place of the static reserved CPU
+// and memory. It must run once the node is fingerprinted, as the quota is
+// converted to MHz from the compute of the cores of the node.
+func (c *Client) reserveCgroupSlice() error {
+	c.configLock.Lock()
+	defer c.configLock.Unlock()
+
+	slice := c.config.ReservedCgroupSlice
+	if slice == "" {
+		return nil
+	}
+
+	limits, err := cgutil.SliceLimits(slice)
+	if err != nil {
+		return fmt.Errorf("failed to read limits of reserved cgroup slice: %v", err)
+	}
+	if err := reserveSliceLimits(c.config.Node, limits); err != nil {
+		return err
+	}
+
+	res := c.config.Node.ReservedResources
+	c.logger.Info("reserved resources of cgroup slice", "slice", slice,
+		"cpu", res.Cpu.CpuShares, "memory_mb", res.Memory.MemoryMB)
+	c.updateNodeLocked()
+	return nil
+}
+
+// reserveSliceLimits sets the reserved CPU and memory of the node from the
+// limits of a cgroup slice. Resources the slice doesn't limit keep their
+// static reservation.
+func reserveSliceLimits(node *structs.Node, limits *cgutil.Limits) error {
+	if limits.CPUCores > 0 {
+		cores, err := strconv.Atoi(node.Attributes["cpu.numcores"])
+		if err != nil || cores <= 0 {
+			return fmt.Errorf("failed to reserve CPU of cgroup slice: unknown number of cores")
+		}
+
+		total := node.NodeResources.Cpu.CpuShares
+		shares := int64(limits.CPUCores * float64(total) / float64(cores))
+		if shares > total {
+			shares = total
+		}
+		node.ReservedResources.Cpu.CpuShares = shares
+
+		// COMPAT(0.10): Remove in 0.10
+		node.Reserved.CPU = int(shares)
+	}
+
+	if limits.MemoryBytes > 0 {
+		memoryMB := int64(limits.MemoryBytes / MB)
+		node.ReservedResources.Memory.MemoryMB = memoryMB
+
+		// COMPAT(0.10): Remove in 0.10
+		node.Reserved.MemoryMB = int(memoryMB)
+	}
+	return nil
+}
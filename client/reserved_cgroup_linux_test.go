@@ -0,0 +1,38 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/client/lib/cgutil"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReserveSliceLimits(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	node := mock.Node()
+	node.Attributes["cpu.numcores"] = "4"
+	node.NodeResources.Cpu.CpuShares = 10000
+	node.ReservedResources.Cpu.CpuShares = 100
+	node.ReservedResources.Memory.MemoryMB = 256
+
+	// Only the limited resources are reserved from the slice
+	require.NoError(reserveSliceLimits(node, &cgutil.Limits{CPUCores: 1.5}))
+	require.EqualValues(3750, node.ReservedResources.Cpu.CpuShares)
+	require.Equal(3750, node.Reserved.CPU)
+	require.EqualValues(256, node.ReservedResources.Memory.MemoryMB)
+
+	require.NoError(reserveSliceLimits(node, &cgutil.Limits{MemoryBytes: 2 << 30}))
+	require.EqualValues(3750, node.ReservedResources.Cpu.CpuShares)
+	require.EqualValues(2048, node.ReservedResources.Memory.MemoryMB)
+	require.Equal(2048, node.Reserved.MemoryMB)
+
+	// A quota above the cores of the node reserves all of its compute
+	require.NoError(reserveSliceLimits(node, &cgutil.Limits{CPUCores: 8}))
+	require.EqualValues(10000, node.ReservedResources.Cpu.CpuShares)
+
+	delete(node.Attributes, "cpu.numcores")
+	require.Error(reserveSliceLimits(node, &cgutil.Limits{CPUCores: 1}))
+}
@@ -23,6 +23,9 @@ type rpcEndpoints struct {
 	ClientStats *ClientStats
 	FileSystem  *FileSystem
 	Allocations *Allocations
+	Agent       *Agent
+	NodeMeta    *NodeMeta
+	HostVolume  *HostVolume
 }
 
 // ClientRPC is used to make a local, client only RPC call
@@ -217,7 +220,10 @@ func (c *Client) setupClientRpc() {
 	// Initialize the RPC handlers
 	c.endpoints.ClientStats = &ClientStats{c}
 	c.endpoints.FileSystem = NewFileSystemEndpoint(c)
-	c.endpoints.Allocations = &Allocations{c}
+	c.endpoints.Allocations = NewAllocationsEndpoint(c)
+	c.endpoints.Agent = NewAgentEndpoint(c)
+	c.endpoints.NodeMeta = &NodeMeta{c}
+	c.endpoints.HostVolume = &HostVolume{c}
 
 	// Create the RPC Server
 	c.rpcServer = rpc.NewServer()
@@ -234,6 +240,8 @@ func (c *Client) setupClientRpcServer(server *rpc.Server) {
 	server.Register(c.endpoints.ClientStats)
 	server.Register(c.endpoints.FileSystem)
 	server.Register(c.endpoints.Allocations)
+	server.Register(c.endpoints.NodeMeta)
+	server.Register(c.endpoints.HostVolume)
 }
 
 // rpcConnListener is a long lived function that listens for new connections
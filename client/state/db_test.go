@@ -8,6 +8,7 @@ import (
 
 	trstate "github.com/hashicorp/nomad/client/allocrunner/taskrunner/state"
 	dmstate "github.com/hashicorp/nomad/client/devicemanager/state"
+	"github.com/hashicorp/nomad/helper/testlog"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/kr/pretty"
@@ -18,7 +19,7 @@ func setupBoltDB(t *testing.T) (*BoltStateDB, func()) {
 	dir, err := ioutil.TempDir("", "nomadtest")
 	require.NoError(t, err)
 
-	db, err := NewBoltStateDB(dir)
+	db, err := NewBoltStateDB(testlog.HCLogger(t), dir)
 	if err != nil {
 		if err := os.RemoveAll(dir); err != nil {
 			t.Logf("error removing boltdb dir: %v", err)
@@ -216,3 +217,27 @@ func TestStateDB_DeviceManager(t *testing.T) {
 		require.Equal(state, ps)
 	})
 }
+
+// TestStateDB_NodeMeta asserts the behavior of node metadata related StateDB
+// methods.
+func TestStateDB_NodeMeta(t *testing.T) {
+	t.Parallel()
+
+	testDB(t, func(t *testing.T, db StateDB) {
+		require := require.New(t)
+
+		// Getting nonexistent metadata should return nil
+		meta, err := db.GetNodeMeta()
+		require.NoError(err)
+		require.Nil(meta)
+
+		// Putting metadata should work
+		orig := map[string]string{"rack": "r1"}
+		require.NoError(db.PutNodeMeta(orig))
+
+		// Getting should return the stored metadata
+		meta, err = db.GetNodeMeta()
+		require.NoError(err)
+		require.Equal(orig, meta)
+	})
+}
@@ -0,0 +1,517 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/boltdb/bolt"
+	hclog "github.com/hashicorp/go-hclog"
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/helper/boltdd"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// stateDBFile is the name of the boltdb state database in the state
+	// directory
+	stateDBFile = "state.db"
+
+	// compactMinSize is the size of the state database below which it isn't
+	// compacted when opened
+	compactMinSize = 1 << 20
+
+	// compactFreeRatio is the fraction of the state database that must be
+	// free for it to be compacted when opened
+	compactFreeRatio = 0.5
+
+	// maintenanceOpenTimeout bounds waiting for the lock of the state
+	// database, which is held as long as a client is running
+	maintenanceOpenTimeout = 1 * time.Second
+)
+
+// ErrStateDBInUse is returned when the state database is locked by a running
+// client.
+var ErrStateDBInUse = errors.New("state database is in use by a running client")
+
+// StateDBPath returns the path of the boltdb state database of the state
+// directory.
+func StateDBPath(stateDir string) string {
+	return filepath.Join(stateDir, stateDBFile)
+}
+
+// BoltStateDBInfo describes a boltdb state database.
+type BoltStateDBInfo struct {
+	// Size is the size of the database file in bytes.
+	Size int64
+
+	// FreeBytes is the size of the free pages of the database and of the
+	// space past its last page, which is reclaimed by compacting it.
+	FreeBytes int64
+
+	// CheckErrors are the integrity errors of the database.
+	CheckErrors []error
+
+	// Allocations are the allocations stored in the database, and
+	// AllocErrors the errors of the allocations that couldn't be decoded.
+	Allocations []*structs.Allocation
+	AllocErrors map[string]error
+}
+
+// NeedsCompaction returns true if enough of the database is free for
+// compacting it to be worthwhile.
+func (i *BoltStateDBInfo) NeedsCompaction() bool {
+	return i.Size >= compactMinSize && float64(i.FreeBytes) >= compactFreeRatio*float64(i.Size)
+}
+
+// InspectBoltStateDB checks the integrity of the boltdb state database at path
+// and reads its allocations. The database must not be in use.
+func InspectBoltStateDB(path string) (*BoltStateDBInfo, error) {
+	db, err := openBoltForMaintenance(path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := statBoltDB(path, db)
+	db.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	// Allocations are only decoded from a consistent database, as reading
+	// corrupt pages may panic
+	if len(info.CheckErrors) != 0 {
+		return info, nil
+	}
+
+	sdb, err := boltdd.Open(path, 0600, &bolt.Options{ReadOnly: true, Timeout: maintenanceOpenTimeout})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open state database: %v", err)
+	}
+	defer sdb.Close()
+
+	s := &BoltStateDB{db: sdb}
+	err = sdb.View(func(tx *boltdd.Tx) error {
+		info.Allocations, info.AllocErrors = s.getAllAllocations(tx)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// CompactBoltStateDB rewrites the boltdb state database at path without its
+// free pages, returning its size before and after the compaction. The database
+// must not be in use.
+func CompactBoltStateDB(path string) (int64, int64, error) {
+	src, err := openBoltForMaintenance(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer src.Close()
+
+	fi, err := os.Stat(path)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	tmpPath := path + ".compact"
+	dst, err := bolt.Open(tmpPath, fi.Mode(), &bolt.Options{Timeout: maintenanceOpenTimeout})
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to create compacted state database: %v", err)
+	}
+
+	if _, err := copyBoltDB(src, dst, false); err != nil {
+		dst.Close()
+		os.Remove(tmpPath)
+		return 0, 0, fmt.Errorf("failed to compact state database: %v", err)
+	}
+	if err := dst.Close(); err != nil {
+		os.Remove(tmpPath)
+		return 0, 0, fmt.Errorf("failed to write compacted state database: %v", err)
+	}
+
+	// Release the lock of the database before replacing it
+	src.Close()
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return 0, 0, fmt.Errorf("failed to replace state database: %v", err)
+	}
+
+	compacted, err := os.Stat(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	return fi.Size(), compacted.Size(), nil
+}
+
+// BoltStateDBRepair is the result of repairing a state database.
+type BoltStateDBRepair struct {
+	// BackupPath is the path the corrupt database was moved to.
+	BackupPath string
+
+	// Lost are the buckets that couldn't be recovered, such as
+	// "allocations/<alloc-id>".
+	Lost []string
+}
+
+// RepairBoltStateDB recovers the readable buckets of the boltdb state
+// database at path into a new database. The corrupt database is kept next to
+// it for inspection. Allocations whose state is lost are garbage collected by
+// the client once it restarts. The database must not be in use.
+func RepairBoltStateDB(path string) (*BoltStateDBRepair, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	repair := &BoltStateDBRepair{
+		BackupPath: fmt.Sprintf("%s.corrupt-%d", path, time.Now().Unix()),
+	}
+
+	// Fail early if the database is in use, before moving it
+	src, err := openBoltForMaintenance(path)
+	if err == ErrStateDBInUse {
+		return nil, err
+	}
+	if src != nil {
+		src.Close()
+	}
+	if err := os.Rename(path, repair.BackupPath); err != nil {
+		return nil, fmt.Errorf("failed to move corrupt state database: %v", err)
+	}
+
+	dst, err := bolt.Open(path, fi.Mode(), &bolt.Options{Timeout: maintenanceOpenTimeout})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create state database: %v", err)
+	}
+	defer dst.Close()
+
+	src, err = openBoltForMaintenance(repair.BackupPath)
+	if err != nil {
+		// Nothing can be recovered from a database that can't be opened
+		repair.Lost = []string{"*"}
+		return repair, nil
+	}
+	defer src.Close()
+
+	repair.Lost, err = copyBoltDB(src, dst, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to recover state database: %v", err)
+	}
+	return repair, nil
+}
+
+// openBoltForMaintenance opens the database at path read-only, failing with
+// ErrStateDBInUse if it's locked by a client. Opening a database with corrupt
+// meta pages may panic, which is returned as an error.
+func openBoltForMaintenance(path string) (db *bolt.DB, err error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			db, err = nil, fmt.Errorf("failed to open state database: %v", r)
+		}
+	}()
+
+	db, err = bolt.Open(path, 0600, &bolt.Options{
+		ReadOnly: true,
+		Timeout:  maintenanceOpenTimeout,
+	})
+	if err == bolt.ErrTimeout {
+		return nil, ErrStateDBInUse
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to open state database: %v", err)
+	}
+	return db, nil
+}
+
+// statBoltDB returns the size, free space and integrity errors of the open
+// database at path.
+func statBoltDB(path string, db *bolt.DB) (*BoltStateDBInfo, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	info := &BoltStateDBInfo{
+		Size: fi.Size(),
+	}
+
+	err = db.View(func(tx *bolt.Tx) error {
+		if tx.Size() > info.Size {
+			info.CheckErrors = []error{fmt.Errorf("state database is truncated to %d bytes, expected %d", info.Size, tx.Size())}
+			return nil
+		}
+		info.CheckErrors = checkBoltTx(tx)
+		if len(info.CheckErrors) != 0 {
+			return nil
+		}
+
+		// The stats of a read-only database aren't updated, so the free
+		// pages are counted from the freelist, in addition to the space
+		// past the last page
+		freePages, err := countFreePages(tx)
+		if err != nil {
+			return err
+		}
+		info.FreeBytes = int64(freePages*db.Info().PageSize) + info.Size - tx.Size()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// checkBoltTx returns the integrity errors of the database of the
+// transaction. Bolt's own check may not terminate on corrupt pages, so
+// instead all the buckets are read, their root pages being checked to be
+// within the database, and the panics and faults of reading corrupt pages are
+// returned as errors.
+func checkBoltTx(tx *bolt.Tx) []error {
+	defer debug.SetPanicOnFault(debug.SetPanicOnFault(true))
+
+	names, err := bucketNames(tx.Cursor())
+	if err != nil {
+		return []error{err}
+	}
+
+	var errs []error
+	for _, name := range names {
+		errs = append(errs, checkBucket(tx, tx.Bucket(name), string(name))...)
+	}
+	return errs
+}
+
+// checkBucket returns the errors reading the bucket and its nested buckets.
+func checkBucket(tx *bolt.Tx, b *bolt.Bucket, path string) (errs []error) {
+	defer func() {
+		if r := recover(); r != nil {
+			errs = append(errs, fmt.Errorf("failed to read bucket %q: %v", path, r))
+		}
+	}()
+
+	if b == nil {
+		return []error{fmt.Errorf("missing bucket %q", path)}
+	}
+	if err := checkBucketRoot(tx, b); err != nil {
+		return []error{fmt.Errorf("invalid bucket %q: %v", path, err)}
+	}
+
+	var nested [][]byte
+	c := b.Cursor()
+	for k, v := c.First(); k != nil; k, v = c.Next() {
+		if v == nil {
+			nested = append(nested, append([]byte(nil), k...))
+		}
+	}
+
+	for _, k := range nested {
+		errs = append(errs, checkBucket(tx, b.Bucket(k), path+"/"+string(k))...)
+	}
+	return errs
+}
+
+// checkBucketRoot returns an error if the root page of the bucket, including
+// its overflow pages, isn't a bucket page within the database.
+func checkBucketRoot(tx *bolt.Tx, b *bolt.Bucket) error {
+	// Inline buckets are stored in the page of their parent
+	id := int(b.Root())
+	if id == 0 {
+		return nil
+	}
+
+	p, err := tx.Page(id)
+	if err != nil {
+		return err
+	}
+	if p == nil {
+		return fmt.Errorf("root page %d is past the end of the database", id)
+	}
+	if p.Type != "branch" && p.Type != "leaf" {
+		return fmt.Errorf("root page %d is a %s page", id, p.Type)
+	}
+	if last, err := tx.Page(id + p.OverflowCount); err != nil || last == nil {
+		return fmt.Errorf("root page %d overflows past the end of the database", id)
+	}
+	return nil
+}
+
+// countFreePages returns the number of pages of the database of the
+// transaction that are on its freelist.
+func countFreePages(tx *bolt.Tx) (int, error) {
+	var n int
+	for id := 0; ; id++ {
+		p, err := tx.Page(id)
+		if err != nil {
+			return 0, err
+		}
+		if p == nil {
+			return n, nil
+		}
+		if p.Type == "free" {
+			n++
+		}
+	}
+}
+
+// copyBoltDB copies the buckets of src into dst. With salvage set, the
+// buckets that can't be read are skipped and returned, otherwise the first
+// error is returned.
+func copyBoltDB(src, dst *bolt.DB, salvage bool) ([]string, error) {
+	// Return the faults of reading corrupt pages as panics
+	defer debug.SetPanicOnFault(debug.SetPanicOnFault(true))
+
+	var lost []string
+	err := src.View(func(srcTx *bolt.Tx) error {
+		return dst.Update(func(dstTx *bolt.Tx) error {
+			names, err := bucketNames(srcTx.Cursor())
+			if err != nil {
+				if !salvage {
+					return err
+				}
+				lost = append(lost, "*")
+				return nil
+			}
+
+			for _, name := range names {
+				err := copyBucket(srcTx.Bucket(name), dstTx, name, string(name), salvage, &lost)
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+	return lost, err
+}
+
+// bucketParent is a transaction or bucket that buckets are created in.
+type bucketParent interface {
+	CreateBucket([]byte) (*bolt.Bucket, error)
+	DeleteBucket([]byte) error
+}
+
+// copyBucket copies the src bucket and its nested buckets into parent. With
+// salvage set, the nested buckets that can't be read are skipped and their
+// path appended to lost.
+func copyBucket(src *bolt.Bucket, parent bucketParent, name []byte, path string, salvage bool, lost *[]string) error {
+	err := copyBucketContents(src, parent, name, path, salvage, lost)
+	if err == nil || !salvage {
+		return err
+	}
+
+	// Drop the partially copied bucket
+	parent.DeleteBucket(name)
+	*lost = append(*lost, path)
+	return nil
+}
+
+// copyBucketContents copies a bucket, returning reading errors and panics on
+// corrupt pages.
+func copyBucketContents(src *bolt.Bucket, parent bucketParent, name []byte, path string, salvage bool, lost *[]string) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("failed to read bucket %q: %v", path, r)
+		}
+	}()
+
+	if src == nil {
+		return fmt.Errorf("missing bucket %q", path)
+	}
+
+	dst, err := parent.CreateBucket(name)
+	if err != nil {
+		return err
+	}
+	dst.FillPercent = 1.0
+
+	var nested [][]byte
+	c := src.Cursor()
+	for k, v := c.First(); k != nil; k, v = c.Next() {
+		if v == nil {
+			// Copy the nested buckets once the values are read, so a
+			// corrupt nested bucket only loses itself
+			nested = append(nested, append([]byte(nil), k...))
+			continue
+		}
+		if err := dst.Put(k, v); err != nil {
+			return err
+		}
+	}
+
+	for _, k := range nested {
+		err := copyBucket(src.Bucket(k), dst, k, path+"/"+string(k), salvage, lost)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// bucketNames returns the names of the root buckets of a transaction.
+func bucketNames(c *bolt.Cursor) (names [][]byte, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("failed to list buckets: %v", r)
+		}
+	}()
+
+	for k, _ := c.First(); k != nil; k, _ = c.Next() {
+		names = append(names, append([]byte(nil), k...))
+	}
+	return names, nil
+}
+
+// maintainBoltStateDB checks the state database at path before the client
+// opens it, repairing it if it's corrupt and compacting it if enough of it is
+// free.
+func maintainBoltStateDB(logger hclog.Logger, path string) error {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil
+	}
+
+	var info *BoltStateDBInfo
+	db, err := openBoltForMaintenance(path)
+	if err == nil {
+		info, err = statBoltDB(path, db)
+		db.Close()
+	}
+	if err == ErrStateDBInUse {
+		return err
+	}
+
+	if err != nil || len(info.CheckErrors) != 0 {
+		if err == nil {
+			err = multierror.Append(nil, info.CheckErrors...)
+		}
+		logger.Error("client state database is corrupt, recovering it", "path", path, "error", err)
+
+		repair, err := RepairBoltStateDB(path)
+		if err != nil {
+			return fmt.Errorf("failed to repair corrupt state database: %v", err)
+		}
+		if len(repair.Lost) != 0 {
+			logger.Warn("client state could not be fully recovered", "lost", strings.Join(repair.Lost, ", "))
+		}
+		logger.Info("recovered client state database", "backup", repair.BackupPath)
+		return nil
+	}
+
+	if info.NeedsCompaction() {
+		before, after, err := CompactBoltStateDB(path)
+		if err != nil {
+			// The database is still usable
+			logger.Warn("failed to compact client state database", "error", err)
+			return nil
+		}
+		logger.Info("compacted client state database", "size_before", before, "size_after", after)
+	}
+	return nil
+}
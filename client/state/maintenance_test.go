@@ -0,0 +1,139 @@
+package state
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+// setupStateDir returns a state directory with a state database storing the
+// allocations.
+func setupStateDir(t *testing.T, allocs ...*structs.Allocation) (string, func()) {
+	dir, err := ioutil.TempDir("", "nomadtest")
+	require.NoError(t, err)
+	cleanup := func() {
+		os.RemoveAll(dir)
+	}
+
+	db, err := NewBoltStateDB(testlog.HCLogger(t), dir)
+	require.NoError(t, err)
+	for _, alloc := range allocs {
+		require.NoError(t, db.PutAllocation(alloc))
+	}
+	require.NoError(t, db.Close())
+	return dir, cleanup
+}
+
+func TestBoltStateDB_Inspect(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	alloc := mock.Alloc()
+	dir, cleanup := setupStateDir(t, alloc)
+	defer cleanup()
+
+	info, err := InspectBoltStateDB(StateDBPath(dir))
+	require.NoError(err)
+	require.Empty(info.CheckErrors)
+	require.Empty(info.AllocErrors)
+	require.Len(info.Allocations, 1)
+	require.Equal(alloc.ID, info.Allocations[0].ID)
+	require.NotZero(info.Size)
+
+	// The database can't be inspected while a client uses it
+	db, err := NewBoltStateDB(testlog.HCLogger(t), dir)
+	require.NoError(err)
+	defer db.Close()
+
+	_, err = InspectBoltStateDB(StateDBPath(dir))
+	require.Equal(ErrStateDBInUse, err)
+}
+
+func TestBoltStateDB_Compact(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	kept := mock.Alloc()
+	dir, cleanup := setupStateDir(t, kept)
+	defer cleanup()
+
+	// Grow the database and free most of it
+	db, err := NewBoltStateDB(testlog.HCLogger(t), dir)
+	require.NoError(err)
+	var ids []string
+	for i := 0; i < 500; i++ {
+		alloc := mock.Alloc()
+		require.NoError(db.PutAllocation(alloc))
+		ids = append(ids, alloc.ID)
+	}
+	for _, id := range ids {
+		require.NoError(db.DeleteAllocationBucket(id))
+	}
+	require.NoError(db.Close())
+
+	path := StateDBPath(dir)
+	info, err := InspectBoltStateDB(path)
+	require.NoError(err)
+	require.True(info.NeedsCompaction(), "size %d, free %d", info.Size, info.FreeBytes)
+
+	before, after, err := CompactBoltStateDB(path)
+	require.NoError(err)
+	require.Equal(info.Size, before)
+	require.True(after < before, "size before %d, after %d", before, after)
+
+	// The state is kept
+	info, err = InspectBoltStateDB(path)
+	require.NoError(err)
+	require.False(info.NeedsCompaction())
+	require.Len(info.Allocations, 1)
+	require.Equal(kept.ID, info.Allocations[0].ID)
+}
+
+func TestBoltStateDB_Repair(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	var allocs []*structs.Allocation
+	for i := 0; i < 50; i++ {
+		allocs = append(allocs, mock.Alloc())
+	}
+	dir, cleanup := setupStateDir(t, allocs...)
+	defer cleanup()
+
+	// Corrupt the pages following the meta and freelist pages
+	path := StateDBPath(dir)
+	f, err := os.OpenFile(path, os.O_RDWR, 0600)
+	require.NoError(err)
+	garbage := make([]byte, 4*os.Getpagesize())
+	for i := range garbage {
+		garbage[i] = 0xff
+	}
+	_, err = f.WriteAt(garbage, int64(4*os.Getpagesize()))
+	require.NoError(err)
+	require.NoError(f.Close())
+
+	info, err := InspectBoltStateDB(path)
+	require.NoError(err)
+	require.NotEmpty(info.CheckErrors)
+
+	// Opening the database repairs it, keeping the corrupt one
+	db, err := NewBoltStateDB(testlog.HCLogger(t), dir)
+	require.NoError(err)
+	_, _, err = db.GetAllAllocations()
+	require.NoError(err)
+	require.NoError(db.Close())
+
+	info, err = InspectBoltStateDB(path)
+	require.NoError(err)
+	require.Empty(info.CheckErrors)
+
+	backups, err := filepath.Glob(path + ".corrupt-*")
+	require.NoError(err)
+	require.Len(backups, 1)
+}
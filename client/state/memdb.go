@@ -21,6 +21,9 @@ type MemDB struct {
 	// devicemanager -> plugin-state
 	devManagerPs *dmstate.PluginState
 
+	// nodemeta -> meta
+	nodeMeta map[string]string
+
 	mu sync.RWMutex
 }
 
@@ -150,6 +153,19 @@ func (m *MemDB) GetDevicePluginState() (*dmstate.PluginState, error) {
 	return m.devManagerPs, nil
 }
 
+func (m *MemDB) PutNodeMeta(meta map[string]string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.nodeMeta = meta
+	return nil
+}
+
+func (m *MemDB) GetNodeMeta() (map[string]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.nodeMeta, nil
+}
+
 func (m *MemDB) Close() error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
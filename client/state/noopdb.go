@@ -49,6 +49,14 @@ func (n NoopDB) GetDevicePluginState() (*dmstate.PluginState, error) {
 	return nil, nil
 }
 
+func (n NoopDB) PutNodeMeta(meta map[string]string) error {
+	return nil
+}
+
+func (n NoopDB) GetNodeMeta() (map[string]string, error) {
+	return nil, nil
+}
+
 func (n NoopDB) Close() error {
 	return nil
 }
@@ -2,8 +2,8 @@ package state
 
 import (
 	"fmt"
-	"path/filepath"
 
+	hclog "github.com/hashicorp/go-hclog"
 	trstate "github.com/hashicorp/nomad/client/allocrunner/taskrunner/state"
 	dmstate "github.com/hashicorp/nomad/client/devicemanager/state"
 	"github.com/hashicorp/nomad/helper/boltdd"
@@ -22,6 +22,9 @@ allocations/ (bucket)
 
 devicemanager/
 |--> plugin-state -> *dmstate.PluginState
+
+nodemeta/
+|--> meta -> map[string]string
 */
 
 var (
@@ -48,16 +51,23 @@ var (
 	// devManagerPluginStateKey is the key serialized device manager
 	// plugin state is stored at
 	devManagerPluginStateKey = []byte("plugin_state")
+
+	// nodeMetaBucket is the bucket name containing the dynamic metadata of
+	// the node
+	nodeMetaBucket = []byte("nodemeta")
+
+	// nodeMetaKey is the key the dynamic metadata of the node is stored at
+	nodeMetaKey = []byte("meta")
 )
 
 // NewStateDBFunc creates a StateDB given a state directory.
-type NewStateDBFunc func(stateDir string) (StateDB, error)
+type NewStateDBFunc func(logger hclog.Logger, stateDir string) (StateDB, error)
 
 // GetStateDBFactory returns a func for creating a StateDB
 func GetStateDBFactory(devMode bool) NewStateDBFunc {
 	// Return a noop state db implementation when in debug mode
 	if devMode {
-		return func(string) (StateDB, error) {
+		return func(hclog.Logger, string) (StateDB, error) {
 			return NoopDB{}, nil
 		}
 	}
@@ -72,10 +82,16 @@ type BoltStateDB struct {
 }
 
 // NewBoltStateDB creates or opens an existing boltdb state file or returns an
-// error.
-func NewBoltStateDB(stateDir string) (StateDB, error) {
+// error. An existing state file is checked for corruption and repaired, and
+// compacted if enough of it is free.
+func NewBoltStateDB(logger hclog.Logger, stateDir string) (StateDB, error) {
+	path := StateDBPath(stateDir)
+	if err := maintainBoltStateDB(logger, path); err != nil {
+		return nil, err
+	}
+
 	// Create or open the boltdb state database
-	db, err := boltdd.Open(filepath.Join(stateDir, "state.db"), 0600, nil)
+	db, err := boltdd.Open(path, 0600, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create state database: %v", err)
 	}
@@ -413,3 +429,43 @@ func (s *BoltStateDB) GetDevicePluginState() (*dmstate.PluginState, error) {
 
 	return ps, nil
 }
+
+// PutNodeMeta stores the dynamic metadata of the node or returns an error.
+func (s *BoltStateDB) PutNodeMeta(meta map[string]string) error {
+	return s.db.Update(func(tx *boltdd.Tx) error {
+		metaBkt, err := tx.CreateBucketIfNotExists(nodeMetaBucket)
+		if err != nil {
+			return err
+		}
+
+		return metaBkt.Put(nodeMetaKey, meta)
+	})
+}
+
+// GetNodeMeta retrieves the dynamic metadata of the node or returns an error.
+// Nil is returned if no metadata has been stored.
+func (s *BoltStateDB) GetNodeMeta() (map[string]string, error) {
+	var meta map[string]string
+
+	err := s.db.View(func(tx *boltdd.Tx) error {
+		metaBkt := tx.Bucket(nodeMetaBucket)
+		if metaBkt == nil {
+			// No state, return
+			return nil
+		}
+
+		if err := metaBkt.Get(nodeMetaKey, &meta); err != nil {
+			if !boltdd.IsErrNotFound(err) {
+				return fmt.Errorf("failed to read node metadata: %v", err)
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return meta, nil
+}
@@ -38,6 +38,50 @@ type ClientStatsResponse struct {
 	structs.QueryMeta
 }
 
+// NodeMetaResponse is used to return the metadata of a node.
+type NodeMetaResponse struct {
+	// Meta is the effective metadata of the node, which is the static
+	// metadata overridden by the dynamic one.
+	Meta map[string]string
+
+	// Static is the metadata set by the configuration of the client
+	Static map[string]string
+
+	// Dynamic is the metadata set at runtime, which is the dynamic metadata
+	// of the node
+	Dynamic map[string]string
+}
+
+// HostVolumeCreateRequest is used to create a host volume on a node and
+// register it with the servers.
+type HostVolumeCreateRequest struct {
+	NodeID string
+
+	// Provisioner is the name of the provisioner creating the volume. It
+	// defaults to the builtin mkdir provisioner.
+	Provisioner string
+
+	// Volume is the volume to create. Its node ID and external ID are set
+	// by the client.
+	Volume *structs.Volume
+
+	structs.QueryOptions
+}
+
+// HostVolumeCreateResponse is used to return the created host volume.
+type HostVolumeCreateResponse struct {
+	Volume *structs.Volume
+}
+
+// HostVolumeDeleteRequest is used to delete a host volume created on a node
+// and deregister it.
+type HostVolumeDeleteRequest struct {
+	NodeID   string
+	VolumeID string
+
+	structs.QueryOptions
+}
+
 // AllocFileInfo holds information about a file inside the AllocDir
 type AllocFileInfo struct {
 	Name     string
@@ -133,12 +177,68 @@ type FsLogsRequest struct {
 	// PlainText disables base64 encoding.
 	PlainText bool
 
+	// Format is the format of the streamed logs, one of the LogFormat
+	// constants. PlainText overrides it.
+	Format string
+
+	// File is the name of the log file to resume streaming from, such as the
+	// File of the last received LogFrame. The offset is then applied within
+	// that file and the origin is ignored. If the file has been rotated out,
+	// streaming resumes at the start of the next log file.
+	File string
+
 	// Follow follows logs.
 	Follow bool
 
 	structs.QueryOptions
 }
 
+const (
+	// LogFormatFrames streams the logs as JSON StreamFrames with base64
+	// encoded data.
+	LogFormatFrames = "frames"
+
+	// LogFormatJSON streams the logs as newline delimited JSON LogFrames.
+	LogFormatJSON = "json"
+
+	// LogFormatPlain streams the logs as is.
+	LogFormatPlain = "plain"
+)
+
+// LogFrame is a frame of the logs of a task streamed in the JSON format.
+type LogFrame struct {
+	// File is the name of the log file the data was read from
+	File string `json:",omitempty"`
+
+	// Index is the rotation index of the log file
+	Index int64
+
+	// Offset is the offset in the log file following the data, from which
+	// the stream can be resumed.
+	Offset int64
+
+	// Data is the read data
+	Data string `json:",omitempty"`
+
+	// FileEvent is the last file event that occurred on the log file, such
+	// as its truncation.
+	FileEvent string `json:",omitempty"`
+
+	// Timestamp is the time the data was read
+	Timestamp time.Time
+}
+
+// MonitorRequest is the initial request for streaming the logs of an agent.
+type MonitorRequest struct {
+	// LogLevel is the minimum level of the streamed logs
+	LogLevel string
+
+	// NodeID is the client node whose agent logs are streamed
+	NodeID string
+
+	structs.QueryOptions
+}
+
 // StreamErrWrapper is used to serialize output of a stream of a file or logs.
 type StreamErrWrapper struct {
 	// Error stores any error that may have occurred.
@@ -160,6 +260,89 @@ type AllocStatsRequest struct {
 	structs.QueryOptions
 }
 
+// AllocRestartRequest is used to restart a task of an allocation, or all its
+// tasks.
+type AllocRestartRequest struct {
+	// AllocID is the allocation to restart
+	AllocID string
+
+	// TaskName is the task to restart. All the tasks of the allocation are
+	// restarted if it is empty.
+	TaskName string
+
+	structs.QueryOptions
+}
+
+// AllocSignalRequest is used to send a signal to a task of an allocation, or
+// to all its tasks.
+type AllocSignalRequest struct {
+	// AllocID is the allocation to signal
+	AllocID string
+
+	// Task is the task to signal. All the tasks of the allocation are
+	// signaled if it is empty.
+	Task string
+
+	// Signal is the name of the signal to send, such as SIGHUP
+	Signal string
+
+	structs.QueryOptions
+}
+
+// AllocExecRequest is the initial request for executing an interactive
+// command inside a task.
+type AllocExecRequest struct {
+	// AllocID is the allocation of the task to execute the command in
+	AllocID string
+
+	// Task is the task to execute the command in
+	Task string
+
+	// Tty indicates whether a pseudo-terminal is allocated for the command
+	Tty bool
+
+	// Cmd is the command to execute, including its arguments
+	Cmd []string
+
+	structs.QueryOptions
+}
+
+// ExecStreamingIOOperation is a write to or the close of a stream of an exec
+// session. Data and Close are mutually exclusive.
+type ExecStreamingIOOperation struct {
+	Data  []byte `json:"data,omitempty"`
+	Close bool   `json:"close,omitempty"`
+}
+
+// TerminalSize is the size of the terminal of an exec session in characters.
+type TerminalSize struct {
+	Height int `json:"height,omitempty"`
+	Width  int `json:"width,omitempty"`
+}
+
+// ExecStreamingInput is a message sent by the caller of an exec session.
+type ExecStreamingInput struct {
+	Stdin   *ExecStreamingIOOperation `json:"stdin,omitempty"`
+	TTYSize *TerminalSize             `json:"tty_size,omitempty"`
+}
+
+// ExecStreamingExitResult is the exit result of the command of an exec
+// session.
+type ExecStreamingExitResult struct {
+	ExitCode int `json:"exit_code"`
+}
+
+// ExecStreamingOutput is a message sent to the caller of an exec session. The
+// last message of a session has Exited set along with the exit result of the
+// command.
+type ExecStreamingOutput struct {
+	Stdout *ExecStreamingIOOperation `json:"stdout,omitempty"`
+	Stderr *ExecStreamingIOOperation `json:"stderr,omitempty"`
+
+	Exited bool                     `json:"exited,omitempty"`
+	Result *ExecStreamingExitResult `json:"result,omitempty"`
+}
+
 // AllocStatsResponse is used to return the resource usage of a given
 // allocation.
 type AllocStatsResponse struct {
@@ -175,6 +358,11 @@ type MemoryStats struct {
 	MaxUsage       uint64
 	KernelUsage    uint64
 	KernelMaxUsage uint64
+	Usage          uint64
+
+	// BalloonActual is the memory currently available to a virtual machine
+	// guest as reported by its memory balloon device
+	BalloonActual uint64
 
 	// A list of fields whose values were actually sampled
 	Measured []string
@@ -187,6 +375,8 @@ func (ms *MemoryStats) Add(other *MemoryStats) {
 	ms.MaxUsage += other.MaxUsage
 	ms.KernelUsage += other.KernelUsage
 	ms.KernelMaxUsage += other.KernelMaxUsage
+	ms.Usage += other.Usage
+	ms.BalloonActual += other.BalloonActual
 	ms.Measured = joinStringSet(ms.Measured, other.Measured)
 }
 
@@ -213,17 +403,45 @@ func (cs *CpuStats) Add(other *CpuStats) {
 	cs.Measured = joinStringSet(cs.Measured, other.Measured)
 }
 
+// IOStats holds block I/O usage related stats
+type IOStats struct {
+	ReadBytes  uint64
+	WriteBytes uint64
+	ReadOps    uint64
+	WriteOps   uint64
+
+	// A list of fields whose values were actually sampled
+	Measured []string
+}
+
+func (is *IOStats) Add(other *IOStats) {
+	is.ReadBytes += other.ReadBytes
+	is.WriteBytes += other.WriteBytes
+	is.ReadOps += other.ReadOps
+	is.WriteOps += other.WriteOps
+	is.Measured = joinStringSet(is.Measured, other.Measured)
+}
+
 // ResourceUsage holds information related to cpu and memory stats
 type ResourceUsage struct {
 	MemoryStats *MemoryStats
 	CpuStats    *CpuStats
 	DeviceStats []*device.DeviceGroupStats
+
+	// IOStats is nil if the driver doesn't measure block I/O
+	IOStats *IOStats
 }
 
 func (ru *ResourceUsage) Add(other *ResourceUsage) {
 	ru.MemoryStats.Add(other.MemoryStats)
 	ru.CpuStats.Add(other.CpuStats)
 	ru.DeviceStats = append(ru.DeviceStats, other.DeviceStats...)
+	if other.IOStats != nil {
+		if ru.IOStats == nil {
+			ru.IOStats = &IOStats{}
+		}
+		ru.IOStats.Add(other.IOStats)
+	}
 }
 
 // TaskResourceUsage holds aggregated resource usage of all processes in a Task
@@ -76,6 +76,10 @@ const (
 	// bind to this port.
 	PortPrefix = "NOMAD_PORT_"
 
+	// VariablePrefix is the prefix used for interpolating the items of the
+	// variables of the task, eg ${nomad_var.password}.
+	VariablePrefix = "nomad_var."
+
 	// HostPortPrefix is the prefix for passing the host port when a port
 	// map is specified.
 	HostPortPrefix = "NOMAD_HOST_PORT_"
@@ -285,6 +289,9 @@ type Builder struct {
 	injectVaultToken bool
 	jobName          string
 
+	// variables are the items of the variables of the task
+	variables map[string]string
+
 	// otherPorts for tasks in the same alloc
 	otherPorts map[string]string
 
@@ -404,6 +411,11 @@ func (b *Builder) Build() *TaskEnv {
 		nodeAttrs[k] = v
 	}
 
+	// Copy the items of the variables of the task
+	for k, v := range b.variables {
+		nodeAttrs[VariablePrefix+k] = v
+	}
+
 	// Interpolate and add environment variables
 	for k, v := range b.hostEnv {
 		envMap[k] = hargs.ReplaceEnv(v, nodeAttrs, envMap)
@@ -532,6 +544,11 @@ func (b *Builder) setAlloc(alloc *structs.Allocation) *Builder {
 			}
 		}
 
+		// Add the networks shared by the tasks of the group
+		for _, n := range alloc.AllocatedResources.Shared.Networks {
+			b.networks = append(b.networks, n.Copy())
+		}
+
 		// Add ports from other tasks
 		for taskName, resources := range alloc.AllocatedResources.Tasks {
 			// Add ports from other tasks
@@ -541,10 +558,10 @@ func (b *Builder) setAlloc(alloc *structs.Allocation) *Builder {
 
 			for _, nw := range resources.Networks {
 				for _, p := range nw.ReservedPorts {
-					addPort(b.otherPorts, taskName, nw.IP, p.Label, p.Value)
+					addPort(b.otherPorts, taskName, p.IP(nw), p.Label, p.Value)
 				}
 				for _, p := range nw.DynamicPorts {
-					addPort(b.otherPorts, taskName, nw.IP, p.Label, p.Value)
+					addPort(b.otherPorts, taskName, p.IP(nw), p.Label, p.Value)
 				}
 			}
 		}
@@ -556,10 +573,10 @@ func (b *Builder) setAlloc(alloc *structs.Allocation) *Builder {
 			}
 			for _, nw := range resources.Networks {
 				for _, p := range nw.ReservedPorts {
-					addPort(b.otherPorts, taskName, nw.IP, p.Label, p.Value)
+					addPort(b.otherPorts, taskName, p.IP(nw), p.Label, p.Value)
 				}
 				for _, p := range nw.DynamicPorts {
-					addPort(b.otherPorts, taskName, nw.IP, p.Label, p.Value)
+					addPort(b.otherPorts, taskName, p.IP(nw), p.Label, p.Value)
 				}
 			}
 		}
@@ -630,10 +647,10 @@ func (b *Builder) SetDriverNetwork(n *cstructs.DriverNetwork) *Builder {
 func buildNetworkEnv(envMap map[string]string, nets structs.Networks, driverNet *cstructs.DriverNetwork) {
 	for _, n := range nets {
 		for _, p := range n.ReservedPorts {
-			buildPortEnv(envMap, p, n.IP, driverNet)
+			buildPortEnv(envMap, p, p.IP(n), driverNet)
 		}
 		for _, p := range n.DynamicPorts {
-			buildPortEnv(envMap, p, n.IP, driverNet)
+			buildPortEnv(envMap, p, p.IP(n), driverNet)
 		}
 	}
 }
@@ -697,6 +714,15 @@ func (b *Builder) SetVaultToken(token string, inject bool) *Builder {
 	return b
 }
 
+// SetVariables sets the items of the variables of the task, available for
+// interpolation as nomad_var.<key>.
+func (b *Builder) SetVariables(items map[string]string) *Builder {
+	b.mu.Lock()
+	b.variables = items
+	b.mu.Unlock()
+	return b
+}
+
 // addPort keys and values for other tasks to an env var map
 func addPort(m map[string]string, taskName, ip, portLabel string, port int) {
 	key := fmt.Sprintf("%s%s_%s", AddrPrefix, taskName, portLabel)
@@ -493,6 +493,23 @@ func TestEnvironment_VaultToken(t *testing.T) {
 	}
 }
 
+func TestEnvironment_Variables(t *testing.T) {
+	n := mock.Node()
+	a := mock.Alloc()
+	task := a.Job.TaskGroups[0].Tasks[0]
+	task.Env = map[string]string{"PASSWORD": "${nomad_var.password}"}
+	env := NewBuilder(n, a, task, "global")
+	env.SetVariables(map[string]string{"password": "hunter2"})
+
+	act := env.Build()
+	if act.EnvMap["PASSWORD"] != "hunter2" {
+		t.Fatalf("Unexpected environment variables: PASSWORD=%q", act.EnvMap["PASSWORD"])
+	}
+	if act.All()["nomad_var.password"] != "hunter2" {
+		t.Fatalf("Variable item not found in: %v", act.All())
+	}
+}
+
 func TestEnvironment_Envvars(t *testing.T) {
 	envMap := map[string]string{"foo": "baz", "bar": "bang"}
 	n := mock.Node()
@@ -670,3 +687,33 @@ func TestEnvironment_InterpolateEmptyOptionalMeta(t *testing.T) {
 	require.Equal("metaopt1val", env.ReplaceEnv("${NOMAD_META_metaopt1}"))
 	require.Empty(env.ReplaceEnv("${NOMAD_META_metaopt2}"))
 }
+
+// TestEnvironment_SharedNetworks asserts the ports of the network shared by
+// the tasks of a group are set, mapped to the ports the tasks listen on.
+func TestEnvironment_SharedNetworks(t *testing.T) {
+	require := require.New(t)
+	a := mock.Alloc()
+	a.AllocatedResources.Shared.Networks = structs.Networks{
+		{
+			Mode: structs.NetworkModeBridge,
+			IP:   "10.0.0.1",
+			DynamicPorts: []structs.Port{
+				{Label: "http", Value: 25000, To: 8080},
+				{Label: "admin", Value: 25001, HostNetwork: "private", HostIP: "10.1.0.5"},
+			},
+		},
+	}
+	task := a.Job.TaskGroups[0].Tasks[0]
+	env := NewBuilder(mock.Node(), a, task, "global").SetDriverNetwork(&cstructs.DriverNetwork{
+		PortMap: map[string]int{"http": 8080},
+	}).Build().Map()
+
+	require.Equal("10.0.0.1", env["NOMAD_IP_http"])
+	require.Equal("25000", env["NOMAD_HOST_PORT_http"])
+	require.Equal("8080", env["NOMAD_PORT_http"])
+	require.Equal("10.0.0.1:25000", env["NOMAD_ADDR_http"])
+
+	// Ports bound to a host network are reached on its address
+	require.Equal("10.1.0.5", env["NOMAD_IP_admin"])
+	require.Equal("10.1.0.5:25001", env["NOMAD_ADDR_admin"])
+}
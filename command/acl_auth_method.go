@@ -0,0 +1,96 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/mitchellh/cli"
+)
+
+type ACLAuthMethodCommand struct {
+	Meta
+}
+
+func (f *ACLAuthMethodCommand) Help() string {
+	helpText := `
+Usage: nomad acl auth-method <subcommand> [options] [args]
+
+  This command groups subcommands for interacting with ACL auth methods. Auth
+  methods allow users to log in with an external identity provider, and are
+  given ACL tokens bound by the binding rules of the method.
+
+  Create an ACL auth method:
+
+      $ nomad acl auth-method create -name=<name> -config=<config-file>
+
+  List ACL auth methods:
+
+      $ nomad acl auth-method list
+
+  Inspect an ACL auth method:
+
+      $ nomad acl auth-method info <name>
+
+  Please see the individual subcommand help for detailed usage information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (f *ACLAuthMethodCommand) Synopsis() string {
+	return "Interact with ACL auth methods"
+}
+
+func (f *ACLAuthMethodCommand) Name() string { return "acl auth-method" }
+
+func (f *ACLAuthMethodCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
+
+// readACLAuthMethodConfig reads the JSON configuration of an auth method from
+// the file, or from stdin if the path is "-".
+func readACLAuthMethodConfig(path string) (*api.ACLAuthMethodConfig, error) {
+	var raw []byte
+	var err error
+	if path == "-" {
+		raw, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		raw, err = ioutil.ReadFile(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read config: %v", err)
+	}
+
+	var config api.ACLAuthMethodConfig
+	if err := json.Unmarshal(raw, &config); err != nil {
+		return nil, fmt.Errorf("Failed to parse config: %v", err)
+	}
+	return &config, nil
+}
+
+// formatKVACLAuthMethod returns a K/V formatted ACL auth method
+func formatKVACLAuthMethod(method *api.ACLAuthMethod) string {
+	output := []string{
+		fmt.Sprintf("Name|%s", method.Name),
+		fmt.Sprintf("Type|%s", method.Type),
+		fmt.Sprintf("Locality|%s", method.TokenLocality),
+		fmt.Sprintf("Max Token TTL|%v", method.MaxTokenTTL),
+		fmt.Sprintf("Default|%v", method.Default),
+		fmt.Sprintf("Create Index|%d", method.CreateIndex),
+		fmt.Sprintf("Modify Index|%d", method.ModifyIndex),
+	}
+	return formatKV(output)
+}
+
+// formatACLAuthMethodConfig returns the indented JSON of an auth method's
+// configuration
+func formatACLAuthMethodConfig(config *api.ACLAuthMethodConfig) (string, error) {
+	out, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
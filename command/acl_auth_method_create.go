@@ -0,0 +1,139 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+)
+
+type ACLAuthMethodCreateCommand struct {
+	Meta
+}
+
+func (c *ACLAuthMethodCreateCommand) Help() string {
+	helpText := `
+Usage: nomad acl auth-method create [options]
+
+  Create is used to create new ACL auth methods. Requires a management token.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Create Options:
+
+  -name=""
+    Sets the name of the ACL auth method.
+
+  -type="OIDC"
+    Sets the type of the auth method. Supported types are "OIDC" and "JWT".
+
+  -token-locality="local"
+    Sets the locality of the tokens created by the auth method. Must be one of
+    "local" (default), or "global". Global tokens are replicated to all regions.
+
+  -max-token-ttl="1h"
+    Sets the duration the tokens created by the auth method are valid for.
+
+  -default=false
+    Toggles whether the auth method is used when logging in without specifying
+    one. Only one auth method can be the default.
+
+  -config=""
+    Path to the JSON configuration of the auth method, or "-" to read it from
+    stdin.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *ACLAuthMethodCreateCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-name":           complete.PredictAnything,
+			"-type":           complete.PredictSet(api.ACLAuthMethodTypeOIDC, api.ACLAuthMethodTypeJWT),
+			"-token-locality": complete.PredictSet(api.ACLAuthMethodTokenLocalityLocal, api.ACLAuthMethodTokenLocalityGlobal),
+			"-max-token-ttl":  complete.PredictAnything,
+			"-default":        complete.PredictNothing,
+			"-config":         complete.PredictFiles("*.json"),
+		})
+}
+
+func (c *ACLAuthMethodCreateCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *ACLAuthMethodCreateCommand) Synopsis() string {
+	return "Create a new ACL auth method"
+}
+
+func (c *ACLAuthMethodCreateCommand) Name() string { return "acl auth-method create" }
+
+func (c *ACLAuthMethodCreateCommand) Run(args []string) int {
+	var name, methodType, locality, configPath string
+	var maxTokenTTL time.Duration
+	var isDefault bool
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&name, "name", "", "")
+	flags.StringVar(&methodType, "type", api.ACLAuthMethodTypeOIDC, "")
+	flags.StringVar(&locality, "token-locality", api.ACLAuthMethodTokenLocalityLocal, "")
+	flags.DurationVar(&maxTokenTTL, "max-token-ttl", time.Hour, "")
+	flags.BoolVar(&isDefault, "default", false, "")
+	flags.StringVar(&configPath, "config", "", "")
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got no arguments
+	args = flags.Args()
+	if l := len(args); l != 0 {
+		c.Ui.Error("This command takes no arguments")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	if name == "" {
+		c.Ui.Error("ACL auth method name must be specified using the -name flag")
+		return 1
+	}
+	if configPath == "" {
+		c.Ui.Error("ACL auth method config must be specified using the -config flag")
+		return 1
+	}
+	config, err := readACLAuthMethodConfig(configPath)
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	// Setup the auth method
+	method := &api.ACLAuthMethod{
+		Name:          name,
+		Type:          methodType,
+		TokenLocality: locality,
+		MaxTokenTTL:   maxTokenTTL,
+		Default:       isDefault,
+		Config:        config,
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	// Create the auth method
+	method, _, err = client.ACLAuthMethods().Create(method, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error creating ACL auth method: %s", err))
+		return 1
+	}
+
+	// Format the output
+	c.Ui.Output(formatKVACLAuthMethod(method))
+	return 0
+}
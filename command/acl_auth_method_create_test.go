@@ -0,0 +1,62 @@
+package command
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/command/agent"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestACLAuthMethodCreateCommand(t *testing.T) {
+	require := require.New(t)
+	t.Parallel()
+	config := func(c *agent.Config) {
+		c.ACL.Enabled = true
+	}
+
+	srv, _, url := testServer(t, true, config)
+	defer srv.Shutdown()
+	token := srv.RootToken
+	require.NotNil(token, "failed to bootstrap ACL token")
+
+	// Write the config to a file
+	f, err := ioutil.TempFile("", "nomad-test")
+	require.NoError(err)
+	defer os.Remove(f.Name())
+	configFile := f.Name()
+	require.NoError(ioutil.WriteFile(configFile, []byte(`{
+  "OIDCDiscoveryURL": "https://example.okta.com",
+  "OIDCClientID": "nomad",
+  "AllowedRedirectURIs": ["http://localhost:4649/oidc/callback"]
+}`), 0644))
+
+	ui := new(cli.MockUi)
+	cmd := &ACLAuthMethodCreateCommand{Meta: Meta{Ui: ui, flagAddress: url}}
+
+	// The name and config are required
+	code := cmd.Run([]string{"-address=" + url, "-token=" + token.SecretID, "-config=" + configFile})
+	require.Equal(1, code)
+	require.Contains(ui.ErrorWriter.String(), "-name")
+	ui.ErrorWriter.Reset()
+
+	// Creating requires a management token
+	code = cmd.Run([]string{"-address=" + url, "-token=foo", "-name=okta", "-config=" + configFile})
+	require.Equal(1, code)
+
+	code = cmd.Run([]string{"-address=" + url, "-token=" + token.SecretID,
+		"-name=okta", "-max-token-ttl=10m", "-default", "-config=" + configFile})
+	require.Equal(0, code, ui.ErrorWriter.String())
+
+	out := ui.OutputWriter.String()
+	require.True(strings.Contains(out, "okta"))
+	require.Contains(out, "10m0s")
+
+	method, err := srv.Agent.Server().State().ACLAuthMethodByName(nil, "okta")
+	require.NoError(err)
+	require.True(method.Default)
+	require.Equal("nomad", method.Config.OIDCClientID)
+}
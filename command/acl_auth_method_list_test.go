@@ -0,0 +1,41 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/command/agent"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestACLAuthMethodListCommand(t *testing.T) {
+	require := require.New(t)
+	t.Parallel()
+	config := func(c *agent.Config) {
+		c.ACL.Enabled = true
+	}
+
+	srv, _, url := testServer(t, true, config)
+	state := srv.Agent.Server().State()
+	defer srv.Shutdown()
+
+	method := mock.ACLAuthMethod()
+	require.NoError(state.UpsertACLAuthMethods(1000, []*structs.ACLAuthMethod{method}))
+
+	ui := new(cli.MockUi)
+	cmd := &ACLAuthMethodListCommand{Meta: Meta{Ui: ui, flagAddress: url}}
+
+	// Listing doesn't require a management token
+	token := mock.ACLToken()
+	require.NoError(state.UpsertACLTokens(1001, []*structs.ACLToken{token}))
+	code := cmd.Run([]string{"-address=" + url, "-token=" + token.SecretID})
+	require.Equal(0, code, ui.ErrorWriter.String())
+	require.Contains(ui.OutputWriter.String(), method.Name)
+
+	ui.OutputWriter.Reset()
+	code = cmd.Run([]string{"-address=" + url, "-token=" + token.SecretID, "-json"})
+	require.Equal(0, code)
+	require.Contains(ui.OutputWriter.String(), `"Name": "`+method.Name+`"`)
+}
@@ -0,0 +1,134 @@
+package command
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+)
+
+type ACLAuthMethodUpdateCommand struct {
+	Meta
+}
+
+func (c *ACLAuthMethodUpdateCommand) Help() string {
+	helpText := `
+Usage: nomad acl auth-method update [options] <name>
+
+  Update is used to update an existing ACL auth method. Only the options given
+  are updated. Requires a management token.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Update Options:
+
+  -token-locality=""
+    Sets the locality of the tokens created by the auth method. Must be one of
+    "local", or "global". Global tokens are replicated to all regions.
+
+  -max-token-ttl=""
+    Sets the duration the tokens created by the auth method are valid for.
+
+  -default=false
+    Toggles whether the auth method is used when logging in without specifying
+    one. Only one auth method can be the default.
+
+  -config=""
+    Path to the JSON configuration of the auth method, or "-" to read it from
+    stdin.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *ACLAuthMethodUpdateCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-token-locality": complete.PredictSet(api.ACLAuthMethodTokenLocalityLocal, api.ACLAuthMethodTokenLocalityGlobal),
+			"-max-token-ttl":  complete.PredictAnything,
+			"-default":        complete.PredictNothing,
+			"-config":         complete.PredictFiles("*.json"),
+		})
+}
+
+func (c *ACLAuthMethodUpdateCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *ACLAuthMethodUpdateCommand) Synopsis() string {
+	return "Update an existing ACL auth method"
+}
+
+func (c *ACLAuthMethodUpdateCommand) Name() string { return "acl auth-method update" }
+
+func (c *ACLAuthMethodUpdateCommand) Run(args []string) int {
+	var locality, configPath string
+	var maxTokenTTL time.Duration
+	var isDefault bool
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&locality, "token-locality", "", "")
+	flags.DurationVar(&maxTokenTTL, "max-token-ttl", 0, "")
+	flags.BoolVar(&isDefault, "default", false, "")
+	flags.StringVar(&configPath, "config", "", "")
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got exactly one argument
+	args = flags.Args()
+	if l := len(args); l != 1 {
+		c.Ui.Error("This command takes one argument: <name>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+	name := args[0]
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	// Get the current auth method
+	method, _, err := client.ACLAuthMethods().Info(name, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error fetching ACL auth method: %s", err))
+		return 1
+	}
+
+	// Only update the options that were given
+	var flagErr error
+	flags.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "token-locality":
+			method.TokenLocality = locality
+		case "max-token-ttl":
+			method.MaxTokenTTL = maxTokenTTL
+		case "default":
+			method.Default = isDefault
+		case "config":
+			method.Config, flagErr = readACLAuthMethodConfig(configPath)
+		}
+	})
+	if flagErr != nil {
+		c.Ui.Error(flagErr.Error())
+		return 1
+	}
+
+	// Update the auth method
+	method, _, err = client.ACLAuthMethods().Update(method, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error updating ACL auth method: %s", err))
+		return 1
+	}
+
+	// Format the output
+	c.Ui.Output(formatKVACLAuthMethod(method))
+	return 0
+}
@@ -0,0 +1,46 @@
+package command
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/command/agent"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestACLAuthMethodUpdateCommand(t *testing.T) {
+	require := require.New(t)
+	t.Parallel()
+	config := func(c *agent.Config) {
+		c.ACL.Enabled = true
+	}
+
+	srv, _, url := testServer(t, true, config)
+	state := srv.Agent.Server().State()
+	defer srv.Shutdown()
+	token := srv.RootToken
+	require.NotNil(token, "failed to bootstrap ACL token")
+
+	method := mock.ACLAuthMethod()
+	require.NoError(state.UpsertACLAuthMethods(1000, []*structs.ACLAuthMethod{method}))
+
+	ui := new(cli.MockUi)
+	cmd := &ACLAuthMethodUpdateCommand{Meta: Meta{Ui: ui, flagAddress: url}}
+
+	// Updating requires a management token
+	code := cmd.Run([]string{"-address=" + url, "-token=foo", "-max-token-ttl=2h", method.Name})
+	require.Equal(1, code)
+
+	// Only the given options are updated
+	code = cmd.Run([]string{"-address=" + url, "-token=" + token.SecretID, "-max-token-ttl=2h", method.Name})
+	require.Equal(0, code, ui.ErrorWriter.String())
+
+	out, err := state.ACLAuthMethodByName(nil, method.Name)
+	require.NoError(err)
+	require.Equal(2*time.Hour, out.MaxTokenTTL)
+	require.Equal(method.TokenLocality, out.TokenLocality)
+	require.Equal(method.Config, out.Config)
+}
@@ -0,0 +1,70 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/mitchellh/cli"
+)
+
+type ACLBindingRuleCommand struct {
+	Meta
+}
+
+func (f *ACLBindingRuleCommand) Help() string {
+	helpText := `
+Usage: nomad acl binding-rule <subcommand> [options] [args]
+
+  This command groups subcommands for interacting with ACL binding rules.
+  Binding rules bind the users of an auth method whose claims match the
+  selector of the rule to an ACL policy or to a management token.
+
+  Create an ACL binding rule:
+
+      $ nomad acl binding-rule create -auth-method=<name> -bind-type=policy \
+          -bind-name=<policy> -selector='"engineering" in list.groups'
+
+  List ACL binding rules:
+
+      $ nomad acl binding-rule list
+
+  Inspect an ACL binding rule:
+
+      $ nomad acl binding-rule info <id>
+
+  Please see the individual subcommand help for detailed usage information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (f *ACLBindingRuleCommand) Synopsis() string {
+	return "Interact with ACL binding rules"
+}
+
+func (f *ACLBindingRuleCommand) Name() string { return "acl binding-rule" }
+
+func (f *ACLBindingRuleCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
+
+// formatKVACLBindingRule returns a K/V formatted ACL binding rule
+func formatKVACLBindingRule(rule *api.ACLBindingRule) string {
+	output := []string{
+		fmt.Sprintf("ID|%s", rule.ID),
+		fmt.Sprintf("Description|%s", rule.Description),
+		fmt.Sprintf("Auth Method|%s", rule.AuthMethod),
+		fmt.Sprintf("Selector|%s", rule.Selector),
+		fmt.Sprintf("Bind Type|%s", rule.BindType),
+	}
+	if rule.BindType == api.ACLBindingRuleBindTypeManagement {
+		output = append(output, "Bind Name|n/a")
+	} else {
+		output = append(output, fmt.Sprintf("Bind Name|%s", rule.BindName))
+	}
+	output = append(output,
+		fmt.Sprintf("Create Index|%d", rule.CreateIndex),
+		fmt.Sprintf("Modify Index|%d", rule.ModifyIndex),
+	)
+	return formatKV(output)
+}
@@ -0,0 +1,121 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+)
+
+type ACLBindingRuleCreateCommand struct {
+	Meta
+}
+
+func (c *ACLBindingRuleCreateCommand) Help() string {
+	helpText := `
+Usage: nomad acl binding-rule create [options]
+
+  Create is used to create new ACL binding rules. Requires a management token.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Create Options:
+
+  -auth-method=""
+    Sets the name of the ACL auth method the binding rule applies to.
+
+  -description=""
+    Sets the human readable description of the binding rule.
+
+  -selector=""
+    Sets the selector matched against the claims of users logging in. Users
+    match the rule if the selector is empty.
+
+  -bind-type="policy"
+    Sets the type of the binding. Must be one of "policy" (default), or
+    "management".
+
+  -bind-name=""
+    Sets the name of the policy users matching the rule are bound to. Must not
+    be set for management bindings.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *ACLBindingRuleCreateCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-auth-method": complete.PredictAnything,
+			"-description": complete.PredictAnything,
+			"-selector":    complete.PredictAnything,
+			"-bind-type":   complete.PredictSet(api.ACLBindingRuleBindTypePolicy, api.ACLBindingRuleBindTypeManagement),
+			"-bind-name":   complete.PredictAnything,
+		})
+}
+
+func (c *ACLBindingRuleCreateCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *ACLBindingRuleCreateCommand) Synopsis() string {
+	return "Create a new ACL binding rule"
+}
+
+func (c *ACLBindingRuleCreateCommand) Name() string { return "acl binding-rule create" }
+
+func (c *ACLBindingRuleCreateCommand) Run(args []string) int {
+	var authMethod, description, selector, bindType, bindName string
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&authMethod, "auth-method", "", "")
+	flags.StringVar(&description, "description", "", "")
+	flags.StringVar(&selector, "selector", "", "")
+	flags.StringVar(&bindType, "bind-type", api.ACLBindingRuleBindTypePolicy, "")
+	flags.StringVar(&bindName, "bind-name", "", "")
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got no arguments
+	args = flags.Args()
+	if l := len(args); l != 0 {
+		c.Ui.Error("This command takes no arguments")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	if authMethod == "" {
+		c.Ui.Error("ACL auth method must be specified using the -auth-method flag")
+		return 1
+	}
+
+	// Setup the binding rule
+	rule := &api.ACLBindingRule{
+		AuthMethod:  authMethod,
+		Description: description,
+		Selector:    selector,
+		BindType:    bindType,
+		BindName:    bindName,
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	// Create the binding rule
+	rule, _, err = client.ACLBindingRules().Create(rule, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error creating ACL binding rule: %s", err))
+		return 1
+	}
+
+	// Format the output
+	c.Ui.Output(formatKVACLBindingRule(rule))
+	return 0
+}
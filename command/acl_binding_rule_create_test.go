@@ -0,0 +1,54 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/command/agent"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestACLBindingRuleCreateCommand(t *testing.T) {
+	require := require.New(t)
+	t.Parallel()
+	config := func(c *agent.Config) {
+		c.ACL.Enabled = true
+	}
+
+	srv, _, url := testServer(t, true, config)
+	state := srv.Agent.Server().State()
+	defer srv.Shutdown()
+	token := srv.RootToken
+	require.NotNil(token, "failed to bootstrap ACL token")
+
+	method := mock.ACLAuthMethod()
+	require.NoError(state.UpsertACLAuthMethods(1000, []*structs.ACLAuthMethod{method}))
+
+	ui := new(cli.MockUi)
+	cmd := &ACLBindingRuleCreateCommand{Meta: Meta{Ui: ui, flagAddress: url}}
+
+	args := []string{"-address=" + url, "-auth-method=" + method.Name,
+		`-selector="engineering" in list.groups`, "-bind-name=engineering-read"}
+
+	// Creating requires a management token
+	code := cmd.Run(append(args, "-token=foo"))
+	require.Equal(1, code)
+
+	// Invalid selectors are rejected
+	code = cmd.Run([]string{"-address=" + url, "-token=" + token.SecretID, "-auth-method=" + method.Name,
+		"-selector=engineering", "-bind-name=engineering-read"})
+	require.Equal(1, code)
+	require.Contains(ui.ErrorWriter.String(), "invalid selector")
+
+	code = cmd.Run(append(args, "-token="+token.SecretID))
+	require.Equal(0, code, ui.ErrorWriter.String())
+	require.Contains(ui.OutputWriter.String(), "engineering-read")
+
+	iter, err := state.ACLBindingRulesByAuthMethod(nil, method.Name)
+	require.NoError(err)
+	raw := iter.Next()
+	require.NotNil(raw)
+	require.Equal(`"engineering" in list.groups`, raw.(*structs.ACLBindingRule).Selector)
+}
@@ -0,0 +1,48 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/command/agent"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestACLBindingRuleUpdateCommand(t *testing.T) {
+	require := require.New(t)
+	t.Parallel()
+	config := func(c *agent.Config) {
+		c.ACL.Enabled = true
+	}
+
+	srv, _, url := testServer(t, true, config)
+	state := srv.Agent.Server().State()
+	defer srv.Shutdown()
+	token := srv.RootToken
+	require.NotNil(token, "failed to bootstrap ACL token")
+
+	method := mock.ACLAuthMethod()
+	require.NoError(state.UpsertACLAuthMethods(1000, []*structs.ACLAuthMethod{method}))
+	rule := mock.ACLBindingRule()
+	rule.AuthMethod = method.Name
+	require.NoError(state.UpsertACLBindingRules(1001, []*structs.ACLBindingRule{rule}))
+
+	ui := new(cli.MockUi)
+	cmd := &ACLBindingRuleUpdateCommand{Meta: Meta{Ui: ui, flagAddress: url}}
+
+	// Updating requires a management token
+	code := cmd.Run([]string{"-address=" + url, "-token=foo", "-description=updated", rule.ID})
+	require.Equal(1, code)
+
+	// Only the given options are updated
+	code = cmd.Run([]string{"-address=" + url, "-token=" + token.SecretID, "-description=updated", rule.ID})
+	require.Equal(0, code, ui.ErrorWriter.String())
+
+	out, err := state.ACLBindingRuleByID(nil, rule.ID)
+	require.NoError(err)
+	require.Equal("updated", out.Description)
+	require.Equal(rule.Selector, out.Selector)
+	require.Equal(rule.BindName, out.BindName)
+}
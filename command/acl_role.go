@@ -0,0 +1,59 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/mitchellh/cli"
+)
+
+type ACLRoleCommand struct {
+	Meta
+}
+
+func (f *ACLRoleCommand) Help() string {
+	helpText := `
+Usage: nomad acl role <subcommand> [options] [args]
+
+  This command groups subcommands for interacting with ACL roles. Roles bundle
+  ACL policies, and grant them to the ACL tokens linked to the role.
+
+  Create an ACL role:
+
+      $ nomad acl role create -name=<name> -policy=<policy>
+
+  List ACL roles:
+
+      $ nomad acl role list
+
+  Inspect an ACL role:
+
+      $ nomad acl role info <name>
+
+  Please see the individual subcommand help for detailed usage information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (f *ACLRoleCommand) Synopsis() string {
+	return "Interact with ACL roles"
+}
+
+func (f *ACLRoleCommand) Name() string { return "acl role" }
+
+func (f *ACLRoleCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
+
+// formatKVACLRole returns a K/V formatted ACL role
+func formatKVACLRole(role *api.ACLRole) string {
+	output := []string{
+		fmt.Sprintf("Name|%s", role.Name),
+		fmt.Sprintf("Description|%s", role.Description),
+		fmt.Sprintf("Policies|%v", role.Policies),
+		fmt.Sprintf("Create Index|%d", role.CreateIndex),
+		fmt.Sprintf("Modify Index|%d", role.ModifyIndex),
+	}
+	return formatKV(output)
+}
@@ -0,0 +1,112 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+)
+
+type ACLRoleCreateCommand struct {
+	Meta
+}
+
+func (c *ACLRoleCreateCommand) Help() string {
+	helpText := `
+Usage: nomad acl role create [options]
+
+  Create is used to create new ACL roles. Requires a management token.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Create Options:
+
+  -name=""
+    Sets the name of the ACL role.
+
+  -description=""
+    Sets the human readable description of the ACL role.
+
+  -policy=""
+    Specifies a policy bundled by the role. Can be specified multiple times,
+    and must be specified at least once.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *ACLRoleCreateCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-name":        complete.PredictAnything,
+			"-description": complete.PredictAnything,
+			"-policy":      complete.PredictAnything,
+		})
+}
+
+func (c *ACLRoleCreateCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *ACLRoleCreateCommand) Synopsis() string {
+	return "Create a new ACL role"
+}
+
+func (c *ACLRoleCreateCommand) Name() string { return "acl role create" }
+
+func (c *ACLRoleCreateCommand) Run(args []string) int {
+	var name, description string
+	var policies []string
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&name, "name", "", "")
+	flags.StringVar(&description, "description", "", "")
+	flags.Var((funcVar)(func(s string) error {
+		policies = append(policies, s)
+		return nil
+	}), "policy", "")
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got no arguments
+	args = flags.Args()
+	if l := len(args); l != 0 {
+		c.Ui.Error("This command takes no arguments")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	if name == "" {
+		c.Ui.Error("ACL role name must be specified using the -name flag")
+		return 1
+	}
+	if len(policies) == 0 {
+		c.Ui.Error("ACL role policies must be specified using the -policy flag")
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	// Create the role
+	role, _, err := client.ACLRoles().Upsert(&api.ACLRole{
+		Name:        name,
+		Description: description,
+		Policies:    policies,
+	}, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error creating ACL role: %s", err))
+		return 1
+	}
+
+	// Format the output
+	c.Ui.Output(formatKVACLRole(role))
+	return 0
+}
@@ -0,0 +1,50 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/command/agent"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestACLRoleCreateCommand(t *testing.T) {
+	require := require.New(t)
+	t.Parallel()
+	config := func(c *agent.Config) {
+		c.ACL.Enabled = true
+	}
+
+	srv, _, url := testServer(t, true, config)
+	state := srv.Agent.Server().State()
+	defer srv.Shutdown()
+	token := srv.RootToken
+	require.NotNil(token, "failed to bootstrap ACL token")
+
+	policy := mock.ACLPolicy()
+	require.NoError(state.UpsertACLPolicies(1000, []*structs.ACLPolicy{policy}))
+
+	ui := new(cli.MockUi)
+	cmd := &ACLRoleCreateCommand{Meta: Meta{Ui: ui, flagAddress: url}}
+
+	// The policies are required
+	code := cmd.Run([]string{"-address=" + url, "-token=" + token.SecretID, "-name=ops"})
+	require.Equal(1, code)
+	require.Contains(ui.ErrorWriter.String(), "-policy")
+	ui.ErrorWriter.Reset()
+
+	// Creating requires a management token
+	code = cmd.Run([]string{"-address=" + url, "-token=foo", "-name=ops", "-policy=" + policy.Name})
+	require.Equal(1, code)
+
+	code = cmd.Run([]string{"-address=" + url, "-token=" + token.SecretID,
+		"-name=ops", "-description=operators", "-policy=" + policy.Name})
+	require.Equal(0, code, ui.ErrorWriter.String())
+	require.Contains(ui.OutputWriter.String(), "operators")
+
+	role, err := state.ACLRoleByName(nil, "ops")
+	require.NoError(err)
+	require.Equal([]string{policy.Name}, role.Policies)
+}
@@ -0,0 +1,45 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/command/agent"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestACLRoleInfoCommand(t *testing.T) {
+	require := require.New(t)
+	t.Parallel()
+	config := func(c *agent.Config) {
+		c.ACL.Enabled = true
+	}
+
+	srv, _, url := testServer(t, true, config)
+	state := srv.Agent.Server().State()
+	defer srv.Shutdown()
+
+	role := mock.ACLRole()
+	require.NoError(state.UpsertACLRoles(1000, []*structs.ACLRole{role}))
+
+	ui := new(cli.MockUi)
+	cmd := &ACLRoleInfoCommand{Meta: Meta{Ui: ui, flagAddress: url}}
+
+	// Client tokens can only read their own roles
+	token := mock.ACLToken()
+	require.NoError(state.UpsertACLTokens(1001, []*structs.ACLToken{token}))
+	code := cmd.Run([]string{"-address=" + url, "-token=" + token.SecretID, role.Name})
+	require.Equal(1, code)
+
+	token = mock.ACLToken()
+	token.Roles = []string{role.Name}
+	require.NoError(state.UpsertACLTokens(1002, []*structs.ACLToken{token}))
+	code = cmd.Run([]string{"-address=" + url, "-token=" + token.SecretID, role.Name})
+	require.Equal(0, code, ui.ErrorWriter.String())
+
+	out := ui.OutputWriter.String()
+	require.Contains(out, role.Name)
+	require.Contains(out, "[foo bar]")
+}
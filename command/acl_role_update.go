@@ -0,0 +1,113 @@
+package command
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/posener/complete"
+)
+
+type ACLRoleUpdateCommand struct {
+	Meta
+}
+
+func (c *ACLRoleUpdateCommand) Help() string {
+	helpText := `
+Usage: nomad acl role update [options] <name>
+
+  Update is used to update an existing ACL role. Only the options given are
+  updated. Requires a management token.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Update Options:
+
+  -description=""
+    Sets the human readable description of the ACL role.
+
+  -policy=""
+    Specifies a policy bundled by the role. Can be specified multiple times,
+    and replaces all the policies of the role.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *ACLRoleUpdateCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-description": complete.PredictAnything,
+			"-policy":      complete.PredictAnything,
+		})
+}
+
+func (c *ACLRoleUpdateCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *ACLRoleUpdateCommand) Synopsis() string {
+	return "Update an existing ACL role"
+}
+
+func (c *ACLRoleUpdateCommand) Name() string { return "acl role update" }
+
+func (c *ACLRoleUpdateCommand) Run(args []string) int {
+	var description string
+	var policies []string
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&description, "description", "", "")
+	flags.Var((funcVar)(func(s string) error {
+		policies = append(policies, s)
+		return nil
+	}), "policy", "")
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got exactly one argument
+	args = flags.Args()
+	if l := len(args); l != 1 {
+		c.Ui.Error("This command takes one argument: <name>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+	name := args[0]
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	// Get the current role
+	role, _, err := client.ACLRoles().Info(name, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error fetching ACL role: %s", err))
+		return 1
+	}
+
+	// Only update the options that were given
+	flags.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "description":
+			role.Description = description
+		case "policy":
+			role.Policies = policies
+		}
+	})
+
+	// Update the role
+	role, _, err = client.ACLRoles().Upsert(role, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error updating ACL role: %s", err))
+		return 1
+	}
+
+	// Format the output
+	c.Ui.Output(formatKVACLRole(role))
+	return 0
+}
@@ -0,0 +1,47 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/command/agent"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestACLRoleUpdateCommand(t *testing.T) {
+	require := require.New(t)
+	t.Parallel()
+	config := func(c *agent.Config) {
+		c.ACL.Enabled = true
+	}
+
+	srv, _, url := testServer(t, true, config)
+	state := srv.Agent.Server().State()
+	defer srv.Shutdown()
+	token := srv.RootToken
+	require.NotNil(token, "failed to bootstrap ACL token")
+
+	policy := mock.ACLPolicy()
+	require.NoError(state.UpsertACLPolicies(1000, []*structs.ACLPolicy{policy}))
+	role := mock.ACLRole()
+	role.Policies = []string{policy.Name}
+	require.NoError(state.UpsertACLRoles(1001, []*structs.ACLRole{role}))
+
+	ui := new(cli.MockUi)
+	cmd := &ACLRoleUpdateCommand{Meta: Meta{Ui: ui, flagAddress: url}}
+
+	// Updating requires a management token
+	code := cmd.Run([]string{"-address=" + url, "-token=foo", "-description=updated", role.Name})
+	require.Equal(1, code)
+
+	// Only the given options are updated
+	code = cmd.Run([]string{"-address=" + url, "-token=" + token.SecretID, "-description=updated", role.Name})
+	require.Equal(0, code, ui.ErrorWriter.String())
+
+	out, err := state.ACLRoleByName(nil, role.Name)
+	require.NoError(err)
+	require.Equal("updated", out.Description)
+	require.Equal([]string{policy.Name}, out.Policies)
+}
@@ -3,6 +3,7 @@ package command
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/nomad/api"
 	"github.com/posener/complete"
@@ -36,6 +37,14 @@ Create Options:
   -policy=""
     Specifies a policy to associate with the token. Can be specified multiple times,
     but only with client type tokens.
+
+  -role=""
+    Specifies a role to link the token to, granting it the policies of the role.
+    Can be specified multiple times, but only with client type tokens.
+
+  -ttl=""
+    Sets the duration the token is valid for, after which it expires and is
+    garbage collected. Tokens don't expire by default.
 `
 	return strings.TrimSpace(helpText)
 }
@@ -47,6 +56,8 @@ func (c *ACLTokenCreateCommand) AutocompleteFlags() complete.Flags {
 			"type":   complete.PredictAnything,
 			"global": complete.PredictNothing,
 			"policy": complete.PredictAnything,
+			"role":   complete.PredictAnything,
+			"ttl":    complete.PredictAnything,
 		})
 }
 
@@ -63,7 +74,8 @@ func (c *ACLTokenCreateCommand) Name() string { return "acl token create" }
 func (c *ACLTokenCreateCommand) Run(args []string) int {
 	var name, tokenType string
 	var global bool
-	var policies []string
+	var policies, roles []string
+	var ttl time.Duration
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
 	flags.StringVar(&name, "name", "", "")
@@ -73,6 +85,11 @@ func (c *ACLTokenCreateCommand) Run(args []string) int {
 		policies = append(policies, s)
 		return nil
 	}), "policy", "")
+	flags.Var((funcVar)(func(s string) error {
+		roles = append(roles, s)
+		return nil
+	}), "role", "")
+	flags.DurationVar(&ttl, "ttl", 0, "")
 	if err := flags.Parse(args); err != nil {
 		return 1
 	}
@@ -87,10 +104,12 @@ func (c *ACLTokenCreateCommand) Run(args []string) int {
 
 	// Setup the token
 	tk := &api.ACLToken{
-		Name:     name,
-		Type:     tokenType,
-		Policies: policies,
-		Global:   global,
+		Name:          name,
+		Type:          tokenType,
+		Policies:      policies,
+		Roles:         roles,
+		Global:        global,
+		ExpirationTTL: ttl,
 	}
 
 	// Get the HTTP client
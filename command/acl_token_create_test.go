@@ -42,4 +42,10 @@ func TestACLTokenCreateCommand(t *testing.T) {
 	if !strings.Contains(out, "[foo]") {
 		t.Fatalf("bad: %v", out)
 	}
+
+	// Request to create an expiring token
+	ui.OutputWriter.Reset()
+	code = cmd.Run([]string{"-address=" + url, "-policy=foo", "-ttl=1h"})
+	assert.Equal(0, code, ui.ErrorWriter.String())
+	assert.Contains(ui.OutputWriter.String(), "Expiration Time")
 }
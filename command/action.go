@@ -0,0 +1,39 @@
+package command
+
+import (
+	"strings"
+
+	"github.com/mitchellh/cli"
+)
+
+type ActionCommand struct {
+	Meta
+}
+
+func (f *ActionCommand) Help() string {
+	helpText := `
+Usage: nomad action <subcommand> [options] [args]
+
+  This command groups subcommands for interacting with the actions of jobs.
+  Actions are named commands declared by the tasks of a job, which can be run
+  on demand inside their allocations for maintenance operations such as cache
+  flushes.
+
+  Run an action of a job:
+
+      $ nomad action run example flush-cache
+
+  Please see the individual subcommand help for detailed usage information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (f *ActionCommand) Synopsis() string {
+	return "Interact with the actions of jobs"
+}
+
+func (f *ActionCommand) Name() string { return "action" }
+
+func (f *ActionCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
@@ -0,0 +1,260 @@
+package command
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/api/contexts"
+	"github.com/posener/complete"
+)
+
+type ActionRunCommand struct {
+	Meta
+
+	Stdin  io.Reader
+	Stdout io.WriteCloser
+	Stderr io.WriteCloser
+}
+
+func (c *ActionRunCommand) Help() string {
+	helpText := `
+Usage: nomad action run [options] <job> <action>
+
+  Run an action declared by a task of the job. The command of the action is
+  executed inside the task in one of its running allocations, and its output
+  is streamed back. The exit code is the exit code of the command.
+
+  When ACLs are enabled, this command requires a token with the 'read-job'
+  and 'alloc-exec' capabilities for the job's namespace.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Action Run Options:
+
+  -group <group-name>
+    Sets the task group declaring the action. Required if several task groups
+    declare an action with the same name.
+
+  -task <task-name>
+    Sets the task declaring the action. Required if several tasks declare an
+    action with the same name.
+
+  -allocation <alloc-id>
+    Sets the allocation to run the action in. Defaults to a random running
+    allocation of the task group.
+
+  -i
+    Pass stdin to the action, defaults to false.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *ActionRunCommand) Synopsis() string {
+	return "Run an action of a job"
+}
+
+func (c *ActionRunCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-group":      complete.PredictAnything,
+			"-task":       complete.PredictAnything,
+			"-allocation": complete.PredictAnything,
+			"-i":          complete.PredictNothing,
+		})
+}
+
+func (c *ActionRunCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictFunc(func(a complete.Args) []string {
+		client, err := c.Meta.Client()
+		if err != nil {
+			return nil
+		}
+
+		resp, _, err := client.Search().PrefixSearch(a.Last, contexts.Jobs, nil)
+		if err != nil {
+			return []string{}
+		}
+		return resp.Matches[contexts.Jobs]
+	})
+}
+
+func (c *ActionRunCommand) Name() string { return "action run" }
+
+func (c *ActionRunCommand) Run(args []string) int {
+	var group, task, allocID string
+	var stdinOpt bool
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&group, "group", "", "")
+	flags.StringVar(&task, "task", "", "")
+	flags.StringVar(&allocID, "allocation", "", "")
+	flags.BoolVar(&stdinOpt, "i", false, "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got the job and the action
+	args = flags.Args()
+	if len(args) != 2 {
+		c.Ui.Error("This command takes two arguments: <job> <action>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+	jobID, actionName := args[0], args[1]
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	job, _, err := client.Jobs().Info(jobID, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error querying job: %s", err))
+		return 1
+	}
+
+	// Find the task declaring the action
+	matches := findJobActions(job, actionName, group, task)
+	switch len(matches) {
+	case 0:
+		c.Ui.Error(fmt.Sprintf("Action %q not found in job %q", actionName, jobID))
+		return 1
+	case 1:
+	default:
+		c.Ui.Error(fmt.Sprintf("Action %q is declared by several tasks:", actionName))
+		for _, m := range matches {
+			c.Ui.Error(fmt.Sprintf("  * group %q, task %q", m.group, m.task))
+		}
+		c.Ui.Error("\nPlease specify the task group and task.")
+		return 1
+	}
+	match := matches[0]
+
+	alloc, err := c.actionAlloc(client, job, match.group, allocID)
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	if c.Stdin == nil {
+		c.Stdin = os.Stdin
+	}
+	if c.Stdout == nil {
+		c.Stdout = os.Stdout
+	}
+	if c.Stderr == nil {
+		c.Stderr = os.Stderr
+	}
+
+	var stdin io.Reader = c.Stdin
+	if !stdinOpt {
+		stdin = nil
+	}
+
+	// Run the action through the exec path
+	command := append([]string{match.action.Command}, match.action.Args...)
+	exec := &AllocExecCommand{Meta: c.Meta}
+	code, err := exec.execImpl(client, alloc, match.task, false, command, stdin, c.Stdout, c.Stderr)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to run action: %v", err))
+		return 1
+	}
+
+	return code
+}
+
+// actionAlloc returns the allocation of the task group to run an action in,
+// looking up the allocation ID prefix if set and picking a random running
+// allocation otherwise.
+func (c *ActionRunCommand) actionAlloc(client *api.Client, job *api.Job, group, allocID string) (*api.Allocation, error) {
+	if allocID == "" {
+		allocs, _, err := client.Jobs().Allocations(*job.ID, false, nil)
+		if err != nil {
+			return nil, fmt.Errorf("Error querying allocations: %v", err)
+		}
+
+		var running []*api.AllocationListStub
+		for _, a := range allocs {
+			if a.TaskGroup == group && a.ClientStatus == "running" {
+				running = append(running, a)
+			}
+		}
+		if len(running) == 0 {
+			return nil, fmt.Errorf("No running allocation of task group %q found", group)
+		}
+
+		r := rand.New(rand.NewSource(time.Now().UnixNano()))
+		allocID = running[r.Intn(len(running))].ID
+	}
+
+	if len(allocID) == 1 {
+		return nil, fmt.Errorf("Alloc ID must contain at least two characters.")
+	}
+
+	allocID = sanitizeUUIDPrefix(allocID)
+	allocs, _, err := client.Allocations().PrefixList(allocID)
+	if err != nil {
+		return nil, fmt.Errorf("Error querying allocation: %v", err)
+	}
+	if len(allocs) == 0 {
+		return nil, fmt.Errorf("No allocation(s) with prefix or id %q found", allocID)
+	}
+	if len(allocs) > 1 {
+		out := formatAllocListStubs(allocs, false, shortId)
+		return nil, fmt.Errorf("Prefix matched multiple allocations\n\n%s", out)
+	}
+
+	alloc, _, err := client.Allocations().Info(allocs[0].ID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("Error querying allocation: %s", err)
+	}
+	if alloc.JobID != *job.ID || alloc.TaskGroup != group {
+		return nil, fmt.Errorf("Allocation %q is not an allocation of task group %q of job %q",
+			limit(alloc.ID, shortId), group, *job.ID)
+	}
+	return alloc, nil
+}
+
+// jobAction is an action declared by a task of a job.
+type jobAction struct {
+	group  string
+	task   string
+	action *api.Action
+}
+
+// findJobActions returns the actions with the given name declared by the
+// tasks of the job, optionally filtered by task group and task name.
+func findJobActions(job *api.Job, name, group, task string) []*jobAction {
+	var matches []*jobAction
+	for _, tg := range job.TaskGroups {
+		if group != "" && *tg.Name != group {
+			continue
+		}
+		for _, t := range tg.Tasks {
+			if task != "" && t.Name != task {
+				continue
+			}
+			for _, a := range t.Actions {
+				if a.Name == name {
+					matches = append(matches, &jobAction{
+						group:  *tg.Name,
+						task:   t.Name,
+						action: a,
+					})
+				}
+			}
+		}
+	}
+	return matches
+}
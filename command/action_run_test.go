@@ -0,0 +1,87 @@
+package command
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActionRunCommand_Implements(t *testing.T) {
+	t.Parallel()
+	var _ cli.Command = &ActionRunCommand{}
+}
+
+func TestActionRunCommand_Fails(t *testing.T) {
+	t.Parallel()
+	srv, _, url := testServer(t, false, nil)
+	defer srv.Shutdown()
+
+	ui := new(cli.MockUi)
+	cmd := &ActionRunCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	if code := cmd.Run([]string{"some", "bad", "args"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, commandErrorText(cmd)) {
+		t.Fatalf("expected help output, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	// Fails on connection failure
+	if code := cmd.Run([]string{"-address=nope", "example", "flush"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "Error querying job") {
+		t.Fatalf("expected failed query error, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	// Fails on missing job
+	if code := cmd.Run([]string{"-address=" + url, "nope", "flush"}); code != 1 {
+		t.Fatalf("expected exit 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "Error querying job") {
+		t.Fatalf("expected not found error, got: %s", out)
+	}
+}
+
+func TestActionRunCommand_FindJobActions(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	flush := &api.Action{Name: "flush", Command: "redis-cli", Args: []string{"FLUSHALL"}}
+	info := &api.Action{Name: "info", Command: "redis-cli", Args: []string{"INFO"}}
+	job := &api.Job{
+		TaskGroups: []*api.TaskGroup{
+			{
+				Name: helper.StringToPtr("cache"),
+				Tasks: []*api.Task{
+					{Name: "redis", Actions: []*api.Action{flush, info}},
+					{Name: "sidecar", Actions: []*api.Action{info}},
+				},
+			},
+			{
+				Name: helper.StringToPtr("web"),
+				Tasks: []*api.Task{
+					{Name: "frontend"},
+				},
+			},
+		},
+	}
+
+	matches := findJobActions(job, "flush", "", "")
+	require.Len(matches, 1)
+	require.Equal("cache", matches[0].group)
+	require.Equal("redis", matches[0].task)
+	require.Equal(flush, matches[0].action)
+
+	require.Len(findJobActions(job, "info", "", ""), 2)
+	require.Len(findJobActions(job, "info", "cache", "sidecar"), 1)
+	require.Empty(findJobActions(job, "flush", "web", ""))
+	require.Empty(findJobActions(job, "restart", "", ""))
+}
@@ -3,11 +3,14 @@ package agent
 import (
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"github.com/hashicorp/nomad/lib/auth/authtest"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestHTTP_ACLPolicyList(t *testing.T) {
@@ -448,3 +451,162 @@ func TestHTTP_ACLTokenDelete(t *testing.T) {
 		assert.Nil(t, out)
 	})
 }
+
+func TestHTTP_ACLAuthMethodCRUD(t *testing.T) {
+	t.Parallel()
+	httpACLTest(t, nil, func(s *TestAgent) {
+		require := require.New(t)
+
+		// Create the auth method
+		am := mock.ACLAuthMethod()
+		req, err := http.NewRequest("PUT", "/v1/acl/auth-method", encodeReq(am))
+		require.NoError(err)
+		respW := httptest.NewRecorder()
+		setToken(req, s.RootToken)
+
+		obj, err := s.Server.ACLAuthMethodSpecificRequest(respW, req)
+		require.NoError(err)
+		require.NotEmpty(respW.HeaderMap.Get("X-Nomad-Index"))
+		require.Equal(am.Name, obj.(*structs.ACLAuthMethod).Name)
+
+		// The name must match the path when updating
+		req, err = http.NewRequest("PUT", "/v1/acl/auth-method/other", encodeReq(am))
+		require.NoError(err)
+		setToken(req, s.RootToken)
+		_, err = s.Server.ACLAuthMethodSpecificRequest(httptest.NewRecorder(), req)
+		require.EqualError(err, "ACL auth method name does not match request path")
+
+		// List the auth methods without a token
+		req, err = http.NewRequest("GET", "/v1/acl/auth-methods", nil)
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+		obj, err = s.Server.ACLAuthMethodsRequest(respW, req)
+		require.NoError(err)
+		require.Equal("true", respW.HeaderMap.Get("X-Nomad-KnownLeader"))
+		require.Len(obj.([]*structs.ACLAuthMethodListStub), 1)
+
+		// Read the auth method
+		req, err = http.NewRequest("GET", "/v1/acl/auth-method/"+am.Name, nil)
+		require.NoError(err)
+		setToken(req, s.RootToken)
+		obj, err = s.Server.ACLAuthMethodSpecificRequest(httptest.NewRecorder(), req)
+		require.NoError(err)
+		require.Equal(am.Config, obj.(*structs.ACLAuthMethod).Config)
+
+		// Delete the auth method
+		req, err = http.NewRequest("DELETE", "/v1/acl/auth-method/"+am.Name, nil)
+		require.NoError(err)
+		setToken(req, s.RootToken)
+		_, err = s.Server.ACLAuthMethodSpecificRequest(httptest.NewRecorder(), req)
+		require.NoError(err)
+
+		req, err = http.NewRequest("GET", "/v1/acl/auth-method/"+am.Name, nil)
+		require.NoError(err)
+		setToken(req, s.RootToken)
+		_, err = s.Server.ACLAuthMethodSpecificRequest(httptest.NewRecorder(), req)
+		require.EqualError(err, "ACL auth method not found")
+	})
+}
+
+func TestHTTP_ACLBindingRuleCRUD(t *testing.T) {
+	t.Parallel()
+	httpACLTest(t, nil, func(s *TestAgent) {
+		require := require.New(t)
+
+		am := mock.ACLAuthMethod()
+		require.NoError(s.Agent.server.State().UpsertACLAuthMethods(1000, []*structs.ACLAuthMethod{am}))
+
+		// Create the binding rule
+		rule := mock.ACLBindingRule()
+		rule.ID = ""
+		rule.AuthMethod = am.Name
+		req, err := http.NewRequest("PUT", "/v1/acl/binding-rule", encodeReq(rule))
+		require.NoError(err)
+		respW := httptest.NewRecorder()
+		setToken(req, s.RootToken)
+
+		obj, err := s.Server.ACLBindingRuleSpecificRequest(respW, req)
+		require.NoError(err)
+		require.NotEmpty(respW.HeaderMap.Get("X-Nomad-Index"))
+		id := obj.(*structs.ACLBindingRule).ID
+		require.NotEmpty(id)
+
+		// Update the binding rule
+		rule.ID = id
+		rule.Description = "updated"
+		req, err = http.NewRequest("PUT", "/v1/acl/binding-rule/"+id, encodeReq(rule))
+		require.NoError(err)
+		setToken(req, s.RootToken)
+		obj, err = s.Server.ACLBindingRuleSpecificRequest(httptest.NewRecorder(), req)
+		require.NoError(err)
+		require.Equal("updated", obj.(*structs.ACLBindingRule).Description)
+
+		// List the binding rules
+		req, err = http.NewRequest("GET", "/v1/acl/binding-rules", nil)
+		require.NoError(err)
+		setToken(req, s.RootToken)
+		obj, err = s.Server.ACLBindingRulesRequest(httptest.NewRecorder(), req)
+		require.NoError(err)
+		require.Len(obj.([]*structs.ACLBindingRuleListStub), 1)
+
+		// Read the binding rule
+		req, err = http.NewRequest("GET", "/v1/acl/binding-rule/"+id, nil)
+		require.NoError(err)
+		setToken(req, s.RootToken)
+		obj, err = s.Server.ACLBindingRuleSpecificRequest(httptest.NewRecorder(), req)
+		require.NoError(err)
+		require.Equal(rule.Selector, obj.(*structs.ACLBindingRule).Selector)
+
+		// Delete the binding rule
+		req, err = http.NewRequest("DELETE", "/v1/acl/binding-rule/"+id, nil)
+		require.NoError(err)
+		setToken(req, s.RootToken)
+		_, err = s.Server.ACLBindingRuleSpecificRequest(httptest.NewRecorder(), req)
+		require.NoError(err)
+
+		out, err := s.Agent.server.State().ACLBindingRuleByID(nil, id)
+		require.NoError(err)
+		require.Nil(out)
+	})
+}
+
+func TestHTTP_ACLOIDCAuthURL(t *testing.T) {
+	t.Parallel()
+	httpACLTest(t, nil, func(s *TestAgent) {
+		require := require.New(t)
+
+		provider := authtest.NewTestOIDCProvider()
+		defer provider.Close()
+
+		am := mock.ACLAuthMethod()
+		am.Default = true
+		am.Config.OIDCDiscoveryURL = provider.URL()
+		am.Config.OIDCClientID = authtest.TestOIDCClientID
+		am.Config.AllowedRedirectURIs = []string{"http://localhost:4649/oidc/callback"}
+		require.NoError(s.Agent.server.State().UpsertACLAuthMethods(1000, []*structs.ACLAuthMethod{am}))
+
+		args := structs.ACLOIDCAuthURLRequest{
+			RedirectURI: "http://localhost:4649/oidc/callback",
+			ClientNonce: "nonce",
+			State:       "state",
+		}
+		req, err := http.NewRequest("POST", "/v1/acl/oidc/auth-url", encodeReq(args))
+		require.NoError(err)
+		obj, err := s.Server.ACLOIDCAuthURLRequest(httptest.NewRecorder(), req)
+		require.NoError(err)
+
+		authURL := obj.(structs.ACLOIDCAuthURLResponse).AuthURL
+		require.True(strings.HasPrefix(authURL, provider.URL()+"/authorize?"))
+		require.Contains(authURL, "nonce=nonce")
+
+		// A missing authorization code is rejected
+		complete := structs.ACLOIDCCompleteAuthRequest{
+			ClientNonce: "nonce",
+			RedirectURI: "http://localhost:4649/oidc/callback",
+		}
+		req, err = http.NewRequest("POST", "/v1/acl/oidc/complete-auth", encodeReq(complete))
+		require.NoError(err)
+		_, err = s.Server.ACLOIDCCompleteAuthRequest(httptest.NewRecorder(), req)
+		require.EqualError(err, "missing authorization code")
+	})
+}
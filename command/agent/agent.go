@@ -1,6 +1,7 @@
 package agent
 
 import (
+	"crypto/rand"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -56,6 +57,10 @@ type Agent struct {
 	httpLogger log.Logger
 	logOutput  io.Writer
 
+	// logWriter buffers the logs of the agent and streams them to the
+	// monitors of the agent
+	logWriter *logWriter
+
 	// consulService is Nomad's custom Consul client for managing services
 	// and checks.
 	consulService *consul.ServiceClient
@@ -89,16 +94,17 @@ type Agent struct {
 func NewAgent(config *Config, logOutput io.Writer, inmem *metrics.InmemSink) (*Agent, error) {
 	a := &Agent{
 		config:     config,
-		logOutput:  logOutput,
+		logWriter:  NewLogWriter(512),
 		shutdownCh: make(chan struct{}),
 		InmemSink:  inmem,
 	}
+	a.logOutput = io.MultiWriter(logOutput, a.logWriter)
 
 	// Create the loggers
 	a.logger = log.New(&log.LoggerOptions{
 		Name:       "agent",
 		Level:      log.LevelFromString(config.LogLevel),
-		Output:     logOutput,
+		Output:     a.logOutput,
 		JSONFormat: false, // TODO(alex,hclog) Add a config option
 	})
 	a.httpLogger = a.logger.ResetNamed("http")
@@ -199,9 +205,18 @@ func convertServerConfig(agentConfig *Config) (*nomad.Config, error) {
 	if agentConfig.ACL.ReplicationToken != "" {
 		conf.ReplicationToken = agentConfig.ACL.ReplicationToken
 	}
+	if agentConfig.ACL.TokenMinExpirationTTL != 0 {
+		conf.ACLTokenMinExpirationTTL = agentConfig.ACL.TokenMinExpirationTTL
+	}
+	if agentConfig.ACL.TokenMaxExpirationTTL != 0 {
+		conf.ACLTokenMaxExpirationTTL = agentConfig.ACL.TokenMaxExpirationTTL
+	}
 	if agentConfig.Sentinel != nil {
 		conf.SentinelConfig = agentConfig.Sentinel
 	}
+	if agentConfig.OPA != nil {
+		conf.OPAConfig = agentConfig.OPA.Copy()
+	}
 	if agentConfig.Server.NonVotingServer {
 		conf.NonVoter = true
 	}
@@ -211,6 +226,25 @@ func convertServerConfig(agentConfig *Config) (*nomad.Config, error) {
 	if agentConfig.Server.UpgradeVersion != "" {
 		conf.UpgradeVersion = agentConfig.Server.UpgradeVersion
 	}
+	if agentConfig.Server.KeyringEncryptionKey != "" {
+		kek, err := agentConfig.Server.KeyringEncryptionKeyBytes()
+		if err != nil {
+			return nil, fmt.Errorf("Failed to decode keyring_encryption_key: %v", err)
+		}
+		if len(kek) != nomad.KeyringEncryptionKeySize {
+			return nil, fmt.Errorf("keyring_encryption_key must be %d bytes, not %d", nomad.KeyringEncryptionKeySize, len(kek))
+		}
+		conf.KeyringEncryptionKey = kek
+	} else if agentConfig.DevMode {
+		// Dev agents don't persist their state, so an ephemeral key does
+		kek := make([]byte, nomad.KeyringEncryptionKeySize)
+		if _, err := rand.Read(kek); err != nil {
+			return nil, fmt.Errorf("Failed to generate keyring encryption key: %v", err)
+		}
+		conf.KeyringEncryptionKey = kek
+	} else {
+		return nil, fmt.Errorf("keyring_encryption_key must be set on servers")
+	}
 	if agentConfig.Autopilot != nil {
 		if agentConfig.Autopilot.CleanupDeadServers != nil {
 			conf.AutopilotConfig.CleanupDeadServers = *agentConfig.Autopilot.CleanupDeadServers
@@ -224,6 +258,9 @@ func convertServerConfig(agentConfig *Config) (*nomad.Config, error) {
 		if agentConfig.Autopilot.MaxTrailingLogs != 0 {
 			conf.AutopilotConfig.MaxTrailingLogs = uint64(agentConfig.Autopilot.MaxTrailingLogs)
 		}
+		if agentConfig.Autopilot.MinQuorum != 0 {
+			conf.AutopilotConfig.MinQuorum = uint(agentConfig.Autopilot.MinQuorum)
+		}
 		if agentConfig.Autopilot.EnableRedundancyZones != nil {
 			conf.AutopilotConfig.EnableRedundancyZones = *agentConfig.Autopilot.EnableRedundancyZones
 		}
@@ -234,6 +271,12 @@ func convertServerConfig(agentConfig *Config) (*nomad.Config, error) {
 			conf.AutopilotConfig.EnableCustomUpgrades = *agentConfig.Autopilot.EnableCustomUpgrades
 		}
 	}
+	if agentConfig.SnapshotAgent != nil {
+		conf.SnapshotAgentConfig = agentConfig.SnapshotAgent.Copy()
+	}
+	if len(agentConfig.Server.AdmissionWebhooks) != 0 {
+		conf.AdmissionWebhooks = config.AdmissionWebhookSetMerge(nil, agentConfig.Server.AdmissionWebhooks)
+	}
 
 	// Set up the bind addresses
 	rpcAddr, err := net.ResolveTCPAddr("tcp", agentConfig.normalizedAddrs.RPC)
@@ -311,6 +354,71 @@ func convertServerConfig(agentConfig *Config) (*nomad.Config, error) {
 		conf.MaxHeartbeatsPerSecond = maxHPS
 	}
 
+	if retention := agentConfig.Server.NodeEventRetention; retention != 0 {
+		if retention < 1 {
+			return nil, fmt.Errorf("node_event_retention must be at least 1, got %d", retention)
+		}
+		conf.NodeEventRetention = retention
+	}
+
+	if size := agentConfig.Server.PlanBatchSize; size != 0 {
+		if size < 1 {
+			return nil, fmt.Errorf("plan_batch_size must be at least 1, got %d", size)
+		}
+		conf.PlanBatchSize = size
+	}
+	if size := agentConfig.Server.EvalUpdateBatchSize; size != 0 {
+		if size < 1 {
+			return nil, fmt.Errorf("eval_update_batch_size must be at least 1, got %d", size)
+		}
+		conf.EvalUpdateBatchSize = size
+	}
+	if streams := agentConfig.Server.RPCMaxIdleStreams; streams != 0 {
+		if streams < 1 {
+			return nil, fmt.Errorf("rpc_max_idle_streams must be at least 1, got %d", streams)
+		}
+		conf.RPCMaxIdleStreams = streams
+	}
+	if timeout := agentConfig.Server.RPCConnIdleTimeout; timeout != 0 {
+		if timeout < 0 {
+			return nil, fmt.Errorf("rpc_conn_idle_timeout must be positive, got %v", timeout)
+		}
+		conf.RPCConnIdleTimeout = timeout
+	}
+
+	conf.EvalFairShare = agentConfig.Server.EvalFairShare
+	for ns, weight := range agentConfig.Server.EvalFairShareWeights {
+		if weight < 1 {
+			return nil, fmt.Errorf("eval_fair_share_weights: weight of namespace %q must be at least 1, got %d", ns, weight)
+		}
+	}
+	conf.EvalFairShareWeights = agentConfig.Server.EvalFairShareWeights
+
+	if timeout := agentConfig.Server.EvalNackTimeout; timeout != 0 {
+		if timeout < 0 {
+			return nil, fmt.Errorf("eval_nack_timeout must be positive, got %v", timeout)
+		}
+		conf.EvalNackTimeout = timeout
+	}
+	if limit := agentConfig.Server.EvalDeliveryLimit; limit != 0 {
+		if limit < 1 {
+			return nil, fmt.Errorf("eval_delivery_limit must be at least 1, got %d", limit)
+		}
+		conf.EvalDeliveryLimit = limit
+	}
+	if delay := agentConfig.Server.EvalNackInitialReenqueueDelay; delay != 0 {
+		if delay < 0 {
+			return nil, fmt.Errorf("eval_nack_initial_reenqueue_delay cannot be negative, got %v", delay)
+		}
+		conf.EvalNackInitialReenqueueDelay = delay
+	}
+	if delay := agentConfig.Server.EvalNackSubsequentReenqueueDelay; delay != 0 {
+		if delay < 0 {
+			return nil, fmt.Errorf("eval_nack_subsequent_reenqueue_delay cannot be negative, got %v", delay)
+		}
+		conf.EvalNackSubsequentReenqueueDelay = delay
+	}
+
 	if *agentConfig.Consul.AutoAdvertise && agentConfig.Consul.ServerServiceName == "" {
 		return nil, fmt.Errorf("server_service_name must be set when auto_advertise is enabled")
 	}
@@ -376,6 +484,7 @@ func (a *Agent) finalizeClientConfig(c *clientconfig.Config) error {
 	// Setup the logging
 	c.Logger = a.logger
 	c.LogOutput = a.logOutput
+	c.LogSource = a.logWriter
 
 	// If we are running a server, append both its bind and advertise address so
 	// we are able to at least talk to the local server even if that isn't
@@ -434,6 +543,7 @@ func convertClientConfig(agentConfig *Config) (*clientconfig.Config, error) {
 	if agentConfig.DataDir != "" {
 		conf.StateDir = filepath.Join(agentConfig.DataDir, "client")
 		conf.AllocDir = filepath.Join(agentConfig.DataDir, "alloc")
+		conf.HostVolumesDir = filepath.Join(agentConfig.DataDir, "host_volumes")
 	}
 	if agentConfig.Client.StateDir != "" {
 		conf.StateDir = agentConfig.Client.StateDir
@@ -441,6 +551,24 @@ func convertClientConfig(agentConfig *Config) (*clientconfig.Config, error) {
 	if agentConfig.Client.AllocDir != "" {
 		conf.AllocDir = agentConfig.Client.AllocDir
 	}
+	if agentConfig.Client.HostVolumesDir != "" {
+		conf.HostVolumesDir = agentConfig.Client.HostVolumesDir
+	}
+	if agentConfig.Client.HostVolumePluginDir != "" {
+		conf.HostVolumePluginDir = agentConfig.Client.HostVolumePluginDir
+	}
+	if agentConfig.Client.CNIPath != "" {
+		conf.CNIPath = agentConfig.Client.CNIPath
+	}
+	if agentConfig.Client.CNIConfigDir != "" {
+		conf.CNIConfigDir = agentConfig.Client.CNIConfigDir
+	}
+	if agentConfig.Client.BridgeNetworkName != "" {
+		conf.BridgeNetworkName = agentConfig.Client.BridgeNetworkName
+	}
+	if agentConfig.Client.BridgeNetworkSubnet != "" {
+		conf.BridgeNetworkSubnet = agentConfig.Client.BridgeNetworkSubnet
+	}
 	if agentConfig.Client.NetworkInterface != "" {
 		conf.NetworkInterface = agentConfig.Client.NetworkInterface
 	}
@@ -455,6 +583,7 @@ func convertClientConfig(agentConfig *Config) (*clientconfig.Config, error) {
 	if agentConfig.Client.MemoryMB != 0 {
 		conf.MemoryMB = agentConfig.Client.MemoryMB
 	}
+	conf.ReservableCores = agentConfig.Client.ReservableCores
 	if agentConfig.Client.MaxKillTimeout != "" {
 		dur, err := time.ParseDuration(agentConfig.Client.MaxKillTimeout)
 		if err != nil {
@@ -471,6 +600,7 @@ func convertClientConfig(agentConfig *Config) (*clientconfig.Config, error) {
 	conf.Node.Name = agentConfig.NodeName
 	conf.Node.Meta = agentConfig.Client.Meta
 	conf.Node.NodeClass = agentConfig.Client.NodeClass
+	conf.Node.NodePool = agentConfig.Client.NodePool
 
 	// Set up the HTTP advertise address
 	conf.Node.HTTPAddr = agentConfig.AdvertiseAddrs.HTTP
@@ -496,6 +626,7 @@ func convertClientConfig(agentConfig *Config) (*clientconfig.Config, error) {
 	res.Memory.MemoryMB = int64(agentConfig.Client.Reserved.MemoryMB)
 	res.Disk.DiskMB = int64(agentConfig.Client.Reserved.DiskMB)
 	res.Networks.ReservedHostPorts = agentConfig.Client.Reserved.ReservedPorts
+	conf.ReservedCgroupSlice = agentConfig.Client.Reserved.CgroupSlice
 
 	conf.Version = agentConfig.Version
 
@@ -523,12 +654,54 @@ func convertClientConfig(agentConfig *Config) (*clientconfig.Config, error) {
 	conf.GCDiskUsageThreshold = agentConfig.Client.GCDiskUsageThreshold
 	conf.GCInodeUsageThreshold = agentConfig.Client.GCInodeUsageThreshold
 	conf.GCMaxAllocs = agentConfig.Client.GCMaxAllocs
+	conf.DiskIneligibleThreshold = agentConfig.Client.DiskIneligibleThreshold
 	if agentConfig.Client.NoHostUUID != nil {
 		conf.NoHostUUID = *agentConfig.Client.NoHostUUID
 	} else {
 		// Default no_host_uuid to true
 		conf.NoHostUUID = true
 	}
+	conf.EnableTaskAPI = agentConfig.Client.EnableTaskAPI
+	for _, f := range agentConfig.Client.Fingerprinters {
+		f = f.Copy()
+		f.Canonicalize()
+		if err := f.Validate(); err != nil {
+			return nil, err
+		}
+		conf.Fingerprinters = append(conf.Fingerprinters, f)
+	}
+	if len(agentConfig.Client.HostVolumes) != 0 {
+		conf.HostVolumes = make(map[string]*structs.ClientHostVolumeConfig, len(agentConfig.Client.HostVolumes))
+		for _, v := range agentConfig.Client.HostVolumes {
+			if err := v.Validate(); err != nil {
+				return nil, err
+			}
+			conf.HostVolumes[v.Name] = v.Copy()
+		}
+	}
+	if len(agentConfig.Client.HostNetworks) != 0 {
+		conf.HostNetworks = make(map[string]*structs.ClientHostNetworkConfig, len(agentConfig.Client.HostNetworks))
+		for _, n := range agentConfig.Client.HostNetworks {
+			if err := n.Validate(); err != nil {
+				return nil, err
+			}
+			conf.HostNetworks[n.Name] = n.Copy()
+		}
+	}
+	if drain := agentConfig.Client.DrainOnShutdown.Copy(); drain != nil {
+		drain.Canonicalize()
+		if err := drain.Validate(); err != nil {
+			return nil, err
+		}
+		conf.DrainOnShutdown = drain
+	}
+	if drain := agentConfig.Client.DrainOnTermination.Copy(); drain != nil {
+		drain.Canonicalize()
+		if err := drain.Validate(); err != nil {
+			return nil, err
+		}
+		conf.DrainOnTermination = drain
+	}
 
 	// Setup the ACLs
 	conf.ACLEnabled = agentConfig.ACL.Enabled
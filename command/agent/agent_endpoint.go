@@ -1,13 +1,22 @@
 package agent
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
 	"net"
 	"net/http"
+	"runtime"
+	"runtime/pprof"
+	"runtime/trace"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/nomad/acl"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/command/agent/monitor"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/serf/serf"
 	"github.com/mitchellh/copystructure"
@@ -290,6 +299,187 @@ func (s *HTTPServer) KeyringOperationRequest(resp http.ResponseWriter, req *http
 	return kresp, nil
 }
 
+// AgentMonitor streams the logs of the agent at or above the requested level
+// until the request is canceled. The logs of the agent of another client node
+// are streamed through the servers when a node_id is given.
+func (s *HTTPServer) AgentMonitor(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "GET" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	// Check agent read permissions
+	if aclObj, err := s.resolveAgentToken(req); err != nil {
+		return nil, err
+	} else if aclObj != nil && !aclObj.AllowAgentRead() {
+		return nil, structs.ErrPermissionDenied
+	}
+
+	logLevel := req.URL.Query().Get("log_level")
+	if logLevel == "" {
+		logLevel = "INFO"
+	}
+	m, ok := monitor.New(logLevel, 512)
+	if !ok {
+		return nil, CodedError(400, fmt.Sprintf("Unknown log level: %s", logLevel))
+	}
+
+	// Stream the logs of another node through the servers
+	nodeID := req.URL.Query().Get("node_id")
+	if nodeID != "" && (s.agent.Client() == nil || s.agent.Client().NodeID() != nodeID) {
+		return s.agentMonitorNode(resp, req, logLevel, nodeID)
+	}
+
+	flusher, ok := resp.(http.Flusher)
+	if !ok {
+		return nil, CodedError(400, "Streaming not supported")
+	}
+	if s.agent.logWriter == nil {
+		return nil, CodedError(500, "Agent logs are not available")
+	}
+
+	s.agent.logWriter.RegisterHandler(m)
+	defer s.agent.logWriter.DeregisterHandler(m)
+
+	resp.Header().Set("Content-Type", "text/plain")
+	resp.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case <-req.Context().Done():
+			return nil, nil
+		case <-s.agent.shutdownCh:
+			return nil, nil
+		case line := <-m.LogCh():
+			if _, err := fmt.Fprintln(resp, line); err != nil {
+				return nil, nil
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// agentMonitorNode streams the logs of the agent of a client node through
+// the servers.
+func (s *HTTPServer) agentMonitorNode(resp http.ResponseWriter, req *http.Request,
+	logLevel, nodeID string) (interface{}, error) {
+
+	args := cstructs.MonitorRequest{
+		LogLevel: logLevel,
+		NodeID:   nodeID,
+	}
+	s.parse(resp, req, &args.QueryOptions.Region, &args.QueryOptions)
+
+	handler, err := s.serverStreamingRpcHandler("Agent.Monitor")
+	if err != nil {
+		return nil, CodedError(500, err.Error())
+	}
+
+	return s.streamRpcImpl(resp, req, handler, &args)
+}
+
+// maxPprofSeconds bounds the duration of the CPU profiles and traces, which
+// hold the request and buffer their output in memory meanwhile.
+const maxPprofSeconds = 300
+
+// AgentPprofRequest serves the runtime profiles of the agent. The profiles
+// require a management token, or the agent to run with enable_debug if ACLs
+// are disabled.
+func (s *HTTPServer) AgentPprofRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "GET" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	// Check management permissions
+	aclObj, err := s.resolveAgentToken(req)
+	if err != nil {
+		return nil, err
+	} else if aclObj == nil && !s.agent.config.EnableDebug {
+		return nil, CodedError(403, "Profiles require enable_debug when ACLs are disabled")
+	} else if aclObj != nil && !aclObj.IsManagement() {
+		return nil, structs.ErrPermissionDenied
+	}
+
+	profile := strings.TrimPrefix(req.URL.Path, "/v1/agent/pprof/")
+	if profile == "" {
+		return nil, CodedError(404, "Profile name required")
+	}
+
+	// Durations of the CPU profile and the trace in seconds
+	seconds := 1
+	if secStr := req.URL.Query().Get("seconds"); secStr != "" {
+		if seconds, err = strconv.Atoi(secStr); err != nil || seconds <= 0 {
+			return nil, CodedError(400, fmt.Sprintf("Invalid seconds: %q", secStr))
+		} else if seconds > maxPprofSeconds {
+			return nil, CodedError(400, fmt.Sprintf("Seconds must be at most %d", maxPprofSeconds))
+		}
+	}
+
+	debug := 0
+	if debugStr := req.URL.Query().Get("debug"); debugStr != "" {
+		if debug, err = strconv.Atoi(debugStr); err != nil {
+			return nil, CodedError(400, fmt.Sprintf("Invalid debug level: %q", debugStr))
+		}
+	}
+
+	var buf bytes.Buffer
+	switch profile {
+	case "profile":
+		if err := pprof.StartCPUProfile(&buf); err != nil {
+			return nil, CodedError(500, fmt.Sprintf("Failed to start CPU profile: %v", err))
+		}
+		s.sleepOrCanceled(req, time.Duration(seconds)*time.Second)
+		pprof.StopCPUProfile()
+	case "trace":
+		if err := trace.Start(&buf); err != nil {
+			return nil, CodedError(500, fmt.Sprintf("Failed to start trace: %v", err))
+		}
+		s.sleepOrCanceled(req, time.Duration(seconds)*time.Second)
+		trace.Stop()
+	default:
+		p := pprof.Lookup(profile)
+		if p == nil {
+			return nil, CodedError(404, fmt.Sprintf("Unknown profile: %s", profile))
+		}
+		if profile == "heap" && req.URL.Query().Get("gc") != "" {
+			runtime.GC()
+		}
+		if err := p.WriteTo(&buf, debug); err != nil {
+			return nil, CodedError(500, fmt.Sprintf("Failed to write profile: %v", err))
+		}
+	}
+
+	if debug > 0 && profile != "profile" && profile != "trace" {
+		resp.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	} else {
+		resp.Header().Set("Content-Type", "application/octet-stream")
+		resp.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", profile))
+	}
+	resp.Write(buf.Bytes())
+	return nil, nil
+}
+
+// sleepOrCanceled waits for the duration or until the request is canceled.
+func (s *HTTPServer) sleepOrCanceled(req *http.Request, d time.Duration) {
+	select {
+	case <-time.After(d):
+	case <-req.Context().Done():
+	case <-s.agent.shutdownCh:
+	}
+}
+
+// resolveAgentToken resolves the ACL token of the request using the server of
+// the agent if it runs one and its client otherwise.
+func (s *HTTPServer) resolveAgentToken(req *http.Request) (*acl.ACL, error) {
+	var secret string
+	s.parseToken(req, &secret)
+
+	if srv := s.agent.Server(); srv != nil {
+		return srv.ResolveToken(secret)
+	}
+	return s.agent.Client().ResolveToken(secret)
+}
+
 type agentSelf struct {
 	Config *Config                      `json:"config"`
 	Member Member                       `json:"member,omitempty"`
@@ -333,39 +523,63 @@ func (s *HTTPServer) HealthRequest(resp http.ResponseWriter, req *http.Request)
 					Ok:      false,
 					Message: "server not enabled",
 				}
+			default:
+				return nil, CodedError(400, fmt.Sprintf("Invalid health type %q", ht))
 			}
 		}
 	}
 
-	// If we should check the client and it exists assume it's healthy
+	// If we should check the client and it exists, see if it is registered
+	// and able to run tasks
 	if client := s.agent.Client(); getClient && client != nil {
+		health.Client = newHealthResponseAgent()
+
 		if len(client.GetServers()) == 0 {
-			health.Client = &healthResponseAgent{
-				Ok:      false,
-				Message: "no known servers",
-			}
+			health.Client.check("servers", false, "no known servers")
 		} else {
-			health.Client = &healthResponseAgent{
-				Ok:      true,
-				Message: "ok",
+			health.Client.check("servers", true, "ok")
+		}
+
+		if client.Registered() {
+			health.Client.check("registered", true, "ok")
+		} else {
+			health.Client.check("registered", false, "node not registered")
+		}
+
+		// The client is healthy as long as one of its drivers is
+		var healthy, unhealthy []string
+		for name, driver := range client.Node().Drivers {
+			if !driver.Detected {
+				continue
 			}
+			if driver.Healthy {
+				healthy = append(healthy, name)
+			} else {
+				unhealthy = append(unhealthy, name)
+			}
+		}
+		sort.Strings(unhealthy)
+		switch {
+		case len(healthy) == 0:
+			health.Client.check("drivers", false, "no healthy drivers")
+		case len(unhealthy) != 0:
+			health.Client.check("drivers", true, "unhealthy drivers: "+strings.Join(unhealthy, ", "))
+		default:
+			health.Client.check("drivers", true, "ok")
 		}
 	}
 
 	// If we should check the server and it exists, see if there's a leader
 	if server := s.agent.Server(); getServer && server != nil {
-		health.Server = &healthResponseAgent{
-			Ok:      true,
-			Message: "ok",
-		}
+		health.Server = newHealthResponseAgent()
 
 		leader := ""
 		if err := s.agent.RPC("Status.Leader", &args, &leader); err != nil {
-			health.Server.Ok = false
-			health.Server.Message = err.Error()
+			health.Server.check("leader", false, err.Error())
 		} else if leader == "" {
-			health.Server.Ok = false
-			health.Server.Message = "no leader"
+			health.Server.check("leader", false, "no leader")
+		} else {
+			health.Server.check("leader", true, "ok")
 		}
 	}
 
@@ -377,7 +591,7 @@ func (s *HTTPServer) HealthRequest(resp http.ResponseWriter, req *http.Request)
 	if err != nil {
 		return nil, err
 	}
-	return nil, CodedError(500, string(jsonResp))
+	return nil, CodedError(http.StatusServiceUnavailable, string(jsonResp))
 }
 
 type healthResponse struct {
@@ -399,4 +613,49 @@ func (h healthResponse) ok() bool {
 type healthResponseAgent struct {
 	Ok      bool   `json:"ok"`
 	Message string `json:"message,omitempty"`
+
+	// Checks are the readiness checks of the agent, by name
+	Checks map[string]*healthCheck `json:"checks,omitempty"`
+}
+
+// healthCheck is the result of a readiness check of an agent
+type healthCheck struct {
+	Ok      bool   `json:"ok"`
+	Message string `json:"message"`
+}
+
+// newHealthResponseAgent returns the health of an agent with no checks, which
+// is healthy until a check fails.
+func newHealthResponseAgent() *healthResponseAgent {
+	return &healthResponseAgent{
+		Ok:      true,
+		Message: "ok",
+		Checks:  make(map[string]*healthCheck),
+	}
+}
+
+// check records the result of a check. The message of the agent lists the
+// messages of its failed checks.
+func (h *healthResponseAgent) check(name string, ok bool, message string) {
+	h.Checks[name] = &healthCheck{Ok: ok, Message: message}
+	if ok {
+		return
+	}
+
+	if h.Ok {
+		h.Ok = false
+		h.Message = message
+	} else {
+		h.Message += "; " + message
+	}
+}
+
+// serverStreamingRpcHandler returns the handler of a streaming RPC of the
+// servers, either from the local server or through the servers known to the
+// client.
+func (s *HTTPServer) serverStreamingRpcHandler(method string) (structs.StreamingRpcHandler, error) {
+	if srv := s.agent.Server(); srv != nil {
+		return srv.StreamingRpcHandler(method)
+	}
+	return s.agent.Client().RemoteStreamingRpcHandler(method)
 }
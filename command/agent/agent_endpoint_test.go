@@ -2,18 +2,21 @@ package agent
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strings"
 	"testing"
 	"time"
 
 	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
 	"github.com/hashicorp/nomad/acl"
 	"github.com/hashicorp/nomad/helper/pool"
+	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/testutil"
@@ -673,6 +676,13 @@ func TestHTTP_AgentHealth_Ok(t *testing.T) {
 
 	// Enable ACLs to ensure they're not enforced
 	httpACLTest(t, nil, func(s *TestAgent) {
+		// Wait for the client to be ready
+		testutil.WaitForResult(func() (bool, error) {
+			return s.client.Registered(), nil
+		}, func(err error) {
+			t.Fatalf("client not registered")
+		})
+
 		// No ?type=
 		{
 			req, err := http.NewRequest("GET", "/v1/agent/health", nil)
@@ -747,6 +757,41 @@ func TestHTTP_AgentHealth_Ok(t *testing.T) {
 	})
 }
 
+func TestHTTP_AgentHealth_Checks(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	httpTest(t, nil, func(s *TestAgent) {
+		testutil.WaitForResult(func() (bool, error) {
+			return s.client.Registered(), nil
+		}, func(err error) {
+			t.Fatalf("client not registered")
+		})
+
+		req, err := http.NewRequest("GET", "/v1/agent/health", nil)
+		require.Nil(err)
+
+		respW := httptest.NewRecorder()
+		healthI, err := s.Server.HealthRequest(respW, req)
+		require.Nil(err)
+		health := healthI.(*healthResponse)
+		for _, name := range []string{"servers", "registered", "drivers"} {
+			require.Contains(health.Client.Checks, name)
+			require.True(health.Client.Checks[name].Ok)
+		}
+		require.True(health.Server.Checks["leader"].Ok)
+
+		// Invalid types are rejected
+		req, err = http.NewRequest("GET", "/v1/agent/health?type=foo", nil)
+		require.Nil(err)
+
+		respW = httptest.NewRecorder()
+		_, err = s.Server.HealthRequest(respW, req)
+		require.NotNil(err)
+		require.Equal(400, err.(HTTPCodedError).Code())
+	})
+}
+
 func TestHTTP_AgentHealth_BadServer(t *testing.T) {
 	t.Parallel()
 	require := require.New(t)
@@ -784,7 +829,7 @@ func TestHTTP_AgentHealth_BadServer(t *testing.T) {
 			require.NotNil(err)
 			httpErr, ok := err.(HTTPCodedError)
 			require.True(ok)
-			require.Equal(500, httpErr.Code())
+			require.Equal(http.StatusServiceUnavailable, httpErr.Code())
 			require.Equal(`{"server":{"ok":false,"message":"server not enabled"}}`, err.Error())
 		}
 	})
@@ -827,8 +872,162 @@ func TestHTTP_AgentHealth_BadClient(t *testing.T) {
 			require.NotNil(err)
 			httpErr, ok := err.(HTTPCodedError)
 			require.True(ok)
-			require.Equal(500, httpErr.Code())
+			require.Equal(http.StatusServiceUnavailable, httpErr.Code())
 			require.Equal(`{"client":{"ok":false,"message":"client not enabled"}}`, err.Error())
 		}
 	})
 }
+
+func TestHTTP_AgentMonitor(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	httpTest(t, nil, func(s *TestAgent) {
+		// Invalid log levels are rejected
+		{
+			req, err := http.NewRequest("GET", "/v1/agent/monitor?log_level=unknown", nil)
+			require.Nil(err)
+			_, err = s.Server.AgentMonitor(httptest.NewRecorder(), req)
+			require.NotNil(err)
+			require.Contains(err.Error(), "Unknown log level")
+		}
+
+		// The logs of unknown nodes are rejected by the servers
+		{
+			req, err := http.NewRequest("GET", "/v1/agent/monitor?node_id="+uuid.Generate(), nil)
+			require.Nil(err)
+			_, err = s.Server.AgentMonitor(httptest.NewRecorder(), req)
+			require.NotNil(err)
+			require.Contains(err.Error(), "Unknown node")
+		}
+
+		// Logs at or above the level are streamed
+		req, err := http.NewRequest("GET", "/v1/agent/monitor?log_level=warn", nil)
+		require.Nil(err)
+		ctx, cancel := context.WithCancel(req.Context())
+		req = req.WithContext(ctx)
+
+		respW := httptest.NewRecorder()
+		doneCh := make(chan error, 1)
+		go func() {
+			_, err := s.Server.AgentMonitor(respW, req)
+			doneCh <- err
+		}()
+
+		// Wait for the monitor to be registered before logging
+		testutil.WaitForResult(func() (bool, error) {
+			s.Agent.logWriter.Lock()
+			defer s.Agent.logWriter.Unlock()
+			return len(s.Agent.logWriter.handlers) == 1, fmt.Errorf("monitor not registered")
+		}, func(err error) {
+			t.Fatal(err)
+		})
+		s.Agent.logger.Info("monitor test info")
+		s.Agent.logger.Warn("monitor test warning")
+
+		time.Sleep(100 * time.Millisecond)
+		cancel()
+		require.Nil(<-doneCh)
+
+		body := respW.Body.String()
+		require.Contains(body, "monitor test warning")
+		require.NotContains(body, "monitor test info")
+	})
+}
+
+func TestHTTP_AgentPprof(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	// Profiles require enable_debug without ACLs
+	httpTest(t, func(c *Config) {
+		c.EnableDebug = false
+	}, func(s *TestAgent) {
+		req, err := http.NewRequest("GET", "/v1/agent/pprof/goroutine", nil)
+		require.Nil(err)
+		_, err = s.Server.AgentPprofRequest(httptest.NewRecorder(), req)
+		require.NotNil(err)
+		require.Contains(err.Error(), "enable_debug")
+	})
+
+	httpTest(t, func(c *Config) {
+		c.EnableDebug = true
+	}, func(s *TestAgent) {
+		// Lookup profiles
+		req, err := http.NewRequest("GET", "/v1/agent/pprof/goroutine?debug=1", nil)
+		require.Nil(err)
+		respW := httptest.NewRecorder()
+		_, err = s.Server.AgentPprofRequest(respW, req)
+		require.Nil(err)
+		require.True(strings.HasPrefix(respW.Body.String(), "goroutine profile:"))
+
+		// CPU profile
+		req, err = http.NewRequest("GET", "/v1/agent/pprof/profile?seconds=1", nil)
+		require.Nil(err)
+		respW = httptest.NewRecorder()
+		_, err = s.Server.AgentPprofRequest(respW, req)
+		require.Nil(err)
+		require.NotZero(respW.Body.Len())
+		require.Equal("application/octet-stream", respW.HeaderMap.Get("Content-Type"))
+
+		// The duration of CPU profiles is bounded
+		req, err = http.NewRequest("GET", "/v1/agent/pprof/profile?seconds=3600", nil)
+		require.Nil(err)
+		_, err = s.Server.AgentPprofRequest(httptest.NewRecorder(), req)
+		require.NotNil(err)
+		require.Contains(err.Error(), "Seconds must be at most")
+
+		// Unknown profiles
+		req, err = http.NewRequest("GET", "/v1/agent/pprof/unknown", nil)
+		require.Nil(err)
+		_, err = s.Server.AgentPprofRequest(httptest.NewRecorder(), req)
+		require.NotNil(err)
+		require.Contains(err.Error(), "Unknown profile")
+	})
+}
+
+func TestHTTP_AgentPprof_ACL(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	httpACLTest(t, nil, func(s *TestAgent) {
+		state := s.Agent.server.State()
+
+		req, err := http.NewRequest("GET", "/v1/agent/pprof/goroutine", nil)
+		require.Nil(err)
+
+		// Try request without a token and expect failure
+		{
+			_, err := s.Server.AgentPprofRequest(httptest.NewRecorder(), req)
+			require.NotNil(err)
+			require.Equal(err.Error(), structs.ErrPermissionDenied.Error())
+		}
+
+		// Try request with a read token and expect failure
+		{
+			token := mock.CreatePolicyAndToken(t, state, 1005, "invalid", mock.AgentPolicy(acl.PolicyRead))
+			setToken(req, token)
+			_, err := s.Server.AgentPprofRequest(httptest.NewRecorder(), req)
+			require.NotNil(err)
+			require.Equal(err.Error(), structs.ErrPermissionDenied.Error())
+		}
+
+		// Try request with a write token and expect failure
+		{
+			token := mock.CreatePolicyAndToken(t, state, 1007, "write", mock.AgentPolicy(acl.PolicyWrite))
+			setToken(req, token)
+			_, err := s.Server.AgentPprofRequest(httptest.NewRecorder(), req)
+			require.NotNil(err)
+			require.Equal(err.Error(), structs.ErrPermissionDenied.Error())
+		}
+
+		// Try request with a management token
+		{
+			respW := httptest.NewRecorder()
+			setToken(req, s.RootToken)
+			_, err := s.Server.AgentPprofRequest(respW, req)
+			require.Nil(err)
+			require.NotZero(respW.Body.Len())
+		}
+	})
+}
@@ -11,6 +11,7 @@ import (
 	cstructs "github.com/hashicorp/nomad/client/structs"
 	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad"
 	"github.com/hashicorp/nomad/nomad/structs"
 	sconfig "github.com/hashicorp/nomad/nomad/structs/config"
 	"github.com/stretchr/testify/assert"
@@ -194,6 +195,22 @@ func TestAgent_ServerConfig(t *testing.T) {
 		t.Fatalf("expect 11, got: %v", max)
 	}
 
+	conf.Server.EvalNackTimeout = 90 * time.Second
+	conf.Server.EvalDeliveryLimit = 5
+	out, err = a.serverConfig()
+	if timeout := out.EvalNackTimeout; timeout != 90*time.Second {
+		t.Fatalf("expect 90s, got: %s", timeout)
+	}
+	if limit := out.EvalDeliveryLimit; limit != 5 {
+		t.Fatalf("expect 5, got: %d", limit)
+	}
+
+	conf.Server.EvalDeliveryLimit = -1
+	if _, err := a.serverConfig(); err == nil {
+		t.Fatalf("expected error for negative delivery limit")
+	}
+	conf.Server.EvalDeliveryLimit = 0
+
 	// Defaults to the global bind addr
 	conf.Addresses.RPC = ""
 	conf.Addresses.Serf = ""
@@ -260,6 +277,19 @@ func TestAgent_ServerConfig(t *testing.T) {
 	if out.BootstrapExpect != 3 {
 		t.Fatalf("should have bootstrap-expect = 3")
 	}
+
+	// Dev agents generate a keyring encryption key, other servers require one
+	if len(out.KeyringEncryptionKey) != nomad.KeyringEncryptionKeySize {
+		t.Fatalf("expected a generated keyring encryption key")
+	}
+	conf.DevMode = false
+	if _, err := a.serverConfig(); err == nil {
+		t.Fatalf("expected error for missing keyring encryption key")
+	}
+	conf.Server.KeyringEncryptionKey = "M2Y0ZjdkZTkxNmQyZjY5YWE3NDg2MTQ2YWEyNjNhNTA="
+	if _, err := a.serverConfig(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
 }
 
 func TestAgent_ClientConfig(t *testing.T) {
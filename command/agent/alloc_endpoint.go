@@ -1,13 +1,20 @@
 package agent
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"net"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/golang/snappy"
+	"github.com/gorilla/websocket"
 	cstructs "github.com/hashicorp/nomad/client/structs"
 	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/ugorji/go/codec"
 )
 
 const (
@@ -15,6 +22,12 @@ const (
 	resourceNotFoundErr = "resource not found"
 )
 
+// wsUpgrader upgrades the connections of exec sessions to websockets
+var wsUpgrader = websocket.Upgrader{
+	ReadBufferSize:  2048,
+	WriteBufferSize: 2048,
+}
+
 func (s *HTTPServer) AllocsRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
 	if req.Method != "GET" {
 		return nil, CodedError(405, ErrInvalidMethod)
@@ -42,6 +55,9 @@ func (s *HTTPServer) AllocsRequest(resp http.ResponseWriter, req *http.Request)
 
 func (s *HTTPServer) AllocSpecificRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
 	allocID := strings.TrimPrefix(req.URL.Path, "/v1/allocation/")
+	if strings.HasSuffix(allocID, "/stop") {
+		return s.allocStop(strings.TrimSuffix(allocID, "/stop"), resp, req)
+	}
 	if req.Method != "GET" {
 		return nil, CodedError(405, ErrInvalidMethod)
 	}
@@ -78,6 +94,28 @@ func (s *HTTPServer) AllocSpecificRequest(resp http.ResponseWriter, req *http.Re
 	return alloc, nil
 }
 
+func (s *HTTPServer) allocStop(allocID string, resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "PUT" && req.Method != "POST" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	args := structs.AllocStopRequest{
+		AllocID: allocID,
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.AllocStopResponse
+	if err := s.agent.RPC("Alloc.Stop", &args, &out); err != nil {
+		if structs.IsErrUnknownAllocation(err) {
+			return nil, CodedError(404, err.Error())
+		}
+		return nil, err
+	}
+
+	setIndex(resp, out.Index)
+	return &out, nil
+}
+
 func (s *HTTPServer) ClientAllocRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
 
 	reqSuffix := strings.TrimPrefix(req.URL.Path, "/v1/client/allocation/")
@@ -100,6 +138,12 @@ func (s *HTTPServer) ClientAllocRequest(resp http.ResponseWriter, req *http.Requ
 		return s.allocSnapshot(allocID, resp, req)
 	case "gc":
 		return s.allocGC(allocID, resp, req)
+	case "restart":
+		return s.allocRestart(allocID, resp, req)
+	case "signal":
+		return s.allocSignal(allocID, resp, req)
+	case "exec":
+		return s.allocExec(allocID, resp, req)
 	}
 
 	return nil, CodedError(404, resourceNotFoundErr)
@@ -172,6 +216,86 @@ func (s *HTTPServer) allocGC(allocID string, resp http.ResponseWriter, req *http
 	return nil, rpcErr
 }
 
+func (s *HTTPServer) allocRestart(allocID string, resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "PUT" && req.Method != "POST" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	// Build the request and parse the ACL token. An empty body restarts all
+	// the tasks of the allocation.
+	var args cstructs.AllocRestartRequest
+	if err := decodeBody(req, &args); err != nil && err != io.EOF {
+		return nil, CodedError(400, err.Error())
+	}
+	args.AllocID = allocID
+	s.parse(resp, req, &args.QueryOptions.Region, &args.QueryOptions)
+
+	// Determine the handler to use
+	useLocalClient, useClientRPC, useServerRPC := s.rpcHandlerForAlloc(allocID)
+
+	// Make the RPC
+	var reply structs.GenericResponse
+	var rpcErr error
+	if useLocalClient {
+		rpcErr = s.agent.Client().ClientRPC("Allocations.Restart", &args, &reply)
+	} else if useClientRPC {
+		rpcErr = s.agent.Client().RPC("ClientAllocations.Restart", &args, &reply)
+	} else if useServerRPC {
+		rpcErr = s.agent.Server().RPC("ClientAllocations.Restart", &args, &reply)
+	} else {
+		rpcErr = CodedError(400, "No local Node and node_id not provided")
+	}
+
+	if rpcErr != nil {
+		if structs.IsErrNoNodeConn(rpcErr) || structs.IsErrUnknownAllocation(rpcErr) {
+			rpcErr = CodedError(404, rpcErr.Error())
+		}
+	}
+
+	return nil, rpcErr
+}
+
+func (s *HTTPServer) allocSignal(allocID string, resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "PUT" && req.Method != "POST" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	// Build the request and parse the ACL token
+	var args cstructs.AllocSignalRequest
+	if err := decodeBody(req, &args); err != nil {
+		return nil, CodedError(400, err.Error())
+	}
+	if args.Signal == "" {
+		return nil, CodedError(400, "must provide a signal")
+	}
+	args.AllocID = allocID
+	s.parse(resp, req, &args.QueryOptions.Region, &args.QueryOptions)
+
+	// Determine the handler to use
+	useLocalClient, useClientRPC, useServerRPC := s.rpcHandlerForAlloc(allocID)
+
+	// Make the RPC
+	var reply structs.GenericResponse
+	var rpcErr error
+	if useLocalClient {
+		rpcErr = s.agent.Client().ClientRPC("Allocations.Signal", &args, &reply)
+	} else if useClientRPC {
+		rpcErr = s.agent.Client().RPC("ClientAllocations.Signal", &args, &reply)
+	} else if useServerRPC {
+		rpcErr = s.agent.Server().RPC("ClientAllocations.Signal", &args, &reply)
+	} else {
+		rpcErr = CodedError(400, "No local Node and node_id not provided")
+	}
+
+	if rpcErr != nil {
+		if structs.IsErrNoNodeConn(rpcErr) || structs.IsErrUnknownAllocation(rpcErr) {
+			rpcErr = CodedError(404, rpcErr.Error())
+		}
+	}
+
+	return nil, rpcErr
+}
+
 func (s *HTTPServer) allocSnapshot(allocID string, resp http.ResponseWriter, req *http.Request) (interface{}, error) {
 	var secret string
 	s.parseToken(req, &secret)
@@ -223,3 +347,185 @@ func (s *HTTPServer) allocStats(allocID string, resp http.ResponseWriter, req *h
 
 	return reply.Stats, rpcErr
 }
+
+func (s *HTTPServer) allocExec(allocID string, resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	// Build the request and parse the ACL token
+	q := req.URL.Query()
+	task := q.Get("task")
+	if task == "" {
+		return nil, CodedError(400, "must provide task name")
+	}
+
+	var command []string
+	if err := json.Unmarshal([]byte(q.Get("command")), &command); err != nil {
+		return nil, CodedError(400, fmt.Sprintf("command must be a JSON encoded array of strings: %v", err))
+	}
+	if len(command) == 0 {
+		return nil, CodedError(400, "must provide a command")
+	}
+
+	var tty bool
+	if ttyStr := q.Get("tty"); ttyStr != "" {
+		var err error
+		if tty, err = strconv.ParseBool(ttyStr); err != nil {
+			return nil, CodedError(400, fmt.Sprintf("Failed to parse tty field to boolean: %v", err))
+		}
+	}
+
+	args := &cstructs.AllocExecRequest{
+		AllocID: allocID,
+		Task:    task,
+		Cmd:     command,
+		Tty:     tty,
+	}
+	s.parse(resp, req, &args.QueryOptions.Region, &args.QueryOptions)
+
+	// The upgrader replies with an error itself if the upgrade fails
+	conn, err := wsUpgrader.Upgrade(resp, req, nil)
+	if err != nil {
+		s.logger.Debug("failed to upgrade exec connection", "error", err)
+		return nil, nil
+	}
+
+	s.execStreamImpl(conn, args)
+	return nil, nil
+}
+
+// execStreamImpl bridges the websocket of an exec session to the (possibly
+// remote) handler of the session. The messages received over the websocket
+// are JSON encoded ExecStreamingInput and the messages sent are JSON encoded
+// ExecStreamingOutput. Errors are reported by closing the websocket.
+func (s *HTTPServer) execStreamImpl(ws *websocket.Conn, args *cstructs.AllocExecRequest) {
+	defer ws.Close()
+	method := "Allocations.Exec"
+
+	// Get the correct handler
+	localClient, remoteClient, localServer := s.rpcHandlerForAlloc(args.AllocID)
+	var handler structs.StreamingRpcHandler
+	var handlerErr error
+	if localClient {
+		handler, handlerErr = s.agent.Client().StreamingRpcHandler(method)
+	} else if remoteClient {
+		handler, handlerErr = s.agent.Client().RemoteStreamingRpcHandler(method)
+	} else if localServer {
+		handler, handlerErr = s.agent.Server().StreamingRpcHandler(method)
+	} else {
+		handlerErr = fmt.Errorf("no local client or server to handle the request")
+	}
+
+	if handlerErr != nil {
+		writeWsClose(ws, CodedError(500, handlerErr.Error()))
+		return
+	}
+
+	// Create a pipe connecting the (possibly remote) handler to the websocket
+	httpPipe, handlerPipe := net.Pipe()
+	decoder := codec.NewDecoder(httpPipe, structs.MsgpackHandle)
+	encoder := codec.NewEncoder(httpPipe, structs.MsgpackHandle)
+
+	// Create a goroutine that closes the pipe if the session ends
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-ctx.Done()
+		httpPipe.Close()
+	}()
+
+	errCh := make(chan HTTPCodedError, 1)
+	go func() {
+		defer cancel()
+
+		// Send the request
+		if err := encoder.Encode(args); err != nil {
+			errCh <- CodedError(500, err.Error())
+			return
+		}
+
+		go forwardExecInput(ctx, cancel, encoder, ws)
+
+		for {
+			var res cstructs.StreamErrWrapper
+			if err := decoder.Decode(&res); err != nil {
+				errCh <- CodedError(500, err.Error())
+				return
+			}
+			decoder.Reset(httpPipe)
+
+			if err := res.Error; err != nil {
+				code := 500
+				if err.Code != nil {
+					code = int(*err.Code)
+				}
+				errCh <- CodedError(code, err.Error())
+				return
+			}
+
+			if err := ws.WriteMessage(websocket.TextMessage, res.Payload); err != nil {
+				errCh <- CodedError(500, err.Error())
+				return
+			}
+		}
+	}()
+
+	handler(handlerPipe)
+	cancel()
+	codedErr := <-errCh
+
+	// Ignore EOF and ErrClosedPipe errors.
+	if codedErr != nil &&
+		(codedErr == io.EOF ||
+			strings.Contains(codedErr.Error(), "closed") ||
+			strings.Contains(codedErr.Error(), "EOF")) {
+		codedErr = nil
+	}
+	writeWsClose(ws, codedErr)
+}
+
+// forwardExecInput forwards the messages received over the websocket of an
+// exec session to its handler. The session is cancelled once the websocket is
+// closed.
+func forwardExecInput(ctx context.Context, cancel context.CancelFunc, encoder *codec.Encoder, ws *websocket.Conn) {
+	defer cancel()
+
+	for {
+		_, msg, err := ws.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		var in cstructs.ExecStreamingInput
+		if err := json.Unmarshal(msg, &in); err != nil {
+			return
+		}
+
+		if err := encoder.Encode(&in); err != nil {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+	}
+}
+
+// writeWsClose closes the websocket of an exec session, reporting the error
+// if any. The HTTP status code of the error is mapped to the private close
+// code range of the websocket protocol.
+func writeWsClose(ws *websocket.Conn, err HTTPCodedError) {
+	code := websocket.CloseNormalClosure
+	reason := ""
+	if err != nil {
+		code = 4000 + err.Code()
+		if err.Code() == 500 {
+			code = websocket.CloseInternalServerErr
+		}
+
+		// Control frames are limited to 125 bytes
+		reason = err.Error()
+		if len(reason) > 123 {
+			reason = reason[:123]
+		}
+	}
+	ws.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason))
+}
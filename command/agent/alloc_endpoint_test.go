@@ -2,6 +2,8 @@ package agent
 
 import (
 	"archive/tar"
+	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -11,6 +13,7 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/snappy"
 	"github.com/hashicorp/nomad/acl"
@@ -204,6 +207,47 @@ func TestHTTP_AllocQuery(t *testing.T) {
 	})
 }
 
+func TestHTTP_AllocStop(t *testing.T) {
+	t.Parallel()
+	httpTest(t, nil, func(s *TestAgent) {
+		// Directly manipulate the state
+		state := s.Agent.server.State()
+		alloc := mock.Alloc()
+		require := require.New(t)
+		require.NoError(state.UpsertJobSummary(999, mock.JobSummary(alloc.JobID)))
+		require.NoError(state.UpsertAllocs(1000, []*structs.Allocation{alloc}))
+
+		// Only PUT and POST are allowed
+		req, err := http.NewRequest("GET", "/v1/allocation/"+alloc.ID+"/stop", nil)
+		require.NoError(err)
+		respW := httptest.NewRecorder()
+		_, err = s.Server.AllocSpecificRequest(respW, req)
+		require.Error(err)
+		require.Contains(err.Error(), ErrInvalidMethod)
+
+		// Stop the allocation
+		req, err = http.NewRequest("POST", "/v1/allocation/"+alloc.ID+"/stop", nil)
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+		obj, err := s.Server.AllocSpecificRequest(respW, req)
+		require.NoError(err)
+		require.NotEmpty(respW.HeaderMap.Get("X-Nomad-Index"))
+
+		a := obj.(*structs.AllocStopResponse)
+		require.NotEmpty(a.EvalID)
+
+		// Unknown allocations return a 404
+		req, err = http.NewRequest("POST", "/v1/allocation/"+uuid.Generate()+"/stop", nil)
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+		_, err = s.Server.AllocSpecificRequest(respW, req)
+		require.Error(err)
+		codedErr, ok := err.(HTTPCodedError)
+		require.True(ok)
+		require.Equal(404, codedErr.Code())
+	})
+}
+
 func TestHTTP_AllocQuery_Payload(t *testing.T) {
 	t.Parallel()
 	httpTest(t, nil, func(s *TestAgent) {
@@ -779,3 +823,65 @@ func TestHTTP_AllocAllGC_ACL(t *testing.T) {
 		}
 	})
 }
+
+func TestHTTP_AllocExec_MissingParams(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		allocID := uuid.Generate()
+
+		// Missing task
+		req, err := http.NewRequest("GET", "/v1/client/allocation/"+allocID+"/exec", nil)
+		require.Nil(err)
+		_, err = s.Server.ClientAllocRequest(httptest.NewRecorder(), req)
+		require.Error(err)
+		require.Contains(err.Error(), "must provide task name")
+
+		// Missing command
+		req, err = http.NewRequest("GET", "/v1/client/allocation/"+allocID+"/exec?task=web", nil)
+		require.Nil(err)
+		_, err = s.Server.ClientAllocRequest(httptest.NewRecorder(), req)
+		require.Error(err)
+		require.Contains(err.Error(), "command must be a JSON encoded array")
+
+		// Bad tty
+		req, err = http.NewRequest("GET", "/v1/client/allocation/"+allocID+"/exec?task=web&command=%5B%22ls%22%5D&tty=maybe", nil)
+		require.Nil(err)
+		_, err = s.Server.ClientAllocRequest(httptest.NewRecorder(), req)
+		require.Error(err)
+		require.Contains(err.Error(), "Failed to parse tty")
+	})
+}
+
+func TestHTTP_AllocExec(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		a := mockFSAlloc(s.client.NodeID(), map[string]interface{}{
+			"run_for": "20s",
+		})
+		addAllocToClient(s, a, runningClientAlloc)
+
+		client := s.Client()
+		alloc, _, err := client.Allocations().Info(a.ID, nil)
+		require.NoError(err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		// The mock driver echoes stdin to stdout
+		var stdout, stderr bytes.Buffer
+		code, err := client.Allocations().Exec(ctx, alloc, a.Job.TaskGroups[0].Tasks[0].Name,
+			false, []string{"cat"}, strings.NewReader("hello from stdin"), &stdout, &stderr, nil, nil)
+		require.NoError(err)
+		require.Equal(0, code)
+		require.Equal("hello from stdin", stdout.String())
+		require.Empty(stderr.String())
+
+		// Unknown tasks are rejected
+		_, err = client.Allocations().Exec(ctx, alloc, "unknown",
+			false, []string{"cat"}, nil, &stdout, &stderr, nil, nil)
+		require.Error(err)
+		require.Contains(err.Error(), "unknown task name")
+	})
+}
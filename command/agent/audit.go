@@ -0,0 +1,390 @@
+package agent
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// auditDialTimeout bounds the time spent connecting to a socket sink.
+	auditDialTimeout = 5 * time.Second
+
+	// auditWriteTimeout bounds the time spent writing a record to a socket
+	// sink.
+	auditWriteTimeout = 5 * time.Second
+
+	// auditBufferSize is the number of records waiting to be written to the
+	// sinks, beyond which the records of new requests are dropped.
+	auditBufferSize = 1024
+)
+
+// auditEvent is the record of an HTTP API request written to the audit log.
+type auditEvent struct {
+	ID        string        `json:"id"`
+	Timestamp time.Time     `json:"timestamp"`
+	Auth      auditAuth     `json:"auth"`
+	Request   auditRequest  `json:"request"`
+	Response  auditResponse `json:"response"`
+}
+
+// auditAuth is the ACL token of an audited request. It is only set if ACLs
+// are enabled, and never includes the secret ID of the token.
+type auditAuth struct {
+	AccessorID string `json:"accessor_id,omitempty"`
+	Name       string `json:"name,omitempty"`
+	Type       string `json:"type,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// auditRequest is an audited request.
+type auditRequest struct {
+	Operation  string `json:"operation"`
+	Endpoint   string `json:"endpoint"`
+	Namespace  string `json:"namespace,omitempty"`
+	Region     string `json:"region,omitempty"`
+	RemoteAddr string `json:"remote_addr"`
+}
+
+// auditResponse is the response of an audited request.
+type auditResponse struct {
+	StatusCode int     `json:"status_code"`
+	LatencyMS  float64 `json:"latency_ms"`
+}
+
+// auditor writes the audit records of the HTTP API requests to its sinks.
+// Records are queued by the requests and written in the background, so that
+// slow or unavailable sinks don't delay the requests.
+type auditor struct {
+	logger  log.Logger
+	sinks   []auditSink
+	filters []*AuditFilter
+
+	// resolveToken resolves the secret ID of the token of a request.
+	resolveToken func(secretID string) (*structs.ACLToken, error)
+
+	// records is the queue of the records to write, closed when the
+	// auditor is closed, and doneCh is closed once it is drained.
+	records chan *auditRecord
+	doneCh  chan struct{}
+	closed  bool
+	l       sync.RWMutex
+}
+
+// auditRecord is a queued record with the secret ID of the token of its
+// request, which is resolved when the record is written.
+type auditRecord struct {
+	event    *auditEvent
+	secretID string
+}
+
+// auditSink is where the audit records are written.
+type auditSink interface {
+	// Write writes a record, terminated by a newline.
+	Write(record []byte) error
+	Close() error
+}
+
+// newAuditor returns the auditor of the configuration, or nil if the audit log
+// isn't enabled.
+func newAuditor(conf *AuditConfig, logger log.Logger,
+	resolveToken func(string) (*structs.ACLToken, error)) (*auditor, error) {
+
+	if conf == nil || conf.Enabled == nil || !*conf.Enabled {
+		return nil, nil
+	}
+	if len(conf.Sinks) == 0 {
+		return nil, fmt.Errorf("audit log requires at least one sink")
+	}
+
+	a := &auditor{
+		logger:       logger.Named("audit"),
+		filters:      conf.Filters,
+		resolveToken: resolveToken,
+		records:      make(chan *auditRecord, auditBufferSize),
+		doneCh:       make(chan struct{}),
+	}
+	for _, s := range conf.Sinks {
+		sink, err := newAuditSink(s)
+		if err != nil {
+			a.closeSinks()
+			return nil, fmt.Errorf("audit sink %q: %v", s.Name, err)
+		}
+		a.sinks = append(a.sinks, sink)
+	}
+
+	go a.run()
+	return a, nil
+}
+
+// newAuditSink returns the sink of the configuration.
+func newAuditSink(conf *AuditSink) (auditSink, error) {
+	switch conf.Type {
+	case AuditSinkFile:
+		if conf.Path == "" {
+			return nil, fmt.Errorf("file sink requires a path")
+		}
+		f, err := os.OpenFile(conf.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+		if err != nil {
+			return nil, err
+		}
+		return &auditFileSink{f: f}, nil
+	case AuditSinkSocket:
+		if conf.Network == "" || conf.Address == "" {
+			return nil, fmt.Errorf("socket sink requires a network and an address")
+		}
+		return &auditSocketSink{network: conf.Network, address: conf.Address}, nil
+	default:
+		return nil, fmt.Errorf("unknown sink type %q", conf.Type)
+	}
+}
+
+// Close writes the queued records and closes the sinks of the auditor, if
+// any.
+func (a *auditor) Close() {
+	if a == nil {
+		return
+	}
+
+	a.l.Lock()
+	if !a.closed {
+		a.closed = true
+		close(a.records)
+	}
+	a.l.Unlock()
+
+	<-a.doneCh
+}
+
+// closeSinks closes the sinks of the auditor.
+func (a *auditor) closeSinks() {
+	for _, s := range a.sinks {
+		if err := s.Close(); err != nil {
+			a.logger.Warn("failed to close sink", "error", err)
+		}
+	}
+}
+
+// excluded returns whether a filter excludes the request from the audit log.
+func (a *auditor) excluded(req *http.Request) bool {
+	for _, f := range a.filters {
+		if matchAuditFilter(f.Operations, req.Method, strings.EqualFold) &&
+			matchAuditFilter(f.Endpoints, req.URL.Path, matchAuditEndpoint) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchAuditFilter returns whether the value matches one of the patterns of a
+// filter, or if the filter has no pattern.
+func matchAuditFilter(patterns []string, value string, match func(pattern, value string) bool) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, p := range patterns {
+		if match(p, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchAuditEndpoint returns whether the endpoint matches the pattern, whose
+// trailing "*" matches any suffix.
+func matchAuditEndpoint(pattern, endpoint string) bool {
+	if strings.HasSuffix(pattern, "*") {
+		return strings.HasPrefix(endpoint, strings.TrimSuffix(pattern, "*"))
+	}
+	return pattern == endpoint
+}
+
+// event returns the audit record of the request, whose token is resolved when
+// the record is written.
+func (a *auditor) event(req *http.Request, code int, start time.Time) *auditRecord {
+	query := req.URL.Query()
+	e := &auditEvent{
+		ID:        uuid.Generate(),
+		Timestamp: start.UTC(),
+		Request: auditRequest{
+			Operation:  req.Method,
+			Endpoint:   req.URL.Path,
+			Namespace:  query.Get("namespace"),
+			Region:     query.Get("region"),
+			RemoteAddr: req.RemoteAddr,
+		},
+		Response: auditResponse{
+			StatusCode: code,
+			LatencyMS:  float64(time.Since(start)) / float64(time.Millisecond),
+		},
+	}
+
+	return &auditRecord{event: e, secretID: req.Header.Get("X-Nomad-Token")}
+}
+
+// queue queues the record to be written, dropping it if the queue is full
+// because the sinks can't keep up.
+func (a *auditor) queue(r *auditRecord) {
+	a.l.RLock()
+	defer a.l.RUnlock()
+	if a.closed {
+		return
+	}
+
+	select {
+	case a.records <- r:
+	default:
+		metrics.IncrCounter([]string{"nomad", "audit", "dropped"}, 1)
+	}
+}
+
+// run writes the queued records until the auditor is closed, and then closes
+// the sinks.
+func (a *auditor) run() {
+	defer close(a.doneCh)
+	defer a.closeSinks()
+
+	for r := range a.records {
+		a.write(r)
+	}
+}
+
+// write resolves the token of the record and writes it to every sink.
+func (a *auditor) write(r *auditRecord) {
+	e := r.event
+	if a.resolveToken != nil {
+		token, err := a.resolveToken(r.secretID)
+		switch {
+		case err != nil:
+			e.Auth.Error = err.Error()
+		case token != nil:
+			e.Auth.AccessorID = token.AccessorID
+			e.Auth.Name = token.Name
+			e.Auth.Type = token.Type
+		}
+	}
+
+	record, err := json.Marshal(e)
+	if err != nil {
+		a.logger.Error("failed to encode record", "error", err)
+		return
+	}
+	record = append(record, '\n')
+
+	for _, s := range a.sinks {
+		if err := s.Write(record); err != nil {
+			metrics.IncrCounter([]string{"nomad", "audit", "write_errors"}, 1)
+			a.logger.Error("failed to write record", "error", err)
+		}
+	}
+}
+
+// agentTokenResolver returns a function resolving the ACL tokens through the
+// server or the client of the agent.
+func agentTokenResolver(agent *Agent) func(secretID string) (*structs.ACLToken, error) {
+	return func(secretID string) (*structs.ACLToken, error) {
+		if srv := agent.Server(); srv != nil {
+			return srv.ResolveSecretToken(secretID)
+		}
+		if client := agent.Client(); client != nil {
+			return client.ResolveSecretToken(secretID)
+		}
+		return nil, nil
+	}
+}
+
+// auditHandler wraps the handler to queue the audit records of its requests,
+// once they complete. It wraps the handlers within the rate limits, so that
+// rejected requests don't cost the resolution of their token.
+func (s *HTTPServer) auditHandler(h http.Handler) http.Handler {
+	if s.auditor == nil {
+		return h
+	}
+
+	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		if s.auditor.excluded(req) {
+			h.ServeHTTP(resp, req)
+			return
+		}
+
+		start := time.Now()
+		rw := &statusResponseWriter{ResponseWriter: resp}
+		h.ServeHTTP(rw, req)
+		s.auditor.queue(s.auditor.event(req, rw.statusCode(), start))
+	})
+}
+
+// auditFileSink appends the records to a file.
+type auditFileSink struct {
+	l sync.Mutex
+	f *os.File
+}
+
+func (s *auditFileSink) Write(record []byte) error {
+	s.l.Lock()
+	defer s.l.Unlock()
+	_, err := s.f.Write(record)
+	return err
+}
+
+func (s *auditFileSink) Close() error {
+	return s.f.Close()
+}
+
+// auditSocketSink writes the records to a socket, connecting to it as
+// needed so that it recovers from the restarts of the listener.
+type auditSocketSink struct {
+	network string
+	address string
+
+	l    sync.Mutex
+	conn net.Conn
+}
+
+func (s *auditSocketSink) Write(record []byte) error {
+	s.l.Lock()
+	defer s.l.Unlock()
+
+	// A connection closed by the listener is only detected when written to,
+	// so the record is written again on a new connection once.
+	var err error
+	for attempt := 0; attempt < 2; attempt++ {
+		if s.conn == nil {
+			conn, err := net.DialTimeout(s.network, s.address, auditDialTimeout)
+			if err != nil {
+				return err
+			}
+			s.conn = conn
+		}
+
+		s.conn.SetWriteDeadline(time.Now().Add(auditWriteTimeout))
+		if _, err = s.conn.Write(record); err == nil {
+			return nil
+		}
+		s.conn.Close()
+		s.conn = nil
+	}
+	return err
+}
+
+func (s *auditSocketSink) Close() error {
+	s.l.Lock()
+	defer s.l.Unlock()
+
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
@@ -0,0 +1,176 @@
+package agent
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditor_Excluded(t *testing.T) {
+	t.Parallel()
+
+	a := &auditor{
+		filters: []*AuditFilter{
+			{
+				Name:      "health",
+				Endpoints: []string{"/v1/agent/health", "/v1/metrics"},
+			},
+			{
+				Name:       "reads",
+				Endpoints:  []string{"/v1/job/*"},
+				Operations: []string{"GET"},
+			},
+		},
+	}
+
+	cases := []struct {
+		method, path string
+		excluded     bool
+	}{
+		{"GET", "/v1/agent/health", true},
+		{"PUT", "/v1/metrics", true},
+		{"GET", "/v1/agent/self", false},
+		{"get", "/v1/job/example", true},
+		{"DELETE", "/v1/job/example", false},
+		{"GET", "/v1/jobs", false},
+	}
+	for _, c := range cases {
+		req, err := http.NewRequest(c.method, c.path, nil)
+		require.NoError(t, err)
+		req.Method = c.method
+		require.Equal(t, c.excluded, a.excluded(req), "%s %s", c.method, c.path)
+	}
+}
+
+func TestNewAuditor(t *testing.T) {
+	t.Parallel()
+
+	a, err := newAuditor(nil, testlog.HCLogger(t), nil)
+	require.NoError(t, err)
+	require.Nil(t, a)
+
+	_, err = newAuditor(&AuditConfig{Enabled: helper.BoolToPtr(true)}, testlog.HCLogger(t), nil)
+	require.Error(t, err)
+
+	_, err = newAuditor(&AuditConfig{
+		Enabled: helper.BoolToPtr(true),
+		Sinks:   []*AuditSink{{Name: "bad", Type: "syslog"}},
+	}, testlog.HCLogger(t), nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "unknown sink type")
+}
+
+func TestAuditSocketSink(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(err)
+	defer ln.Close()
+
+	sink := &auditSocketSink{network: "tcp", address: ln.Addr().String()}
+	defer sink.Close()
+	require.NoError(sink.Write([]byte("one\n")))
+
+	conn, err := ln.Accept()
+	require.NoError(err)
+	r := bufio.NewReader(conn)
+	line, err := r.ReadString('\n')
+	require.NoError(err)
+	require.Equal("one\n", line)
+
+	// The sink reconnects once the listener closes the connection. Records
+	// written before the close is detected are lost.
+	conn.Close()
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		if conn, err := ln.Accept(); err == nil {
+			accepted <- conn
+		}
+	}()
+
+	for i := 0; ; i++ {
+		require.True(i < 50, "sink didn't reconnect")
+		sink.Write([]byte("two\n"))
+		select {
+		case conn := <-accepted:
+			defer conn.Close()
+			line, err := bufio.NewReader(conn).ReadString('\n')
+			require.NoError(err)
+			require.Equal("two\n", line)
+			return
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+func TestHTTP_Audit(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "nomad-audit")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "audit.json")
+
+	httpACLTest(t, func(c *Config) {
+		c.Audit = &AuditConfig{
+			Enabled: helper.BoolToPtr(true),
+			Sinks: []*AuditSink{
+				{Name: "file", Type: AuditSinkFile, Path: path},
+			},
+			Filters: []*AuditFilter{
+				{Name: "health", Endpoints: []string{"/v1/agent/health"}},
+			},
+		}
+	}, func(s *TestAgent) {
+		do := func(path, token string) {
+			req, err := http.NewRequest("GET", s.HTTPAddr()+path, nil)
+			require.NoError(err)
+			if token != "" {
+				req.Header.Set("X-Nomad-Token", token)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(err)
+			ioutil.ReadAll(resp.Body)
+			resp.Body.Close()
+		}
+
+		do("/v1/agent/health", "")
+		do("/v1/jobs?namespace=default", s.RootToken.SecretID)
+		do("/v1/jobs", "")
+
+		s.Server.auditor.Close()
+		raw, err := ioutil.ReadFile(path)
+		require.NoError(err)
+		lines := strings.Split(strings.TrimSpace(string(raw)), "\n")
+		require.Len(lines, 2)
+
+		var e auditEvent
+		require.NoError(json.Unmarshal([]byte(lines[0]), &e))
+		require.Equal("GET", e.Request.Operation)
+		require.Equal("/v1/jobs", e.Request.Endpoint)
+		require.Equal("default", e.Request.Namespace)
+		require.Equal(200, e.Response.StatusCode)
+		require.Equal(s.RootToken.AccessorID, e.Auth.AccessorID)
+		require.Equal(structs.ACLManagementToken, e.Auth.Type)
+		require.NotContains(lines[0], s.RootToken.SecretID)
+
+		e = auditEvent{}
+		require.NoError(json.Unmarshal([]byte(lines[1]), &e))
+		require.Equal(403, e.Response.StatusCode)
+		require.Equal(structs.AnonymousACLToken.AccessorID, e.Auth.AccessorID)
+	})
+}
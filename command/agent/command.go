@@ -24,6 +24,7 @@ import (
 	discover "github.com/hashicorp/go-discover"
 	gsyslog "github.com/hashicorp/go-syslog"
 	"github.com/hashicorp/logutils"
+	"github.com/hashicorp/nomad/client"
 	flaghelper "github.com/hashicorp/nomad/helper/flag-helpers"
 	gatedwriter "github.com/hashicorp/nomad/helper/gated-writer"
 	"github.com/hashicorp/nomad/nomad/structs/config"
@@ -726,6 +727,16 @@ WAIT:
 		graceful = true
 	}
 
+	// Clients draining on shutdown leave gracefully on SIGTERM and may take
+	// up to the drain deadline to do so
+	timeout := gracefulTimeout
+	if sig == syscall.SIGTERM && c.agent.Client() != nil {
+		if drain := c.agent.Client().GetConfig().DrainOnShutdown; drain != nil {
+			graceful = true
+			timeout += drain.Deadline + client.DrainWaitGrace
+		}
+	}
+
 	// Bail fast if not doing a graceful leave
 	if !graceful {
 		return 1
@@ -746,7 +757,7 @@ WAIT:
 	select {
 	case <-signalCh:
 		return 1
-	case <-time.After(gracefulTimeout):
+	case <-time.After(timeout):
 		return 1
 	case <-gracefulCh:
 		return 0
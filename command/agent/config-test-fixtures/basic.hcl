@@ -25,8 +25,15 @@ client {
 	enabled = true
 	state_dir = "/tmp/client-state"
 	alloc_dir = "/tmp/alloc"
+	host_volumes_dir = "/tmp/host_volumes"
+	host_volume_plugin_dir = "/tmp/host_volume_plugins"
+	cni_path = "/tmp/cni/bin"
+	cni_config_dir = "/tmp/cni/config"
+	bridge_network_name = "testbridge"
+	bridge_network_subnet = "10.10.0.0/16"
 	servers = ["a.b.c:80", "127.0.0.1:1234"]
 	node_class = "linux-medium-64bit"
+	node_pool = "linux"
 	meta {
 		foo = "bar"
 		baz = "zip"
@@ -53,6 +60,7 @@ client {
 		disk = 10
 		iops = 10
 		reserved_ports = "1,100,10-12"
+		cgroup_slice = "system.slice"
 	}
 	client_min_port = 1000
 	client_max_port = 2000
@@ -66,7 +74,31 @@ client {
 	gc_disk_usage_threshold = 82
 	gc_inode_usage_threshold = 91
 	gc_max_allocs = 50
+	disk_ineligible_threshold = 95
 	no_host_uuid = false
+	enable_task_api = true
+	fingerprinter "raid" {
+		command = "/usr/local/bin/raid-health"
+		args = ["-json"]
+		interval = "30s"
+		timeout = "5s"
+	}
+	host_volume "shared_data" {
+		path = "/srv/data"
+		read_only = true
+	}
+	host_network "public" {
+		interface = "eth1"
+		cidr = "203.0.113.0/24"
+		reserved_ports = "22"
+	}
+	drain_on_shutdown {
+		deadline = "10m"
+		ignore_system_jobs = true
+	}
+	drain_on_termination {
+		deadline = "90s"
+	}
 }
 server {
 	enabled = true
@@ -84,6 +116,20 @@ server {
 	heartbeat_grace   = "30s"
 	min_heartbeat_ttl = "33s"
 	max_heartbeats_per_second = 11.0
+	node_event_retention = 25
+	plan_batch_size = 8
+	eval_update_batch_size = 32
+	rpc_max_idle_streams = 16
+	rpc_conn_idle_timeout = "5m"
+	eval_fair_share = true
+	eval_fair_share_weights {
+		default = 1
+		prod = 3
+	}
+	eval_nack_timeout = "90s"
+	eval_delivery_limit = 5
+	eval_nack_initial_reenqueue_delay = "2s"
+	eval_nack_subsequent_reenqueue_delay = "30s"
 	retry_join = [ "1.1.1.1", "2.2.2.2" ]
 	start_join = [ "1.1.1.1", "2.2.2.2" ]
 	retry_max = 3
@@ -93,6 +139,13 @@ server {
 	redundancy_zone = "foo"
 	upgrade_version = "0.8.0"
 	encrypt = "abc"
+	keyring_encryption_key = "M2Y0ZjdkZTkxNmQyZjY5YWE3NDg2MTQ2YWEyNjNhNTA="
+	admission_webhook "policy" {
+		url = "https://policy.example.com/nomad"
+		type = "mutating"
+		timeout = "5s"
+		failure_policy = "ignore"
+	}
 	server_join {
 		retry_join = [ "1.1.1.1", "2.2.2.2" ]
 		retry_max = 3
@@ -103,6 +156,8 @@ acl {
 	enabled = true
 	token_ttl = "60s"
 	policy_ttl = "60s"
+	token_min_expiration_ttl = "1m"
+	token_max_expiration_ttl = "72h"
 	replication_token = "foobar"
 }
 telemetry {
@@ -125,6 +180,31 @@ disable_anonymous_signature = true
 http_api_response_headers {
 	Access-Control-Allow-Origin = "*"
 }
+http_api_gzip_min_size = 2048
+http_api_cors {
+	allowed_origins = ["https://*.example.com"]
+	allowed_methods = ["GET", "PUT"]
+	allowed_headers = ["X-Nomad-Token"]
+	exposed_headers = ["X-Nomad-Index"]
+	allow_credentials = true
+	max_age = 600
+}
+audit {
+	enabled = true
+	sink "file" {
+		type = "file"
+		path = "/var/log/nomad/audit.json"
+	}
+	sink "collector" {
+		type = "socket"
+		network = "tcp"
+		address = "127.0.0.1:9090"
+	}
+	filter "health" {
+		endpoints = ["/v1/agent/health", "/v1/metrics"]
+		operations = ["GET"]
+	}
+}
 consul {
 	server_service_name = "nomad"
 	server_http_check_name = "nomad-server-http-health-check"
@@ -163,6 +243,9 @@ tls {
 	http = true
 	rpc = true
 	verify_server_hostname = true
+	region_server_names {
+		europe = "server.eu.example.com"
+	}
 	ca_file = "foo"
 	cert_file = "bar"
 	key_file = "pipe"
@@ -187,10 +270,41 @@ autopilot {
 	disable_upgrade_migration = true
 	last_contact_threshold = "12705s"
 	max_trailing_logs = 17849
+	min_quorum = 3
 	enable_redundancy_zones = true
 	server_stabilization_time = "23057s"
 	enable_custom_upgrades = true
 }
+opa {
+	address = "http://127.0.0.1:8181"
+	timeout = "3s"
+	policy "privileged" {
+		path = "nomad/docker/privileged"
+		enforcement_level = "soft-mandatory"
+	}
+}
+snapshot_agent {
+	enabled = true
+	interval = "30m"
+	retain = 48
+	local_storage {
+		path = "/opt/nomad/snapshots"
+	}
+	s3_storage {
+		bucket = "nomad-backups"
+		key_prefix = "prod/"
+		region = "us-west-2"
+		server_side_encryption = true
+	}
+}
+limits {
+	http_max_conns = 500
+	http_max_conns_per_client = 50
+	http_rate_limit = 20.5
+	http_rate_burst = 40
+	http_token_rate_limit = 10
+	http_token_rate_burst = 15
+}
 plugin "docker" {
   args = ["foo", "bar"]
   config {
@@ -132,16 +132,140 @@ type Config struct {
 	// set arbitrary headers on API responses
 	HTTPAPIResponseHeaders map[string]string `mapstructure:"http_api_response_headers"`
 
+	// HTTPAPIGzipMinSize is the minimum size in bytes of the API responses
+	// compressed with gzip, for the clients accepting it.
+	HTTPAPIGzipMinSize int `mapstructure:"http_api_gzip_min_size"`
+
+	// HTTPAPICORS configures the CORS headers of the HTTP API, allowing the
+	// browser based tools of other origins to call it.
+	HTTPAPICORS *CORSConfig `mapstructure:"http_api_cors"`
+
+	// Audit configures the audit log of the HTTP API requests.
+	Audit *AuditConfig `mapstructure:"audit"`
+
 	// Sentinel holds sentinel related settings
 	Sentinel *config.SentinelConfig `mapstructure:"sentinel"`
 
+	// OPA configures the OPA policies the jobs submitted are evaluated
+	// against.
+	OPA *config.OPAConfig `mapstructure:"opa"`
+
 	// Autopilot contains the configuration for Autopilot behavior.
 	Autopilot *config.AutopilotConfig `mapstructure:"autopilot"`
 
+	// SnapshotAgent contains the configuration of the snapshots saved
+	// periodically by the leader.
+	SnapshotAgent *config.SnapshotAgentConfig `mapstructure:"snapshot_agent"`
+
+	// Limits contains the limits on the connections and requests of the HTTP
+	// API, protecting the agent from misbehaving clients.
+	Limits *Limits `mapstructure:"limits"`
+
 	// Plugins is the set of configured plugins
 	Plugins []*config.PluginConfig `hcl:"plugin,expand"`
 }
 
+// Limits contains the limits on the connections and requests of the HTTP API.
+// A zero value disables the limit.
+type Limits struct {
+	// HTTPMaxConns is the maximum number of concurrent connections to the
+	// HTTP API.
+	HTTPMaxConns int `mapstructure:"http_max_conns"`
+
+	// HTTPMaxConnsPerClient is the maximum number of concurrent connections
+	// to the HTTP API from a single client IP address.
+	HTTPMaxConnsPerClient int `mapstructure:"http_max_conns_per_client"`
+
+	// HTTPRateLimit is the maximum number of requests per second from a
+	// single client IP address, allowing bursts of HTTPRateBurst requests.
+	HTTPRateLimit float64 `mapstructure:"http_rate_limit"`
+	HTTPRateBurst int     `mapstructure:"http_rate_burst"`
+
+	// HTTPTokenRateLimit is the maximum number of requests per second made
+	// with a single ACL token, allowing bursts of HTTPTokenRateBurst
+	// requests.
+	HTTPTokenRateLimit float64 `mapstructure:"http_token_rate_limit"`
+	HTTPTokenRateBurst int     `mapstructure:"http_token_rate_burst"`
+}
+
+// CORSConfig configures the CORS headers of the HTTP API. CORS is disabled
+// unless allowed origins are set.
+type CORSConfig struct {
+	// AllowedOrigins are the origins allowed to call the HTTP API. They may
+	// contain a "*" wildcard, and "*" alone allows any origin.
+	AllowedOrigins []string `mapstructure:"allowed_origins"`
+
+	// AllowedMethods are the HTTP methods the origins may use.
+	AllowedMethods []string `mapstructure:"allowed_methods"`
+
+	// AllowedHeaders are the request headers the origins may set.
+	AllowedHeaders []string `mapstructure:"allowed_headers"`
+
+	// ExposedHeaders are the response headers the origins may read.
+	ExposedHeaders []string `mapstructure:"exposed_headers"`
+
+	// AllowCredentials allows the origins to send cookies and HTTP basic
+	// authentication.
+	AllowCredentials bool `mapstructure:"allow_credentials"`
+
+	// MaxAge is how long in seconds browsers may cache the responses of the
+	// preflight requests.
+	MaxAge int `mapstructure:"max_age"`
+}
+
+// AuditConfig configures the audit log of the HTTP API requests, which are
+// written as JSON records to the sinks unless excluded by a filter.
+type AuditConfig struct {
+	// Enabled enables the audit log.
+	Enabled *bool `mapstructure:"enabled"`
+
+	// Sinks are where the records are written.
+	Sinks []*AuditSink `mapstructure:"sink"`
+
+	// Filters exclude the requests they match from the audit log.
+	Filters []*AuditFilter `mapstructure:"filter"`
+}
+
+const (
+	// AuditSinkFile writes the records to a file.
+	AuditSinkFile = "file"
+
+	// AuditSinkSocket writes the records to a socket.
+	AuditSinkSocket = "socket"
+)
+
+// AuditSink is where the audit records are written.
+type AuditSink struct {
+	// Name is the name of the sink, from the label of its block.
+	Name string `mapstructure:"-"`
+
+	// Type is the type of the sink, either "file" or "socket".
+	Type string `mapstructure:"type"`
+
+	// Path is the path of the file the records are appended to.
+	Path string `mapstructure:"path"`
+
+	// Network is the network of the socket, such as "tcp", "udp" or
+	// "unix", and Address its address.
+	Network string `mapstructure:"network"`
+	Address string `mapstructure:"address"`
+}
+
+// AuditFilter excludes the matching requests from the audit log. A request
+// matches if both its endpoint and operation match, and an empty list
+// matches any value.
+type AuditFilter struct {
+	// Name is the name of the filter, from the label of its block.
+	Name string `mapstructure:"-"`
+
+	// Endpoints are the paths of the requests excluded. A trailing "*"
+	// matches any path with the prefix.
+	Endpoints []string `mapstructure:"endpoints"`
+
+	// Operations are the HTTP methods of the requests excluded.
+	Operations []string `mapstructure:"operations"`
+}
+
 // ClientConfig is configuration specific to the client mode
 type ClientConfig struct {
 	// Enabled controls if we are a client
@@ -153,12 +277,37 @@ type ClientConfig struct {
 	// AllocDir is the directory for storing allocation data
 	AllocDir string `mapstructure:"alloc_dir"`
 
+	// HostVolumesDir is the directory host volumes created at runtime are
+	// created in
+	HostVolumesDir string `mapstructure:"host_volumes_dir"`
+
+	// HostVolumePluginDir is the directory of the plugins provisioning host
+	// volumes created at runtime
+	HostVolumePluginDir string `mapstructure:"host_volume_plugin_dir"`
+
+	// CNIPath is the list of directories, separated by colons, searched for
+	// CNI plugins
+	CNIPath string `mapstructure:"cni_path"`
+
+	// CNIConfigDir is the directory of the CNI network configurations
+	CNIConfigDir string `mapstructure:"cni_config_dir"`
+
+	// BridgeNetworkName is the name of the bridge of bridge networks
+	BridgeNetworkName string `mapstructure:"bridge_network_name"`
+
+	// BridgeNetworkSubnet is the subnet of bridge networks
+	BridgeNetworkSubnet string `mapstructure:"bridge_network_subnet"`
+
 	// Servers is a list of known server addresses. These are as "host:port"
 	Servers []string `mapstructure:"servers"`
 
 	// NodeClass is used to group the node by class
 	NodeClass string `mapstructure:"node_class"`
 
+	// NodePool is the node pool the node belongs to. Only jobs targeting
+	// the node pool are placed on the node.
+	NodePool string `mapstructure:"node_pool"`
+
 	// Options is used for configuration of nomad internals,
 	// like fingerprinters and drivers. The format is:
 	//
@@ -185,6 +334,9 @@ type ClientConfig struct {
 	// MemoryMB is used to override any detected or default total memory.
 	MemoryMB int `mapstructure:"memory_total_mb"`
 
+	// ReservableCores is the set of cores that may be dedicated to tasks.
+	ReservableCores string `mapstructure:"reservable_cores"`
+
 	// MaxKillTimeout allows capping the user-specifiable KillTimeout.
 	MaxKillTimeout string `mapstructure:"max_kill_timeout"`
 
@@ -221,10 +373,39 @@ type ClientConfig struct {
 	// before garbage collection is triggered.
 	GCMaxAllocs int `mapstructure:"gc_max_allocs"`
 
+	// DiskIneligibleThreshold is the disk usage threshold given as a percent
+	// beyond which the Nomad client marks its node ineligible for scheduling
+	DiskIneligibleThreshold float64 `mapstructure:"disk_ineligible_threshold"`
+
 	// NoHostUUID disables using the host's UUID and will force generation of a
 	// random UUID.
 	NoHostUUID *bool `mapstructure:"no_host_uuid"`
 
+	// EnableTaskAPI exposes the task API on a unix socket in the secrets
+	// directory of every task.
+	EnableTaskAPI bool `mapstructure:"enable_task_api"`
+
+	// Fingerprinters are the external fingerprinters run periodically to
+	// contribute attributes and resources to the node.
+	Fingerprinters []*config.FingerprinterConfig `mapstructure:"fingerprinter"`
+
+	// HostVolumes are the host volumes exposed by the client to the task
+	// groups requiring them.
+	HostVolumes []*structs.ClientHostVolumeConfig `mapstructure:"host_volume"`
+
+	// HostNetworks are the named host networks the ports of jobs can be
+	// bound to.
+	HostNetworks []*structs.ClientHostNetworkConfig `mapstructure:"host_network"`
+
+	// DrainOnShutdown makes the client drain itself when the agent receives
+	// SIGTERM, waiting for its allocations to migrate before exiting.
+	DrainOnShutdown *config.DrainConfig `mapstructure:"drain_on_shutdown"`
+
+	// DrainOnTermination makes the client drain itself when its cloud
+	// provider issues a termination notice for its spot or preemptible
+	// instance.
+	DrainOnTermination *config.DrainConfig `mapstructure:"drain_on_termination"`
+
 	// ServerJoin contains information that is used to attempt to join servers
 	ServerJoin *ServerJoin `mapstructure:"server_join"`
 }
@@ -246,6 +427,11 @@ type ACLConfig struct {
 	// frequent resolution.
 	PolicyTTL time.Duration `mapstructure:"policy_ttl"`
 
+	// TokenMinExpirationTTL and TokenMaxExpirationTTL bound the expiration
+	// TTL tokens can be created with.
+	TokenMinExpirationTTL time.Duration `mapstructure:"token_min_expiration_ttl"`
+	TokenMaxExpirationTTL time.Duration `mapstructure:"token_max_expiration_ttl"`
+
 	// ReplicationToken is used by servers to replicate tokens and policies
 	// from the authoritative region. This must be a valid management token
 	// within the authoritative region.
@@ -318,6 +504,53 @@ type ServerConfig struct {
 	// to meet the target rate.
 	MaxHeartbeatsPerSecond float64 `mapstructure:"max_heartbeats_per_second"`
 
+	// NodeEventRetention is the number of most recent events retained for
+	// each node.
+	NodeEventRetention int `mapstructure:"node_event_retention"`
+
+	// PlanBatchSize is the maximum number of compatible plans the leader
+	// verifies in parallel and commits back to back. A value of one disables
+	// batching.
+	PlanBatchSize int `mapstructure:"plan_batch_size"`
+
+	// EvalUpdateBatchSize is the maximum number of evaluation updates the
+	// leader coalesces into a single Raft log entry. A value of one disables
+	// batching.
+	EvalUpdateBatchSize int `mapstructure:"eval_update_batch_size"`
+
+	// RPCMaxIdleStreams is the maximum number of idle streams kept open on
+	// the connection to each server, including the servers of other regions.
+	RPCMaxIdleStreams int `mapstructure:"rpc_max_idle_streams"`
+
+	// RPCConnIdleTimeout is how long an idle connection to another server is
+	// kept open.
+	RPCConnIdleTimeout time.Duration `mapstructure:"rpc_conn_idle_timeout"`
+
+	// EvalFairShare enables fair-share dequeuing of evaluations across
+	// namespaces so that a flood of evaluations in one namespace can not
+	// starve the placements of other namespaces.
+	EvalFairShare bool `mapstructure:"eval_fair_share"`
+
+	// EvalFairShareWeights is the fair-share weight of each namespace.
+	// Namespaces without a weight have a weight of one.
+	EvalFairShareWeights map[string]int `mapstructure:"eval_fair_share_weights"`
+
+	// EvalNackTimeout is how long a scheduler may work on an evaluation
+	// before it is considered failed and Nacked.
+	EvalNackTimeout time.Duration `mapstructure:"eval_nack_timeout"`
+
+	// EvalDeliveryLimit is the number of times an evaluation is delivered
+	// to the schedulers before it is failed.
+	EvalDeliveryLimit int `mapstructure:"eval_delivery_limit"`
+
+	// EvalNackInitialReenqueueDelay is the delay before re-enqueuing an
+	// evaluation Nacked for the first time.
+	EvalNackInitialReenqueueDelay time.Duration `mapstructure:"eval_nack_initial_reenqueue_delay"`
+
+	// EvalNackSubsequentReenqueueDelay is the compounding delay before
+	// re-enqueuing an evaluation Nacked more than once.
+	EvalNackSubsequentReenqueueDelay time.Duration `mapstructure:"eval_nack_subsequent_reenqueue_delay"`
+
 	// StartJoin is a list of addresses to attempt to join when the
 	// agent starts. If Serf is unable to communicate with any of these
 	// addresses, then the agent will error and exit.
@@ -346,11 +579,11 @@ type ServerConfig struct {
 	// true, we ignore the leave, and rejoin the cluster on start.
 	RejoinAfterLeave bool `mapstructure:"rejoin_after_leave"`
 
-	// (Enterprise-only) NonVotingServer is whether this server will act as a
+	// NonVotingServer is whether this server will act as a
 	// non-voting member of the cluster to help provide read scalability.
 	NonVotingServer bool `mapstructure:"non_voting_server"`
 
-	// (Enterprise-only) RedundancyZone is the redundancy zone to use for this server.
+	// RedundancyZone is the redundancy zone to use for this server.
 	RedundancyZone string `mapstructure:"redundancy_zone"`
 
 	// (Enterprise-only) UpgradeVersion is the custom upgrade version to use when
@@ -360,6 +593,14 @@ type ServerConfig struct {
 	// Encryption key to use for the Serf communication
 	EncryptKey string `mapstructure:"encrypt" json:"-"`
 
+	// KeyringEncryptionKey is the base64 encoded key the root keys variables
+	// are encrypted with are wrapped with in the state and the snapshots.
+	KeyringEncryptionKey string `mapstructure:"keyring_encryption_key" json:"-"`
+
+	// AdmissionWebhooks are the external webhooks called to mutate or
+	// validate the jobs submitted, in order.
+	AdmissionWebhooks []*config.AdmissionWebhookConfig `mapstructure:"admission_webhook"`
+
 	// ServerJoin contains information that is used to attempt to join servers
 	ServerJoin *ServerJoin `mapstructure:"server_join"`
 }
@@ -419,6 +660,11 @@ func (s *ServerConfig) EncryptBytes() ([]byte, error) {
 	return base64.StdEncoding.DecodeString(s.EncryptKey)
 }
 
+// KeyringEncryptionKeyBytes returns the keyring encryption key configured.
+func (s *ServerConfig) KeyringEncryptionKeyBytes() ([]byte, error) {
+	return base64.StdEncoding.DecodeString(s.KeyringEncryptionKey)
+}
+
 // Telemetry is the telemetry configuration for the server
 type Telemetry struct {
 	StatsiteAddr             string        `mapstructure:"statsite_address"`
@@ -575,6 +821,11 @@ type Resources struct {
 	DiskMB        int    `mapstructure:"disk"`
 	IOPS          int    `mapstructure:"iops"`
 	ReservedPorts string `mapstructure:"reserved_ports"`
+
+	// CgroupSlice is the cgroup or systemd slice, relative to the cgroup
+	// root, whose CPU quota and memory limit are reserved instead of CPU and
+	// MemoryMB. Only supported on Linux.
+	CgroupSlice string `mapstructure:"cgroup_slice"`
 }
 
 // CanParseReserved returns if the reserved ports specification is parsable.
@@ -673,6 +924,8 @@ func DefaultConfig() *Config {
 		Sentinel:           &config.SentinelConfig{},
 		Version:            version.GetVersion(),
 		Autopilot:          config.DefaultAutopilotConfig(),
+		SnapshotAgent:      config.DefaultSnapshotAgentConfig(),
+		Limits:             &Limits{},
 		DisableUpdateCheck: helper.BoolToPtr(false),
 	}
 }
@@ -842,6 +1095,28 @@ func (c *Config) Merge(b *Config) *Config {
 		result.Autopilot = result.Autopilot.Merge(b.Autopilot)
 	}
 
+	// Apply the OPA config
+	if result.OPA == nil && b.OPA != nil {
+		result.OPA = b.OPA.Copy()
+	} else if b.OPA != nil {
+		result.OPA = result.OPA.Merge(b.OPA)
+	}
+
+	// Apply the snapshot agent config
+	if result.SnapshotAgent == nil && b.SnapshotAgent != nil {
+		result.SnapshotAgent = b.SnapshotAgent.Copy()
+	} else if b.SnapshotAgent != nil {
+		result.SnapshotAgent = result.SnapshotAgent.Merge(b.SnapshotAgent)
+	}
+
+	// Apply the limits config
+	if result.Limits == nil && b.Limits != nil {
+		limits := *b.Limits
+		result.Limits = &limits
+	} else if b.Limits != nil {
+		result.Limits = result.Limits.Merge(b.Limits)
+	}
+
 	if len(result.Plugins) == 0 && len(b.Plugins) != 0 {
 		copy := make([]*config.PluginConfig, len(b.Plugins))
 		for i, v := range b.Plugins {
@@ -862,6 +1137,25 @@ func (c *Config) Merge(b *Config) *Config {
 	for k, v := range b.HTTPAPIResponseHeaders {
 		result.HTTPAPIResponseHeaders[k] = v
 	}
+	if b.HTTPAPIGzipMinSize != 0 {
+		result.HTTPAPIGzipMinSize = b.HTTPAPIGzipMinSize
+	}
+
+	// Apply the audit config
+	if result.Audit == nil && b.Audit != nil {
+		audit := *b.Audit
+		result.Audit = &audit
+	} else if b.Audit != nil {
+		result.Audit = result.Audit.Merge(b.Audit)
+	}
+
+	// Apply the CORS config
+	if result.HTTPAPICORS == nil && b.HTTPAPICORS != nil {
+		cors := *b.HTTPAPICORS
+		result.HTTPAPICORS = &cors
+	} else if b.HTTPAPICORS != nil {
+		result.HTTPAPICORS = result.HTTPAPICORS.Merge(b.HTTPAPICORS)
+	}
 
 	return &result
 }
@@ -1031,6 +1325,101 @@ func isTooManyColons(err error) bool {
 	return err != nil && strings.Contains(err.Error(), tooManyColons)
 }
 
+// Merge is used to merge two limits configs together. The settings from the
+// input always take precedence.
+func (a *Limits) Merge(b *Limits) *Limits {
+	result := *a
+
+	if b.HTTPMaxConns != 0 {
+		result.HTTPMaxConns = b.HTTPMaxConns
+	}
+	if b.HTTPMaxConnsPerClient != 0 {
+		result.HTTPMaxConnsPerClient = b.HTTPMaxConnsPerClient
+	}
+	if b.HTTPRateLimit != 0 {
+		result.HTTPRateLimit = b.HTTPRateLimit
+	}
+	if b.HTTPRateBurst != 0 {
+		result.HTTPRateBurst = b.HTTPRateBurst
+	}
+	if b.HTTPTokenRateLimit != 0 {
+		result.HTTPTokenRateLimit = b.HTTPTokenRateLimit
+	}
+	if b.HTTPTokenRateBurst != 0 {
+		result.HTTPTokenRateBurst = b.HTTPTokenRateBurst
+	}
+	return &result
+}
+
+// Merge is used to merge two audit configs together. The settings from the
+// input always take precedence, and the sinks and filters are merged by name.
+func (a *AuditConfig) Merge(b *AuditConfig) *AuditConfig {
+	result := *a
+
+	if b.Enabled != nil {
+		result.Enabled = b.Enabled
+	}
+
+	sinks := make([]*AuditSink, 0, len(a.Sinks)+len(b.Sinks))
+	for _, s := range a.Sinks {
+		sinks = append(sinks, s)
+	}
+SINKS:
+	for _, s := range b.Sinks {
+		for i, existing := range sinks {
+			if existing.Name == s.Name {
+				sinks[i] = s
+				continue SINKS
+			}
+		}
+		sinks = append(sinks, s)
+	}
+	result.Sinks = sinks
+
+	filters := make([]*AuditFilter, 0, len(a.Filters)+len(b.Filters))
+	for _, f := range a.Filters {
+		filters = append(filters, f)
+	}
+FILTERS:
+	for _, f := range b.Filters {
+		for i, existing := range filters {
+			if existing.Name == f.Name {
+				filters[i] = f
+				continue FILTERS
+			}
+		}
+		filters = append(filters, f)
+	}
+	result.Filters = filters
+	return &result
+}
+
+// Merge is used to merge two CORS configs together. The settings from the
+// input always take precedence.
+func (a *CORSConfig) Merge(b *CORSConfig) *CORSConfig {
+	result := *a
+
+	if len(b.AllowedOrigins) != 0 {
+		result.AllowedOrigins = b.AllowedOrigins
+	}
+	if len(b.AllowedMethods) != 0 {
+		result.AllowedMethods = b.AllowedMethods
+	}
+	if len(b.AllowedHeaders) != 0 {
+		result.AllowedHeaders = b.AllowedHeaders
+	}
+	if len(b.ExposedHeaders) != 0 {
+		result.ExposedHeaders = b.ExposedHeaders
+	}
+	if b.AllowCredentials {
+		result.AllowCredentials = true
+	}
+	if b.MaxAge != 0 {
+		result.MaxAge = b.MaxAge
+	}
+	return &result
+}
+
 // Merge is used to merge two ACL configs together. The settings from the input always take precedence.
 func (a *ACLConfig) Merge(b *ACLConfig) *ACLConfig {
 	result := *a
@@ -1044,6 +1433,12 @@ func (a *ACLConfig) Merge(b *ACLConfig) *ACLConfig {
 	if b.PolicyTTL != 0 {
 		result.PolicyTTL = b.PolicyTTL
 	}
+	if b.TokenMinExpirationTTL != 0 {
+		result.TokenMinExpirationTTL = b.TokenMinExpirationTTL
+	}
+	if b.TokenMaxExpirationTTL != 0 {
+		result.TokenMaxExpirationTTL = b.TokenMaxExpirationTTL
+	}
 	if b.ReplicationToken != "" {
 		result.ReplicationToken = b.ReplicationToken
 	}
@@ -1096,6 +1491,45 @@ func (a *ServerConfig) Merge(b *ServerConfig) *ServerConfig {
 	if b.MaxHeartbeatsPerSecond != 0.0 {
 		result.MaxHeartbeatsPerSecond = b.MaxHeartbeatsPerSecond
 	}
+	if b.NodeEventRetention != 0 {
+		result.NodeEventRetention = b.NodeEventRetention
+	}
+	if b.PlanBatchSize != 0 {
+		result.PlanBatchSize = b.PlanBatchSize
+	}
+	if b.EvalUpdateBatchSize != 0 {
+		result.EvalUpdateBatchSize = b.EvalUpdateBatchSize
+	}
+	if b.RPCMaxIdleStreams != 0 {
+		result.RPCMaxIdleStreams = b.RPCMaxIdleStreams
+	}
+	if b.RPCConnIdleTimeout != 0 {
+		result.RPCConnIdleTimeout = b.RPCConnIdleTimeout
+	}
+	if b.EvalFairShare {
+		result.EvalFairShare = true
+	}
+	if len(b.EvalFairShareWeights) != 0 {
+		result.EvalFairShareWeights = make(map[string]int, len(a.EvalFairShareWeights)+len(b.EvalFairShareWeights))
+		for ns, weight := range a.EvalFairShareWeights {
+			result.EvalFairShareWeights[ns] = weight
+		}
+		for ns, weight := range b.EvalFairShareWeights {
+			result.EvalFairShareWeights[ns] = weight
+		}
+	}
+	if b.EvalNackTimeout != 0 {
+		result.EvalNackTimeout = b.EvalNackTimeout
+	}
+	if b.EvalDeliveryLimit != 0 {
+		result.EvalDeliveryLimit = b.EvalDeliveryLimit
+	}
+	if b.EvalNackInitialReenqueueDelay != 0 {
+		result.EvalNackInitialReenqueueDelay = b.EvalNackInitialReenqueueDelay
+	}
+	if b.EvalNackSubsequentReenqueueDelay != 0 {
+		result.EvalNackSubsequentReenqueueDelay = b.EvalNackSubsequentReenqueueDelay
+	}
 	if b.RetryMaxAttempts != 0 {
 		result.RetryMaxAttempts = b.RetryMaxAttempts
 	}
@@ -1117,6 +1551,12 @@ func (a *ServerConfig) Merge(b *ServerConfig) *ServerConfig {
 	if b.EncryptKey != "" {
 		result.EncryptKey = b.EncryptKey
 	}
+	if b.KeyringEncryptionKey != "" {
+		result.KeyringEncryptionKey = b.KeyringEncryptionKey
+	}
+	if len(b.AdmissionWebhooks) != 0 {
+		result.AdmissionWebhooks = config.AdmissionWebhookSetMerge(result.AdmissionWebhooks, b.AdmissionWebhooks)
+	}
 	if b.ServerJoin != nil {
 		result.ServerJoin = result.ServerJoin.Merge(b.ServerJoin)
 	}
@@ -1150,9 +1590,30 @@ func (a *ClientConfig) Merge(b *ClientConfig) *ClientConfig {
 	if b.AllocDir != "" {
 		result.AllocDir = b.AllocDir
 	}
+	if b.HostVolumesDir != "" {
+		result.HostVolumesDir = b.HostVolumesDir
+	}
+	if b.HostVolumePluginDir != "" {
+		result.HostVolumePluginDir = b.HostVolumePluginDir
+	}
+	if b.CNIPath != "" {
+		result.CNIPath = b.CNIPath
+	}
+	if b.CNIConfigDir != "" {
+		result.CNIConfigDir = b.CNIConfigDir
+	}
+	if b.BridgeNetworkName != "" {
+		result.BridgeNetworkName = b.BridgeNetworkName
+	}
+	if b.BridgeNetworkSubnet != "" {
+		result.BridgeNetworkSubnet = b.BridgeNetworkSubnet
+	}
 	if b.NodeClass != "" {
 		result.NodeClass = b.NodeClass
 	}
+	if b.NodePool != "" {
+		result.NodePool = b.NodePool
+	}
 	if b.NetworkInterface != "" {
 		result.NetworkInterface = b.NetworkInterface
 	}
@@ -1165,6 +1626,9 @@ func (a *ClientConfig) Merge(b *ClientConfig) *ClientConfig {
 	if b.MemoryMB != 0 {
 		result.MemoryMB = b.MemoryMB
 	}
+	if b.ReservableCores != "" {
+		result.ReservableCores = b.ReservableCores
+	}
 	if b.MaxKillTimeout != "" {
 		result.MaxKillTimeout = b.MaxKillTimeout
 	}
@@ -1195,10 +1659,16 @@ func (a *ClientConfig) Merge(b *ClientConfig) *ClientConfig {
 	if b.GCMaxAllocs != 0 {
 		result.GCMaxAllocs = b.GCMaxAllocs
 	}
+	if b.DiskIneligibleThreshold != 0 {
+		result.DiskIneligibleThreshold = b.DiskIneligibleThreshold
+	}
 	// NoHostUUID defaults to true, merge if false
 	if b.NoHostUUID != nil {
 		result.NoHostUUID = b.NoHostUUID
 	}
+	if b.EnableTaskAPI {
+		result.EnableTaskAPI = true
+	}
 
 	// Add the servers
 	result.Servers = append(result.Servers, b.Servers...)
@@ -1227,6 +1697,26 @@ func (a *ClientConfig) Merge(b *ClientConfig) *ClientConfig {
 		result.ChrootEnv[k] = v
 	}
 
+	if len(b.Fingerprinters) != 0 {
+		result.Fingerprinters = config.FingerprinterSetMerge(result.Fingerprinters, b.Fingerprinters)
+	}
+
+	if len(b.HostVolumes) != 0 {
+		result.HostVolumes = structs.HostVolumeSliceMerge(result.HostVolumes, b.HostVolumes)
+	}
+
+	if len(b.HostNetworks) != 0 {
+		result.HostNetworks = structs.HostNetworkSliceMerge(result.HostNetworks, b.HostNetworks)
+	}
+
+	if b.DrainOnShutdown != nil {
+		result.DrainOnShutdown = result.DrainOnShutdown.Merge(b.DrainOnShutdown)
+	}
+
+	if b.DrainOnTermination != nil {
+		result.DrainOnTermination = result.DrainOnTermination.Merge(b.DrainOnTermination)
+	}
+
 	if b.ServerJoin != nil {
 		result.ServerJoin = result.ServerJoin.Merge(b.ServerJoin)
 	}
@@ -1400,6 +1890,9 @@ func (r *Resources) Merge(b *Resources) *Resources {
 	if b.ReservedPorts != "" {
 		result.ReservedPorts = b.ReservedPorts
 	}
+	if b.CgroupSlice != "" {
+		result.CgroupSlice = b.CgroupSlice
+	}
 	return &result
 }
 
@@ -14,6 +14,7 @@ import (
 	"github.com/hashicorp/hcl/hcl/ast"
 	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/helper/tlsutil"
+	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/nomad/structs/config"
 	"github.com/mitchellh/mapstructure"
 )
@@ -99,9 +100,15 @@ func parseConfig(result *Config, list *ast.ObjectList) error {
 		"vault",
 		"tls",
 		"http_api_response_headers",
+		"http_api_gzip_min_size",
+		"http_api_cors",
+		"audit",
 		"acl",
 		"sentinel",
+		"opa",
 		"autopilot",
+		"snapshot_agent",
+		"limits",
 		"plugin",
 	}
 	if err := helper.CheckHCLKeys(list, valid); err != nil {
@@ -124,9 +131,14 @@ func parseConfig(result *Config, list *ast.ObjectList) error {
 	delete(m, "vault")
 	delete(m, "tls")
 	delete(m, "http_api_response_headers")
+	delete(m, "http_api_cors")
+	delete(m, "audit")
 	delete(m, "acl")
 	delete(m, "sentinel")
+	delete(m, "opa")
 	delete(m, "autopilot")
+	delete(m, "snapshot_agent")
+	delete(m, "limits")
 	delete(m, "plugin")
 
 	// Decode the rest
@@ -211,6 +223,13 @@ func parseConfig(result *Config, list *ast.ObjectList) error {
 		}
 	}
 
+	// Parse OPA config
+	if o := list.Filter("opa"); len(o.Items) > 0 {
+		if err := parseOPA(&result.OPA, o); err != nil {
+			return multierror.Prefix(err, "opa->")
+		}
+	}
+
 	// Parse Autopilot config
 	if o := list.Filter("autopilot"); len(o.Items) > 0 {
 		if err := parseAutopilot(&result.Autopilot, o); err != nil {
@@ -218,6 +237,34 @@ func parseConfig(result *Config, list *ast.ObjectList) error {
 		}
 	}
 
+	// Parse snapshot agent config
+	if o := list.Filter("snapshot_agent"); len(o.Items) > 0 {
+		if err := parseSnapshotAgent(&result.SnapshotAgent, o); err != nil {
+			return multierror.Prefix(err, "snapshot_agent->")
+		}
+	}
+
+	// Parse limits config
+	if o := list.Filter("limits"); len(o.Items) > 0 {
+		if err := parseLimits(&result.Limits, o); err != nil {
+			return multierror.Prefix(err, "limits ->")
+		}
+	}
+
+	// Parse CORS config
+	if o := list.Filter("http_api_cors"); len(o.Items) > 0 {
+		if err := parseCORS(&result.HTTPAPICORS, o); err != nil {
+			return multierror.Prefix(err, "http_api_cors ->")
+		}
+	}
+
+	// Parse audit config
+	if o := list.Filter("audit"); len(o.Items) > 0 {
+		if err := parseAudit(&result.Audit, o); err != nil {
+			return multierror.Prefix(err, "audit ->")
+		}
+	}
+
 	// Parse Plugin configs
 	if o := list.Filter("plugin"); len(o.Items) > 0 {
 		if err := parsePlugins(&result.Plugins, o); err != nil {
@@ -360,8 +407,15 @@ func parseClient(result **ClientConfig, list *ast.ObjectList) error {
 		"enabled",
 		"state_dir",
 		"alloc_dir",
+		"host_volumes_dir",
+		"host_volume_plugin_dir",
+		"cni_path",
+		"cni_config_dir",
+		"bridge_network_name",
+		"bridge_network_subnet",
 		"servers",
 		"node_class",
+		"node_pool",
 		"options",
 		"meta",
 		"chroot_env",
@@ -369,6 +423,7 @@ func parseClient(result **ClientConfig, list *ast.ObjectList) error {
 		"network_speed",
 		"memory_total_mb",
 		"cpu_total_compute",
+		"reservable_cores",
 		"max_kill_timeout",
 		"client_max_port",
 		"client_min_port",
@@ -379,7 +434,14 @@ func parseClient(result **ClientConfig, list *ast.ObjectList) error {
 		"gc_inode_usage_threshold",
 		"gc_parallel_destroys",
 		"gc_max_allocs",
+		"disk_ineligible_threshold",
 		"no_host_uuid",
+		"enable_task_api",
+		"fingerprinter",
+		"host_volume",
+		"host_network",
+		"drain_on_shutdown",
+		"drain_on_termination",
 		"server_join",
 	}
 	if err := helper.CheckHCLKeys(listVal, valid); err != nil {
@@ -396,6 +458,11 @@ func parseClient(result **ClientConfig, list *ast.ObjectList) error {
 	delete(m, "chroot_env")
 	delete(m, "reserved")
 	delete(m, "stats")
+	delete(m, "fingerprinter")
+	delete(m, "host_volume")
+	delete(m, "host_network")
+	delete(m, "drain_on_shutdown")
+	delete(m, "drain_on_termination")
 	delete(m, "server_join")
 
 	var config ClientConfig
@@ -460,6 +527,41 @@ func parseClient(result **ClientConfig, list *ast.ObjectList) error {
 		}
 	}
 
+	// Parse the external fingerprinters
+	if o := listVal.Filter("fingerprinter"); len(o.Items) > 0 {
+		if err := parseFingerprinters(&config.Fingerprinters, o); err != nil {
+			return multierror.Prefix(err, "fingerprinter->")
+		}
+	}
+
+	// Parse the host volumes
+	if o := listVal.Filter("host_volume"); len(o.Items) > 0 {
+		if err := parseHostVolumes(&config.HostVolumes, o); err != nil {
+			return multierror.Prefix(err, "host_volume->")
+		}
+	}
+
+	// Parse the host networks
+	if o := listVal.Filter("host_network"); len(o.Items) > 0 {
+		if err := parseHostNetworks(&config.HostNetworks, o); err != nil {
+			return multierror.Prefix(err, "host_network->")
+		}
+	}
+
+	// Parse the drain on shutdown config
+	if o := listVal.Filter("drain_on_shutdown"); len(o.Items) > 0 {
+		if err := parseDrain("drain_on_shutdown", &config.DrainOnShutdown, o); err != nil {
+			return multierror.Prefix(err, "drain_on_shutdown ->")
+		}
+	}
+
+	// Parse the drain on termination config
+	if o := listVal.Filter("drain_on_termination"); len(o.Items) > 0 {
+		if err := parseDrain("drain_on_termination", &config.DrainOnTermination, o); err != nil {
+			return multierror.Prefix(err, "drain_on_termination ->")
+		}
+	}
+
 	// Parse ServerJoin config
 	if o := listVal.Filter("server_join"); len(o.Items) > 0 {
 		if err := parseServerJoin(&config.ServerJoin, o); err != nil {
@@ -471,6 +573,130 @@ func parseClient(result **ClientConfig, list *ast.ObjectList) error {
 	return nil
 }
 
+func parseFingerprinters(result *[]*config.FingerprinterConfig, list *ast.ObjectList) error {
+	fingerprinters := make([]*config.FingerprinterConfig, 0, len(list.Items))
+
+	// Check for invalid keys
+	valid := []string{
+		"command",
+		"args",
+		"interval",
+		"timeout",
+	}
+
+	for i, item := range list.Items {
+		// Ensure there is a name
+		if len(item.Keys) != 1 {
+			return fmt.Errorf("fingerprinter %d doesn't include a name key", i+1)
+		}
+		name := item.Keys[0].Token.Value().(string)
+
+		if err := helper.CheckHCLKeys(item.Val, valid); err != nil {
+			return multierror.Prefix(err, fmt.Sprintf("%s:", name))
+		}
+
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, item.Val); err != nil {
+			return err
+		}
+
+		fingerprinter := config.FingerprinterConfig{Name: name}
+		dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+			DecodeHook:       mapstructure.StringToTimeDurationHookFunc(),
+			WeaklyTypedInput: true,
+			Result:           &fingerprinter,
+		})
+		if err != nil {
+			return err
+		}
+		if err := dec.Decode(m); err != nil {
+			return fmt.Errorf("error decoding fingerprinter %q: %v", name, err)
+		}
+
+		fingerprinters = append(fingerprinters, &fingerprinter)
+	}
+
+	*result = fingerprinters
+	return nil
+}
+
+func parseHostVolumes(result *[]*structs.ClientHostVolumeConfig, list *ast.ObjectList) error {
+	volumes := make([]*structs.ClientHostVolumeConfig, 0, len(list.Items))
+
+	// Check for invalid keys
+	valid := []string{
+		"path",
+		"read_only",
+		"mountpoint",
+	}
+
+	for i, item := range list.Items {
+		// Ensure there is a name
+		if len(item.Keys) != 1 {
+			return fmt.Errorf("host volume %d doesn't include a name key", i+1)
+		}
+		name := item.Keys[0].Token.Value().(string)
+
+		if err := helper.CheckHCLKeys(item.Val, valid); err != nil {
+			return multierror.Prefix(err, fmt.Sprintf("%s:", name))
+		}
+
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, item.Val); err != nil {
+			return err
+		}
+
+		// Host volumes are expected to be mounts unless configured otherwise
+		volume := structs.ClientHostVolumeConfig{Name: name, Mountpoint: true}
+		if err := mapstructure.WeakDecode(m, &volume); err != nil {
+			return fmt.Errorf("error decoding host volume %q: %v", name, err)
+		}
+
+		volumes = append(volumes, &volume)
+	}
+
+	*result = volumes
+	return nil
+}
+
+func parseHostNetworks(result *[]*structs.ClientHostNetworkConfig, list *ast.ObjectList) error {
+	networks := make([]*structs.ClientHostNetworkConfig, 0, len(list.Items))
+
+	// Check for invalid keys
+	valid := []string{
+		"cidr",
+		"interface",
+		"reserved_ports",
+	}
+
+	for i, item := range list.Items {
+		// Ensure there is a name
+		if len(item.Keys) != 1 {
+			return fmt.Errorf("host network %d doesn't include a name key", i+1)
+		}
+		name := item.Keys[0].Token.Value().(string)
+
+		if err := helper.CheckHCLKeys(item.Val, valid); err != nil {
+			return multierror.Prefix(err, fmt.Sprintf("%s:", name))
+		}
+
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, item.Val); err != nil {
+			return err
+		}
+
+		network := structs.ClientHostNetworkConfig{Name: name}
+		if err := mapstructure.WeakDecode(m, &network); err != nil {
+			return fmt.Errorf("error decoding host network %q: %v", name, err)
+		}
+
+		networks = append(networks, &network)
+	}
+
+	*result = networks
+	return nil
+}
+
 func parseReserved(result **Resources, list *ast.ObjectList) error {
 	list = list.Elem()
 	if len(list.Items) > 1 {
@@ -495,6 +721,7 @@ func parseReserved(result **Resources, list *ast.ObjectList) error {
 		"disk",
 		"iops",
 		"reserved_ports",
+		"cgroup_slice",
 	}
 	if err := helper.CheckHCLKeys(listVal, valid); err != nil {
 		return err
@@ -550,14 +777,27 @@ func parseServer(result **ServerConfig, list *ast.ObjectList) error {
 		"heartbeat_grace",
 		"min_heartbeat_ttl",
 		"max_heartbeats_per_second",
+		"node_event_retention",
+		"plan_batch_size",
+		"eval_update_batch_size",
+		"rpc_max_idle_streams",
+		"rpc_conn_idle_timeout",
+		"eval_fair_share",
+		"eval_fair_share_weights",
+		"eval_nack_timeout",
+		"eval_delivery_limit",
+		"eval_nack_initial_reenqueue_delay",
+		"eval_nack_subsequent_reenqueue_delay",
 		"rejoin_after_leave",
 		"encrypt",
+		"keyring_encryption_key",
 		"authoritative_region",
 		"non_voting_server",
 		"redundancy_zone",
 		"upgrade_version",
 
 		"server_join",
+		"admission_webhook",
 
 		// For backwards compatibility
 		"start_join",
@@ -575,6 +815,8 @@ func parseServer(result **ServerConfig, list *ast.ObjectList) error {
 	}
 
 	delete(m, "server_join")
+	delete(m, "eval_fair_share_weights")
+	delete(m, "admission_webhook")
 
 	var config ServerConfig
 	dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
@@ -595,6 +837,20 @@ func parseServer(result **ServerConfig, list *ast.ObjectList) error {
 		}
 	}
 
+	// Parse out the fair-share weights. These are in HCL as a list so we need
+	// to iterate over them and merge them.
+	if weightsO := listVal.Filter("eval_fair_share_weights"); len(weightsO.Items) > 0 {
+		for _, o := range weightsO.Elem().Items {
+			var m map[string]interface{}
+			if err := hcl.DecodeObject(&m, o.Val); err != nil {
+				return err
+			}
+			if err := mapstructure.WeakDecode(m, &config.EvalFairShareWeights); err != nil {
+				return err
+			}
+		}
+	}
+
 	// Parse ServerJoin config
 	if o := listVal.Filter("server_join"); len(o.Items) > 0 {
 		if err := parseServerJoin(&config.ServerJoin, o); err != nil {
@@ -602,10 +858,104 @@ func parseServer(result **ServerConfig, list *ast.ObjectList) error {
 		}
 	}
 
+	// Parse the admission webhooks
+	if o := listVal.Filter("admission_webhook"); len(o.Items) > 0 {
+		if err := parseAdmissionWebhooks(&config.AdmissionWebhooks, o); err != nil {
+			return multierror.Prefix(err, "admission_webhook->")
+		}
+	}
+
 	*result = &config
 	return nil
 }
 
+func parseAdmissionWebhooks(result *[]*config.AdmissionWebhookConfig, list *ast.ObjectList) error {
+	webhooks := make([]*config.AdmissionWebhookConfig, 0, len(list.Items))
+
+	// Check for invalid keys
+	valid := []string{
+		"url",
+		"type",
+		"timeout",
+		"failure_policy",
+	}
+
+	for i, item := range list.Items {
+		// Ensure there is a name
+		if len(item.Keys) != 1 {
+			return fmt.Errorf("admission webhook %d doesn't include a name key", i+1)
+		}
+		name := item.Keys[0].Token.Value().(string)
+
+		if err := helper.CheckHCLKeys(item.Val, valid); err != nil {
+			return multierror.Prefix(err, fmt.Sprintf("%s:", name))
+		}
+
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, item.Val); err != nil {
+			return err
+		}
+
+		webhook := config.AdmissionWebhookConfig{Name: name}
+		dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+			DecodeHook:       mapstructure.StringToTimeDurationHookFunc(),
+			WeaklyTypedInput: true,
+			Result:           &webhook,
+		})
+		if err != nil {
+			return err
+		}
+		if err := dec.Decode(m); err != nil {
+			return fmt.Errorf("error decoding admission webhook %q: %v", name, err)
+		}
+
+		webhooks = append(webhooks, &webhook)
+	}
+
+	*result = webhooks
+	return nil
+}
+
+func parseDrain(name string, result **config.DrainConfig, list *ast.ObjectList) error {
+	list = list.Elem()
+	if len(list.Items) > 1 {
+		return fmt.Errorf("only one '%s' block allowed", name)
+	}
+
+	// Get our object
+	listVal := list.Items[0].Val
+
+	// Check for invalid keys
+	valid := []string{
+		"deadline",
+		"ignore_system_jobs",
+	}
+	if err := helper.CheckHCLKeys(listVal, valid); err != nil {
+		return err
+	}
+
+	var m map[string]interface{}
+	if err := hcl.DecodeObject(&m, listVal); err != nil {
+		return err
+	}
+
+	var drain config.DrainConfig
+	dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook:       mapstructure.StringToTimeDurationHookFunc(),
+		WeaklyTypedInput: true,
+		Result:           &drain,
+	})
+	if err != nil {
+		return err
+	}
+	if err := dec.Decode(m); err != nil {
+		return err
+	}
+
+	*result = &drain
+	return nil
+}
+
 func parseServerJoin(result **ServerJoin, list *ast.ObjectList) error {
 	list = list.Elem()
 	if len(list.Items) > 1 {
@@ -670,6 +1020,8 @@ func parseACL(result **ACLConfig, list *ast.ObjectList) error {
 		"enabled",
 		"token_ttl",
 		"policy_ttl",
+		"token_min_expiration_ttl",
+		"token_max_expiration_ttl",
 		"replication_token",
 	}
 	if err := helper.CheckHCLKeys(listVal, valid); err != nil {
@@ -833,6 +1185,7 @@ func parseTLSConfig(result **config.TLSConfig, list *ast.ObjectList) error {
 		"http",
 		"rpc",
 		"verify_server_hostname",
+		"region_server_names",
 		"rpc_upgrade_mode",
 		"ca_file",
 		"cert_file",
@@ -851,12 +1204,27 @@ func parseTLSConfig(result **config.TLSConfig, list *ast.ObjectList) error {
 	if err := hcl.DecodeObject(&m, listVal); err != nil {
 		return err
 	}
+	delete(m, "region_server_names")
 
 	var tlsConfig config.TLSConfig
 	if err := mapstructure.WeakDecode(m, &tlsConfig); err != nil {
 		return err
 	}
 
+	// Parse out the region server names. These are in HCL as a list so we
+	// need to iterate over them and merge them.
+	if ot, ok := listVal.(*ast.ObjectType); ok {
+		for _, o := range ot.List.Filter("region_server_names").Elem().Items {
+			var m map[string]interface{}
+			if err := hcl.DecodeObject(&m, o.Val); err != nil {
+				return err
+			}
+			if err := mapstructure.WeakDecode(m, &tlsConfig.RegionServerNames); err != nil {
+				return err
+			}
+		}
+	}
+
 	if _, err := tlsutil.ParseCiphers(&tlsConfig); err != nil {
 		return err
 	}
@@ -954,6 +1322,81 @@ func parseSentinel(result **config.SentinelConfig, list *ast.ObjectList) error {
 	return nil
 }
 
+func parseOPA(result **config.OPAConfig, list *ast.ObjectList) error {
+	list = list.Elem()
+	if len(list.Items) > 1 {
+		return fmt.Errorf("only one 'opa' block allowed")
+	}
+
+	// Get our OPA object
+	obj := list.Items[0]
+
+	// Value should be an object
+	var listVal *ast.ObjectList
+	if ot, ok := obj.Val.(*ast.ObjectType); ok {
+		listVal = ot.List
+	} else {
+		return fmt.Errorf("opa value: should be an object")
+	}
+
+	// Check for invalid keys
+	valid := []string{
+		"address",
+		"token",
+		"timeout",
+		"policy",
+	}
+	if err := helper.CheckHCLKeys(listVal, valid); err != nil {
+		return err
+	}
+
+	var m map[string]interface{}
+	if err := hcl.DecodeObject(&m, listVal); err != nil {
+		return err
+	}
+	delete(m, "policy")
+
+	var opaConfig config.OPAConfig
+	dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook:       mapstructure.StringToTimeDurationHookFunc(),
+		WeaklyTypedInput: true,
+		Result:           &opaConfig,
+	})
+	if err != nil {
+		return err
+	}
+	if err := dec.Decode(m); err != nil {
+		return err
+	}
+
+	// Parse the policies
+	for i, item := range listVal.Filter("policy").Items {
+		// Ensure there is a name
+		if len(item.Keys) != 1 {
+			return fmt.Errorf("opa policy %d doesn't include a name key", i+1)
+		}
+		name := item.Keys[0].Token.Value().(string)
+
+		if err := helper.CheckHCLKeys(item.Val, []string{"path", "enforcement_level"}); err != nil {
+			return multierror.Prefix(err, fmt.Sprintf("policy %s:", name))
+		}
+
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, item.Val); err != nil {
+			return err
+		}
+
+		policy := config.OPAPolicyConfig{Name: name}
+		if err := mapstructure.WeakDecode(m, &policy); err != nil {
+			return fmt.Errorf("error decoding opa policy %q: %v", name, err)
+		}
+		opaConfig.Policies = append(opaConfig.Policies, &policy)
+	}
+
+	*result = &opaConfig
+	return nil
+}
+
 func parseAutopilot(result **config.AutopilotConfig, list *ast.ObjectList) error {
 	list = list.Elem()
 	if len(list.Items) > 1 {
@@ -969,6 +1412,7 @@ func parseAutopilot(result **config.AutopilotConfig, list *ast.ObjectList) error
 		"server_stabilization_time",
 		"last_contact_threshold",
 		"max_trailing_logs",
+		"min_quorum",
 		"enable_redundancy_zones",
 		"disable_upgrade_migration",
 		"enable_custom_upgrades",
@@ -1000,6 +1444,302 @@ func parseAutopilot(result **config.AutopilotConfig, list *ast.ObjectList) error
 	return nil
 }
 
+func parseSnapshotAgent(result **config.SnapshotAgentConfig, list *ast.ObjectList) error {
+	list = list.Elem()
+	if len(list.Items) > 1 {
+		return fmt.Errorf("only one 'snapshot_agent' block allowed")
+	}
+
+	// Get our snapshot agent object
+	obj := list.Items[0]
+
+	// Value should be an object
+	var listVal *ast.ObjectList
+	if ot, ok := obj.Val.(*ast.ObjectType); ok {
+		listVal = ot.List
+	} else {
+		return fmt.Errorf("snapshot_agent value: should be an object")
+	}
+
+	// Check for invalid keys
+	valid := []string{
+		"enabled",
+		"interval",
+		"retain",
+		"name_prefix",
+		"local_storage",
+		"s3_storage",
+		"gcs_storage",
+		"azure_blob_storage",
+	}
+	if err := helper.CheckHCLKeys(listVal, valid); err != nil {
+		return err
+	}
+
+	var m map[string]interface{}
+	if err := hcl.DecodeObject(&m, listVal); err != nil {
+		return err
+	}
+	delete(m, "local_storage")
+	delete(m, "s3_storage")
+	delete(m, "gcs_storage")
+	delete(m, "azure_blob_storage")
+
+	snapshotConfig := config.DefaultSnapshotAgentConfig()
+	dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook:       mapstructure.StringToTimeDurationHookFunc(),
+		WeaklyTypedInput: true,
+		Result:           snapshotConfig,
+	})
+	if err != nil {
+		return err
+	}
+	if err := dec.Decode(m); err != nil {
+		return err
+	}
+
+	// Parse the storages
+	parseStorage := func(name string, valid []string, out interface{}) (bool, error) {
+		o := listVal.Filter(name)
+		if len(o.Items) == 0 {
+			return false, nil
+		}
+		if len(o.Items) > 1 {
+			return false, fmt.Errorf("only one '%s' block allowed", name)
+		}
+		if err := helper.CheckHCLKeys(o.Items[0].Val, valid); err != nil {
+			return false, multierror.Prefix(err, name+"->")
+		}
+
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, o.Items[0].Val); err != nil {
+			return false, err
+		}
+		return true, mapstructure.WeakDecode(m, out)
+	}
+
+	var local config.SnapshotLocalStorage
+	if ok, err := parseStorage("local_storage", []string{"path"}, &local); err != nil {
+		return err
+	} else if ok {
+		snapshotConfig.LocalStorage = &local
+	}
+
+	var s3 config.SnapshotS3Storage
+	if ok, err := parseStorage("s3_storage", []string{
+		"bucket",
+		"key_prefix",
+		"region",
+		"endpoint",
+		"force_path_style",
+		"access_key_id",
+		"secret_access_key",
+		"server_side_encryption",
+	}, &s3); err != nil {
+		return err
+	} else if ok {
+		snapshotConfig.S3Storage = &s3
+	}
+
+	var gcs config.SnapshotGCSStorage
+	if ok, err := parseStorage("gcs_storage", []string{
+		"bucket",
+		"key_prefix",
+		"endpoint",
+		"access_key_id",
+		"secret_access_key",
+	}, &gcs); err != nil {
+		return err
+	} else if ok {
+		snapshotConfig.GCSStorage = &gcs
+	}
+
+	var azure config.SnapshotAzureBlobStorage
+	if ok, err := parseStorage("azure_blob_storage", []string{
+		"account_name",
+		"account_key",
+		"container_name",
+		"key_prefix",
+		"endpoint",
+	}, &azure); err != nil {
+		return err
+	} else if ok {
+		snapshotConfig.AzureBlobStorage = &azure
+	}
+
+	*result = snapshotConfig
+	return nil
+}
+
+func parseLimits(result **Limits, list *ast.ObjectList) error {
+	list = list.Elem()
+	if len(list.Items) > 1 {
+		return fmt.Errorf("only one 'limits' block allowed")
+	}
+
+	// Get our limits object
+	listVal := list.Items[0].Val
+
+	// Check for invalid keys
+	valid := []string{
+		"http_max_conns",
+		"http_max_conns_per_client",
+		"http_rate_limit",
+		"http_rate_burst",
+		"http_token_rate_limit",
+		"http_token_rate_burst",
+	}
+	if err := helper.CheckHCLKeys(listVal, valid); err != nil {
+		return err
+	}
+
+	var m map[string]interface{}
+	if err := hcl.DecodeObject(&m, listVal); err != nil {
+		return err
+	}
+
+	var limits Limits
+	if err := mapstructure.WeakDecode(m, &limits); err != nil {
+		return err
+	}
+
+	*result = &limits
+	return nil
+}
+
+func parseCORS(result **CORSConfig, list *ast.ObjectList) error {
+	list = list.Elem()
+	if len(list.Items) > 1 {
+		return fmt.Errorf("only one 'http_api_cors' block allowed")
+	}
+
+	// Get our CORS object
+	listVal := list.Items[0].Val
+
+	// Check for invalid keys
+	valid := []string{
+		"allowed_origins",
+		"allowed_methods",
+		"allowed_headers",
+		"exposed_headers",
+		"allow_credentials",
+		"max_age",
+	}
+	if err := helper.CheckHCLKeys(listVal, valid); err != nil {
+		return err
+	}
+
+	var m map[string]interface{}
+	if err := hcl.DecodeObject(&m, listVal); err != nil {
+		return err
+	}
+
+	var cors CORSConfig
+	if err := mapstructure.WeakDecode(m, &cors); err != nil {
+		return err
+	}
+
+	*result = &cors
+	return nil
+}
+
+func parseAudit(result **AuditConfig, list *ast.ObjectList) error {
+	list = list.Elem()
+	if len(list.Items) > 1 {
+		return fmt.Errorf("only one 'audit' block allowed")
+	}
+
+	// Get our audit object
+	obj := list.Items[0]
+
+	// Value should be an object
+	var listVal *ast.ObjectList
+	if ot, ok := obj.Val.(*ast.ObjectType); ok {
+		listVal = ot.List
+	} else {
+		return fmt.Errorf("audit value: should be an object")
+	}
+
+	// Check for invalid keys
+	valid := []string{
+		"enabled",
+		"sink",
+		"filter",
+	}
+	if err := helper.CheckHCLKeys(listVal, valid); err != nil {
+		return err
+	}
+
+	var m map[string]interface{}
+	if err := hcl.DecodeObject(&m, listVal); err != nil {
+		return err
+	}
+	delete(m, "sink")
+	delete(m, "filter")
+
+	var audit AuditConfig
+	if err := mapstructure.WeakDecode(m, &audit); err != nil {
+		return err
+	}
+
+	// Parse the sinks
+	for i, o := range listVal.Filter("sink").Items {
+		if len(o.Keys) != 1 {
+			return fmt.Errorf("sink %d doesn't include a name key", i+1)
+		}
+		name := o.Keys[0].Token.Value().(string)
+
+		valid := []string{
+			"type",
+			"path",
+			"network",
+			"address",
+		}
+		if err := helper.CheckHCLKeys(o.Val, valid); err != nil {
+			return multierror.Prefix(err, fmt.Sprintf("sink %q:", name))
+		}
+
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, o.Val); err != nil {
+			return err
+		}
+		sink := AuditSink{Name: name}
+		if err := mapstructure.WeakDecode(m, &sink); err != nil {
+			return err
+		}
+		audit.Sinks = append(audit.Sinks, &sink)
+	}
+
+	// Parse the filters
+	for i, o := range listVal.Filter("filter").Items {
+		if len(o.Keys) != 1 {
+			return fmt.Errorf("filter %d doesn't include a name key", i+1)
+		}
+		name := o.Keys[0].Token.Value().(string)
+
+		valid := []string{
+			"endpoints",
+			"operations",
+		}
+		if err := helper.CheckHCLKeys(o.Val, valid); err != nil {
+			return multierror.Prefix(err, fmt.Sprintf("filter %q:", name))
+		}
+
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, o.Val); err != nil {
+			return err
+		}
+		filter := AuditFilter{Name: name}
+		if err := mapstructure.WeakDecode(m, &filter); err != nil {
+			return err
+		}
+		audit.Filters = append(audit.Filters, &filter)
+	}
+
+	*result = &audit
+	return nil
+}
+
 func parsePlugins(result *[]*config.PluginConfig, list *ast.ObjectList) error {
 	listLen := len(list.Items)
 	plugins := make([]*config.PluginConfig, listLen)
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/nomad/structs/config"
 	"github.com/stretchr/testify/require"
 )
@@ -43,11 +44,18 @@ func TestConfig_Parse(t *testing.T) {
 					Serf: "127.0.0.4",
 				},
 				Client: &ClientConfig{
-					Enabled:   true,
-					StateDir:  "/tmp/client-state",
-					AllocDir:  "/tmp/alloc",
-					Servers:   []string{"a.b.c:80", "127.0.0.1:1234"},
-					NodeClass: "linux-medium-64bit",
+					Enabled:             true,
+					StateDir:            "/tmp/client-state",
+					AllocDir:            "/tmp/alloc",
+					HostVolumesDir:      "/tmp/host_volumes",
+					HostVolumePluginDir: "/tmp/host_volume_plugins",
+					CNIPath:             "/tmp/cni/bin",
+					CNIConfigDir:        "/tmp/cni/config",
+					BridgeNetworkName:   "testbridge",
+					BridgeNetworkSubnet: "10.10.0.0/16",
+					Servers:             []string{"a.b.c:80", "127.0.0.1:1234"},
+					NodeClass:           "linux-medium-64bit",
+					NodePool:            "linux",
 					ServerJoin: &ServerJoin{
 						RetryJoin:        []string{"1.1.1.1", "2.2.2.2"},
 						RetryInterval:    time.Duration(15) * time.Second,
@@ -78,13 +86,48 @@ func TestConfig_Parse(t *testing.T) {
 						DiskMB:        10,
 						IOPS:          10,
 						ReservedPorts: "1,100,10-12",
+						CgroupSlice:   "system.slice",
+					},
+					GCInterval:              6 * time.Second,
+					GCParallelDestroys:      6,
+					GCDiskUsageThreshold:    82,
+					GCInodeUsageThreshold:   91,
+					GCMaxAllocs:             50,
+					DiskIneligibleThreshold: 95,
+					NoHostUUID:              helper.BoolToPtr(false),
+					EnableTaskAPI:           true,
+					Fingerprinters: []*config.FingerprinterConfig{
+						{
+							Name:     "raid",
+							Command:  "/usr/local/bin/raid-health",
+							Args:     []string{"-json"},
+							Interval: 30 * time.Second,
+							Timeout:  5 * time.Second,
+						},
+					},
+					HostVolumes: []*structs.ClientHostVolumeConfig{
+						{
+							Name:       "shared_data",
+							Path:       "/srv/data",
+							ReadOnly:   true,
+							Mountpoint: true,
+						},
+					},
+					HostNetworks: []*structs.ClientHostNetworkConfig{
+						{
+							Name:          "public",
+							Interface:     "eth1",
+							CIDR:          "203.0.113.0/24",
+							ReservedPorts: "22",
+						},
+					},
+					DrainOnShutdown: &config.DrainConfig{
+						Deadline:         10 * time.Minute,
+						IgnoreSystemJobs: true,
+					},
+					DrainOnTermination: &config.DrainConfig{
+						Deadline: 90 * time.Second,
 					},
-					GCInterval:            6 * time.Second,
-					GCParallelDestroys:    6,
-					GCDiskUsageThreshold:  82,
-					GCInodeUsageThreshold: 91,
-					GCMaxAllocs:           50,
-					NoHostUUID:            helper.BoolToPtr(false),
 				},
 				Server: &ServerConfig{
 					Enabled:                true,
@@ -102,6 +145,14 @@ func TestConfig_Parse(t *testing.T) {
 					HeartbeatGrace:         30 * time.Second,
 					MinHeartbeatTTL:        33 * time.Second,
 					MaxHeartbeatsPerSecond: 11.0,
+					NodeEventRetention:     25,
+					PlanBatchSize:          8,
+					EvalUpdateBatchSize:    32,
+					RPCMaxIdleStreams:      16,
+					RPCConnIdleTimeout:     5 * time.Minute,
+					EvalFairShare:          true,
+					EvalNackTimeout:        90 * time.Second,
+					EvalDeliveryLimit:      5,
 					RetryJoin:              []string{"1.1.1.1", "2.2.2.2"},
 					StartJoin:              []string{"1.1.1.1", "2.2.2.2"},
 					RetryInterval:          15 * time.Second,
@@ -111,6 +162,22 @@ func TestConfig_Parse(t *testing.T) {
 					RedundancyZone:         "foo",
 					UpgradeVersion:         "0.8.0",
 					EncryptKey:             "abc",
+					KeyringEncryptionKey:   "M2Y0ZjdkZTkxNmQyZjY5YWE3NDg2MTQ2YWEyNjNhNTA=",
+					AdmissionWebhooks: []*config.AdmissionWebhookConfig{
+						{
+							Name:          "policy",
+							URL:           "https://policy.example.com/nomad",
+							Type:          "mutating",
+							Timeout:       5 * time.Second,
+							FailurePolicy: "ignore",
+						},
+					},
+					EvalFairShareWeights: map[string]int{
+						"default": 1,
+						"prod":    3,
+					},
+					EvalNackInitialReenqueueDelay:    2 * time.Second,
+					EvalNackSubsequentReenqueueDelay: 30 * time.Second,
 					ServerJoin: &ServerJoin{
 						RetryJoin:        []string{"1.1.1.1", "2.2.2.2"},
 						RetryInterval:    time.Duration(15) * time.Second,
@@ -118,10 +185,12 @@ func TestConfig_Parse(t *testing.T) {
 					},
 				},
 				ACL: &ACLConfig{
-					Enabled:          true,
-					TokenTTL:         60 * time.Second,
-					PolicyTTL:        60 * time.Second,
-					ReplicationToken: "foobar",
+					Enabled:               true,
+					TokenTTL:              60 * time.Second,
+					PolicyTTL:             60 * time.Second,
+					TokenMinExpirationTTL: 1 * time.Minute,
+					TokenMaxExpirationTTL: 72 * time.Hour,
+					ReplicationToken:      "foobar",
 				},
 				Telemetry: &Telemetry{
 					StatsiteAddr:               "127.0.0.1:1234",
@@ -177,9 +246,12 @@ func TestConfig_Parse(t *testing.T) {
 					Token:                "12345",
 				},
 				TLSConfig: &config.TLSConfig{
-					EnableHTTP:                  true,
-					EnableRPC:                   true,
-					VerifyServerHostname:        true,
+					EnableHTTP:           true,
+					EnableRPC:            true,
+					VerifyServerHostname: true,
+					RegionServerNames: map[string]string{
+						"europe": "server.eu.example.com",
+					},
 					CAFile:                      "foo",
 					CertFile:                    "bar",
 					KeyFile:                     "pipe",
@@ -192,6 +264,38 @@ func TestConfig_Parse(t *testing.T) {
 				HTTPAPIResponseHeaders: map[string]string{
 					"Access-Control-Allow-Origin": "*",
 				},
+				HTTPAPIGzipMinSize: 2048,
+				HTTPAPICORS: &CORSConfig{
+					AllowedOrigins:   []string{"https://*.example.com"},
+					AllowedMethods:   []string{"GET", "PUT"},
+					AllowedHeaders:   []string{"X-Nomad-Token"},
+					ExposedHeaders:   []string{"X-Nomad-Index"},
+					AllowCredentials: true,
+					MaxAge:           600,
+				},
+				Audit: &AuditConfig{
+					Enabled: helper.BoolToPtr(true),
+					Sinks: []*AuditSink{
+						{
+							Name: "file",
+							Type: "file",
+							Path: "/var/log/nomad/audit.json",
+						},
+						{
+							Name:    "collector",
+							Type:    "socket",
+							Network: "tcp",
+							Address: "127.0.0.1:9090",
+						},
+					},
+					Filters: []*AuditFilter{
+						{
+							Name:       "health",
+							Endpoints:  []string{"/v1/agent/health", "/v1/metrics"},
+							Operations: []string{"GET"},
+						},
+					},
+				},
 				Sentinel: &config.SentinelConfig{
 					Imports: []*config.SentinelImport{
 						{
@@ -211,10 +315,43 @@ func TestConfig_Parse(t *testing.T) {
 					ServerStabilizationTime: 23057 * time.Second,
 					LastContactThreshold:    12705 * time.Second,
 					MaxTrailingLogs:         17849,
+					MinQuorum:               3,
 					EnableRedundancyZones:   &trueValue,
 					DisableUpgradeMigration: &trueValue,
 					EnableCustomUpgrades:    &trueValue,
 				},
+				OPA: &config.OPAConfig{
+					Address: "http://127.0.0.1:8181",
+					Timeout: 3 * time.Second,
+					Policies: []*config.OPAPolicyConfig{
+						{
+							Name:             "privileged",
+							Path:             "nomad/docker/privileged",
+							EnforcementLevel: "soft-mandatory",
+						},
+					},
+				},
+				SnapshotAgent: &config.SnapshotAgentConfig{
+					Enabled:      &trueValue,
+					Interval:     30 * time.Minute,
+					Retain:       48,
+					NamePrefix:   "nomad",
+					LocalStorage: &config.SnapshotLocalStorage{Path: "/opt/nomad/snapshots"},
+					S3Storage: &config.SnapshotS3Storage{
+						Bucket:               "nomad-backups",
+						KeyPrefix:            "prod/",
+						Region:               "us-west-2",
+						ServerSideEncryption: true,
+					},
+				},
+				Limits: &Limits{
+					HTTPMaxConns:          500,
+					HTTPMaxConnsPerClient: 50,
+					HTTPRateLimit:         20.5,
+					HTTPRateBurst:         40,
+					HTTPTokenRateLimit:    10,
+					HTTPTokenRateBurst:    15,
+				},
 				Plugins: []*config.PluginConfig{
 					{
 						Name: "docker",
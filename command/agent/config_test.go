@@ -38,6 +38,7 @@ func TestConfig_Merge(t *testing.T) {
 		Consul:         &config.ConsulConfig{},
 		Sentinel:       &config.SentinelConfig{},
 		Autopilot:      &config.AutopilotConfig{},
+		Limits:         &Limits{},
 	}
 
 	c2 := &Config{
@@ -114,12 +115,18 @@ func TestConfig_Merge(t *testing.T) {
 			MaxHeartbeatsPerSecond: 30.0,
 			RedundancyZone:         "foo",
 			UpgradeVersion:         "foo",
+			KeyringEncryptionKey:   "foo",
+			AdmissionWebhooks: []*config.AdmissionWebhookConfig{
+				{Name: "policy", URL: "http://foo"},
+			},
 		},
 		ACL: &ACLConfig{
-			Enabled:          true,
-			TokenTTL:         60 * time.Second,
-			PolicyTTL:        60 * time.Second,
-			ReplicationToken: "foo",
+			Enabled:               true,
+			TokenTTL:              60 * time.Second,
+			PolicyTTL:             60 * time.Second,
+			TokenMinExpirationTTL: 1 * time.Minute,
+			TokenMaxExpirationTTL: 1 * time.Hour,
+			ReplicationToken:      "foo",
 		},
 		Ports: &Ports{
 			HTTP: 4646,
@@ -138,6 +145,17 @@ func TestConfig_Merge(t *testing.T) {
 		HTTPAPIResponseHeaders: map[string]string{
 			"Access-Control-Allow-Origin": "*",
 		},
+		HTTPAPIGzipMinSize: 2048,
+		HTTPAPICORS: &CORSConfig{
+			AllowedOrigins: []string{"https://a.example.com"},
+			MaxAge:         60,
+		},
+		Audit: &AuditConfig{
+			Enabled: &falseValue,
+			Sinks: []*AuditSink{
+				{Name: "file", Type: "file", Path: "/tmp/audit1.json"},
+			},
+		},
 		Vault: &config.VaultConfig{
 			Token:                "1",
 			AllowUnauthenticated: &falseValue,
@@ -172,10 +190,30 @@ func TestConfig_Merge(t *testing.T) {
 			ServerStabilizationTime: 1 * time.Second,
 			LastContactThreshold:    1 * time.Second,
 			MaxTrailingLogs:         1,
+			MinQuorum:               1,
 			EnableRedundancyZones:   &falseValue,
 			DisableUpgradeMigration: &falseValue,
 			EnableCustomUpgrades:    &falseValue,
 		},
+		OPA: &config.OPAConfig{
+			Address: "http://foo",
+			Timeout: time.Second,
+		},
+		SnapshotAgent: &config.SnapshotAgentConfig{
+			Enabled:      &falseValue,
+			Interval:     time.Hour,
+			Retain:       10,
+			NamePrefix:   "nomad",
+			LocalStorage: &config.SnapshotLocalStorage{Path: "/tmp/snapshots1"},
+		},
+		Limits: &Limits{
+			HTTPMaxConns:          100,
+			HTTPMaxConnsPerClient: 10,
+			HTTPRateLimit:         1,
+			HTTPRateBurst:         2,
+			HTTPTokenRateLimit:    3,
+			HTTPTokenRateBurst:    4,
+		},
 		Plugins: []*config.PluginConfig{
 			{
 				Name: "docker",
@@ -231,11 +269,18 @@ func TestConfig_Merge(t *testing.T) {
 			FilterDefault:                      helper.BoolToPtr(false),
 		},
 		Client: &ClientConfig{
-			Enabled:   true,
-			StateDir:  "/tmp/state2",
-			AllocDir:  "/tmp/alloc2",
-			NodeClass: "class2",
-			Servers:   []string{"server2"},
+			Enabled:             true,
+			StateDir:            "/tmp/state2",
+			AllocDir:            "/tmp/alloc2",
+			HostVolumesDir:      "/tmp/host_volumes2",
+			HostVolumePluginDir: "/tmp/host_volume_plugins2",
+			CNIPath:             "/tmp/cni/bin2",
+			CNIConfigDir:        "/tmp/cni/config2",
+			BridgeNetworkName:   "testbridge2",
+			BridgeNetworkSubnet: "10.20.0.0/16",
+			NodeClass:           "class2",
+			NodePool:            "pool2",
+			Servers:             []string{"server2"},
 			Meta: map[string]string{
 				"baz": "zip",
 			},
@@ -256,11 +301,38 @@ func TestConfig_Merge(t *testing.T) {
 				DiskMB:        15,
 				IOPS:          15,
 				ReservedPorts: "2,10-30,55",
+				CgroupSlice:   "system.slice",
+			},
+			GCInterval:              6 * time.Second,
+			GCParallelDestroys:      6,
+			GCDiskUsageThreshold:    71,
+			GCInodeUsageThreshold:   86,
+			DiskIneligibleThreshold: 90,
+			EnableTaskAPI:           true,
+			Fingerprinters: []*config.FingerprinterConfig{
+				{
+					Name:    "raid",
+					Command: "/usr/local/bin/raid-health",
+				},
+			},
+			HostVolumes: []*structs.ClientHostVolumeConfig{
+				{
+					Name: "shared_data",
+					Path: "/srv/data",
+				},
+			},
+			HostNetworks: []*structs.ClientHostNetworkConfig{
+				{
+					Name:      "public",
+					Interface: "eth1",
+				},
+			},
+			DrainOnShutdown: &config.DrainConfig{
+				Deadline: 30 * time.Minute,
+			},
+			DrainOnTermination: &config.DrainConfig{
+				Deadline: time.Minute,
 			},
-			GCInterval:            6 * time.Second,
-			GCParallelDestroys:    6,
-			GCDiskUsageThreshold:  71,
-			GCInodeUsageThreshold: 86,
 		},
 		Server: &ServerConfig{
 			Enabled:                true,
@@ -275,6 +347,14 @@ func TestConfig_Merge(t *testing.T) {
 			HeartbeatGrace:         2 * time.Minute,
 			MinHeartbeatTTL:        2 * time.Minute,
 			MaxHeartbeatsPerSecond: 200.0,
+			NodeEventRetention:     50,
+			PlanBatchSize:          4,
+			EvalUpdateBatchSize:    16,
+			RPCMaxIdleStreams:      32,
+			RPCConnIdleTimeout:     3 * time.Minute,
+			EvalFairShare:          true,
+			EvalNackTimeout:        2 * time.Minute,
+			EvalDeliveryLimit:      4,
 			RejoinAfterLeave:       true,
 			StartJoin:              []string{"1.1.1.1"},
 			RetryJoin:              []string{"1.1.1.1"},
@@ -282,12 +362,18 @@ func TestConfig_Merge(t *testing.T) {
 			NonVotingServer:        true,
 			RedundancyZone:         "bar",
 			UpgradeVersion:         "bar",
+			KeyringEncryptionKey:   "bar",
+			AdmissionWebhooks: []*config.AdmissionWebhookConfig{
+				{Name: "policy", URL: "http://bar"},
+			},
 		},
 		ACL: &ACLConfig{
-			Enabled:          true,
-			TokenTTL:         20 * time.Second,
-			PolicyTTL:        20 * time.Second,
-			ReplicationToken: "foobar",
+			Enabled:               true,
+			TokenTTL:              20 * time.Second,
+			PolicyTTL:             20 * time.Second,
+			TokenMinExpirationTTL: 2 * time.Minute,
+			TokenMaxExpirationTTL: 2 * time.Hour,
+			ReplicationToken:      "foobar",
 		},
 		Ports: &Ports{
 			HTTP: 20000,
@@ -307,6 +393,24 @@ func TestConfig_Merge(t *testing.T) {
 			"Access-Control-Allow-Origin":  "*",
 			"Access-Control-Allow-Methods": "GET, POST, OPTIONS",
 		},
+		HTTPAPIGzipMinSize: 4096,
+		HTTPAPICORS: &CORSConfig{
+			AllowedOrigins:   []string{"https://b.example.com"},
+			AllowedMethods:   []string{"GET"},
+			AllowedHeaders:   []string{"X-Nomad-Token"},
+			ExposedHeaders:   []string{"X-Nomad-Index"},
+			AllowCredentials: true,
+			MaxAge:           120,
+		},
+		Audit: &AuditConfig{
+			Enabled: &trueValue,
+			Sinks: []*AuditSink{
+				{Name: "file", Type: "file", Path: "/tmp/audit2.json"},
+			},
+			Filters: []*AuditFilter{
+				{Name: "health", Endpoints: []string{"/v1/agent/health"}},
+			},
+		},
 		Vault: &config.VaultConfig{
 			Token:                "2",
 			AllowUnauthenticated: &trueValue,
@@ -350,10 +454,31 @@ func TestConfig_Merge(t *testing.T) {
 			ServerStabilizationTime: 2 * time.Second,
 			LastContactThreshold:    2 * time.Second,
 			MaxTrailingLogs:         2,
+			MinQuorum:               2,
 			EnableRedundancyZones:   &trueValue,
 			DisableUpgradeMigration: &trueValue,
 			EnableCustomUpgrades:    &trueValue,
 		},
+		OPA: &config.OPAConfig{
+			Address: "http://bar",
+			Token:   "bar",
+			Timeout: 2 * time.Second,
+		},
+		SnapshotAgent: &config.SnapshotAgentConfig{
+			Enabled:      &trueValue,
+			Interval:     2 * time.Hour,
+			Retain:       20,
+			NamePrefix:   "nomad2",
+			LocalStorage: &config.SnapshotLocalStorage{Path: "/tmp/snapshots2"},
+		},
+		Limits: &Limits{
+			HTTPMaxConns:          200,
+			HTTPMaxConnsPerClient: 20,
+			HTTPRateLimit:         5,
+			HTTPRateBurst:         6,
+			HTTPTokenRateLimit:    7,
+			HTTPTokenRateBurst:    8,
+		},
 		Plugins: []*config.PluginConfig{
 			{
 				Name: "docker",
@@ -642,7 +642,7 @@ func (c *ServiceClient) serviceRegs(ops *operations, service *structs.Service, t
 	}
 
 	// Determine the address to advertise based on the mode
-	ip, port, err := getAddress(addrMode, service.PortLabel, task.Networks, task.DriverNetwork)
+	ip, port, err := GetAddress(addrMode, service.PortLabel, task.Networks, task.DriverNetwork)
 	if err != nil {
 		return nil, fmt.Errorf("unable to get address for service %q: %v", service.Name, err)
 	}
@@ -706,7 +706,7 @@ func (c *ServiceClient) checkRegs(ops *operations, serviceID string, service *st
 				c.client, c.logger, c.shutdownCh)
 			ops.scripts = append(ops.scripts, sc)
 
-			// Skip getAddress for script checks
+			// Skip GetAddress for script checks
 			checkReg, err := createCheckReg(serviceID, checkID, check, "", 0)
 			if err != nil {
 				return nil, fmt.Errorf("failed to add script check %q: %v", check.Name, err)
@@ -728,7 +728,7 @@ func (c *ServiceClient) checkRegs(ops *operations, serviceID string, service *st
 			addrMode = structs.AddressModeHost
 		}
 
-		ip, port, err := getAddress(addrMode, portLabel, task.Networks, task.DriverNetwork)
+		ip, port, err := GetAddress(addrMode, portLabel, task.Networks, task.DriverNetwork)
 		if err != nil {
 			return nil, fmt.Errorf("error getting address for check %q: %v", check.Name, err)
 		}
@@ -1171,10 +1171,10 @@ func isOldNomadService(id string) bool {
 	return strings.HasPrefix(id, prefix)
 }
 
-// getAddress returns the IP and port to use for a service or check. If no port
+// GetAddress returns the IP and port to use for a service or check. If no port
 // label is specified (an empty value), zero values are returned because no
 // address could be resolved.
-func getAddress(addrMode, portLabel string, networks structs.Networks, driverNet *cstructs.DriverNetwork) (string, int, error) {
+func GetAddress(addrMode, portLabel string, networks structs.Networks, driverNet *cstructs.DriverNetwork) (string, int, error) {
 	switch addrMode {
 	case structs.AddressModeAuto:
 		if driverNet.Advertise() {
@@ -1182,7 +1182,7 @@ func getAddress(addrMode, portLabel string, networks structs.Networks, driverNet
 		} else {
 			addrMode = structs.AddressModeHost
 		}
-		return getAddress(addrMode, portLabel, networks, driverNet)
+		return GetAddress(addrMode, portLabel, networks, driverNet)
 	case structs.AddressModeHost:
 		if portLabel == "" {
 			if len(networks) != 1 {
@@ -1710,8 +1710,8 @@ func TestGetAddress(t *testing.T) {
 				i++
 			}
 
-			// Run getAddress
-			ip, port, err := getAddress(tc.Mode, tc.PortLabel, networks, tc.Driver)
+			// Run GetAddress
+			ip, port, err := GetAddress(tc.Mode, tc.PortLabel, networks, tc.Driver)
 
 			// Assert the results
 			assert.Equal(t, tc.ExpectedIP, ip, "IP mismatch")
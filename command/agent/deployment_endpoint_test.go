@@ -206,6 +206,41 @@ func TestHTTP_DeploymentPause(t *testing.T) {
 	})
 }
 
+func TestHTTP_DeploymentUnblock(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		// Directly manipulate the state
+		state := s.Agent.server.State()
+		j := mock.Job()
+		d := mock.Deployment()
+		d.JobID = j.ID
+		d.IsMultiregion = true
+		d.Status = structs.DeploymentStatusBlocked
+		assert.Nil(state.UpsertJob(999, j), "UpsertJob")
+		assert.Nil(state.UpsertDeployment(1000, d), "UpsertDeployment")
+
+		// Make the HTTP request
+		req, err := http.NewRequest("PUT", "/v1/deployment/unblock/"+d.ID, nil)
+		assert.Nil(err, "HTTP Request")
+		respW := httptest.NewRecorder()
+
+		// Make the request
+		obj, err := s.Server.DeploymentSpecificRequest(respW, req)
+		assert.Nil(err, "Deployment Request")
+
+		// Check the response
+		resp := obj.(structs.DeploymentUpdateResponse)
+		assert.NotZero(resp.DeploymentModifyIndex, "Expect Deployment to be Modified")
+		assert.NotZero(respW.HeaderMap.Get("X-Nomad-Index"), "missing index")
+
+		// The deployment is successful
+		out, err := state.DeploymentByID(nil, d.ID)
+		assert.Nil(err, "DeploymentByID")
+		assert.Equal(structs.DeploymentStatusSuccessful, out.Status)
+	})
+}
+
 func TestHTTP_DeploymentPromote(t *testing.T) {
 	t.Parallel()
 	assert := assert.New(t)
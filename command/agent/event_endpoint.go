@@ -0,0 +1,74 @@
+package agent
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// EventStream streams the cluster events as newline delimited JSON until the
+// request is canceled. The events are filtered by the topic query parameters,
+// of the form topic=Job or topic=Job:example, and resumed after the index
+// query parameter.
+func (s *HTTPServer) EventStream(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "GET" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	topics, err := parseEventTopics(req.URL.Query())
+	if err != nil {
+		return nil, CodedError(400, err.Error())
+	}
+
+	args := structs.EventStreamRequest{
+		Topics: topics,
+	}
+	if s.parse(resp, req, &args.QueryOptions.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	// The index is the index to resume the stream after, not the index of a
+	// blocking query.
+	args.Index = args.MinQueryIndex
+	args.MinQueryIndex = 0
+
+	handler, err := s.serverStreamingRpcHandler("Event.Stream")
+	if err != nil {
+		return nil, CodedError(500, err.Error())
+	}
+
+	resp.Header().Set("Content-Type", "application/json")
+	return s.streamRpcImpl(resp, req, handler, &args)
+}
+
+// parseEventTopics parses the topic query parameters. A topic without a key
+// matches all the keys of the topic.
+func parseEventTopics(query url.Values) (map[structs.Topic][]string, error) {
+	raw, ok := query["topic"]
+	if !ok {
+		return nil, nil
+	}
+
+	topics := make(map[structs.Topic][]string, len(raw))
+	for _, t := range raw {
+		parts := strings.SplitN(t, ":", 2)
+		if parts[0] == "" {
+			return nil, fmt.Errorf("Invalid topic %q", t)
+		}
+
+		key := "*"
+		if len(parts) == 2 {
+			if parts[1] == "" {
+				return nil, fmt.Errorf("Invalid topic %q: missing key", t)
+			}
+			key = parts[1]
+		}
+
+		topic := structs.Topic(parts[0])
+		topics[topic] = append(topics[topic], key)
+	}
+	return topics, nil
+}
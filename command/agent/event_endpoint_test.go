@@ -0,0 +1,84 @@
+package agent
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTP_EventStream(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	httpTest(t, nil, func(s *TestAgent) {
+		// Invalid topics are rejected
+		req, err := http.NewRequest("GET", "/v1/event/stream?topic=Job:", nil)
+		require.NoError(err)
+		_, err = s.Server.EventStream(httptest.NewRecorder(), req)
+		require.Error(err)
+		require.Contains(err.Error(), "missing key")
+
+		// Register a job
+		job := mock.Job()
+		args := structs.JobRegisterRequest{
+			Job:          job,
+			WriteRequest: structs.WriteRequest{Region: "global", Namespace: structs.DefaultNamespace},
+		}
+		var resp structs.JobRegisterResponse
+		require.NoError(s.Agent.RPC("Job.Register", &args, &resp))
+
+		// Stream the events of the job from the start
+		req, err = http.NewRequest("GET", "/v1/event/stream?index=1&topic=Job:"+url.QueryEscape(job.ID), nil)
+		require.NoError(err)
+		ctx, cancel := context.WithCancel(req.Context())
+		req = req.WithContext(ctx)
+
+		respW := httptest.NewRecorder()
+		doneCh := make(chan error, 1)
+		go func() {
+			_, err := s.Server.EventStream(respW, req)
+			doneCh <- err
+		}()
+
+		time.Sleep(500 * time.Millisecond)
+		cancel()
+		select {
+		case err := <-doneCh:
+			require.NoError(err)
+		case <-time.After(time.Duration(testutil.TestMultiplier()*5) * time.Second):
+			t.Fatal("timeout waiting for the stream to end")
+		}
+
+		body := respW.Body.String()
+		require.Contains(body, `"Type":"JobRegistered"`)
+		require.Contains(body, job.ID)
+		require.NotContains(body, `"Topic":"Evaluation"`)
+	})
+}
+
+func TestParseEventTopics(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	topics, err := parseEventTopics(url.Values{})
+	require.NoError(err)
+	require.Nil(topics)
+
+	topics, err = parseEventTopics(url.Values{"topic": {"Job", "Allocation:a", "Allocation:b:c"}})
+	require.NoError(err)
+	require.Equal(map[structs.Topic][]string{
+		structs.TopicJob:        {"*"},
+		structs.TopicAllocation: {"a", "b:c"},
+	}, topics)
+
+	_, err = parseEventTopics(url.Values{"topic": {":a"}})
+	require.Error(err)
+}
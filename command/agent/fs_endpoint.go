@@ -23,6 +23,8 @@ var (
 	logTypeNotPresentErr  = fmt.Errorf("must provide log type (stdout/stderr)")
 	clientNotRunning      = fmt.Errorf("node is not running a Nomad Client")
 	invalidOrigin         = fmt.Errorf("origin must be start or end")
+	invalidLogFormat      = fmt.Errorf("format must be frames, json or plain")
+	invalidPlainFormat    = fmt.Errorf("plain can only be used with the plain format")
 )
 
 func (s *HTTPServer) FsRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
@@ -194,11 +196,16 @@ func (s *HTTPServer) FileCatRequest(resp http.ResponseWriter, req *http.Request)
 // Stream streams the content of a file blocking on EOF.
 // The parameters are:
 // * path: path to file to stream.
+// * follow: A boolean of whether to follow the file, defaults to true.
+// * plain: A boolean of whether to return the plain text without framing.
 // * offset: The offset to start streaming data at, defaults to zero.
 // * origin: Either "start" or "end" and defines from where the offset is
 //           applied. Defaults to "start".
 func (s *HTTPServer) Stream(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
 	var allocID, path string
+	var plain bool
+	follow := true
+	var err error
 
 	q := req.URL.Query()
 
@@ -210,6 +217,18 @@ func (s *HTTPServer) Stream(resp http.ResponseWriter, req *http.Request) (interf
 		return nil, fileNameNotPresentErr
 	}
 
+	if followStr := q.Get("follow"); followStr != "" {
+		if follow, err = strconv.ParseBool(followStr); err != nil {
+			return nil, fmt.Errorf("Failed to parse follow field to boolean: %v", err)
+		}
+	}
+
+	if plainStr := q.Get("plain"); plainStr != "" {
+		if plain, err = strconv.ParseBool(plainStr); err != nil {
+			return nil, fmt.Errorf("Failed to parse plain field to boolean: %v", err)
+		}
+	}
+
 	var offset int64
 	offsetString := q.Get("offset")
 	if offsetString != "" {
@@ -230,11 +249,12 @@ func (s *HTTPServer) Stream(resp http.ResponseWriter, req *http.Request) (interf
 
 	// Create the request arguments
 	fsReq := &cstructs.FsStreamRequest{
-		AllocID: allocID,
-		Path:    path,
-		Origin:  origin,
-		Offset:  offset,
-		Follow:  true,
+		AllocID:   allocID,
+		Path:      path,
+		Origin:    origin,
+		Offset:    offset,
+		PlainText: plain,
+		Follow:    follow,
 	}
 	s.parse(resp, req, &fsReq.QueryOptions.Region, &fsReq.QueryOptions)
 
@@ -249,6 +269,10 @@ func (s *HTTPServer) Stream(resp http.ResponseWriter, req *http.Request) (interf
 // * offset: The offset to start streaming data at, defaults to zero.
 // * origin: Either "start" or "end" and defines from where the offset is
 //           applied. Defaults to "start".
+// * file: The log file to resume streaming from, in which the offset is
+//         applied. The origin is then ignored.
+// * format: Either "frames", "json" or "plain". Defaults to "frames".
+// * plain: A boolean of whether to use the "plain" format.
 func (s *HTTPServer) Logs(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
 	var allocID, task, logType string
 	var plain, follow bool
@@ -300,6 +324,21 @@ func (s *HTTPServer) Logs(resp http.ResponseWriter, req *http.Request) (interfac
 		return nil, invalidOrigin
 	}
 
+	format := q.Get("format")
+	switch format {
+	case "":
+		format = cstructs.LogFormatFrames
+		if plain {
+			format = cstructs.LogFormatPlain
+		}
+	case cstructs.LogFormatFrames, cstructs.LogFormatJSON, cstructs.LogFormatPlain:
+		if plain && format != cstructs.LogFormatPlain {
+			return nil, invalidPlainFormat
+		}
+	default:
+		return nil, invalidLogFormat
+	}
+
 	// Create the request arguments
 	fsReq := &cstructs.FsLogsRequest{
 		AllocID:   allocID,
@@ -307,11 +346,22 @@ func (s *HTTPServer) Logs(resp http.ResponseWriter, req *http.Request) (interfac
 		LogType:   logType,
 		Offset:    offset,
 		Origin:    origin,
-		PlainText: plain,
+		PlainText: format == cstructs.LogFormatPlain,
+		Format:    format,
+		File:      q.Get("file"),
 		Follow:    follow,
 	}
 	s.parse(resp, req, &fsReq.QueryOptions.Region, &fsReq.QueryOptions)
 
+	switch format {
+	case cstructs.LogFormatPlain:
+		resp.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	case cstructs.LogFormatJSON:
+		resp.Header().Set("Content-Type", "application/x-ndjson")
+	default:
+		resp.Header().Set("Content-Type", "application/json")
+	}
+
 	// Make the request
 	return s.fsStreamImpl(resp, req, "FileSystem.Logs", fsReq, fsReq.AllocID)
 }
@@ -338,6 +388,15 @@ func (s *HTTPServer) fsStreamImpl(resp http.ResponseWriter,
 		return nil, CodedError(500, handlerErr.Error())
 	}
 
+	return s.streamRpcImpl(resp, req, handler, args)
+}
+
+// streamRpcImpl is used to make a streaming RPC call to the handler that
+// serializes the args and then expects a stream of StreamErrWrapper results
+// where the payload is copied to the response body.
+func (s *HTTPServer) streamRpcImpl(resp http.ResponseWriter, req *http.Request,
+	handler structs.StreamingRpcHandler, args interface{}) (interface{}, error) {
+
 	// Create a pipe connecting the (possibly remote) handler to the http response
 	httpPipe, handlerPipe := net.Pipe()
 	decoder := codec.NewDecoder(httpPipe, structs.MsgpackHandle)
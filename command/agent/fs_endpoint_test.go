@@ -244,6 +244,22 @@ func TestHTTP_FS_Logs_MissingParams(t *testing.T) {
 		require.Equal(respW.Body.String(), logTypeNotPresentErr.Error())
 		require.Equal(500, respW.Code) // 500 for backward compat
 
+		// Invalid format
+		req, err = http.NewRequest("GET", "/v1/client/fs/logs/foo?task=foo&type=stdout&format=xml", nil)
+		require.Nil(err)
+		respW = httptest.NewRecorder()
+
+		s.Server.mux.ServeHTTP(respW, req)
+		require.Equal(respW.Body.String(), invalidLogFormat.Error())
+
+		// Plain with another format
+		req, err = http.NewRequest("GET", "/v1/client/fs/logs/foo?task=foo&type=stdout&format=json&plain=true", nil)
+		require.Nil(err)
+		respW = httptest.NewRecorder()
+
+		s.Server.mux.ServeHTTP(respW, req)
+		require.Equal(respW.Body.String(), invalidPlainFormat.Error())
+
 		// Ok
 		req, err = http.NewRequest("GET", "/v1/client/fs/logs/foo?task=foo&type=stdout", nil)
 		require.Nil(err)
@@ -389,6 +405,44 @@ func TestHTTP_FS_Stream(t *testing.T) {
 	})
 }
 
+func TestHTTP_FS_Stream_NoFollow_Plain(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		a := mockFSAlloc(s.client.NodeID(), nil)
+		addAllocToClient(s, a, terminalClientAlloc)
+
+		offset := 4
+		expectation := defaultLoggerMockDriverStdout[len(defaultLoggerMockDriverStdout)-offset:]
+		path := fmt.Sprintf("/v1/client/fs/stream/%s?path=alloc/logs/web.stdout.0&offset=%d&origin=end&plain=true&follow=false",
+			a.ID, offset)
+
+		p, _ := io.Pipe()
+		req, err := http.NewRequest("GET", path, p)
+		require.Nil(err)
+		respW := testutil.NewResponseRecorder()
+		errCh := make(chan error, 1)
+		go func() {
+			_, err := s.Server.Stream(respW, req)
+			errCh <- err
+		}()
+
+		// The stream ends once the end of the file is reached
+		select {
+		case err := <-errCh:
+			require.Nil(err)
+		case <-time.After(10 * time.Second):
+			t.Fatal("stream should have ended")
+		}
+
+		out, err := ioutil.ReadAll(respW)
+		require.Nil(err)
+		require.Equal(expectation, string(out))
+
+		p.Close()
+	})
+}
+
 func TestHTTP_FS_Logs(t *testing.T) {
 	t.Parallel()
 	require := require.New(t)
@@ -0,0 +1,101 @@
+package agent
+
+import (
+	"net/http"
+	"strings"
+
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// HostVolumeSpecificRequest is used to create and delete host volumes on a
+// node
+func (s *HTTPServer) HostVolumeSpecificRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "PUT" && req.Method != "POST" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	path := strings.TrimPrefix(req.URL.Path, "/v1/client/host-volume/")
+	switch path {
+	case "create":
+		return s.hostVolumeCreate(resp, req)
+	case "delete":
+		return s.hostVolumeDelete(resp, req)
+	default:
+		return nil, CodedError(404, "Invalid host volume request")
+	}
+}
+
+func (s *HTTPServer) hostVolumeCreate(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	var args cstructs.HostVolumeCreateRequest
+	if err := decodeBody(req, &args); err != nil {
+		return nil, CodedError(400, err.Error())
+	}
+	if args.Volume == nil {
+		return nil, CodedError(400, "Missing volume")
+	}
+	if nodeID := req.URL.Query().Get("node_id"); nodeID != "" {
+		args.NodeID = nodeID
+	}
+	s.parse(resp, req, &args.QueryOptions.Region, &args.QueryOptions)
+
+	// Determine the handler to use
+	useLocalClient, useClientRPC, useServerRPC := s.rpcHandlerForNode(args.NodeID)
+	if useLocalClient && args.NodeID == "" {
+		args.NodeID = s.agent.Client().NodeID()
+	}
+
+	// Make the RPC
+	var reply cstructs.HostVolumeCreateResponse
+	var rpcErr error
+	if useLocalClient {
+		rpcErr = s.agent.Client().ClientRPC("HostVolume.Create", &args, &reply)
+	} else if useClientRPC {
+		rpcErr = s.agent.Client().RPC("ClientHostVolume.Create", &args, &reply)
+	} else if useServerRPC {
+		rpcErr = s.agent.Server().RPC("ClientHostVolume.Create", &args, &reply)
+	} else {
+		rpcErr = CodedError(400, "No local Node and node_id not provided")
+	}
+
+	if rpcErr != nil {
+		return nil, nodeMetaError(rpcErr)
+	}
+	return reply, nil
+}
+
+func (s *HTTPServer) hostVolumeDelete(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	args := cstructs.HostVolumeDeleteRequest{
+		NodeID:   req.URL.Query().Get("node_id"),
+		VolumeID: req.URL.Query().Get("volume_id"),
+	}
+	if args.VolumeID == "" {
+		return nil, CodedError(400, "Missing volume_id")
+	}
+	s.parse(resp, req, &args.QueryOptions.Region, &args.QueryOptions)
+
+	// Determine the handler to use
+	useLocalClient, useClientRPC, useServerRPC := s.rpcHandlerForNode(args.NodeID)
+	if useLocalClient && args.NodeID == "" {
+		args.NodeID = s.agent.Client().NodeID()
+	}
+
+	// Make the RPC
+	var reply structs.GenericResponse
+	var rpcErr error
+	if useLocalClient {
+		rpcErr = s.agent.Client().ClientRPC("HostVolume.Delete", &args, &reply)
+	} else if useClientRPC {
+		rpcErr = s.agent.Client().RPC("ClientHostVolume.Delete", &args, &reply)
+	} else if useServerRPC {
+		rpcErr = s.agent.Server().RPC("ClientHostVolume.Delete", &args, &reply)
+	} else {
+		rpcErr = CodedError(400, "No local Node and node_id not provided")
+	}
+
+	if rpcErr != nil {
+		return nil, nodeMetaError(rpcErr)
+	}
+	setIndex(resp, reply.Index)
+	return nil, nil
+}
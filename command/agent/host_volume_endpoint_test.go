@@ -0,0 +1,53 @@
+package agent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTP_HostVolume_CreateDelete(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		// Create a volume on the local node
+		args := api.HostVolumeCreateRequest{
+			Volume: &api.Volume{ID: "data", Name: "data"},
+		}
+		req, err := http.NewRequest("PUT", "/v1/client/host-volume/create", encodeReq(args))
+		require.NoError(err)
+		respW := httptest.NewRecorder()
+
+		obj, err := s.Server.HostVolumeSpecificRequest(respW, req)
+		require.NoError(err)
+		volume := obj.(cstructs.HostVolumeCreateResponse).Volume
+		require.Equal(s.client.NodeID(), volume.NodeID)
+		_, err = os.Stat(volume.ExternalID)
+		require.NoError(err)
+
+		// Missing volume
+		req, err = http.NewRequest("PUT", "/v1/client/host-volume/create", encodeReq(api.HostVolumeCreateRequest{}))
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+
+		_, err = s.Server.HostVolumeSpecificRequest(respW, req)
+		require.Error(err)
+		require.Equal(400, err.(HTTPCodedError).Code())
+
+		// Delete the volume
+		req, err = http.NewRequest("PUT", "/v1/client/host-volume/delete?volume_id=data", nil)
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+
+		_, err = s.Server.HostVolumeSpecificRequest(respW, req)
+		require.NoError(err)
+		require.NotEmpty(respW.Header().Get("X-Nomad-Index"))
+		_, err = os.Stat(volume.ExternalID)
+		require.True(os.IsNotExist(err))
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"crypto/tls"
 	"encoding/json"
 	"fmt"
+	"hash/fnv"
 	"net"
 	"net/http"
 	"net/http/pprof"
@@ -13,7 +14,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/NYTimes/gziphandler"
 	assetfs "github.com/elazarl/go-bindata-assetfs"
 	log "github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/nomad/helper/tlsutil"
@@ -54,17 +54,41 @@ type HTTPServer struct {
 	listenerCh chan struct{}
 	logger     log.Logger
 	Addr       string
+
+	// clientLimiter and tokenLimiter limit the rate of the requests of each
+	// client IP address and ACL token, if enabled.
+	clientLimiter *rateLimiter
+	tokenLimiter  *rateLimiter
+
+	// resolveToken resolves the secret ID of the token of a request.
+	resolveToken func(secretID string) (*structs.ACLToken, error)
+
+	// cors sets the CORS headers of the origins configured in http_api_cors,
+	// if any.
+	cors *cors.Cors
+
+	// auditor writes the audit log of the requests, if enabled.
+	auditor *auditor
 }
 
 // NewHTTPServer starts new HTTP server over the agent
 func NewHTTPServer(agent *Agent, config *Config) (*HTTPServer, error) {
+	// Open the audit log
+	resolveToken := agentTokenResolver(agent)
+	auditor, err := newAuditor(config.Audit, agent.httpLogger, resolveToken)
+	if err != nil {
+		return nil, err
+	}
+
 	// Start the listener
 	lnAddr, err := net.ResolveTCPAddr("tcp", config.normalizedAddrs.HTTP)
 	if err != nil {
+		auditor.Close()
 		return nil, err
 	}
 	ln, err := config.Listener("tcp", lnAddr.IP.String(), lnAddr.Port)
 	if err != nil {
+		auditor.Close()
 		return nil, fmt.Errorf("failed to start HTTP listener: %v", err)
 	}
 
@@ -87,24 +111,38 @@ func NewHTTPServer(agent *Agent, config *Config) (*HTTPServer, error) {
 
 	// Create the server
 	srv := &HTTPServer{
-		agent:      agent,
-		mux:        mux,
-		listener:   ln,
-		listenerCh: make(chan struct{}),
-		logger:     agent.httpLogger,
-		Addr:       ln.Addr().String(),
+		agent:        agent,
+		mux:          mux,
+		listener:     ln,
+		listenerCh:   make(chan struct{}),
+		logger:       agent.httpLogger,
+		Addr:         ln.Addr().String(),
+		cors:         newCORS(config.HTTPAPICORS),
+		auditor:      auditor,
+		resolveToken: resolveToken,
 	}
 	srv.registerHandlers(config.EnableDebug)
 
 	// Handle requests with gzip compression
-	gzip, err := gziphandler.GzipHandlerWithOpts(gziphandler.MinSize(0))
-	if err != nil {
-		return nil, err
+	gzipMinSize := config.HTTPAPIGzipMinSize
+	if gzipMinSize <= 0 {
+		gzipMinSize = defaultGzipMinSize
+	}
+
+	// Limit the connections and requests of the clients
+	httpServer := &http.Server{}
+	if limits := config.Limits; limits != nil {
+		if c := newConnLimiter(limits.HTTPMaxConns, limits.HTTPMaxConnsPerClient); c != nil {
+			httpServer.ConnState = c.connState
+		}
+		srv.clientLimiter = newRateLimiter(limits.HTTPRateLimit, limits.HTTPRateBurst)
+		srv.tokenLimiter = newRateLimiter(limits.HTTPTokenRateLimit, limits.HTTPTokenRateBurst)
 	}
+	httpServer.Handler = srv.corsHandler(srv.metricsHandler(srv.limitHandler(srv.auditHandler(gzipHandler(gzipMinSize, mux)))))
 
 	go func() {
 		defer close(srv.listenerCh)
-		http.Serve(ln, gzip(mux))
+		httpServer.Serve(ln)
 	}()
 
 	return srv, nil
@@ -133,6 +171,7 @@ func (s *HTTPServer) Shutdown() {
 		s.logger.Debug("shutting down http server")
 		s.listener.Close()
 		<-s.listenerCh // block until http.Serve has returned.
+		s.auditor.Close()
 	}
 }
 
@@ -144,6 +183,26 @@ func (s *HTTPServer) registerHandlers(enableDebug bool) {
 
 	s.mux.HandleFunc("/v1/nodes", s.wrap(s.NodesRequest))
 	s.mux.HandleFunc("/v1/node/", s.wrap(s.NodeSpecificRequest))
+	s.mux.HandleFunc("/v1/node/pools", s.wrap(s.NodePoolsRequest))
+	s.mux.HandleFunc("/v1/node/pool/", s.wrap(s.NodePoolSpecificRequest))
+
+	s.mux.HandleFunc("/v1/namespaces", s.wrap(s.NamespacesRequest))
+	s.mux.HandleFunc("/v1/namespace", s.wrap(s.NamespaceCreateRequest))
+	s.mux.HandleFunc("/v1/namespace/", s.wrap(s.NamespaceSpecificRequest))
+
+	s.mux.HandleFunc("/v1/quotas", s.wrap(s.QuotasRequest))
+	s.mux.HandleFunc("/v1/quota-usages", s.wrap(s.QuotaUsagesRequest))
+	s.mux.HandleFunc("/v1/quota", s.wrap(s.QuotaCreateRequest))
+	s.mux.HandleFunc("/v1/quota/", s.wrap(s.QuotaSpecificRequest))
+
+	s.mux.HandleFunc("/v1/vars", s.wrap(s.VariablesListRequest))
+	s.mux.HandleFunc("/v1/var/", s.wrap(s.VariableSpecificRequest))
+
+	s.mux.HandleFunc("/v1/services", s.wrap(s.ServiceRegistrationListRequest))
+	s.mux.HandleFunc("/v1/service/", s.wrap(s.ServiceRegistrationRequest))
+
+	s.mux.HandleFunc("/v1/volumes", s.wrap(s.VolumesRequest))
+	s.mux.HandleFunc("/v1/volume/", s.wrap(s.VolumeSpecificRequest))
 
 	s.mux.HandleFunc("/v1/allocations", s.wrap(s.AllocsRequest))
 	s.mux.HandleFunc("/v1/allocation/", s.wrap(s.AllocSpecificRequest))
@@ -161,11 +220,25 @@ func (s *HTTPServer) registerHandlers(enableDebug bool) {
 	s.mux.HandleFunc("/v1/acl/tokens", s.wrap(s.ACLTokensRequest))
 	s.mux.HandleFunc("/v1/acl/token", s.wrap(s.ACLTokenSpecificRequest))
 	s.mux.HandleFunc("/v1/acl/token/", s.wrap(s.ACLTokenSpecificRequest))
-
-	s.mux.Handle("/v1/client/fs/", wrapCORS(s.wrap(s.FsRequest)))
+	s.mux.HandleFunc("/v1/acl/roles", s.wrap(s.ACLRolesRequest))
+	s.mux.HandleFunc("/v1/acl/role", s.wrap(s.ACLRoleSpecificRequest))
+	s.mux.HandleFunc("/v1/acl/role/", s.wrap(s.ACLRoleSpecificRequest))
+	s.mux.HandleFunc("/v1/acl/auth-methods", s.wrap(s.ACLAuthMethodsRequest))
+	s.mux.HandleFunc("/v1/acl/auth-method", s.wrap(s.ACLAuthMethodSpecificRequest))
+	s.mux.HandleFunc("/v1/acl/auth-method/", s.wrap(s.ACLAuthMethodSpecificRequest))
+	s.mux.HandleFunc("/v1/acl/binding-rules", s.wrap(s.ACLBindingRulesRequest))
+	s.mux.HandleFunc("/v1/acl/binding-rule", s.wrap(s.ACLBindingRuleSpecificRequest))
+	s.mux.HandleFunc("/v1/acl/binding-rule/", s.wrap(s.ACLBindingRuleSpecificRequest))
+	s.mux.HandleFunc("/v1/acl/oidc/auth-url", s.wrap(s.ACLOIDCAuthURLRequest))
+	s.mux.HandleFunc("/v1/acl/oidc/complete-auth", s.wrap(s.ACLOIDCCompleteAuthRequest))
+	s.mux.HandleFunc("/v1/acl/login", s.wrap(s.ACLLoginRequest))
+
+	s.mux.Handle("/v1/client/fs/", s.wrapCORS(s.wrap(s.FsRequest)))
 	s.mux.HandleFunc("/v1/client/gc", s.wrap(s.ClientGCRequest))
-	s.mux.Handle("/v1/client/stats", wrapCORS(s.wrap(s.ClientStatsRequest)))
-	s.mux.Handle("/v1/client/allocation/", wrapCORS(s.wrap(s.ClientAllocRequest)))
+	s.mux.Handle("/v1/client/stats", s.wrapCORS(s.wrap(s.ClientStatsRequest)))
+	s.mux.HandleFunc("/v1/client/metadata", s.wrap(s.NodeMetaRequest))
+	s.mux.HandleFunc("/v1/client/host-volume/", s.wrap(s.HostVolumeSpecificRequest))
+	s.mux.Handle("/v1/client/allocation/", s.wrapCORS(s.wrap(s.ClientAllocRequest)))
 
 	s.mux.HandleFunc("/v1/agent/self", s.wrap(s.AgentSelfRequest))
 	s.mux.HandleFunc("/v1/agent/join", s.wrap(s.AgentJoinRequest))
@@ -174,9 +247,13 @@ func (s *HTTPServer) registerHandlers(enableDebug bool) {
 	s.mux.HandleFunc("/v1/agent/servers", s.wrap(s.AgentServersRequest))
 	s.mux.HandleFunc("/v1/agent/keyring/", s.wrap(s.KeyringOperationRequest))
 	s.mux.HandleFunc("/v1/agent/health", s.wrap(s.HealthRequest))
+	s.mux.HandleFunc("/v1/agent/monitor", s.wrap(s.AgentMonitor))
+	s.mux.HandleFunc("/v1/agent/pprof/", s.wrap(s.AgentPprofRequest))
 
 	s.mux.HandleFunc("/v1/metrics", s.wrap(s.MetricsRequest))
 
+	s.mux.HandleFunc("/v1/event/stream", s.wrap(s.EventStream))
+
 	s.mux.HandleFunc("/v1/validate/job", s.wrap(s.ValidateJobRequest))
 
 	s.mux.HandleFunc("/v1/regions", s.wrap(s.RegionListRequest))
@@ -185,15 +262,19 @@ func (s *HTTPServer) registerHandlers(enableDebug bool) {
 	s.mux.HandleFunc("/v1/status/peers", s.wrap(s.StatusPeersRequest))
 
 	s.mux.HandleFunc("/v1/search", s.wrap(s.SearchRequest))
+	s.mux.HandleFunc("/v1/search/fuzzy", s.wrap(s.FuzzySearchRequest))
 
 	s.mux.HandleFunc("/v1/operator/raft/", s.wrap(s.OperatorRequest))
 	s.mux.HandleFunc("/v1/operator/autopilot/configuration", s.wrap(s.OperatorAutopilotConfiguration))
 	s.mux.HandleFunc("/v1/operator/autopilot/health", s.wrap(s.OperatorServerHealth))
+	s.mux.HandleFunc("/v1/operator/snapshot", s.wrap(s.SnapshotRequest))
+	s.mux.HandleFunc("/v1/operator/keyring/", s.wrap(s.KeyringRequest))
 
 	s.mux.HandleFunc("/v1/system/gc", s.wrap(s.GarbageCollectRequest))
 	s.mux.HandleFunc("/v1/system/reconcile/summaries", s.wrap(s.ReconcileJobSummaries))
 
 	s.mux.HandleFunc("/v1/operator/scheduler/configuration", s.wrap(s.OperatorSchedulerConfiguration))
+	s.mux.HandleFunc("/v1/operator/eval-broker", s.wrap(s.OperatorEvalBrokerStats))
 
 	if uiEnabled {
 		s.mux.Handle("/ui/", http.StripPrefix("/ui/", handleUI(http.FileServer(&UIAssetWrapper{FileSystem: assetFS()}))))
@@ -299,6 +380,8 @@ func (s *HTTPServer) wrap(handler func(resp http.ResponseWriter, req *http.Reque
 				} else if strings.HasSuffix(errMsg, structs.ErrTokenNotFound.Error()) {
 					errMsg = structs.ErrTokenNotFound.Error()
 					code = 403
+				} else if structs.IsErrInvalidFilter(err) {
+					code = 400
 				}
 			}
 
@@ -332,12 +415,44 @@ func (s *HTTPServer) wrap(handler func(resp http.ResponseWriter, req *http.Reque
 				goto HAS_ERR
 			}
 			resp.Header().Set("Content-Type", "application/json")
+			if notModified(resp, req, buf.Bytes()) {
+				resp.WriteHeader(http.StatusNotModified)
+				return
+			}
 			resp.Write(buf.Bytes())
 		}
 	}
 	return f
 }
 
+// notModified sets the ETag of the responses of the queries, and returns
+// whether the client already has the response, as found by matching the ETag
+// against the If-None-Match header of the request. This saves clients polling
+// with blocking queries from downloading the results again when they are
+// unchanged once the query times out.
+func notModified(resp http.ResponseWriter, req *http.Request, body []byte) bool {
+	index := resp.Header().Get("X-Nomad-Index")
+	if req.Method != "GET" || index == "" {
+		return false
+	}
+
+	hash := fnv.New64a()
+	hash.Write(body)
+	etag := fmt.Sprintf(`"%s-%x"`, index, hash.Sum64())
+
+	// The results of queries can change at any time, so caches must
+	// revalidate them
+	resp.Header().Set("ETag", etag)
+	resp.Header().Set("Cache-Control", "no-cache")
+
+	for _, match := range strings.Split(req.Header.Get("If-None-Match"), ",") {
+		if match = strings.TrimSpace(match); match == etag || match == "*" {
+			return true
+		}
+	}
+	return false
+}
+
 // decodeBody is used to decode a JSON request body
 func decodeBody(req *http.Request, out interface{}) error {
 	dec := json.NewDecoder(req.Body)
@@ -364,11 +479,19 @@ func setLastContact(resp http.ResponseWriter, last time.Duration) {
 	resp.Header().Set("X-Nomad-LastContact", strconv.FormatUint(lastMsec, 10))
 }
 
+// setNextToken is used to set the next token header of paginated lists
+func setNextToken(resp http.ResponseWriter, token string) {
+	if token != "" {
+		resp.Header().Set("X-Nomad-NextToken", token)
+	}
+}
+
 // setMeta is used to set the query response meta data
 func setMeta(resp http.ResponseWriter, m *structs.QueryMeta) {
 	setIndex(resp, m.Index)
 	setLastContact(resp, m.LastContact)
 	setKnownLeader(resp, m.KnownLeader)
+	setNextToken(resp, m.NextToken)
 }
 
 // setHeaders is used to set canonical response header fields
@@ -419,6 +542,24 @@ func parsePrefix(req *http.Request, b *structs.QueryOptions) {
 	}
 }
 
+// parsePagination is used to parse the ?filter, ?per_page and ?next_token
+// query params. Returns true on error
+func parsePagination(resp http.ResponseWriter, req *http.Request, b *structs.QueryOptions) bool {
+	query := req.URL.Query()
+	b.Filter = query.Get("filter")
+	b.NextToken = query.Get("next_token")
+	if perPage := query.Get("per_page"); perPage != "" {
+		n, err := strconv.ParseInt(perPage, 10, 32)
+		if err != nil || n < 0 {
+			resp.WriteHeader(400)
+			resp.Write([]byte("Invalid per_page"))
+			return true
+		}
+		b.PerPage = int32(n)
+	}
+	return false
+}
+
 // parseRegion is used to parse the ?region query param
 func (s *HTTPServer) parseRegion(req *http.Request, r *string) {
 	if other := req.URL.Query().Get("region"); other != "" {
@@ -452,6 +593,9 @@ func (s *HTTPServer) parse(resp http.ResponseWriter, req *http.Request, r *strin
 	parseConsistency(req, b)
 	parsePrefix(req, b)
 	parseNamespace(req, &b.Namespace)
+	if parsePagination(resp, req, b) {
+		return true
+	}
 	return parseWait(resp, req, b)
 }
 
@@ -463,7 +607,11 @@ func (s *HTTPServer) parseWriteRequest(req *http.Request, w *structs.WriteReques
 	s.parseRegion(req, &w.Region)
 }
 
-// wrapCORS wraps a HandlerFunc in allowCORS and returns a http.Handler
-func wrapCORS(f func(http.ResponseWriter, *http.Request)) http.Handler {
+// wrapCORS wraps a HandlerFunc in allowCORS and returns a http.Handler. The
+// handler is left to the CORS configuration of the agent if there is one.
+func (s *HTTPServer) wrapCORS(f func(http.ResponseWriter, *http.Request)) http.Handler {
+	if s.cors != nil {
+		return http.HandlerFunc(f)
+	}
 	return allowCORS.Handler(http.HandlerFunc(f))
 }
@@ -0,0 +1,63 @@
+package agent
+
+import (
+	"net/http"
+
+	"github.com/rs/cors"
+)
+
+var (
+	// defaultCORSMethods are the methods allowed by default to the origins
+	// configured in http_api_cors.
+	defaultCORSMethods = []string{"HEAD", "GET", "PUT", "POST", "DELETE"}
+
+	// defaultCORSHeaders are the request headers allowed by default to the
+	// origins configured in http_api_cors.
+	defaultCORSHeaders = []string{"Content-Type", "X-Nomad-Token"}
+
+	// defaultCORSExposedHeaders are the response headers exposed by default
+	// to the origins configured in http_api_cors, which are the ones needed
+	// for blocking queries and pagination.
+	defaultCORSExposedHeaders = []string{
+		"X-Nomad-Index",
+		"X-Nomad-KnownLeader",
+		"X-Nomad-LastContact",
+		"X-Nomad-NextToken",
+	}
+)
+
+// newCORS returns the CORS handler of the configuration, or nil if CORS isn't
+// enabled.
+func newCORS(conf *CORSConfig) *cors.Cors {
+	if conf == nil || len(conf.AllowedOrigins) == 0 {
+		return nil
+	}
+
+	opts := cors.Options{
+		AllowedOrigins:   conf.AllowedOrigins,
+		AllowedMethods:   conf.AllowedMethods,
+		AllowedHeaders:   conf.AllowedHeaders,
+		ExposedHeaders:   conf.ExposedHeaders,
+		AllowCredentials: conf.AllowCredentials,
+		MaxAge:           conf.MaxAge,
+	}
+	if len(opts.AllowedMethods) == 0 {
+		opts.AllowedMethods = defaultCORSMethods
+	}
+	if len(opts.AllowedHeaders) == 0 {
+		opts.AllowedHeaders = defaultCORSHeaders
+	}
+	if len(opts.ExposedHeaders) == 0 {
+		opts.ExposedHeaders = defaultCORSExposedHeaders
+	}
+	return cors.New(opts)
+}
+
+// corsHandler wraps the handler to set the CORS headers of the configured
+// origins, and to answer their preflight requests.
+func (s *HTTPServer) corsHandler(h http.Handler) http.Handler {
+	if s.cors == nil {
+		return h
+	}
+	return s.cors.Handler(h)
+}
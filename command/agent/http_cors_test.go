@@ -0,0 +1,66 @@
+package agent
+
+import (
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCORS(t *testing.T) {
+	t.Parallel()
+
+	require.Nil(t, newCORS(nil))
+	require.Nil(t, newCORS(&CORSConfig{AllowedMethods: []string{"GET"}}))
+	require.NotNil(t, newCORS(&CORSConfig{AllowedOrigins: []string{"*"}}))
+}
+
+func TestHTTP_CORS(t *testing.T) {
+	t.Parallel()
+	s := makeHTTPServer(t, func(c *Config) {
+		c.HTTPAPICORS = &CORSConfig{
+			AllowedOrigins: []string{"https://*.example.com"},
+			MaxAge:         600,
+		}
+	})
+	defer s.Shutdown()
+
+	do := func(method, path, origin string, headers map[string]string) *http.Response {
+		req, err := http.NewRequest(method, s.HTTPAddr()+path, nil)
+		require.NoError(t, err)
+		req.Header.Set("Origin", origin)
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		return resp
+	}
+
+	// Preflight requests of allowed origins are answered
+	resp := do("OPTIONS", "/v1/jobs", "https://tools.example.com", map[string]string{
+		"Access-Control-Request-Method":  "PUT",
+		"Access-Control-Request-Headers": "X-Nomad-Token",
+	})
+	require.Equal(t, 200, resp.StatusCode)
+	require.Equal(t, "https://tools.example.com", resp.Header.Get("Access-Control-Allow-Origin"))
+	require.Equal(t, "PUT", resp.Header.Get("Access-Control-Allow-Methods"))
+	require.Equal(t, "X-Nomad-Token", resp.Header.Get("Access-Control-Allow-Headers"))
+	require.Equal(t, "600", resp.Header.Get("Access-Control-Max-Age"))
+
+	// Requests expose the blocking query headers
+	resp = do("GET", "/v1/jobs", "https://tools.example.com", nil)
+	require.Equal(t, 200, resp.StatusCode)
+	require.Equal(t, "https://tools.example.com", resp.Header.Get("Access-Control-Allow-Origin"))
+	require.Contains(t, resp.Header.Get("Access-Control-Expose-Headers"), "X-Nomad-Index")
+
+	// Other origins are not allowed, including on the endpoints allowing any
+	// origin by default
+	resp = do("GET", "/v1/jobs", "https://evil.com", nil)
+	require.Empty(t, resp.Header.Get("Access-Control-Allow-Origin"))
+	resp = do("GET", "/v1/client/stats", "https://evil.com", nil)
+	require.Empty(t, resp.Header.Get("Access-Control-Allow-Origin"))
+}
@@ -0,0 +1,171 @@
+package agent
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+const (
+	// defaultGzipMinSize is the default minimum size of the responses
+	// compressed with gzip. Compressing smaller responses costs more CPU
+	// than it saves bandwidth.
+	defaultGzipMinSize = 1024
+)
+
+// gzipWriterPool holds the gzip writers of the responses to reuse them
+var gzipWriterPool = sync.Pool{
+	New: func() interface{} {
+		return gzip.NewWriter(nil)
+	},
+}
+
+// gzipHandler wraps the handler to compress the responses of the clients
+// accepting gzip once they reach minSize bytes. Flushed responses, such as the
+// streaming ones, are compressed regardless of their size so that they aren't
+// held back.
+func gzipHandler(minSize int, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		resp.Header().Add("Vary", "Accept-Encoding")
+		if !acceptsGzip(req) {
+			h.ServeHTTP(resp, req)
+			return
+		}
+
+		gw := &gzipResponseWriter{ResponseWriter: resp, minSize: minSize}
+		defer gw.close()
+		h.ServeHTTP(gw, req)
+	})
+}
+
+// acceptsGzip returns whether the Accept-Encoding header of the request
+// accepts gzip.
+func acceptsGzip(req *http.Request) bool {
+	for _, enc := range strings.Split(req.Header.Get("Accept-Encoding"), ",") {
+		parts := strings.Split(enc, ";")
+		if strings.TrimSpace(parts[0]) != "gzip" {
+			continue
+		}
+		for _, param := range parts[1:] {
+			if q := strings.TrimSpace(param); q == "q=0" || q == "q=0.0" {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// gzipResponseWriter buffers the response until it either reaches the minimum
+// size and is compressed, or ends and is written as is.
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	minSize int
+
+	code    int
+	buf     []byte
+	gw      *gzip.Writer
+	started bool
+}
+
+func (w *gzipResponseWriter) WriteHeader(code int) {
+	if w.started {
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+	w.code = code
+}
+
+func (w *gzipResponseWriter) Write(b []byte) (int, error) {
+	if w.started {
+		if w.gw != nil {
+			return w.gw.Write(b)
+		}
+		return w.ResponseWriter.Write(b)
+	}
+
+	w.buf = append(w.buf, b...)
+	if len(w.buf) >= w.minSize {
+		if err := w.start(true); err != nil {
+			return 0, err
+		}
+	}
+	return len(b), nil
+}
+
+// Flush compresses the response regardless of its size, and flushes it to
+// the client.
+func (w *gzipResponseWriter) Flush() {
+	if !w.started {
+		w.start(true)
+	}
+	if w.gw != nil {
+		w.gw.Flush()
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack hijacks the connection of the response, which isn't compressed.
+func (w *gzipResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if h, ok := w.ResponseWriter.(http.Hijacker); ok {
+		w.started = true
+		return h.Hijack()
+	}
+	return nil, nil, fmt.Errorf("response doesn't support hijacking")
+}
+
+// start writes the header and the buffered response, compressing it if
+// requested and the response isn't already encoded.
+func (w *gzipResponseWriter) start(compress bool) error {
+	w.started = true
+
+	header := w.Header()
+	if compress && header.Get("Content-Encoding") == "" {
+		if header.Get("Content-Type") == "" && len(w.buf) > 0 {
+			header.Set("Content-Type", http.DetectContentType(w.buf))
+		}
+		header.Set("Content-Encoding", "gzip")
+		header.Del("Content-Length")
+
+		w.gw = gzipWriterPool.Get().(*gzip.Writer)
+		w.gw.Reset(w.ResponseWriter)
+	}
+
+	if w.code != 0 {
+		w.ResponseWriter.WriteHeader(w.code)
+	}
+
+	buf := w.buf
+	w.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	if w.gw != nil {
+		_, err := w.gw.Write(buf)
+		return err
+	}
+	_, err := w.ResponseWriter.Write(buf)
+	return err
+}
+
+// close writes the responses too small to be compressed, and completes the
+// compressed ones.
+func (w *gzipResponseWriter) close() error {
+	if !w.started {
+		return w.start(false)
+	}
+	if w.gw == nil {
+		return nil
+	}
+
+	err := w.gw.Close()
+	gzipWriterPool.Put(w.gw)
+	w.gw = nil
+	return err
+}
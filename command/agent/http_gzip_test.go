@@ -0,0 +1,90 @@
+package agent
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGzipHandler(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	small := "small"
+	large := strings.Repeat("large", 100)
+	handler := gzipHandler(100, http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/small":
+			resp.Write([]byte(small))
+		case "/large":
+			resp.WriteHeader(201)
+			resp.Write([]byte(large))
+		case "/flush":
+			resp.Write([]byte(small))
+			resp.(http.Flusher).Flush()
+		}
+	}))
+
+	get := func(path string, gzipped bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		if gzipped {
+			req.Header.Set("Accept-Encoding", "deflate, gzip")
+		}
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, req)
+		return resp
+	}
+
+	gunzip := func(body *bytes.Buffer) string {
+		r, err := gzip.NewReader(body)
+		require.NoError(err)
+		out, err := ioutil.ReadAll(r)
+		require.NoError(err)
+		return string(out)
+	}
+
+	// Small responses aren't compressed
+	resp := get("/small", true)
+	require.Empty(resp.Header().Get("Content-Encoding"))
+	require.Equal("Accept-Encoding", resp.Header().Get("Vary"))
+	require.Equal(small, resp.Body.String())
+
+	// Large responses are compressed
+	resp = get("/large", true)
+	require.Equal(201, resp.Code)
+	require.Equal("gzip", resp.Header().Get("Content-Encoding"))
+	require.Equal(large, gunzip(resp.Body))
+
+	// Unless the client doesn't accept gzip
+	resp = get("/large", false)
+	require.Empty(resp.Header().Get("Content-Encoding"))
+	require.Equal(large, resp.Body.String())
+
+	// Flushed responses are compressed regardless of their size
+	resp = get("/flush", true)
+	require.Equal("gzip", resp.Header().Get("Content-Encoding"))
+	require.Equal(small, gunzip(resp.Body))
+}
+
+func TestAcceptsGzip(t *testing.T) {
+	t.Parallel()
+	cases := map[string]bool{
+		"":                  false,
+		"gzip":              true,
+		"deflate, gzip":     true,
+		"gzip;q=0.5":        true,
+		"gzip;q=0, deflate": false,
+		"identity":          false,
+	}
+	for header, accepts := range cases {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("Accept-Encoding", header)
+		require.Equal(t, accepts, acceptsGzip(req), header)
+	}
+}
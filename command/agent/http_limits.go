@@ -0,0 +1,220 @@
+package agent
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// limiterIdleTimeout is how long the rate limiter of a client or token
+	// is kept after its last request.
+	limiterIdleTimeout = 5 * time.Minute
+
+	// limiterPurgeInterval is how often the idle rate limiters are removed.
+	limiterPurgeInterval = time.Minute
+
+	// connLimitWriteTimeout bounds the time spent writing the response to
+	// the connections rejected for exceeding the connection limits.
+	connLimitWriteTimeout = 100 * time.Millisecond
+)
+
+// connLimitResponse is written to the connections rejected for exceeding the
+// connection limits before closing them.
+var connLimitResponse = []byte("HTTP/1.1 429 Too Many Requests\r\n" +
+	"Content-Type: text/plain\r\n" +
+	"Content-Length: 20\r\n" +
+	"Connection: close\r\n\r\n" +
+	"Too many connections")
+
+// connLimiter limits the number of concurrent connections of the HTTP server,
+// in total and per client IP address. It is used as the connection state hook
+// of the server.
+type connLimiter struct {
+	maxConns          int
+	maxConnsPerClient int
+
+	l       sync.Mutex
+	conns   int
+	clients map[string]int
+}
+
+// newConnLimiter returns a connection limiter, or nil if the limits are
+// disabled.
+func newConnLimiter(maxConns, maxConnsPerClient int) *connLimiter {
+	if maxConns <= 0 && maxConnsPerClient <= 0 {
+		return nil
+	}
+	return &connLimiter{
+		maxConns:          maxConns,
+		maxConnsPerClient: maxConnsPerClient,
+		clients:           make(map[string]int),
+	}
+}
+
+// connState tracks the connections of the server, and rejects the new
+// connections exceeding the limits.
+func (c *connLimiter) connState(conn net.Conn, state http.ConnState) {
+	switch state {
+	case http.StateNew:
+		if c.acquire(clientIP(conn.RemoteAddr().String())) {
+			return
+		}
+		metrics.IncrCounter([]string{"nomad", "http", "conn_limited"}, 1)
+		conn.SetWriteDeadline(time.Now().Add(connLimitWriteTimeout))
+		conn.Write(connLimitResponse)
+		conn.Close()
+	case http.StateHijacked, http.StateClosed:
+		c.release(clientIP(conn.RemoteAddr().String()))
+	}
+}
+
+// acquire counts a new connection of the client and returns whether it is
+// within the limits. Connections are counted even if rejected, since they
+// are released once closed.
+func (c *connLimiter) acquire(client string) bool {
+	c.l.Lock()
+	defer c.l.Unlock()
+
+	c.conns++
+	c.clients[client]++
+
+	if c.maxConns > 0 && c.conns > c.maxConns {
+		return false
+	}
+	if c.maxConnsPerClient > 0 && c.clients[client] > c.maxConnsPerClient {
+		return false
+	}
+	return true
+}
+
+// release stops counting a connection of the client.
+func (c *connLimiter) release(client string) {
+	c.l.Lock()
+	defer c.l.Unlock()
+
+	c.conns--
+	if c.clients[client] <= 1 {
+		delete(c.clients, client)
+	} else {
+		c.clients[client]--
+	}
+}
+
+// rateLimiter limits the rate of the requests made by each key, such as a
+// client IP address or an ACL token, using a token bucket per key.
+type rateLimiter struct {
+	limit rate.Limit
+	burst int
+
+	l         sync.Mutex
+	limiters  map[string]*keyLimiter
+	lastPurge time.Time
+}
+
+// keyLimiter is the token bucket of a key.
+type keyLimiter struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// newRateLimiter returns a rate limiter allowing limit requests per second
+// with bursts of burst requests, or nil if the limit is disabled. The burst
+// defaults to the limit.
+func newRateLimiter(limit float64, burst int) *rateLimiter {
+	if limit <= 0 {
+		return nil
+	}
+	if burst <= 0 {
+		burst = int(math.Ceil(limit))
+	}
+	return &rateLimiter{
+		limit:     rate.Limit(limit),
+		burst:     burst,
+		limiters:  make(map[string]*keyLimiter),
+		lastPurge: time.Now(),
+	}
+}
+
+// allow returns whether a request of the key is within the rate limit.
+func (r *rateLimiter) allow(key string) bool {
+	r.l.Lock()
+	defer r.l.Unlock()
+
+	now := time.Now()
+	if now.Sub(r.lastPurge) > limiterPurgeInterval {
+		for k, l := range r.limiters {
+			if now.Sub(l.lastSeen) > limiterIdleTimeout {
+				delete(r.limiters, k)
+			}
+		}
+		r.lastPurge = now
+	}
+
+	l, ok := r.limiters[key]
+	if !ok {
+		l = &keyLimiter{limiter: rate.NewLimiter(r.limit, r.burst)}
+		r.limiters[key] = l
+	}
+	l.lastSeen = now
+	return l.limiter.AllowN(now, 1)
+}
+
+// limitHandler wraps the handler to reject with a 429 status code the
+// requests exceeding the rate limits of their client IP address or ACL
+// token. Requests whose token can't be resolved to a known token, such as
+// anonymous requests, share the token rate limit of their client IP address.
+func (s *HTTPServer) limitHandler(h http.Handler) http.Handler {
+	if s.clientLimiter == nil && s.tokenLimiter == nil {
+		return h
+	}
+
+	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		limit := ""
+		if s.clientLimiter != nil && !s.clientLimiter.allow(clientIP(req.RemoteAddr)) {
+			limit = "client"
+		} else if s.tokenLimiter != nil && !s.tokenLimiter.allow(s.tokenLimitKey(req)) {
+			limit = "token"
+		}
+
+		if limit != "" {
+			metrics.IncrCounterWithLabels([]string{"nomad", "http", "rate_limited"}, 1,
+				[]metrics.Label{{Name: "limit", Value: limit}})
+			resp.Header().Set("Retry-After", "1")
+			resp.WriteHeader(http.StatusTooManyRequests)
+			resp.Write([]byte("Too many requests"))
+			return
+		}
+
+		h.ServeHTTP(resp, req)
+	})
+}
+
+// tokenLimitKey returns the key of the token rate limit of the request: the
+// accessor ID of its token, or its client IP address if the token is unknown
+// or anonymous. Keying on the resolved tokens ensures made up tokens can't be
+// used to bypass the limit, nor to grow the limiters without bound.
+func (s *HTTPServer) tokenLimitKey(req *http.Request) string {
+	if s.resolveToken != nil {
+		token, err := s.resolveToken(req.Header.Get("X-Nomad-Token"))
+		if err == nil && token != nil && token.AccessorID != structs.AnonymousACLToken.AccessorID {
+			return "token:" + token.AccessorID
+		}
+	}
+	return "ip:" + clientIP(req.RemoteAddr)
+}
+
+// clientIP returns the IP address of the remote address of a connection.
+func clientIP(addr string) string {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	return host
+}
@@ -0,0 +1,122 @@
+package agent
+
+import (
+	"io/ioutil"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConnLimiter(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	c := newConnLimiter(3, 2)
+	require.True(c.acquire("1.1.1.1"))
+	require.True(c.acquire("1.1.1.1"))
+
+	// Over the limit of the client
+	require.False(c.acquire("1.1.1.1"))
+	c.release("1.1.1.1")
+
+	// Over the total limit
+	require.True(c.acquire("2.2.2.2"))
+	require.False(c.acquire("3.3.3.3"))
+	c.release("3.3.3.3")
+
+	c.release("1.1.1.1")
+	require.True(c.acquire("3.3.3.3"))
+
+	require.Nil(newConnLimiter(0, 0))
+}
+
+func TestRateLimiter(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	r := newRateLimiter(0.001, 2)
+	require.True(r.allow("a"))
+	require.True(r.allow("a"))
+	require.False(r.allow("a"))
+
+	// Keys are limited independently
+	require.True(r.allow("b"))
+
+	// The burst defaults to the limit
+	require.Equal(1, newRateLimiter(0.5, 0).burst)
+	require.Nil(newRateLimiter(0, 10))
+}
+
+func TestHTTP_RateLimit(t *testing.T) {
+	t.Parallel()
+	httpACLTest(t, func(c *Config) {
+		c.Limits = &Limits{
+			HTTPTokenRateLimit: 0.001,
+			HTTPTokenRateBurst: 1,
+		}
+	}, func(s *TestAgent) {
+		token := mock.ACLManagementToken()
+		token.AccessorID = ""
+		args := structs.ACLTokenUpsertRequest{
+			Tokens: []*structs.ACLToken{token},
+			WriteRequest: structs.WriteRequest{
+				Region:    "global",
+				AuthToken: s.RootToken.SecretID,
+			},
+		}
+		var out structs.ACLTokenUpsertResponse
+		require.NoError(t, s.Agent.RPC("ACL.UpsertTokens", &args, &out))
+
+		get := func(secretID string) int {
+			req, err := http.NewRequest("GET", s.HTTPAddr()+"/v1/agent/self", nil)
+			require.NoError(t, err)
+			req.Header.Set("X-Nomad-Token", secretID)
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			ioutil.ReadAll(resp.Body)
+			resp.Body.Close()
+			if resp.StatusCode == 429 {
+				require.Equal(t, "1", resp.Header.Get("Retry-After"))
+			}
+			return resp.StatusCode
+		}
+
+		require.NotEqual(t, 429, get(s.RootToken.SecretID))
+		require.Equal(t, 429, get(s.RootToken.SecretID))
+
+		// Other tokens have their own limit
+		require.NotEqual(t, 429, get(out.Tokens[0].SecretID))
+
+		// Unknown tokens share the limit of the client
+		require.NotEqual(t, 429, get("foo"))
+		require.Equal(t, 429, get("bar"))
+		require.Equal(t, 429, get(""))
+	})
+}
+
+func TestHTTP_ConnLimit(t *testing.T) {
+	t.Parallel()
+	s := makeHTTPServer(t, func(c *Config) {
+		c.Limits = &Limits{
+			HTTPMaxConnsPerClient: 1,
+		}
+	})
+	defer s.Shutdown()
+
+	// Hold a connection open
+	conn, err := net.Dial("tcp", s.Server.Addr)
+	require.NoError(t, err)
+	defer conn.Close()
+	conn.Write([]byte("GET /v1/agent/self HTTP/1.1\r\nHost: localhost\r\n\r\n"))
+
+	// Further connections of the client are rejected
+	client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+	resp, err := client.Get(s.HTTPAddr() + "/v1/agent/self")
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, 429, resp.StatusCode)
+}
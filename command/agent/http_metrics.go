@@ -0,0 +1,98 @@
+package agent
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+)
+
+// metricsHandler wraps the handler to emit the latency and status code
+// metrics of the requests of each endpoint family, which is the pattern of the
+// handler of the mux serving them.
+func (s *HTTPServer) metricsHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		start := time.Now()
+		rw := &statusResponseWriter{ResponseWriter: resp}
+		h.ServeHTTP(rw, req)
+
+		labels := []metrics.Label{
+			{Name: "endpoint", Value: s.endpointFamily(req)},
+			{Name: "method", Value: metricsMethod(req.Method)},
+		}
+		metrics.MeasureSinceWithLabels([]string{"nomad", "http", "request"}, start, labels)
+
+		code := rw.statusCode()
+		labels = append(labels, metrics.Label{Name: "code_class", Value: fmt.Sprintf("%dxx", code/100)})
+		metrics.IncrCounterWithLabels([]string{"nomad", "http", "response"}, 1, labels)
+	})
+}
+
+// endpointFamily returns the pattern of the handler of the mux serving the
+// request, such as "/v1/job/", or "unmatched" if there is none.
+func (s *HTTPServer) endpointFamily(req *http.Request) string {
+	if _, pattern := s.mux.Handler(req); pattern != "" {
+		return pattern
+	}
+	return "unmatched"
+}
+
+// metricsMethod returns the method label of a request, bounding the values
+// of the label to the methods of the API.
+func metricsMethod(method string) string {
+	switch method {
+	case "GET", "HEAD", "PUT", "POST", "DELETE", "OPTIONS":
+		return method
+	default:
+		return "OTHER"
+	}
+}
+
+// statusResponseWriter records the status code of a response.
+type statusResponseWriter struct {
+	http.ResponseWriter
+	code int
+}
+
+func (w *statusResponseWriter) WriteHeader(code int) {
+	if w.code == 0 {
+		w.code = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *statusResponseWriter) Write(b []byte) (int, error) {
+	if w.code == 0 {
+		w.code = http.StatusOK
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *statusResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack hijacks the connection of the response, which is recorded as
+// switching protocols.
+func (w *statusResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if h, ok := w.ResponseWriter.(http.Hijacker); ok {
+		if w.code == 0 {
+			w.code = http.StatusSwitchingProtocols
+		}
+		return h.Hijack()
+	}
+	return nil, nil, fmt.Errorf("response doesn't support hijacking")
+}
+
+// statusCode returns the status code of the response, which defaults to 200.
+func (w *statusResponseWriter) statusCode() int {
+	if w.code == 0 {
+		return http.StatusOK
+	}
+	return w.code
+}
@@ -0,0 +1,80 @@
+package agent
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTP_EndpointFamily(t *testing.T) {
+	t.Parallel()
+	s := makeHTTPServer(t, nil)
+	defer s.Shutdown()
+
+	cases := map[string]string{
+		"/v1/jobs":                    "/v1/jobs",
+		"/v1/job/example/allocations": "/v1/job/",
+		"/v1/agent/health":            "/v1/agent/health",
+		"/v2/unknown":                 "/",
+	}
+	for path, family := range cases {
+		req := httptest.NewRequest("GET", path, nil)
+		require.Equal(t, family, s.Server.endpointFamily(req), path)
+	}
+
+	require.Equal(t, "PUT", metricsMethod("PUT"))
+	require.Equal(t, "OTHER", metricsMethod("PROPFIND"))
+}
+
+func TestStatusResponseWriter(t *testing.T) {
+	t.Parallel()
+
+	resp := httptest.NewRecorder()
+	w := &statusResponseWriter{ResponseWriter: resp}
+	require.Equal(t, 200, w.statusCode())
+
+	w.WriteHeader(404)
+	w.WriteHeader(500)
+	w.Write([]byte("missing"))
+	require.Equal(t, 404, w.statusCode())
+	require.Equal(t, 404, resp.Code)
+}
+
+// TestHTTP_EndpointMetrics isn't parallel since the metrics are global.
+func TestHTTP_EndpointMetrics(t *testing.T) {
+	s := makeHTTPServer(t, nil)
+	defer s.Shutdown()
+
+	for _, path := range []string{"/v1/jobs", "/v1/job/missing", "/v1/job/missing"} {
+		resp, err := http.Get(s.HTTPAddr() + path)
+		require.NoError(t, err)
+		ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+	}
+
+	var timer, ok, notFound bool
+	for _, interval := range s.Agent.InmemSink.Data() {
+		interval.RLock()
+		for name, sample := range interval.Samples {
+			if strings.Contains(name, "nomad.http.request;endpoint=/v1/job/;method=GET") {
+				timer = sample.Count == 2
+			}
+		}
+		for name, counter := range interval.Counters {
+			switch {
+			case strings.Contains(name, "nomad.http.response;endpoint=/v1/jobs;method=GET;code_class=2xx"):
+				ok = counter.Count == 1
+			case strings.Contains(name, "nomad.http.response;endpoint=/v1/job/;method=GET;code_class=4xx"):
+				notFound = counter.Count == 2
+			}
+		}
+		interval.RUnlock()
+	}
+	require.True(t, timer, "request latency")
+	require.True(t, ok, "2xx responses")
+	require.True(t, notFound, "4xx responses")
+}
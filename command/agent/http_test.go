@@ -122,6 +122,18 @@ func TestSetMeta(t *testing.T) {
 	if header != "123" {
 		t.Fatalf("Bad: %v", header)
 	}
+	header = resp.Header().Get("X-Nomad-NextToken")
+	if header != "" {
+		t.Fatalf("Bad: %v", header)
+	}
+
+	meta.NextToken = "foo"
+	resp = httptest.NewRecorder()
+	setMeta(resp, &meta)
+	header = resp.Header().Get("X-Nomad-NextToken")
+	if header != "foo" {
+		t.Fatalf("Bad: %v", header)
+	}
 }
 
 func TestSetHeaders(t *testing.T) {
@@ -219,6 +231,42 @@ func testPrettyPrint(pretty string, prettyFmt bool, t *testing.T) {
 	}
 }
 
+func TestHTTP_NotModified(t *testing.T) {
+	t.Parallel()
+	s := makeHTTPServer(t, nil)
+	defer s.Shutdown()
+
+	index := uint64(10)
+	handler := func(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+		setIndex(resp, index)
+		return &structs.Job{Name: "foo"}, nil
+	}
+
+	// The first response sets the ETag
+	resp := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/v1/job/foo", nil)
+	s.Server.wrap(handler)(resp, req)
+	assert.Equal(t, 200, resp.Code)
+	assert.Equal(t, "no-cache", resp.Header().Get("Cache-Control"))
+	etag := resp.Header().Get("ETag")
+	assert.NotEmpty(t, etag)
+
+	// Requests with the ETag aren't sent the unchanged response again
+	resp = httptest.NewRecorder()
+	req.Header.Set("If-None-Match", etag)
+	s.Server.wrap(handler)(resp, req)
+	assert.Equal(t, 304, resp.Code)
+	assert.Empty(t, resp.Body.String())
+
+	// Changed responses are sent
+	index = 11
+	resp = httptest.NewRecorder()
+	s.Server.wrap(handler)(resp, req)
+	assert.Equal(t, 200, resp.Code)
+	assert.NotEqual(t, etag, resp.Header().Get("ETag"))
+	assert.NotEmpty(t, resp.Body.String())
+}
+
 func TestPermissionDenied(t *testing.T) {
 	s := makeHTTPServer(t, func(c *Config) {
 		c.ACL.Enabled = true
@@ -329,6 +377,40 @@ func TestParseWait_InvalidIndex(t *testing.T) {
 	}
 }
 
+func TestParsePagination(t *testing.T) {
+	t.Parallel()
+	resp := httptest.NewRecorder()
+	var b structs.QueryOptions
+
+	req, err := http.NewRequest("GET",
+		"/v1/jobs?per_page=10&next_token=foo&filter=Status+%3D%3D+%22running%22", nil)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	if d := parsePagination(resp, req, &b); d {
+		t.Fatalf("unexpected done")
+	}
+
+	if b.PerPage != 10 || b.NextToken != "foo" || b.Filter != `Status == "running"` {
+		t.Fatalf("Bad: %v", b)
+	}
+
+	resp = httptest.NewRecorder()
+	req, err = http.NewRequest("GET", "/v1/jobs?per_page=-1", nil)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	if d := parsePagination(resp, req, &b); !d {
+		t.Fatalf("expected done")
+	}
+
+	if resp.Code != 400 {
+		t.Fatalf("bad code: %v", resp.Code)
+	}
+}
+
 func TestParseConsistency(t *testing.T) {
 	t.Parallel()
 	var b structs.QueryOptions
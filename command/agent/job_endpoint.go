@@ -9,6 +9,7 @@ import (
 	"github.com/golang/snappy"
 	"github.com/hashicorp/nomad/api"
 	"github.com/hashicorp/nomad/jobspec"
+	"github.com/hashicorp/nomad/jobspec2"
 	"github.com/hashicorp/nomad/nomad/structs"
 )
 
@@ -62,6 +63,9 @@ func (s *HTTPServer) JobSpecificRequest(resp http.ResponseWriter, req *http.Requ
 	case strings.HasSuffix(path, "/summary"):
 		jobName := strings.TrimSuffix(path, "/summary")
 		return s.jobSummaryRequest(resp, req, jobName)
+	case strings.HasSuffix(path, "/dispatch/batch"):
+		jobName := strings.TrimSuffix(path, "/dispatch/batch")
+		return s.jobDispatchBatchRequest(resp, req, jobName)
 	case strings.HasSuffix(path, "/dispatch"):
 		jobName := strings.TrimSuffix(path, "/dispatch")
 		return s.jobDispatchRequest(resp, req, jobName)
@@ -80,6 +84,9 @@ func (s *HTTPServer) JobSpecificRequest(resp http.ResponseWriter, req *http.Requ
 	case strings.HasSuffix(path, "/stable"):
 		jobName := strings.TrimSuffix(path, "/stable")
 		return s.jobStable(resp, req, jobName)
+	case strings.HasSuffix(path, "/scale"):
+		jobName := strings.TrimSuffix(path, "/scale")
+		return s.jobScale(resp, req, jobName)
 	default:
 		return s.jobCRUD(resp, req, path)
 	}
@@ -562,6 +569,84 @@ func (s *HTTPServer) jobDispatchRequest(resp http.ResponseWriter, req *http.Requ
 	return out, nil
 }
 
+func (s *HTTPServer) jobDispatchBatchRequest(resp http.ResponseWriter, req *http.Request, name string) (interface{}, error) {
+	if req.Method != "PUT" && req.Method != "POST" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+	args := structs.JobBatchDispatchRequest{}
+	if err := decodeBody(req, &args); err != nil {
+		return nil, CodedError(400, err.Error())
+	}
+	if args.JobID != "" && args.JobID != name {
+		return nil, CodedError(400, "Job ID does not match")
+	}
+	if args.JobID == "" {
+		args.JobID = name
+	}
+
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.JobBatchDispatchResponse
+	if err := s.agent.RPC("Job.DispatchBatch", &args, &out); err != nil {
+		return nil, err
+	}
+	setIndex(resp, out.Index)
+	return out, nil
+}
+
+func (s *HTTPServer) jobScale(resp http.ResponseWriter, req *http.Request, name string) (interface{}, error) {
+	switch req.Method {
+	case "GET":
+		return s.jobScaleStatus(resp, req, name)
+	case "PUT", "POST":
+		return s.jobScaleAction(resp, req, name)
+	default:
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+}
+
+func (s *HTTPServer) jobScaleStatus(resp http.ResponseWriter, req *http.Request, name string) (interface{}, error) {
+	args := structs.JobScaleStatusRequest{
+		JobID: name,
+	}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.JobScaleStatusResponse
+	if err := s.agent.RPC("Job.ScaleStatus", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.JobScaleStatus == nil {
+		return nil, CodedError(404, "job not found")
+	}
+	return out.JobScaleStatus, nil
+}
+
+func (s *HTTPServer) jobScaleAction(resp http.ResponseWriter, req *http.Request, name string) (interface{}, error) {
+	args := structs.JobScaleRequest{}
+	if err := decodeBody(req, &args); err != nil {
+		return nil, CodedError(400, err.Error())
+	}
+	if args.JobID != "" && args.JobID != name {
+		return nil, CodedError(400, "Job ID does not match")
+	}
+	if args.JobID == "" {
+		args.JobID = name
+	}
+
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.JobRegisterResponse
+	if err := s.agent.RPC("Job.Scale", &args, &out); err != nil {
+		return nil, err
+	}
+	setIndex(resp, out.Index)
+	return out, nil
+}
+
 // JobsParseRequest parses a hcl jobspec and returns a api.Job
 func (s *HTTPServer) JobsParseRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
 	if req.Method != http.MethodPut && req.Method != http.MethodPost {
@@ -576,8 +661,18 @@ func (s *HTTPServer) JobsParseRequest(resp http.ResponseWriter, req *http.Reques
 		return nil, CodedError(400, "Job spec is empty")
 	}
 
-	jobfile := strings.NewReader(args.JobHCL)
-	jobStruct, err := jobspec.Parse(jobfile)
+	var jobStruct *api.Job
+	var err error
+	if args.HCLv1 {
+		jobStruct, err = jobspec.Parse(strings.NewReader(args.JobHCL))
+	} else {
+		jobStruct, err = jobspec2.ParseWithConfig(&jobspec2.ParseConfig{
+			Path:       "input.hcl",
+			Body:       []byte(args.JobHCL),
+			AllowFS:    false,
+			VarContent: args.Variables,
+		})
+	}
 	if err != nil {
 		return nil, CodedError(400, err.Error())
 	}
@@ -602,6 +697,7 @@ func ApiJobToStructJob(job *api.Job) *structs.Job {
 		Priority:    *job.Priority,
 		AllAtOnce:   *job.AllAtOnce,
 		Datacenters: job.Datacenters,
+		NodePool:    *job.NodePool,
 		Payload:     job.Payload,
 		Meta:        job.Meta,
 		VaultToken:  *job.VaultToken,
@@ -649,6 +745,33 @@ func ApiJobToStructJob(job *api.Job) *structs.Job {
 		}
 	}
 
+	if job.Multiregion != nil {
+		j.Multiregion = &structs.Multiregion{}
+		if s := job.Multiregion.Strategy; s != nil {
+			j.Multiregion.Strategy = &structs.MultiregionStrategy{
+				MaxParallel: s.MaxParallel,
+				OnFailure:   s.OnFailure,
+			}
+		}
+		j.Multiregion.Regions = make([]*structs.MultiregionRegion, len(job.Multiregion.Regions))
+		for i, r := range job.Multiregion.Regions {
+			j.Multiregion.Regions[i] = &structs.MultiregionRegion{
+				Name:        r.Name,
+				Count:       r.Count,
+				Datacenters: r.Datacenters,
+				Meta:        r.Meta,
+			}
+		}
+	}
+
+	if job.GC != nil {
+		j.GC = &structs.GCConfig{
+			JobThreshold:   job.GC.JobThreshold,
+			EvalThreshold:  job.GC.EvalThreshold,
+			AllocThreshold: job.GC.AllocThreshold,
+		}
+	}
+
 	if l := len(job.TaskGroups); l != 0 {
 		j.TaskGroups = make([]*structs.TaskGroup, l)
 		for i, taskGroup := range job.TaskGroups {
@@ -701,6 +824,34 @@ func ApiTgToStructsTG(taskGroup *api.TaskGroup, tg *structs.TaskGroup) {
 		Migrate: *taskGroup.EphemeralDisk.Migrate,
 	}
 
+	if taskGroup.MaxClientDisconnect != nil {
+		maxDisconnect := *taskGroup.MaxClientDisconnect
+		tg.MaxClientDisconnect = &maxDisconnect
+	}
+
+	if l := len(taskGroup.Volumes); l != 0 {
+		tg.Volumes = make(map[string]*structs.VolumeRequest, l)
+		for name, volume := range taskGroup.Volumes {
+			tg.Volumes[name] = &structs.VolumeRequest{
+				Name:     volume.Name,
+				Type:     volume.Type,
+				Source:   volume.Source,
+				ReadOnly: volume.ReadOnly,
+			}
+		}
+	}
+
+	if l := len(taskGroup.Networks); l != 0 {
+		tg.Networks = make(structs.Networks, l)
+		for i, nw := range taskGroup.Networks {
+			tg.Networks[i] = &structs.NetworkResource{
+				Mode:          nw.Mode,
+				DynamicPorts:  apiPortsToStructs(nw.DynamicPorts),
+				ReservedPorts: apiPortsToStructs(nw.ReservedPorts),
+			}
+		}
+	}
+
 	if l := len(taskGroup.Spreads); l != 0 {
 		tg.Spreads = make([]*structs.Spread, l)
 		for k, spread := range taskGroup.Spreads {
@@ -710,14 +861,16 @@ func ApiTgToStructsTG(taskGroup *api.TaskGroup, tg *structs.TaskGroup) {
 
 	if taskGroup.Update != nil {
 		tg.Update = &structs.UpdateStrategy{
-			Stagger:          *taskGroup.Update.Stagger,
-			MaxParallel:      *taskGroup.Update.MaxParallel,
-			HealthCheck:      *taskGroup.Update.HealthCheck,
-			MinHealthyTime:   *taskGroup.Update.MinHealthyTime,
-			HealthyDeadline:  *taskGroup.Update.HealthyDeadline,
-			ProgressDeadline: *taskGroup.Update.ProgressDeadline,
-			AutoRevert:       *taskGroup.Update.AutoRevert,
-			Canary:           *taskGroup.Update.Canary,
+			Stagger:            *taskGroup.Update.Stagger,
+			MaxParallel:        *taskGroup.Update.MaxParallel,
+			MaxParallelPercent: *taskGroup.Update.MaxParallelPercent,
+			HealthCheck:        *taskGroup.Update.HealthCheck,
+			MinHealthyTime:     *taskGroup.Update.MinHealthyTime,
+			HealthyDeadline:    *taskGroup.Update.HealthyDeadline,
+			ProgressDeadline:   *taskGroup.Update.ProgressDeadline,
+			AutoRevert:         *taskGroup.Update.AutoRevert,
+			AutoPromote:        *taskGroup.Update.AutoPromote,
+			Canary:             *taskGroup.Update.Canary,
 		}
 	}
 
@@ -738,6 +891,7 @@ func ApiTaskToStructsTask(apiTask *api.Task, structsTask *structs.Task) {
 	structsTask.Driver = apiTask.Driver
 	structsTask.User = apiTask.User
 	structsTask.Leader = apiTask.Leader
+	structsTask.Checkpoint = apiTask.Checkpoint
 	structsTask.Config = apiTask.Config
 	structsTask.Env = apiTask.Env
 	structsTask.Meta = apiTask.Meta
@@ -756,6 +910,7 @@ func ApiTaskToStructsTask(apiTask *api.Task, structsTask *structs.Task) {
 				Tags:        service.Tags,
 				CanaryTags:  service.CanaryTags,
 				AddressMode: service.AddressMode,
+				Provider:    service.Provider,
 			}
 
 			if l := len(service.Checks); l != 0 {
@@ -838,6 +993,17 @@ func ApiTaskToStructsTask(apiTask *api.Task, structsTask *structs.Task) {
 		}
 	}
 
+	if l := len(apiTask.Actions); l != 0 {
+		structsTask.Actions = make([]*structs.Action, l)
+		for i, action := range apiTask.Actions {
+			structsTask.Actions[i] = &structs.Action{
+				Name:    action.Name,
+				Command: action.Command,
+				Args:    action.Args,
+			}
+		}
+	}
+
 	if apiTask.DispatchPayload != nil {
 		structsTask.DispatchPayload = &structs.DispatchPayloadConfig{
 			File: apiTask.DispatchPayload.File,
@@ -863,26 +1029,9 @@ func ApiResourcesToStructs(in *api.Resources) *structs.Resources {
 				CIDR:  nw.CIDR,
 				IP:    nw.IP,
 				MBits: *nw.MBits,
-			}
 
-			if l := len(nw.DynamicPorts); l != 0 {
-				out.Networks[i].DynamicPorts = make([]structs.Port, l)
-				for j, dp := range nw.DynamicPorts {
-					out.Networks[i].DynamicPorts[j] = structs.Port{
-						Label: dp.Label,
-						Value: dp.Value,
-					}
-				}
-			}
-
-			if l := len(nw.ReservedPorts); l != 0 {
-				out.Networks[i].ReservedPorts = make([]structs.Port, l)
-				for j, rp := range nw.ReservedPorts {
-					out.Networks[i].ReservedPorts[j] = structs.Port{
-						Label: rp.Label,
-						Value: rp.Value,
-					}
-				}
+				DynamicPorts:  apiPortsToStructs(nw.DynamicPorts),
+				ReservedPorts: apiPortsToStructs(nw.ReservedPorts),
 			}
 		}
 	}
@@ -895,10 +1044,63 @@ func ApiResourcesToStructs(in *api.Resources) *structs.Resources {
 				Count:       *d.Count,
 				Constraints: ApiConstraintsToStructs(d.Constraints),
 				Affinities:  ApiAffinitiesToStructs(d.Affinities),
+				Shared:      d.Shared,
 			}
 		}
 	}
 
+	if in.NUMA != nil {
+		out.NUMA = &structs.NUMA{
+			Affinity: *in.NUMA.Affinity,
+		}
+	}
+
+	if in.IOWeight != nil {
+		out.IOWeight = *in.IOWeight
+	}
+
+	if in.CPUHardLimit != nil {
+		out.CPUHardLimit = *in.CPUHardLimit
+	}
+
+	if in.CPUCFSPeriod != nil {
+		out.CPUCFSPeriod = *in.CPUCFSPeriod
+	}
+
+	if in.Cores != nil {
+		out.Cores = *in.Cores
+	}
+
+	if l := len(in.IOLimits); l != 0 {
+		out.IOLimits = make([]*structs.IOLimit, l)
+		for i, lim := range in.IOLimits {
+			out.IOLimits[i] = &structs.IOLimit{
+				Device:    lim.Device,
+				ReadBps:   lim.ReadBps,
+				WriteBps:  lim.WriteBps,
+				ReadIOps:  lim.ReadIOps,
+				WriteIOps: lim.WriteIOps,
+			}
+		}
+	}
+
+	return out
+}
+
+func apiPortsToStructs(in []api.Port) []structs.Port {
+	if len(in) == 0 {
+		return nil
+	}
+
+	out := make([]structs.Port, len(in))
+	for i, p := range in {
+		out[i] = structs.Port{
+			Label:       p.Label,
+			Value:       p.Value,
+			To:          p.To,
+			HostNetwork: p.HostNetwork,
+		}
+	}
 	return out
 }
 
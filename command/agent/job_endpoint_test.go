@@ -15,6 +15,7 @@ import (
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/kr/pretty"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestHTTP_JobsList(t *testing.T) {
@@ -304,6 +305,53 @@ func TestHTTP_JobsParse(t *testing.T) {
 		}
 	})
 }
+
+func TestHTTP_JobsParse_Variables(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		jobHCL := `
+variable "datacenter" {
+  type = string
+}
+
+job "example" {
+  datacenters = [var.datacenter]
+
+  group "web" {
+    task "web" {
+      driver = "exec"
+    }
+  }
+}
+`
+		buf := encodeReq(api.JobsParseRequest{
+			JobHCL:    jobHCL,
+			Variables: "datacenter = \"dc2\"\n",
+		})
+		req, err := http.NewRequest("POST", "/v1/jobs/parse", buf)
+		require.NoError(err)
+		respW := httptest.NewRecorder()
+
+		obj, err := s.Server.JobsParseRequest(respW, req)
+		require.NoError(err)
+		job := obj.(*api.Job)
+		require.Equal([]string{"dc2"}, job.Datacenters)
+
+		// The HCLv1 parser does not support variables
+		buf = encodeReq(api.JobsParseRequest{
+			JobHCL: jobHCL,
+			HCLv1:  true,
+		})
+		req, err = http.NewRequest("POST", "/v1/jobs/parse", buf)
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+
+		_, err = s.Server.JobsParseRequest(respW, req)
+		require.Error(err)
+	})
+}
+
 func TestHTTP_JobQuery(t *testing.T) {
 	t.Parallel()
 	httpTest(t, nil, func(s *TestAgent) {
@@ -1075,6 +1123,129 @@ func TestHTTP_JobDispatch(t *testing.T) {
 	})
 }
 
+func TestHTTP_JobDispatchBatch(t *testing.T) {
+	t.Parallel()
+	httpTest(t, nil, func(s *TestAgent) {
+		// Create the parameterized job
+		job := mock.BatchJob()
+		job.ParameterizedJob = &structs.ParameterizedJobConfig{}
+
+		args := structs.JobRegisterRequest{
+			Job: job,
+			WriteRequest: structs.WriteRequest{
+				Region:    "global",
+				Namespace: structs.DefaultNamespace,
+			},
+		}
+		var resp structs.JobRegisterResponse
+		if err := s.Agent.RPC("Job.Register", &args, &resp); err != nil {
+			t.Fatalf("err: %v", err)
+		}
+
+		// Make the request
+		respW := httptest.NewRecorder()
+		args2 := structs.JobBatchDispatchRequest{
+			Dispatches: []*structs.JobDispatchItem{
+				{IdempotencyToken: "foo"},
+				{IdempotencyToken: "bar"},
+			},
+			WriteRequest: structs.WriteRequest{
+				Region:    "global",
+				Namespace: structs.DefaultNamespace,
+			},
+		}
+		buf := encodeReq(args2)
+
+		// Make the HTTP request
+		req2, err := http.NewRequest("PUT", "/v1/job/"+job.ID+"/dispatch/batch", buf)
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		respW.Flush()
+
+		// Make the request
+		obj, err := s.Server.JobSpecificRequest(respW, req2)
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+
+		// Check the response
+		dispatch := obj.(structs.JobBatchDispatchResponse)
+		if len(dispatch.Dispatches) != 2 {
+			t.Fatalf("bad: %v", dispatch)
+		}
+		for _, d := range dispatch.Dispatches {
+			if d.EvalID == "" || d.DispatchedJobID == "" {
+				t.Fatalf("bad: %v", d)
+			}
+		}
+		if respW.HeaderMap.Get("X-Nomad-Index") == "" {
+			t.Fatalf("missing index")
+		}
+	})
+}
+
+func TestHTTP_JobScale(t *testing.T) {
+	t.Parallel()
+	httpTest(t, nil, func(s *TestAgent) {
+		require := require.New(t)
+
+		// Create the job
+		job := mock.Job()
+		args := structs.JobRegisterRequest{
+			Job: job,
+			WriteRequest: structs.WriteRequest{
+				Region:    "global",
+				Namespace: structs.DefaultNamespace,
+			},
+		}
+		var resp structs.JobRegisterResponse
+		require.NoError(s.Agent.RPC("Job.Register", &args, &resp))
+
+		// Scale the task group
+		scale := structs.JobScaleRequest{
+			Target:  map[string]string{structs.ScalingTargetGroup: job.TaskGroups[0].Name},
+			Count:   helper.Int64ToPtr(4),
+			Message: "scaling up",
+		}
+		req, err := http.NewRequest("PUT", "/v1/job/"+job.ID+"/scale", encodeReq(scale))
+		require.NoError(err)
+		respW := httptest.NewRecorder()
+		obj, err := s.Server.JobSpecificRequest(respW, req)
+		require.NoError(err)
+		scaleResp := obj.(structs.JobRegisterResponse)
+		require.NotEmpty(scaleResp.EvalID)
+		require.NotEmpty(respW.HeaderMap.Get("X-Nomad-Index"))
+
+		// Query the scaling status
+		req, err = http.NewRequest("GET", "/v1/job/"+job.ID+"/scale", nil)
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+		obj, err = s.Server.JobSpecificRequest(respW, req)
+		require.NoError(err)
+		status := obj.(*structs.JobScaleStatus)
+		tgStatus := status.TaskGroups[job.TaskGroups[0].Name]
+		require.Equal(4, tgStatus.Desired)
+		require.Len(tgStatus.Events, 1)
+		require.Equal("scaling up", tgStatus.Events[0].Message)
+
+		// The job ID of the body must match the path
+		scale.JobID = "other"
+		req, err = http.NewRequest("PUT", "/v1/job/"+job.ID+"/scale", encodeReq(scale))
+		require.NoError(err)
+		_, err = s.Server.JobSpecificRequest(httptest.NewRecorder(), req)
+		require.Error(err)
+		require.Contains(err.Error(), "Job ID does not match")
+
+		// Unknown jobs are not found
+		req, err = http.NewRequest("GET", "/v1/job/missing/scale", nil)
+		require.NoError(err)
+		_, err = s.Server.JobSpecificRequest(httptest.NewRecorder(), req)
+		require.Error(err)
+		require.Contains(err.Error(), "job not found")
+	})
+}
+
 func TestHTTP_JobRevert(t *testing.T) {
 	t.Parallel()
 	httpTest(t, nil, func(s *TestAgent) {
@@ -1204,6 +1375,7 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 		Priority:    helper.IntToPtr(50),
 		AllAtOnce:   helper.BoolToPtr(true),
 		Datacenters: []string{"dc1", "dc2"},
+		NodePool:    helper.StringToPtr("gpu"),
 		Constraints: []*api.Constraint{
 			{
 				LTarget: "a",
@@ -1313,6 +1485,25 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 					Sticky:  helper.BoolToPtr(true),
 					Migrate: helper.BoolToPtr(true),
 				},
+				MaxClientDisconnect: helper.TimeToPtr(30 * time.Minute),
+				Volumes: map[string]*api.VolumeRequest{
+					"data": {
+						Name:   "data",
+						Type:   "host",
+						Source: "shared_data",
+					},
+				},
+				Networks: []*api.NetworkResource{
+					{
+						Mode: "bridge",
+						DynamicPorts: []api.Port{
+							{
+								Label: "http",
+								To:    8080,
+							},
+						},
+					},
+				},
 				Update: &api.UpdateStrategy{
 					HealthCheck:      helper.StringToPtr(structs.UpdateStrategyHealthCheck_Checks),
 					MinHealthyTime:   helper.TimeToPtr(2 * time.Minute),
@@ -1398,6 +1589,19 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 						Resources: &api.Resources{
 							CPU:      helper.IntToPtr(100),
 							MemoryMB: helper.IntToPtr(10),
+							NUMA: &api.NUMAResource{
+								Affinity: helper.StringToPtr("require"),
+							},
+							IOWeight: helper.IntToPtr(500),
+							IOLimits: []*api.IOLimit{
+								{
+									Device:  "/dev/sda",
+									ReadBps: 1024,
+								},
+							},
+							CPUHardLimit: helper.BoolToPtr(true),
+							CPUCFSPeriod: helper.IntToPtr(500000),
+							Cores:        helper.IntToPtr(2),
 							Networks: []*api.NetworkResource{
 								{
 									IP:    "10.10.11.1",
@@ -1451,6 +1655,13 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 							MaxFiles:      helper.IntToPtr(10),
 							MaxFileSizeMB: helper.IntToPtr(100),
 						},
+						Actions: []*api.Action{
+							{
+								Name:    "flush",
+								Command: "redis-cli",
+								Args:    []string{"FLUSHALL"},
+							},
+						},
 						Artifacts: []*api.TaskArtifact{
 							{
 								GetterSource: helper.StringToPtr("source"),
@@ -1509,6 +1720,7 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 		Priority:    50,
 		AllAtOnce:   true,
 		Datacenters: []string{"dc1", "dc2"},
+		NodePool:    "gpu",
 		Constraints: []*structs.Constraint{
 			{
 				LTarget: "a",
@@ -1612,6 +1824,25 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 					Sticky:  true,
 					Migrate: true,
 				},
+				MaxClientDisconnect: helper.TimeToPtr(30 * time.Minute),
+				Volumes: map[string]*structs.VolumeRequest{
+					"data": {
+						Name:   "data",
+						Type:   "host",
+						Source: "shared_data",
+					},
+				},
+				Networks: structs.Networks{
+					{
+						Mode: "bridge",
+						DynamicPorts: []structs.Port{
+							{
+								Label: "http",
+								To:    8080,
+							},
+						},
+					},
+				},
 				Update: &structs.UpdateStrategy{
 					Stagger:          1 * time.Second,
 					MaxParallel:      5,
@@ -1659,6 +1890,7 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 								CanaryTags:  []string{"3", "4"},
 								PortLabel:   "foo",
 								AddressMode: "auto",
+								Provider:    "consul",
 								Checks: []*structs.ServiceCheck{
 									{
 										Name:          "bar",
@@ -1697,6 +1929,19 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 						Resources: &structs.Resources{
 							CPU:      100,
 							MemoryMB: 10,
+							NUMA: &structs.NUMA{
+								Affinity: structs.NUMAAffinityRequire,
+							},
+							IOWeight: 500,
+							IOLimits: []*structs.IOLimit{
+								{
+									Device:  "/dev/sda",
+									ReadBps: 1024,
+								},
+							},
+							CPUHardLimit: true,
+							CPUCFSPeriod: 500000,
+							Cores:        2,
 							Networks: []*structs.NetworkResource{
 								{
 									IP:    "10.10.11.1",
@@ -1750,6 +1995,13 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 							MaxFiles:      10,
 							MaxFileSizeMB: 100,
 						},
+						Actions: []*structs.Action{
+							{
+								Name:    "flush",
+								Command: "redis-cli",
+								Args:    []string{"FLUSHALL"},
+							},
+						},
 						Artifacts: []*structs.TaskArtifact{
 							{
 								GetterSource: "source",
@@ -1927,6 +2179,7 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 		Priority:    50,
 		AllAtOnce:   true,
 		Datacenters: []string{"dc1", "dc2"},
+		NodePool:    "default",
 		Constraints: []*structs.Constraint{
 			{
 				LTarget: "a",
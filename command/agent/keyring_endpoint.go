@@ -0,0 +1,82 @@
+package agent
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// KeyringRequest is used to list and rotate the root keys variables are
+// encrypted with
+func (s *HTTPServer) KeyringRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	path := strings.TrimPrefix(req.URL.Path, "/v1/operator/keyring/")
+	switch {
+	case path == "keys":
+		if req.Method != "GET" {
+			return nil, CodedError(405, ErrInvalidMethod)
+		}
+		return s.keyringList(resp, req)
+	case path == "rotate":
+		if req.Method != "PUT" && req.Method != "POST" {
+			return nil, CodedError(405, ErrInvalidMethod)
+		}
+		return s.keyringRotate(resp, req)
+	case strings.HasPrefix(path, "key/"):
+		if req.Method != "DELETE" {
+			return nil, CodedError(405, ErrInvalidMethod)
+		}
+		return s.keyringDelete(resp, req, strings.TrimPrefix(path, "key/"))
+	default:
+		return nil, CodedError(404, "Invalid keyring request")
+	}
+}
+
+func (s *HTTPServer) keyringList(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	args := structs.GenericRequest{}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.KeyringListRootKeyMetaResponse
+	if err := s.agent.RPC("Keyring.List", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.Keys == nil {
+		out.Keys = make([]*structs.RootKeyMeta, 0)
+	}
+	return out.Keys, nil
+}
+
+func (s *HTTPServer) keyringRotate(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	args := structs.KeyringRotateRootKeyRequest{
+		Full: req.URL.Query().Get("full") == "true",
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.KeyringRotateRootKeyResponse
+	if err := s.agent.RPC("Keyring.Rotate", &args, &out); err != nil {
+		return nil, err
+	}
+	setIndex(resp, out.Index)
+	return out, nil
+}
+
+func (s *HTTPServer) keyringDelete(resp http.ResponseWriter, req *http.Request, keyID string) (interface{}, error) {
+	if keyID == "" {
+		return nil, CodedError(400, "Missing root key ID")
+	}
+	args := structs.KeyringDeleteRootKeyRequest{
+		KeyID: keyID,
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.GenericResponse
+	if err := s.agent.RPC("Keyring.Delete", &args, &out); err != nil {
+		return nil, err
+	}
+	setIndex(resp, out.Index)
+	return nil, nil
+}
@@ -0,0 +1,56 @@
+package agent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTP_KeyringRotateList(t *testing.T) {
+	t.Parallel()
+	httpTest(t, nil, func(s *TestAgent) {
+		require := require.New(t)
+
+		// Rotate the root key
+		req, err := http.NewRequest("PUT", "/v1/operator/keyring/rotate?full=true", nil)
+		require.NoError(err)
+		respW := httptest.NewRecorder()
+		obj, err := s.Server.KeyringRequest(respW, req)
+		require.NoError(err)
+		require.NotEmpty(respW.HeaderMap.Get("X-Nomad-Index"))
+		rotated := obj.(structs.KeyringRotateRootKeyResponse)
+		require.True(rotated.Key.Active)
+
+		// List the keys
+		req, err = http.NewRequest("GET", "/v1/operator/keyring/keys", nil)
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+		obj, err = s.Server.KeyringRequest(respW, req)
+		require.NoError(err)
+		keys := obj.([]*structs.RootKeyMeta)
+		require.Len(keys, 2)
+
+		// Remove the inactive key
+		var inactive string
+		for _, k := range keys {
+			if !k.Active {
+				inactive = k.KeyID
+			}
+		}
+		req, err = http.NewRequest("DELETE", "/v1/operator/keyring/key/"+inactive, nil)
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+		_, err = s.Server.KeyringRequest(respW, req)
+		require.NoError(err)
+
+		// Invalid methods are rejected
+		req, err = http.NewRequest("GET", "/v1/operator/keyring/rotate", nil)
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+		_, err = s.Server.KeyringRequest(respW, req)
+		require.Error(err)
+	})
+}
@@ -2,13 +2,13 @@ package agent
 
 import (
 	"sync"
+
+	"github.com/hashicorp/nomad/command/agent/monitor"
 )
 
 // LogHandler interface is used for clients that want to subscribe
 // to logs, for example to stream them over an IPC mechanism
-type LogHandler interface {
-	HandleLog(string)
-}
+type LogHandler = monitor.LogHandler
 
 // logWriter implements io.Writer so it can be used as a log sink.
 // It maintains a circular buffer of logs, and a set of handlers to
@@ -69,6 +69,9 @@ func (l *logWriter) Write(p []byte) (n int, err error) {
 	// Strip off newlines at the end if there are any since we store
 	// individual log lines in the agent.
 	n = len(p)
+	if n == 0 {
+		return 0, nil
+	}
 	if p[n-1] == '\n' {
 		p = p[:n-1]
 	}
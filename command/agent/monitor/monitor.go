@@ -0,0 +1,97 @@
+package monitor
+
+import (
+	"strings"
+
+	"github.com/hashicorp/logutils"
+)
+
+// LogHandler interface is used for clients that want to subscribe
+// to logs, for example to stream them over an IPC mechanism
+type LogHandler interface {
+	HandleLog(string)
+}
+
+// LogSource is a source of the logs of an agent that handlers subscribe to.
+type LogSource interface {
+	RegisterHandler(LogHandler)
+	DeregisterHandler(LogHandler)
+}
+
+// Monitor is a LogHandler that receives the logs of the agent at or above a
+// minimum level so they can be streamed to an operator.
+type Monitor struct {
+	// logCh receives the filtered log lines. Lines are dropped if the
+	// consumer falls behind rather than blocking the logging of the agent.
+	logCh chan string
+
+	// minLevel is the index of the minimum level in levels
+	minLevel int
+}
+
+// levels are the log levels in increasing order of severity
+var levels = []logutils.LogLevel{"TRACE", "DEBUG", "INFO", "WARN", "ERR"}
+
+// New returns a monitor for the given minimum level or false if the level is
+// invalid.
+func New(level string, buf int) (*Monitor, bool) {
+	idx := levelIndex(level)
+	if idx < 0 {
+		return nil, false
+	}
+
+	return &Monitor{
+		logCh:    make(chan string, buf),
+		minLevel: idx,
+	}, true
+}
+
+// LogCh returns the channel receiving the filtered log lines.
+func (m *Monitor) LogCh() <-chan string {
+	return m.logCh
+}
+
+// HandleLog implements LogHandler.
+func (m *Monitor) HandleLog(line string) {
+	// Lines without a known level, such as the continuations of multi-line
+	// messages, are always sent
+	if idx := levelIndex(lineLevel(line)); idx >= 0 && idx < m.minLevel {
+		return
+	}
+
+	select {
+	case m.logCh <- line:
+	default:
+	}
+}
+
+// levelIndex returns the severity of the log level or -1 if it is unknown.
+func levelIndex(level string) int {
+	level = strings.ToUpper(strings.TrimSpace(level))
+	if level == "ERROR" {
+		level = "ERR"
+	} else if level == "WARNING" {
+		level = "WARN"
+	}
+
+	for i, l := range levels {
+		if string(l) == level {
+			return i
+		}
+	}
+	return -1
+}
+
+// lineLevel returns the level of a log line written either by the standard
+// logger ("[INFO] ...") or by hclog ("[INFO ] ..."), or an empty string.
+func lineLevel(line string) string {
+	start := strings.IndexByte(line, '[')
+	if start < 0 {
+		return ""
+	}
+	end := strings.IndexByte(line[start:], ']')
+	if end < 0 {
+		return ""
+	}
+	return line[start+1 : start+end]
+}
@@ -0,0 +1,44 @@
+package monitor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMonitor_Levels(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	_, ok := New("bogus", 10)
+	require.False(ok)
+
+	m, ok := New("warn", 10)
+	require.True(ok)
+
+	lines := []string{
+		"2019/01/01 00:00:00.000000 [DEBUG] client: dropped",
+		"2019-01-01T00:00:00.000Z [INFO ] agent: dropped",
+		"2019-01-01T00:00:00.000Z [WARN ] agent: kept",
+		"2019/01/01 00:00:00.000000 [ERR] nomad: kept",
+		"2019-01-01T00:00:00.000Z [ERROR] agent: kept",
+		"continuation of a multi-line message is kept",
+	}
+	for _, line := range lines {
+		m.HandleLog(line)
+	}
+	close(m.logCh)
+
+	var received []string
+	for line := range m.logCh {
+		received = append(received, line)
+	}
+	require.Equal(lines[2:], received)
+
+	// Lines are dropped rather than blocking when the buffer is full
+	m, ok = New("trace", 1)
+	require.True(ok)
+	m.HandleLog("[INFO] one")
+	m.HandleLog("[INFO] two")
+	require.Len(m.logCh, 1)
+}
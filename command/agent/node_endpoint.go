@@ -41,12 +41,18 @@ func (s *HTTPServer) NodeSpecificRequest(resp http.ResponseWriter, req *http.Req
 	case strings.HasSuffix(path, "/allocations"):
 		nodeName := strings.TrimSuffix(path, "/allocations")
 		return s.nodeAllocations(resp, req, nodeName)
+	case strings.HasSuffix(path, "/events"):
+		nodeName := strings.TrimSuffix(path, "/events")
+		return s.nodeEvents(resp, req, nodeName)
 	case strings.HasSuffix(path, "/drain"):
 		nodeName := strings.TrimSuffix(path, "/drain")
 		return s.nodeToggleDrain(resp, req, nodeName)
 	case strings.HasSuffix(path, "/eligibility"):
 		nodeName := strings.TrimSuffix(path, "/eligibility")
 		return s.nodeToggleEligibility(resp, req, nodeName)
+	case strings.HasSuffix(path, "/meta"):
+		nodeName := strings.TrimSuffix(path, "/meta")
+		return s.nodeUpdateDynamicMeta(resp, req, nodeName)
 	case strings.HasSuffix(path, "/purge"):
 		nodeName := strings.TrimSuffix(path, "/purge")
 		return s.nodePurge(resp, req, nodeName)
@@ -176,6 +182,40 @@ func (s *HTTPServer) nodeToggleEligibility(resp http.ResponseWriter, req *http.R
 	return out, nil
 }
 
+func (s *HTTPServer) nodeUpdateDynamicMeta(resp http.ResponseWriter, req *http.Request,
+	nodeID string) (interface{}, error) {
+	if req.Method != "PUT" && req.Method != "POST" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	var metaRequest structs.NodeUpdateDynamicMetaRequest
+	if err := decodeBody(req, &metaRequest); err != nil {
+		return nil, CodedError(400, err.Error())
+	}
+	metaRequest.NodeID = nodeID
+	s.parseWriteRequest(req, &metaRequest.WriteRequest)
+
+	// Updates go through the client of the node so that it persists its copy
+	// of the metadata and merges it into the metadata of the node. The
+	// metadata of nodes that can't be reached is only updated on the servers.
+	var out structs.NodeDynamicMetaUpdateResponse
+	var err error
+	if c := s.agent.Client(); c != nil && c.NodeID() == nodeID {
+		err = c.ClientRPC("NodeMeta.Apply", &metaRequest, &out)
+	} else {
+		err = s.agent.RPC("ClientNodeMeta.Apply", &metaRequest, &out)
+		if structs.IsErrNoNodeConn(err) || structs.IsErrUnknownNode(err) ||
+			structs.IsErrNodeLacksRpc(err) || structs.IsErrUnknownNomadVersion(err) {
+			err = s.agent.RPC("Node.UpdateDynamicMeta", &metaRequest, &out)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	setIndex(resp, out.Index)
+	return out, nil
+}
+
 func (s *HTTPServer) nodeQuery(resp http.ResponseWriter, req *http.Request,
 	nodeID string) (interface{}, error) {
 	if req.Method != "GET" {
@@ -200,6 +240,41 @@ func (s *HTTPServer) nodeQuery(resp http.ResponseWriter, req *http.Request,
 	return out.Node, nil
 }
 
+// nodeEvents returns the retained events of the node, oldest first,
+// optionally filtered by subsystem.
+func (s *HTTPServer) nodeEvents(resp http.ResponseWriter, req *http.Request,
+	nodeID string) (interface{}, error) {
+	if req.Method != "GET" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+	args := structs.NodeSpecificRequest{
+		NodeID: nodeID,
+	}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.SingleNodeResponse
+	if err := s.agent.RPC("Node.GetNode", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.Node == nil {
+		return nil, CodedError(404, "node not found")
+	}
+
+	subsystem := req.URL.Query().Get("subsystem")
+	events := make([]*structs.NodeEvent, 0, len(out.Node.Events))
+	for _, event := range out.Node.Events {
+		if subsystem != "" && !strings.EqualFold(event.Subsystem, subsystem) {
+			continue
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
 func (s *HTTPServer) nodePurge(resp http.ResponseWriter, req *http.Request, nodeID string) (interface{}, error) {
 	if req.Method != "PUT" && req.Method != "POST" {
 		return nil, CodedError(405, ErrInvalidMethod)
@@ -412,6 +412,48 @@ func TestHTTP_NodeEligible(t *testing.T) {
 	})
 }
 
+func TestHTTP_NodeUpdateDynamicMeta(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		// Create the node
+		node := mock.Node()
+		args := structs.NodeRegisterRequest{
+			Node:         node,
+			WriteRequest: structs.WriteRequest{Region: "global"},
+		}
+		var resp structs.NodeUpdateResponse
+		require.Nil(s.Agent.RPC("Node.Register", &args, &resp))
+
+		metaReq := api.NodeUpdateDynamicMetaRequest{
+			Set: map[string]string{"rack": "r1"},
+		}
+
+		// Make the HTTP request
+		buf := encodeReq(metaReq)
+		req, err := http.NewRequest("POST", "/v1/node/"+node.ID+"/meta", buf)
+		require.Nil(err)
+		respW := httptest.NewRecorder()
+
+		// Make the request
+		obj, err := s.Server.NodeSpecificRequest(respW, req)
+		require.Nil(err)
+
+		// Check for the index
+		require.NotZero(respW.HeaderMap.Get("X-Nomad-Index"))
+
+		// Check the response
+		_, ok := obj.(structs.NodeDynamicMetaUpdateResponse)
+		require.True(ok)
+
+		// Check that the node has been updated
+		state := s.Agent.server.State()
+		out, err := state.NodeByID(nil, node.ID)
+		require.Nil(err)
+		require.Equal("r1", out.DynamicMeta["rack"])
+	})
+}
+
 func TestHTTP_NodePurge(t *testing.T) {
 	t.Parallel()
 	httpTest(t, nil, func(s *TestAgent) {
@@ -477,6 +519,54 @@ func TestHTTP_NodePurge(t *testing.T) {
 	})
 }
 
+func TestHTTP_NodeEvents(t *testing.T) {
+	t.Parallel()
+	httpTest(t, nil, func(s *TestAgent) {
+		node := mock.Node()
+		args := structs.NodeRegisterRequest{
+			Node:         node,
+			WriteRequest: structs.WriteRequest{Region: "global"},
+		}
+		var resp structs.NodeUpdateResponse
+		require.NoError(t, s.Agent.RPC("Node.Register", &args, &resp))
+
+		// Emit a driver event in addition to the registration event
+		eventArgs := structs.EmitNodeEventsRequest{
+			NodeEvents: map[string][]*structs.NodeEvent{
+				node.ID: {structs.NewNodeEvent().
+					SetSubsystem(structs.NodeEventSubsystemDriver).
+					SetMessage("Driver docker is unhealthy")},
+			},
+			WriteRequest: structs.WriteRequest{Region: "global"},
+		}
+		var eventResp structs.EmitNodeEventsResponse
+		require.NoError(t, s.Agent.RPC("Node.EmitEvents", &eventArgs, &eventResp))
+
+		req, err := http.NewRequest("GET", "/v1/node/"+node.ID+"/events", nil)
+		require.NoError(t, err)
+		respW := httptest.NewRecorder()
+		obj, err := s.Server.NodeSpecificRequest(respW, req)
+		require.NoError(t, err)
+		require.NotEmpty(t, respW.HeaderMap.Get("X-Nomad-Index"))
+
+		events := obj.([]*structs.NodeEvent)
+		require.Len(t, events, 2)
+		require.Equal(t, structs.NodeEventSubsystemCluster, events[0].Subsystem)
+		require.Equal(t, structs.NodeEventSubsystemDriver, events[1].Subsystem)
+
+		// Filter the events by subsystem
+		req, err = http.NewRequest("GET", "/v1/node/"+node.ID+"/events?subsystem=driver", nil)
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+		obj, err = s.Server.NodeSpecificRequest(respW, req)
+		require.NoError(t, err)
+
+		events = obj.([]*structs.NodeEvent)
+		require.Len(t, events, 1)
+		require.Equal(t, "Driver docker is unhealthy", events[0].Message)
+	})
+}
+
 func TestHTTP_NodeQuery(t *testing.T) {
 	t.Parallel()
 	httpTest(t, nil, func(s *TestAgent) {
@@ -0,0 +1,120 @@
+package agent
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// NodeMetaRequest is used to read and update the metadata of a node
+func (s *HTTPServer) NodeMetaRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	switch req.Method {
+	case "GET":
+		return s.nodeMetaRead(resp, req)
+	case "PUT", "POST":
+		return s.nodeMetaApply(resp, req)
+	default:
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+}
+
+func (s *HTTPServer) nodeMetaRead(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	// Build the request and parse the ACL token
+	args := structs.NodeSpecificRequest{
+		NodeID: req.URL.Query().Get("node_id"),
+	}
+	s.parse(resp, req, &args.QueryOptions.Region, &args.QueryOptions)
+
+	var reply cstructs.NodeMetaResponse
+	if err := s.nodeMetaRPC("Read", &args.NodeID, &args, &reply); err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
+
+// nodeMetaApply sets and unsets the dynamic metadata of a node through its
+// client, the same as updating the metadata of the node with
+// /v1/node/:node_id/meta, and returns the resulting metadata.
+func (s *HTTPServer) nodeMetaApply(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	var body api.NodeMetaApplyRequest
+	if err := decodeBody(req, &body); err != nil {
+		return nil, CodedError(400, err.Error())
+	}
+	if len(body.Meta) == 0 {
+		return nil, CodedError(400, "Missing metadata to set or unset")
+	}
+
+	args := structs.NodeUpdateDynamicMetaRequest{
+		NodeID: body.NodeID,
+		Set:    make(map[string]string, len(body.Meta)),
+	}
+	if nodeID := req.URL.Query().Get("node_id"); nodeID != "" {
+		args.NodeID = nodeID
+	}
+	for k, v := range body.Meta {
+		if v == nil {
+			args.Unset = append(args.Unset, k)
+		} else {
+			args.Set[k] = *v
+		}
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.NodeDynamicMetaUpdateResponse
+	if err := s.nodeMetaRPC("Apply", &args.NodeID, &args, &out); err != nil {
+		return nil, err
+	}
+	setIndex(resp, out.Index)
+
+	readArgs := structs.NodeSpecificRequest{
+		NodeID: args.NodeID,
+		QueryOptions: structs.QueryOptions{
+			Region:    args.Region,
+			AuthToken: args.AuthToken,
+		},
+	}
+	var reply cstructs.NodeMetaResponse
+	if err := s.nodeMetaRPC("Read", &readArgs.NodeID, &readArgs, &reply); err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
+
+// nodeMetaRPC makes the NodeMeta RPC of the node, directly if it's the local
+// node, which it defaults to, or through the servers.
+func (s *HTTPServer) nodeMetaRPC(method string, nodeID *string, args, reply interface{}) error {
+	// Determine the handler to use
+	useLocalClient, useClientRPC, useServerRPC := s.rpcHandlerForNode(*nodeID)
+	if useLocalClient && *nodeID == "" {
+		*nodeID = s.agent.Client().NodeID()
+	}
+
+	// Make the RPC
+	var rpcErr error
+	if useLocalClient {
+		rpcErr = s.agent.Client().ClientRPC("NodeMeta."+method, args, reply)
+	} else if useClientRPC {
+		rpcErr = s.agent.Client().RPC("ClientNodeMeta."+method, args, reply)
+	} else if useServerRPC {
+		rpcErr = s.agent.Server().RPC("ClientNodeMeta."+method, args, reply)
+	} else {
+		rpcErr = CodedError(400, "No local Node and node_id not provided")
+	}
+
+	if rpcErr != nil {
+		return nodeMetaError(rpcErr)
+	}
+	return nil
+}
+
+// nodeMetaError returns a 404 error for the requests of unknown or
+// unreachable nodes.
+func nodeMetaError(err error) error {
+	if structs.IsErrNoNodeConn(err) || strings.Contains(err.Error(), "Unknown node") {
+		return CodedError(404, err.Error())
+	}
+	return err
+}
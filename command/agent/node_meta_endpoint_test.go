@@ -0,0 +1,75 @@
+package agent
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTP_NodeMeta(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		// Update the metadata of the local node
+		args := api.NodeMetaApplyRequest{
+			Meta: map[string]*string{"rack": helper.StringToPtr("r1")},
+		}
+		req, err := http.NewRequest("POST", "/v1/client/metadata", encodeReq(args))
+		require.NoError(err)
+		respW := httptest.NewRecorder()
+
+		obj, err := s.Server.NodeMetaRequest(respW, req)
+		require.NoError(err)
+		require.Equal("r1", obj.(cstructs.NodeMetaResponse).Meta["rack"])
+		require.Equal("r1", obj.(cstructs.NodeMetaResponse).Dynamic["rack"])
+
+		// The update is the dynamic metadata of the node
+		node, err := s.Agent.Server().State().NodeByID(nil, s.Agent.Client().NodeID())
+		require.NoError(err)
+		require.Equal(map[string]string{"rack": "r1"}, node.DynamicMeta)
+
+		// Read it back
+		req, err = http.NewRequest("GET", "/v1/client/metadata", nil)
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+
+		obj, err = s.Server.NodeMetaRequest(respW, req)
+		require.NoError(err)
+		require.Equal("r1", obj.(cstructs.NodeMetaResponse).Meta["rack"])
+
+		// Unset it
+		args.Meta["rack"] = nil
+		req, err = http.NewRequest("POST", "/v1/client/metadata", encodeReq(args))
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+
+		obj, err = s.Server.NodeMetaRequest(respW, req)
+		require.NoError(err)
+		require.NotContains(obj.(cstructs.NodeMetaResponse).Meta, "rack")
+
+		// Missing metadata
+		req, err = http.NewRequest("POST", "/v1/client/metadata", encodeReq(api.NodeMetaApplyRequest{}))
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+
+		_, err = s.Server.NodeMetaRequest(respW, req)
+		require.Error(err)
+		require.Equal(400, err.(HTTPCodedError).Code())
+
+		// Unknown node
+		req, err = http.NewRequest("GET", fmt.Sprintf("/v1/client/metadata?node_id=%s", uuid.Generate()), nil)
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+
+		_, err = s.Server.NodeMetaRequest(respW, req)
+		require.Error(err)
+		require.Contains(err.Error(), "Unknown node")
+	})
+}
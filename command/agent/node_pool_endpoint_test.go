@@ -0,0 +1,67 @@
+package agent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTP_NodePoolCRUD(t *testing.T) {
+	t.Parallel()
+	httpTest(t, nil, func(s *TestAgent) {
+		require := require.New(t)
+
+		// Create the node pool
+		pool := &structs.NodePool{
+			Name:        "gpu",
+			Description: "nodes with GPUs",
+		}
+		req, err := http.NewRequest("PUT", "/v1/node/pool/gpu", encodeReq(pool))
+		require.NoError(err)
+		respW := httptest.NewRecorder()
+		_, err = s.Server.NodePoolSpecificRequest(respW, req)
+		require.NoError(err)
+		require.NotEmpty(respW.HeaderMap.Get("X-Nomad-Index"))
+
+		// Query the node pool
+		req, err = http.NewRequest("GET", "/v1/node/pool/gpu", nil)
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+		obj, err := s.Server.NodePoolSpecificRequest(respW, req)
+		require.NoError(err)
+		out := obj.(*structs.NodePool)
+		require.Equal(pool.Description, out.Description)
+
+		// List the node pools
+		req, err = http.NewRequest("GET", "/v1/node/pools?prefix=gp", nil)
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+		obj, err = s.Server.NodePoolsRequest(respW, req)
+		require.NoError(err)
+		require.Len(obj.([]*structs.NodePool), 1)
+
+		// A mismatched name is rejected
+		req, err = http.NewRequest("PUT", "/v1/node/pool/batch", encodeReq(pool))
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+		_, err = s.Server.NodePoolSpecificRequest(respW, req)
+		require.Error(err)
+
+		// Delete the node pool
+		req, err = http.NewRequest("DELETE", "/v1/node/pool/gpu", nil)
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+		_, err = s.Server.NodePoolSpecificRequest(respW, req)
+		require.NoError(err)
+
+		req, err = http.NewRequest("GET", "/v1/node/pool/gpu", nil)
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+		_, err = s.Server.NodePoolSpecificRequest(respW, req)
+		require.Error(err)
+		require.Contains(err.Error(), "not found")
+	})
+}
@@ -1,6 +1,9 @@
 package agent
 
 import (
+	"context"
+	"io"
+	"net"
 	"net/http"
 	"strings"
 
@@ -10,8 +13,10 @@ import (
 
 	"github.com/hashicorp/consul/agent/consul/autopilot"
 	"github.com/hashicorp/nomad/api"
+	cstructs "github.com/hashicorp/nomad/client/structs"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/raft"
+	"github.com/ugorji/go/codec"
 )
 
 func (s *HTTPServer) OperatorRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
@@ -108,6 +113,7 @@ func (s *HTTPServer) OperatorAutopilotConfiguration(resp http.ResponseWriter, re
 			CleanupDeadServers:      reply.CleanupDeadServers,
 			LastContactThreshold:    reply.LastContactThreshold,
 			MaxTrailingLogs:         reply.MaxTrailingLogs,
+			MinQuorum:               reply.MinQuorum,
 			ServerStabilizationTime: reply.ServerStabilizationTime,
 			EnableRedundancyZones:   reply.EnableRedundancyZones,
 			DisableUpgradeMigration: reply.DisableUpgradeMigration,
@@ -131,6 +137,7 @@ func (s *HTTPServer) OperatorAutopilotConfiguration(resp http.ResponseWriter, re
 			CleanupDeadServers:      conf.CleanupDeadServers,
 			LastContactThreshold:    conf.LastContactThreshold,
 			MaxTrailingLogs:         conf.MaxTrailingLogs,
+			MinQuorum:               conf.MinQuorum,
 			ServerStabilizationTime: conf.ServerStabilizationTime,
 			EnableRedundancyZones:   conf.EnableRedundancyZones,
 			DisableUpgradeMigration: conf.DisableUpgradeMigration,
@@ -250,7 +257,14 @@ func (s *HTTPServer) schedulerUpdateConfig(resp http.ResponseWriter, req *http.R
 	}
 
 	args.Config = structs.SchedulerConfiguration{
-		PreemptionConfig: structs.PreemptionConfig{SystemSchedulerEnabled: conf.PreemptionConfig.SystemSchedulerEnabled},
+		SchedulerAlgorithm: structs.SchedulerAlgorithm(conf.SchedulerAlgorithm),
+		PreemptionConfig: structs.PreemptionConfig{
+			SystemSchedulerEnabled:  conf.PreemptionConfig.SystemSchedulerEnabled,
+			ServiceSchedulerEnabled: conf.PreemptionConfig.ServiceSchedulerEnabled,
+			BatchSchedulerEnabled:   conf.PreemptionConfig.BatchSchedulerEnabled,
+		},
+		NumSchedulers:       conf.NumSchedulers,
+		DisableEvalPriority: conf.DisableEvalPriority,
 	}
 
 	// Check for cas value
@@ -271,3 +285,175 @@ func (s *HTTPServer) schedulerUpdateConfig(resp http.ResponseWriter, req *http.R
 	setIndex(resp, reply.Index)
 	return reply, nil
 }
+
+// OperatorEvalBrokerStats is used to inspect the eval broker of the leader.
+func (s *HTTPServer) OperatorEvalBrokerStats(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "GET" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	var args structs.GenericRequest
+	if done := s.parse(resp, req, &args.Region, &args.QueryOptions); done {
+		return nil, nil
+	}
+
+	var reply structs.EvalBrokerStatsResponse
+	if err := s.agent.RPC("Operator.EvalBrokerStats", &args, &reply); err != nil {
+		return nil, err
+	}
+	setMeta(resp, &reply.QueryMeta)
+
+	return reply.Stats, nil
+}
+
+// SnapshotRequest is used to take a snapshot of the state of the cluster with
+// a GET, or to restore one with a PUT.
+func (s *HTTPServer) SnapshotRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	switch req.Method {
+	case "GET":
+		return s.snapshotSaveRequest(resp, req)
+	case "PUT", "POST":
+		return s.snapshotRestoreRequest(resp, req)
+	default:
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+}
+
+func (s *HTTPServer) snapshotSaveRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	args := &structs.SnapshotSaveRequest{}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	handler, err := s.serverStreamingRpcHandler("Operator.SnapshotSave")
+	if err != nil {
+		return nil, CodedError(500, err.Error())
+	}
+
+	// Create a pipe connecting the (possibly remote) handler to the http response
+	httpPipe, handlerPipe := net.Pipe()
+	decoder := codec.NewDecoder(httpPipe, structs.MsgpackHandle)
+	encoder := codec.NewEncoder(httpPipe, structs.MsgpackHandle)
+
+	// Create a goroutine that closes the pipe if the connection closes.
+	ctx, cancel := context.WithCancel(req.Context())
+	defer cancel()
+	go func() {
+		<-ctx.Done()
+		httpPipe.Close()
+	}()
+
+	errCh := make(chan HTTPCodedError, 1)
+	go func() {
+		defer cancel()
+
+		// Send the request
+		if err := encoder.Encode(args); err != nil {
+			errCh <- CodedError(500, err.Error())
+			return
+		}
+
+		// Read the header of the snapshot
+		var res structs.SnapshotSaveResponse
+		if err := decoder.Decode(&res); err != nil {
+			errCh <- CodedError(500, err.Error())
+			return
+		}
+		if res.ErrorMsg != "" {
+			errCh <- CodedError(res.ErrorCode, res.ErrorMsg)
+			return
+		}
+
+		setMeta(resp, &res.QueryMeta)
+		resp.Header().Set("Digest", res.SnapshotChecksum)
+		resp.Header().Set("Content-Type", "application/x-gzip")
+
+		// Copy the archive to the response
+		if _, err := io.Copy(resp, httpPipe); err != nil && err != io.ErrClosedPipe {
+			errCh <- CodedError(500, err.Error())
+			return
+		}
+		errCh <- nil
+	}()
+
+	handler(handlerPipe)
+	if codedErr := <-errCh; codedErr != nil {
+		return nil, codedErr
+	}
+	return nil, nil
+}
+
+func (s *HTTPServer) snapshotRestoreRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	args := &structs.SnapshotRestoreRequest{}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	handler, err := s.serverStreamingRpcHandler("Operator.SnapshotRestore")
+	if err != nil {
+		return nil, CodedError(500, err.Error())
+	}
+
+	// Create a pipe connecting the http request to the (possibly remote)
+	// handler
+	httpPipe, handlerPipe := net.Pipe()
+	decoder := codec.NewDecoder(httpPipe, structs.MsgpackHandle)
+	encoder := codec.NewEncoder(httpPipe, structs.MsgpackHandle)
+
+	// Create a goroutine that closes the pipe if the connection closes.
+	ctx, cancel := context.WithCancel(req.Context())
+	defer cancel()
+	go func() {
+		<-ctx.Done()
+		httpPipe.Close()
+	}()
+
+	errCh := make(chan HTTPCodedError, 1)
+	go func() {
+		defer cancel()
+
+		// Send the request
+		if err := encoder.Encode(args); err != nil {
+			errCh <- CodedError(500, err.Error())
+			return
+		}
+
+		// Stream the archive in chunks, marking its end with an error
+		go func() {
+			var wrapper cstructs.StreamErrWrapper
+			buf := make([]byte, 32*1024)
+			for {
+				n, err := req.Body.Read(buf)
+				if n > 0 {
+					wrapper.Payload = buf[:n]
+					if err := encoder.Encode(&wrapper); err != nil {
+						return
+					}
+				}
+				if err != nil {
+					wrapper.Payload = nil
+					wrapper.Error = cstructs.NewRpcError(err, nil)
+					encoder.Encode(&wrapper)
+					return
+				}
+			}
+		}()
+
+		var res structs.SnapshotRestoreResponse
+		if err := decoder.Decode(&res); err != nil {
+			errCh <- CodedError(500, err.Error())
+			return
+		}
+		if res.ErrorMsg != "" {
+			errCh <- CodedError(res.ErrorCode, res.ErrorMsg)
+			return
+		}
+
+		setMeta(resp, &res.QueryMeta)
+		errCh <- nil
+	}()
+
+	handler(handlerPipe)
+	if codedErr := <-errCh; codedErr != nil {
+		return nil, codedErr
+	}
+	return nil, nil
+}
@@ -2,6 +2,8 @@ package agent
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -11,6 +13,8 @@ import (
 
 	"github.com/hashicorp/consul/testutil/retry"
 	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/helper/snapshot"
+	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -105,7 +109,7 @@ func TestOperator_AutopilotGetConfiguration(t *testing.T) {
 func TestOperator_AutopilotSetConfiguration(t *testing.T) {
 	t.Parallel()
 	httpTest(t, nil, func(s *TestAgent) {
-		body := bytes.NewBuffer([]byte(`{"CleanupDeadServers": false}`))
+		body := bytes.NewBuffer([]byte(`{"CleanupDeadServers": false, "MinQuorum": 3}`))
 		req, _ := http.NewRequest("PUT", "/v1/operator/autopilot/configuration", body)
 		resp := httptest.NewRecorder()
 		if _, err := s.Server.OperatorAutopilotConfiguration(resp, req); err != nil {
@@ -125,7 +129,7 @@ func TestOperator_AutopilotSetConfiguration(t *testing.T) {
 		if err := s.RPC("Operator.AutopilotGetConfiguration", &args, &reply); err != nil {
 			t.Fatalf("err: %v", err)
 		}
-		if reply.CleanupDeadServers {
+		if reply.CleanupDeadServers || reply.MinQuorum != 3 {
 			t.Fatalf("bad: %#v", reply)
 		}
 	})
@@ -272,6 +276,24 @@ func TestOperator_SchedulerGetConfiguration(t *testing.T) {
 		out, ok := obj.(structs.SchedulerConfigurationResponse)
 		require.True(ok)
 		require.True(out.SchedulerConfig.PreemptionConfig.SystemSchedulerEnabled)
+		require.False(out.SchedulerConfig.PreemptionConfig.ServiceSchedulerEnabled)
+		require.False(out.SchedulerConfig.PreemptionConfig.BatchSchedulerEnabled)
+	})
+}
+
+func TestOperator_EvalBrokerStats(t *testing.T) {
+	t.Parallel()
+	httpTest(t, nil, func(s *TestAgent) {
+		require := require.New(t)
+		req, _ := http.NewRequest("GET", "/v1/operator/eval-broker", nil)
+		resp := httptest.NewRecorder()
+		obj, err := s.Server.OperatorEvalBrokerStats(resp, req)
+		require.Nil(err)
+		require.Equal(200, resp.Code)
+		out, ok := obj.(*structs.EvalBrokerStats)
+		require.True(ok)
+		require.NotZero(out.DeliveryLimit)
+		require.NotZero(out.NackTimeout)
 	})
 }
 
@@ -279,8 +301,10 @@ func TestOperator_SchedulerSetConfiguration(t *testing.T) {
 	t.Parallel()
 	httpTest(t, nil, func(s *TestAgent) {
 		require := require.New(t)
-		body := bytes.NewBuffer([]byte(`{"PreemptionConfig": {
-                     "SystemSchedulerEnabled": true
+		body := bytes.NewBuffer([]byte(`{"SchedulerAlgorithm": "spread",
+                     "PreemptionConfig": {
+                     "SystemSchedulerEnabled": true,
+                     "ServiceSchedulerEnabled": true
         }}`))
 		req, _ := http.NewRequest("PUT", "/v1/operator/scheduler/configuration", body)
 		resp := httptest.NewRecorder()
@@ -301,6 +325,9 @@ func TestOperator_SchedulerSetConfiguration(t *testing.T) {
 		err = s.RPC("Operator.SchedulerGetConfiguration", &args, &reply)
 		require.Nil(err)
 		require.True(reply.SchedulerConfig.PreemptionConfig.SystemSchedulerEnabled)
+		require.True(reply.SchedulerConfig.PreemptionConfig.ServiceSchedulerEnabled)
+		require.False(reply.SchedulerConfig.PreemptionConfig.BatchSchedulerEnabled)
+		require.Equal(structs.SchedulerAlgorithmSpread, reply.SchedulerConfig.SchedulerAlgorithm)
 	})
 }
 
@@ -371,3 +398,79 @@ func TestOperator_SchedulerCASConfiguration(t *testing.T) {
 		require.False(reply.SchedulerConfig.PreemptionConfig.SystemSchedulerEnabled)
 	})
 }
+
+func TestHTTP_OperatorSnapshotSaveRestore(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		// Take a snapshot
+		req, err := http.NewRequest("GET", "/v1/operator/snapshot", nil)
+		require.NoError(err)
+		resp := httptest.NewRecorder()
+		_, err = s.Server.SnapshotRequest(resp, req)
+		require.NoError(err)
+		require.Equal(200, resp.Code)
+		require.NotEmpty(resp.Header().Get("X-Nomad-Index"))
+
+		digest := resp.Header().Get("Digest")
+		require.True(strings.HasPrefix(digest, "sha-256="))
+		archive := resp.Body.Bytes()
+		sum := sha256.Sum256(archive)
+		require.Equal(base64.StdEncoding.EncodeToString(sum[:]), strings.TrimPrefix(digest, "sha-256="))
+
+		meta, err := snapshot.Verify(bytes.NewReader(archive))
+		require.NoError(err)
+		require.NotZero(meta.Index)
+
+		// Restore it
+		req, err = http.NewRequest("PUT", "/v1/operator/snapshot", bytes.NewReader(archive))
+		require.NoError(err)
+		resp = httptest.NewRecorder()
+		_, err = s.Server.SnapshotRequest(resp, req)
+		require.NoError(err)
+		require.Equal(200, resp.Code)
+
+		// Invalid snapshots fail to restore
+		req, err = http.NewRequest("PUT", "/v1/operator/snapshot", strings.NewReader("foo"))
+		require.NoError(err)
+		resp = httptest.NewRecorder()
+		_, err = s.Server.SnapshotRequest(resp, req)
+		require.Error(err)
+		require.Contains(err.Error(), "failed to restore snapshot")
+	})
+}
+
+func TestHTTP_OperatorSnapshot_ACL(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	httpACLTest(t, nil, func(s *TestAgent) {
+		state := s.Agent.server.State()
+
+		// Try without a token
+		req, err := http.NewRequest("GET", "/v1/operator/snapshot", nil)
+		require.NoError(err)
+		resp := httptest.NewRecorder()
+		_, err = s.Server.SnapshotRequest(resp, req)
+		require.Error(err)
+		require.Equal(403, err.(HTTPCodedError).Code())
+
+		// Try with a token that isn't a management token
+		token := mock.CreatePolicyAndToken(t, state, 1005, "operator", `operator { policy = "write" }`)
+		req, err = http.NewRequest("PUT", "/v1/operator/snapshot", strings.NewReader("foo"))
+		require.NoError(err)
+		setToken(req, token)
+		resp = httptest.NewRecorder()
+		_, err = s.Server.SnapshotRequest(resp, req)
+		require.Error(err)
+		require.Equal(403, err.(HTTPCodedError).Code())
+
+		// Try with a management token
+		req, err = http.NewRequest("GET", "/v1/operator/snapshot", nil)
+		require.NoError(err)
+		setToken(req, s.RootToken)
+		resp = httptest.NewRecorder()
+		_, err = s.Server.SnapshotRequest(resp, req)
+		require.NoError(err)
+		require.NotEmpty(resp.Body.Bytes())
+	})
+}
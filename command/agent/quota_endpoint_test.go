@@ -0,0 +1,108 @@
+package agent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTP_QuotaCRUD(t *testing.T) {
+	t.Parallel()
+	httpTest(t, nil, func(s *TestAgent) {
+		require := require.New(t)
+
+		// Create the quota specification
+		spec := &structs.QuotaSpec{
+			Name: "small",
+			Limits: []*structs.QuotaLimit{{
+				Region:      "global",
+				RegionLimit: &structs.Resources{CPU: 2000},
+			}},
+		}
+		req, err := http.NewRequest("PUT", "/v1/quota", encodeReq(spec))
+		require.NoError(err)
+		respW := httptest.NewRecorder()
+		_, err = s.Server.QuotaCreateRequest(respW, req)
+		require.NoError(err)
+		require.NotEmpty(respW.HeaderMap.Get("X-Nomad-Index"))
+
+		// Query the quota specification
+		req, err = http.NewRequest("GET", "/v1/quota/small", nil)
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+		obj, err := s.Server.QuotaSpecificRequest(respW, req)
+		require.NoError(err)
+		out := obj.(*structs.QuotaSpec)
+		require.Len(out.Limits, 1)
+		require.Equal(2000, out.Limits[0].RegionLimit.CPU)
+
+		// List the quota specifications
+		req, err = http.NewRequest("GET", "/v1/quotas?prefix=sm", nil)
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+		obj, err = s.Server.QuotasRequest(respW, req)
+		require.NoError(err)
+		require.Len(obj.([]*structs.QuotaSpec), 1)
+
+		// Query the quota usage
+		req, err = http.NewRequest("GET", "/v1/quota/usage/small", nil)
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+		obj, err = s.Server.QuotaSpecificRequest(respW, req)
+		require.NoError(err)
+		usage := obj.(*structs.QuotaUsage)
+		require.Contains(usage.Used, out.Limits[0].HashKey())
+
+		req, err = http.NewRequest("GET", "/v1/quota-usages", nil)
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+		obj, err = s.Server.QuotaUsagesRequest(respW, req)
+		require.NoError(err)
+		require.Len(obj.([]*structs.QuotaUsage), 1)
+
+		// Attach the quota to a namespace
+		ns := &structs.Namespace{Name: "team-a", Quota: "small"}
+		req, err = http.NewRequest("PUT", "/v1/namespace/team-a", encodeReq(ns))
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+		_, err = s.Server.NamespaceSpecificRequest(respW, req)
+		require.NoError(err)
+
+		req, err = http.NewRequest("GET", "/v1/namespaces", nil)
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+		obj, err = s.Server.NamespacesRequest(respW, req)
+		require.NoError(err)
+		require.Len(obj.([]*structs.Namespace), 1)
+
+		// A quota referenced by a namespace can not be deleted
+		req, err = http.NewRequest("DELETE", "/v1/quota/small", nil)
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+		_, err = s.Server.QuotaSpecificRequest(respW, req)
+		require.Error(err)
+
+		// Delete the namespace and then the quota specification
+		req, err = http.NewRequest("DELETE", "/v1/namespace/team-a", nil)
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+		_, err = s.Server.NamespaceSpecificRequest(respW, req)
+		require.NoError(err)
+
+		req, err = http.NewRequest("DELETE", "/v1/quota/small", nil)
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+		_, err = s.Server.QuotaSpecificRequest(respW, req)
+		require.NoError(err)
+
+		req, err = http.NewRequest("GET", "/v1/quota/small", nil)
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+		_, err = s.Server.QuotaSpecificRequest(respW, req)
+		require.Error(err)
+		require.Contains(err.Error(), "not found")
+	})
+}
@@ -333,3 +333,32 @@ func TestHTTP_Search_AllContext(t *testing.T) {
 		assert.Equal("8000", respW.HeaderMap.Get("X-Nomad-Index"))
 	})
 }
+
+func TestHTTP_FuzzySearch(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Parallel()
+	httpTest(t, nil, func(s *TestAgent) {
+		createJobForTest("frontend-web", s, t)
+
+		data := structs.FuzzySearchRequest{Text: "web"}
+		req, err := http.NewRequest("POST", "/v1/search/fuzzy", encodeReq(data))
+		assert.Nil(err)
+
+		respW := httptest.NewRecorder()
+
+		resp, err := s.Server.FuzzySearchRequest(respW, req)
+		assert.Nil(err)
+
+		res := resp.(structs.FuzzySearchResponse)
+		assert.Len(res.Matches[structs.Jobs], 1)
+		assert.Equal("frontend-web", res.Matches[structs.Jobs][0].ID)
+		assert.Equal("1000", respW.HeaderMap.Get("X-Nomad-Index"))
+
+		// The text is required
+		req, err = http.NewRequest("POST", "/v1/search/fuzzy", encodeReq(structs.FuzzySearchRequest{}))
+		assert.Nil(err)
+		_, err = s.Server.FuzzySearchRequest(httptest.NewRecorder(), req)
+		assert.EqualError(err, "Missing search text")
+	})
+}
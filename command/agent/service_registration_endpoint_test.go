@@ -0,0 +1,72 @@
+package agent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTP_ServiceRegistrations(t *testing.T) {
+	t.Parallel()
+	httpTest(t, nil, func(s *TestAgent) {
+		require := require.New(t)
+
+		// Register an instance of a service
+		service := &structs.ServiceRegistration{
+			ID:          "_nomad-task-web",
+			ServiceName: "web",
+			Namespace:   structs.DefaultNamespace,
+			JobID:       "example",
+			AllocID:     uuid.Generate(),
+			Task:        "frontend",
+			Address:     "10.0.0.1",
+			Port:        8080,
+		}
+		state := s.Agent.server.State()
+		require.NoError(state.UpsertServiceRegistrations(1000, []*structs.ServiceRegistration{service}))
+
+		// List the services
+		req, err := http.NewRequest("GET", "/v1/services", nil)
+		require.NoError(err)
+		respW := httptest.NewRecorder()
+		obj, err := s.Server.ServiceRegistrationListRequest(respW, req)
+		require.NoError(err)
+		require.Equal("1000", respW.HeaderMap.Get("X-Nomad-Index"))
+		stubs := obj.([]*structs.ServiceRegistrationListStub)
+		require.Len(stubs, 1)
+		require.Equal("web", stubs[0].ServiceName)
+		require.Equal(1, stubs[0].Instances)
+
+		// List the services of another job
+		req, err = http.NewRequest("GET", "/v1/services?job=other", nil)
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+		obj, err = s.Server.ServiceRegistrationListRequest(respW, req)
+		require.NoError(err)
+		require.Empty(obj.([]*structs.ServiceRegistrationListStub))
+
+		// Read the instances of the service, which is critical as its
+		// allocation doesn't exist
+		req, err = http.NewRequest("GET", "/v1/service/web", nil)
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+		obj, err = s.Server.ServiceRegistrationRequest(respW, req)
+		require.NoError(err)
+		services := obj.([]*structs.ServiceRegistration)
+		require.Len(services, 1)
+		require.Equal("10.0.0.1", services[0].Address)
+		require.Equal(structs.ServiceHealthCritical, services[0].Health)
+
+		// Only reads are allowed
+		req, err = http.NewRequest("PUT", "/v1/service/web", nil)
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+		_, err = s.Server.ServiceRegistrationRequest(respW, req)
+		require.Error(err)
+		require.Equal(405, err.(HTTPCodedError).Code())
+	})
+}
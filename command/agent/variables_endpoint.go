@@ -0,0 +1,147 @@
+package agent
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+func (s *HTTPServer) VariablesListRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "GET" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	args := structs.VariablesListRequest{}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.VariablesListResponse
+	if err := s.agent.RPC("Variables.List", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.Data == nil {
+		out.Data = make([]*structs.VariableMetadata, 0)
+	}
+	return out.Data, nil
+}
+
+func (s *HTTPServer) VariableSpecificRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	path := strings.TrimPrefix(req.URL.Path, "/v1/var/")
+	if len(path) == 0 {
+		return nil, CodedError(400, "Missing Variable Path")
+	}
+	switch req.Method {
+	case "GET":
+		return s.variableQuery(resp, req, path)
+	case "PUT", "POST":
+		return s.variableUpsert(resp, req, path)
+	case "DELETE":
+		return s.variableDelete(resp, req, path)
+	default:
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+}
+
+func (s *HTTPServer) variableQuery(resp http.ResponseWriter, req *http.Request,
+	path string) (interface{}, error) {
+	args := structs.VariablesReadRequest{
+		Path: path,
+	}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.VariablesReadResponse
+	if err := s.agent.RPC("Variables.Read", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.Data == nil {
+		return nil, CodedError(404, "variable not found")
+	}
+	return out.Data, nil
+}
+
+func (s *HTTPServer) variableUpsert(resp http.ResponseWriter, req *http.Request,
+	path string) (interface{}, error) {
+	// Parse the variable
+	var variable structs.VariableDecrypted
+	if err := decodeBody(req, &variable); err != nil {
+		return nil, CodedError(400, err.Error())
+	}
+
+	// Ensure the variable path matches
+	if variable.Path == "" {
+		variable.Path = path
+	} else if variable.Path != path {
+		return nil, CodedError(400, "Variable path does not match request path")
+	}
+
+	// Format the request
+	args := structs.VariablesUpsertRequest{
+		Data: &variable,
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+	cas, err := parseCAS(req)
+	if err != nil {
+		return nil, err
+	}
+	args.CheckIndex = cas
+
+	var out structs.VariablesUpsertResponse
+	if err := s.agent.RPC("Variables.Upsert", &args, &out); err != nil {
+		return nil, casError(err)
+	}
+	setIndex(resp, out.Index)
+	return out.Data, nil
+}
+
+func (s *HTTPServer) variableDelete(resp http.ResponseWriter, req *http.Request,
+	path string) (interface{}, error) {
+
+	args := structs.VariablesDeleteRequest{
+		Path: path,
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+	cas, err := parseCAS(req)
+	if err != nil {
+		return nil, err
+	}
+	args.CheckIndex = cas
+
+	var out structs.GenericResponse
+	if err := s.agent.RPC("Variables.Delete", &args, &out); err != nil {
+		return nil, casError(err)
+	}
+	setIndex(resp, out.Index)
+	return nil, nil
+}
+
+// parseCAS returns the check index of the cas query parameter, or nil if it
+// is not set.
+func parseCAS(req *http.Request) (*uint64, error) {
+	params := req.URL.Query()
+	if _, ok := params["cas"]; !ok {
+		return nil, nil
+	}
+	casVal, err := strconv.ParseUint(params.Get("cas"), 10, 64)
+	if err != nil {
+		return nil, CodedError(http.StatusBadRequest, fmt.Sprintf("Error parsing cas value: %v", err))
+	}
+	return &casVal, nil
+}
+
+// casError returns check-and-set conflicts as 409 errors.
+func casError(err error) error {
+	if strings.Contains(err.Error(), structs.ErrCASConflictPrefix) {
+		return CodedError(http.StatusConflict, err.Error())
+	}
+	return err
+}
@@ -0,0 +1,69 @@
+package agent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTP_VariablesCRUD(t *testing.T) {
+	t.Parallel()
+	httpTest(t, nil, func(s *TestAgent) {
+		require := require.New(t)
+
+		// Create the variable, which must not exist yet
+		variable := &structs.VariableDecrypted{
+			Items: map[string]string{"user": "admin"},
+		}
+		req, err := http.NewRequest("PUT", "/v1/var/project/web?cas=0", encodeReq(variable))
+		require.NoError(err)
+		respW := httptest.NewRecorder()
+		obj, err := s.Server.VariableSpecificRequest(respW, req)
+		require.NoError(err)
+		require.NotEmpty(respW.HeaderMap.Get("X-Nomad-Index"))
+		created := obj.(*structs.VariableDecrypted)
+		require.Equal("project/web", created.Path)
+
+		// A stale check index is a conflict
+		req, err = http.NewRequest("PUT", "/v1/var/project/web?cas=0", encodeReq(variable))
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+		_, err = s.Server.VariableSpecificRequest(respW, req)
+		require.Error(err)
+		require.Equal(http.StatusConflict, err.(HTTPCodedError).Code())
+
+		// Query the variable
+		req, err = http.NewRequest("GET", "/v1/var/project/web", nil)
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+		obj, err = s.Server.VariableSpecificRequest(respW, req)
+		require.NoError(err)
+		out := obj.(*structs.VariableDecrypted)
+		require.Equal(variable.Items, out.Items)
+
+		// List the variables
+		req, err = http.NewRequest("GET", "/v1/vars?prefix=project", nil)
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+		obj, err = s.Server.VariablesListRequest(respW, req)
+		require.NoError(err)
+		require.Len(obj.([]*structs.VariableMetadata), 1)
+
+		// Delete the variable
+		req, err = http.NewRequest("DELETE", "/v1/var/project/web", nil)
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+		_, err = s.Server.VariableSpecificRequest(respW, req)
+		require.NoError(err)
+
+		req, err = http.NewRequest("GET", "/v1/var/project/web", nil)
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+		_, err = s.Server.VariableSpecificRequest(respW, req)
+		require.Error(err)
+		require.Equal(404, err.(HTTPCodedError).Code())
+	})
+}
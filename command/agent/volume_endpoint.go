@@ -0,0 +1,155 @@
+package agent
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+func (s *HTTPServer) VolumesRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "GET" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	args := structs.VolumeListRequest{
+		Type: req.URL.Query().Get("type"),
+	}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.VolumeListResponse
+	if err := s.agent.RPC("Volume.List", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.Volumes == nil {
+		out.Volumes = make([]*structs.VolumeListStub, 0)
+	}
+	return out.Volumes, nil
+}
+
+func (s *HTTPServer) VolumeSpecificRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	path := strings.TrimPrefix(req.URL.Path, "/v1/volume/")
+	switch {
+	case strings.HasSuffix(path, "/create"):
+		id := strings.TrimSuffix(path, "/create")
+		return s.volumeCreate(resp, req, id)
+	case strings.HasSuffix(path, "/detach"):
+		id := strings.TrimSuffix(path, "/detach")
+		return s.volumeDetach(resp, req, id)
+	}
+
+	if len(path) == 0 {
+		return nil, CodedError(400, "Missing Volume ID")
+	}
+	switch req.Method {
+	case "GET":
+		return s.volumeQuery(resp, req, path)
+	case "PUT", "POST":
+		return s.volumeRegister(resp, req, path, "Volume.Register")
+	case "DELETE":
+		return s.volumeDelete(resp, req, path)
+	default:
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+}
+
+func (s *HTTPServer) volumeQuery(resp http.ResponseWriter, req *http.Request,
+	id string) (interface{}, error) {
+	args := structs.VolumeSpecificRequest{
+		VolumeID: id,
+	}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.SingleVolumeResponse
+	if err := s.agent.RPC("Volume.GetVolume", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.Volume == nil {
+		return nil, CodedError(404, "volume not found")
+	}
+	return out.Volume, nil
+}
+
+func (s *HTTPServer) volumeCreate(resp http.ResponseWriter, req *http.Request,
+	id string) (interface{}, error) {
+	if req.Method != "PUT" && req.Method != "POST" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+	return s.volumeRegister(resp, req, id, "Volume.Create")
+}
+
+func (s *HTTPServer) volumeRegister(resp http.ResponseWriter, req *http.Request,
+	id, method string) (interface{}, error) {
+	// Parse the volume
+	var volume structs.Volume
+	if err := decodeBody(req, &volume); err != nil {
+		return nil, CodedError(500, err.Error())
+	}
+
+	// Ensure the volume ID matches
+	if volume.ID != id {
+		return nil, CodedError(400, "Volume ID does not match request path")
+	}
+
+	// Format the request
+	args := structs.VolumeRegisterRequest{
+		Volumes: []*structs.Volume{&volume},
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.GenericResponse
+	if err := s.agent.RPC(method, &args, &out); err != nil {
+		return nil, err
+	}
+	setIndex(resp, out.Index)
+	return nil, nil
+}
+
+func (s *HTTPServer) volumeDelete(resp http.ResponseWriter, req *http.Request,
+	id string) (interface{}, error) {
+
+	args := structs.VolumeDeleteRequest{
+		VolumeIDs: []string{id},
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.GenericResponse
+	if err := s.agent.RPC("Volume.Delete", &args, &out); err != nil {
+		return nil, err
+	}
+	setIndex(resp, out.Index)
+	return nil, nil
+}
+
+func (s *HTTPServer) volumeDetach(resp http.ResponseWriter, req *http.Request,
+	id string) (interface{}, error) {
+	if req.Method != "PUT" && req.Method != "POST" && req.Method != "DELETE" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	nodeID := req.URL.Query().Get("node")
+	if nodeID == "" {
+		return nil, CodedError(400, "Missing Node ID")
+	}
+
+	args := structs.VolumeDetachRequest{
+		VolumeID: id,
+		NodeID:   nodeID,
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.GenericResponse
+	if err := s.agent.RPC("Volume.Detach", &args, &out); err != nil {
+		return nil, err
+	}
+	setIndex(resp, out.Index)
+	return nil, nil
+}
@@ -0,0 +1,80 @@
+package agent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTP_VolumeCRUD(t *testing.T) {
+	t.Parallel()
+	httpTest(t, nil, func(s *TestAgent) {
+		require := require.New(t)
+
+		// Register a volume
+		volume := mock.Volume()
+		req, err := http.NewRequest("PUT", "/v1/volume/"+volume.ID, encodeReq(volume))
+		require.NoError(err)
+		respW := httptest.NewRecorder()
+		_, err = s.Server.VolumeSpecificRequest(respW, req)
+		require.NoError(err)
+		require.NotEmpty(respW.HeaderMap.Get("X-Nomad-Index"))
+
+		// The ID must match the path
+		req, err = http.NewRequest("PUT", "/v1/volume/other", encodeReq(volume))
+		require.NoError(err)
+		_, err = s.Server.VolumeSpecificRequest(httptest.NewRecorder(), req)
+		require.EqualError(err, "Volume ID does not match request path")
+
+		// Creating the existing volume fails
+		req, err = http.NewRequest("PUT", "/v1/volume/"+volume.ID+"/create", encodeReq(volume))
+		require.NoError(err)
+		_, err = s.Server.VolumeSpecificRequest(httptest.NewRecorder(), req)
+		require.Error(err)
+		require.Contains(err.Error(), "already exists")
+
+		// List the volumes
+		req, err = http.NewRequest("GET", "/v1/volumes?type=csi", nil)
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+		obj, err := s.Server.VolumesRequest(respW, req)
+		require.NoError(err)
+		stubs := obj.([]*structs.VolumeListStub)
+		require.Len(stubs, 1)
+		require.Equal(volume.ID, stubs[0].ID)
+
+		// Read the volume
+		req, err = http.NewRequest("GET", "/v1/volume/"+volume.ID, nil)
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+		obj, err = s.Server.VolumeSpecificRequest(respW, req)
+		require.NoError(err)
+		require.Equal(volume.ExternalID, obj.(*structs.Volume).ExternalID)
+
+		// Detaching requires a node
+		req, err = http.NewRequest("DELETE", "/v1/volume/"+volume.ID+"/detach", nil)
+		require.NoError(err)
+		_, err = s.Server.VolumeSpecificRequest(httptest.NewRecorder(), req)
+		require.EqualError(err, "Missing Node ID")
+
+		req, err = http.NewRequest("DELETE", "/v1/volume/"+volume.ID+"/detach?node=foo", nil)
+		require.NoError(err)
+		_, err = s.Server.VolumeSpecificRequest(httptest.NewRecorder(), req)
+		require.NoError(err)
+
+		// Delete the volume
+		req, err = http.NewRequest("DELETE", "/v1/volume/"+volume.ID, nil)
+		require.NoError(err)
+		_, err = s.Server.VolumeSpecificRequest(httptest.NewRecorder(), req)
+		require.NoError(err)
+
+		req, err = http.NewRequest("GET", "/v1/volume/"+volume.ID, nil)
+		require.NoError(err)
+		_, err = s.Server.VolumeSpecificRequest(httptest.NewRecorder(), req)
+		require.EqualError(err, "volume not found")
+	})
+}
@@ -0,0 +1,148 @@
+package command
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/api/contexts"
+	"github.com/posener/complete"
+)
+
+type MonitorCommand struct {
+	Meta
+}
+
+func (c *MonitorCommand) Help() string {
+	helpText := `
+Usage: nomad monitor [options]
+
+  Stream the logs of a Nomad agent. The logs of the agent the command is
+  connected to are streamed, or the logs of the agent of a client node when
+  -node-id is given. Only the logs emitted at the log level the agent runs
+  with are available. The stream ends when interrupted.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Monitor Options:
+
+  -log-level=<level>
+    The minimum level of the streamed logs. Valid levels are TRACE, DEBUG,
+    INFO, WARN and ERR. Defaults to INFO.
+
+  -node-id=<node-id>
+    The ID of the client node whose agent logs are streamed. Node ID prefixes
+    are accepted.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *MonitorCommand) Synopsis() string {
+	return "Stream logs from a Nomad agent"
+}
+
+func (c *MonitorCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-log-level": complete.PredictSet("TRACE", "DEBUG", "INFO", "WARN", "ERR"),
+			"-node-id": complete.PredictFunc(func(a complete.Args) []string {
+				client, err := c.Meta.Client()
+				if err != nil {
+					return nil
+				}
+
+				resp, _, err := client.Search().PrefixSearch(a.Last, contexts.Nodes, nil)
+				if err != nil {
+					return []string{}
+				}
+				return resp.Matches[contexts.Nodes]
+			}),
+		})
+}
+
+func (c *MonitorCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *MonitorCommand) Name() string { return "monitor" }
+
+func (c *MonitorCommand) Run(args []string) int {
+	var logLevel, nodeID string
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&logLevel, "log-level", "INFO", "")
+	flags.StringVar(&nodeID, "node-id", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got no arguments
+	if len(flags.Args()) != 0 {
+		c.Ui.Error("This command takes no arguments")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	// Resolve the node ID prefix
+	q := &api.QueryOptions{}
+	if nodeID != "" {
+		if len(nodeID) == 1 {
+			c.Ui.Error(fmt.Sprintf("Node ID must contain at least two characters."))
+			return 1
+		}
+
+		nodeID = sanitizeUUIDPrefix(nodeID)
+		nodes, _, err := client.Nodes().PrefixList(nodeID)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error querying node info: %s", err))
+			return 1
+		}
+		if len(nodes) == 0 {
+			c.Ui.Error(fmt.Sprintf("No node(s) with prefix %q found", nodeID))
+			return 1
+		}
+		if len(nodes) > 1 {
+			c.Ui.Error(fmt.Sprintf("Prefix matched multiple nodes\n\n%s",
+				formatNodeStubList(nodes, true)))
+			return 1
+		}
+		q.Params = map[string]string{"node_id": nodes[0].ID}
+	}
+
+	// Stop streaming when interrupted
+	stopCh := make(chan struct{})
+	signalCh := make(chan os.Signal, 1)
+	signal.Notify(signalCh, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signalCh)
+
+	logCh, errCh := client.Agent().Monitor(logLevel, stopCh, q)
+	for {
+		select {
+		case line, ok := <-logCh:
+			if !ok {
+				return 0
+			}
+			c.Ui.Output(line)
+		case err := <-errCh:
+			c.Ui.Error(fmt.Sprintf("Error monitoring logs: %s", err))
+			return 1
+		case <-signalCh:
+			close(stopCh)
+			return 0
+		}
+	}
+}
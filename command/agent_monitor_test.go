@@ -0,0 +1,39 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMonitorCommand_Implements(t *testing.T) {
+	t.Parallel()
+	var _ cli.Command = &MonitorCommand{}
+}
+
+func TestMonitorCommand_Fails(t *testing.T) {
+	t.Parallel()
+	srv, _, url := testServer(t, false, nil)
+	defer srv.Shutdown()
+
+	ui := new(cli.MockUi)
+	cmd := &MonitorCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	code := cmd.Run([]string{"some", "bad", "args"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), commandErrorText(cmd))
+	ui.ErrorWriter.Reset()
+
+	// Fails on unknown nodes
+	code = cmd.Run([]string{"-address=" + url, "-node-id=12345678-abcd-efab-cdef-123456789abc"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), "No node(s) with prefix")
+	ui.ErrorWriter.Reset()
+
+	// Fails on invalid log levels
+	code = cmd.Run([]string{"-address=" + url, "-log-level=bogus"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), "Unknown log level")
+}
@@ -15,7 +15,8 @@ func (f *AllocCommand) Help() string {
 Usage: nomad alloc <subcommand> [options] [args]
 
   This command groups subcommands for interacting with allocations. Users can
-  inspect the status, examine the filesystem or logs of an allocation.
+  inspect the status, examine the filesystem or logs of an allocation,
+  execute commands inside its tasks, or restart, stop and signal them.
 
   Examine an allocations status:
 
@@ -25,6 +26,18 @@ Usage: nomad alloc <subcommand> [options] [args]
 
       $ nomad alloc logs -f <alloc-id> <task>
 
+  Run a command inside a running task:
+
+      $ nomad alloc exec -task <task> <alloc-id> /bin/bash
+
+  Restart the tasks of an allocation in place:
+
+      $ nomad alloc restart <alloc-id>
+
+  Stop an allocation and reschedule it:
+
+      $ nomad alloc stop <alloc-id>
+
   Please see the individual subcommand help for detailed usage information.
 `
 
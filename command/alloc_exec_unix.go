@@ -0,0 +1,14 @@
+// +build !windows
+
+package command
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// setupWindowNotification sends the signal of terminal window resizes to ch.
+func setupWindowNotification(ch chan<- os.Signal) {
+	signal.Notify(ch, syscall.SIGWINCH)
+}
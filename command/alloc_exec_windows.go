@@ -0,0 +1,12 @@
+// +build windows
+
+package command
+
+import (
+	"os"
+)
+
+// setupWindowNotification is a no-op as windows does not signal terminal
+// window resizes.
+func setupWindowNotification(ch chan<- os.Signal) {
+}
@@ -58,9 +58,10 @@ FS Specific Options:
   -stat
     Show file stat information instead of displaying the file, or listing the directory.
 
-  -f
+  -f, -follow
     Causes the output to not stop when the end of the file is reached, but rather to
-    wait for additional output.
+    wait for additional output. Any file of the allocation directory can be
+    followed, not only the logs of its tasks.
 
   -tail
     Show the files contents with offsets relative to the end of the file. If no
@@ -88,6 +89,7 @@ func (c *AllocFSCommand) AutocompleteFlags() complete.Flags {
 			"-job":     complete.PredictAnything,
 			"-stat":    complete.PredictNothing,
 			"-f":       complete.PredictNothing,
+			"-follow":  complete.PredictNothing,
 			"-tail":    complete.PredictNothing,
 			"-n":       complete.PredictAnything,
 			"-c":       complete.PredictAnything,
@@ -122,6 +124,7 @@ func (f *AllocFSCommand) Run(args []string) int {
 	flags.BoolVar(&job, "job", false, "")
 	flags.BoolVar(&stat, "stat", false, "")
 	flags.BoolVar(&follow, "f", false, "")
+	flags.BoolVar(&follow, "follow", false, "")
 	flags.BoolVar(&tail, "tail", false, "")
 	flags.Int64Var(&numLines, "n", -1, "")
 	flags.Int64Var(&numBytes, "c", -1, "")
@@ -411,8 +411,11 @@ func buildDisplayMessage(event *api.TaskEvent) string {
 		} else {
 			desc = "Task successfully killed"
 		}
-	case api.TaskTerminated:
+	case api.TaskTerminated, api.TaskOOMKilled:
 		var parts []string
+		if event.Type == api.TaskOOMKilled {
+			parts = append(parts, "Task exceeded its memory limit and was killed by the OOM killer")
+		}
 		parts = append(parts, fmt.Sprintf("Exit Code: %d", event.ExitCode))
 
 		if event.Signal != 0 {
@@ -465,6 +468,8 @@ func buildDisplayMessage(event *api.TaskEvent) string {
 		desc = event.DriverMessage
 	case api.TaskLeaderDead:
 		desc = "Leader Task in Group dead"
+	case api.TaskCheckpointed:
+		desc = "Task state checkpointed for migration"
 	default:
 		desc = event.Message
 	}
@@ -542,6 +547,7 @@ func (c *AllocStatusCommand) outputTaskResources(alloc *api.Allocation, task str
 func (c *AllocStatusCommand) outputVerboseResourceUsage(task string, resourceUsage *api.ResourceUsage) {
 	memoryStats := resourceUsage.MemoryStats
 	cpuStats := resourceUsage.CpuStats
+	ioStats := resourceUsage.IOStats
 	deviceStats := resourceUsage.DeviceStats
 
 	if memoryStats != nil && len(memoryStats.Measured) > 0 {
@@ -565,6 +571,10 @@ func (c *AllocStatusCommand) outputVerboseResourceUsage(task string, resourceUsa
 				measuredStats = append(measuredStats, humanize.IBytes(memoryStats.KernelUsage))
 			case "Kernel Max Usage":
 				measuredStats = append(measuredStats, humanize.IBytes(memoryStats.KernelMaxUsage))
+			case "Usage":
+				measuredStats = append(measuredStats, humanize.IBytes(memoryStats.Usage))
+			case "Balloon Actual":
+				measuredStats = append(measuredStats, humanize.IBytes(memoryStats.BalloonActual))
 			}
 		}
 
@@ -606,6 +616,33 @@ func (c *AllocStatusCommand) outputVerboseResourceUsage(task string, resourceUsa
 		c.Ui.Output(formatList(out))
 	}
 
+	if ioStats != nil && len(ioStats.Measured) > 0 {
+		c.Ui.Output("")
+		c.Ui.Output("IO Stats")
+
+		// Sort the measured stats
+		sort.Strings(ioStats.Measured)
+
+		var measuredStats []string
+		for _, measured := range ioStats.Measured {
+			switch measured {
+			case "Read Bytes":
+				measuredStats = append(measuredStats, humanize.IBytes(ioStats.ReadBytes))
+			case "Write Bytes":
+				measuredStats = append(measuredStats, humanize.IBytes(ioStats.WriteBytes))
+			case "Read Ops":
+				measuredStats = append(measuredStats, fmt.Sprintf("%v", ioStats.ReadOps))
+			case "Write Ops":
+				measuredStats = append(measuredStats, fmt.Sprintf("%v", ioStats.WriteOps))
+			}
+		}
+
+		out := make([]string, 2)
+		out[0] = strings.Join(ioStats.Measured, "|")
+		out[1] = strings.Join(measuredStats, "|")
+		c.Ui.Output(formatList(out))
+	}
+
 	if len(deviceStats) > 0 {
 		c.Ui.Output("")
 		c.Ui.Output("Device Stats")
@@ -0,0 +1,55 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/nomad/client/allocrunner/taskrunner/getter"
+)
+
+type ArtifactGetterCommand struct {
+	Meta
+}
+
+func (c *ArtifactGetterCommand) Help() string {
+	helpText := `
+	This is a command used by Nomad internally to download artifacts in a sandbox
+	`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *ArtifactGetterCommand) Synopsis() string {
+	return "internal - download an artifact in a sandbox"
+}
+
+func (c *ArtifactGetterCommand) Run(args []string) int {
+	if len(args) != 0 {
+		c.Ui.Error("This command takes no arguments")
+		return 1
+	}
+
+	var req getter.Request
+	if err := json.NewDecoder(os.Stdin).Decode(&req); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error decoding artifact request: %v", err))
+		return 1
+	}
+	if !filepath.IsAbs(req.Dest) {
+		c.Ui.Error(fmt.Sprintf("Artifact destination must be an absolute path: %q", req.Dest))
+		return 1
+	}
+
+	// Only allow writing to the staging directory holding the destination
+	if err := getter.RestrictWrites(filepath.Dir(req.Dest)); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error restricting the sandbox: %v", err))
+		return 1
+	}
+
+	if err := getter.Fetch(&req); err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+	return 0
+}
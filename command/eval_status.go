@@ -32,7 +32,8 @@ Eval Status Options:
     Monitor an outstanding evaluation
 
   -verbose
-    Show full information.
+    Show full information, including the reason each node was rejected for
+    the failed placements.
 
   -json
     Output the evaluation in its JSON format.
@@ -246,6 +247,10 @@ func (c *EvalStatusCommand) Run(args []string) int {
 			}
 			c.Ui.Output(fmt.Sprintf("Task Group %q (failed to place %d %s):", tg, metrics.CoalescedFailures+1, noun))
 			c.Ui.Output(formatAllocMetrics(metrics, false, "  "))
+			if verbose && len(metrics.NodeFailures) != 0 {
+				c.Ui.Output("")
+				c.Ui.Output(formatNodeFailures(metrics))
+			}
 			c.Ui.Output("")
 		}
 
@@ -0,0 +1,228 @@
+package command
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/nomad/jobspec2"
+	"github.com/posener/complete"
+)
+
+// fmtExtensions are the extensions of the job files formatted in directories.
+var fmtExtensions = []string{".nomad", ".hcl"}
+
+type FormatCommand struct {
+	Meta
+
+	check     bool
+	list      bool
+	write     bool
+	recursive bool
+
+	// unformatted is whether a file isn't canonically formatted, and failed
+	// whether a file couldn't be formatted.
+	unformatted bool
+	failed      bool
+
+	// The fields below can be overwritten for tests
+	testStdin io.Reader
+}
+
+func (c *FormatCommand) Help() string {
+	helpText := `
+Usage: nomad fmt [options] [<path> ...]
+
+  Rewrites Nomad job files to a canonical format. Blocks are indented by two
+  spaces, the "=" of consecutive attributes are aligned and the spacing
+  between tokens and blank lines are normalized.
+
+  Each path is either a job file or a directory, in which case the files with
+  a .nomad or .hcl extension it contains are formatted. If no path is given,
+  the current directory is used. If the path is "-", the job file is read from
+  stdin and the formatted output is written to stdout.
+
+Format Options:
+
+  -check
+    Check if the files are formatted without modifying them. The files that
+    aren't formatted are listed and the exit code is 1 if any exist.
+
+  -list=true
+    List the files whose formatting differs.
+
+  -write=true
+    Overwrite the files with their formatted contents.
+
+  -recursive
+    Also format the job files in the subdirectories of directories.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *FormatCommand) Synopsis() string {
+	return "Rewrites Nomad job files to canonical format"
+}
+
+func (c *FormatCommand) AutocompleteFlags() complete.Flags {
+	return complete.Flags{
+		"-check":     complete.PredictNothing,
+		"-list":      complete.PredictNothing,
+		"-write":     complete.PredictNothing,
+		"-recursive": complete.PredictNothing,
+	}
+}
+
+func (c *FormatCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictOr(complete.PredictDirs("*"),
+		complete.PredictFiles("*.nomad"), complete.PredictFiles("*.hcl"))
+}
+
+func (c *FormatCommand) Name() string { return "fmt" }
+
+func (c *FormatCommand) Run(args []string) int {
+	flags := c.Meta.FlagSet(c.Name(), FlagSetNone)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&c.check, "check", false, "")
+	flags.BoolVar(&c.list, "list", true, "")
+	flags.BoolVar(&c.write, "write", true, "")
+	flags.BoolVar(&c.recursive, "recursive", false, "")
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Checking never modifies the files
+	if c.check {
+		c.write = false
+	}
+
+	paths := flags.Args()
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+
+	for _, path := range paths {
+		if path == "-" {
+			c.formatStdin()
+			continue
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error reading %q: %s", path, err))
+			c.failed = true
+			continue
+		}
+		if info.IsDir() {
+			c.formatDir(path)
+		} else {
+			c.formatFile(path, info.Mode())
+		}
+	}
+
+	if c.failed || (c.check && c.unformatted) {
+		return 1
+	}
+	return 0
+}
+
+// formatStdin formats the job file read from stdin, writing the formatted
+// output to stdout unless only checking the formatting.
+func (c *FormatCommand) formatStdin() {
+	var stdin io.Reader = os.Stdin
+	if c.testStdin != nil {
+		stdin = c.testStdin
+	}
+
+	src, err := ioutil.ReadAll(stdin)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error reading stdin: %s", err))
+		c.failed = true
+		return
+	}
+
+	out, err := jobspec2.Format("stdin.hcl", src)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error formatting stdin: %s", err))
+		c.failed = true
+		return
+	}
+
+	if !bytes.Equal(src, out) {
+		c.unformatted = true
+	}
+	if !c.check {
+		c.Ui.Output(strings.TrimSuffix(string(out), "\n"))
+	}
+}
+
+// formatDir formats the job files of the directory, and of its
+// subdirectories if recursive.
+func (c *FormatCommand) formatDir(dir string) {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error reading directory %q: %s", dir, err))
+		c.failed = true
+		return
+	}
+
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		switch {
+		case entry.IsDir():
+			if c.recursive && !strings.HasPrefix(entry.Name(), ".") {
+				c.formatDir(path)
+			}
+		case isJobFile(entry.Name()):
+			c.formatFile(path, entry.Mode())
+		}
+	}
+}
+
+// formatFile formats the job file, listing it and overwriting it if its
+// formatting differs.
+func (c *FormatCommand) formatFile(path string, mode os.FileMode) {
+	src, err := ioutil.ReadFile(path)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error reading %q: %s", path, err))
+		c.failed = true
+		return
+	}
+
+	out, err := jobspec2.Format(path, src)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error formatting %q: %s", path, err))
+		c.failed = true
+		return
+	}
+
+	if bytes.Equal(src, out) {
+		return
+	}
+	c.unformatted = true
+
+	if c.list {
+		c.Ui.Output(path)
+	}
+	if c.write {
+		if err := ioutil.WriteFile(path, out, mode.Perm()); err != nil {
+			c.Ui.Error(fmt.Sprintf("Error writing %q: %s", path, err))
+			c.failed = true
+		}
+	}
+}
+
+// isJobFile returns whether the file name has the extension of a job file.
+func isJobFile(name string) bool {
+	ext := filepath.Ext(name)
+	for _, e := range fmtExtensions {
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
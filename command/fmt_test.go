@@ -0,0 +1,120 @@
+package command
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	fmtUnformatted = `job "example" {
+    type="batch"
+  datacenters = ["dc1"]
+}
+`
+	fmtFormatted = `job "example" {
+  type        = "batch"
+  datacenters = ["dc1"]
+}
+`
+)
+
+func TestFormatCommand_Implements(t *testing.T) {
+	t.Parallel()
+	var _ cli.Command = &FormatCommand{}
+}
+
+func TestFormatCommand_Files(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "nomad-fmt")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	unformatted := filepath.Join(dir, "unformatted.nomad")
+	formatted := filepath.Join(dir, "formatted.hcl")
+	other := filepath.Join(dir, "other.txt")
+	nested := filepath.Join(dir, "nested", "nested.nomad")
+	require.NoError(os.Mkdir(filepath.Dir(nested), 0755))
+	require.NoError(ioutil.WriteFile(unformatted, []byte(fmtUnformatted), 0644))
+	require.NoError(ioutil.WriteFile(formatted, []byte(fmtFormatted), 0644))
+	require.NoError(ioutil.WriteFile(other, []byte(fmtUnformatted), 0644))
+	require.NoError(ioutil.WriteFile(nested, []byte(fmtUnformatted), 0644))
+
+	// Checking lists the unformatted files without modifying them
+	ui := cli.NewMockUi()
+	cmd := &FormatCommand{Meta: Meta{Ui: ui}}
+	require.Equal(1, cmd.Run([]string{"-check", dir}))
+	require.Equal(unformatted+"\n", ui.OutputWriter.String())
+	content, err := ioutil.ReadFile(unformatted)
+	require.NoError(err)
+	require.Equal(fmtUnformatted, string(content))
+
+	// Formatting overwrites the unformatted files, recursively if requested
+	ui = cli.NewMockUi()
+	cmd = &FormatCommand{Meta: Meta{Ui: ui}}
+	require.Equal(0, cmd.Run([]string{"-recursive", dir}))
+	out := ui.OutputWriter.String()
+	require.Contains(out, unformatted)
+	require.Contains(out, nested)
+	require.NotContains(out, formatted)
+
+	for _, path := range []string{unformatted, formatted, nested} {
+		content, err := ioutil.ReadFile(path)
+		require.NoError(err)
+		require.Equal(fmtFormatted, string(content))
+	}
+	content, err = ioutil.ReadFile(other)
+	require.NoError(err)
+	require.Equal(fmtUnformatted, string(content))
+
+	// Checking succeeds once formatted
+	ui = cli.NewMockUi()
+	cmd = &FormatCommand{Meta: Meta{Ui: ui}}
+	require.Equal(0, cmd.Run([]string{"-check", "-recursive", dir}))
+	require.Empty(ui.OutputWriter.String())
+}
+
+func TestFormatCommand_Stdin(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	ui := cli.NewMockUi()
+	cmd := &FormatCommand{Meta: Meta{Ui: ui}, testStdin: strings.NewReader(fmtUnformatted)}
+	require.Equal(0, cmd.Run([]string{"-"}))
+	require.Equal(fmtFormatted, ui.OutputWriter.String())
+
+	ui = cli.NewMockUi()
+	cmd = &FormatCommand{Meta: Meta{Ui: ui}, testStdin: strings.NewReader(fmtUnformatted)}
+	require.Equal(1, cmd.Run([]string{"-check", "-"}))
+	require.Empty(ui.OutputWriter.String())
+}
+
+func TestFormatCommand_Fails(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "nomad-fmt")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	// Fails on missing files
+	ui := cli.NewMockUi()
+	cmd := &FormatCommand{Meta: Meta{Ui: ui}}
+	require.Equal(1, cmd.Run([]string{filepath.Join(dir, "missing.nomad")}))
+	require.Contains(ui.ErrorWriter.String(), "Error reading")
+
+	// Fails on invalid job files
+	invalid := filepath.Join(dir, "invalid.nomad")
+	require.NoError(ioutil.WriteFile(invalid, []byte(`job "example" {`), 0644))
+	ui = cli.NewMockUi()
+	cmd = &FormatCommand{Meta: Meta{Ui: ui}}
+	require.Equal(1, cmd.Run([]string{invalid}))
+	require.Contains(ui.ErrorWriter.String(), "Error formatting")
+}
@@ -13,6 +13,7 @@ import (
 	gg "github.com/hashicorp/go-getter"
 	"github.com/hashicorp/nomad/api"
 	"github.com/hashicorp/nomad/jobspec"
+	"github.com/hashicorp/nomad/jobspec2"
 	"github.com/kr/text"
 	"github.com/posener/complete"
 
@@ -376,12 +377,22 @@ READ:
 }
 
 type JobGetter struct {
+	// hcl1 parses the jobfile with the HCL parser instead of the HCL2 one
+	hcl1 bool
+
 	// The fields below can be overwritten for tests
 	testStdin io.Reader
 }
 
 // StructJob returns the Job struct from jobfile.
 func (j *JobGetter) ApiJob(jpath string) (*api.Job, error) {
+	return j.ApiJobWithArgs(jpath, nil, nil)
+}
+
+// ApiJobWithArgs returns the Job struct from jobfile, setting the variables
+// of HCL2 jobfiles from the "name=value" vars, the varfiles and the
+// environment.
+func (j *JobGetter) ApiJobWithArgs(jpath string, vars, varfiles []string) (*api.Job, error) {
 	var jobfile io.Reader
 	switch jpath {
 	case "-":
@@ -429,8 +440,32 @@ func (j *JobGetter) ApiJob(jpath string) (*api.Job, error) {
 		}
 	}
 
-	// Parse the JobFile
-	jobStruct, err := jobspec.Parse(jobfile)
+	body, err := ioutil.ReadAll(jobfile)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading job file from %s: %v", jpath, err)
+	}
+
+	// Parse the JobFile. JSON jobfiles are only supported by the HCL parser.
+	var jobStruct *api.Job
+	if j.hcl1 || bytes.HasPrefix(bytes.TrimSpace(body), []byte("{")) {
+		if len(vars) != 0 || len(varfiles) != 0 {
+			return nil, fmt.Errorf("Variables are only supported by HCL2 job files")
+		}
+		jobStruct, err = jobspec.Parse(bytes.NewReader(body))
+	} else {
+		path := jpath
+		if path == "-" {
+			path = "stdin.hcl"
+		}
+		jobStruct, err = jobspec2.ParseWithConfig(&jobspec2.ParseConfig{
+			Path:     path,
+			Body:     body,
+			AllowFS:  true,
+			ArgVars:  vars,
+			VarFiles: varfiles,
+			Envs:     os.Environ(),
+		})
+	}
 	if err != nil {
 		return nil, fmt.Errorf("Error parsing job file from %s: %v", jpath, err)
 	}
@@ -16,6 +16,7 @@ import (
 	"github.com/hashicorp/nomad/helper/flatmap"
 	"github.com/kr/pretty"
 	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
 )
 
 func TestHelpers_FormatKV(t *testing.T) {
@@ -271,6 +272,63 @@ func TestJobGetter_LocalFile(t *testing.T) {
 	}
 }
 
+// Test APIJob with an HCL2 jobfile setting variables
+func TestJobGetter_Variables(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	hcl := `
+variable "datacenter" {
+  type = string
+}
+
+variable "count" {
+  type    = number
+  default = 1
+}
+
+job "job1" {
+  datacenters = [var.datacenter]
+
+  group "group1" {
+    count = var.count
+
+    task "task1" {
+      driver = "exec"
+    }
+  }
+}
+`
+	fh, err := ioutil.TempFile("", "nomad")
+	require.NoError(err)
+	defer os.Remove(fh.Name())
+	_, err = fh.WriteString(hcl)
+	require.NoError(err)
+
+	varFile, err := ioutil.TempFile("", "nomad-vars")
+	require.NoError(err)
+	defer os.Remove(varFile.Name())
+	_, err = varFile.WriteString("count = 3\n")
+	require.NoError(err)
+
+	j := &JobGetter{}
+	aj, err := j.ApiJobWithArgs(fh.Name(), []string{"datacenter=dc2"}, []string{varFile.Name()})
+	require.NoError(err)
+	require.Equal([]string{"dc2"}, aj.Datacenters)
+	require.Equal(3, *aj.TaskGroups[0].Count)
+
+	// Unset variables are an error
+	_, err = j.ApiJob(fh.Name())
+	require.Error(err)
+	require.Contains(err.Error(), `variable "datacenter"`)
+
+	// Variables are not supported with -hcl1
+	j = &JobGetter{hcl1: true}
+	_, err = j.ApiJobWithArgs(fh.Name(), []string{"datacenter=dc2"}, nil)
+	require.Error(err)
+	require.Contains(err.Error(), "only supported by HCL2")
+}
+
 // Test StructJob with jobfile from HTTP Server
 func TestJobGetter_HTTPServer(t *testing.T) {
 	t.Parallel()
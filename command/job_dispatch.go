@@ -41,6 +41,11 @@ Dispatch Options:
     once to inject multiple metadata key/value pairs. Arbitrary keys are not
     allowed. The parameterized job must allow the key to be merged.
 
+  -idempotency-token <token>
+    Optional token that uniquely identifies the dispatch. If a job was already
+    dispatched from the parameterized job with the same token, its ID is
+    returned and no new job is dispatched.
+
   -detach
     Return immediately instead of entering monitor mode. After job dispatch,
     the evaluation ID will be printed to the screen, which can be used to
@@ -59,9 +64,10 @@ func (c *JobDispatchCommand) Synopsis() string {
 func (c *JobDispatchCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
-			"-meta":    complete.PredictAnything,
-			"-detach":  complete.PredictNothing,
-			"-verbose": complete.PredictNothing,
+			"-meta":              complete.PredictAnything,
+			"-idempotency-token": complete.PredictAnything,
+			"-detach":            complete.PredictNothing,
+			"-verbose":           complete.PredictNothing,
 		})
 }
 
@@ -85,12 +91,14 @@ func (c *JobDispatchCommand) Name() string { return "job dispatch" }
 func (c *JobDispatchCommand) Run(args []string) int {
 	var detach, verbose bool
 	var meta []string
+	var idempotencyToken string
 
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
 	flags.BoolVar(&detach, "detach", false, "")
 	flags.BoolVar(&verbose, "verbose", false, "")
 	flags.Var((*flaghelper.StringFlag)(&meta), "meta", "")
+	flags.StringVar(&idempotencyToken, "idempotency-token", "", "")
 
 	if err := flags.Parse(args); err != nil {
 		return 1
@@ -148,7 +156,7 @@ func (c *JobDispatchCommand) Run(args []string) int {
 	}
 
 	// Dispatch the job
-	resp, _, err := client.Jobs().Dispatch(job, metaMap, payload, nil)
+	resp, _, err := client.Jobs().DispatchIdempotent(job, metaMap, payload, idempotencyToken, nil)
 	if err != nil {
 		c.Ui.Error(fmt.Sprintf("Failed to dispatch job: %s", err))
 		return 1
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/hashicorp/nomad/api"
+	flaghelper "github.com/hashicorp/nomad/helper/flag-helpers"
 	"github.com/hashicorp/nomad/scheduler"
 	"github.com/posener/complete"
 )
@@ -71,9 +72,29 @@ Plan Options:
     Determines whether the diff between the remote job and planned job is shown.
     Defaults to true.
 
+  -hcl1
+    Parses the job file in the HCLv1 format instead of the HCL2 one.
+
+  -json
+    Output the plan in its JSON format. The output contains the structured
+    diff, the annotations and the failed placements of the plan, as well as a
+    summary of the desired updates of all the task groups. The exit code is
+    the same as without -json.
+
+  -t
+    Format and display the plan using a Go template.
+
   -policy-override
     Sets the flag to force override any soft mandatory Sentinel policies.
 
+  -var 'key=value'
+    Variable for the HCL2 job file. This flag may be repeated to set
+    several variables.
+
+  -var-file=path
+    Path to an HCL2 file setting the values of variables of the job file.
+    This flag may be repeated to read several files.
+
   -verbose
     Increase diff verbosity.
 `
@@ -88,6 +109,11 @@ func (c *JobPlanCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
 			"-diff":            complete.PredictNothing,
+			"-hcl1":            complete.PredictNothing,
+			"-var":             complete.PredictAnything,
+			"-var-file":        complete.PredictFiles("*.hcl"),
+			"-json":            complete.PredictNothing,
+			"-t":               complete.PredictAnything,
 			"-policy-override": complete.PredictNothing,
 			"-verbose":         complete.PredictNothing,
 		})
@@ -99,11 +125,18 @@ func (c *JobPlanCommand) AutocompleteArgs() complete.Predictor {
 
 func (c *JobPlanCommand) Name() string { return "job plan" }
 func (c *JobPlanCommand) Run(args []string) int {
-	var diff, policyOverride, verbose bool
+	var diff, policyOverride, verbose, json bool
+	var tmpl string
+	var varArgs, varFiles []string
 
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
 	flags.BoolVar(&diff, "diff", true, "")
+	flags.BoolVar(&c.JobGetter.hcl1, "hcl1", false, "")
+	flags.Var((*flaghelper.StringFlag)(&varArgs), "var", "")
+	flags.Var((*flaghelper.StringFlag)(&varFiles), "var-file", "")
+	flags.BoolVar(&json, "json", false, "")
+	flags.StringVar(&tmpl, "t", "", "")
 	flags.BoolVar(&policyOverride, "policy-override", false, "")
 	flags.BoolVar(&verbose, "verbose", false, "")
 
@@ -121,7 +154,7 @@ func (c *JobPlanCommand) Run(args []string) int {
 
 	path := args[0]
 	// Get Job struct from Jobfile
-	job, err := c.JobGetter.ApiJob(args[0])
+	job, err := c.JobGetter.ApiJobWithArgs(args[0], varArgs, varFiles)
 	if err != nil {
 		c.Ui.Error(fmt.Sprintf("Error getting job struct: %s", err))
 		return 255
@@ -160,6 +193,17 @@ func (c *JobPlanCommand) Run(args []string) int {
 		return 255
 	}
 
+	if json || len(tmpl) > 0 {
+		out, err := Format(json, tmpl, newJobPlanOutput(resp))
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 255
+		}
+
+		c.Ui.Output(out)
+		return getExitCode(resp)
+	}
+
 	// Print the diff if not disabled
 	if diff {
 		c.Ui.Output(fmt.Sprintf("%s\n",
@@ -242,6 +286,60 @@ func (c *JobPlanCommand) addPreemptions(resp *api.JobPlanResponse) {
 
 }
 
+// jobPlanOutput is the machine-readable output of the plan.
+type jobPlanOutput struct {
+	*api.JobPlanResponse
+
+	// Summary totals the effects of the plan
+	Summary *jobPlanSummary
+}
+
+// jobPlanSummary totals the desired updates of the task groups of the plan so
+// its effects can be checked without walking the annotations.
+type jobPlanSummary struct {
+	// ExitCode is the exit code of the command for the plan
+	ExitCode int
+
+	Ignore            uint64
+	Place             uint64
+	Migrate           uint64
+	Stop              uint64
+	InPlaceUpdate     uint64
+	DestructiveUpdate uint64
+	Canary            uint64
+	Preemptions       uint64
+
+	// FailedPlacements is the number of allocations that could not be
+	// placed
+	FailedPlacements uint64
+}
+
+func newJobPlanOutput(resp *api.JobPlanResponse) *jobPlanOutput {
+	summary := &jobPlanSummary{
+		ExitCode: getExitCode(resp),
+	}
+	if resp.Annotations != nil {
+		for _, d := range resp.Annotations.DesiredTGUpdates {
+			summary.Ignore += d.Ignore
+			summary.Place += d.Place
+			summary.Migrate += d.Migrate
+			summary.Stop += d.Stop
+			summary.InPlaceUpdate += d.InPlaceUpdate
+			summary.DestructiveUpdate += d.DestructiveUpdate
+			summary.Canary += d.Canary
+			summary.Preemptions += d.Preemptions
+		}
+	}
+	for _, metrics := range resp.FailedTGAllocs {
+		summary.FailedPlacements += uint64(metrics.CoalescedFailures) + 1
+	}
+
+	return &jobPlanOutput{
+		JobPlanResponse: resp,
+		Summary:         summary,
+	}
+}
+
 type namespaceIdPair struct {
 	id        string
 	namespace string
@@ -283,7 +381,7 @@ func formatDryRun(resp *api.JobPlanResponse, job *api.Job) string {
 		out = "[bold][green]- All tasks successfully allocated.[reset]\n"
 	} else {
 		// Change the output depending on if we are a system job or not
-		if job.Type != nil && *job.Type == "system" {
+		if job.Type != nil && (*job.Type == "system" || *job.Type == "sysbatch") {
 			out = "[bold][yellow]- WARNING: Failed to place allocations on all nodes.[reset]\n"
 		} else {
 			out = "[bold][yellow]- WARNING: Failed to place all allocations.[reset]\n"
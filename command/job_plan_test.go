@@ -1,6 +1,7 @@
 package command
 
 import (
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -255,3 +256,59 @@ func TestPlanCommad_Preemptions(t *testing.T) {
 	require.Contains(out, "batch")
 	require.Contains(out, "service")
 }
+
+func TestPlanCommand_JSON(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	srv, _, url := testServer(t, false, nil)
+	defer srv.Shutdown()
+
+	fh, err := ioutil.TempFile("", "nomad")
+	require.NoError(err)
+	defer os.Remove(fh.Name())
+	_, err = fh.WriteString(`
+job "job1" {
+	type = "service"
+	datacenters = [ "dc1" ]
+	group "group1" {
+		count = 2
+		task "task1" {
+			driver = "exec"
+			resources = {
+				cpu = 1000
+				memory = 512
+			}
+		}
+	}
+}`)
+	require.NoError(err)
+
+	ui := new(cli.MockUi)
+	cmd := &JobPlanCommand{Meta: Meta{Ui: ui}}
+
+	// The job can not be placed without clients
+	code := cmd.Run([]string{"-address=" + url, "-json", fh.Name()})
+	require.Equal(1, code, ui.ErrorWriter.String())
+
+	var out struct {
+		api.JobPlanResponse
+		Summary jobPlanSummary
+	}
+	require.NoError(json.Unmarshal(ui.OutputWriter.Bytes(), &out))
+
+	require.Equal("Added", out.Diff.Type)
+	require.Equal(uint64(2), out.Annotations.DesiredTGUpdates["group1"].Place)
+	require.Contains(out.FailedTGAllocs, "group1")
+
+	require.Equal(1, out.Summary.ExitCode)
+	require.Equal(uint64(2), out.Summary.Place)
+	require.Equal(uint64(0), out.Summary.DestructiveUpdate)
+	require.Equal(uint64(2), out.Summary.FailedPlacements)
+
+	// Templates are applied to the same output
+	ui.OutputWriter.Reset()
+	code = cmd.Run([]string{"-address=" + url, "-t", "{{ .Summary.Place }}", fh.Name()})
+	require.Equal(1, code, ui.ErrorWriter.String())
+	require.Equal("2", strings.TrimSpace(ui.OutputWriter.String()))
+}
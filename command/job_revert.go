@@ -101,11 +101,11 @@ func (c *JobRevertCommand) Run(args []string) int {
 	jobID := args[0]
 	revertVersion, ok, err := parseVersion(args[1])
 	if !ok {
-		c.Ui.Error("The job version to revert to must be specified using the -job-version flag")
+		c.Ui.Error("The job version to revert to must be specified")
 		return 1
 	}
 	if err != nil {
-		c.Ui.Error(fmt.Sprintf("Failed to parse job-version flag: %v", err))
+		c.Ui.Error(fmt.Sprintf("Failed to parse job version: %v", err))
 		return 1
 	}
 
@@ -127,7 +127,7 @@ func (c *JobRevertCommand) Run(args []string) int {
 	// Prefix lookup matched a single job
 	resp, _, err := client.Jobs().Revert(jobs[0].ID, revertVersion, nil, nil)
 	if err != nil {
-		c.Ui.Error(fmt.Sprintf("Error retrieving job versions: %s", err))
+		c.Ui.Error(fmt.Sprintf("Error reverting job: %s", err))
 		return 1
 	}
 
@@ -36,6 +36,15 @@ func TestJobRevertCommand_Fails(t *testing.T) {
 		t.Fatalf("expected failed query error, got: %s", out)
 	}
 	ui.ErrorWriter.Reset()
+
+	// Fails on an invalid version
+	if code := cmd.Run([]string{"-address=nope", "foo", "v1"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "Failed to parse job version") {
+		t.Fatalf("expected parse error, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
 }
 
 func TestJobRevertCommand_AutocompleteArgs(t *testing.T) {
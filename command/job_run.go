@@ -11,6 +11,7 @@ import (
 
 	"github.com/hashicorp/nomad/api"
 	"github.com/hashicorp/nomad/helper"
+	flaghelper "github.com/hashicorp/nomad/helper/flag-helpers"
 	"github.com/posener/complete"
 )
 
@@ -75,6 +76,9 @@ Run Options:
     the evaluation ID will be printed to the screen, which can be used to
     examine the evaluation using the eval-status command.
 
+  -hcl1
+    Parses the job file in the HCLv1 format instead of the HCL2 one.
+
   -output
     Output the JSON that would be submitted to the HTTP API without submitting
     the job.
@@ -88,6 +92,14 @@ Run Options:
     the job file. This overrides the token found in $VAULT_TOKEN environment
     variable and that found in the job.
 
+  -var 'key=value'
+    Variable for the HCL2 job file. This flag may be repeated to set
+    several variables.
+
+  -var-file=path
+    Path to an HCL2 file setting the values of variables of the job file.
+    This flag may be repeated to read several files.
+
   -verbose
     Display full information.
 `
@@ -103,6 +115,9 @@ func (c *JobRunCommand) AutocompleteFlags() complete.Flags {
 		complete.Flags{
 			"-check-index":     complete.PredictNothing,
 			"-detach":          complete.PredictNothing,
+			"-hcl1":            complete.PredictNothing,
+			"-var":             complete.PredictAnything,
+			"-var-file":        complete.PredictFiles("*.hcl"),
 			"-verbose":         complete.PredictNothing,
 			"-vault-token":     complete.PredictAnything,
 			"-output":          complete.PredictNothing,
@@ -119,10 +134,14 @@ func (c *JobRunCommand) Name() string { return "job run" }
 func (c *JobRunCommand) Run(args []string) int {
 	var detach, verbose, output, override bool
 	var checkIndexStr, vaultToken string
+	var varArgs, varFiles []string
 
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
 	flags.BoolVar(&detach, "detach", false, "")
+	flags.BoolVar(&c.JobGetter.hcl1, "hcl1", false, "")
+	flags.Var((*flaghelper.StringFlag)(&varArgs), "var", "")
+	flags.Var((*flaghelper.StringFlag)(&varFiles), "var-file", "")
 	flags.BoolVar(&verbose, "verbose", false, "")
 	flags.BoolVar(&output, "output", false, "")
 	flags.BoolVar(&override, "policy-override", false, "")
@@ -148,7 +167,7 @@ func (c *JobRunCommand) Run(args []string) int {
 	}
 
 	// Get Job struct from Jobfile
-	job, err := c.JobGetter.ApiJob(args[0])
+	job, err := c.JobGetter.ApiJobWithArgs(args[0], varArgs, varFiles)
 	if err != nil {
 		c.Ui.Error(fmt.Sprintf("Error getting job struct: %s", err))
 		return 1
@@ -0,0 +1,171 @@
+package command
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/nomad/api/contexts"
+	"github.com/posener/complete"
+)
+
+type JobScaleCommand struct {
+	Meta
+}
+
+func (c *JobScaleCommand) Help() string {
+	helpText := `
+Usage: nomad job scale [options] <job> [<group>] <count>
+
+  Set the count of a task group of a job. The task group can be omitted if the
+  job has a single task group. Each scaling request is recorded as a scaling
+  event of the task group, which can be listed with the "nomad job
+  scaling-events" command.
+
+  Upon successful scaling, an interactive monitor session will start to
+  display log lines as the job's count is updated. The monitor will exit once
+  all allocations are finished placing. It is safe to exit the monitor early
+  using ctrl+c.
+
+  When ACLs are enabled, this command requires a token with the 'scale-job'
+  or 'submit-job' capability for the job's namespace.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Scale Options:
+
+  -message
+    A message describing the reason of the scaling, recorded with the scaling
+    event.
+
+  -detach
+    Return immediately instead of entering monitor mode. After the scaling
+    request is submitted, the evaluation ID will be printed to the screen,
+    which can be used to examine the evaluation using the eval-status command.
+
+  -verbose
+    Display full information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *JobScaleCommand) Synopsis() string {
+	return "Change the count of a task group of a job"
+}
+
+func (c *JobScaleCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-message": complete.PredictAnything,
+			"-detach":  complete.PredictNothing,
+			"-verbose": complete.PredictNothing,
+		})
+}
+
+func (c *JobScaleCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictFunc(func(a complete.Args) []string {
+		client, err := c.Meta.Client()
+		if err != nil {
+			return nil
+		}
+
+		resp, _, err := client.Search().PrefixSearch(a.Last, contexts.Jobs, nil)
+		if err != nil {
+			return []string{}
+		}
+		return resp.Matches[contexts.Jobs]
+	})
+}
+
+func (c *JobScaleCommand) Name() string { return "job scale" }
+
+func (c *JobScaleCommand) Run(args []string) int {
+	var detach, verbose bool
+	var message string
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&message, "message", "", "")
+	flags.BoolVar(&detach, "detach", false, "")
+	flags.BoolVar(&verbose, "verbose", false, "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got the job, the optional group and the count
+	args = flags.Args()
+	if l := len(args); l != 2 && l != 3 {
+		c.Ui.Error("This command takes two or three arguments: <job> [<group>] <count>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	count, err := strconv.Atoi(args[len(args)-1])
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to parse count %q: %v", args[len(args)-1], err))
+		return 1
+	}
+	if count < 0 {
+		c.Ui.Error("Count can't be negative")
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	// Truncate the id unless full length is requested
+	length := shortId
+	if verbose {
+		length = fullId
+	}
+
+	// Default to the only task group of the job
+	jobID := args[0]
+	var group string
+	if len(args) == 3 {
+		group = args[1]
+	} else {
+		job, _, err := client.Jobs().Info(jobID, nil)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error querying job: %s", err))
+			return 1
+		}
+		if len(job.TaskGroups) != 1 {
+			c.Ui.Error(fmt.Sprintf("Job %q has %d task groups, the task group to scale must be specified",
+				jobID, len(job.TaskGroups)))
+			return 1
+		}
+		group = *job.TaskGroups[0].Name
+	}
+
+	if message == "" {
+		message = "submitted using the Nomad CLI"
+	}
+	resp, _, err := client.Jobs().Scale(jobID, group, &count, message, false, nil, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error scaling job: %s", err))
+		return 1
+	}
+
+	// Nothing to monitor if no evaluation was created, either because the
+	// count didn't change or because the job is periodic or parameterized
+	if resp.EvalID == "" {
+		c.Ui.Output(fmt.Sprintf("Scaling event recorded for task group %q of job %q", group, jobID))
+		return 0
+	}
+
+	if detach {
+		c.Ui.Output("Evaluation ID: " + resp.EvalID)
+		return 0
+	}
+
+	mon := newMonitor(c.Ui, client, length)
+	return mon.monitor(resp.EvalID, false)
+}
@@ -0,0 +1,80 @@
+package command
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobScaleCommand_Implements(t *testing.T) {
+	t.Parallel()
+	var _ cli.Command = &JobScaleCommand{}
+	var _ cli.Command = &JobScalingEventsCommand{}
+}
+
+func TestJobScaleCommand_Fails(t *testing.T) {
+	t.Parallel()
+	ui := new(cli.MockUi)
+	cmd := &JobScaleCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	if code := cmd.Run([]string{"some"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, commandErrorText(cmd)) {
+		t.Fatalf("expected help output, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	// Fails on an invalid count
+	if code := cmd.Run([]string{"job", "group", "many"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "Failed to parse count") {
+		t.Fatalf("unexpected error: %v", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	// Fails on connection failure
+	if code := cmd.Run([]string{"-address=nope", "job", "group", "2"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "Error scaling job") {
+		t.Fatalf("expected failed query error, got: %s", out)
+	}
+}
+
+func TestJobScaleCommand_Run(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	srv, _, url := testServer(t, false, nil)
+	defer srv.Shutdown()
+
+	// Create a job with a single task group
+	state := srv.Agent.Server().State()
+	job := mock.Job()
+	require.NoError(state.UpsertJob(1000, job))
+
+	// Scale the only task group of the job
+	ui := new(cli.MockUi)
+	cmd := &JobScaleCommand{Meta: Meta{Ui: ui}}
+	code := cmd.Run([]string{"-address=" + url, "-detach", "-message=more capacity", job.ID, "3"})
+	require.Equal(0, code, ui.ErrorWriter.String())
+	require.Contains(ui.OutputWriter.String(), "Evaluation ID")
+
+	out, err := state.JobByID(nil, job.Namespace, job.ID)
+	require.NoError(err)
+	require.Equal(3, out.TaskGroups[0].Count)
+
+	// List the scaling events
+	ui = new(cli.MockUi)
+	eventsCmd := &JobScalingEventsCommand{Meta: Meta{Ui: ui}}
+	code = eventsCmd.Run([]string{"-address=" + url, "-verbose", job.ID})
+	require.Equal(0, code, ui.ErrorWriter.String())
+	output := ui.OutputWriter.String()
+	require.Contains(output, job.TaskGroups[0].Name)
+	require.Contains(output, "more capacity")
+}
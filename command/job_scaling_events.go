@@ -0,0 +1,175 @@
+package command
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/api/contexts"
+	"github.com/posener/complete"
+)
+
+type JobScalingEventsCommand struct {
+	Meta
+}
+
+func (c *JobScalingEventsCommand) Help() string {
+	helpText := `
+Usage: nomad job scaling-events [options] <job>
+
+  List the most recent scaling events of the task groups of a job, the most
+  recent first. Scaling events record when a task group was scaled, who
+  requested it and why.
+
+  When ACLs are enabled, this command requires a token with the 'read-job'
+  capability for the job's namespace.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Scaling Events Options:
+
+  -verbose
+    Display full information, including the accessor ID of the requester and
+    the metadata of the events.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *JobScalingEventsCommand) Synopsis() string {
+	return "Display the scaling events of a job"
+}
+
+func (c *JobScalingEventsCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-verbose": complete.PredictNothing,
+		})
+}
+
+func (c *JobScalingEventsCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictFunc(func(a complete.Args) []string {
+		client, err := c.Meta.Client()
+		if err != nil {
+			return nil
+		}
+
+		resp, _, err := client.Search().PrefixSearch(a.Last, contexts.Jobs, nil)
+		if err != nil {
+			return []string{}
+		}
+		return resp.Matches[contexts.Jobs]
+	})
+}
+
+func (c *JobScalingEventsCommand) Name() string { return "job scaling-events" }
+
+func (c *JobScalingEventsCommand) Run(args []string) int {
+	var verbose bool
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&verbose, "verbose", false, "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got exactly one job
+	args = flags.Args()
+	if len(args) != 1 {
+		c.Ui.Error("This command takes one argument: <job>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	status, _, err := client.Jobs().ScaleStatus(args[0], nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error querying job scaling status: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(formatScalingEvents(status, verbose))
+	return 0
+}
+
+// formatScalingEvents formats the scaling events of the task groups of a job,
+// the most recent first.
+func formatScalingEvents(status *api.JobScaleStatusResponse, verbose bool) string {
+	type groupEvent struct {
+		group string
+		event api.ScalingEvent
+	}
+	var events []groupEvent
+	for group, tg := range status.TaskGroups {
+		for _, event := range tg.Events {
+			events = append(events, groupEvent{group, event})
+		}
+	}
+	if len(events) == 0 {
+		return "No scaling events found"
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].event.Time > events[j].event.Time
+	})
+
+	length := shortId
+	header := "Task Group|Count|PrevCount|Error|Date|Eval ID|Message"
+	if verbose {
+		length = fullId
+		header += "|Accessor ID|Meta"
+	}
+
+	out := make([]string, 0, len(events)+1)
+	out = append(out, header)
+	for _, e := range events {
+		count := "-"
+		if e.event.Count != nil {
+			count = strconv.FormatInt(*e.event.Count, 10)
+		}
+		evalID := "-"
+		if e.event.EvalID != nil {
+			evalID = limit(*e.event.EvalID, length)
+		}
+		line := fmt.Sprintf("%s|%s|%d|%t|%s|%s|%s",
+			e.group, count, e.event.PreviousCount, e.event.Error,
+			formatUnixNanoTime(e.event.Time), evalID, e.event.Message)
+		if verbose {
+			accessor := e.event.AccessorID
+			if accessor == "" {
+				accessor = "-"
+			}
+			line += fmt.Sprintf("|%s|%s", accessor, formatScalingEventMeta(e.event.Meta))
+		}
+		out = append(out, line)
+	}
+	return formatList(out)
+}
+
+// formatScalingEventMeta formats the metadata of a scaling event as sorted
+// key=value pairs.
+func formatScalingEventMeta(meta map[string]interface{}) string {
+	if len(meta) == 0 {
+		return "-"
+	}
+	keys := make([]string, 0, len(meta))
+	for k := range meta {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, fmt.Sprintf("%s=%v", k, meta[k]))
+	}
+	return strings.Join(pairs, ",")
+}
@@ -155,7 +155,11 @@ func (c *JobStatusCommand) Run(args []string) int {
 		c.Ui.Error(fmt.Sprintf("Prefix matched multiple jobs\n\n%s", createStatusListOutput(jobs)))
 		return 1
 	}
-	// Prefix lookup matched a single job
+	// Prefix lookup matched a single job, possibly in another namespace when
+	// querying all the namespaces
+	if jobs[0].Namespace != "" {
+		client.SetNamespace(jobs[0].Namespace)
+	}
 	job, _, err := client.Jobs().Info(jobs[0].ID, nil)
 	if err != nil {
 		c.Ui.Error(fmt.Sprintf("Error querying job: %s", err))
@@ -508,7 +512,7 @@ func (c *JobStatusCommand) outputJobSummary(client *api.Client, job *api.Job) er
 	if !periodic && !parameterizedJob {
 		c.Ui.Output(c.Colorize().Color("\n[bold]Summary[reset]"))
 		summaries := make([]string, len(summary.Summary)+1)
-		summaries[0] = "Task Group|Queued|Starting|Running|Failed|Complete|Lost"
+		summaries[0] = "Task Group|Queued|Starting|Running|Failed|Complete|Lost|Unknown"
 		taskGroups := make([]string, 0, len(summary.Summary))
 		for taskGroup := range summary.Summary {
 			taskGroups = append(taskGroups, taskGroup)
@@ -516,10 +520,10 @@ func (c *JobStatusCommand) outputJobSummary(client *api.Client, job *api.Job) er
 		sort.Strings(taskGroups)
 		for idx, taskGroup := range taskGroups {
 			tgs := summary.Summary[taskGroup]
-			summaries[idx+1] = fmt.Sprintf("%s|%d|%d|%d|%d|%d|%d",
+			summaries[idx+1] = fmt.Sprintf("%s|%d|%d|%d|%d|%d|%d|%d",
 				taskGroup, tgs.Queued, tgs.Starting,
 				tgs.Running, tgs.Failed,
-				tgs.Complete, tgs.Lost,
+				tgs.Complete, tgs.Lost, tgs.Unknown,
 			)
 		}
 		c.Ui.Output(formatList(summaries))
@@ -632,11 +636,27 @@ func (c *JobStatusCommand) outputFailedPlacements(failedEval *api.Evaluation) {
 
 // list general information about a list of jobs
 func createStatusListOutput(jobs []*api.JobListStub) string {
+	// Show the namespaces of the jobs when listing several namespaces
+	multipleNamespaces := false
+	for _, job := range jobs {
+		if job.Namespace != jobs[0].Namespace {
+			multipleNamespaces = true
+			break
+		}
+	}
+
 	out := make([]string, len(jobs)+1)
 	out[0] = "ID|Type|Priority|Status|Submit Date"
+	if multipleNamespaces {
+		out[0] = "ID|Namespace|Type|Priority|Status|Submit Date"
+	}
 	for i, job := range jobs {
+		id := job.ID
+		if multipleNamespaces {
+			id = fmt.Sprintf("%s|%s", job.ID, job.Namespace)
+		}
 		out[i+1] = fmt.Sprintf("%s|%s|%d|%s|%s",
-			job.ID,
+			id,
 			getTypeString(job),
 			job.Priority,
 			getStatusString(job.Status, &job.Stop),
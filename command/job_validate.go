@@ -7,6 +7,7 @@ import (
 	multierror "github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/nomad/api"
 	"github.com/hashicorp/nomad/command/agent"
+	flaghelper "github.com/hashicorp/nomad/helper/flag-helpers"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/posener/complete"
 )
@@ -27,6 +28,19 @@ Alias: nomad validate
   If the supplied path is "-", the jobfile is read from stdin. Otherwise
   it is read from the file at the supplied path or downloaded and
   read from URL specified.
+
+Validate Options:
+
+  -hcl1
+    Parses the job file in the HCLv1 format instead of the HCL2 one.
+
+  -var 'key=value'
+    Variable for the HCL2 job file. This flag may be repeated to set
+    several variables.
+
+  -var-file=path
+    Path to an HCL2 file setting the values of variables of the job file.
+    This flag may be repeated to read several files.
 `
 	return strings.TrimSpace(helpText)
 }
@@ -36,7 +50,11 @@ func (c *JobValidateCommand) Synopsis() string {
 }
 
 func (c *JobValidateCommand) AutocompleteFlags() complete.Flags {
-	return nil
+	return complete.Flags{
+		"-hcl1":     complete.PredictNothing,
+		"-var":      complete.PredictAnything,
+		"-var-file": complete.PredictFiles("*.hcl"),
+	}
 }
 
 func (c *JobValidateCommand) AutocompleteArgs() complete.Predictor {
@@ -46,8 +64,13 @@ func (c *JobValidateCommand) AutocompleteArgs() complete.Predictor {
 func (c *JobValidateCommand) Name() string { return "job validate" }
 
 func (c *JobValidateCommand) Run(args []string) int {
+	var varArgs, varFiles []string
+
 	flags := c.Meta.FlagSet(c.Name(), FlagSetNone)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&c.JobGetter.hcl1, "hcl1", false, "")
+	flags.Var((*flaghelper.StringFlag)(&varArgs), "var", "")
+	flags.Var((*flaghelper.StringFlag)(&varFiles), "var-file", "")
 	if err := flags.Parse(args); err != nil {
 		return 1
 	}
@@ -61,7 +84,7 @@ func (c *JobValidateCommand) Run(args []string) int {
 	}
 
 	// Get Job struct from Jobfile
-	job, err := c.JobGetter.ApiJob(args[0])
+	job, err := c.JobGetter.ApiJobWithArgs(args[0], varArgs, varFiles)
 	if err != nil {
 		c.Ui.Error(fmt.Sprintf("Error getting job struct: %s", err))
 		return 1
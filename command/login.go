@@ -0,0 +1,259 @@
+package command
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/posener/complete"
+	"github.com/skratchdot/open-golang/open"
+)
+
+const (
+	// defaultOIDCCallbackAddr is the address the callback server listens on
+	// while logging in with an OIDC auth method.
+	defaultOIDCCallbackAddr = "localhost:4649"
+
+	// oidcCallbackPath is the path the OIDC provider redirects to once the
+	// user authenticated.
+	oidcCallbackPath = "/oidc/callback"
+)
+
+type LoginCommand struct {
+	Meta
+
+	// openURL opens the auth URL of the OIDC provider. It defaults to opening
+	// the URL in the browser and is overridden in tests.
+	openURL func(string) error
+}
+
+func (c *LoginCommand) Help() string {
+	helpText := `
+Usage: nomad login [options]
+
+  Login is used to exchange the credentials of an external identity provider
+  for a Nomad ACL token, using an ACL auth method. The auth method's binding
+  rules determine the policies of the token.
+
+  For OIDC auth methods, the provider is opened in the browser and a local
+  callback server waits for the user to authenticate. For JWT auth methods,
+  the JWT signed by a trusted issuer is passed with the -login-token flag.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Login Options:
+
+  -method=""
+    Sets the name of the ACL auth method to log in with. The default auth
+    method is used if not set.
+
+  -type="OIDC"
+    Sets the type of the auth method. Supported types are "OIDC" and "JWT".
+
+  -login-token=""
+    Sets the JWT to log in with. Required for JWT auth methods.
+
+  -oidc-callback-addr="localhost:4649"
+    Sets the address of the local callback server. The callback URL
+    http://<addr>/oidc/callback must be allowed by the auth method.
+
+  -json
+    Output the ACL token in a JSON format.
+
+  -t
+    Format and display the ACL token using a Go template.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *LoginCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-method":             complete.PredictAnything,
+			"-type":               complete.PredictSet(api.ACLAuthMethodTypeOIDC, api.ACLAuthMethodTypeJWT),
+			"-login-token":        complete.PredictAnything,
+			"-oidc-callback-addr": complete.PredictAnything,
+			"-json":               complete.PredictNothing,
+			"-t":                  complete.PredictAnything,
+		})
+}
+
+func (c *LoginCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *LoginCommand) Synopsis() string {
+	return "Login to Nomad using an auth method"
+}
+
+func (c *LoginCommand) Name() string { return "login" }
+
+func (c *LoginCommand) Run(args []string) int {
+	var method, methodType, callbackAddr, loginToken, tmpl string
+	var json bool
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&method, "method", "", "")
+	flags.StringVar(&methodType, "type", api.ACLAuthMethodTypeOIDC, "")
+	flags.StringVar(&callbackAddr, "oidc-callback-addr", defaultOIDCCallbackAddr, "")
+	flags.StringVar(&loginToken, "login-token", "", "")
+	flags.BoolVar(&json, "json", false, "")
+	flags.StringVar(&tmpl, "t", "", "")
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got no arguments
+	args = flags.Args()
+	if l := len(args); l != 0 {
+		c.Ui.Error("This command takes no arguments")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	isJWT := strings.EqualFold(methodType, api.ACLAuthMethodTypeJWT)
+	if !isJWT && !strings.EqualFold(methodType, api.ACLAuthMethodTypeOIDC) {
+		c.Ui.Error(fmt.Sprintf("Unsupported ACL auth method type %q", methodType))
+		return 1
+	}
+	if isJWT && loginToken == "" {
+		c.Ui.Error("The -login-token flag is required for JWT auth methods")
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	var token *api.ACLToken
+	if isJWT {
+		token, _, err = client.ACLAuth().Login(&api.ACLLoginRequest{
+			AuthMethodName: method,
+			LoginToken:     loginToken,
+		}, nil)
+	} else {
+		token, err = c.loginOIDC(client, method, callbackAddr)
+	}
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error logging in: %s", err))
+		return 1
+	}
+
+	if json || len(tmpl) > 0 {
+		out, err := Format(json, tmpl, token)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+
+		c.Ui.Output(out)
+		return 0
+	}
+
+	c.Ui.Output(fmt.Sprintf("Successfully logged in via %s\n", methodType))
+	c.Ui.Output(formatKVACLToken(token))
+	return 0
+}
+
+// oidcCallback is the result of the OIDC provider redirecting to the
+// callback server.
+type oidcCallback struct {
+	code  string
+	state string
+	err   error
+}
+
+// loginOIDC logs in with the OIDC auth method. A callback server is started
+// on the address before opening the auth URL of the provider, and the
+// authorization code it is redirected with is exchanged for an ACL token.
+func (c *LoginCommand) loginOIDC(client *api.Client, method, callbackAddr string) (*api.ACLToken, error) {
+	// The state protects the callback against cross-site request forgery and
+	// the nonce the ID token against replay
+	state := uuid.Generate()
+	nonce := uuid.Generate()
+	redirectURI := "http://" + callbackAddr + oidcCallbackPath
+
+	ln, err := net.Listen("tcp", callbackAddr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start callback server: %v", err)
+	}
+	defer ln.Close()
+
+	callbackCh := make(chan *oidcCallback, 1)
+	mux := http.NewServeMux()
+	mux.HandleFunc(oidcCallbackPath, func(w http.ResponseWriter, req *http.Request) {
+		q := req.URL.Query()
+		cb := &oidcCallback{code: q.Get("code"), state: q.Get("state")}
+		if e := q.Get("error"); e != "" {
+			cb.err = fmt.Errorf("OIDC provider returned an error: %s %s", e, q.Get("error_description"))
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintln(w, "Login failed, check the Nomad CLI for details.")
+		} else {
+			fmt.Fprintln(w, "Login successful, you can close this window and return to the Nomad CLI.")
+		}
+
+		select {
+		case callbackCh <- cb:
+		default:
+		}
+	})
+	srv := &http.Server{Handler: mux}
+	go srv.Serve(ln)
+	defer srv.Close()
+
+	urlResp, _, err := client.ACLAuth().GetAuthURL(&api.ACLOIDCAuthURLRequest{
+		AuthMethodName: method,
+		RedirectURI:    redirectURI,
+		ClientNonce:    nonce,
+		State:          state,
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	openURL := c.openURL
+	if openURL == nil {
+		openURL = open.Start
+	}
+	c.Ui.Output(fmt.Sprintf("Complete the login via your OIDC provider. Launching browser to:\n\n    %s\n", urlResp.AuthURL))
+	if err := openURL(urlResp.AuthURL); err != nil {
+		c.Ui.Warn(fmt.Sprintf("Error opening browser, open the URL manually: %s", err))
+	}
+
+	// Wait for the provider to redirect to the callback server
+	signalCh := make(chan os.Signal, 1)
+	signal.Notify(signalCh, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signalCh)
+
+	var cb *oidcCallback
+	select {
+	case cb = <-callbackCh:
+	case <-signalCh:
+		return nil, fmt.Errorf("interrupted")
+	}
+	if cb.err != nil {
+		return nil, cb.err
+	}
+	if cb.state != state {
+		return nil, fmt.Errorf("callback state does not match the auth URL's")
+	}
+
+	token, _, err := client.ACLAuth().CompleteAuth(&api.ACLOIDCCompleteAuthRequest{
+		AuthMethodName: method,
+		ClientNonce:    nonce,
+		Code:           cb.code,
+		RedirectURI:    redirectURI,
+	}, nil)
+	return token, err
+}
@@ -0,0 +1,127 @@
+package command
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/command/agent"
+	"github.com/hashicorp/nomad/lib/auth"
+	"github.com/hashicorp/nomad/lib/auth/authtest"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoginCommand_OIDC(t *testing.T) {
+	require := require.New(t)
+	t.Parallel()
+	config := func(c *agent.Config) {
+		c.ACL.Enabled = true
+	}
+
+	srv, _, url := testServer(t, true, config)
+	state := srv.Agent.Server().State()
+	defer srv.Shutdown()
+
+	provider := authtest.NewTestOIDCProvider()
+	defer provider.Close()
+	provider.SetClaims(auth.Claims{"groups": []interface{}{"engineering"}})
+
+	// Find a free address for the callback server
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(err)
+	callbackAddr := ln.Addr().String()
+	ln.Close()
+
+	method := mock.ACLAuthMethod()
+	method.Default = true
+	method.Config.OIDCDiscoveryURL = provider.URL()
+	method.Config.OIDCClientID = authtest.TestOIDCClientID
+	method.Config.OIDCClientSecret = authtest.TestOIDCClientSecret
+	method.Config.BoundAudiences = nil
+	method.Config.SigningAlgs = []string{"ES256"}
+	method.Config.AllowedRedirectURIs = []string{fmt.Sprintf("http://%s/oidc/callback", callbackAddr)}
+	require.NoError(state.UpsertACLAuthMethods(1000, []*structs.ACLAuthMethod{method}))
+
+	rule := mock.ACLBindingRule()
+	rule.AuthMethod = method.Name
+	rule.Selector = `"engineering" in list.groups`
+	require.NoError(state.UpsertACLBindingRules(1001, []*structs.ACLBindingRule{rule}))
+
+	// Instead of the browser, follow the redirects of the provider to the
+	// callback server
+	ui := new(cli.MockUi)
+	cmd := &LoginCommand{
+		Meta: Meta{Ui: ui, flagAddress: url},
+		openURL: func(authURL string) error {
+			resp, err := http.Get(authURL)
+			if err != nil {
+				return err
+			}
+			return resp.Body.Close()
+		},
+	}
+
+	code := cmd.Run([]string{"-address=" + url, "-oidc-callback-addr=" + callbackAddr})
+	require.Equal(0, code, ui.ErrorWriter.String())
+
+	out := ui.OutputWriter.String()
+	require.Contains(out, "Successfully logged in via OIDC")
+	require.Contains(out, "[engineering-read]")
+	require.Contains(out, "Expiration Time")
+
+	// JWT methods require a login token
+	code = cmd.Run([]string{"-address=" + url, "-type=JWT"})
+	require.Equal(1, code)
+}
+
+func TestLoginCommand_JWT(t *testing.T) {
+	require := require.New(t)
+	t.Parallel()
+	config := func(c *agent.Config) {
+		c.ACL.Enabled = true
+	}
+
+	srv, _, url := testServer(t, true, config)
+	state := srv.Agent.Server().State()
+	defer srv.Shutdown()
+
+	issuer := authtest.NewTestOIDCProvider()
+	defer issuer.Close()
+
+	method := mock.ACLAuthMethod()
+	method.Type = structs.ACLAuthMethodTypeJWT
+	method.Config = &structs.ACLAuthMethodConfig{
+		JWTValidationPubKeys: []string{issuer.PublicKeyPEM()},
+		BoundIssuer:          "https://ci.example.com",
+		BoundAudiences:       []string{"nomad"},
+		SigningAlgs:          []string{"ES256"},
+		ListClaimMappings:    map[string]string{"groups": "groups"},
+	}
+	require.NoError(state.UpsertACLAuthMethods(1000, []*structs.ACLAuthMethod{method}))
+
+	rule := mock.ACLBindingRule()
+	rule.AuthMethod = method.Name
+	rule.Selector = `"engineering" in list.groups`
+	require.NoError(state.UpsertACLBindingRules(1001, []*structs.ACLBindingRule{rule}))
+
+	loginToken := issuer.SignToken(auth.Claims{
+		"iss":    "https://ci.example.com",
+		"aud":    "nomad",
+		"exp":    float64(time.Now().Add(time.Minute).Unix()),
+		"groups": []interface{}{"engineering"},
+	})
+
+	ui := new(cli.MockUi)
+	cmd := &LoginCommand{Meta: Meta{Ui: ui, flagAddress: url}}
+	code := cmd.Run([]string{"-address=" + url, "-type=JWT", "-method=" + method.Name, "-login-token=" + loginToken})
+	require.Equal(0, code, ui.ErrorWriter.String())
+
+	out := ui.OutputWriter.String()
+	require.Contains(out, "Successfully logged in via JWT")
+	require.Contains(out, "[engineering-read]")
+}
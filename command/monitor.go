@@ -405,3 +405,22 @@ func formatAllocMetrics(metrics *api.AllocationMetric, scores bool, prefix strin
 	out = strings.TrimSuffix(out, "\n")
 	return out
 }
+
+// formatNodeFailures returns a table of the reason each node was rejected for
+// a placement.
+func formatNodeFailures(metrics *api.AllocationMetric) string {
+	if len(metrics.NodeFailures) == 0 {
+		return ""
+	}
+
+	out := make([]string, len(metrics.NodeFailures)+1)
+	out[0] = "Node|Name|Reason"
+	for i, failure := range metrics.NodeFailures {
+		reason := fmt.Sprintf("constraint %q filtered", failure.Constraint)
+		if failure.Dimension != "" {
+			reason = fmt.Sprintf("dimension %q exhausted", failure.Dimension)
+		}
+		out[i+1] = fmt.Sprintf("%s|%s|%s", failure.NodeID, failure.NodeName, reason)
+	}
+	return formatList(out)
+}
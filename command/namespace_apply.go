@@ -3,6 +3,7 @@ package command
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/nomad/api"
 	flaghelper "github.com/hashicorp/nomad/helper/flag-helpers"
@@ -31,6 +32,18 @@ Apply Options:
 
   -description
     An optional description for the namespace.
+
+  -job-gc-threshold
+    Overrides the job GC threshold of the servers for the jobs of the
+    namespace, such as "168h". A threshold of "0s" removes the override.
+
+  -eval-gc-threshold
+    Overrides the eval GC threshold of the servers for the jobs of the
+    namespace.
+
+  -alloc-gc-threshold
+    Overrides the threshold terminal allocations of the jobs of the namespace
+    are garbage collected after.
 `
 	return strings.TrimSpace(helpText)
 }
@@ -38,8 +51,11 @@ Apply Options:
 func (c *NamespaceApplyCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
-			"-description": complete.PredictAnything,
-			"-quota":       QuotaPredictor(c.Meta.Client),
+			"-description":        complete.PredictAnything,
+			"-quota":              QuotaPredictor(c.Meta.Client),
+			"-job-gc-threshold":   complete.PredictAnything,
+			"-eval-gc-threshold":  complete.PredictAnything,
+			"-alloc-gc-threshold": complete.PredictAnything,
 		})
 }
 
@@ -55,6 +71,7 @@ func (c *NamespaceApplyCommand) Name() string { return "namespace apply" }
 
 func (c *NamespaceApplyCommand) Run(args []string) int {
 	var description, quota *string
+	var jobGC, evalGC, allocGC *time.Duration
 
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
@@ -66,6 +83,9 @@ func (c *NamespaceApplyCommand) Run(args []string) int {
 		quota = &s
 		return nil
 	}), "quota", "")
+	flags.Var(durationFlag(&jobGC), "job-gc-threshold", "")
+	flags.Var(durationFlag(&evalGC), "eval-gc-threshold", "")
+	flags.Var(durationFlag(&allocGC), "alloc-gc-threshold", "")
 
 	if err := flags.Parse(args); err != nil {
 		return 1
@@ -114,6 +134,20 @@ func (c *NamespaceApplyCommand) Run(args []string) int {
 	if quota != nil {
 		ns.Quota = *quota
 	}
+	if jobGC != nil || evalGC != nil || allocGC != nil {
+		if ns.GC == nil {
+			ns.GC = &api.GCConfig{}
+		}
+		if jobGC != nil {
+			ns.GC.JobThreshold = *jobGC
+		}
+		if evalGC != nil {
+			ns.GC.EvalThreshold = *evalGC
+		}
+		if allocGC != nil {
+			ns.GC.AllocThreshold = *allocGC
+		}
+	}
 
 	_, err = client.Namespaces().Register(ns, nil)
 	if err != nil {
@@ -124,3 +158,16 @@ func (c *NamespaceApplyCommand) Run(args []string) int {
 	c.Ui.Output(fmt.Sprintf("Successfully applied namespace %q!", name))
 	return 0
 }
+
+// durationFlag returns a flag setting the duration to the parsed value of the
+// flag, leaving it nil if the flag is not set.
+func durationFlag(d **time.Duration) flaghelper.FuncVar {
+	return func(s string) error {
+		v, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		*d = &v
+		return nil
+	}
+}
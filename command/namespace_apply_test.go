@@ -5,7 +5,9 @@ package command
 import (
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/hashicorp/nomad/api"
 	"github.com/mitchellh/cli"
 	"github.com/stretchr/testify/assert"
 )
@@ -58,3 +60,33 @@ func TestNamespaceApplyCommand_Good(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Len(t, namespaces, 2)
 }
+
+func TestNamespaceApplyCommand_GC(t *testing.T) {
+	t.Parallel()
+
+	// Create a server
+	srv, client, url := testServer(t, true, nil)
+	defer srv.Shutdown()
+
+	ui := new(cli.MockUi)
+	cmd := &NamespaceApplyCommand{Meta: Meta{Ui: ui}}
+
+	// Create a namespace overriding the GC thresholds
+	if code := cmd.Run([]string{"-address=" + url, "-job-gc-threshold=168h", "-alloc-gc-threshold=24h", "foo"}); code != 0 {
+		t.Fatalf("expected exit 0, got: %d; %v", code, ui.ErrorWriter.String())
+	}
+
+	// Update a single threshold
+	if code := cmd.Run([]string{"-address=" + url, "-alloc-gc-threshold=48h", "foo"}); code != 0 {
+		t.Fatalf("expected exit 0, got: %d; %v", code, ui.ErrorWriter.String())
+	}
+
+	ns, _, err := client.Namespaces().Info("foo", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, &api.GCConfig{JobThreshold: 168 * time.Hour, AllocThreshold: 48 * time.Hour}, ns.GC)
+
+	// Invalid durations are rejected
+	if code := cmd.Run([]string{"-address=" + url, "-eval-gc-threshold=foo", "foo"}); code != 1 {
+		t.Fatalf("expected exit 1, got: %d", code)
+	}
+}
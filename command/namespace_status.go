@@ -3,6 +3,7 @@ package command
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/nomad/api"
 	"github.com/posener/complete"
@@ -113,10 +114,24 @@ func formatNamespaceBasics(ns *api.Namespace) string {
 		fmt.Sprintf("Description|%s", ns.Description),
 		fmt.Sprintf("Quota|%s", ns.Quota),
 	}
+	if gc := ns.GC; gc != nil {
+		basic = append(basic,
+			fmt.Sprintf("Job GC Threshold|%s", formatGCThreshold(gc.JobThreshold)),
+			fmt.Sprintf("Eval GC Threshold|%s", formatGCThreshold(gc.EvalThreshold)),
+			fmt.Sprintf("Alloc GC Threshold|%s", formatGCThreshold(gc.AllocThreshold)))
+	}
 
 	return formatKV(basic)
 }
 
+// formatGCThreshold formats a GC threshold, which is unset when zero.
+func formatGCThreshold(threshold time.Duration) string {
+	if threshold == 0 {
+		return "<default>"
+	}
+	return threshold.String()
+}
+
 func getNamespace(client *api.Namespaces, ns string) (match *api.Namespace, possible []*api.Namespace, err error) {
 	// Do a prefix lookup
 	namespaces, _, err := client.PrefixList(ns, nil)
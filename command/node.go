@@ -32,6 +32,10 @@ Usage: nomad node <subcommand> [options] [args]
 
       $ nomad node drain -enable -deadline 4h <node-id>
 
+  Set dynamic metadata on the local node:
+
+      $ nomad node meta apply rack=r12
+
   Please see the individual subcommand help for detailed usage information.
 `
 
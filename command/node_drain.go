@@ -251,8 +251,14 @@ func (c *NodeDrainCommand) Run(args []string) int {
 	// If monitoring the drain start the montior and return when done
 	if monitor {
 		c.Ui.Info(fmt.Sprintf("%s: Monitoring node %q: Ctrl-C to detach monitoring", formatTime(time.Now()), node.ID))
-		c.monitorDrain(client, context.Background(), node, 0, ignoreSystem)
-		return 0
+
+		// Honor the drain spec stored by the servers so that system
+		// allocations are not waited upon if the drain ignores them
+		if node.DrainStrategy != nil {
+			ignoreSystem = ignoreSystem || node.DrainStrategy.IgnoreSystemJobs
+			c.outputDrainDeadline(node.DrainStrategy)
+		}
+		return c.monitorDrain(client, context.Background(), node, 0, ignoreSystem)
 	}
 
 	// Confirm drain if the node was a prefix match.
@@ -309,12 +315,28 @@ func (c *NodeDrainCommand) Run(args []string) int {
 		now := time.Now()
 		c.Ui.Info(fmt.Sprintf("%s: Ctrl-C to stop monitoring: will not cancel the node drain", formatTime(now)))
 		c.Ui.Output(fmt.Sprintf("%s: Node %q drain strategy set", formatTime(now), node.ID))
-		c.monitorDrain(client, context.Background(), node, meta.LastIndex, ignoreSystem)
+		return c.monitorDrain(client, context.Background(), node, meta.LastIndex, ignoreSystem)
 	}
 	return 0
 }
 
-func (c *NodeDrainCommand) monitorDrain(client *api.Client, ctx context.Context, node *api.Node, index uint64, ignoreSystem bool) {
+// outputDrainDeadline outputs when the allocations remaining on the node are
+// force stopped by the drain.
+func (c *NodeDrainCommand) outputDrainDeadline(strategy *api.DrainStrategy) {
+	switch {
+	case strategy.Deadline < 0:
+		c.Ui.Output(fmt.Sprintf("%s: Drain deadline: force", formatTime(time.Now())))
+	case strategy.Deadline == 0:
+		c.Ui.Output(fmt.Sprintf("%s: Drain deadline: none", formatTime(time.Now())))
+	case !strategy.ForceDeadline.IsZero():
+		c.Ui.Output(fmt.Sprintf("%s: Drain deadline: %s", formatTime(time.Now()), formatTime(strategy.ForceDeadline)))
+	}
+}
+
+// monitorDrain outputs the progress of the drain of the node until it
+// completes and returns the exit code of the command.
+func (c *NodeDrainCommand) monitorDrain(client *api.Client, ctx context.Context, node *api.Node, index uint64, ignoreSystem bool) int {
+	code := 0
 	outCh := client.Nodes().MonitorDrain(ctx, node.ID, index, ignoreSystem)
 	for msg := range outCh {
 		switch msg.Level {
@@ -324,8 +346,10 @@ func (c *NodeDrainCommand) monitorDrain(client *api.Client, ctx context.Context,
 			c.Ui.Warn(fmt.Sprintf("%s: %s", formatTime(time.Now()), msg))
 		case api.MonitorMsgLevelError:
 			c.Ui.Error(fmt.Sprintf("%s: %s", formatTime(time.Now()), msg))
+			code = 1
 		default:
 			c.Ui.Output(fmt.Sprintf("%s: %s", formatTime(time.Now()), msg))
 		}
 	}
+	return code
 }
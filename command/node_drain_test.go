@@ -236,6 +236,7 @@ func TestNodeDrainCommand_Monitor(t *testing.T) {
 			require.Contains(out, fmt.Sprintf("Alloc %q marked for migration", a.ID))
 			require.Contains(out, fmt.Sprintf("Alloc %q draining", a.ID))
 		}
+		require.Contains(out, fmt.Sprintf("allocation(s) remaining on node %q", nodeID))
 
 		expected := fmt.Sprintf("All allocations on node %q have stopped.\n", nodeID)
 		if !strings.HasSuffix(out, expected) {
@@ -0,0 +1,70 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/mitchellh/cli"
+)
+
+type NodeMetaCommand struct {
+	Meta
+}
+
+func (f *NodeMetaCommand) Help() string {
+	helpText := `
+Usage: nomad node meta <subcommand> [options] [args]
+
+  This command groups subcommands for interacting with the metadata of nodes.
+  The dynamic metadata of a node is set at runtime, persisted by the client and
+  overrides the metadata of its configuration. Constraints and affinities
+  target it using ${meta.<key>} or ${dynamic_meta.<key>}.
+
+  Read the metadata of the local node:
+
+      $ nomad node meta read
+
+  Set and unset dynamic metadata of a node:
+
+      $ nomad node meta apply -node-id <node-id> -unset rack zone=us-east-1a
+
+  Please see the individual subcommand help for detailed usage information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (f *NodeMetaCommand) Synopsis() string {
+	return "Interact with node metadata"
+}
+
+func (f *NodeMetaCommand) Name() string { return "node meta" }
+
+func (f *NodeMetaCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
+
+// lookupNodeMetaID resolves the node ID prefix given to the node meta
+// commands. An empty prefix targets the local node of the agent.
+func lookupNodeMetaID(client *api.Client, prefix string) (string, error) {
+	if prefix == "" {
+		return "", nil
+	}
+	if len(prefix) == 1 {
+		return "", fmt.Errorf("Identifier must contain at least two characters.")
+	}
+
+	prefix = sanitizeUUIDPrefix(prefix)
+	nodes, _, err := client.Nodes().PrefixList(prefix)
+	if err != nil {
+		return "", fmt.Errorf("Error querying nodes: %s", err)
+	}
+	if len(nodes) == 0 {
+		return "", fmt.Errorf("No node(s) with prefix or id %q found", prefix)
+	}
+	if len(nodes) > 1 {
+		return "", fmt.Errorf("Prefix matched multiple nodes\n\n%s",
+			formatNodeStubList(nodes, true))
+	}
+	return nodes[0].ID, nil
+}
@@ -0,0 +1,122 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+)
+
+type NodeMetaApplyCommand struct {
+	Meta
+}
+
+func (c *NodeMetaApplyCommand) Help() string {
+	helpText := `
+Usage: nomad node meta apply [options] <key>=<value>...
+
+  Apply is used to set and unset the dynamic metadata of a node. The dynamic
+  metadata is persisted by the client, overrides the metadata of its
+  configuration and is sent to the servers so constraints and affinities can
+  use it.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Apply Options:
+
+  -node-id
+    Updates the metadata of the given node instead of the local node.
+
+  -unset <key>,...
+    Comma separated list of keys of the dynamic metadata to unset. Unsetting
+    a key of the static metadata restores its static value.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *NodeMetaApplyCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-node-id": complete.PredictAnything,
+			"-unset":   complete.PredictAnything,
+		})
+}
+
+func (c *NodeMetaApplyCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictAnything
+}
+
+func (c *NodeMetaApplyCommand) Synopsis() string {
+	return "Set and unset the dynamic metadata of a node"
+}
+
+func (c *NodeMetaApplyCommand) Name() string { return "node meta apply" }
+
+func (c *NodeMetaApplyCommand) Run(args []string) int {
+	var nodeID, unset string
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&nodeID, "node-id", "", "")
+	flags.StringVar(&unset, "unset", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got something to apply
+	args = flags.Args()
+	if len(args) == 0 && unset == "" {
+		c.Ui.Error("This command takes at least one argument: <key>=<value> or the -unset flag")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Build the metadata to apply
+	meta := make(map[string]*string, len(args))
+	for _, kv := range args {
+		split := strings.SplitN(kv, "=", 2)
+		if len(split) != 2 || split[0] == "" {
+			c.Ui.Error(fmt.Sprintf("Error parsing meta value: %v", kv))
+			return 1
+		}
+		value := split[1]
+		meta[split[0]] = &value
+	}
+	if unset != "" {
+		for _, k := range strings.Split(unset, ",") {
+			if _, ok := meta[k]; ok {
+				c.Ui.Error(fmt.Sprintf("Key %q can not be both set and unset", k))
+				return 1
+			}
+			meta[k] = nil
+		}
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	if nodeID, err = lookupNodeMetaID(client, nodeID); err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	req := &api.NodeMetaApplyRequest{
+		NodeID: nodeID,
+		Meta:   meta,
+	}
+	if _, err := client.NodeMeta().Apply(req, nil); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error applying node metadata: %s", err))
+		return 1
+	}
+
+	c.Ui.Output("Successfully applied node metadata!")
+	return 0
+}
@@ -0,0 +1,74 @@
+package command
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodeMetaApplyCommand_Implements(t *testing.T) {
+	t.Parallel()
+	var _ cli.Command = &NodeMetaApplyCommand{}
+}
+
+func TestNodeMetaApplyCommand(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	srv, client, url := testServer(t, true, nil)
+	defer srv.Shutdown()
+
+	// Wait for the client to register
+	testutil.WaitForResult(func() (bool, error) {
+		nodes, _, err := client.Nodes().List(nil)
+		if err != nil {
+			return false, err
+		}
+		if len(nodes) != 1 {
+			return false, fmt.Errorf("expected one node, got %d", len(nodes))
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatal(err)
+	})
+
+	ui := new(cli.MockUi)
+	cmd := &NodeMetaApplyCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	require.Equal(1, cmd.Run([]string{"-address=" + url}))
+	require.Contains(ui.ErrorWriter.String(), commandErrorText(cmd))
+	ui.ErrorWriter.Reset()
+
+	require.Equal(1, cmd.Run([]string{"-address=" + url, "rack"}))
+	require.Contains(ui.ErrorWriter.String(), "Error parsing meta value")
+	ui.ErrorWriter.Reset()
+
+	require.Equal(1, cmd.Run([]string{"-address=" + url, "-unset=rack", "rack=r1"}))
+	require.Contains(ui.ErrorWriter.String(), "both set and unset")
+	ui.ErrorWriter.Reset()
+
+	// Set the metadata of the local node
+	code := cmd.Run([]string{"-address=" + url, "rack=r12", "zone=a"})
+	require.Equal(0, code, ui.ErrorWriter.String())
+	require.Contains(ui.OutputWriter.String(), "Successfully applied node metadata")
+
+	code = cmd.Run([]string{"-address=" + url, "-unset=zone"})
+	require.Equal(0, code, ui.ErrorWriter.String())
+
+	meta, err := client.NodeMeta().Read("", nil)
+	require.NoError(err)
+	require.Equal("r12", meta.Meta["rack"])
+	require.NotContains(meta.Meta, "zone")
+
+	// Read the metadata
+	ui = new(cli.MockUi)
+	read := &NodeMetaReadCommand{Meta: Meta{Ui: ui}}
+	code = read.Run([]string{"-address=" + url})
+	require.Equal(0, code, ui.ErrorWriter.String())
+	out := ui.OutputWriter.String()
+	require.Contains(out, "Dynamic Meta")
+	require.Contains(out, "r12")
+}
@@ -0,0 +1,137 @@
+package command
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/posener/complete"
+)
+
+type NodeMetaReadCommand struct {
+	Meta
+}
+
+func (c *NodeMetaReadCommand) Help() string {
+	helpText := `
+Usage: nomad node meta read [options]
+
+  Read is used to fetch the metadata of a node, which is its static metadata
+  set by the client configuration overridden by its dynamic metadata.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Read Options:
+
+  -node-id
+    Reads the metadata of the given node instead of the local node.
+
+  -json
+    Output the node metadata in a JSON format.
+
+  -t
+    Format and display the node metadata using a Go template.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *NodeMetaReadCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-node-id": complete.PredictAnything,
+			"-json":    complete.PredictNothing,
+			"-t":       complete.PredictAnything,
+		})
+}
+
+func (c *NodeMetaReadCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *NodeMetaReadCommand) Synopsis() string {
+	return "Read the metadata of a node"
+}
+
+func (c *NodeMetaReadCommand) Name() string { return "node meta read" }
+
+func (c *NodeMetaReadCommand) Run(args []string) int {
+	var nodeID, tmpl string
+	var json bool
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&nodeID, "node-id", "", "")
+	flags.BoolVar(&json, "json", false, "")
+	flags.StringVar(&tmpl, "t", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got no arguments
+	if args = flags.Args(); len(args) != 0 {
+		c.Ui.Error("This command takes no arguments")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	if nodeID, err = lookupNodeMetaID(client, nodeID); err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	meta, err := client.NodeMeta().Read(nodeID, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error reading node metadata: %s", err))
+		return 1
+	}
+
+	if json || len(tmpl) > 0 {
+		out, err := Format(json, tmpl, meta)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+
+		c.Ui.Output(out)
+		return 0
+	}
+
+	c.Ui.Output(c.Colorize().Color("[bold]All Meta[reset]"))
+	c.Ui.Output(formatNodeMeta(meta.Meta))
+
+	c.Ui.Output(c.Colorize().Color("\n[bold]Dynamic Meta[reset]"))
+	c.Ui.Output(formatNodeMeta(meta.Dynamic))
+
+	c.Ui.Output(c.Colorize().Color("\n[bold]Static Meta[reset]"))
+	c.Ui.Output(formatNodeMeta(meta.Static))
+	return 0
+}
+
+// formatNodeMeta returns the metadata sorted by key for output
+func formatNodeMeta(meta map[string]string) string {
+	if len(meta) == 0 {
+		return "No metadata"
+	}
+
+	keys := make([]string, 0, len(meta))
+	for k := range meta {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	out := make([]string, 0, len(keys))
+	for _, k := range keys {
+		out = append(out, fmt.Sprintf("%s|%s", k, meta[k]))
+	}
+	return formatKV(out)
+}
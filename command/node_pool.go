@@ -0,0 +1,46 @@
+package command
+
+import (
+	"strings"
+
+	"github.com/mitchellh/cli"
+)
+
+type NodePoolCommand struct {
+	Meta
+}
+
+func (f *NodePoolCommand) Help() string {
+	helpText := `
+Usage: nomad node pool <subcommand> [options] [args]
+
+  This command groups subcommands for interacting with node pools. Node pools
+  partition the nodes of a cluster. Clients declare the node pool they belong
+  to and jobs are only placed on nodes of the node pool they target.
+
+  Create or update a node pool:
+
+      $ nomad node pool apply -description "nodes with GPUs" gpu
+
+  List node pools:
+
+      $ nomad node pool list
+
+  Inspect a node pool:
+
+      $ nomad node pool info gpu
+
+  Please see the individual subcommand help for detailed usage information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (f *NodePoolCommand) Synopsis() string {
+	return "Interact with node pools"
+}
+
+func (f *NodePoolCommand) Name() string { return "node pool" }
+
+func (f *NodePoolCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
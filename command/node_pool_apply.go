@@ -0,0 +1,125 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	flaghelper "github.com/hashicorp/nomad/helper/flag-helpers"
+	"github.com/posener/complete"
+)
+
+type NodePoolApplyCommand struct {
+	Meta
+}
+
+func (c *NodePoolApplyCommand) Help() string {
+	helpText := `
+Usage: nomad node pool apply [options] <name>
+
+  Apply is used to create or update a node pool. Nodes join a node pool by
+  setting node_pool in their client configuration.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Apply Options:
+
+  -description
+    Specifies a human readable description for the node pool.
+
+  -meta <key>=<value>
+    Sets a metadata key on the node pool. May be specified multiple times.
+
+  -scheduler-algorithm <binpack|spread>
+    Overrides the cluster wide scheduler algorithm when placing allocations on
+    the nodes of the node pool. "binpack" packs allocations onto as few nodes
+    as possible while "spread" spreads them across as many nodes as possible.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *NodePoolApplyCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-description": complete.PredictAnything,
+			"-meta":        complete.PredictAnything,
+			"-scheduler-algorithm": complete.PredictSet(
+				string(api.SchedulerAlgorithmBinpack),
+				string(api.SchedulerAlgorithmSpread),
+			),
+		})
+}
+
+func (c *NodePoolApplyCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *NodePoolApplyCommand) Synopsis() string {
+	return "Create or update a node pool"
+}
+
+func (c *NodePoolApplyCommand) Name() string { return "node pool apply" }
+
+func (c *NodePoolApplyCommand) Run(args []string) int {
+	var description, algorithm string
+	var meta []string
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&description, "description", "", "")
+	flags.Var((*flaghelper.StringFlag)(&meta), "meta", "")
+	flags.StringVar(&algorithm, "scheduler-algorithm", "", "")
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got exactly one argument
+	args = flags.Args()
+	if l := len(args); l != 1 {
+		c.Ui.Error("This command takes one argument: <name>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Build the meta
+	var metaMap map[string]string
+	for _, m := range meta {
+		split := strings.SplitN(m, "=", 2)
+		if len(split) != 2 {
+			c.Ui.Error(fmt.Sprintf("Error parsing meta value: %v", m))
+			return 1
+		}
+
+		if metaMap == nil {
+			metaMap = make(map[string]string, len(meta))
+		}
+		metaMap[split[0]] = split[1]
+	}
+
+	// Construct the node pool
+	pool := &api.NodePool{
+		Name:               args[0],
+		Description:        description,
+		Meta:               metaMap,
+		SchedulerAlgorithm: api.SchedulerAlgorithm(algorithm),
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	// Upsert the node pool
+	_, err = client.NodePools().Register(pool, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error writing node pool: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf("Successfully applied node pool %q!", pool.Name))
+	return 0
+}
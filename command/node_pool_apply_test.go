@@ -0,0 +1,52 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodePoolApplyCommand_Implements(t *testing.T) {
+	t.Parallel()
+	var _ cli.Command = &NodePoolApplyCommand{}
+}
+
+func TestNodePoolApplyCommand(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	srv, client, url := testServer(t, false, nil)
+	defer srv.Shutdown()
+
+	ui := new(cli.MockUi)
+	cmd := &NodePoolApplyCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	require.Equal(1, cmd.Run([]string{"-address=" + url}))
+	require.Contains(ui.ErrorWriter.String(), commandErrorText(cmd))
+	ui.ErrorWriter.Reset()
+
+	require.Equal(1, cmd.Run([]string{"-address=" + url, "-meta=owner", "gpu"}))
+	require.Contains(ui.ErrorWriter.String(), "Error parsing meta value")
+	ui.ErrorWriter.Reset()
+
+	// Create the node pool
+	code := cmd.Run([]string{"-address=" + url, "-description=nodes with GPUs", "-meta=owner=ml", "gpu"})
+	require.Equal(0, code, ui.ErrorWriter.String())
+	require.Contains(ui.OutputWriter.String(), `Successfully applied node pool "gpu"`)
+
+	pool, _, err := client.NodePools().Info("gpu", nil)
+	require.NoError(err)
+	require.Equal("nodes with GPUs", pool.Description)
+	require.Equal(map[string]string{"owner": "ml"}, pool.Meta)
+
+	// Inspect the node pool
+	ui = new(cli.MockUi)
+	info := &NodePoolInfoCommand{Meta: Meta{Ui: ui}}
+	code = info.Run([]string{"-address=" + url, "gpu"})
+	require.Equal(0, code, ui.ErrorWriter.String())
+	out := ui.OutputWriter.String()
+	require.Contains(out, "nodes with GPUs")
+	require.Contains(out, "owner")
+	require.Contains(out, "No nodes in node pool")
+}
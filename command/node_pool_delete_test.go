@@ -0,0 +1,32 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodePoolDeleteCommand(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	srv, client, url := testServer(t, false, nil)
+	defer srv.Shutdown()
+
+	_, err := client.NodePools().Register(&api.NodePool{Name: "gpu"}, nil)
+	require.NoError(err)
+
+	ui := new(cli.MockUi)
+	cmd := &NodePoolDeleteCommand{Meta: Meta{Ui: ui}}
+
+	// The default node pool can not be deleted
+	require.Equal(1, cmd.Run([]string{"-address=" + url, "default"}))
+	require.Contains(ui.ErrorWriter.String(), "built-in")
+
+	require.Equal(0, cmd.Run([]string{"-address=" + url, "gpu"}), ui.ErrorWriter.String())
+	require.Contains(ui.OutputWriter.String(), `Successfully deleted node pool "gpu"`)
+
+	_, _, err = client.NodePools().Info("gpu", nil)
+	require.Error(err)
+}
@@ -0,0 +1,174 @@
+package command
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+)
+
+type NodePoolInfoCommand struct {
+	Meta
+}
+
+func (c *NodePoolInfoCommand) Help() string {
+	helpText := `
+Usage: nomad node pool info [options] <name>
+
+  Info is used to fetch information on an existing node pool, including the
+  nodes that are in it.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Info Options:
+
+  -json
+    Output the node pool in a JSON format.
+
+  -t
+    Format and display the node pool using a Go template.
+`
+
+	return strings.TrimSpace(helpText)
+}
+
+func (c *NodePoolInfoCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-json": complete.PredictNothing,
+			"-t":    complete.PredictAnything,
+		})
+}
+
+func (c *NodePoolInfoCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictFunc(func(a complete.Args) []string {
+		client, err := c.Meta.Client()
+		if err != nil {
+			return nil
+		}
+
+		pools, _, err := client.NodePools().PrefixList(a.Last, nil)
+		if err != nil {
+			return nil
+		}
+
+		names := make([]string, 0, len(pools))
+		for _, p := range pools {
+			names = append(names, p.Name)
+		}
+		return names
+	})
+}
+
+func (c *NodePoolInfoCommand) Synopsis() string {
+	return "Fetch info on an existing node pool"
+}
+
+func (c *NodePoolInfoCommand) Name() string { return "node pool info" }
+
+func (c *NodePoolInfoCommand) Run(args []string) int {
+	var json bool
+	var tmpl string
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&json, "json", false, "")
+	flags.StringVar(&tmpl, "t", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got exactly one argument
+	args = flags.Args()
+	if l := len(args); l != 1 {
+		c.Ui.Error("This command takes one argument: <name>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	pool, _, err := client.NodePools().Info(args[0], nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error fetching info on node pool: %s", err))
+		return 1
+	}
+
+	if json || len(tmpl) > 0 {
+		out, err := Format(json, tmpl, pool)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+
+		c.Ui.Output(out)
+		return 0
+	}
+
+	// Find the nodes in the node pool
+	nodes, _, err := client.Nodes().List(nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error querying nodes: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(formatKV([]string{
+		fmt.Sprintf("Name|%s", pool.Name),
+		fmt.Sprintf("Description|%s", pool.Description),
+		fmt.Sprintf("Scheduler Algorithm|%s", formatSchedulerAlgorithm(pool.SchedulerAlgorithm)),
+	}))
+
+	if len(pool.Meta) != 0 {
+		keys := make([]string, 0, len(pool.Meta))
+		for k := range pool.Meta {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		meta := make([]string, 0, len(keys))
+		for _, k := range keys {
+			meta = append(meta, fmt.Sprintf("%s|%s", k, pool.Meta[k]))
+		}
+		c.Ui.Output(c.Colorize().Color("\n[bold]Meta[reset]"))
+		c.Ui.Output(formatKV(meta))
+	}
+
+	c.Ui.Output(c.Colorize().Color("\n[bold]Nodes[reset]"))
+	c.Ui.Output(formatNodePoolNodes(nodes, pool.Name))
+	return 0
+}
+
+// formatSchedulerAlgorithm returns the scheduler algorithm of a node pool for
+// output. Node pools without an algorithm use the cluster wide algorithm.
+func formatSchedulerAlgorithm(algorithm api.SchedulerAlgorithm) string {
+	if algorithm == "" {
+		return "<cluster default>"
+	}
+	return string(algorithm)
+}
+
+func formatNodePoolNodes(nodes []*api.NodeListStub, pool string) string {
+	output := []string{"ID|DC|Name|Status"}
+	for _, n := range nodes {
+		if n.NodePool != pool {
+			continue
+		}
+		output = append(output, fmt.Sprintf("%s|%s|%s|%s",
+			limit(n.ID, shortId), n.Datacenter, n.Name, n.Status))
+	}
+
+	if len(output) == 1 {
+		return "No nodes in node pool"
+	}
+	return formatList(output)
+}
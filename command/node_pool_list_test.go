@@ -0,0 +1,32 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodePoolListCommand(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	srv, client, url := testServer(t, false, nil)
+	defer srv.Shutdown()
+
+	_, err := client.NodePools().Register(&api.NodePool{Name: "gpu", Description: "nodes with GPUs"}, nil)
+	require.NoError(err)
+
+	ui := new(cli.MockUi)
+	cmd := &NodePoolListCommand{Meta: Meta{Ui: ui}}
+
+	require.Equal(0, cmd.Run([]string{"-address=" + url}), ui.ErrorWriter.String())
+	out := ui.OutputWriter.String()
+	require.Contains(out, "gpu")
+	require.Contains(out, "nodes with GPUs")
+	ui.OutputWriter.Reset()
+
+	// List json
+	require.Equal(0, cmd.Run([]string{"-address=" + url, "-json"}), ui.ErrorWriter.String())
+	require.Contains(ui.OutputWriter.String(), "CreateIndex")
+}
@@ -22,6 +22,10 @@ const (
 
 	// bytesPerMegabyte is the number of bytes per MB
 	bytesPerMegabyte = 1024 * 1024
+
+	// nodeEventsShown is the number of most recent node events displayed
+	// unless all of them are requested
+	nodeEventsShown = 10
 )
 
 type NodeStatusCommand struct {
@@ -32,6 +36,7 @@ type NodeStatusCommand struct {
 	list_allocs bool
 	self        bool
 	stats       bool
+	events      bool
 	json        bool
 	tmpl        string
 }
@@ -64,6 +69,10 @@ Node Status Options:
   -allocs
     Display a count of running allocations for each node.
 
+  -events
+    Display all the retained events of the node, oldest last, to debug a
+    flapping node. By default only the most recent events are displayed.
+
   -short
     Display short output. Used only when a single node is being
     queried, and drops verbose output about node allocations.
@@ -88,6 +97,7 @@ func (c *NodeStatusCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
 			"-allocs":  complete.PredictNothing,
+			"-events":  complete.PredictNothing,
 			"-json":    complete.PredictNothing,
 			"-self":    complete.PredictNothing,
 			"-short":   complete.PredictNothing,
@@ -123,6 +133,7 @@ func (c *NodeStatusCommand) Run(args []string) int {
 	flags.BoolVar(&c.list_allocs, "allocs", false, "")
 	flags.BoolVar(&c.self, "self", false, "")
 	flags.BoolVar(&c.stats, "stats", false, "")
+	flags.BoolVar(&c.events, "events", false, "")
 	flags.BoolVar(&c.json, "json", false, "")
 	flags.StringVar(&c.tmpl, "t", "", "")
 
@@ -265,6 +276,10 @@ func (c *NodeStatusCommand) Run(args []string) int {
 		return 1
 	}
 
+	if c.events {
+		return c.formatNodeEvents(client, node)
+	}
+
 	// If output format is specified, format and output the data
 	if c.json || len(c.tmpl) > 0 {
 		out, err := Format(c.json, c.tmpl, node)
@@ -327,6 +342,7 @@ func (c *NodeStatusCommand) formatNode(client *api.Client, node *api.Node) int {
 		fmt.Sprintf("ID|%s", limit(node.ID, c.length)),
 		fmt.Sprintf("Name|%s", node.Name),
 		fmt.Sprintf("Class|%s", node.NodeClass),
+		fmt.Sprintf("Node Pool|%s", node.NodePool),
 		fmt.Sprintf("DC|%s", node.Datacenter),
 		fmt.Sprintf("Drain|%v", formatDrain(node)),
 		fmt.Sprintf("Eligibility|%s", node.SchedulingEligibility),
@@ -360,6 +376,11 @@ func (c *NodeStatusCommand) formatNode(client *api.Client, node *api.Node) int {
 			c.outputNodeDriverInfo(node)
 		}
 
+		// Emit the host volumes and their health
+		if len(node.HostVolumes) > 0 {
+			c.outputNodeHostVolumes(node)
+		}
+
 		// Emit node events
 		c.outputNodeStatusEvents(node)
 
@@ -465,9 +486,55 @@ func (c *NodeStatusCommand) outputNodeDriverInfo(node *api.Node) {
 	c.Ui.Output(formatList(nodeDrivers))
 }
 
+func (c *NodeStatusCommand) outputNodeHostVolumes(node *api.Node) {
+	c.Ui.Output(c.Colorize().Color("\n[bold]Host Volumes"))
+
+	names := make([]string, 0, len(node.HostVolumes))
+	for name := range node.HostVolumes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	volumes := make([]string, 0, len(names)+1)
+	volumes = append(volumes, "Name|Path|Read Only|Healthy|Message")
+	for _, name := range names {
+		v := node.HostVolumes[name]
+		volumes = append(volumes, fmt.Sprintf("%s|%s|%v|%v|%s", name, v.Path, v.ReadOnly, v.Healthy, v.HealthDescription))
+	}
+	c.Ui.Output(formatList(volumes))
+}
+
 func (c *NodeStatusCommand) outputNodeStatusEvents(node *api.Node) {
 	c.Ui.Output(c.Colorize().Color("\n[bold]Node Events"))
-	c.outputNodeEvent(node.Events)
+	events := node.Events
+	if l := len(events); !c.verbose && l > nodeEventsShown {
+		events = events[l-nodeEventsShown:]
+	}
+	c.outputNodeEvent(events)
+}
+
+// formatNodeEvents displays all the retained events of the node.
+func (c *NodeStatusCommand) formatNodeEvents(client *api.Client, node *api.Node) int {
+	events, _, err := client.Nodes().Events(node.ID, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error querying node events: %s", err))
+		return 1
+	}
+
+	if c.json || len(c.tmpl) > 0 {
+		out, err := Format(c.json, c.tmpl, events)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+
+		c.Ui.Output(out)
+		return 0
+	}
+
+	c.Ui.Output(c.Colorize().Color(fmt.Sprintf("[bold]Node Events[reset] for %s", limit(node.ID, c.length))))
+	c.outputNodeEvent(events)
+	return 0
 }
 
 func (c *NodeStatusCommand) outputNodeEvent(events []*api.NodeEvent) {
@@ -545,6 +612,24 @@ func (c *NodeStatusCommand) formatMeta(node *api.Node) {
 	}
 	c.Ui.Output(c.Colorize().Color("\n[bold]Meta[reset]"))
 	c.Ui.Output(formatKV(meta))
+
+	if len(node.DynamicMeta) == 0 {
+		return
+	}
+
+	// Print the dynamic meta
+	keys = make([]string, 0, len(node.DynamicMeta))
+	for k := range node.DynamicMeta {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var dynamicMeta []string
+	for _, k := range keys {
+		dynamicMeta = append(dynamicMeta, fmt.Sprintf("%s|%s", k, node.DynamicMeta[k]))
+	}
+	c.Ui.Output(c.Colorize().Color("\n[bold]Dynamic Meta[reset]"))
+	c.Ui.Output(formatKV(dynamicMeta))
 }
 
 func (c *NodeStatusCommand) printCpuStats(hostStats *api.HostStats) {
@@ -17,6 +17,7 @@ func (c *OperatorAutopilotSetCommand) AutocompleteFlags() complete.Flags {
 		complete.Flags{
 			"-cleanup-dead-servers":      complete.PredictAnything,
 			"-max-trailing-logs":         complete.PredictAnything,
+			"-min-quorum":                complete.PredictAnything,
 			"-last-contact-threshold":    complete.PredictAnything,
 			"-server-stabilization-time": complete.PredictAnything,
 			"-enable-redundancy-zones":   complete.PredictNothing,
@@ -34,6 +35,7 @@ func (c *OperatorAutopilotSetCommand) Name() string { return "operator autopilot
 func (c *OperatorAutopilotSetCommand) Run(args []string) int {
 	var cleanupDeadServers flags.BoolValue
 	var maxTrailingLogs flags.UintValue
+	var minQuorum flags.UintValue
 	var lastContactThreshold flags.DurationValue
 	var serverStabilizationTime flags.DurationValue
 	var enableRedundancyZones flags.BoolValue
@@ -45,6 +47,7 @@ func (c *OperatorAutopilotSetCommand) Run(args []string) int {
 
 	f.Var(&cleanupDeadServers, "cleanup-dead-servers", "")
 	f.Var(&maxTrailingLogs, "max-trailing-logs", "")
+	f.Var(&minQuorum, "min-quorum", "")
 	f.Var(&lastContactThreshold, "last-contact-threshold", "")
 	f.Var(&serverStabilizationTime, "server-stabilization-time", "")
 	f.Var(&enableRedundancyZones, "enable-redundancy-zones", "")
@@ -80,6 +83,7 @@ func (c *OperatorAutopilotSetCommand) Run(args []string) int {
 	trailing := uint(conf.MaxTrailingLogs)
 	maxTrailingLogs.Merge(&trailing)
 	conf.MaxTrailingLogs = uint64(trailing)
+	minQuorum.Merge(&conf.MinQuorum)
 	lastContactThreshold.Merge(&conf.LastContactThreshold)
 	serverStabilizationTime.Merge(&conf.ServerStabilizationTime)
 
@@ -131,6 +135,10 @@ Set Config Options:
      Controls the maximum number of log entries that a server can trail
      the leader by before being considered unhealthy.
 
+  -min-quorum=<value>
+     Controls the minimum number of alive servers the cluster must have
+     for Nomad to remove dead servers. Zero disables the minimum.
+
   -redundancy-zone-tag=<value>
      (Enterprise-only) Controls the node_meta tag name used for
      separating servers into different redundancy zones.
@@ -0,0 +1,46 @@
+package command
+
+import (
+	"strings"
+
+	"github.com/mitchellh/cli"
+)
+
+type OperatorClientStateCommand struct {
+	Meta
+}
+
+func (c *OperatorClientStateCommand) Help() string {
+	helpText := `
+Usage: nomad operator client-state <subcommand> [options]
+
+  This command has subcommands for inspecting and maintaining the state
+  database of a Nomad client, which stores the state of its allocations. The
+  client must be stopped, as the database is locked while it runs.
+
+  Display the integrity, size and allocations of the state database:
+
+      $ nomad operator client-state inspect /var/lib/nomad/client
+
+  Reclaim the free space of the state database:
+
+      $ nomad operator client-state compact /var/lib/nomad/client
+
+  Recover the readable state of a corrupt state database:
+
+      $ nomad operator client-state repair /var/lib/nomad/client
+
+  Please see the individual subcommand help for detailed usage information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *OperatorClientStateCommand) Synopsis() string {
+	return "Inspects and repairs the state database of a client"
+}
+
+func (c *OperatorClientStateCommand) Name() string { return "operator client-state" }
+
+func (c *OperatorClientStateCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
@@ -0,0 +1,70 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	humanize "github.com/dustin/go-humanize"
+	"github.com/hashicorp/nomad/client/state"
+	"github.com/posener/complete"
+)
+
+type OperatorClientStateCompactCommand struct {
+	Meta
+}
+
+func (c *OperatorClientStateCompactCommand) Help() string {
+	helpText := `
+Usage: nomad operator client-state compact [options] <state_dir>
+
+  Rewrites the state database of a client without its free space. Clients
+  compact their state database when they start if at least half of it is
+  free, so this is only needed to reclaim space sooner. <state_dir> is the
+  "client" directory of the data_dir of the client. The client must be
+  stopped.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *OperatorClientStateCompactCommand) AutocompleteFlags() complete.Flags {
+	return complete.Flags{}
+}
+
+func (c *OperatorClientStateCompactCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictDirs("*")
+}
+
+func (c *OperatorClientStateCompactCommand) Synopsis() string {
+	return "Reclaims the free space of the state database of a client"
+}
+
+func (c *OperatorClientStateCompactCommand) Name() string {
+	return "operator client-state compact"
+}
+
+func (c *OperatorClientStateCompactCommand) Run(args []string) int {
+	flags := c.Meta.FlagSet(c.Name(), FlagSetNone)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	if err := flags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to parse args: %v", err))
+		return 1
+	}
+
+	// Check that we got exactly one argument
+	args = flags.Args()
+	if len(args) != 1 {
+		c.Ui.Error("This command takes one argument: <state_dir>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	before, after, err := state.CompactBoltStateDB(state.StateDBPath(args[0]))
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error compacting state database: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf("Compacted state database from %s to %s",
+		humanize.IBytes(uint64(before)), humanize.IBytes(uint64(after))))
+	return 0
+}
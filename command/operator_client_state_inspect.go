@@ -0,0 +1,140 @@
+package command
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	humanize "github.com/dustin/go-humanize"
+	"github.com/hashicorp/nomad/client/state"
+	"github.com/posener/complete"
+)
+
+type OperatorClientStateInspectCommand struct {
+	Meta
+}
+
+func (c *OperatorClientStateInspectCommand) Help() string {
+	helpText := `
+Usage: nomad operator client-state inspect [options] <state_dir>
+
+  Displays information about the state database of a client: its size, the
+  space compacting it would reclaim, its integrity errors and the allocations
+  it stores. <state_dir> is the "client" directory of the data_dir of the
+  client. The client must be stopped.
+
+  Inspect Options:
+
+  -verbose
+    Display full allocation IDs.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *OperatorClientStateInspectCommand) AutocompleteFlags() complete.Flags {
+	return complete.Flags{
+		"-verbose": complete.PredictNothing,
+	}
+}
+
+func (c *OperatorClientStateInspectCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictDirs("*")
+}
+
+func (c *OperatorClientStateInspectCommand) Synopsis() string {
+	return "Displays information about the state database of a client"
+}
+
+func (c *OperatorClientStateInspectCommand) Name() string {
+	return "operator client-state inspect"
+}
+
+func (c *OperatorClientStateInspectCommand) Run(args []string) int {
+	var verbose bool
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetNone)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&verbose, "verbose", false, "")
+	if err := flags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to parse args: %v", err))
+		return 1
+	}
+
+	// Check that we got exactly one argument
+	args = flags.Args()
+	if len(args) != 1 {
+		c.Ui.Error("This command takes one argument: <state_dir>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	length := shortId
+	if verbose {
+		length = fullId
+	}
+
+	path := state.StateDBPath(args[0])
+	info, err := state.InspectBoltStateDB(path)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error inspecting state database: %s", err))
+		return 1
+	}
+
+	integrity := "ok"
+	if len(info.CheckErrors) != 0 {
+		integrity = fmt.Sprintf("%d errors", len(info.CheckErrors))
+	}
+
+	output := []string{
+		fmt.Sprintf("Path|%s", path),
+		fmt.Sprintf("Size|%s", humanize.IBytes(uint64(info.Size))),
+		fmt.Sprintf("Free|%s", humanize.IBytes(uint64(info.FreeBytes))),
+		fmt.Sprintf("Needs Compaction|%t", info.NeedsCompaction()),
+		fmt.Sprintf("Integrity|%s", integrity),
+	}
+	c.Ui.Output(formatKV(output))
+
+	if len(info.CheckErrors) != 0 {
+		c.Ui.Output(c.Colorize().Color("\n[bold]Integrity Errors[reset]"))
+		for _, err := range info.CheckErrors {
+			c.Ui.Output(err.Error())
+		}
+		c.Ui.Output("\nRun \"nomad operator client-state repair\" to recover the readable state.")
+		return 2
+	}
+
+	c.Ui.Output(c.Colorize().Color("\n[bold]Allocations[reset]"))
+	if len(info.Allocations) == 0 && len(info.AllocErrors) == 0 {
+		c.Ui.Output("No allocations")
+		return 0
+	}
+
+	allocs := info.Allocations
+	sort.Slice(allocs, func(i, j int) bool { return allocs[i].ID < allocs[j].ID })
+	allocOutput := []string{"ID|Job ID|Task Group|Desired|Status"}
+	for _, alloc := range allocs {
+		allocOutput = append(allocOutput, fmt.Sprintf("%s|%s|%s|%s|%s",
+			limit(alloc.ID, length),
+			alloc.JobID,
+			alloc.TaskGroup,
+			alloc.DesiredStatus,
+			alloc.ClientStatus))
+	}
+	c.Ui.Output(formatList(allocOutput))
+
+	if len(info.AllocErrors) != 0 {
+		ids := make([]string, 0, len(info.AllocErrors))
+		for id := range info.AllocErrors {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+
+		c.Ui.Output(c.Colorize().Color("\n[bold]Unreadable Allocations[reset]"))
+		errOutput := []string{"ID|Error"}
+		for _, id := range ids {
+			errOutput = append(errOutput, fmt.Sprintf("%s|%s", limit(id, length), info.AllocErrors[id]))
+		}
+		c.Ui.Output(formatList(errOutput))
+	}
+	return 0
+}
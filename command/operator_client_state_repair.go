@@ -0,0 +1,77 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/client/state"
+	"github.com/posener/complete"
+)
+
+type OperatorClientStateRepairCommand struct {
+	Meta
+}
+
+func (c *OperatorClientStateRepairCommand) Help() string {
+	helpText := `
+Usage: nomad operator client-state repair [options] <state_dir>
+
+  Recovers the readable buckets of a corrupt state database of a client into a
+  new state database. The corrupt database is kept next to it with a
+  ".corrupt-<timestamp>" suffix. Allocations whose state is lost are garbage
+  collected by the client once it restarts. <state_dir> is the "client"
+  directory of the data_dir of the client. The client must be stopped.
+
+  Clients repair their state database when they start if it is corrupt, so
+  this is only needed to repair a database without starting the client.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *OperatorClientStateRepairCommand) AutocompleteFlags() complete.Flags {
+	return complete.Flags{}
+}
+
+func (c *OperatorClientStateRepairCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictDirs("*")
+}
+
+func (c *OperatorClientStateRepairCommand) Synopsis() string {
+	return "Recovers the state database of a client"
+}
+
+func (c *OperatorClientStateRepairCommand) Name() string {
+	return "operator client-state repair"
+}
+
+func (c *OperatorClientStateRepairCommand) Run(args []string) int {
+	flags := c.Meta.FlagSet(c.Name(), FlagSetNone)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	if err := flags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to parse args: %v", err))
+		return 1
+	}
+
+	// Check that we got exactly one argument
+	args = flags.Args()
+	if len(args) != 1 {
+		c.Ui.Error("This command takes one argument: <state_dir>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	repair, err := state.RepairBoltStateDB(state.StateDBPath(args[0]))
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error repairing state database: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf("Repaired state database, corrupt database moved to %s", repair.BackupPath))
+	if len(repair.Lost) != 0 {
+		c.Ui.Output(c.Colorize().Color("\n[bold]Lost State[reset]"))
+		for _, lost := range repair.Lost {
+			c.Ui.Output(lost)
+		}
+	}
+	return 0
+}
@@ -0,0 +1,85 @@
+package command
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/nomad/client/state"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperatorClientStateCommand_Implements(t *testing.T) {
+	t.Parallel()
+	var _ cli.Command = &OperatorClientStateCommand{}
+	var _ cli.Command = &OperatorClientStateInspectCommand{}
+	var _ cli.Command = &OperatorClientStateCompactCommand{}
+	var _ cli.Command = &OperatorClientStateRepairCommand{}
+}
+
+func TestOperatorClientStateCommand_InspectCompactRepair(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "nomad-client-state")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	alloc := mock.Alloc()
+	db, err := state.NewBoltStateDB(testlog.HCLogger(t), dir)
+	require.NoError(err)
+	require.NoError(db.PutAllocation(alloc))
+	require.NoError(db.Close())
+
+	// Inspect the state database
+	ui := new(cli.MockUi)
+	inspect := &OperatorClientStateInspectCommand{Meta: Meta{Ui: ui}}
+	code := inspect.Run([]string{"-verbose", dir})
+	require.Equal(0, code, ui.ErrorWriter.String())
+	out := ui.OutputWriter.String()
+	require.Regexp(`Integrity\s+= ok\n`, out)
+	require.Contains(out, alloc.ID)
+
+	// Compact it
+	ui = new(cli.MockUi)
+	compact := &OperatorClientStateCompactCommand{Meta: Meta{Ui: ui}}
+	code = compact.Run([]string{dir})
+	require.Equal(0, code, ui.ErrorWriter.String())
+	require.Contains(ui.OutputWriter.String(), "Compacted state database")
+
+	// Repair it, which keeps a healthy database intact
+	ui = new(cli.MockUi)
+	repair := &OperatorClientStateRepairCommand{Meta: Meta{Ui: ui}}
+	code = repair.Run([]string{dir})
+	require.Equal(0, code, ui.ErrorWriter.String())
+	require.Contains(ui.OutputWriter.String(), "Repaired state database")
+	require.NotContains(ui.OutputWriter.String(), "Lost State")
+
+	ui = new(cli.MockUi)
+	inspect = &OperatorClientStateInspectCommand{Meta: Meta{Ui: ui}}
+	code = inspect.Run([]string{"-verbose", dir})
+	require.Equal(0, code, ui.ErrorWriter.String())
+	require.Contains(ui.OutputWriter.String(), alloc.ID)
+}
+
+func TestOperatorClientStateCommand_InUse(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "nomad-client-state")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	db, err := state.NewBoltStateDB(testlog.HCLogger(t), dir)
+	require.NoError(err)
+	defer db.Close()
+
+	ui := new(cli.MockUi)
+	cmd := &OperatorClientStateCompactCommand{Meta: Meta{Ui: ui}}
+	code := cmd.Run([]string{dir})
+	require.Equal(1, code)
+	require.Contains(ui.ErrorWriter.String(), state.ErrStateDBInUse.Error())
+}
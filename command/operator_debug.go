@@ -0,0 +1,429 @@
+package command
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+)
+
+// maxPprofDuration is the longest CPU profile and trace the agents capture.
+const maxPprofDuration = 5 * time.Minute
+
+type OperatorDebugCommand struct {
+	Meta
+
+	// timestamp is used to name the debug bundle
+	timestamp string
+
+	// collectDir is the directory the bundle is assembled in
+	collectDir string
+
+	client *api.Client
+}
+
+func (c *OperatorDebugCommand) Help() string {
+	helpText := `
+Usage: nomad operator debug [options]
+
+  Build an archive containing Nomad cluster configuration and state
+  information, runtime profiles and the logs of the agent. The profiles and
+  logs are captured from the agent the command is connected to. Cluster state
+  and metrics are captured repeatedly at the given interval for the duration
+  of the capture.
+
+  The archive is meant to be attached to support requests. It contains the
+  configuration of the agent, the names of the jobs and the nodes of the
+  cluster, and their metadata, so review it before sharing it.
+
+  Runtime profiles require a management token, or the agent to run with
+  enable_debug if ACLs are disabled. Captures that fail are reported as
+  warnings and omitted from the archive.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Debug Options:
+
+  -duration=<duration>
+    The duration of the capture of the logs and the cluster state. Defaults
+    to 2m.
+
+  -interval=<interval>
+    The interval between captures of the cluster state and metrics. Defaults
+    to 30s.
+
+  -log-level=<level>
+    The minimum level of the captured agent logs. Defaults to DEBUG.
+
+  -pprof-duration=<duration>
+    The duration of the CPU profile and the execution trace, at most 5m.
+    Defaults to 1s.
+
+  -output=<path>
+    The directory the archive is written to. Defaults to the current
+    directory.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *OperatorDebugCommand) Synopsis() string {
+	return "Build a debug archive"
+}
+
+func (c *OperatorDebugCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-duration":       complete.PredictAnything,
+			"-interval":       complete.PredictAnything,
+			"-log-level":      complete.PredictSet("TRACE", "DEBUG", "INFO", "WARN", "ERR"),
+			"-pprof-duration": complete.PredictAnything,
+			"-output":         complete.PredictDirs("*"),
+		})
+}
+
+func (c *OperatorDebugCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *OperatorDebugCommand) Name() string { return "operator debug" }
+
+func (c *OperatorDebugCommand) Run(args []string) int {
+	var duration, interval, pprofDuration time.Duration
+	var logLevel, output string
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.DurationVar(&duration, "duration", 2*time.Minute, "")
+	flags.DurationVar(&interval, "interval", 30*time.Second, "")
+	flags.DurationVar(&pprofDuration, "pprof-duration", 1*time.Second, "")
+	flags.StringVar(&logLevel, "log-level", "DEBUG", "")
+	flags.StringVar(&output, "output", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got no arguments
+	if len(flags.Args()) != 0 {
+		c.Ui.Error("This command takes no arguments")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	if duration <= 0 {
+		c.Ui.Error("Duration must be positive")
+		return 1
+	}
+	if interval <= 0 {
+		c.Ui.Error("Interval must be positive")
+		return 1
+	}
+	if interval > duration {
+		interval = duration
+	}
+	if pprofDuration < time.Second {
+		pprofDuration = time.Second
+	}
+	if pprofDuration > maxPprofDuration {
+		c.Ui.Error(fmt.Sprintf("Pprof duration must be at most %s", maxPprofDuration))
+		return 1
+	}
+
+	if output == "" {
+		output = "."
+	}
+	if fi, err := os.Stat(output); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error reading output directory: %v", err))
+		return 1
+	} else if !fi.IsDir() {
+		c.Ui.Error(fmt.Sprintf("Output path %q is not a directory", output))
+		return 1
+	}
+
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+	c.client = client
+
+	c.timestamp = time.Now().UTC().Format("2006-01-02-150405Z")
+	name := "nomad-debug-" + c.timestamp
+
+	tmp, err := ioutil.TempDir("", "nomad-debug-")
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error creating temporary directory: %v", err))
+		return 1
+	}
+	defer os.RemoveAll(tmp)
+	c.collectDir = filepath.Join(tmp, name)
+
+	c.Ui.Output("Starting debugger and capturing cluster data...")
+	c.Ui.Output(fmt.Sprintf("          Interval: %s", interval))
+	c.Ui.Output(fmt.Sprintf("          Duration: %s", duration))
+	c.Ui.Output(fmt.Sprintf("         Log Level: %s", logLevel))
+
+	if err := c.collect(duration, interval, pprofDuration, logLevel); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error collecting data: %v", err))
+		return 1
+	}
+
+	archive := filepath.Join(output, name+".tar.gz")
+	if err := tarCZF(archive, tmp, name); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error creating archive: %v", err))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf("Created debug archive: %s", archive))
+	return 0
+}
+
+// collect captures the debug data into the collection directory.
+func (c *OperatorDebugCommand) collect(duration, interval, pprofDuration time.Duration, logLevel string) error {
+	for _, dir := range []string{"cluster", "profile"} {
+		if err := os.MkdirAll(filepath.Join(c.collectDir, dir), 0755); err != nil {
+			return err
+		}
+	}
+
+	// Stop the capture early on interrupt but still build the archive
+	interruptCh := make(chan os.Signal, 1)
+	signal.Notify(interruptCh, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(interruptCh)
+
+	// Stop capturing the logs before returning so the log file is complete
+	// once archived
+	stopCh := make(chan struct{})
+	monitorDoneCh := c.startMonitor(logLevel, stopCh)
+	defer func() {
+		close(stopCh)
+		<-monitorDoneCh
+	}()
+
+	c.collectAgent()
+	c.collectPprof(pprofDuration)
+
+	deadline := time.After(duration)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for i := 0; ; i++ {
+		if err := c.collectInterval(i); err != nil {
+			return err
+		}
+
+		select {
+		case <-ticker.C:
+		case <-deadline:
+			return nil
+		case <-interruptCh:
+			c.Ui.Warn("Interrupted, stopping the capture")
+			return nil
+		}
+	}
+}
+
+// collectAgent captures the configuration of the agent and the membership of
+// the cluster.
+func (c *OperatorDebugCommand) collectAgent() {
+	self, err := c.client.Agent().Self()
+	c.writeJSON("cluster", "agent-self.json", self, err)
+
+	members, err := c.client.Agent().Members()
+	c.writeJSON("cluster", "members.json", members, err)
+
+	regions, err := c.client.Regions().List()
+	c.writeJSON("cluster", "regions.json", regions, err)
+
+	leader, err := c.client.Status().Leader()
+	c.writeJSON("cluster", "leader.json", leader, err)
+
+	peers, err := c.client.Status().Peers()
+	c.writeJSON("cluster", "peers.json", peers, err)
+}
+
+// collectPprof captures the runtime profiles of the agent.
+func (c *OperatorDebugCommand) collectPprof(pprofDuration time.Duration) {
+	seconds := int(pprofDuration.Seconds())
+
+	profile, err := c.client.Agent().CPUProfile(seconds, nil)
+	c.writeBytes("profile", "profile.prof", profile, err)
+
+	trace, err := c.client.Agent().Trace(seconds, nil)
+	c.writeBytes("profile", "trace.prof", trace, err)
+
+	goroutines, err := c.client.Agent().Lookup("goroutine", 0, nil)
+	c.writeBytes("profile", "goroutine.prof", goroutines, err)
+
+	// The text dumps are more convenient to read than the profile
+	goroutines, err = c.client.Agent().Lookup("goroutine", 1, nil)
+	c.writeBytes("profile", "goroutine-debug1.txt", goroutines, err)
+
+	goroutines, err = c.client.Agent().Lookup("goroutine", 2, nil)
+	c.writeBytes("profile", "goroutine-debug2.txt", goroutines, err)
+
+	heap, err := c.client.Agent().Lookup("heap", 0, nil)
+	c.writeBytes("profile", "heap.prof", heap, err)
+}
+
+// collectInterval captures the state of the cluster and the metrics of the
+// agent into a directory named after the index of the interval.
+func (c *OperatorDebugCommand) collectInterval(i int) error {
+	dir := fmt.Sprintf("%04d", i)
+	if err := os.MkdirAll(filepath.Join(c.collectDir, dir), 0755); err != nil {
+		return err
+	}
+	c.Ui.Output(fmt.Sprintf("    Capture interval %s", dir))
+
+	nodes, _, err := c.client.Nodes().List(nil)
+	c.writeJSON(dir, "nodes.json", nodes, err)
+
+	jobs, _, err := c.client.Jobs().List(nil)
+	c.writeJSON(dir, "jobs.json", jobs, err)
+
+	evals, _, err := c.client.Evaluations().List(nil)
+	c.writeJSON(dir, "evaluations.json", evals, err)
+
+	allocs, _, err := c.client.Allocations().List(nil)
+	c.writeJSON(dir, "allocations.json", allocs, err)
+
+	deployments, _, err := c.client.Deployments().List(nil)
+	c.writeJSON(dir, "deployments.json", deployments, err)
+
+	metrics, err := c.client.Operator().Metrics(nil)
+	c.writeBytes(dir, "metrics.json", metrics, err)
+
+	return nil
+}
+
+// startMonitor streams the logs of the agent into the collection directory
+// until stopCh is closed. The returned channel is closed once the capture
+// ends.
+func (c *OperatorDebugCommand) startMonitor(logLevel string, stopCh <-chan struct{}) <-chan struct{} {
+	doneCh := make(chan struct{})
+
+	f, err := os.Create(filepath.Join(c.collectDir, "monitor.log"))
+	if err != nil {
+		c.Ui.Warn(fmt.Sprintf("Failed to capture agent logs: %v", err))
+		close(doneCh)
+		return doneCh
+	}
+
+	logCh, errCh := c.client.Agent().Monitor(logLevel, stopCh, nil)
+	go func() {
+		defer close(doneCh)
+		defer f.Close()
+
+		for {
+			select {
+			case line, ok := <-logCh:
+				if !ok {
+					return
+				}
+				if _, err := fmt.Fprintln(f, line); err != nil {
+					c.Ui.Warn(fmt.Sprintf("Failed to write agent logs: %v", err))
+					return
+				}
+			case err := <-errCh:
+				c.Ui.Warn(fmt.Sprintf("Failed to capture agent logs: %v", err))
+				return
+			case <-stopCh:
+				return
+			}
+		}
+	}()
+
+	return doneCh
+}
+
+// writeJSON writes obj as JSON into the file of the collection directory or
+// warns about the error of its capture.
+func (c *OperatorDebugCommand) writeJSON(dir, file string, obj interface{}, err error) {
+	if err != nil {
+		c.Ui.Warn(fmt.Sprintf("Failed to capture %s: %v", filepath.Join(dir, file), err))
+		return
+	}
+
+	buf, err := json.MarshalIndent(obj, "", "  ")
+	c.writeBytes(dir, file, buf, err)
+}
+
+// writeBytes writes buf into the file of the collection directory or warns
+// about the error of its capture.
+func (c *OperatorDebugCommand) writeBytes(dir, file string, buf []byte, err error) {
+	path := filepath.Join(c.collectDir, dir, file)
+	if err != nil {
+		c.Ui.Warn(fmt.Sprintf("Failed to capture %s: %v", filepath.Join(dir, file), err))
+		return
+	}
+
+	if err := ioutil.WriteFile(path, buf, 0644); err != nil {
+		c.Ui.Warn(fmt.Sprintf("Failed to write %s: %v", path, err))
+	}
+}
+
+// tarCZF writes the target directory of src into a gzipped tar archive.
+func tarCZF(archive, src, target string) error {
+	f, err := os.Create(archive)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	zw := gzip.NewWriter(f)
+	defer zw.Close()
+
+	tw := tar.NewWriter(zw)
+	defer tw.Close()
+
+	return filepath.Walk(filepath.Join(src, target), func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		header, err := tar.FileInfoHeader(fi, "")
+		if err != nil {
+			return err
+		}
+
+		// Name the entries relative to src so the archive extracts into
+		// the target directory
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(rel)
+		if fi.IsDir() {
+			header.Name += "/"
+		}
+
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if !fi.Mode().IsRegular() {
+			return nil
+		}
+
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		_, err = io.Copy(tw, file)
+		return err
+	})
+}
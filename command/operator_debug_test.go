@@ -0,0 +1,111 @@
+package command
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/command/agent"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperatorDebugCommand_Implements(t *testing.T) {
+	t.Parallel()
+	var _ cli.Command = &OperatorDebugCommand{}
+}
+
+func TestOperatorDebugCommand_Fails(t *testing.T) {
+	t.Parallel()
+	ui := new(cli.MockUi)
+	cmd := &OperatorDebugCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	code := cmd.Run([]string{"some", "bad", "args"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), commandErrorText(cmd))
+	ui.ErrorWriter.Reset()
+
+	// Fails on invalid durations
+	code = cmd.Run([]string{"-duration=0s"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), "Duration must be positive")
+	ui.ErrorWriter.Reset()
+
+	// Fails on a missing output directory
+	code = cmd.Run([]string{"-output=/nonexistent/nomad-debug"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), "Error reading output directory")
+}
+
+func TestOperatorDebugCommand_Run(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	srv, _, url := testServer(t, false, func(c *agent.Config) {
+		c.EnableDebug = true
+	})
+	defer srv.Shutdown()
+
+	output, err := ioutil.TempDir("", "nomad-debug-test")
+	require.NoError(err)
+	defer os.RemoveAll(output)
+
+	ui := new(cli.MockUi)
+	cmd := &OperatorDebugCommand{Meta: Meta{Ui: ui}}
+
+	code := cmd.Run([]string{"-address=" + url, "-output=" + output,
+		"-duration=2s", "-interval=1s"})
+	require.Equal(0, code, ui.ErrorWriter.String())
+	require.Contains(ui.OutputWriter.String(), "Created debug archive")
+
+	archives, err := filepath.Glob(filepath.Join(output, "nomad-debug-*.tar.gz"))
+	require.NoError(err)
+	require.Len(archives, 1)
+
+	// Read the names of the files of the archive
+	f, err := os.Open(archives[0])
+	require.NoError(err)
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	require.NoError(err)
+	tr := tar.NewReader(zr)
+
+	files := make(map[string]int64)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(err)
+
+		// Strip the name of the bundle directory
+		parts := strings.SplitN(header.Name, "/", 2)
+		require.True(strings.HasPrefix(parts[0], "nomad-debug-"))
+		if len(parts) == 2 {
+			files[parts[1]] = header.Size
+		}
+	}
+
+	for _, file := range []string{
+		"monitor.log",
+		"cluster/agent-self.json",
+		"cluster/members.json",
+		"profile/profile.prof",
+		"profile/goroutine-debug2.txt",
+		"profile/heap.prof",
+		"0000/nodes.json",
+		"0000/jobs.json",
+		"0000/allocations.json",
+		"0000/metrics.json",
+	} {
+		size, ok := files[file]
+		require.True(ok, "missing %s in %v", file, files)
+		require.NotZero(size, "empty %s", file)
+	}
+}
@@ -0,0 +1,64 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/mitchellh/cli"
+)
+
+type OperatorRootKeyringCommand struct {
+	Meta
+}
+
+func (c *OperatorRootKeyringCommand) Help() string {
+	helpText := `
+Usage: nomad operator root keyring <subcommand> [options]
+
+  This command groups subcommands for managing the root keys the servers
+  encrypt variables with. Each variable is encrypted with its own data key,
+  wrapped by the active root key. Rotating the root key makes new variables
+  use a new root key; a full rotation also rewraps the data keys of the
+  existing variables so that the previous root keys can be removed.
+
+  List the root keys:
+
+      $ nomad operator root keyring list
+
+  Rotate the active root key and rewrap the existing variables:
+
+      $ nomad operator root keyring rotate -full
+
+  Remove an inactive root key:
+
+      $ nomad operator root keyring remove <key ID>
+
+  Please see the individual subcommand help for detailed usage information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *OperatorRootKeyringCommand) Synopsis() string {
+	return "Manages the root keys variables are encrypted with"
+}
+
+func (c *OperatorRootKeyringCommand) Name() string { return "operator root keyring" }
+
+func (c *OperatorRootKeyringCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
+
+func formatRootKeys(keys []*api.RootKeyMeta, length int) string {
+	if len(keys) == 0 {
+		return "No root keys found"
+	}
+
+	output := make([]string, 0, len(keys)+1)
+	output = append(output, "Key|Active|Wrapped|Create Time")
+	for _, k := range keys {
+		output = append(output, fmt.Sprintf("%s|%v|%v|%s",
+			limit(k.KeyID, length), k.Active, k.Wrapped, formatUnixNanoTime(k.CreateTime)))
+	}
+	return formatList(output)
+}
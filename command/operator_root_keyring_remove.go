@@ -0,0 +1,96 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/posener/complete"
+)
+
+type OperatorRootKeyringRemoveCommand struct {
+	Meta
+}
+
+func (c *OperatorRootKeyringRemoveCommand) Help() string {
+	helpText := `
+Usage: nomad operator root keyring remove [options] <key ID>
+
+  Remove an inactive root key. Keys variables are still encrypted with can't
+  be removed; run "nomad operator root keyring rotate -full" first to rewrap
+  them with the active root key.
+
+General Options:
+
+  ` + generalOptionsUsage()
+	return strings.TrimSpace(helpText)
+}
+
+func (c *OperatorRootKeyringRemoveCommand) AutocompleteFlags() complete.Flags {
+	return c.Meta.AutocompleteFlags(FlagSetClient)
+}
+
+func (c *OperatorRootKeyringRemoveCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictAnything
+}
+
+func (c *OperatorRootKeyringRemoveCommand) Synopsis() string {
+	return "Remove an inactive root key"
+}
+
+func (c *OperatorRootKeyringRemoveCommand) Name() string { return "operator root keyring remove" }
+
+func (c *OperatorRootKeyringRemoveCommand) Run(args []string) int {
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got exactly one argument
+	args = flags.Args()
+	if len(args) != 1 {
+		c.Ui.Error("This command takes one argument: <key ID>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+	keyID := args[0]
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	// Complete a key ID prefix
+	keys, _, err := client.Keyring().List(nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error listing root keys: %s", err))
+		return 1
+	}
+	var matches []string
+	for _, k := range keys {
+		if strings.HasPrefix(k.KeyID, keyID) {
+			matches = append(matches, k.KeyID)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		c.Ui.Error(fmt.Sprintf("No root key with prefix %q found", keyID))
+		return 1
+	case 1:
+		keyID = matches[0]
+	default:
+		c.Ui.Error(fmt.Sprintf("Prefix %q matched multiple root keys: %s", keyID, strings.Join(matches, ", ")))
+		return 1
+	}
+
+	if _, err := client.Keyring().Delete(keyID, nil); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error removing root key: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf("Removed root key %q", keyID))
+	return 0
+}
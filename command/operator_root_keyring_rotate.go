@@ -0,0 +1,97 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+)
+
+type OperatorRootKeyringRotateCommand struct {
+	Meta
+}
+
+func (c *OperatorRootKeyringRotateCommand) Help() string {
+	helpText := `
+Usage: nomad operator root keyring rotate [options]
+
+  Rotate the root key new variables are encrypted with. The previous root keys
+  are kept to decrypt the existing variables.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Rotate Options:
+
+  -full
+    Rewrap the data keys of all the existing variables with the new root key,
+    so that the previous root keys can be removed.
+
+  -verbose
+    Display the full key ID.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *OperatorRootKeyringRotateCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-full":    complete.PredictNothing,
+			"-verbose": complete.PredictNothing,
+		})
+}
+
+func (c *OperatorRootKeyringRotateCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *OperatorRootKeyringRotateCommand) Synopsis() string {
+	return "Rotate the active root key"
+}
+
+func (c *OperatorRootKeyringRotateCommand) Name() string { return "operator root keyring rotate" }
+
+func (c *OperatorRootKeyringRotateCommand) Run(args []string) int {
+	var full, verbose bool
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&full, "full", false, "")
+	flags.BoolVar(&verbose, "verbose", false, "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got no arguments
+	if len(flags.Args()) != 0 {
+		c.Ui.Error("This command takes no arguments")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	resp, _, err := client.Keyring().Rotate(full, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error rotating root key: %s", err))
+		return 1
+	}
+
+	length := shortId
+	if verbose {
+		length = fullId
+	}
+	c.Ui.Output(formatRootKeys([]*api.RootKeyMeta{resp.Key}, length))
+	if full {
+		c.Ui.Output(fmt.Sprintf("\nRewrapped %d variables", resp.Rewrapped))
+	}
+	return 0
+}
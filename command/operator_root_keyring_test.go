@@ -0,0 +1,67 @@
+package command
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperatorRootKeyringCommands_Implements(t *testing.T) {
+	t.Parallel()
+	var _ cli.Command = &OperatorRootKeyringListCommand{}
+	var _ cli.Command = &OperatorRootKeyringRotateCommand{}
+	var _ cli.Command = &OperatorRootKeyringRemoveCommand{}
+}
+
+func TestOperatorRootKeyringCommands(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	srv, client, url := testServer(t, false, nil)
+	defer srv.Shutdown()
+
+	// Wait for the leader to create the first root key
+	var oldKeyID string
+	testutil.WaitForResult(func() (bool, error) {
+		keys, _, err := client.Keyring().List(nil)
+		if err != nil {
+			return false, err
+		}
+		if len(keys) != 1 {
+			return false, nil
+		}
+		oldKeyID = keys[0].KeyID
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("root key not created: %v", err)
+	})
+
+	ui := new(cli.MockUi)
+	list := &OperatorRootKeyringListCommand{Meta: Meta{Ui: ui}}
+	require.Equal(0, list.Run([]string{"-address=" + url, "-verbose"}))
+	require.Contains(ui.OutputWriter.String(), oldKeyID)
+
+	ui = new(cli.MockUi)
+	rotate := &OperatorRootKeyringRotateCommand{Meta: Meta{Ui: ui}}
+	require.Equal(0, rotate.Run([]string{"-address=" + url, "-full"}))
+	require.Contains(ui.OutputWriter.String(), "Rewrapped 0 variables")
+
+	// Remove the old key by prefix
+	ui = new(cli.MockUi)
+	remove := &OperatorRootKeyringRemoveCommand{Meta: Meta{Ui: ui}}
+	require.Equal(0, remove.Run([]string{"-address=" + url, oldKeyID[:8]}))
+	require.Contains(ui.OutputWriter.String(), oldKeyID)
+
+	keys, _, err := client.Keyring().List(nil)
+	require.NoError(err)
+	require.Len(keys, 1)
+	require.NotEqual(oldKeyID, keys[0].KeyID)
+
+	// The active key can't be removed
+	ui = new(cli.MockUi)
+	remove = &OperatorRootKeyringRemoveCommand{Meta: Meta{Ui: ui}}
+	require.Equal(1, remove.Run([]string{"-address=" + url, keys[0].KeyID}))
+	require.True(strings.Contains(ui.ErrorWriter.String(), "active"))
+}
@@ -0,0 +1,47 @@
+package command
+
+import (
+	"strings"
+
+	"github.com/mitchellh/cli"
+)
+
+type OperatorSnapshotCommand struct {
+	Meta
+}
+
+func (c *OperatorSnapshotCommand) Help() string {
+	helpText := `
+Usage: nomad operator snapshot <subcommand> [options]
+
+  This command has subcommands for saving, inspecting and restoring snapshots
+  of the state of the Nomad servers, for disaster recovery. Snapshots contain
+  all the state of the cluster, including the jobs, allocations, nodes and ACL
+  tokens, and are taken by the leader.
+
+  Save a snapshot of the current state of the cluster:
+
+      $ nomad operator snapshot save backup.snap
+
+  Inspect the contents of a snapshot:
+
+      $ nomad operator snapshot inspect backup.snap
+
+  Restore a snapshot into the cluster:
+
+      $ nomad operator snapshot restore backup.snap
+
+  Please see the individual subcommand help for detailed usage information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *OperatorSnapshotCommand) Synopsis() string {
+	return "Saves, inspects and restores snapshots of the state of the cluster"
+}
+
+func (c *OperatorSnapshotCommand) Name() string { return "operator snapshot" }
+
+func (c *OperatorSnapshotCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
@@ -0,0 +1,141 @@
+package command
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	log "github.com/hashicorp/go-hclog"
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/helper/raftutil"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/posener/complete"
+)
+
+type OperatorSnapshotInspectCommand struct {
+	Meta
+}
+
+func (c *OperatorSnapshotInspectCommand) Help() string {
+	helpText := `
+Usage: nomad operator snapshot inspect [options] <file>
+
+  Displays information about a snapshot file saved with the
+  "nomad operator snapshot save" command: its metadata and the number of
+  objects of the state it contains. The snapshot is inspected locally and
+  doesn't require an agent.
+
+  To inspect the file "backup.snap":
+
+      $ nomad operator snapshot inspect backup.snap
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *OperatorSnapshotInspectCommand) AutocompleteFlags() complete.Flags {
+	return complete.Flags{}
+}
+
+func (c *OperatorSnapshotInspectCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictFiles("*")
+}
+
+func (c *OperatorSnapshotInspectCommand) Synopsis() string {
+	return "Displays information about a snapshot file"
+}
+
+func (c *OperatorSnapshotInspectCommand) Name() string { return "operator snapshot inspect" }
+
+func (c *OperatorSnapshotInspectCommand) Run(args []string) int {
+	flags := c.Meta.FlagSet(c.Name(), FlagSetNone)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	if err := flags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to parse args: %v", err))
+		return 1
+	}
+
+	// Check that we got exactly one argument
+	args = flags.Args()
+	if len(args) != 1 {
+		c.Ui.Error("This command takes one argument: <file>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+	path := args[0]
+
+	f, err := os.Open(path)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error opening snapshot file: %s", err))
+		return 1
+	}
+	defer f.Close()
+
+	state, meta, err := raftutil.RestoreFromArchive(log.NewNullLogger(), f)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error reading snapshot: %s", err))
+		return 1
+	}
+
+	counts, err := snapshotStateCounts(state)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error reading snapshot state: %s", err))
+		return 1
+	}
+
+	output := []string{
+		fmt.Sprintf("ID|%s", meta.ID),
+		fmt.Sprintf("Size|%d", meta.Size),
+		fmt.Sprintf("Index|%d", meta.Index),
+		fmt.Sprintf("Term|%d", meta.Term),
+		fmt.Sprintf("Version|%d", meta.Version),
+	}
+	c.Ui.Output(formatKV(output))
+
+	c.Ui.Output(c.Colorize().Color("\n[bold]State[reset]"))
+	var stateOutput []string
+	for _, count := range counts {
+		stateOutput = append(stateOutput, fmt.Sprintf("%s|%d", count.name, count.count))
+	}
+	c.Ui.Output(formatKV(stateOutput))
+	return 0
+}
+
+// snapshotStateCount is the number of objects of a kind in the state of a
+// snapshot.
+type snapshotStateCount struct {
+	name  string
+	count int
+}
+
+// snapshotStateCounts counts the objects of the state of a snapshot.
+func snapshotStateCounts(store *state.StateStore) ([]snapshotStateCount, error) {
+	tables := []struct {
+		name string
+		iter func(memdb.WatchSet) (memdb.ResultIterator, error)
+	}{
+		{"Jobs", store.Jobs},
+		{"Allocations", store.Allocs},
+		{"Nodes", store.Nodes},
+		{"Evaluations", store.Evals},
+		{"Deployments", store.Deployments},
+		{"Namespaces", store.Namespaces},
+		{"Variables", store.Variables},
+		{"ACL Policies", store.ACLPolicies},
+		{"ACL Tokens", store.ACLTokens},
+	}
+
+	counts := make([]snapshotStateCount, 0, len(tables))
+	for _, table := range tables {
+		iter, err := table.iter(nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %v", strings.ToLower(table.name), err)
+		}
+
+		count := 0
+		for raw := iter.Next(); raw != nil; raw = iter.Next() {
+			count++
+		}
+		counts = append(counts, snapshotStateCount{table.name, count})
+	}
+	return counts, nil
+}
@@ -0,0 +1,138 @@
+package command
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/helper/snapshot"
+	"github.com/posener/complete"
+)
+
+type OperatorSnapshotSaveCommand struct {
+	Meta
+}
+
+func (c *OperatorSnapshotSaveCommand) Help() string {
+	helpText := `
+Usage: nomad operator snapshot save [options] <file>
+
+  Takes a snapshot of the state of the Nomad servers and saves it to the
+  file. The snapshot is taken by the leader, unless -stale is set. The
+  integrity of the snapshot is verified before it is saved.
+
+  If ACLs are enabled, this command requires a management token.
+
+  To create a snapshot from the leader server and save it to "backup.snap":
+
+      $ nomad operator snapshot save backup.snap
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Snapshot Save Options:
+
+  -stale=[true|false]
+    The -stale argument defaults to "false" which means the leader takes the
+    snapshot. If the cluster is in an outage state without a leader, you may
+    need to set -stale to "true" to take the snapshot from a non-leader server.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *OperatorSnapshotSaveCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-stale": complete.PredictAnything,
+		})
+}
+
+func (c *OperatorSnapshotSaveCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *OperatorSnapshotSaveCommand) Synopsis() string {
+	return "Saves a snapshot of the state of the cluster"
+}
+
+func (c *OperatorSnapshotSaveCommand) Name() string { return "operator snapshot save" }
+
+func (c *OperatorSnapshotSaveCommand) Run(args []string) int {
+	var stale bool
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&stale, "stale", false, "")
+	if err := flags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to parse args: %v", err))
+		return 1
+	}
+
+	// Check that we got exactly one argument
+	args = flags.Args()
+	if len(args) != 1 {
+		c.Ui.Error("This command takes one argument: <file>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+	path := args[0]
+
+	// Set up a client.
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	// Take the snapshot.
+	snap, err := client.Operator().Snapshot(&api.QueryOptions{AllowStale: stale})
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error querying snapshot: %s", err))
+		return 1
+	}
+	defer snap.Close()
+
+	// Save the snapshot into a temporary file next to the destination, so
+	// that a partial snapshot is never left behind.
+	tmpFile, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error creating snapshot file: %s", err))
+		return 1
+	}
+	defer os.Remove(tmpFile.Name())
+
+	_, err = io.Copy(tmpFile, snap)
+	if closeErr := tmpFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error writing snapshot file: %s", err))
+		return 1
+	}
+
+	// Verify the snapshot.
+	f, err := os.Open(tmpFile.Name())
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error opening snapshot file for verify: %s", err))
+		return 1
+	}
+	meta, err := snapshot.Verify(f)
+	f.Close()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error verifying snapshot file: %s", err))
+		return 1
+	}
+
+	if err := os.Rename(tmpFile.Name(), path); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error saving snapshot file: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf("State file written to %s (index %d)", path, meta.Index))
+	return 0
+}
@@ -0,0 +1,111 @@
+package command
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperatorSnapshotCommand_Implements(t *testing.T) {
+	t.Parallel()
+	var _ cli.Command = &OperatorSnapshotCommand{}
+	var _ cli.Command = &OperatorSnapshotSaveCommand{}
+	var _ cli.Command = &OperatorSnapshotInspectCommand{}
+	var _ cli.Command = &OperatorSnapshotRestoreCommand{}
+}
+
+func TestOperatorSnapshotCommand_SaveInspectRestore(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	srv, client, url := testServer(t, false, nil)
+	defer srv.Shutdown()
+	testutil.WaitForLeader(t, srv.Agent.RPC)
+
+	// Register a job
+	job := testJob("snapshot_job")
+	_, _, err := client.Jobs().Register(job, nil)
+	require.NoError(err)
+
+	tmpDir, err := ioutil.TempDir("", "nomad-snapshot")
+	require.NoError(err)
+	defer os.RemoveAll(tmpDir)
+	path := filepath.Join(tmpDir, "backup.snap")
+
+	// Save a snapshot
+	ui := new(cli.MockUi)
+	save := &OperatorSnapshotSaveCommand{Meta: Meta{Ui: ui}}
+	code := save.Run([]string{"-address=" + url, path})
+	require.Equal(0, code, ui.ErrorWriter.String())
+	require.Contains(ui.OutputWriter.String(), "State file written to "+path)
+
+	// Inspect the snapshot
+	ui = new(cli.MockUi)
+	inspect := &OperatorSnapshotInspectCommand{Meta: Meta{Ui: ui}}
+	code = inspect.Run([]string{path})
+	require.Equal(0, code, ui.ErrorWriter.String())
+	out := ui.OutputWriter.String()
+	require.Contains(out, "Index")
+	require.Regexp(`Jobs\s+= 1\n`, out)
+	require.Regexp(`Allocations\s+= 0\n`, out)
+
+	// Delete the job and restore the snapshot
+	_, _, err = client.Jobs().Deregister(*job.ID, true, nil)
+	require.NoError(err)
+
+	ui = new(cli.MockUi)
+	restore := &OperatorSnapshotRestoreCommand{Meta: Meta{Ui: ui}}
+	code = restore.Run([]string{"-address=" + url, path})
+	require.Equal(0, code, ui.ErrorWriter.String())
+	require.Contains(ui.OutputWriter.String(), "Snapshot Restored")
+
+	// The job is back
+	restored, _, err := client.Jobs().Info(*job.ID, &api.QueryOptions{})
+	require.NoError(err)
+	require.Equal(*job.ID, *restored.ID)
+}
+
+func TestOperatorSnapshotCommand_Fails(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	tmpDir, err := ioutil.TempDir("", "nomad-snapshot")
+	require.NoError(err)
+	defer os.RemoveAll(tmpDir)
+	invalid := filepath.Join(tmpDir, "invalid.snap")
+	require.NoError(ioutil.WriteFile(invalid, []byte("foo"), 0600))
+
+	// Fails on misuse
+	ui := new(cli.MockUi)
+	inspect := &OperatorSnapshotInspectCommand{Meta: Meta{Ui: ui}}
+	code := inspect.Run([]string{})
+	require.Equal(1, code)
+	require.Contains(ui.ErrorWriter.String(), commandErrorText(inspect))
+
+	// Fails on invalid snapshots
+	ui = new(cli.MockUi)
+	inspect = &OperatorSnapshotInspectCommand{Meta: Meta{Ui: ui}}
+	code = inspect.Run([]string{invalid})
+	require.Equal(1, code)
+	require.Contains(ui.ErrorWriter.String(), "Error reading snapshot")
+
+	// Fails on missing files
+	ui = new(cli.MockUi)
+	restore := &OperatorSnapshotRestoreCommand{Meta: Meta{Ui: ui}}
+	code = restore.Run([]string{filepath.Join(tmpDir, "missing.snap")})
+	require.Equal(1, code)
+	require.True(strings.Contains(ui.ErrorWriter.String(), "Error opening snapshot file"))
+
+	// Fails on connection failure
+	ui = new(cli.MockUi)
+	save := &OperatorSnapshotSaveCommand{Meta: Meta{Ui: ui}}
+	code = save.Run([]string{"-address=nope", filepath.Join(tmpDir, "backup.snap")})
+	require.Equal(1, code)
+	require.Contains(ui.ErrorWriter.String(), "Error querying snapshot")
+}
@@ -115,7 +115,9 @@ limit {
     region_limit {
         cpu = 2500
         memory = 1000
+        disk = 10000
     }
+    alloc_limit = 50
 }
 `)
 
@@ -128,8 +130,10 @@ var defaultJsonQuotaSpec = strings.TrimSpace(`
 			"Region": "global",
 			"RegionLimit": {
 				"CPU": 2500,
-				"MemoryMB": 1000
-			}
+				"MemoryMB": 1000,
+				"DiskMB": 10000
+			},
+			"AllocLimit": 50
 		}
 	]
 }
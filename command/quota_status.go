@@ -155,7 +155,7 @@ func formatQuotaLimits(spec *api.QuotaSpec, usages map[string]*api.QuotaUsage) s
 	sort.Sort(api.QuotaLimitSort(spec.Limits))
 
 	limits := make([]string, len(spec.Limits)+1)
-	limits[0] = "Region|CPU Usage|Memory Usage"
+	limits[0] = "Region|CPU Usage|Memory Usage|Disk Usage|Alloc Usage"
 	i := 0
 	for _, specLimit := range spec.Limits {
 		i++
@@ -175,18 +175,31 @@ func formatQuotaLimits(spec *api.QuotaSpec, usages map[string]*api.QuotaUsage) s
 		if !ok {
 			cpu := fmt.Sprintf("- / %s", formatQuotaLimitInt(specLimit.RegionLimit.CPU))
 			memory := fmt.Sprintf("- / %s", formatQuotaLimitInt(specLimit.RegionLimit.MemoryMB))
-			limits[i] = fmt.Sprintf("%s|%s|%s", specLimit.Region, cpu, memory)
+			disk := fmt.Sprintf("- / %s", formatQuotaLimitInt(specLimit.RegionLimit.DiskMB))
+			allocs := fmt.Sprintf("- / %s", formatQuotaLimitInt(&specLimit.AllocLimit))
+			limits[i] = fmt.Sprintf("%s|%s|%s|%s|%s", specLimit.Region, cpu, memory, disk, allocs)
 			continue
 		}
 
-		cpu := fmt.Sprintf("%d / %s", *used.RegionLimit.CPU, formatQuotaLimitInt(specLimit.RegionLimit.CPU))
-		memory := fmt.Sprintf("%d / %s", *used.RegionLimit.MemoryMB, formatQuotaLimitInt(specLimit.RegionLimit.MemoryMB))
-		limits[i] = fmt.Sprintf("%s|%s|%s", specLimit.Region, cpu, memory)
+		cpu := fmt.Sprintf("%s / %s", formatQuotaUsedInt(used.RegionLimit.CPU), formatQuotaLimitInt(specLimit.RegionLimit.CPU))
+		memory := fmt.Sprintf("%s / %s", formatQuotaUsedInt(used.RegionLimit.MemoryMB), formatQuotaLimitInt(specLimit.RegionLimit.MemoryMB))
+		disk := fmt.Sprintf("%s / %s", formatQuotaUsedInt(used.RegionLimit.DiskMB), formatQuotaLimitInt(specLimit.RegionLimit.DiskMB))
+		allocs := fmt.Sprintf("%d / %s", used.AllocLimit, formatQuotaLimitInt(&specLimit.AllocLimit))
+		limits[i] = fmt.Sprintf("%s|%s|%s|%s|%s", specLimit.Region, cpu, memory, disk, allocs)
 	}
 
 	return formatList(limits)
 }
 
+// formatQuotaUsedInt takes a used integer resource value and returns the
+// appropriate string for output.
+func formatQuotaUsedInt(value *int) string {
+	if value == nil {
+		return "0"
+	}
+	return strconv.Itoa(*value)
+}
+
 // formatQuotaLimitInt takes a integer resource value and returns the
 // appropriate string for output.
 func formatQuotaLimitInt(value *int) string {
@@ -0,0 +1,42 @@
+package command
+
+import (
+	"strings"
+
+	"github.com/mitchellh/cli"
+)
+
+type ServiceCommand struct {
+	Meta
+}
+
+func (f *ServiceCommand) Help() string {
+	helpText := `
+Usage: nomad service <subcommand> [options] [args]
+
+  This command groups subcommands for interacting with the services registered
+  with the Nomad servers. Services are registered with the servers instead of
+  Consul when their provider is "nomad".
+
+  List the services of the namespace:
+
+      $ nomad service list
+
+  Read the registered instances of a service:
+
+      $ nomad service info redis
+
+  Please see the individual subcommand help for detailed usage information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (f *ServiceCommand) Synopsis() string {
+	return "Interact with registered services"
+}
+
+func (f *ServiceCommand) Name() string { return "service" }
+
+func (f *ServiceCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
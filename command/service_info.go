@@ -0,0 +1,161 @@
+package command
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+)
+
+type ServiceInfoCommand struct {
+	Meta
+}
+
+func (c *ServiceInfoCommand) Help() string {
+	helpText := `
+Usage: nomad service info [options] <service>
+
+  Info is used to read the registered instances of a service, including their
+  address, port and health. A service is healthy while its task is running
+  and its allocation isn't unhealthy.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Info Options:
+
+  -job <job-id>
+    Only read the instances of the service registered by the job.
+
+  -verbose
+    Display full information.
+
+  -json
+    Output the instances in a JSON format.
+
+  -t
+    Format and display the instances using a Go template.
+`
+
+	return strings.TrimSpace(helpText)
+}
+
+func (c *ServiceInfoCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-job":     complete.PredictAnything,
+			"-verbose": complete.PredictNothing,
+			"-json":    complete.PredictNothing,
+			"-t":       complete.PredictAnything,
+		})
+}
+
+func (c *ServiceInfoCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictFunc(func(a complete.Args) []string {
+		client, err := c.Meta.Client()
+		if err != nil {
+			return nil
+		}
+
+		services, _, err := client.Services().List(nil)
+		if err != nil {
+			return nil
+		}
+		var names []string
+		for _, s := range services {
+			if strings.HasPrefix(s.ServiceName, a.Last) {
+				names = append(names, s.ServiceName)
+			}
+		}
+		return names
+	})
+}
+
+func (c *ServiceInfoCommand) Synopsis() string {
+	return "Display the registered instances of a service"
+}
+
+func (c *ServiceInfoCommand) Name() string { return "service info" }
+
+func (c *ServiceInfoCommand) Run(args []string) int {
+	var json, verbose bool
+	var tmpl, jobID string
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&jobID, "job", "", "")
+	flags.BoolVar(&verbose, "verbose", false, "")
+	flags.BoolVar(&json, "json", false, "")
+	flags.StringVar(&tmpl, "t", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got exactly one argument
+	args = flags.Args()
+	if len(args) != 1 {
+		c.Ui.Error("This command takes one argument: <service>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+	name := args[0]
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	var services []*api.ServiceRegistration
+	if jobID != "" {
+		services, _, err = client.Services().GetByJob(name, jobID, nil)
+	} else {
+		services, _, err = client.Services().Get(name, nil)
+	}
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error reading service: %s", err))
+		return 1
+	}
+
+	if json || len(tmpl) > 0 {
+		out, err := Format(json, tmpl, services)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+
+		c.Ui.Output(out)
+		return 0
+	}
+
+	if len(services) == 0 {
+		c.Ui.Error(fmt.Sprintf("No instances of service %q found", name))
+		return 1
+	}
+
+	// Truncate the id unless full length is requested
+	length := shortId
+	if verbose {
+		length = fullId
+	}
+
+	rows := make([]string, len(services)+1)
+	rows[0] = "Job ID|Address|Tags|Node ID|Alloc ID|Health"
+	for i, s := range services {
+		rows[i+1] = fmt.Sprintf("%s|%s|[%s]|%s|%s|%s",
+			s.JobID,
+			net.JoinHostPort(s.Address, strconv.Itoa(s.Port)),
+			strings.Join(s.Tags, ","),
+			limit(s.NodeID, length),
+			limit(s.AllocID, length),
+			s.Health)
+	}
+	c.Ui.Output(formatList(rows))
+	return 0
+}
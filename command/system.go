@@ -0,0 +1,41 @@
+package command
+
+import (
+	"strings"
+
+	"github.com/mitchellh/cli"
+)
+
+type SystemCommand struct {
+	Meta
+}
+
+func (c *SystemCommand) Help() string {
+	helpText := `
+Usage: nomad system <subcommand> [options]
+
+  This command groups subcommands for interacting with the system API. Users
+  can force a garbage collection or reconcile the job summaries.
+
+  Run a garbage collection:
+
+      $ nomad system gc
+
+  Reconcile the job summaries:
+
+      $ nomad system reconcile summaries
+
+  Please see the individual subcommand help for detailed usage information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *SystemCommand) Synopsis() string {
+	return "Interact with the system API"
+}
+
+func (c *SystemCommand) Name() string { return "system" }
+
+func (c *SystemCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
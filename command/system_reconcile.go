@@ -0,0 +1,36 @@
+package command
+
+import (
+	"strings"
+
+	"github.com/mitchellh/cli"
+)
+
+type SystemReconcileCommand struct {
+	Meta
+}
+
+func (c *SystemReconcileCommand) Help() string {
+	helpText := `
+Usage: nomad system reconcile <subcommand> [options]
+
+  This command groups subcommands for reconciling the system state.
+
+  Reconcile the job summaries:
+
+      $ nomad system reconcile summaries
+
+  Please see the individual subcommand help for detailed usage information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *SystemReconcileCommand) Synopsis() string {
+	return "Perform system reconciliation tasks"
+}
+
+func (c *SystemReconcileCommand) Name() string { return "system reconcile" }
+
+func (c *SystemReconcileCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
@@ -0,0 +1,69 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/posener/complete"
+)
+
+type SystemReconcileSummariesCommand struct {
+	Meta
+}
+
+func (c *SystemReconcileSummariesCommand) Help() string {
+	helpText := `
+Usage: nomad system reconcile summaries [options]
+
+  Reconciles the summaries of all registered jobs. The summaries are rebuilt
+  from the allocations of the jobs, which fixes the counts of the allocations
+  by status when they drifted.
+
+General Options:
+
+  ` + generalOptionsUsage()
+	return strings.TrimSpace(helpText)
+}
+
+func (c *SystemReconcileSummariesCommand) Synopsis() string {
+	return "Reconciles the summaries of all registered jobs"
+}
+
+func (c *SystemReconcileSummariesCommand) AutocompleteFlags() complete.Flags {
+	return c.Meta.AutocompleteFlags(FlagSetClient)
+}
+
+func (c *SystemReconcileSummariesCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *SystemReconcileSummariesCommand) Name() string { return "system reconcile summaries" }
+
+func (c *SystemReconcileSummariesCommand) Run(args []string) int {
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got no arguments
+	if len(flags.Args()) != 0 {
+		c.Ui.Error("This command takes no arguments")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	if err := client.System().ReconcileSummaries(); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error running system summary reconciliation: %s", err))
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,73 @@
+package command
+
+import (
+	"strings"
+
+	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
+)
+
+type VarCommand struct {
+	Meta
+}
+
+func (f *VarCommand) Help() string {
+	helpText := `
+Usage: nomad var <subcommand> [options] [args]
+
+  This command groups subcommands for interacting with variables. Variables
+  are sets of key/value items stored encrypted by the servers at a path of a
+  namespace. Tasks can read the variables at the paths of their job, task
+  group and task below "nomad/jobs".
+
+  Create or update a variable:
+
+      $ nomad var put project/web user=admin password=hunter2
+
+  Read a variable:
+
+      $ nomad var get project/web
+
+  List variables:
+
+      $ nomad var list project/
+
+  Delete a variable:
+
+      $ nomad var purge project/web
+
+  Please see the individual subcommand help for detailed usage information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (f *VarCommand) Synopsis() string {
+	return "Interact with variables"
+}
+
+func (f *VarCommand) Name() string { return "var" }
+
+func (f *VarCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
+
+// VariablePathPredictor returns a predictor of the paths of variables
+func VariablePathPredictor(factory ApiClientFactory) complete.Predictor {
+	return complete.PredictFunc(func(a complete.Args) []string {
+		client, err := factory()
+		if err != nil {
+			return nil
+		}
+
+		variables, _, err := client.Variables().PrefixList(a.Last, nil)
+		if err != nil {
+			return nil
+		}
+
+		paths := make([]string, 0, len(variables))
+		for _, v := range variables {
+			paths = append(paths, v.Path)
+		}
+		return paths
+	})
+}
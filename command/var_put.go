@@ -0,0 +1,127 @@
+package command
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+)
+
+type VarPutCommand struct {
+	Meta
+}
+
+func (c *VarPutCommand) Help() string {
+	helpText := `
+Usage: nomad var put [options] <path> <key>=<value> [<key>=<value>...]
+
+  Put is used to create or update the variable at the given path. The items of
+  the variable are replaced by the given items. A value starting with "@" is
+  read from the file at the path following it.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Put Options:
+
+  -check-index
+    If set, the variable is only written if its modify index is the given
+    index. A check index of 0 only creates the variable if it doesn't exist.
+`
+
+	return strings.TrimSpace(helpText)
+}
+
+func (c *VarPutCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-check-index": complete.PredictAnything,
+		})
+}
+
+func (c *VarPutCommand) AutocompleteArgs() complete.Predictor {
+	return VariablePathPredictor(c.Meta.Client)
+}
+
+func (c *VarPutCommand) Synopsis() string {
+	return "Create or update a variable"
+}
+
+func (c *VarPutCommand) Name() string { return "var put" }
+
+func (c *VarPutCommand) Run(args []string) int {
+	var checkIndex int64
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.Int64Var(&checkIndex, "check-index", -1, "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got a path and items
+	args = flags.Args()
+	if len(args) < 2 {
+		c.Ui.Error("This command takes at least two arguments: <path> <key>=<value>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	items, err := parseVarItems(args[1:])
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	variable := &api.Variable{
+		Path:  args[0],
+		Items: items,
+	}
+	var out *api.Variable
+	if checkIndex >= 0 {
+		out, _, err = client.Variables().CheckedPut(variable, uint64(checkIndex), nil)
+	} else {
+		out, _, err = client.Variables().Put(variable, nil)
+	}
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error writing variable: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf("Successfully wrote variable %q at index %d!", out.Path, out.ModifyIndex))
+	return 0
+}
+
+// parseVarItems parses the key=value arguments of the items of a variable.
+// Values starting with "@" are read from a file.
+func parseVarItems(args []string) (map[string]string, error) {
+	items := make(map[string]string, len(args))
+	for _, arg := range args {
+		parts := strings.SplitN(arg, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("Invalid item %q, items must be in the form key=value", arg)
+		}
+
+		key, value := parts[0], parts[1]
+		if strings.HasPrefix(value, "@") {
+			content, err := ioutil.ReadFile(value[1:])
+			if err != nil {
+				return nil, fmt.Errorf("Error reading the value of item %q: %s", key, err)
+			}
+			value = string(content)
+		}
+		items[key] = value
+	}
+	return items, nil
+}
@@ -0,0 +1,61 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVarCommands(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	srv, _, url := testServer(t, false, nil)
+	defer srv.Shutdown()
+
+	ui := new(cli.MockUi)
+	put := &VarPutCommand{Meta: Meta{Ui: ui}}
+	get := &VarGetCommand{Meta: Meta{Ui: ui}}
+	list := &VarListCommand{Meta: Meta{Ui: ui}}
+	purge := &VarPurgeCommand{Meta: Meta{Ui: ui}}
+
+	// Items must be in the key=value form
+	require.Equal(1, put.Run([]string{"-address=" + url, "project/web", "user"}))
+	require.Contains(ui.ErrorWriter.String(), "must be in the form key=value")
+	ui.ErrorWriter.Reset()
+
+	// Create the variable
+	code := put.Run([]string{"-address=" + url, "-check-index=0", "project/web", "user=admin", "password=hunter2"})
+	require.Equal(0, code, ui.ErrorWriter.String())
+	require.Contains(ui.OutputWriter.String(), `Successfully wrote variable "project/web"`)
+	ui.OutputWriter.Reset()
+
+	// The variable already exists
+	require.Equal(1, put.Run([]string{"-address=" + url, "-check-index=0", "project/web", "user=admin"}))
+	ui.ErrorWriter.Reset()
+
+	// Read the variable
+	require.Equal(0, get.Run([]string{"-address=" + url, "project/web"}), ui.ErrorWriter.String())
+	out := ui.OutputWriter.String()
+	require.Contains(out, "password")
+	require.Contains(out, "hunter2")
+	ui.OutputWriter.Reset()
+
+	require.Equal(0, get.Run([]string{"-address=" + url, "-item=user", "project/web"}), ui.ErrorWriter.String())
+	require.Equal("admin\n", ui.OutputWriter.String())
+	ui.OutputWriter.Reset()
+
+	// List the variables
+	require.Equal(0, list.Run([]string{"-address=" + url, "project/"}), ui.ErrorWriter.String())
+	out = ui.OutputWriter.String()
+	require.Contains(out, "project/web")
+	require.NotContains(out, "hunter2")
+	ui.OutputWriter.Reset()
+
+	// Delete the variable
+	require.Equal(0, purge.Run([]string{"-address=" + url, "project/web"}), ui.ErrorWriter.String())
+	ui.OutputWriter.Reset()
+
+	require.Equal(0, list.Run([]string{"-address=" + url}), ui.ErrorWriter.String())
+	require.Contains(ui.OutputWriter.String(), "No variables found")
+}
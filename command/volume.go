@@ -0,0 +1,46 @@
+package command
+
+import (
+	"strings"
+
+	"github.com/mitchellh/cli"
+)
+
+type VolumeCommand struct {
+	Meta
+}
+
+func (f *VolumeCommand) Help() string {
+	helpText := `
+Usage: nomad volume <subcommand> [options] [args]
+
+  This command groups subcommands for interacting with the external storage
+  volumes registered with Nomad. Volumes are either backed by a path on the
+  host of a node or by the storage of a CSI plugin.
+
+  Register a volume that was provisioned out-of-band:
+
+      $ nomad volume register volume.hcl
+
+  Display the status of a volume:
+
+      $ nomad volume status <id>
+
+  Release the claims of a volume on a node:
+
+      $ nomad volume detach <id> <node>
+
+  Please see the individual subcommand help for detailed usage information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (f *VolumeCommand) Synopsis() string {
+	return "Interact with volumes"
+}
+
+func (f *VolumeCommand) Name() string { return "volume" }
+
+func (f *VolumeCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
@@ -0,0 +1,92 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/posener/complete"
+)
+
+type VolumeCreateCommand struct {
+	Meta
+}
+
+func (c *VolumeCreateCommand) Help() string {
+	helpText := `
+Usage: nomad volume create [options] <input>
+
+  Create is used to create a new volume. Unlike register, create fails if a
+  volume with the same ID already exists and doesn't require the external_id
+  of the volume, which is set once the storage provider provisioned it. The
+  specification file will be read from stdin by specifying "-", otherwise a
+  path to the file is expected.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Create Options:
+
+  -json
+    Parse the input as a JSON volume specification.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *VolumeCreateCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-json": complete.PredictNothing,
+		})
+}
+
+func (c *VolumeCreateCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictFiles("*")
+}
+
+func (c *VolumeCreateCommand) Synopsis() string {
+	return "Create a new volume"
+}
+
+func (c *VolumeCreateCommand) Name() string { return "volume create" }
+
+func (c *VolumeCreateCommand) Run(args []string) int {
+	var jsonInput bool
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&jsonInput, "json", false, "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we get exactly one argument
+	args = flags.Args()
+	if l := len(args); l != 1 {
+		c.Ui.Error("This command takes one argument: <input>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	volume, err := readVolumeSpec(args[0], jsonInput)
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	_, err = client.Volumes().Create(volume, volumeWriteOptions(volume))
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error creating volume: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf("Successfully created volume %q!", volume.ID))
+	return 0
+}
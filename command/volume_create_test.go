@@ -0,0 +1,40 @@
+package command
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVolumeCreateCommand_Implements(t *testing.T) {
+	t.Parallel()
+	var _ cli.Command = &VolumeCreateCommand{}
+}
+
+func TestVolumeCreateCommand_Run(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	srv, client, url := testServer(t, false, nil)
+	defer srv.Shutdown()
+
+	fh, err := ioutil.TempFile("", "nomad-volume")
+	require.NoError(err)
+	defer os.Remove(fh.Name())
+	_, err = fh.WriteString(`{"ID": "database", "Type": "csi", "PluginID": "ebs"}`)
+	require.NoError(err)
+
+	ui := new(cli.MockUi)
+	cmd := &VolumeCreateCommand{Meta: Meta{Ui: ui}}
+	require.Equal(0, cmd.Run([]string{"-address=" + url, "-json", fh.Name()}), ui.ErrorWriter.String())
+	require.Contains(ui.OutputWriter.String(), `Successfully created volume "database"`)
+
+	_, _, err = client.Volumes().Info("database", nil)
+	require.NoError(err)
+
+	// Creating it again fails
+	require.Equal(1, cmd.Run([]string{"-address=" + url, "-json", fh.Name()}))
+	require.Contains(ui.ErrorWriter.String(), "already exists")
+}
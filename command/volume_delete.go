@@ -0,0 +1,94 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/posener/complete"
+)
+
+type VolumeDeleteCommand struct {
+	Meta
+}
+
+func (c *VolumeDeleteCommand) Help() string {
+	helpText := `
+Usage: nomad volume delete [options] <id>
+
+  Delete is used to delete a volume from Nomad. Volumes that are still claimed
+  by running allocations can not be deleted.
+
+General Options:
+
+  ` + generalOptionsUsage()
+
+	return strings.TrimSpace(helpText)
+}
+
+func (c *VolumeDeleteCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{})
+}
+
+func (c *VolumeDeleteCommand) AutocompleteArgs() complete.Predictor {
+	return predictVolumes(c.Meta)
+}
+
+func (c *VolumeDeleteCommand) Synopsis() string {
+	return "Delete a volume"
+}
+
+func (c *VolumeDeleteCommand) Name() string { return "volume delete" }
+
+func (c *VolumeDeleteCommand) Run(args []string) int {
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got exactly one argument
+	args = flags.Args()
+	if l := len(args); l != 1 {
+		c.Ui.Error("This command takes one argument: <id>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	// Delete the volume
+	id := args[0]
+	if _, err := client.Volumes().Delete(id, nil); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error deleting volume: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf("Successfully deleted volume %q!", id))
+	return 0
+}
+
+// predictVolumes predicts the IDs of the volumes of the namespace.
+func predictVolumes(m Meta) complete.Predictor {
+	return complete.PredictFunc(func(a complete.Args) []string {
+		client, err := m.Client()
+		if err != nil {
+			return nil
+		}
+
+		volumes, _, err := client.Volumes().PrefixList(a.Last, nil)
+		if err != nil {
+			return nil
+		}
+		ids := make([]string, 0, len(volumes))
+		for _, v := range volumes {
+			ids = append(ids, v.ID)
+		}
+		return ids
+	})
+}
@@ -0,0 +1,36 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVolumeDeleteCommand(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	srv, client, url := testServer(t, false, nil)
+	defer srv.Shutdown()
+
+	_, err := client.Volumes().Register(&api.Volume{
+		ID:         "database",
+		Type:       api.VolumeTypeCSI,
+		PluginID:   "ebs",
+		ExternalID: "vol-0123456789",
+	}, nil)
+	require.NoError(err)
+
+	ui := new(cli.MockUi)
+	cmd := &VolumeDeleteCommand{Meta: Meta{Ui: ui}}
+
+	require.Equal(1, cmd.Run([]string{"-address=" + url, "other"}))
+	require.Contains(ui.ErrorWriter.String(), "not found")
+
+	require.Equal(0, cmd.Run([]string{"-address=" + url, "database"}), ui.ErrorWriter.String())
+	require.Contains(ui.OutputWriter.String(), `Successfully deleted volume "database"`)
+
+	_, _, err = client.Volumes().Info("database", nil)
+	require.Error(err)
+}
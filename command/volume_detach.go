@@ -0,0 +1,93 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/posener/complete"
+)
+
+type VolumeDetachCommand struct {
+	Meta
+}
+
+func (c *VolumeDetachCommand) Help() string {
+	helpText := `
+Usage: nomad volume detach [options] <id> <node>
+
+  Detach is used to release the claims of a volume on a node, once the
+  allocations using the volume on the node stopped. The claims of allocations
+  still running on the node can not be released.
+
+General Options:
+
+  ` + generalOptionsUsage()
+
+	return strings.TrimSpace(helpText)
+}
+
+func (c *VolumeDetachCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{})
+}
+
+func (c *VolumeDetachCommand) AutocompleteArgs() complete.Predictor {
+	return predictVolumes(c.Meta)
+}
+
+func (c *VolumeDetachCommand) Synopsis() string {
+	return "Release the claims of a volume on a node"
+}
+
+func (c *VolumeDetachCommand) Name() string { return "volume detach" }
+
+func (c *VolumeDetachCommand) Run(args []string) int {
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got exactly two arguments
+	args = flags.Args()
+	if l := len(args); l != 2 {
+		c.Ui.Error("This command takes two arguments: <id> <node>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+	id, nodeID := args[0], args[1]
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	// Resolve a node ID prefix
+	if len(nodeID) < 36 {
+		nodes, _, err := client.Nodes().PrefixList(sanitizeUUIDPrefix(nodeID))
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error querying node: %s", err))
+			return 1
+		}
+		switch len(nodes) {
+		case 0:
+			c.Ui.Error(fmt.Sprintf("No node(s) with prefix %q found", nodeID))
+			return 1
+		case 1:
+			nodeID = nodes[0].ID
+		default:
+			c.Ui.Error(fmt.Sprintf("Prefix %q matched multiple nodes", nodeID))
+			return 1
+		}
+	}
+
+	if _, err := client.Volumes().Detach(id, nodeID, nil); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error detaching volume: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf("Successfully detached volume %q from node %q!", id, nodeID))
+	return 0
+}
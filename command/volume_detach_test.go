@@ -0,0 +1,41 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVolumeDetachCommand(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	srv, client, url := testServer(t, false, nil)
+	defer srv.Shutdown()
+
+	_, err := client.Volumes().Register(&api.Volume{
+		ID:         "database",
+		Type:       api.VolumeTypeCSI,
+		PluginID:   "ebs",
+		ExternalID: "vol-0123456789",
+	}, nil)
+	require.NoError(err)
+
+	ui := new(cli.MockUi)
+	cmd := &VolumeDetachCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	require.Equal(1, cmd.Run([]string{"-address=" + url, "database"}))
+	require.Contains(ui.ErrorWriter.String(), commandErrorText(cmd))
+	ui.ErrorWriter.Reset()
+
+	// Node prefixes must match a node
+	require.Equal(1, cmd.Run([]string{"-address=" + url, "database", "abc"}))
+	require.Contains(ui.ErrorWriter.String(), "No node(s) with prefix")
+
+	nodeID := uuid.Generate()
+	require.Equal(0, cmd.Run([]string{"-address=" + url, "database", nodeID}), ui.ErrorWriter.String())
+	require.Contains(ui.OutputWriter.String(), `Successfully detached volume "database"`)
+}
@@ -0,0 +1,233 @@
+package command
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	humanize "github.com/dustin/go-humanize"
+	"github.com/hashicorp/hcl"
+	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/mitchellh/mapstructure"
+	"github.com/posener/complete"
+)
+
+type VolumeRegisterCommand struct {
+	Meta
+}
+
+func (c *VolumeRegisterCommand) Help() string {
+	helpText := `
+Usage: nomad volume register [options] <input>
+
+  Register is used to register or update a volume that was provisioned
+  out-of-band, such as a disk created in the cloud provider. The specification
+  must set the external_id of the volume in the storage provider. The
+  specification file will be read from stdin by specifying "-", otherwise a
+  path to the file is expected.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Register Options:
+
+  -json
+    Parse the input as a JSON volume specification.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *VolumeRegisterCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-json": complete.PredictNothing,
+		})
+}
+
+func (c *VolumeRegisterCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictFiles("*")
+}
+
+func (c *VolumeRegisterCommand) Synopsis() string {
+	return "Register an existing volume"
+}
+
+func (c *VolumeRegisterCommand) Name() string { return "volume register" }
+
+func (c *VolumeRegisterCommand) Run(args []string) int {
+	var jsonInput bool
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&jsonInput, "json", false, "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we get exactly one argument
+	args = flags.Args()
+	if l := len(args); l != 1 {
+		c.Ui.Error("This command takes one argument: <input>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	volume, err := readVolumeSpec(args[0], jsonInput)
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	_, err = client.Volumes().Register(volume, volumeWriteOptions(volume))
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error registering volume: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf("Successfully registered volume %q!", volume.ID))
+	return 0
+}
+
+// readVolumeSpec reads the volume specification from the file, or stdin if
+// the file is "-", and parses it as HCL or JSON.
+func readVolumeSpec(file string, jsonInput bool) (*api.Volume, error) {
+	var raw []byte
+	var err error
+	if file == "-" {
+		raw, err = ioutil.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read stdin: %v", err)
+		}
+	} else {
+		raw, err = ioutil.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read file: %v", err)
+		}
+	}
+
+	if jsonInput {
+		var volume api.Volume
+		dec := json.NewDecoder(bytes.NewBuffer(raw))
+		if err := dec.Decode(&volume); err != nil {
+			return nil, fmt.Errorf("Failed to parse volume: %v", err)
+		}
+		return &volume, nil
+	}
+
+	volume, err := parseVolumeSpec(raw)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing volume specification: %s", err)
+	}
+	return volume, nil
+}
+
+// volumeWriteOptions returns the write options registering the volume in
+// the namespace of its specification, if set.
+func volumeWriteOptions(volume *api.Volume) *api.WriteOptions {
+	if volume.Namespace == "" {
+		return nil
+	}
+	return &api.WriteOptions{Namespace: volume.Namespace}
+}
+
+// parseVolumeSpec is used to parse the volume specification from HCL
+func parseVolumeSpec(input []byte) (*api.Volume, error) {
+	root, err := hcl.ParseBytes(input)
+	if err != nil {
+		return nil, err
+	}
+
+	// Top-level item should be a list
+	list, ok := root.Node.(*ast.ObjectList)
+	if !ok {
+		return nil, fmt.Errorf("error parsing: root should be an object")
+	}
+
+	// Check for invalid keys
+	valid := []string{
+		"id",
+		"name",
+		"namespace",
+		"type",
+		"plugin_id",
+		"node_id",
+		"external_id",
+		"access_mode",
+		"attachment_mode",
+		"capacity_min",
+		"capacity_max",
+		"parameters",
+		"context",
+	}
+	if err := helper.CheckHCLKeys(list, valid); err != nil {
+		return nil, err
+	}
+
+	// Decode the full thing into a map[string]interface for ease
+	var m map[string]interface{}
+	if err := hcl.DecodeObject(&m, list); err != nil {
+		return nil, err
+	}
+
+	// Manually parse
+	delete(m, "capacity_min")
+	delete(m, "capacity_max")
+	delete(m, "parameters")
+	delete(m, "context")
+
+	// Decode the rest
+	var volume api.Volume
+	if err := mapstructure.WeakDecode(m, &volume); err != nil {
+		return nil, err
+	}
+
+	// Parse the capacities, which are sizes such as "10GiB"
+	for _, c := range []struct {
+		key string
+		out *int64
+	}{
+		{"capacity_min", &volume.CapacityMin},
+		{"capacity_max", &volume.CapacityMax},
+	} {
+		o := list.Filter(c.key)
+		if len(o.Items) == 0 {
+			continue
+		}
+		var size string
+		if err := hcl.DecodeObject(&size, o.Items[0].Val); err != nil {
+			return nil, fmt.Errorf("%s: %v", c.key, err)
+		}
+		parsed, err := humanize.ParseBytes(size)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", c.key, err)
+		}
+		*c.out = int64(parsed)
+	}
+
+	// Parse the parameters and context
+	if o := list.Filter("parameters"); len(o.Items) > 0 {
+		if err := hcl.DecodeObject(&volume.Parameters, o.Items[0].Val); err != nil {
+			return nil, fmt.Errorf("parameters: %v", err)
+		}
+	}
+	if o := list.Filter("context"); len(o.Items) > 0 {
+		if err := hcl.DecodeObject(&volume.Context, o.Items[0].Val); err != nil {
+			return nil, fmt.Errorf("context: %v", err)
+		}
+	}
+
+	return &volume, nil
+}
@@ -0,0 +1,113 @@
+package command
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVolumeRegisterCommand_Implements(t *testing.T) {
+	t.Parallel()
+	var _ cli.Command = &VolumeRegisterCommand{}
+}
+
+func TestVolumeRegisterCommand_Fails(t *testing.T) {
+	t.Parallel()
+	ui := new(cli.MockUi)
+	cmd := &VolumeRegisterCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	if code := cmd.Run([]string{"some", "bad", "args"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, commandErrorText(cmd)) {
+		t.Fatalf("expected help output, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	// Fails on a missing file
+	if code := cmd.Run([]string{"/unicorns/leprechauns"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "Failed to read file") {
+		t.Fatalf("expected read error, got: %s", out)
+	}
+}
+
+func TestVolumeRegisterCommand_Run(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	srv, client, url := testServer(t, false, nil)
+	defer srv.Shutdown()
+
+	fh, err := ioutil.TempFile("", "nomad-volume")
+	require.NoError(err)
+	defer os.Remove(fh.Name())
+	_, err = fh.WriteString(`
+id          = "database"
+type        = "csi"
+plugin_id   = "ebs"
+external_id = "vol-0123456789"
+
+capacity_min = "10GiB"
+
+parameters {
+  type = "gp2"
+}
+`)
+	require.NoError(err)
+
+	ui := new(cli.MockUi)
+	cmd := &VolumeRegisterCommand{Meta: Meta{Ui: ui}}
+	require.Equal(0, cmd.Run([]string{"-address=" + url, fh.Name()}), ui.ErrorWriter.String())
+	require.Contains(ui.OutputWriter.String(), `Successfully registered volume "database"`)
+
+	volume, _, err := client.Volumes().Info("database", nil)
+	require.NoError(err)
+	require.Equal("vol-0123456789", volume.ExternalID)
+	require.EqualValues(10<<30, volume.CapacityMin)
+	require.Equal(map[string]string{"type": "gp2"}, volume.Parameters)
+}
+
+func TestParseVolumeSpec(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	volume, err := parseVolumeSpec([]byte(`
+id              = "data"
+name            = "host data"
+type            = "host"
+node_id         = "node"
+external_id     = "/srv/data"
+access_mode     = "single-node-reader-only"
+attachment_mode = "file-system"
+capacity_max    = "1GB"
+
+context {
+  owner = "ops"
+}
+`))
+	require.NoError(err)
+	require.Equal(&api.Volume{
+		ID:             "data",
+		Name:           "host data",
+		Type:           api.VolumeTypeHost,
+		NodeID:         "node",
+		ExternalID:     "/srv/data",
+		AccessMode:     api.VolumeAccessModeSingleNodeReader,
+		AttachmentMode: api.VolumeAttachmentModeFilesystem,
+		CapacityMax:    1000 * 1000 * 1000,
+		Context:        map[string]string{"owner": "ops"},
+	}, volume)
+
+	_, err = parseVolumeSpec([]byte(`size = "1GB"`))
+	require.Error(err)
+
+	_, err = parseVolumeSpec([]byte(`capacity_min = "lots"`))
+	require.Error(err)
+}
@@ -0,0 +1,221 @@
+package command
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	humanize "github.com/dustin/go-humanize"
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+)
+
+type VolumeStatusCommand struct {
+	Meta
+}
+
+func (c *VolumeStatusCommand) Help() string {
+	helpText := `
+Usage: nomad volume status [options] [id]
+
+  Status is used to display the status of a volume, including the allocations
+  claiming it. If no volume ID is given, the volumes of the namespace are
+  listed.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Status Options:
+
+  -type <host|csi>
+    Only list the volumes of the type.
+
+  -verbose
+    Display full information.
+
+  -json
+    Output the volumes in a JSON format.
+
+  -t
+    Format and display the volumes using a Go template.
+`
+
+	return strings.TrimSpace(helpText)
+}
+
+func (c *VolumeStatusCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-type":    complete.PredictSet(api.VolumeTypeHost, api.VolumeTypeCSI),
+			"-verbose": complete.PredictNothing,
+			"-json":    complete.PredictNothing,
+			"-t":       complete.PredictAnything,
+		})
+}
+
+func (c *VolumeStatusCommand) AutocompleteArgs() complete.Predictor {
+	return predictVolumes(c.Meta)
+}
+
+func (c *VolumeStatusCommand) Synopsis() string {
+	return "Display the status of volumes"
+}
+
+func (c *VolumeStatusCommand) Name() string { return "volume status" }
+
+func (c *VolumeStatusCommand) Run(args []string) int {
+	var json, verbose bool
+	var tmpl, volumeType string
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&volumeType, "type", "", "")
+	flags.BoolVar(&verbose, "verbose", false, "")
+	flags.BoolVar(&json, "json", false, "")
+	flags.StringVar(&tmpl, "t", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got at most one argument
+	args = flags.Args()
+	if l := len(args); l > 1 {
+		c.Ui.Error("This command takes either no arguments or one: <id>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	// Truncate the id unless full length is requested
+	length := shortId
+	if verbose {
+		length = fullId
+	}
+
+	if len(args) == 0 {
+		volumes, _, err := client.Volumes().List(volumeType, nil)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error listing volumes: %s", err))
+			return 1
+		}
+
+		if json || len(tmpl) > 0 {
+			out, err := Format(json, tmpl, volumes)
+			if err != nil {
+				c.Ui.Error(err.Error())
+				return 1
+			}
+
+			c.Ui.Output(out)
+			return 0
+		}
+
+		if len(volumes) == 0 {
+			c.Ui.Output("No volumes found")
+			return 0
+		}
+		c.Ui.Output(formatVolumes(volumes, length))
+		return 0
+	}
+
+	volume, _, err := client.Volumes().Info(args[0], nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error querying volume: %s", err))
+		return 1
+	}
+
+	if json || len(tmpl) > 0 {
+		out, err := Format(json, tmpl, volume)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+
+		c.Ui.Output(out)
+		return 0
+	}
+
+	c.Ui.Output(formatKV([]string{
+		fmt.Sprintf("ID|%s", volume.ID),
+		fmt.Sprintf("Name|%s", volume.Name),
+		fmt.Sprintf("Namespace|%s", volume.Namespace),
+		fmt.Sprintf("Type|%s", volume.Type),
+		fmt.Sprintf("Provider|%s", formatVolumeProvider(volume.Type, volume.PluginID, volume.NodeID, length)),
+		fmt.Sprintf("External ID|%s", volume.ExternalID),
+		fmt.Sprintf("Access Mode|%s", volume.AccessMode),
+		fmt.Sprintf("Attachment Mode|%s", volume.AttachmentMode),
+		fmt.Sprintf("Capacity|%s", formatVolumeCapacity(volume.CapacityMin, volume.CapacityMax)),
+	}))
+
+	c.Ui.Output(c.Colorize().Color("\n[bold]Claims[reset]"))
+	c.Ui.Output(formatVolumeClaims(volume.Claims, length))
+	return 0
+}
+
+func formatVolumes(volumes []*api.VolumeListStub, length int) string {
+	rows := make([]string, len(volumes)+1)
+	rows[0] = "ID|Name|Type|Provider|Access Mode|Claims"
+	for i, v := range volumes {
+		rows[i+1] = fmt.Sprintf("%s|%s|%s|%s|%s|%d read, %d write",
+			v.ID,
+			v.Name,
+			v.Type,
+			formatVolumeProvider(v.Type, v.PluginID, v.NodeID, length),
+			v.AccessMode,
+			v.ReadClaims,
+			v.WriteClaims)
+	}
+	return formatList(rows)
+}
+
+// formatVolumeProvider returns the plugin of CSI volumes and the node of
+// host volumes.
+func formatVolumeProvider(volumeType, pluginID, nodeID string, length int) string {
+	if volumeType == api.VolumeTypeHost {
+		return "node " + limit(nodeID, length)
+	}
+	return "plugin " + pluginID
+}
+
+// formatVolumeCapacity returns the capacity range of the volume, where zero
+// means no limit.
+func formatVolumeCapacity(min, max int64) string {
+	format := func(b int64) string {
+		if b == 0 {
+			return "<none>"
+		}
+		return humanize.IBytes(uint64(b))
+	}
+	return fmt.Sprintf("%s - %s", format(min), format(max))
+}
+
+func formatVolumeClaims(claims map[string]*api.VolumeClaim, length int) string {
+	if len(claims) == 0 {
+		return "No allocations claim the volume"
+	}
+
+	ids := make([]string, 0, len(claims))
+	for id := range claims {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	rows := make([]string, len(ids)+1)
+	rows[0] = "Alloc ID|Node ID|Mode"
+	for i, id := range ids {
+		claim := claims[id]
+		rows[i+1] = fmt.Sprintf("%s|%s|%s",
+			limit(claim.AllocID, length),
+			limit(claim.NodeID, length),
+			claim.Mode)
+	}
+	return formatList(rows)
+}
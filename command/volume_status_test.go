@@ -0,0 +1,74 @@
+package command
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVolumeStatusCommand_Implements(t *testing.T) {
+	t.Parallel()
+	var _ cli.Command = &VolumeStatusCommand{}
+}
+
+func TestVolumeStatusCommand_Fails(t *testing.T) {
+	t.Parallel()
+	ui := new(cli.MockUi)
+	cmd := &VolumeStatusCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	if code := cmd.Run([]string{"some", "bad", "args"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, commandErrorText(cmd)) {
+		t.Fatalf("expected help output, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	// Fails on connection failure
+	if code := cmd.Run([]string{"-address=nope"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "Error listing volumes") {
+		t.Fatalf("expected failed query error, got: %s", out)
+	}
+}
+
+func TestVolumeStatusCommand_Run(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	srv, client, url := testServer(t, false, nil)
+	defer srv.Shutdown()
+
+	ui := new(cli.MockUi)
+	cmd := &VolumeStatusCommand{Meta: Meta{Ui: ui}}
+
+	// No volumes are registered
+	require.Equal(0, cmd.Run([]string{"-address=" + url}), ui.ErrorWriter.String())
+	require.Contains(ui.OutputWriter.String(), "No volumes found")
+	ui.OutputWriter.Reset()
+
+	_, err := client.Volumes().Register(&api.Volume{
+		ID:          "database",
+		Type:        api.VolumeTypeCSI,
+		PluginID:    "ebs",
+		ExternalID:  "vol-0123456789",
+		CapacityMin: 10 << 30,
+	}, nil)
+	require.NoError(err)
+
+	require.Equal(0, cmd.Run([]string{"-address=" + url}), ui.ErrorWriter.String())
+	out := ui.OutputWriter.String()
+	require.Contains(out, "database")
+	require.Contains(out, "plugin ebs")
+	ui.OutputWriter.Reset()
+
+	require.Equal(0, cmd.Run([]string{"-address=" + url, "database"}), ui.ErrorWriter.String())
+	out = ui.OutputWriter.String()
+	require.Contains(out, "vol-0123456789")
+	require.Contains(out, "10 GiB - <none>")
+	require.Contains(out, "No allocations claim the volume")
+}
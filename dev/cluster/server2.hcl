@@ -11,6 +11,9 @@ name = "server2"
 server {
   enabled = true
 
+  # Wraps the root keys of variables, must be the same on all servers
+  keyring_encryption_key = "sOQZmTJ60lx+kEx+Bh/2JHmaMPFVxhLAur6NQRhNwYI="
+
   server_join {
     retry_join = ["127.0.0.1:4648", "127.0.0.1:6648"]
   }
@@ -4,6 +4,9 @@ data_dir = "/tmp/nomad-server"
 server {
   enabled = true
 
+  # Wraps the root keys of variables, must be the same on all servers
+  keyring_encryption_key = "sOQZmTJ60lx+kEx+Bh/2JHmaMPFVxhLAur6NQRhNwYI="
+
   # Self-elect, should be 3 or 5 for production
   bootstrap_expect = 1
 }
@@ -2,6 +2,7 @@ package nvidia
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/hashicorp/nomad/devices/gpu/nvidia/nvml"
@@ -21,6 +22,7 @@ const (
 	PCIBandwidthAttr    = "pci_bandwidth"
 	DisplayStateAttr    = "display_state"
 	PersistenceModeAttr = "persistence_mode"
+	MigProfileAttr      = "mig_profile"
 )
 
 // fingerprint is the long running goroutine that detects hardware
@@ -74,8 +76,11 @@ func (d *NvidiaDevice) writeFingerprintToChannel(devices chan<- *device.Fingerpr
 		},
 	}
 
-	// Group all FingerprintDevices by DeviceName attribute
+	// Group all FingerprintDevices by DeviceName attribute. Devices with MIG
+	// enabled are not schedulable themselves, instead their MIG devices are
+	// grouped by DeviceName and MIG profile
 	deviceListByDeviceName := make(map[string][]*nvml.FingerprintDeviceData)
+	migListByGroupName := make(map[string][]*migDevice)
 	for _, device := range fingerprintDevices {
 		deviceName := device.DeviceName
 		if deviceName == nil {
@@ -85,24 +90,88 @@ func (d *NvidiaDevice) writeFingerprintToChannel(devices chan<- *device.Fingerpr
 			deviceName = &notAvailableCopy
 		}
 
+		if len(device.MigDevices) != 0 {
+			for _, mig := range device.MigDevices {
+				groupName := migGroupName(*deviceName, mig.Profile)
+				migListByGroupName[groupName] = append(migListByGroupName[groupName], &migDevice{
+					MigDeviceInfo: mig,
+					parent:        device,
+				})
+			}
+			continue
+		}
+
 		deviceListByDeviceName[*deviceName] = append(deviceListByDeviceName[*deviceName], device)
 	}
 
 	// Build Fingerprint response with computed groups and send it over the channel
-	deviceGroups := make([]*device.DeviceGroup, 0, len(deviceListByDeviceName))
+	deviceGroups := make([]*device.DeviceGroup, 0, len(deviceListByDeviceName)+len(migListByGroupName))
 	for groupName, devices := range deviceListByDeviceName {
 		deviceGroups = append(deviceGroups, deviceGroupFromFingerprintData(groupName, devices, commonAttributes))
 	}
+	for groupName, migs := range migListByGroupName {
+		deviceGroups = append(deviceGroups, deviceGroupFromMigDevices(groupName, migs, commonAttributes))
+	}
 	devices <- device.NewFingerprint(deviceGroups...)
 }
 
-// ignoreFingerprintedDevices excludes ignored devices from fingerprint output
+// migDevice is a MIG device along with the device it partitions
+type migDevice struct {
+	*nvml.MigDeviceInfo
+	parent *nvml.FingerprintDeviceData
+}
+
+// migGroupName returns the name of the device group of MIG devices with the
+// given profile partitioning devices with the given name
+func migGroupName(deviceName, profile string) string {
+	return fmt.Sprintf("%s MIG %s", deviceName, profile)
+}
+
+// schedulableDeviceIDs returns the IDs of the devices exposed to nomad for the
+// fingerprinted device. Devices with MIG enabled expose their MIG devices
+func schedulableDeviceIDs(device *nvml.FingerprintDeviceData) []string {
+	if len(device.MigDevices) == 0 {
+		return []string{device.UUID}
+	}
+
+	ids := make([]string, len(device.MigDevices))
+	for i, mig := range device.MigDevices {
+		ids[i] = mig.UUID
+	}
+	return ids
+}
+
+// ignoreFingerprintedDevices excludes ignored devices from fingerprint output.
+// Ignoring a device with MIG enabled ignores all of its MIG devices while MIG
+// devices may also be ignored individually
 func ignoreFingerprintedDevices(deviceData []*nvml.FingerprintDeviceData, ignoredGPUIDs map[string]struct{}) []*nvml.FingerprintDeviceData {
 	var result []*nvml.FingerprintDeviceData
 	for _, fingerprintDevice := range deviceData {
-		if _, ignored := ignoredGPUIDs[fingerprintDevice.UUID]; !ignored {
+		if _, ignored := ignoredGPUIDs[fingerprintDevice.UUID]; ignored {
+			continue
+		}
+
+		if len(fingerprintDevice.MigDevices) == 0 {
 			result = append(result, fingerprintDevice)
+			continue
+		}
+
+		var migDevices []*nvml.MigDeviceInfo
+		for _, mig := range fingerprintDevice.MigDevices {
+			if _, ignored := ignoredGPUIDs[mig.UUID]; !ignored {
+				migDevices = append(migDevices, mig)
+			}
+		}
+
+		// The device itself is not schedulable with MIG enabled so it is
+		// dropped once all of its MIG devices are ignored
+		if len(migDevices) == 0 {
+			continue
 		}
+
+		deviceCopy := *fingerprintDevice
+		deviceCopy.MigDevices = migDevices
+		result = append(result, &deviceCopy)
 	}
 	return result
 }
@@ -116,17 +185,17 @@ func (d *NvidiaDevice) fingerprintChanged(allDevices []*nvml.FingerprintDeviceDa
 
 	changeDetected := false
 	// check if every device in allDevices is in d.devices
+	fingerprintDeviceMap := make(map[string]struct{})
 	for _, device := range allDevices {
-		if _, ok := d.devices[device.UUID]; !ok {
-			changeDetected = true
+		for _, id := range schedulableDeviceIDs(device) {
+			if _, ok := d.devices[id]; !ok {
+				changeDetected = true
+			}
+			fingerprintDeviceMap[id] = struct{}{}
 		}
 	}
 
 	// check if every device in d.devices is in allDevices
-	fingerprintDeviceMap := make(map[string]struct{})
-	for _, device := range allDevices {
-		fingerprintDeviceMap[device.UUID] = struct{}{}
-	}
 	for id := range d.devices {
 		if _, ok := fingerprintDeviceMap[id]; !ok {
 			changeDetected = true
@@ -175,6 +244,51 @@ func deviceGroupFromFingerprintData(groupName string, deviceList []*nvml.Fingerp
 	return deviceGroup
 }
 
+// deviceGroupFromMigDevices composes deviceGroup from MIG devices with the
+// same profile partitioning devices with the same DeviceName
+func deviceGroupFromMigDevices(groupName string, migList []*migDevice, commonAttributes map[string]*structs.Attribute) *device.DeviceGroup {
+	if len(migList) == 0 {
+		return nil
+	}
+
+	devices := make([]*device.Device, len(migList))
+	for index, mig := range migList {
+		devices[index] = &device.Device{
+			ID:      mig.UUID,
+			Healthy: true,
+			HwLocality: &device.DeviceLocality{
+				PciBusID: mig.parent.PCIBusID,
+			},
+		}
+	}
+
+	// MIG devices share the attributes of the device they partition except
+	// for their memory
+	attributes := attributesFromFingerprintDeviceData(migList[0].parent)
+	delete(attributes, MemoryAttr)
+	if mig := migList[0]; mig.MemoryMiB != nil {
+		attributes[MemoryAttr] = &structs.Attribute{
+			Int:  helper.Int64ToPtr(int64(*mig.MemoryMiB)),
+			Unit: structs.UnitMiB,
+		}
+	}
+	attributes[MigProfileAttr] = &structs.Attribute{
+		String: helper.StringToPtr(migList[0].Profile),
+	}
+
+	for attributeKey, attributeValue := range commonAttributes {
+		attributes[attributeKey] = attributeValue
+	}
+
+	return &device.DeviceGroup{
+		Vendor:     vendor,
+		Type:       deviceType,
+		Name:       groupName,
+		Devices:    devices,
+		Attributes: attributes,
+	}
+}
+
 // attributesFromFingerprintDeviceData converts nvml.FingerprintDeviceData
 // struct to device.DeviceGroup.Attributes format (map[string]string)
 // this function performs all nil checks for FingerprintDeviceData pointers
@@ -1177,6 +1177,107 @@ func TestWriteFingerprintToChannel(t *testing.T) {
 	}
 }
 
+func TestWriteFingerprintToChannel_MigDevices(t *testing.T) {
+	d := &NvidiaDevice{
+		nvmlClient: &MockNvmlClient{
+			FingerprintResponseReturned: &nvml.FingerprintData{
+				DriverVersion: "1",
+				Devices: []*nvml.FingerprintDeviceData{
+					{
+						DeviceData: &nvml.DeviceData{
+							UUID:       "1",
+							DeviceName: helper.StringToPtr("A100"),
+							MemoryMiB:  helper.Uint64ToPtr(40960),
+						},
+						PCIBusID:        "pciBusID1",
+						DisplayState:    "Enabled",
+						PersistenceMode: "Enabled",
+						MigDevices: []*nvml.MigDeviceInfo{
+							{
+								UUID:       "MIG-1",
+								ParentUUID: "1",
+								Profile:    "1g.5gb",
+								MemoryMiB:  helper.Uint64ToPtr(5120),
+							},
+							{
+								UUID:       "MIG-2",
+								ParentUUID: "1",
+								Profile:    "1g.5gb",
+								MemoryMiB:  helper.Uint64ToPtr(5120),
+							},
+							{
+								UUID:       "MIG-3",
+								ParentUUID: "1",
+								Profile:    "3g.20gb",
+								MemoryMiB:  helper.Uint64ToPtr(20480),
+							},
+						},
+					},
+				},
+			},
+		},
+		ignoredGPUIDs: map[string]struct{}{
+			"MIG-3": {},
+		},
+		devices: make(map[string]struct{}),
+		logger:  hclog.NewNullLogger(),
+	}
+
+	channel := make(chan *device.FingerprintResponse, 1)
+	d.writeFingerprintToChannel(channel)
+	actualResult := <-channel
+
+	// The device with MIG enabled is only exposed through its MIG devices
+	expected := &device.FingerprintResponse{
+		Devices: []*device.DeviceGroup{
+			{
+				Vendor: vendor,
+				Type:   deviceType,
+				Name:   "A100 MIG 1g.5gb",
+				Devices: []*device.Device{
+					{
+						ID:         "MIG-1",
+						Healthy:    true,
+						HwLocality: &device.DeviceLocality{PciBusID: "pciBusID1"},
+					},
+					{
+						ID:         "MIG-2",
+						Healthy:    true,
+						HwLocality: &device.DeviceLocality{PciBusID: "pciBusID1"},
+					},
+				},
+				Attributes: map[string]*structs.Attribute{
+					MemoryAttr: {
+						Int:  helper.Int64ToPtr(5120),
+						Unit: structs.UnitMiB,
+					},
+					MigProfileAttr: {
+						String: helper.StringToPtr("1g.5gb"),
+					},
+					DisplayStateAttr: {
+						String: helper.StringToPtr("Enabled"),
+					},
+					PersistenceModeAttr: {
+						String: helper.StringToPtr("Enabled"),
+					},
+					DriverVersionAttr: {
+						String: helper.StringToPtr("1"),
+					},
+				},
+			},
+		},
+	}
+	require.Equal(t, expected, actualResult)
+	require.Equal(t, map[string]struct{}{"MIG-1": {}, "MIG-2": {}}, d.devices)
+
+	// MIG devices are reserved by their own IDs
+	reservation, err := d.Reserve([]string{"MIG-1"})
+	require.NoError(t, err)
+	require.Equal(t, "MIG-1", reservation.Envs[nvidiaVisibleDevices])
+	_, err = d.Reserve([]string{"1"})
+	require.Error(t, err)
+}
+
 // Test if nonworking driver returns empty fingerprint data
 func TestFingerprint(t *testing.T) {
 	for _, testCase := range []struct {
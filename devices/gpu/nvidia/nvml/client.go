@@ -24,6 +24,10 @@ type FingerprintDeviceData struct {
 	DisplayState       string
 	PersistenceMode    string
 	PCIBusID           string
+
+	// MigDevices are the MIG devices partitioning the device. If MIG is
+	// enabled, the MIG devices are schedulable instead of the device itself
+	MigDevices []*MigDeviceInfo
 }
 
 // FingerprintData represets attributes of driver/devices
@@ -89,6 +93,7 @@ func (c *nvmlClient) GetFingerprintData() (*FingerprintData, error) {
 		9  - Memory, Cores Clock        # nvmlDeviceGetMaxClockInfo
 		10 - Display Mode               # nvmlDeviceGetDisplayMode
 		11 - Persistence Mode           # nvmlDeviceGetPersistenceMode
+		12 - MIG Devices                # nvidia-smi -L
 	*/
 
 	// Assumed that this method is called with receiver retrieved from
@@ -129,6 +134,19 @@ func (c *nvmlClient) GetFingerprintData() (*FingerprintData, error) {
 			PCIBusID:           deviceInfo.PCIBusID,
 		}
 	}
+
+	migDevices, err := c.driver.MigDevices()
+	if err != nil {
+		return nil, fmt.Errorf("nvidia MigDevices() error: %v\n", err)
+	}
+	for _, migDevice := range migDevices {
+		for _, device := range allNvidiaGPUResources {
+			if device.UUID == migDevice.ParentUUID {
+				device.MigDevices = append(device.MigDevices, migDevice)
+			}
+		}
+	}
+
 	return &FingerprintData{
 		Devices:       allNvidiaGPUResources,
 		DriverVersion: driverVersion,
@@ -16,6 +16,7 @@ type MockNVMLDriver struct {
 	driverVersion                            string
 	devices                                  []*DeviceInfo
 	deviceStatus                             []*DeviceStatus
+	migDevices                               []*MigDeviceInfo
 }
 
 func (m *MockNVMLDriver) Initialize() error {
@@ -60,6 +61,10 @@ func (m *MockNVMLDriver) DeviceInfoAndStatusByIndex(index uint) (*DeviceInfo, *D
 	return m.devices[index], m.deviceStatus[index], nil
 }
 
+func (m *MockNVMLDriver) MigDevices() ([]*MigDeviceInfo, error) {
+	return m.migDevices, nil
+}
+
 func TestGetFingerprintDataFromNVML(t *testing.T) {
 	for _, testCase := range []struct {
 		Name                string
@@ -191,6 +196,57 @@ func TestGetFingerprintDataFromNVML(t *testing.T) {
 				},
 			},
 		},
+		{
+			Name:          "successful outcome with MIG devices",
+			ExpectedError: false,
+			ExpectedResult: &FingerprintData{
+				DriverVersion: "driverVersion",
+				Devices: []*FingerprintDeviceData{
+					{
+						DeviceData: &DeviceData{
+							DeviceName: helper.StringToPtr("A100"),
+							UUID:       "UUID1",
+							MemoryMiB:  helper.Uint64ToPtr(40960),
+						},
+						PCIBusID:        "busId1",
+						DisplayState:    "Enabled",
+						PersistenceMode: "Enabled",
+						MigDevices: []*MigDeviceInfo{
+							{
+								UUID:       "MIG-UUID1",
+								ParentUUID: "UUID1",
+								Profile:    "3g.20gb",
+								MemoryMiB:  helper.Uint64ToPtr(20480),
+							},
+						},
+					},
+				},
+			},
+			DriverConfiguration: &MockNVMLDriver{
+				systemDriverCallSuccessful:      true,
+				deviceCountCallSuccessful:       true,
+				deviceInfoByIndexCallSuccessful: true,
+				driverVersion:                   "driverVersion",
+				devices: []*DeviceInfo{
+					{
+						UUID:            "UUID1",
+						Name:            helper.StringToPtr("A100"),
+						MemoryMiB:       helper.Uint64ToPtr(40960),
+						PCIBusID:        "busId1",
+						DisplayState:    "Enabled",
+						PersistenceMode: "Enabled",
+					},
+				},
+				migDevices: []*MigDeviceInfo{
+					{
+						UUID:       "MIG-UUID1",
+						ParentUUID: "UUID1",
+						Profile:    "3g.20gb",
+						MemoryMiB:  helper.Uint64ToPtr(20480),
+					},
+				},
+			},
+		},
 	} {
 		cli := nvmlClient{driver: testCase.DriverConfiguration}
 		fingerprintData, err := cli.GetFingerprintData()
@@ -31,3 +31,8 @@ func (n *nvmlDriver) DeviceInfoByIndex(index uint) (*DeviceInfo, error) {
 func (n *nvmlDriver) DeviceInfoAndStatusByIndex(index uint) (*DeviceInfo, *DeviceStatus, error) {
 	return nil, nil, UnavailableLib
 }
+
+// MigDevices returns the MIG devices of all GPU devices in the system
+func (n *nvmlDriver) MigDevices() ([]*MigDeviceInfo, error) {
+	return nil, UnavailableLib
+}
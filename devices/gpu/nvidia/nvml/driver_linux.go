@@ -1,6 +1,9 @@
 package nvml
 
 import (
+	"fmt"
+	"os/exec"
+
 	"github.com/NVIDIA/gpu-monitoring-tools/bindings/go/nvml"
 )
 
@@ -83,3 +86,19 @@ func (n *nvmlDriver) DeviceInfoAndStatusByIndex(index uint) (*DeviceInfo, *Devic
 			BAR1UsedMiB:        status.PCI.BAR1Used,
 		}, nil
 }
+
+// MigDevices returns the MIG devices of all GPU devices in the system. The
+// NVML bindings do not expose MIG devices so they are listed using
+// nvidia-smi. If nvidia-smi is not installed no MIG devices are returned.
+func (n *nvmlDriver) MigDevices() ([]*MigDeviceInfo, error) {
+	path, err := exec.LookPath(nvidiaSMI)
+	if err != nil {
+		return nil, nil
+	}
+
+	out, err := exec.Command(path, "-L").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list devices with %s: %v", nvidiaSMI, err)
+	}
+	return parseMigDevices(string(out)), nil
+}
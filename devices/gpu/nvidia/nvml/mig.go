@@ -0,0 +1,74 @@
+package nvml
+
+import (
+	"bufio"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/nomad/helper"
+)
+
+const (
+	// nvidiaSMI is the binary used to list MIG devices
+	nvidiaSMI = "nvidia-smi"
+)
+
+var (
+	// gpuListRe matches a GPU device in the output of "nvidia-smi -L"
+	gpuListRe = regexp.MustCompile(`^GPU \d+: .*\(UUID: (GPU-[^)]+)\)`)
+
+	// migListRe matches a MIG device in the output of "nvidia-smi -L"
+	migListRe = regexp.MustCompile(`^\s+MIG (\S+)\s+Device\s+\d+: \(UUID: ([^)]+)\)`)
+
+	// migProfileMemoryRe matches the memory of a MIG profile such as
+	// "1g.5gb" or "1g.10gb+me"
+	migProfileMemoryRe = regexp.MustCompile(`^\d+g\.(\d+)gb`)
+)
+
+// parseMigDevices parses the MIG devices from the output of "nvidia-smi -L".
+// MIG devices are listed below the GPU device they partition:
+//
+//	GPU 0: A100-SXM4-40GB (UUID: GPU-5d5ba0d6-...)
+//	  MIG 3g.20gb Device 0: (UUID: MIG-c6d4f1ef-...)
+//	  MIG 1g.5gb  Device 1: (UUID: MIG-7f9e6a2b-...)
+func parseMigDevices(out string) []*MigDeviceInfo {
+	var devices []*MigDeviceInfo
+	parent := ""
+
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if m := gpuListRe.FindStringSubmatch(line); m != nil {
+			parent = m[1]
+			continue
+		}
+
+		m := migListRe.FindStringSubmatch(line)
+		if m == nil || parent == "" {
+			continue
+		}
+
+		devices = append(devices, &MigDeviceInfo{
+			UUID:       m[2],
+			ParentUUID: parent,
+			Profile:    m[1],
+			MemoryMiB:  migProfileMemoryMiB(m[1]),
+		})
+	}
+	return devices
+}
+
+// migProfileMemoryMiB returns the memory of a MIG profile or nil if the
+// profile does not encode its memory
+func migProfileMemoryMiB(profile string) *uint64 {
+	m := migProfileMemoryRe.FindStringSubmatch(profile)
+	if m == nil {
+		return nil
+	}
+	gb, err := strconv.ParseUint(m[1], 10, 64)
+	if err != nil {
+		return nil
+	}
+	return helper.Uint64ToPtr(gb * 1024)
+}
@@ -0,0 +1,41 @@
+package nvml
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/helper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMigDevices(t *testing.T) {
+	out := `GPU 0: A100-SXM4-40GB (UUID: GPU-5d5ba0d6-d33d-2b2c-524d-9e3d8d2b8a0b)
+  MIG 3g.20gb     Device  0: (UUID: MIG-c6d4f1ef-42e4-5de3-91c7-45d71c87eb3f)
+  MIG 1g.5gb      Device  1: (UUID: MIG-GPU-5d5ba0d6-d33d-2b2c-524d-9e3d8d2b8a0b/7/0)
+GPU 1: Tesla V100-SXM2-16GB (UUID: GPU-8e1c4a7b-6f0e-4a5b-9b1e-2c3d4e5f6a7b)
+GPU 2: A100-SXM4-80GB (UUID: GPU-1a2b3c4d-5e6f-7a8b-9c0d-1e2f3a4b5c6d)
+  MIG 1g.10gb+me  Device  0: (UUID: MIG-0e28e1a9-e1c1-5d14-9a71-df8f9e4ae9d3)
+`
+
+	expected := []*MigDeviceInfo{
+		{
+			UUID:       "MIG-c6d4f1ef-42e4-5de3-91c7-45d71c87eb3f",
+			ParentUUID: "GPU-5d5ba0d6-d33d-2b2c-524d-9e3d8d2b8a0b",
+			Profile:    "3g.20gb",
+			MemoryMiB:  helper.Uint64ToPtr(20480),
+		},
+		{
+			UUID:       "MIG-GPU-5d5ba0d6-d33d-2b2c-524d-9e3d8d2b8a0b/7/0",
+			ParentUUID: "GPU-5d5ba0d6-d33d-2b2c-524d-9e3d8d2b8a0b",
+			Profile:    "1g.5gb",
+			MemoryMiB:  helper.Uint64ToPtr(5120),
+		},
+		{
+			UUID:       "MIG-0e28e1a9-e1c1-5d14-9a71-df8f9e4ae9d3",
+			ParentUUID: "GPU-1a2b3c4d-5e6f-7a8b-9c0d-1e2f3a4b5c6d",
+			Profile:    "1g.10gb+me",
+			MemoryMiB:  helper.Uint64ToPtr(10240),
+		},
+	}
+	require.Equal(t, expected, parseMigDevices(out))
+	require.Empty(t, parseMigDevices("No devices found."))
+}
@@ -19,6 +19,7 @@ type NvmlDriver interface {
 	DeviceCount() (uint, error)
 	DeviceInfoByIndex(uint) (*DeviceInfo, error)
 	DeviceInfoAndStatusByIndex(uint) (*DeviceInfo, *DeviceStatus, error)
+	MigDevices() ([]*MigDeviceInfo, error)
 }
 
 // DeviceInfo represents nvml device data
@@ -59,3 +60,21 @@ type DeviceStatus struct {
 	ECCErrorsL2Cache   *uint64
 	ECCErrorsDevice    *uint64
 }
+
+// MigDeviceInfo represents a MIG (Multi-Instance GPU) device, a partition of a
+// physical device that is isolated from the other partitions
+// this struct is returned by NvmlDriver MigDevices method
+type MigDeviceInfo struct {
+	// UUID is the identifier of the MIG device
+	UUID string
+
+	// ParentUUID is the UUID of the physical device that is partitioned
+	ParentUUID string
+
+	// Profile is the MIG profile of the device such as "1g.5gb"
+	Profile string
+
+	// MemoryMiB is the memory of the MIG device. It can be nil if the memory
+	// could not be determined from the profile
+	MemoryMiB *uint64
+}
@@ -0,0 +1,126 @@
+package docker
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	docker "github.com/fsouza/go-dockerclient"
+	"github.com/hashicorp/nomad/plugins/drivers"
+)
+
+// checkpointID is the name of the docker checkpoint created for a task inside
+// its checkpoint directory
+const checkpointID = "nomad"
+
+// CheckpointTask checkpoints the container of the given task into the task's
+// checkpoint directory using CRIU and stops the container. The docker daemon
+// must run with experimental features enabled and CRIU installed.
+func (d *Driver) CheckpointTask(taskID string) (bool, error) {
+	h, ok := d.tasks.Get(taskID)
+	if !ok {
+		return false, drivers.ErrTaskNotFound
+	}
+
+	dir := h.task.TaskDir().CheckpointDir
+	if err := os.RemoveAll(dir); err != nil {
+		return false, fmt.Errorf("failed to remove previous checkpoint: %v", err)
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return false, fmt.Errorf("failed to create checkpoint directory: %v", err)
+	}
+
+	body := map[string]interface{}{
+		"CheckpointID":  checkpointID,
+		"CheckpointDir": dir,
+		"Exit":          true,
+	}
+	path := fmt.Sprintf("/containers/%s/checkpoints", h.containerID)
+	if err := dockerAPIPost(h.client, path, nil, body); err != nil {
+		os.RemoveAll(dir)
+		return false, fmt.Errorf("failed to checkpoint container %s: %v", h.containerID, err)
+	}
+
+	h.logger.Info("checkpointed container", "checkpoint_dir", dir)
+	return true, nil
+}
+
+// restoreContainer starts the container from the checkpoint in the task's
+// checkpoint directory if the task was checkpointed on its previous node. It
+// returns whether the container was restored. The checkpoint is removed
+// afterwards so it is only ever restored once.
+func (d *Driver) restoreContainer(task *drivers.TaskConfig, client *docker.Client,
+	container *docker.Container) (bool, error) {
+
+	dir := task.TaskDir().CheckpointDir
+	if _, err := os.Stat(filepath.Join(dir, checkpointID)); err != nil {
+		return false, nil
+	}
+	defer os.RemoveAll(dir)
+
+	query := url.Values{}
+	query.Set("checkpoint", checkpointID)
+	query.Set("checkpoint-dir", dir)
+	path := fmt.Sprintf("/containers/%s/start", container.ID)
+	if err := dockerAPIPost(client, path, query, nil); err != nil {
+		return false, fmt.Errorf("failed to restore container %s from checkpoint: %v", container.ID, err)
+	}
+
+	d.logger.Info("restored container from checkpoint", "container_id", container.ID)
+	return true, nil
+}
+
+// dockerAPIPost sends a POST request to the docker daemon for endpoints that
+// are not covered by the docker client library, such as checkpoints.
+func dockerAPIPost(client *docker.Client, path string, query url.Values, body interface{}) error {
+	u, err := url.Parse(client.Endpoint())
+	if err != nil {
+		return fmt.Errorf("failed to parse docker endpoint: %v", err)
+	}
+
+	switch u.Scheme {
+	case "unix", "npipe":
+		// The HTTP client dials the socket or pipe itself, the host of the
+		// URL is not used.
+		u = &url.URL{Scheme: "http", Host: "docker"}
+	case "tcp":
+		u.Scheme = "http"
+		if client.TLSConfig != nil {
+			u.Scheme = "https"
+		}
+	}
+	u.Path = path
+	u.RawQuery = query.Encode()
+
+	var reqBody bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&reqBody).Encode(body); err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequest("POST", u.String(), &reqBody)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := client.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 400 {
+		msg, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("API error (%d): %s", resp.StatusCode, bytes.TrimSpace(msg))
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package docker
 
 import (
 	"fmt"
+	"runtime"
 	"strconv"
 	"strings"
 	"time"
@@ -98,6 +99,11 @@ func PluginLoader(opts map[string]string) (map[string]interface{}, error) {
 	if v, err := strconv.ParseBool(opts["docker.privileged.enabled"]); err == nil {
 		conf["allow_privileged"] = v
 	}
+
+	// image of the containers holding the network namespace of allocations
+	if v, ok := opts["docker.infra_image"]; ok {
+		conf["infra_image"] = v
+	}
 	return conf, nil
 }
 
@@ -149,6 +155,7 @@ var (
 	//		}
 	//		allow_privileged = false
 	//		allow_caps = ["CHOWN", "NET_RAW" ... ]
+	//		infra_image = "gcr.io/google_containers/pause-amd64:3.0"
 	//	}
 	configSpec = hclspec.NewObject(map[string]*hclspec.Spec{
 		"endpoint": hclspec.NewAttr("endpoint", "string", false),
@@ -199,6 +206,13 @@ var (
 			hclspec.NewAttr("allow_caps", "list(string)", false),
 			hclspec.NewLiteral(`["CHOWN","DAC_OVERRIDE","FSETID","FOWNER","MKNOD","NET_RAW","SETGID","SETUID","SETFCAP","SETPCAP","NET_BIND_SERVICE","SYS_CHROOT","KILL","AUDIT_WRITE"]`),
 		),
+
+		// image of the containers holding the network namespace of
+		// allocations in bridge mode
+		"infra_image": hclspec.NewDefault(
+			hclspec.NewAttr("infra_image", "string", false),
+			hclspec.NewLiteral(`"gcr.io/google_containers/pause-amd64:3.0"`),
+		),
 	})
 
 	// taskConfigSpec is the hcl specification for the driver config section of
@@ -292,6 +306,11 @@ var (
 		SendSignals: true,
 		Exec:        true,
 		FSIsolation: structs.FSIsolationImage,
+		Checkpoint:  runtime.GOOS == "linux",
+
+		// Containers join the network namespace of an infra container
+		NetworkIsolation:  runtime.GOOS == "linux",
+		MustCreateNetwork: runtime.GOOS == "linux",
 	}
 )
 
@@ -443,6 +462,7 @@ type DriverConfig struct {
 	Volumes         VolumeConfig `codec:"volumes"`
 	AllowPrivileged bool         `codec:"allow_privileged"`
 	AllowCaps       []string     `codec:"allow_caps"`
+	InfraImage      string       `codec:"infra_image"`
 }
 
 type AuthConfig struct {
@@ -43,6 +43,7 @@ var (
 	// The statistics the Docker driver exposes
 	DockerMeasuredMemStats = []string{"RSS", "Cache", "Swap", "Max Usage"}
 	DockerMeasuredCpuStats = []string{"Throttled Periods", "Throttled Time", "Percent"}
+	DockerMeasuredIOStats  = []string{"Read Bytes", "Write Bytes", "Read Ops", "Write Ops"}
 
 	// recoverableErrTimeouts returns a recoverable error if the error was due
 	// to timeouts
@@ -185,6 +186,14 @@ func (d *Driver) StartTask(cfg *drivers.TaskConfig) (*drivers.TaskHandle, *struc
 		return nil, nil, fmt.Errorf("Failed to create container configuration for image %q (%q): %v", driverConfig.Image, id, err)
 	}
 
+	// Restrict the task to the cores and memory chosen by the client, either
+	// its dedicated cores, its NUMA node or the cores shared by all tasks.
+	if cfg.Resources != nil && cfg.Resources.LinuxResources != nil {
+		lr := cfg.Resources.LinuxResources
+		containerCfg.HostConfig.CPUSetCPUs = lr.CpusetCPUs
+		containerCfg.HostConfig.CPUSetMEMs = lr.CpusetMems
+	}
+
 	startAttempts := 0
 CREATE:
 	container, err := d.createContainer(client, containerCfg, &driverConfig)
@@ -199,20 +208,29 @@ CREATE:
 	// since we don't create containers which are already present on the host
 	// and are running
 	if !container.State.Running {
-		// Start the container
-		if err := d.startContainer(container); err != nil {
-			d.logger.Error("failed to start container", "container_id", container.ID, "error", err)
-			client.RemoveContainer(docker.RemoveContainerOptions{
-				ID:    container.ID,
-				Force: true,
-			})
-			// Some sort of docker race bug, recreating the container usually works
-			if strings.Contains(err.Error(), "OCI runtime create failed: container with id exists:") && startAttempts < 5 {
-				startAttempts++
-				d.logger.Debug("reattempting container create/start sequence", "attempt", startAttempts, "container_id", id)
-				goto CREATE
+		// Restore the container if the task was migrated with a checkpoint,
+		// falling back to starting it fresh if that fails
+		restored, err := d.restoreContainer(cfg, client, container)
+		if err != nil {
+			d.logger.Warn("failed to restore container, starting it without checkpoint", "container_id", container.ID, "error", err)
+		}
+
+		if !restored {
+			// Start the container
+			if err := d.startContainer(container); err != nil {
+				d.logger.Error("failed to start container", "container_id", container.ID, "error", err)
+				client.RemoveContainer(docker.RemoveContainerOptions{
+					ID:    container.ID,
+					Force: true,
+				})
+				// Some sort of docker race bug, recreating the container usually works
+				if strings.Contains(err.Error(), "OCI runtime create failed: container with id exists:") && startAttempts < 5 {
+					startAttempts++
+					d.logger.Debug("reattempting container create/start sequence", "attempt", startAttempts, "container_id", id)
+					goto CREATE
+				}
+				return nil, nil, nstructs.WrapRecoverable(fmt.Sprintf("Failed to start container %s: %s", container.ID, err), err)
 			}
-			return nil, nil, nstructs.WrapRecoverable(fmt.Sprintf("Failed to start container %s: %s", container.ID, err), err)
 		}
 
 		// InspectContainer to get all of the container metadata as
@@ -257,13 +275,16 @@ CREATE:
 		return nil, nil, fmt.Errorf("failed to launch docker logger process %s: %v", container.ID, err)
 	}
 
-	// Detect container address
-	ip, autoUse := d.detectIP(container, &driverConfig)
-
-	net := &structs.DriverNetwork{
-		PortMap:       driverConfig.PortMap,
-		IP:            ip,
-		AutoAdvertise: autoUse,
+	// Detect container address. Containers in the network namespace of their
+	// allocation use the network of the allocation.
+	var net *structs.DriverNetwork
+	if cfg.NetworkIsolation == nil {
+		ip, autoUse := d.detectIP(container, &driverConfig)
+		net = &structs.DriverNetwork{
+			PortMap:       driverConfig.PortMap,
+			IP:            ip,
+			AutoAdvertise: autoUse,
+		}
 	}
 
 	// Return a driver handle
@@ -585,6 +606,26 @@ func (d *Driver) containerBinds(task *drivers.TaskConfig, driverConfig *TaskConf
 	return binds, nil
 }
 
+// setIOResources sets the block I/O weight and the per device bandwidth and
+// operation limits of the task on the container.
+func setIOResources(hostConfig *docker.HostConfig, lr *drivers.LinuxResources) {
+	hostConfig.BlkioWeight = lr.IOWeight
+	for _, l := range lr.IOLimits {
+		if l.ReadBps > 0 {
+			hostConfig.BlkioDeviceReadBps = append(hostConfig.BlkioDeviceReadBps, docker.BlockLimit{Path: l.Device, Rate: l.ReadBps})
+		}
+		if l.WriteBps > 0 {
+			hostConfig.BlkioDeviceWriteBps = append(hostConfig.BlkioDeviceWriteBps, docker.BlockLimit{Path: l.Device, Rate: l.WriteBps})
+		}
+		if l.ReadIOps > 0 {
+			hostConfig.BlkioDeviceReadIOps = append(hostConfig.BlkioDeviceReadIOps, docker.BlockLimit{Path: l.Device, Rate: l.ReadIOps})
+		}
+		if l.WriteIOps > 0 {
+			hostConfig.BlkioDeviceWriteIOps = append(hostConfig.BlkioDeviceWriteIOps, docker.BlockLimit{Path: l.Device, Rate: l.WriteIOps})
+		}
+	}
+}
+
 func (d *Driver) createContainerConfig(task *drivers.TaskConfig, driverConfig *TaskConfig,
 	imageID string) (docker.CreateContainerOptions, error) {
 
@@ -632,11 +673,19 @@ func (d *Driver) createContainerConfig(task *drivers.TaskConfig, driverConfig *T
 		PidsLimit: driverConfig.PidsLimit,
 	}
 
+	setIOResources(hostConfig, task.Resources.LinuxResources)
+
 	// Calculate CPU Quota
 	// cfs_quota_us is the time per core, so we must
 	// multiply the time by the number of cores available
 	// See https://access.redhat.com/documentation/en-us/red_hat_enterprise_linux/6/html/resource_management_guide/sec-cpu
-	if driverConfig.CPUHardLimit {
+	//
+	// A hard limit set in the task's resources has already been calculated
+	// by the client and takes precedence over the driver configuration.
+	if lr := task.Resources.LinuxResources; lr.CPUQuota > 0 {
+		hostConfig.CPUPeriod = lr.CPUPeriod
+		hostConfig.CPUQuota = lr.CPUQuota
+	} else if driverConfig.CPUHardLimit {
 		numCores := runtime.NumCPU()
 		if driverConfig.CPUCFSPeriod < 0 || driverConfig.CPUCFSPeriod > 1000000 {
 			return c, fmt.Errorf("invalid value for cpu_cfs_period")
@@ -663,7 +712,7 @@ func (d *Driver) createContainerConfig(task *drivers.TaskConfig, driverConfig *T
 
 	logger.Debug("configured resources", "memory", hostConfig.Memory,
 		"cpu_shares", hostConfig.CPUShares, "cpu_quota", hostConfig.CPUQuota,
-		"cpu_period", hostConfig.CPUPeriod)
+		"cpu_period", hostConfig.CPUPeriod, "blkio_weight", hostConfig.BlkioWeight)
 	logger.Debug("binding directories", "binds", hclog.Fmt("%#v", hostConfig.Binds))
 
 	//  set privileged mode
@@ -767,6 +816,11 @@ func (d *Driver) createContainerConfig(task *drivers.TaskConfig, driverConfig *T
 	// set DNS search domains and extra hosts
 	hostConfig.DNSSearch = driverConfig.DNSSearchDomains
 	hostConfig.DNSOptions = driverConfig.DNSOptions
+	for _, host := range driverConfig.ExtraHosts {
+		if err := validateExtraHost(host); err != nil {
+			return c, err
+		}
+	}
 	hostConfig.ExtraHosts = driverConfig.ExtraHosts
 
 	hostConfig.IpcMode = driverConfig.IPCMode
@@ -785,14 +839,31 @@ func (d *Driver) createContainerConfig(task *drivers.TaskConfig, driverConfig *T
 	hostConfig.ReadonlyRootfs = driverConfig.ReadonlyRootfs
 
 	hostConfig.NetworkMode = driverConfig.NetworkMode
-	if hostConfig.NetworkMode == "" {
+	if task.NetworkIsolation != nil {
+		// Join the network namespace of the infra container of the
+		// allocation, whose ports are mapped by the client
+		if driverConfig.NetworkMode != "" {
+			return c, fmt.Errorf("network_mode can't be set in tasks whose group has a network")
+		}
+		if len(driverConfig.PortMap) > 0 {
+			return c, fmt.Errorf("port_map can't be set in tasks whose group has a network, set the port \"to\" field instead")
+		}
+		id := task.NetworkIsolation.Labels[dockerNetSpecLabelKey]
+		if id == "" {
+			return c, fmt.Errorf("network namespace of task wasn't created by the docker driver")
+		}
+		hostConfig.NetworkMode = "container:" + id
+		logger.Debug("joining infra container network", "network_mode", hostConfig.NetworkMode)
+	} else if hostConfig.NetworkMode == "" {
 		// docker default
 		logger.Debug("networking mode not specified; using default", "network_mode", defaultNetworkMode)
 		hostConfig.NetworkMode = defaultNetworkMode
 	}
 
 	// Setup port mapping and exposed ports
-	if len(task.Resources.NomadResources.Networks) == 0 {
+	if task.NetworkIsolation != nil {
+		logger.Debug("ports are mapped to the network namespace of the allocation")
+	} else if len(task.Resources.NomadResources.Networks) == 0 {
 		logger.Debug("no network interfaces are available")
 		if len(driverConfig.PortMap) > 0 {
 			return c, fmt.Errorf("Trying to map ports but no network interface is available")
@@ -815,9 +886,10 @@ func (d *Driver) createContainerConfig(task *drivers.TaskConfig, driverConfig *T
 			hostPortStr := strconv.Itoa(port.Value)
 			containerPort := docker.Port(strconv.Itoa(containerPortInt))
 
-			publishedPorts[containerPort+"/tcp"] = getPortBinding(network.IP, hostPortStr)
-			publishedPorts[containerPort+"/udp"] = getPortBinding(network.IP, hostPortStr)
-			logger.Debug("allocated static port", "ip", network.IP, "port", port.Value)
+			hostIP := port.IP(network)
+			publishedPorts[containerPort+"/tcp"] = getPortBinding(hostIP, hostPortStr)
+			publishedPorts[containerPort+"/udp"] = getPortBinding(hostIP, hostPortStr)
+			logger.Debug("allocated static port", "ip", hostIP, "port", port.Value)
 
 			exposedPorts[containerPort+"/tcp"] = struct{}{}
 			exposedPorts[containerPort+"/udp"] = struct{}{}
@@ -836,9 +908,10 @@ func (d *Driver) createContainerConfig(task *drivers.TaskConfig, driverConfig *T
 			hostPortStr := strconv.Itoa(port.Value)
 			containerPort := docker.Port(strconv.Itoa(containerPortInt))
 
-			publishedPorts[containerPort+"/tcp"] = getPortBinding(network.IP, hostPortStr)
-			publishedPorts[containerPort+"/udp"] = getPortBinding(network.IP, hostPortStr)
-			logger.Debug("allocated mapped port", "ip", network.IP, "port", port.Value)
+			hostIP := port.IP(network)
+			publishedPorts[containerPort+"/tcp"] = getPortBinding(hostIP, hostPortStr)
+			publishedPorts[containerPort+"/udp"] = getPortBinding(hostIP, hostPortStr)
+			logger.Debug("allocated mapped port", "ip", hostIP, "port", port.Value)
 
 			exposedPorts[containerPort+"/tcp"] = struct{}{}
 			exposedPorts[containerPort+"/udp"] = struct{}{}
@@ -1044,6 +1117,7 @@ func (d *Driver) DestroyTask(taskID string, force bool) error {
 			"error", err)
 	}
 
+	d.tasks.Delete(taskID)
 	return nil
 }
 
@@ -1247,3 +1321,16 @@ func sliceMergeUlimit(ulimitsRaw map[string]string) ([]docker.ULimit, error) {
 	}
 	return ulimits, nil
 }
+
+// validateExtraHost validates an entry of extra_hosts, given as host:IP. The
+// IP may be an IPv6 address, so only the first colon separates the host.
+func validateExtraHost(extraHost string) error {
+	split := strings.SplitN(extraHost, ":", 2)
+	if len(split) != 2 || split[0] == "" {
+		return fmt.Errorf("Malformed extra_hosts entry %q, must be host:IP", extraHost)
+	}
+	if net.ParseIP(split[1]) == nil {
+		return fmt.Errorf("Malformed extra_hosts entry %q, %q is not a valid IP address", extraHost, split[1])
+	}
+	return nil
+}
@@ -1141,6 +1141,73 @@ func TestDockerDriver_CreateContainerConfig(t *testing.T) {
 	require.EqualValues(t, opt, c.HostConfig.StorageOpt)
 }
 
+func TestDockerDriver_CreateContainerConfig_IO(t *testing.T) {
+	t.Parallel()
+
+	task, cfg, _ := dockerTask(t)
+	task.Resources.LinuxResources.IOWeight = 500
+	task.Resources.LinuxResources.IOLimits = []*drivers.IOLimit{
+		{Device: "/dev/sda", ReadBps: 1048576, WriteIOps: 100},
+	}
+	require.NoError(t, task.EncodeConcreteDriverConfig(cfg))
+
+	dh := dockerDriverHarness(t, nil)
+	driver := dh.Impl().(*Driver)
+
+	c, err := driver.createContainerConfig(task, cfg, "org/repo:0.1")
+	require.NoError(t, err)
+
+	require.EqualValues(t, 500, c.HostConfig.BlkioWeight)
+	require.Equal(t, []docker.BlockLimit{{Path: "/dev/sda", Rate: 1048576}}, c.HostConfig.BlkioDeviceReadBps)
+	require.Equal(t, []docker.BlockLimit{{Path: "/dev/sda", Rate: 100}}, c.HostConfig.BlkioDeviceWriteIOps)
+	require.Empty(t, c.HostConfig.BlkioDeviceWriteBps)
+	require.Empty(t, c.HostConfig.BlkioDeviceReadIOps)
+}
+
+func TestDockerDriver_CreateContainerConfig_CPUHardLimit(t *testing.T) {
+	t.Parallel()
+
+	task, cfg, _ := dockerTask(t)
+	task.Resources.LinuxResources.CPUPeriod = 500000
+	task.Resources.LinuxResources.CPUQuota = 250000
+	require.NoError(t, task.EncodeConcreteDriverConfig(cfg))
+
+	dh := dockerDriverHarness(t, nil)
+	driver := dh.Impl().(*Driver)
+
+	c, err := driver.createContainerConfig(task, cfg, "org/repo:0.1")
+	require.NoError(t, err)
+
+	require.EqualValues(t, 500000, c.HostConfig.CPUPeriod)
+	require.EqualValues(t, 250000, c.HostConfig.CPUQuota)
+}
+
+func TestDockerDriver_CreateContainerConfig_ExtraHosts(t *testing.T) {
+	t.Parallel()
+
+	task, cfg, _ := dockerTask(t)
+	cfg.ExtraHosts = []string{"db.internal:10.0.0.5", "ipv6.internal:fd00::1"}
+	cfg.DNSSearchDomains = []string{"service.consul"}
+	cfg.DNSOptions = []string{"ndots:2"}
+	require.NoError(t, task.EncodeConcreteDriverConfig(cfg))
+
+	dh := dockerDriverHarness(t, nil)
+	driver := dh.Impl().(*Driver)
+
+	c, err := driver.createContainerConfig(task, cfg, "org/repo:0.1")
+	require.NoError(t, err)
+	require.Equal(t, cfg.ExtraHosts, c.HostConfig.ExtraHosts)
+	require.Equal(t, cfg.DNSSearchDomains, c.HostConfig.DNSSearch)
+	require.Equal(t, cfg.DNSOptions, c.HostConfig.DNSOptions)
+
+	for _, invalid := range []string{"db.internal", ":10.0.0.5", "db.internal:not-an-ip"} {
+		cfg.ExtraHosts = []string{invalid}
+		_, err := driver.createContainerConfig(task, cfg, "org/repo:0.1")
+		require.Error(t, err, invalid)
+		require.Contains(t, err.Error(), "extra_hosts")
+	}
+}
+
 func TestDockerDriver_Capabilities(t *testing.T) {
 	if !tu.IsTravis() {
 		t.Parallel()
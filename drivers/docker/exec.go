@@ -0,0 +1,98 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	docker "github.com/fsouza/go-dockerclient"
+	"github.com/hashicorp/nomad/plugins/drivers"
+)
+
+const (
+	// execTerminatingTimeout is the time to wait for docker to report the
+	// exit code of an exec session once its streams are closed.
+	execTerminatingTimeout = 3 * time.Second
+)
+
+// ExecTaskStreaming runs an interactive command within the container of the
+// task using a docker exec session.
+func (d *Driver) ExecTaskStreaming(ctx context.Context, taskID string, opts *drivers.ExecOptions) (*drivers.ExitResult, error) {
+	h, ok := d.tasks.Get(taskID)
+	if !ok {
+		return nil, drivers.ErrTaskNotFound
+	}
+
+	if len(opts.Command) == 0 {
+		return nil, fmt.Errorf("command is required but was empty")
+	}
+
+	createExecOpts := docker.CreateExecOptions{
+		AttachStdin:  true,
+		AttachStdout: true,
+		AttachStderr: true,
+		Tty:          opts.Tty,
+		Cmd:          opts.Command,
+		Container:    h.containerID,
+		Context:      ctx,
+	}
+	exec, err := h.client.CreateExec(createExecOpts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create exec object: %v", err)
+	}
+
+	if opts.Tty && opts.ResizeCh != nil {
+		go func() {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case size, ok := <-opts.ResizeCh:
+					if !ok {
+						return
+					}
+					if err := h.client.ResizeExecTTY(exec.ID, size.Height, size.Width); err != nil {
+						h.logger.Debug("failed to resize exec terminal", "error", err)
+					}
+				}
+			}
+		}()
+	}
+
+	// A raw terminal is used with a TTY as docker does not multiplex stdout
+	// and stderr in that case
+	startOpts := docker.StartExecOptions{
+		Detach:       false,
+		Tty:          opts.Tty,
+		RawTerminal:  opts.Tty,
+		InputStream:  opts.Stdin,
+		OutputStream: opts.Stdout,
+		ErrorStream:  opts.Stderr,
+		Context:      ctx,
+	}
+	if err := h.waitClient.StartExec(exec.ID, startOpts); err != nil {
+		return nil, fmt.Errorf("failed to start exec: %v", err)
+	}
+
+	// StartExec returns once the streams are closed but docker may take a
+	// moment to report the exit code
+	var res *docker.ExecInspect
+	deadline := time.Now().Add(execTerminatingTimeout)
+	for {
+		res, err = h.client.InspectExec(exec.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to inspect exec result: %v", err)
+		}
+		if !res.Running || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	if res.Running {
+		return nil, fmt.Errorf("failed to retrieve exec result")
+	}
+
+	opts.Stdout.Close()
+	opts.Stderr.Close()
+	return &drivers.ExitResult{ExitCode: res.ExitCode}, nil
+}
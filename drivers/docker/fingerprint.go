@@ -2,6 +2,7 @@ package docker
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"github.com/hashicorp/nomad/plugins/drivers"
@@ -64,6 +65,12 @@ func (d *Driver) buildFingerprint() *drivers.Fingerprint {
 		fp.Attributes["driver.docker.volumes.enabled"] = pstructs.NewBoolAttribute(true)
 	}
 
+	if info, err := client.Info(); err != nil {
+		d.logger.Warn("error fetching docker daemon info", "error", err)
+	} else if usernsRemapped(info.SecurityOptions) {
+		fp.Attributes["driver.docker.userns_remap"] = pstructs.NewBoolAttribute(true)
+	}
+
 	if nets, err := client.ListNetworks(); err != nil {
 		d.logger.Warn("error discovering bridge IP", "error", err)
 	} else {
@@ -90,3 +97,17 @@ func (d *Driver) buildFingerprint() *drivers.Fingerprint {
 
 	return fp
 }
+
+// usernsRemapped returns whether the security options reported by the docker
+// daemon show that it remaps containers into user namespaces. Older daemons
+// report the option as "userns", newer ones as "name=userns".
+func usernsRemapped(securityOptions []string) bool {
+	for _, opt := range securityOptions {
+		for _, field := range strings.Split(opt, ",") {
+			if field == "userns" || field == "name=userns" {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,17 @@
+package docker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDockerDriver_UsernsRemapped(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	require.False(usernsRemapped(nil))
+	require.False(usernsRemapped([]string{"name=seccomp,profile=default"}))
+	require.True(usernsRemapped([]string{"apparmor", "userns"}))
+	require.True(usernsRemapped([]string{"name=seccomp,profile=default", "name=userns"}))
+}
@@ -261,11 +261,32 @@ func (h *taskHandle) collectStats() {
 					s.CPUStats.CPUUsage.TotalUsage, s.PreCPUStats.CPUUsage.TotalUsage, numCores)
 				cs.TotalTicks = (cs.Percent / 100) * stats.TotalTicksAvailable() / float64(numCores)
 
+				is := &cstructs.IOStats{
+					Measured: DockerMeasuredIOStats,
+				}
+				for _, e := range s.BlkioStats.IOServiceBytesRecursive {
+					switch strings.ToLower(e.Op) {
+					case "read":
+						is.ReadBytes += e.Value
+					case "write":
+						is.WriteBytes += e.Value
+					}
+				}
+				for _, e := range s.BlkioStats.IOServicedRecursive {
+					switch strings.ToLower(e.Op) {
+					case "read":
+						is.ReadOps += e.Value
+					case "write":
+						is.WriteOps += e.Value
+					}
+				}
+
 				h.resourceUsageLock.Lock()
 				h.resourceUsage = &cstructs.TaskResourceUsage{
 					ResourceUsage: &cstructs.ResourceUsage{
 						MemoryStats: ms,
 						CpuStats:    cs,
+						IOStats:     is,
 					},
 					Timestamp: s.Read.UTC().UnixNano(),
 				}
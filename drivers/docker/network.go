@@ -0,0 +1,144 @@
+package docker
+
+import (
+	"fmt"
+
+	docker "github.com/fsouza/go-dockerclient"
+	"github.com/hashicorp/nomad/plugins/drivers"
+)
+
+const (
+	// infraContainerPrefix is the prefix of the name of the containers
+	// holding the network namespace of allocations
+	infraContainerPrefix = "nomad_init_"
+
+	// dockerNetSpecLabelKey is the label of network isolation specs set to
+	// the ID of the container holding the network namespace
+	dockerNetSpecLabelKey = "docker_sandbox_container_id"
+)
+
+// CreateNetwork creates the network namespace of an allocation by starting an
+// infra container the tasks of the allocation join. Docker containers can't
+// join a network namespace created by the client, so the client adds the
+// namespace of the infra container to the network of the allocation instead.
+func (d *Driver) CreateNetwork(allocID string) (*drivers.NetworkIsolationSpec, bool, error) {
+	client, _, err := d.dockerClients()
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to connect to docker daemon: %s", err)
+	}
+
+	name := infraContainerPrefix + allocID
+
+	// The infra container of a restored allocation is already running
+	container, err := client.InspectContainer(name)
+	if err != nil {
+		if _, ok := err.(*docker.NoSuchContainer); !ok {
+			return nil, false, fmt.Errorf("failed to inspect infra container: %v", err)
+		}
+		container = nil
+	}
+	if container != nil {
+		if container.State.Running {
+			return infraNetworkSpec(container), false, nil
+		}
+
+		// A stopped infra container doesn't hold the namespace anymore
+		if err := client.RemoveContainer(docker.RemoveContainerOptions{ID: container.ID, Force: true}); err != nil {
+			return nil, false, fmt.Errorf("failed to remove stopped infra container: %v", err)
+		}
+	}
+
+	if err := d.pullInfraImage(client, allocID); err != nil {
+		return nil, false, err
+	}
+
+	container, err = client.CreateContainer(docker.CreateContainerOptions{
+		Name: name,
+		Config: &docker.Config{
+			Image: d.config.InfraImage,
+			Labels: map[string]string{
+				"com.hashicorp.nomad.alloc_id": allocID,
+			},
+		},
+		HostConfig: &docker.HostConfig{
+			// The network of the namespace is configured by the client
+			NetworkMode: "none",
+		},
+	})
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to create infra container: %v", err)
+	}
+
+	if err := client.StartContainer(container.ID, nil); err != nil {
+		client.RemoveContainer(docker.RemoveContainerOptions{ID: container.ID, Force: true})
+		return nil, false, fmt.Errorf("failed to start infra container: %v", err)
+	}
+
+	// The pid of the container is only known once started
+	id := container.ID
+	container, err = client.InspectContainer(id)
+	if err != nil {
+		client.RemoveContainer(docker.RemoveContainerOptions{ID: id, Force: true})
+		return nil, false, fmt.Errorf("failed to inspect infra container: %v", err)
+	}
+
+	d.logger.Debug("started infra container", "alloc_id", allocID, "container_id", id)
+	return infraNetworkSpec(container), true, nil
+}
+
+// DestroyNetwork removes the infra container holding the network namespace
+// of an allocation. The container is looked up by name if the spec is
+// unknown. Removing a missing container is not an error.
+func (d *Driver) DestroyNetwork(allocID string, spec *drivers.NetworkIsolationSpec) error {
+	client, _, err := d.dockerClients()
+	if err != nil {
+		return fmt.Errorf("failed to connect to docker daemon: %s", err)
+	}
+
+	id := infraContainerPrefix + allocID
+	if spec != nil && spec.Labels[dockerNetSpecLabelKey] != "" {
+		id = spec.Labels[dockerNetSpecLabelKey]
+	}
+
+	err = client.RemoveContainer(docker.RemoveContainerOptions{ID: id, Force: true})
+	if _, ok := err.(*docker.NoSuchContainer); err != nil && !ok {
+		return fmt.Errorf("failed to remove infra container: %v", err)
+	}
+	return nil
+}
+
+// pullInfraImage pulls the image of infra containers if it isn't present.
+func (d *Driver) pullInfraImage(client *docker.Client, allocID string) error {
+	image := d.config.InfraImage
+	if dockerImage, _ := client.InspectImage(image); dockerImage != nil {
+		return nil
+	}
+
+	repo, _ := parseDockerImage(image)
+	authOptions, err := firstValidAuth(repo, []authBackend{
+		authFromDockerConfig(d.config.Auth.Config),
+		authFromHelper(d.config.Auth.Helper),
+	})
+	if err != nil {
+		d.logger.Debug("failed to find docker auth for infra image", "image", image, "error", err)
+	}
+
+	emitFn := func(msg string, annotations map[string]string) {
+		d.logger.Debug(msg, "image", image)
+	}
+	if _, err := d.coordinator.PullImage(image, authOptions, infraContainerPrefix+allocID, emitFn); err != nil {
+		return fmt.Errorf("failed to pull infra image %q: %v", image, err)
+	}
+	return nil
+}
+
+// infraNetworkSpec returns the network namespace of a running infra
+// container.
+func infraNetworkSpec(c *docker.Container) *drivers.NetworkIsolationSpec {
+	return &drivers.NetworkIsolationSpec{
+		Path: fmt.Sprintf("/proc/%d/ns/net", c.State.Pid),
+		Labels: map[string]string{
+			dockerNetSpecLabelKey: c.ID,
+		},
+	}
+}
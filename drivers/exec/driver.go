@@ -31,6 +31,10 @@ const (
 
 	// fingerprintPeriod is the interval at which the driver will send fingerprint responses
 	fingerprintPeriod = 30 * time.Second
+
+	// checkpointInventory is the file CRIU writes into every checkpoint, its
+	// presence marks a complete checkpoint
+	checkpointInventory = "inventory.img"
 )
 
 var (
@@ -57,21 +61,28 @@ var (
 	}
 
 	// configSpec is the hcl specification returned by the ConfigSchema RPC
-	configSpec = hclspec.NewObject(map[string]*hclspec.Spec{})
+	configSpec = hclspec.NewObject(map[string]*hclspec.Spec{
+		"uid_map": hclspec.NewAttr("uid_map", "list(string)", false),
+		"gid_map": hclspec.NewAttr("gid_map", "list(string)", false),
+	})
 
 	// taskConfigSpec is the hcl specification for the driver config section of
 	// a task within a job. It is returned in the TaskConfigSchema RPC
 	taskConfigSpec = hclspec.NewObject(map[string]*hclspec.Spec{
 		"command": hclspec.NewAttr("command", "string", true),
 		"args":    hclspec.NewAttr("args", "list(string)", false),
+		"uid_map": hclspec.NewAttr("uid_map", "list(string)", false),
+		"gid_map": hclspec.NewAttr("gid_map", "list(string)", false),
 	})
 
 	// capabilities is returned by the Capabilities RPC and indicates what
 	// optional features this driver supports
 	capabilities = &drivers.Capabilities{
-		SendSignals: true,
-		Exec:        true,
-		FSIsolation: cstructs.FSIsolationChroot,
+		SendSignals:      true,
+		Exec:             true,
+		FSIsolation:      cstructs.FSIsolationChroot,
+		Checkpoint:       true,
+		NetworkIsolation: true,
 	}
 )
 
@@ -85,6 +96,11 @@ type Driver struct {
 	// nomadConfig is the client config from nomad
 	nomadConfig *base.ClientDriverConfig
 
+	// uidMap and gidMap are the ID maps of the user namespace tasks run in
+	// unless they set their own
+	uidMap []executor.IDMap
+	gidMap []executor.IDMap
+
 	// tasks is the in memory datastore mapping taskIDs to driverHandles
 	tasks *taskStore
 
@@ -100,10 +116,21 @@ type Driver struct {
 	logger hclog.Logger
 }
 
+// Config is the driver configuration set by the SetConfig RPC call
+type Config struct {
+	// UIDMap and GIDMap remap the user and group IDs of tasks into a user
+	// namespace, so root inside a task is an unprivileged user on the host.
+	// Each map is formatted as "<container_id>:<host_id>:<size>".
+	UIDMap []string `codec:"uid_map"`
+	GIDMap []string `codec:"gid_map"`
+}
+
 // TaskConfig is the driver configuration of a task within a job
 type TaskConfig struct {
 	Command string   `codec:"command"`
 	Args    []string `codec:"args"`
+	UIDMap  []string `codec:"uid_map"`
+	GIDMap  []string `codec:"gid_map"`
 }
 
 // TaskState is the state which is encoded in the handle returned in
@@ -137,7 +164,18 @@ func (d *Driver) ConfigSchema() (*hclspec.Spec, error) {
 	return configSpec, nil
 }
 
-func (d *Driver) SetConfig(_ []byte, cfg *base.ClientAgentConfig) error {
+func (d *Driver) SetConfig(data []byte, cfg *base.ClientAgentConfig) error {
+	var config Config
+	if err := base.MsgPackDecode(data, &config); err != nil {
+		return err
+	}
+
+	uidMap, gidMap, err := parseUsernsMaps(config.UIDMap, config.GIDMap)
+	if err != nil {
+		return err
+	}
+	d.uidMap, d.gidMap = uidMap, gidMap
+
 	if cfg != nil {
 		d.nomadConfig = cfg.Driver
 	}
@@ -281,6 +319,11 @@ func (d *Driver) StartTask(cfg *drivers.TaskConfig) (*drivers.TaskHandle, *cstru
 		return nil, nil, fmt.Errorf("failed to decode driver config: %v", err)
 	}
 
+	uidMap, gidMap, err := d.usernsMaps(&driverConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	d.logger.Info("starting task", "driver_cfg", hclog.Fmt("%+v", driverConfig))
 	handle := drivers.NewTaskHandle(pluginName)
 	handle.Config = cfg
@@ -308,9 +351,26 @@ func (d *Driver) StartTask(cfg *drivers.TaskConfig) (*drivers.TaskHandle, *cstru
 		TaskDir:        cfg.TaskDir().Dir,
 		StdoutPath:     cfg.StdoutPath,
 		StderrPath:     cfg.StderrPath,
+		UIDMap:         uidMap,
+		GIDMap:         gidMap,
+	}
+	if cfg.NetworkIsolation != nil {
+		execCmd.NetworkNamespace = cfg.NetworkIsolation.Path
+	}
+
+	// Restore the task from a checkpoint taken on its previous node
+	checkpointDir := cfg.TaskDir().CheckpointDir
+	if _, err := os.Stat(filepath.Join(checkpointDir, checkpointInventory)); err == nil {
+		execCmd.RestoreDir = checkpointDir
+		defer os.RemoveAll(checkpointDir)
 	}
 
 	ps, err := exec.Launch(execCmd)
+	if err != nil && execCmd.RestoreDir != "" {
+		d.logger.Warn("failed to restore task from checkpoint, starting it instead", "error", err)
+		execCmd.RestoreDir = ""
+		ps, err = exec.Launch(execCmd)
+	}
 	if err != nil {
 		pluginClient.Kill()
 		return nil, nil, fmt.Errorf("failed to launch command with executor: %v", err)
@@ -350,12 +410,28 @@ func toExecResources(resources *drivers.Resources) *executor.Resources {
 		return nil
 	}
 
-	return &executor.Resources{
+	res := &executor.Resources{
 		CPU:      resources.NomadResources.CPU,
 		MemoryMB: resources.NomadResources.MemoryMB,
 		DiskMB:   resources.NomadResources.DiskMB,
 	}
-
+	if resources.LinuxResources != nil {
+		res.IOWeight = int(resources.LinuxResources.IOWeight)
+		res.CPUPeriod = resources.LinuxResources.CPUPeriod
+		res.CPUQuota = resources.LinuxResources.CPUQuota
+		res.CpusetCPUs = resources.LinuxResources.CpusetCPUs
+		res.CpusetMems = resources.LinuxResources.CpusetMems
+		for _, l := range resources.LinuxResources.IOLimits {
+			res.IOLimits = append(res.IOLimits, &executor.IOLimit{
+				Device:    l.Device,
+				ReadBps:   l.ReadBps,
+				WriteBps:  l.WriteBps,
+				ReadIOps:  l.ReadIOps,
+				WriteIOps: l.WriteIOps,
+			})
+		}
+	}
+	return res
 }
 
 func (d *Driver) WaitTask(ctx context.Context, taskID string) (<-chan *drivers.ExitResult, error) {
@@ -470,6 +546,29 @@ func (d *Driver) SignalTask(taskID string, signal string) error {
 	return handle.exec.Signal(sig)
 }
 
+// CheckpointTask dumps the task into its checkpoint directory using CRIU and
+// stops it, so it can be restored when the task is started on another node.
+func (d *Driver) CheckpointTask(taskID string) (bool, error) {
+	handle, ok := d.tasks.Get(taskID)
+	if !ok {
+		return false, drivers.ErrTaskNotFound
+	}
+
+	dir := handle.taskConfig.TaskDir().CheckpointDir
+	if err := os.RemoveAll(dir); err != nil {
+		return false, fmt.Errorf("failed to remove previous checkpoint: %v", err)
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return false, fmt.Errorf("failed to create checkpoint directory: %v", err)
+	}
+
+	if err := handle.exec.Checkpoint(dir); err != nil {
+		os.RemoveAll(dir)
+		return false, fmt.Errorf("failed to checkpoint task: %v", err)
+	}
+	return true, nil
+}
+
 func (d *Driver) ExecTask(taskID string, cmd []string, timeout time.Duration) (*drivers.ExecTaskResult, error) {
 	if len(cmd) == 0 {
 		return nil, fmt.Errorf("error cmd must have atleast one value")
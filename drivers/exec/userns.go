@@ -0,0 +1,105 @@
+package exec
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/nomad/drivers/shared/executor"
+)
+
+// parseIDMaps parses user or group ID maps given in the format of
+// /proc/<pid>/uid_map, "<container_id>:<host_id>:<size>".
+func parseIDMaps(raw []string) ([]executor.IDMap, error) {
+	maps := make([]executor.IDMap, 0, len(raw))
+	for _, r := range raw {
+		parts := strings.Split(r, ":")
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("invalid ID map %q: expected <container_id>:<host_id>:<size>", r)
+		}
+
+		var ids [3]int
+		for i, p := range parts {
+			id, err := strconv.ParseUint(strings.TrimSpace(p), 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("invalid ID map %q: %v", r, err)
+			}
+			ids[i] = int(id)
+		}
+		if ids[2] == 0 {
+			return nil, fmt.Errorf("invalid ID map %q: size must be greater than zero", r)
+		}
+
+		maps = append(maps, executor.IDMap{
+			ContainerID: ids[0],
+			HostID:      ids[1],
+			Size:        ids[2],
+		})
+	}
+	return maps, nil
+}
+
+// parseUsernsMaps parses a pair of user and group ID maps, which must either
+// both be set or both be empty.
+func parseUsernsMaps(uidMap, gidMap []string) ([]executor.IDMap, []executor.IDMap, error) {
+	if (len(uidMap) == 0) != (len(gidMap) == 0) {
+		return nil, nil, fmt.Errorf("uid_map and gid_map must be set together")
+	}
+
+	uids, err := parseIDMaps(uidMap)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse uid_map: %v", err)
+	}
+	gids, err := parseIDMaps(gidMap)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse gid_map: %v", err)
+	}
+	return uids, gids, nil
+}
+
+// idMapsWithin returns an error if any host ID mapped by maps is not also
+// mapped by one of the allowed maps.
+func idMapsWithin(maps, allowed []executor.IDMap) error {
+	for _, m := range maps {
+		ok := false
+		for _, a := range allowed {
+			if m.HostID >= a.HostID && m.HostID+m.Size <= a.HostID+a.Size {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return fmt.Errorf("host IDs %d-%d are outside of the ranges allowed by the client",
+				m.HostID, m.HostID+m.Size-1)
+		}
+	}
+	return nil
+}
+
+// usernsMaps returns the user and group ID maps to run the task with. Tasks
+// use the maps configured for the client unless they set their own, which
+// may only map to host IDs the client maps are allowed to use. Tasks may not
+// set maps if the client configures none, as they could then map to any host
+// ID, including root.
+func (d *Driver) usernsMaps(driverConfig *TaskConfig) ([]executor.IDMap, []executor.IDMap, error) {
+	if len(driverConfig.UIDMap) == 0 && len(driverConfig.GIDMap) == 0 {
+		return d.uidMap, d.gidMap, nil
+	}
+
+	if len(d.uidMap) == 0 {
+		return nil, nil, fmt.Errorf("uid_map and gid_map may only be set when the client configures them")
+	}
+
+	uids, gids, err := parseUsernsMaps(driverConfig.UIDMap, driverConfig.GIDMap)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := idMapsWithin(uids, d.uidMap); err != nil {
+		return nil, nil, fmt.Errorf("invalid uid_map: %v", err)
+	}
+	if err := idMapsWithin(gids, d.gidMap); err != nil {
+		return nil, nil, fmt.Errorf("invalid gid_map: %v", err)
+	}
+	return uids, gids, nil
+}
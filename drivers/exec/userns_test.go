@@ -0,0 +1,79 @@
+package exec
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/drivers/shared/executor"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecDriver_ParseIDMaps(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	maps, err := parseIDMaps([]string{"0:100000:65536", "65536: 1000 :1"})
+	require.NoError(err)
+	require.Equal([]executor.IDMap{
+		{ContainerID: 0, HostID: 100000, Size: 65536},
+		{ContainerID: 65536, HostID: 1000, Size: 1},
+	}, maps)
+
+	for _, invalid := range []string{"0:100000", "0:a:1", "0:1:0", "-1:1:1"} {
+		_, err := parseIDMaps([]string{invalid})
+		require.Error(err, invalid)
+	}
+
+	_, _, err = parseUsernsMaps([]string{"0:100000:65536"}, nil)
+	require.Error(err)
+}
+
+func TestExecDriver_UsernsMaps(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	d := NewExecDriver(testlog.HCLogger(t)).(*Driver)
+
+	// Without client maps tasks may not set maps
+	uids, gids, err := d.usernsMaps(&TaskConfig{})
+	require.NoError(err)
+	require.Empty(uids)
+	require.Empty(gids)
+
+	_, _, err = d.usernsMaps(&TaskConfig{
+		UIDMap: []string{"65534:0:1"},
+		GIDMap: []string{"65534:0:1"},
+	})
+	require.Error(err)
+	require.Contains(err.Error(), "client")
+
+	// With client maps tasks default to them and may only use their host IDs
+	d.uidMap, d.gidMap, err = parseUsernsMaps([]string{"0:100000:65536"}, []string{"0:100000:65536"})
+	require.NoError(err)
+
+	uids, gids, err = d.usernsMaps(&TaskConfig{})
+	require.NoError(err)
+	require.Equal(d.uidMap, uids)
+	require.Equal(d.gidMap, gids)
+
+	uids, _, err = d.usernsMaps(&TaskConfig{
+		UIDMap: []string{"0:110000:1000"},
+		GIDMap: []string{"0:110000:1000"},
+	})
+	require.NoError(err)
+	require.Equal([]executor.IDMap{{ContainerID: 0, HostID: 110000, Size: 1000}}, uids)
+
+	_, _, err = d.usernsMaps(&TaskConfig{
+		UIDMap: []string{"0:0:1"},
+		GIDMap: []string{"0:110000:1000"},
+	})
+	require.Error(err)
+	require.Contains(err.Error(), "uid_map")
+
+	_, _, err = d.usernsMaps(&TaskConfig{
+		UIDMap: []string{"0:100000:65536"},
+		GIDMap: []string{"0:100001:65536"},
+	})
+	require.Error(err)
+	require.Contains(err.Error(), "gid_map")
+}
@@ -7,6 +7,9 @@ import (
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"strconv"
+	"strings"
+	"sync"
 	"syscall"
 	"time"
 
@@ -37,6 +40,10 @@ const (
 	// The key populated in Node Attributes to indicate presence of the Java driver
 	driverAttr        = "driver.java"
 	driverVersionAttr = "driver.java.version"
+
+	// driverVersionsAttr lists the major versions of all Java installations
+	// found on the host
+	driverVersionsAttr = "driver.java.versions"
 )
 
 var (
@@ -63,7 +70,12 @@ var (
 	}
 
 	// configSpec is the hcl specification returned by the ConfigSchema RPC
-	configSpec = hclspec.NewObject(map[string]*hclspec.Spec{})
+	configSpec = hclspec.NewObject(map[string]*hclspec.Spec{
+		"jdk_search_paths": hclspec.NewDefault(
+			hclspec.NewAttr("jdk_search_paths", "list(string)", false),
+			hclspec.NewLiteral(`["/usr/lib/jvm"]`),
+		),
+	})
 
 	// taskConfigSpec is the hcl specification for the driver config section of
 	// a taskConfig within a job. It is returned in the TaskConfigSchema RPC
@@ -76,6 +88,7 @@ var (
 		"jar_path":    hclspec.NewAttr("jar_path", "string", false),
 		"jvm_options": hclspec.NewAttr("jvm_options", "list(string)", false),
 		"args":        hclspec.NewAttr("args", "list(string)", false),
+		"jdk_version": hclspec.NewAttr("jdk_version", "string", false),
 	})
 
 	// capabilities is returned by the Capabilities RPC and indicates what
@@ -95,6 +108,14 @@ func init() {
 	}
 }
 
+// Config is the driver configuration set by the SetConfig RPC call
+type Config struct {
+	// JDKSearchPaths is the list of paths searched for Java installations in
+	// addition to the java executable found in the $PATH. Each path may be a
+	// JAVA_HOME or a directory containing JAVA_HOMEs.
+	JDKSearchPaths []string `codec:"jdk_search_paths"`
+}
+
 // TaskConfig is the driver configuration of a taskConfig within a job
 type TaskConfig struct {
 	Class     string   `codec:"class"`
@@ -102,6 +123,10 @@ type TaskConfig struct {
 	JarPath   string   `codec:"jar_path"`
 	JvmOpts   []string `codec:"jvm_options"`
 	Args      []string `codec:"args"` // extra arguments to java executable
+
+	// JDKVersion is the major version of the JDK to launch the task with.
+	// If empty the java executable in the $PATH is used.
+	JDKVersion string `codec:"jdk_version"`
 }
 
 // TaskState is the state which is encoded in the handle returned in
@@ -123,6 +148,17 @@ type Driver struct {
 	// tasks is the in memory datastore mapping taskIDs to taskHandle
 	tasks *taskStore
 
+	// config is the driver configuration set by the SetConfig RPC
+	config     *Config
+	configLock sync.RWMutex
+
+	// jdks are the Java installations found during the last fingerprint, and
+	// jdkCache the installations found so far by home, so that their version
+	// is only read again when their java binary changes
+	jdks     []*jdk
+	jdkCache map[string]*jdk
+	jdksLock sync.RWMutex
+
 	// ctx is the context for the driver. It is passed to other subsystems to
 	// coordinate shutdown
 	ctx context.Context
@@ -144,6 +180,8 @@ func NewDriver(logger hclog.Logger) drivers.DriverPlugin {
 	return &Driver{
 		eventer:        eventer.NewEventer(ctx, logger),
 		tasks:          newTaskStore(),
+		config:         &Config{},
+		jdkCache:       make(map[string]*jdk),
 		ctx:            ctx,
 		signalShutdown: cancel,
 		logger:         logger,
@@ -158,7 +196,17 @@ func (d *Driver) ConfigSchema() (*hclspec.Spec, error) {
 	return configSpec, nil
 }
 
-func (d *Driver) SetConfig(_ []byte, cfg *base.ClientAgentConfig) error {
+func (d *Driver) SetConfig(data []byte, cfg *base.ClientAgentConfig) error {
+	var config Config
+	if len(data) != 0 {
+		if err := base.MsgPackDecode(data, &config); err != nil {
+			return err
+		}
+	}
+
+	d.configLock.Lock()
+	d.config = &config
+	d.configLock.Unlock()
 	if cfg != nil {
 		d.nomadConfig = cfg.Driver
 	}
@@ -224,8 +272,10 @@ func (d *Driver) buildFingerprint() *drivers.Fingerprint {
 		}
 	}
 
+	jdks := d.refreshJDKs()
+
 	version, runtime, vm, err := javaVersionInfo()
-	if err != nil {
+	if err != nil && len(jdks) == 0 {
 		// return no error, as it isn't an error to not find java, it just means we
 		// can't use it.
 		fp.Health = drivers.HealthStateUndetected
@@ -234,13 +284,70 @@ func (d *Driver) buildFingerprint() *drivers.Fingerprint {
 	}
 
 	fp.Attributes[driverAttr] = pstructs.NewBoolAttribute(true)
-	fp.Attributes[driverVersionAttr] = pstructs.NewStringAttribute(version)
-	fp.Attributes["driver.java.runtime"] = pstructs.NewStringAttribute(runtime)
-	fp.Attributes["driver.java.vm"] = pstructs.NewStringAttribute(vm)
+	if err == nil {
+		fp.Attributes[driverVersionAttr] = pstructs.NewStringAttribute(version)
+		fp.Attributes["driver.java.runtime"] = pstructs.NewStringAttribute(runtime)
+		fp.Attributes["driver.java.vm"] = pstructs.NewStringAttribute(vm)
+	}
+
+	if len(jdks) != 0 {
+		majors := make([]string, 0, len(jdks))
+		for _, j := range jdks {
+			major := strconv.Itoa(j.Major)
+			prefix := fmt.Sprintf("%s.jdk.%s", driverAttr, major)
+			if _, ok := fp.Attributes[prefix+".version"]; ok {
+				// Only the first installation of a major version is used
+				continue
+			}
+
+			majors = append(majors, major)
+			fp.Attributes[prefix+".version"] = pstructs.NewStringAttribute(j.Version)
+			fp.Attributes[prefix+".home"] = pstructs.NewStringAttribute(j.Home)
+		}
+		fp.Attributes[driverVersionsAttr] = pstructs.NewStringAttribute(strings.Join(majors, ","))
+	}
 
 	return fp
 }
 
+// refreshJDKs discovers the Java installations of the configured search
+// paths and returns them.
+func (d *Driver) refreshJDKs() []*jdk {
+	d.configLock.RLock()
+	paths := d.config.JDKSearchPaths
+	d.configLock.RUnlock()
+
+	d.jdksLock.Lock()
+	defer d.jdksLock.Unlock()
+	d.jdks = discoverJDKs(paths, d.jdkCache)
+	return d.jdks
+}
+
+// findJDK returns the Java installation matching the given major version.
+func (d *Driver) findJDK(version string) (*jdk, error) {
+	major, err := strconv.Atoi(version)
+	if err != nil {
+		return nil, fmt.Errorf("invalid jdk_version %q: must be a major version such as \"11\"", version)
+	}
+
+	d.jdksLock.RLock()
+	jdks := d.jdks
+	d.jdksLock.RUnlock()
+
+	// The task may be started before the first fingerprint completes
+	if jdks == nil {
+		jdks = d.refreshJDKs()
+	}
+
+	for _, j := range jdks {
+		if j.Major == major {
+			return j, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no JDK with major version %d found", major)
+}
+
 func (d *Driver) RecoverTask(handle *drivers.TaskHandle) error {
 	if handle == nil {
 		return fmt.Errorf("handle cannot be nil")
@@ -307,9 +414,21 @@ func (d *Driver) StartTask(cfg *drivers.TaskConfig) (*drivers.TaskHandle, *cstru
 		return nil, nil, fmt.Errorf("jar_path or class must be specified")
 	}
 
-	absPath, err := GetAbsolutePath("java")
-	if err != nil {
-		return nil, nil, fmt.Errorf("failed to find java binary: %s", err)
+	env := cfg.EnvList()
+	var absPath string
+	if driverConfig.JDKVersion != "" {
+		j, err := d.findJDK(driverConfig.JDKVersion)
+		if err != nil {
+			return nil, nil, err
+		}
+		absPath = j.Bin()
+		env = setEnv(env, "JAVA_HOME", j.Home)
+	} else {
+		var err error
+		absPath, err = GetAbsolutePath("java")
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to find java binary: %s", err)
+		}
 	}
 
 	args := javaCmdArgs(driverConfig)
@@ -333,7 +452,7 @@ func (d *Driver) StartTask(cfg *drivers.TaskConfig) (*drivers.TaskHandle, *cstru
 	execCmd := &executor.ExecCommand{
 		Cmd:            absPath,
 		Args:           args,
-		Env:            cfg.EnvList(),
+		Env:            env,
 		User:           cfg.User,
 		ResourceLimits: true,
 		Resources: &executor.Resources{
@@ -345,6 +464,10 @@ func (d *Driver) StartTask(cfg *drivers.TaskConfig) (*drivers.TaskHandle, *cstru
 		StdoutPath: cfg.StdoutPath,
 		StderrPath: cfg.StderrPath,
 	}
+	if lr := cfg.Resources.LinuxResources; lr != nil {
+		execCmd.Resources.CpusetCPUs = lr.CpusetCPUs
+		execCmd.Resources.CpusetMems = lr.CpusetMems
+	}
 
 	ps, err := exec.Launch(execCmd)
 	if err != nil {
@@ -3,17 +3,57 @@ package java
 import (
 	"bytes"
 	"fmt"
+	"io/ioutil"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"regexp"
+	"runtime"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 )
 
 var javaVersionCommand = []string{"java", "-version"}
 
+// jdk describes a Java installation discovered on the host
+type jdk struct {
+	// Home is the JAVA_HOME of the installation
+	Home string
+
+	// Major is the major Java version, ex: 8 for 1.8.0_191 or 11 for 11.0.1
+	Major int
+
+	Version string
+	Runtime string
+	VM      string
+
+	// binModTime is the modification time of the java executable when its
+	// version was read
+	binModTime time.Time
+}
+
+// Bin returns the path to the java executable of the installation
+func (j *jdk) Bin() string {
+	return filepath.Join(j.Home, "bin", javaBinName())
+}
+
+func javaBinName() string {
+	if runtime.GOOS == "windows" {
+		return "java.exe"
+	}
+	return "java"
+}
+
 func javaVersionInfo() (version, runtime, vm string, err error) {
+	return javaVersionInfoCmd(javaVersionCommand)
+}
+
+func javaVersionInfoCmd(command []string) (version, runtime, vm string, err error) {
 	var out bytes.Buffer
 
-	cmd := exec.Command(javaVersionCommand[0], javaVersionCommand[1:]...)
+	cmd := exec.Command(command[0], command[1:]...)
 	cmd.Stdout = &out
 	cmd.Stderr = &out
 	err = cmd.Run()
@@ -42,3 +82,122 @@ func parseJavaVersionOutput(infoString string) (version, runtime, vm string, err
 
 	return versionString, strings.TrimSpace(lines[1]), strings.TrimSpace(lines[2]), nil
 }
+
+// javaMajorVersion returns the major version of a Java version string. Legacy
+// versions use the 1.x scheme so 1.8.0_191 is major version 8.
+func javaMajorVersion(version string) (int, error) {
+	parts := strings.SplitN(version, ".", 3)
+	if parts[0] == "1" && len(parts) > 1 {
+		parts = parts[1:]
+	}
+
+	// Strip any suffix such as "-ea" or "+13"
+	major := strings.FieldsFunc(parts[0], func(r rune) bool {
+		return r < '0' || r > '9'
+	})
+	if len(major) == 0 {
+		return 0, fmt.Errorf("invalid java version %q", version)
+	}
+
+	return strconv.Atoi(major[0])
+}
+
+// discoverJDKs returns the Java installations found in the given search paths.
+// Each path may either be a JAVA_HOME or a directory containing JAVA_HOMEs,
+// such as /usr/lib/jvm. Installations reachable through multiple symlinked
+// paths are only returned once. The result is sorted by major version.
+//
+// The installations found are cached by home in the given cache, which may be
+// nil, so that java -version only runs again for the installations whose
+// executable changed. The installations no longer found are removed from it.
+func discoverJDKs(paths []string, cache map[string]*jdk) []*jdk {
+	var homes []string
+	for _, path := range paths {
+		if isJavaHome(path) {
+			homes = append(homes, path)
+			continue
+		}
+
+		entries, err := ioutil.ReadDir(path)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			home := filepath.Join(path, entry.Name())
+			if isJavaHome(home) {
+				homes = append(homes, home)
+			}
+		}
+	}
+
+	seen := make(map[string]struct{}, len(homes))
+	jdks := make([]*jdk, 0, len(homes))
+	for _, home := range homes {
+		resolved, err := filepath.EvalSymlinks(home)
+		if err != nil {
+			continue
+		}
+		if _, ok := seen[resolved]; ok {
+			continue
+		}
+		seen[resolved] = struct{}{}
+
+		j := &jdk{Home: resolved}
+		fi, err := os.Stat(j.Bin())
+		if err != nil {
+			continue
+		}
+		if cached, ok := cache[resolved]; ok && cached.binModTime.Equal(fi.ModTime()) {
+			jdks = append(jdks, cached)
+			continue
+		}
+
+		version, rt, vm, err := javaVersionInfoCmd([]string{j.Bin(), "-version"})
+		if err != nil {
+			continue
+		}
+		major, err := javaMajorVersion(version)
+		if err != nil {
+			continue
+		}
+
+		j.Major = major
+		j.Version = version
+		j.Runtime = rt
+		j.VM = vm
+		j.binModTime = fi.ModTime()
+		jdks = append(jdks, j)
+		if cache != nil {
+			cache[resolved] = j
+		}
+	}
+
+	for home := range cache {
+		if _, ok := seen[home]; !ok {
+			delete(cache, home)
+		}
+	}
+
+	sort.SliceStable(jdks, func(i, j int) bool {
+		return jdks[i].Major < jdks[j].Major
+	})
+	return jdks
+}
+
+func isJavaHome(path string) bool {
+	fi, err := os.Stat(filepath.Join(path, "bin", javaBinName()))
+	return err == nil && !fi.IsDir()
+}
+
+// setEnv returns env with the key set to the given value, replacing any
+// existing entry for the key.
+func setEnv(env []string, key, value string) []string {
+	prefix := key + "="
+	out := make([]string, 0, len(env)+1)
+	for _, e := range env {
+		if !strings.HasPrefix(e, prefix) {
+			out = append(out, e)
+		}
+	}
+	return append(out, prefix+value)
+}
@@ -2,8 +2,12 @@ package java
 
 import (
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"runtime"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -82,3 +86,94 @@ func TestDriver_javaVersionInfo(t *testing.T) {
 	require.Equal(t, "Java HotSpot(TM) 64-Bit Server VM (build 24.80-b11, mixed mode)", vm)
 
 }
+
+func TestDriver_javaMajorVersion(t *testing.T) {
+	cases := []struct {
+		version string
+		major   int
+	}{
+		{"1.6.0_36", 6},
+		{"1.8.0_191", 8},
+		{"9", 9},
+		{"11.0.1", 11},
+		{"12-ea", 12},
+		{"17+35", 17},
+	}
+
+	for _, c := range cases {
+		t.Run(c.version, func(t *testing.T) {
+			major, err := javaMajorVersion(c.version)
+			require.NoError(t, err)
+			require.Equal(t, c.major, major)
+		})
+	}
+
+	_, err := javaMajorVersion("unknown")
+	require.Error(t, err)
+}
+
+func TestDriver_discoverJDKs(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test requires bash to run")
+	}
+
+	dir, err := ioutil.TempDir("", "nomad_java_test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	fakeJDK := func(name, output string) string {
+		home := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Join(home, "bin"), 0755))
+		script := fmt.Sprintf("#!/bin/sh\nprintf '%%s\\n' '%s' >&2\n", output)
+		require.NoError(t, ioutil.WriteFile(filepath.Join(home, "bin", "java"), []byte(script), 0755))
+		return home
+	}
+
+	fakeJDK("java-11-openjdk", `openjdk version "11.0.1" 2018-10-16
+OpenJDK Runtime Environment 18.9 (build 11.0.1+13)
+OpenJDK 64-Bit Server VM 18.9 (build 11.0.1+13, mixed mode)`)
+	jdk7 := fakeJDK("java-7-oracle", oracleJDKOutput)
+	require.NoError(t, os.Symlink(jdk7, filepath.Join(dir, "default-java")))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "not-a-jdk"), 0755))
+
+	cache := make(map[string]*jdk)
+	jdks := discoverJDKs([]string{dir, filepath.Join(dir, "missing")}, cache)
+	require.Len(t, jdks, 2)
+	require.Len(t, cache, 2)
+
+	require.Equal(t, 7, jdks[0].Major)
+	require.Equal(t, "1.7.0_80", jdks[0].Version)
+	require.Equal(t, jdk7, jdks[0].Home)
+
+	require.Equal(t, 11, jdks[1].Major)
+	require.Equal(t, "11.0.1", jdks[1].Version)
+	require.Equal(t, filepath.Join(jdks[1].Home, "bin", "java"), jdks[1].Bin())
+
+	// The versions are cached until the executable changes
+	jdk11 := jdks[1]
+	require.NoError(t, ioutil.WriteFile(jdk11.Bin(), []byte("#!/bin/sh\nexit 1\n"), 0755))
+	modTime := jdk11.binModTime
+	require.NoError(t, os.Chtimes(jdk11.Bin(), modTime, modTime))
+	jdks = discoverJDKs([]string{dir}, cache)
+	require.Len(t, jdks, 2)
+	require.True(t, jdks[1] == jdk11)
+
+	later := modTime.Add(time.Second)
+	require.NoError(t, os.Chtimes(jdk11.Bin(), later, later))
+	jdks = discoverJDKs([]string{dir}, cache)
+	require.Len(t, jdks, 1)
+	require.Equal(t, 7, jdks[0].Major)
+
+	// A JAVA_HOME may also be given directly, and the installations no longer
+	// found are removed from the cache
+	jdks = discoverJDKs([]string{jdk7}, cache)
+	require.Len(t, jdks, 1)
+	require.Equal(t, 7, jdks[0].Major)
+	require.Len(t, cache, 1)
+}
+
+func TestDriver_setEnv(t *testing.T) {
+	env := []string{"FOO=bar", "JAVA_HOME=/usr/lib/jvm/default", "JAVA_HOMEX=1"}
+	env = setEnv(env, "JAVA_HOME", "/usr/lib/jvm/java-11")
+	require.Equal(t, []string{"FOO=bar", "JAVA_HOMEX=1", "JAVA_HOME=/usr/lib/jvm/java-11"}, env)
+}
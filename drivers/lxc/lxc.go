@@ -7,10 +7,10 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
-	"strings"
 	"syscall"
 	"time"
 
+	"github.com/hashicorp/nomad/drivers/shared/syscontainer"
 	"github.com/hashicorp/nomad/plugins/drivers"
 	lxc "gopkg.in/lxc/go-lxc.v2"
 )
@@ -92,31 +92,21 @@ func networkTypeConfigKey() string {
 
 func (d *Driver) mountVolumes(c *lxc.Container, cfg *drivers.TaskConfig, taskConfig TaskConfig) error {
 	// Bind mount the shared alloc dir and task local dir in the container
-	mounts := []string{
-		fmt.Sprintf("%s local none rw,bind,create=dir", cfg.TaskDir().LocalDir),
-		fmt.Sprintf("%s alloc none rw,bind,create=dir", cfg.TaskDir().SharedAllocDir),
-		fmt.Sprintf("%s secrets none rw,bind,create=dir", cfg.TaskDir().SecretsDir),
-	}
-
-	volumesEnabled := d.config.AllowVolumes
+	mounts := syscontainer.TaskMounts(cfg.TaskDir())
 
-	for _, volDesc := range taskConfig.Volumes {
-		// the format was checked in Validate()
-		paths := strings.Split(volDesc, ":")
+	volumes, err := syscontainer.ParseVolumes(taskConfig.Volumes, cfg.TaskDir().Dir, d.config.AllowVolumes)
+	if err != nil {
+		return err
+	}
+	mounts = append(mounts, volumes...)
 
-		if filepath.IsAbs(paths[0]) {
-			if !volumesEnabled {
-				return fmt.Errorf("absolute bind-mount volume in config but volumes are disabled")
-			}
-		} else {
-			// Relative source paths are treated as relative to alloc dir
-			paths[0] = filepath.Join(cfg.TaskDir().Dir, paths[0])
+	for _, m := range mounts {
+		opts := "rw,bind,create=dir"
+		if m.ReadOnly {
+			opts = "ro,bind,create=dir"
 		}
 
-		mounts = append(mounts, fmt.Sprintf("%s %s none rw,bind,create=dir", paths[0], paths[1]))
-	}
-
-	for _, mnt := range mounts {
+		mnt := fmt.Sprintf("%s %s none %s", m.Source, m.Destination, opts)
 		if err := c.SetConfigItem("lxc.mount.entry", mnt); err != nil {
 			return fmt.Errorf("error setting bind mount %q error: %v", mnt, err)
 		}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"strconv"
 	"strings"
 	"sync"
@@ -489,6 +490,33 @@ func (d *Driver) ExecTask(taskID string, cmd []string, timeout time.Duration) (*
 	return &res, nil
 }
 
+// ExecTaskStreaming echoes the stdin of the session to its stdout until stdin
+// is closed and exits successfully.
+func (d *Driver) ExecTaskStreaming(ctx context.Context, taskID string, opts *drivers.ExecOptions) (*drivers.ExitResult, error) {
+	if _, ok := d.tasks.Get(taskID); !ok {
+		return nil, drivers.ErrTaskNotFound
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := io.Copy(opts.Stdout, opts.Stdin)
+		errCh <- err
+	}()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case err := <-errCh:
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	opts.Stdout.Close()
+	opts.Stderr.Close()
+	return &drivers.ExitResult{}, nil
+}
+
 // GetTaskConfig is unique to the mock driver and for testing purposes only. It
 // returns the *drivers.TaskConfig passed to StartTask and the decoded
 // *mock.TaskConfig created by the last StartTask call.
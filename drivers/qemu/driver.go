@@ -4,7 +4,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"net"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -41,9 +40,10 @@ const (
 	driverVersionAttr = "driver.qemu.version"
 
 	// Represents an ACPI shutdown request to the VM (emulates pressing a physical power button)
-	// Reference: https://en.wikibooks.org/wiki/QEMU/Monitor
-	qemuGracefulShutdownMsg = "system_powerdown\n"
-	qemuMonitorSocketName   = "qemu-monitor.sock"
+	// Reference: https://qemu.weilnetz.de/doc/qemu-qmp-ref.html#index-system_005fpowerdown
+	qmpSystemPowerdown    = "system_powerdown"
+	qmpQueryBalloon       = "query-balloon"
+	qemuMonitorSocketName = "qemu-monitor.sock"
 
 	// Maximum socket path length prior to qemu 2.10.1
 	qemuLegacyMaxMonitorPathLen = 108
@@ -119,10 +119,12 @@ type TaskConfig struct {
 // This information is needed to rebuild the taskConfig state and handler
 // during recovery.
 type TaskState struct {
-	ReattachConfig *shared.ReattachConfig
-	TaskConfig     *drivers.TaskConfig
-	Pid            int
-	StartedAt      time.Time
+	ReattachConfig   *shared.ReattachConfig
+	TaskConfig       *drivers.TaskConfig
+	Pid              int
+	StartedAt        time.Time
+	MonitorPath      string
+	GracefulShutdown bool
 }
 
 // Driver is a driver for running images via Qemu
@@ -277,13 +279,17 @@ func (d *Driver) RecoverTask(handle *drivers.TaskHandle) error {
 	}
 
 	h := &taskHandle{
-		exec:         execImpl,
-		pid:          taskState.Pid,
-		pluginClient: pluginClient,
-		taskConfig:   taskState.TaskConfig,
-		procState:    drivers.TaskStateRunning,
-		startedAt:    taskState.StartedAt,
-		exitResult:   &drivers.ExitResult{},
+		exec:             execImpl,
+		pid:              taskState.Pid,
+		monitor:          monitorFor(taskState.MonitorPath),
+		gracefulShutdown: taskState.GracefulShutdown,
+		pluginClient:     pluginClient,
+		taskConfig:       taskState.TaskConfig,
+		procState:        drivers.TaskStateRunning,
+		startedAt:        taskState.StartedAt,
+		exitResult:       &drivers.ExitResult{},
+		doneCh:           make(chan struct{}),
+		logger:           d.logger,
 	}
 
 	d.tasks.Set(taskState.TaskConfig.ID, h)
@@ -339,25 +345,26 @@ func (d *Driver) StartTask(cfg *drivers.TaskConfig) (*drivers.TaskHandle, *cstru
 		"-nographic",
 	}
 
+	if driverConfig.GracefulShutdown && runtime.GOOS == "windows" {
+		return nil, nil, errors.New("QEMU graceful shutdown is unsupported on the Windows platform")
+	}
+
+	// The QMP socket is used to manage the virtual machine (for example, to
+	// perform graceful shutdowns and to query memory statistics). It is only
+	// required when graceful shutdown is requested.
 	var monitorPath string
-	if driverConfig.GracefulShutdown {
-		if runtime.GOOS == "windows" {
-			return nil, nil, errors.New("QEMU graceful shutdown is unsupported on the Windows platform")
-		}
-		// This socket will be used to manage the virtual machine (for example,
-		// to perform graceful shutdowns)
-		taskDir := filepath.Join(cfg.AllocDir, cfg.Name)
-		fingerPrint := d.buildFingerprint()
-		if fingerPrint.Attributes == nil {
-			return nil, nil, fmt.Errorf("unable to get qemu driver version from fingerprinted attributes")
-		}
-		monitorPath, err = d.getMonitorPath(taskDir, fingerPrint)
+	if runtime.GOOS != "windows" {
+		monitorPath, err = d.qmpSocketPath(cfg)
 		if err != nil {
-			d.logger.Debug("could not get qemu monitor path", "error", err)
-			return nil, nil, err
+			if driverConfig.GracefulShutdown {
+				d.logger.Debug("could not get qemu monitor path", "error", err)
+				return nil, nil, err
+			}
+			d.logger.Debug("not creating qemu monitor socket", "error", err)
+		} else {
+			d.logger.Debug("got monitor path", "monitorPath", monitorPath)
+			args = append(args, "-qmp", fmt.Sprintf("unix:%s,server,nowait", monitorPath))
 		}
-		d.logger.Debug("got monitor path", "monitorPath", monitorPath)
-		args = append(args, "-monitor", fmt.Sprintf("unix:%s,server,nowait", monitorPath))
 	}
 
 	// Add pass through arguments to qemu executable. A user can specify
@@ -441,21 +448,25 @@ func (d *Driver) StartTask(cfg *drivers.TaskConfig) (*drivers.TaskHandle, *cstru
 	d.logger.Debug("started new QemuVM", "ID", vmID)
 
 	h := &taskHandle{
-		exec:         execImpl,
-		pid:          ps.Pid,
-		monitorPath:  monitorPath,
-		pluginClient: pluginClient,
-		taskConfig:   cfg,
-		procState:    drivers.TaskStateRunning,
-		startedAt:    time.Now().Round(time.Millisecond),
-		logger:       d.logger,
+		exec:             execImpl,
+		pid:              ps.Pid,
+		monitor:          monitorFor(monitorPath),
+		gracefulShutdown: driverConfig.GracefulShutdown,
+		pluginClient:     pluginClient,
+		taskConfig:       cfg,
+		procState:        drivers.TaskStateRunning,
+		startedAt:        time.Now().Round(time.Millisecond),
+		doneCh:           make(chan struct{}),
+		logger:           d.logger,
 	}
 
 	qemuDriverState := TaskState{
-		ReattachConfig: shared.ReattachConfigFromGoPlugin(pluginClient.ReattachConfig()),
-		Pid:            ps.Pid,
-		TaskConfig:     cfg,
-		StartedAt:      h.startedAt,
+		ReattachConfig:   shared.ReattachConfigFromGoPlugin(pluginClient.ReattachConfig()),
+		Pid:              ps.Pid,
+		TaskConfig:       cfg,
+		StartedAt:        h.startedAt,
+		MonitorPath:      monitorPath,
+		GracefulShutdown: driverConfig.GracefulShutdown,
 	}
 
 	if err := handle.SetDriverState(&qemuDriverState); err != nil {
@@ -495,16 +506,25 @@ func (d *Driver) StopTask(taskID string, timeout time.Duration, signal string) e
 		return drivers.ErrTaskNotFound
 	}
 
-	// Attempt a graceful shutdown only if it was configured in the job
-	if handle.monitorPath != "" {
-		if err := sendQemuShutdown(d.logger, handle.monitorPath, handle.pid); err != nil {
+	// Attempt a graceful shutdown only if it was configured in the job. If
+	// the guest acknowledges the ACPI power down request, give it until the
+	// timeout to exit before the executor kills the VM.
+	if handle.gracefulShutdown && handle.monitor != nil {
+		if err := sendQemuShutdown(d.logger, handle.monitor, handle.pid); err != nil {
 			d.logger.Debug("error sending graceful shutdown ", "pid", handle.pid, "error", err)
+		} else {
+			select {
+			case <-handle.doneCh:
+			case <-time.After(timeout):
+				d.logger.Debug("qemu did not shut down gracefully within timeout", "pid", handle.pid, "timeout", timeout)
+			}
+
+			// The VM already had its chance to stop, so don't send it a
+			// signal which would stop qemu immediately.
+			signal, timeout = "", 0
 		}
 	}
 
-	// TODO(preetha) we are calling shutdown on the executor here
-	// after attempting a graceful qemu shutdown, qemu process may
-	// not be around when we call exec.shutdown
 	if err := handle.exec.Shutdown(signal, timeout); err != nil {
 		if handle.pluginClient.Exited() {
 			return nil
@@ -554,7 +574,25 @@ func (d *Driver) TaskStats(taskID string) (*cstructs.TaskResourceUsage, error) {
 		return nil, drivers.ErrTaskNotFound
 	}
 
-	return handle.exec.Stats()
+	usage, err := handle.exec.Stats()
+	if err != nil {
+		return nil, err
+	}
+
+	// Query the guest's memory balloon if a QMP socket is available. The
+	// balloon is only present if the VM was started with a balloon device.
+	if handle.monitor != nil && handle.IsRunning() {
+		var balloon qmpBalloonInfo
+		if err := handle.monitor.execute(qmpQueryBalloon, &balloon); err != nil {
+			d.logger.Trace("failed to query qemu memory balloon", "task_id", taskID, "error", err)
+		} else if usage.ResourceUsage != nil && usage.ResourceUsage.MemoryStats != nil {
+			ms := usage.ResourceUsage.MemoryStats
+			ms.BalloonActual = balloon.Actual
+			ms.Measured = append(ms.Measured, "Balloon Actual")
+		}
+	}
+
+	return usage, nil
 }
 
 func (d *Driver) TaskEvents(ctx context.Context) (<-chan *drivers.TaskEvent, error) {
@@ -626,22 +664,36 @@ func (d *Driver) getMonitorPath(dir string, fingerPrint *drivers.Fingerprint) (s
 	return fullSocketPath, nil
 }
 
+// qmpSocketPath returns the path of the QMP socket for the task, or an error
+// if the socket path can not be used by the version of qemu on the host.
+func (d *Driver) qmpSocketPath(cfg *drivers.TaskConfig) (string, error) {
+	taskDir := filepath.Join(cfg.AllocDir, cfg.Name)
+	fingerPrint := d.buildFingerprint()
+	if _, ok := fingerPrint.Attributes[driverVersionAttr]; !ok {
+		return "", fmt.Errorf("unable to get qemu driver version from fingerprinted attributes")
+	}
+	return d.getMonitorPath(taskDir, fingerPrint)
+}
+
+// monitorFor returns a QMP monitor for the socket at the given path, or nil
+// if the VM has no QMP socket.
+func monitorFor(monitorPath string) *qmpMonitor {
+	if monitorPath == "" {
+		return nil
+	}
+	return newQMPMonitor(monitorPath)
+}
+
 // sendQemuShutdown attempts to issue an ACPI power-off command via the qemu
 // monitor
-func sendQemuShutdown(logger hclog.Logger, monitorPath string, userPid int) error {
-	if monitorPath == "" {
-		return errors.New("monitorPath not set")
+func sendQemuShutdown(logger hclog.Logger, monitor *qmpMonitor, userPid int) error {
+	if monitor == nil {
+		return errors.New("monitor not set")
 	}
-	monitorSocket, err := net.Dial("unix", monitorPath)
-	if err != nil {
-		logger.Warn("could not connect to qemu monitor", "pid", userPid, "monitorPath", monitorPath, "error", err)
+	logger.Debug("sending graceful shutdown command to qemu monitor socket", "monitor_path", monitor.path, "pid", userPid)
+	if err := monitor.execute(qmpSystemPowerdown, nil); err != nil {
+		logger.Warn("failed to send shutdown message", "command", qmpSystemPowerdown, "monitorPath", monitor.path, "userPid", userPid, "error", err)
 		return err
 	}
-	defer monitorSocket.Close()
-	logger.Debug("sending graceful shutdown command to qemu monitor socket", "monitor_path", monitorPath, "pid", userPid)
-	_, err = monitorSocket.Write([]byte(qemuGracefulShutdownMsg))
-	if err != nil {
-		logger.Warn("failed to send shutdown message", "shutdown message", qemuGracefulShutdownMsg, "monitorPath", monitorPath, "userPid", userPid, "error", err)
-	}
-	return err
+	return nil
 }
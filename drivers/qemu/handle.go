@@ -16,7 +16,17 @@ type taskHandle struct {
 	pid          int
 	pluginClient *plugin.Client
 	logger       hclog.Logger
-	monitorPath  string
+
+	// monitor is the connection to the QMP socket of the VM, or nil if the
+	// VM has no QMP socket
+	monitor *qmpMonitor
+
+	// gracefulShutdown is set if the VM should be sent an ACPI power down
+	// request via the monitor socket before being killed
+	gracefulShutdown bool
+
+	// doneCh is closed when the VM exits
+	doneCh chan struct{}
 
 	// stateLock syncs access to all fields below
 	stateLock sync.RWMutex
@@ -59,6 +69,12 @@ func (h *taskHandle) run() {
 	h.stateLock.Unlock()
 
 	ps, err := h.exec.Wait()
+	defer close(h.doneCh)
+
+	// The QMP socket is gone with the VM
+	if h.monitor != nil {
+		h.monitor.Close()
+	}
 
 	h.stateLock.Lock()
 	defer h.stateLock.Unlock()
@@ -72,7 +88,6 @@ func (h *taskHandle) run() {
 	h.procState = drivers.TaskStateExited
 	h.exitResult.ExitCode = ps.ExitCode
 	h.exitResult.Signal = ps.Signal
+	h.exitResult.OOMKilled = ps.OOMKilled
 	h.completedAt = ps.Time
-
-	// TODO: detect if the taskConfig OOMed
 }
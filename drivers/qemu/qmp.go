@@ -0,0 +1,179 @@
+package qemu
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+const (
+	// qmpTimeout is the maximum time spent on a single QMP exchange,
+	// including connecting to the monitor socket.
+	qmpTimeout = 5 * time.Second
+)
+
+// qmpClient is a minimal client for the QEMU Machine Protocol (QMP). It
+// supports executing synchronous commands and discards any asynchronous events
+// received while waiting for a response.
+//
+// Reference: https://wiki.qemu.org/Documentation/QMP
+type qmpClient struct {
+	conn net.Conn
+	dec  *json.Decoder
+}
+
+// qmpCommand is a command sent to the QMP server
+type qmpCommand struct {
+	Execute   string      `json:"execute"`
+	Arguments interface{} `json:"arguments,omitempty"`
+}
+
+// qmpResponse is a message received from the QMP server. Only one of the
+// fields is set depending on the message type.
+type qmpResponse struct {
+	Greeting json.RawMessage `json:"QMP"`
+	Return   json.RawMessage `json:"return"`
+	Error    *qmpError       `json:"error"`
+	Event    string          `json:"event"`
+}
+
+// qmpError is an error returned by the QMP server in response to a command
+type qmpError struct {
+	Class string `json:"class"`
+	Desc  string `json:"desc"`
+}
+
+func (e *qmpError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Class, e.Desc)
+}
+
+// qmpBalloonInfo is the response to the query-balloon command
+type qmpBalloonInfo struct {
+	// Actual is the amount of memory in bytes currently available to the
+	// guest
+	Actual uint64 `json:"actual"`
+}
+
+// dialQMP connects to the QMP socket at the given path, waits for the server
+// greeting and negotiates capabilities so commands can be executed.
+func dialQMP(path string) (*qmpClient, error) {
+	conn, err := net.DialTimeout("unix", path, qmpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &qmpClient{
+		conn: conn,
+		dec:  json.NewDecoder(conn),
+	}
+
+	conn.SetDeadline(time.Now().Add(qmpTimeout))
+	var greeting qmpResponse
+	if err := c.dec.Decode(&greeting); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to read QMP greeting: %v", err)
+	}
+	if greeting.Greeting == nil {
+		conn.Close()
+		return nil, fmt.Errorf("unexpected QMP greeting")
+	}
+
+	if err := c.execute("qmp_capabilities", nil); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to negotiate QMP capabilities: %v", err)
+	}
+
+	return c, nil
+}
+
+// execute runs the given QMP command and decodes the returned value into out
+// if it is non-nil.
+func (c *qmpClient) execute(cmd string, out interface{}) error {
+	c.conn.SetDeadline(time.Now().Add(qmpTimeout))
+	if err := json.NewEncoder(c.conn).Encode(&qmpCommand{Execute: cmd}); err != nil {
+		return err
+	}
+
+	for {
+		var resp qmpResponse
+		if err := c.dec.Decode(&resp); err != nil {
+			return err
+		}
+
+		switch {
+		case resp.Event != "":
+			// Asynchronous events may be received at any time
+			continue
+		case resp.Error != nil:
+			return resp.Error
+		case resp.Return != nil:
+			if out == nil {
+				return nil
+			}
+			return json.Unmarshal(resp.Return, out)
+		}
+	}
+}
+
+// Close closes the connection to the QMP server
+func (c *qmpClient) Close() error {
+	return c.conn.Close()
+}
+
+// qmpMonitor holds a connection to the QMP socket of a VM that is reused
+// across commands. The connection is established on first use and
+// re-established by the next command after it fails.
+type qmpMonitor struct {
+	path string
+
+	client *qmpClient
+	closed bool
+	lock   sync.Mutex
+}
+
+func newQMPMonitor(path string) *qmpMonitor {
+	return &qmpMonitor{path: path}
+}
+
+// execute runs a single command over the monitor connection, connecting to
+// the QMP socket first if needed.
+func (m *qmpMonitor) execute(cmd string, out interface{}) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.closed {
+		return fmt.Errorf("QMP monitor closed")
+	}
+	if m.client == nil {
+		c, err := dialQMP(m.path)
+		if err != nil {
+			return err
+		}
+		m.client = c
+	}
+
+	err := m.client.execute(cmd, out)
+	if _, ok := err.(*qmpError); err != nil && !ok {
+		// The connection is in an unknown state, so drop it
+		m.client.Close()
+		m.client = nil
+	}
+	return err
+}
+
+// Close closes the monitor connection if it is open. No further commands
+// may be executed.
+func (m *qmpMonitor) Close() error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.closed = true
+	if m.client == nil {
+		return nil
+	}
+	err := m.client.Close()
+	m.client = nil
+	return err
+}
@@ -0,0 +1,142 @@
+package qemu
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeQMPServer listens on a unix socket and responds to QMP commands using
+// the given handler. Received commands are sent on the returned channel.
+func fakeQMPServer(t *testing.T, handler func(cmd string) string) (string, <-chan string, func()) {
+	dir, err := ioutil.TempDir("", "nomad_qmp_test")
+	require.NoError(t, err)
+
+	path := filepath.Join(dir, qemuMonitorSocketName)
+	l, err := net.Listen("unix", path)
+	require.NoError(t, err)
+
+	cmds := make(chan string, 10)
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+
+			go func(conn net.Conn) {
+				defer conn.Close()
+				conn.Write([]byte(`{"QMP": {"version": {"qemu": {"micro": 0, "minor": 11, "major": 2}}, "capabilities": []}}` + "\n"))
+
+				scanner := bufio.NewScanner(conn)
+				for scanner.Scan() {
+					var cmd qmpCommand
+					if err := json.Unmarshal(scanner.Bytes(), &cmd); err != nil {
+						return
+					}
+					cmds <- cmd.Execute
+
+					if cmd.Execute == "qmp_capabilities" {
+						conn.Write([]byte(`{"return": {}}` + "\n"))
+						continue
+					}
+
+					// Interleave an event to ensure it is skipped
+					conn.Write([]byte(`{"event": "RTC_CHANGE", "data": {"offset": 0}, "timestamp": {"seconds": 1, "microseconds": 2}}` + "\n"))
+					conn.Write([]byte(handler(cmd.Execute) + "\n"))
+				}
+			}(conn)
+		}
+	}()
+
+	return path, cmds, func() {
+		l.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestQemuDriver_QMP_Shutdown(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("QMP sockets are not supported on Windows")
+	}
+	t.Parallel()
+	require := require.New(t)
+
+	path, cmds, cleanup := fakeQMPServer(t, func(string) string {
+		return `{"return": {}}`
+	})
+	defer cleanup()
+
+	require.NoError(sendQemuShutdown(testlog.HCLogger(t), newQMPMonitor(path), 1))
+	require.Equal("qmp_capabilities", <-cmds)
+	require.Equal(qmpSystemPowerdown, <-cmds)
+}
+
+func TestQemuDriver_QMP_Balloon(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("QMP sockets are not supported on Windows")
+	}
+	t.Parallel()
+	require := require.New(t)
+
+	path, _, cleanup := fakeQMPServer(t, func(cmd string) string {
+		if cmd == qmpQueryBalloon {
+			return `{"return": {"actual": 536870912}}`
+		}
+		return `{"error": {"class": "CommandNotFound", "desc": "The command does not exist"}}`
+	})
+	defer cleanup()
+
+	m := newQMPMonitor(path)
+	defer m.Close()
+
+	var balloon qmpBalloonInfo
+	require.NoError(m.execute(qmpQueryBalloon, &balloon))
+	require.EqualValues(512*1024*1024, balloon.Actual)
+
+	err := m.execute("query-unknown", nil)
+	require.Error(err)
+	require.Contains(err.Error(), "CommandNotFound")
+}
+
+func TestQemuDriver_QMP_ReusesConnection(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("QMP sockets are not supported on Windows")
+	}
+	t.Parallel()
+	require := require.New(t)
+
+	path, cmds, cleanup := fakeQMPServer(t, func(string) string {
+		return `{"return": {"actual": 1024}}`
+	})
+	defer cleanup()
+
+	m := newQMPMonitor(path)
+	for i := 0; i < 3; i++ {
+		var balloon qmpBalloonInfo
+		require.NoError(m.execute(qmpQueryBalloon, &balloon))
+	}
+
+	// Capabilities are only negotiated once for the single connection
+	require.Equal("qmp_capabilities", <-cmds)
+	for i := 0; i < 3; i++ {
+		require.Equal(qmpQueryBalloon, <-cmds)
+	}
+
+	require.NoError(m.Close())
+	require.Error(m.execute(qmpQueryBalloon, nil))
+}
+
+func TestQemuDriver_QMP_NoSocket(t *testing.T) {
+	t.Parallel()
+	err := sendQemuShutdown(testlog.HCLogger(t), newQMPMonitor(filepath.Join(os.TempDir(), "nomad-missing-qmp.sock")), 1)
+	require.Error(t, err)
+}
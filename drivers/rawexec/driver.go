@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"runtime"
 	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
@@ -59,6 +60,9 @@ func PluginLoader(opts map[string]string) (map[string]interface{}, error) {
 	if v, err := strconv.ParseBool(opts["driver.raw_exec.no_cgroups"]); err == nil {
 		conf["no_cgroups"] = v
 	}
+	if v, ok := opts["driver.raw_exec.user.whitelist"]; ok {
+		conf["allow_users"] = strings.Split(v, ",")
+	}
 	return conf, nil
 }
 
@@ -81,6 +85,7 @@ var (
 			hclspec.NewAttr("no_cgroups", "bool", false),
 			hclspec.NewLiteral("false"),
 		),
+		"allow_users": hclspec.NewAttr("allow_users", "list(string)", false),
 	})
 
 	// taskConfigSpec is the hcl specification for the driver config section of
@@ -136,6 +141,10 @@ type Config struct {
 
 	// Enabled is set to true to enable the raw_exec driver
 	Enabled bool `codec:"enabled"`
+
+	// AllowUsers is the set of users tasks may run as. If empty, tasks may
+	// set any user.
+	AllowUsers []string `codec:"allow_users"`
 }
 
 // TaskConfig is the driver configuration of a task within a job
@@ -308,6 +317,10 @@ func (d *Driver) StartTask(cfg *drivers.TaskConfig) (*drivers.TaskHandle, *cstru
 		return nil, nil, fmt.Errorf("failed to decode driver config: %v", err)
 	}
 
+	if err := d.validateUser(cfg.User); err != nil {
+		return nil, nil, err
+	}
+
 	d.logger.Info("starting task", "driver_cfg", hclog.Fmt("%+v", driverConfig))
 	handle := drivers.NewTaskHandle(pluginName)
 	handle.Config = cfg
@@ -324,8 +337,10 @@ func (d *Driver) StartTask(cfg *drivers.TaskConfig) (*drivers.TaskHandle, *cstru
 	}
 
 	// Only use cgroups when running as root on linux - Doing so in other cases
-	// will cause an error.
-	useCgroups := !d.config.NoCgroups && runtime.GOOS == "linux" && syscall.Geteuid() == 0
+	// will cause an error. On Windows the process tree is contained in a job
+	// object instead.
+	useCgroups := !d.config.NoCgroups &&
+		(runtime.GOOS == "linux" && syscall.Geteuid() == 0 || runtime.GOOS == "windows")
 
 	execCmd := &executor.ExecCommand{
 		Cmd:                driverConfig.Command,
@@ -373,6 +388,22 @@ func (d *Driver) StartTask(cfg *drivers.TaskConfig) (*drivers.TaskHandle, *cstru
 	return handle, nil, nil
 }
 
+// validateUser returns an error if the task is not allowed to run as the given
+// user by the driver configuration.
+func (d *Driver) validateUser(user string) error {
+	if user == "" || len(d.config.AllowUsers) == 0 {
+		return nil
+	}
+
+	for _, allowed := range d.config.AllowUsers {
+		if strings.TrimSpace(allowed) == user {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("running as user %q is not allowed by the raw_exec driver configuration", user)
+}
+
 func (d *Driver) WaitTask(ctx context.Context, taskID string) (<-chan *drivers.ExitResult, error) {
 	handle, ok := d.tasks.Get(taskID)
 	if !ok {
@@ -60,6 +60,25 @@ func TestRawExecDriver_SetConfig(t *testing.T) {
 	require.NoError(basePlug.MsgPackEncode(&data, config))
 	require.NoError(harness.SetConfig(data, nil))
 	require.Exactly(config, d.(*Driver).config)
+
+	config.AllowUsers = []string{"nobody", "www-data"}
+	data = []byte{}
+	require.NoError(basePlug.MsgPackEncode(&data, config))
+	require.NoError(harness.SetConfig(data, nil))
+	require.Exactly(config, d.(*Driver).config)
+}
+
+func TestRawExecDriver_PluginLoader(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	conf, err := PluginLoader(map[string]string{
+		"driver.raw_exec.enable":         "1",
+		"driver.raw_exec.user.whitelist": "nobody,www-data",
+	})
+	require.NoError(err)
+	require.Equal(true, conf["enabled"])
+	require.Equal([]string{"nobody", "www-data"}, conf["allow_users"])
 }
 
 func TestRawExecDriver_Fingerprint(t *testing.T) {
@@ -16,6 +16,7 @@ import (
 	"github.com/hashicorp/nomad/helper/testlog"
 	"github.com/hashicorp/nomad/helper/testtask"
 	"github.com/hashicorp/nomad/helper/uuid"
+	basePlug "github.com/hashicorp/nomad/plugins/base"
 	"github.com/hashicorp/nomad/plugins/drivers"
 	"github.com/hashicorp/nomad/testutil"
 	"github.com/stretchr/testify/require"
@@ -53,6 +54,43 @@ func TestRawExecDriver_User(t *testing.T) {
 	require.Contains(err.Error(), msg)
 }
 
+func TestRawExecDriver_User_NotAllowed(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	d := NewRawExecDriver(testlog.HCLogger(t))
+	harness := drivers.NewDriverHarness(t, d)
+	defer harness.Kill()
+
+	config := &Config{
+		Enabled:    true,
+		AllowUsers: []string{"nobody"},
+	}
+	var data []byte
+	require.NoError(basePlug.MsgPackEncode(&data, config))
+	require.NoError(harness.SetConfig(data, nil))
+
+	task := &drivers.TaskConfig{
+		ID:   uuid.Generate(),
+		Name: "sleep",
+		User: "root",
+	}
+
+	cleanup := harness.MkAllocDir(task, false)
+	defer cleanup()
+
+	taskConfig := map[string]interface{}{}
+	taskConfig["command"] = testtask.Path()
+	taskConfig["args"] = []string{"sleep", "45s"}
+
+	encodeDriverHelper(require, task, taskConfig)
+	testtask.SetTaskConfigEnv(task)
+
+	_, _, err := harness.StartTask(task)
+	require.Error(err)
+	require.Contains(err.Error(), `running as user "root" is not allowed`)
+}
+
 func TestRawExecDriver_Signal(t *testing.T) {
 	t.Parallel()
 	if runtime.GOOS != "linux" {
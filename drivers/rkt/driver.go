@@ -555,6 +555,10 @@ func (d *Driver) StartTask(cfg *drivers.TaskConfig) (*drivers.TaskHandle, *cstru
 			containerPort = mapped
 
 			hostPortStr := strconv.Itoa(port.Value)
+			if port.HostIP != "" {
+				// Only bind the port on the address of its host network
+				hostPortStr = port.HostIP + ":" + hostPortStr
+			}
 
 			d.logger.Debug("driver.rkt: exposed port", "containerPort", containerPort)
 			// Add port option to rkt run arguments. rkt allows multiple port args
@@ -573,6 +577,10 @@ func (d *Driver) StartTask(cfg *drivers.TaskConfig) (*drivers.TaskHandle, *cstru
 			}
 
 			hostPortStr := strconv.Itoa(port.Value)
+			if port.HostIP != "" {
+				// Only bind the port on the address of its host network
+				hostPortStr = port.HostIP + ":" + hostPortStr
+			}
 
 			d.logger.Debug("exposed port", "containerPort", containerPort, "task_name", cfg.Name)
 			// Add port option to rkt run arguments. rkt allows multiple port args
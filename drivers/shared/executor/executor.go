@@ -76,6 +76,11 @@ type Executor interface {
 	// Exec executes the given command and args inside the executor context
 	// and returns the output and exit code.
 	Exec(deadline time.Time, cmd string, args []string) ([]byte, int, error)
+
+	// Checkpoint dumps the state of the user process into the given directory
+	// using CRIU and stops the process. It is only supported when the
+	// executor isolates the process.
+	Checkpoint(dir string) error
 }
 
 // Resources describes the resource isolation required
@@ -84,6 +89,31 @@ type Resources struct {
 	MemoryMB int
 	DiskMB   int
 	IOPS     int
+
+	// IOWeight is the relative block I/O weight and IOLimits throttle the
+	// block I/O against individual devices
+	IOWeight int
+	IOLimits []*IOLimit
+
+	// CPUPeriod and CPUQuota are the CFS period and quota in microseconds
+	// hard limiting the CPU usage. A zero quota leaves the CPU unlimited.
+	CPUPeriod int64
+	CPUQuota  int64
+
+	// CpusetCPUs and CpusetMems restrict the task to a set of cores and
+	// memory nodes in cpuset list format. Empty values leave it unrestricted.
+	CpusetCPUs string
+	CpusetMems string
+}
+
+// IOLimit throttles the block I/O against a single device. Zero values are
+// left unlimited.
+type IOLimit struct {
+	Device    string
+	ReadBps   int64
+	WriteBps  int64
+	ReadIOps  int64
+	WriteIOps int64
 }
 
 // ExecCommand holds the user command, args, and other isolation related
@@ -123,6 +153,29 @@ type ExecCommand struct {
 	// doesn't enforce resource limits. To enforce limits, set ResourceLimits.
 	// Using the cgroup does allow more precise cleanup of processes.
 	BasicProcessCgroup bool
+
+	// RestoreDir is a directory holding a checkpoint of the process. If set,
+	// the process is restored from it instead of started from Cmd.
+	RestoreDir string
+
+	// UIDMap and GIDMap remap the user and group IDs of the process into a
+	// new user namespace. They are only supported with filesystem isolation
+	// and must either both be set or both be empty.
+	UIDMap []IDMap
+	GIDMap []IDMap
+
+	// NetworkNamespace is the path of a network namespace the process joins
+	// instead of using the network of the host. It is only supported with
+	// filesystem isolation.
+	NetworkNamespace string
+}
+
+// IDMap maps a range of user or group IDs inside a user namespace to a range
+// of IDs on the host
+type IDMap struct {
+	ContainerID int
+	HostID      int
+	Size        int
 }
 
 type nopCloser struct {
@@ -180,6 +233,10 @@ type ProcessState struct {
 	ExitCode int
 	Signal   int
 	Time     time.Time
+
+	// OOMKilled is true if the process was killed by the OOM killer. It is
+	// only detected when the executor isolates the process.
+	OOMKilled bool
 }
 
 // ExecutorVersion is the version of the executor
@@ -292,7 +349,7 @@ func (e *UniversalExecutor) Launch(command *ExecCommand) (*ProcessState, error)
 	e.childCmd.Env = e.commandCfg.Env
 
 	// Start the process
-	if err := e.childCmd.Start(); err != nil {
+	if err := e.start(); err != nil {
 		return nil, fmt.Errorf("failed to start command path=%q --- args=%q: %v", path, e.childCmd.Args, err)
 	}
 
@@ -308,6 +365,11 @@ func (e *UniversalExecutor) Exec(deadline time.Time, name string, args []string)
 	return ExecScript(ctx, e.childCmd.Dir, e.commandCfg.Env, e.childCmd.SysProcAttr, name, args)
 }
 
+// Checkpoint is not supported as the process is not isolated.
+func (e *UniversalExecutor) Checkpoint(dir string) error {
+	return fmt.Errorf("checkpointing requires filesystem isolation")
+}
+
 // ExecScript executes cmd with args and returns the output, exit code, and
 // error. Output is truncated to drivers/shared/structs.CheckBufSize
 func ExecScript(ctx context.Context, dir string, env []string, attrs *syscall.SysProcAttr,
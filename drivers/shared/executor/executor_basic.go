@@ -10,6 +10,4 @@ func NewExecutorWithIsolation(logger hclog.Logger) Executor {
 	return NewExecutor(logger)
 }
 
-func (e *UniversalExecutor) configureResourceContainer(_ int) error { return nil }
-
 func (e *UniversalExecutor) runAs(_ string) error { return nil }
@@ -10,6 +10,7 @@ import (
 	"path"
 	"path/filepath"
 	"strings"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -17,6 +18,7 @@ import (
 	"github.com/hashicorp/consul-template/signals"
 	hclog "github.com/hashicorp/go-hclog"
 	multierror "github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/client/lib/cgutil"
 	"github.com/hashicorp/nomad/client/stats"
 	cstructs "github.com/hashicorp/nomad/client/structs"
 	"github.com/hashicorp/nomad/helper/discover"
@@ -26,6 +28,7 @@ import (
 	"github.com/opencontainers/runc/libcontainer/cgroups"
 	cgroupFs "github.com/opencontainers/runc/libcontainer/cgroups/fs"
 	lconfigs "github.com/opencontainers/runc/libcontainer/configs"
+	"golang.org/x/sys/unix"
 
 	"github.com/syndtr/gocapability/capability"
 )
@@ -36,8 +39,9 @@ const (
 
 var (
 	// The statistics the executor exposes when using cgroups
-	ExecutorCgroupMeasuredMemStats = []string{"RSS", "Cache", "Swap", "Max Usage", "Kernel Usage", "Kernel Max Usage"}
+	ExecutorCgroupMeasuredMemStats = []string{"RSS", "Cache", "Swap", "Usage", "Max Usage", "Kernel Usage", "Kernel Max Usage"}
 	ExecutorCgroupMeasuredCpuStats = []string{"System Mode", "User Mode", "Throttled Periods", "Throttled Time", "Percent"}
+	ExecutorCgroupMeasuredIOStats  = []string{"Read Bytes", "Write Bytes", "Read Ops", "Write Ops"}
 
 	// allCaps is all linux capabilities which is used to configure libcontainer
 	allCaps []string
@@ -74,6 +78,10 @@ type LibcontainerExecutor struct {
 	userProc       *libcontainer.Process
 	userProcExited chan interface{}
 	exitState      *ProcessState
+
+	// oomKilled is set to 1 once the OOM killer was invoked for the memory
+	// cgroup of the container. Accessed atomically.
+	oomKilled uint32
 }
 
 func NewExecutorWithIsolation(logger hclog.Logger) Executor {
@@ -118,7 +126,7 @@ func (l *LibcontainerExecutor) Launch(command *ExecCommand) (*ProcessState, erro
 	// create a new factory which will store the container state in the allocDir
 	factory, err := libcontainer.New(
 		path.Join(command.TaskDir, "../alloc/container"),
-		libcontainer.Cgroupfs,
+		cgroupsFactory,
 		libcontainer.InitArgs(bin, "libcontainer-shim"),
 	)
 	if err != nil {
@@ -179,8 +187,15 @@ func (l *LibcontainerExecutor) Launch(command *ExecCommand) (*ProcessState, erro
 	l.userCpuStats = stats.NewCpuStats()
 	l.systemCpuStats = stats.NewCpuStats()
 
-	// Starts the task
-	if err := container.Run(process); err != nil {
+	// Starts the task, restoring it from a checkpoint if one is given
+	if command.RestoreDir != "" {
+		err = container.Restore(process, &libcontainer.CriuOpts{
+			ImagesDirectory: command.RestoreDir,
+		})
+	} else {
+		err = container.Run(process)
+	}
+	if err != nil {
 		container.Destroy()
 		return nil, err
 	}
@@ -200,6 +215,13 @@ func (l *LibcontainerExecutor) Launch(command *ExecCommand) (*ProcessState, erro
 		l.logger.Error("error entering user process cgroups", "executor_pid", os.Getpid(), "error", err)
 	}
 
+	// Watch for the OOM killer so it can be reported as the exit reason
+	if oomCh, err := container.NotifyOOM(); err != nil {
+		l.logger.Warn("failed to watch for OOM kills of user process", "error", err)
+	} else {
+		go l.watchOOM(oomCh)
+	}
+
 	// start a goroutine to wait on the process to complete, so Wait calls can
 	// be multiplexed
 	l.userProcExited = make(chan interface{})
@@ -265,11 +287,25 @@ func (l *LibcontainerExecutor) wait() {
 		}
 	}
 
+	// The OOM killer may have killed another process of the container, so
+	// only attribute the exit to it if the user process was killed as well
+	oomKilled := signal == int(syscall.SIGKILL) && atomic.LoadUint32(&l.oomKilled) == 1
+
 	l.exitState = &ProcessState{
-		Pid:      ps.Pid(),
-		ExitCode: exitCode,
-		Signal:   signal,
-		Time:     time.Now(),
+		Pid:       ps.Pid(),
+		ExitCode:  exitCode,
+		Signal:    signal,
+		Time:      time.Now(),
+		OOMKilled: oomKilled,
+	}
+}
+
+// watchOOM records whether the OOM killer was invoked for the container until
+// the notification channel is closed when the cgroup is destroyed.
+func (l *LibcontainerExecutor) watchOOM(oomCh <-chan struct{}) {
+	for range oomCh {
+		l.logger.Warn("OOM killer invoked for user process cgroup")
+		atomic.StoreUint32(&l.oomKilled, 1)
 	}
 }
 
@@ -360,6 +396,7 @@ func (l *LibcontainerExecutor) Stats() (*cstructs.TaskResourceUsage, error) {
 		RSS:            rss,
 		Cache:          cache,
 		Swap:           swap.Usage,
+		Usage:          stats.MemoryStats.Usage.Usage,
 		MaxUsage:       maxUsage,
 		KernelUsage:    stats.MemoryStats.KernelUsage.Usage,
 		KernelMaxUsage: stats.MemoryStats.KernelUsage.MaxUsage,
@@ -381,10 +418,33 @@ func (l *LibcontainerExecutor) Stats() (*cstructs.TaskResourceUsage, error) {
 		TotalTicks:       l.systemCpuStats.TicksConsumed(totalPercent),
 		Measured:         ExecutorCgroupMeasuredCpuStats,
 	}
+
+	// Block I/O Related Stats
+	is := &cstructs.IOStats{
+		Measured: ExecutorCgroupMeasuredIOStats,
+	}
+	for _, e := range stats.BlkioStats.IoServiceBytesRecursive {
+		switch e.Op {
+		case "Read":
+			is.ReadBytes += e.Value
+		case "Write":
+			is.WriteBytes += e.Value
+		}
+	}
+	for _, e := range stats.BlkioStats.IoServicedRecursive {
+		switch e.Op {
+		case "Read":
+			is.ReadOps += e.Value
+		case "Write":
+			is.WriteOps += e.Value
+		}
+	}
+
 	taskResUsage := cstructs.TaskResourceUsage{
 		ResourceUsage: &cstructs.ResourceUsage{
 			MemoryStats: ms,
 			CpuStats:    cs,
+			IOStats:     is,
 		},
 		Timestamp: ts.UTC().UnixNano(),
 		Pids:      pidStats,
@@ -398,6 +458,16 @@ func (l *LibcontainerExecutor) Signal(s os.Signal) error {
 	return l.userProc.Signal(s)
 }
 
+// Checkpoint dumps the container of the user process into dir and stops it
+func (l *LibcontainerExecutor) Checkpoint(dir string) error {
+	if l.container == nil {
+		return fmt.Errorf("no container to checkpoint")
+	}
+	return l.container.Checkpoint(&libcontainer.CriuOpts{
+		ImagesDirectory: dir,
+	})
+}
+
 // Exec starts an additional process inside the container
 func (l *LibcontainerExecutor) Exec(deadline time.Time, cmd string, args []string) ([]byte, int, error) {
 	combined := append([]string{cmd}, args...)
@@ -549,6 +619,16 @@ func configureCgroups(cfg *lconfigs.Config, command *ExecCommand) error {
 	// Set the relative CPU shares for this cgroup.
 	cfg.Cgroups.Resources.CpuShares = uint64(command.Resources.CPU)
 
+	// Hard limit the CPU bandwidth if requested
+	if command.Resources.CPUQuota > 0 {
+		cfg.Cgroups.Resources.CpuPeriod = uint64(command.Resources.CPUPeriod)
+		cfg.Cgroups.Resources.CpuQuota = command.Resources.CPUQuota
+	}
+
+	// Restrict the task to the cores and memory nodes chosen by the client
+	cfg.Cgroups.Resources.CpusetCpus = command.Resources.CpusetCPUs
+	cfg.Cgroups.Resources.CpusetMems = command.Resources.CpusetMems
+
 	if command.Resources.IOPS != 0 {
 		// Validate it is in an acceptable range.
 		if command.Resources.IOPS < 10 || command.Resources.IOPS > 1000 {
@@ -557,6 +637,40 @@ func configureCgroups(cfg *lconfigs.Config, command *ExecCommand) error {
 
 		cfg.Cgroups.Resources.BlkioWeight = uint16(command.Resources.IOPS)
 	}
+
+	if command.Resources.IOWeight != 0 {
+		cfg.Cgroups.Resources.BlkioWeight = uint16(command.Resources.IOWeight)
+	}
+	return configureIOLimits(cfg.Cgroups.Resources, command.Resources.IOLimits)
+}
+
+// configureIOLimits adds the block I/O limits of the task to the throttled
+// devices of the cgroup, resolving each device path to its device numbers.
+func configureIOLimits(r *lconfigs.Resources, limits []*IOLimit) error {
+	for _, l := range limits {
+		var st unix.Stat_t
+		if err := unix.Stat(l.Device, &st); err != nil {
+			return fmt.Errorf("failed to stat device %q: %v", l.Device, err)
+		}
+		if st.Mode&unix.S_IFMT != unix.S_IFBLK {
+			return fmt.Errorf("%q is not a block device", l.Device)
+		}
+
+		major := int64(unix.Major(uint64(st.Rdev)))
+		minor := int64(unix.Minor(uint64(st.Rdev)))
+		if l.ReadBps > 0 {
+			r.BlkioThrottleReadBpsDevice = append(r.BlkioThrottleReadBpsDevice, lconfigs.NewThrottleDevice(major, minor, uint64(l.ReadBps)))
+		}
+		if l.WriteBps > 0 {
+			r.BlkioThrottleWriteBpsDevice = append(r.BlkioThrottleWriteBpsDevice, lconfigs.NewThrottleDevice(major, minor, uint64(l.WriteBps)))
+		}
+		if l.ReadIOps > 0 {
+			r.BlkioThrottleReadIOPSDevice = append(r.BlkioThrottleReadIOPSDevice, lconfigs.NewThrottleDevice(major, minor, uint64(l.ReadIOps)))
+		}
+		if l.WriteIOps > 0 {
+			r.BlkioThrottleWriteIOPSDevice = append(r.BlkioThrottleWriteIOPSDevice, lconfigs.NewThrottleDevice(major, minor, uint64(l.WriteIOps)))
+		}
+	}
 	return nil
 }
 
@@ -565,6 +679,16 @@ func configureBasicCgroups(cfg *lconfigs.Config) error {
 
 	// Manually create freezer cgroup
 	cfg.Cgroups.Paths = map[string]string{}
+	if cgutil.UseV2 {
+		// The unified hierarchy has a single cgroup for all controllers
+		path := cgutil.CgroupPath(defaultCgroupParent, id)
+		if err := cgutil.CreateCgroup(path); err != nil {
+			return err
+		}
+		cfg.Cgroups.Paths[""] = path
+		return nil
+	}
+
 	root, err := cgroups.FindCgroupMountpointDir()
 	if err != nil {
 		return err
@@ -605,12 +729,79 @@ func newLibcontainerConfig(command *ExecCommand) *lconfigs.Config {
 
 	configureCapabilities(cfg, command)
 	configureIsolation(cfg, command)
+	configureUserNamespace(cfg, command)
+	configureNetworkNamespace(cfg, command)
 	configureCgroups(cfg, command)
 	return cfg
 }
 
+// configureNetworkNamespace joins the network namespace of the allocation if
+// it has one
+func configureNetworkNamespace(cfg *lconfigs.Config, command *ExecCommand) {
+	if command.NetworkNamespace == "" {
+		return
+	}
+	cfg.Namespaces = append(cfg.Namespaces, lconfigs.Namespace{
+		Type: lconfigs.NEWNET,
+		Path: command.NetworkNamespace,
+	})
+}
+
+// configureUserNamespace runs the process in a new user namespace if ID maps
+// are given, so root inside the container is an unprivileged user on the host
+func configureUserNamespace(cfg *lconfigs.Config, command *ExecCommand) {
+	if len(command.UIDMap) == 0 && len(command.GIDMap) == 0 {
+		return
+	}
+
+	// mqueue can only be mounted by a user namespace owning the IPC namespace
+	cfg.Namespaces = append(cfg.Namespaces,
+		lconfigs.Namespace{Type: lconfigs.NEWUSER},
+		lconfigs.Namespace{Type: lconfigs.NEWIPC},
+	)
+	cfg.UidMappings = toLibcontainerIDMaps(command.UIDMap)
+	cfg.GidMappings = toLibcontainerIDMaps(command.GIDMap)
+
+	// sysfs can only be mounted by a user namespace owning the network
+	// namespace, so bind mount the host's instead
+	for _, m := range cfg.Mounts {
+		if m.Device == "sysfs" {
+			m.Source = "/sys"
+			m.Device = "bind"
+			m.Flags = syscall.MS_BIND | syscall.MS_REC | syscall.MS_RDONLY
+		}
+	}
+}
+
+func toLibcontainerIDMaps(maps []IDMap) []lconfigs.IDMap {
+	out := make([]lconfigs.IDMap, len(maps))
+	for i, m := range maps {
+		out[i] = lconfigs.IDMap{
+			ContainerID: m.ContainerID,
+			HostID:      m.HostID,
+			Size:        m.Size,
+		}
+	}
+	return out
+}
+
+// cgroupsFactory configures a libcontainer factory to manage cgroups through
+// the cgroup filesystem, using the unified hierarchy when the host has no v1
+// hierarchies.
+func cgroupsFactory(l *libcontainer.LinuxFactory) error {
+	if !cgutil.UseV2 {
+		return libcontainer.Cgroupfs(l)
+	}
+	l.NewCgroupsManager = cgutil.NewManager
+	return nil
+}
+
 // JoinRootCgroup moves the current process to the cgroups of the init process
 func JoinRootCgroup(subsystems []string) error {
+	if cgutil.UseV2 {
+		return cgroups.EnterPid(map[string]string{"": cgutil.CgroupRoot}, os.Getpid())
+	}
+
 	mErrs := new(multierror.Error)
 	paths := map[string]string{}
 	for _, s := range subsystems {
@@ -18,6 +18,7 @@ import (
 	"github.com/hashicorp/nomad/helper/testlog"
 	"github.com/hashicorp/nomad/nomad/mock"
 	tu "github.com/hashicorp/nomad/testutil"
+	lconfigs "github.com/opencontainers/runc/libcontainer/configs"
 	"github.com/stretchr/testify/require"
 )
 
@@ -182,3 +183,26 @@ func TestExecutor_ClientCleanup(t *testing.T) {
 	output1 := execCmd.stdout.(*bufferCloser).String()
 	require.Equal(len(output), len(output1))
 }
+
+func TestConfigureIOLimits(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	r := &lconfigs.Resources{}
+	err := configureIOLimits(r, []*IOLimit{{Device: "/dev/null", ReadBps: 1024}})
+	require.Error(err)
+	require.Contains(err.Error(), "not a block device")
+
+	err = configureIOLimits(r, []*IOLimit{{Device: "/dev/does-not-exist", ReadBps: 1024}})
+	require.Error(err)
+
+	if _, err := os.Stat("/dev/loop0"); err != nil {
+		t.Skip("no block device to throttle")
+	}
+	limits := []*IOLimit{{Device: "/dev/loop0", ReadBps: 1024, WriteIOps: 10}}
+	require.NoError(configureIOLimits(r, limits))
+	require.Equal("7:0 1024", r.BlkioThrottleReadBpsDevice[0].String())
+	require.Equal("7:0 10", r.BlkioThrottleWriteIOPSDevice[0].String())
+	require.Empty(r.BlkioThrottleWriteBpsDevice)
+	require.Empty(r.BlkioThrottleReadIOPSDevice)
+}
@@ -8,6 +8,7 @@ import (
 	"syscall"
 
 	multierror "github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/client/lib/cgutil"
 	"github.com/hashicorp/nomad/helper"
 	"github.com/opencontainers/runc/libcontainer/cgroups"
 	cgroupFs "github.com/opencontainers/runc/libcontainer/cgroups/fs"
@@ -28,7 +29,7 @@ func (e *UniversalExecutor) runAs(userid string) error {
 		return fmt.Errorf("Unable to lookup user's group membership: %v", err)
 	}
 
-	gids := make([]uint32, len(gidStrings))
+	gids := make([]uint32, 0, len(gidStrings))
 	for _, gidString := range gidStrings {
 		u, err := strconv.Atoi(gidString)
 		if err != nil {
@@ -87,6 +88,9 @@ func DestroyCgroup(groups *lconfigs.Cgroup, executorPid int) error {
 	if groups == nil {
 		return fmt.Errorf("Can't destroy: cgroup configuration empty")
 	}
+	if cgutil.UseV2 {
+		return destroyCgroupV2(groups.Paths[""], executorPid)
+	}
 
 	// Move the executor into the global cgroup so that the task specific
 	// cgroup can be destroyed.
@@ -154,3 +158,62 @@ func DestroyCgroup(groups *lconfigs.Cgroup, executorPid int) error {
 	}
 	return mErrs.ErrorOrNil()
 }
+
+// destroyCgroupV2 kills all processes in the cgroup at path of the unified
+// hierarchy and removes it.
+func destroyCgroupV2(path string, executorPid int) error {
+	mErrs := new(multierror.Error)
+	if path == "" {
+		return fmt.Errorf("Can't destroy: cgroup path empty")
+	}
+
+	// Move the executor into the root cgroup so that the task specific
+	// cgroup can be destroyed.
+	if err := cgroups.EnterPid(map[string]string{"": cgutil.CgroupRoot}, executorPid); err != nil {
+		return err
+	}
+
+	// Freeze the Cgroup so that it can not continue to fork/exec.
+	if err := cgutil.Freeze(path, lconfigs.Frozen); err != nil {
+		return err
+	}
+
+	var procs []*os.Process
+	pids, err := cgroups.GetAllPids(path)
+	if err != nil {
+		multierror.Append(mErrs, fmt.Errorf("error getting pids: %v", err))
+	}
+
+	// Kill the processes in the cgroup
+	for _, pid := range pids {
+		proc, err := os.FindProcess(pid)
+		if err != nil {
+			multierror.Append(mErrs, fmt.Errorf("error finding process %v: %v", pid, err))
+			continue
+		}
+
+		procs = append(procs, proc)
+		if e := proc.Kill(); e != nil {
+			multierror.Append(mErrs, fmt.Errorf("error killing process %v: %v", pid, e))
+		}
+	}
+
+	// Unfreeze the cgroup so we can wait.
+	if err := cgutil.Freeze(path, lconfigs.Thawed); err != nil {
+		multierror.Append(mErrs, fmt.Errorf("failed to unfreeze cgroup: %v", err))
+		return mErrs.ErrorOrNil()
+	}
+
+	// Wait on the killed processes to ensure they are cleaned up.
+	for _, proc := range procs {
+		// Don't capture the error because we expect this to fail for
+		// processes we didn't fork.
+		proc.Wait()
+	}
+
+	// Remove the cgroup.
+	if err := cgroups.RemovePaths(map[string]string{"": path}); err != nil {
+		multierror.Append(mErrs, fmt.Errorf("failed to delete the cgroup directories: %v", err))
+	}
+	return mErrs.ErrorOrNil()
+}
@@ -17,6 +17,11 @@ func (e *UniversalExecutor) setNewProcessGroup() error {
 	return nil
 }
 
+// start starts the user process
+func (e *UniversalExecutor) start() error {
+	return e.childCmd.Start()
+}
+
 // Cleanup any still hanging user processes
 func (e *UniversalExecutor) cleanupChildProcesses(proc *os.Process) error {
 	// If new process group was created upon command execution
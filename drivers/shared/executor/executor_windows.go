@@ -58,10 +58,15 @@ func sendCtrlBreak(pid int) error {
 }
 
 // Send the process a Ctrl-Break event, allowing it to shutdown by itself
-// before being Terminate.
+// before being Terminate. Processes without a console cannot receive the
+// event, so they are terminated right away.
 func (e *UniversalExecutor) shutdownProcess(_ os.Signal, proc *os.Process) error {
 	if err := sendCtrlBreak(proc.Pid); err != nil {
-		return fmt.Errorf("executor shutdown error: %v", err)
+		e.logger.Warn("failed to send Ctrl-Break to process, terminating it", "pid", proc.Pid, "error", err)
+		if err := proc.Kill(); err != nil {
+			return fmt.Errorf("executor shutdown error: %v", err)
+		}
+		return nil
 	}
 	e.logger.Info("sent Ctrl-Break to process", "pid", proc.Pid)
 
@@ -1,4 +1,4 @@
-// +build darwin dragonfly freebsd netbsd openbsd solaris windows
+// +build darwin dragonfly freebsd netbsd openbsd solaris
 
 package executor
 
@@ -10,3 +10,5 @@ type resourceContainerContext struct {
 func (rc *resourceContainerContext) executorCleanup() error {
 	return nil
 }
+
+func (e *UniversalExecutor) configureResourceContainer(_ int) error { return nil }
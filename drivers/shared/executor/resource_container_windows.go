@@ -0,0 +1,200 @@
+package executor
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"syscall"
+	"unsafe"
+
+	shelpers "github.com/hashicorp/nomad/helper/stats"
+)
+
+var (
+	kernel32 = syscall.NewLazyDLL("kernel32.dll")
+	ntdll    = syscall.NewLazyDLL("ntdll.dll")
+
+	procCreateJobObjectW         = kernel32.NewProc("CreateJobObjectW")
+	procSetInformationJobObject  = kernel32.NewProc("SetInformationJobObject")
+	procAssignProcessToJobObject = kernel32.NewProc("AssignProcessToJobObject")
+	procTerminateJobObject       = kernel32.NewProc("TerminateJobObject")
+	procNtResumeProcess          = ntdll.NewProc("NtResumeProcess")
+)
+
+const (
+	// job object information classes
+	jobObjectExtendedLimitInformationClass  = 9
+	jobObjectCpuRateControlInformationClass = 15
+
+	// job object limit flags
+	jobObjectLimitJobMemory      = 0x00000200
+	jobObjectLimitKillOnJobClose = 0x00002000
+
+	// job object CPU rate control flags
+	jobObjectCpuRateControlEnable  = 0x1
+	jobObjectCpuRateControlHardCap = 0x4
+
+	// createSuspended creates a process with its main thread suspended
+	createSuspended = 0x00000004
+
+	// process access rights required to assign a process to a job object
+	// and resume it
+	processSetQuota      = 0x0100
+	processSuspendResume = 0x0800
+)
+
+type jobObjectBasicLimitInformation struct {
+	PerProcessUserTimeLimit int64
+	PerJobUserTimeLimit     int64
+	LimitFlags              uint32
+	MinimumWorkingSetSize   uintptr
+	MaximumWorkingSetSize   uintptr
+	ActiveProcessLimit      uint32
+	Affinity                uintptr
+	PriorityClass           uint32
+	SchedulingClass         uint32
+}
+
+type ioCounters struct {
+	ReadOperationCount  uint64
+	WriteOperationCount uint64
+	OtherOperationCount uint64
+	ReadTransferCount   uint64
+	WriteTransferCount  uint64
+	OtherTransferCount  uint64
+}
+
+type jobObjectExtendedLimitInformation struct {
+	BasicLimitInformation jobObjectBasicLimitInformation
+	IoInfo                ioCounters
+	ProcessMemoryLimit    uintptr
+	JobMemoryLimit        uintptr
+	PeakProcessMemoryUsed uintptr
+	PeakJobMemoryUsed     uintptr
+}
+
+type jobObjectCpuRateControlInformation struct {
+	ControlFlags uint32
+	CpuRate      uint32
+}
+
+// resourceContainerContext is a platform-specific struct for managing a
+// resource container. In the case of Windows, this is a job object that
+// contains the user process and all processes it starts.
+type resourceContainerContext struct {
+	job  syscall.Handle
+	lock sync.Mutex
+}
+
+// executorCleanup terminates all processes in the job object and closes it
+func (rc *resourceContainerContext) executorCleanup() error {
+	rc.lock.Lock()
+	defer rc.lock.Unlock()
+
+	if rc.job == 0 {
+		return nil
+	}
+
+	var err error
+	if r, _, e := procTerminateJobObject.Call(uintptr(rc.job), 1); r == 0 {
+		err = fmt.Errorf("failed to terminate job object: %v", e)
+	}
+	syscall.CloseHandle(rc.job)
+	rc.job = 0
+	return err
+}
+
+// configureResourceContainer creates the job object the user process is
+// started in, applying resource limits if enabled. The process is created
+// suspended so it cannot start any processes before it is in the job object.
+func (e *UniversalExecutor) configureResourceContainer(_ int) error {
+	if !(e.commandCfg.ResourceLimits || e.commandCfg.BasicProcessCgroup) {
+		return nil
+	}
+
+	r, _, err := procCreateJobObjectW.Call(0, 0)
+	if r == 0 {
+		return fmt.Errorf("failed to create job object: %v", err)
+	}
+	job := syscall.Handle(r)
+
+	// Kill the processes in the job if the executor exits without cleanup
+	limits := jobObjectExtendedLimitInformation{}
+	limits.BasicLimitInformation.LimitFlags = jobObjectLimitKillOnJobClose
+
+	res := e.commandCfg.Resources
+	if e.commandCfg.ResourceLimits && res != nil && res.MemoryMB > 0 {
+		limits.BasicLimitInformation.LimitFlags |= jobObjectLimitJobMemory
+		limits.JobMemoryLimit = uintptr(res.MemoryMB) * 1024 * 1024
+	}
+
+	if err := setInformationJobObject(job, jobObjectExtendedLimitInformationClass,
+		unsafe.Pointer(&limits), unsafe.Sizeof(limits)); err != nil {
+		syscall.CloseHandle(job)
+		return fmt.Errorf("failed to set job object limits: %v", err)
+	}
+
+	// Cap the CPU at the task's share of all cores, in 1/100th of a percent
+	total := shelpers.TotalTicksAvailable()
+	if e.commandCfg.ResourceLimits && res != nil && res.CPU > 0 && total > 0 {
+		rate := uint32(float64(res.CPU) / total * 10000)
+		if rate < 1 {
+			rate = 1
+		}
+		if rate < 10000 {
+			cpu := jobObjectCpuRateControlInformation{
+				ControlFlags: jobObjectCpuRateControlEnable | jobObjectCpuRateControlHardCap,
+				CpuRate:      rate,
+			}
+			if err := setInformationJobObject(job, jobObjectCpuRateControlInformationClass,
+				unsafe.Pointer(&cpu), unsafe.Sizeof(cpu)); err != nil {
+				// CPU rate control requires Windows 8 or Server 2012
+				e.logger.Warn("failed to set job object CPU limit", "error", err)
+			}
+		}
+	}
+
+	e.resConCtx.job = job
+	e.childCmd.SysProcAttr.CreationFlags |= createSuspended
+	return nil
+}
+
+// start starts the user process. If a job object was created, the process is
+// assigned to it before being resumed.
+func (e *UniversalExecutor) start() error {
+	if err := e.childCmd.Start(); err != nil {
+		return err
+	}
+
+	job := e.resConCtx.job
+	if job == 0 {
+		return nil
+	}
+
+	access := uint32(syscall.PROCESS_TERMINATE | processSetQuota | processSuspendResume)
+	proc, err := syscall.OpenProcess(access, false, uint32(e.childCmd.Process.Pid))
+	if err != nil {
+		e.childCmd.Process.Kill()
+		return os.NewSyscallError("OpenProcess", err)
+	}
+	defer syscall.CloseHandle(proc)
+
+	if r, _, err := procAssignProcessToJobObject.Call(uintptr(job), uintptr(proc)); r == 0 {
+		e.childCmd.Process.Kill()
+		return fmt.Errorf("failed to assign process to job object: %v", err)
+	}
+
+	if r, _, _ := procNtResumeProcess.Call(uintptr(proc)); r != 0 {
+		e.childCmd.Process.Kill()
+		return fmt.Errorf("failed to resume process: NTSTATUS 0x%x", r)
+	}
+	return nil
+}
+
+func setInformationJobObject(job syscall.Handle, class uint32, info unsafe.Pointer, size uintptr) error {
+	r, _, err := procSetInformationJobObject.Call(uintptr(job), uintptr(class), uintptr(info), size)
+	if r == 0 {
+		return err
+	}
+	return nil
+}
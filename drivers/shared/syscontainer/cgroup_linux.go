@@ -0,0 +1,95 @@
+// +build linux
+
+package syscontainer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/hashicorp/nomad/client/lib/cgutil"
+	"github.com/opencontainers/runc/libcontainer/cgroups"
+)
+
+// delegatedCgroupFiles are the files of a cgroup that must be owned by the
+// container for its init to create and manage sub-cgroups
+var delegatedCgroupFiles = []string{
+	"cgroup.procs",
+	"tasks",
+	"cgroup.clone_children",
+}
+
+// delegatedCgroupFilesV2 are the files of a cgroup in the unified hierarchy
+// that must be owned by the container to manage sub-cgroups
+var delegatedCgroupFilesV2 = []string{
+	"cgroup.procs",
+	"cgroup.subtree_control",
+	"cgroup.threads",
+}
+
+// CgroupPaths returns the paths of the cgroup named name below parent in the
+// hierarchy of each of the given subsystems. Subsystems that are not mounted
+// on the host are skipped. On hosts using the unified hierarchy a single path
+// is returned under the empty key.
+func CgroupPaths(parent, name string, subsystems []string) (map[string]string, error) {
+	if cgutil.UseV2 {
+		return map[string]string{"": cgutil.CgroupPath(parent, name)}, nil
+	}
+
+	paths := make(map[string]string, len(subsystems))
+	for _, s := range subsystems {
+		mnt, err := cgroups.FindCgroupMountpoint(s)
+		if err != nil {
+			if cgroups.IsNotFound(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to find cgroup mount point of %q: %v", s, err)
+		}
+		paths[s] = filepath.Join(mnt, parent, name)
+	}
+	return paths, nil
+}
+
+// DelegateCgroup creates the given cgroups and hands them to the given user
+// and group, which should be the host IDs root of the container is mapped
+// to. This allows the init of an unprivileged system container to manage its
+// own sub-cgroups while the driver keeps enforcing the limits set on the
+// delegated cgroups.
+func DelegateCgroup(paths map[string]string, uid, gid int) error {
+	files := delegatedCgroupFiles
+	if cgutil.UseV2 {
+		files = delegatedCgroupFilesV2
+	}
+
+	for s, path := range paths {
+		if cgutil.UseV2 {
+			if err := cgutil.CreateCgroup(path); err != nil {
+				return err
+			}
+		} else if err := os.MkdirAll(path, 0755); err != nil {
+			return fmt.Errorf("failed to create %s cgroup: %v", s, err)
+		}
+		if err := os.Chown(path, uid, gid); err != nil {
+			return fmt.Errorf("failed to delegate %s cgroup: %v", s, err)
+		}
+
+		for _, f := range files {
+			err := os.Chown(filepath.Join(path, f), uid, gid)
+			if err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("failed to delegate %s cgroup: %v", s, err)
+			}
+		}
+	}
+	return nil
+}
+
+// RemoveCgroup removes the given cgroups along with any sub-cgroups the
+// container created in them. The cgroups must not contain any processes.
+func RemoveCgroup(paths map[string]string) error {
+	// RemovePaths deletes the paths it removed from the map it is given
+	remaining := make(map[string]string, len(paths))
+	for s, path := range paths {
+		remaining[s] = path
+	}
+	return cgroups.RemovePaths(remaining)
+}
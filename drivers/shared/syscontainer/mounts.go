@@ -0,0 +1,76 @@
+// Package syscontainer provides helpers for driver plugins that run system
+// containers, such as LXC, where the driver provisions a full root filesystem
+// and the container runs its own init. It covers sharing the task directories
+// with the container, provisioning the rootfs from an artifact, delegating
+// cgroups and collecting stats of containers recovered after a restart.
+package syscontainer
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/nomad/client/allocdir"
+)
+
+// Mount is a bind mount of a host path into the container
+type Mount struct {
+	// Source is the absolute path on the host
+	Source string
+
+	// Destination is the path inside the container, relative to its root
+	Destination string
+
+	// ReadOnly mounts the source read only
+	ReadOnly bool
+}
+
+// TaskMounts returns the mounts that share the task's local, alloc and
+// secrets directories with the container. They are mounted at the same paths
+// tasks of every other driver find them at.
+func TaskMounts(taskDir *allocdir.TaskDir) []Mount {
+	return []Mount{
+		{Source: taskDir.LocalDir, Destination: allocdir.TaskLocal},
+		{Source: taskDir.SharedAllocDir, Destination: allocdir.SharedAllocName},
+		{Source: taskDir.SecretsDir, Destination: allocdir.TaskSecrets},
+	}
+}
+
+// ParseVolumes parses volumes given as "<host_path>:<container_path>" with
+// an optional ":ro" suffix. Relative host paths are relative to the task
+// directory. Absolute host paths are only allowed if allowAbsolute is set.
+func ParseVolumes(volumes []string, taskDir string, allowAbsolute bool) ([]Mount, error) {
+	mounts := make([]Mount, 0, len(volumes))
+	for _, v := range volumes {
+		parts := strings.Split(v, ":")
+		readOnly := false
+		if len(parts) == 3 && parts[2] == "ro" {
+			readOnly = true
+			parts = parts[:2]
+		}
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("invalid volume %q: expected <host_path>:<container_path>[:ro]", v)
+		}
+
+		source := parts[0]
+		if filepath.IsAbs(source) {
+			if !allowAbsolute {
+				return nil, fmt.Errorf("invalid volume %q: absolute host paths are disabled", v)
+			}
+		} else {
+			source = filepath.Join(taskDir, source)
+		}
+
+		dest := strings.TrimPrefix(filepath.Clean("/"+parts[1]), "/")
+		if dest == "" {
+			return nil, fmt.Errorf("invalid volume %q: cannot mount over the container root", v)
+		}
+
+		mounts = append(mounts, Mount{
+			Source:      source,
+			Destination: dest,
+			ReadOnly:    readOnly,
+		})
+	}
+	return mounts, nil
+}
@@ -0,0 +1,42 @@
+package syscontainer
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/client/allocdir"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskMounts(t *testing.T) {
+	taskDir := &allocdir.TaskDir{
+		LocalDir:       "/alloc/web/local",
+		SharedAllocDir: "/alloc/alloc",
+		SecretsDir:     "/alloc/web/secrets",
+	}
+
+	require.Equal(t, []Mount{
+		{Source: "/alloc/web/local", Destination: "local"},
+		{Source: "/alloc/alloc", Destination: "alloc"},
+		{Source: "/alloc/web/secrets", Destination: "secrets"},
+	}, TaskMounts(taskDir))
+}
+
+func TestParseVolumes(t *testing.T) {
+	require := require.New(t)
+
+	mounts, err := ParseVolumes([]string{"data:/var/lib/data", "/etc/ssl:/etc/ssl:ro"}, "/alloc/web", true)
+	require.NoError(err)
+	require.Equal([]Mount{
+		{Source: "/alloc/web/data", Destination: "var/lib/data"},
+		{Source: "/etc/ssl", Destination: "etc/ssl", ReadOnly: true},
+	}, mounts)
+
+	_, err = ParseVolumes([]string{"/etc/ssl:/etc/ssl"}, "/alloc/web", false)
+	require.Error(err)
+	require.Contains(err.Error(), "absolute host paths are disabled")
+
+	for _, invalid := range []string{"data", "data:", ":/data", "data:/", "data:/data:rw"} {
+		_, err := ParseVolumes([]string{invalid}, "/alloc/web", true)
+		require.Error(err, invalid)
+	}
+}
@@ -0,0 +1,121 @@
+// +build linux
+
+package syscontainer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	securejoin "github.com/cyphar/filepath-securejoin"
+)
+
+// ResolveRootfs returns the absolute path of a root filesystem unpacked from
+// an artifact, given relative to the task directory. The path may not escape
+// the task directory and the rootfs must contain the init the container runs,
+// given relative to the rootfs.
+func ResolveRootfs(taskDir, rootfs, init string) (string, error) {
+	if filepath.IsAbs(rootfs) {
+		return "", fmt.Errorf("rootfs %q must be relative to the task directory", rootfs)
+	}
+
+	path := filepath.Join(taskDir, rootfs)
+	if path != taskDir && !strings.HasPrefix(path, taskDir+string(filepath.Separator)) {
+		return "", fmt.Errorf("rootfs %q escapes the task directory", rootfs)
+	}
+
+	fi, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to find rootfs: %v", err)
+	}
+	if !fi.IsDir() {
+		return "", fmt.Errorf("rootfs %q is not a directory", rootfs)
+	}
+
+	if init != "" {
+		initPath, err := securejoin.SecureJoin(path, init)
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve init %q: %v", init, err)
+		}
+		if _, err := os.Stat(initPath); err != nil {
+			return "", fmt.Errorf("failed to find init in rootfs: %v", err)
+		}
+	}
+	return path, nil
+}
+
+// PrepareRootfs creates the mount points of the given mounts inside the
+// rootfs. Mount points are resolved inside the rootfs, so symlinks in it
+// cannot point them at paths on the host.
+func PrepareRootfs(rootfs string, mounts []Mount) error {
+	for _, m := range mounts {
+		dest, err := securejoin.SecureJoin(rootfs, m.Destination)
+		if err != nil {
+			return fmt.Errorf("failed to resolve mount point %q: %v", m.Destination, err)
+		}
+
+		fi, err := os.Stat(m.Source)
+		if err != nil {
+			return fmt.Errorf("failed to find mount source %q: %v", m.Source, err)
+		}
+
+		if fi.IsDir() {
+			if err := os.MkdirAll(dest, 0755); err != nil {
+				return fmt.Errorf("failed to create mount point %q: %v", m.Destination, err)
+			}
+			continue
+		}
+
+		// Files are bind mounted onto an empty file
+		if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+			return fmt.Errorf("failed to create mount point %q: %v", m.Destination, err)
+		}
+		f, err := os.OpenFile(dest, os.O_CREATE, 0644)
+		if err != nil {
+			return fmt.Errorf("failed to create mount point %q: %v", m.Destination, err)
+		}
+		f.Close()
+	}
+	return nil
+}
+
+// ShiftOwnership shifts the owners of all files of the rootfs by the given
+// offsets, so that a rootfs unpacked by the client is owned by the users of
+// an unprivileged container whose IDs are mapped starting at the offsets.
+// IDs that are already at or above the offset are left alone, so the rootfs
+// of a restarted task is not shifted twice.
+func ShiftOwnership(rootfs string, uidOffset, gidOffset int) error {
+	return filepath.Walk(rootfs, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		st, ok := fi.Sys().(*syscall.Stat_t)
+		if !ok {
+			return fmt.Errorf("failed to read owner of %q", path)
+		}
+
+		uid, gid := int(st.Uid), int(st.Gid)
+		if uid < uidOffset {
+			uid += uidOffset
+		}
+		if gid < gidOffset {
+			gid += gidOffset
+		}
+		if uid == int(st.Uid) && gid == int(st.Gid) {
+			return nil
+		}
+
+		if err := os.Lchown(path, uid, gid); err != nil {
+			return err
+		}
+
+		// Changing the owner clears the setuid and setgid bits
+		if fi.Mode()&(os.ModeSetuid|os.ModeSetgid) != 0 && fi.Mode()&os.ModeSymlink == 0 {
+			return os.Chmod(path, fi.Mode())
+		}
+		return nil
+	})
+}
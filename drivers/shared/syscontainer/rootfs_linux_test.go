@@ -0,0 +1,102 @@
+package syscontainer
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveRootfs(t *testing.T) {
+	require := require.New(t)
+
+	taskDir, err := ioutil.TempDir("", "syscontainer")
+	require.NoError(err)
+	defer os.RemoveAll(taskDir)
+
+	require.NoError(os.MkdirAll(filepath.Join(taskDir, "local/rootfs/sbin"), 0755))
+	require.NoError(ioutil.WriteFile(filepath.Join(taskDir, "local/rootfs/sbin/init"), nil, 0755))
+
+	path, err := ResolveRootfs(taskDir, "local/rootfs", "/sbin/init")
+	require.NoError(err)
+	require.Equal(filepath.Join(taskDir, "local/rootfs"), path)
+
+	_, err = ResolveRootfs(taskDir, "local/rootfs", "/bin/systemd")
+	require.Error(err)
+
+	_, err = ResolveRootfs(taskDir, "../rootfs", "")
+	require.Error(err)
+	require.Contains(err.Error(), "escapes")
+
+	_, err = ResolveRootfs(taskDir, "/", "")
+	require.Error(err)
+}
+
+func TestPrepareRootfs(t *testing.T) {
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "syscontainer")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	rootfs := filepath.Join(dir, "rootfs")
+	require.NoError(os.MkdirAll(rootfs, 0755))
+	require.NoError(ioutil.WriteFile(filepath.Join(dir, "file"), nil, 0644))
+
+	// A symlink in the rootfs must not place mount points outside of it
+	outside := filepath.Join(dir, "outside")
+	require.NoError(os.Symlink(outside, filepath.Join(rootfs, "escape")))
+
+	mounts := []Mount{
+		{Source: dir, Destination: "local"},
+		{Source: filepath.Join(dir, "file"), Destination: "etc/file"},
+		{Source: dir, Destination: "escape/data"},
+	}
+	require.NoError(PrepareRootfs(rootfs, mounts))
+
+	fi, err := os.Stat(filepath.Join(rootfs, "local"))
+	require.NoError(err)
+	require.True(fi.IsDir())
+
+	fi, err = os.Stat(filepath.Join(rootfs, "etc/file"))
+	require.NoError(err)
+	require.True(fi.Mode().IsRegular())
+
+	_, err = os.Stat(outside)
+	require.True(os.IsNotExist(err))
+}
+
+func TestShiftOwnership(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("Must be run as root")
+	}
+	require := require.New(t)
+
+	rootfs, err := ioutil.TempDir("", "syscontainer")
+	require.NoError(err)
+	defer os.RemoveAll(rootfs)
+
+	file := filepath.Join(rootfs, "file")
+	require.NoError(ioutil.WriteFile(file, nil, 0644))
+
+	owner := func(path string) (uint32, uint32) {
+		fi, err := os.Lstat(path)
+		require.NoError(err)
+		st := fi.Sys().(*syscall.Stat_t)
+		return st.Uid, st.Gid
+	}
+
+	require.NoError(ShiftOwnership(rootfs, 100000, 200000))
+	uid, gid := owner(file)
+	require.EqualValues(100000, uid)
+	require.EqualValues(200000, gid)
+
+	// Shifting again must not change the owners
+	require.NoError(ShiftOwnership(rootfs, 100000, 200000))
+	uid, gid = owner(rootfs)
+	require.EqualValues(100000, uid)
+	require.EqualValues(200000, gid)
+}
@@ -0,0 +1,89 @@
+// +build linux
+
+package syscontainer
+
+import (
+	"time"
+
+	"github.com/hashicorp/nomad/client/lib/cgutil"
+	"github.com/hashicorp/nomad/client/stats"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/opencontainers/runc/libcontainer/cgroups"
+	cgroupFs "github.com/opencontainers/runc/libcontainer/cgroups/fs"
+)
+
+var (
+	// MeasuredMemStats are the memory stats measured from cgroups
+	MeasuredMemStats = []string{"RSS", "Cache", "Swap", "Max Usage", "Kernel Usage", "Kernel Max Usage"}
+
+	// MeasuredCpuStats are the CPU stats measured from cgroups
+	MeasuredCpuStats = []string{"System Mode", "User Mode", "Throttled Periods", "Throttled Time", "Percent"}
+)
+
+// StatsCollector collects the resource usage of a container from its
+// cgroups. As it only needs the cgroup paths, which drivers can persist in
+// their task state, it works the same for containers recovered after the
+// driver restarted.
+type StatsCollector struct {
+	manager cgroups.Manager
+
+	totalCpuStats  *stats.CpuStats
+	userCpuStats   *stats.CpuStats
+	systemCpuStats *stats.CpuStats
+}
+
+// NewStatsCollector returns a StatsCollector for the cgroups at the given
+// paths, keyed by subsystem as returned by CgroupPaths.
+func NewStatsCollector(paths map[string]string) *StatsCollector {
+	var manager cgroups.Manager = &cgroupFs.Manager{Paths: paths}
+	if cgutil.UseV2 {
+		manager = cgutil.NewManager(nil, paths)
+	}
+
+	return &StatsCollector{
+		manager:        manager,
+		totalCpuStats:  stats.NewCpuStats(),
+		userCpuStats:   stats.NewCpuStats(),
+		systemCpuStats: stats.NewCpuStats(),
+	}
+}
+
+// Collect returns the current resource usage of the container.
+func (c *StatsCollector) Collect() (*cstructs.TaskResourceUsage, error) {
+	cs, err := c.manager.GetStats()
+	if err != nil {
+		return nil, err
+	}
+	return c.toTaskResourceUsage(cs, time.Now()), nil
+}
+
+func (c *StatsCollector) toTaskResourceUsage(cs *cgroups.Stats, ts time.Time) *cstructs.TaskResourceUsage {
+	ms := &cstructs.MemoryStats{
+		RSS:            cs.MemoryStats.Stats["rss"],
+		Cache:          cs.MemoryStats.Stats["cache"],
+		Swap:           cs.MemoryStats.SwapUsage.Usage,
+		MaxUsage:       cs.MemoryStats.Usage.MaxUsage,
+		KernelUsage:    cs.MemoryStats.KernelUsage.Usage,
+		KernelMaxUsage: cs.MemoryStats.KernelUsage.MaxUsage,
+		Measured:       MeasuredMemStats,
+	}
+
+	totalPercent := c.totalCpuStats.Percent(float64(cs.CpuStats.CpuUsage.TotalUsage))
+	cpu := &cstructs.CpuStats{
+		SystemMode:       c.systemCpuStats.Percent(float64(cs.CpuStats.CpuUsage.UsageInKernelmode)),
+		UserMode:         c.userCpuStats.Percent(float64(cs.CpuStats.CpuUsage.UsageInUsermode)),
+		Percent:          totalPercent,
+		ThrottledPeriods: cs.CpuStats.ThrottlingData.ThrottledPeriods,
+		ThrottledTime:    cs.CpuStats.ThrottlingData.ThrottledTime,
+		TotalTicks:       c.systemCpuStats.TicksConsumed(totalPercent),
+		Measured:         MeasuredCpuStats,
+	}
+
+	return &cstructs.TaskResourceUsage{
+		ResourceUsage: &cstructs.ResourceUsage{
+			MemoryStats: ms,
+			CpuStats:    cpu,
+		},
+		Timestamp: ts.UTC().UnixNano(),
+	}
+}
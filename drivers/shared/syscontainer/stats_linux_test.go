@@ -0,0 +1,34 @@
+package syscontainer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/opencontainers/runc/libcontainer/cgroups"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatsCollector_ToTaskResourceUsage(t *testing.T) {
+	require := require.New(t)
+
+	cs := cgroups.NewStats()
+	cs.MemoryStats.Stats["rss"] = 100
+	cs.MemoryStats.Stats["cache"] = 50
+	cs.MemoryStats.SwapUsage.Usage = 10
+	cs.MemoryStats.Usage.MaxUsage = 200
+	cs.CpuStats.ThrottlingData.ThrottledPeriods = 3
+
+	now := time.Now()
+	c := NewStatsCollector(map[string]string{})
+	usage := c.toTaskResourceUsage(cs, now)
+
+	require.Equal(now.UTC().UnixNano(), usage.Timestamp)
+	ms := usage.ResourceUsage.MemoryStats
+	require.EqualValues(100, ms.RSS)
+	require.EqualValues(50, ms.Cache)
+	require.EqualValues(10, ms.Swap)
+	require.EqualValues(200, ms.MaxUsage)
+	require.Equal(MeasuredMemStats, ms.Measured)
+	require.EqualValues(3, usage.ResourceUsage.CpuStats.ThrottledPeriods)
+	require.Equal(MeasuredCpuStats, usage.ResourceUsage.CpuStats.Measured)
+}
@@ -0,0 +1,35 @@
+// +build linux
+
+package syscontainer
+
+import (
+	"context"
+	"os"
+	"syscall"
+	"time"
+)
+
+// WaitForExit blocks until the process with the given pid, usually the init
+// of a container, exits or the context is done. Unlike waiting on a child
+// process it also works for containers recovered after the driver restarted,
+// at the cost of not learning the exit code.
+func WaitForExit(ctx context.Context, pid int, interval time.Duration) error {
+	ps, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := ps.Signal(syscall.Signal(0)); err != nil {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package syscontainer
+
+import (
+	"context"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWaitForExit(t *testing.T) {
+	require := require.New(t)
+
+	cmd := exec.Command("sleep", "10")
+	require.NoError(cmd.Start())
+	defer cmd.Process.Kill()
+
+	// Still running, so waiting is cancelled by the context
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	require.Equal(context.DeadlineExceeded, WaitForExit(ctx, cmd.Process.Pid, 10*time.Millisecond))
+
+	require.NoError(cmd.Process.Kill())
+	cmd.Wait()
+	require.NoError(WaitForExit(context.Background(), cmd.Process.Pid, 10*time.Millisecond))
+}
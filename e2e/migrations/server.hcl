@@ -5,6 +5,9 @@ data_dir = "/tmp/server1"
 server {
   enabled = true
 
+  # Wraps the root keys of variables, must be the same on all servers
+  keyring_encryption_key = "sOQZmTJ60lx+kEx+Bh/2JHmaMPFVxhLAur6NQRhNwYI="
+
   bootstrap_expect = 1
 }
 
@@ -0,0 +1,502 @@
+// Package filter implements the boolean expressions used to filter the
+// objects returned by the list endpoints, such as:
+//
+//	Status == "running" and (Meta.env != "dev" or "web" in Tags)
+//
+// Selectors are the dotted paths of the fields of the objects, with map keys
+// selected by name. The operators are ==, !=, contains, not contains, in, not
+// in, matches, not matches, is empty and is not empty, combined with and, or,
+// not and parentheses.
+package filter
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Filter is a parsed filter expression.
+type Filter struct {
+	expr expression
+}
+
+// New parses the filter expression.
+func New(input string) (*Filter, error) {
+	tokens, err := lex(input)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected %s at position %d", tok, tok.pos)
+	}
+	return &Filter{expr: expr}, nil
+}
+
+// Evaluate returns whether the object matches the filter. Errors are returned
+// for selectors that don't exist in the object.
+func (f *Filter) Evaluate(obj interface{}) (bool, error) {
+	return f.expr.eval(reflect.ValueOf(obj))
+}
+
+type expression interface {
+	eval(v reflect.Value) (bool, error)
+}
+
+type andExpr struct{ left, right expression }
+
+func (e *andExpr) eval(v reflect.Value) (bool, error) {
+	ok, err := e.left.eval(v)
+	if err != nil || !ok {
+		return false, err
+	}
+	return e.right.eval(v)
+}
+
+type orExpr struct{ left, right expression }
+
+func (e *orExpr) eval(v reflect.Value) (bool, error) {
+	ok, err := e.left.eval(v)
+	if err != nil || ok {
+		return ok, err
+	}
+	return e.right.eval(v)
+}
+
+type notExpr struct{ expr expression }
+
+func (e *notExpr) eval(v reflect.Value) (bool, error) {
+	ok, err := e.expr.eval(v)
+	return !ok, err
+}
+
+type operator int
+
+const (
+	opEqual operator = iota
+	opContains
+	opMatches
+	opEmpty
+)
+
+// matchExpr matches the value selected by the selector against the value of
+// the expression.
+type matchExpr struct {
+	selector []string
+	op       operator
+	negate   bool
+	value    string
+	re       *regexp.Regexp
+}
+
+func (e *matchExpr) eval(v reflect.Value) (bool, error) {
+	selected, found, err := selectValue(v, e.selector)
+	if err != nil {
+		return false, err
+	}
+
+	var ok bool
+	switch e.op {
+	case opEqual:
+		ok = found && equal(selected, e.value)
+	case opContains:
+		ok = found && contains(selected, e.value)
+	case opMatches:
+		ok = found && selected.Kind() == reflect.String && e.re.MatchString(selected.String())
+	case opEmpty:
+		ok = !found || empty(selected)
+	}
+
+	if e.negate {
+		return !ok, nil
+	}
+	return ok, nil
+}
+
+// selectValue returns the value of the selector. The value isn't found if a
+// pointer of the path is nil or a map of the path doesn't hold the key.
+func selectValue(v reflect.Value, selector []string) (reflect.Value, bool, error) {
+	for _, part := range selector {
+		v = indirect(v)
+		if !v.IsValid() {
+			return v, false, nil
+		}
+
+		switch v.Kind() {
+		case reflect.Struct:
+			field, ok := v.Type().FieldByName(part)
+			if !ok || field.PkgPath != "" {
+				return v, false, fmt.Errorf("selector %q is invalid: unknown field %q",
+					strings.Join(selector, "."), part)
+			}
+			v = v.FieldByIndex(field.Index)
+		case reflect.Map:
+			if v.Type().Key().Kind() != reflect.String {
+				return v, false, fmt.Errorf("selector %q is invalid: map keys aren't strings",
+					strings.Join(selector, "."))
+			}
+			v = v.MapIndex(reflect.ValueOf(part).Convert(v.Type().Key()))
+			if !v.IsValid() {
+				return v, false, nil
+			}
+		default:
+			return v, false, fmt.Errorf("selector %q is invalid: can't select %q of a %s",
+				strings.Join(selector, "."), part, v.Kind())
+		}
+	}
+
+	v = indirect(v)
+	return v, v.IsValid(), nil
+}
+
+// indirect dereferences the pointers and interfaces, returning an invalid
+// value if one is nil.
+func indirect(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// equal returns whether the primitive value equals the value of the
+// expression.
+func equal(v reflect.Value, value string) bool {
+	v = indirect(v)
+	if !v.IsValid() {
+		return false
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String() == value
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		return err == nil && v.Bool() == b
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(value, 10, 64)
+		return err == nil && v.Int() == i
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(value, 10, 64)
+		return err == nil && v.Uint() == u
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, 64)
+		return err == nil && v.Float() == f
+	}
+	return false
+}
+
+// contains returns whether the string holds the value, the slice an element
+// equal to the value, or the map the value as key.
+func contains(v reflect.Value, value string) bool {
+	switch v.Kind() {
+	case reflect.String:
+		return strings.Contains(v.String(), value)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if equal(v.Index(i), value) {
+				return true
+			}
+		}
+	case reflect.Map:
+		if v.Type().Key().Kind() == reflect.String {
+			return v.MapIndex(reflect.ValueOf(value).Convert(v.Type().Key())).IsValid()
+		}
+	}
+	return false
+}
+
+// empty returns whether the value is empty or the zero value.
+func empty(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		return v.Len() == 0
+	}
+	return v.IsZero()
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// keyword consumes the next token if it is the keyword.
+func (p *parser) keyword(kw string) bool {
+	if tok := p.peek(); tok.kind == tokenIdent && tok.text == kw {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) parseOr() (expression, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("or") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &orExpr{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (expression, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("and") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &andExpr{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (expression, error) {
+	if p.keyword("not") {
+		expr, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &notExpr{expr: expr}, nil
+	}
+
+	if tok := p.peek(); tok.kind == tokenLParen {
+		p.next()
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if tok := p.next(); tok.kind != tokenRParen {
+			return nil, fmt.Errorf("expected ) at position %d, got %s", tok.pos, tok)
+		}
+		return expr, nil
+	}
+
+	return p.parseMatch()
+}
+
+// parseMatch parses either "<value> [not] in <selector>" or
+// "<selector> <operator> [<value>]".
+func (p *parser) parseMatch() (expression, error) {
+	tok := p.next()
+	switch {
+	case tok.kind == tokenValue || (tok.kind == tokenIdent && (tok.text == "true" || tok.text == "false")):
+		negate := p.keyword("not")
+		if !p.keyword("in") {
+			return nil, fmt.Errorf("expected in after value at position %d, got %s", p.peek().pos, p.peek())
+		}
+		selector, err := p.parseSelector()
+		if err != nil {
+			return nil, err
+		}
+		return &matchExpr{selector: selector, op: opContains, negate: negate, value: tok.text}, nil
+	case tok.kind == tokenIdent:
+		if isKeyword(tok.text) {
+			return nil, fmt.Errorf("unexpected %s at position %d", tok, tok.pos)
+		}
+	default:
+		return nil, fmt.Errorf("expected selector or value at position %d, got %s", tok.pos, tok)
+	}
+
+	expr := &matchExpr{selector: strings.Split(tok.text, ".")}
+	for _, part := range expr.selector {
+		if part == "" {
+			return nil, fmt.Errorf("invalid selector %q at position %d", tok.text, tok.pos)
+		}
+	}
+
+	op := p.next()
+	switch {
+	case op.kind == tokenEqual:
+		expr.op = opEqual
+	case op.kind == tokenNotEqual:
+		expr.op, expr.negate = opEqual, true
+	case op.kind == tokenIdent && op.text == "is":
+		expr.op = opEmpty
+		expr.negate = p.keyword("not")
+		if !p.keyword("empty") {
+			return nil, fmt.Errorf("expected empty at position %d, got %s", p.peek().pos, p.peek())
+		}
+		return expr, nil
+	case op.kind == tokenIdent && op.text == "not":
+		expr.negate = true
+		op = p.next()
+		fallthrough
+	case op.kind == tokenIdent && (op.text == "contains" || op.text == "matches"):
+		if op.kind != tokenIdent || (op.text != "contains" && op.text != "matches") {
+			return nil, fmt.Errorf("expected contains or matches at position %d, got %s", op.pos, op)
+		}
+		expr.op = opContains
+		if op.text == "matches" {
+			expr.op = opMatches
+		}
+	default:
+		return nil, fmt.Errorf("expected operator at position %d, got %s", op.pos, op)
+	}
+
+	value := p.next()
+	if value.kind != tokenValue && !(value.kind == tokenIdent && (value.text == "true" || value.text == "false")) {
+		return nil, fmt.Errorf("expected value at position %d, got %s", value.pos, value)
+	}
+	expr.value = value.text
+
+	if expr.op == opMatches {
+		re, err := regexp.Compile(expr.value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression %q: %v", expr.value, err)
+		}
+		expr.re = re
+	}
+	return expr, nil
+}
+
+func (p *parser) parseSelector() ([]string, error) {
+	tok := p.next()
+	if tok.kind != tokenIdent || isKeyword(tok.text) {
+		return nil, fmt.Errorf("expected selector at position %d, got %s", tok.pos, tok)
+	}
+	selector := strings.Split(tok.text, ".")
+	for _, part := range selector {
+		if part == "" {
+			return nil, fmt.Errorf("invalid selector %q at position %d", tok.text, tok.pos)
+		}
+	}
+	return selector, nil
+}
+
+func isKeyword(s string) bool {
+	switch s {
+	case "and", "or", "not", "in", "contains", "matches", "is", "empty", "true", "false":
+		return true
+	}
+	return false
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenValue
+	tokenEqual
+	tokenNotEqual
+	tokenLParen
+	tokenRParen
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of expression"
+	case tokenValue:
+		return strconv.Quote(t.text)
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+func isIdentRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-' || r == '.'
+}
+
+// lex splits the input into tokens. Quoted strings and numbers are values,
+// while the other words are selectors or keywords.
+func lex(input string) ([]token, error) {
+	var tokens []token
+	runes := []rune(input)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenLParen, text: "(", pos: i})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenRParen, text: ")", pos: i})
+			i++
+		case r == '=' || r == '!':
+			if i+1 >= len(runes) || runes[i+1] != '=' {
+				return nil, fmt.Errorf("unexpected %q at position %d", r, i)
+			}
+			kind := tokenEqual
+			if r == '!' {
+				kind = tokenNotEqual
+			}
+			tokens = append(tokens, token{kind: kind, text: string(runes[i : i+2]), pos: i})
+			i += 2
+		case r == '"' || r == '`':
+			end := i + 1
+			for ; end < len(runes) && runes[end] != r; end++ {
+				if r == '"' && runes[end] == '\\' {
+					end++
+				}
+			}
+			if end >= len(runes) {
+				return nil, fmt.Errorf("unterminated string at position %d", i)
+			}
+			text := string(runes[i+1 : end])
+			if r == '"' {
+				unquoted, err := strconv.Unquote(string(runes[i : end+1]))
+				if err != nil {
+					return nil, fmt.Errorf("invalid string at position %d: %v", i, err)
+				}
+				text = unquoted
+			}
+			tokens = append(tokens, token{kind: tokenValue, text: text, pos: i})
+			i = end + 1
+		case unicode.IsDigit(r):
+			end := i
+			for end < len(runes) && (unicode.IsDigit(runes[end]) || runes[end] == '.') {
+				end++
+			}
+			tokens = append(tokens, token{kind: tokenValue, text: string(runes[i:end]), pos: i})
+			i = end
+		case isIdentRune(r):
+			end := i
+			for end < len(runes) && isIdentRune(runes[end]) {
+				end++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: string(runes[i:end]), pos: i})
+			i = end
+		default:
+			return nil, fmt.Errorf("unexpected %q at position %d", r, i)
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, pos: len(runes)}), nil
+}
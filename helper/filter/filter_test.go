@@ -0,0 +1,95 @@
+package filter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testNode struct {
+	ID       string
+	Status   string
+	Drain    bool
+	Priority int
+	Tags     []string
+	Meta     map[string]string
+	Resource *testResource
+}
+
+type testResource struct {
+	CPU int
+}
+
+func TestFilter_Evaluate(t *testing.T) {
+	obj := &testNode{
+		ID:       "foo-1",
+		Status:   "ready",
+		Priority: 50,
+		Tags:     []string{"web", "cache"},
+		Meta:     map[string]string{"env": "prod"},
+		Resource: &testResource{CPU: 500},
+	}
+
+	cases := []struct {
+		expr  string
+		match bool
+	}{
+		{`Status == "ready"`, true},
+		{`Status != "ready"`, false},
+		{`Drain == false`, true},
+		{`Priority == 50`, true},
+		{`Resource.CPU == 500`, true},
+		{`Meta.env == "prod"`, true},
+		{`Meta.missing == "prod"`, false},
+		{`Meta.missing is empty`, true},
+		{`Meta is not empty`, true},
+		{`Tags contains "web"`, true},
+		{`"cache" in Tags`, true},
+		{`"db" not in Tags`, true},
+		{`Meta contains "env"`, true},
+		{`ID contains "foo"`, true},
+		{`ID not contains "foo"`, false},
+		{`ID matches "^foo-[0-9]+$"`, true},
+		{"ID not matches `^bar`", true},
+		{`Status == "ready" and Meta.env == "dev"`, false},
+		{`Status == "ready" and (Meta.env == "dev" or "web" in Tags)`, true},
+		{`not Drain == false`, false},
+		{`Status == "down" or not (Priority == 10)`, true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.expr, func(t *testing.T) {
+			f, err := New(c.expr)
+			require.NoError(t, err)
+			match, err := f.Evaluate(obj)
+			require.NoError(t, err)
+			require.Equal(t, c.match, match)
+		})
+	}
+}
+
+func TestFilter_Errors(t *testing.T) {
+	for _, expr := range []string{
+		``,
+		`Status`,
+		`Status ==`,
+		`Status = "ready"`,
+		`"ready" == Status`,
+		`Status == "ready" and`,
+		`(Status == "ready"`,
+		`Status == "ready")`,
+		`Status is "ready"`,
+		`Status == "unterminated`,
+		`ID matches "["`,
+	} {
+		_, err := New(expr)
+		require.Error(t, err, expr)
+	}
+
+	// Unknown fields are only detected against an object
+	f, err := New(`Unknown == "foo"`)
+	require.NoError(t, err)
+	_, err = f.Evaluate(&testNode{})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "unknown field")
+}
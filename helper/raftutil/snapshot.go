@@ -0,0 +1,37 @@
+// Package raftutil contains utilities to work with the Raft state of the
+// Nomad servers outside of a running server.
+package raftutil
+
+import (
+	"io"
+	"os"
+
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/helper/snapshot"
+	"github.com/hashicorp/nomad/nomad"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/raft"
+)
+
+// RestoreFromArchive reads a snapshot archive, verifying its integrity, and
+// restores the state it contains into a new state store.
+func RestoreFromArchive(logger log.Logger, archive io.Reader) (*state.StateStore, *raft.SnapshotMeta, error) {
+	fsm, err := nomad.NewFSM(&nomad.FSMConfig{
+		Logger: logger,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	snap, meta, err := snapshot.Read(logger, archive)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer os.Remove(snap.Name())
+
+	// The FSM closes the snapshot file once restored
+	if err := fsm.Restore(snap); err != nil {
+		return nil, nil, err
+	}
+	return fsm.State(), meta, nil
+}
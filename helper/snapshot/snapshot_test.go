@@ -0,0 +1,168 @@
+package snapshot
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/raft"
+	"github.com/stretchr/testify/require"
+)
+
+// testFSM is a Raft FSM that keeps the applied log data in memory.
+type testFSM struct {
+	sync.Mutex
+	logs [][]byte
+}
+
+func (f *testFSM) Apply(l *raft.Log) interface{} {
+	f.Lock()
+	defer f.Unlock()
+	f.logs = append(f.logs, l.Data)
+	return nil
+}
+
+func (f *testFSM) Snapshot() (raft.FSMSnapshot, error) {
+	f.Lock()
+	defer f.Unlock()
+	return &testSnapshot{bytes.Join(f.logs, []byte("\n"))}, nil
+}
+
+func (f *testFSM) Restore(in io.ReadCloser) error {
+	defer in.Close()
+	data, err := ioutil.ReadAll(in)
+	if err != nil {
+		return err
+	}
+
+	f.Lock()
+	defer f.Unlock()
+	f.logs = bytes.Split(data, []byte("\n"))
+	return nil
+}
+
+func (f *testFSM) Logs() [][]byte {
+	f.Lock()
+	defer f.Unlock()
+	return f.logs
+}
+
+type testSnapshot struct {
+	data []byte
+}
+
+func (s *testSnapshot) Persist(sink raft.SnapshotSink) error {
+	if _, err := sink.Write(s.data); err != nil {
+		sink.Cancel()
+		return err
+	}
+	return sink.Close()
+}
+
+func (s *testSnapshot) Release() {}
+
+// testRaft returns a single node Raft cluster that is the leader.
+func testRaft(t *testing.T, fsm raft.FSM) *raft.Raft {
+	conf := raft.DefaultConfig()
+	conf.LocalID = raft.ServerID("server")
+	conf.Logger = testlog.Logger(t)
+	conf.HeartbeatTimeout = 50 * time.Millisecond
+	conf.ElectionTimeout = 50 * time.Millisecond
+	conf.LeaderLeaseTimeout = 50 * time.Millisecond
+	conf.CommitTimeout = 5 * time.Millisecond
+
+	logs := raft.NewInmemStore()
+	snaps := raft.NewInmemSnapshotStore()
+	addr, trans := raft.NewInmemTransport("")
+	configuration := raft.Configuration{
+		Servers: []raft.Server{{ID: conf.LocalID, Address: addr}},
+	}
+	require.NoError(t, raft.BootstrapCluster(conf, logs, logs, snaps, trans, configuration))
+
+	r, err := raft.NewRaft(conf, fsm, logs, logs, snaps, trans)
+	require.NoError(t, err)
+
+	timeout := time.After(10 * time.Second)
+	for r.State() != raft.Leader {
+		select {
+		case <-r.LeaderCh():
+		case <-time.After(10 * time.Millisecond):
+		case <-timeout:
+			t.Fatalf("timed out waiting for leader")
+		}
+	}
+	return r
+}
+
+func TestSnapshot_SaveRestore(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	// Apply some logs to a first cluster.
+	fsm := &testFSM{}
+	r := testRaft(t, fsm)
+	defer r.Shutdown()
+
+	var expected [][]byte
+	for i := 0; i < 16; i++ {
+		data := []byte(fmt.Sprintf("entry-%d", i))
+		require.NoError(r.Apply(data, time.Second).Error())
+		expected = append(expected, data)
+	}
+
+	// Take a snapshot.
+	logger := testlog.HCLogger(t)
+	snap, err := New(logger, r)
+	require.NoError(err)
+	defer snap.Close()
+	require.NotZero(snap.Index())
+	require.Contains(snap.Checksum(), "sha-256=")
+
+	var archive bytes.Buffer
+	_, err = io.Copy(&archive, snap)
+	require.NoError(err)
+
+	// Verify the archive.
+	meta, err := Verify(bytes.NewReader(archive.Bytes()))
+	require.NoError(err)
+	require.Equal(snap.Index(), meta.Index)
+
+	// Restore it into a second cluster.
+	fsm2 := &testFSM{}
+	r2 := testRaft(t, fsm2)
+	defer r2.Shutdown()
+	require.NoError(r2.Apply([]byte("unrelated"), time.Second).Error())
+
+	require.NoError(Restore(logger, bytes.NewReader(archive.Bytes()), r2))
+	require.Equal(expected, fsm2.Logs())
+}
+
+func TestSnapshot_Corrupt(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	fsm := &testFSM{}
+	r := testRaft(t, fsm)
+	defer r.Shutdown()
+	require.NoError(r.Apply([]byte("entry"), time.Second).Error())
+
+	snap, err := New(testlog.HCLogger(t), r)
+	require.NoError(err)
+	defer snap.Close()
+
+	archive, err := ioutil.ReadAll(snap)
+	require.NoError(err)
+
+	// Truncated archives fail to verify.
+	_, err = Verify(bytes.NewReader(archive[:len(archive)/2]))
+	require.Error(err)
+
+	// Anything that isn't a gzip archive fails to verify.
+	_, err = Verify(bytes.NewReader([]byte("not a snapshot")))
+	require.Error(err)
+}
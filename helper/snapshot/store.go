@@ -0,0 +1,93 @@
+package snapshot
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Store is a storage the snapshots are saved in, such as a directory or a
+// bucket of an object storage.
+type Store interface {
+	// Name returns the name of the store, for logging.
+	Name() string
+
+	// Put saves the snapshot read from the reader under the name.
+	Put(name string, r io.ReadSeeker) error
+
+	// List returns the names of the saved snapshots starting with the
+	// prefix.
+	List(prefix string) ([]string, error)
+
+	// Delete deletes a saved snapshot.
+	Delete(name string) error
+}
+
+// localStore saves the snapshots in a directory.
+type localStore struct {
+	path string
+}
+
+// NewLocalStore returns a store saving the snapshots in the directory, which
+// is created if needed.
+func NewLocalStore(path string) (Store, error) {
+	if path == "" {
+		return nil, fmt.Errorf("local storage requires a path")
+	}
+	if err := os.MkdirAll(path, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create snapshot directory: %v", err)
+	}
+	return &localStore{path: path}, nil
+}
+
+func (s *localStore) Name() string {
+	return "local"
+}
+
+func (s *localStore) Put(name string, r io.ReadSeeker) error {
+	// Write to a temporary file renamed once complete, so that partial
+	// snapshots are never listed
+	f, err := ioutil.TempFile(s.path, "."+name)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), filepath.Join(s.path, name))
+}
+
+func (s *localStore) List(prefix string) ([]string, error) {
+	entries, err := ioutil.ReadDir(s.path)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasPrefix(e.Name(), prefix) {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (s *localStore) Delete(name string) error {
+	return os.Remove(filepath.Join(s.path, name))
+}
@@ -0,0 +1,255 @@
+package snapshot
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	cleanhttp "github.com/hashicorp/go-cleanhttp"
+)
+
+const (
+	// azureStorageVersion is the version of the Azure Storage REST API
+	// used to authorize the requests.
+	azureStorageVersion = "2019-12-12"
+)
+
+// AzureStoreConfig is the configuration of a store saving the snapshots in
+// an Azure Blob Storage container.
+type AzureStoreConfig struct {
+	AccountName   string
+	AccountKey    string
+	ContainerName string
+	KeyPrefix     string
+	Endpoint      string
+}
+
+// azureStore saves the snapshots in an Azure Blob Storage container, using
+// the REST API of the blob service authorized with the shared key of the
+// storage account.
+type azureStore struct {
+	config   AzureStoreConfig
+	key      []byte
+	endpoint *url.URL
+	client   *http.Client
+}
+
+// NewAzureStore returns a store saving the snapshots in an Azure Blob Storage
+// container.
+func NewAzureStore(config AzureStoreConfig) (Store, error) {
+	if config.AccountName == "" || config.AccountKey == "" {
+		return nil, fmt.Errorf("azure blob storage requires an account name and key")
+	}
+	if config.ContainerName == "" {
+		return nil, fmt.Errorf("azure blob storage requires a container name")
+	}
+
+	key, err := base64.StdEncoding.DecodeString(config.AccountKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid account key: %v", err)
+	}
+
+	endpoint := config.Endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://%s.blob.core.windows.net", config.AccountName)
+	}
+	u, err := url.Parse(strings.TrimSuffix(endpoint, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid endpoint: %v", err)
+	}
+
+	return &azureStore{
+		config:   config,
+		key:      key,
+		endpoint: u,
+		client:   cleanhttp.DefaultClient(),
+	}, nil
+}
+
+func (s *azureStore) Name() string {
+	return "azure"
+}
+
+// url returns the URL of the blob of the container, or of the container if
+// the blob is empty.
+func (s *azureStore) url(blob string, query url.Values) *url.URL {
+	u := *s.endpoint
+	u.Path += "/" + s.config.ContainerName
+	if blob != "" {
+		u.Path += "/" + blob
+	}
+	u.RawQuery = query.Encode()
+	return &u
+}
+
+// do signs and sends the request, returning an error if it doesn't succeed.
+func (s *azureStore) do(req *http.Request) (*http.Response, error) {
+	req.Header.Set("x-ms-date", time.Now().UTC().Format(http.TimeFormat))
+	req.Header.Set("x-ms-version", azureStorageVersion)
+	req.Header.Set("Authorization", fmt.Sprintf("SharedKey %s:%s",
+		s.config.AccountName, azureSignature(s.key, s.config.AccountName, req)))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		defer resp.Body.Close()
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("unexpected response code %d: %s", resp.StatusCode, body)
+	}
+	return resp, nil
+}
+
+func (s *azureStore) Put(name string, r io.ReadSeeker) error {
+	size, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("PUT", s.url(s.config.KeyPrefix+name, nil).String(), ioutil.NopCloser(r))
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Length", strconv.FormatInt(size, 10))
+	req.Header.Set("x-ms-blob-type", "BlockBlob")
+
+	resp, err := s.do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// azureBlobList is the result of listing the blobs of a container.
+type azureBlobList struct {
+	Blobs []struct {
+		Name string `xml:"Name"`
+	} `xml:"Blobs>Blob"`
+	NextMarker string `xml:"NextMarker"`
+}
+
+func (s *azureStore) List(prefix string) ([]string, error) {
+	query := url.Values{
+		"restype": []string{"container"},
+		"comp":    []string{"list"},
+		"prefix":  []string{s.config.KeyPrefix + prefix},
+	}
+
+	var names []string
+	for {
+		req, err := http.NewRequest("GET", s.url("", query).String(), nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := s.do(req)
+		if err != nil {
+			return nil, err
+		}
+
+		var list azureBlobList
+		err = xml.NewDecoder(resp.Body).Decode(&list)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode blob list: %v", err)
+		}
+
+		for _, b := range list.Blobs {
+			names = append(names, strings.TrimPrefix(b.Name, s.config.KeyPrefix))
+		}
+		if list.NextMarker == "" {
+			break
+		}
+		query.Set("marker", list.NextMarker)
+	}
+
+	sort.Strings(names)
+	return names, nil
+}
+
+func (s *azureStore) Delete(name string) error {
+	req, err := http.NewRequest("DELETE", s.url(s.config.KeyPrefix+name, nil).String(), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := s.do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// azureSignature returns the shared key signature of the request, as
+// described in https://docs.microsoft.com/en-us/rest/api/storageservices/authorize-with-shared-key
+func azureSignature(key []byte, account string, req *http.Request) string {
+	// The content length is empty rather than zero
+	length := req.Header.Get("Content-Length")
+	if length == "0" {
+		length = ""
+	}
+
+	// Canonicalize the x-ms- headers
+	var headers []string
+	for k := range req.Header {
+		if k := strings.ToLower(k); strings.HasPrefix(k, "x-ms-") {
+			headers = append(headers, k)
+		}
+	}
+	sort.Strings(headers)
+
+	var b strings.Builder
+	for _, v := range []string{
+		req.Method,
+		req.Header.Get("Content-Encoding"),
+		req.Header.Get("Content-Language"),
+		length,
+		req.Header.Get("Content-MD5"),
+		req.Header.Get("Content-Type"),
+		"", // Date, superseded by x-ms-date
+		req.Header.Get("If-Modified-Since"),
+		req.Header.Get("If-Match"),
+		req.Header.Get("If-None-Match"),
+		req.Header.Get("If-Unmodified-Since"),
+		req.Header.Get("Range"),
+	} {
+		b.WriteString(v)
+		b.WriteByte('\n')
+	}
+	for _, k := range headers {
+		fmt.Fprintf(&b, "%s:%s\n", k, strings.TrimSpace(req.Header.Get(k)))
+	}
+
+	// Canonicalize the resource
+	fmt.Fprintf(&b, "/%s%s", account, req.URL.EscapedPath())
+	query := req.URL.Query()
+	var params []string
+	for k := range query {
+		params = append(params, k)
+	}
+	sort.Strings(params)
+	for _, k := range params {
+		values := query[k]
+		sort.Strings(values)
+		fmt.Fprintf(&b, "\n%s:%s", strings.ToLower(k), strings.Join(values, ","))
+	}
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(b.String()))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
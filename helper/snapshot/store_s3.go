@@ -0,0 +1,138 @@
+package snapshot
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+const (
+	// gcsEndpoint is the endpoint of the XML API of Google Cloud Storage,
+	// which is compatible with the S3 API.
+	gcsEndpoint = "https://storage.googleapis.com"
+)
+
+// S3StoreConfig is the configuration of a store saving the snapshots in an
+// S3 bucket.
+type S3StoreConfig struct {
+	Bucket               string
+	KeyPrefix            string
+	Region               string
+	Endpoint             string
+	ForcePathStyle       bool
+	AccessKeyID          string
+	SecretAccessKey      string
+	ServerSideEncryption bool
+}
+
+// s3Store saves the snapshots in an S3 bucket.
+type s3Store struct {
+	name   string
+	config S3StoreConfig
+	client *s3.S3
+}
+
+// NewS3Store returns a store saving the snapshots in an S3 bucket.
+func NewS3Store(config S3StoreConfig) (Store, error) {
+	return newS3Store("s3", config)
+}
+
+// NewGCSStore returns a store saving the snapshots in a Google Cloud Storage
+// bucket, through its S3 compatible XML API authenticated with an HMAC key.
+func NewGCSStore(config S3StoreConfig) (Store, error) {
+	if config.AccessKeyID == "" || config.SecretAccessKey == "" {
+		return nil, fmt.Errorf("gcs storage requires an HMAC access key ID and secret")
+	}
+	if config.Endpoint == "" {
+		config.Endpoint = gcsEndpoint
+	}
+	config.Region = "auto"
+	config.ForcePathStyle = true
+	config.ServerSideEncryption = false
+	return newS3Store("gcs", config)
+}
+
+func newS3Store(name string, config S3StoreConfig) (Store, error) {
+	if config.Bucket == "" {
+		return nil, fmt.Errorf("%s storage requires a bucket", name)
+	}
+
+	awsConfig := &aws.Config{
+		S3ForcePathStyle: aws.Bool(config.ForcePathStyle),
+	}
+	if config.Region != "" {
+		awsConfig.Region = aws.String(config.Region)
+	}
+	if config.Endpoint != "" {
+		awsConfig.Endpoint = aws.String(config.Endpoint)
+	}
+
+	// Use the credentials of the environment or instance if unset
+	if config.AccessKeyID != "" {
+		awsConfig.Credentials = credentials.NewStaticCredentials(
+			config.AccessKeyID, config.SecretAccessKey, "")
+	}
+
+	return &s3Store{
+		name:   name,
+		config: config,
+		client: s3.New(session.New(awsConfig)),
+	}, nil
+}
+
+func (s *s3Store) Name() string {
+	return s.name
+}
+
+func (s *s3Store) Put(name string, r io.ReadSeeker) error {
+	input := &s3.PutObjectInput{
+		Bucket: aws.String(s.config.Bucket),
+		Key:    aws.String(s.config.KeyPrefix + name),
+		Body:   r,
+	}
+	if s.config.ServerSideEncryption {
+		input.ServerSideEncryption = aws.String(s3.ServerSideEncryptionAes256)
+	}
+	_, err := s.client.PutObject(input)
+	return err
+}
+
+func (s *s3Store) List(prefix string) ([]string, error) {
+	input := &s3.ListObjectsInput{
+		Bucket: aws.String(s.config.Bucket),
+		Prefix: aws.String(s.config.KeyPrefix + prefix),
+	}
+
+	var names []string
+	for {
+		out, err := s.client.ListObjects(input)
+		if err != nil {
+			return nil, err
+		}
+		for _, o := range out.Contents {
+			key := aws.StringValue(o.Key)
+			names = append(names, strings.TrimPrefix(key, s.config.KeyPrefix))
+			input.Marker = o.Key
+		}
+		if !aws.BoolValue(out.IsTruncated) || len(out.Contents) == 0 {
+			break
+		}
+	}
+
+	sort.Strings(names)
+	return names, nil
+}
+
+func (s *s3Store) Delete(name string) error {
+	_, err := s.client.DeleteObject(&s3.DeleteObjectInput{
+		Bucket: aws.String(s.config.Bucket),
+		Key:    aws.String(s.config.KeyPrefix + name),
+	})
+	return err
+}
@@ -0,0 +1,222 @@
+package snapshot
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testStore asserts the store saves, lists and deletes the snapshots.
+func testStore(t *testing.T, store Store) {
+	require := require.New(t)
+
+	for _, name := range []string{"nomad-2.snap", "nomad-1.snap", "other-1.snap"} {
+		require.NoError(store.Put(name, bytes.NewReader([]byte("data of "+name))))
+	}
+
+	names, err := store.List("nomad-")
+	require.NoError(err)
+	require.Equal([]string{"nomad-1.snap", "nomad-2.snap"}, names)
+
+	require.NoError(store.Delete("nomad-1.snap"))
+	names, err = store.List("nomad-")
+	require.NoError(err)
+	require.Equal([]string{"nomad-2.snap"}, names)
+}
+
+func TestLocalStore(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "nomad-snapshots")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	store, err := NewLocalStore(dir + "/snapshots")
+	require.NoError(t, err)
+	testStore(t, store)
+
+	data, err := ioutil.ReadFile(dir + "/snapshots/nomad-2.snap")
+	require.NoError(t, err)
+	require.Equal(t, "data of nomad-2.snap", string(data))
+
+	_, err = NewLocalStore("")
+	require.Error(t, err)
+}
+
+// fakeObjectStore is an in-memory object storage answering the requests of
+// the S3 and Azure stores.
+type fakeObjectStore struct {
+	sync.Mutex
+	objects map[string][]byte
+}
+
+func (f *fakeObjectStore) put(key string, data []byte) {
+	f.Lock()
+	defer f.Unlock()
+	f.objects[key] = data
+}
+
+func (f *fakeObjectStore) delete(key string) {
+	f.Lock()
+	defer f.Unlock()
+	delete(f.objects, key)
+}
+
+func (f *fakeObjectStore) list(prefix string) []string {
+	f.Lock()
+	defer f.Unlock()
+	var keys []string
+	for k := range f.objects {
+		if strings.HasPrefix(k, prefix) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// s3Handler answers the S3 requests of path style buckets.
+func (f *fakeObjectStore) s3Handler(t *testing.T, bucket string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=id/") {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		key := strings.TrimPrefix(r.URL.Path, "/"+bucket+"/")
+		switch r.Method {
+		case "PUT":
+			data, _ := ioutil.ReadAll(r.Body)
+			f.put(key, data)
+		case "DELETE":
+			f.delete(key)
+			w.WriteHeader(http.StatusNoContent)
+		case "GET":
+			fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?><ListBucketResult xmlns="http://s3.amazonaws.com/doc/2006-03-01/"><Name>%s</Name><IsTruncated>false</IsTruncated>`, bucket)
+			for _, k := range f.list(r.URL.Query().Get("prefix")) {
+				fmt.Fprintf(w, "<Contents><Key>%s</Key></Contents>", k)
+			}
+			fmt.Fprint(w, "</ListBucketResult>")
+		}
+	})
+}
+
+func TestS3Store(t *testing.T) {
+	t.Parallel()
+
+	fake := &fakeObjectStore{objects: make(map[string][]byte)}
+	srv := httptest.NewServer(fake.s3Handler(t, "backups"))
+	defer srv.Close()
+
+	store, err := NewS3Store(S3StoreConfig{
+		Bucket:          "backups",
+		KeyPrefix:       "nomad/",
+		Region:          "us-east-1",
+		Endpoint:        srv.URL,
+		ForcePathStyle:  true,
+		AccessKeyID:     "id",
+		SecretAccessKey: "secret",
+	})
+	require.NoError(t, err)
+	testStore(t, store)
+	require.Equal(t, []byte("data of nomad-2.snap"), fake.objects["nomad/nomad-2.snap"])
+	require.Contains(t, fake.objects, "nomad/other-1.snap")
+
+	_, err = NewS3Store(S3StoreConfig{})
+	require.Error(t, err)
+	_, err = NewGCSStore(S3StoreConfig{Bucket: "backups"})
+	require.Error(t, err)
+}
+
+// azureHandler answers the Azure Blob Storage requests of the container,
+// verifying their signature.
+func (f *fakeObjectStore) azureHandler(t *testing.T, account string, key []byte, container string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		expected := fmt.Sprintf("SharedKey %s:%s", account, azureSignature(key, account, r))
+		if r.Header.Get("Authorization") != expected || r.Header.Get("x-ms-date") == "" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		blob := strings.TrimPrefix(r.URL.Path, "/"+container+"/")
+		switch r.Method {
+		case "PUT":
+			if r.Header.Get("x-ms-blob-type") != "BlockBlob" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			data, _ := ioutil.ReadAll(r.Body)
+			f.put(blob, data)
+			w.WriteHeader(http.StatusCreated)
+		case "DELETE":
+			f.delete(blob)
+			w.WriteHeader(http.StatusAccepted)
+		case "GET":
+			// List the blobs one page at a time
+			var list azureBlobList
+			keys := f.list(r.URL.Query().Get("prefix"))
+			for _, k := range keys {
+				if k > r.URL.Query().Get("marker") {
+					list.Blobs = append(list.Blobs, struct {
+						Name string `xml:"Name"`
+					}{k})
+					break
+				}
+			}
+			if len(list.Blobs) != 0 && list.Blobs[0].Name != keys[len(keys)-1] {
+				list.NextMarker = list.Blobs[0].Name
+			}
+			type result struct {
+				XMLName xml.Name `xml:"EnumerationResults"`
+				azureBlobList
+			}
+			xml.NewEncoder(w).Encode(result{azureBlobList: list})
+		}
+	})
+}
+
+func TestAzureStore(t *testing.T) {
+	t.Parallel()
+
+	key := []byte("account key")
+	fake := &fakeObjectStore{objects: make(map[string][]byte)}
+	srv := httptest.NewServer(fake.azureHandler(t, "nomad", key, "backups"))
+	defer srv.Close()
+
+	store, err := NewAzureStore(AzureStoreConfig{
+		AccountName:   "nomad",
+		AccountKey:    base64.StdEncoding.EncodeToString(key),
+		ContainerName: "backups",
+		KeyPrefix:     "snapshots/",
+		Endpoint:      srv.URL,
+	})
+	require.NoError(t, err)
+	testStore(t, store)
+	require.Equal(t, []byte("data of nomad-2.snap"), fake.objects["snapshots/nomad-2.snap"])
+
+	// Requests signed with another key are rejected
+	store, err = NewAzureStore(AzureStoreConfig{
+		AccountName:   "nomad",
+		AccountKey:    base64.StdEncoding.EncodeToString([]byte("other key")),
+		ContainerName: "backups",
+		Endpoint:      srv.URL,
+	})
+	require.NoError(t, err)
+	_, err = store.List("nomad-")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "403")
+
+	_, err = NewAzureStore(AzureStoreConfig{AccountName: "nomad", AccountKey: "not base64!", ContainerName: "backups"})
+	require.Error(t, err)
+}
@@ -126,6 +126,10 @@ type Config struct {
 	// existing clients.
 	VerifyServerHostname bool
 
+	// RegionServerNames overrides the server name verified for the servers of
+	// a region when VerifyServerHostname is set.
+	RegionServerNames map[string]string
+
 	// CAFile is a path to a certificate authority file. This is used with VerifyIncoming
 	// or VerifyOutgoing to verify the TLS connection.
 	CAFile string
@@ -170,6 +174,7 @@ func NewTLSConfiguration(newConf *config.TLSConfig, verifyIncoming, verifyOutgoi
 		VerifyIncoming:           verifyIncoming,
 		VerifyOutgoing:           verifyOutgoing,
 		VerifyServerHostname:     newConf.VerifyServerHostname,
+		RegionServerNames:        newConf.RegionServerNames,
 		CAFile:                   newConf.CAFile,
 		CertFile:                 newConf.CertFile,
 		KeyFile:                  newConf.KeyFile,
@@ -285,7 +290,7 @@ func (c *Config) OutgoingTLSWrapper() (RegionWrapper, error) {
 	if c.VerifyServerHostname {
 		wrapper := func(region string, conn net.Conn) (net.Conn, error) {
 			conf := tlsConfig.Clone()
-			conf.ServerName = "server." + region + ".nomad"
+			conf.ServerName = c.ServerName(region)
 			return WrapTLSClient(conn, conf)
 		}
 		return wrapper, nil
@@ -298,6 +303,15 @@ func (c *Config) OutgoingTLSWrapper() (RegionWrapper, error) {
 
 }
 
+// ServerName returns the server name verified for the servers of the region,
+// which is server.<region>.nomad unless overridden.
+func (c *Config) ServerName(region string) string {
+	if name, ok := c.RegionServerNames[region]; ok && name != "" {
+		return name
+	}
+	return "server." + region + ".nomad"
+}
+
 // Wrap a net.Conn into a client tls connection, performing any
 // additional verification as needed.
 //
@@ -352,6 +352,14 @@ func TestConfig_OutgoingTLS_VerifyHostname(t *testing.T) {
 	}
 }
 
+func TestConfig_ServerName(t *testing.T) {
+	conf := &Config{
+		RegionServerNames: map[string]string{"europe": "server.eu.example.com"},
+	}
+	require.Equal(t, "server.eu.example.com", conf.ServerName("europe"))
+	require.Equal(t, "server.global.nomad", conf.ServerName("global"))
+}
+
 func TestConfig_OutgoingTLS_WithKeyPair(t *testing.T) {
 	assert := assert.New(t)
 
@@ -41,6 +41,13 @@ func Parse(r io.Reader) (*api.Job, error) {
 	}
 	buf.Reset()
 
+	return ParseAST(root)
+}
+
+// ParseAST parses the job spec from an already parsed HCL file. It allows
+// front ends other than the HCL parser, such as the HCL2 job spec parser, to
+// share the decoding of the job.
+func ParseAST(root *ast.File) (*api.Job, error) {
 	// Top-level item should be a list
 	list, ok := root.Node.(*ast.ObjectList)
 	if !ok {
@@ -104,8 +111,10 @@ func parseJob(result *api.Job, list *ast.ObjectList) error {
 	}
 	delete(m, "constraint")
 	delete(m, "affinity")
+	delete(m, "gc")
 	delete(m, "meta")
 	delete(m, "migrate")
+	delete(m, "multiregion")
 	delete(m, "parameterized")
 	delete(m, "periodic")
 	delete(m, "reschedule")
@@ -137,12 +146,15 @@ func parseJob(result *api.Job, list *ast.ObjectList) error {
 		"affinity",
 		"spread",
 		"datacenters",
+		"gc",
 		"group",
 		"id",
 		"meta",
 		"migrate",
+		"multiregion",
 		"name",
 		"namespace",
+		"node_pool",
 		"parameterized",
 		"periodic",
 		"priority",
@@ -200,6 +212,20 @@ func parseJob(result *api.Job, list *ast.ObjectList) error {
 		}
 	}
 
+	// If we have a multiregion definition, then parse that
+	if o := listVal.Filter("multiregion"); len(o.Items) > 0 {
+		if err := parseMultiregion(&result.Multiregion, o); err != nil {
+			return multierror.Prefix(err, "multiregion ->")
+		}
+	}
+
+	// If we have a gc stanza, then parse that
+	if o := listVal.Filter("gc"); len(o.Items) > 0 {
+		if err := parseGC(&result.GC, o); err != nil {
+			return multierror.Prefix(err, "gc ->")
+		}
+	}
+
 	// If we have a reschedule stanza, then parse that
 	if o := listVal.Filter("reschedule"); len(o.Items) > 0 {
 		if err := parseReschedulePolicy(&result.Reschedule, o); err != nil {
@@ -315,6 +341,9 @@ func parseGroups(result *api.Job, list *ast.ObjectList) error {
 			"vault",
 			"migrate",
 			"spread",
+			"max_client_disconnect",
+			"volume",
+			"network",
 		}
 		if err := helper.CheckHCLKeys(listVal, valid); err != nil {
 			return multierror.Prefix(err, fmt.Sprintf("'%s' ->", n))
@@ -334,11 +363,21 @@ func parseGroups(result *api.Job, list *ast.ObjectList) error {
 		delete(m, "vault")
 		delete(m, "migrate")
 		delete(m, "spread")
+		delete(m, "volume")
+		delete(m, "network")
 
 		// Build the group with the basic decode
 		var g api.TaskGroup
 		g.Name = helper.StringToPtr(n)
-		if err := mapstructure.WeakDecode(m, &g); err != nil {
+		dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+			DecodeHook:       mapstructure.StringToTimeDurationHookFunc(),
+			WeaklyTypedInput: true,
+			Result:           &g,
+		})
+		if err != nil {
+			return err
+		}
+		if err := dec.Decode(m); err != nil {
 			return err
 		}
 
@@ -376,6 +415,21 @@ func parseGroups(result *api.Job, list *ast.ObjectList) error {
 				return multierror.Prefix(err, fmt.Sprintf("'%s', reschedule ->", n))
 			}
 		}
+
+		// Parse volumes
+		if o := listVal.Filter("volume"); len(o.Items) > 0 {
+			if err := parseVolumes(&g.Volumes, o); err != nil {
+				return multierror.Prefix(err, fmt.Sprintf("'%s', volume ->", n))
+			}
+		}
+
+		// Parse the group network
+		if o := listVal.Filter("network"); len(o.Items) > 0 {
+			if err := parseGroupNetwork(&g.Networks, o); err != nil {
+				return multierror.Prefix(err, fmt.Sprintf("'%s', network ->", n))
+			}
+		}
+
 		// Parse ephemeral disk
 		if o := listVal.Filter("ephemeral_disk"); len(o.Items) > 0 {
 			g.EphemeralDisk = &api.EphemeralDisk{}
@@ -721,6 +775,85 @@ func parseEphemeralDisk(result **api.EphemeralDisk, list *ast.ObjectList) error
 	return nil
 }
 
+func parseVolumes(result *map[string]*api.VolumeRequest, list *ast.ObjectList) error {
+	volumes := make(map[string]*api.VolumeRequest, len(list.Items))
+
+	for _, item := range list.Items {
+		// Ensure there is a name
+		if len(item.Keys) != 1 {
+			return fmt.Errorf("volume doesn't include a name")
+		}
+		name := item.Keys[0].Token.Value().(string)
+
+		if _, ok := volumes[name]; ok {
+			return fmt.Errorf("volume '%s' defined more than once", name)
+		}
+
+		// Check for invalid keys
+		valid := []string{
+			"type",
+			"source",
+			"read_only",
+		}
+		if err := helper.CheckHCLKeys(item.Val, valid); err != nil {
+			return multierror.Prefix(err, fmt.Sprintf("'%s' ->", name))
+		}
+
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, item.Val); err != nil {
+			return err
+		}
+
+		volume := api.VolumeRequest{Name: name}
+		if err := mapstructure.WeakDecode(m, &volume); err != nil {
+			return err
+		}
+		volumes[name] = &volume
+	}
+
+	*result = volumes
+	return nil
+}
+
+func parseGroupNetwork(result *[]*api.NetworkResource, list *ast.ObjectList) error {
+	if len(list.Items) > 1 {
+		return fmt.Errorf("only one 'network' block allowed")
+	}
+
+	// Check for invalid keys
+	valid := []string{
+		"mode",
+		"port",
+	}
+	if err := helper.CheckHCLKeys(list.Items[0].Val, valid); err != nil {
+		return err
+	}
+
+	var m map[string]interface{}
+	if err := hcl.DecodeObject(&m, list.Items[0].Val); err != nil {
+		return err
+	}
+	delete(m, "port")
+
+	var r api.NetworkResource
+	if err := mapstructure.WeakDecode(m, &r); err != nil {
+		return err
+	}
+
+	var networkObj *ast.ObjectList
+	if ot, ok := list.Items[0].Val.(*ast.ObjectType); ok {
+		networkObj = ot.List
+	} else {
+		return fmt.Errorf("should be an object")
+	}
+	if err := parsePorts(networkObj, &r); err != nil {
+		return multierror.Prefix(err, "ports ->")
+	}
+
+	*result = []*api.NetworkResource{&r}
+	return nil
+}
+
 func parseSpread(result *[]*api.Spread, list *ast.ObjectList) error {
 	for _, o := range list.Elem().Items {
 		// Check for invalid keys
@@ -856,7 +989,9 @@ func parseTasks(jobName string, taskGroupName string, result *[]*api.Task, list
 
 		// Check for invalid keys
 		valid := []string{
+			"action",
 			"artifact",
+			"checkpoint",
 			"config",
 			"constraint",
 			"affinity",
@@ -883,6 +1018,7 @@ func parseTasks(jobName string, taskGroupName string, result *[]*api.Task, list
 		if err := hcl.DecodeObject(&m, item.Val); err != nil {
 			return err
 		}
+		delete(m, "action")
 		delete(m, "artifact")
 		delete(m, "config")
 		delete(m, "constraint")
@@ -1030,6 +1166,13 @@ func parseTasks(jobName string, taskGroupName string, result *[]*api.Task, list
 			}
 		}
 
+		// Parse actions
+		if o := listVal.Filter("action"); len(o.Items) > 0 {
+			if err := parseActions(&t.Actions, o); err != nil {
+				return multierror.Prefix(err, fmt.Sprintf("'%s', action ->", n))
+			}
+		}
+
 		// If we have a vault block, then parse that
 		if o := listVal.Filter("vault"); len(o.Items) > 0 {
 			v := &api.Vault{
@@ -1143,6 +1286,46 @@ func parseArtifactOption(result map[string]string, list *ast.ObjectList) error {
 	return nil
 }
 
+func parseActions(result *[]*api.Action, list *ast.ObjectList) error {
+	if len(list.Elem().Items) > 0 {
+		return fmt.Errorf("action blocks must be named")
+	}
+
+	seen := make(map[string]struct{})
+	for _, item := range list.Children().Items {
+		n := item.Keys[0].Token.Value().(string)
+
+		// Make sure we haven't already found this
+		if _, ok := seen[n]; ok {
+			return fmt.Errorf("action '%s' defined more than once", n)
+		}
+		seen[n] = struct{}{}
+
+		// Check for invalid keys
+		valid := []string{
+			"args",
+			"command",
+		}
+		if err := helper.CheckHCLKeys(item.Val, valid); err != nil {
+			return multierror.Prefix(err, fmt.Sprintf("'%s' ->", n))
+		}
+
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, item.Val); err != nil {
+			return err
+		}
+
+		action := &api.Action{Name: n}
+		if err := mapstructure.WeakDecode(m, action); err != nil {
+			return err
+		}
+
+		*result = append(*result, action)
+	}
+
+	return nil
+}
+
 func parseTemplates(result *[]*api.Template, list *ast.ObjectList) error {
 	for _, o := range list.Elem().Items {
 		// Check for invalid keys
@@ -1203,6 +1386,7 @@ func parseServices(jobName string, taskGroupName string, task *api.Task, service
 			"port",
 			"check",
 			"address_mode",
+			"provider",
 			"check_restart",
 		}
 		if err := helper.CheckHCLKeys(o.Val, valid); err != nil {
@@ -1416,6 +1600,12 @@ func parseResources(result *api.Resources, list *ast.ObjectList) error {
 		"memory",
 		"network",
 		"device",
+		"numa",
+		"io_weight",
+		"io_limit",
+		"cpu_hard_limit",
+		"cpu_cfs_period",
+		"cores",
 	}
 	if err := helper.CheckHCLKeys(listVal, valid); err != nil {
 		return multierror.Prefix(err, "resources ->")
@@ -1427,6 +1617,8 @@ func parseResources(result *api.Resources, list *ast.ObjectList) error {
 	}
 	delete(m, "network")
 	delete(m, "device")
+	delete(m, "numa")
+	delete(m, "io_limit")
 
 	if err := mapstructure.WeakDecode(m, result); err != nil {
 		return err
@@ -1469,6 +1661,65 @@ func parseResources(result *api.Resources, list *ast.ObjectList) error {
 		result.Networks = []*api.NetworkResource{&r}
 	}
 
+	// Parse the NUMA placement
+	if o := listVal.Filter("numa"); len(o.Items) > 0 {
+		if len(o.Items) > 1 {
+			return fmt.Errorf("only one 'numa' block allowed")
+		}
+
+		// Check for invalid keys
+		valid := []string{
+			"affinity",
+		}
+		if err := helper.CheckHCLKeys(o.Items[0].Val, valid); err != nil {
+			return multierror.Prefix(err, "resources, numa ->")
+		}
+
+		var n api.NUMAResource
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, o.Items[0].Val); err != nil {
+			return err
+		}
+		if err := mapstructure.WeakDecode(m, &n); err != nil {
+			return err
+		}
+		result.NUMA = &n
+	}
+
+	// Parse the block I/O limits
+	if o := listVal.Filter("io_limit"); len(o.Items) > 0 {
+		result.IOLimits = make([]*api.IOLimit, len(o.Items))
+		for idx, lo := range o.Items {
+			if l := len(lo.Keys); l == 0 {
+				return multierror.Prefix(fmt.Errorf("missing device"), fmt.Sprintf("resources, io_limit[%d]->", idx))
+			} else if l > 1 {
+				return multierror.Prefix(fmt.Errorf("only one device may be specified"), fmt.Sprintf("resources, io_limit[%d]->", idx))
+			}
+
+			// Check for invalid keys
+			valid := []string{
+				"read_bps",
+				"write_bps",
+				"read_iops",
+				"write_iops",
+			}
+			if err := helper.CheckHCLKeys(lo.Val, valid); err != nil {
+				return multierror.Prefix(err, fmt.Sprintf("resources, io_limit[%d]->", idx))
+			}
+
+			var l api.IOLimit
+			var m map[string]interface{}
+			if err := hcl.DecodeObject(&m, lo.Val); err != nil {
+				return err
+			}
+			if err := mapstructure.WeakDecode(m, &l); err != nil {
+				return err
+			}
+			l.Device = lo.Keys[0].Token.Value().(string)
+			result.IOLimits[idx] = &l
+		}
+	}
+
 	// Parse the device resources
 	if o := listVal.Filter("device"); len(o.Items) > 0 {
 		result.Devices = make([]*api.RequestedDevice, len(o.Items))
@@ -1494,6 +1745,7 @@ func parseResources(result *api.Resources, list *ast.ObjectList) error {
 				"count",
 				"affinity",
 				"constraint",
+				"shared",
 			}
 			if err := helper.CheckHCLKeys(do.Val, valid); err != nil {
 				return multierror.Prefix(err, fmt.Sprintf("resources, device[%d]->", idx))
@@ -1536,16 +1788,9 @@ func parseResources(result *api.Resources, list *ast.ObjectList) error {
 	return nil
 }
 
+// parsePorts parses the port blocks of a network. The keys of the network must
+// already be checked.
 func parsePorts(networkObj *ast.ObjectList, nw *api.NetworkResource) error {
-	// Check for invalid keys
-	valid := []string{
-		"mbits",
-		"port",
-	}
-	if err := helper.CheckHCLKeys(networkObj, valid); err != nil {
-		return err
-	}
-
 	portsObjList := networkObj.Filter("port")
 	knownPortLabels := make(map[string]bool)
 	for _, port := range portsObjList.Items {
@@ -1603,12 +1848,31 @@ func parseUpdate(result **api.UpdateStrategy, list *ast.ObjectList) error {
 		"healthy_deadline",
 		"progress_deadline",
 		"auto_revert",
+		"auto_promote",
 		"canary",
 	}
 	if err := helper.CheckHCLKeys(o.Val, valid); err != nil {
 		return err
 	}
 
+	// max_parallel may be given as a percentage of the group count. An
+	// explicit count resets any percentage inherited from the job.
+	if raw, ok := m["max_parallel"]; ok {
+		if str, ok := raw.(string); ok && strings.HasSuffix(str, "%") {
+			percent, err := strconv.Atoi(strings.TrimSuffix(str, "%"))
+			if err != nil {
+				return fmt.Errorf("invalid max_parallel percentage %q: %v", str, err)
+			}
+			if percent < 1 || percent > 100 {
+				return fmt.Errorf("invalid max_parallel percentage %q: must be between 1%% and 100%%", str)
+			}
+			delete(m, "max_parallel")
+			m["max_parallel_percent"] = percent
+		} else {
+			m["max_parallel_percent"] = 0
+		}
+	}
+
 	dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
 		DecodeHook:       mapstructure.StringToTimeDurationHookFunc(),
 		WeaklyTypedInput: true,
@@ -1780,3 +2044,188 @@ func parseParameterizedJob(result **api.ParameterizedJobConfig, list *ast.Object
 	*result = &d
 	return nil
 }
+
+func parseGC(result **api.GCConfig, list *ast.ObjectList) error {
+	list = list.Elem()
+	if len(list.Items) > 1 {
+		return fmt.Errorf("only one 'gc' block allowed per job")
+	}
+
+	// Get our resource object
+	o := list.Items[0]
+
+	var m map[string]interface{}
+	if err := hcl.DecodeObject(&m, o.Val); err != nil {
+		return err
+	}
+
+	// Check for invalid keys
+	valid := []string{
+		"job_threshold",
+		"eval_threshold",
+		"alloc_threshold",
+	}
+	if err := helper.CheckHCLKeys(o.Val, valid); err != nil {
+		return err
+	}
+
+	// Build the gc block
+	var gc api.GCConfig
+	dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook:       mapstructure.StringToTimeDurationHookFunc(),
+		WeaklyTypedInput: true,
+		Result:           &gc,
+	})
+	if err != nil {
+		return err
+	}
+	if err := dec.Decode(m); err != nil {
+		return err
+	}
+
+	*result = &gc
+	return nil
+}
+
+func parseMultiregion(result **api.Multiregion, list *ast.ObjectList) error {
+	list = list.Elem()
+	if len(list.Items) > 1 {
+		return fmt.Errorf("only one 'multiregion' block allowed per job")
+	}
+
+	// Get our resource object
+	o := list.Items[0]
+
+	// We need this later
+	var listVal *ast.ObjectList
+	if ot, ok := o.Val.(*ast.ObjectType); ok {
+		listVal = ot.List
+	} else {
+		return fmt.Errorf("multiregion: should be an object")
+	}
+
+	// Check for invalid keys
+	valid := []string{
+		"strategy",
+		"region",
+	}
+	if err := helper.CheckHCLKeys(listVal, valid); err != nil {
+		return err
+	}
+
+	var mr api.Multiregion
+
+	// Parse the strategy
+	if o := listVal.Filter("strategy"); len(o.Items) > 0 {
+		if err := parseMultiregionStrategy(&mr.Strategy, o); err != nil {
+			return multierror.Prefix(err, "strategy ->")
+		}
+	}
+
+	// Parse the regions
+	if o := listVal.Filter("region"); len(o.Items) > 0 {
+		if err := parseMultiregionRegions(&mr.Regions, o); err != nil {
+			return multierror.Prefix(err, "region ->")
+		}
+	}
+
+	*result = &mr
+	return nil
+}
+
+func parseMultiregionStrategy(result **api.MultiregionStrategy, list *ast.ObjectList) error {
+	list = list.Elem()
+	if len(list.Items) > 1 {
+		return fmt.Errorf("only one 'strategy' block allowed per multiregion")
+	}
+
+	// Get our resource object
+	o := list.Items[0]
+
+	var m map[string]interface{}
+	if err := hcl.DecodeObject(&m, o.Val); err != nil {
+		return err
+	}
+
+	// Check for invalid keys
+	valid := []string{
+		"max_parallel",
+		"on_failure",
+	}
+	if err := helper.CheckHCLKeys(o.Val, valid); err != nil {
+		return err
+	}
+
+	var s api.MultiregionStrategy
+	if err := mapstructure.WeakDecode(m, &s); err != nil {
+		return err
+	}
+	*result = &s
+	return nil
+}
+
+func parseMultiregionRegions(result *[]*api.MultiregionRegion, list *ast.ObjectList) error {
+	list = list.Children()
+	if len(list.Items) == 0 {
+		return nil
+	}
+
+	// Go through each object and turn it into an actual result.
+	seen := make(map[string]struct{})
+	for _, item := range list.Items {
+		n := item.Keys[0].Token.Value().(string)
+
+		// Make sure we haven't already found this
+		if _, ok := seen[n]; ok {
+			return fmt.Errorf("region '%s' defined more than once", n)
+		}
+		seen[n] = struct{}{}
+
+		// We need this later
+		var listVal *ast.ObjectList
+		if ot, ok := item.Val.(*ast.ObjectType); ok {
+			listVal = ot.List
+		} else {
+			return fmt.Errorf("region '%s': should be an object", n)
+		}
+
+		// Check for invalid keys
+		valid := []string{
+			"count",
+			"datacenters",
+			"meta",
+		}
+		if err := helper.CheckHCLKeys(listVal, valid); err != nil {
+			return multierror.Prefix(err, fmt.Sprintf("'%s' ->", n))
+		}
+
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, item.Val); err != nil {
+			return err
+		}
+		delete(m, "meta")
+
+		r := &api.MultiregionRegion{Name: n}
+		if err := mapstructure.WeakDecode(m, r); err != nil {
+			return err
+		}
+
+		// Parse out meta fields. These are in HCL as a list so we need
+		// to iterate over them and merge them.
+		if metaO := listVal.Filter("meta"); len(metaO.Items) > 0 {
+			for _, o := range metaO.Elem().Items {
+				var m map[string]interface{}
+				if err := hcl.DecodeObject(&m, o.Val); err != nil {
+					return err
+				}
+				if err := mapstructure.WeakDecode(m, &r.Meta); err != nil {
+					return err
+				}
+			}
+		}
+
+		*result = append(*result, r)
+	}
+
+	return nil
+}
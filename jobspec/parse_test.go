@@ -30,6 +30,7 @@ func TestParse(t *testing.T) {
 				Priority:    helper.IntToPtr(52),
 				AllAtOnce:   helper.BoolToPtr(true),
 				Datacenters: []string{"us2", "eu1"},
+				NodePool:    helper.StringToPtr("gpu"),
 				Region:      helper.StringToPtr("fooregion"),
 				Namespace:   helper.StringToPtr("foonamespace"),
 				VaultToken:  helper.StringToPtr("foo"),
@@ -73,14 +74,16 @@ func TestParse(t *testing.T) {
 				},
 
 				Update: &api.UpdateStrategy{
-					Stagger:          helper.TimeToPtr(60 * time.Second),
-					MaxParallel:      helper.IntToPtr(2),
-					HealthCheck:      helper.StringToPtr("manual"),
-					MinHealthyTime:   helper.TimeToPtr(10 * time.Second),
-					HealthyDeadline:  helper.TimeToPtr(10 * time.Minute),
-					ProgressDeadline: helper.TimeToPtr(10 * time.Minute),
-					AutoRevert:       helper.BoolToPtr(true),
-					Canary:           helper.IntToPtr(1),
+					Stagger:            helper.TimeToPtr(60 * time.Second),
+					MaxParallel:        helper.IntToPtr(2),
+					MaxParallelPercent: helper.IntToPtr(0),
+					HealthCheck:        helper.StringToPtr("manual"),
+					MinHealthyTime:     helper.TimeToPtr(10 * time.Second),
+					HealthyDeadline:    helper.TimeToPtr(10 * time.Minute),
+					ProgressDeadline:   helper.TimeToPtr(10 * time.Minute),
+					AutoRevert:         helper.BoolToPtr(true),
+					AutoPromote:        helper.BoolToPtr(true),
+					Canary:             helper.IntToPtr(1),
 				},
 
 				TaskGroups: []*api.TaskGroup{
@@ -157,14 +160,29 @@ func TestParse(t *testing.T) {
 							Sticky: helper.BoolToPtr(true),
 							SizeMB: helper.IntToPtr(150),
 						},
+						MaxClientDisconnect: helper.TimeToPtr(2 * time.Hour),
+						Volumes: map[string]*api.VolumeRequest{
+							"data": {
+								Name:     "data",
+								Type:     "host",
+								Source:   "shared_data",
+								ReadOnly: true,
+							},
+						},
+						Networks: []*api.NetworkResource{
+							{
+								Mode: "cni/mynet",
+							},
+						},
 						Update: &api.UpdateStrategy{
-							MaxParallel:      helper.IntToPtr(3),
-							HealthCheck:      helper.StringToPtr("checks"),
-							MinHealthyTime:   helper.TimeToPtr(1 * time.Second),
-							HealthyDeadline:  helper.TimeToPtr(1 * time.Minute),
-							ProgressDeadline: helper.TimeToPtr(1 * time.Minute),
-							AutoRevert:       helper.BoolToPtr(false),
-							Canary:           helper.IntToPtr(2),
+							MaxParallel:        helper.IntToPtr(3),
+							MaxParallelPercent: helper.IntToPtr(0),
+							HealthCheck:        helper.StringToPtr("checks"),
+							MinHealthyTime:     helper.TimeToPtr(1 * time.Second),
+							HealthyDeadline:    helper.TimeToPtr(1 * time.Minute),
+							ProgressDeadline:   helper.TimeToPtr(1 * time.Minute),
+							AutoRevert:         helper.BoolToPtr(false),
+							Canary:             helper.IntToPtr(2),
 						},
 						Migrate: &api.MigrateStrategy{
 							MaxParallel:     helper.IntToPtr(2),
@@ -251,8 +269,9 @@ func TestParse(t *testing.T) {
 											},
 										},
 										{
-											Name:  "intel/gpu",
-											Count: nil,
+											Name:   "intel/gpu",
+											Count:  nil,
+											Shared: true,
 										},
 									},
 								},
@@ -305,6 +324,7 @@ func TestParse(t *testing.T) {
 									},
 								},
 								Leader:     true,
+								Checkpoint: true,
 								KillSignal: "",
 							},
 							{
@@ -318,6 +338,20 @@ func TestParse(t *testing.T) {
 									CPU:      helper.IntToPtr(500),
 									MemoryMB: helper.IntToPtr(128),
 									IOPS:     helper.IntToPtr(30),
+									NUMA: &api.NUMAResource{
+										Affinity: helper.StringToPtr("require"),
+									},
+									IOWeight: helper.IntToPtr(500),
+									IOLimits: []*api.IOLimit{
+										{
+											Device:    "/dev/sda",
+											ReadBps:   10485760,
+											WriteIOps: 100,
+										},
+									},
+									CPUHardLimit: helper.BoolToPtr(true),
+									CPUCFSPeriod: helper.IntToPtr(500000),
+									Cores:        helper.IntToPtr(2),
 								},
 								Constraints: []*api.Constraint{
 									{
@@ -496,6 +530,47 @@ func TestParse(t *testing.T) {
 			false,
 		},
 
+		{
+			"actions.hcl",
+			&api.Job{
+				ID:   helper.StringToPtr("foo"),
+				Name: helper.StringToPtr("foo"),
+				TaskGroups: []*api.TaskGroup{
+					{
+						Name: helper.StringToPtr("bar"),
+						Tasks: []*api.Task{
+							{
+								Name:   "bar",
+								Driver: "docker",
+								Config: map[string]interface{}{
+									"image": "redis",
+								},
+								Actions: []*api.Action{
+									{
+										Name:    "flush-cache",
+										Command: "redis-cli",
+										Args:    []string{"FLUSHALL"},
+									},
+									{
+										Name:    "info",
+										Command: "redis-cli",
+										Args:    []string{"INFO"},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			false,
+		},
+
+		{
+			"actions-duplicate.hcl",
+			nil,
+			true,
+		},
+
 		{
 			"bad-artifact.hcl",
 			nil,
@@ -662,6 +737,68 @@ func TestParse(t *testing.T) {
 			},
 			false,
 		},
+		{
+			"multiregion.hcl",
+			&api.Job{
+				ID:   helper.StringToPtr("multiregion_job"),
+				Name: helper.StringToPtr("multiregion_job"),
+				Multiregion: &api.Multiregion{
+					Strategy: &api.MultiregionStrategy{
+						MaxParallel: 1,
+						OnFailure:   "fail_all",
+					},
+					Regions: []*api.MultiregionRegion{
+						{
+							Name:        "west",
+							Count:       2,
+							Datacenters: []string{"west-1"},
+							Meta:        map[string]string{"region_code": "W"},
+						},
+						{
+							Name:        "east",
+							Count:       1,
+							Datacenters: []string{"east-1", "east-2"},
+						},
+					},
+				},
+				TaskGroups: []*api.TaskGroup{
+					{
+						Name: helper.StringToPtr("group"),
+						Tasks: []*api.Task{
+							{
+								Name:   "task",
+								Driver: "docker",
+							},
+						},
+					},
+				},
+			},
+			false,
+		},
+		{
+			"gc.hcl",
+			&api.Job{
+				ID:   helper.StringToPtr("gc_job"),
+				Name: helper.StringToPtr("gc_job"),
+				Type: helper.StringToPtr("batch"),
+				GC: &api.GCConfig{
+					JobThreshold:   168 * time.Hour,
+					AllocThreshold: 72 * time.Hour,
+				},
+				TaskGroups: []*api.TaskGroup{
+					{
+						Name: helper.StringToPtr("group"),
+						Tasks: []*api.Task{
+							{
+								Name:   "task",
+								Driver: "docker",
+							},
+						},
+					},
+				},
+			},
+			false,
+		},
 		{
 			"job-with-kill-signal.hcl",
 			&api.Job{
@@ -833,6 +970,43 @@ func TestParse(t *testing.T) {
 			},
 			false,
 		},
+		{
+			"update-max-parallel-percent.hcl",
+			&api.Job{
+				ID:          helper.StringToPtr("foo"),
+				Name:        helper.StringToPtr("foo"),
+				Datacenters: []string{"dc1"},
+				Update: &api.UpdateStrategy{
+					MaxParallelPercent: helper.IntToPtr(25),
+				},
+				TaskGroups: []*api.TaskGroup{
+					{
+						Name:  helper.StringToPtr("bar"),
+						Count: helper.IntToPtr(8),
+						Update: &api.UpdateStrategy{
+							MaxParallel:        helper.IntToPtr(3),
+							MaxParallelPercent: helper.IntToPtr(0),
+						},
+						Tasks: []*api.Task{
+							{
+								Name:   "bar",
+								Driver: "raw_exec",
+								Config: map[string]interface{}{
+									"command": "bash",
+									"args":    []interface{}{"-c", "echo hi"},
+								},
+							},
+						},
+					},
+				},
+			},
+			false,
+		},
+		{
+			"update-max-parallel-zero-percent.hcl",
+			nil,
+			true,
+		},
 		{
 			"migrate-job.hcl",
 			&api.Job{
@@ -871,6 +1045,65 @@ func TestParse(t *testing.T) {
 			},
 			false,
 		},
+		{
+			"tg-network-bridge.hcl",
+			&api.Job{
+				ID:          helper.StringToPtr("foo"),
+				Name:        helper.StringToPtr("foo"),
+				Datacenters: []string{"dc1"},
+				TaskGroups: []*api.TaskGroup{
+					{
+						Name: helper.StringToPtr("bar"),
+						Networks: []*api.NetworkResource{
+							{
+								Mode:          "bridge",
+								DynamicPorts:  []api.Port{{Label: "http", To: 8080}},
+								ReservedPorts: []api.Port{{Label: "metrics", Value: 9100}},
+							},
+						},
+						Tasks: []*api.Task{
+							{
+								Name:   "bar",
+								Driver: "exec",
+								Config: map[string]interface{}{
+									"command": "server",
+								},
+							},
+						},
+					},
+				},
+			},
+			false,
+		},
+		{
+			"tg-network-host-network.hcl",
+			&api.Job{
+				ID:          helper.StringToPtr("foo"),
+				Name:        helper.StringToPtr("foo"),
+				Datacenters: []string{"dc1"},
+				TaskGroups: []*api.TaskGroup{
+					{
+						Name: helper.StringToPtr("bar"),
+						Networks: []*api.NetworkResource{
+							{
+								DynamicPorts:  []api.Port{{Label: "admin", HostNetwork: "private"}},
+								ReservedPorts: []api.Port{{Label: "http", Value: 80, HostNetwork: "public"}},
+							},
+						},
+						Tasks: []*api.Task{
+							{
+								Name:   "bar",
+								Driver: "exec",
+								Config: map[string]interface{}{
+									"command": "server",
+								},
+							},
+						},
+					},
+				},
+			},
+			false,
+		},
 	}
 
 	for _, tc := range cases {
@@ -0,0 +1,13 @@
+job "foo" {
+  task "bar" {
+    driver = "docker"
+
+    action "info" {
+      command = "redis-cli"
+    }
+
+    action "info" {
+      command = "redis-cli"
+    }
+  }
+}
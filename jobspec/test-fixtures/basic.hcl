@@ -5,6 +5,7 @@ job "binstore-storagelocker" {
   priority    = 52
   all_at_once = true
   datacenters = ["us2", "eu1"]
+  node_pool   = "gpu"
   vault_token = "foo"
 
   meta {
@@ -42,6 +43,7 @@ job "binstore-storagelocker" {
     healthy_deadline = "10m"
     progress_deadline = "10m"
     auto_revert = true
+    auto_promote = true
     canary = 1
   }
 
@@ -60,6 +62,18 @@ job "binstore-storagelocker" {
   group "binsl" {
     count = 5
 
+    max_client_disconnect = "2h"
+
+    volume "data" {
+      type      = "host"
+      source    = "shared_data"
+      read_only = true
+    }
+
+    network {
+      mode = "cni/mynet"
+    }
+
     restart {
       attempts = 5
       interval = "10m"
@@ -120,6 +134,7 @@ job "binstore-storagelocker" {
       driver = "docker"
       user   = "bob"
       leader = true
+      checkpoint = true
 
       affinity {
         attribute = "${meta.foo}"
@@ -212,7 +227,9 @@ job "binstore-storagelocker" {
             }
         }
         
-        device "intel/gpu" {}
+        device "intel/gpu" {
+            shared = true
+        }
       }
 
       kill_timeout = "22s"
@@ -271,6 +288,21 @@ job "binstore-storagelocker" {
         cpu    = 500
         memory = 128
         iops   = 30
+
+        numa {
+          affinity = "require"
+        }
+
+        io_weight = 500
+
+        io_limit "/dev/sda" {
+          read_bps   = 10485760
+          write_iops = 100
+        }
+
+        cpu_hard_limit = true
+        cpu_cfs_period = 500000
+        cores          = 2
       }
 
       constraint {
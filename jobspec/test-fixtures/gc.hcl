@@ -0,0 +1,14 @@
+job "gc_job" {
+  type = "batch"
+
+  gc {
+    job_threshold   = "168h"
+    alloc_threshold = "72h"
+  }
+
+  group "group" {
+    task "task" {
+      driver = "docker"
+    }
+  }
+}
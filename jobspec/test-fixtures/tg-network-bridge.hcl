@@ -0,0 +1,25 @@
+job "foo" {
+  datacenters = ["dc1"]
+
+  group "bar" {
+    network {
+      mode = "bridge"
+
+      port "http" {
+        to = 8080
+      }
+
+      port "metrics" {
+        static = 9100
+      }
+    }
+
+    task "bar" {
+      driver = "exec"
+
+      config {
+        command = "server"
+      }
+    }
+  }
+}
@@ -0,0 +1,24 @@
+job "foo" {
+  datacenters = ["dc1"]
+
+  group "bar" {
+    network {
+      port "http" {
+        static       = 80
+        host_network = "public"
+      }
+
+      port "admin" {
+        host_network = "private"
+      }
+    }
+
+    task "bar" {
+      driver = "exec"
+
+      config {
+        command = "server"
+      }
+    }
+  }
+}
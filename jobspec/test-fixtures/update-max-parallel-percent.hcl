@@ -0,0 +1,21 @@
+job "foo" {
+  datacenters = ["dc1"]
+  update {
+      max_parallel = "25%"
+  }
+
+  group "bar" {
+    count = 8
+    task "bar" {
+      driver = "raw_exec"
+      config {
+         command = "bash"
+         args    = ["-c", "echo hi"]
+      }
+    }
+
+    update {
+        max_parallel = 3
+    }
+  }
+}
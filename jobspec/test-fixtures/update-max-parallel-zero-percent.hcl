@@ -0,0 +1,15 @@
+job "foo" {
+  datacenters = ["dc1"]
+  update {
+      max_parallel = "0%"
+  }
+
+  group "bar" {
+    task "bar" {
+      driver = "raw_exec"
+      config {
+         command = "bash"
+      }
+    }
+  }
+}
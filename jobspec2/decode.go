@@ -0,0 +1,421 @@
+package jobspec2
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/hashicorp/hcl/hcl/token"
+	"github.com/hashicorp/hcl2/hcl"
+	"github.com/hashicorp/hcl2/hcl/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+const (
+	// dynamicBlock is the block generating a block for each element of a
+	// collection
+	dynamicBlock = "dynamic"
+
+	// dynamicContentBlock is the block of a dynamic block holding the body of
+	// the generated blocks
+	dynamicContentBlock = "content"
+)
+
+// converter evaluates an HCL2 job spec and converts it into the syntax tree of
+// the HCL parser.
+type converter struct {
+	// src is the source of the job spec
+	src []byte
+
+	// root is the top-level body of the job spec
+	root *hclsyntax.Body
+
+	// baseDir is the directory files read by the job spec are relative to.
+	// It is empty if the job spec may not read files.
+	baseDir string
+}
+
+// body evaluates an HCL2 body into an HCL object list. The attributes and
+// blocks of the body are kept in the order they are declared in.
+func (c *converter) body(body *hclsyntax.Body, ctx *hcl.EvalContext, root bool) (*ast.ObjectList, hcl.Diagnostics) {
+	type entry struct {
+		start int
+		items []*ast.ObjectItem
+	}
+
+	var diags hcl.Diagnostics
+	entries := make([]entry, 0, len(body.Attributes)+len(body.Blocks))
+
+	for name, attr := range body.Attributes {
+		val, valDiags := c.eval(attr.Expr, ctx)
+		diags = append(diags, valDiags...)
+		if valDiags.HasErrors() || val.IsNull() {
+			continue
+		}
+
+		node, nodeDiags := c.node(val, attr.Expr.Range())
+		diags = append(diags, nodeDiags...)
+		if nodeDiags.HasErrors() {
+			continue
+		}
+
+		entries = append(entries, entry{
+			start: attr.SrcRange.Start.Byte,
+			items: []*ast.ObjectItem{{
+				Keys:   []*ast.ObjectKey{identKey(name, attr.NameRange)},
+				Assign: pos(attr.EqualsRange),
+				Val:    node,
+			}},
+		})
+	}
+
+	for _, block := range body.Blocks {
+		if root && block.Type == variableBlock {
+			continue
+		}
+
+		var items []*ast.ObjectItem
+		var blockDiags hcl.Diagnostics
+		if block.Type == dynamicBlock {
+			items, blockDiags = c.dynamic(block, ctx)
+		} else {
+			var item *ast.ObjectItem
+			item, blockDiags = c.block(block.Type, block.Labels, block, block.Body, ctx)
+			items = []*ast.ObjectItem{item}
+		}
+
+		diags = append(diags, blockDiags...)
+		if blockDiags.HasErrors() {
+			continue
+		}
+		entries = append(entries, entry{
+			start: block.TypeRange.Start.Byte,
+			items: items,
+		})
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].start < entries[j].start
+	})
+
+	list := &ast.ObjectList{}
+	for _, e := range entries {
+		list.Items = append(list.Items, e.items...)
+	}
+	return list, diags
+}
+
+// block converts a block with the given type, labels and body.
+func (c *converter) block(typ string, labels []string, block *hclsyntax.Block,
+	body *hclsyntax.Body, ctx *hcl.EvalContext) (*ast.ObjectItem, hcl.Diagnostics) {
+
+	list, diags := c.body(body, ctx, false)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	keys := make([]*ast.ObjectKey, 0, len(labels)+1)
+	keys = append(keys, identKey(typ, block.TypeRange))
+	for _, label := range labels {
+		keys = append(keys, stringKey(label, block.TypeRange))
+	}
+
+	item := &ast.ObjectItem{
+		Keys: keys,
+		Val: &ast.ObjectType{
+			Lbrace: pos(block.OpenBraceRange),
+			Rbrace: pos(block.CloseBraceRange),
+			List:   list,
+		},
+	}
+	return item, diags
+}
+
+// dynamic expands a dynamic block into a block for each element of its
+// for_each collection:
+//
+//   dynamic "<type>" {
+//     for_each = <collection>
+//     iterator = <name>
+//     labels   = [<label>, ...]
+//
+//     content {
+//       ...
+//     }
+//   }
+//
+// The element is available in the labels and content of the block as
+// <name>.key and <name>.value, <name> defaulting to the type of the block.
+func (c *converter) dynamic(block *hclsyntax.Block, ctx *hcl.EvalContext) ([]*ast.ObjectItem, hcl.Diagnostics) {
+	invalid := func(detail string, subject hcl.Range) hcl.Diagnostics {
+		return hcl.Diagnostics{{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid dynamic block",
+			Detail:   detail,
+			Subject:  subject.Ptr(),
+		}}
+	}
+
+	if len(block.Labels) != 1 {
+		return nil, invalid("A dynamic block must have a single label, the type of the generated blocks.", defRange(block))
+	}
+	typ := block.Labels[0]
+	body := block.Body
+
+	var content *hclsyntax.Block
+	for _, b := range body.Blocks {
+		if b.Type != dynamicContentBlock {
+			return nil, invalid(fmt.Sprintf("Blocks of type %q are not expected in a dynamic block.", b.Type), defRange(b))
+		}
+		if content != nil {
+			return nil, invalid("A dynamic block must have a single content block.", defRange(b))
+		}
+		content = b
+	}
+	if content == nil {
+		return nil, invalid("A dynamic block must have a content block.", defRange(block))
+	}
+
+	iterator := typ
+	var forEachExpr, labelsExpr hcl.Expression
+	for name, attr := range body.Attributes {
+		switch name {
+		case "for_each":
+			forEachExpr = attr.Expr
+		case "labels":
+			labelsExpr = attr.Expr
+		case "iterator":
+			iterator = hcl.ExprAsKeyword(attr.Expr)
+			if iterator == "" {
+				return nil, invalid("The iterator of a dynamic block must be an identifier.", attr.Expr.Range())
+			}
+		default:
+			return nil, invalid(fmt.Sprintf("An argument named %q is not expected in a dynamic block.", name), attr.NameRange)
+		}
+	}
+	if forEachExpr == nil {
+		return nil, invalid("A dynamic block must have a for_each argument.", defRange(block))
+	}
+
+	forEach, diags := forEachExpr.Value(ctx)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	if forEach.IsNull() || !forEach.IsKnown() || !forEach.CanIterateElements() {
+		return nil, invalid("The for_each argument of a dynamic block must be a list, set, map or object.", forEachExpr.Range())
+	}
+
+	var items []*ast.ObjectItem
+	for it := forEach.ElementIterator(); it.Next(); {
+		key, value := it.Element()
+
+		child := ctx.NewChild()
+		child.Variables = map[string]cty.Value{
+			iterator: cty.ObjectVal(map[string]cty.Value{
+				"key":   key,
+				"value": value,
+			}),
+		}
+
+		var labels []string
+		if labelsExpr != nil {
+			labelVals, diags := labelsExpr.Value(child)
+			if diags.HasErrors() {
+				return nil, diags
+			}
+			if labelVals.IsNull() || !labelVals.CanIterateElements() {
+				return nil, invalid("The labels argument of a dynamic block must be a list of strings.", labelsExpr.Range())
+			}
+			for lit := labelVals.ElementIterator(); lit.Next(); {
+				_, label := lit.Element()
+				if label.IsNull() || !label.Type().Equals(cty.String) {
+					return nil, invalid("The labels argument of a dynamic block must be a list of strings.", labelsExpr.Range())
+				}
+				labels = append(labels, label.AsString())
+			}
+		}
+
+		item, diags := c.block(typ, labels, content, content.Body, child)
+		if diags.HasErrors() {
+			return nil, diags
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// eval evaluates an expression. Interpolations in templates that reference
+// variables unknown to the job spec, such as ${attr.kernel.name} or
+// ${NOMAD_TASK_DIR}, are kept as is so they are interpolated by Nomad at
+// runtime.
+func (c *converter) eval(expr hclsyntax.Expression, ctx *hcl.EvalContext) (cty.Value, hcl.Diagnostics) {
+	known := make(map[string]struct{})
+	for cur := ctx; cur != nil; cur = cur.Parent() {
+		for name := range cur.Variables {
+			known[name] = struct{}{}
+		}
+	}
+
+	runtime := func(part hclsyntax.Expression) bool {
+		for _, traversal := range hclsyntax.Variables(part) {
+			if _, ok := known[traversal.RootName()]; !ok {
+				return true
+			}
+		}
+		return false
+	}
+
+	hclsyntax.VisitAll(expr, func(n hclsyntax.Node) hcl.Diagnostics {
+		switch t := n.(type) {
+		case *hclsyntax.TemplateExpr:
+			for i, part := range t.Parts {
+				if runtime(part) {
+					t.Parts[i] = c.literalInterpolation(part)
+				}
+			}
+		case *hclsyntax.TemplateWrapExpr:
+			if runtime(t.Wrapped) {
+				t.Wrapped = c.literalInterpolation(t.Wrapped)
+			}
+		}
+		return nil
+	})
+
+	return expr.Value(ctx)
+}
+
+// literalInterpolation returns the literal string of the interpolation of the
+// expression in a template.
+func (c *converter) literalInterpolation(expr hclsyntax.Expression) hclsyntax.Expression {
+	rng := expr.Range()
+	return &hclsyntax.LiteralValueExpr{
+		Val:      cty.StringVal("${" + string(rng.SliceBytes(c.src)) + "}"),
+		SrcRange: rng,
+	}
+}
+
+// node converts a value into an HCL node.
+func (c *converter) node(val cty.Value, rng hcl.Range) (ast.Node, hcl.Diagnostics) {
+	if !val.IsKnown() {
+		return nil, hcl.Diagnostics{{
+			Severity: hcl.DiagError,
+			Summary:  "Unknown value",
+			Detail:   "The value of the expression is not known.",
+			Subject:  rng.Ptr(),
+		}}
+	}
+
+	t := val.Type()
+	switch {
+	case t.Equals(cty.String):
+		return &ast.LiteralType{Token: token.Token{
+			Type: token.STRING,
+			Pos:  pos(rng),
+			Text: strconv.Quote(val.AsString()),
+			JSON: true,
+		}}, nil
+
+	case t.Equals(cty.Number):
+		f := val.AsBigFloat()
+		if f.IsInt() {
+			if i, acc := f.Int64(); acc == 0 {
+				return &ast.LiteralType{Token: token.Token{
+					Type: token.NUMBER,
+					Pos:  pos(rng),
+					Text: strconv.FormatInt(i, 10),
+				}}, nil
+			}
+		}
+		v, _ := f.Float64()
+		return &ast.LiteralType{Token: token.Token{
+			Type: token.FLOAT,
+			Pos:  pos(rng),
+			Text: strconv.FormatFloat(v, 'f', -1, 64),
+		}}, nil
+
+	case t.Equals(cty.Bool):
+		return &ast.LiteralType{Token: token.Token{
+			Type: token.BOOL,
+			Pos:  pos(rng),
+			Text: strconv.FormatBool(val.True()),
+		}}, nil
+
+	case t.IsListType() || t.IsSetType() || t.IsTupleType():
+		list := &ast.ListType{Lbrack: pos(rng)}
+		for it := val.ElementIterator(); it.Next(); {
+			_, elem := it.Element()
+			if elem.IsNull() {
+				continue
+			}
+			node, diags := c.node(elem, rng)
+			if diags.HasErrors() {
+				return nil, diags
+			}
+			list.Add(node)
+		}
+		return list, nil
+
+	case t.IsMapType() || t.IsObjectType():
+		obj := &ast.ObjectType{Lbrace: pos(rng), List: &ast.ObjectList{}}
+		for it := val.ElementIterator(); it.Next(); {
+			key, elem := it.Element()
+			if elem.IsNull() {
+				continue
+			}
+			node, diags := c.node(elem, rng)
+			if diags.HasErrors() {
+				return nil, diags
+			}
+			obj.List.Add(&ast.ObjectItem{
+				Keys:   []*ast.ObjectKey{stringKey(key.AsString(), rng)},
+				Assign: pos(rng),
+				Val:    node,
+			})
+		}
+		return obj, nil
+
+	default:
+		return nil, hcl.Diagnostics{{
+			Severity: hcl.DiagError,
+			Summary:  "Unsupported value",
+			Detail:   fmt.Sprintf("Values of type %s are not supported in job specs.", t.FriendlyName()),
+			Subject:  rng.Ptr(),
+		}}
+	}
+}
+
+// pos returns the HCL position of the start of the range.
+func pos(rng hcl.Range) token.Pos {
+	return token.Pos{
+		Filename: rng.Filename,
+		Offset:   rng.Start.Byte,
+		Line:     rng.Start.Line,
+		Column:   rng.Start.Column,
+	}
+}
+
+// identKey returns an object key for the identifier.
+func identKey(name string, rng hcl.Range) *ast.ObjectKey {
+	return &ast.ObjectKey{Token: token.Token{
+		Type: token.IDENT,
+		Pos:  pos(rng),
+		Text: name,
+	}}
+}
+
+// stringKey returns an object key for the string, such as a block label.
+func stringKey(s string, rng hcl.Range) *ast.ObjectKey {
+	return &ast.ObjectKey{Token: token.Token{
+		Type: token.STRING,
+		Pos:  pos(rng),
+		Text: strconv.Quote(s),
+		JSON: true,
+	}}
+}
+
+// defRange returns the range of the header of the block.
+func defRange(block *hclsyntax.Block) hcl.Range {
+	return hcl.RangeBetween(block.TypeRange, block.OpenBraceRange)
+}
@@ -0,0 +1,314 @@
+package jobspec2
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/hashicorp/hcl2/hcl"
+	"github.com/hashicorp/hcl2/hcl/hclsyntax"
+)
+
+// formatIndent is the indentation of each nesting level of a formatted job
+// spec.
+const formatIndent = "  "
+
+// Format returns the canonical formatting of the given job spec. Blocks and
+// brackets are indented by two spaces, the spacing between tokens is
+// normalized, the "=" of consecutive attributes are aligned and runs of blank
+// lines are collapsed. Strings, heredocs and comments are kept as written.
+// The job spec must be syntactically valid, and path is only used in error
+// messages.
+func Format(path string, src []byte) ([]byte, error) {
+	start := hcl.Pos{Line: 1, Column: 1}
+	if _, diags := hclsyntax.ParseConfig(src, path, start); diags.HasErrors() {
+		return nil, diags
+	}
+	tokens, diags := hclsyntax.LexConfig(src, path, start)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	lines := formatLines(src, tokens)
+	formatIndents(lines)
+	formatAlign(lines)
+
+	// Trailing blank lines are dropped
+	for len(lines) > 0 && len(lines[len(lines)-1].words) == 0 {
+		lines = lines[:len(lines)-1]
+	}
+
+	var buf bytes.Buffer
+	for i, line := range lines {
+		if len(line.words) == 0 {
+			// Only keep single blank lines separating items of a block
+			if i == 0 || len(lines[i-1].words) == 0 ||
+				lines[i-1].opens() || lines[i+1].closes() {
+				continue
+			}
+			buf.WriteString("\n")
+			continue
+		}
+
+		buf.WriteString(strings.Repeat(formatIndent, line.indent))
+		for j, w := range line.words {
+			if j > 0 {
+				buf.WriteString(strings.Repeat(" ", w.spaces))
+			}
+			buf.WriteString(w.text)
+		}
+		buf.WriteString("\n")
+	}
+	return buf.Bytes(), nil
+}
+
+// formatWord is a token of a line, or a sequence of tokens such as a string
+// or heredoc that is written as is.
+type formatWord struct {
+	typ  hclsyntax.TokenType
+	text string
+
+	// spaces is the number of spaces preceding the word on its line.
+	spaces int
+
+	// unary is whether the word is a unary operator.
+	unary bool
+}
+
+// formatLine is a line of a formatted job spec.
+type formatLine struct {
+	words  []*formatWord
+	indent int
+}
+
+// opens returns whether the line ends with an opening bracket, ignoring a
+// trailing comment.
+func (l *formatLine) opens() bool {
+	words := l.words
+	if n := len(words); n > 0 && words[n-1].typ == hclsyntax.TokenComment {
+		words = words[:n-1]
+	}
+	return len(words) > 0 && isOpening(words[len(words)-1].typ)
+}
+
+// closes returns whether the line starts with a closing bracket.
+func (l *formatLine) closes() bool {
+	return len(l.words) > 0 && isClosing(l.words[0].typ)
+}
+
+// isAttribute returns whether the line is the start of an attribute.
+func (l *formatLine) isAttribute() bool {
+	return len(l.words) > 1 && l.words[0].typ == hclsyntax.TokenIdent &&
+		l.words[1].typ == hclsyntax.TokenEqual
+}
+
+// formatLines splits the tokens into lines of words, normalizing the spacing
+// between the words.
+func formatLines(src []byte, tokens hclsyntax.Tokens) []*formatLine {
+	lines := []*formatLine{{}}
+	line := lines[0]
+	prevEnd := 0
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		switch tok.Type {
+		case hclsyntax.TokenEOF:
+			return lines
+		case hclsyntax.TokenNewline:
+			line = &formatLine{}
+			lines = append(lines, line)
+			prevEnd = tok.Range.End.Byte
+			continue
+		}
+
+		// Strings and heredocs are written as is, up to their closing token
+		start, end := tok.Range.Start.Byte, tok.Range.End.Byte
+		if open, close := tok.Type, closingQuote(tok.Type); close != 0 {
+			depth := 0
+			for ; i < len(tokens); i++ {
+				switch tokens[i].Type {
+				case open:
+					depth++
+				case close:
+					depth--
+				}
+				if depth == 0 || tokens[i].Type == hclsyntax.TokenEOF {
+					break
+				}
+			}
+			if i == len(tokens) {
+				i--
+			}
+			end = tokens[i].Range.End.Byte
+		}
+
+		w := &formatWord{
+			typ:  tok.Type,
+			text: strings.TrimRight(string(src[start:end]), " \t\r\n"),
+		}
+		if len(line.words) > 0 {
+			prev := line.words[len(line.words)-1]
+			w.unary = tok.Type == hclsyntax.TokenMinus && !isOperand(prev.typ)
+			w.spaces = wordSpaces(prev, w, start > prevEnd)
+		} else {
+			w.unary = tok.Type == hclsyntax.TokenMinus
+		}
+		line.words = append(line.words, w)
+		prevEnd = end
+
+		// Line comments include the line break
+		if tok.Type == hclsyntax.TokenComment && strings.HasSuffix(string(tok.Bytes), "\n") {
+			line = &formatLine{}
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// wordSpaces returns the number of spaces between the two words of a line.
+// spaced is whether the words were separated by whitespace in the source.
+func wordSpaces(prev, next *formatWord, spaced bool) int {
+	switch {
+	case next.typ == hclsyntax.TokenComment:
+		return 1
+	case next.typ == hclsyntax.TokenComma,
+		next.typ == hclsyntax.TokenCBrack, next.typ == hclsyntax.TokenCParen,
+		prev.typ == hclsyntax.TokenOBrack, prev.typ == hclsyntax.TokenOParen,
+		prev.typ == hclsyntax.TokenDot, next.typ == hclsyntax.TokenDot,
+		next.typ == hclsyntax.TokenEllipsis:
+		return 0
+	case prev.unary, prev.typ == hclsyntax.TokenBang:
+		return 0
+	case prev.typ == hclsyntax.TokenComma, isSpacedOperator(prev.typ),
+		!next.unary && isSpacedOperator(next.typ):
+		return 1
+	case next.typ == hclsyntax.TokenOBrace:
+		// Block and object openings are preceded by a space
+		return 1
+	case prev.typ == hclsyntax.TokenOBrace && next.typ == hclsyntax.TokenCBrace:
+		return 0
+	case spaced:
+		return 1
+	}
+	return 0
+}
+
+// formatIndents sets the indentation of the lines from the nesting of their
+// brackets. A line opening several brackets only increases the indentation
+// by one level.
+func formatIndents(lines []*formatLine) {
+	var stack []int
+	pop := func(closed int) {
+		for closed > 0 && len(stack) > 0 {
+			last := len(stack) - 1
+			if closed < stack[last] {
+				stack[last] -= closed
+				return
+			}
+			closed -= stack[last]
+			stack = stack[:last]
+		}
+	}
+
+	for _, line := range lines {
+		// Leading closing brackets dedent the line itself
+		leading := 0
+		for leading < len(line.words) && isClosing(line.words[leading].typ) {
+			leading++
+		}
+		pop(leading)
+		line.indent = len(stack)
+
+		net := 0
+		for _, w := range line.words[leading:] {
+			switch {
+			case isOpening(w.typ):
+				net++
+			case isClosing(w.typ):
+				net--
+			}
+		}
+		if net > 0 {
+			stack = append(stack, net)
+		} else {
+			pop(-net)
+		}
+	}
+}
+
+// formatAlign aligns the "=" of consecutive attributes at the same
+// indentation.
+func formatAlign(lines []*formatLine) {
+	for i := 0; i < len(lines); {
+		if !lines[i].isAttribute() {
+			i++
+			continue
+		}
+
+		j, width := i, 0
+		for ; j < len(lines) && lines[j].isAttribute() && lines[j].indent == lines[i].indent; j++ {
+			if n := len(lines[j].words[0].text); n > width {
+				width = n
+			}
+		}
+		for _, line := range lines[i:j] {
+			line.words[1].spaces = width - len(line.words[0].text) + 1
+		}
+		i = j
+	}
+}
+
+// closingQuote returns the token closing the string or heredoc opened by the
+// token, or 0 if the token doesn't open one.
+func closingQuote(typ hclsyntax.TokenType) hclsyntax.TokenType {
+	switch typ {
+	case hclsyntax.TokenOQuote:
+		return hclsyntax.TokenCQuote
+	case hclsyntax.TokenOHeredoc:
+		return hclsyntax.TokenCHeredoc
+	}
+	return 0
+}
+
+func isOpening(typ hclsyntax.TokenType) bool {
+	switch typ {
+	case hclsyntax.TokenOBrace, hclsyntax.TokenOBrack, hclsyntax.TokenOParen,
+		hclsyntax.TokenTemplateInterp, hclsyntax.TokenTemplateControl:
+		return true
+	}
+	return false
+}
+
+func isClosing(typ hclsyntax.TokenType) bool {
+	switch typ {
+	case hclsyntax.TokenCBrace, hclsyntax.TokenCBrack, hclsyntax.TokenCParen,
+		hclsyntax.TokenTemplateSeqEnd:
+		return true
+	}
+	return false
+}
+
+// isSpacedOperator returns whether the token is an operator surrounded by
+// spaces.
+func isSpacedOperator(typ hclsyntax.TokenType) bool {
+	switch typ {
+	case hclsyntax.TokenEqual, hclsyntax.TokenEqualOp, hclsyntax.TokenNotEqual,
+		hclsyntax.TokenLessThan, hclsyntax.TokenLessThanEq,
+		hclsyntax.TokenGreaterThan, hclsyntax.TokenGreaterThanEq,
+		hclsyntax.TokenAnd, hclsyntax.TokenOr, hclsyntax.TokenFatArrow,
+		hclsyntax.TokenPlus, hclsyntax.TokenMinus, hclsyntax.TokenSlash,
+		hclsyntax.TokenPercent, hclsyntax.TokenQuestion:
+		return true
+	}
+	return false
+}
+
+// isOperand returns whether an operator following the token is a binary
+// operator.
+func isOperand(typ hclsyntax.TokenType) bool {
+	switch typ {
+	case hclsyntax.TokenIdent, hclsyntax.TokenNumberLit, hclsyntax.TokenOQuote,
+		hclsyntax.TokenOHeredoc, hclsyntax.TokenCParen, hclsyntax.TokenCBrack,
+		hclsyntax.TokenCBrace:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,177 @@
+package jobspec2
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormat(t *testing.T) {
+	cases := []struct {
+		Name     string
+		Input    string
+		Expected string
+	}{
+		{
+			Name:     "empty",
+			Input:    "",
+			Expected: "",
+		},
+		{
+			Name: "indentation and spacing",
+			Input: `
+job "example"{
+    datacenters = [ "dc1",   "dc2" ]
+  group "cache" {
+  count=1+var.count
+      task "redis" {
+        config {
+          args = [
+          "--port",
+              upper( "x" )
+          ]
+        }
+      }
+  }
+}
+`,
+			Expected: `job "example" {
+  datacenters = ["dc1", "dc2"]
+  group "cache" {
+    count = 1 + var.count
+    task "redis" {
+      config {
+        args = [
+          "--port",
+          upper("x")
+        ]
+      }
+    }
+  }
+}
+`,
+		},
+		{
+			Name: "alignment",
+			Input: `task "web" {
+  driver = "docker"
+  kill_timeout = "20s"
+
+  leader = true
+  # comment
+  user = "nobody"
+  kill_signal = "SIGINT"
+}
+`,
+			Expected: `task "web" {
+  driver       = "docker"
+  kill_timeout = "20s"
+
+  leader = true
+  # comment
+  user        = "nobody"
+  kill_signal = "SIGINT"
+}
+`,
+		},
+		{
+			Name: "blank lines",
+			Input: `
+
+job "example" {
+
+  type = "batch"
+
+
+
+  # group
+  group "cache" {}
+
+}
+
+
+`,
+			Expected: `job "example" {
+  type = "batch"
+
+  # group
+  group "cache" {}
+}
+`,
+		},
+		{
+			Name: "strings comments and heredocs",
+			Input: `job "example" { # the job
+      meta {
+  path = "${NOMAD_TASK_DIR}/ x  "   /* path */
+      }
+  template {
+        data = <<EOH
+  key = {{ key "foo" }}
+EOH
+  }
+}
+`,
+			Expected: `job "example" { # the job
+  meta {
+    path = "${NOMAD_TASK_DIR}/ x  " /* path */
+  }
+  template {
+    data = <<EOH
+  key = {{ key "foo" }}
+EOH
+  }
+}
+`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			out, err := Format("test.hcl", []byte(c.Input))
+			require.NoError(t, err)
+			require.Equal(t, c.Expected, string(out))
+		})
+	}
+}
+
+func TestFormat_Invalid(t *testing.T) {
+	_, err := Format("test.hcl", []byte(`job "example" {`))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "test.hcl")
+}
+
+// TestFormat_Fixtures asserts formatting the job specs of the fixtures
+// doesn't change the job and is idempotent.
+func TestFormat_Fixtures(t *testing.T) {
+	files := []string{
+		"../jobspec/test-fixtures/basic.hcl",
+		"../jobspec/test-fixtures/artifacts.hcl",
+		"../jobspec/test-fixtures/task-nested-config.hcl",
+		"test-fixtures/variables.hcl",
+		"test-fixtures/dynamic.hcl",
+	}
+	envs := []string{"NOMAD_VAR_region=europe", "NOMAD_VAR_image=5.0", "NOMAD_VAR_count=2"}
+
+	for _, file := range files {
+		t.Run(filepath.Base(file), func(t *testing.T) {
+			body, err := ioutil.ReadFile(file)
+			require.NoError(t, err)
+
+			out, err := Format(file, body)
+			require.NoError(t, err)
+
+			again, err := Format(file, out)
+			require.NoError(t, err)
+			require.Equal(t, string(out), string(again))
+
+			expected, err := ParseWithConfig(&ParseConfig{Path: file, Body: body, Envs: envs})
+			require.NoError(t, err)
+			actual, err := ParseWithConfig(&ParseConfig{Path: file, Body: out, Envs: envs})
+			require.NoError(t, err)
+			require.Equal(t, expected, actual)
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package jobspec2
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+	"github.com/zclconf/go-cty/cty/function/stdlib"
+)
+
+// functions returns the functions that can be called in job specs. The file
+// function is only available if baseDir is set, relative paths being resolved
+// from it.
+func functions(baseDir string) map[string]function.Function {
+	funcs := map[string]function.Function{
+		"abs":        stdlib.AbsoluteFunc,
+		"coalesce":   stdlib.CoalesceFunc,
+		"concat":     stdlib.ConcatFunc,
+		"csvdecode":  stdlib.CSVDecodeFunc,
+		"format":     stdlib.FormatFunc,
+		"formatlist": stdlib.FormatListFunc,
+		"int":        stdlib.IntFunc,
+		"join":       joinFunc,
+		"jsondecode": stdlib.JSONDecodeFunc,
+		"jsonencode": stdlib.JSONEncodeFunc,
+		"length":     stdlib.LengthFunc,
+		"lower":      stdlib.LowerFunc,
+		"max":        stdlib.MaxFunc,
+		"min":        stdlib.MinFunc,
+		"reverse":    stdlib.ReverseFunc,
+		"split":      splitFunc,
+		"strlen":     stdlib.StrlenFunc,
+		"substr":     stdlib.SubstrFunc,
+		"upper":      stdlib.UpperFunc,
+	}
+
+	if baseDir != "" {
+		funcs["file"] = fileFunc(baseDir)
+	}
+	return funcs
+}
+
+// joinFunc concatenates the elements of a list of strings with a separator.
+var joinFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{Name: "separator", Type: cty.String},
+		{Name: "list", Type: cty.List(cty.String)},
+	},
+	Type: function.StaticReturnType(cty.String),
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		sep := args[0].AsString()
+		elems := make([]string, 0, args[1].LengthInt())
+		for it := args[1].ElementIterator(); it.Next(); {
+			_, v := it.Element()
+			if v.IsNull() {
+				return cty.NilVal, fmt.Errorf("cannot join a null string")
+			}
+			elems = append(elems, v.AsString())
+		}
+		return cty.StringVal(strings.Join(elems, sep)), nil
+	},
+})
+
+// splitFunc splits a string into a list of strings on a separator.
+var splitFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{Name: "separator", Type: cty.String},
+		{Name: "str", Type: cty.String},
+	},
+	Type: function.StaticReturnType(cty.List(cty.String)),
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		parts := strings.Split(args[1].AsString(), args[0].AsString())
+		elems := make([]cty.Value, len(parts))
+		for i, p := range parts {
+			elems[i] = cty.StringVal(p)
+		}
+		return cty.ListVal(elems), nil
+	},
+})
+
+// fileFunc returns a function reading the contents of a file, relative paths
+// being resolved from baseDir.
+func fileFunc(baseDir string) function.Function {
+	return function.New(&function.Spec{
+		Params: []function.Parameter{
+			{Name: "path", Type: cty.String},
+		},
+		Type: function.StaticReturnType(cty.String),
+		Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+			path := args[0].AsString()
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(baseDir, path)
+			}
+
+			content, err := ioutil.ReadFile(path)
+			if err != nil {
+				return cty.NilVal, fmt.Errorf("failed to read %q: %v", path, err)
+			}
+			return cty.StringVal(string(content)), nil
+		},
+	})
+}
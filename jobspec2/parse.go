@@ -0,0 +1,117 @@
+// Package jobspec2 parses job specifications written in HCL2.
+//
+// HCL2 job specs support typed input variables, built-in functions and
+// dynamic blocks. The HCL2 body is evaluated and converted into the syntax
+// tree of the HCL parser so the job is decoded by the jobspec package and
+// behaves exactly like a job spec written in HCL.
+package jobspec2
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/hashicorp/hcl2/hcl"
+	"github.com/hashicorp/hcl2/hcl/hclsyntax"
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/jobspec"
+)
+
+// ParseConfig is the configuration of the parsing of a job spec.
+type ParseConfig struct {
+	// Path is the path of the job spec, used in error messages and to
+	// resolve the paths of files read by the job spec.
+	Path string
+
+	// Body is the content of the job spec.
+	Body []byte
+
+	// AllowFS enables the functions that read from the file system.
+	AllowFS bool
+
+	// ArgVars are the values of variables in the "name=value" form, as given
+	// with the -var flag.
+	ArgVars []string
+
+	// VarFiles are the paths of files setting the values of variables.
+	VarFiles []string
+
+	// VarContent is the content of a variable file, used when the variables
+	// are not read from the file system.
+	VarContent string
+
+	// Envs are the environment variables in the "KEY=value" form. Variables
+	// are set by environment variables prefixed with NOMAD_VAR_.
+	Envs []string
+}
+
+// Parse parses the HCL2 job spec from the given io.Reader.
+func Parse(path string, r io.Reader) (*api.Job, error) {
+	var buf bytes.Buffer
+	if _, err := io.Copy(&buf, r); err != nil {
+		return nil, err
+	}
+
+	return ParseWithConfig(&ParseConfig{
+		Path:    path,
+		Body:    buf.Bytes(),
+		AllowFS: false,
+	})
+}
+
+// ParseFile parses the given path as an HCL2 job spec, reading the values of
+// its variables from the environment.
+func ParseFile(path string) (*api.Job, error) {
+	body, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseWithConfig(&ParseConfig{
+		Path:    path,
+		Body:    body,
+		AllowFS: true,
+		Envs:    os.Environ(),
+	})
+}
+
+// ParseWithConfig parses the HCL2 job spec described by the configuration.
+func ParseWithConfig(args *ParseConfig) (*api.Job, error) {
+	filename := args.Path
+	if filename == "" {
+		filename = "input.hcl"
+	}
+
+	file, diags := hclsyntax.ParseConfig(args.Body, filename, hcl.Pos{Line: 1, Column: 1})
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	body, ok := file.Body.(*hclsyntax.Body)
+	if !ok {
+		return nil, fmt.Errorf("error parsing: unexpected body type %T", file.Body)
+	}
+
+	c := &converter{
+		src:  args.Body,
+		root: body,
+	}
+	if args.AllowFS {
+		c.baseDir = filepath.Dir(filename)
+	}
+
+	ctx, diags := c.evalContext(args)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	list, diags := c.body(body, ctx, true)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	return jobspec.ParseAST(&ast.File{Node: list})
+}
@@ -0,0 +1,172 @@
+package jobspec2
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/jobspec"
+	"github.com/stretchr/testify/require"
+)
+
+func parseFixture(t *testing.T, file string, args *ParseConfig) error {
+	_, err := parseFixtureJob(t, file, args)
+	return err
+}
+
+func parseFixtureJob(t *testing.T, file string, args *ParseConfig) (*api.Job, error) {
+	path := filepath.Join("test-fixtures", file)
+	body, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+
+	if args == nil {
+		args = &ParseConfig{}
+	}
+	args.Path = path
+	args.Body = body
+	return ParseWithConfig(args)
+}
+
+// TestParse_HCL1Compatible asserts job specs written in HCL parse to the same
+// job with the HCL2 parser.
+func TestParse_HCL1Compatible(t *testing.T) {
+	files := []string{
+		"basic.hcl",
+		"actions.hcl",
+		"artifacts.hcl",
+		"gc.hcl",
+		"multiregion.hcl",
+		"parameterized_job.hcl",
+		"periodic-cron.hcl",
+		"task-nested-config.hcl",
+		"vault_inheritance.hcl",
+	}
+
+	for _, file := range files {
+		t.Run(file, func(t *testing.T) {
+			path := filepath.Join("../jobspec/test-fixtures", file)
+			expected, err := jobspec.ParseFile(path)
+			require.NoError(t, err)
+
+			body, err := ioutil.ReadFile(path)
+			require.NoError(t, err)
+			actual, err := ParseWithConfig(&ParseConfig{Path: path, Body: body})
+			require.NoError(t, err)
+			require.Equal(t, expected, actual)
+		})
+	}
+}
+
+func TestParse_Variables(t *testing.T) {
+	require := require.New(t)
+
+	job, err := parseFixtureJob(t, "variables.hcl", &ParseConfig{
+		ArgVars:  []string{"region=europe", "image=5.0"},
+		VarFiles: []string{"test-fixtures/variables.vars.hcl"},
+		Envs:     []string{"NOMAD_VAR_count=2", "NOMAD_VAR_undeclared=foo", "HOME=/root"},
+	})
+	require.NoError(err)
+
+	require.Equal("europe", *job.Region)
+	require.Equal([]string{"dc1"}, job.Datacenters)
+
+	// The variable file has precedence over the environment
+	require.Equal(3, *job.TaskGroups[0].Count)
+
+	// The -var flags have precedence over the variable files
+	task := job.TaskGroups[0].Tasks[0]
+	require.Equal("redis:5.0", task.Config["image"])
+
+	// Functions are evaluated
+	require.Equal("EUROPE", task.Env["REGION"])
+	require.Equal("dc1", task.Env["DCS"])
+
+	// Runtime interpolations are kept as is
+	require.Equal([]interface{}{"--dir", "${NOMAD_TASK_DIR}/data"}, task.Config["args"])
+	require.Equal("${attr.kernel.name}", task.Constraints[0].LTarget)
+}
+
+func TestParse_Variables_Errors(t *testing.T) {
+	cases := []struct {
+		name string
+		args *ParseConfig
+		err  string
+	}{
+		{
+			name: "unset",
+			args: &ParseConfig{},
+			err:  `A value must be set for variable "image"`,
+		},
+		{
+			name: "undefined var",
+			args: &ParseConfig{ArgVars: []string{"image=6.0", "foo=bar"}},
+			err:  `A "foo" variable was passed with -var but it is not declared`,
+		},
+		{
+			name: "invalid var",
+			args: &ParseConfig{ArgVars: []string{"image"}},
+			err:  `The -var option "image" must be in the form name=value`,
+		},
+		{
+			name: "invalid type",
+			args: &ParseConfig{ArgVars: []string{"image=6.0", "count=many"}},
+			err:  "Variables not allowed",
+		},
+		{
+			name: "undefined in file",
+			args: &ParseConfig{ArgVars: []string{"image=6.0"}, VarContent: "foo = 1\n"},
+			err:  `A "foo" variable was set in variables.hcl but it is not declared`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := parseFixture(t, "variables.hcl", c.args)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), c.err)
+		})
+	}
+}
+
+func TestParse_Dynamic(t *testing.T) {
+	require := require.New(t)
+
+	job, err := parseFixtureJob(t, "dynamic.hcl", nil)
+	require.NoError(err)
+
+	tasks := job.TaskGroups[0].Tasks
+	require.Len(tasks, 2)
+	require.Equal("api", tasks[0].Name)
+	require.Equal("example/api:latest", tasks[0].Config["image"])
+	require.Equal("8080", tasks[0].Meta["port"])
+	require.Equal("web", tasks[1].Name)
+	require.Equal("example/web:latest", tasks[1].Config["image"])
+	require.Equal("80", tasks[1].Meta["port"])
+}
+
+func TestParse_File(t *testing.T) {
+	require := require.New(t)
+
+	// Files may only be read if allowed
+	err := parseFixture(t, "file.hcl", nil)
+	require.Error(err)
+	require.Contains(err.Error(), `no function named "file"`)
+
+	job, err := parseFixtureJob(t, "file.hcl", &ParseConfig{AllowFS: true})
+	require.NoError(err)
+	tmpl := job.TaskGroups[0].Tasks[0].Templates[0]
+	require.Equal("key = {{ key \"service/key\" }}\n", *tmpl.EmbeddedTmpl)
+}
+
+func TestParse_UnknownVariable(t *testing.T) {
+	body := `
+job "example" {
+  datacenters = [dc1]
+}
+`
+	_, err := Parse("input.hcl", strings.NewReader(body))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `There is no variable named "dc1"`)
+}
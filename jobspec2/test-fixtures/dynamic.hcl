@@ -0,0 +1,31 @@
+variable "services" {
+  type = map(number)
+  default = {
+    api = 8080
+    web = 80
+  }
+}
+
+job "dynamic" {
+  datacenters = ["dc1"]
+
+  group "web" {
+    dynamic "task" {
+      for_each = var.services
+      iterator = svc
+      labels   = [svc.key]
+
+      content {
+        driver = "docker"
+
+        config {
+          image = "example/${svc.key}:latest"
+        }
+
+        meta {
+          port = svc.value
+        }
+      }
+    }
+  }
+}
@@ -0,0 +1,14 @@
+job "file" {
+  datacenters = ["dc1"]
+
+  group "web" {
+    task "web" {
+      driver = "docker"
+
+      template {
+        data        = file("./file.tpl")
+        destination = "local/config.txt"
+      }
+    }
+  }
+}
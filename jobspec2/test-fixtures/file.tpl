@@ -0,0 +1 @@
+key = {{ key "service/key" }}
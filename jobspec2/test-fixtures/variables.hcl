@@ -0,0 +1,47 @@
+variable "region" {
+  type    = string
+  default = "global"
+}
+
+variable "datacenters" {
+  type        = list(string)
+  description = "The datacenters the job runs in"
+  default     = ["dc1"]
+}
+
+variable "count" {
+  type    = number
+  default = 1
+}
+
+variable "image" {
+  type = string
+}
+
+job "example" {
+  region      = var.region
+  datacenters = var.datacenters
+
+  group "cache" {
+    count = var.count
+
+    task "redis" {
+      driver = "docker"
+
+      config {
+        image = "redis:${var.image}"
+        args  = ["--dir", "${NOMAD_TASK_DIR}/data"]
+      }
+
+      env {
+        REGION = upper(var.region)
+        DCS    = join(",", var.datacenters)
+      }
+
+      constraint {
+        attribute = "${attr.kernel.name}"
+        value     = "linux"
+      }
+    }
+  }
+}
@@ -0,0 +1,2 @@
+count = 3
+image = "6.0"
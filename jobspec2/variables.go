@@ -0,0 +1,416 @@
+package jobspec2
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl2/hcl"
+	"github.com/hashicorp/hcl2/hcl/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+	"github.com/zclconf/go-cty/cty/function"
+)
+
+const (
+	// variableBlock is the top-level block declaring an input variable
+	variableBlock = "variable"
+
+	// varEnvPrefix is the prefix of the environment variables setting the
+	// values of input variables
+	varEnvPrefix = "NOMAD_VAR_"
+)
+
+// variable is an input variable of a job spec.
+type variable struct {
+	Name        string
+	Description string
+
+	// Type is the type values of the variable are converted to. It is
+	// cty.DynamicPseudoType if the variable accepts any type.
+	Type cty.Type
+
+	// Value is the value of the variable, set from its default and
+	// overridden by the values given when parsing the job spec.
+	Value cty.Value
+
+	// set is whether a value was set for the variable
+	set bool
+
+	DeclRange hcl.Range
+}
+
+// setValue converts the value to the type of the variable and sets it.
+func (v *variable) setValue(val cty.Value, subject *hcl.Range) hcl.Diagnostics {
+	if !v.Type.Equals(cty.DynamicPseudoType) {
+		converted, err := convert.Convert(val, v.Type)
+		if err != nil {
+			return hcl.Diagnostics{{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid value for variable",
+				Detail:   fmt.Sprintf("The value of variable %q is not valid: %v.", v.Name, err),
+				Subject:  subject,
+			}}
+		}
+		val = converted
+	}
+
+	v.Value = val
+	v.set = true
+	return nil
+}
+
+// setRawValue sets the value of the variable from its string representation,
+// as given with the -var flag or an environment variable. The string is used
+// as is for string variables and parsed as an HCL expression otherwise.
+func (v *variable) setRawValue(raw, source string) hcl.Diagnostics {
+	if v.Type.Equals(cty.String) || v.Type.Equals(cty.DynamicPseudoType) {
+		return v.setValue(cty.StringVal(raw), nil)
+	}
+
+	expr, diags := hclsyntax.ParseExpression([]byte(raw), source, hcl.Pos{Line: 1, Column: 1})
+	if diags.HasErrors() {
+		return diags
+	}
+	val, diags := expr.Value(nil)
+	if diags.HasErrors() {
+		return diags
+	}
+	return v.setValue(val, expr.Range().Ptr())
+}
+
+// evalContext returns the context the job spec is evaluated in, with the
+// values of its variables and the built-in functions.
+func (c *converter) evalContext(args *ParseConfig) (*hcl.EvalContext, hcl.Diagnostics) {
+	funcs := functions(c.baseDir)
+
+	vars, diags := c.decodeVariables(funcs)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	// Environment variables have the lowest precedence after the defaults,
+	// followed by the variable files and the -var flags
+	for _, env := range args.Envs {
+		if !strings.HasPrefix(env, varEnvPrefix) {
+			continue
+		}
+		kv := strings.SplitN(strings.TrimPrefix(env, varEnvPrefix), "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+
+		// Environment variables of undeclared variables are ignored as they
+		// may be meant for other job specs
+		if v, ok := vars[kv[0]]; ok {
+			diags = append(diags, v.setRawValue(kv[1], varEnvPrefix+kv[0])...)
+		}
+	}
+
+	for _, path := range args.VarFiles {
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Failed to read variable file",
+				Detail:   fmt.Sprintf("The variable file %q could not be read: %v.", path, err),
+			})
+			continue
+		}
+		diags = append(diags, decodeVarFile(vars, path, content, funcs)...)
+	}
+
+	if args.VarContent != "" {
+		diags = append(diags, decodeVarFile(vars, "variables.hcl", []byte(args.VarContent), funcs)...)
+	}
+
+	for _, raw := range args.ArgVars {
+		kv := strings.SplitN(raw, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid -var option",
+				Detail:   fmt.Sprintf("The -var option %q must be in the form name=value.", raw),
+			})
+			continue
+		}
+
+		v, ok := vars[kv[0]]
+		if !ok {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Undefined -var variable",
+				Detail:   fmt.Sprintf("A %q variable was passed with -var but it is not declared in the job spec.", kv[0]),
+			})
+			continue
+		}
+		diags = append(diags, v.setRawValue(kv[1], "-var "+kv[0])...)
+	}
+
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	names := make([]string, 0, len(vars))
+	for name := range vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	values := make(map[string]cty.Value, len(vars))
+	for _, name := range names {
+		v := vars[name]
+		if !v.set {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Unset variable",
+				Detail: fmt.Sprintf("A value must be set for variable %q with -var, -var-file or the %s%s environment variable.",
+					name, varEnvPrefix, name),
+				Subject: v.DeclRange.Ptr(),
+			})
+			continue
+		}
+		values[name] = v.Value
+	}
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	ctx := &hcl.EvalContext{
+		Variables: map[string]cty.Value{
+			"var": cty.ObjectVal(values),
+		},
+		Functions: funcs,
+	}
+	return ctx, nil
+}
+
+// decodeVariables decodes the variable blocks of the job spec.
+func (c *converter) decodeVariables(funcs map[string]function.Function) (map[string]*variable, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+	vars := make(map[string]*variable)
+
+	for _, block := range c.root.Blocks {
+		if block.Type != variableBlock {
+			continue
+		}
+
+		if len(block.Labels) != 1 || !hclsyntax.ValidIdentifier(block.Labels[0]) {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid variable block",
+				Detail:   "A variable block must have a single label that is a valid identifier.",
+				Subject:  defRange(block).Ptr(),
+			})
+			continue
+		}
+
+		name := block.Labels[0]
+		if prev, ok := vars[name]; ok {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Duplicate variable",
+				Detail:   fmt.Sprintf("The variable %q was already declared at %s.", name, prev.DeclRange),
+				Subject:  defRange(block).Ptr(),
+			})
+			continue
+		}
+
+		v, vDiags := decodeVariable(name, block, funcs)
+		diags = append(diags, vDiags...)
+		if v != nil {
+			vars[name] = v
+		}
+	}
+
+	return vars, diags
+}
+
+// decodeVariable decodes a variable block.
+func decodeVariable(name string, block *hclsyntax.Block, funcs map[string]function.Function) (*variable, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+	v := &variable{
+		Name:      name,
+		Type:      cty.DynamicPseudoType,
+		DeclRange: defRange(block),
+	}
+
+	for _, b := range block.Body.Blocks {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Unsupported block type",
+			Detail:   fmt.Sprintf("Blocks of type %q are not expected in a variable block.", b.Type),
+			Subject:  defRange(b).Ptr(),
+		})
+	}
+
+	if attr, ok := block.Body.Attributes["description"]; ok {
+		val, valDiags := attr.Expr.Value(nil)
+		diags = append(diags, valDiags...)
+		if !valDiags.HasErrors() {
+			if val, err := convert.Convert(val, cty.String); err == nil && !val.IsNull() {
+				v.Description = val.AsString()
+			}
+		}
+	}
+
+	if attr, ok := block.Body.Attributes["type"]; ok {
+		t, tDiags := parseVarType(attr.Expr)
+		diags = append(diags, tDiags...)
+		v.Type = t
+	}
+
+	if attr, ok := block.Body.Attributes["default"]; ok {
+		val, valDiags := attr.Expr.Value(&hcl.EvalContext{Functions: funcs})
+		diags = append(diags, valDiags...)
+		if !valDiags.HasErrors() {
+			diags = append(diags, v.setValue(val, attr.Expr.Range().Ptr())...)
+		}
+	}
+
+	for attrName, attr := range block.Body.Attributes {
+		switch attrName {
+		case "description", "type", "default":
+		default:
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Unsupported argument",
+				Detail:   fmt.Sprintf("An argument named %q is not expected in a variable block.", attrName),
+				Subject:  attr.NameRange.Ptr(),
+			})
+		}
+	}
+
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	return v, nil
+}
+
+// decodeVarFile sets the values of the variables from the attributes of a
+// variable file.
+func decodeVarFile(vars map[string]*variable, path string, content []byte, funcs map[string]function.Function) hcl.Diagnostics {
+	file, diags := hclsyntax.ParseConfig(content, path, hcl.Pos{Line: 1, Column: 1})
+	if diags.HasErrors() {
+		return diags
+	}
+
+	attrs, diags := file.Body.JustAttributes()
+	if diags.HasErrors() {
+		return diags
+	}
+
+	ctx := &hcl.EvalContext{Functions: funcs}
+	for name, attr := range attrs {
+		v, ok := vars[name]
+		if !ok {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Undefined variable",
+				Detail:   fmt.Sprintf("A %q variable was set in %s but it is not declared in the job spec.", name, path),
+				Subject:  attr.NameRange.Ptr(),
+			})
+			continue
+		}
+
+		val, valDiags := attr.Expr.Value(ctx)
+		diags = append(diags, valDiags...)
+		if !valDiags.HasErrors() {
+			diags = append(diags, v.setValue(val, attr.Expr.Range().Ptr())...)
+		}
+	}
+	return diags
+}
+
+// parseVarType parses the type constraint of a variable. The primitive types
+// string, number and bool, the any type and the list, set, map, object and
+// tuple type constructors are supported.
+func parseVarType(expr hcl.Expression) (cty.Type, hcl.Diagnostics) {
+	invalid := func(detail string) (cty.Type, hcl.Diagnostics) {
+		return cty.DynamicPseudoType, hcl.Diagnostics{{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid type specification",
+			Detail:   detail,
+			Subject:  expr.Range().Ptr(),
+		}}
+	}
+
+	switch hcl.ExprAsKeyword(expr) {
+	case "string":
+		return cty.String, nil
+	case "number":
+		return cty.Number, nil
+	case "bool":
+		return cty.Bool, nil
+	case "any":
+		return cty.DynamicPseudoType, nil
+	case "list", "set", "map", "object", "tuple":
+		return invalid(fmt.Sprintf("The %s type constructor requires one argument.", hcl.ExprAsKeyword(expr)))
+	case "":
+	default:
+		return invalid(fmt.Sprintf("The keyword %q is not a valid type.", hcl.ExprAsKeyword(expr)))
+	}
+
+	call, diags := hcl.ExprCall(expr)
+	if diags.HasErrors() {
+		return invalid("A type must be a primitive type keyword or a type constructor such as list(string).")
+	}
+	if len(call.Arguments) != 1 {
+		return invalid(fmt.Sprintf("The %s type constructor requires one argument.", call.Name))
+	}
+	arg := call.Arguments[0]
+
+	switch call.Name {
+	case "list", "set", "map":
+		elem, diags := parseVarType(arg)
+		if diags.HasErrors() {
+			return cty.DynamicPseudoType, diags
+		}
+		switch call.Name {
+		case "list":
+			return cty.List(elem), nil
+		case "set":
+			return cty.Set(elem), nil
+		default:
+			return cty.Map(elem), nil
+		}
+
+	case "object":
+		pairs, diags := hcl.ExprMap(arg)
+		if diags.HasErrors() {
+			return invalid("The object type constructor requires an object of attribute types.")
+		}
+		attrs := make(map[string]cty.Type, len(pairs))
+		for _, pair := range pairs {
+			name := hcl.ExprAsKeyword(pair.Key)
+			if name == "" {
+				return invalid("Object attribute names must be identifiers.")
+			}
+			t, diags := parseVarType(pair.Value)
+			if diags.HasErrors() {
+				return cty.DynamicPseudoType, diags
+			}
+			attrs[name] = t
+		}
+		return cty.Object(attrs), nil
+
+	case "tuple":
+		exprs, diags := hcl.ExprList(arg)
+		if diags.HasErrors() {
+			return invalid("The tuple type constructor requires a list of element types.")
+		}
+		elems := make([]cty.Type, len(exprs))
+		for i, e := range exprs {
+			t, diags := parseVarType(e)
+			if diags.HasErrors() {
+				return cty.DynamicPseudoType, diags
+			}
+			elems[i] = t
+		}
+		return cty.Tuple(elems), nil
+
+	default:
+		return invalid(fmt.Sprintf("The type constructor %q is not supported.", call.Name))
+	}
+}
@@ -0,0 +1,233 @@
+// Package authtest provides an OIDC provider for testing auth methods.
+package authtest
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/lib/auth"
+)
+
+const (
+	// TestOIDCClientID and TestOIDCClientSecret are the client credentials
+	// accepted by the test OIDC provider.
+	TestOIDCClientID     = "nomad"
+	TestOIDCClientSecret = "nomad-secret"
+
+	// TestKeyID is the key ID of the signing key of the test provider.
+	TestKeyID = "test-key"
+
+	// discoveryPath is the path of the provider configuration.
+	discoveryPath = "/.well-known/openid-configuration"
+)
+
+// TestOIDCProvider is an OIDC provider for tests. Users are authenticated
+// without interaction: the authorization endpoint redirects immediately with
+// an authorization code, and the ID tokens have the claims set with
+// SetClaims. Tokens are signed using ES256.
+type TestOIDCProvider struct {
+	server *httptest.Server
+	key    *ecdsa.PrivateKey
+
+	mu     sync.Mutex
+	claims auth.Claims
+	codes  map[string]*testAuthCode
+}
+
+// testAuthCode is an authorization code issued by the test provider.
+type testAuthCode struct {
+	nonce       string
+	redirectURI string
+}
+
+// NewTestOIDCProvider starts a test OIDC provider. It must be closed.
+func NewTestOIDCProvider() *TestOIDCProvider {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	p := &TestOIDCProvider{
+		key:    key,
+		claims: auth.Claims{"sub": "test-user"},
+		codes:  make(map[string]*testAuthCode),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(discoveryPath, p.handleDiscovery)
+	mux.HandleFunc("/authorize", p.handleAuthorize)
+	mux.HandleFunc("/token", p.handleToken)
+	mux.HandleFunc("/keys", p.handleKeys)
+	p.server = httptest.NewServer(mux)
+	return p
+}
+
+// URL returns the issuer and discovery URL of the provider.
+func (p *TestOIDCProvider) URL() string {
+	return p.server.URL
+}
+
+// JWKSURL returns the URL of the JSON Web Key Set of the provider.
+func (p *TestOIDCProvider) JWKSURL() string {
+	return p.server.URL + "/keys"
+}
+
+// PublicKeyPEM returns the PEM encoded public key signing the tokens of the
+// provider.
+func (p *TestOIDCProvider) PublicKeyPEM() string {
+	der, err := x509.MarshalPKIXPublicKey(&p.key.PublicKey)
+	if err != nil {
+		panic(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+}
+
+// Close stops the provider.
+func (p *TestOIDCProvider) Close() {
+	p.server.Close()
+}
+
+// SetClaims sets the claims included in the ID tokens, in addition to the
+// registered claims.
+func (p *TestOIDCProvider) SetClaims(claims auth.Claims) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.claims = claims
+}
+
+// PublicKey returns the public key signing the tokens of the provider.
+func (p *TestOIDCProvider) PublicKey() crypto.PublicKey {
+	return &p.key.PublicKey
+}
+
+// SignToken returns a token with the claims signed by the provider.
+func (p *TestOIDCProvider) SignToken(claims auth.Claims) string {
+	header, _ := json.Marshal(map[string]string{"alg": "ES256", "kid": TestKeyID, "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." +
+		base64.RawURLEncoding.EncodeToString(payload)
+
+	digest := sha256.Sum256([]byte(signed))
+	r, s, err := ecdsa.Sign(rand.Reader, p.key, digest[:])
+	if err != nil {
+		panic(err)
+	}
+	sig := make([]byte, 64)
+	r.FillBytes(sig[:32])
+	s.FillBytes(sig[32:])
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func (p *TestOIDCProvider) handleDiscovery(w http.ResponseWriter, r *http.Request) {
+	writeTestJSON(w, map[string]string{
+		"issuer":                 p.URL(),
+		"authorization_endpoint": p.URL() + "/authorize",
+		"token_endpoint":         p.URL() + "/token",
+		"jwks_uri":               p.URL() + "/keys",
+	})
+}
+
+func (p *TestOIDCProvider) handleKeys(w http.ResponseWriter, r *http.Request) {
+	size := 32
+	x := make([]byte, size)
+	y := make([]byte, size)
+	p.key.PublicKey.X.FillBytes(x)
+	p.key.PublicKey.Y.FillBytes(y)
+	writeTestJSON(w, map[string]interface{}{
+		"keys": []map[string]string{{
+			"kid": TestKeyID,
+			"kty": "EC",
+			"alg": "ES256",
+			"use": "sig",
+			"crv": "P-256",
+			"x":   base64.RawURLEncoding.EncodeToString(x),
+			"y":   base64.RawURLEncoding.EncodeToString(y),
+		}},
+	})
+}
+
+// handleAuthorize authenticates the user and redirects with an
+// authorization code.
+func (p *TestOIDCProvider) handleAuthorize(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if q.Get("client_id") != TestOIDCClientID {
+		http.Error(w, "invalid client_id", http.StatusBadRequest)
+		return
+	}
+	redirectURI, err := url.Parse(q.Get("redirect_uri"))
+	if err != nil || redirectURI.Host == "" {
+		http.Error(w, "invalid redirect_uri", http.StatusBadRequest)
+		return
+	}
+
+	code := uuid.Generate()
+	p.mu.Lock()
+	p.codes[code] = &testAuthCode{nonce: q.Get("nonce"), redirectURI: q.Get("redirect_uri")}
+	p.mu.Unlock()
+
+	v := redirectURI.Query()
+	v.Set("code", code)
+	v.Set("state", q.Get("state"))
+	redirectURI.RawQuery = v.Encode()
+	http.Redirect(w, r, redirectURI.String(), http.StatusFound)
+}
+
+// handleToken exchanges an authorization code for an ID token.
+func (p *TestOIDCProvider) handleToken(w http.ResponseWriter, r *http.Request) {
+	clientID, clientSecret, ok := r.BasicAuth()
+	if !ok || clientID != TestOIDCClientID || clientSecret != TestOIDCClientSecret {
+		http.Error(w, "invalid client credentials", http.StatusUnauthorized)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	p.mu.Lock()
+	code, ok := p.codes[r.PostForm.Get("code")]
+	delete(p.codes, r.PostForm.Get("code"))
+	claims := make(auth.Claims, len(p.claims))
+	for k, v := range p.claims {
+		claims[k] = v
+	}
+	p.mu.Unlock()
+
+	if !ok || code.redirectURI != r.PostForm.Get("redirect_uri") {
+		http.Error(w, "invalid authorization code", http.StatusBadRequest)
+		return
+	}
+
+	now := time.Now()
+	claims["iss"] = p.URL()
+	claims["aud"] = TestOIDCClientID
+	claims["iat"] = now.Unix()
+	claims["exp"] = now.Add(5 * time.Minute).Unix()
+	claims["nonce"] = code.nonce
+
+	writeTestJSON(w, map[string]string{
+		"access_token": uuid.Generate(),
+		"token_type":   "Bearer",
+		"id_token":     p.SignToken(claims),
+	})
+}
+
+func writeTestJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		panic(fmt.Sprintf("failed to encode response: %v", err))
+	}
+}
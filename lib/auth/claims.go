@@ -0,0 +1,104 @@
+package auth
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SelectorData is the data binding rule selectors are evaluated against. It
+// holds the claims of a token mapped to names by the claim mappings of an
+// auth method.
+type SelectorData struct {
+	// Value holds the claims mapped by the claim mappings, available to
+	// selectors as value.<name>.
+	Value map[string]string
+
+	// List holds the claims mapped by the list claim mappings, available to
+	// selectors as list.<name>.
+	List map[string][]string
+}
+
+// NewSelectorData returns the selector data of the claims. The keys of the
+// mappings are claim names, or JSON pointers such as "/groups/nested" to
+// nested claims, and the values the names the claims are mapped to. Claims
+// that don't exist are ignored.
+func NewSelectorData(claims Claims, claimMappings, listClaimMappings map[string]string) (*SelectorData, error) {
+	data := &SelectorData{
+		Value: make(map[string]string, len(claimMappings)),
+		List:  make(map[string][]string, len(listClaimMappings)),
+	}
+
+	for claim, name := range claimMappings {
+		raw, ok := claims.lookup(claim)
+		if !ok {
+			continue
+		}
+		v, err := stringify(raw)
+		if err != nil {
+			return nil, fmt.Errorf("error converting claim %q: %v", claim, err)
+		}
+		data.Value[name] = v
+	}
+
+	for claim, name := range listClaimMappings {
+		raw, ok := claims.lookup(claim)
+		if !ok {
+			continue
+		}
+		items, ok := raw.([]interface{})
+		if !ok {
+			items = []interface{}{raw}
+		}
+		list := make([]string, 0, len(items))
+		for _, item := range items {
+			v, err := stringify(item)
+			if err != nil {
+				return nil, fmt.Errorf("error converting claim %q: %v", claim, err)
+			}
+			list = append(list, v)
+		}
+		data.List[name] = list
+	}
+
+	return data, nil
+}
+
+// lookup returns the claim, which is either the name of a top level claim or
+// a JSON pointer to a nested claim.
+func (c Claims) lookup(claim string) (interface{}, bool) {
+	if !strings.HasPrefix(claim, "/") {
+		v, ok := c[claim]
+		return v, ok
+	}
+
+	var cur interface{} = map[string]interface{}(c)
+	for _, part := range strings.Split(claim[1:], "/") {
+		// Unescape the reference token as described in RFC 6901
+		part = strings.Replace(part, "~1", "/", -1)
+		part = strings.Replace(part, "~0", "~", -1)
+
+		m, ok := cur.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if cur, ok = m[part]; !ok {
+			return nil, false
+		}
+	}
+	return cur, true
+}
+
+// stringify converts a scalar claim to a string.
+func stringify(v interface{}) (string, error) {
+	switch t := v.(type) {
+	case string:
+		return t, nil
+	case bool:
+		return strconv.FormatBool(t), nil
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64), nil
+	default:
+		return "", fmt.Errorf("unsupported type %T", v)
+	}
+}
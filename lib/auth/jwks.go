@@ -0,0 +1,189 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+)
+
+// jsonWebKey is a public key of a JSON Web Key Set, as described in RFC 7517.
+type jsonWebKey struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+
+	// RSA keys
+	N string `json:"n"`
+	E string `json:"e"`
+
+	// Elliptic curve keys
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// publicKey is a parsed key of a key set.
+type publicKey struct {
+	kid string
+	key crypto.PublicKey
+}
+
+// KeySet is a set of public keys used to verify the signatures of JSON Web
+// Tokens.
+type KeySet struct {
+	keys []*publicKey
+
+	// ignoreKid is set for configured public keys, which have no key ID and
+	// may verify any token.
+	ignoreKid bool
+}
+
+// ParseKeySet parses a JSON Web Key Set. Keys that aren't used for signing or
+// whose type isn't supported are ignored.
+func ParseKeySet(data []byte) (*KeySet, error) {
+	var set struct {
+		Keys []*jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("failed to decode key set: %v", err)
+	}
+
+	ks := &KeySet{}
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("invalid key %q: %v", jwk.Kid, err)
+		}
+		if key == nil {
+			continue
+		}
+		ks.keys = append(ks.keys, &publicKey{kid: jwk.Kid, key: key})
+	}
+
+	if len(ks.keys) == 0 {
+		return nil, fmt.Errorf("key set contains no signing keys")
+	}
+	return ks, nil
+}
+
+// NewKeySet returns a key set of the RSA or ECDSA public keys, identified by
+// their key ID.
+func NewKeySet(keys map[string]crypto.PublicKey) (*KeySet, error) {
+	ks := &KeySet{}
+	for kid, key := range keys {
+		switch key.(type) {
+		case *rsa.PublicKey, *ecdsa.PublicKey:
+		default:
+			return nil, fmt.Errorf("unsupported key type %T", key)
+		}
+		ks.keys = append(ks.keys, &publicKey{kid: kid, key: key})
+	}
+	return ks, nil
+}
+
+// FetchKeySet fetches and parses the JSON Web Key Set at the URL.
+func FetchKeySet(client *http.Client, url string) (*KeySet, error) {
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch key set: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key set: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch key set: %s: %s", resp.Status, body)
+	}
+	return ParseKeySet(body)
+}
+
+// candidates returns the keys that may have signed a token with the key ID,
+// which is all the keys if the token has no key ID.
+func (k *KeySet) candidates(kid string) []*publicKey {
+	if kid == "" || k.ignoreKid {
+		return k.keys
+	}
+	var keys []*publicKey
+	for _, key := range k.keys {
+		if key.kid == kid {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// hasKey returns whether the key set has a key that may have signed a token
+// with the key ID.
+func (k *KeySet) hasKey(kid string) bool {
+	return len(k.candidates(kid)) != 0
+}
+
+// publicKey returns the public key of the JSON Web Key, or nil if its type
+// isn't supported.
+func (j *jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch j.Kty {
+	case "RSA":
+		n, err := decodeBigInt(j.N)
+		if err != nil {
+			return nil, fmt.Errorf("invalid modulus: %v", err)
+		}
+		e, err := decodeBigInt(j.E)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exponent: %v", err)
+		}
+		if !e.IsInt64() {
+			return nil, fmt.Errorf("exponent too large")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+
+	case "EC":
+		var curve elliptic.Curve
+		switch j.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", j.Crv)
+		}
+		x, err := decodeBigInt(j.X)
+		if err != nil {
+			return nil, fmt.Errorf("invalid x coordinate: %v", err)
+		}
+		y, err := decodeBigInt(j.Y)
+		if err != nil {
+			return nil, fmt.Errorf("invalid y coordinate: %v", err)
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, fmt.Errorf("point not on curve")
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	}
+	return nil, nil
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) == 0 {
+		return nil, fmt.Errorf("missing value")
+	}
+	return new(big.Int).SetBytes(b), nil
+}
@@ -0,0 +1,244 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// DefaultSigningAlgs are the signing algorithms allowed when none are
+// configured.
+var DefaultSigningAlgs = []string{"RS256"}
+
+// signingAlgs maps the supported signing algorithms to their hash.
+var signingAlgs = map[string]crypto.Hash{
+	"RS256": crypto.SHA256,
+	"RS384": crypto.SHA384,
+	"RS512": crypto.SHA512,
+	"ES256": crypto.SHA256,
+	"ES384": crypto.SHA384,
+	"ES512": crypto.SHA512,
+}
+
+// ValidateSigningAlgs returns an error if an algorithm isn't supported.
+func ValidateSigningAlgs(algs []string) error {
+	for _, alg := range algs {
+		if _, ok := signingAlgs[alg]; !ok {
+			return fmt.Errorf("unsupported signing algorithm %q", alg)
+		}
+	}
+	return nil
+}
+
+// Claims are the claims of the payload of a JSON Web Token.
+type Claims map[string]interface{}
+
+// Expected are the expected values of the registered claims of a token.
+// Empty values aren't checked.
+type Expected struct {
+	// Issuer is the expected "iss" claim.
+	Issuer string
+
+	// Audiences are the allowed values of the "aud" claim, the token being
+	// valid if it has one of them.
+	Audiences []string
+
+	// Nonce is the expected "nonce" claim of an OIDC ID token.
+	Nonce string
+
+	// Now is the time the token is validated at, and Leeway the clock skew
+	// allowed when checking the "exp" and "nbf" claims.
+	Now    time.Time
+	Leeway time.Duration
+}
+
+// tokenKeyID returns the key ID of the header of the token, or an empty
+// string if it has none or the token is malformed.
+func tokenKeyID(token string) string {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return ""
+	}
+	var header struct {
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return ""
+	}
+	return header.Kid
+}
+
+// VerifyToken verifies the signature of the compact serialized JSON Web
+// Token using the keys of the key set, and returns its claims. The algorithm
+// of the token must be one of algs, or DefaultSigningAlgs if empty.
+func VerifyToken(ks *KeySet, token string, algs []string) (Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("malformed token")
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("invalid token header: %v", err)
+	}
+
+	if len(algs) == 0 {
+		algs = DefaultSigningAlgs
+	}
+	allowed := false
+	for _, alg := range algs {
+		if alg == header.Alg {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return nil, fmt.Errorf("signing algorithm %q not allowed", header.Alg)
+	}
+	hash, ok := signingAlgs[header.Alg]
+	if !ok {
+		return nil, fmt.Errorf("unsupported signing algorithm %q", header.Alg)
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid token signature: %v", err)
+	}
+	h := hash.New()
+	h.Write([]byte(parts[0] + "." + parts[1]))
+	digest := h.Sum(nil)
+
+	verified := false
+	for _, key := range ks.candidates(header.Kid) {
+		if verifySignature(key.key, header.Alg, hash, digest, sig) {
+			verified = true
+			break
+		}
+	}
+	if !verified {
+		return nil, fmt.Errorf("failed to verify token signature")
+	}
+
+	var claims Claims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("invalid token payload: %v", err)
+	}
+	return claims, nil
+}
+
+// verifySignature returns whether the signature of the digest is valid for
+// the key and algorithm.
+func verifySignature(key crypto.PublicKey, alg string, hash crypto.Hash, digest, sig []byte) bool {
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		if !strings.HasPrefix(alg, "RS") {
+			return false
+		}
+		return rsa.VerifyPKCS1v15(k, hash, digest, sig) == nil
+
+	case *ecdsa.PublicKey:
+		if !strings.HasPrefix(alg, "ES") {
+			return false
+		}
+		// The signature is the concatenation of R and S, each of the size
+		// of the curve
+		size := (k.Curve.Params().BitSize + 7) / 8
+		if len(sig) != 2*size {
+			return false
+		}
+		r := new(big.Int).SetBytes(sig[:size])
+		s := new(big.Int).SetBytes(sig[size:])
+		return ecdsa.Verify(k, digest, r, s)
+	}
+	return false
+}
+
+// Validate checks the registered claims of the token against the expected
+// values.
+func (c Claims) Validate(expected Expected) error {
+	if expected.Issuer != "" {
+		if iss, _ := c["iss"].(string); iss != expected.Issuer {
+			return fmt.Errorf("invalid issuer %q", iss)
+		}
+	}
+
+	if len(expected.Audiences) != 0 {
+		found := false
+		for _, aud := range c.audiences() {
+			for _, e := range expected.Audiences {
+				if aud == e {
+					found = true
+				}
+			}
+		}
+		if !found {
+			return fmt.Errorf("invalid audience")
+		}
+	}
+
+	if expected.Nonce != "" {
+		if nonce, _ := c["nonce"].(string); nonce != expected.Nonce {
+			return fmt.Errorf("invalid nonce")
+		}
+	}
+
+	now := expected.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	if exp, ok := c.time("exp"); !ok {
+		return fmt.Errorf("missing expiration")
+	} else if now.After(exp.Add(expected.Leeway)) {
+		return fmt.Errorf("token is expired")
+	}
+	if nbf, ok := c.time("nbf"); ok && now.Add(expected.Leeway).Before(nbf) {
+		return fmt.Errorf("token is not valid yet")
+	}
+	return nil
+}
+
+// audiences returns the audiences of the "aud" claim, which is either a
+// string or an array of strings.
+func (c Claims) audiences() []string {
+	switch aud := c["aud"].(type) {
+	case string:
+		return []string{aud}
+	case []interface{}:
+		var out []string
+		for _, a := range aud {
+			if s, ok := a.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+// time returns the time of a NumericDate claim.
+func (c Claims) time(name string) (time.Time, bool) {
+	v, ok := c[name].(float64)
+	if !ok {
+		return time.Time{}, false
+	}
+	sec := int64(v)
+	nsec := int64((v - float64(sec)) * float64(time.Second))
+	return time.Unix(sec, nsec), true
+}
+
+func decodeSegment(seg string, out interface{}) error {
+	b, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, out)
+}
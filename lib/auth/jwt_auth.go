@@ -0,0 +1,240 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultKeySetTTL is the time the key sets fetched from JWKS URLs are
+	// cached for.
+	DefaultKeySetTTL = time.Hour
+
+	// DefaultKeySetRefetchInterval is the minimum time between two fetches
+	// of the key set of an auth method, when tokens are signed by unknown
+	// keys or fetching the key set fails.
+	DefaultKeySetRefetchInterval = 30 * time.Second
+)
+
+// JWTConfig is the configuration of the validation of JSON Web Tokens signed
+// by an external issuer, such as the identity tokens of a CI system. Unlike
+// OIDC logins, the tokens are presented directly by the caller.
+type JWTConfig struct {
+	// JWKSURL is the URL of the JSON Web Key Set the tokens are verified
+	// with, and JWKSCaPem are PEM encoded CA certificates used to verify its
+	// TLS certificate.
+	JWKSURL   string
+	JWKSCaPem []string
+
+	// ValidationPubKeys are PEM encoded public keys the tokens are verified
+	// with, used instead of a key set URL.
+	ValidationPubKeys []string
+
+	// BoundIssuer is the expected issuer of the tokens, and BoundAudiences
+	// their allowed audiences. Empty values aren't checked.
+	BoundIssuer    string
+	BoundAudiences []string
+
+	// SigningAlgs are the allowed signing algorithms of the tokens.
+	SigningAlgs []string
+
+	// ClockSkewLeeway is the clock skew allowed when validating the
+	// expiration of the tokens.
+	ClockSkewLeeway time.Duration
+}
+
+// ParsePublicKeys returns a key set of the PEM encoded RSA or ECDSA public
+// keys. The keys have no key ID, so they may verify any token.
+func ParsePublicKeys(pems []string) (*KeySet, error) {
+	ks := &KeySet{ignoreKid: true}
+	for i, p := range pems {
+		block, _ := pem.Decode([]byte(p))
+		if block == nil {
+			return nil, fmt.Errorf("public key %d is not PEM encoded", i)
+		}
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key %d: %v", i, err)
+		}
+		switch key.(type) {
+		case *rsa.PublicKey, *ecdsa.PublicKey:
+		default:
+			return nil, fmt.Errorf("unsupported public key %d type %T", i, key)
+		}
+		ks.keys = append(ks.keys, &publicKey{key: key})
+	}
+	if len(ks.keys) == 0 {
+		return nil, fmt.Errorf("no public keys")
+	}
+	return ks, nil
+}
+
+// JWTVerifier verifies the JSON Web Tokens presented to JWT auth methods. The
+// key sets of JWKS URLs are cached per auth method, so that logins don't make
+// the servers fetch them from the issuer every time.
+type JWTVerifier struct {
+	// ttl is the time key sets are cached for, and refetchInterval the
+	// minimum time between two fetches of the key set of a method.
+	ttl             time.Duration
+	refetchInterval time.Duration
+
+	// now returns the current time, overridden in tests
+	now func() time.Time
+
+	// keySets are the key sets of the auth methods, and lastPurge the time
+	// the unused ones were last removed. The lock only guards the map, each
+	// key set having its own lock held while it is fetched.
+	keySets   map[string]*cachedKeySet
+	lastPurge time.Time
+	mu        sync.Mutex
+}
+
+// cachedKeySet is the last fetched key set of an auth method.
+type cachedKeySet struct {
+	// l serializes the fetches of the key set, so that concurrent logins
+	// wait for a single fetch without blocking the other auth methods.
+	l sync.Mutex
+
+	// used is the last time the key set was requested, guarded by the lock
+	// of the verifier.
+	used time.Time
+
+	// source identifies the JWKS URL and CA certificates the key set was
+	// fetched with, so that it is fetched again if they change.
+	source string
+
+	// ks is the key set, nil if it was never fetched successfully, and
+	// fetched the time it was fetched at.
+	ks      *KeySet
+	fetched time.Time
+
+	// attempted is the time of the last fetch, and err its error if it
+	// failed.
+	attempted time.Time
+	err       error
+}
+
+// NewJWTVerifier returns a JWTVerifier caching key sets for the ttl, and
+// fetching the key set of an auth method at most once per refetch interval.
+func NewJWTVerifier(ttl, refetchInterval time.Duration) *JWTVerifier {
+	return &JWTVerifier{
+		ttl:             ttl,
+		refetchInterval: refetchInterval,
+		now:             time.Now,
+		keySets:         make(map[string]*cachedKeySet),
+		lastPurge:       time.Now(),
+	}
+}
+
+// Forget removes the cached key set of the auth method, such as once it is
+// deleted.
+func (v *JWTVerifier) Forget(method string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	delete(v.keySets, method)
+}
+
+// Verify verifies the signature and claims of the JSON Web Token presented to
+// the named auth method and returns its claims. The cached key set of the JWKS
+// URL is used if configured, otherwise the validation public keys are used.
+func (v *JWTVerifier) Verify(method string, config *JWTConfig, token string) (Claims, error) {
+	var ks *KeySet
+	var err error
+	if config.JWKSURL != "" {
+		ks, err = v.keySet(method, config, tokenKeyID(token))
+	} else {
+		ks, err = ParsePublicKeys(config.ValidationPubKeys)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := VerifyToken(ks, token, config.SigningAlgs)
+	if err != nil {
+		return nil, err
+	}
+	err = claims.Validate(Expected{
+		Issuer:    config.BoundIssuer,
+		Audiences: config.BoundAudiences,
+		Leeway:    config.ClockSkewLeeway,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// cachedKeySet returns the cached key set of the auth method for the source,
+// and removes the key sets that weren't used for a TTL, such as those of
+// deleted auth methods.
+func (v *JWTVerifier) cachedKeySet(method, source string, now time.Time) *cachedKeySet {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if now.Sub(v.lastPurge) > v.ttl {
+		for m, cached := range v.keySets {
+			if now.Sub(cached.used) > v.ttl {
+				delete(v.keySets, m)
+			}
+		}
+		v.lastPurge = now
+	}
+
+	cached, ok := v.keySets[method]
+	if !ok || cached.source != source {
+		cached = &cachedKeySet{source: source}
+		v.keySets[method] = cached
+	}
+	cached.used = now
+	return cached
+}
+
+// keySet returns the key set of the JWKS URL of the auth method. The cached
+// key set is fetched again once expired, or if it has no key for the key ID,
+// unless it was fetched less than a refetch interval ago.
+func (v *JWTVerifier) keySet(method string, config *JWTConfig, kid string) (*KeySet, error) {
+	source := config.JWKSURL + "\n" + strings.Join(config.JWKSCaPem, "\n")
+	cached := v.cachedKeySet(method, source, v.now())
+
+	cached.l.Lock()
+	defer cached.l.Unlock()
+
+	// Take the time once the lock is held, as a concurrent fetch may have
+	// just completed
+	now := v.now()
+	fresh := cached.ks != nil && now.Sub(cached.fetched) < v.ttl
+	if fresh && cached.ks.hasKey(kid) {
+		return cached.ks, nil
+	}
+	if !cached.attempted.IsZero() && now.Sub(cached.attempted) < v.refetchInterval {
+		if fresh {
+			return cached.ks, nil
+		}
+		if cached.err != nil {
+			return nil, cached.err
+		}
+	}
+
+	cached.attempted = now
+	client, err := oidcHTTPClient(config.JWKSCaPem)
+	if err == nil {
+		var ks *KeySet
+		if ks, err = FetchKeySet(client, config.JWKSURL); err == nil {
+			cached.ks, cached.fetched, cached.err = ks, now, nil
+			return ks, nil
+		}
+	}
+
+	// Keep using the key set until it expires if the issuer can't be reached
+	cached.err = err
+	if fresh {
+		return cached.ks, nil
+	}
+	return nil, err
+}
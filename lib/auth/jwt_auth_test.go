@@ -0,0 +1,198 @@
+package auth_test
+
+import (
+	"crypto"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/lib/auth"
+	"github.com/hashicorp/nomad/lib/auth/authtest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJWTVerifier_Verify(t *testing.T) {
+	p := authtest.NewTestOIDCProvider()
+	defer p.Close()
+
+	token := p.SignToken(auth.Claims{
+		"iss": "https://ci.example.com",
+		"aud": "nomad",
+		"sub": "pipeline",
+		"exp": float64(time.Now().Add(time.Minute).Unix()),
+	})
+
+	// Tokens are verified with the key set of the JWKS URL
+	config := &auth.JWTConfig{
+		JWKSURL:        p.JWKSURL(),
+		BoundIssuer:    "https://ci.example.com",
+		BoundAudiences: []string{"nomad"},
+		SigningAlgs:    []string{"ES256"},
+	}
+	v := auth.NewJWTVerifier(time.Hour, time.Minute)
+	claims, err := v.Verify("ci", config, token)
+	require.NoError(t, err)
+	require.Equal(t, "pipeline", claims["sub"])
+
+	// or the validation public keys, which don't have key IDs
+	config.JWKSURL = ""
+	config.ValidationPubKeys = []string{p.PublicKeyPEM()}
+	_, err = v.Verify("ci", config, token)
+	require.NoError(t, err)
+
+	// The claims are validated
+	config.BoundIssuer = "https://other.example.com"
+	_, err = v.Verify("ci", config, token)
+	require.EqualError(t, err, `invalid issuer "https://ci.example.com"`)
+
+	config.BoundIssuer = ""
+	config.BoundAudiences = []string{"other"}
+	_, err = v.Verify("ci", config, token)
+	require.EqualError(t, err, "invalid audience")
+
+	// Tokens signed by other keys fail
+	other := authtest.NewTestOIDCProvider()
+	defer other.Close()
+	config.BoundAudiences = nil
+	config.ValidationPubKeys = []string{other.PublicKeyPEM()}
+	_, err = v.Verify("ci", config, token)
+	require.EqualError(t, err, "failed to verify token signature")
+}
+
+func TestJWTVerifier_KeySetCache(t *testing.T) {
+	p := authtest.NewTestOIDCProvider()
+	defer p.Close()
+
+	// Count the fetches of the key set of the provider
+	var fetches int32
+	jwks := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&fetches, 1)
+		http.Redirect(w, r, p.JWKSURL(), http.StatusTemporaryRedirect)
+	}))
+	defer jwks.Close()
+
+	token := p.SignToken(auth.Claims{
+		"sub": "pipeline",
+		"exp": float64(time.Now().Add(time.Minute).Unix()),
+	})
+	config := &auth.JWTConfig{
+		JWKSURL:     jwks.URL,
+		SigningAlgs: []string{"ES256"},
+	}
+
+	// The key set is fetched once
+	v := auth.NewJWTVerifier(time.Hour, time.Hour)
+	for i := 0; i < 3; i++ {
+		_, err := v.Verify("ci", config, token)
+		require.NoError(t, err)
+	}
+	require.Equal(t, int32(1), atomic.LoadInt32(&fetches))
+
+	// Tokens signed by unknown keys don't fetch the key set again within the
+	// refetch interval
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"ES256","kid":"other"}`))
+	unknown := header + token[strings.Index(token, "."):]
+	_, err := v.Verify("ci", config, unknown)
+	require.EqualError(t, err, "failed to verify token signature")
+	require.Equal(t, int32(1), atomic.LoadInt32(&fetches))
+
+	// Each auth method has its own key set
+	_, err = v.Verify("other", config, token)
+	require.NoError(t, err)
+	require.Equal(t, int32(2), atomic.LoadInt32(&fetches))
+
+	// Forgotten auth methods fetch their key set again
+	v.Forget("other")
+	_, err = v.Verify("other", config, token)
+	require.NoError(t, err)
+	require.Equal(t, int32(3), atomic.LoadInt32(&fetches))
+
+	// Once the refetch interval has passed, unknown keys fetch the key set
+	v = auth.NewJWTVerifier(time.Hour, 0)
+	_, err = v.Verify("ci", config, token)
+	require.NoError(t, err)
+	_, err = v.Verify("ci", config, unknown)
+	require.EqualError(t, err, "failed to verify token signature")
+	require.Equal(t, int32(5), atomic.LoadInt32(&fetches))
+
+	// The key set is fetched again once expired
+	v = auth.NewJWTVerifier(0, time.Hour)
+	for i := 0; i < 2; i++ {
+		_, err = v.Verify("ci", config, token)
+		require.NoError(t, err)
+	}
+	require.Equal(t, int32(7), atomic.LoadInt32(&fetches))
+}
+
+func TestJWTVerifier_ConcurrentFetches(t *testing.T) {
+	p := authtest.NewTestOIDCProvider()
+	defer p.Close()
+
+	// The key set of the slow method is served once unblocked
+	unblock := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-unblock
+		http.Redirect(w, r, p.JWKSURL(), http.StatusTemporaryRedirect)
+	}))
+	defer slow.Close()
+
+	token := p.SignToken(auth.Claims{
+		"sub": "pipeline",
+		"exp": float64(time.Now().Add(time.Minute).Unix()),
+	})
+	v := auth.NewJWTVerifier(time.Hour, time.Hour)
+
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := v.Verify("slow", &auth.JWTConfig{
+			JWKSURL:     slow.URL,
+			SigningAlgs: []string{"ES256"},
+		}, token)
+		errCh <- err
+	}()
+
+	// Fetching the key set of the slow method doesn't block the others
+	_, err := v.Verify("ci", &auth.JWTConfig{
+		JWKSURL:     p.JWKSURL(),
+		SigningAlgs: []string{"ES256"},
+	}, token)
+	require.NoError(t, err)
+
+	close(unblock)
+	require.NoError(t, <-errCh)
+}
+
+func TestVerifyToken_KeyIDs(t *testing.T) {
+	p := authtest.NewTestOIDCProvider()
+	defer p.Close()
+	token := p.SignToken(auth.Claims{"sub": "jane"})
+
+	// Keys without key ID of key sets don't verify tokens with a key ID
+	ks, err := auth.NewKeySet(map[string]crypto.PublicKey{"": p.PublicKey()})
+	require.NoError(t, err)
+	_, err = auth.VerifyToken(ks, token, []string{"ES256"})
+	require.EqualError(t, err, "failed to verify token signature")
+
+	ks, err = auth.NewKeySet(map[string]crypto.PublicKey{authtest.TestKeyID: p.PublicKey()})
+	require.NoError(t, err)
+	_, err = auth.VerifyToken(ks, token, []string{"ES256"})
+	require.NoError(t, err)
+}
+
+func TestParsePublicKeys(t *testing.T) {
+	p := authtest.NewTestOIDCProvider()
+	defer p.Close()
+
+	_, err := auth.ParsePublicKeys([]string{p.PublicKeyPEM()})
+	require.NoError(t, err)
+
+	_, err = auth.ParsePublicKeys([]string{"not a key"})
+	require.EqualError(t, err, "public key 0 is not PEM encoded")
+
+	_, err = auth.ParsePublicKeys(nil)
+	require.EqualError(t, err, "no public keys")
+}
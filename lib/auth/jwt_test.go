@@ -0,0 +1,127 @@
+package auth_test
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/lib/auth"
+	"github.com/hashicorp/nomad/lib/auth/authtest"
+	"github.com/stretchr/testify/require"
+)
+
+// signRS256 returns a token with the claims signed by the RSA key.
+func signRS256(t *testing.T, key *rsa.PrivateKey, kid string, claims auth.Claims) string {
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "kid": kid})
+	require.NoError(t, err)
+	payload, err := json.Marshal(claims)
+	require.NoError(t, err)
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." +
+		base64.RawURLEncoding.EncodeToString(payload)
+
+	digest := sha256.Sum256([]byte(signed))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	require.NoError(t, err)
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func TestVerifyToken_RS256(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ks, err := auth.NewKeySet(map[string]crypto.PublicKey{"rsa": &key.PublicKey})
+	require.NoError(t, err)
+
+	token := signRS256(t, key, "rsa", auth.Claims{"sub": "jane"})
+	claims, err := auth.VerifyToken(ks, token, nil)
+	require.NoError(t, err)
+	require.Equal(t, "jane", claims["sub"])
+
+	// The algorithm must be allowed
+	_, err = auth.VerifyToken(ks, token, []string{"ES256"})
+	require.EqualError(t, err, `signing algorithm "RS256" not allowed`)
+
+	// An unknown key ID has no candidate key
+	_, err = auth.VerifyToken(ks, signRS256(t, key, "other", auth.Claims{}), nil)
+	require.EqualError(t, err, "failed to verify token signature")
+
+	// A token signed by another key fails
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	_, err = auth.VerifyToken(ks, signRS256(t, other, "rsa", auth.Claims{}), nil)
+	require.EqualError(t, err, "failed to verify token signature")
+
+	_, err = auth.VerifyToken(ks, "not-a-token", nil)
+	require.EqualError(t, err, "malformed token")
+}
+
+func TestVerifyToken_ES256(t *testing.T) {
+	p := authtest.NewTestOIDCProvider()
+	defer p.Close()
+
+	ks, err := auth.NewKeySet(map[string]crypto.PublicKey{authtest.TestKeyID: p.PublicKey()})
+	require.NoError(t, err)
+
+	claims, err := auth.VerifyToken(ks, p.SignToken(auth.Claims{"sub": "jane"}), []string{"ES256"})
+	require.NoError(t, err)
+	require.Equal(t, "jane", claims["sub"])
+
+	// Tampering with the payload invalidates the signature
+	token := p.SignToken(auth.Claims{"sub": "jane"})
+	payload := base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"john"}`))
+	parts := strings.Split(token, ".")
+	tampered := parts[0] + "." + payload + "." + parts[2]
+	_, err = auth.VerifyToken(ks, tampered, []string{"ES256"})
+	require.EqualError(t, err, "failed to verify token signature")
+}
+
+func TestParseKeySet(t *testing.T) {
+	p := authtest.NewTestOIDCProvider()
+	defer p.Close()
+
+	ks, err := auth.FetchKeySet(http.DefaultClient, p.URL()+"/keys")
+	require.NoError(t, err)
+	_, err = auth.VerifyToken(ks, p.SignToken(auth.Claims{"sub": "jane"}), []string{"ES256"})
+	require.NoError(t, err)
+
+	// Encryption and unknown keys are ignored
+	_, err = auth.ParseKeySet([]byte(`{"keys":[{"kty":"RSA","use":"enc"},{"kty":"oct"}]}`))
+	require.EqualError(t, err, "key set contains no signing keys")
+
+	_, err = auth.ParseKeySet([]byte(`{"keys":[{"kid":"bad","kty":"EC","crv":"P-256","x":"AQ","y":"AQ"}]}`))
+	require.EqualError(t, err, `invalid key "bad": point not on curve`)
+}
+
+func TestClaims_Validate(t *testing.T) {
+	now := time.Now()
+	claims := auth.Claims{
+		"iss":   "https://issuer",
+		"aud":   []interface{}{"a", "b"},
+		"nonce": "n",
+		"exp":   float64(now.Add(time.Minute).Unix()),
+		"nbf":   float64(now.Add(-time.Minute).Unix()),
+	}
+
+	require.NoError(t, claims.Validate(auth.Expected{
+		Issuer:    "https://issuer",
+		Audiences: []string{"b"},
+		Nonce:     "n",
+		Now:       now,
+	}))
+
+	require.EqualError(t, claims.Validate(auth.Expected{Issuer: "other", Now: now}), `invalid issuer "https://issuer"`)
+	require.EqualError(t, claims.Validate(auth.Expected{Audiences: []string{"c"}, Now: now}), "invalid audience")
+	require.EqualError(t, claims.Validate(auth.Expected{Nonce: "other", Now: now}), "invalid nonce")
+	require.EqualError(t, claims.Validate(auth.Expected{Now: now.Add(2 * time.Minute)}), "token is expired")
+	require.NoError(t, claims.Validate(auth.Expected{Now: now.Add(2 * time.Minute), Leeway: 2 * time.Minute}))
+	require.EqualError(t, claims.Validate(auth.Expected{Now: now.Add(-2 * time.Minute)}), "token is not valid yet")
+
+	delete(claims, "exp")
+	require.EqualError(t, claims.Validate(auth.Expected{Now: now}), "missing expiration")
+}
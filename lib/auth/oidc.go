@@ -0,0 +1,234 @@
+package auth
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	cleanhttp "github.com/hashicorp/go-cleanhttp"
+)
+
+const (
+	// oidcDiscoveryPath is the path of the OpenID provider configuration
+	// relative to the discovery URL.
+	oidcDiscoveryPath = "/.well-known/openid-configuration"
+
+	// oidcClockSkewLeeway is the clock skew allowed when validating the
+	// expiration of ID tokens.
+	oidcClockSkewLeeway = 1 * time.Minute
+)
+
+// OIDCConfig is the configuration of an OIDC provider.
+type OIDCConfig struct {
+	// DiscoveryURL is the issuer URL of the provider, the provider
+	// configuration being discovered from it.
+	DiscoveryURL string
+
+	// ClientID and ClientSecret are the credentials of the client with the
+	// provider.
+	ClientID     string
+	ClientSecret string
+
+	// Scopes are the scopes requested in addition to the "openid" scope.
+	Scopes []string
+
+	// BoundAudiences are the allowed audiences of ID tokens. The client ID
+	// is used if empty.
+	BoundAudiences []string
+
+	// DiscoveryCaPem are PEM encoded CA certificates used to verify the TLS
+	// certificate of the provider, the system roots being used if empty.
+	DiscoveryCaPem []string
+
+	// SigningAlgs are the allowed signing algorithms of ID tokens.
+	SigningAlgs []string
+}
+
+// OIDCProvider performs the authorization code flow against an OIDC
+// provider.
+type OIDCProvider struct {
+	config *OIDCConfig
+	client *http.Client
+
+	issuer        string
+	authEndpoint  string
+	tokenEndpoint string
+	keys          *KeySet
+}
+
+// NewOIDCProvider discovers the configuration of the OIDC provider and
+// fetches its signing keys.
+func NewOIDCProvider(ctx context.Context, config *OIDCConfig) (*OIDCProvider, error) {
+	client, err := oidcHTTPClient(config.DiscoveryCaPem)
+	if err != nil {
+		return nil, err
+	}
+
+	discoveryURL := strings.TrimSuffix(config.DiscoveryURL, "/") + oidcDiscoveryPath
+	req, err := http.NewRequest("GET", discoveryURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query OIDC discovery URL: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read OIDC provider configuration: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to query OIDC discovery URL: %s: %s", resp.Status, body)
+	}
+
+	var discovery struct {
+		Issuer        string `json:"issuer"`
+		AuthEndpoint  string `json:"authorization_endpoint"`
+		TokenEndpoint string `json:"token_endpoint"`
+		JWKSURI       string `json:"jwks_uri"`
+	}
+	if err := json.Unmarshal(body, &discovery); err != nil {
+		return nil, fmt.Errorf("failed to decode OIDC provider configuration: %v", err)
+	}
+
+	// The issuer must match the URL the configuration was discovered from
+	if strings.TrimSuffix(discovery.Issuer, "/") != strings.TrimSuffix(config.DiscoveryURL, "/") {
+		return nil, fmt.Errorf("OIDC issuer %q does not match discovery URL %q", discovery.Issuer, config.DiscoveryURL)
+	}
+	if discovery.AuthEndpoint == "" || discovery.TokenEndpoint == "" || discovery.JWKSURI == "" {
+		return nil, fmt.Errorf("OIDC provider configuration is missing endpoints")
+	}
+
+	keys, err := FetchKeySet(client, discovery.JWKSURI)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OIDCProvider{
+		config:        config,
+		client:        client,
+		issuer:        discovery.Issuer,
+		authEndpoint:  discovery.AuthEndpoint,
+		tokenEndpoint: discovery.TokenEndpoint,
+		keys:          keys,
+	}, nil
+}
+
+// AuthURL returns the URL of the provider the user authenticates at. The
+// provider redirects to the redirect URI with the state and an authorization
+// code, and includes the nonce in the ID token.
+func (p *OIDCProvider) AuthURL(redirectURI, state, nonce string) string {
+	scopes := append([]string{"openid"}, p.config.Scopes...)
+	v := url.Values{}
+	v.Set("response_type", "code")
+	v.Set("client_id", p.config.ClientID)
+	v.Set("redirect_uri", redirectURI)
+	v.Set("scope", strings.Join(scopes, " "))
+	v.Set("state", state)
+	v.Set("nonce", nonce)
+
+	sep := "?"
+	if strings.Contains(p.authEndpoint, "?") {
+		sep = "&"
+	}
+	return p.authEndpoint + sep + v.Encode()
+}
+
+// Exchange exchanges the authorization code for tokens, verifies the ID token
+// and returns its claims.
+func (p *OIDCProvider) Exchange(ctx context.Context, code, redirectURI, nonce string) (Claims, error) {
+	v := url.Values{}
+	v.Set("grant_type", "authorization_code")
+	v.Set("code", code)
+	v.Set("redirect_uri", redirectURI)
+
+	req, err := http.NewRequest("POST", p.tokenEndpoint, strings.NewReader(v.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(url.QueryEscape(p.config.ClientID), url.QueryEscape(p.config.ClientSecret))
+
+	resp, err := p.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to exchange authorization code: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token response: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to exchange authorization code: %s: %s", resp.Status, body)
+	}
+
+	var token struct {
+		IDToken string `json:"id_token"`
+	}
+	if err := json.Unmarshal(body, &token); err != nil {
+		return nil, fmt.Errorf("failed to decode token response: %v", err)
+	}
+	if token.IDToken == "" {
+		return nil, fmt.Errorf("token response is missing the ID token")
+	}
+
+	return p.VerifyIDToken(token.IDToken, nonce)
+}
+
+// VerifyIDToken verifies the signature and claims of the ID token and
+// returns its claims.
+func (p *OIDCProvider) VerifyIDToken(idToken, nonce string) (Claims, error) {
+	claims, err := VerifyToken(p.keys, idToken, p.config.SigningAlgs)
+	if err != nil {
+		return nil, err
+	}
+
+	audiences := p.config.BoundAudiences
+	if len(audiences) == 0 {
+		audiences = []string{p.config.ClientID}
+	}
+	if nonce == "" {
+		return nil, fmt.Errorf("missing nonce")
+	}
+	err = claims.Validate(Expected{
+		Issuer:    p.issuer,
+		Audiences: audiences,
+		Nonce:     nonce,
+		Leeway:    oidcClockSkewLeeway,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// oidcHTTPClient returns the client used to query the provider, trusting the
+// CA certificates if any.
+func oidcHTTPClient(caPem []string) (*http.Client, error) {
+	client := cleanhttp.DefaultClient()
+	client.Timeout = 30 * time.Second
+	if len(caPem) == 0 {
+		return client, nil
+	}
+
+	pool := x509.NewCertPool()
+	for _, pem := range caPem {
+		if !pool.AppendCertsFromPEM([]byte(pem)) {
+			return nil, fmt.Errorf("could not parse CA certificate")
+		}
+	}
+	transport := cleanhttp.DefaultTransport()
+	transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+	client.Transport = transport
+	return client, nil
+}
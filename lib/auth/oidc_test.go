@@ -0,0 +1,105 @@
+package auth_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/hashicorp/nomad/lib/auth"
+	"github.com/hashicorp/nomad/lib/auth/authtest"
+	"github.com/stretchr/testify/require"
+)
+
+// authorize follows the auth URL to the test provider and returns the
+// authorization code and state of the redirect.
+func authorize(t *testing.T, authURL string) (string, string) {
+	client := &http.Client{
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	resp, err := client.Get(authURL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusFound, resp.StatusCode)
+
+	loc, err := url.Parse(resp.Header.Get("Location"))
+	require.NoError(t, err)
+	return loc.Query().Get("code"), loc.Query().Get("state")
+}
+
+func TestOIDCProvider_Flow(t *testing.T) {
+	p := authtest.NewTestOIDCProvider()
+	defer p.Close()
+	p.SetClaims(auth.Claims{"sub": "jane", "groups": []interface{}{"dev"}})
+
+	provider, err := auth.NewOIDCProvider(context.Background(), &auth.OIDCConfig{
+		DiscoveryURL: p.URL(),
+		ClientID:     authtest.TestOIDCClientID,
+		ClientSecret: authtest.TestOIDCClientSecret,
+		Scopes:       []string{"groups"},
+		SigningAlgs:  []string{"ES256"},
+	})
+	require.NoError(t, err)
+
+	redirectURI := "http://localhost:4649/oidc/callback"
+	authURL := provider.AuthURL(redirectURI, "state", "nonce")
+	u, err := url.Parse(authURL)
+	require.NoError(t, err)
+	require.Equal(t, "openid groups", u.Query().Get("scope"))
+
+	code, state := authorize(t, authURL)
+	require.Equal(t, "state", state)
+
+	// The nonce must match the one of the auth URL
+	_, err = provider.Exchange(context.Background(), code, redirectURI, "other")
+	require.EqualError(t, err, "invalid nonce")
+
+	code, _ = authorize(t, authURL)
+	claims, err := provider.Exchange(context.Background(), code, redirectURI, "nonce")
+	require.NoError(t, err)
+	require.Equal(t, "jane", claims["sub"])
+	require.Equal(t, []interface{}{"dev"}, claims["groups"])
+
+	// Codes can only be exchanged once
+	_, err = provider.Exchange(context.Background(), code, redirectURI, "nonce")
+	require.Error(t, err)
+}
+
+func TestOIDCProvider_Audience(t *testing.T) {
+	p := authtest.NewTestOIDCProvider()
+	defer p.Close()
+
+	provider, err := auth.NewOIDCProvider(context.Background(), &auth.OIDCConfig{
+		DiscoveryURL:   p.URL(),
+		ClientID:       authtest.TestOIDCClientID,
+		ClientSecret:   authtest.TestOIDCClientSecret,
+		BoundAudiences: []string{"other"},
+		SigningAlgs:    []string{"ES256"},
+	})
+	require.NoError(t, err)
+
+	redirectURI := "http://localhost:4649/oidc/callback"
+	code, _ := authorize(t, provider.AuthURL(redirectURI, "state", "nonce"))
+	_, err = provider.Exchange(context.Background(), code, redirectURI, "nonce")
+	require.EqualError(t, err, "invalid audience")
+}
+
+func TestNewOIDCProvider_Invalid(t *testing.T) {
+	p := authtest.NewTestOIDCProvider()
+	defer p.Close()
+
+	// The issuer must match the discovery URL
+	_, err := auth.NewOIDCProvider(context.Background(), &auth.OIDCConfig{
+		DiscoveryURL: p.URL() + "/other",
+		ClientID:     authtest.TestOIDCClientID,
+	})
+	require.Error(t, err)
+
+	_, err = auth.NewOIDCProvider(context.Background(), &auth.OIDCConfig{
+		DiscoveryURL:   p.URL(),
+		DiscoveryCaPem: []string{"not a certificate"},
+	})
+	require.EqualError(t, err, "could not parse CA certificate")
+}
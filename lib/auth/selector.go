@@ -0,0 +1,379 @@
+package auth
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Selector is a parsed binding rule selector. Selectors are boolean
+// expressions evaluated against the selector data of a token, of the forms:
+//
+//	value.<name> == "<string>"
+//	value.<name> != "<string>"
+//	"<string>" in list.<name>
+//	"<string>" not in list.<name>
+//	list.<name> contains "<string>"
+//	list.<name> not contains "<string>"
+//	list.<name> is empty
+//	list.<name> is not empty
+//
+// combined with "and", "or", "not" and parentheses. The empty selector
+// matches any token.
+type Selector struct {
+	expr selectorExpr
+}
+
+// ParseSelector parses a selector expression.
+func ParseSelector(s string) (*Selector, error) {
+	if strings.TrimSpace(s) == "" {
+		return &Selector{}, nil
+	}
+
+	tokens, err := tokenizeSelector(s)
+	if err != nil {
+		return nil, err
+	}
+	p := &selectorParser{tokens: tokens}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok != nil {
+		return nil, fmt.Errorf("unexpected %q", tok.text)
+	}
+	return &Selector{expr: expr}, nil
+}
+
+// Match returns whether the selector matches the data.
+func (s *Selector) Match(data *SelectorData) bool {
+	if s.expr == nil {
+		return true
+	}
+	return s.expr.eval(data)
+}
+
+type selectorExpr interface {
+	eval(data *SelectorData) bool
+}
+
+type andExpr struct{ left, right selectorExpr }
+
+func (e *andExpr) eval(data *SelectorData) bool { return e.left.eval(data) && e.right.eval(data) }
+
+type orExpr struct{ left, right selectorExpr }
+
+func (e *orExpr) eval(data *SelectorData) bool { return e.left.eval(data) || e.right.eval(data) }
+
+type notExpr struct{ expr selectorExpr }
+
+func (e *notExpr) eval(data *SelectorData) bool { return !e.expr.eval(data) }
+
+// valueEqualExpr matches a value, a missing value being not equal to any
+// string.
+type valueEqualExpr struct {
+	name  string
+	value string
+}
+
+func (e *valueEqualExpr) eval(data *SelectorData) bool {
+	v, ok := data.Value[e.name]
+	return ok && v == e.value
+}
+
+// listContainsExpr matches a list containing the value.
+type listContainsExpr struct {
+	name  string
+	value string
+}
+
+func (e *listContainsExpr) eval(data *SelectorData) bool {
+	for _, v := range data.List[e.name] {
+		if v == e.value {
+			return true
+		}
+	}
+	return false
+}
+
+// listEmptyExpr matches a missing or empty list.
+type listEmptyExpr struct {
+	name string
+}
+
+func (e *listEmptyExpr) eval(data *SelectorData) bool {
+	return len(data.List[e.name]) == 0
+}
+
+type tokenKind int
+
+const (
+	tokenWord tokenKind = iota
+	tokenString
+	tokenLParen
+	tokenRParen
+	tokenEqual
+	tokenNotEqual
+)
+
+type selectorToken struct {
+	kind tokenKind
+	text string
+}
+
+// tokenizeSelector splits the selector into tokens.
+func tokenizeSelector(s string) ([]*selectorToken, error) {
+	var tokens []*selectorToken
+	runes := []rune(s)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, &selectorToken{kind: tokenLParen, text: "("})
+			i++
+		case r == ')':
+			tokens = append(tokens, &selectorToken{kind: tokenRParen, text: ")"})
+			i++
+		case r == '=' || r == '!':
+			if i+1 >= len(runes) || runes[i+1] != '=' {
+				return nil, fmt.Errorf("unexpected %q at offset %d", r, i)
+			}
+			if r == '=' {
+				tokens = append(tokens, &selectorToken{kind: tokenEqual, text: "=="})
+			} else {
+				tokens = append(tokens, &selectorToken{kind: tokenNotEqual, text: "!="})
+			}
+			i += 2
+		case r == '"':
+			var b strings.Builder
+			j := i + 1
+			for ; j < len(runes) && runes[j] != '"'; j++ {
+				if runes[j] == '\\' && j+1 < len(runes) {
+					j++
+				}
+				b.WriteRune(runes[j])
+			}
+			if j >= len(runes) {
+				return nil, fmt.Errorf("unterminated string at offset %d", i)
+			}
+			tokens = append(tokens, &selectorToken{kind: tokenString, text: b.String()})
+			i = j + 1
+		case isWordRune(r):
+			j := i
+			for j < len(runes) && isWordRune(runes[j]) {
+				j++
+			}
+			tokens = append(tokens, &selectorToken{kind: tokenWord, text: string(runes[i:j])})
+			i = j
+		default:
+			return nil, fmt.Errorf("unexpected %q at offset %d", r, i)
+		}
+	}
+	return tokens, nil
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-' || r == '.'
+}
+
+type selectorParser struct {
+	tokens []*selectorToken
+	pos    int
+}
+
+func (p *selectorParser) peek() *selectorToken {
+	if p.pos >= len(p.tokens) {
+		return nil
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *selectorParser) next() *selectorToken {
+	tok := p.peek()
+	if tok != nil {
+		p.pos++
+	}
+	return tok
+}
+
+// acceptWord consumes the next token if it is the keyword.
+func (p *selectorParser) acceptWord(word string) bool {
+	if tok := p.peek(); tok != nil && tok.kind == tokenWord && tok.text == word {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *selectorParser) expectWord(word string) error {
+	if !p.acceptWord(word) {
+		return p.unexpected(fmt.Sprintf("%q", word))
+	}
+	return nil
+}
+
+func (p *selectorParser) expectString() (string, error) {
+	tok := p.peek()
+	if tok == nil || tok.kind != tokenString {
+		return "", p.unexpected("string")
+	}
+	p.next()
+	return tok.text, nil
+}
+
+func (p *selectorParser) unexpected(expected string) error {
+	if tok := p.peek(); tok != nil {
+		return fmt.Errorf("expected %s, got %q", expected, tok.text)
+	}
+	return fmt.Errorf("expected %s, got end of selector", expected)
+}
+
+func (p *selectorParser) parseOr() (selectorExpr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.acceptWord("or") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &orExpr{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *selectorParser) parseAnd() (selectorExpr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.acceptWord("and") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &andExpr{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *selectorParser) parseUnary() (selectorExpr, error) {
+	if p.acceptWord("not") {
+		expr, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &notExpr{expr: expr}, nil
+	}
+
+	tok := p.peek()
+	switch {
+	case tok == nil:
+		return nil, p.unexpected("expression")
+	case tok.kind == tokenLParen:
+		p.next()
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if tok := p.peek(); tok == nil || tok.kind != tokenRParen {
+			return nil, p.unexpected(`")"`)
+		}
+		p.next()
+		return expr, nil
+	case tok.kind == tokenString:
+		return p.parseIn()
+	case tok.kind == tokenWord:
+		return p.parseMatch()
+	}
+	return nil, p.unexpected("expression")
+}
+
+// parseIn parses `"<string>" [not] in list.<name>`.
+func (p *selectorParser) parseIn() (selectorExpr, error) {
+	value := p.next().text
+	negate := p.acceptWord("not")
+	if err := p.expectWord("in"); err != nil {
+		return nil, err
+	}
+	name, err := p.parseSelector("list")
+	if err != nil {
+		return nil, err
+	}
+	var expr selectorExpr = &listContainsExpr{name: name, value: value}
+	if negate {
+		expr = &notExpr{expr: expr}
+	}
+	return expr, nil
+}
+
+// parseMatch parses the expressions starting with a value or list selector.
+func (p *selectorParser) parseMatch() (selectorExpr, error) {
+	tok := p.peek()
+	if strings.HasPrefix(tok.text, "value.") {
+		name, err := p.parseSelector("value")
+		if err != nil {
+			return nil, err
+		}
+		op := p.peek()
+		if op == nil || (op.kind != tokenEqual && op.kind != tokenNotEqual) {
+			return nil, p.unexpected(`"==" or "!="`)
+		}
+		p.next()
+		value, err := p.expectString()
+		if err != nil {
+			return nil, err
+		}
+		var expr selectorExpr = &valueEqualExpr{name: name, value: value}
+		if op.kind == tokenNotEqual {
+			expr = &notExpr{expr: expr}
+		}
+		return expr, nil
+	}
+
+	name, err := p.parseSelector("list")
+	if err != nil {
+		return nil, err
+	}
+
+	if p.acceptWord("is") {
+		negate := p.acceptWord("not")
+		if err := p.expectWord("empty"); err != nil {
+			return nil, err
+		}
+		var expr selectorExpr = &listEmptyExpr{name: name}
+		if negate {
+			expr = &notExpr{expr: expr}
+		}
+		return expr, nil
+	}
+
+	negate := p.acceptWord("not")
+	if err := p.expectWord("contains"); err != nil {
+		return nil, err
+	}
+	value, err := p.expectString()
+	if err != nil {
+		return nil, err
+	}
+	var expr selectorExpr = &listContainsExpr{name: name, value: value}
+	if negate {
+		expr = &notExpr{expr: expr}
+	}
+	return expr, nil
+}
+
+// parseSelector parses a selector of the kind, either value.<name> or
+// list.<name>, and returns the name.
+func (p *selectorParser) parseSelector(kind string) (string, error) {
+	tok := p.peek()
+	prefix := kind + "."
+	if tok == nil || tok.kind != tokenWord || !strings.HasPrefix(tok.text, prefix) || len(tok.text) == len(prefix) {
+		return "", p.unexpected(prefix + "<name>")
+	}
+	p.next()
+	return strings.TrimPrefix(tok.text, prefix), nil
+}
@@ -0,0 +1,109 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelector_Match(t *testing.T) {
+	data := &SelectorData{
+		Value: map[string]string{"email": "jane@example.com", "admin": "true"},
+		List:  map[string][]string{"groups": {"dev", "ops"}},
+	}
+
+	cases := []struct {
+		selector string
+		match    bool
+	}{
+		{``, true},
+		{`value.email == "jane@example.com"`, true},
+		{`value.email != "jane@example.com"`, false},
+		{`value.missing == ""`, false},
+		{`"dev" in list.groups`, true},
+		{`"qa" in list.groups`, false},
+		{`"qa" not in list.groups`, true},
+		{`list.groups contains "ops"`, true},
+		{`list.groups not contains "ops"`, false},
+		{`list.groups is empty`, false},
+		{`list.missing is empty`, true},
+		{`list.groups is not empty`, true},
+		{`"dev" in list.groups and value.admin == "false"`, false},
+		{`"qa" in list.groups or value.admin == "true"`, true},
+		{`not ("qa" in list.groups or value.admin == "false")`, true},
+		{`"qa" in list.groups and value.admin == "true" or "ops" in list.groups`, true},
+		{`value.email == "with \"quotes\""`, false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.selector, func(t *testing.T) {
+			sel, err := ParseSelector(c.selector)
+			require.NoError(t, err)
+			require.Equal(t, c.match, sel.Match(data))
+		})
+	}
+}
+
+func TestSelector_Parse_Invalid(t *testing.T) {
+	cases := []string{
+		`value.email`,
+		`value.email = "x"`,
+		`value.email == x`,
+		`"dev" in value.groups`,
+		`"dev" in list.`,
+		`list.groups contains`,
+		`("dev" in list.groups`,
+		`"dev" in list.groups)`,
+		`"unterminated`,
+		`"dev" in list.groups and`,
+		`list.groups is full`,
+	}
+
+	for _, c := range cases {
+		t.Run(c, func(t *testing.T) {
+			_, err := ParseSelector(c)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestNewSelectorData(t *testing.T) {
+	claims := Claims{
+		"email":  "jane@example.com",
+		"admin":  true,
+		"level":  float64(3),
+		"groups": []interface{}{"dev", "ops"},
+		"role":   "lead",
+		"nested": map[string]interface{}{
+			"a/b": map[string]interface{}{"team": "infra"},
+		},
+	}
+
+	data, err := NewSelectorData(claims,
+		map[string]string{
+			"email":             "email",
+			"admin":             "admin",
+			"level":             "level",
+			"/nested/a~1b/team": "team",
+			"missing":           "missing",
+		},
+		map[string]string{
+			"groups": "groups",
+			"role":   "roles",
+		})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"email": "jane@example.com",
+		"admin": "true",
+		"level": "3",
+		"team":  "infra",
+	}, data.Value)
+	require.Equal(t, map[string][]string{
+		"groups": {"dev", "ops"},
+		"roles":  {"lead"},
+	}, data.List)
+
+	// Objects can't be mapped to values
+	_, err = NewSelectorData(claims, map[string]string{"nested": "nested"}, nil)
+	require.Error(t, err)
+}
@@ -0,0 +1,172 @@
+// Package cpuset implements a set of CPU core IDs that can be parsed from and
+// formatted to the Linux cpuset list format, for example "0-3,8,10-11".
+package cpuset
+
+import (
+	"fmt"
+	"io/ioutil"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// onlineCPUsPath lists the CPUs of the host that are currently online
+const onlineCPUsPath = "/sys/devices/system/cpu/online"
+
+// CPUSet is an immutable set of CPU core IDs. The zero value is an empty set.
+type CPUSet struct {
+	cpus map[uint16]struct{}
+}
+
+// New returns a CPUSet containing the given cores.
+func New(cpus ...uint16) CPUSet {
+	s := CPUSet{cpus: make(map[uint16]struct{}, len(cpus))}
+	for _, c := range cpus {
+		s.cpus[c] = struct{}{}
+	}
+	return s
+}
+
+// Parse parses a cpuset in list format, such as "0-3,8". An empty string
+// results in an empty set.
+func Parse(s string) (CPUSet, error) {
+	cpus := []uint16{}
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return New(), nil
+	}
+
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		bounds := strings.SplitN(part, "-", 2)
+
+		start, err := strconv.ParseUint(strings.TrimSpace(bounds[0]), 10, 16)
+		if err != nil {
+			return CPUSet{}, fmt.Errorf("invalid cpuset %q: %v", s, err)
+		}
+		end := start
+		if len(bounds) == 2 {
+			end, err = strconv.ParseUint(strings.TrimSpace(bounds[1]), 10, 16)
+			if err != nil {
+				return CPUSet{}, fmt.Errorf("invalid cpuset %q: %v", s, err)
+			}
+			if end < start {
+				return CPUSet{}, fmt.Errorf("invalid cpuset %q: range %q is reversed", s, part)
+			}
+		}
+
+		for c := start; c <= end; c++ {
+			cpus = append(cpus, uint16(c))
+		}
+	}
+
+	return New(cpus...), nil
+}
+
+// Host returns the set of CPUs that are online on the host. If that cannot
+// be determined, all CPUs usable by the current process are assumed to be
+// numbered from zero.
+func Host() CPUSet {
+	if runtime.GOOS == "linux" {
+		if raw, err := ioutil.ReadFile(onlineCPUsPath); err == nil {
+			if s, err := Parse(string(raw)); err == nil && s.Size() > 0 {
+				return s
+			}
+		}
+	}
+
+	cpus := make([]uint16, runtime.NumCPU())
+	for i := range cpus {
+		cpus[i] = uint16(i)
+	}
+	return New(cpus...)
+}
+
+// Size returns the number of cores in the set.
+func (s CPUSet) Size() int {
+	return len(s.cpus)
+}
+
+// Contains returns whether the given core is in the set.
+func (s CPUSet) Contains(cpu uint16) bool {
+	_, ok := s.cpus[cpu]
+	return ok
+}
+
+// ToSlice returns the cores of the set in ascending order.
+func (s CPUSet) ToSlice() []uint16 {
+	cpus := make([]uint16, 0, len(s.cpus))
+	for c := range s.cpus {
+		cpus = append(cpus, c)
+	}
+	sort.Slice(cpus, func(i, j int) bool { return cpus[i] < cpus[j] })
+	return cpus
+}
+
+// Union returns a new set with the cores of both sets.
+func (s CPUSet) Union(o CPUSet) CPUSet {
+	u := New(s.ToSlice()...)
+	for c := range o.cpus {
+		u.cpus[c] = struct{}{}
+	}
+	return u
+}
+
+// Difference returns a new set with the cores of s that are not in o.
+func (s CPUSet) Difference(o CPUSet) CPUSet {
+	d := New()
+	for c := range s.cpus {
+		if !o.Contains(c) {
+			d.cpus[c] = struct{}{}
+		}
+	}
+	return d
+}
+
+// Intersect returns a new set with the cores that are in both s and o.
+func (s CPUSet) Intersect(o CPUSet) CPUSet {
+	i := New()
+	for c := range s.cpus {
+		if o.Contains(c) {
+			i.cpus[c] = struct{}{}
+		}
+	}
+	return i
+}
+
+// IsSubsetOf returns whether every core of s is also in o.
+func (s CPUSet) IsSubsetOf(o CPUSet) bool {
+	for c := range s.cpus {
+		if !o.Contains(c) {
+			return false
+		}
+	}
+	return true
+}
+
+// Equals returns whether both sets contain the same cores.
+func (s CPUSet) Equals(o CPUSet) bool {
+	return s.Size() == o.Size() && s.IsSubsetOf(o)
+}
+
+// String returns the set in cpuset list format, collapsing consecutive cores
+// into ranges.
+func (s CPUSet) String() string {
+	cpus := s.ToSlice()
+	parts := []string{}
+	for i := 0; i < len(cpus); {
+		j := i
+		for j+1 < len(cpus) && cpus[j+1] == cpus[j]+1 {
+			j++
+		}
+
+		if i == j {
+			parts = append(parts, strconv.Itoa(int(cpus[i])))
+		} else {
+			parts = append(parts, fmt.Sprintf("%d-%d", cpus[i], cpus[j]))
+		}
+		i = j + 1
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,67 @@
+package cpuset
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCPUSet_Parse(t *testing.T) {
+	cases := []struct {
+		input    string
+		expected []uint16
+		err      bool
+	}{
+		{input: "", expected: []uint16{}},
+		{input: "0", expected: []uint16{0}},
+		{input: "0-3", expected: []uint16{0, 1, 2, 3}},
+		{input: "0-1,4, 6-7\n", expected: []uint16{0, 1, 4, 6, 7}},
+		{input: "3,1,2", expected: []uint16{1, 2, 3}},
+		{input: "3-1", err: true},
+		{input: "a", err: true},
+		{input: "1-", err: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.input, func(t *testing.T) {
+			s, err := Parse(c.input)
+			if c.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, c.expected, s.ToSlice())
+		})
+	}
+}
+
+func TestCPUSet_String(t *testing.T) {
+	require := require.New(t)
+
+	require.Equal("", New().String())
+	require.Equal("5", New(5).String())
+	require.Equal("0-3,8,10-11", New(11, 10, 8, 3, 2, 1, 0).String())
+}
+
+func TestCPUSet_Operations(t *testing.T) {
+	require := require.New(t)
+
+	a := New(0, 1, 2, 3)
+	b := New(2, 3, 4)
+
+	require.Equal([]uint16{0, 1, 2, 3, 4}, a.Union(b).ToSlice())
+	require.Equal([]uint16{0, 1}, a.Difference(b).ToSlice())
+	require.Equal([]uint16{2, 3}, a.Intersect(b).ToSlice())
+	require.True(New(1, 2).IsSubsetOf(a))
+	require.False(b.IsSubsetOf(a))
+	require.True(a.Equals(New(3, 2, 1, 0)))
+	require.False(a.Equals(b))
+
+	// Operations must not modify their operands
+	require.Equal([]uint16{0, 1, 2, 3}, a.ToSlice())
+	require.Equal([]uint16{2, 3, 4}, b.ToSlice())
+}
+
+func TestCPUSet_Host(t *testing.T) {
+	require.NotZero(t, Host().Size())
+}
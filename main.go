@@ -23,6 +23,7 @@ var (
 	// commands above.
 	hidden = []string{
 		"alloc-status",
+		"artifact-getter",
 		"check",
 		"client-config",
 		"eval-status",
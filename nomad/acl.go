@@ -4,6 +4,7 @@ import (
 	"time"
 
 	metrics "github.com/armon/go-metrics"
+	memdb "github.com/hashicorp/go-memdb"
 	lru "github.com/hashicorp/golang-lru"
 	"github.com/hashicorp/nomad/acl"
 	"github.com/hashicorp/nomad/nomad/state"
@@ -35,6 +36,33 @@ func (s *Server) ResolveToken(secretID string) (*acl.ACL, error) {
 	return resolveTokenFromSnapshotCache(snap, s.aclCache, secretID)
 }
 
+// ResolveSecretToken is used to translate an ACL Token Secret ID into the ACL
+// token, nil if ACLs are disabled, or an error.
+func (s *Server) ResolveSecretToken(secretID string) (*structs.ACLToken, error) {
+	// Fast-path if ACLs are disabled
+	if !s.config.ACLEnabled {
+		return nil, nil
+	}
+
+	// Handle anonymous requests
+	if secretID == "" {
+		return structs.AnonymousACLToken, nil
+	}
+
+	snap, err := s.fsm.State().Snapshot()
+	if err != nil {
+		return nil, err
+	}
+	token, err := snap.ACLTokenBySecretID(nil, secretID)
+	if err != nil {
+		return nil, err
+	}
+	if token == nil || token.IsExpired(time.Now().UTC()) {
+		return nil, structs.ErrTokenNotFound
+	}
+	return token, nil
+}
+
 // resolveTokenFromSnapshotCache is used to resolve an ACL object from a snapshot of state,
 // using a cache to avoid parsing and ACL construction when possible. It is split from resolveToken
 // to simplify testing.
@@ -51,7 +79,7 @@ func resolveTokenFromSnapshotCache(snap *state.StateSnapshot, cache *lru.TwoQueu
 		if err != nil {
 			return nil, err
 		}
-		if token == nil {
+		if token == nil || token.IsExpired(time.Now().UTC()) {
 			return nil, structs.ErrTokenNotFound
 		}
 	}
@@ -61,9 +89,13 @@ func resolveTokenFromSnapshotCache(snap *state.StateSnapshot, cache *lru.TwoQueu
 		return acl.ManagementACL, nil
 	}
 
-	// Get all associated policies
-	policies := make([]*structs.ACLPolicy, 0, len(token.Policies))
-	for _, policyName := range token.Policies {
+	// Get all associated policies, including the ones of the roles
+	policyNames, err := tokenPolicyNames(&snap.StateStore, token)
+	if err != nil {
+		return nil, err
+	}
+	policies := make([]*structs.ACLPolicy, 0, len(policyNames))
+	for _, policyName := range policyNames {
 		policy, err := snap.ACLPolicyByName(nil, policyName)
 		if err != nil {
 			return nil, err
@@ -84,3 +116,59 @@ func resolveTokenFromSnapshotCache(snap *state.StateSnapshot, cache *lru.TwoQueu
 	}
 	return aclObj, nil
 }
+
+// tokenPolicyNames returns the names of the policies granted to a token: its
+// own policies along with the policies of its roles. Roles that don't exist
+// are ignored, since they don't grant any more privilege.
+func tokenPolicyNames(store *state.StateStore, token *structs.ACLToken) ([]string, error) {
+	if len(token.Roles) == 0 {
+		return token.Policies, nil
+	}
+
+	names := make([]string, 0, len(token.Policies))
+	seen := make(map[string]struct{})
+	add := func(policies []string) {
+		for _, name := range policies {
+			if _, ok := seen[name]; !ok {
+				seen[name] = struct{}{}
+				names = append(names, name)
+			}
+		}
+	}
+
+	add(token.Policies)
+	for _, roleName := range token.Roles {
+		role, err := store.ACLRoleByName(nil, roleName)
+		if err != nil {
+			return nil, err
+		}
+		if role != nil {
+			add(role.Policies)
+		}
+	}
+	return names, nil
+}
+
+// allowNsOp returns whether the token may perform the operation in the
+// namespace of a request. The requests for all the namespaces are allowed,
+// their results being filtered by namespaceACLFilter.
+func allowNsOp(aclObj *acl.ACL, namespace, op string) bool {
+	if aclObj == nil || namespace == structs.AllNamespacesSentinel {
+		return true
+	}
+	return aclObj.AllowNsOp(namespace, op)
+}
+
+// namespaceACLFilter wraps the iterator of a request for all the namespaces
+// with a filter removing the objects of the namespaces the token may not
+// perform the operation in. nsFn returns the namespace of an object.
+func namespaceACLFilter(iter memdb.ResultIterator, aclObj *acl.ACL, namespace, op string,
+	nsFn func(raw interface{}) string) memdb.ResultIterator {
+
+	if aclObj == nil || namespace != structs.AllNamespacesSentinel {
+		return iter
+	}
+	return memdb.NewFilterIterator(iter, func(raw interface{}) bool {
+		return !aclObj.AllowNsOp(nsFn(raw), op)
+	})
+}
@@ -0,0 +1,797 @@
+package nomad
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/lib/auth"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// oidcProviderTimeout bounds the requests made to OIDC providers while
+	// logging in.
+	oidcProviderTimeout = 30 * time.Second
+)
+
+// UpsertAuthMethods is used to create or update a set of auth methods
+func (a *ACL) UpsertAuthMethods(args *structs.ACLAuthMethodUpsertRequest, reply *structs.ACLAuthMethodUpsertResponse) error {
+	// Ensure ACLs are enabled, and always flow modification requests to the authoritative region
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	args.Region = a.srv.config.AuthoritativeRegion
+
+	if done, err := a.srv.forward("ACL.UpsertAuthMethods", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "upsert_auth_methods"}, time.Now())
+
+	// Check management level permissions
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Validate non-zero set of auth methods
+	if len(args.AuthMethods) == 0 {
+		return fmt.Errorf("must specify as least one auth method")
+	}
+
+	// Snapshot the state
+	state, err := a.srv.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	defaultMethod, err := state.DefaultACLAuthMethod(nil)
+	if err != nil {
+		return err
+	}
+
+	// Validate each auth method, compute hash
+	now := time.Now().UTC()
+	for idx, method := range args.AuthMethods {
+		if err := method.Validate(); err != nil {
+			return fmt.Errorf("auth method %d invalid: %v", idx, err)
+		}
+
+		// Only one auth method can be the default
+		if method.Default {
+			if defaultMethod != nil && defaultMethod.Name != method.Name {
+				return fmt.Errorf("default auth method %q already exists", defaultMethod.Name)
+			}
+			defaultMethod = method
+		}
+
+		existing, err := state.ACLAuthMethodByName(nil, method.Name)
+		if err != nil {
+			return fmt.Errorf("auth method lookup failed: %v", err)
+		}
+		if existing == nil {
+			method.CreateTime = now
+		}
+		method.ModifyTime = now
+		method.SetHash()
+	}
+
+	// Update via Raft
+	_, index, err := a.srv.raftApply(structs.ACLAuthMethodsUpsertRequestType, args)
+	if err != nil {
+		return err
+	}
+
+	// Populate the response. We do a lookup against the state to
+	// pickup the proper create / modify times.
+	state, err = a.srv.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	for _, method := range args.AuthMethods {
+		out, err := state.ACLAuthMethodByName(nil, method.Name)
+		if err != nil {
+			return fmt.Errorf("auth method lookup failed: %v", err)
+		}
+		reply.AuthMethods = append(reply.AuthMethods, out)
+	}
+
+	// Update the index
+	reply.Index = index
+	return nil
+}
+
+// DeleteAuthMethods is used to delete auth methods, along with their binding
+// rules
+func (a *ACL) DeleteAuthMethods(args *structs.ACLAuthMethodDeleteRequest, reply *structs.GenericResponse) error {
+	// Ensure ACLs are enabled, and always flow modification requests to the authoritative region
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	args.Region = a.srv.config.AuthoritativeRegion
+
+	if done, err := a.srv.forward("ACL.DeleteAuthMethods", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "delete_auth_methods"}, time.Now())
+
+	// Check management level permissions
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Validate non-zero set of auth methods
+	if len(args.Names) == 0 {
+		return fmt.Errorf("must specify as least one auth method")
+	}
+
+	// Update via Raft
+	_, index, err := a.srv.raftApply(structs.ACLAuthMethodsDeleteRequestType, args)
+	if err != nil {
+		return err
+	}
+
+	// Drop the cached key sets of the deleted methods
+	for _, name := range args.Names {
+		a.srv.jwtVerifier.Forget(name)
+	}
+
+	// Update the index
+	reply.Index = index
+	return nil
+}
+
+// ListAuthMethods is used to list the auth methods. Any token can list them,
+// as users need to know the methods they can log in with
+func (a *ACL) ListAuthMethods(args *structs.ACLAuthMethodListRequest, reply *structs.ACLAuthMethodListResponse) error {
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	if done, err := a.srv.forward("ACL.ListAuthMethods", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "list_auth_methods"}, time.Now())
+
+	// Ensure the token is valid
+	if _, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			iter, err := state.ACLAuthMethods(ws)
+			if err != nil {
+				return err
+			}
+
+			// Convert all the auth methods to a list stub
+			reply.AuthMethods = nil
+			for raw := iter.Next(); raw != nil; raw = iter.Next() {
+				method := raw.(*structs.ACLAuthMethod)
+				reply.AuthMethods = append(reply.AuthMethods, method.Stub())
+			}
+
+			// Use the last index that affected the auth methods table
+			return a.setTableIndex(state, "acl_auth_methods", &reply.QueryMeta)
+		}}
+	return a.srv.blockingRPC(&opts)
+}
+
+// GetAuthMethod is used to get a specific auth method
+func (a *ACL) GetAuthMethod(args *structs.ACLAuthMethodGetRequest, reply *structs.ACLAuthMethodGetResponse) error {
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	if done, err := a.srv.forward("ACL.GetAuthMethod", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "get_auth_method"}, time.Now())
+
+	// Check management level permissions, as the method has the OIDC
+	// client secret
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			out, err := state.ACLAuthMethodByName(ws, args.Name)
+			if err != nil {
+				return err
+			}
+
+			// Setup the output
+			reply.AuthMethod = out
+			if out != nil {
+				reply.Index = out.ModifyIndex
+				return nil
+			}
+
+			// Use the last index that affected the auth methods table
+			return a.setTableIndex(state, "acl_auth_methods", &reply.QueryMeta)
+		}}
+	return a.srv.blockingRPC(&opts)
+}
+
+// GetAuthMethods is used to get a set of auth methods
+func (a *ACL) GetAuthMethods(args *structs.ACLAuthMethodsGetRequest, reply *structs.ACLAuthMethodsGetResponse) error {
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	if done, err := a.srv.forward("ACL.GetAuthMethods", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "get_auth_methods"}, time.Now())
+
+	// Check management level permissions
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			reply.AuthMethods = make(map[string]*structs.ACLAuthMethod, len(args.Names))
+			for _, name := range args.Names {
+				out, err := state.ACLAuthMethodByName(ws, name)
+				if err != nil {
+					return err
+				}
+				if out != nil {
+					reply.AuthMethods[name] = out
+				}
+			}
+
+			// Use the last index that affected the auth methods table
+			return a.setTableIndex(state, "acl_auth_methods", &reply.QueryMeta)
+		}}
+	return a.srv.blockingRPC(&opts)
+}
+
+// UpsertBindingRules is used to create or update a set of binding rules
+func (a *ACL) UpsertBindingRules(args *structs.ACLBindingRulesUpsertRequest, reply *structs.ACLBindingRulesUpsertResponse) error {
+	// Ensure ACLs are enabled, and always flow modification requests to the authoritative region
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	args.Region = a.srv.config.AuthoritativeRegion
+
+	if done, err := a.srv.forward("ACL.UpsertBindingRules", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "upsert_binding_rules"}, time.Now())
+
+	// Check management level permissions
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Validate non-zero set of binding rules
+	if len(args.ACLBindingRules) == 0 {
+		return fmt.Errorf("must specify as least one binding rule")
+	}
+
+	// Snapshot the state
+	state, err := a.srv.State().Snapshot()
+	if err != nil {
+		return err
+	}
+
+	// Validate each binding rule, compute hash
+	now := time.Now().UTC()
+	for idx, rule := range args.ACLBindingRules {
+		if err := rule.Validate(); err != nil {
+			return fmt.Errorf("binding rule %d invalid: %v", idx, err)
+		}
+
+		method, err := state.ACLAuthMethodByName(nil, rule.AuthMethod)
+		if err != nil {
+			return fmt.Errorf("auth method lookup failed: %v", err)
+		}
+		if method == nil {
+			return fmt.Errorf("ACL auth method %q not found", rule.AuthMethod)
+		}
+
+		// Generate an ID if new
+		if rule.ID == "" {
+			rule.ID = uuid.Generate()
+			rule.CreateTime = now
+		} else {
+			// Verify the binding rule exists
+			out, err := state.ACLBindingRuleByID(nil, rule.ID)
+			if err != nil {
+				return fmt.Errorf("binding rule lookup failed: %v", err)
+			}
+			if out == nil {
+				return fmt.Errorf("cannot find binding rule %s", rule.ID)
+			}
+		}
+		rule.ModifyTime = now
+		rule.SetHash()
+	}
+
+	// Update via Raft
+	fsmErr, index, err := a.srv.raftApply(structs.ACLBindingRulesUpsertRequestType, args)
+	if err, ok := fsmErr.(error); ok && err != nil {
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	// Populate the response. We do a lookup against the state to
+	// pickup the proper create / modify times.
+	state, err = a.srv.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	for _, rule := range args.ACLBindingRules {
+		out, err := state.ACLBindingRuleByID(nil, rule.ID)
+		if err != nil {
+			return fmt.Errorf("binding rule lookup failed: %v", err)
+		}
+		reply.ACLBindingRules = append(reply.ACLBindingRules, out)
+	}
+
+	// Update the index
+	reply.Index = index
+	return nil
+}
+
+// DeleteBindingRules is used to delete binding rules
+func (a *ACL) DeleteBindingRules(args *structs.ACLBindingRulesDeleteRequest, reply *structs.GenericResponse) error {
+	// Ensure ACLs are enabled, and always flow modification requests to the authoritative region
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	args.Region = a.srv.config.AuthoritativeRegion
+
+	if done, err := a.srv.forward("ACL.DeleteBindingRules", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "delete_binding_rules"}, time.Now())
+
+	// Check management level permissions
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Validate non-zero set of binding rules
+	if len(args.ACLBindingRuleIDs) == 0 {
+		return fmt.Errorf("must specify as least one binding rule")
+	}
+
+	// Update via Raft
+	_, index, err := a.srv.raftApply(structs.ACLBindingRulesDeleteRequestType, args)
+	if err != nil {
+		return err
+	}
+
+	// Update the index
+	reply.Index = index
+	return nil
+}
+
+// ListBindingRules is used to list the binding rules
+func (a *ACL) ListBindingRules(args *structs.ACLBindingRulesListRequest, reply *structs.ACLBindingRulesListResponse) error {
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	if done, err := a.srv.forward("ACL.ListBindingRules", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "list_binding_rules"}, time.Now())
+
+	// Check management level permissions
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			iter, err := state.ACLBindingRules(ws)
+			if err != nil {
+				return err
+			}
+
+			// Convert all the binding rules to a list stub
+			reply.ACLBindingRules = nil
+			for raw := iter.Next(); raw != nil; raw = iter.Next() {
+				rule := raw.(*structs.ACLBindingRule)
+				reply.ACLBindingRules = append(reply.ACLBindingRules, rule.Stub())
+			}
+
+			// Use the last index that affected the binding rules table
+			return a.setTableIndex(state, "acl_binding_rules", &reply.QueryMeta)
+		}}
+	return a.srv.blockingRPC(&opts)
+}
+
+// GetBindingRule is used to get a specific binding rule
+func (a *ACL) GetBindingRule(args *structs.ACLBindingRuleRequest, reply *structs.ACLBindingRuleResponse) error {
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	if done, err := a.srv.forward("ACL.GetBindingRule", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "get_binding_rule"}, time.Now())
+
+	// Check management level permissions
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			out, err := state.ACLBindingRuleByID(ws, args.ACLBindingRuleID)
+			if err != nil {
+				return err
+			}
+
+			// Setup the output
+			reply.ACLBindingRule = out
+			if out != nil {
+				reply.Index = out.ModifyIndex
+				return nil
+			}
+
+			// Use the last index that affected the binding rules table
+			return a.setTableIndex(state, "acl_binding_rules", &reply.QueryMeta)
+		}}
+	return a.srv.blockingRPC(&opts)
+}
+
+// GetBindingRules is used to get a set of binding rules
+func (a *ACL) GetBindingRules(args *structs.ACLBindingRulesRequest, reply *structs.ACLBindingRulesResponse) error {
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	if done, err := a.srv.forward("ACL.GetBindingRules", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "get_binding_rules"}, time.Now())
+
+	// Check management level permissions
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			reply.ACLBindingRules = make(map[string]*structs.ACLBindingRule, len(args.ACLBindingRuleIDs))
+			for _, id := range args.ACLBindingRuleIDs {
+				out, err := state.ACLBindingRuleByID(ws, id)
+				if err != nil {
+					return err
+				}
+				if out != nil {
+					reply.ACLBindingRules[id] = out
+				}
+			}
+
+			// Use the last index that affected the binding rules table
+			return a.setTableIndex(state, "acl_binding_rules", &reply.QueryMeta)
+		}}
+	return a.srv.blockingRPC(&opts)
+}
+
+// OIDCAuthURL is used to start logging in with an OIDC auth method. It
+// returns the URL of the OIDC provider the user authenticates at
+func (a *ACL) OIDCAuthURL(args *structs.ACLOIDCAuthURLRequest, reply *structs.ACLOIDCAuthURLResponse) error {
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	if done, err := a.srv.forward("ACL.OIDCAuthURL", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "oidc_auth_url"}, time.Now())
+
+	switch {
+	case args.RedirectURI == "":
+		return fmt.Errorf("missing redirect URI")
+	case args.ClientNonce == "":
+		return fmt.Errorf("missing client nonce")
+	case args.State == "":
+		return fmt.Errorf("missing state")
+	}
+
+	method, err := a.oidcAuthMethod(args.AuthMethodName, args.RedirectURI)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), oidcProviderTimeout)
+	defer cancel()
+	provider, err := auth.NewOIDCProvider(ctx, method.OIDCConfig())
+	if err != nil {
+		return fmt.Errorf("failed to query OIDC provider: %v", err)
+	}
+
+	reply.AuthURL = provider.AuthURL(args.RedirectURI, args.State, args.ClientNonce)
+	return nil
+}
+
+// OIDCCompleteAuth is used to complete logging in with an OIDC auth method.
+// The authorization code of the OIDC provider is exchanged for an ID token,
+// whose claims are matched against the binding rules of the method to create
+// an ACL token
+func (a *ACL) OIDCCompleteAuth(args *structs.ACLOIDCCompleteAuthRequest, reply *structs.ACLLoginResponse) error {
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	if done, err := a.srv.forward("ACL.OIDCCompleteAuth", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "oidc_complete_auth"}, time.Now())
+
+	switch {
+	case args.Code == "":
+		return fmt.Errorf("missing authorization code")
+	case args.ClientNonce == "":
+		return fmt.Errorf("missing client nonce")
+	case args.RedirectURI == "":
+		return fmt.Errorf("missing redirect URI")
+	}
+
+	method, err := a.oidcAuthMethod(args.AuthMethodName, args.RedirectURI)
+	if err != nil {
+		return err
+	}
+
+	// Global tokens are created in the authoritative region
+	if method.TokenLocalityIsGlobal() && a.srv.config.Region != a.srv.config.AuthoritativeRegion {
+		args.Region = a.srv.config.AuthoritativeRegion
+		return a.srv.forwardRegion(args.Region, "ACL.OIDCCompleteAuth", args, reply)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), oidcProviderTimeout)
+	defer cancel()
+	provider, err := auth.NewOIDCProvider(ctx, method.OIDCConfig())
+	if err != nil {
+		return fmt.Errorf("failed to query OIDC provider: %v", err)
+	}
+	claims, err := provider.Exchange(ctx, args.Code, args.RedirectURI, args.ClientNonce)
+	if err != nil {
+		return fmt.Errorf("failed to complete OIDC login: %v", err)
+	}
+
+	token, err := a.loginToken(method, claims)
+	if err != nil {
+		return err
+	}
+	return a.upsertLoginToken(token, args.WriteRequest, reply)
+}
+
+// Login is used to log in with a JWT auth method. The JWT presented by the
+// caller is verified, and its claims are matched against the binding rules
+// of the method to create an ACL token
+func (a *ACL) Login(args *structs.ACLLoginRequest, reply *structs.ACLLoginResponse) error {
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	if done, err := a.srv.forward("ACL.Login", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "login"}, time.Now())
+
+	if args.LoginToken == "" {
+		return fmt.Errorf("missing login token")
+	}
+
+	method, err := a.authMethod(args.AuthMethodName)
+	if err != nil {
+		return err
+	}
+	if method.Type != structs.ACLAuthMethodTypeJWT {
+		return fmt.Errorf("ACL auth method %q is not a JWT method", method.Name)
+	}
+
+	// Global tokens are created in the authoritative region
+	if method.TokenLocalityIsGlobal() && a.srv.config.Region != a.srv.config.AuthoritativeRegion {
+		args.Region = a.srv.config.AuthoritativeRegion
+		return a.srv.forwardRegion(args.Region, "ACL.Login", args, reply)
+	}
+
+	claims, err := a.srv.jwtVerifier.Verify(method.Name, method.JWTConfig(), args.LoginToken)
+	if err != nil {
+		return fmt.Errorf("failed to verify login token: %v", err)
+	}
+
+	token, err := a.loginToken(method, claims)
+	if err != nil {
+		return err
+	}
+	return a.upsertLoginToken(token, args.WriteRequest, reply)
+}
+
+// upsertLoginToken creates the token of a login and sets it in the reply.
+func (a *ACL) upsertLoginToken(token *structs.ACLToken, wr structs.WriteRequest, reply *structs.ACLLoginResponse) error {
+	// Update via Raft
+	req := &structs.ACLTokenUpsertRequest{
+		Tokens:       []*structs.ACLToken{token},
+		WriteRequest: wr,
+	}
+	_, index, err := a.srv.raftApply(structs.ACLTokenUpsertRequestType, req)
+	if err != nil {
+		return err
+	}
+
+	// Populate the response. We do a lookup against the state to
+	// pickup the proper create / modify times.
+	out, err := a.srv.State().ACLTokenByAccessorID(nil, token.AccessorID)
+	if err != nil {
+		return fmt.Errorf("token lookup failed: %v", err)
+	}
+	reply.ACLToken = out
+	reply.Index = index
+	return nil
+}
+
+// oidcAuthMethod returns the OIDC auth method to log in with, which is the
+// default method if the name is empty. The redirect URI must be allowed.
+func (a *ACL) oidcAuthMethod(name, redirectURI string) (*structs.ACLAuthMethod, error) {
+	method, err := a.authMethod(name)
+	if err != nil {
+		return nil, err
+	}
+	if method.Type != structs.ACLAuthMethodTypeOIDC {
+		return nil, fmt.Errorf("ACL auth method %q is not an OIDC method", method.Name)
+	}
+	if !method.RedirectURIAllowed(redirectURI) {
+		return nil, fmt.Errorf("redirect URI %q is not allowed", redirectURI)
+	}
+	return method, nil
+}
+
+// authMethod returns the auth method to log in with, which is the default
+// method if the name is empty.
+func (a *ACL) authMethod(name string) (*structs.ACLAuthMethod, error) {
+	state := a.srv.State()
+	var method *structs.ACLAuthMethod
+	var err error
+	if name == "" {
+		method, err = state.DefaultACLAuthMethod(nil)
+		if err != nil {
+			return nil, err
+		}
+		if method == nil {
+			return nil, fmt.Errorf("no default ACL auth method")
+		}
+	} else {
+		method, err = state.ACLAuthMethodByName(nil, name)
+		if err != nil {
+			return nil, err
+		}
+		if method == nil {
+			return nil, fmt.Errorf("ACL auth method %q not found", name)
+		}
+	}
+	return method, nil
+}
+
+// loginToken returns the token created by logging in with the auth method,
+// bound by the binding rules of the method matching the claims of the user.
+// A management rule creates a management token, otherwise the token has the
+// policies and roles of the matching policy and role rules.
+func (a *ACL) loginToken(method *structs.ACLAuthMethod, claims auth.Claims) (*structs.ACLToken, error) {
+	data, err := auth.NewSelectorData(claims, method.Config.ClaimMappings, method.Config.ListClaimMappings)
+	if err != nil {
+		return nil, err
+	}
+
+	iter, err := a.srv.State().ACLBindingRulesByAuthMethod(nil, method.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	management := false
+	var policies, roles []string
+	seen := make(map[string]struct{})
+	seenRoles := make(map[string]struct{})
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		rule := raw.(*structs.ACLBindingRule)
+		selector, err := auth.ParseSelector(rule.Selector)
+		if err != nil {
+			// Rules are validated when upserted
+			a.logger.Warn("invalid binding rule selector", "binding_rule", rule.ID, "error", err)
+			continue
+		}
+		if !selector.Match(data) {
+			continue
+		}
+
+		switch rule.BindType {
+		case structs.ACLBindingRuleBindTypeManagement:
+			management = true
+		case structs.ACLBindingRuleBindTypePolicy:
+			if _, ok := seen[rule.BindName]; !ok {
+				seen[rule.BindName] = struct{}{}
+				policies = append(policies, rule.BindName)
+			}
+		case structs.ACLBindingRuleBindTypeRole:
+			if _, ok := seenRoles[rule.BindName]; !ok {
+				seenRoles[rule.BindName] = struct{}{}
+				roles = append(roles, rule.BindName)
+			}
+		}
+	}
+
+	now := time.Now().UTC()
+	expiration := now.Add(method.MaxTokenTTL)
+	token := &structs.ACLToken{
+		AccessorID:     uuid.Generate(),
+		SecretID:       uuid.Generate(),
+		Name:           method.Type + "-" + method.Name,
+		Type:           structs.ACLClientToken,
+		Policies:       policies,
+		Roles:          roles,
+		Global:         method.TokenLocalityIsGlobal(),
+		CreateTime:     now,
+		ExpirationTime: &expiration,
+	}
+	if management {
+		token.Type = structs.ACLManagementToken
+		token.Policies = nil
+		token.Roles = nil
+	} else if len(policies) == 0 && len(roles) == 0 {
+		a.logger.Debug("no binding rules matched the login claims", "auth_method", method.Name)
+		return nil, structs.ErrPermissionDenied
+	}
+	token.SetHash()
+	return token, nil
+}
+
+// setTableIndex sets the index of the reply to the last index that affected
+// the table.
+func (a *ACL) setTableIndex(state *state.StateStore, table string, reply *structs.QueryMeta) error {
+	index, err := state.Index(table)
+	if err != nil {
+		return err
+	}
+
+	// Ensure we never set the index to zero, otherwise a blocking query cannot be used.
+	// We floor the index at one, since realistically the first write must have a higher index.
+	if index == 0 {
+		index = 1
+	}
+	reply.Index = index
+	return nil
+}
@@ -0,0 +1,375 @@
+package nomad
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/lib/auth"
+	"github.com/hashicorp/nomad/lib/auth/authtest"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestACLEndpoint_UpsertAuthMethods(t *testing.T) {
+	t.Parallel()
+	s1, root := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	am1 := mock.ACLAuthMethod()
+	am1.Default = true
+	req := &structs.ACLAuthMethodUpsertRequest{
+		AuthMethods: []*structs.ACLAuthMethod{am1},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	var resp structs.ACLAuthMethodUpsertResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ACL.UpsertAuthMethods", req, &resp))
+	require.NotEqual(t, uint64(0), resp.Index)
+	require.Len(t, resp.AuthMethods, 1)
+	require.False(t, resp.AuthMethods[0].CreateTime.IsZero())
+	require.NotEmpty(t, resp.AuthMethods[0].Hash)
+
+	// Only one method can be the default
+	am2 := mock.ACLAuthMethod()
+	am2.Default = true
+	req.AuthMethods = []*structs.ACLAuthMethod{am2}
+	err := msgpackrpc.CallWithCodec(codec, "ACL.UpsertAuthMethods", req, &resp)
+	require.EqualError(t, err, `default auth method "`+am1.Name+`" already exists`)
+
+	// Invalid methods are rejected
+	am2.Default = false
+	am2.Config.OIDCClientID = ""
+	err = msgpackrpc.CallWithCodec(codec, "ACL.UpsertAuthMethods", req, &resp)
+	require.Error(t, err)
+
+	// Management permissions are required
+	token := mock.ACLToken()
+	require.NoError(t, s1.fsm.State().UpsertACLTokens(1000, []*structs.ACLToken{token}))
+	req.AuthMethods = []*structs.ACLAuthMethod{mock.ACLAuthMethod()}
+	req.AuthToken = token.SecretID
+	err = msgpackrpc.CallWithCodec(codec, "ACL.UpsertAuthMethods", req, &resp)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+}
+
+func TestACLEndpoint_ListGetDeleteAuthMethods(t *testing.T) {
+	t.Parallel()
+	s1, root := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	am1 := mock.ACLAuthMethod()
+	am2 := mock.ACLAuthMethod()
+	require.NoError(t, s1.fsm.State().UpsertACLAuthMethods(1000, []*structs.ACLAuthMethod{am1, am2}))
+
+	// Listing doesn't require a management token
+	listReq := &structs.ACLAuthMethodListRequest{
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var listResp structs.ACLAuthMethodListResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ACL.ListAuthMethods", listReq, &listResp))
+	require.Equal(t, uint64(1000), listResp.Index)
+	require.Len(t, listResp.AuthMethods, 2)
+
+	// Reading the config does
+	getReq := &structs.ACLAuthMethodGetRequest{
+		Name:         am1.Name,
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var getResp structs.ACLAuthMethodGetResponse
+	err := msgpackrpc.CallWithCodec(codec, "ACL.GetAuthMethod", getReq, &getResp)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+
+	getReq.AuthToken = root.SecretID
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ACL.GetAuthMethod", getReq, &getResp))
+	require.Equal(t, am1, getResp.AuthMethod)
+
+	getsReq := &structs.ACLAuthMethodsGetRequest{
+		Names:        []string{am1.Name, am2.Name},
+		QueryOptions: structs.QueryOptions{Region: "global", AuthToken: root.SecretID},
+	}
+	var getsResp structs.ACLAuthMethodsGetResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ACL.GetAuthMethods", getsReq, &getsResp))
+	require.Len(t, getsResp.AuthMethods, 2)
+
+	delReq := &structs.ACLAuthMethodDeleteRequest{
+		Names: []string{am1.Name},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	var delResp structs.GenericResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ACL.DeleteAuthMethods", delReq, &delResp))
+
+	out, err := s1.fsm.State().ACLAuthMethodByName(nil, am1.Name)
+	require.NoError(t, err)
+	require.Nil(t, out)
+}
+
+func TestACLEndpoint_BindingRules(t *testing.T) {
+	t.Parallel()
+	s1, root := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	am := mock.ACLAuthMethod()
+	require.NoError(t, s1.fsm.State().UpsertACLAuthMethods(1000, []*structs.ACLAuthMethod{am}))
+
+	// New rules are given an ID
+	rule := mock.ACLBindingRule()
+	rule.ID = ""
+	rule.AuthMethod = am.Name
+	req := &structs.ACLBindingRulesUpsertRequest{
+		ACLBindingRules: []*structs.ACLBindingRule{rule},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	var resp structs.ACLBindingRulesUpsertResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ACL.UpsertBindingRules", req, &resp))
+	require.Len(t, resp.ACLBindingRules, 1)
+	id := resp.ACLBindingRules[0].ID
+	require.NotEmpty(t, id)
+
+	// The auth method must exist
+	missing := mock.ACLBindingRule()
+	missing.ID = ""
+	req.ACLBindingRules = []*structs.ACLBindingRule{missing}
+	err := msgpackrpc.CallWithCodec(codec, "ACL.UpsertBindingRules", req, &resp)
+	require.EqualError(t, err, `ACL auth method "auth0" not found`)
+
+	// Updated rules must exist
+	unknown := mock.ACLBindingRule()
+	unknown.AuthMethod = am.Name
+	req.ACLBindingRules = []*structs.ACLBindingRule{unknown}
+	err = msgpackrpc.CallWithCodec(codec, "ACL.UpsertBindingRules", req, &resp)
+	require.EqualError(t, err, "cannot find binding rule "+unknown.ID)
+
+	listReq := &structs.ACLBindingRulesListRequest{
+		QueryOptions: structs.QueryOptions{Region: "global", AuthToken: root.SecretID},
+	}
+	var listResp structs.ACLBindingRulesListResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ACL.ListBindingRules", listReq, &listResp))
+	require.Len(t, listResp.ACLBindingRules, 1)
+
+	getReq := &structs.ACLBindingRuleRequest{
+		ACLBindingRuleID: id,
+		QueryOptions:     structs.QueryOptions{Region: "global", AuthToken: root.SecretID},
+	}
+	var getResp structs.ACLBindingRuleResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ACL.GetBindingRule", getReq, &getResp))
+	require.Equal(t, rule.Selector, getResp.ACLBindingRule.Selector)
+
+	delReq := &structs.ACLBindingRulesDeleteRequest{
+		ACLBindingRuleIDs: []string{id},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	var delResp structs.GenericResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ACL.DeleteBindingRules", delReq, &delResp))
+
+	out, err := s1.fsm.State().ACLBindingRuleByID(nil, id)
+	require.NoError(t, err)
+	require.Nil(t, out)
+}
+
+// authorizeOIDC follows the auth URL to the test OIDC provider and returns
+// the authorization code it redirects with.
+func authorizeOIDC(t *testing.T, authURL string) string {
+	client := &http.Client{
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	resp, err := client.Get(authURL)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	loc, err := url.Parse(resp.Header.Get("Location"))
+	require.NoError(t, err)
+	require.Equal(t, "state", loc.Query().Get("state"))
+	return loc.Query().Get("code")
+}
+
+func TestACLEndpoint_OIDCLogin(t *testing.T) {
+	t.Parallel()
+	s1, _ := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	provider := authtest.NewTestOIDCProvider()
+	defer provider.Close()
+	provider.SetClaims(auth.Claims{"groups": []interface{}{"engineering"}})
+
+	redirectURI := "http://localhost:4649/oidc/callback"
+	am := mock.ACLAuthMethod()
+	am.Default = true
+	am.Config.OIDCDiscoveryURL = provider.URL()
+	am.Config.OIDCClientID = authtest.TestOIDCClientID
+	am.Config.OIDCClientSecret = authtest.TestOIDCClientSecret
+	am.Config.BoundAudiences = nil
+	am.Config.AllowedRedirectURIs = []string{redirectURI}
+	am.Config.SigningAlgs = []string{"ES256"}
+	require.NoError(t, s1.fsm.State().UpsertACLAuthMethods(1000, []*structs.ACLAuthMethod{am}))
+
+	rule := mock.ACLBindingRule()
+	rule.AuthMethod = am.Name
+	rule.Selector = `"engineering" in list.groups`
+	require.NoError(t, s1.fsm.State().UpsertACLBindingRules(1001, []*structs.ACLBindingRule{rule}))
+
+	login := func() (*structs.ACLLoginResponse, error) {
+		urlReq := &structs.ACLOIDCAuthURLRequest{
+			RedirectURI:  redirectURI,
+			ClientNonce:  "nonce",
+			State:        "state",
+			WriteRequest: structs.WriteRequest{Region: "global"},
+		}
+		var urlResp structs.ACLOIDCAuthURLResponse
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "ACL.OIDCAuthURL", urlReq, &urlResp))
+
+		req := &structs.ACLOIDCCompleteAuthRequest{
+			AuthMethodName: am.Name,
+			ClientNonce:    "nonce",
+			Code:           authorizeOIDC(t, urlResp.AuthURL),
+			RedirectURI:    redirectURI,
+			WriteRequest:   structs.WriteRequest{Region: "global"},
+		}
+		var resp structs.ACLLoginResponse
+		err := msgpackrpc.CallWithCodec(codec, "ACL.OIDCCompleteAuth", req, &resp)
+		return &resp, err
+	}
+
+	// The token has the policy of the matching binding rule
+	resp, err := login()
+	require.NoError(t, err)
+	token := resp.ACLToken
+	require.Equal(t, structs.ACLClientToken, token.Type)
+	require.Equal(t, []string{rule.BindName}, token.Policies)
+	require.False(t, token.Global)
+	require.NotNil(t, token.ExpirationTime)
+	require.WithinDuration(t, time.Now().Add(am.MaxTokenTTL), *token.ExpirationTime, time.Minute)
+
+	out, err := s1.ResolveToken(token.SecretID)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+
+	// Expired tokens can't be resolved
+	expired := mock.ACLToken()
+	past := time.Now().Add(-time.Minute)
+	expired.ExpirationTime = &past
+	require.NoError(t, s1.fsm.State().UpsertACLTokens(1010, []*structs.ACLToken{expired}))
+	_, err = s1.ResolveToken(expired.SecretID)
+	require.Equal(t, structs.ErrTokenNotFound, err)
+
+	// Users not matching any binding rule can't log in
+	provider.SetClaims(auth.Claims{"groups": []interface{}{"marketing"}})
+	_, err = login()
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+
+	// The redirect URI must be allowed
+	urlReq := &structs.ACLOIDCAuthURLRequest{
+		RedirectURI:  "http://evil.example.com",
+		ClientNonce:  "nonce",
+		State:        "state",
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var urlResp structs.ACLOIDCAuthURLResponse
+	err = msgpackrpc.CallWithCodec(codec, "ACL.OIDCAuthURL", urlReq, &urlResp)
+	require.EqualError(t, err, `redirect URI "http://evil.example.com" is not allowed`)
+}
+
+func TestACLEndpoint_JWTLogin(t *testing.T) {
+	t.Parallel()
+	s1, _ := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	issuer := authtest.NewTestOIDCProvider()
+	defer issuer.Close()
+
+	am := mock.ACLAuthMethod()
+	am.Type = structs.ACLAuthMethodTypeJWT
+	am.Config = &structs.ACLAuthMethodConfig{
+		JWKSURL:           issuer.JWKSURL(),
+		BoundIssuer:       "https://ci.example.com",
+		BoundAudiences:    []string{"nomad"},
+		SigningAlgs:       []string{"ES256"},
+		ClaimMappings:     map[string]string{"project": "project"},
+		ListClaimMappings: map[string]string{"groups": "groups"},
+	}
+	am.SetHash()
+	require.NoError(t, am.Validate())
+	require.NoError(t, s1.fsm.State().UpsertACLAuthMethods(1000, []*structs.ACLAuthMethod{am}))
+
+	rule := mock.ACLBindingRule()
+	rule.AuthMethod = am.Name
+	rule.Selector = `value.project == "web"`
+	require.NoError(t, s1.fsm.State().UpsertACLBindingRules(1001, []*structs.ACLBindingRule{rule}))
+
+	login := func(claims auth.Claims) (*structs.ACLLoginResponse, error) {
+		claims["iss"] = "https://ci.example.com"
+		claims["aud"] = "nomad"
+		claims["exp"] = float64(time.Now().Add(time.Minute).Unix())
+		req := &structs.ACLLoginRequest{
+			AuthMethodName: am.Name,
+			LoginToken:     issuer.SignToken(claims),
+			WriteRequest:   structs.WriteRequest{Region: "global"},
+		}
+		var resp structs.ACLLoginResponse
+		err := msgpackrpc.CallWithCodec(codec, "ACL.Login", req, &resp)
+		return &resp, err
+	}
+
+	// The token has the policy of the matching binding rule
+	resp, err := login(auth.Claims{"project": "web"})
+	require.NoError(t, err)
+	token := resp.ACLToken
+	require.Equal(t, structs.ACLClientToken, token.Type)
+	require.Equal(t, "JWT-"+am.Name, token.Name)
+	require.Equal(t, []string{rule.BindName}, token.Policies)
+	require.NotNil(t, token.ExpirationTime)
+
+	// Callers not matching any binding rule can't log in
+	_, err = login(auth.Claims{"project": "api"})
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+
+	// Tokens of other issuers are rejected
+	other := authtest.NewTestOIDCProvider()
+	defer other.Close()
+	req := &structs.ACLLoginRequest{
+		AuthMethodName: am.Name,
+		LoginToken: other.SignToken(auth.Claims{
+			"iss": "https://ci.example.com",
+			"aud": "nomad",
+			"exp": float64(time.Now().Add(time.Minute).Unix()),
+		}),
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var loginResp structs.ACLLoginResponse
+	err = msgpackrpc.CallWithCodec(codec, "ACL.Login", req, &loginResp)
+	require.EqualError(t, err, "failed to verify login token: failed to verify token signature")
+
+	// OIDC methods can't be logged in with a JWT
+	oidc := mock.ACLAuthMethod()
+	require.NoError(t, s1.fsm.State().UpsertACLAuthMethods(1002, []*structs.ACLAuthMethod{oidc}))
+	req.AuthMethodName = oidc.Name
+	err = msgpackrpc.CallWithCodec(codec, "ACL.Login", req, &loginResp)
+	require.EqualError(t, err, fmt.Sprintf("ACL auth method %q is not a JWT method", oidc.Name))
+}
@@ -148,8 +148,12 @@ func (a *ACL) ListPolicies(args *structs.ACLPolicyListRequest, reply *structs.AC
 			return structs.ErrTokenNotFound
 		}
 
-		policies = make(map[string]struct{}, len(token.Policies))
-		for _, p := range token.Policies {
+		policyNames, err := tokenPolicyNames(&snap.StateStore, token)
+		if err != nil {
+			return err
+		}
+		policies = make(map[string]struct{}, len(policyNames))
+		for _, p := range policyNames {
 			policies[p] = struct{}{}
 		}
 	}
@@ -235,8 +239,12 @@ func (a *ACL) GetPolicy(args *structs.ACLPolicySpecificRequest, reply *structs.S
 			return structs.ErrTokenNotFound
 		}
 
+		policyNames, err := tokenPolicyNames(&snap.StateStore, token)
+		if err != nil {
+			return err
+		}
 		found := false
-		for _, p := range token.Policies {
+		for _, p := range policyNames {
 			if p == args.Name {
 				found = true
 				break
@@ -301,11 +309,20 @@ func (a *ACL) GetPolicies(args *structs.ACLPolicySetRequest, reply *structs.ACLP
 		}
 	}
 
-	if token == nil {
+	if token == nil || token.IsExpired(time.Now().UTC()) {
 		return structs.ErrTokenNotFound
 	}
-	if token.Type != structs.ACLManagementToken && !token.PolicySubset(args.Names) {
-		return structs.ErrPermissionDenied
+	if token.Type != structs.ACLManagementToken {
+		// The policies of the roles of the token are associated with it too
+		policyNames, err := tokenPolicyNames(a.srv.State(), token)
+		if err != nil {
+			return err
+		}
+		granted := *token
+		granted.Policies = policyNames
+		if !granted.PolicySubset(args.Names) {
+			return structs.ErrPermissionDenied
+		}
 	}
 
 	// Setup the blocking query
@@ -497,11 +514,38 @@ func (a *ACL) UpsertTokens(args *structs.ACLTokenUpsertRequest, reply *structs.A
 			return fmt.Errorf("token %d invalid: %v", idx, err)
 		}
 
-		// Generate an accessor and secret ID if new
+		// Ensure the roles of the token exist
+		for _, roleName := range token.Roles {
+			role, err := state.ACLRoleByName(nil, roleName)
+			if err != nil {
+				return fmt.Errorf("role lookup failed: %v", err)
+			}
+			if role == nil {
+				return fmt.Errorf("cannot find role %s", roleName)
+			}
+		}
+
+		// Generate an accessor and secret ID if new, along with the
+		// expiration time of the tokens created with an expiration TTL. The
+		// expiration time of existing tokens cannot change
 		if token.AccessorID == "" {
 			token.AccessorID = uuid.Generate()
 			token.SecretID = uuid.Generate()
 			token.CreateTime = time.Now().UTC()
+			token.ExpirationTime = nil
+
+			if ttl := token.ExpirationTTL; ttl != 0 {
+				if ttl < a.srv.config.ACLTokenMinExpirationTTL {
+					return fmt.Errorf("token %d invalid: expiration TTL is shorter than %v",
+						idx, a.srv.config.ACLTokenMinExpirationTTL)
+				}
+				if ttl > a.srv.config.ACLTokenMaxExpirationTTL {
+					return fmt.Errorf("token %d invalid: expiration TTL is longer than %v",
+						idx, a.srv.config.ACLTokenMaxExpirationTTL)
+				}
+				expiration := token.CreateTime.Add(ttl)
+				token.ExpirationTime = &expiration
+			}
 
 		} else {
 			// Verify the token exists
@@ -813,11 +857,14 @@ func (a *ACL) ResolveToken(args *structs.ResolveACLTokenRequest, reply *structs.
 		return err
 	}
 
-	// Look for the token
+	// Look for the token, expired tokens being treated as not found
 	out, err := state.ACLTokenBySecretID(nil, args.SecretID)
 	if err != nil {
 		return err
 	}
+	if out != nil && out.IsExpired(time.Now().UTC()) {
+		out = nil
+	}
 
 	// Setup the output
 	reply.Token = out
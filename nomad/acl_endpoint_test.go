@@ -15,6 +15,7 @@ import (
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/testutil"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestACLEndpoint_GetPolicy(t *testing.T) {
@@ -1089,6 +1090,47 @@ func TestACLEndpoint_UpsertTokens(t *testing.T) {
 	assert.Equal(t, created, out)
 }
 
+func TestACLEndpoint_UpsertTokens_ExpirationTTL(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1, root := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	token := mock.ACLToken()
+	token.AccessorID = ""
+	token.ExpirationTTL = time.Hour
+	req := &structs.ACLTokenUpsertRequest{
+		Tokens: []*structs.ACLToken{token},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	var resp structs.ACLTokenUpsertResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "ACL.UpsertTokens", req, &resp))
+	created := resp.Tokens[0]
+	require.NotNil(created.ExpirationTime)
+	require.Equal(created.CreateTime.Add(time.Hour), *created.ExpirationTime)
+
+	// The TTL must be within the configured bounds
+	token.AccessorID = ""
+	token.ExpirationTTL = time.Second
+	err := msgpackrpc.CallWithCodec(codec, "ACL.UpsertTokens", req, &resp)
+	require.EqualError(err, "token 0 invalid: expiration TTL is shorter than 1m0s")
+
+	token.ExpirationTTL = 48 * time.Hour
+	err = msgpackrpc.CallWithCodec(codec, "ACL.UpsertTokens", req, &resp)
+	require.EqualError(err, "token 0 invalid: expiration TTL is longer than 24h0m0s")
+
+	// The roles of the token must exist
+	token.ExpirationTTL = 0
+	token.Roles = []string{"missing"}
+	err = msgpackrpc.CallWithCodec(codec, "ACL.UpsertTokens", req, &resp)
+	require.EqualError(err, "cannot find role missing")
+}
+
 func TestACLEndpoint_UpsertTokens_Invalid(t *testing.T) {
 	t.Parallel()
 	s1, root := TestACLServer(t, nil)
@@ -0,0 +1,267 @@
+package nomad
+
+import (
+	"fmt"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// UpsertRoles is used to create or update a set of roles
+func (a *ACL) UpsertRoles(args *structs.ACLRolesUpsertRequest, reply *structs.ACLRolesUpsertResponse) error {
+	// Ensure ACLs are enabled, and always flow modification requests to the authoritative region
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	args.Region = a.srv.config.AuthoritativeRegion
+
+	if done, err := a.srv.forward("ACL.UpsertRoles", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "upsert_roles"}, time.Now())
+
+	// Check management level permissions
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Validate non-zero set of roles
+	if len(args.Roles) == 0 {
+		return fmt.Errorf("must specify as least one role")
+	}
+
+	// Snapshot the state
+	state, err := a.srv.State().Snapshot()
+	if err != nil {
+		return err
+	}
+
+	// Validate each role, compute hash
+	for idx, role := range args.Roles {
+		if err := role.Validate(); err != nil {
+			return fmt.Errorf("role %d invalid: %v", idx, err)
+		}
+
+		// Ensure the policies of the role exist
+		for _, policyName := range role.Policies {
+			policy, err := state.ACLPolicyByName(nil, policyName)
+			if err != nil {
+				return fmt.Errorf("policy lookup failed: %v", err)
+			}
+			if policy == nil {
+				return fmt.Errorf("role %d invalid: cannot find policy %s", idx, policyName)
+			}
+		}
+		role.SetHash()
+	}
+
+	// Update via Raft
+	_, index, err := a.srv.raftApply(structs.ACLRolesUpsertRequestType, args)
+	if err != nil {
+		return err
+	}
+
+	// Populate the response. We do a lookup against the state to
+	// pickup the proper indexes.
+	state, err = a.srv.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	for _, role := range args.Roles {
+		out, err := state.ACLRoleByName(nil, role.Name)
+		if err != nil {
+			return fmt.Errorf("role lookup failed: %v", err)
+		}
+		reply.Roles = append(reply.Roles, out)
+	}
+
+	// Update the index
+	reply.Index = index
+	return nil
+}
+
+// DeleteRoles is used to delete roles. The tokens linked to a deleted role
+// lose the policies of the role.
+func (a *ACL) DeleteRoles(args *structs.ACLRolesDeleteRequest, reply *structs.GenericResponse) error {
+	// Ensure ACLs are enabled, and always flow modification requests to the authoritative region
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	args.Region = a.srv.config.AuthoritativeRegion
+
+	if done, err := a.srv.forward("ACL.DeleteRoles", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "delete_roles"}, time.Now())
+
+	// Check management level permissions
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Validate non-zero set of roles
+	if len(args.Names) == 0 {
+		return fmt.Errorf("must specify as least one role")
+	}
+
+	// Update via Raft
+	_, index, err := a.srv.raftApply(structs.ACLRolesDeleteRequestType, args)
+	if err != nil {
+		return err
+	}
+
+	// Update the index
+	reply.Index = index
+	return nil
+}
+
+// ListRoles is used to list the roles. Management tokens list all the roles,
+// while client tokens only list their own roles.
+func (a *ACL) ListRoles(args *structs.ACLRolesListRequest, reply *structs.ACLRolesListResponse) error {
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	if done, err := a.srv.forward("ACL.ListRoles", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "list_roles"}, time.Now())
+
+	token, err := a.roleRequestToken(args.AuthToken)
+	if err != nil {
+		return err
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			// Iterate over all the roles
+			var err error
+			var iter memdb.ResultIterator
+			if prefix := args.QueryOptions.Prefix; prefix != "" {
+				iter, err = state.ACLRoleByNamePrefix(ws, prefix)
+			} else {
+				iter, err = state.ACLRoles(ws)
+			}
+			if err != nil {
+				return err
+			}
+
+			// Convert all the roles to a list stub
+			reply.Roles = nil
+			for raw := iter.Next(); raw != nil; raw = iter.Next() {
+				role := raw.(*structs.ACLRole)
+				if token.RoleSubset([]string{role.Name}) {
+					reply.Roles = append(reply.Roles, role.Stub())
+				}
+			}
+
+			// Use the last index that affected the roles table
+			return a.setTableIndex(state, "acl_roles", &reply.QueryMeta)
+		}}
+	return a.srv.blockingRPC(&opts)
+}
+
+// GetRole is used to get a specific role
+func (a *ACL) GetRole(args *structs.ACLRoleRequest, reply *structs.ACLRoleResponse) error {
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	if done, err := a.srv.forward("ACL.GetRole", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "get_role"}, time.Now())
+
+	// Client tokens can only get their own roles
+	token, err := a.roleRequestToken(args.AuthToken)
+	if err != nil {
+		return err
+	}
+	if !token.RoleSubset([]string{args.Name}) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			out, err := state.ACLRoleByName(ws, args.Name)
+			if err != nil {
+				return err
+			}
+
+			// Setup the output
+			reply.Role = out
+			if out != nil {
+				reply.Index = out.ModifyIndex
+				return nil
+			}
+
+			// Use the last index that affected the roles table
+			return a.setTableIndex(state, "acl_roles", &reply.QueryMeta)
+		}}
+	return a.srv.blockingRPC(&opts)
+}
+
+// GetRoles is used to get a set of roles. Client tokens can get their own
+// roles, which clients use to resolve the policies of the tokens.
+func (a *ACL) GetRoles(args *structs.ACLRolesRequest, reply *structs.ACLRolesResponse) error {
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	if done, err := a.srv.forward("ACL.GetRoles", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "get_roles"}, time.Now())
+
+	token, err := a.roleRequestToken(args.AuthToken)
+	if err != nil {
+		return err
+	}
+	if !token.RoleSubset(args.Names) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			reply.Roles = make(map[string]*structs.ACLRole, len(args.Names))
+			for _, name := range args.Names {
+				out, err := state.ACLRoleByName(ws, name)
+				if err != nil {
+					return err
+				}
+				if out != nil {
+					reply.Roles[name] = out
+				}
+			}
+
+			// Use the last index that affected the roles table
+			return a.setTableIndex(state, "acl_roles", &reply.QueryMeta)
+		}}
+	return a.srv.blockingRPC(&opts)
+}
+
+// roleRequestToken returns the token of a request reading roles, which
+// determines the roles that can be read.
+func (a *ACL) roleRequestToken(secretID string) (*structs.ACLToken, error) {
+	token, err := a.srv.ResolveSecretToken(secretID)
+	if err != nil {
+		return nil, err
+	}
+	if token == nil {
+		return nil, structs.ErrTokenNotFound
+	}
+	return token, nil
+}
@@ -0,0 +1,122 @@
+package nomad
+
+import (
+	"testing"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestACLEndpoint_UpsertRoles(t *testing.T) {
+	t.Parallel()
+	s1, root := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	policy := mock.ACLPolicy()
+	require.NoError(t, s1.fsm.State().UpsertACLPolicies(1000, []*structs.ACLPolicy{policy}))
+
+	role := mock.ACLRole()
+	role.Policies = []string{policy.Name}
+	req := &structs.ACLRolesUpsertRequest{
+		Roles: []*structs.ACLRole{role},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	var resp structs.ACLRolesUpsertResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ACL.UpsertRoles", req, &resp))
+	require.NotEqual(t, uint64(0), resp.Index)
+	require.Len(t, resp.Roles, 1)
+	require.NotEmpty(t, resp.Roles[0].Hash)
+
+	// The policies of the role must exist
+	role2 := mock.ACLRole()
+	req.Roles = []*structs.ACLRole{role2}
+	err := msgpackrpc.CallWithCodec(codec, "ACL.UpsertRoles", req, &resp)
+	require.EqualError(t, err, "role 0 invalid: cannot find policy foo")
+
+	// Management permissions are required
+	token := mock.ACLToken()
+	require.NoError(t, s1.fsm.State().UpsertACLTokens(1001, []*structs.ACLToken{token}))
+	req.Roles = []*structs.ACLRole{role}
+	req.AuthToken = token.SecretID
+	err = msgpackrpc.CallWithCodec(codec, "ACL.UpsertRoles", req, &resp)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+}
+
+func TestACLEndpoint_ListGetDeleteRoles(t *testing.T) {
+	t.Parallel()
+	s1, root := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	role1 := mock.ACLRole()
+	role2 := mock.ACLRole()
+	token := mock.ACLToken()
+	token.Roles = []string{role1.Name}
+	state := s1.fsm.State()
+	require.NoError(t, state.UpsertACLRoles(1000, []*structs.ACLRole{role1, role2}))
+	require.NoError(t, state.UpsertACLTokens(1001, []*structs.ACLToken{token}))
+
+	// Management tokens list all the roles
+	listReq := &structs.ACLRolesListRequest{
+		QueryOptions: structs.QueryOptions{Region: "global", AuthToken: root.SecretID},
+	}
+	var listResp structs.ACLRolesListResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ACL.ListRoles", listReq, &listResp))
+	require.Equal(t, uint64(1000), listResp.Index)
+	require.Len(t, listResp.Roles, 2)
+
+	// Client tokens only list their own roles
+	listReq.AuthToken = token.SecretID
+	var clientListResp structs.ACLRolesListResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ACL.ListRoles", listReq, &clientListResp))
+	require.Len(t, clientListResp.Roles, 1)
+	require.Equal(t, role1.Name, clientListResp.Roles[0].Name)
+
+	// and can only read their own roles
+	getReq := &structs.ACLRoleRequest{
+		Name:         role1.Name,
+		QueryOptions: structs.QueryOptions{Region: "global", AuthToken: token.SecretID},
+	}
+	var getResp structs.ACLRoleResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ACL.GetRole", getReq, &getResp))
+	require.Equal(t, role1, getResp.Role)
+
+	getReq.Name = role2.Name
+	err := msgpackrpc.CallWithCodec(codec, "ACL.GetRole", getReq, &getResp)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+
+	getsReq := &structs.ACLRolesRequest{
+		Names:        []string{role1.Name, role2.Name},
+		QueryOptions: structs.QueryOptions{Region: "global", AuthToken: token.SecretID},
+	}
+	var getsResp structs.ACLRolesResponse
+	err = msgpackrpc.CallWithCodec(codec, "ACL.GetRoles", getsReq, &getsResp)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+
+	getsReq.AuthToken = root.SecretID
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ACL.GetRoles", getsReq, &getsResp))
+	require.Len(t, getsResp.Roles, 2)
+
+	delReq := &structs.ACLRolesDeleteRequest{
+		Names: []string{role1.Name},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	var delResp structs.GenericResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ACL.DeleteRoles", delReq, &delResp))
+
+	out, err := state.ACLRoleByName(nil, role1.Name)
+	require.NoError(t, err)
+	require.Nil(t, out)
+}
@@ -92,6 +92,39 @@ func TestResolveACLToken(t *testing.T) {
 	}
 }
 
+func TestResolveACLToken_Roles(t *testing.T) {
+	t.Parallel()
+
+	state := state.TestStateStore(t)
+	cache, err := lru.New2Q(16)
+	assert.Nil(t, err)
+
+	// Create a token granted a policy through a role only
+	policy := mock.ACLPolicy()
+	role := mock.ACLRole()
+	role.Policies = []string{policy.Name}
+	token := mock.ACLToken()
+	token.Policies = nil
+	token.Roles = []string{role.Name}
+	assert.Nil(t, state.UpsertACLPolicies(100, []*structs.ACLPolicy{policy}))
+	assert.Nil(t, state.UpsertACLRoles(110, []*structs.ACLRole{role}))
+	assert.Nil(t, state.UpsertACLTokens(120, []*structs.ACLToken{token}))
+
+	snap, err := state.Snapshot()
+	assert.Nil(t, err)
+	aclObj, err := resolveTokenFromSnapshotCache(snap, cache, token.SecretID)
+	assert.Nil(t, err)
+	assert.True(t, aclObj.AllowNamespaceOperation("default", acl.NamespaceCapabilityListJobs))
+
+	// Deleting the role revokes its policies
+	assert.Nil(t, state.DeleteACLRoles(130, []string{role.Name}))
+	snap, err = state.Snapshot()
+	assert.Nil(t, err)
+	aclObj, err = resolveTokenFromSnapshotCache(snap, cache, token.SecretID)
+	assert.Nil(t, err)
+	assert.False(t, aclObj.AllowNamespaceOperation("default", acl.NamespaceCapabilityListJobs))
+}
+
 func TestResolveACLToken_LeaderToken(t *testing.T) {
 	t.Parallel()
 	assert := assert.New(t)
@@ -107,3 +140,24 @@ func TestResolveACLToken_LeaderToken(t *testing.T) {
 		assert.True(token.IsManagement())
 	}
 }
+
+func TestResolveSecretToken(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+	s1, root := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	token, err := s1.ResolveSecretToken(root.SecretID)
+	assert.Nil(err)
+	if assert.NotNil(token) {
+		assert.Equal(root.AccessorID, token.AccessorID)
+	}
+
+	token, err = s1.ResolveSecretToken("")
+	assert.Nil(err)
+	assert.Equal(structs.AnonymousACLToken, token)
+
+	_, err = s1.ResolveSecretToken(uuid.Generate())
+	assert.Equal(structs.ErrTokenNotFound, err)
+}
@@ -0,0 +1,200 @@
+package nomad
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	cleanhttp "github.com/hashicorp/go-cleanhttp"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+)
+
+const (
+	// admissionOperationRegister, admissionOperationPlan and
+	// admissionOperationValidate are the operations reported to the
+	// admission webhooks.
+	admissionOperationRegister = "register"
+	admissionOperationPlan     = "plan"
+	admissionOperationValidate = "validate"
+
+	// admissionWebhookMaxResponseSize is the maximum size of the responses
+	// of the admission webhooks.
+	admissionWebhookMaxResponseSize = 10 * 1024 * 1024
+)
+
+// AdmissionWebhookRequest is the body of the requests posted to the
+// admission webhooks.
+type AdmissionWebhookRequest struct {
+	// Operation is the job operation being admitted: "register", "plan" or
+	// "validate".
+	Operation string
+
+	// Namespace is the namespace of the job.
+	Namespace string
+
+	// Job is the canonicalized job.
+	Job *structs.Job
+}
+
+// AdmissionWebhookResponse is the body of the responses of the admission
+// webhooks.
+type AdmissionWebhookResponse struct {
+	// Allowed is whether the job is admitted.
+	Allowed bool
+
+	// Reason is the reason the job is rejected.
+	Reason string
+
+	// Warnings are returned to the submitter of the job.
+	Warnings []string
+
+	// Job is the mutated job, only used with the mutating webhooks. The job
+	// isn't modified if it is nil.
+	Job *structs.Job
+}
+
+// admissionWebhook calls an external admission webhook for a job operation.
+// It implements both jobMutator and jobValidator, depending on its type.
+type admissionWebhook struct {
+	config    *config.AdmissionWebhookConfig
+	client    *http.Client
+	operation string
+}
+
+// setupAdmissionWebhooks validates the admission webhooks of the
+// configuration and creates the clients they are called with.
+func (s *Server) setupAdmissionWebhooks() error {
+	clients := make([]*http.Client, 0, len(s.config.AdmissionWebhooks))
+	for _, w := range s.config.AdmissionWebhooks {
+		w.Canonicalize()
+		if err := w.Validate(); err != nil {
+			return err
+		}
+
+		client := cleanhttp.DefaultPooledClient()
+		client.Timeout = w.Timeout
+		clients = append(clients, client)
+	}
+	s.admissionWebhookClients = clients
+	return nil
+}
+
+// admissionWebhooks returns the mutators and validators calling the
+// admission webhooks of the configuration for the given operation.
+func (s *Server) admissionWebhooks(operation string) ([]jobMutator, []jobValidator) {
+	var mutators []jobMutator
+	var validators []jobValidator
+	for i, conf := range s.config.AdmissionWebhooks {
+		w := &admissionWebhook{
+			config:    conf,
+			client:    s.admissionWebhookClients[i],
+			operation: operation,
+		}
+		if conf.Type == config.AdmissionWebhookTypeMutating {
+			mutators = append(mutators, w)
+		} else {
+			validators = append(validators, w)
+		}
+	}
+	return mutators, validators
+}
+
+func (w *admissionWebhook) Name() string {
+	return w.config.Name
+}
+
+// Mutate posts the job to the webhook and returns the job it responded with.
+func (w *admissionWebhook) Mutate(job *structs.Job) (*structs.Job, []error, error) {
+	resp, warnings, err := w.call(job)
+	if err != nil || resp == nil {
+		return job, warnings, err
+	}
+	if resp.Job == nil {
+		return job, warnings, nil
+	}
+
+	// The webhook may not move the job elsewhere
+	out := resp.Job
+	if out.ID != job.ID || out.Namespace != job.Namespace || out.Region != job.Region {
+		return nil, nil, fmt.Errorf("admission webhook %q changed the ID, namespace or region of the job", w.Name())
+	}
+	return out, warnings, nil
+}
+
+// Validate posts the job to the webhook, which accepts or rejects it.
+func (w *admissionWebhook) Validate(job *structs.Job) ([]error, error) {
+	_, warnings, err := w.call(job)
+	return warnings, err
+}
+
+// call posts the job to the webhook. The error returned is the rejection of
+// the job, and a nil response is returned if the webhook failed but its
+// failure policy is to ignore failures.
+func (w *admissionWebhook) call(job *structs.Job) (*AdmissionWebhookResponse, []error, error) {
+	defer metrics.MeasureSince([]string{"nomad", "admission_webhook", w.Name()}, time.Now())
+
+	resp, err := w.post(job)
+	if err != nil {
+		metrics.IncrCounter([]string{"nomad", "admission_webhook", w.Name(), "errors"}, 1)
+		if w.config.FailurePolicy == config.AdmissionFailurePolicyIgnore {
+			warning := fmt.Errorf("admission webhook %q ignored: %v", w.Name(), err)
+			return nil, []error{warning}, nil
+		}
+		return nil, nil, fmt.Errorf("admission webhook %q failed: %v", w.Name(), err)
+	}
+
+	var warnings []error
+	for _, warning := range resp.Warnings {
+		warnings = append(warnings, fmt.Errorf("%s: %s", w.Name(), warning))
+	}
+	if !resp.Allowed {
+		reason := resp.Reason
+		if reason == "" {
+			reason = "no reason given"
+		}
+		return nil, warnings, fmt.Errorf("job rejected by admission webhook %q: %s", w.Name(), reason)
+	}
+	return resp, warnings, nil
+}
+
+// post posts the job to the webhook and decodes its response.
+func (w *admissionWebhook) post(job *structs.Job) (*AdmissionWebhookResponse, error) {
+	body, err := json.Marshal(&AdmissionWebhookRequest{
+		Operation: w.operation,
+		Namespace: job.Namespace,
+		Job:       job,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", w.config.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("unexpected response code %d: %s", resp.StatusCode, bytes.TrimSpace(msg))
+	}
+
+	var out AdmissionWebhookResponse
+	dec := json.NewDecoder(io.LimitReader(resp.Body, admissionWebhookMaxResponseSize))
+	if err := dec.Decode(&out); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %v", err)
+	}
+	return &out, nil
+}
@@ -10,6 +10,8 @@ import (
 	"github.com/armon/go-metrics"
 	"github.com/hashicorp/go-memdb"
 	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/state"
 	"github.com/hashicorp/nomad/nomad/structs"
 )
@@ -28,9 +30,10 @@ func (a *Alloc) List(args *structs.AllocListRequest, reply *structs.AllocListRes
 	defer metrics.MeasureSince([]string{"nomad", "alloc", "list"}, time.Now())
 
 	// Check namespace read-job permissions
-	if aclObj, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+	aclObj, err := a.srv.ResolveToken(args.AuthToken)
+	if err != nil {
 		return err
-	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityReadJob) {
+	} else if !allowNsOp(aclObj, args.RequestNamespace(), acl.NamespaceCapabilityReadJob) {
 		return structs.ErrPermissionDenied
 	}
 
@@ -50,17 +53,31 @@ func (a *Alloc) List(args *structs.AllocListRequest, reply *structs.AllocListRes
 			if err != nil {
 				return err
 			}
+			iter = namespaceACLFilter(iter, aclObj, args.RequestNamespace(), acl.NamespaceCapabilityReadJob,
+				func(raw interface{}) string {
+					return raw.(*structs.Allocation).Namespace
+				})
+
+			paginator, err := newPaginator(iter, args.QueryOptions,
+				func(raw interface{}) string {
+					return raw.(*structs.Allocation).ID
+				},
+				func(raw interface{}) (interface{}, error) {
+					return raw.(*structs.Allocation).Stub(), nil
+				})
+			if err != nil {
+				return err
+			}
 
 			var allocs []*structs.AllocListStub
-			for {
-				raw := iter.Next()
-				if raw == nil {
-					break
-				}
-				alloc := raw.(*structs.Allocation)
-				allocs = append(allocs, alloc.Stub())
+			nextToken, err := paginator.Page(func(obj interface{}) {
+				allocs = append(allocs, obj.(*structs.AllocListStub))
+			})
+			if err != nil {
+				return err
 			}
 			reply.Allocations = allocs
+			reply.NextToken = nextToken
 
 			// Use the last index that affected the jobs table
 			index, err := state.Index("allocs")
@@ -236,3 +253,66 @@ func (a *Alloc) UpdateDesiredTransition(args *structs.AllocUpdateDesiredTransiti
 	reply.Index = index
 	return nil
 }
+
+// Stop is used to stop an allocation and migrate it to another node. The
+// allocation is marked for migration and an evaluation is created to
+// reschedule it.
+func (a *Alloc) Stop(args *structs.AllocStopRequest, reply *structs.AllocStopResponse) error {
+	if done, err := a.srv.forward("Alloc.Stop", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "alloc", "stop"}, time.Now())
+
+	// Verify the arguments.
+	if args.AllocID == "" {
+		return fmt.Errorf("must provide an alloc id")
+	}
+
+	alloc, err := a.srv.State().AllocByID(nil, args.AllocID)
+	if err != nil {
+		return err
+	}
+	if alloc == nil {
+		return structs.NewErrUnknownAllocation(args.AllocID)
+	}
+
+	// Check alloc-lifecycle permissions in the namespace of the allocation
+	if aclObj, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNsOp(alloc.Namespace, acl.NamespaceCapabilityAllocLifecycle) {
+		return structs.ErrPermissionDenied
+	}
+
+	if alloc.TerminalStatus() {
+		return fmt.Errorf("allocation %q is already terminal", args.AllocID)
+	}
+
+	eval := &structs.Evaluation{
+		ID:          uuid.Generate(),
+		Namespace:   alloc.Namespace,
+		Priority:    alloc.Job.Priority,
+		Type:        alloc.Job.Type,
+		TriggeredBy: structs.EvalTriggerAllocStop,
+		JobID:       alloc.Job.ID,
+		Status:      structs.EvalStatusPending,
+	}
+	transitionReq := &structs.AllocUpdateDesiredTransitionRequest{
+		Allocs: map[string]*structs.DesiredTransition{
+			alloc.ID: {Migrate: helper.BoolToPtr(true)},
+		},
+		Evals:        []*structs.Evaluation{eval},
+		WriteRequest: args.WriteRequest,
+	}
+
+	// Commit this update via Raft
+	_, index, err := a.srv.raftApply(structs.AllocUpdateDesiredTransitionRequestType, transitionReq)
+	if err != nil {
+		a.logger.Error("AllocUpdateDesiredTransitionRequest failed", "error", err, "method", "stop")
+		return err
+	}
+
+	// Setup the response
+	reply.EvalID = eval.ID
+	reply.Index = index
+	return nil
+}
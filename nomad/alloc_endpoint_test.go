@@ -138,6 +138,47 @@ func TestAllocEndpoint_List_ACL(t *testing.T) {
 	assert.Equal(stubAllocs, resp.Allocations, "Returned alloc list not equal")
 }
 
+func TestAllocEndpoint_List_AllNamespaces(t *testing.T) {
+	t.Parallel()
+	s1, root := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	require := require.New(t)
+
+	// Create allocs in two namespaces
+	state := s1.fsm.State()
+	require.NoError(state.UpsertNamespaces(998, []*structs.Namespace{{Name: "prod"}}))
+	alloc1 := mock.Alloc()
+	alloc2 := mock.Alloc()
+	alloc2.Namespace = "prod"
+	alloc2.Job.Namespace = "prod"
+	require.NoError(state.UpsertJobSummary(999, mock.JobSummary(alloc1.JobID)))
+	require.NoError(state.UpsertAllocs(1000, []*structs.Allocation{alloc1, alloc2}))
+
+	get := &structs.AllocListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: structs.AllNamespacesSentinel,
+			AuthToken: root.SecretID,
+		},
+	}
+
+	// The management token lists the allocs of all the namespaces
+	var resp structs.AllocListResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Alloc.List", get, &resp))
+	require.Len(resp.Allocations, 2)
+
+	// The allocs of the namespaces the token can't read are filtered
+	token := mock.CreatePolicyAndToken(t, state, 1001, "test-prod",
+		mock.NamespacePolicy("prod", "", []string{acl.NamespaceCapabilityReadJob}))
+	get.AuthToken = token.SecretID
+	resp = structs.AllocListResponse{}
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Alloc.List", get, &resp))
+	require.Len(resp.Allocations, 1)
+	require.Equal(alloc2.ID, resp.Allocations[0].ID)
+}
+
 func TestAllocEndpoint_List_Blocking(t *testing.T) {
 	t.Parallel()
 	s1 := TestServer(t, nil)
@@ -567,3 +608,70 @@ func TestAllocEndpoint_UpdateDesiredTransition(t *testing.T) {
 	require.True(*out1.DesiredTransition.Migrate)
 	require.True(*out2.DesiredTransition.Migrate)
 }
+
+func TestAllocEndpoint_Stop(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1, root := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	// Create the allocation
+	alloc := mock.Alloc()
+	require.Nil(state.UpsertJobSummary(999, mock.JobSummary(alloc.JobID)))
+	require.Nil(state.UpsertAllocs(1000, []*structs.Allocation{alloc}))
+
+	// Create a token without the alloc-lifecycle capability
+	policyBad := mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityReadJob})
+	tokenBad := mock.CreatePolicyAndToken(t, state, 1001, "invalid", policyBad)
+
+	policyGood := mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityAllocLifecycle})
+	tokenGood := mock.CreatePolicyAndToken(t, state, 1003, "valid", policyGood)
+
+	req := &structs.AllocStopRequest{
+		AllocID: alloc.ID,
+		WriteRequest: structs.WriteRequest{
+			Region: "global",
+		},
+	}
+
+	// Try without a token
+	var resp structs.AllocStopResponse
+	err := msgpackrpc.CallWithCodec(codec, "Alloc.Stop", req, &resp)
+	require.NotNil(err)
+	require.True(structs.IsErrPermissionDenied(err))
+
+	// Try with an invalid token
+	req.AuthToken = tokenBad.SecretID
+	err = msgpackrpc.CallWithCodec(codec, "Alloc.Stop", req, &resp)
+	require.NotNil(err)
+	require.True(structs.IsErrPermissionDenied(err))
+
+	// Try with a valid token
+	req.AuthToken = tokenGood.SecretID
+	require.Nil(msgpackrpc.CallWithCodec(codec, "Alloc.Stop", req, &resp))
+	require.NotZero(resp.Index)
+	require.NotEmpty(resp.EvalID)
+
+	// The allocation is marked for migration
+	out, err := state.AllocByID(nil, alloc.ID)
+	require.Nil(err)
+	require.True(out.DesiredTransition.ShouldMigrate())
+
+	// An evaluation is created to reschedule it
+	eval, err := state.EvalByID(nil, resp.EvalID)
+	require.Nil(err)
+	require.NotNil(eval)
+	require.Equal(structs.EvalTriggerAllocStop, eval.TriggeredBy)
+	require.Equal(alloc.JobID, eval.JobID)
+
+	// Unknown allocations are rejected
+	req.AllocID = uuid.Generate()
+	req.AuthToken = root.SecretID
+	err = msgpackrpc.CallWithCodec(codec, "Alloc.Stop", req, &resp)
+	require.NotNil(err)
+	require.True(structs.IsErrUnknownAllocation(err))
+}
@@ -32,7 +32,7 @@ func (d *AutopilotDelegate) AutopilotConfig() *autopilot.Config {
 	}
 
 	conf := &autopilot.Config{
-		CleanupDeadServers:      c.CleanupDeadServers,
+		CleanupDeadServers:      c.CleanupDeadServers && d.minQuorumReached(c.MinQuorum),
 		LastContactThreshold:    c.LastContactThreshold,
 		MaxTrailingLogs:         c.MaxTrailingLogs,
 		ServerStabilizationTime: c.ServerStabilizationTime,
@@ -51,6 +51,25 @@ func (d *AutopilotDelegate) AutopilotConfig() *autopilot.Config {
 	return conf
 }
 
+// minQuorumReached returns whether the region has at least minQuorum alive
+// servers, dead servers not being removed below it.
+func (d *AutopilotDelegate) minQuorumReached(minQuorum uint) bool {
+	if minQuorum == 0 {
+		return true
+	}
+
+	var alive uint
+	for _, m := range d.server.serf.Members() {
+		if m.Status != serf.StatusAlive {
+			continue
+		}
+		if server, _ := d.IsServer(m); server != nil {
+			alive++
+		}
+	}
+	return alive >= minQuorum
+}
+
 func (d *AutopilotDelegate) FetchStats(ctx context.Context, servers []serf.Member) map[string]*autopilot.ServerStats {
 	return d.server.statsFetcher.Fetch(ctx, servers)
 }
@@ -89,7 +108,75 @@ func (d *AutopilotDelegate) PromoteNonVoters(conf *autopilot.Config, health auto
 		return nil, fmt.Errorf("failed to get raft configuration: %v", err)
 	}
 
-	return autopilot.PromoteStableServers(conf, health, future.Configuration().Servers), nil
+	return promoteNonVoters(conf, health, future.Configuration().Servers, d.autopilotServers()), nil
+}
+
+// autopilotServer is the autopilot related configuration of a server,
+// advertised in its Serf tags.
+type autopilotServer struct {
+	nonVoter bool
+	zone     string
+}
+
+// autopilotServers returns the autopilot configuration of the servers of the
+// region, by ID.
+func (d *AutopilotDelegate) autopilotServers() map[raft.ServerID]autopilotServer {
+	servers := make(map[raft.ServerID]autopilotServer)
+	for _, m := range d.server.serf.Members() {
+		ok, parts := isNomadServer(m)
+		if !ok || parts.Region != d.server.Region() {
+			continue
+		}
+		servers[raft.ServerID(parts.ID)] = autopilotServer{
+			nonVoter: parts.NonVoter,
+			zone:     m.Tags[AutopilotRZTag],
+		}
+	}
+	return servers
+}
+
+// promoteNonVoters returns the stable non-voters to promote. Servers configured
+// as non-voters are never promoted. With redundancy zones, a single server is
+// promoted in each zone lacking a healthy voter, the other servers of the zone
+// staying non-voters on standby.
+func promoteNonVoters(conf *autopilot.Config, health autopilot.OperatorHealthReply,
+	servers []raft.Server, members map[raft.ServerID]autopilotServer) []raft.Server {
+
+	var candidates []raft.Server
+	for _, server := range servers {
+		if !members[server.ID].nonVoter {
+			candidates = append(candidates, server)
+		}
+	}
+
+	promotions := autopilot.PromoteStableServers(conf, health, candidates)
+	if conf.RedundancyZoneTag == "" {
+		return promotions
+	}
+
+	covered := make(map[string]bool)
+	for _, server := range servers {
+		zone := members[server.ID].zone
+		if zone == "" || !autopilot.IsPotentialVoter(server.Suffrage) {
+			continue
+		}
+		if h := health.ServerHealth(string(server.ID)); h != nil && h.Healthy {
+			covered[zone] = true
+		}
+	}
+
+	var zoned []raft.Server
+	for _, server := range promotions {
+		zone := members[server.ID].zone
+		if zone != "" {
+			if covered[zone] {
+				continue
+			}
+			covered[zone] = true
+		}
+		zoned = append(zoned, server)
+	}
+	return zoned
 }
 
 func (d *AutopilotDelegate) Raft() *raft.Raft {
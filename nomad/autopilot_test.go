@@ -11,6 +11,7 @@ import (
 	"github.com/hashicorp/nomad/testutil"
 	"github.com/hashicorp/raft"
 	"github.com/hashicorp/serf/serf"
+	"github.com/stretchr/testify/require"
 )
 
 // wantPeers determines whether the server has the given
@@ -169,6 +170,73 @@ func TestAutopilot_CleanupDeadServerPeriodic(t *testing.T) {
 	})
 }
 
+func TestAutopilot_CleanupDeadServer_MinQuorum(t *testing.T) {
+	t.Parallel()
+	s1 := TestServer(t, func(c *Config) {
+		c.AutopilotConfig.MinQuorum = 4
+	})
+	defer s1.Shutdown()
+
+	conf := func(c *Config) {
+		c.DevDisableBootstrap = true
+	}
+
+	s2 := TestServer(t, conf)
+	defer s2.Shutdown()
+
+	s3 := TestServer(t, conf)
+	defer s3.Shutdown()
+
+	s4 := TestServer(t, conf)
+	defer s4.Shutdown()
+
+	// Join the servers to s1, and wait until they are all promoted to
+	// voters.
+	servers := []*Server{s1, s2, s3, s4}
+	TestJoin(t, s1, servers[1:]...)
+	retry.Run(t, func(r *retry.R) {
+		r.Check(wantRaft(servers))
+		for _, s := range servers {
+			r.Check(wantPeers(s, 4))
+		}
+	})
+
+	// Kill a non-leader server, wait for it to be detected as failed
+	s4.Shutdown()
+	retry.Run(t, func(r *retry.R) {
+		for _, m := range s1.Members() {
+			if m.Name == s4.config.NodeName && m.Status != serf.StatusFailed {
+				r.Fatalf("server not failed: %v", m.Status)
+			}
+		}
+	})
+
+	// It isn't removed, as only 3 servers remain alive
+	time.Sleep(1 * time.Second)
+	if err := wantPeers(s1, 4); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	// Lowering the minimum quorum removes it
+	_, apConf, err := s1.fsm.State().AutopilotConfig()
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	newConf := *apConf
+	newConf.MinQuorum = 3
+	if err := s1.fsm.State().AutopilotSetConfig(1000, &newConf); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	servers = []*Server{s1, s2, s3}
+	retry.Run(t, func(r *retry.R) {
+		r.Check(wantRaft(servers))
+		for _, s := range servers {
+			r.Check(wantPeers(s, 3))
+		}
+	})
+}
+
 func TestAutopilot_RollingUpdate(t *testing.T) {
 	t.Parallel()
 	s1 := TestServer(t, func(c *Config) {
@@ -351,3 +419,53 @@ func TestAutopilot_PromoteNonVoter(t *testing.T) {
 		}
 	})
 }
+
+func TestAutopilot_PromoteNonVoters_Policy(t *testing.T) {
+	t.Parallel()
+
+	stable := time.Now().Add(-time.Minute)
+	health := autopilot.OperatorHealthReply{}
+	for _, id := range []string{"a1", "a2", "b1", "b2", "c1", "reader", "free"} {
+		health.Servers = append(health.Servers, autopilot.ServerHealth{
+			ID:          id,
+			Healthy:     id != "b1",
+			StableSince: stable,
+		})
+	}
+
+	servers := []raft.Server{
+		{ID: "a1", Suffrage: raft.Voter},
+		{ID: "a2", Suffrage: raft.Nonvoter},
+		{ID: "b1", Suffrage: raft.Voter},
+		{ID: "b2", Suffrage: raft.Nonvoter},
+		{ID: "c1", Suffrage: raft.Nonvoter},
+		{ID: "reader", Suffrage: raft.Nonvoter},
+		{ID: "free", Suffrage: raft.Nonvoter},
+	}
+	members := map[raft.ServerID]autopilotServer{
+		"a1":     {zone: "a"},
+		"a2":     {zone: "a"},
+		"b1":     {zone: "b"},
+		"b2":     {zone: "b"},
+		"c1":     {zone: "c"},
+		"reader": {zone: "c", nonVoter: true},
+	}
+
+	ids := func(servers []raft.Server) []raft.ServerID {
+		var ids []raft.ServerID
+		for _, s := range servers {
+			ids = append(ids, s.ID)
+		}
+		return ids
+	}
+
+	// Without zones every stable server but the non-voters is promoted
+	conf := &autopilot.Config{ServerStabilizationTime: 10 * time.Second}
+	promotions := promoteNonVoters(conf, health, servers, members)
+	require.Equal(t, []raft.ServerID{"a2", "b2", "c1", "free"}, ids(promotions))
+
+	// With zones only the zones without a healthy voter get one
+	conf.RedundancyZoneTag = AutopilotRZTag
+	promotions = promoteNonVoters(conf, health, servers, members)
+	require.Equal(t, []raft.ServerID{"b2", "c1", "free"}, ids(promotions))
+}
@@ -23,6 +23,16 @@ const (
 
 	// pruneThreshold is the threshold after which objects will be pruned.
 	pruneThreshold = 15 * time.Minute
+
+	// maxBlockedDuplicates is the maximum number of duplicate evaluations
+	// retained while waiting to be cancelled. Duplicates beyond it are
+	// dropped rather than retained, and remain blocked in the state until
+	// the blocked evaluations are restored by the next leader.
+	maxBlockedDuplicates = 10000
+
+	// maxDuplicatesPerCancel is the maximum number of duplicate evaluations
+	// cancelled in a single Raft log entry.
+	maxDuplicatesPerCancel = 1000
 )
 
 // BlockedEvals is used to track evaluations that shouldn't be queued until a
@@ -65,6 +75,13 @@ type BlockedEvals struct {
 	// blocked eval is needed per job.
 	duplicates []*structs.Evaluation
 
+	// duplicateIDs is the set of the IDs of the duplicates, so that an
+	// evaluation is only retained once.
+	duplicateIDs map[string]struct{}
+
+	// maxDuplicates is the maximum number of duplicates retained.
+	maxDuplicates int
+
 	// duplicateCh is used to signal that a duplicate eval was added to the
 	// duplicate set. It can be used to unblock waiting callers looking for
 	// duplicates.
@@ -103,6 +120,10 @@ type BlockedStats struct {
 	// TotalQuotaLimit is the total number of blocked evaluations that are due
 	// to the quota limit being reached.
 	TotalQuotaLimit int
+
+	// TotalDuplicates is the number of duplicate evaluations waiting to be
+	// cancelled.
+	TotalDuplicates int
 }
 
 // NewBlockedEvals creates a new blocked eval tracker that will enqueue
@@ -116,6 +137,8 @@ func NewBlockedEvals(evalBroker *EvalBroker, logger log.Logger) *BlockedEvals {
 		jobs:             make(map[structs.NamespacedID]string),
 		unblockIndexes:   make(map[string]uint64),
 		capacityChangeCh: make(chan *capacityUpdate, unblockBuffer),
+		duplicateIDs:     make(map[string]struct{}),
+		maxDuplicates:    maxBlockedDuplicates,
 		duplicateCh:      make(chan struct{}, 1),
 		stopCh:           make(chan struct{}),
 		stats:            new(BlockedStats),
@@ -247,45 +270,60 @@ func (b *BlockedEvals) processBlockJobDuplicate(eval *structs.Evaluation) (newCa
 		return
 	}
 
-	var dup *structs.Evaluation
 	existingW, ok := b.captured[existingID]
-	if ok {
-		if latestEvalIndex(existingW.eval) <= latestEvalIndex(eval) {
-			delete(b.captured, existingID)
-			b.stats.TotalBlocked--
-			dup = existingW.eval
-		} else {
-			dup = eval
-			newCancelled = true
-		}
-	} else {
+	if !ok {
 		existingW, ok = b.escaped[existingID]
-		if !ok {
-			// This is a programming error
-			b.logger.Error("existing blocked evaluation is neither tracked as captured or escaped", "existing_id", existingID)
-			delete(b.jobs, structs.NewNamespacedID(eval.JobID, eval.Namespace))
-			return
-		}
+	}
+	if !ok {
+		// This is a programming error
+		b.logger.Error("existing blocked evaluation is neither tracked as captured or escaped", "existing_id", existingID)
+		delete(b.jobs, structs.NewNamespacedID(eval.JobID, eval.Namespace))
+		return
+	}
 
-		if latestEvalIndex(existingW.eval) <= latestEvalIndex(eval) {
-			delete(b.escaped, existingID)
-			b.stats.TotalEscaped--
-			dup = existingW.eval
-		} else {
-			dup = eval
-			newCancelled = true
-		}
+	// The evaluation is being blocked again, so it replaces its own tracking
+	// rather than being a duplicate of itself
+	if existingID == eval.ID {
+		b.untrackLocked(existingID)
+		return
+	}
+
+	var dup *structs.Evaluation
+	if latestEvalIndex(existingW.eval) <= latestEvalIndex(eval) {
+		b.untrackLocked(existingID)
+		dup = existingW.eval
+	} else {
+		dup = eval
+		newCancelled = true
 	}
 
+	b.addDuplicateLocked(dup)
+	return
+}
+
+// addDuplicateLocked retains the duplicate evaluation until it is cancelled,
+// unless it is already retained or the maximum number of duplicates is
+// reached. This should be called with the lock held.
+func (b *BlockedEvals) addDuplicateLocked(dup *structs.Evaluation) {
+	if _, ok := b.duplicateIDs[dup.ID]; ok {
+		return
+	}
+	if len(b.duplicates) >= b.maxDuplicates {
+		metrics.IncrCounter([]string{"nomad", "blocked_evals", "duplicate_dropped"}, 1)
+		b.logger.Warn("dropping duplicate blocked evaluation, too many duplicates waiting to be cancelled",
+			"eval_id", dup.ID, "job_id", dup.JobID, "max", b.maxDuplicates)
+		return
+	}
+
+	metrics.IncrCounter([]string{"nomad", "blocked_evals", "duplicate"}, 1)
 	b.duplicates = append(b.duplicates, dup)
+	b.duplicateIDs[dup.ID] = struct{}{}
 
 	// Unblock any waiter.
 	select {
 	case b.duplicateCh <- struct{}{}:
 	default:
 	}
-
-	return
 }
 
 // latestEvalIndex returns the max of the evaluations create and snapshot index
@@ -372,24 +410,26 @@ func (b *BlockedEvals) Untrack(jobID, namespace string) {
 		return
 	}
 
-	// Attempt to delete the evaluation
-	if w, ok := b.captured[evalID]; ok {
-		delete(b.jobs, nsID)
-		delete(b.captured, evalID)
-		b.stats.TotalBlocked--
-		if w.eval.QuotaLimitReached != "" {
-			b.stats.TotalQuotaLimit--
-		}
-	}
+	b.untrackLocked(evalID)
+}
 
-	if w, ok := b.escaped[evalID]; ok {
-		delete(b.jobs, nsID)
+// untrackLocked stops tracking the blocked evaluation, updating the stats.
+// This should be called with the lock held.
+func (b *BlockedEvals) untrackLocked(evalID string) {
+	w, ok := b.captured[evalID]
+	if ok {
+		delete(b.captured, evalID)
+	} else if w, ok = b.escaped[evalID]; ok {
 		delete(b.escaped, evalID)
 		b.stats.TotalEscaped--
-		b.stats.TotalBlocked--
-		if w.eval.QuotaLimitReached != "" {
-			b.stats.TotalQuotaLimit--
-		}
+	} else {
+		return
+	}
+
+	delete(b.jobs, structs.NewNamespacedID(w.eval.JobID, w.eval.Namespace))
+	b.stats.TotalBlocked--
+	if w.eval.QuotaLimitReached != "" {
+		b.stats.TotalQuotaLimit--
 	}
 }
 
@@ -603,6 +643,7 @@ SCAN:
 	if len(b.duplicates) != 0 {
 		dups := b.duplicates
 		b.duplicates = nil
+		b.duplicateIDs = make(map[string]struct{})
 		b.l.Unlock()
 		return dups
 	}
@@ -640,6 +681,7 @@ func (b *BlockedEvals) Flush() {
 	b.unblockIndexes = make(map[string]uint64)
 	b.timetable = nil
 	b.duplicates = nil
+	b.duplicateIDs = make(map[string]struct{})
 	b.capacityChangeCh = make(chan *capacityUpdate, unblockBuffer)
 	b.stopCh = make(chan struct{})
 	b.duplicateCh = make(chan struct{}, 1)
@@ -657,6 +699,7 @@ func (b *BlockedEvals) Stats() *BlockedStats {
 	stats.TotalEscaped = b.stats.TotalEscaped
 	stats.TotalBlocked = b.stats.TotalBlocked
 	stats.TotalQuotaLimit = b.stats.TotalQuotaLimit
+	stats.TotalDuplicates = len(b.duplicates)
 	return stats
 }
 
@@ -669,6 +712,7 @@ func (b *BlockedEvals) EmitStats(period time.Duration, stopCh chan struct{}) {
 			metrics.SetGauge([]string{"nomad", "blocked_evals", "total_quota_limit"}, float32(stats.TotalQuotaLimit))
 			metrics.SetGauge([]string{"nomad", "blocked_evals", "total_blocked"}, float32(stats.TotalBlocked))
 			metrics.SetGauge([]string{"nomad", "blocked_evals", "total_escaped"}, float32(stats.TotalEscaped))
+			metrics.SetGauge([]string{"nomad", "blocked_evals", "total_duplicates"}, float32(stats.TotalDuplicates))
 		case <-stopCh:
 			return
 		}
@@ -157,6 +157,68 @@ func TestBlockedEvals_GetDuplicates(t *testing.T) {
 	}
 }
 
+func TestBlockedEvals_GetDuplicates_Escaped(t *testing.T) {
+	t.Parallel()
+	blocked, _ := testBlockedEvals(t)
+
+	// Replacing an escaped blocked eval keeps the stats consistent
+	e := mock.Eval()
+	e.EscapedComputedClass = true
+	e.CreateIndex = 100
+	e2 := mock.Eval()
+	e2.JobID = e.JobID
+	e2.EscapedComputedClass = true
+	e2.CreateIndex = 101
+	blocked.Block(e)
+	blocked.Block(e2)
+
+	bStats := blocked.Stats()
+	if bStats.TotalBlocked != 1 || bStats.TotalEscaped != 1 || bStats.TotalDuplicates != 1 {
+		t.Fatalf("bad: %#v", bStats)
+	}
+}
+
+func TestBlockedEvals_Block_SameEval(t *testing.T) {
+	t.Parallel()
+	blocked, _ := testBlockedEvals(t)
+
+	// Blocking the same eval again is not a duplicate of itself
+	e := mock.Eval()
+	e.CreateIndex = 100
+	blocked.Block(e)
+	blocked.Block(e)
+
+	bStats := blocked.Stats()
+	if bStats.TotalBlocked != 1 || bStats.TotalDuplicates != 0 {
+		t.Fatalf("bad: %#v", bStats)
+	}
+}
+
+func TestBlockedEvals_GetDuplicates_Max(t *testing.T) {
+	t.Parallel()
+	blocked, _ := testBlockedEvals(t)
+	blocked.maxDuplicates = 2
+
+	// Block newer evals of the same job, each making the previous one a
+	// duplicate
+	e := mock.Eval()
+	for i := 0; i < 4; i++ {
+		eval := mock.Eval()
+		eval.JobID = e.JobID
+		eval.CreateIndex = uint64(100 + i)
+		blocked.Block(eval)
+	}
+
+	// Only the maximum number of duplicates is retained
+	bStats := blocked.Stats()
+	if bStats.TotalBlocked != 1 || bStats.TotalDuplicates != 2 {
+		t.Fatalf("bad: %#v", bStats)
+	}
+	if out := blocked.GetDuplicates(0); len(out) != 2 {
+		t.Fatalf("bad: %#v", out)
+	}
+}
+
 func TestBlockedEvals_UnblockEscaped(t *testing.T) {
 	t.Parallel()
 	blocked, broker := testBlockedEvals(t)
@@ -0,0 +1,225 @@
+package nomad
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/client"
+	"github.com/hashicorp/nomad/client/config"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/command/agent/monitor"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+	"github.com/ugorji/go/codec"
+)
+
+// testLogSource is a LogSource whose logs are emitted by the test
+type testLogSource struct {
+	mu       sync.Mutex
+	handlers map[monitor.LogHandler]struct{}
+}
+
+func (s *testLogSource) RegisterHandler(h monitor.LogHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[h] = struct{}{}
+}
+
+func (s *testLogSource) DeregisterHandler(h monitor.LogHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.handlers, h)
+}
+
+func (s *testLogSource) log(line string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for h := range s.handlers {
+		h.HandleLog(line)
+	}
+}
+
+// monitorStream starts the Agent.Monitor handler of the server with the
+// request and returns the channels of the decoded messages.
+func monitorStream(t *testing.T, s *Server, req *cstructs.MonitorRequest) (<-chan *cstructs.StreamErrWrapper, <-chan error, func()) {
+	handler, err := s.StreamingRpcHandler("Agent.Monitor")
+	require.Nil(t, err)
+
+	// Create a pipe
+	p1, p2 := net.Pipe()
+
+	errCh := make(chan error)
+	streamMsg := make(chan *cstructs.StreamErrWrapper)
+
+	// Start the handler
+	go handler(p2)
+
+	// Start the decoder
+	go func() {
+		decoder := codec.NewDecoder(p1, structs.MsgpackHandle)
+		for {
+			var msg cstructs.StreamErrWrapper
+			if err := decoder.Decode(&msg); err != nil {
+				if err == io.EOF || strings.Contains(err.Error(), "closed") {
+					return
+				}
+				errCh <- fmt.Errorf("error decoding: %v", err)
+			}
+
+			streamMsg <- &msg
+		}
+	}()
+
+	// Send the request
+	encoder := codec.NewEncoder(p1, structs.MsgpackHandle)
+	require.Nil(t, encoder.Encode(req))
+
+	return streamMsg, errCh, func() {
+		p1.Close()
+		p2.Close()
+	}
+}
+
+func TestAgentMonitor_NoNode(t *testing.T) {
+	t.Parallel()
+
+	// Start a server
+	s := TestServer(t, nil)
+	defer s.Shutdown()
+	testutil.WaitForLeader(t, s.RPC)
+
+	// Make the request with bad node id
+	req := &cstructs.MonitorRequest{
+		NodeID:       uuid.Generate(),
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	streamMsg, errCh, cleanup := monitorStream(t, s, req)
+	defer cleanup()
+
+	select {
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout")
+	case err := <-errCh:
+		t.Fatal(err)
+	case msg := <-streamMsg:
+		require.NotNil(t, msg.Error)
+		require.Contains(t, msg.Error.Error(), "Unknown node")
+	}
+}
+
+func TestAgentMonitor_Local(t *testing.T) {
+	t.Parallel()
+
+	// Start a server and client
+	s := TestServer(t, nil)
+	defer s.Shutdown()
+	testutil.WaitForLeader(t, s.RPC)
+
+	source := &testLogSource{handlers: make(map[monitor.LogHandler]struct{})}
+	c, cleanup := client.TestClient(t, func(c *config.Config) {
+		c.Servers = []string{s.config.RPCAddr.String()}
+		c.LogSource = source
+	})
+	defer cleanup()
+
+	// Wait for the client to connect
+	testutil.WaitForResult(func() (bool, error) {
+		nodes := s.connectedNodes()
+		return len(nodes) == 1, nil
+	}, func(err error) {
+		t.Fatalf("should have a clients")
+	})
+
+	req := &cstructs.MonitorRequest{
+		LogLevel:     "debug",
+		NodeID:       c.NodeID(),
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	streamMsg, errCh, stop := monitorStream(t, s, req)
+	defer stop()
+
+	// Log until the monitor of the client receives the line
+	ticker := time.NewTicker(50 * time.Millisecond)
+	defer ticker.Stop()
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case <-timeout:
+			t.Fatal("timeout")
+		case err := <-errCh:
+			t.Fatal(err)
+		case <-ticker.C:
+			source.log("[DEBUG] client: monitored")
+		case msg := <-streamMsg:
+			require.Nil(t, msg.Error)
+			require.Equal(t, "[DEBUG] client: monitored\n", string(msg.Payload))
+			return
+		}
+	}
+}
+
+func TestAgentMonitor_Remote_Region(t *testing.T) {
+	t.Parallel()
+
+	// Start two servers in different regions and a client
+	s1 := TestServer(t, nil)
+	defer s1.Shutdown()
+	s2 := TestServer(t, func(c *Config) {
+		c.Region = "two"
+	})
+	defer s2.Shutdown()
+	TestJoin(t, s1, s2)
+	testutil.WaitForLeader(t, s1.RPC)
+	testutil.WaitForLeader(t, s2.RPC)
+
+	source := &testLogSource{handlers: make(map[monitor.LogHandler]struct{})}
+	c, cleanup := client.TestClient(t, func(c *config.Config) {
+		c.Servers = []string{s2.config.RPCAddr.String()}
+		c.Region = "two"
+		c.LogSource = source
+	})
+	defer cleanup()
+
+	// Wait for the client to connect
+	testutil.WaitForResult(func() (bool, error) {
+		nodes := s2.connectedNodes()
+		return len(nodes) == 1, nil
+	}, func(err error) {
+		t.Fatalf("should have a clients")
+	})
+
+	// Make the request to the server of the other region
+	req := &cstructs.MonitorRequest{
+		LogLevel:     "debug",
+		NodeID:       c.NodeID(),
+		QueryOptions: structs.QueryOptions{Region: "two"},
+	}
+	streamMsg, errCh, stop := monitorStream(t, s1, req)
+	defer stop()
+
+	// Log until the monitor of the client receives the line
+	ticker := time.NewTicker(50 * time.Millisecond)
+	defer ticker.Stop()
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case <-timeout:
+			t.Fatal("timeout")
+		case err := <-errCh:
+			t.Fatal(err)
+		case <-ticker.C:
+			source.log("[DEBUG] client: monitored")
+		case msg := <-streamMsg:
+			require.Nil(t, msg.Error)
+			require.Equal(t, "[DEBUG] client: monitored\n", string(msg.Payload))
+			return
+		}
+	}
+}
@@ -2,7 +2,11 @@ package nomad
 
 import (
 	"fmt"
+	"io"
+	"net"
+	"strings"
 	"testing"
+	"time"
 
 	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
 	"github.com/hashicorp/nomad/acl"
@@ -14,6 +18,7 @@ import (
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/testutil"
 	"github.com/stretchr/testify/require"
+	"github.com/ugorji/go/codec"
 )
 
 func TestClientAllocations_GarbageCollectAll_Local(t *testing.T) {
@@ -401,6 +406,96 @@ func TestClientAllocations_GarbageCollect_Local_ACL(t *testing.T) {
 	}
 }
 
+func TestClientAllocations_Restart_Local_ACL(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	// Start a server
+	s, root := TestACLServer(t, nil)
+	defer s.Shutdown()
+	codec := rpcClient(t, s)
+	testutil.WaitForLeader(t, s.RPC)
+
+	// Create a bad token
+	policyBad := mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityReadJob})
+	tokenBad := mock.CreatePolicyAndToken(t, s.State(), 1005, "invalid", policyBad)
+
+	policyGood := mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityAllocLifecycle})
+	tokenGood := mock.CreatePolicyAndToken(t, s.State(), 1009, "valid2", policyGood)
+
+	// A token that may restart and signal in another namespace only
+	policyOther := mock.NamespacePolicy("other", "", []string{acl.NamespaceCapabilityAllocLifecycle})
+	tokenOther := mock.CreatePolicyAndToken(t, s.State(), 1010, "other", policyOther)
+
+	// Permissions are checked in the namespace of the allocation, whose node
+	// is unknown
+	alloc := mock.Alloc()
+	require.Nil(s.State().UpsertAllocs(1011, []*structs.Allocation{alloc}))
+
+	cases := []struct {
+		Name          string
+		Token         string
+		Namespace     string
+		ExpectedError string
+	}{
+		{
+			Name:          "bad token",
+			Token:         tokenBad.SecretID,
+			Namespace:     structs.DefaultNamespace,
+			ExpectedError: structs.ErrPermissionDenied.Error(),
+		},
+		{
+			Name:          "other namespace token",
+			Token:         tokenOther.SecretID,
+			Namespace:     "other",
+			ExpectedError: structs.ErrPermissionDenied.Error(),
+		},
+		{
+			Name:          "good token",
+			Token:         tokenGood.SecretID,
+			Namespace:     structs.DefaultNamespace,
+			ExpectedError: "Unknown node",
+		},
+		{
+			Name:          "root token",
+			Token:         root.SecretID,
+			Namespace:     structs.DefaultNamespace,
+			ExpectedError: "Unknown node",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			restartReq := &cstructs.AllocRestartRequest{
+				AllocID: alloc.ID,
+				QueryOptions: structs.QueryOptions{
+					AuthToken: c.Token,
+					Region:    "global",
+					Namespace: c.Namespace,
+				},
+			}
+			var resp structs.GenericResponse
+			err := msgpackrpc.CallWithCodec(codec, "ClientAllocations.Restart", restartReq, &resp)
+			require.NotNil(err)
+			require.Contains(err.Error(), c.ExpectedError)
+
+			signalReq := &cstructs.AllocSignalRequest{
+				AllocID: alloc.ID,
+				Signal:  "SIGHUP",
+				QueryOptions: structs.QueryOptions{
+					AuthToken: c.Token,
+					Region:    "global",
+					Namespace: c.Namespace,
+				},
+			}
+			var resp2 structs.GenericResponse
+			err = msgpackrpc.CallWithCodec(codec, "ClientAllocations.Signal", signalReq, &resp2)
+			require.NotNil(err)
+			require.Contains(err.Error(), c.ExpectedError)
+		})
+	}
+}
+
 func TestClientAllocations_GarbageCollect_Remote(t *testing.T) {
 	t.Parallel()
 	require := require.New(t)
@@ -760,3 +855,135 @@ func TestClientAllocations_Stats_Remote(t *testing.T) {
 	require.Nil(err)
 	require.NotNil(resp.Stats)
 }
+
+func TestClientAllocations_Exec_ACL(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	// Start a server
+	s, root := TestACLServer(t, nil)
+	defer s.Shutdown()
+	testutil.WaitForLeader(t, s.RPC)
+
+	// Create a bad token
+	policyBad := mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityReadFS})
+	tokenBad := mock.CreatePolicyAndToken(t, s.State(), 1005, "invalid", policyBad)
+
+	policyGood := mock.NamespacePolicy(structs.DefaultNamespace, "",
+		[]string{acl.NamespaceCapabilityAllocExec})
+	tokenGood := mock.CreatePolicyAndToken(t, s.State(), 1009, "valid2", policyGood)
+
+	// A token that may exec in another namespace only
+	policyOther := mock.NamespacePolicy("other", "",
+		[]string{acl.NamespaceCapabilityAllocExec})
+	tokenOther := mock.CreatePolicyAndToken(t, s.State(), 1010, "other", policyOther)
+
+	// Permissions are checked in the namespace of the allocation, whose node
+	// is unknown
+	alloc := mock.Alloc()
+	require.Nil(s.State().UpsertAllocs(1011, []*structs.Allocation{alloc}))
+
+	cases := []struct {
+		Name          string
+		Token         string
+		Namespace     string
+		ExpectedError string
+	}{
+		{
+			Name:          "bad token",
+			Token:         tokenBad.SecretID,
+			Namespace:     structs.DefaultNamespace,
+			ExpectedError: structs.ErrPermissionDenied.Error(),
+		},
+		{
+			Name:          "other namespace token",
+			Token:         tokenOther.SecretID,
+			Namespace:     "other",
+			ExpectedError: structs.ErrPermissionDenied.Error(),
+		},
+		{
+			Name:          "good token",
+			Token:         tokenGood.SecretID,
+			Namespace:     structs.DefaultNamespace,
+			ExpectedError: "Unknown node",
+		},
+		{
+			Name:          "root token",
+			Token:         root.SecretID,
+			Namespace:     structs.DefaultNamespace,
+			ExpectedError: "Unknown node",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			req := &cstructs.AllocExecRequest{
+				AllocID: alloc.ID,
+				Task:    "web",
+				Cmd:     []string{"ls"},
+				QueryOptions: structs.QueryOptions{
+					Namespace: c.Namespace,
+					Region:    "global",
+					AuthToken: c.Token,
+				},
+			}
+
+			// Get the handler
+			handler, err := s.StreamingRpcHandler("Allocations.Exec")
+			require.Nil(err)
+
+			// Create a pipe
+			p1, p2 := net.Pipe()
+			defer p1.Close()
+			defer p2.Close()
+
+			errCh := make(chan error)
+			streamMsg := make(chan *cstructs.StreamErrWrapper)
+
+			// Start the handler
+			go handler(p2)
+
+			// Start the decoder
+			go func() {
+				decoder := codec.NewDecoder(p1, structs.MsgpackHandle)
+				for {
+					var msg cstructs.StreamErrWrapper
+					if err := decoder.Decode(&msg); err != nil {
+						if err == io.EOF || strings.Contains(err.Error(), "closed") {
+							return
+						}
+						errCh <- fmt.Errorf("error decoding: %v", err)
+					}
+
+					streamMsg <- &msg
+				}
+			}()
+
+			// Send the request
+			encoder := codec.NewEncoder(p1, structs.MsgpackHandle)
+			require.Nil(encoder.Encode(req))
+
+			timeout := time.After(5 * time.Second)
+
+		OUTER:
+			for {
+				select {
+				case <-timeout:
+					t.Fatal("timeout")
+				case err := <-errCh:
+					t.Fatal(err)
+				case msg := <-streamMsg:
+					if msg.Error == nil {
+						continue
+					}
+
+					if strings.Contains(msg.Error.Error(), c.ExpectedError) {
+						break OUTER
+					} else {
+						t.Fatalf("Bad error: %v", msg.Error)
+					}
+				}
+			}
+		})
+	}
+}
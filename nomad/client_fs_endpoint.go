@@ -33,7 +33,7 @@ func (f *FileSystem) register() {
 // handleStreamResultError is a helper for sending an error with a potential
 // error code. The transmission of the error is ignored if the error has been
 // generated by the closing of the underlying transport.
-func (f *FileSystem) handleStreamResultError(err error, code *int64, encoder *codec.Encoder) {
+func handleStreamResultError(err error, code *int64, encoder *codec.Encoder) {
 	// Nothing to do as the conn is closed
 	if err == io.EOF || strings.Contains(err.Error(), "closed") {
 		return
@@ -46,41 +46,23 @@ func (f *FileSystem) handleStreamResultError(err error, code *int64, encoder *co
 }
 
 // forwardRegionStreamingRpc is used to make a streaming RPC to a different
-// region. It looks up the allocation in the remote region to determine what
-// remote server can route the request.
-func (f *FileSystem) forwardRegionStreamingRpc(conn io.ReadWriteCloser,
-	encoder *codec.Encoder, args interface{}, method, allocID string, qo *structs.QueryOptions) {
-	// Request the allocation from the target region
-	allocReq := &structs.AllocSpecificRequest{
-		AllocID:      allocID,
-		QueryOptions: *qo,
-	}
-	var allocResp structs.SingleAllocResponse
-	if err := f.srv.forwardRegion(qo.RequestRegion(), "Alloc.GetAlloc", allocReq, &allocResp); err != nil {
-		f.handleStreamResultError(err, nil, encoder)
-		return
-	}
-
-	if allocResp.Alloc == nil {
-		f.handleStreamResultError(structs.NewErrUnknownAllocation(allocID), helper.Int64ToPtr(404), encoder)
-		return
-	}
-
-	// Determine the Server that has a connection to the node.
-	srv, err := f.srv.serverWithNodeConn(allocResp.Alloc.NodeID, qo.RequestRegion())
+// region. The request is passed through to a random server of the region,
+// which routes it to the server connected to the node as it would a local
+// request. This avoids querying every remote server for its node connections
+// across the WAN.
+func (s *Server) forwardRegionStreamingRpc(conn io.ReadWriteCloser,
+	encoder *codec.Encoder, args interface{}, method, region string) {
+	srv, err := s.regionServer(region)
 	if err != nil {
-		var code *int64
-		if structs.IsErrNoNodeConn(err) {
-			code = helper.Int64ToPtr(404)
-		}
-		f.handleStreamResultError(err, code, encoder)
+		handleStreamResultError(err, nil, encoder)
 		return
 	}
 
 	// Get a connection to the server
-	srvConn, err := f.srv.streamingRpc(srv, method)
+	metrics.IncrCounter([]string{"nomad", "rpc", "cross-region", region}, 1)
+	srvConn, err := s.streamingRpc(srv, method)
 	if err != nil {
-		f.handleStreamResultError(err, nil, encoder)
+		handleStreamResultError(err, nil, encoder)
 		return
 	}
 	defer srvConn.Close()
@@ -88,7 +70,7 @@ func (f *FileSystem) forwardRegionStreamingRpc(conn io.ReadWriteCloser,
 	// Send the request.
 	outEncoder := codec.NewEncoder(srvConn, structs.MsgpackHandle)
 	if err := outEncoder.Encode(args); err != nil {
-		f.handleStreamResultError(err, nil, encoder)
+		handleStreamResultError(err, nil, encoder)
 		return
 	}
 
@@ -217,46 +199,45 @@ func (f *FileSystem) stream(conn io.ReadWriteCloser) {
 	encoder := codec.NewEncoder(conn, structs.MsgpackHandle)
 
 	if err := decoder.Decode(&args); err != nil {
-		f.handleStreamResultError(err, helper.Int64ToPtr(500), encoder)
+		handleStreamResultError(err, helper.Int64ToPtr(500), encoder)
 		return
 	}
 
 	// Check if we need to forward to a different region
 	if r := args.RequestRegion(); r != f.srv.Region() {
-		f.forwardRegionStreamingRpc(conn, encoder, &args, "FileSystem.Stream",
-			args.AllocID, &args.QueryOptions)
+		f.srv.forwardRegionStreamingRpc(conn, encoder, &args, "FileSystem.Stream", r)
 		return
 	}
 
 	// Check node read permissions
 	if aclObj, err := f.srv.ResolveToken(args.AuthToken); err != nil {
-		f.handleStreamResultError(err, nil, encoder)
+		handleStreamResultError(err, nil, encoder)
 		return
 	} else if aclObj != nil && !aclObj.AllowNsOp(args.Namespace, acl.NamespaceCapabilityReadFS) {
-		f.handleStreamResultError(structs.ErrPermissionDenied, nil, encoder)
+		handleStreamResultError(structs.ErrPermissionDenied, nil, encoder)
 		return
 	}
 
 	// Verify the arguments.
 	if args.AllocID == "" {
-		f.handleStreamResultError(errors.New("missing AllocID"), helper.Int64ToPtr(400), encoder)
+		handleStreamResultError(errors.New("missing AllocID"), helper.Int64ToPtr(400), encoder)
 		return
 	}
 
 	// Retrieve the allocation
 	snap, err := f.srv.State().Snapshot()
 	if err != nil {
-		f.handleStreamResultError(err, nil, encoder)
+		handleStreamResultError(err, nil, encoder)
 		return
 	}
 
 	alloc, err := snap.AllocByID(nil, args.AllocID)
 	if err != nil {
-		f.handleStreamResultError(err, nil, encoder)
+		handleStreamResultError(err, nil, encoder)
 		return
 	}
 	if alloc == nil {
-		f.handleStreamResultError(structs.NewErrUnknownAllocation(args.AllocID), helper.Int64ToPtr(404), encoder)
+		handleStreamResultError(structs.NewErrUnknownAllocation(args.AllocID), helper.Int64ToPtr(404), encoder)
 		return
 	}
 	nodeID := alloc.NodeID
@@ -264,18 +245,18 @@ func (f *FileSystem) stream(conn io.ReadWriteCloser) {
 	// Make sure Node is valid and new enough to support RPC
 	node, err := snap.NodeByID(nil, nodeID)
 	if err != nil {
-		f.handleStreamResultError(err, helper.Int64ToPtr(500), encoder)
+		handleStreamResultError(err, helper.Int64ToPtr(500), encoder)
 		return
 	}
 
 	if node == nil {
 		err := fmt.Errorf("Unknown node %q", nodeID)
-		f.handleStreamResultError(err, helper.Int64ToPtr(400), encoder)
+		handleStreamResultError(err, helper.Int64ToPtr(400), encoder)
 		return
 	}
 
 	if err := nodeSupportsRpc(node); err != nil {
-		f.handleStreamResultError(err, helper.Int64ToPtr(400), encoder)
+		handleStreamResultError(err, helper.Int64ToPtr(400), encoder)
 		return
 	}
 
@@ -291,13 +272,14 @@ func (f *FileSystem) stream(conn io.ReadWriteCloser) {
 			if structs.IsErrNoNodeConn(err) {
 				code = helper.Int64ToPtr(404)
 			}
-			f.handleStreamResultError(err, code, encoder)
+			handleStreamResultError(err, code, encoder)
+			return
 		}
 
 		// Get a connection to the server
 		conn, err := f.srv.streamingRpc(srv, "FileSystem.Stream")
 		if err != nil {
-			f.handleStreamResultError(err, nil, encoder)
+			handleStreamResultError(err, nil, encoder)
 			return
 		}
 
@@ -305,7 +287,7 @@ func (f *FileSystem) stream(conn io.ReadWriteCloser) {
 	} else {
 		stream, err := NodeStreamingRpc(state.Session, "FileSystem.Stream")
 		if err != nil {
-			f.handleStreamResultError(err, nil, encoder)
+			handleStreamResultError(err, nil, encoder)
 			return
 		}
 		clientConn = stream
@@ -315,7 +297,7 @@ func (f *FileSystem) stream(conn io.ReadWriteCloser) {
 	// Send the request.
 	outEncoder := codec.NewEncoder(clientConn, structs.MsgpackHandle)
 	if err := outEncoder.Encode(args); err != nil {
-		f.handleStreamResultError(err, nil, encoder)
+		handleStreamResultError(err, nil, encoder)
 		return
 	}
 
@@ -334,50 +316,49 @@ func (f *FileSystem) logs(conn io.ReadWriteCloser) {
 	encoder := codec.NewEncoder(conn, structs.MsgpackHandle)
 
 	if err := decoder.Decode(&args); err != nil {
-		f.handleStreamResultError(err, helper.Int64ToPtr(500), encoder)
+		handleStreamResultError(err, helper.Int64ToPtr(500), encoder)
 		return
 	}
 
 	// Check if we need to forward to a different region
 	if r := args.RequestRegion(); r != f.srv.Region() {
-		f.forwardRegionStreamingRpc(conn, encoder, &args, "FileSystem.Logs",
-			args.AllocID, &args.QueryOptions)
+		f.srv.forwardRegionStreamingRpc(conn, encoder, &args, "FileSystem.Logs", r)
 		return
 	}
 
 	// Check node read permissions
 	if aclObj, err := f.srv.ResolveToken(args.AuthToken); err != nil {
-		f.handleStreamResultError(err, nil, encoder)
+		handleStreamResultError(err, nil, encoder)
 		return
 	} else if aclObj != nil {
 		readfs := aclObj.AllowNsOp(args.QueryOptions.Namespace, acl.NamespaceCapabilityReadFS)
 		logs := aclObj.AllowNsOp(args.QueryOptions.Namespace, acl.NamespaceCapabilityReadLogs)
 		if !readfs && !logs {
-			f.handleStreamResultError(structs.ErrPermissionDenied, nil, encoder)
+			handleStreamResultError(structs.ErrPermissionDenied, nil, encoder)
 			return
 		}
 	}
 
 	// Verify the arguments.
 	if args.AllocID == "" {
-		f.handleStreamResultError(errors.New("missing AllocID"), helper.Int64ToPtr(400), encoder)
+		handleStreamResultError(errors.New("missing AllocID"), helper.Int64ToPtr(400), encoder)
 		return
 	}
 
 	// Retrieve the allocation
 	snap, err := f.srv.State().Snapshot()
 	if err != nil {
-		f.handleStreamResultError(err, nil, encoder)
+		handleStreamResultError(err, nil, encoder)
 		return
 	}
 
 	alloc, err := snap.AllocByID(nil, args.AllocID)
 	if err != nil {
-		f.handleStreamResultError(err, nil, encoder)
+		handleStreamResultError(err, nil, encoder)
 		return
 	}
 	if alloc == nil {
-		f.handleStreamResultError(structs.NewErrUnknownAllocation(args.AllocID), helper.Int64ToPtr(404), encoder)
+		handleStreamResultError(structs.NewErrUnknownAllocation(args.AllocID), helper.Int64ToPtr(404), encoder)
 		return
 	}
 	nodeID := alloc.NodeID
@@ -385,18 +366,18 @@ func (f *FileSystem) logs(conn io.ReadWriteCloser) {
 	// Make sure Node is valid and new enough to support RPC
 	node, err := snap.NodeByID(nil, nodeID)
 	if err != nil {
-		f.handleStreamResultError(err, helper.Int64ToPtr(500), encoder)
+		handleStreamResultError(err, helper.Int64ToPtr(500), encoder)
 		return
 	}
 
 	if node == nil {
 		err := fmt.Errorf("Unknown node %q", nodeID)
-		f.handleStreamResultError(err, helper.Int64ToPtr(400), encoder)
+		handleStreamResultError(err, helper.Int64ToPtr(400), encoder)
 		return
 	}
 
 	if err := nodeSupportsRpc(node); err != nil {
-		f.handleStreamResultError(err, helper.Int64ToPtr(400), encoder)
+		handleStreamResultError(err, helper.Int64ToPtr(400), encoder)
 		return
 	}
 
@@ -412,14 +393,14 @@ func (f *FileSystem) logs(conn io.ReadWriteCloser) {
 			if structs.IsErrNoNodeConn(err) {
 				code = helper.Int64ToPtr(404)
 			}
-			f.handleStreamResultError(err, code, encoder)
+			handleStreamResultError(err, code, encoder)
 			return
 		}
 
 		// Get a connection to the server
 		conn, err := f.srv.streamingRpc(srv, "FileSystem.Logs")
 		if err != nil {
-			f.handleStreamResultError(err, nil, encoder)
+			handleStreamResultError(err, nil, encoder)
 			return
 		}
 
@@ -427,7 +408,7 @@ func (f *FileSystem) logs(conn io.ReadWriteCloser) {
 	} else {
 		stream, err := NodeStreamingRpc(state.Session, "FileSystem.Logs")
 		if err != nil {
-			f.handleStreamResultError(err, nil, encoder)
+			handleStreamResultError(err, nil, encoder)
 			return
 		}
 		clientConn = stream
@@ -437,7 +418,7 @@ func (f *FileSystem) logs(conn io.ReadWriteCloser) {
 	// Send the request.
 	outEncoder := codec.NewEncoder(clientConn, structs.MsgpackHandle)
 	if err := outEncoder.Encode(args); err != nil {
-		f.handleStreamResultError(err, nil, encoder)
+		handleStreamResultError(err, nil, encoder)
 		return
 	}
 
@@ -0,0 +1,94 @@
+package nomad
+
+import (
+	"errors"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/acl"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// ClientHostVolume is used to forward RPC requests to the targed Nomad
+// client's HostVolume endpoint.
+type ClientHostVolume struct {
+	srv    *Server
+	logger log.Logger
+}
+
+// Create is used to create a host volume on a node.
+func (v *ClientHostVolume) Create(args *cstructs.HostVolumeCreateRequest, reply *cstructs.HostVolumeCreateResponse) error {
+	// We only allow stale reads since the only potentially stale information is
+	// the Node registration and the cost is fairly high for adding another hope
+	// in the forwarding chain.
+	args.QueryOptions.AllowStale = true
+
+	// Potentially forward to a different region.
+	if done, err := v.srv.forward("ClientHostVolume.Create", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "client_host_volume", "create"}, time.Now())
+
+	// Check for write-volume permissions
+	if aclObj, err := v.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityWriteVolume) {
+		return structs.ErrPermissionDenied
+	}
+
+	return v.forwardToNode(args.NodeID, "ClientHostVolume.Create", "HostVolume.Create", args, reply)
+}
+
+// Delete is used to delete a host volume created on a node.
+func (v *ClientHostVolume) Delete(args *cstructs.HostVolumeDeleteRequest, reply *structs.GenericResponse) error {
+	// We only allow stale reads since the only potentially stale information is
+	// the Node registration and the cost is fairly high for adding another hope
+	// in the forwarding chain.
+	args.QueryOptions.AllowStale = true
+
+	// Potentially forward to a different region.
+	if done, err := v.srv.forward("ClientHostVolume.Delete", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "client_host_volume", "delete"}, time.Now())
+
+	// Check for write-volume permissions
+	if aclObj, err := v.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityWriteVolume) {
+		return structs.ErrPermissionDenied
+	}
+
+	return v.forwardToNode(args.NodeID, "ClientHostVolume.Delete", "HostVolume.Delete", args, reply)
+}
+
+// forwardToNode makes the client RPC to the node, or forwards the server RPC
+// to the server connected to the node.
+func (v *ClientHostVolume) forwardToNode(nodeID, serverMethod, nodeMethod string, args, reply interface{}) error {
+	// Verify the arguments.
+	if nodeID == "" {
+		return errors.New("missing NodeID")
+	}
+
+	// Make sure Node is valid and new enough to support RPC
+	snap, err := v.srv.State().Snapshot()
+	if err != nil {
+		return err
+	}
+
+	_, err = getNodeForRpc(snap, nodeID)
+	if err != nil {
+		return err
+	}
+
+	// Get the connection to the client
+	state, ok := v.srv.getNodeConn(nodeID)
+	if !ok {
+		return findNodeConnAndForward(v.srv, nodeID, serverMethod, args, reply)
+	}
+
+	// Make the RPC
+	return NodeRpc(state.Session, nodeMethod, args, reply)
+}
@@ -0,0 +1,83 @@
+package nomad
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/client"
+	"github.com/hashicorp/nomad/client/config"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientHostVolume_Local(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	// Start a server and client
+	s := TestServer(t, nil)
+	defer s.Shutdown()
+	codec := rpcClient(t, s)
+	testutil.WaitForLeader(t, s.RPC)
+
+	dir, err := ioutil.TempDir("", "nomadtest-hostvolumes")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	c, cleanup := client.TestClient(t, func(c *config.Config) {
+		c.Servers = []string{s.config.RPCAddr.String()}
+		c.HostVolumesDir = dir
+	})
+	defer cleanup()
+
+	testutil.WaitForResult(func() (bool, error) {
+		nodes := s.connectedNodes()
+		return len(nodes) == 1, nil
+	}, func(err error) {
+		t.Fatalf("should have a clients")
+	})
+
+	// Make the request without having a node-id
+	createReq := &cstructs.HostVolumeCreateRequest{
+		Volume: &structs.Volume{ID: "data", Name: "data"},
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: structs.DefaultNamespace,
+		},
+	}
+	var createResp cstructs.HostVolumeCreateResponse
+	err = msgpackrpc.CallWithCodec(codec, "ClientHostVolume.Create", createReq, &createResp)
+	require.Error(err)
+	require.Contains(err.Error(), "missing")
+
+	// Create the volume on the node
+	createReq.NodeID = c.NodeID()
+	require.NoError(msgpackrpc.CallWithCodec(codec, "ClientHostVolume.Create", createReq, &createResp))
+	require.Equal(c.NodeID(), createResp.Volume.NodeID)
+
+	volume, err := s.State().VolumeByID(nil, structs.DefaultNamespace, "data")
+	require.NoError(err)
+	require.NotNil(volume)
+
+	// Delete it
+	deleteReq := &cstructs.HostVolumeDeleteRequest{
+		NodeID:   c.NodeID(),
+		VolumeID: "data",
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: structs.DefaultNamespace,
+		},
+	}
+	var deleteResp structs.GenericResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "ClientHostVolume.Delete", deleteReq, &deleteResp))
+
+	volume, err = s.State().VolumeByID(nil, structs.DefaultNamespace, "data")
+	require.NoError(err)
+	require.Nil(volume)
+	_, err = os.Stat(createResp.Volume.ExternalID)
+	require.True(os.IsNotExist(err))
+}
@@ -0,0 +1,89 @@
+package nomad
+
+import (
+	"errors"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	log "github.com/hashicorp/go-hclog"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// ClientNodeMeta is used to forward RPC requests to the targed Nomad client's
+// NodeMeta endpoint.
+type ClientNodeMeta struct {
+	srv    *Server
+	logger log.Logger
+}
+
+// Read is used to retrieve the metadata of a node.
+func (n *ClientNodeMeta) Read(args *structs.NodeSpecificRequest, reply *cstructs.NodeMetaResponse) error {
+	// We only allow stale reads since the only potentially stale information is
+	// the Node registration and the cost is fairly high for adding another hope
+	// in the forwarding chain.
+	args.QueryOptions.AllowStale = true
+
+	// Potentially forward to a different region.
+	if done, err := n.srv.forward("ClientNodeMeta.Read", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "client_node_meta", "read"}, time.Now())
+
+	// Check node read permissions
+	if aclObj, err := n.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNodeRead() {
+		return structs.ErrPermissionDenied
+	}
+
+	return n.forwardToNode(args.NodeID, "ClientNodeMeta.Read", "NodeMeta.Read", args, reply)
+}
+
+// Apply is used to set and unset the dynamic metadata of a node through its
+// client, so that the client persists it.
+func (n *ClientNodeMeta) Apply(args *structs.NodeUpdateDynamicMetaRequest, reply *structs.NodeDynamicMetaUpdateResponse) error {
+	// Potentially forward to a different region.
+	if done, err := n.srv.forward("ClientNodeMeta.Apply", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "client_node_meta", "apply"}, time.Now())
+
+	// Check node write permissions
+	if aclObj, err := n.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNodeWrite() {
+		return structs.ErrPermissionDenied
+	}
+
+	return n.forwardToNode(args.NodeID, "ClientNodeMeta.Apply", "NodeMeta.Apply", args, reply)
+}
+
+// forwardToNode makes the client RPC to the node, or forwards the server RPC
+// to the server connected to the node.
+func (n *ClientNodeMeta) forwardToNode(nodeID, serverMethod, nodeMethod string, args, reply interface{}) error {
+	// Verify the arguments.
+	if nodeID == "" {
+		return errors.New("missing NodeID")
+	}
+
+	// Make sure Node is valid and new enough to support RPC
+	snap, err := n.srv.State().Snapshot()
+	if err != nil {
+		return err
+	}
+
+	_, err = getNodeForRpc(snap, nodeID)
+	if err != nil {
+		return err
+	}
+
+	// Get the connection to the client
+	state, ok := n.srv.getNodeConn(nodeID)
+	if !ok {
+		return findNodeConnAndForward(n.srv, nodeID, serverMethod, args, reply)
+	}
+
+	// Make the RPC
+	return NodeRpc(state.Session, nodeMethod, args, reply)
+}
@@ -0,0 +1,64 @@
+package nomad
+
+import (
+	"testing"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/client"
+	"github.com/hashicorp/nomad/client/config"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientNodeMeta_Local(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	// Start a server and client
+	s := TestServer(t, nil)
+	defer s.Shutdown()
+	codec := rpcClient(t, s)
+	testutil.WaitForLeader(t, s.RPC)
+
+	c, cleanup := client.TestClient(t, func(c *config.Config) {
+		c.Servers = []string{s.config.RPCAddr.String()}
+	})
+	defer cleanup()
+
+	testutil.WaitForResult(func() (bool, error) {
+		nodes := s.connectedNodes()
+		return len(nodes) == 1, nil
+	}, func(err error) {
+		t.Fatalf("should have a clients")
+	})
+
+	// Make the request without having a node-id
+	applyReq := &structs.NodeUpdateDynamicMetaRequest{
+		Set:          map[string]string{"rack": "r1"},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.NodeDynamicMetaUpdateResponse
+	err := msgpackrpc.CallWithCodec(codec, "ClientNodeMeta.Apply", applyReq, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "missing")
+
+	// Update the metadata of the node through its client
+	applyReq.NodeID = c.NodeID()
+	require.NoError(msgpackrpc.CallWithCodec(codec, "ClientNodeMeta.Apply", applyReq, &resp))
+
+	node, err := s.State().NodeByID(nil, c.NodeID())
+	require.NoError(err)
+	require.Equal(map[string]string{"rack": "r1"}, node.DynamicMeta)
+
+	// Read it back
+	readReq := &structs.NodeSpecificRequest{
+		NodeID:       c.NodeID(),
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var readResp cstructs.NodeMetaResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "ClientNodeMeta.Read", readReq, &readResp))
+	require.Equal("r1", readResp.Meta["rack"])
+	require.Equal(map[string]string{"rack": "r1"}, readResp.Dynamic)
+}
@@ -147,7 +147,7 @@ func (s *Server) serverWithNodeConn(nodeID, region string) (*serverParts, error)
 	req := &structs.NodeSpecificRequest{
 		NodeID: nodeID,
 		QueryOptions: structs.QueryOptions{
-			Region: s.config.Region,
+			Region: region,
 		},
 	}
 
@@ -188,7 +188,7 @@ func (s *Server) serverWithNodeConn(nodeID, region string) (*serverParts, error)
 
 		// Make the RPC
 		var resp structs.NodeConnQueryResponse
-		err := s.connPool.RPC(s.config.Region, server.Addr, server.MajorVersion,
+		err := s.connPool.RPC(region, server.Addr, server.MajorVersion,
 			"Status.HasNodeConn", &req, &resp)
 		if err != nil {
 			multierror.Append(&rpcErr, fmt.Errorf("failed querying server %q: %v", server.Addr.String(), err))
@@ -104,13 +104,18 @@ type Config struct {
 	// RaftTimeout is applied to any network traffic for raft. Defaults to 10s.
 	RaftTimeout time.Duration
 
-	// (Enterprise-only) NonVoter is used to prevent this server from being added
+	// NonVoter is used to prevent this server from being added
 	// as a voting member of the Raft cluster.
 	NonVoter bool
 
-	// (Enterprise-only) RedundancyZone is the redundancy zone to use for this server.
+	// RedundancyZone is the redundancy zone to use for this server.
 	RedundancyZone string
 
+	// KeyringEncryptionKey is the key the root keys variables are encrypted
+	// with are wrapped with in the state. The root keys are stored unwrapped
+	// if it is empty.
+	KeyringEncryptionKey []byte
+
 	// (Enterprise-only) UpgradeVersion is the custom upgrade version to use when
 	// performing upgrade migrations.
 	UpgradeVersion string
@@ -184,6 +189,10 @@ type Config struct {
 	// for GC. This gives users some time to view terminal deployments.
 	DeploymentGCThreshold time.Duration
 
+	// ACLTokenExpirationGCInterval is how often we dispatch a job to GC
+	// expired ACL tokens.
+	ACLTokenExpirationGCInterval time.Duration
+
 	// EvalNackTimeout controls how long we allow a sub-scheduler to
 	// work on an evaluation before we consider it failed and Nack it.
 	// This allows that evaluation to be handed to another sub-scheduler
@@ -191,11 +200,49 @@ type Config struct {
 	// no evaluation hits it unless the sub-scheduler has failed.
 	EvalNackTimeout time.Duration
 
+	// EventBufferSize is the number of batches of cluster events kept in
+	// memory for the event streams to resume from.
+	EventBufferSize int
+
 	// EvalDeliveryLimit is the limit of attempts we make to deliver and
 	// process an evaluation. This is used so that an eval that will never
 	// complete eventually fails out of the system.
 	EvalDeliveryLimit int
 
+	// NodeEventRetention is the number of most recent events retained for
+	// each node. Servers prune events as they apply them, so it should be the
+	// same on all the servers.
+	NodeEventRetention int
+
+	// PlanBatchSize is the maximum number of compatible plans the leader
+	// verifies in parallel and commits back to back. Plans are compatible if
+	// they touch disjoint sets of nodes and quotas. A value of one disables
+	// batching.
+	PlanBatchSize int
+
+	// EvalUpdateBatchSize is the maximum number of evaluation updates of the
+	// schedulers the leader coalesces into a single Raft log entry. A value
+	// of one disables batching, which is the default.
+	EvalUpdateBatchSize int
+
+	// RPCMaxIdleStreams is the maximum number of idle streams kept open on
+	// the pooled connection to each server, including the servers of other
+	// regions.
+	RPCMaxIdleStreams int
+
+	// RPCConnIdleTimeout is how long an idle pooled connection to another
+	// server is kept open.
+	RPCConnIdleTimeout time.Duration
+
+	// EvalFairShare enables fair-share dequeuing of evaluations across
+	// namespaces so that a flood of evaluations in one namespace can not
+	// starve the placements of other namespaces.
+	EvalFairShare bool
+
+	// EvalFairShareWeights is the fair-share weight of each namespace.
+	// Namespaces without a weight have a weight of one.
+	EvalFairShareWeights map[string]int
+
 	// EvalNackInitialReenqueueDelay is the delay applied before reenqueuing a
 	// Nacked evaluation for the first time. This value should be small as the
 	// initial Nack can be due to a down machine and the eval should be retried
@@ -258,6 +305,11 @@ type Config struct {
 	// ACLEnabled controls if ACL enforcement and management is enabled.
 	ACLEnabled bool
 
+	// ACLTokenMinExpirationTTL and ACLTokenMaxExpirationTTL bound the
+	// expiration TTL tokens can be created with.
+	ACLTokenMinExpirationTTL time.Duration
+	ACLTokenMaxExpirationTTL time.Duration
+
 	// ReplicationBackoff is how much we backoff when replication errors.
 	// This is a tunable knob for testing primarily.
 	ReplicationBackoff time.Duration
@@ -272,6 +324,10 @@ type Config struct {
 	// SentinelConfig is this Agent's Sentinel configuration
 	SentinelConfig *config.SentinelConfig
 
+	// OPAConfig is the configuration of the OPA policies the jobs submitted
+	// are evaluated against
+	OPAConfig *config.OPAConfig
+
 	// StatsCollectionInterval is the interval at which the Nomad server
 	// publishes metrics which are periodic in nature like updating gauges
 	StatsCollectionInterval time.Duration
@@ -301,6 +357,15 @@ type Config struct {
 	// dead servers.
 	AutopilotInterval time.Duration
 
+	// SnapshotAgentConfig is the configuration of the snapshots saved
+	// periodically by the leader. They aren't saved if it's nil or not
+	// enabled.
+	SnapshotAgentConfig *config.SnapshotAgentConfig
+
+	// AdmissionWebhooks are the external webhooks called to mutate or
+	// validate the jobs registered, planned and validated.
+	AdmissionWebhooks []*config.AdmissionWebhookConfig
+
 	// PluginLoader is used to load plugins.
 	PluginLoader loader.PluginCatalog
 
@@ -350,8 +415,17 @@ func DefaultConfig() *Config {
 		NodeGCThreshold:                  24 * time.Hour,
 		DeploymentGCInterval:             5 * time.Minute,
 		DeploymentGCThreshold:            1 * time.Hour,
+		ACLTokenExpirationGCInterval:     5 * time.Minute,
+		ACLTokenMinExpirationTTL:         1 * time.Minute,
+		ACLTokenMaxExpirationTTL:         24 * time.Hour,
 		EvalNackTimeout:                  60 * time.Second,
 		EvalDeliveryLimit:                3,
+		EventBufferSize:                  100,
+		NodeEventRetention:               structs.MaxRetainedNodeEvents,
+		PlanBatchSize:                    1,
+		EvalUpdateBatchSize:              1,
+		RPCMaxIdleStreams:                64,
+		RPCConnIdleTimeout:               2 * time.Minute,
 		EvalNackInitialReenqueueDelay:    1 * time.Second,
 		EvalNackSubsequentReenqueueDelay: 20 * time.Second,
 		EvalFailedFollowupBaselineDelay:  1 * time.Minute,
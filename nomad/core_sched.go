@@ -50,6 +50,10 @@ func (c *CoreScheduler) Process(eval *structs.Evaluation) error {
 		return c.jobGC(eval)
 	case structs.CoreJobDeploymentGC:
 		return c.deploymentGC(eval)
+	case structs.CoreJobLocalTokenExpiredGC:
+		return c.expiredACLTokenGC(eval, false)
+	case structs.CoreJobGlobalTokenExpiredGC:
+		return c.expiredACLTokenGC(eval, true)
 	case structs.CoreJobForceGC:
 		return c.forceGC(eval)
 	default:
@@ -68,6 +72,12 @@ func (c *CoreScheduler) forceGC(eval *structs.Evaluation) error {
 	if err := c.deploymentGC(eval); err != nil {
 		return err
 	}
+	if err := c.expiredACLTokenGC(eval, false); err != nil {
+		return err
+	}
+	if err := c.expiredACLTokenGC(eval, true); err != nil {
+		return err
+	}
 
 	// Node GC must occur after the others to ensure the allocations are
 	// cleared.
@@ -83,19 +93,13 @@ func (c *CoreScheduler) jobGC(eval *structs.Evaluation) error {
 		return err
 	}
 
-	var oldThreshold uint64
-	if eval.JobID == structs.CoreJobForceGC {
-		// The GC was forced, so set the threshold to its maximum so everything
-		// will GC.
-		oldThreshold = math.MaxUint64
+	thresholds := c.newGCThresholds(eval)
+	if thresholds.forced {
 		c.logger.Debug("forced job GC")
 	} else {
-		// Get the time table to calculate GC cutoffs.
-		tt := c.srv.fsm.TimeTable()
-		cutoff := time.Now().UTC().Add(-1 * c.srv.config.JobGCThreshold)
-		oldThreshold = tt.NearestIndex(cutoff)
 		c.logger.Debug("job GC scanning before cutoff index",
-			"index", oldThreshold, "job_gc_threshold", c.srv.config.JobGCThreshold)
+			"index", thresholds.index(c.srv.config.JobGCThreshold),
+			"job_gc_threshold", c.srv.config.JobGCThreshold)
 	}
 
 	// Collect the allocations, evaluations and jobs to GC
@@ -107,9 +111,12 @@ OUTER:
 		job := i.(*structs.Job)
 
 		// Ignore new jobs.
+		jobThreshold := thresholds.jobThreshold(job)
+		oldThreshold := thresholds.index(jobThreshold)
 		if job.CreateIndex > oldThreshold {
 			continue
 		}
+		allocThreshold := thresholds.index(thresholds.allocThreshold(job.Namespace, job, jobThreshold))
 
 		ws := memdb.NewWatchSet()
 		evals, err := c.snap.EvalsByJob(ws, job.Namespace, job.ID)
@@ -121,7 +128,7 @@ OUTER:
 		allEvalsGC := true
 		var jobAlloc, jobEval []string
 		for _, eval := range evals {
-			gc, allocs, err := c.gcEval(eval, oldThreshold, true)
+			gc, allocs, err := c.gcEval(eval, job, oldThreshold, allocThreshold, true)
 			if err != nil {
 				continue OUTER
 			}
@@ -220,21 +227,13 @@ func (c *CoreScheduler) evalGC(eval *structs.Evaluation) error {
 		return err
 	}
 
-	var oldThreshold uint64
-	if eval.JobID == structs.CoreJobForceGC {
-		// The GC was forced, so set the threshold to its maximum so everything
-		// will GC.
-		oldThreshold = math.MaxUint64
+	thresholds := c.newGCThresholds(eval)
+	if thresholds.forced {
 		c.logger.Debug("forced eval GC")
 	} else {
-		// Compute the old threshold limit for GC using the FSM
-		// time table.  This is a rough mapping of a time to the
-		// Raft index it belongs to.
-		tt := c.srv.fsm.TimeTable()
-		cutoff := time.Now().UTC().Add(-1 * c.srv.config.EvalGCThreshold)
-		oldThreshold = tt.NearestIndex(cutoff)
 		c.logger.Debug("eval GC scanning before cutoff index",
-			"index", oldThreshold, "eval_gc_threshold", c.srv.config.EvalGCThreshold)
+			"index", thresholds.index(c.srv.config.EvalGCThreshold),
+			"eval_gc_threshold", c.srv.config.EvalGCThreshold)
 	}
 
 	// Collect the allocations and evaluations to GC
@@ -242,9 +241,22 @@ func (c *CoreScheduler) evalGC(eval *structs.Evaluation) error {
 	for raw := iter.Next(); raw != nil; raw = iter.Next() {
 		eval := raw.(*structs.Evaluation)
 
+		// Ignore non-terminal evaluations before looking up their job
+		if !eval.TerminalStatus() {
+			continue
+		}
+
+		job, err := c.snap.JobByID(nil, eval.Namespace, eval.JobID)
+		if err != nil {
+			return err
+		}
+		evalThreshold := thresholds.evalThreshold(eval.Namespace, job)
+		oldThreshold := thresholds.index(evalThreshold)
+		allocThreshold := thresholds.index(thresholds.allocThreshold(eval.Namespace, job, evalThreshold))
+
 		// The Evaluation GC should not handle batch jobs since those need to be
 		// garbage collected in one shot
-		gc, allocs, err := c.gcEval(eval, oldThreshold, false)
+		gc, allocs, err := c.gcEval(eval, job, oldThreshold, allocThreshold, false)
 		if err != nil {
 			return err
 		}
@@ -265,12 +277,13 @@ func (c *CoreScheduler) evalGC(eval *structs.Evaluation) error {
 	return c.evalReap(gcEval, gcAlloc)
 }
 
-// gcEval returns whether the eval should be garbage collected given a raft
-// threshold index. The eval disqualifies for garbage collection if it or its
-// allocs are not older than the threshold. If the eval should be garbage
-// collected, the associated alloc ids that should also be removed are also
-// returned
-func (c *CoreScheduler) gcEval(eval *structs.Evaluation, thresholdIndex uint64, allowBatch bool) (
+// gcEval returns whether the eval of the job, which may no longer exist,
+// should be garbage collected given raft threshold indexes. The eval
+// disqualifies for garbage collection if it is not older than the eval
+// threshold or its allocs are not older than the alloc threshold. If the eval
+// should be garbage collected, the associated alloc ids that should also be
+// removed are also returned
+func (c *CoreScheduler) gcEval(eval *structs.Evaluation, job *structs.Job, thresholdIndex, allocThresholdIndex uint64, allowBatch bool) (
 	bool, []string, error) {
 	// Ignore non-terminal and new evaluations
 	if !eval.TerminalStatus() || eval.ModifyIndex > thresholdIndex {
@@ -280,12 +293,6 @@ func (c *CoreScheduler) gcEval(eval *structs.Evaluation, thresholdIndex uint64,
 	// Create a watchset
 	ws := memdb.NewWatchSet()
 
-	// Look up the job
-	job, err := c.snap.JobByID(ws, eval.Namespace, eval.JobID)
-	if err != nil {
-		return false, nil, err
-	}
-
 	// Get the allocations by eval
 	allocs, err := c.snap.AllocsByEval(ws, eval.ID)
 	if err != nil {
@@ -294,11 +301,11 @@ func (c *CoreScheduler) gcEval(eval *structs.Evaluation, thresholdIndex uint64,
 		return false, nil, err
 	}
 
-	// If the eval is from a running "batch" job we don't want to garbage
-	// collect its allocations. If there is a long running batch job and its
-	// terminal allocations get GC'd the scheduler would re-run the
+	// If the eval is from a running "batch" or "sysbatch" job we don't want to
+	// garbage collect its allocations. If there is a long running batch job
+	// and its terminal allocations get GC'd the scheduler would re-run the
 	// allocations.
-	if eval.Type == structs.JobTypeBatch {
+	if eval.Type == structs.JobTypeBatch || eval.Type == structs.JobTypeSysBatch {
 		// Check if the job is running
 
 		// Can collect if:
@@ -329,7 +336,7 @@ func (c *CoreScheduler) gcEval(eval *structs.Evaluation, thresholdIndex uint64,
 	gcEval := true
 	var gcAllocIDs []string
 	for _, alloc := range allocs {
-		if !allocGCEligible(alloc, job, time.Now(), thresholdIndex) {
+		if !allocGCEligible(alloc, job, time.Now(), allocThresholdIndex) {
 			// Can't GC the evaluation since not all of the allocations are
 			// terminal
 			gcEval = false
@@ -342,6 +349,112 @@ func (c *CoreScheduler) gcEval(eval *structs.Evaluation, thresholdIndex uint64,
 	return gcEval, gcAllocIDs, nil
 }
 
+// gcThresholds resolves the threshold indexes jobs, evals and allocs are
+// garbage collected before. The thresholds of the server are overridden by
+// the GC configuration of the namespace of the objects, and then by the one
+// of their job.
+type gcThresholds struct {
+	srv    *Server
+	snap   *state.StateSnapshot
+	logger log.Logger
+
+	// forced is set when the GC is forced, so that everything is collected
+	forced bool
+
+	// now is the time the thresholds are relative to
+	now time.Time
+
+	// indexes caches the index of each threshold
+	indexes map[time.Duration]uint64
+
+	// namespaces caches the GC configuration of the namespaces
+	namespaces map[string]*structs.GCConfig
+}
+
+// newGCThresholds returns the GC thresholds of the core eval.
+func (c *CoreScheduler) newGCThresholds(eval *structs.Evaluation) *gcThresholds {
+	return &gcThresholds{
+		srv:        c.srv,
+		snap:       c.snap,
+		logger:     c.logger,
+		forced:     eval.JobID == structs.CoreJobForceGC,
+		now:        time.Now().UTC(),
+		indexes:    make(map[time.Duration]uint64),
+		namespaces: make(map[string]*structs.GCConfig),
+	}
+}
+
+// index returns the Raft index of the threshold. It is computed using the FSM
+// time table, which is a rough mapping of a time to the Raft index it belongs
+// to. If the GC was forced, the index is set to its maximum so everything will
+// GC.
+func (t *gcThresholds) index(threshold time.Duration) uint64 {
+	if t.forced {
+		return math.MaxUint64
+	}
+	if index, ok := t.indexes[threshold]; ok {
+		return index
+	}
+	index := t.srv.fsm.TimeTable().NearestIndex(t.now.Add(-1 * threshold))
+	t.indexes[threshold] = index
+	return index
+}
+
+// jobThreshold returns the threshold of the job.
+func (t *gcThresholds) jobThreshold(job *structs.Job) time.Duration {
+	return t.resolve(job.Namespace, job, t.srv.config.JobGCThreshold, func(gc *structs.GCConfig) time.Duration {
+		return gc.JobThreshold
+	})
+}
+
+// evalThreshold returns the threshold of the evals of the job in the
+// namespace. The job is nil if it no longer exists.
+func (t *gcThresholds) evalThreshold(namespace string, job *structs.Job) time.Duration {
+	return t.resolve(namespace, job, t.srv.config.EvalGCThreshold, func(gc *structs.GCConfig) time.Duration {
+		return gc.EvalThreshold
+	})
+}
+
+// allocThreshold returns the threshold of the allocs of the job in the
+// namespace, defaulting to the threshold of the eval or job they are
+// collected with. The job is nil if it no longer exists.
+func (t *gcThresholds) allocThreshold(namespace string, job *structs.Job, threshold time.Duration) time.Duration {
+	return t.resolve(namespace, job, threshold, func(gc *structs.GCConfig) time.Duration {
+		return gc.AllocThreshold
+	})
+}
+
+// resolve overrides the threshold by the one of the namespace and then by the
+// one of the job, if they are set.
+func (t *gcThresholds) resolve(namespace string, job *structs.Job, threshold time.Duration,
+	field func(*structs.GCConfig) time.Duration) time.Duration {
+	if gc := t.namespaceGC(namespace); gc != nil && field(gc) != 0 {
+		threshold = field(gc)
+	}
+	if job != nil && job.GC != nil && field(job.GC) != 0 {
+		threshold = field(job.GC)
+	}
+	return threshold
+}
+
+// namespaceGC returns the GC configuration of the namespace, or nil if the
+// namespace does not override the thresholds.
+func (t *gcThresholds) namespaceGC(name string) *structs.GCConfig {
+	if gc, ok := t.namespaces[name]; ok {
+		return gc
+	}
+
+	var gc *structs.GCConfig
+	ns, err := t.snap.NamespaceByName(nil, name)
+	if err != nil {
+		t.logger.Error("failed to look up namespace", "namespace", name, "error", err)
+	} else if ns != nil {
+		gc = ns.GC
+	}
+	t.namespaces[name] = gc
+	return gc
+}
+
 // olderVersionTerminalAllocs returns terminal allocations whose job create index
 // is older than the job's create index
 func olderVersionTerminalAllocs(allocs []*structs.Allocation, job *structs.Job) []string {
@@ -615,6 +728,66 @@ func (c *CoreScheduler) partitionDeploymentReap(deployments []string) []*structs
 	return requests
 }
 
+// expiredACLTokenGC is used to garbage collect the expired local or global
+// ACL tokens. Global tokens are only collected in the authoritative region.
+func (c *CoreScheduler) expiredACLTokenGC(eval *structs.Evaluation, global bool) error {
+	if !c.srv.config.ACLEnabled {
+		return nil
+	}
+	if global && c.srv.config.Region != c.srv.config.AuthoritativeRegion {
+		return nil
+	}
+
+	ws := memdb.NewWatchSet()
+	iter, err := c.snap.ACLTokensByGlobal(ws, global)
+	if err != nil {
+		return err
+	}
+
+	// Collect the expired tokens
+	now := time.Now().UTC()
+	var expired []string
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		token := raw.(*structs.ACLToken)
+		if token.IsExpired(now) {
+			expired = append(expired, token.AccessorID)
+		}
+	}
+
+	// Fast-path the nothing case
+	if len(expired) == 0 {
+		return nil
+	}
+	c.logger.Debug("expired ACL token GC found eligible tokens", "tokens", len(expired), "global", global)
+	return c.aclTokenReap(expired, eval.LeaderACL)
+}
+
+// aclTokenReap contacts the leader and issues a reap on the passed tokens,
+// ensuring a single request does not contain too many tokens.
+func (c *CoreScheduler) aclTokenReap(accessorIDs []string, leaderACL string) error {
+	for len(accessorIDs) > 0 {
+		n := len(accessorIDs)
+		if n > maxIdsPerReap {
+			n = maxIdsPerReap
+		}
+		req := &structs.ACLTokenDeleteRequest{
+			AccessorIDs: accessorIDs[:n],
+			WriteRequest: structs.WriteRequest{
+				Region:    c.srv.config.Region,
+				AuthToken: leaderACL,
+			},
+		}
+		accessorIDs = accessorIDs[n:]
+
+		var resp structs.GenericResponse
+		if err := c.srv.RPC("ACL.DeleteTokens", req, &resp); err != nil {
+			c.logger.Error("expired ACL token reap failed", "error", err)
+			return err
+		}
+	}
+	return nil
+}
+
 // allocGCEligible returns if the allocation is eligible to be garbage collected
 // according to its terminal status and its reschedule trackers
 func allocGCEligible(a *structs.Allocation, job *structs.Job, gcTime time.Time, thresholdIndex uint64) bool {
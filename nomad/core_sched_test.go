@@ -106,6 +106,83 @@ func TestCoreScheduler_EvalGC(t *testing.T) {
 	}
 }
 
+// Tests that the GC thresholds of the jobs and namespaces override the ones of
+// the server
+func TestCoreScheduler_EvalGC_Thresholds(t *testing.T) {
+	t.Parallel()
+	s1 := TestServer(t, nil)
+	defer s1.Shutdown()
+	testutil.WaitForLeader(t, s1.RPC)
+	require := require.New(t)
+
+	// COMPAT Remove in 0.6: Reset the FSM time table since we reconcile which sets index 0
+	s1.fsm.timetable.table = make([]TimeTableEntry, 1, 10)
+
+	// Insert a namespace keeping its evals longer than the server
+	state := s1.fsm.State()
+	ns := &structs.Namespace{
+		Name: "keep",
+		GC:   &structs.GCConfig{EvalThreshold: 10 * s1.config.EvalGCThreshold},
+	}
+	require.NoError(state.UpsertNamespaces(999, []*structs.Namespace{ns}))
+
+	// Insert a job of the namespace, a job overriding the threshold of the
+	// namespace, and a job keeping its allocs longer than its evals
+	nsJob := mock.Job()
+	nsJob.Namespace = ns.Name
+	overrideJob := mock.Job()
+	overrideJob.Namespace = ns.Name
+	overrideJob.GC = &structs.GCConfig{EvalThreshold: s1.config.EvalGCThreshold}
+	allocJob := mock.Job()
+	allocJob.GC = &structs.GCConfig{AllocThreshold: 10 * s1.config.EvalGCThreshold}
+
+	var evals []*structs.Evaluation
+	var allocs []*structs.Allocation
+	for i, job := range []*structs.Job{nsJob, overrideJob, allocJob} {
+		require.NoError(state.UpsertJob(uint64(1000+i), job))
+
+		eval := mock.Eval()
+		eval.Namespace = job.Namespace
+		eval.JobID = job.ID
+		eval.Status = structs.EvalStatusComplete
+		evals = append(evals, eval)
+
+		alloc := mock.Alloc()
+		alloc.Namespace = job.Namespace
+		alloc.EvalID = eval.ID
+		alloc.JobID = job.ID
+		alloc.Job = job
+		alloc.DesiredStatus = structs.AllocDesiredStatusStop
+		alloc.ClientStatus = structs.AllocClientStatusComplete
+		allocs = append(allocs, alloc)
+	}
+	require.NoError(state.UpsertEvals(1010, evals))
+	require.NoError(state.UpsertAllocs(1011, allocs))
+
+	// Update the time tables to make this work
+	tt := s1.fsm.TimeTable()
+	tt.Witness(2000, time.Now().UTC().Add(-1*s1.config.EvalGCThreshold))
+
+	// Attempt the GC
+	snap, err := state.Snapshot()
+	require.NoError(err)
+	core := NewCoreScheduler(s1, snap)
+	require.NoError(core.Process(s1.coreJobEval(structs.CoreJobEvalGC, 2000)))
+
+	// Only the evals and allocs of the job overriding the namespace should be
+	// gone
+	ws := memdb.NewWatchSet()
+	for i, shouldGC := range []bool{false, true, false} {
+		outE, err := state.EvalByID(ws, evals[i].ID)
+		require.NoError(err)
+		require.Equal(shouldGC, outE == nil, "eval %d", i)
+
+		outA, err := state.AllocByID(ws, allocs[i].ID)
+		require.NoError(err)
+		require.Equal(shouldGC, outA == nil, "alloc %d", i)
+	}
+}
+
 // Tests GC behavior on allocations being rescheduled
 func TestCoreScheduler_EvalGC_ReschedulingAllocs(t *testing.T) {
 	t.Parallel()
@@ -1816,6 +1893,54 @@ func TestCoreScheduler_DeploymentGC_Force(t *testing.T) {
 	}
 }
 
+func TestCoreScheduler_ExpiredACLTokenGC(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1, _ := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Insert expired and unexpired local and global tokens
+	state := s1.fsm.State()
+	expired := time.Now().UTC().Add(-time.Minute)
+	local1, local2 := mock.ACLToken(), mock.ACLToken()
+	local1.ExpirationTime = &expired
+	global1, global2 := mock.ACLToken(), mock.ACLToken()
+	global1.Global, global2.Global = true, true
+	global1.ExpirationTime = &expired
+	tokens := []*structs.ACLToken{local1, local2, global1, global2}
+	require.NoError(state.UpsertACLTokens(1000, tokens))
+
+	// Create a core scheduler
+	snap, err := state.Snapshot()
+	require.NoError(err)
+	core := NewCoreScheduler(s1, snap)
+
+	// Collect the local tokens
+	gc := s1.coreJobEval(structs.CoreJobLocalTokenExpiredGC, 2000)
+	require.NoError(core.Process(gc))
+
+	out, err := state.ACLTokenByAccessorID(nil, local1.AccessorID)
+	require.NoError(err)
+	require.Nil(out)
+	for _, token := range []*structs.ACLToken{local2, global1, global2} {
+		out, err := state.ACLTokenByAccessorID(nil, token.AccessorID)
+		require.NoError(err)
+		require.NotNil(out)
+	}
+
+	// Collect the global tokens
+	gc = s1.coreJobEval(structs.CoreJobGlobalTokenExpiredGC, 2000)
+	require.NoError(core.Process(gc))
+
+	out, err = state.ACLTokenByAccessorID(nil, global1.AccessorID)
+	require.NoError(err)
+	require.Nil(out)
+	out, err = state.ACLTokenByAccessorID(nil, global2.AccessorID)
+	require.NoError(err)
+	require.NotNil(out)
+}
+
 func TestCoreScheduler_PartitionEvalReap(t *testing.T) {
 	t.Parallel()
 	s1 := TestServer(t, nil)
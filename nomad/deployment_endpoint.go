@@ -158,6 +158,97 @@ func (d *Deployment) Pause(args *structs.DeploymentPauseRequest, reply *structs.
 	return d.srv.deploymentWatcher.PauseDeployment(args, reply)
 }
 
+// Run is used to start a pending deployment of a multiregion job
+func (d *Deployment) Run(args *structs.DeploymentRunRequest, reply *structs.DeploymentUpdateResponse) error {
+	if done, err := d.srv.forward("Deployment.Run", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "deployment", "run"}, time.Now())
+
+	// Check namespace submit-job permissions
+	if aclObj, err := d.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilitySubmitJob) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Validate the arguments
+	if args.DeploymentID == "" {
+		return fmt.Errorf("missing deployment ID")
+	}
+
+	// Lookup the deployment
+	snap, err := d.srv.fsm.State().Snapshot()
+	if err != nil {
+		return err
+	}
+
+	ws := memdb.NewWatchSet()
+	deploy, err := snap.DeploymentByID(ws, args.DeploymentID)
+	if err != nil {
+		return err
+	}
+	if deploy == nil {
+		return fmt.Errorf("deployment not found")
+	}
+
+	if !deploy.Active() {
+		return fmt.Errorf("can't run terminal deployment")
+	}
+	if !deploy.IsMultiregion {
+		return fmt.Errorf("can't run deployment of job that is not multiregion")
+	}
+
+	// Call into the deployment watcher
+	return d.srv.deploymentWatcher.RunDeployment(args, reply)
+}
+
+// Unblock is used to mark a blocked deployment of a multiregion job as
+// successful
+func (d *Deployment) Unblock(args *structs.DeploymentUnblockRequest, reply *structs.DeploymentUpdateResponse) error {
+	if done, err := d.srv.forward("Deployment.Unblock", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "deployment", "unblock"}, time.Now())
+
+	// Check namespace submit-job permissions
+	if aclObj, err := d.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilitySubmitJob) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Validate the arguments
+	if args.DeploymentID == "" {
+		return fmt.Errorf("missing deployment ID")
+	}
+
+	// Lookup the deployment
+	snap, err := d.srv.fsm.State().Snapshot()
+	if err != nil {
+		return err
+	}
+
+	ws := memdb.NewWatchSet()
+	deploy, err := snap.DeploymentByID(ws, args.DeploymentID)
+	if err != nil {
+		return err
+	}
+	if deploy == nil {
+		return fmt.Errorf("deployment not found")
+	}
+
+	if !deploy.Active() {
+		return fmt.Errorf("can't unblock terminal deployment")
+	}
+	if !deploy.IsMultiregion {
+		return fmt.Errorf("can't unblock deployment of job that is not multiregion")
+	}
+
+	// Call into the deployment watcher
+	return d.srv.deploymentWatcher.UnblockDeployment(args, reply)
+}
+
 // Promote is used to promote canaries in a deployment
 func (d *Deployment) Promote(args *structs.DeploymentPromoteRequest, reply *structs.DeploymentUpdateResponse) error {
 	if done, err := d.srv.forward("Deployment.Promote", args, args, reply); done {
@@ -255,9 +346,10 @@ func (d *Deployment) List(args *structs.DeploymentListRequest, reply *structs.De
 	defer metrics.MeasureSince([]string{"nomad", "deployment", "list"}, time.Now())
 
 	// Check namespace read-job permissions
-	if aclObj, err := d.srv.ResolveToken(args.AuthToken); err != nil {
+	aclObj, err := d.srv.ResolveToken(args.AuthToken)
+	if err != nil {
 		return err
-	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityReadJob) {
+	} else if !allowNsOp(aclObj, args.RequestNamespace(), acl.NamespaceCapabilityReadJob) {
 		return structs.ErrPermissionDenied
 	}
 
@@ -277,17 +369,31 @@ func (d *Deployment) List(args *structs.DeploymentListRequest, reply *structs.De
 			if err != nil {
 				return err
 			}
+			iter = namespaceACLFilter(iter, aclObj, args.RequestNamespace(), acl.NamespaceCapabilityReadJob,
+				func(raw interface{}) string {
+					return raw.(*structs.Deployment).Namespace
+				})
+
+			paginator, err := newPaginator(iter, args.QueryOptions,
+				func(raw interface{}) string {
+					return raw.(*structs.Deployment).ID
+				},
+				func(raw interface{}) (interface{}, error) {
+					return raw, nil
+				})
+			if err != nil {
+				return err
+			}
 
 			var deploys []*structs.Deployment
-			for {
-				raw := iter.Next()
-				if raw == nil {
-					break
-				}
-				deploy := raw.(*structs.Deployment)
-				deploys = append(deploys, deploy)
+			nextToken, err := paginator.Page(func(obj interface{}) {
+				deploys = append(deploys, obj.(*structs.Deployment))
+			})
+			if err != nil {
+				return err
 			}
 			reply.Deployments = deploys
+			reply.NextToken = nextToken
 
 			// Use the last index that affected the deployment table
 			index, err := state.Index("deployment")
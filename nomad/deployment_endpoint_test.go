@@ -390,6 +390,67 @@ func TestDeploymentEndpoint_Pause(t *testing.T) {
 	assert.Equal(dout.ModifyIndex, resp.DeploymentModifyIndex, "wrong modify index")
 }
 
+func TestDeploymentEndpoint_Run_Unblock(t *testing.T) {
+	t.Parallel()
+	s1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	assert := assert.New(t)
+
+	// Create a pending and a blocked deployment of a multiregion job and a
+	// deployment of a job that is not multiregion
+	j := mock.Job()
+	pending, blocked, single := mock.Deployment(), mock.Deployment(), mock.Deployment()
+	for _, d := range []*structs.Deployment{pending, blocked, single} {
+		d.JobID = j.ID
+	}
+	pending.IsMultiregion = true
+	pending.Status = structs.DeploymentStatusPending
+	blocked.IsMultiregion = true
+	blocked.Status = structs.DeploymentStatusBlocked
+	state := s1.fsm.State()
+
+	assert.Nil(state.UpsertJob(999, j), "UpsertJob")
+	assert.Nil(state.UpsertDeployment(1000, pending), "UpsertDeployment")
+	assert.Nil(state.UpsertDeployment(1001, blocked), "UpsertDeployment")
+	assert.Nil(state.UpsertDeployment(1002, single), "UpsertDeployment")
+
+	// Run the pending deployment
+	runReq := &structs.DeploymentRunRequest{
+		DeploymentID: pending.ID,
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.DeploymentUpdateResponse
+	assert.Nil(msgpackrpc.CallWithCodec(codec, "Deployment.Run", runReq, &resp), "RPC")
+	assert.NotEmpty(resp.EvalID, "should create eval")
+
+	dout, err := state.DeploymentByID(nil, pending.ID)
+	assert.Nil(err)
+	assert.Equal(structs.DeploymentStatusRunning, dout.Status)
+
+	// Unblock the blocked deployment
+	unblockReq := &structs.DeploymentUnblockRequest{
+		DeploymentID: blocked.ID,
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	resp = structs.DeploymentUpdateResponse{}
+	assert.Nil(msgpackrpc.CallWithCodec(codec, "Deployment.Unblock", unblockReq, &resp), "RPC")
+
+	dout, err = state.DeploymentByID(nil, blocked.ID)
+	assert.Nil(err)
+	assert.Equal(structs.DeploymentStatusSuccessful, dout.Status)
+	assert.Equal(dout.ModifyIndex, resp.DeploymentModifyIndex)
+
+	// Deployments of jobs that are not multiregion can't be unblocked
+	unblockReq.DeploymentID = single.ID
+	err = msgpackrpc.CallWithCodec(codec, "Deployment.Unblock", unblockReq, &resp)
+	assert.Error(err)
+	assert.Contains(err.Error(), "not multiregion")
+}
+
 func TestDeploymentEndpoint_Pause_ACL(t *testing.T) {
 	t.Parallel()
 	s1, _ := TestACLServer(t, func(c *Config) {
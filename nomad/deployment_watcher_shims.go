@@ -53,3 +53,63 @@ func (d *deploymentWatcherRaftShim) UpdateAllocDesiredTransition(req *structs.Al
 	fsmErrIntf, index, raftErr := d.apply(structs.AllocUpdateDesiredTransitionRequestType, req)
 	return d.convertApplyErrors(fsmErrIntf, index, raftErr)
 }
+
+// deploymentWatcherMultiregionShim is the shim that provides the methods used
+// by the deployment watcher to coordinate the deployments of multiregion jobs
+// with their peer regions. Requests are forwarded to the peer regions and
+// authenticated with the replication token.
+type deploymentWatcherMultiregionShim struct {
+	srv *Server
+}
+
+func (d *deploymentWatcherMultiregionShim) writeRequest(region, namespace string) structs.WriteRequest {
+	return structs.WriteRequest{
+		Region:    region,
+		Namespace: namespace,
+		AuthToken: d.srv.ReplicationToken(),
+	}
+}
+
+func (d *deploymentWatcherMultiregionShim) LatestDeployment(region, namespace, jobID string) (*structs.Deployment, error) {
+	args := &structs.JobSpecificRequest{
+		JobID: jobID,
+		QueryOptions: structs.QueryOptions{
+			Region:    region,
+			Namespace: namespace,
+			AuthToken: d.srv.ReplicationToken(),
+		},
+	}
+	var resp structs.SingleDeploymentResponse
+	if err := d.srv.RPC("Job.LatestDeployment", args, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Deployment, nil
+}
+
+func (d *deploymentWatcherMultiregionShim) RunDeployment(region, namespace, deploymentID string) error {
+	args := &structs.DeploymentRunRequest{
+		DeploymentID: deploymentID,
+		WriteRequest: d.writeRequest(region, namespace),
+	}
+	var resp structs.DeploymentUpdateResponse
+	return d.srv.RPC("Deployment.Run", args, &resp)
+}
+
+func (d *deploymentWatcherMultiregionShim) UnblockDeployment(region, namespace, deploymentID string) error {
+	args := &structs.DeploymentUnblockRequest{
+		DeploymentID: deploymentID,
+		WriteRequest: d.writeRequest(region, namespace),
+	}
+	var resp structs.DeploymentUpdateResponse
+	return d.srv.RPC("Deployment.Unblock", args, &resp)
+}
+
+func (d *deploymentWatcherMultiregionShim) FailDeployment(region, namespace, deploymentID, peerRegion string) error {
+	args := &structs.DeploymentFailRequest{
+		DeploymentID: deploymentID,
+		PeerRegion:   peerRegion,
+		WriteRequest: d.writeRequest(region, namespace),
+	}
+	var resp structs.DeploymentUpdateResponse
+	return d.srv.RPC("Deployment.Fail", args, &resp)
+}
@@ -21,6 +21,14 @@ const (
 	perJobEvalBatchPeriod = 1 * time.Second
 )
 
+var (
+	// multiregionSyncInterval is the interval at which the deployment of a
+	// multiregion job checks the deployments of its peer regions while it is
+	// blocked. Changes in the peer regions do not trigger the watcher so they
+	// are polled.
+	multiregionSyncInterval = 10 * time.Second
+)
+
 var (
 	// allowRescheduleTransition is the transition that allows failed
 	// allocations part of a deployment to be rescheduled. We create a one off
@@ -50,6 +58,11 @@ type deploymentTriggers interface {
 	// upsertDeploymentAllocHealth is used to set the health of allocations in a
 	// deployment
 	upsertDeploymentAllocHealth(req *structs.ApplyDeploymentAllocHealthRequest) (uint64, error)
+
+	// multiregionEndpoints returns the endpoints used to coordinate the
+	// deployments of a multiregion job with its peer regions or nil if the
+	// deployments are not coordinated
+	multiregionEndpoints() DeploymentMultiregionEndpoints
 }
 
 // deploymentWatcher is used to watch a single deployment and trigger the
@@ -296,11 +309,59 @@ func (w *deploymentWatcher) PauseDeployment(
 	return nil
 }
 
+func (w *deploymentWatcher) RunDeployment(
+	req *structs.DeploymentRunRequest,
+	resp *structs.DeploymentUpdateResponse) error {
+
+	if w.getDeployment().Status != structs.DeploymentStatusPending {
+		return fmt.Errorf("deployment is not pending")
+	}
+
+	// Commit the change and create an evaluation to start placing
+	update := w.getDeploymentStatusUpdate(structs.DeploymentStatusRunning, structs.DeploymentStatusDescriptionRunning)
+	eval := w.getEval()
+	i, err := w.upsertDeploymentStatusUpdate(update, eval, nil)
+	if err != nil {
+		return err
+	}
+
+	// Build the response
+	resp.EvalID = eval.ID
+	resp.EvalCreateIndex = i
+	resp.DeploymentModifyIndex = i
+	resp.Index = i
+	return nil
+}
+
+func (w *deploymentWatcher) UnblockDeployment(
+	req *structs.DeploymentUnblockRequest,
+	resp *structs.DeploymentUpdateResponse) error {
+
+	if w.getDeployment().Status != structs.DeploymentStatusBlocked {
+		return fmt.Errorf("deployment is not blocked")
+	}
+
+	// Commit the change
+	update := w.getDeploymentStatusUpdate(structs.DeploymentStatusSuccessful, structs.DeploymentStatusDescriptionSuccessful)
+	i, err := w.upsertDeploymentStatusUpdate(update, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	// Build the response
+	resp.DeploymentModifyIndex = i
+	resp.Index = i
+	return nil
+}
+
 func (w *deploymentWatcher) FailDeployment(
 	req *structs.DeploymentFailRequest,
 	resp *structs.DeploymentUpdateResponse) error {
 
 	status, desc := structs.DeploymentStatusFailed, structs.DeploymentStatusDescriptionFailedByUser
+	if req.PeerRegion != "" {
+		desc = structs.DeploymentStatusDescriptionFailedByPeer
+	}
 
 	// Determine if we should rollback
 	rollback := false
@@ -345,6 +406,49 @@ func (w *deploymentWatcher) FailDeployment(
 	return nil
 }
 
+// autoPromoteDeployment promotes the deployment once all of its canaries are
+// healthy. Canaries are only marked healthy after being healthy for the
+// min_healthy_time of their group. The deployment is only promoted if every
+// group with canaries has auto_promote set, since the job version is promoted
+// as a whole.
+func (w *deploymentWatcher) autoPromoteDeployment(allocs []*structs.AllocListStub) error {
+	d := w.getDeployment()
+	if !d.HasPlacedCanaries() || !d.RequiresPromotion() {
+		return nil
+	}
+
+	healthy := make(map[string]struct{}, len(allocs))
+	for _, alloc := range allocs {
+		if alloc.DeploymentStatus.IsHealthy() {
+			healthy[alloc.ID] = struct{}{}
+		}
+	}
+
+	for _, state := range d.TaskGroups {
+		if state.DesiredCanaries == 0 {
+			continue
+		}
+		if !state.AutoPromote || len(state.PlacedCanaries) < state.DesiredCanaries {
+			return nil
+		}
+		for _, id := range state.PlacedCanaries {
+			if _, ok := healthy[id]; !ok {
+				return nil
+			}
+		}
+	}
+
+	w.logger.Debug("auto promoting deployment")
+	_, err := w.upsertDeploymentPromotion(&structs.ApplyDeploymentPromoteRequest{
+		DeploymentPromoteRequest: structs.DeploymentPromoteRequest{
+			DeploymentID: d.GetID(),
+			All:          true,
+		},
+		Eval: w.getEval(),
+	})
+	return err
+}
+
 // StopWatch stops watching the deployment. This should be called whenever a
 // deployment is completed or the watcher is no longer needed.
 func (w *deploymentWatcher) StopWatch() {
@@ -372,6 +476,16 @@ func (w *deploymentWatcher) watch() {
 		deadlineTimer = time.NewTimer(currentDeadline.Sub(time.Now()))
 	}
 
+	// The deployment of a multiregion job is coordinated with the
+	// deployments of its peer regions
+	var multiregionCh <-chan time.Time
+	if w.isMultiregion() {
+		multiregionTicker := time.NewTicker(multiregionSyncInterval)
+		defer multiregionTicker.Stop()
+		multiregionCh = multiregionTicker.C
+		w.syncMultiregion()
+	}
+
 	allocIndex := uint64(1)
 	var updates *allocUpdates
 
@@ -382,6 +496,8 @@ FAIL:
 		select {
 		case <-w.ctx.Done():
 			return
+		case <-multiregionCh:
+			w.syncMultiregion()
 		case <-deadlineTimer.C:
 			// We have hit the progress deadline so fail the deployment. We need
 			// to determine whether we should roll back the job by inspecting
@@ -430,6 +546,10 @@ FAIL:
 				}
 			}
 
+			if multiregionCh != nil {
+				w.syncMultiregion()
+			}
+
 		case updates = <-w.getAllocsCh(allocIndex):
 			if err := updates.err; err != nil {
 				if err == context.Canceled || w.ctx.Err() == context.Canceled {
@@ -460,6 +580,11 @@ FAIL:
 				break FAIL
 			}
 
+			// If permitted, automatically promote this canary deployment
+			if err := w.autoPromoteDeployment(updates.allocs); err != nil {
+				w.logger.Error("failed to auto promote deployment", "error", err)
+			}
+
 			// Create an eval to push the deployment along
 			if res.createEval || len(res.allowReplacements) != 0 {
 				w.createBatchedUpdate(res.allowReplacements, allocIndex)
@@ -497,6 +622,101 @@ FAIL:
 	if _, err := w.upsertDeploymentStatusUpdate(u, e, j); err != nil {
 		w.logger.Error("failed to update deployment status", "error", err)
 	}
+
+	// Fail the deployments of the peer regions if requested
+	if w.isMultiregion() && w.j.Multiregion.OnFailure() == structs.MultiregionOnFailureFailAll {
+		w.failPeers()
+	}
+}
+
+// isMultiregion returns whether the deployment is the deployment of a
+// multiregion job that is coordinated with its peer regions.
+func (w *deploymentWatcher) isMultiregion() bool {
+	return w.getDeployment().IsMultiregion && w.j.IsMultiregion() && w.multiregionEndpoints() != nil
+}
+
+// syncMultiregion coordinates a blocked deployment of a multiregion job with
+// its peer regions. It starts the pending deployment of the next region in the
+// rollout and, once the deployments of every region are complete, marks them
+// all as successful.
+func (w *deploymentWatcher) syncMultiregion() {
+	d := w.getDeployment()
+	if d.Status != structs.DeploymentStatusBlocked {
+		return
+	}
+
+	endpoints := w.multiregionEndpoints()
+	mr := w.j.Multiregion
+
+	// Start the deployment of the next region in the rollout
+	if next := mr.NextRegion(w.j.Region); next != "" {
+		peer, err := endpoints.LatestDeployment(next, w.j.Namespace, w.j.ID)
+		if err != nil {
+			w.logger.Error("failed to lookup peer deployment", "region", next, "error", err)
+			return
+		}
+		if peer != nil && peer.IsMultiregion && peer.Status == structs.DeploymentStatusPending {
+			w.logger.Debug("starting peer deployment", "region", next, "peer_deployment_id", peer.ID)
+			if err := endpoints.RunDeployment(next, w.j.Namespace, peer.ID); err != nil {
+				w.logger.Error("failed to start peer deployment", "region", next, "error", err)
+				return
+			}
+		}
+	}
+
+	// Wait for the deployments of every peer region to complete
+	var blocked []*structs.Deployment
+	regions := make(map[string]string)
+	for _, region := range mr.Peers(w.j.Region) {
+		peer, err := endpoints.LatestDeployment(region, w.j.Namespace, w.j.ID)
+		if err != nil {
+			w.logger.Error("failed to lookup peer deployment", "region", region, "error", err)
+			return
+		}
+		if peer == nil || !peer.IsMultiregion {
+			return
+		}
+		switch peer.Status {
+		case structs.DeploymentStatusBlocked:
+			blocked = append(blocked, peer)
+			regions[peer.ID] = region
+		case structs.DeploymentStatusSuccessful:
+		default:
+			return
+		}
+	}
+
+	for _, peer := range blocked {
+		if err := endpoints.UnblockDeployment(regions[peer.ID], w.j.Namespace, peer.ID); err != nil {
+			w.logger.Error("failed to unblock peer deployment", "region", regions[peer.ID], "error", err)
+			return
+		}
+	}
+
+	w.logger.Debug("all peer deployments complete, unblocking deployment")
+	update := w.getDeploymentStatusUpdate(structs.DeploymentStatusSuccessful, structs.DeploymentStatusDescriptionSuccessful)
+	if _, err := w.upsertDeploymentStatusUpdate(update, nil, nil); err != nil {
+		w.logger.Error("failed to update deployment status", "error", err)
+	}
+}
+
+// failPeers fails the active deployments of the peer regions of a multiregion
+// job.
+func (w *deploymentWatcher) failPeers() {
+	endpoints := w.multiregionEndpoints()
+	for _, region := range w.j.Multiregion.Peers(w.j.Region) {
+		peer, err := endpoints.LatestDeployment(region, w.j.Namespace, w.j.ID)
+		if err != nil {
+			w.logger.Error("failed to lookup peer deployment", "region", region, "error", err)
+			continue
+		}
+		if peer == nil || !peer.IsMultiregion || !peer.Active() {
+			continue
+		}
+		if err := endpoints.FailDeployment(region, w.j.Namespace, peer.ID, w.j.Region); err != nil {
+			w.logger.Error("failed to fail peer deployment", "region", region, "error", err)
+		}
+	}
 }
 
 // allocUpdateResult is used to return the desired actions given the newest set
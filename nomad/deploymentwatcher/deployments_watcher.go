@@ -54,6 +54,26 @@ type DeploymentRaftEndpoints interface {
 	UpdateAllocDesiredTransition(req *structs.AllocUpdateDesiredTransitionRequest) (uint64, error)
 }
 
+// DeploymentMultiregionEndpoints exposes the deployment watcher to the set of
+// functions used to coordinate the deployments of a multiregion job with the
+// deployments of its peer regions.
+type DeploymentMultiregionEndpoints interface {
+	// LatestDeployment returns the latest deployment of the job in the given
+	// region or nil if there is none
+	LatestDeployment(region, namespace, jobID string) (*structs.Deployment, error)
+
+	// RunDeployment starts a pending deployment in the given region
+	RunDeployment(region, namespace, deploymentID string) error
+
+	// UnblockDeployment marks a blocked deployment in the given region as
+	// successful
+	UnblockDeployment(region, namespace, deploymentID string) error
+
+	// FailDeployment fails the deployment in the given region because the
+	// deployment in the peer region failed
+	FailDeployment(region, namespace, deploymentID, peerRegion string) error
+}
+
 // Watcher is used to watch deployments and their allocations created
 // by the scheduler and trigger the scheduler when allocation health
 // transitions.
@@ -72,6 +92,11 @@ type Watcher struct {
 	// deployments watcher
 	raft DeploymentRaftEndpoints
 
+	// multiregion contains the set of endpoints used to coordinate the
+	// deployments of multiregion jobs with their peer regions. It may be nil
+	// in which case deployments are not coordinated.
+	multiregion DeploymentMultiregionEndpoints
+
 	// state is the state that is watched for state changes.
 	state *state.StateStore
 
@@ -92,11 +117,12 @@ type Watcher struct {
 // NewDeploymentsWatcher returns a deployments watcher that is used to watch
 // deployments and trigger the scheduler as needed.
 func NewDeploymentsWatcher(logger log.Logger,
-	raft DeploymentRaftEndpoints, stateQueriesPerSecond float64,
-	updateBatchDuration time.Duration) *Watcher {
+	raft DeploymentRaftEndpoints, multiregion DeploymentMultiregionEndpoints,
+	stateQueriesPerSecond float64, updateBatchDuration time.Duration) *Watcher {
 
 	return &Watcher{
 		raft:                raft,
+		multiregion:         multiregion,
 		queryLimiter:        rate.NewLimiter(rate.Limit(stateQueriesPerSecond), 100),
 		updateBatchDuration: updateBatchDuration,
 		logger:              logger.Named("deployments_watcher"),
@@ -358,6 +384,27 @@ func (w *Watcher) FailDeployment(req *structs.DeploymentFailRequest, resp *struc
 	return watcher.FailDeployment(req, resp)
 }
 
+// RunDeployment is used to start a pending deployment of a multiregion job.
+func (w *Watcher) RunDeployment(req *structs.DeploymentRunRequest, resp *structs.DeploymentUpdateResponse) error {
+	watcher, err := w.getOrCreateWatcher(req.DeploymentID)
+	if err != nil {
+		return err
+	}
+
+	return watcher.RunDeployment(req, resp)
+}
+
+// UnblockDeployment is used to mark a blocked deployment of a multiregion job
+// as successful.
+func (w *Watcher) UnblockDeployment(req *structs.DeploymentUnblockRequest, resp *structs.DeploymentUpdateResponse) error {
+	watcher, err := w.getOrCreateWatcher(req.DeploymentID)
+	if err != nil {
+		return err
+	}
+
+	return watcher.UnblockDeployment(req, resp)
+}
+
 // createUpdate commits the given allocation desired transition and evaluation
 // to Raft but batches the commit with other calls.
 func (w *Watcher) createUpdate(allocs map[string]*structs.DesiredTransition, eval *structs.Evaluation) (uint64, error) {
@@ -392,3 +439,9 @@ func (w *Watcher) upsertDeploymentPromotion(req *structs.ApplyDeploymentPromoteR
 func (w *Watcher) upsertDeploymentAllocHealth(req *structs.ApplyDeploymentAllocHealthRequest) (uint64, error) {
 	return w.raft.UpdateDeploymentAllocHealth(req)
 }
+
+// multiregionEndpoints returns the endpoints used to coordinate the
+// deployments of multiregion jobs or nil if they are not coordinated.
+func (w *Watcher) multiregionEndpoints() DeploymentMultiregionEndpoints {
+	return w.multiregion
+}
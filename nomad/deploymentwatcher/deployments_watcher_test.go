@@ -19,7 +19,7 @@ import (
 
 func testDeploymentWatcher(t *testing.T, qps float64, batchDur time.Duration) (*Watcher, *mockBackend) {
 	m := newMockBackend(t)
-	w := NewDeploymentsWatcher(testlog.HCLogger(t), m, qps, batchDur)
+	w := NewDeploymentsWatcher(testlog.HCLogger(t), m, nil, qps, batchDur)
 	return w, m
 }
 
@@ -692,6 +692,104 @@ func TestWatcher_FailDeployment_Running(t *testing.T) {
 	m.AssertCalled(t, "UpdateDeploymentStatus", mocker.MatchedBy(matcher))
 }
 
+// Test running a pending deployment
+func TestWatcher_RunDeployment_Pending(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	w, m := defaultTestDeploymentWatcher(t)
+
+	// Create a job and a deployment
+	j := mock.Job()
+	d := mock.Deployment()
+	d.JobID = j.ID
+	d.Status = structs.DeploymentStatusPending
+	d.IsMultiregion = true
+	require.Nil(m.state.UpsertJob(m.nextIndex(), j), "UpsertJob")
+	require.Nil(m.state.UpsertDeployment(m.nextIndex(), d), "UpsertDeployment")
+
+	// require that we get a call to UpsertDeploymentStatusUpdate
+	matchConfig := &matchDeploymentStatusUpdateConfig{
+		DeploymentID:      d.ID,
+		Status:            structs.DeploymentStatusRunning,
+		StatusDescription: structs.DeploymentStatusDescriptionRunning,
+		Eval:              true,
+	}
+	matcher := matchDeploymentStatusUpdateRequest(matchConfig)
+	m.On("UpdateDeploymentStatus", mocker.MatchedBy(matcher)).Return(nil)
+
+	w.SetEnabled(true, m.state)
+	testutil.WaitForResult(func() (bool, error) { return 1 == len(w.watchers), nil },
+		func(err error) { require.Equal(1, len(w.watchers), "Should have 1 deployment") })
+
+	// Call RunDeployment
+	req := &structs.DeploymentRunRequest{
+		DeploymentID: d.ID,
+	}
+	var resp structs.DeploymentUpdateResponse
+	require.Nil(w.RunDeployment(req, &resp), "RunDeployment")
+	require.NotEmpty(resp.EvalID)
+
+	require.Equal(1, len(w.watchers), "Deployment should still be active")
+	m.AssertCalled(t, "UpdateDeploymentStatus", mocker.MatchedBy(matcher))
+}
+
+// Tests that the blocked deployment of a multiregion job starts the next
+// region in the rollout and is unblocked once every region is complete
+func TestDeploymentWatcher_Multiregion_Unblock(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	m := newMockBackend(t)
+	mr := newMockMultiregion()
+	w := NewDeploymentsWatcher(testlog.HCLogger(t), m, mr, LimitStateQueriesPerSecond, CrossDeploymentUpdateBatchDuration)
+
+	// Create a multiregion job deployed one region at a time
+	j := mock.Job()
+	j.Multiregion = &structs.Multiregion{
+		Strategy: &structs.MultiregionStrategy{MaxParallel: 1},
+		Regions:  []*structs.MultiregionRegion{{Name: "global"}, {Name: "west"}},
+	}
+	d := structs.NewDeployment(j)
+	d.Status = structs.DeploymentStatusBlocked
+	require.Nil(m.state.UpsertJob(m.nextIndex(), j), "UpsertJob")
+	require.Nil(m.state.UpsertDeployment(m.nextIndex(), d), "UpsertDeployment")
+
+	peer := structs.NewDeployment(j)
+	peer.Status = structs.DeploymentStatusPending
+	mr.setDeployment("west", peer)
+
+	matchConfig := &matchDeploymentStatusUpdateConfig{
+		DeploymentID:      d.ID,
+		Status:            structs.DeploymentStatusSuccessful,
+		StatusDescription: structs.DeploymentStatusDescriptionSuccessful,
+	}
+	matcher := matchDeploymentStatusUpdateRequest(matchConfig)
+	m.On("UpdateDeploymentStatus", mocker.MatchedBy(matcher)).Return(nil)
+
+	// The pending deployment of the next region is started
+	w.SetEnabled(true, m.state)
+	testutil.WaitForResult(func() (bool, error) {
+		run, _, _ := mr.calls()
+		return len(run) == 1, fmt.Errorf("peer deployment not started: %v", run)
+	}, func(err error) { require.NoError(err) })
+	m.AssertNumberOfCalls(t, "UpdateDeploymentStatus", 0)
+
+	// Once the peer region completes, both deployments are unblocked
+	peer = peer.Copy()
+	peer.Status = structs.DeploymentStatusBlocked
+	mr.setDeployment("west", peer)
+	require.Nil(m.state.UpsertDeployment(m.nextIndex(), d.Copy()), "UpsertDeployment")
+
+	testutil.WaitForResult(func() (bool, error) {
+		_, unblocked, _ := mr.calls()
+		if len(unblocked) != 1 || unblocked[0] != "west" {
+			return false, fmt.Errorf("peer deployment not unblocked: %v", unblocked)
+		}
+		ds, _ := m.state.DeploymentByID(nil, d.ID)
+		return ds.Status == structs.DeploymentStatusSuccessful, fmt.Errorf("deployment not unblocked: %v", ds.Status)
+	}, func(err error) { require.NoError(err) })
+	m.AssertCalled(t, "UpdateDeploymentStatus", mocker.MatchedBy(matcher))
+}
+
 // Tests that the watcher properly watches for allocation changes and takes the
 // proper actions
 func TestDeploymentWatcher_Watch_NoProgressDeadline(t *testing.T) {
@@ -1068,6 +1166,75 @@ func TestDeploymentWatcher_Watch_ProgressDeadline_Canaries(t *testing.T) {
 	})
 }
 
+// Test that a deployment with auto_promote is promoted once its canaries are
+// healthy
+func TestDeploymentWatcher_AutoPromote(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	w, m := testDeploymentWatcher(t, 1000.0, 1*time.Millisecond)
+
+	// Create a job, canary alloc, and a deployment
+	j := mock.Job()
+	j.TaskGroups[0].Update = structs.DefaultUpdateStrategy.Copy()
+	j.TaskGroups[0].Update.Canary = 1
+	j.TaskGroups[0].Update.AutoPromote = true
+	j.TaskGroups[0].Update.ProgressDeadline = 0
+	d := mock.Deployment()
+	d.JobID = j.ID
+	d.StatusDescription = structs.DeploymentStatusDescriptionRunningNeedsPromotion
+	a := mock.Alloc()
+	d.TaskGroups[a.TaskGroup].DesiredCanaries = 1
+	d.TaskGroups[a.TaskGroup].AutoPromote = true
+	d.TaskGroups[a.TaskGroup].PlacedCanaries = []string{a.ID}
+	a.DeploymentID = d.ID
+	a.DeploymentStatus = &structs.AllocDeploymentStatus{Canary: true}
+	require.Nil(m.state.UpsertJob(m.nextIndex(), j), "UpsertJob")
+	require.Nil(m.state.UpsertDeployment(m.nextIndex(), d), "UpsertDeployment")
+	require.Nil(m.state.UpsertAllocs(m.nextIndex(), []*structs.Allocation{a}), "UpsertAllocs")
+
+	// require that we get a call to UpsertDeploymentPromotion
+	matchConfig := &matchDeploymentPromoteRequestConfig{
+		Promotion: &structs.DeploymentPromoteRequest{
+			DeploymentID: d.ID,
+			All:          true,
+		},
+		Eval: true,
+	}
+	matcher := matchDeploymentPromoteRequest(matchConfig)
+	m.On("UpdateDeploymentPromotion", mocker.MatchedBy(matcher)).Return(nil)
+	m1 := matchUpdateAllocDesiredTransitions([]string{d.ID})
+	m.On("UpdateAllocDesiredTransition", mocker.MatchedBy(m1)).Return(nil)
+
+	w.SetEnabled(true, m.state)
+	testutil.WaitForResult(func() (bool, error) { return 1 == len(w.watchers), nil },
+		func(err error) { require.Equal(1, len(w.watchers), "Should have 1 deployment") })
+
+	// The canary is not healthy yet so the deployment is not promoted
+	time.Sleep(100 * time.Millisecond)
+	m.AssertNotCalled(t, "UpdateDeploymentPromotion", mocker.MatchedBy(matcher))
+
+	// Mark the canary healthy
+	a2 := a.Copy()
+	a2.DeploymentStatus.Healthy = helper.BoolToPtr(true)
+	a2.DeploymentStatus.Timestamp = time.Now()
+	require.Nil(m.state.UpdateAllocsFromClient(m.nextIndex(), []*structs.Allocation{a2}))
+
+	// The deployment is promoted
+	testutil.WaitForResult(func() (bool, error) {
+		dout, err := m.state.DeploymentByID(nil, d.ID)
+		if err != nil {
+			return false, err
+		}
+		if !dout.TaskGroups[a.TaskGroup].Promoted {
+			return false, fmt.Errorf("deployment not promoted")
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatal(err)
+	})
+	m.AssertCalled(t, "UpdateDeploymentPromotion", mocker.MatchedBy(matcher))
+}
+
 // Test that a promoted deployment with alloc healthy updates create
 // evals to move the deployment forward
 func TestDeploymentWatcher_PromotedCanary_UpdatedAllocs(t *testing.T) {
@@ -265,3 +265,64 @@ func matchDeploymentAllocHealthRequest(c *matchDeploymentAllocHealthRequestConfi
 		return true
 	}
 }
+
+// mockMultiregion is a DeploymentMultiregionEndpoints backed by a set of peer
+// deployments keyed by region.
+type mockMultiregion struct {
+	deployments map[string]*structs.Deployment
+	run         []string
+	unblocked   []string
+	failed      []string
+	l           sync.Mutex
+}
+
+func newMockMultiregion() *mockMultiregion {
+	return &mockMultiregion{
+		deployments: make(map[string]*structs.Deployment),
+	}
+}
+
+func (m *mockMultiregion) setDeployment(region string, d *structs.Deployment) {
+	m.l.Lock()
+	defer m.l.Unlock()
+	m.deployments[region] = d
+}
+
+func (m *mockMultiregion) calls() (run, unblocked, failed []string) {
+	m.l.Lock()
+	defer m.l.Unlock()
+	return append([]string(nil), m.run...), append([]string(nil), m.unblocked...), append([]string(nil), m.failed...)
+}
+
+func (m *mockMultiregion) LatestDeployment(region, namespace, jobID string) (*structs.Deployment, error) {
+	m.l.Lock()
+	defer m.l.Unlock()
+	if d, ok := m.deployments[region]; ok {
+		return d.Copy(), nil
+	}
+	return nil, nil
+}
+
+func (m *mockMultiregion) RunDeployment(region, namespace, deploymentID string) error {
+	m.l.Lock()
+	defer m.l.Unlock()
+	m.run = append(m.run, region)
+	m.deployments[region].Status = structs.DeploymentStatusRunning
+	return nil
+}
+
+func (m *mockMultiregion) UnblockDeployment(region, namespace, deploymentID string) error {
+	m.l.Lock()
+	defer m.l.Unlock()
+	m.unblocked = append(m.unblocked, region)
+	m.deployments[region].Status = structs.DeploymentStatusSuccessful
+	return nil
+}
+
+func (m *mockMultiregion) FailDeployment(region, namespace, deploymentID, peerRegion string) error {
+	m.l.Lock()
+	defer m.l.Unlock()
+	m.failed = append(m.failed, region)
+	m.deployments[region].Status = structs.DeploymentStatusFailed
+	return nil
+}
@@ -66,9 +66,9 @@ func (n *drainingNode) IsDone() (bool, error) {
 	}
 
 	for _, alloc := range allocs {
-		// System jobs are only stopped after a node is done draining
+		// System and sysbatch jobs are only stopped after a node is done draining
 		// everything else, so ignore them here.
-		if alloc.Job.Type == structs.JobTypeSystem {
+		if alloc.Job.IsSystemType() {
 			continue
 		}
 
@@ -108,8 +108,8 @@ func (n *drainingNode) RemainingAllocs() ([]*structs.Allocation, error) {
 			continue
 		}
 
-		// Skip system if configured to
-		if alloc.Job.Type == structs.JobTypeSystem && ignoreSystem {
+		// Skip system and sysbatch if configured to
+		if alloc.Job.IsSystemType() && ignoreSystem {
 			continue
 		}
 
@@ -139,7 +139,7 @@ func (n *drainingNode) DrainingJobs() ([]structs.NamespacedID, error) {
 	jobIDs := make(map[structs.NamespacedID]struct{})
 	var jobs []structs.NamespacedID
 	for _, alloc := range allocs {
-		if alloc.TerminalStatus() || alloc.Job.Type == structs.JobTypeSystem {
+		if alloc.TerminalStatus() || alloc.Job.IsSystemType() {
 			continue
 		}
 
@@ -206,8 +206,8 @@ func (w *drainingJobWatcher) watch() {
 				continue
 			}
 
-			// Ignore any system jobs
-			if job.Type == structs.JobTypeSystem {
+			// Ignore any system and sysbatch jobs
+			if job.IsSystemType() {
 				w.deregisterJob(job.ID, job.Namespace)
 				continue
 			}
@@ -0,0 +1,321 @@
+package nomad
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// rootKeySize is the size in bytes of the AES-256 root keys
+	rootKeySize = 32
+
+	// dataKeySize is the size in bytes of the AES-256 data keys each
+	// variable is encrypted with
+	dataKeySize = 32
+
+	// KeyringEncryptionKeySize is the size in bytes of the key the root keys
+	// are wrapped with
+	KeyringEncryptionKeySize = 32
+)
+
+// Encrypter encrypts and decrypts the items of variables with the root keys
+// stored in the state store. Each variable is encrypted with its own data
+// key, stored along the variable wrapped by the root key. The root keys are
+// themselves wrapped by the keyring encryption key of the servers, so that
+// neither the state nor the snapshots hold the key material in plain text.
+type Encrypter struct {
+	srv *Server
+
+	// kek is the keyring encryption key the root keys are wrapped with
+	kek []byte
+
+	// keys caches the unwrapped material of the root keys by ID
+	keys     map[string][]byte
+	keysLock sync.Mutex
+}
+
+// NewEncrypter returns an encrypter wrapping the root keys with the given
+// keyring encryption key.
+func NewEncrypter(srv *Server, kek []byte) (*Encrypter, error) {
+	if len(kek) == 0 {
+		return nil, fmt.Errorf("a keyring encryption key is required")
+	}
+	if len(kek) != KeyringEncryptionKeySize {
+		return nil, fmt.Errorf("keyring encryption key must be %d bytes, not %d", KeyringEncryptionKeySize, len(kek))
+	}
+	return &Encrypter{
+		srv:  srv,
+		kek:  kek,
+		keys: make(map[string][]byte),
+	}, nil
+}
+
+// Encrypt encrypts the items of a variable with a new data key, wrapped by
+// the active root key.
+func (e *Encrypter) Encrypt(v *structs.VariableDecrypted) (*structs.VariableEncrypted, error) {
+	key, err := e.srv.fsm.State().ActiveRootKey(nil)
+	if err != nil {
+		return nil, err
+	}
+	if key == nil {
+		return nil, fmt.Errorf("no active root key to encrypt variables with")
+	}
+	rootKey, err := e.rootKeyMaterial(key)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := json.Marshal(v.Items)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode variable items: %v", err)
+	}
+
+	dataKey := make([]byte, dataKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, fmt.Errorf("failed to generate data key: %v", err)
+	}
+	data, err := encryptData(dataKey, plaintext)
+	if err != nil {
+		return nil, err
+	}
+	wrapped, err := encryptData(rootKey, dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &structs.VariableEncrypted{
+		VariableMetadata: v.VariableMetadata,
+		Data:             data,
+		KeyID:            key.KeyID,
+		WrappedDataKey:   wrapped,
+	}, nil
+}
+
+// Decrypt decrypts the items of a variable with the root key it was
+// encrypted with, looked up in the given state.
+func (e *Encrypter) Decrypt(store *state.StateStore, v *structs.VariableEncrypted) (*structs.VariableDecrypted, error) {
+	dataKey, err := e.dataKey(store, v)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := decryptData(dataKey, v.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &structs.VariableDecrypted{VariableMetadata: v.VariableMetadata}
+	if err := json.Unmarshal(plaintext, &out.Items); err != nil {
+		return nil, fmt.Errorf("failed to decode variable items: %v", err)
+	}
+	return out, nil
+}
+
+// Rewrap returns a copy of the variable with its data key wrapped by the
+// given root key.
+func (e *Encrypter) Rewrap(store *state.StateStore, v *structs.VariableEncrypted, key *structs.RootKey) (*structs.VariableEncrypted, error) {
+	dataKey, err := e.dataKey(store, v)
+	if err != nil {
+		return nil, err
+	}
+	rootKey, err := e.rootKeyMaterial(key)
+	if err != nil {
+		return nil, err
+	}
+
+	out := v.Copy()
+	if out.WrappedDataKey, err = encryptData(rootKey, dataKey); err != nil {
+		return nil, err
+	}
+	out.KeyID = key.KeyID
+	return out, nil
+}
+
+// dataKey returns the unwrapped data key of a variable.
+func (e *Encrypter) dataKey(store *state.StateStore, v *structs.VariableEncrypted) ([]byte, error) {
+	key, err := store.RootKeyByID(nil, v.KeyID)
+	if err != nil {
+		return nil, err
+	}
+	if key == nil {
+		return nil, fmt.Errorf("root key %q of variable %q not found", v.KeyID, v.Path)
+	}
+	rootKey, err := e.rootKeyMaterial(key)
+	if err != nil {
+		return nil, err
+	}
+
+	dataKey, err := decryptData(rootKey, v.WrappedDataKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap data key of variable %q: %v", v.Path, err)
+	}
+	return dataKey, nil
+}
+
+// rootKeyMaterial returns the key material of a root key, unwrapping it with
+// the keyring encryption key. Keys stored before they were wrapped are
+// returned as is until the leader wraps them.
+func (e *Encrypter) rootKeyMaterial(key *structs.RootKey) ([]byte, error) {
+	if key.Algorithm != structs.RootKeyAlgorithmAES256GCM {
+		return nil, fmt.Errorf("unsupported root key algorithm %q", key.Algorithm)
+	}
+	if !key.IsWrapped() {
+		return key.Key, nil
+	}
+
+	e.keysLock.Lock()
+	defer e.keysLock.Unlock()
+	if material, ok := e.keys[key.KeyID]; ok {
+		return material, nil
+	}
+
+	material, err := decryptData(e.kek, key.WrappedKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap root key %q: %v", key.KeyID, err)
+	}
+	e.keys[key.KeyID] = material
+	return material, nil
+}
+
+// wrapRootKey returns a copy of the root key with its key material wrapped
+// by the keyring encryption key. The key is returned as is if it is already
+// wrapped.
+func (e *Encrypter) wrapRootKey(key *structs.RootKey) (*structs.RootKey, error) {
+	if key.IsWrapped() {
+		return key, nil
+	}
+
+	wrapped, err := encryptData(e.kek, key.Key)
+	if err != nil {
+		return nil, err
+	}
+	out := key.Copy()
+	out.WrappedKey = wrapped
+	out.Key = nil
+	return out, nil
+}
+
+// newRootKey generates a new active root key, wrapped by the keyring
+// encryption key.
+func (e *Encrypter) newRootKey() (*structs.RootKey, error) {
+	material := make([]byte, rootKeySize)
+	if _, err := rand.Read(material); err != nil {
+		return nil, fmt.Errorf("failed to generate root key: %v", err)
+	}
+	return e.wrapRootKey(&structs.RootKey{
+		KeyID:      uuid.Generate(),
+		Algorithm:  structs.RootKeyAlgorithmAES256GCM,
+		Key:        material,
+		Active:     true,
+		CreateTime: time.Now().UnixNano(),
+	})
+}
+
+// encryptData encrypts the data with the AES-256-GCM key, prefixing the
+// nonce to the ciphertext.
+func encryptData(key, plaintext []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %v", err)
+	}
+	return aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// decryptData decrypts data encrypted by encryptData with the key.
+func decryptData(key, data []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) < aead.NonceSize() {
+		return nil, fmt.Errorf("encrypted data too short")
+	}
+	nonce, ciphertext := data[:aead.NonceSize()], data[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt data: %v", err)
+	}
+	return plaintext, nil
+}
+
+// newAEAD returns the AES-GCM cipher of a key.
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// initializeRootKey creates the first root key if there is no active root
+// key yet, and wraps the root keys stored unwrapped. It is called when
+// establishing leadership.
+func (s *Server) initializeRootKey() error {
+	store := s.fsm.State()
+	iter, err := store.RootKeys(nil)
+	if err != nil {
+		return err
+	}
+
+	var active bool
+	var unwrapped []*structs.RootKey
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		key := raw.(*structs.RootKey)
+		if key.Active {
+			active = true
+		}
+		if !key.IsWrapped() {
+			unwrapped = append(unwrapped, key)
+		}
+	}
+
+	// Wrap the keys stored before root keys were wrapped
+	for _, key := range unwrapped {
+		wrapped, err := s.encrypter.wrapRootKey(key)
+		if err != nil {
+			return err
+		}
+		req := structs.RootKeyUpsertRequest{RootKey: wrapped}
+		fsmErr, _, err := s.raftApply(structs.RootKeyUpsertRequestType, req)
+		if err, ok := fsmErr.(error); ok && err != nil {
+			return fmt.Errorf("failed to wrap root key: %v", err)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to wrap root key: %v", err)
+		}
+		s.logger.Info("wrapped root key with the keyring encryption key", "key_id", key.KeyID)
+	}
+
+	if active {
+		return nil
+	}
+
+	key, err := s.encrypter.newRootKey()
+	if err != nil {
+		return err
+	}
+	req := structs.RootKeyUpsertRequest{RootKey: key}
+	fsmErr, _, err := s.raftApply(structs.RootKeyUpsertRequestType, req)
+	if err, ok := fsmErr.(error); ok && err != nil {
+		return fmt.Errorf("failed to create root key: %v", err)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create root key: %v", err)
+	}
+	return nil
+}
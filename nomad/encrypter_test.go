@@ -0,0 +1,119 @@
+package nomad
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncrypter_EncryptDecrypt(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, nil)
+	defer s1.Shutdown()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// The leader creates the active root key
+	key, err := s1.fsm.State().ActiveRootKey(nil)
+	require.NoError(err)
+	require.NotNil(key)
+
+	variable := &structs.VariableDecrypted{
+		VariableMetadata: structs.VariableMetadata{Path: "project/web"},
+		Items:            map[string]string{"password": "hunter2"},
+	}
+	encrypted, err := s1.encrypter.Encrypt(variable)
+	require.NoError(err)
+	require.Equal(key.KeyID, encrypted.KeyID)
+	require.NotEmpty(encrypted.WrappedDataKey)
+	require.NotContains(string(encrypted.Data), "hunter2")
+
+	decrypted, err := s1.encrypter.Decrypt(s1.fsm.State(), encrypted)
+	require.NoError(err)
+	require.Equal(variable, decrypted)
+
+	// Tampered data fails to decrypt
+	encrypted.Data[len(encrypted.Data)-1] ^= 0xff
+	_, err = s1.encrypter.Decrypt(s1.fsm.State(), encrypted)
+	require.Error(err)
+}
+
+func TestEncrypter_WrappedRootKey(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	kek := make([]byte, KeyringEncryptionKeySize)
+	kek[0] = 1
+	s1 := TestServer(t, func(c *Config) {
+		c.KeyringEncryptionKey = kek
+	})
+	defer s1.Shutdown()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// The root key is only stored wrapped
+	key, err := s1.fsm.State().ActiveRootKey(nil)
+	require.NoError(err)
+	require.NotNil(key)
+	require.True(key.IsWrapped())
+	require.Empty(key.Key)
+
+	variable := &structs.VariableDecrypted{
+		VariableMetadata: structs.VariableMetadata{Path: "project/web"},
+		Items:            map[string]string{"password": "hunter2"},
+	}
+	encrypted, err := s1.encrypter.Encrypt(variable)
+	require.NoError(err)
+	decrypted, err := s1.encrypter.Decrypt(s1.fsm.State(), encrypted)
+	require.NoError(err)
+	require.Equal(variable, decrypted)
+
+	// Another keyring encryption key can't unwrap the root key
+	other, err := NewEncrypter(s1, make([]byte, KeyringEncryptionKeySize))
+	require.NoError(err)
+	_, err = other.Decrypt(s1.fsm.State(), encrypted)
+	require.Error(err)
+
+	// The keyring encryption key is required
+	_, err = NewEncrypter(s1, nil)
+	require.Error(err)
+	_, err = NewEncrypter(s1, []byte("short"))
+	require.Error(err)
+}
+
+func TestEncrypter_Rewrap(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, nil)
+	defer s1.Shutdown()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// The root key is stored wrapped
+	oldKey, err := s1.fsm.State().ActiveRootKey(nil)
+	require.NoError(err)
+	require.True(oldKey.IsWrapped())
+
+	items := map[string]string{"password": "hunter2"}
+	current, err := s1.encrypter.Encrypt(&structs.VariableDecrypted{
+		VariableMetadata: structs.VariableMetadata{Path: "project/web"},
+		Items:            items,
+	})
+	require.NoError(err)
+
+	// Rotate the root key and rewrap the variable
+	newKey, err := s1.encrypter.newRootKey()
+	require.NoError(err)
+	require.NoError(s1.fsm.State().UpsertRootKey(1000, newKey))
+
+	out, err := s1.encrypter.Rewrap(s1.fsm.State(), current, newKey)
+	require.NoError(err)
+	require.Equal(newKey.KeyID, out.KeyID)
+	require.NotEmpty(out.WrappedDataKey)
+	decrypted, err := s1.encrypter.Decrypt(s1.fsm.State(), out)
+	require.NoError(err)
+	require.Equal(items, decrypted.Items)
+
+	// Only the data key is rewrapped
+	require.Equal(current.Data, out.Data)
+}
@@ -65,6 +65,16 @@ type EvalBroker struct {
 	// ready tracks the ready jobs by scheduler in a priority queue
 	ready map[string]PendingEvaluations
 
+	// readyNamespaces tracks the ready jobs by scheduler and namespace in
+	// place of ready when fair-share dequeuing is enabled, so that the next
+	// evaluation is found without scanning every ready evaluation.
+	// Namespaces are removed once they have no ready evaluations.
+	readyNamespaces map[string]map[string]*namespaceEvals
+
+	// readyTime tracks when each ready evaluation was enqueued, to measure
+	// how long evaluations wait before being dequeued
+	readyTime map[string]time.Time
+
 	// unack is a map of evalID to an un-acknowledged evaluation
 	unack map[string]*unackEval
 
@@ -101,6 +111,34 @@ type EvalBroker struct {
 	// compounding after the first Nack.
 	subsequentNackDelay time.Duration
 
+	// fairShare enables fair-share dequeuing across namespaces. Ready
+	// evaluations of equal priority are dequeued so that each namespace
+	// receives a share of the dequeues proportional to its weight, which
+	// prevents a flood of evaluations in one namespace from starving the
+	// others.
+	fairShare bool
+
+	// namespaceWeights is the fair-share weight of each namespace.
+	// Namespaces without a weight have a weight of one.
+	namespaceWeights map[string]int
+
+	// namespacePass is the virtual time of each namespace. Each dequeue
+	// advances the virtual time of the namespace by the inverse of its
+	// weight and the namespace with the lowest virtual time is dequeued
+	// first. It is dropped once the namespace has no ready evaluations, as
+	// tracked by namespaceReady.
+	namespacePass  map[string]float64
+	namespaceReady map[string]int
+
+	// virtualTime is the virtual time of the most recent dequeue. Namespaces
+	// that fell behind because they had no ready evaluations are brought
+	// forward to it so that they can not monopolize the broker.
+	virtualTime float64
+
+	// disablePriority dequeues ready evaluations in the order they were
+	// created, ignoring the priority of their jobs.
+	disablePriority bool
+
 	l sync.RWMutex
 }
 
@@ -136,6 +174,8 @@ func NewEvalBroker(timeout, initialNackDelay, subsequentNackDelay time.Duration,
 		jobEvals:             make(map[structs.NamespacedID]string),
 		blocked:              make(map[structs.NamespacedID]PendingEvaluations),
 		ready:                make(map[string]PendingEvaluations),
+		readyNamespaces:      make(map[string]map[string]*namespaceEvals),
+		readyTime:            make(map[string]time.Time),
 		unack:                make(map[string]*unackEval),
 		waiting:              make(map[string]chan struct{}),
 		requeue:              make(map[string]*structs.Evaluation),
@@ -144,12 +184,43 @@ func NewEvalBroker(timeout, initialNackDelay, subsequentNackDelay time.Duration,
 		subsequentNackDelay:  subsequentNackDelay,
 		delayHeap:            delayheap.NewDelayHeap(),
 		delayedEvalsUpdateCh: make(chan struct{}, 1),
+		namespacePass:        make(map[string]float64),
+		namespaceReady:       make(map[string]int),
 	}
 	b.stats.ByScheduler = make(map[string]*SchedulerStats)
 
 	return b, nil
 }
 
+// SetFairShare enables fair-share dequeuing across namespaces using the given
+// per-namespace weights. Namespaces without a weight have a weight of one.
+// It must be called before the broker is enabled.
+func (b *EvalBroker) SetFairShare(weights map[string]int) {
+	b.l.Lock()
+	defer b.l.Unlock()
+	b.fairShare = true
+	b.namespaceWeights = weights
+}
+
+// SetEvalPriority controls whether ready evaluations are dequeued by the
+// priority of their job or in the order they were created.
+func (b *EvalBroker) SetEvalPriority(enabled bool) {
+	b.l.Lock()
+	defer b.l.Unlock()
+	if b.disablePriority == !enabled {
+		return
+	}
+	b.disablePriority = !enabled
+
+	// Reorder the ready evaluations of the namespaces
+	for _, namespaces := range b.readyNamespaces {
+		for _, evals := range namespaces {
+			evals.ignorePriority = b.disablePriority
+			heap.Init(evals)
+		}
+	}
+}
+
 // Enabled is used to check if the broker is enabled.
 func (b *EvalBroker) Enabled() bool {
 	b.l.RLock()
@@ -287,18 +358,12 @@ func (b *EvalBroker) enqueueLocked(eval *structs.Evaluation, queue string) {
 		return
 	}
 
-	// Find the pending by scheduler class
-	pending, ok := b.ready[queue]
-	if !ok {
-		pending = make([]*structs.Evaluation, 0, 16)
-		if _, ok := b.waiting[queue]; !ok {
-			b.waiting[queue] = make(chan struct{}, 1)
-		}
+	// Push onto the ready evaluations of the scheduler class
+	if _, ok := b.waiting[queue]; !ok {
+		b.waiting[queue] = make(chan struct{}, 1)
 	}
-
-	// Push onto the heap
-	heap.Push(&pending, eval)
-	b.ready[queue] = pending
+	b.pushReady(queue, eval)
+	b.readyTime[eval.ID] = time.Now()
 
 	// Update the stats
 	b.stats.TotalReady += 1
@@ -367,27 +432,27 @@ func (b *EvalBroker) scanForSchedulers(schedulers []string) (*structs.Evaluation
 	var eligibleSched []string
 	var eligiblePriority int
 	for _, sched := range schedulers {
-		// Get the pending queue
-		pending, ok := b.ready[sched]
-		if !ok {
-			continue
-		}
-
 		// Peek at the next item
-		ready := pending.Peek()
+		ready := b.peekReady(sched)
 		if ready == nil {
 			continue
 		}
 
+		// Without priorities all schedulers with work are equally eligible
+		priority := ready.Priority
+		if b.disablePriority {
+			priority = 0
+		}
+
 		// Add to eligible if equal or greater priority
-		if len(eligibleSched) == 0 || ready.Priority > eligiblePriority {
+		if len(eligibleSched) == 0 || priority > eligiblePriority {
 			eligibleSched = []string{sched}
-			eligiblePriority = ready.Priority
+			eligiblePriority = priority
 
-		} else if eligiblePriority > ready.Priority {
+		} else if eligiblePriority > priority {
 			continue
 
-		} else if eligiblePriority == ready.Priority {
+		} else if eligiblePriority == priority {
 			eligibleSched = append(eligibleSched, sched)
 		}
 	}
@@ -413,11 +478,12 @@ func (b *EvalBroker) scanForSchedulers(schedulers []string) (*structs.Evaluation
 // dequeueForSched is used to dequeue the next work item for a given scheduler.
 // This assumes locks are held and that this scheduler has work
 func (b *EvalBroker) dequeueForSched(sched string) (*structs.Evaluation, string, error) {
-	// Get the pending queue
-	pending := b.ready[sched]
-	raw := heap.Pop(&pending)
-	b.ready[sched] = pending
-	eval := raw.(*structs.Evaluation)
+	eval := b.popReady(sched)
+
+	if readyTime, ok := b.readyTime[eval.ID]; ok {
+		metrics.MeasureSince([]string{"nomad", "broker", sched, "wait_time"}, readyTime)
+		delete(b.readyTime, eval.ID)
+	}
 
 	// Generate a UUID for the token
 	token := uuid.Generate()
@@ -447,6 +513,152 @@ func (b *EvalBroker) dequeueForSched(sched string) (*structs.Evaluation, string,
 	return eval, token, nil
 }
 
+// pushReady adds the evaluation to the ready evaluations of the scheduler.
+// This assumes locks are held.
+func (b *EvalBroker) pushReady(sched string, eval *structs.Evaluation) {
+	if !b.fairShare {
+		pending := b.ready[sched]
+		heap.Push(&pending, eval)
+		b.ready[sched] = pending
+		return
+	}
+
+	namespaces, ok := b.readyNamespaces[sched]
+	if !ok {
+		namespaces = make(map[string]*namespaceEvals)
+		b.readyNamespaces[sched] = namespaces
+	}
+	evals, ok := namespaces[eval.Namespace]
+	if !ok {
+		evals = &namespaceEvals{ignorePriority: b.disablePriority}
+		namespaces[eval.Namespace] = evals
+	}
+	heap.Push(evals, eval)
+	b.namespaceReady[eval.Namespace]++
+}
+
+// peekReady returns the ready evaluation of the scheduler to dequeue next, or
+// nil if it has none. This assumes locks are held.
+func (b *EvalBroker) peekReady(sched string) *structs.Evaluation {
+	if !b.fairShare {
+		return b.ready[sched].Peek()
+	}
+
+	if evals := b.fairShareNamespace(sched); evals != nil {
+		return evals.Peek()
+	}
+	return nil
+}
+
+// popReady removes the ready evaluation of the scheduler to dequeue next and
+// returns it. This assumes locks are held and that the scheduler has work.
+func (b *EvalBroker) popReady(sched string) *structs.Evaluation {
+	if !b.fairShare {
+		pending := b.ready[sched]
+		var raw interface{}
+		if b.disablePriority {
+			raw = heap.Remove(&pending, oldestIndex(pending))
+		} else {
+			raw = heap.Pop(&pending)
+		}
+		b.ready[sched] = pending
+		return raw.(*structs.Evaluation)
+	}
+
+	evals := b.fairShareNamespace(sched)
+	eval := heap.Pop(evals).(*structs.Evaluation)
+	if evals.Len() == 0 {
+		delete(b.readyNamespaces[sched], eval.Namespace)
+	}
+	b.chargeNamespace(eval.Namespace)
+
+	// Namespaces without ready evaluations start over at the virtual time
+	// of the broker, so their virtual time doesn't need to be kept
+	if b.namespaceReady[eval.Namespace]--; b.namespaceReady[eval.Namespace] == 0 {
+		delete(b.namespaceReady, eval.Namespace)
+		delete(b.namespacePass, eval.Namespace)
+	}
+	return eval
+}
+
+// readyEvals returns the ready evaluations of the scheduler. This assumes
+// locks are held.
+func (b *EvalBroker) readyEvals(sched string) []*structs.Evaluation {
+	if !b.fairShare {
+		return b.ready[sched]
+	}
+
+	var out []*structs.Evaluation
+	for _, evals := range b.readyNamespaces[sched] {
+		out = append(out, evals.evals...)
+	}
+	return out
+}
+
+// fairShareNamespace returns the ready evaluations of the namespace to dequeue
+// next from when fair-share dequeuing is enabled, or nil if the scheduler has
+// no work. Among the namespaces whose next evaluation has the highest
+// priority, the one with the lowest virtual time is chosen, falling back to
+// the oldest evaluation. Priorities are ignored when they are disabled. This
+// assumes locks are held.
+func (b *EvalBroker) fairShareNamespace(sched string) *namespaceEvals {
+	var best *namespaceEvals
+	var bestPass float64
+	for namespace, evals := range b.readyNamespaces[sched] {
+		eval, pass := evals.Peek(), b.namespacePassLocked(namespace)
+		if best == nil {
+			best, bestPass = evals, pass
+			continue
+		}
+
+		bestEval := best.Peek()
+		if !b.disablePriority && eval.Priority != bestEval.Priority {
+			if eval.Priority > bestEval.Priority {
+				best, bestPass = evals, pass
+			}
+			continue
+		}
+		if pass < bestPass || (pass == bestPass && eval.CreateIndex < bestEval.CreateIndex) {
+			best, bestPass = evals, pass
+		}
+	}
+	return best
+}
+
+// oldestIndex returns the index of the evaluation that was created first.
+func oldestIndex(pending PendingEvaluations) int {
+	oldest := 0
+	for i := 1; i < len(pending); i++ {
+		if pending[i].CreateIndex < pending[oldest].CreateIndex {
+			oldest = i
+		}
+	}
+	return oldest
+}
+
+// namespacePassLocked returns the virtual time of the namespace. A namespace
+// is never behind the virtual time of the broker. This assumes locks are
+// held.
+func (b *EvalBroker) namespacePassLocked(namespace string) float64 {
+	if pass := b.namespacePass[namespace]; pass > b.virtualTime {
+		return pass
+	}
+	return b.virtualTime
+}
+
+// chargeNamespace advances the virtual time of the namespace for a dequeue.
+// This assumes locks are held.
+func (b *EvalBroker) chargeNamespace(namespace string) {
+	weight := b.namespaceWeights[namespace]
+	if weight <= 0 {
+		weight = 1
+	}
+
+	pass := b.namespacePassLocked(namespace)
+	b.virtualTime = pass
+	b.namespacePass[namespace] = pass + 1/float64(weight)
+}
+
 // waitForSchedulers is used to wait for work on any of the scheduler or until a timeout.
 // Returns if there is work waiting potentially.
 func (b *EvalBroker) waitForSchedulers(schedulers []string, timeoutCh <-chan time.Time) bool {
@@ -610,6 +822,8 @@ func (b *EvalBroker) Nack(evalID, token string) error {
 	b.stats.TotalUnacked -= 1
 	bySched := b.stats.ByScheduler[unack.Eval.Type]
 	bySched.Unacked -= 1
+	bySched.Nacked += 1
+	metrics.IncrCounter([]string{"nomad", "broker", unack.Eval.Type, "nack"}, 1)
 
 	// Check if we've hit the delivery limit, and re-enqueue
 	// in the failedQueue
@@ -717,9 +931,14 @@ func (b *EvalBroker) flush() {
 	b.jobEvals = make(map[structs.NamespacedID]string)
 	b.blocked = make(map[structs.NamespacedID]PendingEvaluations)
 	b.ready = make(map[string]PendingEvaluations)
+	b.readyNamespaces = make(map[string]map[string]*namespaceEvals)
+	b.readyTime = make(map[string]time.Time)
 	b.unack = make(map[string]*unackEval)
 	b.timeWait = make(map[string]*time.Timer)
 	b.delayHeap = delayheap.NewDelayHeap()
+	b.namespacePass = make(map[string]float64)
+	b.namespaceReady = make(map[string]int)
+	b.virtualTime = 0
 }
 
 // evalWrapper satisfies the HeapNode interface
@@ -806,9 +1025,21 @@ func (b *EvalBroker) Stats() *BrokerStats {
 	stats.TotalUnacked = b.stats.TotalUnacked
 	stats.TotalBlocked = b.stats.TotalBlocked
 	stats.TotalWaiting = b.stats.TotalWaiting
+	now := time.Now()
 	for sched, subStat := range b.stats.ByScheduler {
 		subStatCopy := new(SchedulerStats)
 		*subStatCopy = *subStat
+
+		// Find how long the oldest ready evaluation has been waiting
+		for _, eval := range b.readyEvals(sched) {
+			readyTime, ok := b.readyTime[eval.ID]
+			if !ok {
+				continue
+			}
+			if wait := now.Sub(readyTime); wait > subStatCopy.OldestReadyWait {
+				subStatCopy.OldestReadyWait = wait
+			}
+		}
 		stats.ByScheduler[sched] = subStatCopy
 	}
 	return stats
@@ -827,6 +1058,8 @@ func (b *EvalBroker) EmitStats(period time.Duration, stopCh chan struct{}) {
 			for sched, schedStats := range stats.ByScheduler {
 				metrics.SetGauge([]string{"nomad", "broker", sched, "ready"}, float32(schedStats.Ready))
 				metrics.SetGauge([]string{"nomad", "broker", sched, "unacked"}, float32(schedStats.Unacked))
+				metrics.SetGauge([]string{"nomad", "broker", sched, "oldest_ready_wait"},
+					float32(schedStats.OldestReadyWait.Seconds()*1000))
 			}
 
 		case <-stopCh:
@@ -848,6 +1081,14 @@ type BrokerStats struct {
 type SchedulerStats struct {
 	Ready   int
 	Unacked int
+
+	// Nacked is the number of evaluations Nacked, either explicitly or
+	// because their Nack timeout was reached, since the broker was enabled
+	Nacked int
+
+	// OldestReadyWait is how long the oldest ready evaluation has been
+	// waiting to be dequeued
+	OldestReadyWait time.Duration
 }
 
 // Len is for the sorting interface
@@ -892,3 +1133,45 @@ func (p PendingEvaluations) Peek() *structs.Evaluation {
 	}
 	return p[n-1]
 }
+
+// namespaceEvals is a heap of the ready evaluations of a namespace, ordered
+// by priority unless priorities are ignored, and then by creation.
+type namespaceEvals struct {
+	evals          []*structs.Evaluation
+	ignorePriority bool
+}
+
+func (n *namespaceEvals) Len() int {
+	return len(n.evals)
+}
+
+func (n *namespaceEvals) Less(i, j int) bool {
+	if !n.ignorePriority && n.evals[i].Priority != n.evals[j].Priority {
+		return n.evals[i].Priority > n.evals[j].Priority
+	}
+	return n.evals[i].CreateIndex < n.evals[j].CreateIndex
+}
+
+func (n *namespaceEvals) Swap(i, j int) {
+	n.evals[i], n.evals[j] = n.evals[j], n.evals[i]
+}
+
+func (n *namespaceEvals) Push(e interface{}) {
+	n.evals = append(n.evals, e.(*structs.Evaluation))
+}
+
+func (n *namespaceEvals) Pop() interface{} {
+	last := len(n.evals) - 1
+	e := n.evals[last]
+	n.evals[last] = nil
+	n.evals = n.evals[:last]
+	return e
+}
+
+// Peek returns the next evaluation of the namespace, or nil if it has none.
+func (n *namespaceEvals) Peek() *structs.Evaluation {
+	if len(n.evals) == 0 {
+		return nil
+	}
+	return n.evals[0]
+}
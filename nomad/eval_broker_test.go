@@ -225,6 +225,28 @@ func TestEvalBroker_Enqueue_Dequeue_Nack_Ack(t *testing.T) {
 	}
 }
 
+func TestEvalBroker_Stats_OldestReadyWait(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	b := testBroker(t, 0)
+	b.SetEnabled(true)
+
+	eval := mock.Eval()
+	b.Enqueue(eval)
+	time.Sleep(10 * time.Millisecond)
+
+	// The ready evaluation has been waiting since it was enqueued
+	stats := b.Stats()
+	require.Equal(1, stats.ByScheduler[eval.Type].Ready)
+	require.True(stats.ByScheduler[eval.Type].OldestReadyWait >= 10*time.Millisecond)
+
+	// Dequeued evaluations are no longer waiting
+	_, _, err := b.Dequeue(defaultSched, time.Second)
+	require.NoError(err)
+	stats = b.Stats()
+	require.Zero(stats.ByScheduler[eval.Type].OldestReadyWait)
+}
+
 func TestEvalBroker_Nack_Delay(t *testing.T) {
 	t.Parallel()
 	b := testBroker(t, 0)
@@ -270,6 +292,9 @@ func TestEvalBroker_Nack_Delay(t *testing.T) {
 	if stats.ByScheduler[eval.Type].Unacked != 0 {
 		t.Fatalf("bad: %#v", stats)
 	}
+	if stats.ByScheduler[eval.Type].Nacked != 1 {
+		t.Fatalf("bad: %#v", stats)
+	}
 
 	// Now wait for it to be re-enqueued
 	testutil.WaitForResult(func() (bool, error) {
@@ -738,6 +763,35 @@ func TestEvalBroker_Dequeue_Priority(t *testing.T) {
 	}
 }
 
+// Ensure FIFO across priorities when priorities are disabled
+func TestEvalBroker_Dequeue_PriorityDisabled(t *testing.T) {
+	t.Parallel()
+	b := testBroker(t, 0)
+	b.SetEvalPriority(false)
+	b.SetEnabled(true)
+
+	eval1 := mock.Eval()
+	eval1.Priority = 10
+	eval1.CreateIndex = 1
+	b.Enqueue(eval1)
+
+	eval2 := mock.Eval()
+	eval2.Priority = 30
+	eval2.CreateIndex = 2
+	b.Enqueue(eval2)
+
+	eval3 := mock.Eval()
+	eval3.Priority = 20
+	eval3.CreateIndex = 3
+	b.Enqueue(eval3)
+
+	for _, expected := range []*structs.Evaluation{eval1, eval2, eval3} {
+		out, _, err := b.Dequeue(defaultSched, time.Second)
+		require.NoError(t, err)
+		require.Equal(t, expected, out)
+	}
+}
+
 // Ensure FIFO at fixed priority
 func TestEvalBroker_Dequeue_FIFO(t *testing.T) {
 	t.Parallel()
@@ -760,6 +814,101 @@ func TestEvalBroker_Dequeue_FIFO(t *testing.T) {
 	}
 }
 
+// Ensure a flood of evaluations in one namespace does not starve another
+func TestEvalBroker_Dequeue_FairShare(t *testing.T) {
+	t.Parallel()
+	b := testBroker(t, 0)
+	b.SetFairShare(nil)
+	b.SetEnabled(true)
+
+	for i := 0; i < 100; i++ {
+		eval := mock.Eval()
+		eval.Namespace = "flood"
+		eval.CreateIndex = uint64(i)
+		b.Enqueue(eval)
+	}
+	for i := 0; i < 2; i++ {
+		eval := mock.Eval()
+		eval.Namespace = "other"
+		eval.CreateIndex = uint64(100 + i)
+		b.Enqueue(eval)
+	}
+
+	// The namespaces alternate until the other namespace runs out of work
+	var namespaces []string
+	for i := 0; i < 5; i++ {
+		out, _, err := b.Dequeue(defaultSched, time.Second)
+		require.NoError(t, err)
+		require.NotNil(t, out)
+		namespaces = append(namespaces, out.Namespace)
+	}
+	require.Equal(t, []string{"flood", "other", "flood", "other", "flood"}, namespaces)
+}
+
+// Ensure namespaces are dequeued proportionally to their weight and a
+// namespace that had no work does not get a burst of dequeues
+func TestEvalBroker_Dequeue_FairShare_Weights(t *testing.T) {
+	t.Parallel()
+	b := testBroker(t, 0)
+	b.SetFairShare(map[string]int{"heavy": 3})
+	b.SetEnabled(true)
+
+	enqueue := func(namespace string, n int) {
+		for i := 0; i < n; i++ {
+			eval := mock.Eval()
+			eval.Namespace = namespace
+			b.Enqueue(eval)
+		}
+	}
+	dequeue := func(n int) map[string]int {
+		counts := make(map[string]int)
+		for i := 0; i < n; i++ {
+			out, _, err := b.Dequeue(defaultSched, time.Second)
+			require.NoError(t, err)
+			require.NotNil(t, out)
+			counts[out.Namespace]++
+		}
+		return counts
+	}
+
+	// Only the heavy namespace has work for a while
+	enqueue("heavy", 100)
+	require.Equal(t, map[string]int{"heavy": 20}, dequeue(20))
+
+	// Once the light namespace has work it gets a quarter of the dequeues
+	enqueue("light", 100)
+	counts := dequeue(40)
+	require.Equal(t, 40, counts["heavy"]+counts["light"])
+	require.InDelta(t, 10, counts["light"], 1)
+}
+
+// Ensure namespaces without ready evaluations are dropped
+func TestEvalBroker_Dequeue_FairShare_Prune(t *testing.T) {
+	t.Parallel()
+	b := testBroker(t, 0)
+	b.SetFairShare(nil)
+	b.SetEnabled(true)
+
+	for _, namespace := range []string{"one", "two"} {
+		eval := mock.Eval()
+		eval.Namespace = namespace
+		b.Enqueue(eval)
+	}
+
+	for i := 0; i < 2; i++ {
+		out, token, err := b.Dequeue(defaultSched, time.Second)
+		require.NoError(t, err)
+		require.NotNil(t, out)
+		require.NoError(t, b.Ack(out.ID, token))
+	}
+
+	b.l.Lock()
+	defer b.l.Unlock()
+	require.Empty(t, b.readyNamespaces[defaultSched[0]])
+	require.Empty(t, b.namespacePass)
+	require.Empty(t, b.namespaceReady)
+}
+
 // Ensure fairness between schedulers
 func TestEvalBroker_Dequeue_Fairness(t *testing.T) {
 	t.Parallel()
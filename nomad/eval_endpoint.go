@@ -203,8 +203,8 @@ func (e *Eval) Update(args *structs.EvalUpdateRequest,
 		return err
 	}
 
-	// Update via Raft
-	_, index, err := e.srv.raftApply(structs.EvalUpdateRequestType, args)
+	// Update via Raft, batched with the updates of the other schedulers
+	index, err := e.srv.evalUpdateBatcher.Apply(args)
 	if err != nil {
 		return err
 	}
@@ -248,8 +248,8 @@ func (e *Eval) Create(args *structs.EvalUpdateRequest,
 		return fmt.Errorf("evaluation already exists")
 	}
 
-	// Update via Raft
-	_, index, err := e.srv.raftApply(structs.EvalUpdateRequestType, args)
+	// Update via Raft, batched with the updates of the other schedulers
+	index, err := e.srv.evalUpdateBatcher.Apply(args)
 	if err != nil {
 		return err
 	}
@@ -329,9 +329,10 @@ func (e *Eval) List(args *structs.EvalListRequest,
 	defer metrics.MeasureSince([]string{"nomad", "eval", "list"}, time.Now())
 
 	// Check for read-job permissions
-	if aclObj, err := e.srv.ResolveToken(args.AuthToken); err != nil {
+	aclObj, err := e.srv.ResolveToken(args.AuthToken)
+	if err != nil {
 		return err
-	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityReadJob) {
+	} else if !allowNsOp(aclObj, args.RequestNamespace(), acl.NamespaceCapabilityReadJob) {
 		return structs.ErrPermissionDenied
 	}
 
@@ -351,17 +352,31 @@ func (e *Eval) List(args *structs.EvalListRequest,
 			if err != nil {
 				return err
 			}
+			iter = namespaceACLFilter(iter, aclObj, args.RequestNamespace(), acl.NamespaceCapabilityReadJob,
+				func(raw interface{}) string {
+					return raw.(*structs.Evaluation).Namespace
+				})
+
+			paginator, err := newPaginator(iter, args.QueryOptions,
+				func(raw interface{}) string {
+					return raw.(*structs.Evaluation).ID
+				},
+				func(raw interface{}) (interface{}, error) {
+					return raw, nil
+				})
+			if err != nil {
+				return err
+			}
 
 			var evals []*structs.Evaluation
-			for {
-				raw := iter.Next()
-				if raw == nil {
-					break
-				}
-				eval := raw.(*structs.Evaluation)
-				evals = append(evals, eval)
+			nextToken, err := paginator.Page(func(obj interface{}) {
+				evals = append(evals, obj.(*structs.Evaluation))
+			})
+			if err != nil {
+				return err
 			}
 			reply.Evaluations = evals
+			reply.NextToken = nextToken
 
 			// Use the last index that affected the jobs table
 			index, err := state.Index("evals")
@@ -0,0 +1,145 @@
+package nomad
+
+import (
+	"sync"
+
+	metrics "github.com/armon/go-metrics"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// evalUpdateBatcher coalesces the evaluation updates of the schedulers into
+// batched Raft log entries, each applied to the state store in a single
+// transaction. While a batch is being applied, the updates received are
+// queued and applied together as the next batch. Updates are thus not
+// delayed while the leader is idle, and batch up as the write load grows.
+type evalUpdateBatcher struct {
+	srv *Server
+
+	// maxBatchSize is the maximum number of evaluations in a batch
+	maxBatchSize int
+
+	// batches are the batches waiting to be applied, in order
+	batches []*evalUpdateBatch
+
+	// applying is whether a batch is being applied
+	applying bool
+
+	l sync.Mutex
+}
+
+// evalUpdateBatch is a set of evaluation updates applied as one Raft log
+// entry.
+type evalUpdateBatch struct {
+	updates  []*structs.EvalUpdateRequest
+	numEvals int
+
+	// errs is the error of each update, set before the future responds
+	errs []error
+
+	future *structs.BatchFuture
+}
+
+// newEvalUpdateBatcher returns a batcher applying batches of up to
+// maxBatchSize evaluations.
+func newEvalUpdateBatcher(srv *Server, maxBatchSize int) *evalUpdateBatcher {
+	return &evalUpdateBatcher{
+		srv:          srv,
+		maxBatchSize: maxBatchSize,
+	}
+}
+
+// Apply adds the update to the next batch and blocks until the batch is
+// applied, returning the index it was applied at and the error of the update.
+func (b *evalUpdateBatcher) Apply(args *structs.EvalUpdateRequest) (uint64, error) {
+	// Batching is disabled, apply the update on its own
+	if b.maxBatchSize <= 1 {
+		resp, index, err := b.srv.raftApply(structs.EvalUpdateRequestType, args)
+		if err != nil {
+			return 0, err
+		}
+		if respErr, ok := resp.(error); ok && respErr != nil {
+			return 0, respErr
+		}
+		return index, nil
+	}
+
+	b.l.Lock()
+	var batch *evalUpdateBatch
+	if n := len(b.batches); n != 0 && b.batches[n-1].numEvals+len(args.Evals) <= b.maxBatchSize {
+		batch = b.batches[n-1]
+	} else {
+		batch = &evalUpdateBatch{future: structs.NewBatchFuture()}
+		b.batches = append(b.batches, batch)
+	}
+	pos := len(batch.updates)
+	batch.updates = append(batch.updates, args)
+	batch.numEvals += len(args.Evals)
+
+	// Start applying the batches if no batch is being applied
+	if !b.applying {
+		b.applying = true
+		go b.run()
+	}
+	b.l.Unlock()
+
+	if err := batch.future.Wait(); err != nil {
+		return 0, err
+	}
+	if batch.errs != nil && batch.errs[pos] != nil {
+		return 0, batch.errs[pos]
+	}
+	return batch.future.Index(), nil
+}
+
+// run applies the queued batches one after the other until there are none
+// left.
+func (b *evalUpdateBatcher) run() {
+	for {
+		b.l.Lock()
+		if len(b.batches) == 0 {
+			b.applying = false
+			b.l.Unlock()
+			return
+		}
+		batch := b.batches[0]
+		b.batches[0] = nil
+		b.batches = b.batches[1:]
+		b.l.Unlock()
+
+		metrics.AddSample([]string{"nomad", "eval", "update_batch_size"}, float32(batch.numEvals))
+		index, err := b.applyBatch(batch)
+		if err != nil {
+			b.srv.logger.Error("eval update failed", "num_evals", batch.numEvals, "error", err)
+		}
+		batch.future.Respond(index, err)
+	}
+}
+
+// applyBatch applies a batch, setting the error of each update if the updates
+// failed individually.
+func (b *evalUpdateBatcher) applyBatch(batch *evalUpdateBatch) (uint64, error) {
+	// A single update is applied as is
+	if len(batch.updates) == 1 {
+		resp, index, err := b.srv.raftApply(structs.EvalUpdateRequestType, batch.updates[0])
+		if err != nil {
+			return 0, err
+		}
+		if respErr, ok := resp.(error); ok && respErr != nil {
+			batch.errs = []error{respErr}
+		}
+		return index, nil
+	}
+
+	req := &structs.EvalUpdateBatchRequest{
+		Updates:      batch.updates,
+		WriteRequest: structs.WriteRequest{Region: b.srv.config.Region},
+	}
+	resp, index, err := b.srv.raftApply(structs.EvalUpdateBatchRequestType, req)
+	if err != nil {
+		return 0, err
+	}
+	if errs, ok := resp.([]error); ok {
+		batch.errs = errs
+	}
+	return index, nil
+}
@@ -0,0 +1,75 @@
+package nomad
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvalUpdateBatcher_Apply(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer s1.Shutdown()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	b := newEvalUpdateBatcher(s1, 2)
+
+	// Simulate a batch being applied so that the updates are queued
+	b.l.Lock()
+	b.applying = true
+	b.l.Unlock()
+
+	evals := []*structs.Evaluation{mock.Eval(), mock.Eval(), mock.Eval()}
+	indexCh := make(chan uint64, len(evals))
+	for _, eval := range evals {
+		go func(eval *structs.Evaluation) {
+			index, err := b.Apply(&structs.EvalUpdateRequest{
+				Evals:        []*structs.Evaluation{eval},
+				EvalToken:    uuid.Generate(),
+				WriteRequest: structs.WriteRequest{Region: "global"},
+			})
+			if err != nil {
+				t.Errorf("apply failed: %v", err)
+			}
+			indexCh <- index
+		}(eval)
+	}
+
+	// Wait for the updates to be queued in two batches
+	testutil.WaitForResult(func() (bool, error) {
+		b.l.Lock()
+		defer b.l.Unlock()
+		return len(b.batches) == 2, nil
+	}, func(err error) {
+		t.Fatalf("updates not queued")
+	})
+
+	// Apply the queued batches
+	go b.run()
+
+	indexes := make(map[uint64]int)
+	for range evals {
+		select {
+		case index := <-indexCh:
+			indexes[index]++
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timeout waiting for the batches")
+		}
+	}
+
+	// The three updates were applied in two Raft log entries
+	require.Len(indexes, 2)
+	for _, eval := range evals {
+		out, err := s1.fsm.State().EvalByID(nil, eval.ID)
+		require.NoError(err)
+		require.NotNil(out)
+	}
+}
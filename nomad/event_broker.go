@@ -0,0 +1,162 @@
+package nomad
+
+import (
+	"sync"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// EventBroker keeps the most recent batches of cluster events in memory and
+// notifies the streams of the events as new batches are published. Streams
+// resuming after an index older than the oldest buffered batch only receive
+// the buffered batches.
+type EventBroker struct {
+	l sync.Mutex
+
+	// events holds the most recent batches of events, oldest first.
+	events []*structs.Events
+
+	// size is the maximum number of buffered batches.
+	size int
+
+	// notifyCh is closed and replaced whenever a batch is published.
+	notifyCh chan struct{}
+}
+
+// NewEventBroker returns an event broker buffering up to size batches of
+// events.
+func NewEventBroker(size int) *EventBroker {
+	if size < 1 {
+		size = 1
+	}
+	return &EventBroker{
+		size:     size,
+		notifyCh: make(chan struct{}),
+	}
+}
+
+// Publish buffers the batch of events, dropping the oldest batches beyond the
+// size of the buffer, and notifies the streams.
+func (b *EventBroker) Publish(events *structs.Events) {
+	if events == nil || len(events.Events) == 0 {
+		return
+	}
+
+	b.l.Lock()
+	defer b.l.Unlock()
+
+	b.events = append(b.events, events)
+	if len(b.events) > b.size {
+		b.events = b.events[len(b.events)-b.size:]
+	}
+
+	close(b.notifyCh)
+	b.notifyCh = make(chan struct{})
+}
+
+// Since returns the buffered batches published after the index, and a
+// channel closed once the next batch is published.
+func (b *EventBroker) Since(index uint64) ([]*structs.Events, <-chan struct{}) {
+	b.l.Lock()
+	defer b.l.Unlock()
+
+	var out []*structs.Events
+	for i := len(b.events) - 1; i >= 0; i-- {
+		if b.events[i].Index <= index {
+			break
+		}
+		out = append(out, b.events[i])
+	}
+
+	// Return the batches oldest first
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return out, b.notifyCh
+}
+
+// LastIndex returns the index of the most recent batch of events.
+func (b *EventBroker) LastIndex() uint64 {
+	b.l.Lock()
+	defer b.l.Unlock()
+
+	if len(b.events) == 0 {
+		return 0
+	}
+	return b.events[len(b.events)-1].Index
+}
+
+// jobEvent returns the event of a change of the job. The job is nil once
+// purged.
+func jobEvent(eventType, namespace, jobID string, job *structs.Job) *structs.Event {
+	return &structs.Event{
+		Topic:     structs.TopicJob,
+		Type:      eventType,
+		Key:       jobID,
+		Namespace: namespace,
+		Payload:   map[string]interface{}{"Job": job},
+	}
+}
+
+// allocEvent returns the event of an update of the allocation. The payload
+// is the stub of the allocation to avoid streaming its job.
+func allocEvent(alloc *structs.Allocation) *structs.Event {
+	return &structs.Event{
+		Topic:      structs.TopicAllocation,
+		Type:       structs.TypeAllocationUpdated,
+		Key:        alloc.ID,
+		Namespace:  alloc.Namespace,
+		FilterKeys: filterKeys(alloc.JobID, alloc.NodeID, alloc.DeploymentID),
+		Payload:    map[string]interface{}{"Allocation": alloc.Stub()},
+	}
+}
+
+// deploymentEvent returns the event of a change of the deployment.
+func deploymentEvent(eventType string, deployment *structs.Deployment) *structs.Event {
+	return &structs.Event{
+		Topic:      structs.TopicDeployment,
+		Type:       eventType,
+		Key:        deployment.ID,
+		Namespace:  deployment.Namespace,
+		FilterKeys: filterKeys(deployment.JobID),
+		Payload:    map[string]interface{}{"Deployment": deployment},
+	}
+}
+
+// evalEvent returns the event of an update of the evaluation.
+func evalEvent(eval *structs.Evaluation) *structs.Event {
+	return &structs.Event{
+		Topic:      structs.TopicEvaluation,
+		Type:       structs.TypeEvaluationUpdated,
+		Key:        eval.ID,
+		Namespace:  eval.Namespace,
+		FilterKeys: filterKeys(eval.JobID, eval.DeploymentID),
+		Payload:    map[string]interface{}{"Evaluation": eval},
+	}
+}
+
+// nodeEvent returns the event of a change of the node. The payload is the
+// stub of the node to avoid streaming its secret ID.
+func nodeEvent(eventType, nodeID string, node *structs.Node) *structs.Event {
+	payload := map[string]interface{}{"Node": nil}
+	if node != nil {
+		payload["Node"] = node.Stub()
+	}
+	return &structs.Event{
+		Topic:   structs.TopicNode,
+		Type:    eventType,
+		Key:     nodeID,
+		Payload: payload,
+	}
+}
+
+// filterKeys returns the non-empty keys.
+func filterKeys(keys ...string) []string {
+	var out []string
+	for _, key := range keys {
+		if key != "" {
+			out = append(out, key)
+		}
+	}
+	return out
+}
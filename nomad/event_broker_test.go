@@ -0,0 +1,63 @@
+package nomad
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func testEvents(index uint64) *structs.Events {
+	return &structs.Events{
+		Index: index,
+		Events: []*structs.Event{{
+			Topic: structs.TopicJob,
+			Type:  structs.TypeJobRegistered,
+			Key:   "example",
+			Index: index,
+		}},
+	}
+}
+
+func TestEventBroker_PublishSince(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	b := NewEventBroker(2)
+	require.Zero(b.LastIndex())
+
+	batches, notifyCh := b.Since(0)
+	require.Empty(batches)
+
+	// Publishing notifies the streams
+	b.Publish(testEvents(10))
+	select {
+	case <-notifyCh:
+	default:
+		t.Fatal("expected notification")
+	}
+
+	// Empty batches aren't published
+	b.Publish(&structs.Events{Index: 11})
+	require.Equal(uint64(10), b.LastIndex())
+
+	b.Publish(testEvents(12))
+	batches, _ = b.Since(0)
+	require.Len(batches, 2)
+	require.Equal(uint64(10), batches[0].Index)
+	require.Equal(uint64(12), batches[1].Index)
+
+	batches, _ = b.Since(10)
+	require.Len(batches, 1)
+	require.Equal(uint64(12), batches[0].Index)
+
+	// The oldest batches are dropped
+	b.Publish(testEvents(14))
+	batches, _ = b.Since(0)
+	require.Len(batches, 2)
+	require.Equal(uint64(12), batches[0].Index)
+	require.Equal(uint64(14), b.LastIndex())
+
+	batches, _ = b.Since(14)
+	require.Empty(batches)
+}
@@ -0,0 +1,198 @@
+package nomad
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"math/rand"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/acl"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/ugorji/go/codec"
+)
+
+const (
+	// eventStreamHeartbeatInterval is the interval empty JSON objects are
+	// streamed at to keep the idle event streams open.
+	eventStreamHeartbeatInterval = 10 * time.Second
+)
+
+// Event endpoint is used for streaming the cluster events.
+type Event struct {
+	srv    *Server
+	logger log.Logger
+}
+
+func (e *Event) register() {
+	e.srv.streamingRpcs.Register("Event.Stream", e.stream)
+}
+
+// stream streams the events of the topics published after the index of the
+// request as newline delimited JSON batches of events. Every server streams
+// the events it applies, so requests are only forwarded to other regions.
+func (e *Event) stream(conn io.ReadWriteCloser) {
+	defer conn.Close()
+	defer metrics.MeasureSince([]string{"nomad", "event", "stream"}, time.Now())
+
+	var args structs.EventStreamRequest
+	decoder := codec.NewDecoder(conn, structs.MsgpackHandle)
+	encoder := codec.NewEncoder(conn, structs.MsgpackHandle)
+
+	if err := decoder.Decode(&args); err != nil {
+		handleStreamResultError(err, helper.Int64ToPtr(500), encoder)
+		return
+	}
+
+	// Forward to a server of the region
+	if region := args.RequestRegion(); region != e.srv.Region() {
+		e.forwardRegion(conn, encoder, &args, region)
+		return
+	}
+
+	aclObj, err := e.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		handleStreamResultError(err, nil, encoder)
+		return
+	}
+
+	// Stream all the events by default
+	if len(args.Topics) == 0 {
+		args.Topics = map[structs.Topic][]string{structs.TopicAll: {"*"}}
+	}
+
+	// Only stream the new events unless resuming after an index
+	broker := e.srv.eventBroker
+	index := args.Index
+	if index == 0 {
+		index = broker.LastIndex()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Create a goroutine to detect the remote side closing
+	go func() {
+		for {
+			if _, err := conn.Read(nil); err != nil {
+				cancel()
+				return
+			}
+		}
+	}()
+
+	send := func(payload []byte) bool {
+		resp := cstructs.StreamErrWrapper{Payload: append(payload, '\n')}
+		if err := encoder.Encode(resp); err != nil {
+			return false
+		}
+		encoder.Reset(conn)
+		return true
+	}
+
+	heartbeat := time.NewTicker(eventStreamHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		batches, notifyCh := broker.Since(index)
+		for _, batch := range batches {
+			index = batch.Index
+
+			events := e.filter(&args, aclObj, batch)
+			if len(events) == 0 {
+				continue
+			}
+
+			buf, err := json.Marshal(&structs.Events{Index: batch.Index, Events: events})
+			if err != nil {
+				e.logger.Error("failed to encode events", "index", batch.Index, "error", err)
+				continue
+			}
+			if !send(buf) {
+				return
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-e.srv.shutdownCh:
+			return
+		case <-notifyCh:
+		case <-heartbeat.C:
+			if !send([]byte("{}")) {
+				return
+			}
+		}
+	}
+}
+
+// filter returns the events of the batch matching the topics and namespace
+// of the request that the token is allowed to read.
+func (e *Event) filter(args *structs.EventStreamRequest, aclObj *acl.ACL, batch *structs.Events) []*structs.Event {
+	namespace := args.RequestNamespace()
+
+	var out []*structs.Event
+	for _, event := range batch.Events {
+		if !args.Matches(event) {
+			continue
+		}
+
+		// Nodes aren't namespaced
+		if event.Topic == structs.TopicNode {
+			if aclObj != nil && !aclObj.AllowNodeRead() {
+				continue
+			}
+			out = append(out, event)
+			continue
+		}
+
+		if namespace != "*" && event.Namespace != namespace {
+			continue
+		}
+		if aclObj != nil && !aclObj.AllowNsOp(event.Namespace, acl.NamespaceCapabilityReadJob) {
+			continue
+		}
+		out = append(out, event)
+	}
+	return out
+}
+
+// forwardRegion forwards the stream to a server of the region, bridging the
+// connection to it.
+func (e *Event) forwardRegion(conn io.ReadWriteCloser, encoder *codec.Encoder,
+	args *structs.EventStreamRequest, region string) {
+
+	e.srv.peerLock.RLock()
+	servers := e.srv.peers[region]
+	var server *serverParts
+	if len(servers) != 0 {
+		server = servers[rand.Intn(len(servers))]
+	}
+	e.srv.peerLock.RUnlock()
+
+	if server == nil {
+		handleStreamResultError(structs.ErrNoRegionPath, nil, encoder)
+		return
+	}
+
+	srvConn, err := e.srv.streamingRpc(server, "Event.Stream")
+	if err != nil {
+		handleStreamResultError(err, nil, encoder)
+		return
+	}
+	defer srvConn.Close()
+
+	// Send the request.
+	outEncoder := codec.NewEncoder(srvConn, structs.MsgpackHandle)
+	if err := outEncoder.Encode(args); err != nil {
+		handleStreamResultError(err, nil, encoder)
+		return
+	}
+
+	structs.Bridge(conn, srvConn)
+}
@@ -0,0 +1,215 @@
+package nomad
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+	"github.com/ugorji/go/codec"
+)
+
+// eventStream starts the Event.Stream handler of the server with the request
+// and returns the channels of the decoded batches of events.
+func eventStream(t *testing.T, s *Server, req *structs.EventStreamRequest) (<-chan *structs.Events, <-chan error, func()) {
+	handler, err := s.StreamingRpcHandler("Event.Stream")
+	require.Nil(t, err)
+
+	// Create a pipe
+	p1, p2 := net.Pipe()
+
+	errCh := make(chan error, 1)
+	eventsCh := make(chan *structs.Events, 10)
+
+	// Start the handler
+	go handler(p2)
+
+	// Start the decoder
+	go func() {
+		decoder := codec.NewDecoder(p1, structs.MsgpackHandle)
+		for {
+			var msg cstructs.StreamErrWrapper
+			if err := decoder.Decode(&msg); err != nil {
+				if err == io.EOF || strings.Contains(err.Error(), "closed") {
+					return
+				}
+				errCh <- fmt.Errorf("error decoding: %v", err)
+				return
+			}
+			if msg.Error != nil {
+				errCh <- msg.Error
+				return
+			}
+
+			var events structs.Events
+			if err := json.Unmarshal(msg.Payload, &events); err != nil {
+				errCh <- fmt.Errorf("error decoding events: %v", err)
+				return
+			}
+			eventsCh <- &events
+		}
+	}()
+
+	// Send the request
+	encoder := codec.NewEncoder(p1, structs.MsgpackHandle)
+	require.Nil(t, encoder.Encode(req))
+
+	return eventsCh, errCh, func() {
+		p1.Close()
+		p2.Close()
+	}
+}
+
+func TestEvent_Stream(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s := TestServer(t, nil)
+	defer s.Shutdown()
+	testutil.WaitForLeader(t, s.RPC)
+	start, err := s.fsm.State().LatestIndex()
+	require.NoError(err)
+
+	// Stream the events of a single job
+	job := mock.Job()
+	req := &structs.EventStreamRequest{
+		Topics:       map[structs.Topic][]string{structs.TopicJob: {job.ID}},
+		Index:        start,
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	eventsCh, errCh, cleanup := eventStream(t, s, req)
+	defer cleanup()
+
+	// Register another job and the streamed job
+	register := func(job *structs.Job) {
+		req := &structs.JobRegisterRequest{
+			Job:          job,
+			WriteRequest: structs.WriteRequest{Region: "global", Namespace: job.Namespace},
+		}
+		var resp structs.JobRegisterResponse
+		require.NoError(s.RPC("Job.Register", req, &resp))
+	}
+	register(mock.Job())
+	register(job)
+
+	var index uint64
+	select {
+	case err := <-errCh:
+		t.Fatal(err)
+	case events := <-eventsCh:
+		require.Len(events.Events, 1)
+		event := events.Events[0]
+		require.Equal(structs.TopicJob, event.Topic)
+		require.Equal(structs.TypeJobRegistered, event.Type)
+		require.Equal(job.ID, event.Key)
+		index = events.Index
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for events")
+	}
+
+	// Resume the stream of all the events after the index of the job
+	// registration
+	cleanup()
+
+	req = &structs.EventStreamRequest{
+		Index:        index,
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	eventsCh, errCh, cleanup = eventStream(t, s, req)
+	defer cleanup()
+
+	// Nodes are registered through the FSM to publish events
+	node := mock.Node()
+	nodeReq := &structs.NodeRegisterRequest{
+		Node:         node,
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var nodeResp structs.NodeUpdateResponse
+	require.NoError(s.RPC("Node.Register", nodeReq, &nodeResp))
+
+	deadline := time.After(5 * time.Second)
+	for {
+		select {
+		case err := <-errCh:
+			t.Fatal(err)
+		case events := <-eventsCh:
+			require.True(events.Index > index)
+			for _, event := range events.Events {
+				if event.Topic != structs.TopicNode {
+					continue
+				}
+				require.Equal(structs.TypeNodeRegistration, event.Type)
+				require.Equal(node.ID, event.Key)
+				require.NotContains(string(mustJSON(t, event)), node.SecretID)
+				return
+			}
+		case <-deadline:
+			t.Fatal("timeout waiting for events")
+		}
+	}
+}
+
+func TestEvent_Stream_ACL(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s, root := TestACLServer(t, nil)
+	defer s.Shutdown()
+	testutil.WaitForLeader(t, s.RPC)
+	state := s.fsm.State()
+
+	// A token allowed to read the nodes only
+	token := mock.CreatePolicyAndToken(t, state, 1001, "node", mock.NodePolicy("read"))
+
+	// The policy is written to the state directly, so resume the stream
+	// after the last Raft index
+	start := s.raft.AppliedIndex()
+
+	req := &structs.EventStreamRequest{
+		Index:        start,
+		QueryOptions: structs.QueryOptions{Region: "global", AuthToken: token.SecretID},
+	}
+	eventsCh, errCh, cleanup := eventStream(t, s, req)
+	defer cleanup()
+
+	// The job events are filtered out
+	job := mock.Job()
+	register := &structs.JobRegisterRequest{
+		Job:          job,
+		WriteRequest: structs.WriteRequest{Region: "global", Namespace: job.Namespace, AuthToken: root.SecretID},
+	}
+	var resp structs.JobRegisterResponse
+	require.NoError(s.RPC("Job.Register", register, &resp))
+
+	node := mock.Node()
+	nodeReq := &structs.NodeRegisterRequest{
+		Node:         node,
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var nodeResp structs.NodeUpdateResponse
+	require.NoError(s.RPC("Node.Register", nodeReq, &nodeResp))
+
+	select {
+	case err := <-errCh:
+		t.Fatal(err)
+	case events := <-eventsCh:
+		require.Len(events.Events, 1)
+		require.Equal(structs.TopicNode, events.Events[0].Topic)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for events")
+	}
+}
+
+func mustJSON(t *testing.T, v interface{}) []byte {
+	buf, err := json.Marshal(v)
+	require.NoError(t, err)
+	return buf
+}
@@ -45,6 +45,17 @@ const (
 	ACLPolicySnapshot
 	ACLTokenSnapshot
 	SchedulerConfigSnapshot
+	NodePoolSnapshot
+	NamespaceSnapshot
+	QuotaSpecSnapshot
+	VariableSnapshot
+	RootKeySnapshot
+	ScalingEventsSnapshot
+	ServiceRegistrationSnapshot
+	ACLAuthMethodSnapshot
+	ACLBindingRuleSnapshot
+	VolumeSnapshot
+	ACLRoleSnapshot
 )
 
 // LogApplier is the definition of a function that can apply a Raft log
@@ -68,6 +79,7 @@ type nomadFSM struct {
 	evalBroker         *EvalBroker
 	blockedEvals       *BlockedEvals
 	periodicDispatcher *PeriodicDispatch
+	eventBroker        *EventBroker
 	logger             log.Logger
 	state              *state.StateStore
 	timetable          *TimeTable
@@ -81,6 +93,10 @@ type nomadFSM struct {
 	// enterpriseRestorers holds the set of enterprise only snapshot restorers
 	enterpriseRestorers SnapshotRestorers
 
+	// events holds the events of the log being applied, published to the
+	// event broker once the log is applied.
+	events []*structs.Event
+
 	// stateLock is only used to protect outside callers to State() from
 	// racing with Restore(), which is called by Raft (it puts in a totally
 	// new state store). Everything internal here is synchronized by the
@@ -113,19 +129,28 @@ type FSMConfig struct {
 	// be added to.
 	Blocked *BlockedEvals
 
+	// EventBroker is the broker the events of the applied logs are
+	// published to. Events aren't published if nil.
+	EventBroker *EventBroker
+
 	// Logger is the logger used by the FSM
 	Logger log.Logger
 
 	// Region is the region of the server embedding the FSM
 	Region string
+
+	// NodeEventRetention is the number of most recent events retained for
+	// each node
+	NodeEventRetention int
 }
 
 // NewFSMPath is used to construct a new FSM with a blank state
 func NewFSM(config *FSMConfig) (*nomadFSM, error) {
 	// Create a state store
 	sconfig := &state.StateStoreConfig{
-		Logger: config.Logger,
-		Region: config.Region,
+		Logger:             config.Logger,
+		Region:             config.Region,
+		NodeEventRetention: config.NodeEventRetention,
 	}
 	state, err := state.NewStateStore(sconfig)
 	if err != nil {
@@ -136,6 +161,7 @@ func NewFSM(config *FSMConfig) (*nomadFSM, error) {
 		evalBroker:          config.EvalBroker,
 		periodicDispatcher:  config.Periodic,
 		blockedEvals:        config.Blocked,
+		eventBroker:         config.EventBroker,
 		logger:              config.Logger.Named("fsm"),
 		config:              config,
 		state:               state,
@@ -177,6 +203,9 @@ func (n *nomadFSM) Apply(log *raft.Log) interface{} {
 	// Witness this write
 	n.timetable.Witness(log.Index, time.Now().UTC())
 
+	// Publish the events of the changes once applied
+	defer n.publishEvents(log.Index)
+
 	// Check if this message type should be ignored when unknown. This is
 	// used so that new commands can be added with developer control if older
 	// versions can safely ignore the command, or if they should crash.
@@ -249,6 +278,58 @@ func (n *nomadFSM) Apply(log *raft.Log) interface{} {
 		return n.applyBatchDrainUpdate(buf[1:], log.Index)
 	case structs.SchedulerConfigRequestType:
 		return n.applySchedulerConfigUpdate(buf[1:], log.Index)
+	case structs.NodePoolUpsertRequestType:
+		return n.applyNodePoolUpsert(buf[1:], log.Index)
+	case structs.NodePoolDeleteRequestType:
+		return n.applyNodePoolDelete(buf[1:], log.Index)
+	case structs.NamespaceUpsertRequestType:
+		return n.applyNamespaceUpsert(buf[1:], log.Index)
+	case structs.NamespaceDeleteRequestType:
+		return n.applyNamespaceDelete(buf[1:], log.Index)
+	case structs.QuotaSpecUpsertRequestType:
+		return n.applyQuotaSpecUpsert(buf[1:], log.Index)
+	case structs.QuotaSpecDeleteRequestType:
+		return n.applyQuotaSpecDelete(buf[1:], log.Index)
+	case structs.NodeUpdateDynamicMetaRequestType:
+		return n.applyNodeDynamicMetaUpdate(buf[1:], log.Index)
+	case structs.JobBatchRegisterRequestType:
+		return n.applyBatchRegisterJob(buf[1:], log.Index)
+	case structs.VariablesApplyRequestType:
+		return n.applyVariableUpsert(buf[1:], log.Index)
+	case structs.VariablesDeleteRequestType:
+		return n.applyVariableDelete(buf[1:], log.Index)
+	case structs.RootKeyUpsertRequestType:
+		return n.applyRootKeyUpsert(buf[1:], log.Index)
+	case structs.ScalingEventRegisterRequestType:
+		return n.applyUpsertScalingEvent(buf[1:], log.Index)
+	case structs.ServiceRegistrationUpsertRequestType:
+		return n.applyUpsertServiceRegistrations(buf[1:], log.Index)
+	case structs.ServiceRegistrationDeleteRequestType:
+		return n.applyDeleteServiceRegistrations(buf[1:], log.Index)
+	case structs.ACLAuthMethodsUpsertRequestType:
+		return n.applyACLAuthMethodsUpsert(buf[1:], log.Index)
+	case structs.ACLAuthMethodsDeleteRequestType:
+		return n.applyACLAuthMethodsDelete(buf[1:], log.Index)
+	case structs.ACLBindingRulesUpsertRequestType:
+		return n.applyACLBindingRulesUpsert(buf[1:], log.Index)
+	case structs.ACLBindingRulesDeleteRequestType:
+		return n.applyACLBindingRulesDelete(buf[1:], log.Index)
+	case structs.VolumeRegisterRequestType:
+		return n.applyVolumeRegister(buf[1:], log.Index)
+	case structs.VolumeDeleteRequestType:
+		return n.applyVolumeDelete(buf[1:], log.Index)
+	case structs.VolumeClaimRequestType:
+		return n.applyVolumeClaim(buf[1:], log.Index)
+	case structs.VolumeDetachRequestType:
+		return n.applyVolumeDetach(buf[1:], log.Index)
+	case structs.RootKeyDeleteRequestType:
+		return n.applyRootKeyDelete(buf[1:], log.Index)
+	case structs.ACLRolesUpsertRequestType:
+		return n.applyACLRolesUpsert(buf[1:], log.Index)
+	case structs.ACLRolesDeleteRequestType:
+		return n.applyACLRolesDelete(buf[1:], log.Index)
+	case structs.EvalUpdateBatchRequestType:
+		return n.applyUpdateEvalBatch(buf[1:], log.Index)
 	}
 
 	// Check enterprise only message types.
@@ -265,6 +346,86 @@ func (n *nomadFSM) Apply(log *raft.Log) interface{} {
 	panic(fmt.Errorf("failed to apply request: %#v", buf))
 }
 
+// emit adds the events to the events of the log being applied.
+func (n *nomadFSM) emit(events ...*structs.Event) {
+	if n.eventBroker == nil {
+		return
+	}
+	n.events = append(n.events, events...)
+}
+
+// publishEvents publishes the events of the log applied at the index to the
+// event broker.
+func (n *nomadFSM) publishEvents(index uint64) {
+	if len(n.events) == 0 {
+		return
+	}
+	for _, event := range n.events {
+		event.Index = index
+	}
+	n.eventBroker.Publish(&structs.Events{Index: index, Events: n.events})
+	n.events = nil
+}
+
+// emitNodeEvent emits the event of a change of the node as stored.
+func (n *nomadFSM) emitNodeEvent(eventType, nodeID string) {
+	if n.eventBroker == nil {
+		return
+	}
+	node, err := n.state.NodeByID(nil, nodeID)
+	if err != nil {
+		n.logger.Error("looking up node for event failed", "node_id", nodeID, "error", err)
+		return
+	}
+	n.emit(nodeEvent(eventType, nodeID, node))
+}
+
+// emitJobEvent emits the event of a change of the job as stored.
+func (n *nomadFSM) emitJobEvent(eventType, namespace, jobID string) {
+	if n.eventBroker == nil {
+		return
+	}
+	job, err := n.state.JobByID(nil, namespace, jobID)
+	if err != nil {
+		n.logger.Error("looking up job for event failed", "job", jobID, "namespace", namespace, "error", err)
+		return
+	}
+	n.emit(jobEvent(eventType, namespace, jobID, job))
+}
+
+// emitAllocEvents emits the update events of the allocations as stored.
+func (n *nomadFSM) emitAllocEvents(allocs []*structs.Allocation) {
+	if n.eventBroker == nil {
+		return
+	}
+	for _, alloc := range allocs {
+		existing, err := n.state.AllocByID(nil, alloc.ID)
+		if err != nil {
+			n.logger.Error("looking up alloc for event failed", "alloc_id", alloc.ID, "error", err)
+			continue
+		}
+		if existing != nil {
+			n.emit(allocEvent(existing))
+		}
+	}
+}
+
+// emitDeploymentEvent emits the event of a change of the deployment as
+// stored.
+func (n *nomadFSM) emitDeploymentEvent(eventType, deploymentID string) {
+	if n.eventBroker == nil {
+		return
+	}
+	deployment, err := n.state.DeploymentByID(nil, deploymentID)
+	if err != nil {
+		n.logger.Error("looking up deployment for event failed", "deployment_id", deploymentID, "error", err)
+		return
+	}
+	if deployment != nil {
+		n.emit(deploymentEvent(eventType, deployment))
+	}
+}
+
 func (n *nomadFSM) applyUpsertNode(buf []byte, index uint64) interface{} {
 	defer metrics.MeasureSince([]string{"nomad", "fsm", "register_node"}, time.Now())
 	var req structs.NodeRegisterRequest
@@ -279,6 +440,7 @@ func (n *nomadFSM) applyUpsertNode(buf []byte, index uint64) interface{} {
 		n.logger.Error("UpsertNode failed", "error", err)
 		return err
 	}
+	n.emitNodeEvent(structs.TypeNodeRegistration, req.Node.ID)
 
 	// Unblock evals for the nodes computed node class if it is in a ready
 	// state.
@@ -300,6 +462,7 @@ func (n *nomadFSM) applyDeregisterNode(buf []byte, index uint64) interface{} {
 		n.logger.Error("DeleteNode failed", "error", err)
 		return err
 	}
+	n.emit(nodeEvent(structs.TypeNodeDeregistration, req.NodeID, nil))
 	return nil
 }
 
@@ -314,6 +477,7 @@ func (n *nomadFSM) applyStatusUpdate(buf []byte, index uint64) interface{} {
 		n.logger.Error("UpdateNodeStatus failed", "error", err)
 		return err
 	}
+	n.emitNodeEvent(structs.TypeNodeStatusUpdate, req.NodeID)
 
 	// Unblock evals for the nodes computed node class if it is in a ready
 	// state.
@@ -356,6 +520,7 @@ func (n *nomadFSM) applyDrainUpdate(buf []byte, index uint64) interface{} {
 		n.logger.Error("UpdateNodeDrain failed", "error", err)
 		return err
 	}
+	n.emitNodeEvent(structs.TypeNodeDrain, req.NodeID)
 	return nil
 }
 
@@ -370,6 +535,9 @@ func (n *nomadFSM) applyBatchDrainUpdate(buf []byte, index uint64) interface{} {
 		n.logger.Error("BatchUpdateNodeDrain failed", "error", err)
 		return err
 	}
+	for nodeID := range req.Updates {
+		n.emitNodeEvent(structs.TypeNodeDrain, nodeID)
+	}
 	return nil
 }
 
@@ -402,6 +570,32 @@ func (n *nomadFSM) applyNodeEligibilityUpdate(buf []byte, index uint64) interfac
 	return nil
 }
 
+func (n *nomadFSM) applyNodeDynamicMetaUpdate(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "node_dynamic_meta_update"}, time.Now())
+	var req structs.NodeUpdateDynamicMetaRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpdateNodeDynamicMeta(index, req.NodeID, req.Set, req.Unset); err != nil {
+		n.logger.Error("UpdateNodeDynamicMeta failed", "error", err)
+		return err
+	}
+
+	// Unblock evals for the new computed node class of the node since the
+	// metadata may now satisfy their constraints.
+	node, err := n.state.NodeByID(nil, req.NodeID)
+	if err != nil {
+		n.logger.Error("UpdateNodeDynamicMeta failed to lookup node", "node_id", req.NodeID, "error", err)
+		return err
+	}
+	if node != nil && node.Ready() {
+		n.blockedEvals.Unblock(node.ComputedClass, index)
+	}
+
+	return nil
+}
+
 func (n *nomadFSM) applyUpsertJob(buf []byte, index uint64) interface{} {
 	defer metrics.MeasureSince([]string{"nomad", "fsm", "register_job"}, time.Now())
 	var req structs.JobRegisterRequest
@@ -422,6 +616,7 @@ func (n *nomadFSM) applyUpsertJob(buf []byte, index uint64) interface{} {
 		n.logger.Error("UpsertJob failed", "error", err)
 		return err
 	}
+	n.emitJobEvent(structs.TypeJobRegistered, req.Job.Namespace, req.Job.ID)
 
 	// We always add the job to the periodic dispatcher because there is the
 	// possibility that the periodic spec was removed and then we should stop
@@ -502,7 +697,7 @@ func (n *nomadFSM) applyDeregisterJob(buf []byte, index uint64) interface{} {
 		panic(fmt.Errorf("failed to decode request: %v", err))
 	}
 
-	return n.state.WithWriteTransaction(func(tx state.Txn) error {
+	err := n.state.WithWriteTransaction(func(tx state.Txn) error {
 		if err := n.handleJobDeregister(index, req.JobID, req.Namespace, req.Purge, tx); err != nil {
 			n.logger.Error("deregistering job failed", "error", err)
 			return err
@@ -510,6 +705,12 @@ func (n *nomadFSM) applyDeregisterJob(buf []byte, index uint64) interface{} {
 
 		return nil
 	})
+	if err != nil {
+		return err
+	}
+
+	n.emitJobEvent(structs.TypeJobDeregistered, req.Namespace, req.JobID)
+	return nil
 }
 
 func (n *nomadFSM) applyBatchDeregisterJob(buf []byte, index uint64) interface{} {
@@ -543,6 +744,52 @@ func (n *nomadFSM) applyBatchDeregisterJob(buf []byte, index uint64) interface{}
 	}
 
 	// perform the side effects outside the transactions
+	for jobNS := range req.Jobs {
+		n.emitJobEvent(structs.TypeJobDeregistered, jobNS.Namespace, jobNS.ID)
+	}
+	n.handleUpsertedEvals(req.Evals)
+	return nil
+}
+
+func (n *nomadFSM) applyBatchRegisterJob(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "batch_register_job"}, time.Now())
+	var req structs.JobBatchRegisterRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	// Perform all store updates atomically so that either every job and its
+	// evaluation is registered or none. Periodic jobs are not registered in
+	// batches as they must be tracked by the periodic dispatcher.
+	err := n.state.WithWriteTransaction(func(tx state.Txn) error {
+		for _, job := range req.Jobs {
+			job.Canonicalize()
+			if err := n.state.UpsertJobTxn(index, job, tx); err != nil {
+				n.logger.Error("UpsertJob failed", "job", job.NamespacedID(), "error", err)
+				return err
+			}
+		}
+
+		// The evaluations are created along with the jobs of the batch
+		for _, eval := range req.Evals {
+			eval.JobModifyIndex = index
+		}
+		if err := n.state.UpsertEvalsTxn(index, req.Evals, tx); err != nil {
+			n.logger.Error("UpsertEvals failed", "error", err)
+			return err
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	// perform the side effects outside the transactions
+	for _, job := range req.Jobs {
+		n.emitJobEvent(structs.TypeJobRegistered, job.Namespace, job.ID)
+	}
 	n.handleUpsertedEvals(req.Evals)
 	return nil
 }
@@ -599,6 +846,33 @@ func (n *nomadFSM) applyUpdateEval(buf []byte, index uint64) interface{} {
 	return n.upsertEvals(index, req.Evals)
 }
 
+// applyUpdateEvalBatch upserts the evaluations of a batch of updates in a
+// single transaction. If the transaction fails, the updates are applied one by
+// one so that only the failing updates are rejected, and the error of each
+// update is returned in order.
+func (n *nomadFSM) applyUpdateEvalBatch(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "update_eval_batch"}, time.Now())
+	var req structs.EvalUpdateBatchRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	var evals []*structs.Evaluation
+	for _, update := range req.Updates {
+		evals = append(evals, update.Evals...)
+	}
+	if err := n.state.UpsertEvals(index, evals); err == nil {
+		n.handleUpsertedEvals(evals)
+		return nil
+	}
+
+	errs := make([]error, len(req.Updates))
+	for i, update := range req.Updates {
+		errs[i] = n.upsertEvals(index, update.Evals)
+	}
+	return errs
+}
+
 func (n *nomadFSM) upsertEvals(index uint64, evals []*structs.Evaluation) error {
 	if err := n.state.UpsertEvals(index, evals); err != nil {
 		n.logger.Error("UpsertEvals failed", "error", err)
@@ -622,6 +896,7 @@ func (n *nomadFSM) handleUpsertedEval(eval *structs.Evaluation) {
 	if eval == nil {
 		return
 	}
+	n.emit(evalEvent(eval))
 
 	if eval.ShouldEnqueue() {
 		n.evalBroker.Enqueue(eval)
@@ -683,6 +958,7 @@ func (n *nomadFSM) applyAllocUpdate(buf []byte, index uint64) interface{} {
 		n.logger.Error("UpsertAllocs failed", "error", err)
 		return err
 	}
+	n.emitAllocEvents(req.Alloc)
 	return nil
 }
 
@@ -707,19 +983,13 @@ func (n *nomadFSM) applyAllocClientUpdate(buf []byte, index uint64) interface{}
 		}
 	}
 
-	// Update all the client allocations
-	if err := n.state.UpdateAllocsFromClient(index, req.Alloc); err != nil {
-		n.logger.Error("UpdateAllocFromClient failed", "error", err)
+	// Update all the client allocations and any evals in one transaction
+	if err := n.state.UpdateAllocsAndEvalsFromClient(index, req.Alloc, req.Evals); err != nil {
+		n.logger.Error("UpdateAllocsAndEvalsFromClient failed", "error", err)
 		return err
 	}
-
-	// Update any evals
-	if len(req.Evals) > 0 {
-		if err := n.upsertEvals(index, req.Evals); err != nil {
-			n.logger.Error("applyAllocClientUpdate failed to update evaluations", "error", err)
-			return err
-		}
-	}
+	n.emitAllocEvents(req.Alloc)
+	n.handleUpsertedEvals(req.Evals)
 
 	// Unblock evals for the nodes computed node class if the client has
 	// finished running an allocation.
@@ -835,6 +1105,14 @@ func (n *nomadFSM) applyPlanResults(buf []byte, index uint64) interface{} {
 		n.logger.Error("ApplyPlan failed", "error", err)
 		return err
 	}
+	n.emitAllocEvents(req.Alloc)
+	n.emitAllocEvents(req.NodePreemptions)
+	if req.Deployment != nil {
+		n.emitDeploymentEvent(structs.TypeDeploymentStatusUpdate, req.Deployment.ID)
+	}
+	for _, update := range req.DeploymentUpdates {
+		n.emitDeploymentEvent(structs.TypeDeploymentStatusUpdate, update.DeploymentID)
+	}
 
 	// Add evals for jobs that were preempted
 	n.handleUpsertedEvals(req.PreemptionEvals)
@@ -854,6 +1132,7 @@ func (n *nomadFSM) applyDeploymentStatusUpdate(buf []byte, index uint64) interfa
 		n.logger.Error("UpsertDeploymentStatusUpdate failed", "error", err)
 		return err
 	}
+	n.emitDeploymentEvent(structs.TypeDeploymentStatusUpdate, req.DeploymentUpdate.DeploymentID)
 
 	n.handleUpsertedEval(req.Eval)
 	return nil
@@ -871,6 +1150,7 @@ func (n *nomadFSM) applyDeploymentPromotion(buf []byte, index uint64) interface{
 		n.logger.Error("UpsertDeploymentPromotion failed", "error", err)
 		return err
 	}
+	n.emitDeploymentEvent(structs.TypeDeploymentPromotion, req.DeploymentID)
 
 	n.handleUpsertedEval(req.Eval)
 	return nil
@@ -926,176 +1206,573 @@ func (n *nomadFSM) applyJobStability(buf []byte, index uint64) interface{} {
 	return nil
 }
 
-// applyACLPolicyUpsert is used to upsert a set of policies
-func (n *nomadFSM) applyACLPolicyUpsert(buf []byte, index uint64) interface{} {
-	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_acl_policy_upsert"}, time.Now())
-	var req structs.ACLPolicyUpsertRequest
+// applyNodePoolUpsert is used to upsert a set of node pools
+func (n *nomadFSM) applyNodePoolUpsert(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_node_pool_upsert"}, time.Now())
+	var req structs.NodePoolUpsertRequest
 	if err := structs.Decode(buf, &req); err != nil {
 		panic(fmt.Errorf("failed to decode request: %v", err))
 	}
 
-	if err := n.state.UpsertACLPolicies(index, req.Policies); err != nil {
-		n.logger.Error("UpsertACLPolicies failed", "error", err)
+	if err := n.state.UpsertNodePools(index, req.NodePools); err != nil {
+		n.logger.Error("UpsertNodePools failed", "error", err)
 		return err
 	}
 	return nil
 }
 
-// applyACLPolicyDelete is used to delete a set of policies
-func (n *nomadFSM) applyACLPolicyDelete(buf []byte, index uint64) interface{} {
-	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_acl_policy_delete"}, time.Now())
-	var req structs.ACLPolicyDeleteRequest
+// applyNodePoolDelete is used to delete a set of node pools
+func (n *nomadFSM) applyNodePoolDelete(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_node_pool_delete"}, time.Now())
+	var req structs.NodePoolDeleteRequest
 	if err := structs.Decode(buf, &req); err != nil {
 		panic(fmt.Errorf("failed to decode request: %v", err))
 	}
 
-	if err := n.state.DeleteACLPolicies(index, req.Names); err != nil {
-		n.logger.Error("DeleteACLPolicies failed", "error", err)
+	if err := n.state.DeleteNodePools(index, req.Names); err != nil {
+		n.logger.Error("DeleteNodePools failed", "error", err)
 		return err
 	}
 	return nil
 }
 
-// applyACLTokenUpsert is used to upsert a set of policies
-func (n *nomadFSM) applyACLTokenUpsert(buf []byte, index uint64) interface{} {
-	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_acl_token_upsert"}, time.Now())
-	var req structs.ACLTokenUpsertRequest
+// applyNamespaceUpsert is used to upsert a set of namespaces
+func (n *nomadFSM) applyNamespaceUpsert(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_namespace_upsert"}, time.Now())
+	var req structs.NamespaceUpsertRequest
 	if err := structs.Decode(buf, &req); err != nil {
 		panic(fmt.Errorf("failed to decode request: %v", err))
 	}
 
-	if err := n.state.UpsertACLTokens(index, req.Tokens); err != nil {
-		n.logger.Error("UpsertACLTokens failed", "error", err)
+	// Gather the quotas the namespaces accounted against before the update,
+	// since moving a namespace off a quota may free it up
+	var quotas []string
+	for _, ns := range req.Namespaces {
+		existing, err := n.state.NamespaceByName(nil, ns.Name)
+		if err != nil {
+			n.logger.Error("namespace lookup failed", "namespace", ns.Name, "error", err)
+			return err
+		}
+		if existing != nil && existing.Quota != "" && existing.Quota != ns.Quota {
+			quotas = append(quotas, existing.Quota)
+		}
+	}
+
+	if err := n.state.UpsertNamespaces(index, req.Namespaces); err != nil {
+		n.logger.Error("UpsertNamespaces failed", "error", err)
 		return err
 	}
+
+	// Unblock evals blocked on the quotas that were freed up
+	for _, quota := range quotas {
+		n.blockedEvals.UnblockQuota(quota, index)
+	}
 	return nil
 }
 
-// applyACLTokenDelete is used to delete a set of policies
-func (n *nomadFSM) applyACLTokenDelete(buf []byte, index uint64) interface{} {
-	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_acl_token_delete"}, time.Now())
-	var req structs.ACLTokenDeleteRequest
+// applyNamespaceDelete is used to delete a set of namespaces
+func (n *nomadFSM) applyNamespaceDelete(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_namespace_delete"}, time.Now())
+	var req structs.NamespaceDeleteRequest
 	if err := structs.Decode(buf, &req); err != nil {
 		panic(fmt.Errorf("failed to decode request: %v", err))
 	}
 
-	if err := n.state.DeleteACLTokens(index, req.AccessorIDs); err != nil {
-		n.logger.Error("DeleteACLTokens failed", "error", err)
+	if err := n.state.DeleteNamespaces(index, req.Namespaces); err != nil {
+		n.logger.Error("DeleteNamespaces failed", "error", err)
 		return err
 	}
 	return nil
 }
 
-// applyACLTokenBootstrap is used to bootstrap an ACL token
-func (n *nomadFSM) applyACLTokenBootstrap(buf []byte, index uint64) interface{} {
-	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_acl_token_bootstrap"}, time.Now())
-	var req structs.ACLTokenBootstrapRequest
+// applyQuotaSpecUpsert is used to upsert a set of quota specifications
+func (n *nomadFSM) applyQuotaSpecUpsert(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_quota_spec_upsert"}, time.Now())
+	var req structs.QuotaSpecUpsertRequest
 	if err := structs.Decode(buf, &req); err != nil {
 		panic(fmt.Errorf("failed to decode request: %v", err))
 	}
 
-	if err := n.state.BootstrapACLTokens(index, req.ResetIndex, req.Token); err != nil {
-		n.logger.Error("BootstrapACLToken failed", "error", err)
+	if err := n.state.UpsertQuotaSpecs(index, req.Quotas); err != nil {
+		n.logger.Error("UpsertQuotaSpecs failed", "error", err)
 		return err
 	}
+
+	// The limits may have been raised so unblock evals blocked on the quotas
+	for _, spec := range req.Quotas {
+		n.blockedEvals.UnblockQuota(spec.Name, index)
+	}
 	return nil
 }
 
-func (n *nomadFSM) applyAutopilotUpdate(buf []byte, index uint64) interface{} {
-	var req structs.AutopilotSetConfigRequest
+// applyQuotaSpecDelete is used to delete a set of quota specifications
+func (n *nomadFSM) applyQuotaSpecDelete(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_quota_spec_delete"}, time.Now())
+	var req structs.QuotaSpecDeleteRequest
 	if err := structs.Decode(buf, &req); err != nil {
 		panic(fmt.Errorf("failed to decode request: %v", err))
 	}
-	defer metrics.MeasureSince([]string{"nomad", "fsm", "autopilot"}, time.Now())
 
-	if req.CAS {
-		act, err := n.state.AutopilotCASConfig(index, req.Config.ModifyIndex, &req.Config)
-		if err != nil {
-			return err
-		}
-		return act
+	if err := n.state.DeleteQuotaSpecs(index, req.Names); err != nil {
+		n.logger.Error("DeleteQuotaSpecs failed", "error", err)
+		return err
 	}
-	return n.state.AutopilotSetConfig(index, &req.Config)
+	return nil
 }
 
-func (n *nomadFSM) applySchedulerConfigUpdate(buf []byte, index uint64) interface{} {
-	var req structs.SchedulerSetConfigRequest
+// applyVariableUpsert is used to create or update an encrypted variable
+func (n *nomadFSM) applyVariableUpsert(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_variable_upsert"}, time.Now())
+	var req structs.VariablesApplyRequest
 	if err := structs.Decode(buf, &req); err != nil {
 		panic(fmt.Errorf("failed to decode request: %v", err))
 	}
-	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_scheduler_config"}, time.Now())
 
-	if req.CAS {
-		applied, err := n.state.SchedulerCASConfig(index, req.Config.ModifyIndex, &req.Config)
-		if err != nil {
-			return err
-		}
-		return applied
+	if err := n.state.UpsertVariable(index, req.Data, req.CheckIndex); err != nil {
+		n.logger.Error("UpsertVariable failed", "error", err)
+		return err
 	}
-	return n.state.SchedulerSetConfig(index, &req.Config)
+	return nil
 }
 
-func (n *nomadFSM) Snapshot() (raft.FSMSnapshot, error) {
-	// Create a new snapshot
-	snap, err := n.state.Snapshot()
-	if err != nil {
-		return nil, err
+// applyVariableDelete is used to delete a variable
+func (n *nomadFSM) applyVariableDelete(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_variable_delete"}, time.Now())
+	var req structs.VariablesDeleteRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
 	}
 
-	ns := &nomadSnapshot{
-		snap:      snap,
-		timetable: n.timetable,
+	if err := n.state.DeleteVariable(index, req.RequestNamespace(), req.Path, req.CheckIndex); err != nil {
+		n.logger.Error("DeleteVariable failed", "error", err)
+		return err
 	}
-	return ns, nil
+	return nil
 }
 
-func (n *nomadFSM) Restore(old io.ReadCloser) error {
-	defer old.Close()
+// applyRootKeyUpsert is used to create or update a root key
+func (n *nomadFSM) applyRootKeyUpsert(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_root_key_upsert"}, time.Now())
+	var req structs.RootKeyUpsertRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
 
-	// Create a new state store
-	config := &state.StateStoreConfig{
-		Logger: n.config.Logger,
-		Region: n.config.Region,
+	if err := n.state.UpsertRootKey(index, req.RootKey); err != nil {
+		n.logger.Error("UpsertRootKey failed", "error", err)
+		return err
 	}
-	newState, err := state.NewStateStore(config)
-	if err != nil {
+	return nil
+}
+
+// applyRootKeyDelete is used to delete a root key
+func (n *nomadFSM) applyRootKeyDelete(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_root_key_delete"}, time.Now())
+	var req structs.KeyringDeleteRootKeyRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.DeleteRootKey(index, req.KeyID); err != nil {
+		n.logger.Error("DeleteRootKey failed", "error", err)
 		return err
 	}
+	return nil
+}
 
-	// Start the state restore
-	restore, err := newState.Restore()
-	if err != nil {
+// applyUpsertScalingEvent is used to record a scaling event of a job
+func (n *nomadFSM) applyUpsertScalingEvent(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "upsert_scaling_event"}, time.Now())
+	var req structs.ScalingEventRegisterRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpsertScalingEvent(index, &req); err != nil {
+		n.logger.Error("UpsertScalingEvent failed", "error", err)
 		return err
 	}
-	defer restore.Abort()
+	return nil
+}
 
-	// Create a decoder
-	dec := codec.NewDecoder(old, structs.MsgpackHandle)
+// applyUpsertServiceRegistrations is used to register the services of a task
+func (n *nomadFSM) applyUpsertServiceRegistrations(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "upsert_service_registrations"}, time.Now())
+	var req structs.ServiceRegistrationUpsertRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
 
-	// Read in the header
-	var header snapshotHeader
-	if err := dec.Decode(&header); err != nil {
+	if err := n.state.UpsertServiceRegistrations(index, req.Services); err != nil {
+		n.logger.Error("UpsertServiceRegistrations failed", "error", err)
 		return err
 	}
+	return nil
+}
 
-	// Populate the new state
-	msgType := make([]byte, 1)
-	for {
-		// Read the message type
-		_, err := old.Read(msgType)
-		if err == io.EOF {
-			break
-		} else if err != nil {
-			return err
-		}
+// applyDeleteServiceRegistrations is used to delete the service
+// registrations of a task
+func (n *nomadFSM) applyDeleteServiceRegistrations(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "delete_service_registrations"}, time.Now())
+	var req structs.ServiceRegistrationDeleteRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
 
-		// Decode
-		snapType := SnapshotType(msgType[0])
-		switch snapType {
-		case TimeTableSnapshot:
-			if err := n.timetable.Deserialize(dec); err != nil {
-				return fmt.Errorf("time table deserialize failed: %v", err)
-			}
+	if err := n.state.DeleteServiceRegistrationsByTask(index, req.AllocID, req.Task); err != nil {
+		n.logger.Error("DeleteServiceRegistrationsByTask failed", "error", err)
+		return err
+	}
+	return nil
+}
+
+// applyVolumeRegister is used to register or create a set of volumes
+func (n *nomadFSM) applyVolumeRegister(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "volume_register"}, time.Now())
+	var req structs.VolumeRegisterRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpsertVolumes(index, req.Volumes); err != nil {
+		n.logger.Error("UpsertVolumes failed", "error", err)
+		return err
+	}
+	return nil
+}
+
+// applyVolumeDelete is used to delete a set of volumes
+func (n *nomadFSM) applyVolumeDelete(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "volume_delete"}, time.Now())
+	var req structs.VolumeDeleteRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.DeleteVolumes(index, req.RequestNamespace(), req.VolumeIDs); err != nil {
+		n.logger.Error("DeleteVolumes failed", "error", err)
+		return err
+	}
+	return nil
+}
+
+// applyVolumeClaim is used to claim a volume for an allocation
+func (n *nomadFSM) applyVolumeClaim(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "volume_claim"}, time.Now())
+	var req structs.VolumeClaimRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	claim := &structs.VolumeClaim{
+		AllocID: req.AllocID,
+		NodeID:  req.NodeID,
+		Mode:    req.Mode,
+	}
+	if err := n.state.ClaimVolume(index, req.RequestNamespace(), req.VolumeID, claim); err != nil {
+		n.logger.Error("ClaimVolume failed", "error", err)
+		return err
+	}
+	return nil
+}
+
+// applyVolumeDetach is used to release the claims of a volume on a node
+func (n *nomadFSM) applyVolumeDetach(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "volume_detach"}, time.Now())
+	var req structs.VolumeDetachRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.DetachVolume(index, req.RequestNamespace(), req.VolumeID, req.NodeID); err != nil {
+		n.logger.Error("DetachVolume failed", "error", err)
+		return err
+	}
+	return nil
+}
+
+// allocQuota returns the quota the allocation accounts against through its
+// namespace. An empty string is returned if the namespace has no quota.
+func (n *nomadFSM) allocQuota(allocID string) (string, error) {
+	alloc, err := n.state.AllocByID(nil, allocID)
+	if err != nil {
+		return "", err
+	}
+	if alloc == nil {
+		return "", nil
+	}
+
+	ns, err := n.state.NamespaceByName(nil, alloc.Namespace)
+	if err != nil {
+		return "", err
+	}
+	if ns == nil {
+		return "", nil
+	}
+	return ns.Quota, nil
+}
+
+// applyACLPolicyUpsert is used to upsert a set of policies
+func (n *nomadFSM) applyACLPolicyUpsert(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_acl_policy_upsert"}, time.Now())
+	var req structs.ACLPolicyUpsertRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpsertACLPolicies(index, req.Policies); err != nil {
+		n.logger.Error("UpsertACLPolicies failed", "error", err)
+		return err
+	}
+	return nil
+}
+
+// applyACLPolicyDelete is used to delete a set of policies
+func (n *nomadFSM) applyACLPolicyDelete(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_acl_policy_delete"}, time.Now())
+	var req structs.ACLPolicyDeleteRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.DeleteACLPolicies(index, req.Names); err != nil {
+		n.logger.Error("DeleteACLPolicies failed", "error", err)
+		return err
+	}
+	return nil
+}
+
+// applyACLTokenUpsert is used to upsert a set of policies
+func (n *nomadFSM) applyACLTokenUpsert(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_acl_token_upsert"}, time.Now())
+	var req structs.ACLTokenUpsertRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpsertACLTokens(index, req.Tokens); err != nil {
+		n.logger.Error("UpsertACLTokens failed", "error", err)
+		return err
+	}
+	return nil
+}
+
+// applyACLTokenDelete is used to delete a set of policies
+func (n *nomadFSM) applyACLTokenDelete(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_acl_token_delete"}, time.Now())
+	var req structs.ACLTokenDeleteRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.DeleteACLTokens(index, req.AccessorIDs); err != nil {
+		n.logger.Error("DeleteACLTokens failed", "error", err)
+		return err
+	}
+	return nil
+}
+
+// applyACLRolesUpsert is used to upsert a set of roles
+func (n *nomadFSM) applyACLRolesUpsert(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_acl_roles_upsert"}, time.Now())
+	var req structs.ACLRolesUpsertRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpsertACLRoles(index, req.Roles); err != nil {
+		n.logger.Error("UpsertACLRoles failed", "error", err)
+		return err
+	}
+	return nil
+}
+
+// applyACLRolesDelete is used to delete a set of roles
+func (n *nomadFSM) applyACLRolesDelete(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_acl_roles_delete"}, time.Now())
+	var req structs.ACLRolesDeleteRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.DeleteACLRoles(index, req.Names); err != nil {
+		n.logger.Error("DeleteACLRoles failed", "error", err)
+		return err
+	}
+	return nil
+}
+
+// applyACLAuthMethodsUpsert is used to upsert a set of auth methods
+func (n *nomadFSM) applyACLAuthMethodsUpsert(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_acl_auth_methods_upsert"}, time.Now())
+	var req structs.ACLAuthMethodUpsertRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpsertACLAuthMethods(index, req.AuthMethods); err != nil {
+		n.logger.Error("UpsertACLAuthMethods failed", "error", err)
+		return err
+	}
+	return nil
+}
+
+// applyACLAuthMethodsDelete is used to delete a set of auth methods
+func (n *nomadFSM) applyACLAuthMethodsDelete(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_acl_auth_methods_delete"}, time.Now())
+	var req structs.ACLAuthMethodDeleteRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.DeleteACLAuthMethods(index, req.Names); err != nil {
+		n.logger.Error("DeleteACLAuthMethods failed", "error", err)
+		return err
+	}
+	return nil
+}
+
+// applyACLBindingRulesUpsert is used to upsert a set of binding rules
+func (n *nomadFSM) applyACLBindingRulesUpsert(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_acl_binding_rules_upsert"}, time.Now())
+	var req structs.ACLBindingRulesUpsertRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpsertACLBindingRules(index, req.ACLBindingRules); err != nil {
+		n.logger.Error("UpsertACLBindingRules failed", "error", err)
+		return err
+	}
+	return nil
+}
+
+// applyACLBindingRulesDelete is used to delete a set of binding rules
+func (n *nomadFSM) applyACLBindingRulesDelete(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_acl_binding_rules_delete"}, time.Now())
+	var req structs.ACLBindingRulesDeleteRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.DeleteACLBindingRules(index, req.ACLBindingRuleIDs); err != nil {
+		n.logger.Error("DeleteACLBindingRules failed", "error", err)
+		return err
+	}
+	return nil
+}
+
+// applyACLTokenBootstrap is used to bootstrap an ACL token
+func (n *nomadFSM) applyACLTokenBootstrap(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_acl_token_bootstrap"}, time.Now())
+	var req structs.ACLTokenBootstrapRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.BootstrapACLTokens(index, req.ResetIndex, req.Token); err != nil {
+		n.logger.Error("BootstrapACLToken failed", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (n *nomadFSM) applyAutopilotUpdate(buf []byte, index uint64) interface{} {
+	var req structs.AutopilotSetConfigRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "autopilot"}, time.Now())
+
+	if req.CAS {
+		act, err := n.state.AutopilotCASConfig(index, req.Config.ModifyIndex, &req.Config)
+		if err != nil {
+			return err
+		}
+		return act
+	}
+	return n.state.AutopilotSetConfig(index, &req.Config)
+}
+
+func (n *nomadFSM) applySchedulerConfigUpdate(buf []byte, index uint64) interface{} {
+	var req structs.SchedulerSetConfigRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_scheduler_config"}, time.Now())
+
+	if req.CAS {
+		applied, err := n.state.SchedulerCASConfig(index, req.Config.ModifyIndex, &req.Config)
+		if err != nil {
+			return err
+		}
+		return applied
+	}
+	return n.state.SchedulerSetConfig(index, &req.Config)
+}
+
+func (n *nomadFSM) Snapshot() (raft.FSMSnapshot, error) {
+	// Create a new snapshot
+	snap, err := n.state.Snapshot()
+	if err != nil {
+		return nil, err
+	}
+
+	ns := &nomadSnapshot{
+		snap:      snap,
+		timetable: n.timetable,
+	}
+	return ns, nil
+}
+
+func (n *nomadFSM) Restore(old io.ReadCloser) error {
+	defer old.Close()
+
+	// Create a new state store
+	config := &state.StateStoreConfig{
+		Logger:             n.config.Logger,
+		Region:             n.config.Region,
+		NodeEventRetention: n.config.NodeEventRetention,
+	}
+	newState, err := state.NewStateStore(config)
+	if err != nil {
+		return err
+	}
+
+	// Start the state restore
+	restore, err := newState.Restore()
+	if err != nil {
+		return err
+	}
+	defer restore.Abort()
+
+	// Create a decoder
+	dec := codec.NewDecoder(old, structs.MsgpackHandle)
+
+	// Read in the header
+	var header snapshotHeader
+	if err := dec.Decode(&header); err != nil {
+		return err
+	}
+
+	// Populate the new state
+	msgType := make([]byte, 1)
+	for {
+		// Read the message type
+		_, err := old.Read(msgType)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+
+		// Decode
+		snapType := SnapshotType(msgType[0])
+		switch snapType {
+		case TimeTableSnapshot:
+			if err := n.timetable.Deserialize(dec); err != nil {
+				return fmt.Errorf("time table deserialize failed: %v", err)
+			}
 
 		case NodeSnapshot:
 			node := new(structs.Node)
@@ -1221,46 +1898,145 @@ func (n *nomadFSM) Restore(old io.ReadCloser) error {
 			if err := restore.JobVersionRestore(version); err != nil {
 				return err
 			}
-
-		case DeploymentSnapshot:
-			deployment := new(structs.Deployment)
-			if err := dec.Decode(deployment); err != nil {
+
+		case DeploymentSnapshot:
+			deployment := new(structs.Deployment)
+			if err := dec.Decode(deployment); err != nil {
+				return err
+			}
+
+			// COMPAT: Handle upgrade to v0.7.0
+			if deployment.Namespace == "" {
+				deployment.Namespace = structs.DefaultNamespace
+			}
+
+			if err := restore.DeploymentRestore(deployment); err != nil {
+				return err
+			}
+
+		case ACLPolicySnapshot:
+			policy := new(structs.ACLPolicy)
+			if err := dec.Decode(policy); err != nil {
+				return err
+			}
+			if err := restore.ACLPolicyRestore(policy); err != nil {
+				return err
+			}
+
+		case ACLTokenSnapshot:
+			token := new(structs.ACLToken)
+			if err := dec.Decode(token); err != nil {
+				return err
+			}
+			if err := restore.ACLTokenRestore(token); err != nil {
+				return err
+			}
+
+		case SchedulerConfigSnapshot:
+			schedConfig := new(structs.SchedulerConfiguration)
+			if err := dec.Decode(schedConfig); err != nil {
+				return err
+			}
+			if err := restore.SchedulerConfigRestore(schedConfig); err != nil {
+				return err
+			}
+
+		case NodePoolSnapshot:
+			pool := new(structs.NodePool)
+			if err := dec.Decode(pool); err != nil {
+				return err
+			}
+			if err := restore.NodePoolRestore(pool); err != nil {
+				return err
+			}
+
+		case NamespaceSnapshot:
+			ns := new(structs.Namespace)
+			if err := dec.Decode(ns); err != nil {
+				return err
+			}
+			if err := restore.NamespaceRestore(ns); err != nil {
+				return err
+			}
+
+		case QuotaSpecSnapshot:
+			spec := new(structs.QuotaSpec)
+			if err := dec.Decode(spec); err != nil {
+				return err
+			}
+			if err := restore.QuotaSpecRestore(spec); err != nil {
+				return err
+			}
+
+		case VariableSnapshot:
+			v := new(structs.VariableEncrypted)
+			if err := dec.Decode(v); err != nil {
+				return err
+			}
+			if err := restore.VariableRestore(v); err != nil {
+				return err
+			}
+
+		case RootKeySnapshot:
+			key := new(structs.RootKey)
+			if err := dec.Decode(key); err != nil {
+				return err
+			}
+			if err := restore.RootKeyRestore(key); err != nil {
+				return err
+			}
+
+		case ScalingEventsSnapshot:
+			events := new(structs.JobScalingEvents)
+			if err := dec.Decode(events); err != nil {
+				return err
+			}
+			if err := restore.ScalingEventsRestore(events); err != nil {
+				return err
+			}
+
+		case ServiceRegistrationSnapshot:
+			service := new(structs.ServiceRegistration)
+			if err := dec.Decode(service); err != nil {
+				return err
+			}
+			if err := restore.ServiceRegistrationRestore(service); err != nil {
 				return err
 			}
 
-			// COMPAT: Handle upgrade to v0.7.0
-			if deployment.Namespace == "" {
-				deployment.Namespace = structs.DefaultNamespace
+		case ACLAuthMethodSnapshot:
+			method := new(structs.ACLAuthMethod)
+			if err := dec.Decode(method); err != nil {
+				return err
 			}
-
-			if err := restore.DeploymentRestore(deployment); err != nil {
+			if err := restore.ACLAuthMethodRestore(method); err != nil {
 				return err
 			}
 
-		case ACLPolicySnapshot:
-			policy := new(structs.ACLPolicy)
-			if err := dec.Decode(policy); err != nil {
+		case ACLBindingRuleSnapshot:
+			rule := new(structs.ACLBindingRule)
+			if err := dec.Decode(rule); err != nil {
 				return err
 			}
-			if err := restore.ACLPolicyRestore(policy); err != nil {
+			if err := restore.ACLBindingRuleRestore(rule); err != nil {
 				return err
 			}
 
-		case ACLTokenSnapshot:
-			token := new(structs.ACLToken)
-			if err := dec.Decode(token); err != nil {
+		case VolumeSnapshot:
+			volume := new(structs.Volume)
+			if err := dec.Decode(volume); err != nil {
 				return err
 			}
-			if err := restore.ACLTokenRestore(token); err != nil {
+			if err := restore.VolumeRestore(volume); err != nil {
 				return err
 			}
 
-		case SchedulerConfigSnapshot:
-			schedConfig := new(structs.SchedulerConfiguration)
-			if err := dec.Decode(schedConfig); err != nil {
+		case ACLRoleSnapshot:
+			role := new(structs.ACLRole)
+			if err := dec.Decode(role); err != nil {
 				return err
 			}
-			if err := restore.SchedulerConfigRestore(schedConfig); err != nil {
+			if err := restore.ACLRoleRestore(role); err != nil {
 				return err
 			}
 
@@ -1547,6 +2323,18 @@ func (s *nomadSnapshot) Persist(sink raft.SnapshotSink) error {
 		sink.Cancel()
 		return err
 	}
+	if err := s.persistACLRoles(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
+	if err := s.persistACLAuthMethods(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
+	if err := s.persistACLBindingRules(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
 	if err := s.persistEnterpriseTables(sink, encoder); err != nil {
 		sink.Cancel()
 		return err
@@ -1555,6 +2343,38 @@ func (s *nomadSnapshot) Persist(sink raft.SnapshotSink) error {
 		sink.Cancel()
 		return err
 	}
+	if err := s.persistNodePools(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
+	if err := s.persistNamespaces(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
+	if err := s.persistQuotaSpecs(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
+	if err := s.persistVariables(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
+	if err := s.persistRootKeys(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
+	if err := s.persistScalingEvents(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
+	if err := s.persistServiceRegistrations(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
+	if err := s.persistVolumes(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
 	return nil
 }
 
@@ -1887,6 +2707,84 @@ func (s *nomadSnapshot) persistACLTokens(sink raft.SnapshotSink,
 	return nil
 }
 
+func (s *nomadSnapshot) persistACLRoles(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+	// Get all the roles
+	ws := memdb.NewWatchSet()
+	roles, err := s.snap.ACLRoles(ws)
+	if err != nil {
+		return err
+	}
+
+	for {
+		// Get the next item
+		raw := roles.Next()
+		if raw == nil {
+			break
+		}
+
+		// Write out a role
+		role := raw.(*structs.ACLRole)
+		sink.Write([]byte{byte(ACLRoleSnapshot)})
+		if err := encoder.Encode(role); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *nomadSnapshot) persistACLAuthMethods(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+	// Get all the auth methods
+	ws := memdb.NewWatchSet()
+	methods, err := s.snap.ACLAuthMethods(ws)
+	if err != nil {
+		return err
+	}
+
+	for {
+		// Get the next item
+		raw := methods.Next()
+		if raw == nil {
+			break
+		}
+
+		// Write out an auth method
+		method := raw.(*structs.ACLAuthMethod)
+		sink.Write([]byte{byte(ACLAuthMethodSnapshot)})
+		if err := encoder.Encode(method); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *nomadSnapshot) persistACLBindingRules(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+	// Get all the binding rules
+	ws := memdb.NewWatchSet()
+	rules, err := s.snap.ACLBindingRules(ws)
+	if err != nil {
+		return err
+	}
+
+	for {
+		// Get the next item
+		raw := rules.Next()
+		if raw == nil {
+			break
+		}
+
+		// Write out a binding rule
+		rule := raw.(*structs.ACLBindingRule)
+		sink.Write([]byte{byte(ACLBindingRuleSnapshot)})
+		if err := encoder.Encode(rule); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (s *nomadSnapshot) persistSchedulerConfig(sink raft.SnapshotSink,
 	encoder *codec.Encoder) error {
 	// Get scheduler config
@@ -1902,6 +2800,214 @@ func (s *nomadSnapshot) persistSchedulerConfig(sink raft.SnapshotSink,
 	return nil
 }
 
+func (s *nomadSnapshot) persistNodePools(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+	// Get all the node pools
+	ws := memdb.NewWatchSet()
+	pools, err := s.snap.NodePools(ws)
+	if err != nil {
+		return err
+	}
+
+	for {
+		// Get the next item
+		raw := pools.Next()
+		if raw == nil {
+			break
+		}
+
+		// Write out a node pool registration
+		pool := raw.(*structs.NodePool)
+		sink.Write([]byte{byte(NodePoolSnapshot)})
+		if err := encoder.Encode(pool); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *nomadSnapshot) persistNamespaces(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+	// Get all the namespaces
+	ws := memdb.NewWatchSet()
+	namespaces, err := s.snap.Namespaces(ws)
+	if err != nil {
+		return err
+	}
+
+	for {
+		// Get the next item
+		raw := namespaces.Next()
+		if raw == nil {
+			break
+		}
+
+		// Write out a namespace registration
+		ns := raw.(*structs.Namespace)
+		sink.Write([]byte{byte(NamespaceSnapshot)})
+		if err := encoder.Encode(ns); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *nomadSnapshot) persistQuotaSpecs(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+	// Get all the quota specs
+	ws := memdb.NewWatchSet()
+	specs, err := s.snap.QuotaSpecs(ws)
+	if err != nil {
+		return err
+	}
+
+	for {
+		// Get the next item
+		raw := specs.Next()
+		if raw == nil {
+			break
+		}
+
+		// Write out a quota spec registration
+		spec := raw.(*structs.QuotaSpec)
+		sink.Write([]byte{byte(QuotaSpecSnapshot)})
+		if err := encoder.Encode(spec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *nomadSnapshot) persistVariables(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+	// Get all the variables
+	ws := memdb.NewWatchSet()
+	variables, err := s.snap.Variables(ws)
+	if err != nil {
+		return err
+	}
+
+	for {
+		// Get the next item
+		raw := variables.Next()
+		if raw == nil {
+			break
+		}
+
+		// Write out an encrypted variable
+		v := raw.(*structs.VariableEncrypted)
+		sink.Write([]byte{byte(VariableSnapshot)})
+		if err := encoder.Encode(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *nomadSnapshot) persistRootKeys(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+	// Get all the root keys
+	ws := memdb.NewWatchSet()
+	keys, err := s.snap.RootKeys(ws)
+	if err != nil {
+		return err
+	}
+
+	for {
+		// Get the next item
+		raw := keys.Next()
+		if raw == nil {
+			break
+		}
+
+		// Write out a root key
+		key := raw.(*structs.RootKey)
+		sink.Write([]byte{byte(RootKeySnapshot)})
+		if err := encoder.Encode(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *nomadSnapshot) persistScalingEvents(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+	// Get all the scaling events
+	ws := memdb.NewWatchSet()
+	iter, err := s.snap.ScalingEvents(ws)
+	if err != nil {
+		return err
+	}
+
+	for {
+		// Get the next item
+		raw := iter.Next()
+		if raw == nil {
+			break
+		}
+
+		// Write out the scaling events of a job
+		events := raw.(*structs.JobScalingEvents)
+		sink.Write([]byte{byte(ScalingEventsSnapshot)})
+		if err := encoder.Encode(events); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *nomadSnapshot) persistServiceRegistrations(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+	// Get all the service registrations
+	ws := memdb.NewWatchSet()
+	iter, err := s.snap.ServiceRegistrations(ws)
+	if err != nil {
+		return err
+	}
+
+	for {
+		// Get the next item
+		raw := iter.Next()
+		if raw == nil {
+			break
+		}
+
+		// Write out a service registration
+		service := raw.(*structs.ServiceRegistration)
+		sink.Write([]byte{byte(ServiceRegistrationSnapshot)})
+		if err := encoder.Encode(service); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *nomadSnapshot) persistVolumes(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+	// Get all the volumes
+	ws := memdb.NewWatchSet()
+	iter, err := s.snap.Volumes(ws)
+	if err != nil {
+		return err
+	}
+
+	for {
+		// Get the next item
+		raw := iter.Next()
+		if raw == nil {
+			break
+		}
+
+		// Write out a volume
+		volume := raw.(*structs.Volume)
+		sink.Write([]byte{byte(VolumeSnapshot)})
+		if err := encoder.Encode(volume); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Release is a no-op, as we just need to GC the pointer
 // to the state store snapshot. There is nothing to explicitly
 // cleanup.
@@ -558,6 +558,54 @@ func TestFSM_UpdateNodeEligibility_Unblock(t *testing.T) {
 	})
 }
 
+func TestFSM_UpdateNodeDynamicMeta_Unblock(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	fsm := testFSM(t)
+
+	node := mock.Node()
+	req := structs.NodeRegisterRequest{
+		Node: node,
+	}
+	buf, err := structs.Encode(structs.NodeRegisterRequestType, req)
+	require.Nil(err)
+
+	resp := fsm.Apply(makeLog(buf))
+	require.Nil(resp)
+
+	// Mark an eval as blocked on the class of the node.
+	eval := mock.Eval()
+	eval.ClassEligibility = map[string]bool{node.ComputedClass: false}
+	fsm.blockedEvals.Block(eval)
+
+	// Set the dynamic metadata
+	req2 := structs.NodeUpdateDynamicMetaRequest{
+		NodeID: node.ID,
+		Set:    map[string]string{"rack": "r1"},
+	}
+	buf, err = structs.Encode(structs.NodeUpdateDynamicMetaRequestType, req2)
+	require.Nil(err)
+
+	resp = fsm.Apply(makeLog(buf))
+	require.Nil(resp)
+
+	out, err := fsm.State().NodeByID(nil, node.ID)
+	require.Nil(err)
+	require.Equal("r1", out.DynamicMeta["rack"])
+	require.NotEqual(node.ComputedClass, out.ComputedClass)
+
+	// Verify the eval was unblocked since the node has a new class.
+	testutil.WaitForResult(func() (bool, error) {
+		bStats := fsm.blockedEvals.Stats()
+		if bStats.TotalBlocked != 0 {
+			return false, fmt.Errorf("bad: %#v", bStats)
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("err: %s", err)
+	})
+}
+
 func TestFSM_RegisterJob(t *testing.T) {
 	t.Parallel()
 	fsm := testFSM(t)
@@ -614,6 +662,76 @@ func TestFSM_RegisterJob(t *testing.T) {
 	}
 }
 
+func TestFSM_BatchRegisterJob(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	fsm := testFSM(t)
+
+	parent := mock.BatchJob()
+	parent.ParameterizedJob = &structs.ParameterizedJobConfig{}
+	require.Nil(fsm.State().UpsertJob(1, parent))
+
+	req := structs.JobBatchRegisterRequest{
+		WriteRequest: structs.WriteRequest{
+			Namespace: parent.Namespace,
+		},
+	}
+	for i := 0; i < 3; i++ {
+		job := parent.Copy()
+		job.ID = structs.DispatchedID(parent.ID, time.Now())
+		job.ParentID = parent.ID
+		job.Dispatched = true
+		job.DispatchIdempotencyToken = fmt.Sprintf("token-%d", i)
+		eval := mock.Eval()
+		eval.JobID = job.ID
+		req.Jobs = append(req.Jobs, job)
+		req.Evals = append(req.Evals, eval)
+	}
+	buf, err := structs.Encode(structs.JobBatchRegisterRequestType, req)
+	require.Nil(err)
+
+	resp := fsm.Apply(makeLog(buf))
+	require.Nil(resp)
+
+	// Verify the jobs and evaluations are registered
+	for i, job := range req.Jobs {
+		jobOut, err := fsm.State().JobByID(nil, job.Namespace, job.ID)
+		require.Nil(err)
+		require.NotNil(jobOut)
+		require.EqualValues(1, jobOut.CreateIndex)
+
+		evalOut, err := fsm.State().EvalByID(nil, req.Evals[i].ID)
+		require.Nil(err)
+		require.NotNil(evalOut)
+		require.EqualValues(1, evalOut.JobModifyIndex)
+	}
+
+	// A batch that duplicates an idempotency token is rejected as a whole
+	dup := parent.Copy()
+	dup.ID = structs.DispatchedID(parent.ID, time.Now())
+	dup.ParentID = parent.ID
+	dup.Dispatched = true
+	dup.DispatchIdempotencyToken = "token-1"
+	other := dup.Copy()
+	other.ID = structs.DispatchedID(parent.ID, time.Now())
+	other.DispatchIdempotencyToken = "token-new"
+	req2 := structs.JobBatchRegisterRequest{
+		Jobs: []*structs.Job{other, dup},
+		WriteRequest: structs.WriteRequest{
+			Namespace: parent.Namespace,
+		},
+	}
+	buf, err = structs.Encode(structs.JobBatchRegisterRequestType, req2)
+	require.Nil(err)
+
+	resp = fsm.Apply(makeLog(buf))
+	require.NotNil(resp)
+
+	jobOut, err := fsm.State().JobByID(nil, other.Namespace, other.ID)
+	require.Nil(err)
+	require.Nil(jobOut)
+}
+
 func TestFSM_RegisterPeriodicJob_NonLeader(t *testing.T) {
 	t.Parallel()
 	fsm := testFSM(t)
@@ -966,6 +1084,58 @@ func TestFSM_UpdateEval(t *testing.T) {
 	}
 }
 
+func TestFSM_UpdateEvalBatch(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	fsm := testFSM(t)
+	fsm.evalBroker.SetEnabled(true)
+
+	// The second update fails as its evaluation can not be indexed
+	good, bad := mock.Eval(), mock.Eval()
+	bad.ID = "not-a-uuid"
+	req := structs.EvalUpdateBatchRequest{
+		Updates: []*structs.EvalUpdateRequest{
+			{Evals: []*structs.Evaluation{good}, EvalToken: "token1"},
+			{Evals: []*structs.Evaluation{bad}, EvalToken: "token2"},
+		},
+	}
+	buf, err := structs.Encode(structs.EvalUpdateBatchRequestType, req)
+	require.NoError(err)
+
+	resp := fsm.Apply(makeLog(buf))
+	errs, ok := resp.([]error)
+	require.True(ok, "unexpected response %#v", resp)
+	require.Len(errs, 2)
+	require.NoError(errs[0])
+	require.Error(errs[1])
+
+	// Only the valid update was applied
+	ws := memdb.NewWatchSet()
+	out, err := fsm.State().EvalByID(ws, good.ID)
+	require.NoError(err)
+	require.NotNil(out)
+	require.Equal(uint64(1), out.CreateIndex)
+	require.Equal(1, fsm.evalBroker.Stats().TotalReady)
+
+	// A batch without failures is applied in one transaction
+	evals := []*structs.Evaluation{mock.Eval(), mock.Eval()}
+	req = structs.EvalUpdateBatchRequest{
+		Updates: []*structs.EvalUpdateRequest{
+			{Evals: evals[:1]},
+			{Evals: evals[1:]},
+		},
+	}
+	buf, err = structs.Encode(structs.EvalUpdateBatchRequestType, req)
+	require.NoError(err)
+	require.Nil(fsm.Apply(makeLog(buf)))
+	for _, eval := range evals {
+		out, err := fsm.State().EvalByID(ws, eval.ID)
+		require.NoError(err)
+		require.NotNil(out)
+	}
+	require.Equal(3, fsm.evalBroker.Stats().TotalReady)
+}
+
 func TestFSM_UpdateEval_Blocked(t *testing.T) {
 	t.Parallel()
 	fsm := testFSM(t)
@@ -2313,6 +2483,148 @@ func TestFSM_DeleteACLTokens(t *testing.T) {
 	assert.Nil(t, out)
 }
 
+func TestFSM_UpsertACLAuthMethods(t *testing.T) {
+	t.Parallel()
+	fsm := testFSM(t)
+
+	am := mock.ACLAuthMethod()
+	req := structs.ACLAuthMethodUpsertRequest{
+		AuthMethods: []*structs.ACLAuthMethod{am},
+	}
+	buf, err := structs.Encode(structs.ACLAuthMethodsUpsertRequestType, req)
+	require.NoError(t, err)
+	require.Nil(t, fsm.Apply(makeLog(buf)))
+
+	out, err := fsm.State().ACLAuthMethodByName(nil, am.Name)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+}
+
+func TestFSM_DeleteACLAuthMethods(t *testing.T) {
+	t.Parallel()
+	fsm := testFSM(t)
+
+	am := mock.ACLAuthMethod()
+	require.NoError(t, fsm.State().UpsertACLAuthMethods(1000, []*structs.ACLAuthMethod{am}))
+
+	req := structs.ACLAuthMethodDeleteRequest{
+		Names: []string{am.Name},
+	}
+	buf, err := structs.Encode(structs.ACLAuthMethodsDeleteRequestType, req)
+	require.NoError(t, err)
+	require.Nil(t, fsm.Apply(makeLog(buf)))
+
+	out, err := fsm.State().ACLAuthMethodByName(nil, am.Name)
+	require.NoError(t, err)
+	require.Nil(t, out)
+}
+
+func TestFSM_UpsertDeleteACLRoles(t *testing.T) {
+	t.Parallel()
+	fsm := testFSM(t)
+
+	role := mock.ACLRole()
+	buf, err := structs.Encode(structs.ACLRolesUpsertRequestType, structs.ACLRolesUpsertRequest{
+		Roles: []*structs.ACLRole{role},
+	})
+	require.NoError(t, err)
+	require.Nil(t, fsm.Apply(makeLog(buf)))
+
+	out, err := fsm.State().ACLRoleByName(nil, role.Name)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+
+	buf, err = structs.Encode(structs.ACLRolesDeleteRequestType, structs.ACLRolesDeleteRequest{
+		Names: []string{role.Name},
+	})
+	require.NoError(t, err)
+	require.Nil(t, fsm.Apply(makeLog(buf)))
+
+	out, err = fsm.State().ACLRoleByName(nil, role.Name)
+	require.NoError(t, err)
+	require.Nil(t, out)
+}
+
+func TestFSM_UpsertDeleteACLBindingRules(t *testing.T) {
+	t.Parallel()
+	fsm := testFSM(t)
+
+	am := mock.ACLAuthMethod()
+	require.NoError(t, fsm.State().UpsertACLAuthMethods(1000, []*structs.ACLAuthMethod{am}))
+	rule := mock.ACLBindingRule()
+	rule.AuthMethod = am.Name
+
+	buf, err := structs.Encode(structs.ACLBindingRulesUpsertRequestType, structs.ACLBindingRulesUpsertRequest{
+		ACLBindingRules: []*structs.ACLBindingRule{rule},
+	})
+	require.NoError(t, err)
+	require.Nil(t, fsm.Apply(makeLog(buf)))
+
+	out, err := fsm.State().ACLBindingRuleByID(nil, rule.ID)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+
+	buf, err = structs.Encode(structs.ACLBindingRulesDeleteRequestType, structs.ACLBindingRulesDeleteRequest{
+		ACLBindingRuleIDs: []string{rule.ID},
+	})
+	require.NoError(t, err)
+	require.Nil(t, fsm.Apply(makeLog(buf)))
+
+	out, err = fsm.State().ACLBindingRuleByID(nil, rule.ID)
+	require.NoError(t, err)
+	require.Nil(t, out)
+}
+
+func TestFSM_VolumeRegisterClaimDelete(t *testing.T) {
+	t.Parallel()
+	fsm := testFSM(t)
+
+	volume := mock.Volume()
+	buf, err := structs.Encode(structs.VolumeRegisterRequestType, structs.VolumeRegisterRequest{
+		Volumes:      []*structs.Volume{volume},
+		WriteRequest: structs.WriteRequest{Namespace: volume.Namespace},
+	})
+	require.NoError(t, err)
+	require.Nil(t, fsm.Apply(makeLog(buf)))
+
+	buf, err = structs.Encode(structs.VolumeClaimRequestType, structs.VolumeClaimRequest{
+		VolumeID:     volume.ID,
+		NodeID:       "node",
+		AllocID:      "alloc",
+		Mode:         structs.VolumeClaimWrite,
+		WriteRequest: structs.WriteRequest{Namespace: volume.Namespace},
+	})
+	require.NoError(t, err)
+	require.Nil(t, fsm.Apply(makeLog(buf)))
+
+	out, err := fsm.State().VolumeByID(nil, volume.Namespace, volume.ID)
+	require.NoError(t, err)
+	require.Contains(t, out.Claims, "alloc")
+
+	buf, err = structs.Encode(structs.VolumeDetachRequestType, structs.VolumeDetachRequest{
+		VolumeID:     volume.ID,
+		NodeID:       "node",
+		WriteRequest: structs.WriteRequest{Namespace: volume.Namespace},
+	})
+	require.NoError(t, err)
+	require.Nil(t, fsm.Apply(makeLog(buf)))
+
+	out, err = fsm.State().VolumeByID(nil, volume.Namespace, volume.ID)
+	require.NoError(t, err)
+	require.Empty(t, out.Claims)
+
+	buf, err = structs.Encode(structs.VolumeDeleteRequestType, structs.VolumeDeleteRequest{
+		VolumeIDs:    []string{volume.ID},
+		WriteRequest: structs.WriteRequest{Namespace: volume.Namespace},
+	})
+	require.NoError(t, err)
+	require.Nil(t, fsm.Apply(makeLog(buf)))
+
+	out, err = fsm.State().VolumeByID(nil, volume.Namespace, volume.ID)
+	require.NoError(t, err)
+	require.Nil(t, out)
+}
+
 func testSnapshotRestore(t *testing.T, fsm *nomadFSM) *nomadFSM {
 	// Snapshot
 	snap, err := fsm.Snapshot()
@@ -2659,6 +2971,48 @@ func TestFSM_SnapshotRestore_ACLPolicy(t *testing.T) {
 	assert.Equal(t, p2, out2)
 }
 
+func TestFSM_SnapshotRestore_NodePools(t *testing.T) {
+	t.Parallel()
+	// Add some state
+	fsm := testFSM(t)
+	state := fsm.State()
+	p1 := &structs.NodePool{Name: "gpu", Meta: map[string]string{"owner": "ml"}}
+	p2 := &structs.NodePool{Name: "batch"}
+	state.UpsertNodePools(1000, []*structs.NodePool{p1, p2})
+
+	// Verify the contents
+	fsm2 := testSnapshotRestore(t, fsm)
+	state2 := fsm2.State()
+	ws := memdb.NewWatchSet()
+	out1, _ := state2.NodePoolByName(ws, p1.Name)
+	out2, _ := state2.NodePoolByName(ws, p2.Name)
+	assert.Equal(t, p1, out1)
+	assert.Equal(t, p2, out2)
+}
+
+func TestFSM_SnapshotRestore_NamespacesAndQuotaSpecs(t *testing.T) {
+	t.Parallel()
+	// Add some state
+	fsm := testFSM(t)
+	state := fsm.State()
+	spec := &structs.QuotaSpec{
+		Name:   "small",
+		Limits: []*structs.QuotaLimit{{Region: "global", RegionLimit: &structs.Resources{CPU: 1000}}},
+	}
+	state.UpsertQuotaSpecs(1000, []*structs.QuotaSpec{spec})
+	ns := &structs.Namespace{Name: "team-a", Quota: spec.Name}
+	state.UpsertNamespaces(1001, []*structs.Namespace{ns})
+
+	// Verify the contents
+	fsm2 := testSnapshotRestore(t, fsm)
+	state2 := fsm2.State()
+	ws := memdb.NewWatchSet()
+	outSpec, _ := state2.QuotaSpecByName(ws, spec.Name)
+	outNs, _ := state2.NamespaceByName(ws, ns.Name)
+	assert.Equal(t, spec, outSpec)
+	assert.Equal(t, ns, outNs)
+}
+
 func TestFSM_SnapshotRestore_ACLTokens(t *testing.T) {
 	t.Parallel()
 	// Add some state
@@ -2678,6 +3032,53 @@ func TestFSM_SnapshotRestore_ACLTokens(t *testing.T) {
 	assert.Equal(t, tk2, out2)
 }
 
+func TestFSM_SnapshotRestore_ACLAuthMethodsAndBindingRules(t *testing.T) {
+	t.Parallel()
+	// Add some state
+	fsm := testFSM(t)
+	state := fsm.State()
+	am := mock.ACLAuthMethod()
+	rule := mock.ACLBindingRule()
+	rule.AuthMethod = am.Name
+	require.NoError(t, state.UpsertACLAuthMethods(1000, []*structs.ACLAuthMethod{am}))
+	require.NoError(t, state.UpsertACLBindingRules(1001, []*structs.ACLBindingRule{rule}))
+
+	// Verify the contents
+	fsm2 := testSnapshotRestore(t, fsm)
+	state2 := fsm2.State()
+	outMethod, _ := state2.ACLAuthMethodByName(nil, am.Name)
+	outRule, _ := state2.ACLBindingRuleByID(nil, rule.ID)
+	require.Equal(t, am, outMethod)
+	require.Equal(t, rule, outRule)
+}
+
+func TestFSM_SnapshotRestore_ACLRoles(t *testing.T) {
+	t.Parallel()
+	// Add some state
+	fsm := testFSM(t)
+	role := mock.ACLRole()
+	require.NoError(t, fsm.State().UpsertACLRoles(1000, []*structs.ACLRole{role}))
+
+	// Verify the contents
+	fsm2 := testSnapshotRestore(t, fsm)
+	out, _ := fsm2.State().ACLRoleByName(nil, role.Name)
+	require.Equal(t, role, out)
+}
+
+func TestFSM_SnapshotRestore_Volumes(t *testing.T) {
+	t.Parallel()
+	// Add some state
+	fsm := testFSM(t)
+	state := fsm.State()
+	volume := mock.Volume()
+	require.NoError(t, state.UpsertVolumes(1000, []*structs.Volume{volume}))
+
+	// Verify the contents
+	fsm2 := testSnapshotRestore(t, fsm)
+	out, _ := fsm2.State().VolumeByID(nil, volume.Namespace, volume.ID)
+	require.Equal(t, volume, out)
+}
+
 func TestFSM_SnapshotRestore_SchedulerConfiguration(t *testing.T) {
 	t.Parallel()
 	// Add some state
@@ -2938,3 +3339,185 @@ func TestFSM_SchedulerConfig(t *testing.T) {
 	// Verify that preemption is still enabled
 	require.True(config.PreemptionConfig.SystemSchedulerEnabled)
 }
+
+func TestFSM_SnapshotRestore_Variables(t *testing.T) {
+	t.Parallel()
+	// Add some state
+	fsm := testFSM(t)
+	state := fsm.State()
+	encrypter, err := NewEncrypter(nil, make([]byte, KeyringEncryptionKeySize))
+	require.NoError(t, err)
+	key, err := encrypter.newRootKey()
+	require.NoError(t, err)
+	require.NoError(t, state.UpsertRootKey(1000, key))
+	v := &structs.VariableEncrypted{
+		VariableMetadata: structs.VariableMetadata{
+			Namespace: structs.DefaultNamespace,
+			Path:      "project/web",
+		},
+		Data:  []byte("encrypted"),
+		KeyID: key.KeyID,
+	}
+	require.NoError(t, state.UpsertVariable(1001, v, nil))
+
+	// Verify the contents
+	fsm2 := testSnapshotRestore(t, fsm)
+	state2 := fsm2.State()
+	ws := memdb.NewWatchSet()
+	outKey, _ := state2.RootKeyByID(ws, key.KeyID)
+	outVar, _ := state2.VariableByPath(ws, v.Namespace, v.Path)
+	require.Equal(t, key, outKey)
+	require.Equal(t, v, outVar)
+}
+
+func TestFSM_UpsertDeleteServiceRegistrations(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	fsm := testFSM(t)
+
+	service := &structs.ServiceRegistration{
+		ID:          "_nomad-task-web",
+		ServiceName: "web",
+		Namespace:   structs.DefaultNamespace,
+		JobID:       "example",
+		AllocID:     uuid.Generate(),
+		Task:        "frontend",
+	}
+	req := structs.ServiceRegistrationUpsertRequest{
+		Services: []*structs.ServiceRegistration{service},
+	}
+	buf, err := structs.Encode(structs.ServiceRegistrationUpsertRequestType, req)
+	require.NoError(err)
+	require.Nil(fsm.Apply(makeLog(buf)))
+
+	out, err := fsm.State().ServiceRegistrationByID(nil, service.ID)
+	require.NoError(err)
+	require.NotNil(out)
+	require.EqualValues(1, out.CreateIndex)
+
+	del := structs.ServiceRegistrationDeleteRequest{
+		AllocID: service.AllocID,
+		Task:    service.Task,
+	}
+	buf, err = structs.Encode(structs.ServiceRegistrationDeleteRequestType, del)
+	require.NoError(err)
+	require.Nil(fsm.Apply(makeLog(buf)))
+
+	out, err = fsm.State().ServiceRegistrationByID(nil, service.ID)
+	require.NoError(err)
+	require.Nil(out)
+}
+
+func TestFSM_SnapshotRestore_ServiceRegistrations(t *testing.T) {
+	t.Parallel()
+	// Add some state
+	fsm := testFSM(t)
+	state := fsm.State()
+	service := &structs.ServiceRegistration{
+		ID:          "_nomad-task-web",
+		ServiceName: "web",
+		Namespace:   structs.DefaultNamespace,
+		JobID:       "example",
+		AllocID:     uuid.Generate(),
+		Task:        "frontend",
+		Address:     "10.0.0.1",
+		Port:        8080,
+	}
+	require.NoError(t, state.UpsertServiceRegistrations(1000, []*structs.ServiceRegistration{service}))
+
+	// Verify the contents
+	fsm2 := testSnapshotRestore(t, fsm)
+	out, err := fsm2.State().ServiceRegistrationByID(nil, service.ID)
+	require.NoError(t, err)
+	require.Equal(t, service, out)
+}
+
+func TestFSM_SnapshotRestore_ScalingEvents(t *testing.T) {
+	t.Parallel()
+	// Add some state
+	fsm := testFSM(t)
+	state := fsm.State()
+	req := &structs.ScalingEventRegisterRequest{
+		Namespace: structs.DefaultNamespace,
+		JobID:     "web",
+		TaskGroup: "cache",
+		ScalingEvent: &structs.ScalingEvent{
+			Time:          time.Now().UnixNano(),
+			Count:         helper.Int64ToPtr(3),
+			PreviousCount: 1,
+			Message:       "scaling up",
+		},
+	}
+	require.NoError(t, state.UpsertScalingEvent(1000, req))
+
+	// Verify the contents
+	fsm2 := testSnapshotRestore(t, fsm)
+	state2 := fsm2.State()
+	events, index, err := state2.ScalingEventsByJob(nil, structs.DefaultNamespace, "web")
+	require.NoError(t, err)
+	require.EqualValues(t, 1000, index)
+	require.Len(t, events["cache"], 1)
+	require.Equal(t, "scaling up", events["cache"][0].Message)
+	require.EqualValues(t, 3, *events["cache"][0].Count)
+}
+
+func TestFSM_PublishEvents(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	fsm := testFSM(t)
+	fsm.eventBroker = NewEventBroker(10)
+
+	// Register a job
+	job := mock.Job()
+	buf, err := structs.Encode(structs.JobRegisterRequestType, structs.JobRegisterRequest{
+		Job:          job,
+		WriteRequest: structs.WriteRequest{Namespace: job.Namespace},
+	})
+	require.NoError(err)
+	require.Nil(fsm.Apply(&raft.Log{Index: 5, Data: buf}))
+
+	// Update an evaluation of the job along with an allocation
+	eval := mock.Eval()
+	eval.JobID = job.ID
+	alloc := mock.Alloc()
+	alloc.JobID = job.ID
+	require.NoError(fsm.State().UpsertAllocs(6, []*structs.Allocation{alloc}))
+	update := alloc.Copy()
+	update.ClientStatus = structs.AllocClientStatusRunning
+	buf, err = structs.Encode(structs.AllocClientUpdateRequestType, structs.AllocUpdateRequest{
+		Alloc: []*structs.Allocation{update},
+		Evals: []*structs.Evaluation{eval},
+	})
+	require.NoError(err)
+	require.Nil(fsm.Apply(&raft.Log{Index: 7, Data: buf}))
+
+	// Failed logs don't publish events
+	buf, err = structs.Encode(structs.DeploymentPromoteRequestType, structs.ApplyDeploymentPromoteRequest{
+		DeploymentPromoteRequest: structs.DeploymentPromoteRequest{DeploymentID: uuid.Generate()},
+	})
+	require.NoError(err)
+	require.NotNil(fsm.Apply(&raft.Log{Index: 8, Data: buf}))
+
+	batches, _ := fsm.eventBroker.Since(0)
+	require.Len(batches, 2)
+
+	require.Equal(uint64(5), batches[0].Index)
+	require.Len(batches[0].Events, 1)
+	event := batches[0].Events[0]
+	require.Equal(structs.TopicJob, event.Topic)
+	require.Equal(structs.TypeJobRegistered, event.Type)
+	require.Equal(job.ID, event.Key)
+	require.Equal(uint64(5), event.Index)
+	require.Equal(uint64(5), event.Payload["Job"].(*structs.Job).CreateIndex)
+
+	// The events of a log are published in a single batch
+	require.Equal(uint64(7), batches[1].Index)
+	require.Len(batches[1].Events, 2)
+	allocEvent := batches[1].Events[0]
+	require.Equal(structs.TypeAllocationUpdated, allocEvent.Type)
+	require.Equal(alloc.ID, allocEvent.Key)
+	require.Contains(allocEvent.FilterKeys, job.ID)
+	require.Equal(structs.AllocClientStatusRunning, allocEvent.Payload["Allocation"].(*structs.AllocListStub).ClientStatus)
+	require.Equal(structs.TypeEvaluationUpdated, batches[1].Events[1].Type)
+	require.Equal(eval.ID, batches[1].Events[1].Key)
+}
@@ -156,7 +156,7 @@ func (h *nodeHeartbeater) invalidateHeartbeat(id string) {
 	req := structs.NodeUpdateStatusRequest{
 		NodeID:    id,
 		Status:    structs.NodeStatusDown,
-		NodeEvent: structs.NewNodeEvent().SetSubsystem(structs.NodeEventSubsystemCluster).SetMessage(NodeHeartbeatEventMissed),
+		NodeEvent: structs.NewNodeEvent().SetSubsystem(structs.NodeEventSubsystemHeartbeat).SetMessage(NodeHeartbeatEventMissed),
 		WriteRequest: structs.WriteRequest{
 			Region: h.config.Region,
 		},
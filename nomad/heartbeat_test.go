@@ -160,6 +160,7 @@ func TestHeartbeat_InvalidateHeartbeat(t *testing.T) {
 	require.True(out.TerminalStatus())
 	require.Len(out.Events, 2)
 	require.Equal(NodeHeartbeatEventMissed, out.Events[1].Message)
+	require.Equal(structs.NodeEventSubsystemHeartbeat, out.Events[1].Subsystem)
 }
 
 func TestHeartbeat_ClearHeartbeatTimer(t *testing.T) {
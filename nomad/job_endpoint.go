@@ -30,6 +30,10 @@ const (
 	// DispatchPayloadSizeLimit is the maximum size of the uncompressed input
 	// data payload.
 	DispatchPayloadSizeLimit = 16 * 1024
+
+	// DispatchBatchSizeLimit is the maximum number of jobs dispatched by a
+	// single batch dispatch request.
+	DispatchBatchSizeLimit = 1000
 )
 
 var (
@@ -67,21 +71,6 @@ func (j *Job) Register(args *structs.JobRegisterRequest, reply *structs.JobRegis
 		return fmt.Errorf("missing job for registration")
 	}
 
-	// Initialize the job fields (sets defaults and any necessary init work).
-	canonicalizeWarnings := args.Job.Canonicalize()
-
-	// Add implicit constraints
-	setImplicitConstraints(args.Job)
-
-	// Validate the job and capture any warnings
-	err, warnings := validateJob(args.Job)
-	if err != nil {
-		return err
-	}
-
-	// Set the warning message
-	reply.Warnings = structs.MergeMultierrorWarnings(warnings, canonicalizeWarnings)
-
 	// Check job submission permissions
 	if aclObj, err := j.srv.ResolveToken(args.AuthToken); err != nil {
 		return err
@@ -99,6 +88,28 @@ func (j *Job) Register(args *structs.JobRegisterRequest, reply *structs.JobRegis
 		}
 	}
 
+	// Run the admission controllers, which canonicalize, mutate and validate
+	// the job
+	job, warnings, err := j.admissionControllers(admissionOperationRegister, args.Job)
+	if err != nil {
+		return err
+	}
+	args.Job = job
+
+	// Set the warning message
+	reply.Warnings = structs.MergeMultierrorWarnings(warnings...)
+
+	// Register a multiregion job in each of its regions, unless the request
+	// is the copy fanned out by the region the job was submitted to
+	if args.Job.IsMultiregion() {
+		if args.MultiregionOrigin == nil {
+			return j.multiregionRegister(args, reply)
+		}
+		if err := j.verifyMultiregionOrigin(args.MultiregionOrigin, args.RequestNamespace(), args.Job.ID); err != nil {
+			return err
+		}
+	}
+
 	// Lookup the job
 	snap, err := j.srv.State().Snapshot()
 	if err != nil {
@@ -130,6 +141,15 @@ func (j *Job) Register(args *structs.JobRegisterRequest, reply *structs.JobRegis
 		return err
 	}
 
+	// Ensure the node pool the job targets exists
+	if pool := args.Job.NodePool; pool != "" && pool != structs.NodePoolDefault {
+		if existing, err := snap.NodePoolByName(ws, pool); err != nil {
+			return err
+		} else if existing == nil {
+			return fmt.Errorf("node pool %q does not exist", pool)
+		}
+	}
+
 	// Ensure that the job has permissions for the requested Vault tokens
 	policies := args.Job.VaultPolicies()
 	if len(policies) != 0 {
@@ -173,8 +193,7 @@ func (j *Job) Register(args *structs.JobRegisterRequest, reply *structs.JobRegis
 		return err
 	}
 	if policyWarnings != nil {
-		reply.Warnings = structs.MergeMultierrorWarnings(warnings,
-			canonicalizeWarnings, policyWarnings)
+		reply.Warnings = structs.MergeMultierrorWarnings(append(warnings, policyWarnings)...)
 	}
 
 	// Clear the Vault token
@@ -369,14 +388,9 @@ func (j *Job) Validate(args *structs.JobValidateRequest, reply *structs.JobValid
 		return structs.ErrPermissionDenied
 	}
 
-	// Initialize the job fields (sets defaults and any necessary init work).
-	canonicalizeWarnings := args.Job.Canonicalize()
-
-	// Add implicit constraints
-	setImplicitConstraints(args.Job)
-
-	// Validate the job and capture any warnings
-	err, warnings := validateJob(args.Job)
+	// Run the admission controllers, which canonicalize, mutate and validate
+	// the job, and capture any warnings
+	_, warnings, err := j.admissionControllers(admissionOperationValidate, args.Job)
 	if err != nil {
 		if merr, ok := err.(*multierror.Error); ok {
 			for _, err := range merr.Errors {
@@ -390,7 +404,7 @@ func (j *Job) Validate(args *structs.JobValidateRequest, reply *structs.JobValid
 	}
 
 	// Set the warning message
-	reply.Warnings = structs.MergeMultierrorWarnings(warnings, canonicalizeWarnings)
+	reply.Warnings = structs.MergeMultierrorWarnings(warnings...)
 	reply.DriverConfigValidated = true
 	return nil
 }
@@ -599,6 +613,142 @@ func (j *Job) Evaluate(args *structs.JobEvaluateRequest, reply *structs.JobRegis
 	return nil
 }
 
+// multiregionRegister registers the copy of a multiregion job in each of its
+// regions. The reply is the reply of the local region or, if the job is not
+// registered in the local region, of the first region. If the registration
+// fails in any region, the error reports the outcome in each region.
+func (j *Job) multiregionRegister(args *structs.JobRegisterRequest, reply *structs.JobRegisterResponse) error {
+	regions := args.Job.Multiregion.Regions
+	if err := j.checkMultiregionRegions(regions); err != nil {
+		return err
+	}
+
+	origin := j.srv.multiregionFanouts.start(j.srv.Region(), args.RequestNamespace(), args.Job.ID, regions)
+	defer j.srv.multiregionFanouts.stop(origin.Token)
+
+	warnings := reply.Warnings
+	replyRegion := multiregionReplyRegion(regions, j.srv.Region())
+	status := newMultiregionStatus("register", len(regions))
+	for _, region := range regions {
+		regionArgs := *args
+		regionArgs.Region = region.Name
+		regionArgs.Job = args.Job.RegionalJob(region)
+		regionArgs.MultiregionOrigin = origin
+
+		var regionReply structs.JobRegisterResponse
+		if err := j.srv.RPC("Job.Register", &regionArgs, &regionReply); err != nil {
+			status.failed(region.Name, err)
+			continue
+		}
+		status.succeeded(region.Name)
+		if region.Name == replyRegion {
+			*reply = regionReply
+		}
+	}
+	if err := status.err(); err != nil {
+		return err
+	}
+
+	if reply.Warnings == "" {
+		reply.Warnings = warnings
+	}
+	return nil
+}
+
+// multiregionDeregister deregisters a multiregion job from each of its
+// regions. The reply is the reply of the local region or, if the job is not
+// registered in the local region, of the first region. If the deregistration
+// fails in any region, the error reports the outcome in each region.
+func (j *Job) multiregionDeregister(job *structs.Job, args *structs.JobDeregisterRequest, reply *structs.JobDeregisterResponse) error {
+	regions := job.Multiregion.Regions
+	if err := j.checkMultiregionRegions(regions); err != nil {
+		return err
+	}
+
+	origin := j.srv.multiregionFanouts.start(j.srv.Region(), args.RequestNamespace(), args.JobID, regions)
+	defer j.srv.multiregionFanouts.stop(origin.Token)
+
+	replyRegion := multiregionReplyRegion(regions, j.srv.Region())
+	status := newMultiregionStatus("deregister", len(regions))
+	for _, region := range regions {
+		regionArgs := *args
+		regionArgs.Region = region.Name
+		regionArgs.MultiregionOrigin = origin
+
+		var regionReply structs.JobDeregisterResponse
+		if err := j.srv.RPC("Job.Deregister", &regionArgs, &regionReply); err != nil {
+			status.failed(region.Name, err)
+			continue
+		}
+		status.succeeded(region.Name)
+		if region.Name == replyRegion {
+			*reply = regionReply
+		}
+	}
+	return status.err()
+}
+
+// verifyMultiregionOrigin verifies with the origin region of a request for a
+// multiregion job that the request was fanned out by it, so that clients can
+// not register or deregister a job in a single one of its regions.
+func (j *Job) verifyMultiregionOrigin(origin *structs.MultiregionOrigin, namespace, jobID string) error {
+	args := &structs.JobMultiregionVerifyRequest{
+		Token:      origin.Token,
+		JobID:      jobID,
+		CopyRegion: j.srv.Region(),
+		QueryOptions: structs.QueryOptions{
+			Region:    origin.Region,
+			Namespace: namespace,
+		},
+	}
+	var reply structs.JobMultiregionVerifyResponse
+	if err := j.srv.RPC("Job.MultiregionVerify", args, &reply); err != nil {
+		return fmt.Errorf("failed to verify multiregion request with region %q: %v", origin.Region, err)
+	}
+	if !reply.Valid {
+		return fmt.Errorf("multiregion request was not fanned out by region %q", origin.Region)
+	}
+	return nil
+}
+
+// MultiregionVerify is used by the regions of a multiregion job to verify
+// that a request for the job is being fanned out by this region.
+func (j *Job) MultiregionVerify(args *structs.JobMultiregionVerifyRequest, reply *structs.JobMultiregionVerifyResponse) error {
+	if done, err := j.srv.forward("Job.MultiregionVerify", args, args, reply); done {
+		return err
+	}
+
+	reply.Valid = j.srv.multiregionFanouts.valid(args.Token, args.RequestNamespace(), args.JobID, args.CopyRegion)
+	j.srv.setQueryMeta(&reply.QueryMeta)
+	return nil
+}
+
+// checkMultiregionRegions returns an error if any of the regions of a
+// multiregion job is unknown to the server.
+func (j *Job) checkMultiregionRegions(regions []*structs.MultiregionRegion) error {
+	known := make(map[string]struct{})
+	for _, region := range j.srv.Regions() {
+		known[region] = struct{}{}
+	}
+	for _, region := range regions {
+		if _, ok := known[region.Name]; !ok {
+			return fmt.Errorf("multiregion job references unknown region %q", region.Name)
+		}
+	}
+	return nil
+}
+
+// multiregionReplyRegion returns the region whose reply is returned for a
+// request fanned out to the regions of a multiregion job.
+func multiregionReplyRegion(regions []*structs.MultiregionRegion, local string) string {
+	for _, region := range regions {
+		if region.Name == local {
+			return local
+		}
+	}
+	return regions[0].Name
+}
+
 // Deregister is used to remove a job the cluster.
 func (j *Job) Deregister(args *structs.JobDeregisterRequest, reply *structs.JobDeregisterResponse) error {
 	if done, err := j.srv.forward("Job.Deregister", args, args, reply); done {
@@ -629,6 +779,17 @@ func (j *Job) Deregister(args *structs.JobDeregisterRequest, reply *structs.JobD
 		return err
 	}
 
+	// Deregister a multiregion job from each of its regions, unless the
+	// request is the copy fanned out by the region it was sent to
+	if job != nil && job.IsMultiregion() {
+		if args.MultiregionOrigin == nil {
+			return j.multiregionDeregister(job, args, reply)
+		}
+		if err := j.verifyMultiregionOrigin(args.MultiregionOrigin, args.RequestNamespace(), args.JobID); err != nil {
+			return err
+		}
+	}
+
 	// Commit this update via Raft
 	_, index, err := j.srv.raftApply(structs.JobDeregisterRequestType, args)
 	if err != nil {
@@ -871,9 +1032,10 @@ func (j *Job) List(args *structs.JobListRequest,
 	defer metrics.MeasureSince([]string{"nomad", "job", "list"}, time.Now())
 
 	// Check for list-job permissions
-	if aclObj, err := j.srv.ResolveToken(args.AuthToken); err != nil {
+	aclObj, err := j.srv.ResolveToken(args.AuthToken)
+	if err != nil {
 		return err
-	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityListJobs) {
+	} else if !allowNsOp(aclObj, args.RequestNamespace(), acl.NamespaceCapabilityListJobs) {
 		return structs.ErrPermissionDenied
 	}
 
@@ -893,21 +1055,43 @@ func (j *Job) List(args *structs.JobListRequest,
 			if err != nil {
 				return err
 			}
+			iter = namespaceACLFilter(iter, aclObj, args.RequestNamespace(), acl.NamespaceCapabilityListJobs,
+				func(raw interface{}) string {
+					return raw.(*structs.Job).Namespace
+				})
+
+			paginator, err := newPaginator(iter, args.QueryOptions,
+				func(raw interface{}) string {
+					job := raw.(*structs.Job)
+					if args.RequestNamespace() == structs.AllNamespacesSentinel {
+						// The jobs of all the namespaces are ordered by
+						// namespace first, and the separator sorts before
+						// any character of the namespace names
+						return job.Namespace + "," + job.ID
+					}
+					return job.ID
+				},
+				func(raw interface{}) (interface{}, error) {
+					job := raw.(*structs.Job)
+					summary, err := state.JobSummaryByID(ws, job.Namespace, job.ID)
+					if err != nil {
+						return nil, fmt.Errorf("unable to look up summary for job: %v", job.ID)
+					}
+					return job.Stub(summary), nil
+				})
+			if err != nil {
+				return err
+			}
 
 			var jobs []*structs.JobListStub
-			for {
-				raw := iter.Next()
-				if raw == nil {
-					break
-				}
-				job := raw.(*structs.Job)
-				summary, err := state.JobSummaryByID(ws, args.RequestNamespace(), job.ID)
-				if err != nil {
-					return fmt.Errorf("unable to look up summary for job: %v", job.ID)
-				}
-				jobs = append(jobs, job.Stub(summary))
+			nextToken, err := paginator.Page(func(obj interface{}) {
+				jobs = append(jobs, obj.(*structs.JobListStub))
+			})
+			if err != nil {
+				return err
 			}
 			reply.Jobs = jobs
+			reply.NextToken = nextToken
 
 			// Use the last index that affected the jobs table or summary
 			jindex, err := state.Index("jobs")
@@ -1120,21 +1304,6 @@ func (j *Job) Plan(args *structs.JobPlanRequest, reply *structs.JobPlanResponse)
 		return fmt.Errorf("Job required for plan")
 	}
 
-	// Initialize the job fields (sets defaults and any necessary init work).
-	canonicalizeWarnings := args.Job.Canonicalize()
-
-	// Add implicit constraints
-	setImplicitConstraints(args.Job)
-
-	// Validate the job and capture any warnings
-	err, warnings := validateJob(args.Job)
-	if err != nil {
-		return err
-	}
-
-	// Set the warning message
-	reply.Warnings = structs.MergeMultierrorWarnings(warnings, canonicalizeWarnings)
-
 	// Check job submission permissions, which we assume is the same for plan
 	if aclObj, err := j.srv.ResolveToken(args.AuthToken); err != nil {
 		return err
@@ -1150,14 +1319,24 @@ func (j *Job) Plan(args *structs.JobPlanRequest, reply *structs.JobPlanResponse)
 		}
 	}
 
+	// Run the admission controllers, which canonicalize, mutate and validate
+	// the job
+	job, warnings, err := j.admissionControllers(admissionOperationPlan, args.Job)
+	if err != nil {
+		return err
+	}
+	args.Job = job
+
+	// Set the warning message
+	reply.Warnings = structs.MergeMultierrorWarnings(warnings...)
+
 	// Enforce Sentinel policies
 	policyWarnings, err := j.enforceSubmitJob(args.PolicyOverride, args.Job)
 	if err != nil {
 		return err
 	}
 	if policyWarnings != nil {
-		reply.Warnings = structs.MergeMultierrorWarnings(warnings,
-			canonicalizeWarnings, policyWarnings)
+		reply.Warnings = structs.MergeMultierrorWarnings(append(warnings, policyWarnings)...)
 	}
 
 	// Acquire a snapshot of the state
@@ -1340,54 +1519,38 @@ func (j *Job) Dispatch(args *structs.JobDispatchRequest, reply *structs.JobDispa
 	}
 
 	// Lookup the parameterized job
-	if args.JobID == "" {
-		return fmt.Errorf("missing parameterized job ID")
-	}
-
 	snap, err := j.srv.fsm.State().Snapshot()
 	if err != nil {
 		return err
 	}
-	ws := memdb.NewWatchSet()
-	parameterizedJob, err := snap.JobByID(ws, args.RequestNamespace(), args.JobID)
+	parameterizedJob, err := lookupParameterizedJob(snap, args.RequestNamespace(), args.JobID)
 	if err != nil {
 		return err
 	}
-	if parameterizedJob == nil {
-		return fmt.Errorf("parameterized job not found")
-	}
-
-	if !parameterizedJob.IsParameterized() {
-		return fmt.Errorf("Specified job %q is not a parameterized job", args.JobID)
-	}
-
-	if parameterizedJob.Stop {
-		return fmt.Errorf("Specified job %q is stopped", args.JobID)
-	}
 
 	// Validate the arguments
 	if err := validateDispatchRequest(args, parameterizedJob); err != nil {
 		return err
 	}
 
-	// Derive the child job and commit it via Raft
-	dispatchJob := parameterizedJob.Copy()
-	dispatchJob.ID = structs.DispatchedID(parameterizedJob.ID, time.Now())
-	dispatchJob.ParentID = parameterizedJob.ID
-	dispatchJob.Name = dispatchJob.ID
-	dispatchJob.SetSubmitTime()
-	dispatchJob.Dispatched = true
-
-	// Merge in the meta data
-	for k, v := range args.Meta {
-		if dispatchJob.Meta == nil {
-			dispatchJob.Meta = make(map[string]string, len(args.Meta))
+	// Return the existing job if the job was already dispatched with the
+	// idempotency token
+	if args.IdempotencyToken != "" {
+		existing, err := snap.DispatchedJobByIdempotencyToken(nil, args.RequestNamespace(), args.JobID, args.IdempotencyToken)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			j.logger.Debug("dispatch with idempotency token of existing job ignored", "job", existing.NamespacedID())
+			reply.DispatchedJobID = existing.ID
+			reply.JobCreateIndex = existing.CreateIndex
+			reply.Index = existing.ModifyIndex
+			return nil
 		}
-		dispatchJob.Meta[k] = v
 	}
 
-	// Compress the payload
-	dispatchJob.Payload = snappy.Encode(nil, args.Payload)
+	// Derive the child job and commit it via Raft
+	dispatchJob := deriveDispatchedJob(parameterizedJob, args.Payload, args.Meta, args.IdempotencyToken)
 
 	regReq := &structs.JobRegisterRequest{
 		Job:          dispatchJob,
@@ -1443,6 +1606,442 @@ func (j *Job) Dispatch(args *structs.JobDispatchRequest, reply *structs.JobDispa
 	return nil
 }
 
+// DispatchBatch is used to dispatch a parameterized job multiple times. The
+// dispatched jobs and their evaluations are committed in a single Raft
+// transaction.
+func (j *Job) DispatchBatch(args *structs.JobBatchDispatchRequest, reply *structs.JobBatchDispatchResponse) error {
+	if done, err := j.srv.forward("Job.DispatchBatch", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "job", "dispatch_batch"}, time.Now())
+
+	// Check for submit-job permissions
+	if aclObj, err := j.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityDispatchJob) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Validate the arguments
+	if len(args.Dispatches) == 0 {
+		return fmt.Errorf("missing dispatches")
+	} else if l := len(args.Dispatches); l > DispatchBatchSizeLimit {
+		return fmt.Errorf("Batch exceeds maximum number of dispatches; %d > %d", l, DispatchBatchSizeLimit)
+	}
+
+	// Lookup the parameterized job
+	snap, err := j.srv.fsm.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	parameterizedJob, err := lookupParameterizedJob(snap, args.RequestNamespace(), args.JobID)
+	if err != nil {
+		return err
+	}
+	if parameterizedJob.IsPeriodic() {
+		return fmt.Errorf("Specified job %q is periodic and can not be dispatched in batches", args.JobID)
+	}
+
+	// Derive the child jobs and their evaluations. Dispatches with the
+	// idempotency token of an existing job, or of an earlier dispatch of the
+	// batch, reply with that job.
+	batch := &structs.JobBatchRegisterRequest{
+		WriteRequest: args.WriteRequest,
+	}
+	reply.Dispatches = make([]*structs.JobDispatchResponse, len(args.Dispatches))
+	tokens := make(map[string]*structs.JobDispatchResponse)
+	var dispatched []*structs.JobDispatchResponse
+	for i, d := range args.Dispatches {
+		req := &structs.JobDispatchRequest{
+			JobID:            args.JobID,
+			Payload:          d.Payload,
+			Meta:             d.Meta,
+			IdempotencyToken: d.IdempotencyToken,
+		}
+		if err := validateDispatchRequest(req, parameterizedJob); err != nil {
+			return fmt.Errorf("dispatch %d: %v", i, err)
+		}
+
+		if d.IdempotencyToken != "" {
+			if resp, ok := tokens[d.IdempotencyToken]; ok {
+				reply.Dispatches[i] = resp
+				continue
+			}
+
+			existing, err := snap.DispatchedJobByIdempotencyToken(nil, args.RequestNamespace(), args.JobID, d.IdempotencyToken)
+			if err != nil {
+				return err
+			}
+			if existing != nil {
+				resp := &structs.JobDispatchResponse{
+					DispatchedJobID: existing.ID,
+					JobCreateIndex:  existing.CreateIndex,
+				}
+				tokens[d.IdempotencyToken] = resp
+				reply.Dispatches[i] = resp
+				continue
+			}
+		}
+
+		dispatchJob := deriveDispatchedJob(parameterizedJob, d.Payload, d.Meta, d.IdempotencyToken)
+		eval := &structs.Evaluation{
+			ID:          uuid.Generate(),
+			Namespace:   args.RequestNamespace(),
+			Priority:    dispatchJob.Priority,
+			Type:        dispatchJob.Type,
+			TriggeredBy: structs.EvalTriggerJobRegister,
+			JobID:       dispatchJob.ID,
+			Status:      structs.EvalStatusPending,
+		}
+		batch.Jobs = append(batch.Jobs, dispatchJob)
+		batch.Evals = append(batch.Evals, eval)
+
+		resp := &structs.JobDispatchResponse{
+			DispatchedJobID: dispatchJob.ID,
+			EvalID:          eval.ID,
+		}
+		if d.IdempotencyToken != "" {
+			tokens[d.IdempotencyToken] = resp
+		}
+		reply.Dispatches[i] = resp
+		dispatched = append(dispatched, resp)
+	}
+
+	// Nothing to commit if every dispatch already exists
+	if len(batch.Jobs) == 0 {
+		reply.Index = parameterizedJob.ModifyIndex
+		return nil
+	}
+
+	// Commit the jobs and evaluations via Raft
+	fsmErr, index, err := j.srv.raftApply(structs.JobBatchRegisterRequestType, batch)
+	if err, ok := fsmErr.(error); ok && err != nil {
+		j.logger.Error("batch dispatched job register failed", "error", err, "fsm", true)
+		return err
+	}
+	if err != nil {
+		j.logger.Error("batch dispatched job register failed", "error", err, "raft", true)
+		return err
+	}
+
+	for _, resp := range dispatched {
+		resp.JobCreateIndex = index
+		resp.EvalCreateIndex = index
+		resp.Index = index
+	}
+	reply.Index = index
+	return nil
+}
+
+// Scale is used to set the count of a task group of a job. Every request is
+// recorded as a scaling event of the task group, including requests that
+// only report a message or an error without changing the count.
+func (j *Job) Scale(args *structs.JobScaleRequest, reply *structs.JobRegisterResponse) error {
+	if done, err := j.srv.forward("Job.Scale", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "job", "scale"}, time.Now())
+
+	// Check for scale-job or submit-job permissions
+	if aclObj, err := j.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil &&
+		!aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityScaleJob) &&
+		!aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilitySubmitJob) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Validate the arguments
+	if err := args.Validate(); err != nil {
+		return err
+	}
+	namespace := args.RequestNamespace()
+	groupName := args.Target[structs.ScalingTargetGroup]
+
+	// Lookup the job and the task group
+	snap, err := j.srv.fsm.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	job, err := snap.JobByID(nil, namespace, args.JobID)
+	if err != nil {
+		return err
+	}
+	if job == nil {
+		return fmt.Errorf("job %q not found", args.JobID)
+	}
+	if job.Type == structs.JobTypeSystem || job.Type == structs.JobTypeSysBatch {
+		return fmt.Errorf("cannot scale jobs of type %q", job.Type)
+	}
+	group := job.LookupTaskGroup(groupName)
+	if group == nil {
+		return fmt.Errorf("task group %q not found in job %q", groupName, args.JobID)
+	}
+
+	event := &structs.ScalingEvent{
+		Time:          time.Now().UnixNano(),
+		Count:         args.Count,
+		PreviousCount: int64(group.Count),
+		Message:       args.Message,
+		Error:         args.Error,
+		Meta:          args.Meta,
+	}
+
+	// Record who requested the scaling
+	if j.srv.config.ACLEnabled && args.AuthToken != "" {
+		token, err := snap.ACLTokenBySecretID(nil, args.AuthToken)
+		if err != nil {
+			return err
+		}
+		if token != nil {
+			event.AccessorID = token.AccessorID
+		}
+	}
+
+	// Register the job with the new count and evaluate it
+	reply.JobModifyIndex = job.JobModifyIndex
+	if args.Count != nil && *args.Count != int64(group.Count) {
+		if job.Stop {
+			return fmt.Errorf("job %q is stopped", args.JobID)
+		}
+
+		scaled := job.Copy()
+		scaled.LookupTaskGroup(groupName).Count = int(*args.Count)
+
+		// The scaled job is registered like any other job, so it must pass
+		// the admission controllers and the submission policies
+		scaled, warnings, err := j.admissionControllers(admissionOperationRegister, scaled)
+		if err != nil {
+			return err
+		}
+		policyWarnings, err := j.enforceSubmitJob(false, scaled)
+		if err != nil {
+			return err
+		}
+		if policyWarnings != nil {
+			warnings = append(warnings, policyWarnings)
+		}
+		reply.Warnings = structs.MergeMultierrorWarnings(warnings...)
+
+		scaled.SetSubmitTime()
+		regReq := &structs.JobRegisterRequest{
+			Job:          scaled,
+			WriteRequest: args.WriteRequest,
+		}
+
+		fsmErr, index, err := j.srv.raftApply(structs.JobRegisterRequestType, regReq)
+		if err, ok := fsmErr.(error); ok && err != nil {
+			j.logger.Error("scaled job register failed", "error", err, "fsm", true)
+			return err
+		}
+		if err != nil {
+			j.logger.Error("scaled job register failed", "error", err, "raft", true)
+			return err
+		}
+		reply.JobModifyIndex = index
+		reply.Index = index
+
+		// Periodic and parameterized jobs are evaluated when they are
+		// launched
+		if !scaled.IsPeriodic() && !scaled.IsParameterized() {
+			eval := &structs.Evaluation{
+				ID:             uuid.Generate(),
+				Namespace:      namespace,
+				Priority:       scaled.Priority,
+				Type:           scaled.Type,
+				TriggeredBy:    structs.EvalTriggerScaling,
+				JobID:          scaled.ID,
+				JobModifyIndex: index,
+				Status:         structs.EvalStatusPending,
+			}
+			update := &structs.EvalUpdateRequest{
+				Evals:        []*structs.Evaluation{eval},
+				WriteRequest: structs.WriteRequest{Region: args.Region},
+			}
+
+			_, evalIndex, err := j.srv.raftApply(structs.EvalUpdateRequestType, update)
+			if err != nil {
+				j.logger.Error("eval create failed", "error", err, "method", "scale")
+				return err
+			}
+			reply.EvalID = eval.ID
+			reply.EvalCreateIndex = evalIndex
+			reply.Index = evalIndex
+			event.EvalID = helper.StringToPtr(eval.ID)
+		}
+	}
+
+	// Record the scaling event
+	eventReq := &structs.ScalingEventRegisterRequest{
+		Namespace:    namespace,
+		JobID:        job.ID,
+		TaskGroup:    groupName,
+		ScalingEvent: event,
+		WriteRequest: args.WriteRequest,
+	}
+	fsmErr, index, err := j.srv.raftApply(structs.ScalingEventRegisterRequestType, eventReq)
+	if err, ok := fsmErr.(error); ok && err != nil {
+		j.logger.Error("scaling event register failed", "error", err, "fsm", true)
+		return err
+	}
+	if err != nil {
+		j.logger.Error("scaling event register failed", "error", err, "raft", true)
+		return err
+	}
+	if reply.Index == 0 {
+		reply.Index = index
+	}
+	return nil
+}
+
+// ScaleStatus is used to get the scaling status of the task groups of a job:
+// their desired count, the state of their allocations and their most recent
+// scaling events.
+func (j *Job) ScaleStatus(args *structs.JobScaleStatusRequest,
+	reply *structs.JobScaleStatusResponse) error {
+	if done, err := j.srv.forward("Job.ScaleStatus", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "job", "scale_status"}, time.Now())
+
+	// Check for read-job permissions
+	if aclObj, err := j.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityReadJob) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			namespace := args.RequestNamespace()
+			job, err := state.JobByID(ws, namespace, args.JobID)
+			if err != nil {
+				return err
+			}
+			if job == nil {
+				reply.JobScaleStatus = nil
+				index, err := state.Index("jobs")
+				if err != nil {
+					return err
+				}
+				reply.Index = index
+				return nil
+			}
+
+			allocs, err := state.AllocsByJob(ws, namespace, args.JobID, false)
+			if err != nil {
+				return err
+			}
+			deployment, err := state.LatestDeploymentByJobID(ws, namespace, args.JobID)
+			if err != nil {
+				return err
+			}
+			events, eventsIndex, err := state.ScalingEventsByJob(ws, namespace, args.JobID)
+			if err != nil {
+				return err
+			}
+
+			status := &structs.JobScaleStatus{
+				JobID:          job.ID,
+				Namespace:      job.Namespace,
+				JobCreateIndex: job.CreateIndex,
+				JobModifyIndex: job.ModifyIndex,
+				JobStopped:     job.Stop,
+				TaskGroups:     make(map[string]*structs.TaskGroupScaleStatus, len(job.TaskGroups)),
+			}
+			for _, tg := range job.TaskGroups {
+				tgStatus := &structs.TaskGroupScaleStatus{
+					Desired: tg.Count,
+					Events:  events[tg.Name],
+				}
+				if deployment != nil && deployment.JobVersion == job.Version {
+					if ds, ok := deployment.TaskGroups[tg.Name]; ok {
+						tgStatus.Healthy = ds.HealthyAllocs
+						tgStatus.Unhealthy = ds.UnhealthyAllocs
+					}
+				}
+				status.TaskGroups[tg.Name] = tgStatus
+			}
+
+			// Count the allocations of the task groups
+			maxIndex := helper.Uint64Max(job.ModifyIndex, eventsIndex)
+			for _, alloc := range allocs {
+				maxIndex = helper.Uint64Max(maxIndex, alloc.ModifyIndex)
+				tgStatus, ok := status.TaskGroups[alloc.TaskGroup]
+				if !ok || alloc.TerminalStatus() {
+					continue
+				}
+				tgStatus.Placed++
+				if alloc.ClientStatus == structs.AllocClientStatusRunning {
+					tgStatus.Running++
+				}
+			}
+			if deployment != nil {
+				maxIndex = helper.Uint64Max(maxIndex, deployment.ModifyIndex)
+			}
+
+			reply.JobScaleStatus = status
+			reply.Index = maxIndex
+
+			// Set the query response
+			j.srv.setQueryMeta(&reply.QueryMeta)
+			return nil
+		}}
+	return j.srv.blockingRPC(&opts)
+}
+
+// lookupParameterizedJob returns the parameterized job with the given ID or
+// an error if it does not exist or can not be dispatched.
+func lookupParameterizedJob(snap *state.StateSnapshot, namespace, jobID string) (*structs.Job, error) {
+	if jobID == "" {
+		return nil, fmt.Errorf("missing parameterized job ID")
+	}
+
+	parameterizedJob, err := snap.JobByID(nil, namespace, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if parameterizedJob == nil {
+		return nil, fmt.Errorf("parameterized job not found")
+	}
+
+	if !parameterizedJob.IsParameterized() {
+		return nil, fmt.Errorf("Specified job %q is not a parameterized job", jobID)
+	}
+
+	if parameterizedJob.Stop {
+		return nil, fmt.Errorf("Specified job %q is stopped", jobID)
+	}
+	return parameterizedJob, nil
+}
+
+// deriveDispatchedJob returns the child job of the parameterized job for a
+// dispatch with the given payload, metadata and idempotency token.
+func deriveDispatchedJob(parameterizedJob *structs.Job, payload []byte, meta map[string]string, token string) *structs.Job {
+	dispatchJob := parameterizedJob.Copy()
+	dispatchJob.ID = structs.DispatchedID(parameterizedJob.ID, time.Now())
+	dispatchJob.ParentID = parameterizedJob.ID
+	dispatchJob.Name = dispatchJob.ID
+	dispatchJob.SetSubmitTime()
+	dispatchJob.Dispatched = true
+	dispatchJob.DispatchIdempotencyToken = token
+
+	// Merge in the meta data
+	for k, v := range meta {
+		if dispatchJob.Meta == nil {
+			dispatchJob.Meta = make(map[string]string, len(meta))
+		}
+		dispatchJob.Meta[k] = v
+	}
+
+	// Compress the payload
+	dispatchJob.Payload = snappy.Encode(nil, payload)
+	return dispatchJob
+}
+
 // validateDispatchRequest returns whether the request is valid given the
 // parameterized job.
 func validateDispatchRequest(req *structs.JobDispatchRequest, job *structs.Job) error {
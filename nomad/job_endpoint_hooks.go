@@ -0,0 +1,130 @@
+package nomad
+
+import (
+	"fmt"
+
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// jobMutator is an admission controller modifying the submitted jobs.
+type jobMutator interface {
+	Name() string
+
+	// Mutate returns the mutated job, the warnings to report to the submitter
+	// and an error if the job is rejected.
+	Mutate(*structs.Job) (*structs.Job, []error, error)
+}
+
+// jobValidator is an admission controller accepting or rejecting the
+// submitted jobs.
+type jobValidator interface {
+	Name() string
+
+	// Validate returns the warnings to report to the submitter and an error
+	// if the job is rejected.
+	Validate(*structs.Job) ([]error, error)
+}
+
+// admissionControllers runs the admission controllers on a job submitted for
+// the given operation. The built-in mutators canonicalize the job and add its
+// implicit constraints, around the mutating webhooks, and the built-in
+// validator runs before the validating webhooks. It returns the mutated job,
+// the warnings of the controllers and the errors of the validators.
+func (j *Job) admissionControllers(operation string, job *structs.Job) (*structs.Job, []error, error) {
+	webhookMutators, webhookValidators := j.srv.admissionWebhooks(operation)
+
+	// Mutating webhooks may return jobs lacking defaults, so the jobs are
+	// canonicalized again after each of them
+	mutators := []jobMutator{jobCanonicalizer{}}
+	for _, m := range webhookMutators {
+		mutators = append(mutators, m, jobCanonicalizer{})
+	}
+	mutators = append(mutators, jobImpliedConstraints{})
+
+	validators := append([]jobValidator{jobValidate{}}, webhookValidators...)
+
+	job, warnings, err := admissionMutators(job, mutators)
+	if err != nil {
+		return nil, warnings, err
+	}
+	validateWarnings, err := admissionValidators(job, validators)
+	warnings = append(warnings, validateWarnings...)
+	if err != nil {
+		return nil, warnings, err
+	}
+	return job, warnings, nil
+}
+
+// admissionMutators runs the mutators in order, stopping at the first one
+// rejecting the job.
+func admissionMutators(job *structs.Job, mutators []jobMutator) (*structs.Job, []error, error) {
+	var warnings []error
+	for _, mutator := range mutators {
+		out, w, err := mutator.Mutate(job)
+		warnings = append(warnings, w...)
+		if err != nil {
+			return nil, warnings, err
+		}
+		job = out
+	}
+	return job, warnings, nil
+}
+
+// admissionValidators runs all the validators, and merges their errors.
+func admissionValidators(job *structs.Job, validators []jobValidator) ([]error, error) {
+	var warnings []error
+	var validationErrors *multierror.Error
+	for _, validator := range validators {
+		w, err := validator.Validate(job)
+		warnings = append(warnings, w...)
+		if err != nil {
+			validationErrors = multierror.Append(validationErrors, err)
+		}
+	}
+	return warnings, validationErrors.ErrorOrNil()
+}
+
+// jobCanonicalizer initializes the fields of the job, setting the defaults.
+type jobCanonicalizer struct{}
+
+func (jobCanonicalizer) Name() string {
+	return "canonicalize"
+}
+
+func (jobCanonicalizer) Mutate(job *structs.Job) (*structs.Job, []error, error) {
+	if job == nil {
+		return nil, nil, fmt.Errorf("missing job")
+	}
+	if warning := job.Canonicalize(); warning != nil {
+		return job, []error{warning}, nil
+	}
+	return job, nil, nil
+}
+
+// jobImpliedConstraints adds the implicit constraints of the job.
+type jobImpliedConstraints struct{}
+
+func (jobImpliedConstraints) Name() string {
+	return "implied_constraints"
+}
+
+func (jobImpliedConstraints) Mutate(job *structs.Job) (*structs.Job, []error, error) {
+	setImplicitConstraints(job)
+	return job, nil, nil
+}
+
+// jobValidate validates the job with validateJob.
+type jobValidate struct{}
+
+func (jobValidate) Name() string {
+	return "validate"
+}
+
+func (jobValidate) Validate(job *structs.Job) ([]error, error) {
+	err, warning := validateJob(job)
+	if warning != nil {
+		return []error{warning}, err
+	}
+	return nil, err
+}
@@ -0,0 +1,233 @@
+package nomad
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobEndpoint_Register_AdmissionWebhooks(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	// The mutating webhook adds a meta key to the jobs
+	var operations []string
+	var operationsLock sync.Mutex
+	mutating := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req AdmissionWebhookRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		operationsLock.Lock()
+		operations = append(operations, req.Operation)
+		operationsLock.Unlock()
+		req.Job.Meta = map[string]string{"team": "platform"}
+		json.NewEncoder(w).Encode(&AdmissionWebhookResponse{
+			Allowed:  true,
+			Warnings: []string{"team set"},
+			Job:      req.Job,
+		})
+	}))
+	defer mutating.Close()
+
+	// The validating webhook rejects the jobs with more than one task group
+	validating := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req AdmissionWebhookRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		resp := AdmissionWebhookResponse{Allowed: true}
+		if req.Job.Meta["team"] != "platform" || len(req.Job.TaskGroups) > 1 {
+			resp = AdmissionWebhookResponse{Reason: "too many task groups"}
+		}
+		json.NewEncoder(w).Encode(&resp)
+	}))
+	defer validating.Close()
+
+	s1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+		c.AdmissionWebhooks = []*config.AdmissionWebhookConfig{
+			{Name: "team", URL: mutating.URL, Type: config.AdmissionWebhookTypeMutating},
+			{Name: "groups", URL: validating.URL},
+			{Name: "down", URL: "http://127.0.0.1:0", FailurePolicy: config.AdmissionFailurePolicyIgnore},
+		}
+	})
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// The webhooks reuse the clients created with the server
+	mutators, _ := s1.admissionWebhooks(admissionOperationRegister)
+	planMutators, _ := s1.admissionWebhooks(admissionOperationPlan)
+	require.True(mutators[0].(*admissionWebhook).client == planMutators[0].(*admissionWebhook).client)
+
+	// The job is mutated and admitted
+	job := mock.Job()
+	req := &structs.JobRegisterRequest{
+		Job: job,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var resp structs.JobRegisterResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp))
+	require.Contains(resp.Warnings, "team: team set")
+	require.Contains(resp.Warnings, `admission webhook "down" ignored`)
+	operationsLock.Lock()
+	require.Equal([]string{admissionOperationRegister}, operations)
+	operationsLock.Unlock()
+
+	out, err := s1.fsm.State().JobByID(nil, job.Namespace, job.ID)
+	require.NoError(err)
+	require.NotNil(out)
+	require.Equal("platform", out.Meta["team"])
+
+	// The job is rejected by the validating webhook
+	job = mock.Job()
+	tg := job.TaskGroups[0].Copy()
+	tg.Name = "other"
+	job.TaskGroups = append(job.TaskGroups, tg)
+	req.Job = job
+	err = msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "too many task groups")
+
+	out, err = s1.fsm.State().JobByID(nil, job.Namespace, job.ID)
+	require.NoError(err)
+	require.Nil(out)
+
+	// The rejection is reported as a validation error
+	validateReq := &structs.JobValidateRequest{
+		Job: job,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var validateResp structs.JobValidateResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Validate", validateReq, &validateResp))
+	require.Len(validateResp.ValidationErrors, 1)
+	require.Contains(validateResp.Error, "too many task groups")
+}
+
+func TestJobEndpoint_AdmissionWebhooks_ACL(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	// The webhook records every job it is sent
+	var calls int
+	var callsLock sync.Mutex
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		callsLock.Lock()
+		calls++
+		callsLock.Unlock()
+		json.NewEncoder(w).Encode(&AdmissionWebhookResponse{Allowed: true})
+	}))
+	defer webhook.Close()
+
+	s1, root := TestACLServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+		c.AdmissionWebhooks = []*config.AdmissionWebhookConfig{
+			{Name: "record", URL: webhook.URL},
+		}
+	})
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Unauthorized registrations and plans are rejected before the webhooks
+	// are called
+	job := mock.Job()
+	req := &structs.JobRegisterRequest{
+		Job: job,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var resp structs.JobRegisterResponse
+	err := msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp)
+	require.EqualError(err, structs.ErrPermissionDenied.Error())
+
+	planReq := &structs.JobPlanRequest{
+		Job: job,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var planResp structs.JobPlanResponse
+	err = msgpackrpc.CallWithCodec(codec, "Job.Plan", planReq, &planResp)
+	require.EqualError(err, structs.ErrPermissionDenied.Error())
+
+	callsLock.Lock()
+	require.Zero(calls)
+	callsLock.Unlock()
+
+	// Authorized registrations go through the webhooks
+	req.AuthToken = root.SecretID
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp))
+	callsLock.Lock()
+	require.Equal(1, calls)
+	callsLock.Unlock()
+}
+
+func TestJobEndpoint_AdmissionWebhooks_Failure(t *testing.T) {
+	t.Parallel()
+
+	// The webhook moves the jobs to another namespace
+	moving := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req AdmissionWebhookRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		req.Job.Namespace = "other"
+		json.NewEncoder(w).Encode(&AdmissionWebhookResponse{Allowed: true, Job: req.Job})
+	}))
+	defer moving.Close()
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+
+	cases := []struct {
+		name    string
+		webhook *config.AdmissionWebhookConfig
+		err     string
+	}{
+		{
+			name:    "moved job",
+			webhook: &config.AdmissionWebhookConfig{Name: "moving", URL: moving.URL, Type: config.AdmissionWebhookTypeMutating},
+			err:     "changed the ID, namespace or region",
+		},
+		{
+			name:    "failing webhook",
+			webhook: &config.AdmissionWebhookConfig{Name: "failing", URL: failing.URL},
+			err:     "unexpected response code 500",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			srv := &Server{config: &Config{
+				AdmissionWebhooks: []*config.AdmissionWebhookConfig{c.webhook},
+			}}
+			require.NoError(t, srv.setupAdmissionWebhooks())
+			j := &Job{srv: srv}
+			_, _, err := j.admissionControllers(admissionOperationPlan, mock.Job())
+			require.Error(t, err)
+			require.Contains(t, err.Error(), c.err)
+		})
+	}
+}
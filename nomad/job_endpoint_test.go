@@ -177,6 +177,42 @@ func TestJobEndpoint_Register_InvalidNamespace(t *testing.T) {
 	}
 }
 
+func TestJobEndpoint_Register_NodePool(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Registering a job in a nonexistent node pool fails
+	job := mock.Job()
+	job.NodePool = "gpu"
+	req := &structs.JobRegisterRequest{
+		Job: job,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var resp structs.JobRegisterResponse
+	err := msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), `node pool "gpu" does not exist`)
+
+	// Create the node pool and try again
+	state := s1.fsm.State()
+	require.NoError(state.UpsertNodePools(1000, []*structs.NodePool{{Name: "gpu"}}))
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp))
+
+	out, err := state.JobByID(nil, job.Namespace, job.ID)
+	require.NoError(err)
+	require.NotNil(out)
+	require.Equal("gpu", out.NodePool)
+}
+
 func TestJobEndpoint_Register_InvalidDriverConfig(t *testing.T) {
 	t.Skip("Driver config validation is disabled")
 	t.Parallel()
@@ -459,6 +495,103 @@ func TestJobEndpoint_Register_ParameterizedJob(t *testing.T) {
 	}
 }
 
+func TestJobEndpoint_Register_Multiregion(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer s1.Shutdown()
+	s2 := TestServer(t, func(c *Config) {
+		c.Region = "west"
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer s2.Shutdown()
+	TestJoin(t, s1, s2)
+	testutil.WaitForLeader(t, s1.RPC)
+	testutil.WaitForLeader(t, s2.RPC)
+	codec := rpcClient(t, s1)
+
+	// Create the register request for a multiregion job
+	job := mock.Job()
+	job.Multiregion = &structs.Multiregion{
+		Regions: []*structs.MultiregionRegion{
+			{Name: "global"},
+			{Name: "west", Count: 2, Datacenters: []string{"west-1"}},
+		},
+	}
+	req := &structs.JobRegisterRequest{
+		Job: job,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+
+	// Fetch the response
+	var resp structs.JobRegisterResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp))
+	require.NotZero(resp.JobModifyIndex)
+
+	// Check for the job in both regions
+	out, err := s1.fsm.State().JobByID(nil, job.Namespace, job.ID)
+	require.NoError(err)
+	require.NotNil(out)
+	require.Equal("global", out.Region)
+	require.Equal(resp.JobModifyIndex, out.JobModifyIndex)
+	require.Equal(10, out.TaskGroups[0].Count)
+
+	out, err = s2.fsm.State().JobByID(nil, job.Namespace, job.ID)
+	require.NoError(err)
+	require.NotNil(out)
+	require.Equal("west", out.Region)
+	require.Equal([]string{"west-1"}, out.Datacenters)
+	require.Equal(2, out.TaskGroups[0].Count)
+
+	// A copy of the job that was not fanned out by its origin region is
+	// rejected
+	forged := *req
+	forged.Job = job.Copy()
+	forged.Job.Meta = map[string]string{"forged": "true"}
+	forged.Region = "west"
+	forged.MultiregionOrigin = &structs.MultiregionOrigin{Region: "global", Token: uuid.Generate()}
+	err = msgpackrpc.CallWithCodec(codec, "Job.Register", &forged, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), `not fanned out by region "global"`)
+	out, err = s2.fsm.State().JobByID(nil, job.Namespace, job.ID)
+	require.NoError(err)
+	require.NotContains(out.Meta, "forged")
+
+	// A job referencing an unknown region is rejected
+	job = mock.Job()
+	job.Multiregion = &structs.Multiregion{
+		Regions: []*structs.MultiregionRegion{{Name: "global"}, {Name: "east"}},
+	}
+	req.Job = job
+	err = msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), `unknown region "east"`)
+
+	// Deregistering the job removes it from both regions
+	dereg := &structs.JobDeregisterRequest{
+		JobID: out.ID,
+		Purge: true,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: out.Namespace,
+		},
+	}
+	var dresp structs.JobDeregisterResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Deregister", dereg, &dresp))
+
+	out, err = s1.fsm.State().JobByID(nil, job.Namespace, dereg.JobID)
+	require.NoError(err)
+	require.Nil(out)
+	out, err = s2.fsm.State().JobByID(nil, job.Namespace, dereg.JobID)
+	require.NoError(err)
+	require.Nil(out)
+}
+
 func TestJobEndpoint_Register_Dispatched(t *testing.T) {
 	t.Parallel()
 	require := require.New(t)
@@ -2893,6 +3026,56 @@ func TestJobEndpoint_ListJobs(t *testing.T) {
 	}
 }
 
+func TestJobEndpoint_ListJobs_Paginated(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1 := TestServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create the jobs
+	state := s1.fsm.State()
+	for i, id := range []string{"job-a", "job-b", "job-c"} {
+		job := mock.Job()
+		job.ID = id
+		if id == "job-b" {
+			job.Type = structs.JobTypeBatch
+		}
+		require.NoError(state.UpsertJob(uint64(1000+i), job))
+	}
+
+	// List the first page
+	get := &structs.JobListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: structs.DefaultNamespace,
+			PerPage:   1,
+		},
+	}
+	var resp structs.JobListResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.List", get, &resp))
+	require.Len(resp.Jobs, 1)
+	require.Equal("job-a", resp.Jobs[0].ID)
+	require.Equal("job-b", resp.NextToken)
+
+	// List the next page of the service jobs
+	get.NextToken = resp.NextToken
+	get.Filter = `Type == "service"`
+	var resp2 structs.JobListResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.List", get, &resp2))
+	require.Len(resp2.Jobs, 1)
+	require.Equal("job-c", resp2.Jobs[0].ID)
+	require.Empty(resp2.NextToken)
+
+	// Invalid filters are rejected
+	get.Filter = `Type =`
+	var resp3 structs.JobListResponse
+	err := msgpackrpc.CallWithCodec(codec, "Job.List", get, &resp3)
+	require.True(structs.IsErrInvalidFilter(err))
+}
+
 func TestJobEndpoint_ListJobs_WithACL(t *testing.T) {
 	require := require.New(t)
 	t.Parallel()
@@ -2953,6 +3136,67 @@ func TestJobEndpoint_ListJobs_WithACL(t *testing.T) {
 	require.Equal(job.ID, validResp.Jobs[0].ID)
 }
 
+func TestJobEndpoint_ListJobs_AllNamespaces(t *testing.T) {
+	require := require.New(t)
+	t.Parallel()
+
+	srv, root := TestACLServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer srv.Shutdown()
+	codec := rpcClient(t, srv)
+	testutil.WaitForLeader(t, srv.RPC)
+	state := srv.fsm.State()
+
+	// Create jobs in two namespaces
+	require.NoError(state.UpsertNamespaces(1000, []*structs.Namespace{{Name: "prod"}}))
+	job1 := mock.Job()
+	require.NoError(state.UpsertJob(1001, job1))
+	job2 := mock.Job()
+	job2.Namespace = "prod"
+	require.NoError(state.UpsertJob(1002, job2))
+
+	req := &structs.JobListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: structs.AllNamespacesSentinel,
+			AuthToken: root.SecretID,
+		},
+	}
+
+	// The management token lists the jobs of all the namespaces
+	var resp structs.JobListResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.List", req, &resp))
+	require.Len(resp.Jobs, 2)
+	require.Equal(structs.DefaultNamespace, resp.Jobs[0].Namespace)
+	require.Equal("prod", resp.Jobs[1].Namespace)
+
+	// The jobs of the namespaces the token can't list are filtered
+	token := mock.CreatePolicyAndToken(t, state, 1003, "test-prod",
+		mock.NamespacePolicy("prod", "", []string{acl.NamespaceCapabilityListJobs}))
+	req.AuthToken = token.SecretID
+	resp = structs.JobListResponse{}
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.List", req, &resp))
+	require.Len(resp.Jobs, 1)
+	require.Equal(job2.ID, resp.Jobs[0].ID)
+
+	// The jobs are paginated across the namespaces
+	req.AuthToken = root.SecretID
+	req.PerPage = 1
+	resp = structs.JobListResponse{}
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.List", req, &resp))
+	require.Len(resp.Jobs, 1)
+	require.Equal(job1.ID, resp.Jobs[0].ID)
+	require.Equal("prod,"+job2.ID, resp.NextToken)
+
+	req.NextToken = resp.NextToken
+	resp = structs.JobListResponse{}
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.List", req, &resp))
+	require.Len(resp.Jobs, 1)
+	require.Equal(job2.ID, resp.Jobs[0].ID)
+	require.Empty(resp.NextToken)
+}
+
 func TestJobEndpoint_ListJobs_Blocking(t *testing.T) {
 	t.Parallel()
 	s1 := TestServer(t, nil)
@@ -4146,6 +4390,154 @@ func TestJobEndpoint_Dispatch_ACL(t *testing.T) {
 	require.Equal(eval.CreateIndex, validResp2.EvalCreateIndex)
 }
 
+func TestJobEndpoint_Dispatch_IdempotencyToken(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	// Create a parameterized job
+	job := mock.BatchJob()
+	job.ParameterizedJob = &structs.ParameterizedJobConfig{}
+	require.Nil(state.UpsertJob(400, job))
+
+	req := &structs.JobDispatchRequest{
+		JobID:            job.ID,
+		IdempotencyToken: "foo",
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+
+	var resp structs.JobDispatchResponse
+	require.Nil(msgpackrpc.CallWithCodec(codec, "Job.Dispatch", req, &resp))
+	require.NotEqual("", resp.EvalID)
+
+	out, err := state.JobByID(nil, job.Namespace, resp.DispatchedJobID)
+	require.Nil(err)
+	require.NotNil(out)
+	require.Equal("foo", out.DispatchIdempotencyToken)
+
+	// Dispatching again with the same token returns the existing job
+	var resp2 structs.JobDispatchResponse
+	require.Nil(msgpackrpc.CallWithCodec(codec, "Job.Dispatch", req, &resp2))
+	require.Equal(resp.DispatchedJobID, resp2.DispatchedJobID)
+	require.Equal(resp.JobCreateIndex, resp2.JobCreateIndex)
+	require.Equal("", resp2.EvalID)
+
+	// Dispatching with another token dispatches a new job
+	req.IdempotencyToken = "bar"
+	var resp3 structs.JobDispatchResponse
+	require.Nil(msgpackrpc.CallWithCodec(codec, "Job.Dispatch", req, &resp3))
+	require.NotEqual(resp.DispatchedJobID, resp3.DispatchedJobID)
+
+	// Once the job is stopped its token can be reused
+	stopped := out.Copy()
+	stopped.Stop = true
+	require.Nil(state.UpsertJob(resp3.Index+1, stopped))
+
+	req.IdempotencyToken = "foo"
+	var resp4 structs.JobDispatchResponse
+	require.Nil(msgpackrpc.CallWithCodec(codec, "Job.Dispatch", req, &resp4))
+	require.NotEqual(resp.DispatchedJobID, resp4.DispatchedJobID)
+}
+
+func TestJobEndpoint_DispatchBatch(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	// Create a parameterized job
+	job := mock.BatchJob()
+	job.ParameterizedJob = &structs.ParameterizedJobConfig{
+		MetaOptional: []string{"foo"},
+	}
+	require.Nil(state.UpsertJob(400, job))
+
+	// Dispatch a job with a token outside of the batch
+	dispatchReq := &structs.JobDispatchRequest{
+		JobID:            job.ID,
+		IdempotencyToken: "existing",
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var dispatchResp structs.JobDispatchResponse
+	require.Nil(msgpackrpc.CallWithCodec(codec, "Job.Dispatch", dispatchReq, &dispatchResp))
+
+	req := &structs.JobBatchDispatchRequest{
+		JobID: job.ID,
+		Dispatches: []*structs.JobDispatchItem{
+			{Meta: map[string]string{"foo": "1"}},
+			{Meta: map[string]string{"foo": "2"}, IdempotencyToken: "a"},
+			{Meta: map[string]string{"foo": "3"}, IdempotencyToken: "a"},
+			{IdempotencyToken: "existing"},
+		},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var resp structs.JobBatchDispatchResponse
+	require.Nil(msgpackrpc.CallWithCodec(codec, "Job.DispatchBatch", req, &resp))
+	require.Len(resp.Dispatches, 4)
+
+	// The new jobs and their evaluations are committed at the same index
+	for _, d := range resp.Dispatches[:2] {
+		require.Equal(resp.Index, d.JobCreateIndex)
+		require.Equal(resp.Index, d.EvalCreateIndex)
+
+		out, err := state.JobByID(nil, job.Namespace, d.DispatchedJobID)
+		require.Nil(err)
+		require.NotNil(out)
+		require.Equal(job.ID, out.ParentID)
+
+		eval, err := state.EvalByID(nil, d.EvalID)
+		require.Nil(err)
+		require.NotNil(eval)
+		require.Equal(out.ID, eval.JobID)
+		require.Equal(resp.Index, eval.JobModifyIndex)
+	}
+	require.NotEqual(resp.Dispatches[0].DispatchedJobID, resp.Dispatches[1].DispatchedJobID)
+
+	// Duplicate tokens reply with the job dispatched with the token
+	require.Equal(resp.Dispatches[1].DispatchedJobID, resp.Dispatches[2].DispatchedJobID)
+	require.Equal(dispatchResp.DispatchedJobID, resp.Dispatches[3].DispatchedJobID)
+
+	out, err := state.JobByID(nil, job.Namespace, resp.Dispatches[1].DispatchedJobID)
+	require.Nil(err)
+	require.Equal("2", out.Meta["foo"])
+
+	// Invalid dispatches fail the whole batch
+	req.Dispatches = []*structs.JobDispatchItem{
+		{IdempotencyToken: "b"},
+		{Meta: map[string]string{"bar": "1"}},
+	}
+	var resp2 structs.JobBatchDispatchResponse
+	err = msgpackrpc.CallWithCodec(codec, "Job.DispatchBatch", req, &resp2)
+	require.NotNil(err)
+	require.Contains(err.Error(), "dispatch 1")
+
+	existing, err := state.DispatchedJobByIdempotencyToken(nil, job.Namespace, job.ID, "b")
+	require.Nil(err)
+	require.Nil(existing)
+}
+
 func TestJobEndpoint_Dispatch(t *testing.T) {
 	t.Parallel()
 
@@ -4421,3 +4813,164 @@ func TestJobEndpoint_Dispatch(t *testing.T) {
 		})
 	}
 }
+
+func TestJobEndpoint_Scale(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	job := mock.Job()
+	require.NoError(state.UpsertJob(1000, job))
+	group := job.TaskGroups[0].Name
+
+	scale := &structs.JobScaleRequest{
+		JobID:   job.ID,
+		Target:  map[string]string{structs.ScalingTargetGroup: group},
+		Count:   helper.Int64ToPtr(5),
+		Message: "scaling up",
+		Meta:    map[string]interface{}{"source": "test"},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var resp structs.JobRegisterResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Scale", scale, &resp))
+	require.NotEmpty(resp.EvalID)
+	require.NotZero(resp.Index)
+
+	// The job is registered with the new count and evaluated
+	out, err := state.JobByID(nil, job.Namespace, job.ID)
+	require.NoError(err)
+	require.Equal(5, out.TaskGroups[0].Count)
+	require.Equal(resp.JobModifyIndex, out.JobModifyIndex)
+
+	eval, err := state.EvalByID(nil, resp.EvalID)
+	require.NoError(err)
+	require.Equal(structs.EvalTriggerScaling, eval.TriggeredBy)
+	require.Equal(resp.JobModifyIndex, eval.JobModifyIndex)
+
+	// Record an error without a count
+	scale.Count = nil
+	scale.Error = true
+	scale.Message = "failed to scale"
+	var resp2 structs.JobRegisterResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Scale", scale, &resp2))
+	require.Empty(resp2.EvalID)
+
+	// Both events are in the scaling status, the most recent first
+	get := &structs.JobScaleStatusRequest{
+		JobID: job.ID,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var status structs.JobScaleStatusResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.ScaleStatus", get, &status))
+	require.NotNil(status.JobScaleStatus)
+	tgStatus := status.JobScaleStatus.TaskGroups[group]
+	require.Equal(5, tgStatus.Desired)
+	require.Len(tgStatus.Events, 2)
+	require.True(tgStatus.Events[0].Error)
+	require.Nil(tgStatus.Events[0].Count)
+	require.EqualValues(5, *tgStatus.Events[1].Count)
+	require.EqualValues(job.TaskGroups[0].Count, tgStatus.Events[1].PreviousCount)
+	require.Equal(resp.EvalID, *tgStatus.Events[1].EvalID)
+	require.Equal("test", tgStatus.Events[1].Meta["source"])
+
+	// Invalid requests are rejected
+	scale.Target[structs.ScalingTargetGroup] = "missing"
+	err = msgpackrpc.CallWithCodec(codec, "Job.Scale", scale, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "not found")
+
+	scale.Target[structs.ScalingTargetGroup] = group
+	scale.Count = helper.Int64ToPtr(-1)
+	scale.Error = false
+	err = msgpackrpc.CallWithCodec(codec, "Job.Scale", scale, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "can't be negative")
+
+	// System jobs can't be scaled
+	sysJob := mock.SystemJob()
+	require.NoError(state.UpsertJob(1001, sysJob))
+	scale.JobID = sysJob.ID
+	scale.Target[structs.ScalingTargetGroup] = sysJob.TaskGroups[0].Name
+	scale.Count = helper.Int64ToPtr(2)
+	err = msgpackrpc.CallWithCodec(codec, "Job.Scale", scale, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "cannot scale")
+}
+
+func TestJobEndpoint_Scale_ACL(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1, root := TestACLServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	job := mock.Job()
+	require.NoError(state.UpsertJob(1000, job))
+
+	scale := &structs.JobScaleRequest{
+		JobID:  job.ID,
+		Target: map[string]string{structs.ScalingTargetGroup: job.TaskGroups[0].Name},
+		Count:  helper.Int64ToPtr(3),
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var resp structs.JobRegisterResponse
+
+	// Try without a token
+	err := msgpackrpc.CallWithCodec(codec, "Job.Scale", scale, &resp)
+	require.EqualError(err, structs.ErrPermissionDenied.Error())
+
+	// Try with a token only allowed to read jobs
+	readToken := mock.CreatePolicyAndToken(t, state, 1001, "test-invalid",
+		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityReadJob}))
+	scale.AuthToken = readToken.SecretID
+	err = msgpackrpc.CallWithCodec(codec, "Job.Scale", scale, &resp)
+	require.EqualError(err, structs.ErrPermissionDenied.Error())
+
+	// Try with a token allowed to scale jobs
+	scaleToken := mock.CreatePolicyAndToken(t, state, 1003, "test-valid",
+		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityScaleJob}))
+	scale.AuthToken = scaleToken.SecretID
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Scale", scale, &resp))
+
+	// The accessor of the token is recorded in the scaling event
+	events, _, err := state.ScalingEventsByJob(nil, job.Namespace, job.ID)
+	require.NoError(err)
+	require.Len(events[job.TaskGroups[0].Name], 1)
+	require.Equal(scaleToken.AccessorID, events[job.TaskGroups[0].Name][0].AccessorID)
+
+	// Reading the scaling status requires read-job
+	get := &structs.JobScaleStatusRequest{
+		JobID: job.ID,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: job.Namespace,
+			AuthToken: scaleToken.SecretID,
+		},
+	}
+	var status structs.JobScaleStatusResponse
+	err = msgpackrpc.CallWithCodec(codec, "Job.ScaleStatus", get, &status)
+	require.EqualError(err, structs.ErrPermissionDenied.Error())
+
+	get.AuthToken = root.SecretID
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.ScaleStatus", get, &status))
+	require.Equal(3, status.JobScaleStatus.TaskGroups[job.TaskGroups[0].Name].Desired)
+}
@@ -0,0 +1,195 @@
+package nomad
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	log "github.com/hashicorp/go-hclog"
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// Keyring endpoint is used for managing the root keys variables are
+// encrypted with
+type Keyring struct {
+	srv    *Server
+	logger log.Logger
+}
+
+// List is used to list the metadata of the root keys
+func (k *Keyring) List(args *structs.GenericRequest, reply *structs.KeyringListRootKeyMetaResponse) error {
+	if done, err := k.srv.forward("Keyring.List", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "keyring", "list"}, time.Now())
+
+	// Check operator read permissions
+	if aclObj, err := k.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowOperatorRead() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			iter, err := state.RootKeys(ws)
+			if err != nil {
+				return err
+			}
+
+			keys := []*structs.RootKeyMeta{}
+			for raw := iter.Next(); raw != nil; raw = iter.Next() {
+				keys = append(keys, raw.(*structs.RootKey).Meta())
+			}
+			reply.Keys = keys
+
+			// Use the last index that affected the root keys table
+			index, err := state.Index("root_keys")
+			if err != nil {
+				return err
+			}
+			if index == 0 {
+				index = 1
+			}
+			reply.Index = index
+			return nil
+		}}
+	return k.srv.blockingRPC(&opts)
+}
+
+// Rotate is used to create a new active root key. A full rotation also
+// rewraps the data keys of all the variables with the new root key.
+func (k *Keyring) Rotate(args *structs.KeyringRotateRootKeyRequest, reply *structs.KeyringRotateRootKeyResponse) error {
+	if done, err := k.srv.forward("Keyring.Rotate", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "keyring", "rotate"}, time.Now())
+
+	// Check operator write permissions
+	if aclObj, err := k.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowOperatorWrite() {
+		return structs.ErrPermissionDenied
+	}
+
+	key, err := k.srv.encrypter.newRootKey()
+	if err != nil {
+		return err
+	}
+	req := structs.RootKeyUpsertRequest{RootKey: key, WriteRequest: args.WriteRequest}
+	fsmErr, index, err := k.srv.raftApply(structs.RootKeyUpsertRequestType, req)
+	if err, ok := fsmErr.(error); ok && err != nil {
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	k.logger.Info("rotated root key", "key_id", key.KeyID)
+
+	if args.Full {
+		rewrapped, rewrapIndex, err := k.rewrapVariables(key)
+		if err != nil {
+			return fmt.Errorf("failed to rewrap variables: %v", err)
+		}
+		reply.Rewrapped = rewrapped
+		if rewrapIndex > index {
+			index = rewrapIndex
+		}
+	}
+
+	stored, err := k.srv.fsm.State().RootKeyByID(nil, key.KeyID)
+	if err != nil {
+		return err
+	}
+	if stored == nil {
+		return fmt.Errorf("root key %q not found after rotation", key.KeyID)
+	}
+	reply.Key = stored.Meta()
+	reply.Index = index
+	return nil
+}
+
+// rewrapVariables rewraps the data keys of the variables encrypted with other
+// root keys with the given root key. It returns the number of rewrapped
+// variables and the index of the last update.
+func (k *Keyring) rewrapVariables(key *structs.RootKey) (int, uint64, error) {
+	store := k.srv.fsm.State()
+	iter, err := store.Variables(nil)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var rewrapped int
+	var index uint64
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		variable := raw.(*structs.VariableEncrypted)
+		if variable.KeyID == key.KeyID {
+			continue
+		}
+
+		out, err := k.srv.encrypter.Rewrap(store, variable, key)
+		if err != nil {
+			return rewrapped, index, err
+		}
+
+		// A variable written meanwhile is already encrypted with the new
+		// active root key, so a check-and-set conflict is ignored
+		checkIndex := variable.ModifyIndex
+		req := structs.VariablesApplyRequest{
+			Data:       out,
+			CheckIndex: &checkIndex,
+		}
+		fsmErr, applyIndex, err := k.srv.raftApply(structs.VariablesApplyRequestType, req)
+		if err != nil {
+			return rewrapped, index, err
+		}
+		if err, ok := fsmErr.(error); ok && err != nil {
+			if strings.HasPrefix(err.Error(), structs.ErrCASConflictPrefix) {
+				continue
+			}
+			return rewrapped, index, err
+		}
+		rewrapped++
+		index = applyIndex
+	}
+	return rewrapped, index, nil
+}
+
+// Delete is used to remove an inactive root key no variable is encrypted
+// with anymore
+func (k *Keyring) Delete(args *structs.KeyringDeleteRootKeyRequest, reply *structs.GenericResponse) error {
+	if done, err := k.srv.forward("Keyring.Delete", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "keyring", "delete"}, time.Now())
+
+	// Check operator write permissions
+	if aclObj, err := k.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowOperatorWrite() {
+		return structs.ErrPermissionDenied
+	}
+
+	if args.KeyID == "" {
+		return fmt.Errorf("missing root key ID")
+	}
+
+	// Update via Raft
+	fsmErr, index, err := k.srv.raftApply(structs.RootKeyDeleteRequestType, args)
+	if err, ok := fsmErr.(error); ok && err != nil {
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	// Update the index
+	reply.Index = index
+	return nil
+}
@@ -0,0 +1,118 @@
+package nomad
+
+import (
+	"testing"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyringEndpoint_RotateDelete(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create a variable with the initial root key
+	upsert := &structs.VariablesUpsertRequest{
+		Data: &structs.VariableDecrypted{
+			VariableMetadata: structs.VariableMetadata{Path: "project/web"},
+			Items:            map[string]string{"password": "hunter2"},
+		},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var upsertResp structs.VariablesUpsertResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Variables.Upsert", upsert, &upsertResp))
+
+	list := &structs.GenericRequest{QueryOptions: structs.QueryOptions{Region: "global"}}
+	var listResp structs.KeyringListRootKeyMetaResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Keyring.List", list, &listResp))
+	require.Len(listResp.Keys, 1)
+	oldKey := listResp.Keys[0]
+	require.True(oldKey.Active)
+
+	// A rotation without rewrapping keeps the variable on the old key
+	rotate := &structs.KeyringRotateRootKeyRequest{WriteRequest: structs.WriteRequest{Region: "global"}}
+	var rotateResp structs.KeyringRotateRootKeyResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Keyring.Rotate", rotate, &rotateResp))
+	require.True(rotateResp.Key.Active)
+	require.Zero(rotateResp.Rewrapped)
+
+	del := &structs.KeyringDeleteRootKeyRequest{
+		KeyID:        oldKey.KeyID,
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var delResp structs.GenericResponse
+	err := msgpackrpc.CallWithCodec(codec, "Keyring.Delete", del, &delResp)
+	require.Error(err)
+	require.Contains(err.Error(), "in use")
+
+	// A full rotation rewraps the variable, so the old keys can be removed
+	rotate.Full = true
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Keyring.Rotate", rotate, &rotateResp))
+	require.Equal(1, rotateResp.Rewrapped)
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Keyring.Delete", del, &delResp))
+	require.NotZero(delResp.Index)
+
+	read := &structs.VariablesReadRequest{
+		Path:         "project/web",
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var readResp structs.VariablesReadResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Variables.Read", read, &readResp))
+	require.Equal("hunter2", readResp.Data.Items["password"])
+
+	stored, err := s1.fsm.State().VariableByPath(nil, structs.DefaultNamespace, "project/web")
+	require.NoError(err)
+	require.Equal(rotateResp.Key.KeyID, stored.KeyID)
+	require.Equal(upsertResp.Data.ModifyTime, stored.ModifyTime)
+
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Keyring.List", list, &listResp))
+	require.Len(listResp.Keys, 2)
+}
+
+func TestKeyringEndpoint_ACL(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1, root := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	state := s1.fsm.State()
+	readToken := mock.CreatePolicyAndToken(t, state, 1001, "operator-read", `operator { policy = "read" }`)
+
+	// Listing requires operator read
+	list := &structs.GenericRequest{QueryOptions: structs.QueryOptions{Region: "global"}}
+	var listResp structs.KeyringListRootKeyMetaResponse
+	err := msgpackrpc.CallWithCodec(codec, "Keyring.List", list, &listResp)
+	require.EqualError(err, structs.ErrPermissionDenied.Error())
+
+	list.AuthToken = readToken.SecretID
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Keyring.List", list, &listResp))
+	require.Len(listResp.Keys, 1)
+
+	// Rotating requires operator write
+	rotate := &structs.KeyringRotateRootKeyRequest{
+		WriteRequest: structs.WriteRequest{Region: "global", AuthToken: readToken.SecretID},
+	}
+	var rotateResp structs.KeyringRotateRootKeyResponse
+	err = msgpackrpc.CallWithCodec(codec, "Keyring.Rotate", rotate, &rotateResp)
+	require.EqualError(err, structs.ErrPermissionDenied.Error())
+
+	rotate.AuthToken = root.SecretID
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Keyring.Rotate", rotate, &rotateResp))
+
+	writeToken := mock.CreatePolicyAndToken(t, state, 1002, "operator-write", `operator { policy = "write" }`)
+	del := &structs.KeyringDeleteRootKeyRequest{
+		KeyID:        listResp.Keys[0].KeyID,
+		WriteRequest: structs.WriteRequest{Region: "global", AuthToken: writeToken.SecretID},
+	}
+	var delResp structs.GenericResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Keyring.Delete", del, &delResp))
+}
@@ -42,10 +42,14 @@ const (
 
 var minAutopilotVersion = version.Must(version.NewVersion("0.8.0"))
 
-// Default configuration for scheduler with preemption enabled for system jobs
+// Default configuration for scheduler with bin packing, and preemption
+// enabled for system jobs and disabled for service and batch jobs
 var defaultSchedulerConfig = &structs.SchedulerConfiguration{
+	SchedulerAlgorithm: structs.SchedulerAlgorithmBinpack,
 	PreemptionConfig: structs.PreemptionConfig{
-		SystemSchedulerEnabled: true,
+		SystemSchedulerEnabled:  true,
+		ServiceSchedulerEnabled: false,
+		BatchSchedulerEnabled:   false,
 	},
 }
 
@@ -86,6 +90,28 @@ func (s *Server) monitorLeadership() {
 				s.logger.Info("cluster leadership lost")
 			}
 
+		case errCh := <-s.reassertLeaderCh:
+			// There is nothing to reassert if the leader loop isn't
+			// running, for example if establishing the leadership failed.
+			if weAreLeaderCh == nil {
+				errCh <- fmt.Errorf("leadership has not been established")
+				continue
+			}
+
+			// Restart the leader loop, revoking the leadership and then
+			// establishing it again from the current state.
+			s.logger.Info("reasserting cluster leadership")
+			close(weAreLeaderCh)
+			leaderLoop.Wait()
+
+			weAreLeaderCh = make(chan struct{})
+			leaderLoop.Add(1)
+			go func(ch chan struct{}) {
+				defer leaderLoop.Done()
+				s.leaderLoop(ch)
+			}(weAreLeaderCh)
+			errCh <- nil
+
 		case <-s.shutdownCh:
 			return
 		}
@@ -182,6 +208,8 @@ func (s *Server) establishLeadership(stopCh chan struct{}) error {
 
 	// Disable workers to free half the cores for use in the plan queue and
 	// evaluation broker
+	s.workerLock.Lock()
+	s.workersPaused = true
 	if numWorkers := len(s.workers); numWorkers > 1 {
 		// Disabling 3/4 of the workers frees CPU for raft and the
 		// plan applier which uses 1/2 the cores.
@@ -189,6 +217,7 @@ func (s *Server) establishLeadership(stopCh chan struct{}) error {
 			s.workers[i].SetPause(true)
 		}
 	}
+	s.workerLock.Unlock()
 
 	// Initialize and start the autopilot routine
 	s.getOrCreateAutopilotConfig()
@@ -197,6 +226,11 @@ func (s *Server) establishLeadership(stopCh chan struct{}) error {
 	// Initialize scheduler configuration
 	s.getOrCreateSchedulerConfig()
 
+	// Create the root key variables are encrypted with
+	if err := s.initializeRootKey(); err != nil {
+		return err
+	}
+
 	// Enable the plan queue, since we are now the leader
 	s.planQueue.SetEnabled(true)
 
@@ -250,6 +284,9 @@ func (s *Server) establishLeadership(stopCh chan struct{}) error {
 	// Periodically publish job summary metrics
 	go s.publishJobSummaryMetrics(stopCh)
 
+	// Periodically save snapshots if the snapshot agent is enabled
+	go s.saveSnapshots(stopCh)
+
 	// Setup the heartbeat timers. This is done both when starting up or when
 	// a leader fail over happens. Since the timers are maintained by the leader
 	// node, effectively this means all the timers are renewed at the time of failover.
@@ -278,6 +315,9 @@ func (s *Server) establishLeadership(stopCh chan struct{}) error {
 	if s.config.ACLEnabled && s.config.Region != s.config.AuthoritativeRegion {
 		go s.replicateACLPolicies(stopCh)
 		go s.replicateACLTokens(stopCh)
+		go s.replicateACLRoles(stopCh)
+		go s.replicateACLAuthMethods(stopCh)
+		go s.replicateACLBindingRules(stopCh)
 	}
 
 	// Setup any enterprise systems required.
@@ -447,6 +487,8 @@ func (s *Server) schedulePeriodic(stopCh chan struct{}) {
 	defer jobGC.Stop()
 	deploymentGC := time.NewTicker(s.config.DeploymentGCInterval)
 	defer deploymentGC.Stop()
+	aclTokenGC := time.NewTicker(s.config.ACLTokenExpirationGCInterval)
+	defer aclTokenGC.Stop()
 
 	// getLatest grabs the latest index from the state store. It returns true if
 	// the index was retrieved successfully.
@@ -479,6 +521,18 @@ func (s *Server) schedulePeriodic(stopCh chan struct{}) {
 			if index, ok := getLatest(); ok {
 				s.evalBroker.Enqueue(s.coreJobEval(structs.CoreJobDeploymentGC, index))
 			}
+		case <-aclTokenGC.C:
+			if !s.config.ACLEnabled {
+				continue
+			}
+			if index, ok := getLatest(); ok {
+				s.evalBroker.Enqueue(s.coreJobEval(structs.CoreJobLocalTokenExpiredGC, index))
+
+				// Global tokens are only collected in the authoritative region
+				if s.config.Region == s.config.AuthoritativeRegion {
+					s.evalBroker.Enqueue(s.coreJobEval(structs.CoreJobGlobalTokenExpiredGC, index))
+				}
+			}
 		case <-stopCh:
 			return
 		}
@@ -568,13 +622,20 @@ func (s *Server) reapDupBlockedEvaluations(stopCh chan struct{}) {
 				cancel[i] = newEval
 			}
 
-			// Update via Raft
-			req := structs.EvalUpdateRequest{
-				Evals: cancel,
-			}
-			if _, _, err := s.raftApply(structs.EvalUpdateRequestType, &req); err != nil {
-				s.logger.Error("failed to update duplicate evals", "evals", log.Fmt("%#v", cancel), "error", err)
-				continue
+			// Update via Raft, bounding the size of the log entries
+			for len(cancel) != 0 {
+				batch := cancel
+				if len(batch) > maxDuplicatesPerCancel {
+					batch = batch[:maxDuplicatesPerCancel]
+				}
+				cancel = cancel[len(batch):]
+
+				req := structs.EvalUpdateRequest{
+					Evals: batch,
+				}
+				if _, _, err := s.raftApply(structs.EvalUpdateRequestType, &req); err != nil {
+					s.logger.Error("failed to update duplicate evals", "evals", log.Fmt("%#v", batch), "error", err)
+				}
 			}
 		}
 	}
@@ -745,11 +806,14 @@ func (s *Server) revokeLeadership() error {
 	}
 
 	// Unpause our worker if we paused previously
+	s.workerLock.Lock()
+	s.workersPaused = false
 	if len(s.workers) > 1 {
 		for i := 0; i < len(s.workers)/2; i++ {
 			s.workers[i].SetPause(false)
 		}
 	}
+	s.workerLock.Unlock()
 	return nil
 }
 
@@ -1228,6 +1292,422 @@ func diffACLTokens(state *state.StateStore, minIndex uint64, remoteList []*struc
 	return
 }
 
+// replicateACLRoles is used to replicate ACL roles from
+// the authoritative region to this region.
+func (s *Server) replicateACLRoles(stopCh chan struct{}) {
+	req := structs.ACLRolesListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:     s.config.AuthoritativeRegion,
+			AllowStale: true,
+		},
+	}
+	limiter := rate.NewLimiter(replicationRateLimit, int(replicationRateLimit))
+	s.logger.Debug("starting ACL role replication from authoritative region", "authoritative_region", req.Region)
+
+START:
+	for {
+		select {
+		case <-stopCh:
+			return
+		default:
+			// Rate limit how often we attempt replication
+			limiter.Wait(context.Background())
+
+			// Fetch the list of roles
+			var resp structs.ACLRolesListResponse
+			req.AuthToken = s.ReplicationToken()
+			err := s.forwardRegion(s.config.AuthoritativeRegion,
+				"ACL.ListRoles", &req, &resp)
+			if err != nil {
+				s.logger.Error("failed to fetch roles from authoritative region", "error", err)
+				goto ERR_WAIT
+			}
+
+			// Perform a two-way diff
+			delete, update := diffACLRoles(s.State(), req.MinQueryIndex, resp.Roles)
+
+			// Delete roles that should not exist
+			if len(delete) > 0 {
+				args := &structs.ACLRolesDeleteRequest{
+					Names: delete,
+				}
+				_, _, err := s.raftApply(structs.ACLRolesDeleteRequestType, args)
+				if err != nil {
+					s.logger.Error("failed to delete roles", "error", err)
+					goto ERR_WAIT
+				}
+			}
+
+			// Fetch any outdated roles
+			var fetched []*structs.ACLRole
+			if len(update) > 0 {
+				req := structs.ACLRolesRequest{
+					Names: update,
+					QueryOptions: structs.QueryOptions{
+						Region:        s.config.AuthoritativeRegion,
+						AuthToken:     s.ReplicationToken(),
+						AllowStale:    true,
+						MinQueryIndex: resp.Index - 1,
+					},
+				}
+				var reply structs.ACLRolesResponse
+				if err := s.forwardRegion(s.config.AuthoritativeRegion,
+					"ACL.GetRoles", &req, &reply); err != nil {
+					s.logger.Error("failed to fetch roles from authoritative region", "error", err)
+					goto ERR_WAIT
+				}
+				for _, role := range reply.Roles {
+					fetched = append(fetched, role)
+				}
+			}
+
+			// Update local roles
+			if len(fetched) > 0 {
+				args := &structs.ACLRolesUpsertRequest{
+					Roles: fetched,
+				}
+				_, _, err := s.raftApply(structs.ACLRolesUpsertRequestType, args)
+				if err != nil {
+					s.logger.Error("failed to update roles", "error", err)
+					goto ERR_WAIT
+				}
+			}
+
+			// Update the minimum query index, blocks until there
+			// is a change.
+			req.MinQueryIndex = resp.Index
+		}
+	}
+
+ERR_WAIT:
+	select {
+	case <-time.After(s.config.ReplicationBackoff):
+		goto START
+	case <-stopCh:
+		return
+	}
+}
+
+// diffACLRoles is used to perform a two-way diff between the local
+// roles and the remote roles to determine which roles
+// need to be deleted or updated.
+func diffACLRoles(state *state.StateStore, minIndex uint64, remoteList []*structs.ACLRoleListStub) (delete []string, update []string) {
+	// Construct a set of the local and remote roles
+	local := make(map[string][]byte)
+	remote := make(map[string]struct{})
+
+	// Add all the local roles
+	iter, err := state.ACLRoles(nil)
+	if err != nil {
+		panic("failed to iterate local roles")
+	}
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		role := raw.(*structs.ACLRole)
+		local[role.Name] = role.Hash
+	}
+
+	// Iterate over the remote roles
+	for _, rm := range remoteList {
+		remote[rm.Name] = struct{}{}
+
+		// Check if the role is missing locally
+		if localHash, ok := local[rm.Name]; !ok {
+			update = append(update, rm.Name)
+
+			// Check if the role is newer remotely and there is a hash mis-match.
+		} else if rm.ModifyIndex > minIndex && !bytes.Equal(localHash, rm.Hash) {
+			update = append(update, rm.Name)
+		}
+	}
+
+	// Check if the local role should be deleted
+	for lm := range local {
+		if _, ok := remote[lm]; !ok {
+			delete = append(delete, lm)
+		}
+	}
+	return
+}
+
+// replicateACLAuthMethods is used to replicate ACL auth methods from
+// the authoritative region to this region.
+func (s *Server) replicateACLAuthMethods(stopCh chan struct{}) {
+	req := structs.ACLAuthMethodListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:     s.config.AuthoritativeRegion,
+			AllowStale: true,
+		},
+	}
+	limiter := rate.NewLimiter(replicationRateLimit, int(replicationRateLimit))
+	s.logger.Debug("starting ACL auth method replication from authoritative region", "authoritative_region", req.Region)
+
+START:
+	for {
+		select {
+		case <-stopCh:
+			return
+		default:
+			// Rate limit how often we attempt replication
+			limiter.Wait(context.Background())
+
+			// Fetch the list of auth methods
+			var resp structs.ACLAuthMethodListResponse
+			req.AuthToken = s.ReplicationToken()
+			err := s.forwardRegion(s.config.AuthoritativeRegion,
+				"ACL.ListAuthMethods", &req, &resp)
+			if err != nil {
+				s.logger.Error("failed to fetch auth methods from authoritative region", "error", err)
+				goto ERR_WAIT
+			}
+
+			// Perform a two-way diff
+			delete, update := diffACLAuthMethods(s.State(), req.MinQueryIndex, resp.AuthMethods)
+
+			// Delete auth methods that should not exist
+			if len(delete) > 0 {
+				args := &structs.ACLAuthMethodDeleteRequest{
+					Names: delete,
+				}
+				_, _, err := s.raftApply(structs.ACLAuthMethodsDeleteRequestType, args)
+				if err != nil {
+					s.logger.Error("failed to delete auth methods", "error", err)
+					goto ERR_WAIT
+				}
+			}
+
+			// Fetch any outdated auth methods
+			var fetched []*structs.ACLAuthMethod
+			if len(update) > 0 {
+				req := structs.ACLAuthMethodsGetRequest{
+					Names: update,
+					QueryOptions: structs.QueryOptions{
+						Region:        s.config.AuthoritativeRegion,
+						AuthToken:     s.ReplicationToken(),
+						AllowStale:    true,
+						MinQueryIndex: resp.Index - 1,
+					},
+				}
+				var reply structs.ACLAuthMethodsGetResponse
+				if err := s.forwardRegion(s.config.AuthoritativeRegion,
+					"ACL.GetAuthMethods", &req, &reply); err != nil {
+					s.logger.Error("failed to fetch auth methods from authoritative region", "error", err)
+					goto ERR_WAIT
+				}
+				for _, method := range reply.AuthMethods {
+					fetched = append(fetched, method)
+				}
+			}
+
+			// Update local auth methods
+			if len(fetched) > 0 {
+				args := &structs.ACLAuthMethodUpsertRequest{
+					AuthMethods: fetched,
+				}
+				_, _, err := s.raftApply(structs.ACLAuthMethodsUpsertRequestType, args)
+				if err != nil {
+					s.logger.Error("failed to update auth methods", "error", err)
+					goto ERR_WAIT
+				}
+			}
+
+			// Update the minimum query index, blocks until there
+			// is a change.
+			req.MinQueryIndex = resp.Index
+		}
+	}
+
+ERR_WAIT:
+	select {
+	case <-time.After(s.config.ReplicationBackoff):
+		goto START
+	case <-stopCh:
+		return
+	}
+}
+
+// diffACLAuthMethods is used to perform a two-way diff between the local
+// auth methods and the remote auth methods to determine which auth methods
+// need to be deleted or updated.
+func diffACLAuthMethods(state *state.StateStore, minIndex uint64, remoteList []*structs.ACLAuthMethodListStub) (delete []string, update []string) {
+	// Construct a set of the local and remote auth methods
+	local := make(map[string][]byte)
+	remote := make(map[string]struct{})
+
+	// Add all the local auth methods
+	iter, err := state.ACLAuthMethods(nil)
+	if err != nil {
+		panic("failed to iterate local auth methods")
+	}
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		method := raw.(*structs.ACLAuthMethod)
+		local[method.Name] = method.Hash
+	}
+
+	// Iterate over the remote auth methods
+	for _, rm := range remoteList {
+		remote[rm.Name] = struct{}{}
+
+		// Check if the auth method is missing locally
+		if localHash, ok := local[rm.Name]; !ok {
+			update = append(update, rm.Name)
+
+			// Check if the auth method is newer remotely and there is a hash mis-match.
+		} else if rm.ModifyIndex > minIndex && !bytes.Equal(localHash, rm.Hash) {
+			update = append(update, rm.Name)
+		}
+	}
+
+	// Check if the local auth method should be deleted
+	for lm := range local {
+		if _, ok := remote[lm]; !ok {
+			delete = append(delete, lm)
+		}
+	}
+	return
+}
+
+// replicateACLBindingRules is used to replicate ACL binding rules from
+// the authoritative region to this region.
+func (s *Server) replicateACLBindingRules(stopCh chan struct{}) {
+	req := structs.ACLBindingRulesListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:     s.config.AuthoritativeRegion,
+			AllowStale: true,
+		},
+	}
+	limiter := rate.NewLimiter(replicationRateLimit, int(replicationRateLimit))
+	s.logger.Debug("starting ACL binding rule replication from authoritative region", "authoritative_region", req.Region)
+
+START:
+	for {
+		select {
+		case <-stopCh:
+			return
+		default:
+			// Rate limit how often we attempt replication
+			limiter.Wait(context.Background())
+
+			// Fetch the list of binding rules
+			var resp structs.ACLBindingRulesListResponse
+			req.AuthToken = s.ReplicationToken()
+			err := s.forwardRegion(s.config.AuthoritativeRegion,
+				"ACL.ListBindingRules", &req, &resp)
+			if err != nil {
+				s.logger.Error("failed to fetch binding rules from authoritative region", "error", err)
+				goto ERR_WAIT
+			}
+
+			// Perform a two-way diff
+			delete, update := diffACLBindingRules(s.State(), req.MinQueryIndex, resp.ACLBindingRules)
+
+			// Delete binding rules that should not exist
+			if len(delete) > 0 {
+				args := &structs.ACLBindingRulesDeleteRequest{
+					ACLBindingRuleIDs: delete,
+				}
+				_, _, err := s.raftApply(structs.ACLBindingRulesDeleteRequestType, args)
+				if err != nil {
+					s.logger.Error("failed to delete binding rules", "error", err)
+					goto ERR_WAIT
+				}
+			}
+
+			// Fetch any outdated binding rules
+			var fetched []*structs.ACLBindingRule
+			if len(update) > 0 {
+				req := structs.ACLBindingRulesRequest{
+					ACLBindingRuleIDs: update,
+					QueryOptions: structs.QueryOptions{
+						Region:        s.config.AuthoritativeRegion,
+						AuthToken:     s.ReplicationToken(),
+						AllowStale:    true,
+						MinQueryIndex: resp.Index - 1,
+					},
+				}
+				var reply structs.ACLBindingRulesResponse
+				if err := s.forwardRegion(s.config.AuthoritativeRegion,
+					"ACL.GetBindingRules", &req, &reply); err != nil {
+					s.logger.Error("failed to fetch binding rules from authoritative region", "error", err)
+					goto ERR_WAIT
+				}
+				for _, rule := range reply.ACLBindingRules {
+					fetched = append(fetched, rule)
+				}
+			}
+
+			// Update local binding rules. This fails until the auth methods
+			// of the rules are replicated.
+			if len(fetched) > 0 {
+				args := &structs.ACLBindingRulesUpsertRequest{
+					ACLBindingRules: fetched,
+				}
+				fsmErr, _, err := s.raftApply(structs.ACLBindingRulesUpsertRequestType, args)
+				if err, ok := fsmErr.(error); ok && err != nil {
+					s.logger.Error("failed to update binding rules", "error", err)
+					goto ERR_WAIT
+				}
+				if err != nil {
+					s.logger.Error("failed to update binding rules", "error", err)
+					goto ERR_WAIT
+				}
+			}
+
+			// Update the minimum query index, blocks until there
+			// is a change.
+			req.MinQueryIndex = resp.Index
+		}
+	}
+
+ERR_WAIT:
+	select {
+	case <-time.After(s.config.ReplicationBackoff):
+		goto START
+	case <-stopCh:
+		return
+	}
+}
+
+// diffACLBindingRules is used to perform a two-way diff between the local
+// binding rules and the remote binding rules to determine which binding
+// rules need to be deleted or updated.
+func diffACLBindingRules(state *state.StateStore, minIndex uint64, remoteList []*structs.ACLBindingRuleListStub) (delete []string, update []string) {
+	// Construct a set of the local and remote binding rules
+	local := make(map[string][]byte)
+	remote := make(map[string]struct{})
+
+	// Add all the local binding rules
+	iter, err := state.ACLBindingRules(nil)
+	if err != nil {
+		panic("failed to iterate local binding rules")
+	}
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		rule := raw.(*structs.ACLBindingRule)
+		local[rule.ID] = rule.Hash
+	}
+
+	// Iterate over the remote binding rules
+	for _, rr := range remoteList {
+		remote[rr.ID] = struct{}{}
+
+		// Check if the binding rule is missing locally
+		if localHash, ok := local[rr.ID]; !ok {
+			update = append(update, rr.ID)
+
+			// Check if the binding rule is newer remotely and there is a hash mis-match.
+		} else if rr.ModifyIndex > minIndex && !bytes.Equal(localHash, rr.Hash) {
+			update = append(update, rr.ID)
+		}
+	}
+
+	// Check if the local binding rule should be deleted
+	for lr := range local {
+		if _, ok := remote[lr]; !ok {
+			delete = append(delete, lr)
+		}
+	}
+	return
+}
+
 // getOrCreateAutopilotConfig is used to get the autopilot config, initializing it if necessary
 func (s *Server) getOrCreateAutopilotConfig() *structs.AutopilotConfig {
 	state := s.fsm.State()
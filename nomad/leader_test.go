@@ -744,6 +744,116 @@ func TestLeader_DiffACLPolicies(t *testing.T) {
 	assert.Equal(t, []string{p3.Name, p4.Name}, update)
 }
 
+func TestLeader_ReplicateACLAuthMethodsAndBindingRules(t *testing.T) {
+	t.Parallel()
+	s1, root := TestACLServer(t, func(c *Config) {
+		c.Region = "region1"
+		c.AuthoritativeRegion = "region1"
+		c.ACLEnabled = true
+	})
+	defer s1.Shutdown()
+	s2, _ := TestACLServer(t, func(c *Config) {
+		c.Region = "region2"
+		c.AuthoritativeRegion = "region1"
+		c.ACLEnabled = true
+		c.ReplicationBackoff = 20 * time.Millisecond
+		c.ReplicationToken = root.SecretID
+	})
+	defer s2.Shutdown()
+	TestJoin(t, s1, s2)
+	testutil.WaitForLeader(t, s1.RPC)
+	testutil.WaitForLeader(t, s2.RPC)
+
+	// Write an auth method and binding rule to the authoritative region
+	am := mock.ACLAuthMethod()
+	require.NoError(t, s1.State().UpsertACLAuthMethods(100, []*structs.ACLAuthMethod{am}))
+	rule := mock.ACLBindingRule()
+	rule.AuthMethod = am.Name
+	require.NoError(t, s1.State().UpsertACLBindingRules(101, []*structs.ACLBindingRule{rule}))
+
+	// Wait for both to replicate
+	testutil.WaitForResult(func() (bool, error) {
+		state := s2.State()
+		outMethod, err := state.ACLAuthMethodByName(nil, am.Name)
+		if err != nil || outMethod == nil {
+			return false, err
+		}
+		outRule, err := state.ACLBindingRuleByID(nil, rule.ID)
+		return outRule != nil, err
+	}, func(err error) {
+		t.Fatalf("should replicate auth method and binding rule")
+	})
+}
+
+func TestLeader_DiffACLAuthMethods(t *testing.T) {
+	t.Parallel()
+
+	state := state.TestStateStore(t)
+
+	// Populate the local state
+	am1 := mock.ACLAuthMethod()
+	am2 := mock.ACLAuthMethod()
+	am3 := mock.ACLAuthMethod()
+	require.NoError(t, state.UpsertACLAuthMethods(100, []*structs.ACLAuthMethod{am1, am2, am3}))
+
+	// Simulate a remote list
+	am2Stub := am2.Stub()
+	am2Stub.ModifyIndex = 50 // Ignored, same index
+	am3Stub := am3.Stub()
+	am3Stub.ModifyIndex = 100 // Updated, higher index
+	am3Stub.Hash = []byte{0, 1, 2, 3}
+	am4 := mock.ACLAuthMethod()
+	remoteList := []*structs.ACLAuthMethodListStub{
+		am2Stub,
+		am3Stub,
+		am4.Stub(),
+	}
+	delete, update := diffACLAuthMethods(state, 50, remoteList)
+
+	// AM1 does not exist on the remote side, should delete
+	require.Equal(t, []string{am1.Name}, delete)
+
+	// AM2 is un-modified - ignore. AM3 modified, AM4 new.
+	require.Equal(t, []string{am3.Name, am4.Name}, update)
+}
+
+func TestLeader_DiffACLBindingRules(t *testing.T) {
+	t.Parallel()
+
+	state := state.TestStateStore(t)
+
+	// Populate the local state
+	am := mock.ACLAuthMethod()
+	require.NoError(t, state.UpsertACLAuthMethods(100, []*structs.ACLAuthMethod{am}))
+	r1 := mock.ACLBindingRule()
+	r2 := mock.ACLBindingRule()
+	r3 := mock.ACLBindingRule()
+	for _, r := range []*structs.ACLBindingRule{r1, r2, r3} {
+		r.AuthMethod = am.Name
+	}
+	require.NoError(t, state.UpsertACLBindingRules(100, []*structs.ACLBindingRule{r1, r2, r3}))
+
+	// Simulate a remote list
+	r2Stub := r2.Stub()
+	r2Stub.ModifyIndex = 50 // Ignored, same index
+	r3Stub := r3.Stub()
+	r3Stub.ModifyIndex = 100 // Updated, higher index
+	r3Stub.Hash = []byte{0, 1, 2, 3}
+	r4 := mock.ACLBindingRule()
+	remoteList := []*structs.ACLBindingRuleListStub{
+		r2Stub,
+		r3Stub,
+		r4.Stub(),
+	}
+	delete, update := diffACLBindingRules(state, 50, remoteList)
+
+	// R1 does not exist on the remote side, should delete
+	require.Equal(t, []string{r1.ID}, delete)
+
+	// R2 is un-modified - ignore. R3 modified, R4 new.
+	require.Equal(t, []string{r3.ID, r4.ID}, update)
+}
+
 func TestLeader_ReplicateACLTokens(t *testing.T) {
 	t.Parallel()
 	s1, root := TestACLServer(t, func(c *Config) {
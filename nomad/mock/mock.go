@@ -85,6 +85,7 @@ func Node() *structs.Node {
 			"version":  "5.6",
 		},
 		NodeClass:             "linux-medium-pci",
+		NodePool:              structs.NodePoolDefault,
 		Status:                structs.NodeStatusReady,
 		SchedulingEligibility: structs.NodeSchedulingEligible,
 	}
@@ -389,6 +390,15 @@ func SystemJob() *structs.Job {
 	return job
 }
 
+func SysBatchJob() *structs.Job {
+	job := SystemJob()
+	job.ID = fmt.Sprintf("mock-sysbatch-%s", uuid.Generate())
+	job.Type = structs.JobTypeSysBatch
+	job.TaskGroups[0].RestartPolicy = structs.NewRestartPolicy(structs.JobTypeSysBatch)
+	job.TaskGroups[0].Tasks[0].Resources.Networks = nil
+	return job
+}
+
 func PeriodicJob() *structs.Job {
 	job := Job()
 	job.Type = structs.JobTypeBatch
@@ -740,3 +750,72 @@ func ACLManagementToken() *structs.ACLToken {
 		ModifyIndex: 20,
 	}
 }
+
+func ACLRole() *structs.ACLRole {
+	role := &structs.ACLRole{
+		Name:        fmt.Sprintf("acl-role-%s", uuid.Generate()[:8]),
+		Description: "mock role",
+		Policies:    []string{"foo", "bar"},
+		CreateIndex: 10,
+		ModifyIndex: 10,
+	}
+	role.SetHash()
+	return role
+}
+
+func ACLAuthMethod() *structs.ACLAuthMethod {
+	method := &structs.ACLAuthMethod{
+		Name:          fmt.Sprintf("acl-auth-method-%s", uuid.Generate()[:8]),
+		Type:          structs.ACLAuthMethodTypeOIDC,
+		TokenLocality: structs.ACLAuthMethodTokenLocalityLocal,
+		MaxTokenTTL:   time.Hour,
+		Config: &structs.ACLAuthMethodConfig{
+			OIDCDiscoveryURL:    "http://example.com",
+			OIDCClientID:        "mock",
+			OIDCClientSecret:    "very secret secret",
+			BoundAudiences:      []string{"audience1", "audience2"},
+			AllowedRedirectURIs: []string{"foo", "bar"},
+			SigningAlgs:         []string{"RS256"},
+			ClaimMappings:       map[string]string{"email": "email"},
+			ListClaimMappings:   map[string]string{"groups": "groups"},
+		},
+		CreateTime:  time.Now().UTC(),
+		CreateIndex: 10,
+		ModifyIndex: 10,
+	}
+	method.SetHash()
+	return method
+}
+
+func ACLBindingRule() *structs.ACLBindingRule {
+	rule := &structs.ACLBindingRule{
+		ID:          uuid.Generate(),
+		Description: "mock binding rule",
+		AuthMethod:  "auth0",
+		Selector:    `"nomad-engineering" in list.groups`,
+		BindType:    structs.ACLBindingRuleBindTypePolicy,
+		BindName:    "engineering-read",
+		CreateTime:  time.Now().UTC(),
+		CreateIndex: 10,
+		ModifyIndex: 10,
+	}
+	rule.SetHash()
+	return rule
+}
+
+func Volume() *structs.Volume {
+	return &structs.Volume{
+		ID:             fmt.Sprintf("volume-%s", uuid.Generate()[:8]),
+		Name:           "mock volume",
+		Namespace:      structs.DefaultNamespace,
+		Type:           structs.VolumeTypeCSI,
+		PluginID:       "ebs",
+		ExternalID:     "vol-0123456789",
+		AccessMode:     structs.VolumeAccessModeSingleNodeWriter,
+		AttachmentMode: structs.VolumeAttachmentModeFilesystem,
+		CapacityMin:    1 << 30,
+		Parameters:     map[string]string{"type": "gp2"},
+		CreateIndex:    10,
+		ModifyIndex:    10,
+	}
+}
@@ -0,0 +1,119 @@
+package nomad
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// multiregionFanouts tracks the requests for multiregion jobs the server is
+// fanning out to the regions of the jobs. Each fan-out is identified by a
+// token carried by the copies of the request, which the regions verify with
+// the server before applying a copy. Clients thus can not mark a request as a
+// copy to apply it in a single region.
+type multiregionFanouts struct {
+	fanouts map[string]*multiregionFanout
+	l       sync.Mutex
+}
+
+// multiregionFanout is a request being fanned out to the regions of a job.
+type multiregionFanout struct {
+	namespace string
+	jobID     string
+	regions   map[string]struct{}
+}
+
+// newMultiregionFanouts returns an empty fan-out tracker.
+func newMultiregionFanouts() *multiregionFanouts {
+	return &multiregionFanouts{
+		fanouts: make(map[string]*multiregionFanout),
+	}
+}
+
+// start tracks a new fan-out of a request for the job to the given regions,
+// returning the origin to set on the copies of the request.
+func (m *multiregionFanouts) start(region, namespace, jobID string, regions []*structs.MultiregionRegion) *structs.MultiregionOrigin {
+	fanout := &multiregionFanout{
+		namespace: namespace,
+		jobID:     jobID,
+		regions:   make(map[string]struct{}, len(regions)),
+	}
+	for _, r := range regions {
+		fanout.regions[r.Name] = struct{}{}
+	}
+
+	token := uuid.Generate()
+	m.l.Lock()
+	m.fanouts[token] = fanout
+	m.l.Unlock()
+	return &structs.MultiregionOrigin{
+		Region: region,
+		Token:  token,
+	}
+}
+
+// stop stops tracking a fan-out once it is done.
+func (m *multiregionFanouts) stop(token string) {
+	m.l.Lock()
+	delete(m.fanouts, token)
+	m.l.Unlock()
+}
+
+// valid returns whether the token identifies a fan-out in progress of a
+// request for the job to the given region.
+func (m *multiregionFanouts) valid(token, namespace, jobID, region string) bool {
+	m.l.Lock()
+	defer m.l.Unlock()
+	fanout, ok := m.fanouts[token]
+	if !ok || fanout.namespace != namespace || fanout.jobID != jobID {
+		return false
+	}
+	_, ok = fanout.regions[region]
+	return ok
+}
+
+// multiregionStatus collects the outcome of a request fanned out to the
+// regions of a multiregion job.
+type multiregionStatus struct {
+	op      string
+	total   int
+	applied []string
+	errs    *multierror.Error
+}
+
+// newMultiregionStatus returns the status of a fan-out of the operation to
+// the given number of regions.
+func newMultiregionStatus(op string, total int) *multiregionStatus {
+	return &multiregionStatus{op: op, total: total}
+}
+
+// succeeded records that the request was applied in the region.
+func (s *multiregionStatus) succeeded(region string) {
+	s.applied = append(s.applied, region)
+}
+
+// failed records that the request failed in the region.
+func (s *multiregionStatus) failed(region string, err error) {
+	s.errs = multierror.Append(s.errs, fmt.Errorf("region %q: %v", region, err))
+}
+
+// err returns an error reporting the outcome in each region if the request
+// failed in any region, or nil otherwise.
+func (s *multiregionStatus) err() error {
+	if s.errs == nil {
+		return nil
+	}
+
+	succeeded := "none"
+	if len(s.applied) != 0 {
+		sort.Strings(s.applied)
+		succeeded = strings.Join(s.applied, ", ")
+	}
+	return fmt.Errorf("failed to %s job in %d of %d regions (succeeded in: %s): %v",
+		s.op, len(s.errs.Errors), s.total, succeeded, s.errs)
+}
@@ -0,0 +1,154 @@
+package nomad
+
+import (
+	"testing"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNamespaceEndpoint_UpsertNamespaces(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	ns := &structs.Namespace{
+		Name:        "team-a",
+		Description: "team a",
+		Quota:       "small",
+	}
+	req := &structs.NamespaceUpsertRequest{
+		Namespaces:   []*structs.Namespace{ns},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.GenericResponse
+
+	// Namespaces can not reference unknown quota specifications
+	err := msgpackrpc.CallWithCodec(codec, "Namespace.UpsertNamespaces", req, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "unknown quota specification")
+
+	require.NoError(s1.fsm.State().UpsertQuotaSpecs(1000, []*structs.QuotaSpec{testQuotaSpec("small")}))
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Namespace.UpsertNamespaces", req, &resp))
+	require.NotZero(resp.Index)
+
+	// Check we created the namespace
+	get := &structs.NamespaceSpecificRequest{
+		Name:         ns.Name,
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var getResp structs.SingleNamespaceResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Namespace.GetNamespace", get, &getResp))
+	require.NotNil(getResp.Namespace)
+	require.Equal(ns.Quota, getResp.Namespace.Quota)
+
+	// Invalid namespaces are rejected
+	req.Namespaces = []*structs.Namespace{{Name: "team a"}}
+	err = msgpackrpc.CallWithCodec(codec, "Namespace.UpsertNamespaces", req, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "invalid name")
+}
+
+func TestNamespaceEndpoint_List(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	namespaces := []*structs.Namespace{{Name: "team-a"}, {Name: "team-b"}, {Name: "ops"}}
+	require.NoError(s1.fsm.State().UpsertNamespaces(1000, namespaces))
+
+	get := &structs.NamespaceListRequest{
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var resp structs.NamespaceListResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Namespace.ListNamespaces", get, &resp))
+	require.EqualValues(1000, resp.Index)
+	require.Len(resp.Namespaces, 3)
+
+	// Lookup the namespaces by prefix
+	get.Prefix = "team"
+	var resp2 structs.NamespaceListResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Namespace.ListNamespaces", get, &resp2))
+	require.Len(resp2.Namespaces, 2)
+}
+
+func TestNamespaceEndpoint_DeleteNamespaces(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	state := s1.fsm.State()
+	namespaces := []*structs.Namespace{{Name: "team-a"}, {Name: "empty"}}
+	require.NoError(state.UpsertNamespaces(1000, namespaces))
+
+	job := mock.Job()
+	job.Namespace = "team-a"
+	require.NoError(state.UpsertJob(1001, job))
+
+	req := &structs.NamespaceDeleteRequest{
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.GenericResponse
+
+	// Namespaces with jobs in them and the default namespace can not be
+	// deleted
+	for _, name := range []string{"team-a", structs.DefaultNamespace} {
+		req.Namespaces = []string{name}
+		require.Error(msgpackrpc.CallWithCodec(codec, "Namespace.DeleteNamespaces", req, &resp), name)
+	}
+
+	req.Namespaces = []string{"empty"}
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Namespace.DeleteNamespaces", req, &resp))
+	require.NotZero(resp.Index)
+
+	out, err := state.NamespaceByName(nil, "empty")
+	require.NoError(err)
+	require.Nil(out)
+}
+
+func TestNamespaceEndpoint_ACL(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1, root := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	state := s1.fsm.State()
+	require.NoError(state.UpsertNamespaces(1000, []*structs.Namespace{{Name: "team-a"}, {Name: "team-b"}}))
+	token := mock.CreatePolicyAndToken(t, state, 1001, "team-a",
+		mock.NamespacePolicy("team-a", "", []string{acl.NamespaceCapabilityReadJob}))
+
+	// Listing only returns the namespaces the token has access to
+	get := &structs.NamespaceListRequest{
+		QueryOptions: structs.QueryOptions{Region: "global", AuthToken: token.SecretID},
+	}
+	var resp structs.NamespaceListResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Namespace.ListNamespaces", get, &resp))
+	require.Len(resp.Namespaces, 1)
+	require.Equal("team-a", resp.Namespaces[0].Name)
+
+	// Upserting requires a management token
+	req := &structs.NamespaceUpsertRequest{
+		Namespaces:   []*structs.Namespace{{Name: "team-c"}},
+		WriteRequest: structs.WriteRequest{Region: "global", AuthToken: token.SecretID},
+	}
+	var upsertResp structs.GenericResponse
+	err := msgpackrpc.CallWithCodec(codec, "Namespace.UpsertNamespaces", req, &upsertResp)
+	require.EqualError(err, structs.ErrPermissionDenied.Error())
+	req.AuthToken = root.SecretID
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Namespace.UpsertNamespaces", req, &upsertResp))
+}
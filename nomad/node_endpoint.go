@@ -109,6 +109,9 @@ func (n *Node) Register(args *structs.NodeRegisterRequest, reply *structs.NodeUp
 	if args.Node.SecretID == "" {
 		return fmt.Errorf("missing node secret ID for client registration")
 	}
+	if args.Node.NodePool != "" && !structs.ValidNodePoolName(args.Node.NodePool) {
+		return fmt.Errorf("invalid node pool for client registration")
+	}
 
 	// Default the status if none is given
 	if args.Node.Status == "" {
@@ -457,10 +460,8 @@ func (n *Node) UpdateDrain(args *structs.NodeUpdateDrainRequest,
 	defer metrics.MeasureSince([]string{"nomad", "client", "update_drain"}, time.Now())
 
 	// Check node write permissions
-	if aclObj, err := n.srv.ResolveToken(args.AuthToken); err != nil {
+	if err := n.authorizeNodeWrite(args.AuthToken, args.NodeID); err != nil {
 		return err
-	} else if aclObj != nil && !aclObj.AllowNodeWrite() {
-		return structs.ErrPermissionDenied
 	}
 
 	// Verify the arguments
@@ -536,6 +537,40 @@ func (n *Node) UpdateDrain(args *structs.NodeUpdateDrainRequest,
 	return nil
 }
 
+// authorizeNodeWrite checks that the token may update the given node. Besides
+// ACL tokens with node write permissions, the secret ID of a node is accepted
+// so that clients can drain themselves on shutdown and mark themselves
+// ineligible under disk pressure. A node may only update itself.
+func (n *Node) authorizeNodeWrite(authToken, nodeID string) error {
+	aclObj, err := n.srv.ResolveToken(authToken)
+	if err == nil {
+		if aclObj != nil && !aclObj.AllowNodeWrite() {
+			return structs.ErrPermissionDenied
+		}
+		return nil
+	}
+
+	// If ResolveToken had an unexpected error return that
+	if err != structs.ErrTokenNotFound {
+		return err
+	}
+
+	// Attempt to lookup AuthToken as a Node.SecretID
+	node, stateErr := n.srv.fsm.State().NodeBySecretID(nil, authToken)
+	if stateErr != nil {
+		var merr multierror.Error
+		merr.Errors = append(merr.Errors, err, stateErr)
+		return merr.ErrorOrNil()
+	}
+	if node == nil {
+		return structs.ErrTokenNotFound
+	}
+	if node.ID != nodeID {
+		return structs.ErrPermissionDenied
+	}
+	return nil
+}
+
 // UpdateEligibility is used to update the scheduling eligibility of a node
 func (n *Node) UpdateEligibility(args *structs.NodeUpdateEligibilityRequest,
 	reply *structs.NodeEligibilityUpdateResponse) error {
@@ -545,10 +580,8 @@ func (n *Node) UpdateEligibility(args *structs.NodeUpdateEligibilityRequest,
 	defer metrics.MeasureSince([]string{"nomad", "client", "update_eligibility"}, time.Now())
 
 	// Check node write permissions
-	if aclObj, err := n.srv.ResolveToken(args.AuthToken); err != nil {
+	if err := n.authorizeNodeWrite(args.AuthToken, args.NodeID); err != nil {
 		return err
-	} else if aclObj != nil && !aclObj.AllowNodeWrite() {
-		return structs.ErrPermissionDenied
 	}
 
 	// Verify the arguments
@@ -629,6 +662,84 @@ func (n *Node) UpdateEligibility(args *structs.NodeUpdateEligibilityRequest,
 	return nil
 }
 
+// UpdateDynamicMeta is used to update the dynamic metadata of a node. The
+// jobs with allocations on the node are re-evaluated since the metadata may
+// be targeted by their constraints and affinities. The evaluations only place
+// new allocations; like other node attribute changes, running allocations
+// whose constraints no longer match are not stopped.
+func (n *Node) UpdateDynamicMeta(args *structs.NodeUpdateDynamicMetaRequest,
+	reply *structs.NodeDynamicMetaUpdateResponse) error {
+	if done, err := n.srv.forward("Node.UpdateDynamicMeta", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "client", "update_dynamic_meta"}, time.Now())
+
+	// Check node write permissions
+	if aclObj, err := n.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNodeWrite() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Verify the arguments
+	if args.NodeID == "" {
+		return fmt.Errorf("missing node ID for updating dynamic metadata")
+	}
+	if len(args.Set) == 0 && len(args.Unset) == 0 {
+		return fmt.Errorf("must set or unset at least one dynamic metadata key")
+	}
+	for k := range args.Set {
+		if k == "" {
+			return fmt.Errorf("dynamic metadata keys must not be empty")
+		}
+	}
+	for _, k := range args.Unset {
+		if _, ok := args.Set[k]; ok {
+			return fmt.Errorf("dynamic metadata key %q can not be both set and unset", k)
+		}
+	}
+
+	// Look for the node
+	snap, err := n.srv.fsm.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	node, err := snap.NodeByID(nil, args.NodeID)
+	if err != nil {
+		return err
+	}
+	if node == nil {
+		return fmt.Errorf("node not found")
+	}
+
+	// Commit this update via Raft
+	outErr, index, err := n.srv.raftApply(structs.NodeUpdateDynamicMetaRequestType, args)
+	if err != nil {
+		n.logger.Error("dynamic metadata update failed", "error", err)
+		return err
+	}
+	if outErr != nil {
+		if err, ok := outErr.(error); ok && err != nil {
+			n.logger.Error("dynamic metadata update failed", "error", err)
+			return err
+		}
+	}
+
+	// Create the evaluations for the jobs that may be affected by the change
+	evalIDs, evalIndex, err := n.createNodeEvals(args.NodeID, index)
+	if err != nil {
+		n.logger.Error("eval creation failed", "error", err)
+		return err
+	}
+	reply.EvalIDs = evalIDs
+	reply.EvalCreateIndex = evalIndex
+
+	// Set the reply index
+	reply.NodeModifyIndex = index
+	reply.Index = index
+	return nil
+}
+
 // Evaluate is used to force a re-evaluation of the node
 func (n *Node) Evaluate(args *structs.NodeEvaluateRequest, reply *structs.NodeUpdateResponse) error {
 	if done, err := n.srv.forward("Node.Evaluate", args, args, reply); done {
@@ -1176,16 +1287,26 @@ func (n *Node) List(args *structs.NodeListRequest,
 				return err
 			}
 
+			paginator, err := newPaginator(iter, args.QueryOptions,
+				func(raw interface{}) string {
+					return raw.(*structs.Node).ID
+				},
+				func(raw interface{}) (interface{}, error) {
+					return raw.(*structs.Node).Stub(), nil
+				})
+			if err != nil {
+				return err
+			}
+
 			var nodes []*structs.NodeListStub
-			for {
-				raw := iter.Next()
-				if raw == nil {
-					break
-				}
-				node := raw.(*structs.Node)
-				nodes = append(nodes, node.Stub())
+			nextToken, err := paginator.Page(func(obj interface{}) {
+				nodes = append(nodes, obj.(*structs.NodeListStub))
+			})
+			if err != nil {
+				return err
 			}
 			reply.Nodes = nodes
+			reply.NextToken = nextToken
 
 			// Use the last index that affected the jobs table
 			index, err := state.Index("nodes")
@@ -1227,6 +1348,21 @@ func (n *Node) createNodeEvals(nodeID string, nodeIndex uint64) ([]string, uint6
 		sysJobs = append(sysJobs, job.(*structs.Job))
 	}
 
+	sysBatchJobsIter, err := snap.JobsByScheduler(ws, structs.JobTypeSysBatch)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to find sysbatch jobs for '%s': %v", nodeID, err)
+	}
+
+	for raw := sysBatchJobsIter.Next(); raw != nil; raw = sysBatchJobsIter.Next() {
+		// Periodic and parameterized jobs only launch child jobs and never
+		// have allocations of their own.
+		job := raw.(*structs.Job)
+		if job.IsPeriodic() || job.IsParameterized() {
+			continue
+		}
+		sysJobs = append(sysJobs, job)
+	}
+
 	// Fast-path if nothing to do
 	if len(allocs) == 0 && len(sysJobs) == 0 {
 		return nil, 0, nil
@@ -1260,7 +1396,7 @@ func (n *Node) createNodeEvals(nodeID string, nodeIndex uint64) ([]string, uint6
 		evalIDs = append(evalIDs, eval.ID)
 	}
 
-	// Create an evaluation for each system job.
+	// Create an evaluation for each system and sysbatch job.
 	for _, job := range sysJobs {
 		// Still dedup on JobID as the node may already have the system job.
 		if _, ok := jobIDs[job.ID]; ok {
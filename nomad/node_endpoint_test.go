@@ -991,6 +991,24 @@ func TestClientEndpoint_UpdateDrain_ACL(t *testing.T) {
 		var resp structs.NodeDrainUpdateResponse
 		require.Nil(msgpackrpc.CallWithCodec(codec, "Node.UpdateDrain", dereg, &resp), "RPC")
 	}
+
+	// Try with the secret ID of the node
+	dereg.AuthToken = node.SecretID
+	{
+		var resp structs.NodeDrainUpdateResponse
+		require.Nil(msgpackrpc.CallWithCodec(codec, "Node.UpdateDrain", dereg, &resp), "RPC")
+	}
+
+	// Try with the secret ID of another node
+	other := mock.Node()
+	require.Nil(state.UpsertNode(1004, other), "UpsertNode")
+	dereg.AuthToken = other.SecretID
+	{
+		var resp structs.NodeDrainUpdateResponse
+		err := msgpackrpc.CallWithCodec(codec, "Node.UpdateDrain", dereg, &resp)
+		require.NotNil(err, "RPC")
+		require.Equal(err.Error(), structs.ErrPermissionDenied.Error())
+	}
 }
 
 // This test ensures that Nomad marks client state of allocations which are in
@@ -1182,6 +1200,68 @@ func TestClientEndpoint_UpdateEligibility(t *testing.T) {
 	require.Equal(NodeEligibilityEventEligible, out.Events[2].Message)
 }
 
+func TestClientEndpoint_UpdateDynamicMeta(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create the register request
+	node := mock.Node()
+	reg := &structs.NodeRegisterRequest{
+		Node:         node,
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.NodeUpdateResponse
+	require.Nil(msgpackrpc.CallWithCodec(codec, "Node.Register", reg, &resp))
+
+	// Register a system job
+	job := mock.SystemJob()
+	require.Nil(s1.State().UpsertJob(10, job))
+
+	// Set the dynamic metadata and expect evals
+	req := &structs.NodeUpdateDynamicMetaRequest{
+		NodeID:       node.ID,
+		Set:          map[string]string{"rack": "r1", "zone": "a"},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp2 structs.NodeDynamicMetaUpdateResponse
+	require.Nil(msgpackrpc.CallWithCodec(codec, "Node.UpdateDynamicMeta", req, &resp2))
+	require.NotZero(resp2.Index)
+	require.NotZero(resp2.EvalCreateIndex)
+	require.Len(resp2.EvalIDs, 1)
+
+	state := s1.fsm.State()
+	out, err := state.NodeByID(nil, node.ID)
+	require.Nil(err)
+	require.Equal(map[string]string{"rack": "r1", "zone": "a"}, out.DynamicMeta)
+	require.NotEqual(node.ComputedClass, out.ComputedClass)
+
+	// Re-registering the node retains the dynamic metadata
+	reg.Node = node.Copy()
+	require.Nil(msgpackrpc.CallWithCodec(codec, "Node.Register", reg, &resp))
+	out, err = state.NodeByID(nil, node.ID)
+	require.Nil(err)
+	require.Equal(map[string]string{"rack": "r1", "zone": "a"}, out.DynamicMeta)
+	require.NotEqual(node.ComputedClass, out.ComputedClass)
+
+	// Unset a key
+	req.Set = nil
+	req.Unset = []string{"zone"}
+	require.Nil(msgpackrpc.CallWithCodec(codec, "Node.UpdateDynamicMeta", req, &resp2))
+	out, err = state.NodeByID(nil, node.ID)
+	require.Nil(err)
+	require.Equal(map[string]string{"rack": "r1"}, out.DynamicMeta)
+
+	// An empty update is rejected
+	req.Unset = nil
+	err = msgpackrpc.CallWithCodec(codec, "Node.UpdateDynamicMeta", req, &resp2)
+	require.Error(err)
+	require.Contains(err.Error(), "at least one")
+}
+
 func TestClientEndpoint_UpdateEligibility_ACL(t *testing.T) {
 	t.Parallel()
 	s1, root := TestACLServer(t, nil)
@@ -1235,6 +1315,25 @@ func TestClientEndpoint_UpdateEligibility_ACL(t *testing.T) {
 		var resp structs.NodeEligibilityUpdateResponse
 		require.Nil(msgpackrpc.CallWithCodec(codec, "Node.UpdateEligibility", dereg, &resp), "RPC")
 	}
+
+	// Try with the secret ID of the node
+	dereg.AuthToken = node.SecretID
+	dereg.Eligibility = structs.NodeSchedulingEligible
+	{
+		var resp structs.NodeEligibilityUpdateResponse
+		require.Nil(msgpackrpc.CallWithCodec(codec, "Node.UpdateEligibility", dereg, &resp), "RPC")
+	}
+
+	// Try with the secret ID of another node
+	other := mock.Node()
+	require.Nil(state.UpsertNode(1004, other), "UpsertNode")
+	dereg.AuthToken = other.SecretID
+	{
+		var resp structs.NodeEligibilityUpdateResponse
+		err := msgpackrpc.CallWithCodec(codec, "Node.UpdateEligibility", dereg, &resp)
+		require.NotNil(err, "RPC")
+		require.Equal(err.Error(), structs.ErrPermissionDenied.Error())
+	}
 }
 
 func TestClientEndpoint_GetNode(t *testing.T) {
@@ -2286,6 +2385,21 @@ func TestClientEndpoint_CreateNodeEvals(t *testing.T) {
 		t.Fatalf("err: %v", err)
 	}
 
+	// Inject a fake sysbatch job and a periodic one that should be ignored.
+	sysBatchJob := mock.SysBatchJob()
+	if err := state.UpsertJob(4, sysBatchJob); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	periodicJob := mock.SysBatchJob()
+	periodicJob.Periodic = &structs.PeriodicConfig{
+		Enabled:  true,
+		SpecType: structs.PeriodicSpecCron,
+		Spec:     "*/30 * * * *",
+	}
+	if err := state.UpsertJob(5, periodicJob); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
 	// Create some evaluations
 	ids, index, err := s1.staticEndpoints.Node.createNodeEvals(alloc.NodeID, 1)
 	if err != nil {
@@ -2294,13 +2408,13 @@ func TestClientEndpoint_CreateNodeEvals(t *testing.T) {
 	if index == 0 {
 		t.Fatalf("bad: %d", index)
 	}
-	if len(ids) != 2 {
+	if len(ids) != 3 {
 		t.Fatalf("bad: %s", ids)
 	}
 
 	// Lookup the evaluations
 	ws := memdb.NewWatchSet()
-	evalByType := make(map[string]*structs.Evaluation, 2)
+	evalByType := make(map[string]*structs.Evaluation, 3)
 	for _, id := range ids {
 		eval, err := state.EvalByID(ws, id)
 		if err != nil {
@@ -2317,17 +2431,21 @@ func TestClientEndpoint_CreateNodeEvals(t *testing.T) {
 		evalByType[eval.Type] = eval
 	}
 
-	if len(evalByType) != 2 {
-		t.Fatalf("Expected a service and system job; got %#v", evalByType)
+	if len(evalByType) != 3 {
+		t.Fatalf("Expected a service, system and sysbatch job; got %#v", evalByType)
 	}
 
 	// Ensure the evals are correct.
 	for schedType, eval := range evalByType {
 		expPriority := alloc.Job.Priority
 		expJobID := alloc.JobID
-		if schedType == "system" {
+		switch schedType {
+		case structs.JobTypeSystem:
 			expPriority = job.Priority
 			expJobID = job.ID
+		case structs.JobTypeSysBatch:
+			expPriority = sysBatchJob.Priority
+			expJobID = sysBatchJob.ID
 		}
 
 		if eval.CreateIndex != index {
@@ -0,0 +1,183 @@
+package nomad
+
+import (
+	"testing"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodePoolEndpoint_UpsertNodePools(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	pool := &structs.NodePool{
+		Name:        "gpu",
+		Description: "nodes with GPUs",
+	}
+	req := &structs.NodePoolUpsertRequest{
+		NodePools:    []*structs.NodePool{pool},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.GenericResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "NodePool.UpsertNodePools", req, &resp))
+	require.NotZero(resp.Index)
+
+	// Check we created the node pool
+	out, err := s1.fsm.State().NodePoolByName(nil, pool.Name)
+	require.NoError(err)
+	require.NotNil(out)
+	require.Equal(pool.Description, out.Description)
+
+	// Invalid node pools are rejected
+	req.NodePools = []*structs.NodePool{{Name: "gpu pool"}}
+	err = msgpackrpc.CallWithCodec(codec, "NodePool.UpsertNodePools", req, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "invalid name")
+}
+
+func TestNodePoolEndpoint_GetNodePool(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	pool := &structs.NodePool{Name: "gpu"}
+	require.NoError(s1.fsm.State().UpsertNodePools(1000, []*structs.NodePool{pool}))
+
+	get := &structs.NodePoolSpecificRequest{
+		Name:         pool.Name,
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var resp structs.SingleNodePoolResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "NodePool.GetNodePool", get, &resp))
+	require.EqualValues(1000, resp.Index)
+	require.Equal(pool, resp.NodePool)
+
+	// Lookup a non-existing node pool
+	get.Name = "missing"
+	var resp2 structs.SingleNodePoolResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "NodePool.GetNodePool", get, &resp2))
+	require.EqualValues(1000, resp2.Index)
+	require.Nil(resp2.NodePool)
+}
+
+func TestNodePoolEndpoint_List(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	pools := []*structs.NodePool{{Name: "gpu"}, {Name: "gpu-large"}, {Name: "batch"}}
+	require.NoError(s1.fsm.State().UpsertNodePools(1000, pools))
+
+	get := &structs.NodePoolListRequest{
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var resp structs.NodePoolListResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "NodePool.List", get, &resp))
+	require.EqualValues(1000, resp.Index)
+	require.Len(resp.NodePools, 4)
+
+	// Lookup the node pools by prefix
+	get.Prefix = "gpu"
+	var resp2 structs.NodePoolListResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "NodePool.List", get, &resp2))
+	require.Len(resp2.NodePools, 2)
+}
+
+func TestNodePoolEndpoint_DeleteNodePools(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	state := s1.fsm.State()
+	pools := []*structs.NodePool{{Name: "gpu"}, {Name: "batch"}, {Name: "empty"}}
+	require.NoError(state.UpsertNodePools(1000, pools))
+
+	node := mock.Node()
+	node.NodePool = "gpu"
+	require.NoError(state.UpsertNode(1001, node))
+
+	job := mock.Job()
+	job.NodePool = "batch"
+	require.NoError(state.UpsertJob(1002, job))
+
+	req := &structs.NodePoolDeleteRequest{
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.GenericResponse
+
+	// Node pools with nodes or jobs in them and the default node pool can
+	// not be deleted
+	for _, name := range []string{"gpu", "batch", structs.NodePoolDefault} {
+		req.Names = []string{name}
+		require.Error(msgpackrpc.CallWithCodec(codec, "NodePool.DeleteNodePools", req, &resp), name)
+	}
+
+	req.Names = []string{"empty"}
+	require.NoError(msgpackrpc.CallWithCodec(codec, "NodePool.DeleteNodePools", req, &resp))
+	require.NotZero(resp.Index)
+
+	out, err := state.NodePoolByName(nil, "empty")
+	require.NoError(err)
+	require.Nil(out)
+}
+
+func TestNodePoolEndpoint_ACL(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1, root := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	state := s1.fsm.State()
+	readToken := mock.CreatePolicyAndToken(t, state, 1001, "read", mock.NodePolicy(acl.PolicyRead))
+	writeToken := mock.CreatePolicyAndToken(t, state, 1003, "write", mock.NodePolicy(acl.PolicyWrite))
+
+	req := &structs.NodePoolUpsertRequest{
+		NodePools:    []*structs.NodePool{{Name: "gpu"}},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.GenericResponse
+
+	// Upsert without a token or with a read token fails
+	err := msgpackrpc.CallWithCodec(codec, "NodePool.UpsertNodePools", req, &resp)
+	require.EqualError(err, structs.ErrPermissionDenied.Error())
+	req.AuthToken = readToken.SecretID
+	err = msgpackrpc.CallWithCodec(codec, "NodePool.UpsertNodePools", req, &resp)
+	require.EqualError(err, structs.ErrPermissionDenied.Error())
+
+	// Upsert with a write or management token succeeds
+	req.AuthToken = writeToken.SecretID
+	require.NoError(msgpackrpc.CallWithCodec(codec, "NodePool.UpsertNodePools", req, &resp))
+	req.AuthToken = root.SecretID
+	require.NoError(msgpackrpc.CallWithCodec(codec, "NodePool.UpsertNodePools", req, &resp))
+
+	// List with a read token succeeds
+	get := &structs.NodePoolListRequest{
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var listResp structs.NodePoolListResponse
+	err = msgpackrpc.CallWithCodec(codec, "NodePool.List", get, &listResp)
+	require.EqualError(err, structs.ErrPermissionDenied.Error())
+	get.AuthToken = readToken.SecretID
+	require.NoError(msgpackrpc.CallWithCodec(codec, "NodePool.List", get, &listResp))
+	require.Len(listResp.NodePools, 2)
+}
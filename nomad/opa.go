@@ -0,0 +1,167 @@
+package nomad
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	cleanhttp "github.com/hashicorp/go-cleanhttp"
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+)
+
+// opaMaxResponseSize is the maximum size of the responses of OPA.
+const opaMaxResponseSize = 10 * 1024 * 1024
+
+// opaInput is the input of the OPA policies.
+type opaInput struct {
+	Namespace string          `json:"namespace"`
+	Job       *structs.Job    `json:"job"`
+	Policy    *opaInputPolicy `json:"policy"`
+}
+
+// opaInputPolicy describes the policy being evaluated in its input.
+type opaInputPolicy struct {
+	Name             string `json:"name"`
+	EnforcementLevel string `json:"enforcement_level"`
+}
+
+// setupOPA validates the OPA configuration and creates the client the
+// policies are queried with, if OPA policies are configured.
+func (s *Server) setupOPA() error {
+	conf := s.config.OPAConfig
+	if !conf.IsEnabled() {
+		return nil
+	}
+	conf.Canonicalize()
+	if err := conf.Validate(); err != nil {
+		return err
+	}
+
+	client := cleanhttp.DefaultPooledClient()
+	client.Timeout = conf.Timeout
+	s.opaClient = client
+	return nil
+}
+
+// enforceOPAPolicies evaluates the job against the OPA policies. It returns
+// the failures of the advisory policies and of the soft-mandatory policies
+// overridden as warnings, and the failures of the mandatory policies as an
+// error.
+func (s *Server) enforceOPAPolicies(override bool, job *structs.Job) (error, error) {
+	conf := s.config.OPAConfig
+	if s.opaClient == nil || !conf.IsEnabled() {
+		return nil, nil
+	}
+	defer metrics.MeasureSince([]string{"nomad", "opa", "enforce"}, time.Now())
+
+	var failures, warnings *multierror.Error
+	for _, policy := range conf.Policies {
+		violations, err := s.evaluateOPAPolicy(conf, policy, job)
+		if err != nil {
+			metrics.IncrCounter([]string{"nomad", "opa", "errors"}, 1)
+			violations = []string{err.Error()}
+		}
+		if len(violations) == 0 {
+			continue
+		}
+
+		failure := fmt.Errorf("%s policy %q failed: %s", policy.EnforcementLevel, policy.Name, strings.Join(violations, "; "))
+		switch {
+		case policy.EnforcementLevel == config.OPAEnforcementAdvisory:
+			warnings = multierror.Append(warnings, failure)
+		case policy.EnforcementLevel == config.OPAEnforcementSoftMandatory && override:
+			s.logger.Warn("soft-mandatory policy overridden for job", "policy", policy.Name, "job", job.ID)
+			warnings = multierror.Append(warnings, fmt.Errorf("%v (overridden)", failure))
+		default:
+			failures = multierror.Append(failures, failure)
+		}
+	}
+
+	return warnings.ErrorOrNil(), failures.ErrorOrNil()
+}
+
+// evaluateOPAPolicy queries the rule of a policy with the job as input, and
+// returns the violations of the job.
+func (s *Server) evaluateOPAPolicy(conf *config.OPAConfig, policy *config.OPAPolicyConfig, job *structs.Job) ([]string, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"input": &opaInput{
+			Namespace: job.Namespace,
+			Job:       job,
+			Policy: &opaInputPolicy{
+				Name:             policy.Name,
+				EnforcementLevel: policy.EnforcementLevel,
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	url := strings.TrimRight(conf.Address, "/") + "/v1/data/" + policy.Path
+	req, err := http.NewRequest("POST", url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if conf.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+conf.Token)
+	}
+
+	resp, err := s.opaClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query OPA: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("unexpected response code %d from OPA: %s", resp.StatusCode, bytes.TrimSpace(msg))
+	}
+
+	var out struct {
+		Result json.RawMessage `json:"result"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, opaMaxResponseSize)).Decode(&out); err != nil {
+		return nil, fmt.Errorf("failed to decode OPA response: %v", err)
+	}
+	return opaViolations(out.Result)
+}
+
+// opaViolations returns the violations of a rule result, which is either a
+// boolean allowing the job or the list of the violations of the job.
+func opaViolations(result json.RawMessage) ([]string, error) {
+	if len(result) == 0 {
+		return nil, fmt.Errorf("policy rule is undefined")
+	}
+
+	var allowed bool
+	if err := json.Unmarshal(result, &allowed); err == nil {
+		if allowed {
+			return nil, nil
+		}
+		return []string{"job not allowed"}, nil
+	}
+
+	var list []interface{}
+	if err := json.Unmarshal(result, &list); err != nil {
+		return nil, fmt.Errorf("policy rule must be a boolean or a list of violations")
+	}
+	violations := make([]string, 0, len(list))
+	for _, v := range list {
+		if msg, ok := v.(string); ok {
+			violations = append(violations, msg)
+		} else {
+			encoded, _ := json.Marshal(v)
+			violations = append(violations, string(encoded))
+		}
+	}
+	return violations, nil
+}
@@ -0,0 +1,187 @@
+package nomad
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+// testOPAServer returns an OPA server denying the jobs with more than one
+// task group or a task group count above 10, and requiring a "team" meta key.
+func testOPAServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Input opaInput `json:"input"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		job := body.Input.Job
+
+		switch r.URL.Path {
+		case "/v1/data/nomad/groups":
+			violations := []string{}
+			if len(job.TaskGroups) > 1 {
+				violations = append(violations, "jobs may have only one task group")
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"result": violations})
+		case "/v1/data/nomad/count":
+			violations := []string{}
+			for _, tg := range job.TaskGroups {
+				if tg.Count > 10 {
+					violations = append(violations, "task groups may have at most 10 allocations")
+				}
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"result": violations})
+		case "/v1/data/nomad/team":
+			json.NewEncoder(w).Encode(map[string]interface{}{"result": job.Meta["team"] != ""})
+		default:
+			json.NewEncoder(w).Encode(map[string]interface{}{})
+		}
+	}))
+}
+
+func TestJobEndpoint_Register_OPA(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	opa := testOPAServer(t)
+	defer opa.Close()
+
+	s1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+		c.OPAConfig = &config.OPAConfig{
+			Address: opa.URL,
+			Policies: []*config.OPAPolicyConfig{
+				{Name: "groups", Path: "nomad/groups", EnforcementLevel: config.OPAEnforcementSoftMandatory},
+				{Name: "team", Path: "nomad/team", EnforcementLevel: config.OPAEnforcementAdvisory},
+			},
+		}
+	})
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// The advisory policy fails with a warning
+	job := mock.Job()
+	req := &structs.JobRegisterRequest{
+		Job: job,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var resp structs.JobRegisterResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp))
+	require.Contains(resp.Warnings, `advisory policy "team" failed: job not allowed`)
+
+	// The soft-mandatory policy rejects the job
+	job = mock.Job()
+	job.Meta = map[string]string{"team": "platform"}
+	tg := job.TaskGroups[0].Copy()
+	tg.Name = "other"
+	job.TaskGroups = append(job.TaskGroups, tg)
+	req.Job = job
+	err := msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), `soft-mandatory policy "groups" failed: jobs may have only one task group`)
+
+	out, err := s1.fsm.State().JobByID(nil, job.Namespace, job.ID)
+	require.NoError(err)
+	require.Nil(out)
+
+	// The soft-mandatory policy is overridden
+	req.PolicyOverride = true
+	resp = structs.JobRegisterResponse{}
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp))
+	require.Contains(resp.Warnings, "(overridden)")
+
+	out, err = s1.fsm.State().JobByID(nil, job.Namespace, job.ID)
+	require.NoError(err)
+	require.NotNil(out)
+}
+
+func TestJobEndpoint_Scale_OPA(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	opa := testOPAServer(t)
+	defer opa.Close()
+
+	s1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+		c.OPAConfig = &config.OPAConfig{
+			Address: opa.URL,
+			Policies: []*config.OPAPolicyConfig{
+				{Name: "count", Path: "nomad/count", EnforcementLevel: config.OPAEnforcementSoftMandatory},
+				{Name: "team", Path: "nomad/team", EnforcementLevel: config.OPAEnforcementAdvisory},
+			},
+		}
+	})
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	job := mock.Job()
+	require.NoError(state.UpsertJob(1000, job))
+
+	// Scaling within the policies is allowed, with the advisory warning
+	scale := &structs.JobScaleRequest{
+		JobID:  job.ID,
+		Target: map[string]string{structs.ScalingTargetGroup: job.TaskGroups[0].Name},
+		Count:  helper.Int64ToPtr(5),
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var resp structs.JobRegisterResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Scale", scale, &resp))
+	require.Contains(resp.Warnings, `advisory policy "team" failed`)
+
+	// Scaling beyond the policies is rejected
+	scale.Count = helper.Int64ToPtr(20)
+	err := msgpackrpc.CallWithCodec(codec, "Job.Scale", scale, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), `soft-mandatory policy "count" failed`)
+
+	out, err := state.JobByID(nil, job.Namespace, job.ID)
+	require.NoError(err)
+	require.Equal(5, out.TaskGroups[0].Count)
+}
+
+func TestServer_EnforceOPAPolicies(t *testing.T) {
+	t.Parallel()
+
+	opa := testOPAServer(t)
+	defer opa.Close()
+
+	s := &Server{
+		config: &Config{OPAConfig: &config.OPAConfig{
+			Address: opa.URL,
+			Policies: []*config.OPAPolicyConfig{
+				{Name: "undefined", Path: "nomad/undefined"},
+			},
+		}},
+		logger: testlog.HCLogger(t),
+	}
+	require.NoError(t, s.setupOPA())
+
+	// Undefined rules fail the hard-mandatory policies, even overridden
+	warnings, failures := s.enforceOPAPolicies(true, mock.Job())
+	require.Error(t, failures)
+	require.Contains(t, failures.Error(), "policy rule is undefined")
+	require.NoError(t, warnings)
+}
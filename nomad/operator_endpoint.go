@@ -1,15 +1,29 @@
 package nomad
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"math/rand"
 	"net"
+	"time"
 
+	metrics "github.com/armon/go-metrics"
 	log "github.com/hashicorp/go-hclog"
 
 	"github.com/hashicorp/consul/agent/consul/autopilot"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/helper/snapshot"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/raft"
 	"github.com/hashicorp/serf/serf"
+	"github.com/ugorji/go/codec"
+)
+
+const (
+	// reassertLeaderTimeout is the time to wait for the leadership to be
+	// reasserted after restoring a snapshot.
+	reassertLeaderTimeout = time.Minute
 )
 
 // Operator endpoint is used to perform low-level operator tasks for Nomad.
@@ -18,6 +32,11 @@ type Operator struct {
 	logger log.Logger
 }
 
+func (op *Operator) register() {
+	op.srv.streamingRpcs.Register("Operator.SnapshotSave", op.snapshotSave)
+	op.srv.streamingRpcs.Register("Operator.SnapshotRestore", op.snapshotRestore)
+}
+
 // RaftGetConfiguration is used to retrieve the current Raft configuration.
 func (op *Operator) RaftGetConfiguration(args *structs.GenericRequest, reply *structs.RaftConfigurationResponse) error {
 	if done, err := op.srv.forward("Operator.RaftGetConfiguration", args, args, reply); done {
@@ -299,6 +318,11 @@ func (op *Operator) SchedulerSetConfiguration(args *structs.SchedulerSetConfigRe
 		return structs.ErrPermissionDenied
 	}
 
+	// Validate the configuration
+	if err := args.Config.Validate(); err != nil {
+		return fmt.Errorf("invalid scheduler configuration: %v", err)
+	}
+
 	// Apply the update
 	resp, index, err := op.srv.raftApply(structs.SchedulerConfigRequestType, args)
 	if err != nil {
@@ -346,3 +370,293 @@ func (op *Operator) SchedulerGetConfiguration(args *structs.GenericRequest, repl
 
 	return nil
 }
+
+// EvalBrokerStats is used to retrieve the stats of the eval broker of the
+// leader, which is the only server with an enabled broker.
+func (op *Operator) EvalBrokerStats(args *structs.GenericRequest, reply *structs.EvalBrokerStatsResponse) error {
+	// The broker is only enabled on the leader
+	args.AllowStale = false
+	if done, err := op.srv.forward("Operator.EvalBrokerStats", args, args, reply); done {
+		return err
+	}
+
+	// This action requires operator read access.
+	rule, err := op.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	} else if rule != nil && !rule.AllowOperatorRead() {
+		return structs.ErrPermissionDenied
+	}
+
+	if !op.srv.evalBroker.Enabled() {
+		return fmt.Errorf("eval broker disabled")
+	}
+
+	stats := op.srv.evalBroker.Stats()
+	reply.Stats = &structs.EvalBrokerStats{
+		TotalReady:                   stats.TotalReady,
+		TotalUnacked:                 stats.TotalUnacked,
+		TotalBlocked:                 stats.TotalBlocked,
+		TotalWaiting:                 stats.TotalWaiting,
+		ByScheduler:                  make(map[string]*structs.EvalBrokerSchedulerStats, len(stats.ByScheduler)),
+		NackTimeout:                  op.srv.config.EvalNackTimeout,
+		DeliveryLimit:                op.srv.config.EvalDeliveryLimit,
+		InitialNackReenqueueDelay:    op.srv.config.EvalNackInitialReenqueueDelay,
+		SubsequentNackReenqueueDelay: op.srv.config.EvalNackSubsequentReenqueueDelay,
+	}
+	for sched, schedStats := range stats.ByScheduler {
+		reply.Stats.ByScheduler[sched] = &structs.EvalBrokerSchedulerStats{
+			Ready:           schedStats.Ready,
+			Unacked:         schedStats.Unacked,
+			Nacked:          schedStats.Nacked,
+			OldestReadyWait: schedStats.OldestReadyWait,
+		}
+	}
+	op.srv.setQueryMeta(&reply.QueryMeta)
+	return nil
+}
+
+// snapshotSave streams a snapshot of the state of the cluster taken by the
+// leader. The stream starts with a SnapshotSaveResponse header followed by the
+// snapshot archive.
+func (op *Operator) snapshotSave(conn io.ReadWriteCloser) {
+	defer conn.Close()
+	defer metrics.MeasureSince([]string{"nomad", "operator", "snapshot_save"}, time.Now())
+
+	var args structs.SnapshotSaveRequest
+	var reply structs.SnapshotSaveResponse
+	decoder := codec.NewDecoder(conn, structs.MsgpackHandle)
+	encoder := codec.NewEncoder(conn, structs.MsgpackHandle)
+
+	handleFailure := func(code int, err error) {
+		encoder.Encode(&structs.SnapshotSaveResponse{
+			ErrorCode: code,
+			ErrorMsg:  err.Error(),
+		})
+	}
+
+	if err := decoder.Decode(&args); err != nil {
+		handleFailure(500, err)
+		return
+	}
+
+	// Forward to the leader of the region
+	if server, err := op.streamingRpcServer(args.RequestRegion(), args.AllowStale); err != nil {
+		handleFailure(500, err)
+		return
+	} else if server != nil {
+		if err := op.forwardStreamingRpc(server, "Operator.SnapshotSave", args, conn); err != nil {
+			handleFailure(500, err)
+		}
+		return
+	}
+
+	// This action requires management permissions
+	if aclObj, err := op.srv.ResolveToken(args.AuthToken); err != nil {
+		code := 500
+		if err == structs.ErrTokenNotFound {
+			code = 400
+		}
+		handleFailure(code, err)
+		return
+	} else if aclObj != nil && !aclObj.IsManagement() {
+		handleFailure(403, structs.ErrPermissionDenied)
+		return
+	}
+
+	// Take the snapshot
+	snap, err := snapshot.New(op.logger.Named("snapshot"), op.srv.raft)
+	if err != nil {
+		handleFailure(500, err)
+		return
+	}
+	defer snap.Close()
+
+	op.srv.setQueryMeta(&reply.QueryMeta)
+	reply.Index = snap.Index()
+	reply.SnapshotChecksum = snap.Checksum()
+
+	// Send the header and then the archive
+	if err := encoder.Encode(&reply); err != nil {
+		op.logger.Error("failed to send snapshot header", "error", err)
+		return
+	}
+	if _, err := io.Copy(conn, snap); err != nil {
+		op.logger.Error("failed to stream snapshot", "error", err)
+	}
+}
+
+// snapshotRestore restores a snapshot of the state of the cluster on the
+// leader. The request is followed by the snapshot archive sent in chunks as
+// StreamErrWrapper payloads, the end of the archive being marked by an EOF
+// error. The result is sent back as a SnapshotRestoreResponse.
+func (op *Operator) snapshotRestore(conn io.ReadWriteCloser) {
+	defer conn.Close()
+	defer metrics.MeasureSince([]string{"nomad", "operator", "snapshot_restore"}, time.Now())
+
+	var args structs.SnapshotRestoreRequest
+	var reply structs.SnapshotRestoreResponse
+	decoder := codec.NewDecoder(conn, structs.MsgpackHandle)
+	encoder := codec.NewEncoder(conn, structs.MsgpackHandle)
+
+	handleFailure := func(code int, err error) {
+		encoder.Encode(&structs.SnapshotRestoreResponse{
+			ErrorCode: code,
+			ErrorMsg:  err.Error(),
+		})
+	}
+
+	if err := decoder.Decode(&args); err != nil {
+		handleFailure(500, err)
+		return
+	}
+
+	// Forward to the leader of the region
+	if server, err := op.streamingRpcServer(args.RequestRegion(), false); err != nil {
+		handleFailure(500, err)
+		return
+	} else if server != nil {
+		if err := op.forwardStreamingRpc(server, "Operator.SnapshotRestore", args, conn); err != nil {
+			handleFailure(500, err)
+		}
+		return
+	}
+
+	// This action requires management permissions
+	if aclObj, err := op.srv.ResolveToken(args.AuthToken); err != nil {
+		code := 500
+		if err == structs.ErrTokenNotFound {
+			code = 400
+		}
+		handleFailure(code, err)
+		return
+	} else if aclObj != nil && !aclObj.IsManagement() {
+		handleFailure(403, structs.ErrPermissionDenied)
+		return
+	}
+
+	// Copy the chunks of the archive into a pipe read by the restore
+	snapReader, snapWriter := io.Pipe()
+	errCh := make(chan error, 1)
+	go func() {
+		defer snapWriter.Close()
+		for {
+			var wrapper cstructs.StreamErrWrapper
+			if err := decoder.Decode(&wrapper); err != nil {
+				snapWriter.CloseWithError(err)
+				errCh <- err
+				return
+			}
+
+			if len(wrapper.Payload) != 0 {
+				if _, err := snapWriter.Write(wrapper.Payload); err != nil {
+					errCh <- err
+					return
+				}
+			}
+
+			if wrapper.Error != nil {
+				if wrapper.Error.Message == io.EOF.Error() {
+					errCh <- nil
+				} else {
+					err := errors.New(wrapper.Error.Message)
+					snapWriter.CloseWithError(err)
+					errCh <- err
+				}
+				return
+			}
+		}
+	}()
+
+	err := snapshot.Restore(op.logger.Named("snapshot"), snapReader, op.srv.raft)
+	snapReader.Close()
+	if readErr := <-errCh; readErr != nil && err == nil {
+		err = readErr
+	}
+	if err != nil {
+		handleFailure(500, fmt.Errorf("failed to restore snapshot: %v", err))
+		return
+	}
+
+	// Reassert the leadership so that the leader state, like the evaluation
+	// broker and the periodic dispatcher, is rebuilt from the restored state
+	if err := op.reassertLeader(); err != nil {
+		handleFailure(500, err)
+		return
+	}
+
+	op.srv.setQueryMeta(&reply.QueryMeta)
+	reply.Index, _ = op.srv.State().LatestIndex()
+	encoder.Encode(&reply)
+}
+
+// reassertLeader asks the leader loop to reestablish the leadership of the
+// server.
+func (op *Operator) reassertLeader() error {
+	timeout := time.After(reassertLeaderTimeout)
+	errCh := make(chan error, 1)
+	select {
+	case op.srv.reassertLeaderCh <- errCh:
+	case <-timeout:
+		return fmt.Errorf("timed out reasserting leadership")
+	case <-op.srv.shutdownCh:
+		return fmt.Errorf("server shutting down")
+	}
+
+	select {
+	case err := <-errCh:
+		if err != nil {
+			return fmt.Errorf("failed to reassert leadership: %v", err)
+		}
+		return nil
+	case <-timeout:
+		return fmt.Errorf("timed out reasserting leadership")
+	}
+}
+
+// streamingRpcServer returns the server a streaming RPC of the region should
+// be forwarded to, either a server of another region or the leader of the
+// region. It returns nil if the RPC should be handled by this server.
+func (op *Operator) streamingRpcServer(region string, allowStale bool) (*serverParts, error) {
+	if region != op.srv.Region() {
+		op.srv.peerLock.RLock()
+		defer op.srv.peerLock.RUnlock()
+		servers := op.srv.peers[region]
+		if len(servers) == 0 {
+			return nil, structs.ErrNoRegionPath
+		}
+		return servers[rand.Intn(len(servers))], nil
+	}
+
+	if allowStale {
+		return nil, nil
+	}
+
+	isLeader, leader := op.srv.getLeader()
+	if isLeader {
+		return nil, nil
+	}
+	if leader == nil {
+		return nil, structs.ErrNoLeader
+	}
+	return leader, nil
+}
+
+// forwardStreamingRpc forwards the streaming RPC to the server, bridging the
+// connection to it.
+func (op *Operator) forwardStreamingRpc(server *serverParts, method string, args interface{}, conn io.ReadWriteCloser) error {
+	srvConn, err := op.srv.streamingRpc(server, method)
+	if err != nil {
+		return err
+	}
+	defer srvConn.Close()
+
+	// Send the request
+	outEncoder := codec.NewEncoder(srvConn, structs.MsgpackHandle)
+	if err := outEncoder.Encode(args); err != nil {
+		return err
+	}
+
+	structs.Bridge(conn, srvConn)
+	return nil
+}
@@ -1,7 +1,11 @@
 package nomad
 
 import (
+	"bytes"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
 	"reflect"
 	"strings"
 	"testing"
@@ -9,12 +13,15 @@ import (
 	"github.com/hashicorp/consul/lib/freeport"
 	"github.com/hashicorp/net-rpc-msgpackrpc"
 	"github.com/hashicorp/nomad/acl"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/helper/snapshot"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/testutil"
 	"github.com/hashicorp/raft"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/ugorji/go/codec"
 )
 
 func TestOperator_RaftGetConfiguration(t *testing.T) {
@@ -393,6 +400,89 @@ func TestOperator_SchedulerSetConfiguration(t *testing.T) {
 
 	require.NotZero(reply.Index)
 	require.False(reply.SchedulerConfig.PreemptionConfig.SystemSchedulerEnabled)
+
+	// Set the spread algorithm
+	arg.Config.SchedulerAlgorithm = structs.SchedulerAlgorithmSpread
+	err = msgpackrpc.CallWithCodec(codec, "Operator.SchedulerSetConfiguration", &arg, &setResponse)
+	require.Nil(err)
+	require.Nil(msgpackrpc.CallWithCodec(codec, "Operator.SchedulerGetConfiguration", &readConfig, &reply))
+	require.Equal(structs.SchedulerAlgorithmSpread, reply.SchedulerConfig.SchedulerAlgorithm)
+
+	// An unknown algorithm is rejected
+	arg.Config.SchedulerAlgorithm = "random"
+	err = msgpackrpc.CallWithCodec(codec, "Operator.SchedulerSetConfiguration", &arg, &setResponse)
+	require.Error(err)
+	require.Contains(err.Error(), "invalid scheduler algorithm")
+}
+
+func TestOperator_SchedulerSetConfiguration_Runtime(t *testing.T) {
+	t.Parallel()
+	s1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 2
+	})
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	require := require.New(t)
+
+	numWorkers := func() int {
+		s1.workerLock.Lock()
+		defer s1.workerLock.Unlock()
+		return len(s1.workers)
+	}
+	require.Equal(2, numWorkers())
+
+	// Grow the worker pool and disable eval priorities
+	arg := structs.SchedulerSetConfigRequest{
+		Config: structs.SchedulerConfiguration{
+			NumSchedulers:       5,
+			DisableEvalPriority: true,
+		},
+	}
+	arg.Region = s1.config.Region
+
+	var setResponse structs.SchedulerSetConfigurationResponse
+	require.Nil(msgpackrpc.CallWithCodec(codec, "Operator.SchedulerSetConfiguration", &arg, &setResponse))
+
+	testutil.WaitForResult(func() (bool, error) {
+		if n := numWorkers(); n != 5 {
+			return false, fmt.Errorf("expected 5 workers, got %d", n)
+		}
+		s1.evalBroker.l.RLock()
+		defer s1.evalBroker.l.RUnlock()
+		if !s1.evalBroker.disablePriority {
+			return false, fmt.Errorf("expected eval priorities to be disabled")
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatal(err)
+	})
+
+	// Resetting the override shrinks the pool back to the configured size
+	arg.Config.NumSchedulers = 0
+	arg.Config.DisableEvalPriority = false
+	require.Nil(msgpackrpc.CallWithCodec(codec, "Operator.SchedulerSetConfiguration", &arg, &setResponse))
+
+	testutil.WaitForResult(func() (bool, error) {
+		if n := numWorkers(); n != 2 {
+			return false, fmt.Errorf("expected 2 workers, got %d", n)
+		}
+		s1.evalBroker.l.RLock()
+		defer s1.evalBroker.l.RUnlock()
+		if s1.evalBroker.disablePriority {
+			return false, fmt.Errorf("expected eval priorities to be enabled")
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatal(err)
+	})
+
+	// A negative number of schedulers is rejected
+	arg.Config.NumSchedulers = -1
+	err := msgpackrpc.CallWithCodec(codec, "Operator.SchedulerSetConfiguration", &arg, &setResponse)
+	require.Error(err)
+	require.Contains(err.Error(), "invalid num schedulers")
 }
 
 func TestOperator_SchedulerGetConfiguration_ACL(t *testing.T) {
@@ -440,6 +530,39 @@ func TestOperator_SchedulerGetConfiguration_ACL(t *testing.T) {
 
 }
 
+func TestOperator_EvalBrokerStats(t *testing.T) {
+	t.Parallel()
+	s1, root := TestACLServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+		c.EvalDeliveryLimit = 5
+	})
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	require := require.New(t)
+
+	eval := mock.Eval()
+	s1.evalBroker.Enqueue(eval)
+
+	arg := structs.GenericRequest{
+		QueryOptions: structs.QueryOptions{
+			Region: s1.config.Region,
+		},
+	}
+
+	// Reading the stats requires operator read access
+	var reply structs.EvalBrokerStatsResponse
+	err := msgpackrpc.CallWithCodec(codec, "Operator.EvalBrokerStats", &arg, &reply)
+	require.EqualError(err, structs.ErrPermissionDenied.Error())
+
+	arg.AuthToken = root.SecretID
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Operator.EvalBrokerStats", &arg, &reply))
+	require.Equal(1, reply.Stats.TotalReady)
+	require.Equal(1, reply.Stats.ByScheduler[eval.Type].Ready)
+	require.Equal(5, reply.Stats.DeliveryLimit)
+	require.Equal(s1.config.EvalNackTimeout, reply.Stats.NackTimeout)
+}
+
 func TestOperator_SchedulerSetConfiguration_ACL(t *testing.T) {
 	t.Parallel()
 	s1, root := TestACLServer(t, func(c *Config) {
@@ -488,3 +611,165 @@ func TestOperator_SchedulerSetConfiguration_ACL(t *testing.T) {
 	}
 
 }
+
+// snapshotSave takes a snapshot with the Operator.SnapshotSave handler of the
+// server and returns the header of the response and the snapshot archive.
+func snapshotSave(t *testing.T, s *Server, args *structs.SnapshotSaveRequest) (*structs.SnapshotSaveResponse, []byte) {
+	handler, err := s.StreamingRpcHandler("Operator.SnapshotSave")
+	require.NoError(t, err)
+
+	p1, p2 := net.Pipe()
+	defer p1.Close()
+	go handler(p2)
+
+	encoder := codec.NewEncoder(p1, structs.MsgpackHandle)
+	decoder := codec.NewDecoder(p1, structs.MsgpackHandle)
+	require.NoError(t, encoder.Encode(args))
+
+	var resp structs.SnapshotSaveResponse
+	require.NoError(t, decoder.Decode(&resp))
+	if resp.ErrorMsg != "" {
+		return &resp, nil
+	}
+
+	archive, err := ioutil.ReadAll(p1)
+	require.NoError(t, err)
+	return &resp, archive
+}
+
+// snapshotRestore restores the snapshot archive with the
+// Operator.SnapshotRestore handler of the server.
+func snapshotRestore(t *testing.T, s *Server, args *structs.SnapshotRestoreRequest, archive []byte) *structs.SnapshotRestoreResponse {
+	handler, err := s.StreamingRpcHandler("Operator.SnapshotRestore")
+	require.NoError(t, err)
+
+	p1, p2 := net.Pipe()
+	defer p1.Close()
+	go handler(p2)
+
+	encoder := codec.NewEncoder(p1, structs.MsgpackHandle)
+	decoder := codec.NewDecoder(p1, structs.MsgpackHandle)
+	require.NoError(t, encoder.Encode(args))
+
+	// Send the archive in chunks followed by an EOF
+	go func() {
+		in := bytes.NewReader(archive)
+		buf := make([]byte, 1024)
+		for {
+			n, err := in.Read(buf)
+			if n > 0 {
+				if err := encoder.Encode(&cstructs.StreamErrWrapper{Payload: buf[:n]}); err != nil {
+					return
+				}
+			}
+			if err == io.EOF {
+				encoder.Encode(&cstructs.StreamErrWrapper{Error: cstructs.NewRpcError(err, nil)})
+				return
+			}
+		}
+	}()
+
+	var resp structs.SnapshotRestoreResponse
+	require.NoError(t, decoder.Decode(&resp))
+	return &resp
+}
+
+func TestOperator_SnapshotSaveRestore(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Register a job
+	job := mock.Job()
+	req := &structs.JobRegisterRequest{
+		Job:          job,
+		WriteRequest: structs.WriteRequest{Region: "global", Namespace: job.Namespace},
+	}
+	var resp structs.JobRegisterResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp))
+
+	// Take a snapshot
+	saveResp, archive := snapshotSave(t, s1, &structs.SnapshotSaveRequest{
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	})
+	require.Empty(saveResp.ErrorMsg)
+	require.NotZero(saveResp.Index)
+	require.True(saveResp.KnownLeader)
+	require.Contains(saveResp.SnapshotChecksum, "sha-256=")
+
+	meta, err := snapshot.Verify(bytes.NewReader(archive))
+	require.NoError(err)
+	require.Equal(saveResp.Index, meta.Index)
+
+	// Register a second job after the snapshot
+	job2 := mock.Job()
+	req2 := &structs.JobRegisterRequest{
+		Job:          job2,
+		WriteRequest: structs.WriteRequest{Region: "global", Namespace: job2.Namespace},
+	}
+	var resp2 structs.JobRegisterResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Register", req2, &resp2))
+
+	// Restore the snapshot
+	restoreResp := snapshotRestore(t, s1, &structs.SnapshotRestoreRequest{
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}, archive)
+	require.Empty(restoreResp.ErrorMsg)
+	require.NotZero(restoreResp.Index)
+
+	// Only the first job is in the restored state
+	state := s1.fsm.State()
+	out, err := state.JobByID(nil, job.Namespace, job.ID)
+	require.NoError(err)
+	require.NotNil(out)
+	out, err = state.JobByID(nil, job2.Namespace, job2.ID)
+	require.NoError(err)
+	require.Nil(out)
+
+	// The server is still the leader and accepts writes
+	testutil.WaitForLeader(t, s1.RPC)
+	var resp3 structs.JobRegisterResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Register", req2, &resp3))
+	require.True(resp3.Index > restoreResp.Index)
+
+	// A corrupted snapshot fails to restore
+	restoreResp = snapshotRestore(t, s1, &structs.SnapshotRestoreRequest{
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}, archive[:len(archive)/2])
+	require.Equal(500, restoreResp.ErrorCode)
+	require.Contains(restoreResp.ErrorMsg, "failed to restore snapshot")
+}
+
+func TestOperator_SnapshotSaveRestore_ACL(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1, root := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	// Create a token with operator permissions only
+	token := mock.CreatePolicyAndToken(t, state, 1001, "operator", `operator { policy = "write" }`)
+
+	// Non management tokens are denied
+	saveResp, _ := snapshotSave(t, s1, &structs.SnapshotSaveRequest{
+		QueryOptions: structs.QueryOptions{Region: "global", AuthToken: token.SecretID},
+	})
+	require.Equal(403, saveResp.ErrorCode)
+	require.Equal(structs.ErrPermissionDenied.Error(), saveResp.ErrorMsg)
+
+	restoreResp := snapshotRestore(t, s1, &structs.SnapshotRestoreRequest{
+		WriteRequest: structs.WriteRequest{Region: "global", AuthToken: token.SecretID},
+	}, []byte("foo"))
+	require.Equal(403, restoreResp.ErrorCode)
+
+	// Management tokens are allowed
+	saveResp, archive := snapshotSave(t, s1, &structs.SnapshotSaveRequest{
+		QueryOptions: structs.QueryOptions{Region: "global", AuthToken: root.SecretID},
+	})
+	require.Empty(saveResp.ErrorMsg)
+	require.NotEmpty(archive)
+}
@@ -0,0 +1,91 @@
+package nomad
+
+import (
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/helper/filter"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// paginator is used to page through the objects of a state store iterator,
+// filtering them with the filter expression of the query. The iterator must
+// return the objects ordered by their token, such as the ID indexes do.
+type paginator struct {
+	iter memdb.ResultIterator
+
+	// filter is the parsed filter expression, if any
+	filter *filter.Filter
+
+	perPage   int32
+	nextToken string
+
+	// tokenFn returns the token of a raw object of the iterator
+	tokenFn func(raw interface{}) string
+
+	// convertFn converts a raw object of the iterator to the object returned
+	// by the list, which is the object the filter is evaluated against.
+	convertFn func(raw interface{}) (interface{}, error)
+}
+
+// newPaginator returns a paginator for the iterator, using the filter and
+// pagination parameters of the query options.
+func newPaginator(iter memdb.ResultIterator, opts structs.QueryOptions,
+	tokenFn func(raw interface{}) string,
+	convertFn func(raw interface{}) (interface{}, error)) (*paginator, error) {
+
+	p := &paginator{
+		iter:      iter,
+		perPage:   opts.PerPage,
+		nextToken: opts.NextToken,
+		tokenFn:   tokenFn,
+		convertFn: convertFn,
+	}
+
+	if opts.Filter != "" {
+		f, err := filter.New(opts.Filter)
+		if err != nil {
+			return nil, structs.NewErrInvalidFilter(err)
+		}
+		p.filter = f
+	}
+	return p, nil
+}
+
+// Page calls the append function with the objects of the page, and returns
+// the token of the next page, which is empty on the last page.
+func (p *paginator) Page(appendFn func(obj interface{})) (string, error) {
+	var count int32
+	for {
+		raw := p.iter.Next()
+		if raw == nil {
+			return "", nil
+		}
+
+		token := p.tokenFn(raw)
+		if token < p.nextToken {
+			continue
+		}
+
+		obj, err := p.convertFn(raw)
+		if err != nil {
+			return "", err
+		}
+
+		if p.filter != nil {
+			match, err := p.filter.Evaluate(obj)
+			if err != nil {
+				return "", structs.NewErrInvalidFilter(err)
+			}
+			if !match {
+				continue
+			}
+		}
+
+		// The first matching object past the page starts the next one
+		if p.perPage > 0 && count == p.perPage {
+			return token, nil
+		}
+
+		appendFn(obj)
+		count++
+	}
+}
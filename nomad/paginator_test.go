@@ -0,0 +1,92 @@
+package nomad
+
+import (
+	"fmt"
+	"testing"
+
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaginator(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	// The IDs of the evaluations end with their letter so they sort by it
+	id := func(c string) string {
+		return fmt.Sprintf("00000000-0000-0000-0000-00000000000%s", c)
+	}
+
+	s := state.TestStateStore(t)
+	for i, c := range []string{"a", "b", "c", "d", "e"} {
+		eval := mock.Eval()
+		eval.ID = id(c)
+		if c == "c" {
+			eval.Status = structs.EvalStatusFailed
+		}
+		require.NoError(s.UpsertEvals(uint64(1000+i), []*structs.Evaluation{eval}))
+	}
+
+	page := func(opts structs.QueryOptions) ([]string, string, error) {
+		iter, err := s.EvalsByNamespace(memdb.NewWatchSet(), structs.DefaultNamespace)
+		require.NoError(err)
+
+		p, err := newPaginator(iter, opts,
+			func(raw interface{}) string {
+				return raw.(*structs.Evaluation).ID
+			},
+			func(raw interface{}) (interface{}, error) {
+				return raw, nil
+			})
+		if err != nil {
+			return nil, "", err
+		}
+
+		var ids []string
+		next, err := p.Page(func(obj interface{}) {
+			ids = append(ids, obj.(*structs.Evaluation).ID[35:])
+		})
+		return ids, next, err
+	}
+
+	// All the objects without pagination
+	ids, next, err := page(structs.QueryOptions{})
+	require.NoError(err)
+	require.Equal([]string{"a", "b", "c", "d", "e"}, ids)
+	require.Empty(next)
+
+	// Page through the objects
+	ids, next, err = page(structs.QueryOptions{PerPage: 2})
+	require.NoError(err)
+	require.Equal([]string{"a", "b"}, ids)
+	require.Equal(id("c"), next)
+
+	ids, next, err = page(structs.QueryOptions{PerPage: 2, NextToken: next})
+	require.NoError(err)
+	require.Equal([]string{"c", "d"}, ids)
+	require.Equal(id("e"), next)
+
+	ids, next, err = page(structs.QueryOptions{PerPage: 2, NextToken: next})
+	require.NoError(err)
+	require.Equal([]string{"e"}, ids)
+	require.Empty(next)
+
+	// The next token skips the objects that don't match the filter
+	ids, next, err = page(structs.QueryOptions{
+		PerPage: 2,
+		Filter:  `Status != "failed"`,
+	})
+	require.NoError(err)
+	require.Equal([]string{"a", "b"}, ids)
+	require.Equal(id("d"), next)
+
+	// Invalid filters
+	_, _, err = page(structs.QueryOptions{Filter: `Status ==`})
+	require.True(structs.IsErrInvalidFilter(err))
+
+	_, _, err = page(structs.QueryOptions{Filter: `Unknown == "foo"`})
+	require.True(structs.IsErrInvalidFilter(err))
+}
@@ -3,6 +3,7 @@ package nomad
 import (
 	"fmt"
 	"runtime"
+	"sync"
 	"time"
 
 	"github.com/armon/go-metrics"
@@ -67,6 +68,12 @@ func newPlanner(s *Server) (*planner, error) {
 // the Raft log is updated. This means our schedulers will stall,
 // but there are many of those and only a single plan verifier.
 //
+// To raise throughput further, plans waiting in the queue that are
+// compatible with the dequeued plan are batched with it. Compatible plans
+// touch disjoint sets of nodes and quotas, so they are verified in parallel
+// against the same snapshot and their Raft transactions are dispatched back
+// to back.
+//
 func (p *planner) planApply() {
 	// waitCh is used to track an outstanding application while snap
 	// holds an optimistic state which includes that plan application.
@@ -107,17 +114,33 @@ func (p *planner) planApply() {
 			}
 		}
 
-		// Evaluate the plan
-		result, err := evaluatePlan(pool, snap, pending.plan, p.logger)
-		if err != nil {
-			p.logger.Error("failed to evaluate plan", "error", err)
-			pending.respond(nil, err)
-			continue
+		// Batch the waiting plans that are compatible with the plan
+		batch := []*pendingPlan{pending}
+		if size := p.config.PlanBatchSize; size > 1 {
+			compatible := newPlanBatch(snap)
+			compatible.add(pending.plan)
+			batch = append(batch, p.planQueue.DequeueCompatible(size-1, compatible.add)...)
+			metrics.AddSample([]string{"nomad", "plan", "batch_size"}, float32(len(batch)))
 		}
 
+		// Evaluate the plans
+		results, errs := evaluatePlans(pool, snap, batch, p.logger)
+
 		// Fast-path the response if there is nothing to do
-		if result.IsNoOp() {
-			pending.respond(result, nil)
+		var apply []int
+		for i, pending := range batch {
+			if err := errs[i]; err != nil {
+				p.logger.Error("failed to evaluate plan", "error", err)
+				pending.respond(nil, err)
+				continue
+			}
+			if results[i].IsNoOp() {
+				pending.respond(results[i], nil)
+				continue
+			}
+			apply = append(apply, i)
+		}
+		if len(apply) == 0 {
 			continue
 		}
 
@@ -128,23 +151,112 @@ func (p *planner) planApply() {
 			snap, err = p.fsm.State().Snapshot()
 			if err != nil {
 				p.logger.Error("failed to snapshot state", "error", err)
-				pending.respond(nil, err)
+				for _, i := range apply {
+					batch[i].respond(nil, err)
+				}
 				continue
 			}
 		}
 
-		// Dispatch the Raft transaction for the plan
-		future, err := p.applyPlan(pending.plan, result, snap)
+		// Dispatch the Raft transactions for the plans. The plans of a batch
+		// are compatible so there is no need to wait for the previous one to
+		// apply.
+		var planWaitChs []chan struct{}
+		for _, i := range apply {
+			future, err := p.applyPlan(batch[i].plan, results[i], snap)
+			if err != nil {
+				p.logger.Error("failed to submit plan", "error", err)
+				batch[i].respond(nil, err)
+				continue
+			}
+
+			// Respond to the plan in async
+			planWaitCh := make(chan struct{})
+			planWaitChs = append(planWaitChs, planWaitCh)
+			go p.asyncPlanWait(planWaitCh, future, results[i], batch[i])
+		}
+		if len(planWaitChs) != 0 {
+			waitCh = waitAll(planWaitChs)
+		}
+	}
+}
+
+// waitAll returns a channel that is closed once all the given channels are
+// closed.
+func waitAll(chs []chan struct{}) chan struct{} {
+	if len(chs) == 1 {
+		return chs[0]
+	}
+
+	doneCh := make(chan struct{})
+	go func() {
+		defer close(doneCh)
+		for _, ch := range chs {
+			<-ch
+		}
+	}()
+	return doneCh
+}
+
+// planBatch tracks the nodes and quotas touched by the plans of a batch so
+// that only compatible plans are added to it. Plans are compatible if they
+// touch disjoint sets of nodes and do not account against the same quota, so
+// the evaluation of one plan does not depend on the others.
+type planBatch struct {
+	snap   *state.StateSnapshot
+	nodes  map[string]struct{}
+	quotas map[string]struct{}
+}
+
+// newPlanBatch returns an empty plan batch evaluated against the snapshot.
+func newPlanBatch(snap *state.StateSnapshot) *planBatch {
+	return &planBatch{
+		snap:   snap,
+		nodes:  make(map[string]struct{}),
+		quotas: make(map[string]struct{}),
+	}
+}
+
+// add adds the plan to the batch and returns true if it is compatible with
+// the plans already in the batch.
+func (b *planBatch) add(plan *structs.Plan) bool {
+	nodes := make(map[string]struct{}, len(plan.NodeUpdate)+len(plan.NodeAllocation))
+	for nodeID := range plan.NodeUpdate {
+		nodes[nodeID] = struct{}{}
+	}
+	for nodeID := range plan.NodeAllocation {
+		nodes[nodeID] = struct{}{}
+	}
+	for nodeID := range plan.NodePreemptions {
+		nodes[nodeID] = struct{}{}
+	}
+	for nodeID := range nodes {
+		if _, ok := b.nodes[nodeID]; ok {
+			return false
+		}
+	}
+
+	var quota string
+	if plan.Job != nil {
+		ns, err := b.snap.NamespaceByName(nil, plan.Job.Namespace)
 		if err != nil {
-			p.logger.Error("failed to submit plan", "error", err)
-			pending.respond(nil, err)
-			continue
+			return false
+		}
+		if ns != nil && ns.Quota != "" {
+			quota = ns.Quota
+			if _, ok := b.quotas[quota]; ok {
+				return false
+			}
 		}
+	}
 
-		// Respond to the plan in async
-		waitCh = make(chan struct{})
-		go p.asyncPlanWait(waitCh, future, result, pending)
+	for nodeID := range nodes {
+		b.nodes[nodeID] = struct{}{}
+	}
+	if quota != "" {
+		b.quotas[quota] = struct{}{}
 	}
+	return true
 }
 
 // applyPlan is used to apply the plan result and to return the alloc index
@@ -258,6 +370,28 @@ func (p *planner) asyncPlanWait(waitCh chan struct{}, future raft.ApplyFuture,
 	pending.respond(result, nil)
 }
 
+// evaluatePlans evaluates the plans of a batch in parallel against the same
+// snapshot and returns the result or error of each plan.
+func evaluatePlans(pool *EvaluatePool, snap *state.StateSnapshot, batch []*pendingPlan, logger log.Logger) ([]*structs.PlanResult, []error) {
+	results := make([]*structs.PlanResult, len(batch))
+	errs := make([]error, len(batch))
+	if len(batch) == 1 {
+		results[0], errs[0] = evaluatePlan(pool, snap, batch[0].plan, logger)
+		return results, errs
+	}
+
+	var wg sync.WaitGroup
+	for i, pending := range batch {
+		wg.Add(1)
+		go func(i int, plan *structs.Plan) {
+			defer wg.Done()
+			results[i], errs[i] = evaluatePlan(pool, snap, plan, logger)
+		}(i, pending.plan)
+	}
+	wg.Wait()
+	return results, errs
+}
+
 // evaluatePlan is used to determine what portions of a plan
 // can be applied if any. Returns if there should be a plan application
 // which may be partial or if there was an error
@@ -284,6 +418,133 @@ func evaluatePlan(pool *EvaluatePool, snap *state.StateSnapshot, plan *structs.P
 	return evaluatePlanPlacements(pool, snap, plan, logger)
 }
 
+// refreshIndex returns the index the scheduler should refresh to as the maximum
+// of the allocation, node, namespace and quota spec tables.
+func refreshIndex(snap *state.StateSnapshot) (uint64, error) {
+	var indexes []uint64
+	for _, table := range []string{"allocs", "nodes", "namespaces", "quota_specs"} {
+		index, err := snap.Index(table)
+		if err != nil {
+			return 0, err
+		}
+		indexes = append(indexes, index)
+	}
+	return maxUint64(indexes...), nil
+}
+
+// evaluatePlanQuota returns whether the plan would be over the quota attached
+// to the namespace of the job. Plans that don't increase the usage of an
+// exceeded dimension are allowed so that over quota namespaces can still
+// stop allocations.
+func evaluatePlanQuota(snap *state.StateSnapshot, plan *structs.Plan) (bool, error) {
+	if plan.Job == nil {
+		return false, nil
+	}
+
+	ns, err := snap.NamespaceByName(nil, plan.Job.Namespace)
+	if err != nil {
+		return false, err
+	}
+	if ns == nil || ns.Quota == "" {
+		return false, nil
+	}
+
+	spec, err := snap.QuotaSpecByName(nil, ns.Quota)
+	if err != nil {
+		return false, err
+	}
+	if spec == nil {
+		return false, nil
+	}
+	limit := spec.RegionLimit(plan.Job.Region)
+	if limit == nil {
+		return false, nil
+	}
+
+	usage, err := snap.QuotaUsageByName(nil, plan.Job.Region, spec.Name)
+	if err != nil {
+		return false, err
+	}
+	used := usage.Used[limit.HashKey()]
+	proposed := used.Copy()
+
+	// inQuota returns whether allocations in the namespace account against
+	// the quota
+	quotaNamespaces := map[string]bool{ns.Name: true}
+	inQuota := func(namespace string) (bool, error) {
+		if in, ok := quotaNamespaces[namespace]; ok {
+			return in, nil
+		}
+		other, err := snap.NamespaceByName(nil, namespace)
+		if err != nil {
+			return false, err
+		}
+		in := other != nil && other.Quota == spec.Name
+		quotaNamespaces[namespace] = in
+		return in, nil
+	}
+
+	// removeExisting removes the usage of the allocation if it is currently
+	// accounted against the quota
+	removeExisting := func(allocID string) error {
+		existing, err := snap.AllocByID(nil, allocID)
+		if err != nil {
+			return err
+		}
+		if existing == nil || existing.TerminalStatus() {
+			return nil
+		}
+		in, err := inQuota(existing.Namespace)
+		if err != nil {
+			return err
+		}
+		if in {
+			proposed.SubtractAlloc(existing)
+		}
+		return nil
+	}
+
+	for _, updates := range plan.NodeUpdate {
+		for _, alloc := range updates {
+			if err := removeExisting(alloc.ID); err != nil {
+				return false, err
+			}
+		}
+	}
+	for _, preemptions := range plan.NodePreemptions {
+		for _, alloc := range preemptions {
+			if err := removeExisting(alloc.ID); err != nil {
+				return false, err
+			}
+		}
+	}
+	for _, allocs := range plan.NodeAllocation {
+		for _, alloc := range allocs {
+			if err := removeExisting(alloc.ID); err != nil {
+				return false, err
+			}
+			if !alloc.TerminalStatus() {
+				proposed.AddAlloc(alloc)
+			}
+		}
+	}
+
+	if len(limit.Exceeded(proposed)) == 0 {
+		return false, nil
+	}
+	return quotaUsageIncreased(used, proposed), nil
+}
+
+// quotaUsageIncreased returns whether the proposed usage is higher than the
+// current usage in any dimension.
+func quotaUsageIncreased(used, proposed *structs.QuotaLimit) bool {
+	if proposed.AllocLimit > used.AllocLimit {
+		return true
+	}
+	u, p := used.RegionLimit, proposed.RegionLimit
+	return p.CPU > u.CPU || p.MemoryMB > u.MemoryMB || p.DiskMB > u.DiskMB
+}
+
 // evaluatePlanPlacements is used to determine what portions of a plan can be
 // applied if any, looking for node over commitment. Returns if there should be
 // a plan application which may be partial or if there was an error
@@ -378,9 +639,10 @@ func evaluatePlanPlacements(pool *EvaluatePool, snap *state.StateSnapshot, plan
 		return
 	}
 
-	// Get the pool channels
+	// Get the pool channels. The results are collected on a channel of the
+	// plan so that multiple plans can be evaluated by the pool concurrently.
 	req := pool.RequestCh()
-	resp := pool.ResultCh()
+	resp := make(chan evaluateResult, workerPoolBufferSize)
 	outstanding := 0
 	didCancel := false
 
@@ -390,7 +652,7 @@ OUTER:
 	for len(nodeIDList) > 0 {
 		nodeID := nodeIDList[0]
 		select {
-		case req <- evaluateRequest{snap, plan, nodeID}:
+		case req <- evaluateRequest{snap: snap, plan: plan, nodeID: nodeID, resCh: resp}:
 			outstanding++
 			nodeIDList = nodeIDList[1:]
 		case r := <-resp:
@@ -477,6 +739,17 @@ func correctDeploymentCanaries(result *structs.PlanResult) {
 	}
 }
 
+// isUnknownOnlyPlan returns whether every allocation is being marked unknown
+// because its node disconnected.
+func isUnknownOnlyPlan(allocs []*structs.Allocation) bool {
+	for _, alloc := range allocs {
+		if alloc.ClientStatus != structs.AllocClientStatusUnknown {
+			return false
+		}
+	}
+	return true
+}
+
 // evaluateNodePlan is used to evaluate the plan for a single node,
 // returning if the plan is valid or if an error is encountered
 func evaluateNodePlan(snap *state.StateSnapshot, plan *structs.Plan, nodeID string) (bool, string, error) {
@@ -497,6 +770,10 @@ func evaluateNodePlan(snap *state.StateSnapshot, plan *structs.Plan, nodeID stri
 	// the Raft commit happens.
 	if node == nil {
 		return false, "node does not exist", nil
+	} else if node.Status == structs.NodeStatusDown && isUnknownOnlyPlan(plan.NodeAllocation[nodeID]) {
+		// Marking the allocations of a disconnected node as unknown doesn't
+		// place anything on it, so it always fits.
+		return true, "", nil
 	} else if node.Status != structs.NodeStatusReady {
 		return false, "node is not ready for placements", nil
 	} else if node.SchedulingEligibility == structs.NodeSchedulingIneligible {
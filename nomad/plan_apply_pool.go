@@ -26,6 +26,10 @@ type evaluateRequest struct {
 	snap   *state.StateSnapshot
 	plan   *structs.Plan
 	nodeID string
+
+	// resCh is the channel the result is sent on. If it is nil, the result
+	// is sent on the result channel of the pool.
+	resCh chan evaluateResult
 }
 
 type evaluateResult struct {
@@ -104,7 +108,11 @@ func (p *EvaluatePool) run(stopCh chan struct{}) {
 		select {
 		case req := <-p.req:
 			fit, reason, err := evaluateNodePlan(req.snap, req.plan, req.nodeID)
-			p.res <- evaluateResult{req.nodeID, fit, reason, err}
+			resCh := req.resCh
+			if resCh == nil {
+				resCh = p.res
+			}
+			resCh <- evaluateResult{req.nodeID, fit, reason, err}
 
 		case <-stopCh:
 			return
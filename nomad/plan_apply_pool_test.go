@@ -26,7 +26,7 @@ func TestEvaluatePool(t *testing.T) {
 
 	// Push a request
 	req := pool.RequestCh()
-	req <- evaluateRequest{snap, plan, node.ID}
+	req <- evaluateRequest{snap: snap, plan: plan, nodeID: node.ID}
 
 	// Get the response
 	res := <-pool.ResultCh()
@@ -272,6 +272,187 @@ func TestPlanApply_EvalPlan_Simple(t *testing.T) {
 	}
 }
 
+func TestPlanApply_planApply_Batch(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0
+		c.PlanBatchSize = 4
+	})
+	defer s1.Shutdown()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Submit plans concurrently so that they are batched, two of them
+	// placing on the same node
+	var nodes []*structs.Node
+	for i := 0; i < 3; i++ {
+		node := mock.Node()
+		testRegisterNode(t, s1, node)
+		nodes = append(nodes, node)
+	}
+
+	var allocs []*structs.Allocation
+	var futures []PlanFuture
+	for i := 0; i < 4; i++ {
+		alloc := mock.Alloc()
+		alloc.NodeID = nodes[i%len(nodes)].ID
+		alloc.AllocatedResources.Tasks["web"].Networks = nil
+		s1.State().UpsertJobSummary(uint64(1000+i), mock.JobSummary(alloc.JobID))
+		allocs = append(allocs, alloc)
+
+		future, err := s1.planQueue.Enqueue(&structs.Plan{
+			Job: alloc.Job,
+			NodeAllocation: map[string][]*structs.Allocation{
+				alloc.NodeID: {alloc},
+			},
+		})
+		require.NoError(err)
+		futures = append(futures, future)
+	}
+
+	// All the plans are applied
+	for _, future := range futures {
+		result, err := future.Wait()
+		require.NoError(err)
+		require.NotZero(result.AllocIndex)
+	}
+	for _, alloc := range allocs {
+		out, err := s1.State().AllocByID(nil, alloc.ID)
+		require.NoError(err)
+		require.NotNil(out)
+	}
+}
+
+func TestPlanApply_PlanBatch(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	state := testStateStore(t)
+
+	spec := &structs.QuotaSpec{Name: "shared"}
+	require.NoError(state.UpsertQuotaSpecs(1000, []*structs.QuotaSpec{spec}))
+	require.NoError(state.UpsertNamespaces(1001, []*structs.Namespace{{Name: "limited", Quota: spec.Name}}))
+	snap, _ := state.Snapshot()
+
+	// newPlan returns a plan of a job in the namespace that places on the
+	// node and preempts on the other nodes
+	newPlan := func(namespace, nodeID string, preemptNodeIDs ...string) *structs.Plan {
+		alloc := mock.Alloc()
+		alloc.Namespace = namespace
+		alloc.Job.Namespace = namespace
+		plan := &structs.Plan{
+			Job: alloc.Job,
+			NodeAllocation: map[string][]*structs.Allocation{
+				nodeID: {alloc},
+			},
+			NodePreemptions: make(map[string][]*structs.Allocation),
+		}
+		for _, preemptNodeID := range preemptNodeIDs {
+			plan.NodePreemptions[preemptNodeID] = []*structs.Allocation{mock.Alloc()}
+		}
+		return plan
+	}
+
+	batch := newPlanBatch(snap)
+	require.True(batch.add(newPlan(structs.DefaultNamespace, "node-1")))
+	require.True(batch.add(newPlan(structs.DefaultNamespace, "node-2")))
+
+	// Plans touching a node of the batch are not compatible
+	require.False(batch.add(newPlan(structs.DefaultNamespace, "node-1")))
+	require.False(batch.add(newPlan(structs.DefaultNamespace, "node-3", "node-2")))
+
+	// Plans accounting against the same quota are not compatible
+	require.True(batch.add(newPlan("limited", "node-3")))
+	require.False(batch.add(newPlan("limited", "node-4")))
+	require.True(batch.add(newPlan(structs.DefaultNamespace, "node-4")))
+}
+
+func TestPlanApply_EvalPlans(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	state := testStateStore(t)
+
+	var batch []*pendingPlan
+	for i := 0; i < 3; i++ {
+		node := mock.Node()
+		require.NoError(state.UpsertNode(uint64(1000+i), node))
+
+		alloc := mock.Alloc()
+		batch = append(batch, &pendingPlan{
+			plan: &structs.Plan{
+				Job: alloc.Job,
+				NodeAllocation: map[string][]*structs.Allocation{
+					node.ID: {alloc},
+				},
+			},
+		})
+	}
+	snap, _ := state.Snapshot()
+
+	pool := NewEvaluatePool(workerPoolSize, workerPoolBufferSize)
+	defer pool.Shutdown()
+
+	// Each plan is evaluated on its own
+	results, errs := evaluatePlans(pool, snap, batch, testlog.HCLogger(t))
+	require.Len(results, 3)
+	for i, pending := range batch {
+		require.NoError(errs[i])
+		require.Equal(pending.plan.NodeAllocation, results[i].NodeAllocation)
+	}
+}
+
+func TestPlanApply_EvalPlan_Quota(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	state := testStateStore(t)
+	node := mock.Node()
+	state.UpsertNode(1000, node)
+
+	// Allow a single allocation in the default namespace
+	spec := &structs.QuotaSpec{
+		Name: "single",
+		Limits: []*structs.QuotaLimit{{
+			Region:      "global",
+			RegionLimit: &structs.Resources{},
+			AllocLimit:  1,
+		}},
+	}
+	require.NoError(state.UpsertQuotaSpecs(1001, []*structs.QuotaSpec{spec}))
+	require.NoError(state.UpsertNamespaces(1002, []*structs.Namespace{{Name: structs.DefaultNamespace, Quota: spec.Name}}))
+
+	existing := mock.Alloc()
+	existing.NodeID = node.ID
+	require.NoError(state.UpsertAllocs(1003, []*structs.Allocation{existing}))
+	snap, _ := state.Snapshot()
+
+	pool := NewEvaluatePool(workerPoolSize, workerPoolBufferSize)
+	defer pool.Shutdown()
+
+	// Placing an additional allocation exceeds the quota and forces a refresh
+	alloc := mock.Alloc()
+	alloc.NodeID = node.ID
+	plan := &structs.Plan{
+		Job: alloc.Job,
+		NodeAllocation: map[string][]*structs.Allocation{
+			node.ID: {alloc},
+		},
+	}
+	result, err := evaluatePlan(pool, snap, plan, testlog.HCLogger(t))
+	require.NoError(err)
+	require.Empty(result.NodeAllocation)
+	require.EqualValues(1003, result.RefreshIndex)
+
+	// Replacing the existing allocation stays within the quota
+	stopped := existing.Copy()
+	stopped.DesiredStatus = structs.AllocDesiredStatusStop
+	plan.NodeUpdate = map[string][]*structs.Allocation{
+		node.ID: {stopped},
+	}
+	result, err = evaluatePlan(pool, snap, plan, testlog.HCLogger(t))
+	require.NoError(err)
+	require.Len(result.NodeAllocation[node.ID], 1)
+	require.Zero(result.RefreshIndex)
+}
+
 func TestPlanApply_EvalPlan_Preemption(t *testing.T) {
 	t.Parallel()
 	state := testStateStore(t)
@@ -831,3 +1012,37 @@ func TestPlanApply_EvalNodePlan_NodeDown_EvictOnly(t *testing.T) {
 		t.Fatalf("bad")
 	}
 }
+
+func TestPlanApply_EvalNodePlan_NodeDown_Unknown(t *testing.T) {
+	t.Parallel()
+	alloc := mock.Alloc()
+	state := testStateStore(t)
+	node := mock.Node()
+	alloc.NodeID = node.ID
+	node.Status = structs.NodeStatusDown
+	state.UpsertNode(1000, node)
+	state.UpsertAllocs(1001, []*structs.Allocation{alloc})
+	snap, _ := state.Snapshot()
+
+	// Marking the allocation unknown fits on a down node
+	allocUnknown := alloc.Copy()
+	allocUnknown.ClientStatus = structs.AllocClientStatusUnknown
+	plan := &structs.Plan{
+		Job: alloc.Job,
+		NodeAllocation: map[string][]*structs.Allocation{
+			node.ID: {allocUnknown},
+		},
+	}
+
+	fit, reason, err := evaluateNodePlan(snap, plan, node.ID)
+	require.NoError(t, err)
+	require.True(t, fit)
+	require.Empty(t, reason)
+
+	// Placing a new allocation does not
+	plan.NodeAllocation[node.ID] = append(plan.NodeAllocation[node.ID], mock.Alloc())
+	fit, reason, err = evaluateNodePlan(snap, plan, node.ID)
+	require.NoError(t, err)
+	require.False(t, fit)
+	require.NotEmpty(t, reason)
+}
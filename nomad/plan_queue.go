@@ -160,6 +160,38 @@ SCAN:
 	}
 }
 
+// DequeueCompatible is used to perform a non-blocking dequeue of up to max
+// ready plans for which compatible returns true. Plans are considered in
+// priority order and the plans that are not compatible remain queued.
+func (q *PlanQueue) DequeueCompatible(max int, compatible func(*structs.Plan) bool) []*pendingPlan {
+	q.l.Lock()
+	defer q.l.Unlock()
+
+	// Do nothing if not enabled
+	if !q.enabled {
+		return nil
+	}
+
+	var batch []*pendingPlan
+	var skipped []*pendingPlan
+	for len(batch) < max && len(q.ready) > 0 {
+		pending := heap.Pop(&q.ready).(*pendingPlan)
+		if compatible(pending.plan) {
+			batch = append(batch, pending)
+		} else {
+			skipped = append(skipped, pending)
+		}
+	}
+
+	// Requeue the plans that were not compatible
+	for _, pending := range skipped {
+		heap.Push(&q.ready, pending)
+	}
+
+	q.stats.Depth -= len(batch)
+	return batch
+}
+
 // Flush is used to reset the state of the plan queue
 func (q *PlanQueue) Flush() {
 	q.l.Lock()
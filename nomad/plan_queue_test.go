@@ -160,6 +160,50 @@ func TestPlanQueue_Dequeue_Priority(t *testing.T) {
 	}
 }
 
+func TestPlanQueue_DequeueCompatible(t *testing.T) {
+	t.Parallel()
+	pq := testPlanQueue(t)
+	pq.SetEnabled(true)
+
+	plan1 := mock.Plan()
+	plan1.Priority = 30
+	pq.Enqueue(plan1)
+
+	plan2 := mock.Plan()
+	plan2.Priority = 20
+	pq.Enqueue(plan2)
+
+	plan3 := mock.Plan()
+	plan3.Priority = 10
+	pq.Enqueue(plan3)
+
+	plan4 := mock.Plan()
+	plan4.Priority = 10
+	pq.Enqueue(plan4)
+
+	// Only the plans accepted are dequeued, in priority order
+	compatible := func(plan *structs.Plan) bool {
+		return plan != plan2
+	}
+	out := pq.DequeueCompatible(2, compatible)
+	if len(out) != 2 || out[0].plan != plan1 || out[1].plan != plan3 {
+		t.Fatalf("bad: %#v", out)
+	}
+
+	// The incompatible plan remains queued in order
+	if depth := pq.Stats().Depth; depth != 2 {
+		t.Fatalf("bad: %d", depth)
+	}
+	out1, _ := pq.Dequeue(time.Second)
+	if out1.plan != plan2 {
+		t.Fatalf("bad: %#v", out1)
+	}
+	out2, _ := pq.Dequeue(time.Second)
+	if out2.plan != plan4 {
+		t.Fatalf("bad: %#v", out2)
+	}
+}
+
 // Ensure FIFO at fixed priority
 func TestPlanQueue_Dequeue_FIFO(t *testing.T) {
 	t.Parallel()
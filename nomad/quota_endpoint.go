@@ -0,0 +1,323 @@
+package nomad
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	log "github.com/hashicorp/go-hclog"
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// Quota endpoint is used for managing quota specifications and querying
+// their usage
+type Quota struct {
+	srv    *Server
+	logger log.Logger
+}
+
+// ListQuotaSpecs is used to list the quota specifications
+func (q *Quota) ListQuotaSpecs(args *structs.QuotaSpecListRequest, reply *structs.QuotaSpecListResponse) error {
+	if done, err := q.srv.forward("Quota.ListQuotaSpecs", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "quota", "list_quota_specs"}, time.Now())
+
+	// Check quota read permissions
+	if aclObj, err := q.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowQuotaRead() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			iter, err := state.QuotaSpecs(ws)
+			if err != nil {
+				return err
+			}
+
+			reply.Quotas = nil
+			for {
+				raw := iter.Next()
+				if raw == nil {
+					break
+				}
+				spec := raw.(*structs.QuotaSpec)
+				if prefix := args.QueryOptions.Prefix; prefix != "" && !strings.HasPrefix(spec.Name, prefix) {
+					continue
+				}
+				reply.Quotas = append(reply.Quotas, spec)
+			}
+
+			// Use the last index that affected the quota table
+			index, err := state.Index("quota_specs")
+			if err != nil {
+				return err
+			}
+
+			// Ensure we never set the index to zero, otherwise a blocking query cannot be used.
+			// We floor the index at one, since realistically the first write must have a higher index.
+			if index == 0 {
+				index = 1
+			}
+			reply.Index = index
+			return nil
+		}}
+	return q.srv.blockingRPC(&opts)
+}
+
+// GetQuotaSpec is used to get a specific quota specification
+func (q *Quota) GetQuotaSpec(args *structs.QuotaSpecSpecificRequest, reply *structs.SingleQuotaSpecResponse) error {
+	if done, err := q.srv.forward("Quota.GetQuotaSpec", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "quota", "get_quota_spec"}, time.Now())
+
+	// Check quota read permissions
+	if aclObj, err := q.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowQuotaRead() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			// Look for the quota specification
+			out, err := state.QuotaSpecByName(ws, args.Name)
+			if err != nil {
+				return err
+			}
+
+			// Setup the output
+			reply.Quota = out
+			if out != nil {
+				reply.Index = out.ModifyIndex
+			} else {
+				// Use the last index that affected the quota table
+				index, err := state.Index("quota_specs")
+				if err != nil {
+					return err
+				}
+
+				// Ensure we never set the index to zero, otherwise a blocking query cannot be used.
+				// We floor the index at one, since realistically the first write must have a higher index.
+				if index == 0 {
+					index = 1
+				}
+				reply.Index = index
+			}
+			return nil
+		}}
+	return q.srv.blockingRPC(&opts)
+}
+
+// UpsertQuotaSpecs is used to create or update a set of quota specifications
+func (q *Quota) UpsertQuotaSpecs(args *structs.QuotaSpecUpsertRequest, reply *structs.GenericResponse) error {
+	if done, err := q.srv.forward("Quota.UpsertQuotaSpecs", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "quota", "upsert_quota_specs"}, time.Now())
+
+	// Check quota write permissions
+	if aclObj, err := q.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowQuotaWrite() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Validate non-zero set of quota specifications
+	if len(args.Quotas) == 0 {
+		return fmt.Errorf("must specify at least one quota specification")
+	}
+	for idx, spec := range args.Quotas {
+		if err := spec.Validate(); err != nil {
+			return fmt.Errorf("quota specification %d invalid: %v", idx, err)
+		}
+		spec.SetHash()
+	}
+
+	// Update via Raft
+	_, index, err := q.srv.raftApply(structs.QuotaSpecUpsertRequestType, args)
+	if err != nil {
+		return err
+	}
+
+	// Update the index
+	reply.Index = index
+	return nil
+}
+
+// DeleteQuotaSpecs is used to delete a set of quota specifications. Quota
+// specifications that are referenced by a namespace can not be deleted.
+func (q *Quota) DeleteQuotaSpecs(args *structs.QuotaSpecDeleteRequest, reply *structs.GenericResponse) error {
+	if done, err := q.srv.forward("Quota.DeleteQuotaSpecs", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "quota", "delete_quota_specs"}, time.Now())
+
+	// Check quota write permissions
+	if aclObj, err := q.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowQuotaWrite() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Validate non-zero set of quota specifications
+	if len(args.Names) == 0 {
+		return fmt.Errorf("must specify at least one quota specification to delete")
+	}
+
+	snap, err := q.srv.fsm.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	for _, name := range args.Names {
+		iter, err := snap.NamespacesByQuota(nil, name)
+		if err != nil {
+			return err
+		}
+		if raw := iter.Next(); raw != nil {
+			ns := raw.(*structs.Namespace)
+			return fmt.Errorf("quota specification %q is referenced by namespace %q", name, ns.Name)
+		}
+	}
+
+	// Update via Raft
+	_, index, err := q.srv.raftApply(structs.QuotaSpecDeleteRequestType, args)
+	if err != nil {
+		return err
+	}
+
+	// Update the index
+	reply.Index = index
+	return nil
+}
+
+// ListQuotaUsages is used to list the usages of the quota specifications in
+// the server's region
+func (q *Quota) ListQuotaUsages(args *structs.QuotaSpecListRequest, reply *structs.QuotaUsageListResponse) error {
+	if done, err := q.srv.forward("Quota.ListQuotaUsages", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "quota", "list_quota_usages"}, time.Now())
+
+	// Check quota read permissions
+	if aclObj, err := q.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowQuotaRead() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			iter, err := state.QuotaSpecs(ws)
+			if err != nil {
+				return err
+			}
+
+			reply.Usages = nil
+			for {
+				raw := iter.Next()
+				if raw == nil {
+					break
+				}
+				spec := raw.(*structs.QuotaSpec)
+				if prefix := args.QueryOptions.Prefix; prefix != "" && !strings.HasPrefix(spec.Name, prefix) {
+					continue
+				}
+
+				usage, err := state.QuotaUsageByName(ws, q.srv.config.Region, spec.Name)
+				if err != nil {
+					return err
+				}
+				if usage != nil {
+					reply.Usages = append(reply.Usages, usage)
+				}
+			}
+
+			// Usage changes with the quota specifications and allocations
+			index, err := quotaUsageIndex(state)
+			if err != nil {
+				return err
+			}
+			reply.Index = index
+			return nil
+		}}
+	return q.srv.blockingRPC(&opts)
+}
+
+// GetQuotaUsage is used to get the usage of a specific quota specification in
+// the server's region
+func (q *Quota) GetQuotaUsage(args *structs.QuotaSpecSpecificRequest, reply *structs.SingleQuotaUsageResponse) error {
+	if done, err := q.srv.forward("Quota.GetQuotaUsage", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "quota", "get_quota_usage"}, time.Now())
+
+	// Check quota read permissions
+	if aclObj, err := q.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowQuotaRead() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			usage, err := state.QuotaUsageByName(ws, q.srv.config.Region, args.Name)
+			if err != nil {
+				return err
+			}
+			reply.Usage = usage
+
+			// Usage changes with the quota specifications and allocations
+			index, err := quotaUsageIndex(state)
+			if err != nil {
+				return err
+			}
+			reply.Index = index
+			return nil
+		}}
+	return q.srv.blockingRPC(&opts)
+}
+
+// quotaUsageIndex returns the index to use for quota usage queries. It is the
+// last index that affected the quota specifications, namespaces or
+// allocations.
+func quotaUsageIndex(state *state.StateStore) (uint64, error) {
+	index, err := state.Index("quota_specs")
+	if err != nil {
+		return 0, err
+	}
+	for _, table := range []string{"namespaces", "allocs"} {
+		other, err := state.Index(table)
+		if err != nil {
+			return 0, err
+		}
+		if other > index {
+			index = other
+		}
+	}
+
+	// Ensure we never set the index to zero, otherwise a blocking query cannot be used.
+	// We floor the index at one, since realistically the first write must have a higher index.
+	if index == 0 {
+		index = 1
+	}
+	return index, nil
+}
@@ -0,0 +1,207 @@
+package nomad
+
+import (
+	"testing"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func testQuotaSpec(name string) *structs.QuotaSpec {
+	return &structs.QuotaSpec{
+		Name: name,
+		Limits: []*structs.QuotaLimit{{
+			Region:      "global",
+			RegionLimit: &structs.Resources{CPU: 2000, MemoryMB: 1024},
+		}},
+	}
+}
+
+func TestQuotaEndpoint_UpsertQuotaSpecs(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	spec := testQuotaSpec("small")
+	req := &structs.QuotaSpecUpsertRequest{
+		Quotas:       []*structs.QuotaSpec{spec},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.GenericResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Quota.UpsertQuotaSpecs", req, &resp))
+	require.NotZero(resp.Index)
+
+	// Check we created the quota specification
+	out, err := s1.fsm.State().QuotaSpecByName(nil, spec.Name)
+	require.NoError(err)
+	require.NotNil(out)
+	require.Len(out.Limits, 1)
+	require.NotEmpty(out.Limits[0].Hash)
+
+	// Invalid quota specifications are rejected
+	req.Quotas = []*structs.QuotaSpec{{Name: "empty"}}
+	err = msgpackrpc.CallWithCodec(codec, "Quota.UpsertQuotaSpecs", req, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "at least one quota limit")
+}
+
+func TestQuotaEndpoint_GetQuotaSpec(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	spec := testQuotaSpec("small")
+	require.NoError(s1.fsm.State().UpsertQuotaSpecs(1000, []*structs.QuotaSpec{spec}))
+
+	get := &structs.QuotaSpecSpecificRequest{
+		Name:         spec.Name,
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var resp structs.SingleQuotaSpecResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Quota.GetQuotaSpec", get, &resp))
+	require.EqualValues(1000, resp.Index)
+	require.Equal(spec, resp.Quota)
+
+	// Lookup a non-existing quota specification
+	get.Name = "missing"
+	var resp2 structs.SingleQuotaSpecResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Quota.GetQuotaSpec", get, &resp2))
+	require.EqualValues(1000, resp2.Index)
+	require.Nil(resp2.Quota)
+
+	// List the quota specifications by prefix
+	list := &structs.QuotaSpecListRequest{
+		QueryOptions: structs.QueryOptions{Region: "global", Prefix: "sm"},
+	}
+	var listResp structs.QuotaSpecListResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Quota.ListQuotaSpecs", list, &listResp))
+	require.Len(listResp.Quotas, 1)
+	list.Prefix = "large"
+	var listResp2 structs.QuotaSpecListResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Quota.ListQuotaSpecs", list, &listResp2))
+	require.Empty(listResp2.Quotas)
+}
+
+func TestQuotaEndpoint_DeleteQuotaSpecs(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	state := s1.fsm.State()
+	specs := []*structs.QuotaSpec{testQuotaSpec("small"), testQuotaSpec("unused")}
+	require.NoError(state.UpsertQuotaSpecs(1000, specs))
+	require.NoError(state.UpsertNamespaces(1001, []*structs.Namespace{{Name: "team-a", Quota: "small"}}))
+
+	req := &structs.QuotaSpecDeleteRequest{
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.GenericResponse
+
+	// Quota specifications referenced by a namespace can not be deleted
+	req.Names = []string{"small"}
+	err := msgpackrpc.CallWithCodec(codec, "Quota.DeleteQuotaSpecs", req, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "team-a")
+
+	req.Names = []string{"unused"}
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Quota.DeleteQuotaSpecs", req, &resp))
+	require.NotZero(resp.Index)
+
+	out, err := state.QuotaSpecByName(nil, "unused")
+	require.NoError(err)
+	require.Nil(out)
+}
+
+func TestQuotaEndpoint_GetQuotaUsage(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	state := s1.fsm.State()
+	spec := testQuotaSpec("small")
+	require.NoError(state.UpsertQuotaSpecs(1000, []*structs.QuotaSpec{spec}))
+	require.NoError(state.UpsertNamespaces(1001, []*structs.Namespace{{Name: structs.DefaultNamespace, Quota: spec.Name}}))
+	alloc := mock.Alloc()
+	require.NoError(state.UpsertAllocs(1002, []*structs.Allocation{alloc}))
+
+	get := &structs.QuotaSpecSpecificRequest{
+		Name:         spec.Name,
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var resp structs.SingleQuotaUsageResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Quota.GetQuotaUsage", get, &resp))
+	require.EqualValues(1002, resp.Index)
+	require.NotNil(resp.Usage)
+	used := resp.Usage.Used[spec.Limits[0].HashKey()]
+	require.NotNil(used)
+	require.Equal(500, used.RegionLimit.CPU)
+	require.Equal(1, used.AllocLimit)
+
+	// List the usages
+	list := &structs.QuotaSpecListRequest{
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var listResp structs.QuotaUsageListResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Quota.ListQuotaUsages", list, &listResp))
+	require.Len(listResp.Usages, 1)
+	require.Equal(spec.Name, listResp.Usages[0].Name)
+}
+
+func TestQuotaEndpoint_ACL(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1, root := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	state := s1.fsm.State()
+	readToken := mock.CreatePolicyAndToken(t, state, 1001, "read", mock.QuotaPolicy(acl.PolicyRead))
+	writeToken := mock.CreatePolicyAndToken(t, state, 1003, "write", mock.QuotaPolicy(acl.PolicyWrite))
+
+	req := &structs.QuotaSpecUpsertRequest{
+		Quotas:       []*structs.QuotaSpec{testQuotaSpec("small")},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.GenericResponse
+
+	// Upsert without a token or with a read token fails
+	err := msgpackrpc.CallWithCodec(codec, "Quota.UpsertQuotaSpecs", req, &resp)
+	require.EqualError(err, structs.ErrPermissionDenied.Error())
+	req.AuthToken = readToken.SecretID
+	err = msgpackrpc.CallWithCodec(codec, "Quota.UpsertQuotaSpecs", req, &resp)
+	require.EqualError(err, structs.ErrPermissionDenied.Error())
+
+	// Upsert with a write or management token succeeds
+	req.AuthToken = writeToken.SecretID
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Quota.UpsertQuotaSpecs", req, &resp))
+	req.AuthToken = root.SecretID
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Quota.UpsertQuotaSpecs", req, &resp))
+
+	// List with a read token succeeds
+	get := &structs.QuotaSpecListRequest{
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var listResp structs.QuotaSpecListResponse
+	err = msgpackrpc.CallWithCodec(codec, "Quota.ListQuotaSpecs", get, &listResp)
+	require.EqualError(err, structs.ErrPermissionDenied.Error())
+	get.AuthToken = readToken.SecretID
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Quota.ListQuotaSpecs", get, &listResp))
+	require.Len(listResp.Quotas, 1)
+}
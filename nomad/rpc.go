@@ -476,23 +476,31 @@ func (r *rpcHandler) forwardServer(server *serverParts, method string, args inte
 
 // forwardRegion is used to forward an RPC call to a remote region, or fail if no servers
 func (r *rpcHandler) forwardRegion(region, method string, args interface{}, reply interface{}) error {
-	// Bail if we can't find any servers
+	server, err := r.regionServer(region)
+	if err != nil {
+		return err
+	}
+
+	// Forward to remote Nomad
+	metrics.IncrCounter([]string{"nomad", "rpc", "cross-region", region}, 1)
+	return r.connPool.RPC(region, server.Addr, server.MajorVersion, method, args, reply)
+}
+
+// regionServer returns a random server of the region, or fails if no servers
+// are known.
+func (r *rpcHandler) regionServer(region string) (*serverParts, error) {
 	r.peerLock.RLock()
+	defer r.peerLock.RUnlock()
+
+	// Bail if we can't find any servers
 	servers := r.peers[region]
 	if len(servers) == 0 {
-		r.peerLock.RUnlock()
 		r.logger.Warn("no path found to region", "region", region)
-		return structs.ErrNoRegionPath
+		return nil, structs.ErrNoRegionPath
 	}
 
 	// Select a random addr
-	offset := rand.Intn(len(servers))
-	server := servers[offset]
-	r.peerLock.RUnlock()
-
-	// Forward to remote Nomad
-	metrics.IncrCounter([]string{"nomad", "rpc", "cross-region", region}, 1)
-	return r.connPool.RPC(region, server.Addr, server.MajorVersion, method, args, reply)
+	return servers[rand.Intn(len(servers))].Copy(), nil
 }
 
 // streamingRpc creates a connection to the given server and conducts the
@@ -29,6 +29,19 @@ var (
 		structs.Nodes,
 		structs.Evals,
 		structs.Deployments,
+		structs.Namespaces,
+		structs.Quotas,
+	}
+
+	// fuzzyContexts are the contexts which are searched to find the objects
+	// whose name contains a given text
+	fuzzyContexts = []structs.Context{
+		structs.Allocs,
+		structs.Jobs,
+		structs.Nodes,
+		structs.Deployments,
+		structs.Plugins,
+		structs.Namespaces,
 	}
 )
 
@@ -61,6 +74,10 @@ func (s *Search) getMatches(iter memdb.ResultIterator, prefix string) ([]string,
 			id = raw.(*structs.Node).ID
 		case *structs.Deployment:
 			id = raw.(*structs.Deployment).ID
+		case *structs.Namespace:
+			id = raw.(*structs.Namespace).Name
+		case *structs.QuotaSpec:
+			id = raw.(*structs.QuotaSpec).Name
 		default:
 			matchID, ok := getEnterpriseMatch(raw)
 			if !ok {
@@ -95,6 +112,19 @@ func getResourceIter(context structs.Context, aclObj *acl.ACL, namespace, prefix
 		return state.NodesByIDPrefix(ws, prefix)
 	case structs.Deployments:
 		return state.DeploymentsByIDPrefix(ws, namespace, prefix)
+	case structs.Namespaces:
+		iter, err := state.NamespacesByNamePrefix(ws, prefix)
+		if err != nil || aclObj == nil {
+			return iter, err
+		}
+
+		// Only return namespaces allowed by the ACL
+		return memdb.NewFilterIterator(iter, func(raw interface{}) bool {
+			ns, ok := raw.(*structs.Namespace)
+			return !ok || !aclObj.AllowNamespace(ns.Name)
+		}), nil
+	case structs.Quotas:
+		return state.QuotaSpecsByNamePrefix(ws, prefix)
 	default:
 		return getEnterpriseResourceIter(context, aclObj, namespace, prefix, ws, state)
 	}
@@ -194,3 +224,154 @@ func (s *Search) PrefixSearch(args *structs.SearchRequest, reply *structs.Search
 		}}
 	return s.srv.blockingRPC(&opts)
 }
+
+// FuzzySearch is used to list the objects whose name contains a given text,
+// and returns matching jobs, allocations, nodes, deployments, plugins and/or
+// namespaces.
+func (s *Search) FuzzySearch(args *structs.FuzzySearchRequest, reply *structs.FuzzySearchResponse) error {
+	if done, err := s.srv.forward("Search.FuzzySearch", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "search", "fuzzy_search"}, time.Now())
+
+	if args.Text == "" {
+		return fmt.Errorf("missing search text")
+	}
+	if args.Context != structs.All && !isFuzzyContext(args.Context) {
+		return fmt.Errorf("context must be one of %v or 'all' for all contexts; got %q", fuzzyContexts, args.Context)
+	}
+
+	aclObj, err := s.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	}
+
+	namespace := args.RequestNamespace()
+
+	// Require either node:read, namespace:read-job or namespace:read-volume
+	if !anySearchPerms(aclObj, namespace, args.Context) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryMeta: &reply.QueryMeta,
+		queryOpts: &structs.QueryOptions{},
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			reply.Matches = make(map[structs.Context][]structs.FuzzyMatch)
+			reply.Truncations = make(map[structs.Context]bool)
+
+			text := strings.ToLower(args.Text)
+			contexts := fuzzySearchContexts(aclObj, namespace, args.Context)
+			for _, ctx := range contexts {
+				matches, truncated, err := fuzzyMatches(ctx, aclObj, namespace, text, ws, state)
+				if err != nil {
+					return err
+				}
+				reply.Matches[ctx] = matches
+				reply.Truncations[ctx] = truncated
+
+				index, err := state.Index(contextToIndex(ctx))
+				if err != nil {
+					return err
+				}
+				if index > reply.Index {
+					reply.Index = index
+				}
+			}
+
+			s.srv.setQueryMeta(&reply.QueryMeta)
+			return nil
+		}}
+	return s.srv.blockingRPC(&opts)
+}
+
+// isFuzzyContext returns whether the context can be fuzzy searched.
+func isFuzzyContext(context structs.Context) bool {
+	for _, c := range fuzzyContexts {
+		if c == context {
+			return true
+		}
+	}
+	return false
+}
+
+// fuzzyMatches returns up to truncateLimit objects of the context whose name
+// contains the lower case text, and whether more objects matched.
+func fuzzyMatches(context structs.Context, aclObj *acl.ACL, namespace, text string,
+	ws memdb.WatchSet, state *state.StateStore) ([]structs.FuzzyMatch, bool, error) {
+
+	var iter memdb.ResultIterator
+	var err error
+	switch context {
+	case structs.Jobs:
+		iter, err = state.JobsByNamespace(ws, namespace)
+	case structs.Allocs:
+		iter, err = state.AllocsByNamespace(ws, namespace)
+	case structs.Nodes:
+		iter, err = state.Nodes(ws)
+	case structs.Deployments:
+		iter, err = state.DeploymentsByNamespace(ws, namespace)
+	case structs.Plugins:
+		iter, err = state.VolumesByIDPrefix(ws, namespace, "")
+	case structs.Namespaces:
+		iter, err = state.Namespaces(ws)
+	default:
+		return nil, false, fmt.Errorf("context %q can't be fuzzy searched", context)
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	contains := func(s string) bool {
+		return strings.Contains(strings.ToLower(s), text)
+	}
+
+	var matches []structs.FuzzyMatch
+	seen := make(map[string]struct{})
+	for {
+		raw := iter.Next()
+		if raw == nil {
+			return matches, false, nil
+		}
+
+		var match *structs.FuzzyMatch
+		switch obj := raw.(type) {
+		case *structs.Job:
+			if contains(obj.ID) || contains(obj.Name) {
+				match = &structs.FuzzyMatch{ID: obj.ID, Scope: []string{obj.Namespace}}
+			}
+		case *structs.Allocation:
+			if contains(obj.Name) {
+				match = &structs.FuzzyMatch{ID: obj.Name, Scope: []string{obj.Namespace, obj.ID}}
+			}
+		case *structs.Node:
+			if contains(obj.Name) {
+				match = &structs.FuzzyMatch{ID: obj.Name, Scope: []string{obj.ID}}
+			}
+		case *structs.Deployment:
+			if contains(obj.ID) || contains(obj.JobID) {
+				match = &structs.FuzzyMatch{ID: obj.ID, Scope: []string{obj.Namespace, obj.JobID}}
+			}
+		case *structs.Volume:
+			// Plugins are only known by the volumes they provide, so each
+			// is returned once
+			if _, ok := seen[obj.PluginID]; !ok && obj.PluginID != "" && contains(obj.PluginID) {
+				seen[obj.PluginID] = struct{}{}
+				match = &structs.FuzzyMatch{ID: obj.PluginID}
+			}
+		case *structs.Namespace:
+			if contains(obj.Name) && (aclObj == nil || aclObj.AllowNamespace(obj.Name)) {
+				match = &structs.FuzzyMatch{ID: obj.Name}
+			}
+		}
+		if match == nil {
+			continue
+		}
+
+		if len(matches) == truncateLimit {
+			return matches, true, nil
+		}
+		matches = append(matches, *match)
+	}
+}
@@ -19,7 +19,14 @@ var (
 
 // contextToIndex returns the index name to lookup in the state store.
 func contextToIndex(ctx structs.Context) string {
-	return string(ctx)
+	switch ctx {
+	case structs.Quotas:
+		return "quota_specs"
+	case structs.Plugins:
+		return "volumes"
+	default:
+		return string(ctx)
+	}
 }
 
 // getEnterpriseMatch is a no-op in oss since there are no enterprise objects.
@@ -44,7 +51,9 @@ func anySearchPerms(aclObj *acl.ACL, namespace string, context structs.Context)
 
 	nodeRead := aclObj.AllowNodeRead()
 	jobRead := aclObj.AllowNsOp(namespace, acl.NamespaceCapabilityReadJob)
-	if !nodeRead && !jobRead {
+	quotaRead := aclObj.AllowQuotaRead()
+	volumeRead := aclObj.AllowNsOp(namespace, acl.NamespaceCapabilityReadVolume)
+	if !nodeRead && !jobRead && !quotaRead && !volumeRead {
 		return false
 	}
 
@@ -54,6 +63,12 @@ func anySearchPerms(aclObj *acl.ACL, namespace string, context structs.Context)
 	if !nodeRead && context == structs.Nodes {
 		return false
 	}
+	if !quotaRead && context == structs.Quotas {
+		return false
+	}
+	if !volumeRead && context == structs.Plugins {
+		return false
+	}
 	if !jobRead {
 		switch context {
 		case structs.Allocs, structs.Deployments, structs.Evals, structs.Jobs:
@@ -77,6 +92,28 @@ func searchContexts(aclObj *acl.ACL, namespace string, context structs.Context)
 		all = []structs.Context{context}
 	}
 
+	return filterSearchContexts(aclObj, namespace, all)
+}
+
+// fuzzySearchContexts returns the fuzzy search contexts the aclObj is valid
+// for. If aclObj is nil all contexts are returned.
+func fuzzySearchContexts(aclObj *acl.ACL, namespace string, context structs.Context) []structs.Context {
+	var all []structs.Context
+
+	switch context {
+	case structs.All:
+		all = make([]structs.Context, len(fuzzyContexts))
+		copy(all, fuzzyContexts)
+	default:
+		all = []structs.Context{context}
+	}
+
+	return filterSearchContexts(aclObj, namespace, all)
+}
+
+// filterSearchContexts filters the contexts down to those the aclObj is
+// valid for. If aclObj is nil all contexts are returned.
+func filterSearchContexts(aclObj *acl.ACL, namespace string, all []structs.Context) []structs.Context {
 	// If ACLs aren't enabled return all contexts
 	if aclObj == nil {
 		return all
@@ -96,6 +133,17 @@ func searchContexts(aclObj *acl.ACL, namespace string, context structs.Context)
 			if aclObj.AllowNodeRead() {
 				available = append(available, c)
 			}
+		case structs.Namespaces:
+			// Namespaces are filtered by the ACL when iterating
+			available = append(available, c)
+		case structs.Quotas:
+			if aclObj.AllowQuotaRead() {
+				available = append(available, c)
+			}
+		case structs.Plugins:
+			if aclObj.AllowNsOp(namespace, acl.NamespaceCapabilityReadVolume) {
+				available = append(available, c)
+			}
 		}
 	}
 	return available
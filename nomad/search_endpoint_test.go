@@ -756,3 +756,164 @@ func TestSearch_PrefixSearch_MultiRegion(t *testing.T) {
 	assert.Equal(job.ID, resp.Matches[structs.Jobs][0])
 	assert.Equal(uint64(jobIndex), resp.Index)
 }
+
+func TestSearch_FuzzySearch(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Parallel()
+	s := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0
+	})
+
+	defer s.Shutdown()
+	codec := rpcClient(t, s)
+	testutil.WaitForLeader(t, s.RPC)
+	state := s.fsm.State()
+
+	job := mock.Job()
+	job.ID = "frontend-web"
+	assert.Nil(state.UpsertJob(1000, job))
+
+	alloc := mock.Alloc()
+	alloc.Name = "frontend-web.web[0]"
+	assert.Nil(state.UpsertJobSummary(1001, mock.JobSummary(alloc.JobID)))
+	assert.Nil(state.UpsertAllocs(1002, []*structs.Allocation{alloc}))
+
+	node := mock.Node()
+	node.Name = "web-node-1"
+	assert.Nil(state.UpsertNode(1003, node))
+
+	volume := mock.Volume()
+	volume.PluginID = "aws-ebs-web"
+	other := mock.Volume()
+	other.PluginID = volume.PluginID
+	assert.Nil(state.UpsertVolumes(1004, []*structs.Volume{volume, other}))
+
+	req := &structs.FuzzySearchRequest{
+		Text:    "WEB",
+		Context: structs.All,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: structs.DefaultNamespace,
+		},
+	}
+
+	var resp structs.FuzzySearchResponse
+	assert.Nil(msgpackrpc.CallWithCodec(codec, "Search.FuzzySearch", req, &resp))
+	assert.Equal(uint64(1004), resp.Index)
+	assert.Equal([]structs.FuzzyMatch{{ID: job.ID, Scope: []string{job.Namespace}}}, resp.Matches[structs.Jobs])
+	assert.Equal([]structs.FuzzyMatch{{ID: alloc.Name, Scope: []string{alloc.Namespace, alloc.ID}}}, resp.Matches[structs.Allocs])
+	assert.Equal([]structs.FuzzyMatch{{ID: node.Name, Scope: []string{node.ID}}}, resp.Matches[structs.Nodes])
+	assert.Equal([]structs.FuzzyMatch{{ID: volume.PluginID}}, resp.Matches[structs.Plugins])
+	assert.Len(resp.Matches[structs.Namespaces], 0)
+	assert.False(resp.Truncations[structs.Jobs])
+
+	// Search a single context
+	req.Context = structs.Nodes
+	var resp2 structs.FuzzySearchResponse
+	assert.Nil(msgpackrpc.CallWithCodec(codec, "Search.FuzzySearch", req, &resp2))
+	assert.Len(resp2.Matches, 1)
+	assert.Len(resp2.Matches[structs.Nodes], 1)
+
+	// Evaluations can't be fuzzy searched
+	req.Context = structs.Evals
+	var resp3 structs.FuzzySearchResponse
+	assert.NotNil(msgpackrpc.CallWithCodec(codec, "Search.FuzzySearch", req, &resp3))
+}
+
+func TestSearch_FuzzySearch_Truncate(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Parallel()
+	s := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0
+	})
+
+	defer s.Shutdown()
+	codec := rpcClient(t, s)
+	testutil.WaitForLeader(t, s.RPC)
+
+	var job *structs.Job
+	for counter := 0; counter < 25; counter++ {
+		job = registerAndVerifyJob(s, t, "example-", counter)
+	}
+
+	req := &structs.FuzzySearchRequest{
+		Text:    "ample",
+		Context: structs.Jobs,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+
+	var resp structs.FuzzySearchResponse
+	assert.Nil(msgpackrpc.CallWithCodec(codec, "Search.FuzzySearch", req, &resp))
+	assert.Len(resp.Matches[structs.Jobs], truncateLimit)
+	assert.True(resp.Truncations[structs.Jobs])
+}
+
+func TestSearch_FuzzySearch_ACL(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Parallel()
+	s, root := TestACLServer(t, func(c *Config) {
+		c.NumSchedulers = 0
+	})
+
+	defer s.Shutdown()
+	codec := rpcClient(t, s)
+	testutil.WaitForLeader(t, s.RPC)
+	state := s.fsm.State()
+
+	job := registerAndVerifyJob(s, t, "example-", 0)
+	node := mock.Node()
+	node.Name = "example-node"
+	assert.Nil(state.UpsertNode(1001, node))
+
+	req := &structs.FuzzySearchRequest{
+		Text:    "example",
+		Context: structs.Jobs,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+
+	// Try without a token and expect failure
+	{
+		var resp structs.FuzzySearchResponse
+		err := msgpackrpc.CallWithCodec(codec, "Search.FuzzySearch", req, &resp)
+		assert.NotNil(err)
+		assert.Equal(err.Error(), structs.ErrPermissionDenied.Error())
+	}
+
+	// Try with a node:read token and expect failure due to Jobs being the context
+	validToken := mock.CreatePolicyAndToken(t, state, 1003, "test-valid", mock.NodePolicy(acl.PolicyRead))
+	req.AuthToken = validToken.SecretID
+	{
+		var resp structs.FuzzySearchResponse
+		err := msgpackrpc.CallWithCodec(codec, "Search.FuzzySearch", req, &resp)
+		assert.NotNil(err)
+		assert.Equal(err.Error(), structs.ErrPermissionDenied.Error())
+	}
+
+	// Try with a node:read token and expect only nodes in the All context
+	{
+		req.Context = structs.All
+		var resp structs.FuzzySearchResponse
+		assert.Nil(msgpackrpc.CallWithCodec(codec, "Search.FuzzySearch", req, &resp))
+		assert.Len(resp.Matches[structs.Nodes], 1)
+		assert.Len(resp.Matches[structs.Jobs], 0)
+	}
+
+	// Try with a management token
+	{
+		req.AuthToken = root.SecretID
+		var resp structs.FuzzySearchResponse
+		assert.Nil(msgpackrpc.CallWithCodec(codec, "Search.FuzzySearch", req, &resp))
+		assert.Len(resp.Matches[structs.Nodes], 1)
+		assert.Len(resp.Matches[structs.Jobs], 1)
+		assert.Equal(job.ID, resp.Matches[structs.Jobs][0].ID)
+	}
+}
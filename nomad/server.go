@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net"
+	"net/http"
 	"net/rpc"
 	"os"
 	"path/filepath"
@@ -19,13 +20,16 @@ import (
 	consulapi "github.com/hashicorp/consul/api"
 	"github.com/hashicorp/consul/lib"
 	log "github.com/hashicorp/go-hclog"
+	memdb "github.com/hashicorp/go-memdb"
 	multierror "github.com/hashicorp/go-multierror"
 	lru "github.com/hashicorp/golang-lru"
 	"github.com/hashicorp/nomad/command/agent/consul"
 	"github.com/hashicorp/nomad/helper/codec"
 	"github.com/hashicorp/nomad/helper/pool"
+	"github.com/hashicorp/nomad/helper/snapshot"
 	"github.com/hashicorp/nomad/helper/stats"
 	"github.com/hashicorp/nomad/helper/tlsutil"
+	"github.com/hashicorp/nomad/lib/auth"
 	"github.com/hashicorp/nomad/nomad/deploymentwatcher"
 	"github.com/hashicorp/nomad/nomad/drainer"
 	"github.com/hashicorp/nomad/nomad/state"
@@ -58,12 +62,6 @@ const (
 	serfSnapshot      = "serf/snapshot"
 	snapshotsRetained = 2
 
-	// serverRPCCache controls how long we keep an idle connection open to a server
-	serverRPCCache = 2 * time.Minute
-
-	// serverMaxStreams controls how many idle streams we keep open to a server
-	serverMaxStreams = 64
-
 	// raftLogCacheSize is the maximum number of logs to cache in-memory.
 	// This is used to reduce disk I/O for the recently committed entries.
 	raftLogCacheSize = 512
@@ -166,6 +164,11 @@ type Server struct {
 	// join/leave from the region.
 	reconcileCh chan serf.Member
 
+	// reassertLeaderCh is used to ask the leader to reestablish its
+	// leadership, rebuilding the leader state from the state store, for
+	// example after a snapshot was restored.
+	reassertLeaderCh chan chan error
+
 	// eventCh is used to receive events from the serf cluster
 	eventCh chan serf.Event
 
@@ -184,6 +187,13 @@ type Server struct {
 	// that are waiting to be brokered to a sub-scheduler
 	evalBroker *EvalBroker
 
+	// evalUpdateBatcher coalesces the evaluation updates of the schedulers
+	// into batched Raft log entries
+	evalUpdateBatcher *evalUpdateBatcher
+
+	// eventBroker keeps the recent cluster events for the event streams
+	eventBroker *EventBroker
+
 	// periodicDispatcher is used to track and create evaluations for periodic jobs.
 	periodicDispatcher *PeriodicDispatch
 
@@ -201,12 +211,38 @@ type Server struct {
 	// vault is the client for communicating with Vault.
 	vault VaultClient
 
-	// Worker used for processing
-	workers []*Worker
+	// encrypter encrypts and decrypts variables with the root keys
+	encrypter *Encrypter
+
+	// opaClient is the client the OPA policies are queried with
+	opaClient *http.Client
+
+	// admissionWebhookClients are the clients the admission webhooks are
+	// called with, in the order of their configuration
+	admissionWebhookClients []*http.Client
+
+	// snapshotStores are the stores the leader saves the snapshots of the
+	// snapshot agent in
+	snapshotStores []snapshot.Store
+
+	// Worker used for processing. The pool is resized when the scheduler
+	// configuration overrides the number of schedulers, and while this server
+	// is the leader most of the workers are paused.
+	workers       []*Worker
+	workersPaused bool
+	workerLock    sync.Mutex
 
 	// aclCache is used to maintain the parsed ACL objects
 	aclCache *lru.TwoQueueCache
 
+	// jwtVerifier verifies the tokens of JWT auth method logins, caching the
+	// key sets of the issuers
+	jwtVerifier *auth.JWTVerifier
+
+	// multiregionFanouts tracks the requests for multiregion jobs being
+	// fanned out to the regions of the jobs
+	multiregionFanouts *multiregionFanouts
+
 	// leaderAcl is the management ACL token that is valid when resolved by the
 	// current leader.
 	leaderAcl     string
@@ -229,6 +265,11 @@ type Server struct {
 type endpoints struct {
 	Status     *Status
 	Node       *Node
+	NodePool   *NodePool
+	Namespace  *Namespace
+	Quota      *Quota
+	Variables  *Variables
+	Keyring    *Keyring
 	Job        *Job
 	Eval       *Eval
 	Plan       *Plan
@@ -242,10 +283,22 @@ type endpoints struct {
 	ACL        *ACL
 	Enterprise *EnterpriseEndpoints
 
+	// Service discovery endpoints
+	ServiceRegistration *ServiceRegistration
+
+	// Storage endpoints
+	Volume *Volume
+
 	// Client endpoints
 	ClientStats       *ClientStats
 	FileSystem        *FileSystem
 	ClientAllocations *ClientAllocations
+	ClientNodeMeta    *ClientNodeMeta
+	ClientHostVolume  *ClientHostVolume
+	Agent             *Agent
+
+	// Event stream endpoints
+	Event *Event
 }
 
 // NewServer is used to construct a new Nomad server from the
@@ -265,6 +318,9 @@ func NewServer(config *Config, consulCatalog consul.CatalogAPI) (*Server, error)
 	if err != nil {
 		return nil, err
 	}
+	if config.EvalFairShare {
+		evalBroker.SetFairShare(config.EvalFairShareWeights)
+	}
 
 	// Configure TLS
 	tlsConf, err := tlsutil.NewTLSConfiguration(config.TLSConfig, true, true)
@@ -287,28 +343,40 @@ func NewServer(config *Config, consulCatalog consul.CatalogAPI) (*Server, error)
 
 	// Create the server
 	s := &Server{
-		config:        config,
-		consulCatalog: consulCatalog,
-		connPool:      pool.NewPool(logger, serverRPCCache, serverMaxStreams, tlsWrap),
-		logger:        logger,
-		tlsWrap:       tlsWrap,
-		rpcServer:     rpc.NewServer(),
-		streamingRpcs: structs.NewStreamingRpcRegistry(),
-		nodeConns:     make(map[string][]*nodeConnState),
-		peers:         make(map[string][]*serverParts),
-		localPeers:    make(map[raft.ServerAddress]*serverParts),
-		reconcileCh:   make(chan serf.Member, 32),
-		eventCh:       make(chan serf.Event, 256),
-		evalBroker:    evalBroker,
-		blockedEvals:  NewBlockedEvals(evalBroker, logger),
-		rpcTLS:        incomingTLS,
-		aclCache:      aclCache,
-		shutdownCh:    make(chan struct{}),
-	}
+		config:             config,
+		consulCatalog:      consulCatalog,
+		connPool:           pool.NewPool(logger, config.RPCConnIdleTimeout, config.RPCMaxIdleStreams, tlsWrap),
+		logger:             logger,
+		tlsWrap:            tlsWrap,
+		rpcServer:          rpc.NewServer(),
+		streamingRpcs:      structs.NewStreamingRpcRegistry(),
+		nodeConns:          make(map[string][]*nodeConnState),
+		peers:              make(map[string][]*serverParts),
+		localPeers:         make(map[raft.ServerAddress]*serverParts),
+		reconcileCh:        make(chan serf.Member, 32),
+		reassertLeaderCh:   make(chan chan error),
+		eventCh:            make(chan serf.Event, 256),
+		evalBroker:         evalBroker,
+		eventBroker:        NewEventBroker(config.EventBufferSize),
+		blockedEvals:       NewBlockedEvals(evalBroker, logger),
+		rpcTLS:             incomingTLS,
+		aclCache:           aclCache,
+		jwtVerifier:        auth.NewJWTVerifier(auth.DefaultKeySetTTL, auth.DefaultKeySetRefetchInterval),
+		multiregionFanouts: newMultiregionFanouts(),
+		shutdownCh:         make(chan struct{}),
+	}
+
+	s.evalUpdateBatcher = newEvalUpdateBatcher(s, config.EvalUpdateBatchSize)
 
 	// Create the RPC handler
 	s.rpcHandler = newRpcHandler(s)
 
+	// Create the encrypter of variables
+	s.encrypter, err = NewEncrypter(s, config.KeyringEncryptionKey)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create the planner
 	planner, err := newPlanner(s)
 	if err != nil {
@@ -332,6 +400,27 @@ func NewServer(config *Config, consulCatalog consul.CatalogAPI) (*Server, error)
 		return nil, fmt.Errorf("Failed to setup Vault client: %v", err)
 	}
 
+	// Setup the snapshot agent
+	if err := s.setupSnapshotAgent(); err != nil {
+		s.Shutdown()
+		s.logger.Error("failed to setup snapshot agent", "error", err)
+		return nil, fmt.Errorf("Failed to setup snapshot agent: %v", err)
+	}
+
+	// Setup the OPA policies
+	if err := s.setupOPA(); err != nil {
+		s.Shutdown()
+		s.logger.Error("failed to setup OPA policies", "error", err)
+		return nil, fmt.Errorf("Failed to setup OPA policies: %v", err)
+	}
+
+	// Setup the admission webhooks
+	if err := s.setupAdmissionWebhooks(); err != nil {
+		s.Shutdown()
+		s.logger.Error("failed to setup admission webhooks", "error", err)
+		return nil, fmt.Errorf("Failed to setup admission webhooks: %v", err)
+	}
+
 	// Initialize the RPC layer
 	if err := s.setupRPC(tlsWrap); err != nil {
 		s.Shutdown()
@@ -405,6 +494,9 @@ func NewServer(config *Config, consulCatalog consul.CatalogAPI) (*Server, error)
 	// Emit metrics
 	go s.heartbeatStats()
 
+	// Apply the runtime tunables of the scheduler configuration
+	go s.monitorSchedulerConfig()
+
 	// Start enterprise background workers
 	s.startEnterpriseBackground()
 
@@ -907,9 +999,15 @@ func (s *Server) setupDeploymentWatcher() error {
 		apply: s.raftApply,
 	}
 
+	// Create the multiregion shim used to coordinate the deployments of
+	// multiregion jobs with their peer regions
+	multiregionShim := &deploymentWatcherMultiregionShim{
+		srv: s,
+	}
+
 	// Create the deployment watcher
 	s.deploymentWatcher = deploymentwatcher.NewDeploymentsWatcher(
-		s.logger, raftShim,
+		s.logger, raftShim, multiregionShim,
 		deploymentwatcher.LimitStateQueriesPerSecond,
 		deploymentwatcher.CrossDeploymentUpdateBatchDuration)
 
@@ -1014,6 +1112,13 @@ func (s *Server) setupRpcServer(server *rpc.Server, ctx *RPCContext) {
 		s.staticEndpoints.Eval = &Eval{srv: s, logger: s.logger.Named("eval")}
 		s.staticEndpoints.Job = &Job{srv: s, logger: s.logger.Named("job")}
 		s.staticEndpoints.Node = &Node{srv: s, logger: s.logger.Named("client")} // Add but don't register
+		s.staticEndpoints.NodePool = &NodePool{srv: s, logger: s.logger.Named("node_pool")}
+		s.staticEndpoints.Namespace = &Namespace{srv: s, logger: s.logger.Named("namespace")}
+		s.staticEndpoints.Quota = &Quota{srv: s, logger: s.logger.Named("quota")}
+		s.staticEndpoints.Variables = &Variables{srv: s, logger: s.logger.Named("variables")}
+		s.staticEndpoints.Keyring = &Keyring{srv: s, logger: s.logger.Named("keyring")}
+		s.staticEndpoints.ServiceRegistration = &ServiceRegistration{srv: s, logger: s.logger.Named("service_registration")}
+		s.staticEndpoints.Volume = &Volume{srv: s, logger: s.logger.Named("volume")}
 		s.staticEndpoints.Deployment = &Deployment{srv: s, logger: s.logger.Named("deployment")}
 		s.staticEndpoints.Operator = &Operator{srv: s, logger: s.logger.Named("operator")}
 		s.staticEndpoints.Periodic = &Periodic{srv: s, logger: s.logger.Named("periodic")}
@@ -1027,10 +1132,18 @@ func (s *Server) setupRpcServer(server *rpc.Server, ctx *RPCContext) {
 		// Client endpoints
 		s.staticEndpoints.ClientStats = &ClientStats{srv: s, logger: s.logger.Named("client_stats")}
 		s.staticEndpoints.ClientAllocations = &ClientAllocations{srv: s, logger: s.logger.Named("client_allocs")}
+		s.staticEndpoints.ClientAllocations.register()
+		s.staticEndpoints.ClientNodeMeta = &ClientNodeMeta{srv: s, logger: s.logger.Named("client_node_meta")}
+		s.staticEndpoints.ClientHostVolume = &ClientHostVolume{srv: s, logger: s.logger.Named("client_host_volume")}
 
 		// Streaming endpoints
 		s.staticEndpoints.FileSystem = &FileSystem{srv: s, logger: s.logger.Named("client_fs")}
 		s.staticEndpoints.FileSystem.register()
+		s.staticEndpoints.Agent = &Agent{srv: s, logger: s.logger.Named("client_agent")}
+		s.staticEndpoints.Agent.register()
+		s.staticEndpoints.Operator.register()
+		s.staticEndpoints.Event = &Event{srv: s, logger: s.logger.Named("event")}
+		s.staticEndpoints.Event.register()
 	}
 
 	// Register the static handlers
@@ -1038,6 +1151,13 @@ func (s *Server) setupRpcServer(server *rpc.Server, ctx *RPCContext) {
 	server.Register(s.staticEndpoints.Alloc)
 	server.Register(s.staticEndpoints.Eval)
 	server.Register(s.staticEndpoints.Job)
+	server.Register(s.staticEndpoints.NodePool)
+	server.Register(s.staticEndpoints.Namespace)
+	server.Register(s.staticEndpoints.Quota)
+	server.Register(s.staticEndpoints.Variables)
+	server.Register(s.staticEndpoints.Keyring)
+	server.Register(s.staticEndpoints.ServiceRegistration)
+	server.Register(s.staticEndpoints.Volume)
 	server.Register(s.staticEndpoints.Deployment)
 	server.Register(s.staticEndpoints.Operator)
 	server.Register(s.staticEndpoints.Periodic)
@@ -1049,6 +1169,8 @@ func (s *Server) setupRpcServer(server *rpc.Server, ctx *RPCContext) {
 	s.staticEndpoints.Enterprise.Register(server)
 	server.Register(s.staticEndpoints.ClientStats)
 	server.Register(s.staticEndpoints.ClientAllocations)
+	server.Register(s.staticEndpoints.ClientNodeMeta)
+	server.Register(s.staticEndpoints.ClientHostVolume)
 	server.Register(s.staticEndpoints.FileSystem)
 
 	// Create new dynamic endpoints and add them to the RPC server.
@@ -1071,11 +1193,13 @@ func (s *Server) setupRaft() error {
 
 	// Create the FSM
 	fsmConfig := &FSMConfig{
-		EvalBroker: s.evalBroker,
-		Periodic:   s.periodicDispatcher,
-		Blocked:    s.blockedEvals,
-		Logger:     s.logger,
-		Region:     s.Region(),
+		EvalBroker:         s.evalBroker,
+		Periodic:           s.periodicDispatcher,
+		Blocked:            s.blockedEvals,
+		EventBroker:        s.eventBroker,
+		Logger:             s.logger,
+		Region:             s.Region(),
+		NodeEventRetention: s.config.NodeEventRetention,
 	}
 	var err error
 	s.fsm, err = NewFSM(fsmConfig)
@@ -1110,7 +1234,10 @@ func (s *Server) setupRaft() error {
 		s.raftInmem = store
 		stable = store
 		log = store
-		snap = raft.NewDiscardSnapshotStore()
+
+		// Keep the latest snapshot in memory so that snapshots can be
+		// saved and restored by operators in dev mode too.
+		snap = raft.NewInmemSnapshotStore()
 
 	} else {
 		// Create the base raft path
@@ -1317,6 +1444,86 @@ func (s *Server) setupWorkers() error {
 	return nil
 }
 
+// setNumWorkers starts or stops scheduling workers until n are running. While
+// this server is the leader, the workers are paused in the same proportion as
+// when leadership was established.
+func (s *Server) setNumWorkers(n int) error {
+	s.workerLock.Lock()
+	defer s.workerLock.Unlock()
+
+	current := len(s.workers)
+	if n == current {
+		return nil
+	}
+
+	for i := current; i < n; i++ {
+		w, err := NewWorker(s)
+		if err != nil {
+			return err
+		}
+		s.workers = append(s.workers, w)
+	}
+	for i := current - 1; i >= n; i-- {
+		s.workers[i].Stop()
+		s.workers[i] = nil
+	}
+	if n < current {
+		s.workers = s.workers[:n]
+	}
+
+	if s.workersPaused && n > 1 {
+		for i, w := range s.workers {
+			w.SetPause(i < 3*n/4)
+		}
+	}
+
+	s.logger.Info("resized scheduling worker pool", "from", current, "to", n)
+	return nil
+}
+
+// monitorSchedulerConfig watches the scheduler configuration and applies the
+// tunables that take effect on every server, such as the number of scheduling
+// workers and whether evaluations are dequeued by priority.
+func (s *Server) monitorSchedulerConfig() {
+	for {
+		state := s.fsm.State()
+		ws := memdb.NewWatchSet()
+		ws.Add(state.AbandonCh())
+		ws.Add(s.shutdownCh)
+
+		_, config, err := state.SchedulerConfigWithWatch(ws)
+		if err != nil {
+			s.logger.Error("failed to get scheduler configuration", "error", err)
+		} else {
+			s.applySchedulerConfig(config)
+		}
+
+		ws.Watch(nil)
+		if s.IsShutdown() {
+			return
+		}
+	}
+}
+
+// applySchedulerConfig applies the runtime tunables of the given scheduler
+// configuration, which may be nil if none has been stored yet.
+func (s *Server) applySchedulerConfig(config *structs.SchedulerConfiguration) {
+	s.evalBroker.SetEvalPriority(config == nil || !config.DisableEvalPriority)
+
+	// Servers that do not run schedulers are never resized
+	if len(s.config.EnabledSchedulers) == 0 || s.config.NumSchedulers == 0 {
+		return
+	}
+
+	numWorkers := s.config.NumSchedulers
+	if config != nil && config.NumSchedulers > 0 {
+		numWorkers = config.NumSchedulers
+	}
+	if err := s.setNumWorkers(numWorkers); err != nil {
+		s.logger.Error("failed to resize scheduling worker pool", "error", err)
+	}
+}
+
 // numPeers is used to check on the number of known peers, including the local
 // node.
 func (s *Server) numPeers() (int, error) {
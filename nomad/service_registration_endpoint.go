@@ -0,0 +1,302 @@
+package nomad
+
+import (
+	"fmt"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	log "github.com/hashicorp/go-hclog"
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// ServiceRegistration endpoint is used for registering and discovering the
+// services using the nomad provider
+type ServiceRegistration struct {
+	srv    *Server
+	logger log.Logger
+}
+
+// Upsert is used by clients to register the services of a task of an
+// allocation running on the node
+func (s *ServiceRegistration) Upsert(args *structs.ServiceRegistrationUpsertRequest, reply *structs.GenericResponse) error {
+	if done, err := s.srv.forward("ServiceRegistration.Upsert", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "service_registration", "upsert"}, time.Now())
+
+	if len(args.Services) == 0 {
+		return fmt.Errorf("missing services")
+	}
+
+	// Verify the node and the allocations of the services, the namespace,
+	// job and location of the services being set from them
+	snap, err := s.srv.fsm.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	node, err := s.verifyNode(snap, args.NodeID, args.SecretID)
+	if err != nil {
+		return err
+	}
+
+	services := make([]*structs.ServiceRegistration, len(args.Services))
+	for i, service := range args.Services {
+		if err := service.Validate(); err != nil {
+			return fmt.Errorf("service registration invalid: %v", err)
+		}
+		alloc, err := s.verifyAlloc(snap, node.ID, service.AllocID)
+		if err != nil {
+			return err
+		}
+
+		service = service.Copy()
+		service.Namespace = alloc.Namespace
+		service.JobID = alloc.JobID
+		service.NodeID = node.ID
+		service.Datacenter = node.Datacenter
+		services[i] = service
+	}
+
+	// Update via Raft, without the secret of the node
+	req := structs.ServiceRegistrationUpsertRequest{
+		NodeID:       args.NodeID,
+		Services:     services,
+		WriteRequest: args.WriteRequest,
+	}
+	fsmErr, index, err := s.srv.raftApply(structs.ServiceRegistrationUpsertRequestType, req)
+	if err, ok := fsmErr.(error); ok && err != nil {
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	reply.Index = index
+	return nil
+}
+
+// Delete is used by clients to delete the registrations of the services of a
+// task of an allocation running on the node
+func (s *ServiceRegistration) Delete(args *structs.ServiceRegistrationDeleteRequest, reply *structs.GenericResponse) error {
+	if done, err := s.srv.forward("ServiceRegistration.Delete", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "service_registration", "delete"}, time.Now())
+
+	if args.AllocID == "" {
+		return fmt.Errorf("missing allocation ID")
+	}
+	if args.Task == "" {
+		return fmt.Errorf("missing task name")
+	}
+
+	snap, err := s.srv.fsm.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	if _, err := s.verifyNode(snap, args.NodeID, args.SecretID); err != nil {
+		return err
+	}
+
+	// The registrations of deleted allocations are already deleted
+	alloc, err := snap.AllocByID(nil, args.AllocID)
+	if err != nil {
+		return err
+	}
+	if alloc == nil {
+		return nil
+	}
+	if alloc.NodeID != args.NodeID {
+		return fmt.Errorf("Allocation %q not running on Node %q", args.AllocID, args.NodeID)
+	}
+
+	// Update via Raft, without the secret of the node
+	req := *args
+	req.SecretID = ""
+	fsmErr, index, err := s.srv.raftApply(structs.ServiceRegistrationDeleteRequestType, &req)
+	if err, ok := fsmErr.(error); ok && err != nil {
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	reply.Index = index
+	return nil
+}
+
+// List is used to list the services of a namespace, along with the number of
+// their registered and healthy instances
+func (s *ServiceRegistration) List(args *structs.ServiceRegistrationListRequest, reply *structs.ServiceRegistrationListResponse) error {
+	if done, err := s.srv.forward("ServiceRegistration.List", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "service_registration", "list"}, time.Now())
+
+	// Check for read-job permissions
+	ns := args.RequestNamespace()
+	if aclObj, err := s.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNsOp(ns, acl.NamespaceCapabilityReadJob) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			var iter memdb.ResultIterator
+			var err error
+			if args.JobID != "" {
+				iter, err = state.ServiceRegistrationsByJobID(ws, ns, args.JobID)
+			} else {
+				iter, err = state.ServiceRegistrationsByNamespace(ws, ns)
+			}
+			if err != nil {
+				return err
+			}
+
+			services, err := s.withHealth(ws, state, iter)
+			if err != nil {
+				return err
+			}
+			reply.Services = structs.NewServiceRegistrationListStubs(services)
+
+			// Use the last index that affected the service registrations
+			return s.setIndex(state, &reply.QueryMeta)
+		}}
+	return s.srv.blockingRPC(&opts)
+}
+
+// GetService is used to read the registered instances of a service
+func (s *ServiceRegistration) GetService(args *structs.ServiceRegistrationByNameRequest, reply *structs.ServiceRegistrationByNameResponse) error {
+	if done, err := s.srv.forward("ServiceRegistration.GetService", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "service_registration", "get_service"}, time.Now())
+
+	ns := args.RequestNamespace()
+	if args.SecretID != "" {
+		// Verify the node and the allocation of the task, which can only
+		// read the services of its namespace
+		snap, err := s.srv.fsm.State().Snapshot()
+		if err != nil {
+			return err
+		}
+		node, err := s.verifyNode(snap, args.NodeID, args.SecretID)
+		if err != nil {
+			return err
+		}
+		alloc, err := s.verifyAlloc(snap, node.ID, args.AllocID)
+		if err != nil {
+			return err
+		}
+		ns = alloc.Namespace
+	} else if aclObj, err := s.srv.ResolveToken(args.AuthToken); err != nil {
+		// Check for read-job permissions
+		return err
+	} else if aclObj != nil && !aclObj.AllowNsOp(ns, acl.NamespaceCapabilityReadJob) {
+		return structs.ErrPermissionDenied
+	}
+
+	if args.ServiceName == "" {
+		return fmt.Errorf("missing service name")
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			iter, err := state.ServiceRegistrationsByServiceName(ws, ns, args.ServiceName)
+			if err != nil {
+				return err
+			}
+
+			services, err := s.withHealth(ws, state, iter)
+			if err != nil {
+				return err
+			}
+
+			reply.Services = nil
+			for _, service := range services {
+				if args.JobID == "" || service.JobID == args.JobID {
+					reply.Services = append(reply.Services, service)
+				}
+			}
+
+			// Use the last index that affected the service registrations
+			return s.setIndex(state, &reply.QueryMeta)
+		}}
+	return s.srv.blockingRPC(&opts)
+}
+
+// withHealth returns copies of the registrations of the iterator with their
+// health set from the state of their allocations.
+func (s *ServiceRegistration) withHealth(ws memdb.WatchSet, state *state.StateStore, iter memdb.ResultIterator) ([]*structs.ServiceRegistration, error) {
+	var services []*structs.ServiceRegistration
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		service := raw.(*structs.ServiceRegistration).Copy()
+		alloc, err := state.AllocByID(ws, service.AllocID)
+		if err != nil {
+			return nil, err
+		}
+		service.SetHealth(alloc)
+		services = append(services, service)
+	}
+	return services, nil
+}
+
+// verifyNode returns the node if it exists and has the SecretID.
+func (s *ServiceRegistration) verifyNode(state *state.StateSnapshot, nodeID, secretID string) (*structs.Node, error) {
+	if nodeID == "" {
+		return nil, fmt.Errorf("missing node ID")
+	}
+	node, err := state.NodeByID(nil, nodeID)
+	if err != nil {
+		return nil, err
+	}
+	if node == nil {
+		return nil, fmt.Errorf("Node %q does not exist", nodeID)
+	}
+	if node.SecretID != secretID {
+		return nil, structs.ErrPermissionDenied
+	}
+	return node, nil
+}
+
+// verifyAlloc returns the allocation if it exists and runs on the node.
+func (s *ServiceRegistration) verifyAlloc(state *state.StateSnapshot, nodeID, allocID string) (*structs.Allocation, error) {
+	alloc, err := state.AllocByID(nil, allocID)
+	if err != nil {
+		return nil, err
+	}
+	if alloc == nil {
+		return nil, fmt.Errorf("Allocation %q does not exist", allocID)
+	}
+	if alloc.NodeID != nodeID {
+		return nil, fmt.Errorf("Allocation %q not running on Node %q", allocID, nodeID)
+	}
+	return alloc, nil
+}
+
+// setIndex sets the index of the reply to the last index that affected the
+// service registrations table.
+func (s *ServiceRegistration) setIndex(state *state.StateStore, reply *structs.QueryMeta) error {
+	index, err := state.Index("service_registrations")
+	if err != nil {
+		return err
+	}
+
+	// Ensure we never set the index to zero, otherwise a blocking query cannot be used.
+	// We floor the index at one, since realistically the first write must have a higher index.
+	if index == 0 {
+		index = 1
+	}
+	reply.Index = index
+	return nil
+}
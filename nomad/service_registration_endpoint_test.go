@@ -0,0 +1,212 @@
+package nomad
+
+import (
+	"testing"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceRegistrationEndpoint_UpsertListDelete(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	node := mock.Node()
+	alloc := mock.Alloc()
+	alloc.NodeID = node.ID
+	alloc.ClientStatus = structs.AllocClientStatusRunning
+	task := alloc.Job.TaskGroups[0].Tasks[0]
+	alloc.TaskStates = map[string]*structs.TaskState{
+		task.Name: {State: structs.TaskStateRunning},
+	}
+	require.NoError(state.UpsertNode(1000, node))
+	require.NoError(state.UpsertJobSummary(1001, mock.JobSummary(alloc.JobID)))
+	require.NoError(state.UpsertAllocs(1002, []*structs.Allocation{alloc}))
+
+	service := &structs.Service{Name: "web", PortLabel: "http"}
+	upsert := &structs.ServiceRegistrationUpsertRequest{
+		NodeID:   node.ID,
+		SecretID: node.SecretID,
+		Services: []*structs.ServiceRegistration{{
+			ID:          structs.ServiceRegistrationID(alloc.ID, task.Name, service),
+			ServiceName: service.Name,
+			AllocID:     alloc.ID,
+			Task:        task.Name,
+			Tags:        []string{"primary"},
+			Address:     "10.0.0.1",
+			Port:        8080,
+		}},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var upsertResp structs.GenericResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "ServiceRegistration.Upsert", upsert, &upsertResp))
+	require.NotZero(upsertResp.Index)
+
+	// The node SecretID must match
+	upsert.SecretID = "foo"
+	err := msgpackrpc.CallWithCodec(codec, "ServiceRegistration.Upsert", upsert, &upsertResp)
+	require.EqualError(err, structs.ErrPermissionDenied.Error())
+
+	// List the services of the namespace
+	list := &structs.ServiceRegistrationListRequest{
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var listResp structs.ServiceRegistrationListResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "ServiceRegistration.List", list, &listResp))
+	require.Len(listResp.Services, 1)
+	require.Equal("web", listResp.Services[0].ServiceName)
+	require.Equal(1, listResp.Services[0].Instances)
+	require.Equal(1, listResp.Services[0].Healthy)
+	require.Equal(upsertResp.Index, listResp.Index)
+
+	list.JobID = "other"
+	var listResp2 structs.ServiceRegistrationListResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "ServiceRegistration.List", list, &listResp2))
+	require.Empty(listResp2.Services)
+
+	// Read the instances of the service, their location being set from the
+	// allocation and node
+	get := &structs.ServiceRegistrationByNameRequest{
+		ServiceName:  "web",
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var getResp structs.ServiceRegistrationByNameResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "ServiceRegistration.GetService", get, &getResp))
+	require.Len(getResp.Services, 1)
+	out := getResp.Services[0]
+	require.Equal(alloc.JobID, out.JobID)
+	require.Equal(alloc.Namespace, out.Namespace)
+	require.Equal(node.ID, out.NodeID)
+	require.Equal(node.Datacenter, out.Datacenter)
+	require.Equal("10.0.0.1", out.Address)
+	require.Equal(8080, out.Port)
+	require.Equal(structs.ServiceHealthPassing, out.Health)
+
+	// Delete the registrations of the task
+	del := &structs.ServiceRegistrationDeleteRequest{
+		NodeID:       node.ID,
+		SecretID:     node.SecretID,
+		AllocID:      alloc.ID,
+		Task:         task.Name,
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var delResp structs.GenericResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "ServiceRegistration.Delete", del, &delResp))
+
+	var getResp2 structs.ServiceRegistrationByNameResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "ServiceRegistration.GetService", get, &getResp2))
+	require.Empty(getResp2.Services)
+}
+
+func TestServiceRegistrationEndpoint_Upsert_WrongNode(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	node := mock.Node()
+	alloc := mock.Alloc()
+	require.NoError(state.UpsertNode(1000, node))
+	require.NoError(state.UpsertJobSummary(1001, mock.JobSummary(alloc.JobID)))
+	require.NoError(state.UpsertAllocs(1002, []*structs.Allocation{alloc}))
+
+	upsert := &structs.ServiceRegistrationUpsertRequest{
+		NodeID:   node.ID,
+		SecretID: node.SecretID,
+		Services: []*structs.ServiceRegistration{{
+			ID:          "_nomad-task-web",
+			ServiceName: "web",
+			AllocID:     alloc.ID,
+			Task:        "web",
+		}},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.GenericResponse
+	err := msgpackrpc.CallWithCodec(codec, "ServiceRegistration.Upsert", upsert, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "not running on Node")
+}
+
+func TestServiceRegistrationEndpoint_ACL(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1, root := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	readToken := mock.CreatePolicyAndToken(t, state, 1001, "read",
+		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityReadJob}))
+	otherToken := mock.CreatePolicyAndToken(t, state, 1002, "other",
+		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityListJobs}))
+
+	list := &structs.ServiceRegistrationListRequest{
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var listResp structs.ServiceRegistrationListResponse
+
+	// Try without a token
+	err := msgpackrpc.CallWithCodec(codec, "ServiceRegistration.List", list, &listResp)
+	require.EqualError(err, structs.ErrPermissionDenied.Error())
+
+	// Try with a token without read-job
+	list.AuthToken = otherToken.SecretID
+	err = msgpackrpc.CallWithCodec(codec, "ServiceRegistration.List", list, &listResp)
+	require.EqualError(err, structs.ErrPermissionDenied.Error())
+
+	// Try with a read token and a management token
+	list.AuthToken = readToken.SecretID
+	require.NoError(msgpackrpc.CallWithCodec(codec, "ServiceRegistration.List", list, &listResp))
+	list.AuthToken = root.SecretID
+	require.NoError(msgpackrpc.CallWithCodec(codec, "ServiceRegistration.List", list, &listResp))
+
+	get := &structs.ServiceRegistrationByNameRequest{
+		ServiceName:  "web",
+		QueryOptions: structs.QueryOptions{Region: "global", AuthToken: otherToken.SecretID},
+	}
+	var getResp structs.ServiceRegistrationByNameResponse
+	err = msgpackrpc.CallWithCodec(codec, "ServiceRegistration.GetService", get, &getResp)
+	require.EqualError(err, structs.ErrPermissionDenied.Error())
+
+	get.AuthToken = readToken.SecretID
+	require.NoError(msgpackrpc.CallWithCodec(codec, "ServiceRegistration.GetService", get, &getResp))
+
+	// Clients read the services for the tasks of their allocations without
+	// a token
+	node := mock.Node()
+	alloc := mock.Alloc()
+	alloc.NodeID = node.ID
+	require.NoError(state.UpsertNode(1003, node))
+	require.NoError(state.UpsertJobSummary(1004, mock.JobSummary(alloc.JobID)))
+	require.NoError(state.UpsertAllocs(1005, []*structs.Allocation{alloc}))
+
+	get.AuthToken = ""
+	get.NodeID = node.ID
+	get.SecretID = node.SecretID
+	get.AllocID = alloc.ID
+	require.NoError(msgpackrpc.CallWithCodec(codec, "ServiceRegistration.GetService", get, &getResp))
+
+	// The node SecretID must match
+	get.SecretID = "foo"
+	err = msgpackrpc.CallWithCodec(codec, "ServiceRegistration.GetService", get, &getResp)
+	require.EqualError(err, structs.ErrPermissionDenied.Error())
+
+	// The allocation must run on the node
+	get.SecretID = node.SecretID
+	get.AllocID = mock.Alloc().ID
+	err = msgpackrpc.CallWithCodec(codec, "ServiceRegistration.GetService", get, &getResp)
+	require.Error(err)
+}
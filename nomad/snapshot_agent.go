@@ -0,0 +1,196 @@
+package nomad
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	"github.com/hashicorp/nomad/helper/snapshot"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+)
+
+const (
+	// snapshotExtension is the extension of the names of the snapshots saved
+	// by the snapshot agent.
+	snapshotExtension = ".snap"
+)
+
+// setupSnapshotAgent creates the stores the leader saves the snapshots in, if
+// the snapshot agent is enabled.
+func (s *Server) setupSnapshotAgent() error {
+	conf := s.config.SnapshotAgentConfig
+	if !conf.IsEnabled() {
+		return nil
+	}
+	if conf.Interval <= 0 {
+		return fmt.Errorf("snapshot interval must be positive")
+	}
+	if conf.Retain < 1 {
+		return fmt.Errorf("snapshot retain must be at least 1")
+	}
+
+	stores, err := newSnapshotStores(conf)
+	if err != nil {
+		return err
+	}
+	if len(stores) == 0 {
+		return fmt.Errorf("snapshot agent requires at least one storage")
+	}
+	s.snapshotStores = stores
+	return nil
+}
+
+// newSnapshotStores returns the stores of the storages of the configuration.
+func newSnapshotStores(conf *config.SnapshotAgentConfig) ([]snapshot.Store, error) {
+	var stores []snapshot.Store
+
+	if l := conf.LocalStorage; l != nil {
+		store, err := snapshot.NewLocalStore(l.Path)
+		if err != nil {
+			return nil, err
+		}
+		stores = append(stores, store)
+	}
+
+	if s3 := conf.S3Storage; s3 != nil {
+		store, err := snapshot.NewS3Store(snapshot.S3StoreConfig{
+			Bucket:               s3.Bucket,
+			KeyPrefix:            s3.KeyPrefix,
+			Region:               s3.Region,
+			Endpoint:             s3.Endpoint,
+			ForcePathStyle:       s3.ForcePathStyle,
+			AccessKeyID:          s3.AccessKeyID,
+			SecretAccessKey:      s3.SecretAccessKey,
+			ServerSideEncryption: s3.ServerSideEncryption,
+		})
+		if err != nil {
+			return nil, err
+		}
+		stores = append(stores, store)
+	}
+
+	if gcs := conf.GCSStorage; gcs != nil {
+		store, err := snapshot.NewGCSStore(snapshot.S3StoreConfig{
+			Bucket:          gcs.Bucket,
+			KeyPrefix:       gcs.KeyPrefix,
+			Endpoint:        gcs.Endpoint,
+			AccessKeyID:     gcs.AccessKeyID,
+			SecretAccessKey: gcs.SecretAccessKey,
+		})
+		if err != nil {
+			return nil, err
+		}
+		stores = append(stores, store)
+	}
+
+	if azure := conf.AzureBlobStorage; azure != nil {
+		store, err := snapshot.NewAzureStore(snapshot.AzureStoreConfig{
+			AccountName:   azure.AccountName,
+			AccountKey:    azure.AccountKey,
+			ContainerName: azure.ContainerName,
+			KeyPrefix:     azure.KeyPrefix,
+			Endpoint:      azure.Endpoint,
+		})
+		if err != nil {
+			return nil, err
+		}
+		stores = append(stores, store)
+	}
+
+	return stores, nil
+}
+
+// saveSnapshots periodically saves a snapshot of the state to the stores of
+// the snapshot agent while the server is the leader.
+func (s *Server) saveSnapshots(stopCh chan struct{}) {
+	if len(s.snapshotStores) == 0 {
+		return
+	}
+
+	conf := s.config.SnapshotAgentConfig
+	ticker := time.NewTicker(conf.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+			if err := s.saveSnapshot(conf); err != nil {
+				metrics.IncrCounter([]string{"nomad", "snapshot_agent", "errors"}, 1)
+				s.logger.Error("failed to save snapshot", "error", err)
+			}
+		}
+	}
+}
+
+// saveSnapshot saves a snapshot of the state to every store and deletes the
+// snapshots beyond the retained ones.
+func (s *Server) saveSnapshot(conf *config.SnapshotAgentConfig) error {
+	defer metrics.MeasureSince([]string{"nomad", "snapshot_agent", "save"}, time.Now())
+
+	snap, err := snapshot.New(s.logger, s.raft)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := snap.Close(); err != nil {
+			s.logger.Error("failed to close snapshot", "error", err)
+		}
+	}()
+
+	// The names sort in the order the snapshots are taken
+	prefix := conf.NamePrefix + "-"
+	name := fmt.Sprintf("%s%019d%s", prefix, time.Now().UnixNano(), snapshotExtension)
+
+	// A failing store doesn't prevent saving the snapshot to the others
+	var failed int
+	for _, store := range s.snapshotStores {
+		if _, err := snap.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		if err := store.Put(name, snap); err != nil {
+			failed++
+			s.logger.Error("failed to save snapshot", "storage", store.Name(), "error", err)
+			continue
+		}
+		s.logger.Info("saved snapshot", "storage", store.Name(), "name", name, "index", snap.Index())
+
+		if err := pruneSnapshots(store, prefix, conf.Retain); err != nil {
+			s.logger.Warn("failed to delete old snapshots", "storage", store.Name(), "error", err)
+		}
+	}
+
+	if failed != 0 {
+		return fmt.Errorf("failed to save snapshot to %d of %d storages", failed, len(s.snapshotStores))
+	}
+	return nil
+}
+
+// pruneSnapshots deletes the oldest snapshots of the store with the prefix,
+// keeping the given number of snapshots.
+func pruneSnapshots(store snapshot.Store, prefix string, retain int) error {
+	all, err := store.List(prefix)
+	if err != nil {
+		return err
+	}
+
+	var names []string
+	for _, name := range all {
+		if strings.HasSuffix(name, snapshotExtension) {
+			names = append(names, name)
+		}
+	}
+	if len(names) <= retain {
+		return nil
+	}
+
+	for _, name := range names[:len(names)-retain] {
+		if err := store.Delete(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
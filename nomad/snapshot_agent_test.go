@@ -0,0 +1,122 @@
+package nomad
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/helper/snapshot"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshotAgent_Save(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "nomad-snapshots")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	s1 := TestServer(t, func(c *Config) {
+		c.SnapshotAgentConfig = &config.SnapshotAgentConfig{
+			Enabled:      helper.BoolToPtr(true),
+			Interval:     50 * time.Millisecond,
+			Retain:       2,
+			NamePrefix:   "test",
+			LocalStorage: &config.SnapshotLocalStorage{Path: dir},
+		}
+	})
+	defer s1.Shutdown()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Unrelated files aren't deleted
+	require.NoError(ioutil.WriteFile(filepath.Join(dir, "test-notes.txt"), nil, 0600))
+
+	// Snapshots are saved and the oldest ones deleted
+	var names []string
+	testutil.WaitForResult(func() (bool, error) {
+		var err error
+		names, err = filepath.Glob(filepath.Join(dir, "test-*.snap"))
+		if err != nil {
+			return false, err
+		}
+		return len(names) == 2, nil
+	}, func(err error) {
+		t.Fatalf("snapshots not saved: %v", names)
+	})
+
+	// Wait for more snapshots to assert the retention
+	time.Sleep(200 * time.Millisecond)
+	names, err = filepath.Glob(filepath.Join(dir, "test-*.snap"))
+	require.NoError(err)
+	require.Len(names, 2)
+	_, err = os.Stat(filepath.Join(dir, "test-notes.txt"))
+	require.NoError(err)
+
+	f, err := os.Open(names[0])
+	require.NoError(err)
+	defer f.Close()
+	_, err = snapshot.Verify(f)
+	require.NoError(err)
+}
+
+func TestSnapshotAgent_Setup(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		conf *config.SnapshotAgentConfig
+		err  string
+	}{
+		{
+			name: "disabled",
+			conf: &config.SnapshotAgentConfig{},
+		},
+		{
+			name: "no storage",
+			conf: &config.SnapshotAgentConfig{
+				Enabled:  helper.BoolToPtr(true),
+				Interval: time.Hour,
+				Retain:   1,
+			},
+			err: "at least one storage",
+		},
+		{
+			name: "no retain",
+			conf: &config.SnapshotAgentConfig{
+				Enabled:  helper.BoolToPtr(true),
+				Interval: time.Hour,
+			},
+			err: "retain",
+		},
+		{
+			name: "invalid storage",
+			conf: &config.SnapshotAgentConfig{
+				Enabled:   helper.BoolToPtr(true),
+				Interval:  time.Hour,
+				Retain:    1,
+				S3Storage: &config.SnapshotS3Storage{},
+			},
+			err: "requires a bucket",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			s := &Server{config: &Config{SnapshotAgentConfig: c.conf}}
+			err := s.setupSnapshotAgent()
+			if c.err == "" {
+				require.NoError(t, err)
+				require.Empty(t, s.snapshotStores)
+				return
+			}
+			require.Error(t, err)
+			require.Contains(t, err.Error(), c.err)
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"strings"
 	"time"
 
 	log "github.com/hashicorp/go-hclog"
@@ -34,6 +35,15 @@ type IndexEntry struct {
 	Value uint64
 }
 
+// QuotaUsageEntry is used with the "quota_usage" table for tracking the
+// resources used by the non-terminal allocations of the namespaces referencing
+// a quota specification. The usage is derived from the allocations and is not
+// persisted in snapshots.
+type QuotaUsageEntry struct {
+	Name string
+	Used *structs.QuotaLimit
+}
+
 // StateStoreConfig is used to configure a new state store
 type StateStoreConfig struct {
 	// Logger is used to output the state store's logs
@@ -41,6 +51,10 @@ type StateStoreConfig struct {
 
 	// Region is the region of the server embedding the state store.
 	Region string
+
+	// NodeEventRetention is the number of most recent events retained for
+	// each node. structs.MaxRetainedNodeEvents is used if unset.
+	NodeEventRetention int
 }
 
 // The StateStore is responsible for maintaining all the Nomad
@@ -77,6 +91,23 @@ func NewStateStore(config *StateStoreConfig) (*StateStore, error) {
 		config:    config,
 		abandonCh: make(chan struct{}),
 	}
+
+	// Create the built-in default node pool. It is part of every state
+	// store rather than written through Raft, so that it exists on every
+	// server without being recreated by other writes.
+	txn := db.Txn(true)
+	defer txn.Abort()
+	pool := &structs.NodePool{
+		Name:        structs.NodePoolDefault,
+		Description: "Default node pool",
+		CreateIndex: 1,
+		ModifyIndex: 1,
+	}
+	if err := txn.Insert("node_pools", pool); err != nil {
+		return nil, fmt.Errorf("default node pool insert failed: %v", err)
+	}
+	txn.Commit()
+
 	return s, nil
 }
 
@@ -85,6 +116,14 @@ func (s *StateStore) Config() *StateStoreConfig {
 	return s.config
 }
 
+// nodeEventRetention returns the number of events retained for each node.
+func (s *StateStore) nodeEventRetention() int {
+	if s.config == nil || s.config.NodeEventRetention <= 0 {
+		return structs.MaxRetainedNodeEvents
+	}
+	return s.config.NodeEventRetention
+}
+
 // Snapshot is used to create a point in time snapshot. Because
 // we use MemDB, we just need to snapshot the state of the underlying
 // database.
@@ -404,6 +443,10 @@ func (s *StateStore) Deployments(ws memdb.WatchSet) (memdb.ResultIterator, error
 }
 
 func (s *StateStore) DeploymentsByNamespace(ws memdb.WatchSet, namespace string) (memdb.ResultIterator, error) {
+	if namespace == structs.AllNamespacesSentinel {
+		return s.Deployments(ws)
+	}
+
 	txn := s.db.Txn(false)
 
 	// Walk the entire deployments table
@@ -441,7 +484,7 @@ func deploymentNamespaceFilter(namespace string) func(interface{}) bool {
 			return true
 		}
 
-		return d.Namespace != namespace
+		return namespace != structs.AllNamespacesSentinel && d.Namespace != namespace
 	}
 }
 
@@ -585,7 +628,7 @@ func (s *StateStore) UpsertNode(index uint64, node *structs.Node) error {
 
 		// If we are transitioning from down, record the re-registration
 		if exist.Status == structs.NodeStatusDown && node.Status != structs.NodeStatusDown {
-			appendNodeEvents(index, node, []*structs.NodeEvent{
+			s.appendNodeEvents(index, node, []*structs.NodeEvent{
 				structs.NewNodeEvent().SetSubsystem(structs.NodeEventSubsystemCluster).
 					SetMessage(NodeRegisterEventReregistered).
 					SetTimestamp(time.Unix(node.StatusUpdatedAt, 0))})
@@ -594,6 +637,15 @@ func (s *StateStore) UpsertNode(index uint64, node *structs.Node) error {
 		node.Drain = exist.Drain                                 // Retain the drain mode
 		node.SchedulingEligibility = exist.SchedulingEligibility // Retain the eligibility
 		node.DrainStrategy = exist.DrainStrategy                 // Retain the drain strategy
+		node.DynamicMeta = exist.DynamicMeta                     // Retain the dynamic metadata
+
+		// The dynamic metadata is part of the computed class but is not
+		// known to the client, so the class must be computed again.
+		if len(node.DynamicMeta) != 0 {
+			if err := node.ComputeClass(); err != nil {
+				return fmt.Errorf("failed to compute node class: %v", err)
+			}
+		}
 	} else {
 		// Because this is the first time the node is being registered, we should
 		// also create a node registration event
@@ -663,7 +715,7 @@ func (s *StateStore) UpdateNodeStatus(index uint64, nodeID, status string, event
 
 	// Add the event if given
 	if event != nil {
-		appendNodeEvents(index, copyNode, []*structs.NodeEvent{event})
+		s.appendNodeEvents(index, copyNode, []*structs.NodeEvent{event})
 	}
 
 	// Update the status in the copy
@@ -726,7 +778,7 @@ func (s *StateStore) updateNodeDrainImpl(txn *memdb.Txn, index uint64, nodeID st
 
 	// Add the event if given
 	if event != nil {
-		appendNodeEvents(index, copyNode, []*structs.NodeEvent{event})
+		s.appendNodeEvents(index, copyNode, []*structs.NodeEvent{event})
 	}
 
 	// Update the drain in the copy
@@ -772,7 +824,7 @@ func (s *StateStore) UpdateNodeEligibility(index uint64, nodeID string, eligibil
 
 	// Add the event if given
 	if event != nil {
-		appendNodeEvents(index, copyNode, []*structs.NodeEvent{event})
+		s.appendNodeEvents(index, copyNode, []*structs.NodeEvent{event})
 	}
 
 	// Check if this is a valid action
@@ -796,6 +848,52 @@ func (s *StateStore) UpdateNodeEligibility(index uint64, nodeID string, eligibil
 	return nil
 }
 
+// UpdateNodeDynamicMeta is used to set and unset keys of the dynamic metadata
+// of a node. The computed class of the node is updated to reflect the change.
+func (s *StateStore) UpdateNodeDynamicMeta(index uint64, nodeID string, set map[string]string, unset []string) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	// Lookup the node
+	existing, err := txn.First("nodes", "id", nodeID)
+	if err != nil {
+		return fmt.Errorf("node lookup failed: %v", err)
+	}
+	if existing == nil {
+		return fmt.Errorf("node not found")
+	}
+
+	// Copy the existing node and update its metadata
+	copyNode := existing.(*structs.Node).Copy()
+	if copyNode.DynamicMeta == nil {
+		copyNode.DynamicMeta = make(map[string]string, len(set))
+	}
+	for k, v := range set {
+		copyNode.DynamicMeta[k] = v
+	}
+	for _, k := range unset {
+		delete(copyNode.DynamicMeta, k)
+	}
+	if len(copyNode.DynamicMeta) == 0 {
+		copyNode.DynamicMeta = nil
+	}
+	if err := copyNode.ComputeClass(); err != nil {
+		return fmt.Errorf("failed to compute node class: %v", err)
+	}
+	copyNode.ModifyIndex = index
+
+	// Insert the node
+	if err := txn.Insert("nodes", copyNode); err != nil {
+		return fmt.Errorf("node update failed: %v", err)
+	}
+	if err := txn.Insert("index", &IndexEntry{"nodes", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	txn.Commit()
+	return nil
+}
+
 // UpsertNodeEvents adds the node events to the nodes, rotating events as
 // necessary.
 func (s *StateStore) UpsertNodeEvents(index uint64, nodeEvents map[string][]*structs.NodeEvent) error {
@@ -828,7 +926,7 @@ func (s *StateStore) upsertNodeEvents(index uint64, nodeID string, events []*str
 	// Copy the existing node
 	existingNode := existing.(*structs.Node)
 	copyNode := existingNode.Copy()
-	appendNodeEvents(index, copyNode, events)
+	s.appendNodeEvents(index, copyNode, events)
 
 	// Insert the node
 	if err := txn.Insert("nodes", copyNode); err != nil {
@@ -843,7 +941,7 @@ func (s *StateStore) upsertNodeEvents(index uint64, nodeID string, events []*str
 
 // appendNodeEvents is a helper that takes a node and new events and appends
 // them, pruning older events as needed.
-func appendNodeEvents(index uint64, node *structs.Node, events []*structs.NodeEvent) {
+func (s *StateStore) appendNodeEvents(index uint64, node *structs.Node, events []*structs.NodeEvent) {
 	// Add the events, updating the indexes
 	for _, e := range events {
 		e.CreateIndex = index
@@ -851,9 +949,8 @@ func appendNodeEvents(index uint64, node *structs.Node, events []*structs.NodeEv
 	}
 
 	// Keep node events pruned to not exceed the max allowed
-	if l := len(node.Events); l > structs.MaxRetainedNodeEvents {
-		delta := l - structs.MaxRetainedNodeEvents
-		node.Events = node.Events[delta:]
+	if l, max := len(node.Events), s.nodeEventRetention(); l > max {
+		node.Events = node.Events[l-max:]
 	}
 }
 
@@ -946,12 +1043,31 @@ func (s *StateStore) upsertJobImpl(index uint64, job *structs.Job, keepVersion b
 		return fmt.Errorf("job %q is in nonexistent namespace %q", job.ID, job.Namespace)
 	}
 
+	// Assert the node pool exists. The node pools of stopped jobs may have
+	// been deleted.
+	if pool := job.NodePool; pool != "" && pool != structs.NodePoolDefault && !job.Stopped() {
+		if existing, err := txn.First("node_pools", "id", pool); err != nil {
+			return fmt.Errorf("node pool lookup failed: %v", err)
+		} else if existing == nil {
+			return fmt.Errorf("job %q is in nonexistent node pool %q", job.ID, pool)
+		}
+	}
+
 	// Check if the job already exists
 	existing, err := txn.First("jobs", "id", job.Namespace, job.ID)
 	if err != nil {
 		return fmt.Errorf("job lookup failed: %v", err)
 	}
 
+	// Dispatched jobs sharing an idempotency token are registered only once
+	if existing == nil && job.DispatchIdempotencyToken != "" {
+		if dup, err := dispatchedJobByIdempotencyToken(nil, txn, job.Namespace, job.ParentID, job.DispatchIdempotencyToken); err != nil {
+			return err
+		} else if dup != nil {
+			return fmt.Errorf("job %q already dispatched with idempotency token %q", dup.ID, job.DispatchIdempotencyToken)
+		}
+	}
+
 	// Setup the indexes correctly
 	if existing != nil {
 		job.CreateIndex = existing.(*structs.Job).CreateIndex
@@ -1111,6 +1227,15 @@ func (s *StateStore) DeleteJobTxn(index uint64, namespace, jobID string, txn Txn
 		return fmt.Errorf("index update failed: %v", err)
 	}
 
+	// Delete the scaling events
+	if num, err := txn.DeleteAll("scaling_event", "id", namespace, jobID); err != nil {
+		return fmt.Errorf("deleting job scaling events failed: %v", err)
+	} else if num > 0 {
+		if err := txn.Insert("index", &IndexEntry{"scaling_event", index}); err != nil {
+			return fmt.Errorf("index update failed: %v", err)
+		}
+	}
+
 	return nil
 }
 
@@ -1239,6 +1364,37 @@ func (s *StateStore) JobByIDTxn(ws memdb.WatchSet, namespace, id string, txn Txn
 	return nil, nil
 }
 
+// DispatchedJobByIdempotencyToken returns the job dispatched from the given
+// parameterized job with the idempotency token or nil if there is none.
+// Stopped jobs are ignored so that a token may be reused once its job is
+// stopped.
+func (s *StateStore) DispatchedJobByIdempotencyToken(ws memdb.WatchSet, namespace, parentID, token string) (*structs.Job, error) {
+	txn := s.db.Txn(false)
+	return dispatchedJobByIdempotencyToken(ws, txn, namespace, parentID, token)
+}
+
+// dispatchedJobByIdempotencyToken is the implementation of
+// DispatchedJobByIdempotencyToken that uses the given transaction.
+func dispatchedJobByIdempotencyToken(ws memdb.WatchSet, txn *memdb.Txn, namespace, parentID, token string) (*structs.Job, error) {
+	iter, err := txn.Get("jobs", "id_prefix", namespace, parentID+structs.DispatchLaunchSuffix)
+	if err != nil {
+		return nil, fmt.Errorf("job lookup failed: %v", err)
+	}
+
+	ws.Add(iter.WatchCh())
+
+	for {
+		raw := iter.Next()
+		if raw == nil {
+			return nil, nil
+		}
+		job := raw.(*structs.Job)
+		if job.ParentID == parentID && job.DispatchIdempotencyToken == token && !job.Stop {
+			return job, nil
+		}
+	}
+}
+
 // JobsByIDPrefix is used to lookup a job by prefix
 func (s *StateStore) JobsByIDPrefix(ws memdb.WatchSet, namespace, id string) (memdb.ResultIterator, error) {
 	txn := s.db.Txn(false)
@@ -1248,6 +1404,22 @@ func (s *StateStore) JobsByIDPrefix(ws memdb.WatchSet, namespace, id string) (me
 		namespace = structs.DefaultNamespace
 	}
 
+	if namespace == structs.AllNamespacesSentinel {
+		iter, err := txn.Get("jobs", "id")
+		if err != nil {
+			return nil, fmt.Errorf("job lookup failed: %v", err)
+		}
+
+		ws.Add(iter.WatchCh())
+
+		// Wrap the iterator in a filter on the prefix of the job IDs
+		wrap := memdb.NewFilterIterator(iter, func(raw interface{}) bool {
+			job, ok := raw.(*structs.Job)
+			return !ok || !strings.HasPrefix(job.ID, id)
+		})
+		return wrap, nil
+	}
+
 	iter, err := txn.Get("jobs", "id_prefix", namespace, id)
 	if err != nil {
 		return nil, fmt.Errorf("job lookup failed: %v", err)
@@ -1378,8 +1550,12 @@ func (s *StateStore) Jobs(ws memdb.WatchSet) (memdb.ResultIterator, error) {
 	return iter, nil
 }
 
-// JobsByNamespace returns an iterator over all the jobs for the given namespace
+// JobsByNamespace returns an iterator over all the jobs for the given
+// namespace, or over all the jobs for the AllNamespacesSentinel namespace.
 func (s *StateStore) JobsByNamespace(ws memdb.WatchSet, namespace string) (memdb.ResultIterator, error) {
+	if namespace == structs.AllNamespacesSentinel {
+		return s.Jobs(ws)
+	}
 	txn := s.db.Txn(false)
 	return s.jobsByNamespaceImpl(ws, namespace, txn)
 }
@@ -1820,6 +1996,12 @@ func (s *StateStore) DeleteEval(index uint64, evals []string, allocs []string) e
 		if err := txn.Delete("allocs", raw); err != nil {
 			return fmt.Errorf("alloc delete failed: %v", err)
 		}
+		if err := updateQuotaUsageWithAlloc(txn, nil, raw.(*structs.Allocation)); err != nil {
+			return fmt.Errorf("error updating quota usage: %v", err)
+		}
+		if err := s.deleteServiceRegistrationsByAllocTxn(index, alloc, txn); err != nil {
+			return err
+		}
 	}
 
 	// Update the indexes
@@ -1888,7 +2070,7 @@ func evalNamespaceFilter(namespace string) func(interface{}) bool {
 			return true
 		}
 
-		return eval.Namespace != namespace
+		return namespace != structs.AllNamespacesSentinel && eval.Namespace != namespace
 	}
 }
 
@@ -1944,8 +2126,13 @@ func (s *StateStore) Evals(ws memdb.WatchSet) (memdb.ResultIterator, error) {
 }
 
 // EvalsByNamespace returns an iterator over all the evaluations in the given
+// namespace, or over all the evaluations for the AllNamespacesSentinel
 // namespace
 func (s *StateStore) EvalsByNamespace(ws memdb.WatchSet, namespace string) (memdb.ResultIterator, error) {
+	if namespace == structs.AllNamespacesSentinel {
+		return s.Evals(ws)
+	}
+
 	txn := s.db.Txn(false)
 
 	// Walk the entire table
@@ -1965,6 +2152,13 @@ func (s *StateStore) EvalsByNamespace(ws memdb.WatchSet, namespace string) (memd
 // the desired state comes from the schedulers, while the actual state comes
 // from clients.
 func (s *StateStore) UpdateAllocsFromClient(index uint64, allocs []*structs.Allocation) error {
+	return s.UpdateAllocsAndEvalsFromClient(index, allocs, nil)
+}
+
+// UpdateAllocsAndEvalsFromClient is used to update the allocations based on
+// input from the client and upsert the evaluations created for the update in
+// a single transaction.
+func (s *StateStore) UpdateAllocsAndEvalsFromClient(index uint64, allocs []*structs.Allocation, evals []*structs.Evaluation) error {
 	txn := s.db.Txn(true)
 	defer txn.Abort()
 
@@ -1980,6 +2174,13 @@ func (s *StateStore) UpdateAllocsFromClient(index uint64, allocs []*structs.Allo
 		return fmt.Errorf("index update failed: %v", err)
 	}
 
+	// Upsert the evaluations
+	if len(evals) != 0 {
+		if err := s.UpsertEvalsTxn(index, evals, txn); err != nil {
+			return err
+		}
+	}
+
 	txn.Commit()
 	return nil
 }
@@ -2010,6 +2211,7 @@ func (s *StateStore) nestedUpdateAllocFromClient(txn *memdb.Txn, index uint64, a
 	copyAlloc.ClientStatus = alloc.ClientStatus
 	copyAlloc.ClientDescription = alloc.ClientDescription
 	copyAlloc.TaskStates = alloc.TaskStates
+	copyAlloc.NetworkStatus = alloc.NetworkStatus
 
 	// The client can only set its deployment health and timestamp, so just take
 	// those
@@ -2045,6 +2247,10 @@ func (s *StateStore) nestedUpdateAllocFromClient(txn *memdb.Txn, index uint64, a
 		return fmt.Errorf("error updating job summary: %v", err)
 	}
 
+	if err := updateQuotaUsageWithAlloc(txn, copyAlloc, exist); err != nil {
+		return fmt.Errorf("error updating quota usage: %v", err)
+	}
+
 	if err := s.updateEntWithAlloc(index, copyAlloc, exist, txn); err != nil {
 		return err
 	}
@@ -2054,6 +2260,13 @@ func (s *StateStore) nestedUpdateAllocFromClient(txn *memdb.Txn, index uint64, a
 		return fmt.Errorf("alloc insert failed: %v", err)
 	}
 
+	// The services of terminal allocations are no longer reachable
+	if copyAlloc.ClientTerminalStatus() {
+		if err := s.deleteServiceRegistrationsByAllocTxn(index, copyAlloc.ID, txn); err != nil {
+			return err
+		}
+	}
+
 	// Set the job's status
 	forceStatus := ""
 	if !copyAlloc.TerminalStatus() {
@@ -2126,9 +2339,10 @@ func (s *StateStore) upsertAllocsImpl(index uint64, allocs []*structs.Allocation
 			// Keep the clients task states
 			alloc.TaskStates = exist.TaskStates
 
-			// If the scheduler is marking this allocation as lost we do not
-			// want to reuse the status of the existing allocation.
-			if alloc.ClientStatus != structs.AllocClientStatusLost {
+			// If the scheduler is marking this allocation as lost or unknown
+			// we do not want to reuse the status of the existing allocation.
+			if alloc.ClientStatus != structs.AllocClientStatusLost &&
+				alloc.ClientStatus != structs.AllocClientStatusUnknown {
 				alloc.ClientStatus = exist.ClientStatus
 				alloc.ClientDescription = exist.ClientDescription
 			}
@@ -2156,6 +2370,10 @@ func (s *StateStore) upsertAllocsImpl(index uint64, allocs []*structs.Allocation
 			return fmt.Errorf("error updating job summary: %v", err)
 		}
 
+		if err := updateQuotaUsageWithAlloc(txn, alloc, exist); err != nil {
+			return fmt.Errorf("error updating quota usage: %v", err)
+		}
+
 		if err := s.updateEntWithAlloc(index, alloc, exist, txn); err != nil {
 			return err
 		}
@@ -2164,6 +2382,13 @@ func (s *StateStore) upsertAllocsImpl(index uint64, allocs []*structs.Allocation
 			return fmt.Errorf("alloc insert failed: %v", err)
 		}
 
+		// The services of lost allocations are no longer reachable
+		if alloc.ClientTerminalStatus() {
+			if err := s.deleteServiceRegistrationsByAllocTxn(index, alloc.ID, txn); err != nil {
+				return err
+			}
+		}
+
 		if alloc.PreviousAllocation != "" {
 			prevAlloc, err := txn.First("allocs", "id", alloc.PreviousAllocation)
 			if err != nil {
@@ -2313,7 +2538,7 @@ func allocNamespaceFilter(namespace string) func(interface{}) bool {
 			return true
 		}
 
-		return alloc.Namespace != namespace
+		return namespace != structs.AllNamespacesSentinel && alloc.Namespace != namespace
 	}
 }
 
@@ -2472,8 +2697,13 @@ func (s *StateStore) Allocs(ws memdb.WatchSet) (memdb.ResultIterator, error) {
 }
 
 // AllocsByNamespace returns an iterator over all the allocations in the
+// namespace, or over all the allocations for the AllNamespacesSentinel
 // namespace
 func (s *StateStore) AllocsByNamespace(ws memdb.WatchSet, namespace string) (memdb.ResultIterator, error) {
+	if namespace == structs.AllNamespacesSentinel {
+		return s.Allocs(ws)
+	}
+
 	txn := s.db.Txn(false)
 	return s.allocsByNamespaceImpl(ws, txn, namespace)
 }
@@ -3097,6 +3327,8 @@ func (s *StateStore) ReconcileJobSummaries(index uint64) error {
 				tg.Failed += 1
 			case structs.AllocClientStatusLost:
 				tg.Lost += 1
+			case structs.AllocClientStatusUnknown:
+				tg.Unknown += 1
 			case structs.AllocClientStatusComplete:
 				tg.Complete += 1
 			case structs.AllocClientStatusRunning:
@@ -3568,6 +3800,8 @@ func (s *StateStore) updateSummaryWithAlloc(index uint64, alloc *structs.Allocat
 			tgSummary.Complete += 1
 		case structs.AllocClientStatusLost:
 			tgSummary.Lost += 1
+		case structs.AllocClientStatusUnknown:
+			tgSummary.Unknown += 1
 		}
 
 		// Decrementing the count of the bin of the last state
@@ -3578,6 +3812,8 @@ func (s *StateStore) updateSummaryWithAlloc(index uint64, alloc *structs.Allocat
 			tgSummary.Starting -= 1
 		case structs.AllocClientStatusLost:
 			tgSummary.Lost -= 1
+		case structs.AllocClientStatusUnknown:
+			tgSummary.Unknown -= 1
 		case structs.AllocClientStatusFailed, structs.AllocClientStatusComplete:
 		default:
 			s.logger.Error("invalid old client status for allocatio",
@@ -3627,41 +3863,35 @@ func (s *StateStore) addEphemeralDiskToTaskGroups(job *structs.Job) {
 	}
 }
 
-// UpsertACLPolicies is used to create or update a set of ACL policies
-func (s *StateStore) UpsertACLPolicies(index uint64, policies []*structs.ACLPolicy) error {
+// UpsertNodePools is used to create or update a set of node pools
+func (s *StateStore) UpsertNodePools(index uint64, pools []*structs.NodePool) error {
 	txn := s.db.Txn(true)
 	defer txn.Abort()
 
-	for _, policy := range policies {
-		// Ensure the policy hash is non-nil. This should be done outside the state store
-		// for performance reasons, but we check here for defense in depth.
-		if len(policy.Hash) == 0 {
-			policy.SetHash()
-		}
-
-		// Check if the policy already exists
-		existing, err := txn.First("acl_policy", "id", policy.Name)
+	for _, pool := range pools {
+		// Check if the node pool already exists
+		existing, err := txn.First("node_pools", "id", pool.Name)
 		if err != nil {
-			return fmt.Errorf("policy lookup failed: %v", err)
+			return fmt.Errorf("node pool lookup failed: %v", err)
 		}
 
 		// Update all the indexes
 		if existing != nil {
-			policy.CreateIndex = existing.(*structs.ACLPolicy).CreateIndex
-			policy.ModifyIndex = index
+			pool.CreateIndex = existing.(*structs.NodePool).CreateIndex
+			pool.ModifyIndex = index
 		} else {
-			policy.CreateIndex = index
-			policy.ModifyIndex = index
+			pool.CreateIndex = index
+			pool.ModifyIndex = index
 		}
 
-		// Update the policy
-		if err := txn.Insert("acl_policy", policy); err != nil {
-			return fmt.Errorf("upserting policy failed: %v", err)
+		// Update the node pool
+		if err := txn.Insert("node_pools", pool); err != nil {
+			return fmt.Errorf("upserting node pool failed: %v", err)
 		}
 	}
 
-	// Update the indexes tabl
-	if err := txn.Insert("index", &IndexEntry{"acl_policy", index}); err != nil {
+	// Update the indexes table
+	if err := txn.Insert("index", &IndexEntry{"node_pools", index}); err != nil {
 		return fmt.Errorf("index update failed: %v", err)
 	}
 
@@ -3669,59 +3899,78 @@ func (s *StateStore) UpsertACLPolicies(index uint64, policies []*structs.ACLPoli
 	return nil
 }
 
-// DeleteACLPolicies deletes the policies with the given names
-func (s *StateStore) DeleteACLPolicies(index uint64, names []string) error {
+// DeleteNodePools deletes the node pools with the given names. Node pools
+// that are built-in or still have nodes or jobs in them can not be deleted.
+func (s *StateStore) DeleteNodePools(index uint64, names []string) error {
 	txn := s.db.Txn(true)
 	defer txn.Abort()
 
-	// Delete the policy
 	for _, name := range names {
-		if _, err := txn.DeleteAll("acl_policy", "id", name); err != nil {
-			return fmt.Errorf("deleting acl policy failed: %v", err)
+		if err := nodePoolInUseTxn(txn, name); err != nil {
+			return err
+		}
+		if _, err := txn.DeleteAll("node_pools", "id", name); err != nil {
+			return fmt.Errorf("deleting node pool failed: %v", err)
 		}
 	}
-	if err := txn.Insert("index", &IndexEntry{"acl_policy", index}); err != nil {
+	if err := txn.Insert("index", &IndexEntry{"node_pools", index}); err != nil {
 		return fmt.Errorf("index update failed: %v", err)
 	}
 	txn.Commit()
 	return nil
 }
 
-// ACLPolicyByName is used to lookup a policy by name
-func (s *StateStore) ACLPolicyByName(ws memdb.WatchSet, name string) (*structs.ACLPolicy, error) {
-	txn := s.db.Txn(false)
+// nodePoolInUseTxn returns an error if the node pool can not be deleted
+// because it is built-in or still has nodes or jobs in it.
+func nodePoolInUseTxn(txn *memdb.Txn, name string) error {
+	if name == structs.NodePoolDefault {
+		return fmt.Errorf("node pool %q is built-in and can not be deleted", name)
+	}
 
-	watchCh, existing, err := txn.FirstWatch("acl_policy", "id", name)
+	nodes, err := txn.Get("nodes", "id")
 	if err != nil {
-		return nil, fmt.Errorf("acl policy lookup failed: %v", err)
+		return fmt.Errorf("node lookup failed: %v", err)
+	}
+	for raw := nodes.Next(); raw != nil; raw = nodes.Next() {
+		if node := raw.(*structs.Node); node.NodePool == name {
+			return fmt.Errorf("node pool %q has nodes in it", name)
+		}
 	}
-	ws.Add(watchCh)
 
-	if existing != nil {
-		return existing.(*structs.ACLPolicy), nil
+	jobs, err := txn.Get("jobs", "id")
+	if err != nil {
+		return fmt.Errorf("job lookup failed: %v", err)
 	}
-	return nil, nil
+	for raw := jobs.Next(); raw != nil; raw = jobs.Next() {
+		if job := raw.(*structs.Job); job.NodePool == name && !job.Stopped() {
+			return fmt.Errorf("node pool %q has jobs in it", name)
+		}
+	}
+	return nil
 }
 
-// ACLPolicyByNamePrefix is used to lookup policies by prefix
-func (s *StateStore) ACLPolicyByNamePrefix(ws memdb.WatchSet, prefix string) (memdb.ResultIterator, error) {
+// NodePoolByName is used to lookup a node pool by name
+func (s *StateStore) NodePoolByName(ws memdb.WatchSet, name string) (*structs.NodePool, error) {
 	txn := s.db.Txn(false)
 
-	iter, err := txn.Get("acl_policy", "id_prefix", prefix)
+	watchCh, existing, err := txn.FirstWatch("node_pools", "id", name)
 	if err != nil {
-		return nil, fmt.Errorf("acl policy lookup failed: %v", err)
+		return nil, fmt.Errorf("node pool lookup failed: %v", err)
 	}
-	ws.Add(iter.WatchCh())
+	ws.Add(watchCh)
 
-	return iter, nil
+	if existing != nil {
+		return existing.(*structs.NodePool), nil
+	}
+	return nil, nil
 }
 
-// ACLPolicies returns an iterator over all the acl policies
-func (s *StateStore) ACLPolicies(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+// NodePools returns an iterator over all the node pools
+func (s *StateStore) NodePools(ws memdb.WatchSet) (memdb.ResultIterator, error) {
 	txn := s.db.Txn(false)
 
 	// Walk the entire table
-	iter, err := txn.Get("acl_policy", "id")
+	iter, err := txn.Get("node_pools", "id")
 	if err != nil {
 		return nil, err
 	}
@@ -3729,134 +3978,134 @@ func (s *StateStore) ACLPolicies(ws memdb.WatchSet) (memdb.ResultIterator, error
 	return iter, nil
 }
 
-// UpsertACLTokens is used to create or update a set of ACL tokens
-func (s *StateStore) UpsertACLTokens(index uint64, tokens []*structs.ACLToken) error {
+// UpsertNamespaces is used to create or update a set of namespaces
+func (s *StateStore) UpsertNamespaces(index uint64, namespaces []*structs.Namespace) error {
 	txn := s.db.Txn(true)
 	defer txn.Abort()
 
-	for _, token := range tokens {
-		// Ensure the policy hash is non-nil. This should be done outside the state store
-		// for performance reasons, but we check here for defense in depth.
-		if len(token.Hash) == 0 {
-			token.SetHash()
-		}
-
-		// Check if the token already exists
-		existing, err := txn.First("acl_token", "id", token.AccessorID)
+	for _, ns := range namespaces {
+		// Check if the namespace already exists
+		existing, err := txn.First("namespaces", "id", ns.Name)
 		if err != nil {
-			return fmt.Errorf("token lookup failed: %v", err)
+			return fmt.Errorf("namespace lookup failed: %v", err)
 		}
 
 		// Update all the indexes
+		var quota string
 		if existing != nil {
-			existTK := existing.(*structs.ACLToken)
-			token.CreateIndex = existTK.CreateIndex
-			token.ModifyIndex = index
-
-			// Do not allow SecretID or create time to change
-			token.SecretID = existTK.SecretID
-			token.CreateTime = existTK.CreateTime
-
+			ns.CreateIndex = existing.(*structs.Namespace).CreateIndex
+			ns.ModifyIndex = index
+			quota = existing.(*structs.Namespace).Quota
 		} else {
-			token.CreateIndex = index
-			token.ModifyIndex = index
+			ns.CreateIndex = index
+			ns.ModifyIndex = index
 		}
 
-		// Update the token
-		if err := txn.Insert("acl_token", token); err != nil {
-			return fmt.Errorf("upserting token failed: %v", err)
+		// Move the usage of the namespace if its quota changed
+		if err := moveNamespaceQuotaUsage(txn, ns.Name, quota, ns.Quota); err != nil {
+			return fmt.Errorf("updating quota usage failed: %v", err)
+		}
+
+		// Update the namespace
+		if err := txn.Insert("namespaces", ns); err != nil {
+			return fmt.Errorf("upserting namespace failed: %v", err)
 		}
 	}
 
 	// Update the indexes table
-	if err := txn.Insert("index", &IndexEntry{"acl_token", index}); err != nil {
+	if err := txn.Insert("index", &IndexEntry{"namespaces", index}); err != nil {
 		return fmt.Errorf("index update failed: %v", err)
 	}
+
 	txn.Commit()
 	return nil
 }
 
-// DeleteACLTokens deletes the tokens with the given accessor ids
-func (s *StateStore) DeleteACLTokens(index uint64, ids []string) error {
+// DeleteNamespaces deletes the namespaces with the given names
+func (s *StateStore) DeleteNamespaces(index uint64, names []string) error {
 	txn := s.db.Txn(true)
 	defer txn.Abort()
 
-	// Delete the tokens
-	for _, id := range ids {
-		if _, err := txn.DeleteAll("acl_token", "id", id); err != nil {
-			return fmt.Errorf("deleting acl token failed: %v", err)
+	for _, name := range names {
+		existing, err := s.namespaceByNameImpl(nil, txn, name)
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			continue
+		}
+		if err := moveNamespaceQuotaUsage(txn, name, existing.Quota, ""); err != nil {
+			return fmt.Errorf("updating quota usage failed: %v", err)
+		}
+		if err := txn.Delete("namespaces", existing); err != nil {
+			return fmt.Errorf("deleting namespace failed: %v", err)
 		}
 	}
-	if err := txn.Insert("index", &IndexEntry{"acl_token", index}); err != nil {
+	if err := txn.Insert("index", &IndexEntry{"namespaces", index}); err != nil {
 		return fmt.Errorf("index update failed: %v", err)
 	}
 	txn.Commit()
 	return nil
 }
 
-// ACLTokenByAccessorID is used to lookup a token by accessor ID
-func (s *StateStore) ACLTokenByAccessorID(ws memdb.WatchSet, id string) (*structs.ACLToken, error) {
+// NamespaceByName is used to lookup a namespace by name
+func (s *StateStore) NamespaceByName(ws memdb.WatchSet, name string) (*structs.Namespace, error) {
 	txn := s.db.Txn(false)
-
-	watchCh, existing, err := txn.FirstWatch("acl_token", "id", id)
-	if err != nil {
-		return nil, fmt.Errorf("acl token lookup failed: %v", err)
-	}
-	ws.Add(watchCh)
-
-	if existing != nil {
-		return existing.(*structs.ACLToken), nil
-	}
-	return nil, nil
+	return s.namespaceByNameImpl(ws, txn, name)
 }
 
-// ACLTokenBySecretID is used to lookup a token by secret ID
-func (s *StateStore) ACLTokenBySecretID(ws memdb.WatchSet, secretID string) (*structs.ACLToken, error) {
-	txn := s.db.Txn(false)
-
-	watchCh, existing, err := txn.FirstWatch("acl_token", "secret", secretID)
+// namespaceByNameImpl is used to lookup a namespace by name in the given
+// transaction
+func (s *StateStore) namespaceByNameImpl(ws memdb.WatchSet, txn *memdb.Txn, name string) (*structs.Namespace, error) {
+	watchCh, existing, err := txn.FirstWatch("namespaces", "id", name)
 	if err != nil {
-		return nil, fmt.Errorf("acl token lookup failed: %v", err)
+		return nil, fmt.Errorf("namespace lookup failed: %v", err)
 	}
 	ws.Add(watchCh)
 
 	if existing != nil {
-		return existing.(*structs.ACLToken), nil
+		return existing.(*structs.Namespace), nil
 	}
 	return nil, nil
 }
 
-// ACLTokenByAccessorIDPrefix is used to lookup tokens by prefix
-func (s *StateStore) ACLTokenByAccessorIDPrefix(ws memdb.WatchSet, prefix string) (memdb.ResultIterator, error) {
+// Namespaces returns an iterator over all the namespaces
+func (s *StateStore) Namespaces(ws memdb.WatchSet) (memdb.ResultIterator, error) {
 	txn := s.db.Txn(false)
 
-	iter, err := txn.Get("acl_token", "id_prefix", prefix)
+	// Walk the entire table
+	iter, err := txn.Get("namespaces", "id")
 	if err != nil {
-		return nil, fmt.Errorf("acl token lookup failed: %v", err)
+		return nil, err
 	}
 	ws.Add(iter.WatchCh())
 	return iter, nil
 }
 
-// ACLTokens returns an iterator over all the tokens
-func (s *StateStore) ACLTokens(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+// NamespacesByNamePrefix is used to lookup namespaces by name prefix
+func (s *StateStore) NamespacesByNamePrefix(ws memdb.WatchSet, prefix string) (memdb.ResultIterator, error) {
 	txn := s.db.Txn(false)
 
-	// Walk the entire table
-	iter, err := txn.Get("acl_token", "id")
+	iter, err := txn.Get("namespaces", "id_prefix", prefix)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("namespaces lookup failed: %v", err)
 	}
 	ws.Add(iter.WatchCh())
+
 	return iter, nil
 }
 
-// ACLTokensByGlobal returns an iterator over all the tokens filtered by global value
-func (s *StateStore) ACLTokensByGlobal(ws memdb.WatchSet, globalVal bool) (memdb.ResultIterator, error) {
+// NamespacesByQuota returns an iterator over all the namespaces that
+// reference the given quota specification
+func (s *StateStore) NamespacesByQuota(ws memdb.WatchSet, quota string) (memdb.ResultIterator, error) {
 	txn := s.db.Txn(false)
+	return s.namespacesByQuotaImpl(ws, txn, quota)
+}
 
-	// Walk the entire table
-	iter, err := txn.Get("acl_token", "global", globalVal)
+// namespacesByQuotaImpl returns an iterator over all the namespaces that
+// reference the given quota specification in the given transaction
+func (s *StateStore) namespacesByQuotaImpl(ws memdb.WatchSet, txn *memdb.Txn, quota string) (memdb.ResultIterator, error) {
+	iter, err := txn.Get("namespaces", "quota", quota)
 	if err != nil {
 		return nil, err
 	}
@@ -3864,74 +4113,1601 @@ func (s *StateStore) ACLTokensByGlobal(ws memdb.WatchSet, globalVal bool) (memdb
 	return iter, nil
 }
 
-// CanBootstrapACLToken checks if bootstrapping is possible and returns the reset index
-func (s *StateStore) CanBootstrapACLToken() (bool, uint64, error) {
-	txn := s.db.Txn(false)
+// UpsertQuotaSpecs is used to create or update a set of quota specifications
+func (s *StateStore) UpsertQuotaSpecs(index uint64, specs []*structs.QuotaSpec) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
 
-	// Lookup the bootstrap sentinel
+	for _, spec := range specs {
+		// Ensure the limits are hashed
+		spec.SetHash()
+
+		// Check if the quota spec already exists
+		existing, err := txn.First("quota_specs", "id", spec.Name)
+		if err != nil {
+			return fmt.Errorf("quota spec lookup failed: %v", err)
+		}
+
+		// Update all the indexes
+		if existing != nil {
+			spec.CreateIndex = existing.(*structs.QuotaSpec).CreateIndex
+			spec.ModifyIndex = index
+		} else {
+			spec.CreateIndex = index
+			spec.ModifyIndex = index
+		}
+
+		// Update the quota spec
+		if err := txn.Insert("quota_specs", spec); err != nil {
+			return fmt.Errorf("upserting quota spec failed: %v", err)
+		}
+	}
+
+	// Update the indexes table
+	if err := txn.Insert("index", &IndexEntry{"quota_specs", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	txn.Commit()
+	return nil
+}
+
+// DeleteQuotaSpecs deletes the quota specifications with the given names
+func (s *StateStore) DeleteQuotaSpecs(index uint64, names []string) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	for _, name := range names {
+		if _, err := txn.DeleteAll("quota_specs", "id", name); err != nil {
+			return fmt.Errorf("deleting quota spec failed: %v", err)
+		}
+	}
+	if err := txn.Insert("index", &IndexEntry{"quota_specs", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	txn.Commit()
+	return nil
+}
+
+// QuotaSpecByName is used to lookup a quota specification by name
+func (s *StateStore) QuotaSpecByName(ws memdb.WatchSet, name string) (*structs.QuotaSpec, error) {
+	txn := s.db.Txn(false)
+	return s.quotaSpecByNameImpl(ws, txn, name)
+}
+
+// quotaSpecByNameImpl is used to lookup a quota specification by name in the
+// given transaction
+func (s *StateStore) quotaSpecByNameImpl(ws memdb.WatchSet, txn *memdb.Txn, name string) (*structs.QuotaSpec, error) {
+	watchCh, existing, err := txn.FirstWatch("quota_specs", "id", name)
+	if err != nil {
+		return nil, fmt.Errorf("quota spec lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if existing != nil {
+		return existing.(*structs.QuotaSpec), nil
+	}
+	return nil, nil
+}
+
+// QuotaSpecs returns an iterator over all the quota specifications
+func (s *StateStore) QuotaSpecs(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.Txn(false)
+
+	// Walk the entire table
+	iter, err := txn.Get("quota_specs", "id")
+	if err != nil {
+		return nil, err
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// QuotaSpecsByNamePrefix is used to lookup quota specifications by name
+// prefix
+func (s *StateStore) QuotaSpecsByNamePrefix(ws memdb.WatchSet, prefix string) (memdb.ResultIterator, error) {
+	txn := s.db.Txn(false)
+
+	iter, err := txn.Get("quota_specs", "id_prefix", prefix)
+	if err != nil {
+		return nil, fmt.Errorf("quota specs lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	return iter, nil
+}
+
+// UpsertVariable is used to create or update an encrypted variable. If the
+// check index is set, the variable is only written if its modify index is
+// the check index, a check index of zero meaning it must not exist.
+func (s *StateStore) UpsertVariable(index uint64, v *structs.VariableEncrypted, checkIndex *uint64) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	// Assert the namespace exists
+	if exists, err := s.namespaceExists(txn, v.Namespace); err != nil {
+		return err
+	} else if !exists {
+		return fmt.Errorf("variable %q is in nonexistent namespace %q", v.Path, v.Namespace)
+	}
+
+	// Check if the variable already exists
+	existing, err := txn.First("variables", "id", v.Namespace, v.Path)
+	if err != nil {
+		return fmt.Errorf("variable lookup failed: %v", err)
+	}
+	if err := checkVariableIndex(existing, v.Path, checkIndex); err != nil {
+		return err
+	}
+
+	// Update all the indexes
+	if existing != nil {
+		exist := existing.(*structs.VariableEncrypted)
+		v.CreateIndex = exist.CreateIndex
+		v.CreateTime = exist.CreateTime
+		v.ModifyIndex = index
+	} else {
+		v.CreateIndex = index
+		v.ModifyIndex = index
+	}
+
+	// Update the variable
+	if err := txn.Insert("variables", v); err != nil {
+		return fmt.Errorf("upserting variable failed: %v", err)
+	}
+
+	// Update the indexes table
+	if err := txn.Insert("index", &IndexEntry{"variables", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	txn.Commit()
+	return nil
+}
+
+// DeleteVariable deletes the variable at the given path of a namespace. If
+// the check index is set, the variable is only deleted if its modify index is
+// the check index.
+func (s *StateStore) DeleteVariable(index uint64, namespace, path string, checkIndex *uint64) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	existing, err := txn.First("variables", "id", namespace, path)
+	if err != nil {
+		return fmt.Errorf("variable lookup failed: %v", err)
+	}
+	if err := checkVariableIndex(existing, path, checkIndex); err != nil {
+		return err
+	}
+	if existing == nil {
+		return nil
+	}
+
+	if err := txn.Delete("variables", existing); err != nil {
+		return fmt.Errorf("deleting variable failed: %v", err)
+	}
+	if err := txn.Insert("index", &IndexEntry{"variables", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	txn.Commit()
+	return nil
+}
+
+// checkVariableIndex returns a check-and-set conflict error if the check
+// index is set and doesn't match the modify index of the existing variable.
+func checkVariableIndex(existing interface{}, path string, checkIndex *uint64) error {
+	if checkIndex == nil {
+		return nil
+	}
+
+	var current uint64
+	if existing != nil {
+		current = existing.(*structs.VariableEncrypted).ModifyIndex
+	}
+	if current != *checkIndex {
+		return fmt.Errorf("%s: variable %q modify index is %d, not %d",
+			structs.ErrCASConflictPrefix, path, current, *checkIndex)
+	}
+	return nil
+}
+
+// VariableByPath is used to lookup the variable at the given path of a
+// namespace
+func (s *StateStore) VariableByPath(ws memdb.WatchSet, namespace, path string) (*structs.VariableEncrypted, error) {
+	txn := s.db.Txn(false)
+
+	watchCh, existing, err := txn.FirstWatch("variables", "id", namespace, path)
+	if err != nil {
+		return nil, fmt.Errorf("variable lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if existing != nil {
+		return existing.(*structs.VariableEncrypted), nil
+	}
+	return nil, nil
+}
+
+// VariablesByPathPrefix is used to lookup the variables of a namespace by
+// path prefix
+func (s *StateStore) VariablesByPathPrefix(ws memdb.WatchSet, namespace, prefix string) (memdb.ResultIterator, error) {
+	txn := s.db.Txn(false)
+
+	iter, err := txn.Get("variables", "id_prefix", namespace, prefix)
+	if err != nil {
+		return nil, fmt.Errorf("variables lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	return iter, nil
+}
+
+// Variables returns an iterator over the variables of all the namespaces
+func (s *StateStore) Variables(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.Txn(false)
+
+	// Walk the entire table
+	iter, err := txn.Get("variables", "id")
+	if err != nil {
+		return nil, err
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// VariablesByKeyID returns an iterator over the variables encrypted with the
+// given root key
+func (s *StateStore) VariablesByKeyID(ws memdb.WatchSet, keyID string) (memdb.ResultIterator, error) {
+	txn := s.db.Txn(false)
+
+	iter, err := txn.Get("variables", "keyid", keyID)
+	if err != nil {
+		return nil, fmt.Errorf("variables lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	return iter, nil
+}
+
+// UpsertRootKey is used to create or update a root key. If the key is
+// active, the previously active key is deactivated.
+func (s *StateStore) UpsertRootKey(index uint64, key *structs.RootKey) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	existing, err := txn.First("root_keys", "id", key.KeyID)
+	if err != nil {
+		return fmt.Errorf("root key lookup failed: %v", err)
+	}
+	if existing != nil {
+		key.CreateIndex = existing.(*structs.RootKey).CreateIndex
+		key.ModifyIndex = index
+	} else {
+		key.CreateIndex = index
+		key.ModifyIndex = index
+	}
+
+	if key.Active {
+		iter, err := txn.Get("root_keys", "id")
+		if err != nil {
+			return fmt.Errorf("root keys lookup failed: %v", err)
+		}
+		var deactivated []*structs.RootKey
+		for raw := iter.Next(); raw != nil; raw = iter.Next() {
+			other := raw.(*structs.RootKey)
+			if other.Active && other.KeyID != key.KeyID {
+				inactive := other.Copy()
+				inactive.Active = false
+				inactive.ModifyIndex = index
+				deactivated = append(deactivated, inactive)
+			}
+		}
+		for _, other := range deactivated {
+			if err := txn.Insert("root_keys", other); err != nil {
+				return fmt.Errorf("updating root key failed: %v", err)
+			}
+		}
+	}
+
+	if err := txn.Insert("root_keys", key); err != nil {
+		return fmt.Errorf("upserting root key failed: %v", err)
+	}
+	if err := txn.Insert("index", &IndexEntry{"root_keys", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	txn.Commit()
+	return nil
+}
+
+// DeleteRootKey is used to delete a root key. The active root key and the
+// root keys variables are still encrypted with can't be deleted.
+func (s *StateStore) DeleteRootKey(index uint64, keyID string) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	existing, err := txn.First("root_keys", "id", keyID)
+	if err != nil {
+		return fmt.Errorf("root key lookup failed: %v", err)
+	}
+	if existing == nil {
+		return fmt.Errorf("root key %q not found", keyID)
+	}
+	if existing.(*structs.RootKey).Active {
+		return fmt.Errorf("root key %q is active", keyID)
+	}
+
+	used, err := txn.First("variables", "keyid", keyID)
+	if err != nil {
+		return fmt.Errorf("variables lookup failed: %v", err)
+	}
+	if used != nil {
+		return fmt.Errorf("root key %q is in use by variables", keyID)
+	}
+
+	if err := txn.Delete("root_keys", existing); err != nil {
+		return fmt.Errorf("deleting root key failed: %v", err)
+	}
+	if err := txn.Insert("index", &IndexEntry{"root_keys", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	txn.Commit()
+	return nil
+}
+
+// RootKeyByID is used to lookup a root key by ID
+func (s *StateStore) RootKeyByID(ws memdb.WatchSet, id string) (*structs.RootKey, error) {
+	txn := s.db.Txn(false)
+
+	watchCh, existing, err := txn.FirstWatch("root_keys", "id", id)
+	if err != nil {
+		return nil, fmt.Errorf("root key lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if existing != nil {
+		return existing.(*structs.RootKey), nil
+	}
+	return nil, nil
+}
+
+// ActiveRootKey returns the root key new variables are encrypted with, or
+// nil if there is none
+func (s *StateStore) ActiveRootKey(ws memdb.WatchSet) (*structs.RootKey, error) {
+	iter, err := s.RootKeys(ws)
+	if err != nil {
+		return nil, err
+	}
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		if key := raw.(*structs.RootKey); key.Active {
+			return key, nil
+		}
+	}
+	return nil, nil
+}
+
+// RootKeys returns an iterator over all the root keys
+func (s *StateStore) RootKeys(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.Txn(false)
+
+	// Walk the entire table
+	iter, err := txn.Get("root_keys", "id")
+	if err != nil {
+		return nil, err
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// UpsertScalingEvent is used to record a scaling event of a task group of a
+// job. Only the most recent events of each task group are kept.
+func (s *StateStore) UpsertScalingEvent(index uint64, req *structs.ScalingEventRegisterRequest) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	existing, err := txn.First("scaling_event", "id", req.Namespace, req.JobID)
+	if err != nil {
+		return fmt.Errorf("scaling event lookup failed: %v", err)
+	}
+
+	var events *structs.JobScalingEvents
+	if existing != nil {
+		events = existing.(*structs.JobScalingEvents).Copy()
+	} else {
+		events = &structs.JobScalingEvents{
+			Namespace:     req.Namespace,
+			JobID:         req.JobID,
+			ScalingEvents: make(map[string][]*structs.ScalingEvent),
+		}
+	}
+
+	// Prepend the event, dropping the oldest events
+	event := *req.ScalingEvent
+	event.CreateIndex = index
+	groupEvents := append([]*structs.ScalingEvent{&event}, events.ScalingEvents[req.TaskGroup]...)
+	if len(groupEvents) > structs.JobTrackedScalingEvents {
+		groupEvents = groupEvents[:structs.JobTrackedScalingEvents]
+	}
+	events.ScalingEvents[req.TaskGroup] = groupEvents
+	events.ModifyIndex = index
+
+	if err := txn.Insert("scaling_event", events); err != nil {
+		return fmt.Errorf("upserting scaling event failed: %v", err)
+	}
+	if err := txn.Insert("index", &IndexEntry{"scaling_event", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	txn.Commit()
+	return nil
+}
+
+// ScalingEventsByJob is used to lookup the scaling events of the task groups
+// of a job, along with the index they were last modified at
+func (s *StateStore) ScalingEventsByJob(ws memdb.WatchSet, namespace, jobID string) (map[string][]*structs.ScalingEvent, uint64, error) {
+	txn := s.db.Txn(false)
+
+	watchCh, existing, err := txn.FirstWatch("scaling_event", "id", namespace, jobID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("scaling event lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if existing != nil {
+		events := existing.(*structs.JobScalingEvents)
+		return events.ScalingEvents, events.ModifyIndex, nil
+	}
+	return nil, 0, nil
+}
+
+// ScalingEvents returns an iterator over the scaling events of all the jobs
+func (s *StateStore) ScalingEvents(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.Txn(false)
+
+	// Walk the entire table
+	iter, err := txn.Get("scaling_event", "id")
+	if err != nil {
+		return nil, err
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// UpsertServiceRegistrations is used to create or update the registrations
+// of services
+func (s *StateStore) UpsertServiceRegistrations(index uint64, services []*structs.ServiceRegistration) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	for _, service := range services {
+		existing, err := txn.First("service_registrations", "id", service.ID)
+		if err != nil {
+			return fmt.Errorf("service registration lookup failed: %v", err)
+		}
+
+		if existing != nil {
+			service.CreateIndex = existing.(*structs.ServiceRegistration).CreateIndex
+			service.ModifyIndex = index
+		} else {
+			service.CreateIndex = index
+			service.ModifyIndex = index
+		}
+
+		// Health is derived when reading the registration
+		service.Health = ""
+
+		if err := txn.Insert("service_registrations", service); err != nil {
+			return fmt.Errorf("upserting service registration failed: %v", err)
+		}
+	}
+
+	if err := txn.Insert("index", &IndexEntry{"service_registrations", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	txn.Commit()
+	return nil
+}
+
+// DeleteServiceRegistrationsByTask deletes the registrations of the services
+// of a task of an allocation
+func (s *StateStore) DeleteServiceRegistrationsByTask(index uint64, allocID, task string) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	iter, err := txn.Get("service_registrations", "alloc_id", allocID)
+	if err != nil {
+		return fmt.Errorf("service registration lookup failed: %v", err)
+	}
+
+	var deleted []interface{}
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		if raw.(*structs.ServiceRegistration).Task == task {
+			deleted = append(deleted, raw)
+		}
+	}
+	if len(deleted) == 0 {
+		return nil
+	}
+
+	for _, service := range deleted {
+		if err := txn.Delete("service_registrations", service); err != nil {
+			return fmt.Errorf("deleting service registration failed: %v", err)
+		}
+	}
+	if err := txn.Insert("index", &IndexEntry{"service_registrations", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	txn.Commit()
+	return nil
+}
+
+// deleteServiceRegistrationsByAllocTxn deletes the registrations of the
+// services of an allocation within the transaction, when the allocation is
+// terminal or deleted
+func (s *StateStore) deleteServiceRegistrationsByAllocTxn(index uint64, allocID string, txn *memdb.Txn) error {
+	num, err := txn.DeleteAll("service_registrations", "alloc_id", allocID)
+	if err != nil {
+		return fmt.Errorf("deleting service registrations failed: %v", err)
+	}
+	if num == 0 {
+		return nil
+	}
+
+	if err := txn.Insert("index", &IndexEntry{"service_registrations", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	return nil
+}
+
+// ServiceRegistrationByID is used to lookup a service registration by ID
+func (s *StateStore) ServiceRegistrationByID(ws memdb.WatchSet, id string) (*structs.ServiceRegistration, error) {
+	txn := s.db.Txn(false)
+
+	watchCh, existing, err := txn.FirstWatch("service_registrations", "id", id)
+	if err != nil {
+		return nil, fmt.Errorf("service registration lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if existing != nil {
+		return existing.(*structs.ServiceRegistration), nil
+	}
+	return nil, nil
+}
+
+// ServiceRegistrationsByNamespace returns an iterator over the service
+// registrations of a namespace, ordered by service name
+func (s *StateStore) ServiceRegistrationsByNamespace(ws memdb.WatchSet, namespace string) (memdb.ResultIterator, error) {
+	txn := s.db.Txn(false)
+
+	iter, err := txn.Get("service_registrations", "service_name_prefix", namespace, "")
+	if err != nil {
+		return nil, fmt.Errorf("service registrations lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	return iter, nil
+}
+
+// ServiceRegistrationsByServiceName returns an iterator over the registered
+// instances of a service of a namespace
+func (s *StateStore) ServiceRegistrationsByServiceName(ws memdb.WatchSet, namespace, name string) (memdb.ResultIterator, error) {
+	txn := s.db.Txn(false)
+
+	iter, err := txn.Get("service_registrations", "service_name", namespace, name)
+	if err != nil {
+		return nil, fmt.Errorf("service registrations lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	return iter, nil
+}
+
+// ServiceRegistrationsByJobID returns an iterator over the service
+// registrations of a job
+func (s *StateStore) ServiceRegistrationsByJobID(ws memdb.WatchSet, namespace, jobID string) (memdb.ResultIterator, error) {
+	txn := s.db.Txn(false)
+
+	iter, err := txn.Get("service_registrations", "job", namespace, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("service registrations lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	return iter, nil
+}
+
+// ServiceRegistrationsByAllocID returns an iterator over the service
+// registrations of an allocation
+func (s *StateStore) ServiceRegistrationsByAllocID(ws memdb.WatchSet, allocID string) (memdb.ResultIterator, error) {
+	txn := s.db.Txn(false)
+
+	iter, err := txn.Get("service_registrations", "alloc_id", allocID)
+	if err != nil {
+		return nil, fmt.Errorf("service registrations lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	return iter, nil
+}
+
+// ServiceRegistrations returns an iterator over the service registrations of
+// all the namespaces
+func (s *StateStore) ServiceRegistrations(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.Txn(false)
+
+	// Walk the entire table
+	iter, err := txn.Get("service_registrations", "id")
+	if err != nil {
+		return nil, err
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// UpsertVolumes is used to register or update a set of volumes. The claims
+// of existing volumes are kept, as they are only changed by claiming and
+// detaching the volume.
+func (s *StateStore) UpsertVolumes(index uint64, volumes []*structs.Volume) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	for _, volume := range volumes {
+		existing, err := txn.First("volumes", "id", volume.Namespace, volume.ID)
+		if err != nil {
+			return fmt.Errorf("volume lookup failed: %v", err)
+		}
+
+		if existing != nil {
+			exist := existing.(*structs.Volume)
+			volume.CreateIndex = exist.CreateIndex
+			volume.ModifyIndex = index
+			volume.Claims = exist.Claims
+		} else {
+			volume.CreateIndex = index
+			volume.ModifyIndex = index
+			volume.Claims = nil
+		}
+
+		if err := txn.Insert("volumes", volume); err != nil {
+			return fmt.Errorf("upserting volume failed: %v", err)
+		}
+	}
+
+	if err := txn.Insert("index", &IndexEntry{"volumes", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	txn.Commit()
+	return nil
+}
+
+// DeleteVolumes is used to delete a set of volumes of a namespace
+func (s *StateStore) DeleteVolumes(index uint64, namespace string, ids []string) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	for _, id := range ids {
+		existing, err := txn.First("volumes", "id", namespace, id)
+		if err != nil {
+			return fmt.Errorf("volume lookup failed: %v", err)
+		}
+		if existing == nil {
+			return fmt.Errorf("volume %q not found", id)
+		}
+		if err := txn.Delete("volumes", existing); err != nil {
+			return fmt.Errorf("deleting volume failed: %v", err)
+		}
+	}
+
+	if err := txn.Insert("index", &IndexEntry{"volumes", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	txn.Commit()
+	return nil
+}
+
+// ClaimVolume is used to claim a volume for an allocation. An error is
+// returned if the access mode of the volume doesn't allow the claim.
+func (s *StateStore) ClaimVolume(index uint64, namespace, id string, claim *structs.VolumeClaim) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	existing, err := txn.First("volumes", "id", namespace, id)
+	if err != nil {
+		return fmt.Errorf("volume lookup failed: %v", err)
+	}
+	if existing == nil {
+		return fmt.Errorf("volume %q not found", id)
+	}
+
+	volume := existing.(*structs.Volume).Copy()
+	if err := volume.ClaimAllowed(claim); err != nil {
+		return err
+	}
+	if volume.Claims == nil {
+		volume.Claims = make(map[string]*structs.VolumeClaim)
+	}
+	volume.Claims[claim.AllocID] = claim
+	volume.ModifyIndex = index
+
+	if err := txn.Insert("volumes", volume); err != nil {
+		return fmt.Errorf("upserting volume failed: %v", err)
+	}
+	if err := txn.Insert("index", &IndexEntry{"volumes", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	txn.Commit()
+	return nil
+}
+
+// DetachVolume is used to release the claims of a volume on a node
+func (s *StateStore) DetachVolume(index uint64, namespace, id, nodeID string) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	existing, err := txn.First("volumes", "id", namespace, id)
+	if err != nil {
+		return fmt.Errorf("volume lookup failed: %v", err)
+	}
+	if existing == nil {
+		return fmt.Errorf("volume %q not found", id)
+	}
+
+	volume := existing.(*structs.Volume).Copy()
+	for allocID, claim := range volume.Claims {
+		if claim.NodeID == nodeID {
+			delete(volume.Claims, allocID)
+		}
+	}
+	if len(volume.Claims) == 0 {
+		volume.Claims = nil
+	}
+	volume.ModifyIndex = index
+
+	if err := txn.Insert("volumes", volume); err != nil {
+		return fmt.Errorf("upserting volume failed: %v", err)
+	}
+	if err := txn.Insert("index", &IndexEntry{"volumes", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	txn.Commit()
+	return nil
+}
+
+// VolumeByID is used to lookup a volume of a namespace by ID
+func (s *StateStore) VolumeByID(ws memdb.WatchSet, namespace, id string) (*structs.Volume, error) {
+	txn := s.db.Txn(false)
+
+	watchCh, existing, err := txn.FirstWatch("volumes", "id", namespace, id)
+	if err != nil {
+		return nil, fmt.Errorf("volume lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if existing != nil {
+		return existing.(*structs.Volume), nil
+	}
+	return nil, nil
+}
+
+// VolumesByIDPrefix returns an iterator over the volumes of a namespace
+// whose ID has the prefix, ordered by ID
+func (s *StateStore) VolumesByIDPrefix(ws memdb.WatchSet, namespace, prefix string) (memdb.ResultIterator, error) {
+	txn := s.db.Txn(false)
+
+	iter, err := txn.Get("volumes", "id_prefix", namespace, prefix)
+	if err != nil {
+		return nil, fmt.Errorf("volumes lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	return iter, nil
+}
+
+// Volumes returns an iterator over the volumes of all the namespaces
+func (s *StateStore) Volumes(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.Txn(false)
+
+	// Walk the entire table
+	iter, err := txn.Get("volumes", "id")
+	if err != nil {
+		return nil, err
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// QuotaUsageByName returns the usage of the quota specification's limit for
+// the given region. The usage is the sum of the resources of the non-terminal
+// allocations in the namespaces referencing the quota specification. Nil is
+// returned if the quota specification doesn't exist.
+func (s *StateStore) QuotaUsageByName(ws memdb.WatchSet, region, name string) (*structs.QuotaUsage, error) {
+	txn := s.db.Txn(false)
+
+	spec, err := s.quotaSpecByNameImpl(ws, txn, name)
+	if err != nil {
+		return nil, err
+	}
+	if spec == nil {
+		return nil, nil
+	}
+
+	usage := structs.NewQuotaUsage(spec, region)
+	limit := spec.RegionLimit(region)
+	if limit == nil {
+		return usage, nil
+	}
+	used := usage.Used[limit.HashKey()]
+
+	watchCh, existing, err := txn.FirstWatch("quota_usage", "id", name)
+	if err != nil {
+		return nil, fmt.Errorf("quota usage lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if existing != nil {
+		tracked := existing.(*QuotaUsageEntry).Used
+		used.RegionLimit = tracked.RegionLimit.Copy()
+		used.AllocLimit = tracked.AllocLimit
+	}
+	return usage, nil
+}
+
+// updateQuotaUsageWithAlloc updates the usage of the quota specification
+// referenced by the namespace of an allocation that is added, modified or
+// deleted. Either of the allocations may be nil.
+func updateQuotaUsageWithAlloc(txn *memdb.Txn, alloc, existing *structs.Allocation) error {
+	var remove, add *structs.Allocation
+	if existing != nil && !existing.TerminalStatus() {
+		remove = existing
+	}
+	if alloc != nil && !alloc.TerminalStatus() {
+		add = alloc
+	}
+	if remove == nil && add == nil {
+		return nil
+	}
+
+	namespace := structs.DefaultNamespace
+	if add != nil && add.Namespace != "" {
+		namespace = add.Namespace
+	} else if remove != nil && remove.Namespace != "" {
+		namespace = remove.Namespace
+	}
+	existingNs, err := txn.First("namespaces", "id", namespace)
+	if err != nil {
+		return fmt.Errorf("namespace lookup failed: %v", err)
+	}
+	if existingNs == nil || existingNs.(*structs.Namespace).Quota == "" {
+		return nil
+	}
+
+	return updateQuotaUsage(txn, existingNs.(*structs.Namespace).Quota, func(used *structs.QuotaLimit) {
+		if remove != nil {
+			used.SubtractAlloc(remove)
+		}
+		if add != nil {
+			used.AddAlloc(add)
+		}
+	})
+}
+
+// moveNamespaceQuotaUsage moves the usage of the non-terminal allocations of
+// a namespace from the quota specification it referenced to the one it
+// references now. Either of the quota specifications may be empty.
+func moveNamespaceQuotaUsage(txn *memdb.Txn, namespace, from, to string) error {
+	if from == to {
+		return nil
+	}
+
+	iter, err := txn.Get("allocs", "namespace", namespace)
+	if err != nil {
+		return fmt.Errorf("alloc lookup failed: %v", err)
+	}
+	var allocs []*structs.Allocation
+	for {
+		raw := iter.Next()
+		if raw == nil {
+			break
+		}
+		if alloc := raw.(*structs.Allocation); !alloc.TerminalStatus() {
+			allocs = append(allocs, alloc)
+		}
+	}
+	if len(allocs) == 0 {
+		return nil
+	}
+
+	if from != "" {
+		err := updateQuotaUsage(txn, from, func(used *structs.QuotaLimit) {
+			for _, alloc := range allocs {
+				used.SubtractAlloc(alloc)
+			}
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if to != "" {
+		err := updateQuotaUsage(txn, to, func(used *structs.QuotaLimit) {
+			for _, alloc := range allocs {
+				used.AddAlloc(alloc)
+			}
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// updateQuotaUsage applies the update function to a copy of the tracked usage
+// of the quota specification and stores the result.
+func updateQuotaUsage(txn *memdb.Txn, quota string, update func(used *structs.QuotaLimit)) error {
+	existing, err := txn.First("quota_usage", "id", quota)
+	if err != nil {
+		return fmt.Errorf("quota usage lookup failed: %v", err)
+	}
+
+	var used *structs.QuotaLimit
+	if existing != nil {
+		used = existing.(*QuotaUsageEntry).Used.Copy()
+	} else {
+		used = &structs.QuotaLimit{RegionLimit: &structs.Resources{}}
+	}
+	update(used)
+
+	if err := txn.Insert("quota_usage", &QuotaUsageEntry{Name: quota, Used: used}); err != nil {
+		return fmt.Errorf("quota usage insert failed: %v", err)
+	}
+	return nil
+}
+
+// UpsertACLPolicies is used to create or update a set of ACL policies
+func (s *StateStore) UpsertACLPolicies(index uint64, policies []*structs.ACLPolicy) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	for _, policy := range policies {
+		// Ensure the policy hash is non-nil. This should be done outside the state store
+		// for performance reasons, but we check here for defense in depth.
+		if len(policy.Hash) == 0 {
+			policy.SetHash()
+		}
+
+		// Check if the policy already exists
+		existing, err := txn.First("acl_policy", "id", policy.Name)
+		if err != nil {
+			return fmt.Errorf("policy lookup failed: %v", err)
+		}
+
+		// Update all the indexes
+		if existing != nil {
+			policy.CreateIndex = existing.(*structs.ACLPolicy).CreateIndex
+			policy.ModifyIndex = index
+		} else {
+			policy.CreateIndex = index
+			policy.ModifyIndex = index
+		}
+
+		// Update the policy
+		if err := txn.Insert("acl_policy", policy); err != nil {
+			return fmt.Errorf("upserting policy failed: %v", err)
+		}
+	}
+
+	// Update the indexes tabl
+	if err := txn.Insert("index", &IndexEntry{"acl_policy", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	txn.Commit()
+	return nil
+}
+
+// DeleteACLPolicies deletes the policies with the given names
+func (s *StateStore) DeleteACLPolicies(index uint64, names []string) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	// Delete the policy
+	for _, name := range names {
+		if _, err := txn.DeleteAll("acl_policy", "id", name); err != nil {
+			return fmt.Errorf("deleting acl policy failed: %v", err)
+		}
+	}
+	if err := txn.Insert("index", &IndexEntry{"acl_policy", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	txn.Commit()
+	return nil
+}
+
+// ACLPolicyByName is used to lookup a policy by name
+func (s *StateStore) ACLPolicyByName(ws memdb.WatchSet, name string) (*structs.ACLPolicy, error) {
+	txn := s.db.Txn(false)
+
+	watchCh, existing, err := txn.FirstWatch("acl_policy", "id", name)
+	if err != nil {
+		return nil, fmt.Errorf("acl policy lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if existing != nil {
+		return existing.(*structs.ACLPolicy), nil
+	}
+	return nil, nil
+}
+
+// ACLPolicyByNamePrefix is used to lookup policies by prefix
+func (s *StateStore) ACLPolicyByNamePrefix(ws memdb.WatchSet, prefix string) (memdb.ResultIterator, error) {
+	txn := s.db.Txn(false)
+
+	iter, err := txn.Get("acl_policy", "id_prefix", prefix)
+	if err != nil {
+		return nil, fmt.Errorf("acl policy lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	return iter, nil
+}
+
+// ACLPolicies returns an iterator over all the acl policies
+func (s *StateStore) ACLPolicies(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.Txn(false)
+
+	// Walk the entire table
+	iter, err := txn.Get("acl_policy", "id")
+	if err != nil {
+		return nil, err
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// UpsertACLTokens is used to create or update a set of ACL tokens
+func (s *StateStore) UpsertACLTokens(index uint64, tokens []*structs.ACLToken) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	for _, token := range tokens {
+		// Ensure the policy hash is non-nil. This should be done outside the state store
+		// for performance reasons, but we check here for defense in depth.
+		if len(token.Hash) == 0 {
+			token.SetHash()
+		}
+
+		// Check if the token already exists
+		existing, err := txn.First("acl_token", "id", token.AccessorID)
+		if err != nil {
+			return fmt.Errorf("token lookup failed: %v", err)
+		}
+
+		// Update all the indexes
+		if existing != nil {
+			existTK := existing.(*structs.ACLToken)
+			token.CreateIndex = existTK.CreateIndex
+			token.ModifyIndex = index
+
+			// Do not allow SecretID, create time or expiration time to change
+			token.SecretID = existTK.SecretID
+			token.CreateTime = existTK.CreateTime
+			token.ExpirationTime = existTK.ExpirationTime
+
+		} else {
+			token.CreateIndex = index
+			token.ModifyIndex = index
+		}
+
+		// Update the token
+		if err := txn.Insert("acl_token", token); err != nil {
+			return fmt.Errorf("upserting token failed: %v", err)
+		}
+	}
+
+	// Update the indexes table
+	if err := txn.Insert("index", &IndexEntry{"acl_token", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	txn.Commit()
+	return nil
+}
+
+// DeleteACLTokens deletes the tokens with the given accessor ids
+func (s *StateStore) DeleteACLTokens(index uint64, ids []string) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	// Delete the tokens
+	for _, id := range ids {
+		if _, err := txn.DeleteAll("acl_token", "id", id); err != nil {
+			return fmt.Errorf("deleting acl token failed: %v", err)
+		}
+	}
+	if err := txn.Insert("index", &IndexEntry{"acl_token", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	txn.Commit()
+	return nil
+}
+
+// ACLTokenByAccessorID is used to lookup a token by accessor ID
+func (s *StateStore) ACLTokenByAccessorID(ws memdb.WatchSet, id string) (*structs.ACLToken, error) {
+	txn := s.db.Txn(false)
+
+	watchCh, existing, err := txn.FirstWatch("acl_token", "id", id)
+	if err != nil {
+		return nil, fmt.Errorf("acl token lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if existing != nil {
+		return existing.(*structs.ACLToken), nil
+	}
+	return nil, nil
+}
+
+// ACLTokenBySecretID is used to lookup a token by secret ID
+func (s *StateStore) ACLTokenBySecretID(ws memdb.WatchSet, secretID string) (*structs.ACLToken, error) {
+	txn := s.db.Txn(false)
+
+	watchCh, existing, err := txn.FirstWatch("acl_token", "secret", secretID)
+	if err != nil {
+		return nil, fmt.Errorf("acl token lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if existing != nil {
+		return existing.(*structs.ACLToken), nil
+	}
+	return nil, nil
+}
+
+// ACLTokenByAccessorIDPrefix is used to lookup tokens by prefix
+func (s *StateStore) ACLTokenByAccessorIDPrefix(ws memdb.WatchSet, prefix string) (memdb.ResultIterator, error) {
+	txn := s.db.Txn(false)
+
+	iter, err := txn.Get("acl_token", "id_prefix", prefix)
+	if err != nil {
+		return nil, fmt.Errorf("acl token lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// ACLTokens returns an iterator over all the tokens
+func (s *StateStore) ACLTokens(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.Txn(false)
+
+	// Walk the entire table
+	iter, err := txn.Get("acl_token", "id")
+	if err != nil {
+		return nil, err
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// ACLTokensByGlobal returns an iterator over all the tokens filtered by global value
+func (s *StateStore) ACLTokensByGlobal(ws memdb.WatchSet, globalVal bool) (memdb.ResultIterator, error) {
+	txn := s.db.Txn(false)
+
+	// Walk the entire table
+	iter, err := txn.Get("acl_token", "global", globalVal)
+	if err != nil {
+		return nil, err
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// CanBootstrapACLToken checks if bootstrapping is possible and returns the reset index
+func (s *StateStore) CanBootstrapACLToken() (bool, uint64, error) {
+	txn := s.db.Txn(false)
+
+	// Lookup the bootstrap sentinel
 	out, err := txn.First("index", "id", "acl_token_bootstrap")
 	if err != nil {
-		return false, 0, err
+		return false, 0, err
+	}
+
+	// No entry, we haven't bootstrapped yet
+	if out == nil {
+		return true, 0, nil
+	}
+
+	// Return the reset index if we've already bootstrapped
+	return false, out.(*IndexEntry).Value, nil
+}
+
+// BootstrapACLToken is used to create an initial ACL token
+func (s *StateStore) BootstrapACLTokens(index, resetIndex uint64, token *structs.ACLToken) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	// Check if we have already done a bootstrap
+	existing, err := txn.First("index", "id", "acl_token_bootstrap")
+	if err != nil {
+		return fmt.Errorf("bootstrap check failed: %v", err)
+	}
+	if existing != nil {
+		if resetIndex == 0 {
+			return fmt.Errorf("ACL bootstrap already done")
+		} else if resetIndex != existing.(*IndexEntry).Value {
+			return fmt.Errorf("Invalid reset index for ACL bootstrap")
+		}
+	}
+
+	// Update the Create/Modify time
+	token.CreateIndex = index
+	token.ModifyIndex = index
+
+	// Insert the token
+	if err := txn.Insert("acl_token", token); err != nil {
+		return fmt.Errorf("upserting token failed: %v", err)
+	}
+
+	// Update the indexes table, prevents future bootstrap until reset
+	if err := txn.Insert("index", &IndexEntry{"acl_token", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	if err := txn.Insert("index", &IndexEntry{"acl_token_bootstrap", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	txn.Commit()
+	return nil
+}
+
+// UpsertACLRoles is used to create or update a set of ACL roles
+func (s *StateStore) UpsertACLRoles(index uint64, roles []*structs.ACLRole) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	for _, role := range roles {
+		// Ensure the role hash is non-nil. This should be done outside the state store
+		// for performance reasons, but we check here for defense in depth.
+		if len(role.Hash) == 0 {
+			role.SetHash()
+		}
+
+		// Check if the role already exists
+		existing, err := txn.First("acl_roles", "id", role.Name)
+		if err != nil {
+			return fmt.Errorf("role lookup failed: %v", err)
+		}
+
+		// Update all the indexes
+		if existing != nil {
+			role.CreateIndex = existing.(*structs.ACLRole).CreateIndex
+			role.ModifyIndex = index
+		} else {
+			role.CreateIndex = index
+			role.ModifyIndex = index
+		}
+
+		// Update the role
+		if err := txn.Insert("acl_roles", role); err != nil {
+			return fmt.Errorf("upserting role failed: %v", err)
+		}
+	}
+
+	// Update the indexes table
+	if err := txn.Insert("index", &IndexEntry{"acl_roles", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	txn.Commit()
+	return nil
+}
+
+// DeleteACLRoles deletes the roles with the given names
+func (s *StateStore) DeleteACLRoles(index uint64, names []string) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	// Delete the roles
+	for _, name := range names {
+		if _, err := txn.DeleteAll("acl_roles", "id", name); err != nil {
+			return fmt.Errorf("deleting acl role failed: %v", err)
+		}
+	}
+	if err := txn.Insert("index", &IndexEntry{"acl_roles", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	txn.Commit()
+	return nil
+}
+
+// ACLRoleByName is used to lookup a role by name
+func (s *StateStore) ACLRoleByName(ws memdb.WatchSet, name string) (*structs.ACLRole, error) {
+	txn := s.db.Txn(false)
+
+	watchCh, existing, err := txn.FirstWatch("acl_roles", "id", name)
+	if err != nil {
+		return nil, fmt.Errorf("acl role lookup failed: %v", err)
 	}
+	ws.Add(watchCh)
 
-	// No entry, we haven't bootstrapped yet
-	if out == nil {
-		return true, 0, nil
+	if existing != nil {
+		return existing.(*structs.ACLRole), nil
 	}
+	return nil, nil
+}
 
-	// Return the reset index if we've already bootstrapped
-	return false, out.(*IndexEntry).Value, nil
+// ACLRoleByNamePrefix is used to lookup roles by prefix
+func (s *StateStore) ACLRoleByNamePrefix(ws memdb.WatchSet, prefix string) (memdb.ResultIterator, error) {
+	txn := s.db.Txn(false)
+
+	iter, err := txn.Get("acl_roles", "id_prefix", prefix)
+	if err != nil {
+		return nil, fmt.Errorf("acl role lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
 }
 
-// BootstrapACLToken is used to create an initial ACL token
-func (s *StateStore) BootstrapACLTokens(index, resetIndex uint64, token *structs.ACLToken) error {
+// ACLRoles returns an iterator over all the roles
+func (s *StateStore) ACLRoles(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.Txn(false)
+
+	// Walk the entire table
+	iter, err := txn.Get("acl_roles", "id")
+	if err != nil {
+		return nil, err
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// UpsertACLAuthMethods is used to create or update a set of auth methods
+func (s *StateStore) UpsertACLAuthMethods(index uint64, methods []*structs.ACLAuthMethod) error {
 	txn := s.db.Txn(true)
 	defer txn.Abort()
 
-	// Check if we have already done a bootstrap
-	existing, err := txn.First("index", "id", "acl_token_bootstrap")
+	for _, method := range methods {
+		// Ensure the method hash is non-nil. This should be done outside the state store
+		// for performance reasons, but we check here for defense in depth.
+		if len(method.Hash) == 0 {
+			method.SetHash()
+		}
+
+		// Check if the method already exists
+		existing, err := txn.First("acl_auth_methods", "id", method.Name)
+		if err != nil {
+			return fmt.Errorf("auth method lookup failed: %v", err)
+		}
+
+		// Update all the indexes
+		if existing != nil {
+			method.CreateIndex = existing.(*structs.ACLAuthMethod).CreateIndex
+			method.CreateTime = existing.(*structs.ACLAuthMethod).CreateTime
+			method.ModifyIndex = index
+		} else {
+			method.CreateIndex = index
+			method.ModifyIndex = index
+		}
+
+		// Update the method
+		if err := txn.Insert("acl_auth_methods", method); err != nil {
+			return fmt.Errorf("upserting auth method failed: %v", err)
+		}
+	}
+
+	// Update the indexes table
+	if err := txn.Insert("index", &IndexEntry{"acl_auth_methods", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	txn.Commit()
+	return nil
+}
+
+// DeleteACLAuthMethods deletes the auth methods with the given names, along
+// with their binding rules
+func (s *StateStore) DeleteACLAuthMethods(index uint64, names []string) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	deletedRules := false
+	for _, name := range names {
+		if _, err := txn.DeleteAll("acl_auth_methods", "id", name); err != nil {
+			return fmt.Errorf("deleting auth method failed: %v", err)
+		}
+		n, err := txn.DeleteAll("acl_binding_rules", "auth_method", name)
+		if err != nil {
+			return fmt.Errorf("deleting binding rules failed: %v", err)
+		}
+		deletedRules = deletedRules || n > 0
+	}
+	if err := txn.Insert("index", &IndexEntry{"acl_auth_methods", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	if deletedRules {
+		if err := txn.Insert("index", &IndexEntry{"acl_binding_rules", index}); err != nil {
+			return fmt.Errorf("index update failed: %v", err)
+		}
+	}
+	txn.Commit()
+	return nil
+}
+
+// ACLAuthMethodByName is used to lookup an auth method by name
+func (s *StateStore) ACLAuthMethodByName(ws memdb.WatchSet, name string) (*structs.ACLAuthMethod, error) {
+	txn := s.db.Txn(false)
+
+	watchCh, existing, err := txn.FirstWatch("acl_auth_methods", "id", name)
 	if err != nil {
-		return fmt.Errorf("bootstrap check failed: %v", err)
+		return nil, fmt.Errorf("auth method lookup failed: %v", err)
 	}
+	ws.Add(watchCh)
+
 	if existing != nil {
-		if resetIndex == 0 {
-			return fmt.Errorf("ACL bootstrap already done")
-		} else if resetIndex != existing.(*IndexEntry).Value {
-			return fmt.Errorf("Invalid reset index for ACL bootstrap")
+		return existing.(*structs.ACLAuthMethod), nil
+	}
+	return nil, nil
+}
+
+// ACLAuthMethods returns an iterator over all the auth methods
+func (s *StateStore) ACLAuthMethods(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.Txn(false)
+
+	// Walk the entire table
+	iter, err := txn.Get("acl_auth_methods", "id")
+	if err != nil {
+		return nil, err
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// DefaultACLAuthMethod returns the default auth method, nil if there is none
+func (s *StateStore) DefaultACLAuthMethod(ws memdb.WatchSet) (*structs.ACLAuthMethod, error) {
+	iter, err := s.ACLAuthMethods(ws)
+	if err != nil {
+		return nil, err
+	}
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		if method := raw.(*structs.ACLAuthMethod); method.Default {
+			return method, nil
 		}
 	}
+	return nil, nil
+}
 
-	// Update the Create/Modify time
-	token.CreateIndex = index
-	token.ModifyIndex = index
+// UpsertACLBindingRules is used to create or update a set of binding rules.
+// The auth methods of the rules must exist
+func (s *StateStore) UpsertACLBindingRules(index uint64, rules []*structs.ACLBindingRule) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
 
-	// Insert the token
-	if err := txn.Insert("acl_token", token); err != nil {
-		return fmt.Errorf("upserting token failed: %v", err)
+	for _, rule := range rules {
+		// Ensure the rule hash is non-nil. This should be done outside the state store
+		// for performance reasons, but we check here for defense in depth.
+		if len(rule.Hash) == 0 {
+			rule.SetHash()
+		}
+
+		method, err := txn.First("acl_auth_methods", "id", rule.AuthMethod)
+		if err != nil {
+			return fmt.Errorf("auth method lookup failed: %v", err)
+		}
+		if method == nil {
+			return fmt.Errorf("ACL auth method %q not found", rule.AuthMethod)
+		}
+
+		// Check if the rule already exists
+		existing, err := txn.First("acl_binding_rules", "id", rule.ID)
+		if err != nil {
+			return fmt.Errorf("binding rule lookup failed: %v", err)
+		}
+
+		// Update all the indexes
+		if existing != nil {
+			rule.CreateIndex = existing.(*structs.ACLBindingRule).CreateIndex
+			rule.CreateTime = existing.(*structs.ACLBindingRule).CreateTime
+			rule.ModifyIndex = index
+		} else {
+			rule.CreateIndex = index
+			rule.ModifyIndex = index
+		}
+
+		// Update the rule
+		if err := txn.Insert("acl_binding_rules", rule); err != nil {
+			return fmt.Errorf("upserting binding rule failed: %v", err)
+		}
 	}
 
-	// Update the indexes table, prevents future bootstrap until reset
-	if err := txn.Insert("index", &IndexEntry{"acl_token", index}); err != nil {
+	// Update the indexes table
+	if err := txn.Insert("index", &IndexEntry{"acl_binding_rules", index}); err != nil {
 		return fmt.Errorf("index update failed: %v", err)
 	}
-	if err := txn.Insert("index", &IndexEntry{"acl_token_bootstrap", index}); err != nil {
+	txn.Commit()
+	return nil
+}
+
+// DeleteACLBindingRules deletes the binding rules with the given IDs
+func (s *StateStore) DeleteACLBindingRules(index uint64, ids []string) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	for _, id := range ids {
+		if _, err := txn.DeleteAll("acl_binding_rules", "id", id); err != nil {
+			return fmt.Errorf("deleting binding rule failed: %v", err)
+		}
+	}
+	if err := txn.Insert("index", &IndexEntry{"acl_binding_rules", index}); err != nil {
 		return fmt.Errorf("index update failed: %v", err)
 	}
 	txn.Commit()
 	return nil
 }
 
+// ACLBindingRuleByID is used to lookup a binding rule by ID
+func (s *StateStore) ACLBindingRuleByID(ws memdb.WatchSet, id string) (*structs.ACLBindingRule, error) {
+	txn := s.db.Txn(false)
+
+	watchCh, existing, err := txn.FirstWatch("acl_binding_rules", "id", id)
+	if err != nil {
+		return nil, fmt.Errorf("binding rule lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if existing != nil {
+		return existing.(*structs.ACLBindingRule), nil
+	}
+	return nil, nil
+}
+
+// ACLBindingRules returns an iterator over all the binding rules
+func (s *StateStore) ACLBindingRules(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.Txn(false)
+
+	// Walk the entire table
+	iter, err := txn.Get("acl_binding_rules", "id")
+	if err != nil {
+		return nil, err
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// ACLBindingRulesByAuthMethod returns an iterator over the binding rules of
+// an auth method
+func (s *StateStore) ACLBindingRulesByAuthMethod(ws memdb.WatchSet, authMethod string) (memdb.ResultIterator, error) {
+	txn := s.db.Txn(false)
+
+	iter, err := txn.Get("acl_binding_rules", "auth_method", authMethod)
+	if err != nil {
+		return nil, err
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
 // SchedulerConfig is used to get the current Scheduler configuration.
 func (s *StateStore) SchedulerConfig() (uint64, *structs.SchedulerConfiguration, error) {
+	return s.SchedulerConfigWithWatch(nil)
+}
+
+// SchedulerConfigWithWatch is used to get the current Scheduler
+// configuration, adding a watch on it to the given watch set.
+func (s *StateStore) SchedulerConfigWithWatch(ws memdb.WatchSet) (uint64, *structs.SchedulerConfiguration, error) {
 	tx := s.db.Txn(false)
 	defer tx.Abort()
 
 	// Get the scheduler config
-	c, err := tx.First("scheduler_config", "id")
+	watchCh, c, err := tx.FirstWatch("scheduler_config", "id")
 	if err != nil {
 		return 0, nil, fmt.Errorf("failed scheduler config lookup: %s", err)
 	}
 
+	ws.Add(watchCh)
+
 	config, ok := c.(*structs.SchedulerConfiguration)
 	if !ok {
 		return 0, nil, nil
@@ -4064,6 +5840,11 @@ func (r *StateRestore) AllocRestore(alloc *structs.Allocation) error {
 	if err := r.txn.Insert("allocs", alloc); err != nil {
 		return fmt.Errorf("alloc insert failed: %v", err)
 	}
+
+	// Account the allocation if its namespace was already restored
+	if err := updateQuotaUsageWithAlloc(r.txn, alloc, nil); err != nil {
+		return fmt.Errorf("quota usage update failed: %v", err)
+	}
 	return nil
 }
 
@@ -4115,6 +5896,75 @@ func (r *StateRestore) VaultAccessorRestore(accessor *structs.VaultAccessor) err
 	return nil
 }
 
+// NodePoolRestore is used to restore a node pool
+func (r *StateRestore) NodePoolRestore(pool *structs.NodePool) error {
+	if err := r.txn.Insert("node_pools", pool); err != nil {
+		return fmt.Errorf("inserting node pool failed: %v", err)
+	}
+	return nil
+}
+
+// NamespaceRestore is used to restore a namespace
+func (r *StateRestore) NamespaceRestore(ns *structs.Namespace) error {
+	if err := r.txn.Insert("namespaces", ns); err != nil {
+		return fmt.Errorf("inserting namespace failed: %v", err)
+	}
+
+	// Account the allocations of the namespace that were already restored
+	if err := moveNamespaceQuotaUsage(r.txn, ns.Name, "", ns.Quota); err != nil {
+		return fmt.Errorf("quota usage update failed: %v", err)
+	}
+	return nil
+}
+
+// QuotaSpecRestore is used to restore a quota specification
+func (r *StateRestore) QuotaSpecRestore(spec *structs.QuotaSpec) error {
+	if err := r.txn.Insert("quota_specs", spec); err != nil {
+		return fmt.Errorf("inserting quota spec failed: %v", err)
+	}
+	return nil
+}
+
+// VariableRestore is used to restore an encrypted variable
+func (r *StateRestore) VariableRestore(v *structs.VariableEncrypted) error {
+	if err := r.txn.Insert("variables", v); err != nil {
+		return fmt.Errorf("inserting variable failed: %v", err)
+	}
+	return nil
+}
+
+// RootKeyRestore is used to restore a root key
+func (r *StateRestore) RootKeyRestore(key *structs.RootKey) error {
+	if err := r.txn.Insert("root_keys", key); err != nil {
+		return fmt.Errorf("inserting root key failed: %v", err)
+	}
+	return nil
+}
+
+// ScalingEventsRestore is used to restore the scaling events of a job
+func (r *StateRestore) ScalingEventsRestore(events *structs.JobScalingEvents) error {
+	if err := r.txn.Insert("scaling_event", events); err != nil {
+		return fmt.Errorf("inserting scaling events failed: %v", err)
+	}
+	return nil
+}
+
+// ServiceRegistrationRestore is used to restore a service registration
+func (r *StateRestore) ServiceRegistrationRestore(service *structs.ServiceRegistration) error {
+	if err := r.txn.Insert("service_registrations", service); err != nil {
+		return fmt.Errorf("inserting service registration failed: %v", err)
+	}
+	return nil
+}
+
+// VolumeRestore is used to restore a volume
+func (r *StateRestore) VolumeRestore(volume *structs.Volume) error {
+	if err := r.txn.Insert("volumes", volume); err != nil {
+		return fmt.Errorf("inserting volume failed: %v", err)
+	}
+	return nil
+}
+
 // ACLPolicyRestore is used to restore an ACL policy
 func (r *StateRestore) ACLPolicyRestore(policy *structs.ACLPolicy) error {
 	if err := r.txn.Insert("acl_policy", policy); err != nil {
@@ -4131,6 +5981,30 @@ func (r *StateRestore) ACLTokenRestore(token *structs.ACLToken) error {
 	return nil
 }
 
+// ACLRoleRestore is used to restore an ACL role
+func (r *StateRestore) ACLRoleRestore(role *structs.ACLRole) error {
+	if err := r.txn.Insert("acl_roles", role); err != nil {
+		return fmt.Errorf("inserting acl role failed: %v", err)
+	}
+	return nil
+}
+
+// ACLAuthMethodRestore is used to restore an ACL auth method
+func (r *StateRestore) ACLAuthMethodRestore(method *structs.ACLAuthMethod) error {
+	if err := r.txn.Insert("acl_auth_methods", method); err != nil {
+		return fmt.Errorf("inserting acl auth method failed: %v", err)
+	}
+	return nil
+}
+
+// ACLBindingRuleRestore is used to restore an ACL binding rule
+func (r *StateRestore) ACLBindingRuleRestore(rule *structs.ACLBindingRule) error {
+	if err := r.txn.Insert("acl_binding_rules", rule); err != nil {
+		return fmt.Errorf("inserting acl binding rule failed: %v", err)
+	}
+	return nil
+}
+
 func (r *StateRestore) SchedulerConfigRestore(schedConfig *structs.SchedulerConfiguration) error {
 	if err := r.txn.Insert("scheduler_config", schedConfig); err != nil {
 		return fmt.Errorf("inserting scheduler config failed: %s", err)
@@ -7,9 +7,18 @@ import (
 	"github.com/hashicorp/nomad/nomad/structs"
 )
 
-// namespaceExists returns whether a namespace exists
+// namespaceExists returns whether a namespace exists. The default namespace
+// always exists.
 func (s *StateStore) namespaceExists(txn *memdb.Txn, namespace string) (bool, error) {
-	return namespace == structs.DefaultNamespace, nil
+	if namespace == structs.DefaultNamespace {
+		return true, nil
+	}
+
+	existing, err := s.namespaceByNameImpl(nil, txn, namespace)
+	if err != nil {
+		return false, err
+	}
+	return existing != nil, nil
 }
 
 // updateEntWithAlloc is used to update Nomad Enterprise objects when an allocation is
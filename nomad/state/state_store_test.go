@@ -11,6 +11,7 @@ import (
 
 	memdb "github.com/hashicorp/go-memdb"
 	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/helper/testlog"
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -944,6 +945,34 @@ func TestStateStore_NodeEvents_RetentionWindow(t *testing.T) {
 	require.Equal(uint64(20), out.Events[len(out.Events)-1].CreateIndex)
 }
 
+func TestStateStore_NodeEvents_ConfiguredRetention(t *testing.T) {
+	require := require.New(t)
+	state, err := NewStateStore(&StateStoreConfig{
+		Logger:             testlog.HCLogger(t),
+		Region:             "global",
+		NodeEventRetention: 3,
+	})
+	require.NoError(err)
+
+	node := mock.Node()
+	require.NoError(state.UpsertNode(1000, node))
+
+	for i := 1; i <= 5; i++ {
+		nodeEvents := map[string][]*structs.NodeEvent{
+			node.ID: {structs.NewNodeEvent().
+				SetSubsystem(structs.NodeEventSubsystemHeartbeat).
+				SetMessage(fmt.Sprintf("%dith missed", i))},
+		}
+		require.NoError(state.UpsertNodeEvents(uint64(1000+i), nodeEvents))
+	}
+
+	out, err := state.NodeByID(nil, node.ID)
+	require.NoError(err)
+	require.Len(out.Events, 3)
+	require.Equal(uint64(1003), out.Events[0].CreateIndex)
+	require.Equal(uint64(1005), out.Events[2].CreateIndex)
+}
+
 func TestStateStore_UpdateNodeDrain_ResetEligiblity(t *testing.T) {
 	require := require.New(t)
 	state := testStateStore(t)
@@ -1045,6 +1074,44 @@ func TestStateStore_UpdateNodeEligibility(t *testing.T) {
 	require.Contains(err.Error(), "while it is draining")
 }
 
+func TestStateStore_UpdateNodeDynamicMeta(t *testing.T) {
+	require := require.New(t)
+	state := testStateStore(t)
+	node := mock.Node()
+	require.Nil(state.UpsertNode(1000, node))
+
+	// Create a watchset so we can test that the update fires the watch
+	ws := memdb.NewWatchSet()
+	_, err := state.NodeByID(ws, node.ID)
+	require.Nil(err)
+
+	set := map[string]string{"rack": "r1", "zone": "a"}
+	require.Nil(state.UpdateNodeDynamicMeta(1001, node.ID, set, nil))
+	require.True(watchFired(ws))
+
+	out, err := state.NodeByID(nil, node.ID)
+	require.Nil(err)
+	require.Equal(set, out.DynamicMeta)
+	require.NotEqual(node.ComputedClass, out.ComputedClass)
+	require.EqualValues(1001, out.ModifyIndex)
+
+	index, err := state.Index("nodes")
+	require.Nil(err)
+	require.EqualValues(1001, index)
+
+	// Unsetting all the keys restores the original computed class
+	require.Nil(state.UpdateNodeDynamicMeta(1002, node.ID, nil, []string{"rack", "zone"}))
+	out, err = state.NodeByID(nil, node.ID)
+	require.Nil(err)
+	require.Nil(out.DynamicMeta)
+	require.Equal(node.ComputedClass, out.ComputedClass)
+
+	// Unknown nodes are rejected
+	err = state.UpdateNodeDynamicMeta(1003, uuid.Generate(), set, nil)
+	require.Error(err)
+	require.Contains(err.Error(), "not found")
+}
+
 func TestStateStore_Nodes(t *testing.T) {
 	state := testStateStore(t)
 	var nodes []*structs.Node
@@ -1493,6 +1560,47 @@ func TestStateStore_UpsertJob_ChildJob(t *testing.T) {
 	}
 }
 
+func TestStateStore_UpsertJob_DispatchIdempotencyToken(t *testing.T) {
+	require := require.New(t)
+	state := testStateStore(t)
+
+	parent := mock.BatchJob()
+	parent.ParameterizedJob = &structs.ParameterizedJobConfig{}
+	require.Nil(state.UpsertJob(1000, parent))
+
+	child := parent.Copy()
+	child.ID = structs.DispatchedID(parent.ID, time.Now())
+	child.ParentID = parent.ID
+	child.Dispatched = true
+	child.DispatchIdempotencyToken = "foo"
+	require.Nil(state.UpsertJob(1001, child))
+
+	out, err := state.DispatchedJobByIdempotencyToken(nil, parent.Namespace, parent.ID, "foo")
+	require.Nil(err)
+	require.NotNil(out)
+	require.Equal(child.ID, out.ID)
+
+	out, err = state.DispatchedJobByIdempotencyToken(nil, parent.Namespace, parent.ID, "bar")
+	require.Nil(err)
+	require.Nil(out)
+
+	// Updating the dispatched job is allowed
+	require.Nil(state.UpsertJob(1002, child.Copy()))
+
+	// Registering another job with the same token is not
+	dup := child.Copy()
+	dup.ID = structs.DispatchedID(parent.ID, time.Now())
+	err = state.UpsertJob(1003, dup)
+	require.NotNil(err)
+	require.Contains(err.Error(), "idempotency token")
+
+	// Once the job is stopped the token can be reused
+	stopped := child.Copy()
+	stopped.Stop = true
+	require.Nil(state.UpsertJob(1004, stopped))
+	require.Nil(state.UpsertJob(1005, dup))
+}
+
 func TestStateStore_UpdateUpsertJob_JobVersion(t *testing.T) {
 	state := testStateStore(t)
 
@@ -6330,6 +6438,259 @@ func TestStateStore_RestoreVaultAccessor(t *testing.T) {
 	}
 }
 
+func TestStateStore_UpsertNodePools(t *testing.T) {
+	require := require.New(t)
+	state := testStateStore(t)
+	pool := &structs.NodePool{Name: "gpu"}
+
+	ws := memdb.NewWatchSet()
+	_, err := state.NodePoolByName(ws, pool.Name)
+	require.NoError(err)
+
+	require.NoError(state.UpsertNodePools(1000, []*structs.NodePool{pool}))
+	require.True(watchFired(ws))
+
+	out, err := state.NodePoolByName(nil, pool.Name)
+	require.NoError(err)
+	require.Equal(pool, out)
+	require.EqualValues(1000, out.CreateIndex)
+
+	// Updating the node pool retains its create index
+	update := &structs.NodePool{Name: "gpu", Description: "nodes with GPUs"}
+	require.NoError(state.UpsertNodePools(1001, []*structs.NodePool{update}))
+	out, err = state.NodePoolByName(nil, pool.Name)
+	require.NoError(err)
+	require.Equal(update.Description, out.Description)
+	require.EqualValues(1000, out.CreateIndex)
+	require.EqualValues(1001, out.ModifyIndex)
+
+	index, err := state.Index("node_pools")
+	require.NoError(err)
+	require.EqualValues(1001, index)
+
+	// Delete the node pool
+	require.NoError(state.DeleteNodePools(1002, []string{pool.Name}))
+	out, err = state.NodePoolByName(nil, pool.Name)
+	require.NoError(err)
+	require.Nil(out)
+}
+
+func TestStateStore_DefaultNodePool(t *testing.T) {
+	require := require.New(t)
+	state := testStateStore(t)
+
+	out, err := state.NodePoolByName(nil, structs.NodePoolDefault)
+	require.NoError(err)
+	require.NotNil(out)
+
+	// Nodes don't create the node pools they declare
+	node := mock.Node()
+	node.NodePool = "gpu"
+	require.NoError(state.UpsertNode(1000, node))
+
+	out, err = state.NodePoolByName(nil, "gpu")
+	require.NoError(err)
+	require.Nil(out)
+}
+
+func TestStateStore_DeleteNodePools_InUse(t *testing.T) {
+	require := require.New(t)
+	state := testStateStore(t)
+
+	pools := []*structs.NodePool{{Name: "gpu"}, {Name: "batch"}}
+	require.NoError(state.UpsertNodePools(1000, pools))
+
+	node := mock.Node()
+	node.NodePool = "gpu"
+	require.NoError(state.UpsertNode(1001, node))
+
+	job := mock.Job()
+	job.NodePool = "batch"
+	require.NoError(state.UpsertJob(1002, job))
+
+	// Node pools with nodes or jobs in them and the default node pool can
+	// not be deleted
+	require.EqualError(state.DeleteNodePools(1003, []string{"gpu"}),
+		`node pool "gpu" has nodes in it`)
+	require.EqualError(state.DeleteNodePools(1003, []string{"batch"}),
+		`node pool "batch" has jobs in it`)
+	require.EqualError(state.DeleteNodePools(1003, []string{structs.NodePoolDefault}),
+		`node pool "default" is built-in and can not be deleted`)
+
+	// Node pools whose jobs are stopped can be deleted
+	stopped := job.Copy()
+	stopped.Stop = true
+	require.NoError(state.UpsertJob(1004, stopped))
+	require.NoError(state.DeleteNodePools(1005, []string{"batch"}))
+
+	out, err := state.NodePoolByName(nil, "batch")
+	require.NoError(err)
+	require.Nil(out)
+
+	// Jobs can't be registered in the deleted node pool, but stopped jobs
+	// can still be updated
+	require.EqualError(state.UpsertJob(1006, job),
+		fmt.Sprintf(`job %q is in nonexistent node pool "batch"`, job.ID))
+	require.NoError(state.UpsertJob(1007, stopped))
+}
+
+func TestStateStore_UpsertNamespaces(t *testing.T) {
+	require := require.New(t)
+	state := testStateStore(t)
+	ns := &structs.Namespace{Name: "team-a", Quota: "small"}
+
+	ws := memdb.NewWatchSet()
+	_, err := state.NamespaceByName(ws, ns.Name)
+	require.NoError(err)
+
+	require.NoError(state.UpsertNamespaces(1000, []*structs.Namespace{ns}))
+	require.True(watchFired(ws))
+
+	out, err := state.NamespaceByName(nil, ns.Name)
+	require.NoError(err)
+	require.Equal(ns, out)
+	require.EqualValues(1000, out.CreateIndex)
+
+	// Lookup the namespace by the quota it references
+	iter, err := state.NamespacesByQuota(nil, "small")
+	require.NoError(err)
+	raw := iter.Next()
+	require.NotNil(raw)
+	require.Equal(ns.Name, raw.(*structs.Namespace).Name)
+	require.Nil(iter.Next())
+
+	// Updating the namespace retains its create index
+	update := &structs.Namespace{Name: "team-a", Description: "team a"}
+	require.NoError(state.UpsertNamespaces(1001, []*structs.Namespace{update}))
+	out, err = state.NamespaceByName(nil, ns.Name)
+	require.NoError(err)
+	require.Equal(update.Description, out.Description)
+	require.EqualValues(1000, out.CreateIndex)
+	require.EqualValues(1001, out.ModifyIndex)
+
+	index, err := state.Index("namespaces")
+	require.NoError(err)
+	require.EqualValues(1001, index)
+
+	// Delete the namespace
+	require.NoError(state.DeleteNamespaces(1002, []string{ns.Name}))
+	out, err = state.NamespaceByName(nil, ns.Name)
+	require.NoError(err)
+	require.Nil(out)
+}
+
+func TestStateStore_UpsertQuotaSpecs(t *testing.T) {
+	require := require.New(t)
+	state := testStateStore(t)
+	spec := &structs.QuotaSpec{
+		Name:   "small",
+		Limits: []*structs.QuotaLimit{{Region: "global", RegionLimit: &structs.Resources{CPU: 1000}}},
+	}
+
+	ws := memdb.NewWatchSet()
+	_, err := state.QuotaSpecByName(ws, spec.Name)
+	require.NoError(err)
+
+	require.NoError(state.UpsertQuotaSpecs(1000, []*structs.QuotaSpec{spec}))
+	require.True(watchFired(ws))
+
+	out, err := state.QuotaSpecByName(nil, spec.Name)
+	require.NoError(err)
+	require.Equal(spec, out)
+	require.EqualValues(1000, out.CreateIndex)
+	require.NotEmpty(out.Limits[0].Hash)
+
+	index, err := state.Index("quota_specs")
+	require.NoError(err)
+	require.EqualValues(1000, index)
+
+	// Delete the quota specification
+	require.NoError(state.DeleteQuotaSpecs(1001, []string{spec.Name}))
+	out, err = state.QuotaSpecByName(nil, spec.Name)
+	require.NoError(err)
+	require.Nil(out)
+}
+
+func TestStateStore_QuotaUsageByName(t *testing.T) {
+	require := require.New(t)
+	state := testStateStore(t)
+	spec := &structs.QuotaSpec{
+		Name:   "small",
+		Limits: []*structs.QuotaLimit{{Region: "global", RegionLimit: &structs.Resources{CPU: 1000}}},
+	}
+	require.NoError(state.UpsertQuotaSpecs(1000, []*structs.QuotaSpec{spec}))
+	require.NoError(state.UpsertNamespaces(1001, []*structs.Namespace{
+		{Name: structs.DefaultNamespace, Quota: spec.Name},
+		{Name: "other"},
+	}))
+
+	// Only non-terminal allocations in namespaces referencing the quota are
+	// accounted
+	a1 := mock.Alloc()
+	a2 := mock.Alloc()
+	a3 := mock.Alloc()
+	a3.DesiredStatus = structs.AllocDesiredStatusStop
+	a4 := mock.Alloc()
+	a4.Namespace = "other"
+	require.NoError(state.UpsertAllocs(1002, []*structs.Allocation{a1, a2, a3, a4}))
+
+	usage, err := state.QuotaUsageByName(nil, "global", spec.Name)
+	require.NoError(err)
+	require.Equal(spec.Name, usage.Name)
+	used := usage.Used[spec.Limits[0].HashKey()]
+	require.NotNil(used)
+	require.Equal(1000, used.RegionLimit.CPU)
+	require.Equal(512, used.RegionLimit.MemoryMB)
+	require.Equal(300, used.RegionLimit.DiskMB)
+	require.Equal(2, used.AllocLimit)
+
+	// The usage is released as the allocations reach a terminal status
+	update := a1.Copy()
+	update.ClientStatus = structs.AllocClientStatusComplete
+	require.NoError(state.UpdateAllocsFromClient(1003, []*structs.Allocation{update}))
+
+	usage, err = state.QuotaUsageByName(nil, "global", spec.Name)
+	require.NoError(err)
+	used = usage.Used[spec.Limits[0].HashKey()]
+	require.Equal(500, used.RegionLimit.CPU)
+	require.Equal(1, used.AllocLimit)
+
+	// The usage of a namespace follows the quota it references
+	require.NoError(state.UpsertNamespaces(1004, []*structs.Namespace{
+		{Name: "other", Quota: spec.Name},
+	}))
+	usage, err = state.QuotaUsageByName(nil, "global", spec.Name)
+	require.NoError(err)
+	used = usage.Used[spec.Limits[0].HashKey()]
+	require.Equal(1000, used.RegionLimit.CPU)
+	require.Equal(2, used.AllocLimit)
+
+	require.NoError(state.DeleteNamespaces(1005, []string{"other"}))
+	usage, err = state.QuotaUsageByName(nil, "global", spec.Name)
+	require.NoError(err)
+	used = usage.Used[spec.Limits[0].HashKey()]
+	require.Equal(500, used.RegionLimit.CPU)
+	require.Equal(1, used.AllocLimit)
+
+	// Garbage collected allocations release their usage
+	require.NoError(state.DeleteEval(1006, nil, []string{a2.ID}))
+	usage, err = state.QuotaUsageByName(nil, "global", spec.Name)
+	require.NoError(err)
+	used = usage.Used[spec.Limits[0].HashKey()]
+	require.Equal(0, used.RegionLimit.CPU)
+	require.Equal(0, used.AllocLimit)
+
+	// Regions without a limit have no usage
+	usage, err = state.QuotaUsageByName(nil, "europe", spec.Name)
+	require.NoError(err)
+	require.Empty(usage.Used)
+
+	// Unknown quota specifications have no usage
+	usage, err = state.QuotaUsageByName(nil, "global", "missing")
+	require.NoError(err)
+	require.Nil(usage)
+}
+
 func TestStateStore_UpsertACLPolicy(t *testing.T) {
 	state := testStateStore(t)
 	policy := mock.ACLPolicy()
@@ -6848,6 +7209,203 @@ func TestStateStore_RestoreACLToken(t *testing.T) {
 	assert.Equal(t, token, out)
 }
 
+func TestStateStore_ACLAuthMethods(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	state := testStateStore(t)
+
+	am1 := mock.ACLAuthMethod()
+	am2 := mock.ACLAuthMethod()
+	am2.Default = true
+
+	ws := memdb.NewWatchSet()
+	_, err := state.ACLAuthMethodByName(ws, am1.Name)
+	require.NoError(err)
+
+	require.NoError(state.UpsertACLAuthMethods(1000, []*structs.ACLAuthMethod{am1, am2}))
+	require.True(watchFired(ws))
+
+	out, err := state.ACLAuthMethodByName(nil, am1.Name)
+	require.NoError(err)
+	require.Equal(am1, out)
+	require.Equal(uint64(1000), out.CreateIndex)
+
+	def, err := state.DefaultACLAuthMethod(nil)
+	require.NoError(err)
+	require.Equal(am2.Name, def.Name)
+
+	// Updating keeps the create index and time
+	createTime := am1.CreateTime
+	am1 = am1.Copy()
+	am1.CreateTime = time.Time{}
+	am1.MaxTokenTTL = 2 * time.Hour
+	am1.SetHash()
+	require.NoError(state.UpsertACLAuthMethods(1001, []*structs.ACLAuthMethod{am1}))
+	out, err = state.ACLAuthMethodByName(nil, am1.Name)
+	require.NoError(err)
+	require.Equal(uint64(1000), out.CreateIndex)
+	require.Equal(uint64(1001), out.ModifyIndex)
+	require.Equal(createTime, out.CreateTime)
+	require.Equal(2*time.Hour, out.MaxTokenTTL)
+
+	// Deleting a method deletes its binding rules
+	rule := mock.ACLBindingRule()
+	rule.AuthMethod = am1.Name
+	require.NoError(state.UpsertACLBindingRules(1002, []*structs.ACLBindingRule{rule}))
+	require.NoError(state.DeleteACLAuthMethods(1003, []string{am1.Name}))
+
+	out, err = state.ACLAuthMethodByName(nil, am1.Name)
+	require.NoError(err)
+	require.Nil(out)
+	outRule, err := state.ACLBindingRuleByID(nil, rule.ID)
+	require.NoError(err)
+	require.Nil(outRule)
+
+	for _, table := range []string{"acl_auth_methods", "acl_binding_rules"} {
+		index, err := state.Index(table)
+		require.NoError(err)
+		require.Equal(uint64(1003), index)
+	}
+
+	iter, err := state.ACLAuthMethods(nil)
+	require.NoError(err)
+	var names []string
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		names = append(names, raw.(*structs.ACLAuthMethod).Name)
+	}
+	require.Equal([]string{am2.Name}, names)
+}
+
+func TestStateStore_ACLBindingRules(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	state := testStateStore(t)
+
+	am := mock.ACLAuthMethod()
+	rule1 := mock.ACLBindingRule()
+	rule1.AuthMethod = am.Name
+	rule2 := mock.ACLBindingRule()
+	rule2.AuthMethod = am.Name
+
+	// The auth method must exist
+	err := state.UpsertACLBindingRules(1000, []*structs.ACLBindingRule{rule1})
+	require.EqualError(err, fmt.Sprintf("ACL auth method %q not found", am.Name))
+
+	require.NoError(state.UpsertACLAuthMethods(1000, []*structs.ACLAuthMethod{am}))
+	require.NoError(state.UpsertACLBindingRules(1001, []*structs.ACLBindingRule{rule1, rule2}))
+
+	out, err := state.ACLBindingRuleByID(nil, rule1.ID)
+	require.NoError(err)
+	require.Equal(rule1, out)
+
+	iter, err := state.ACLBindingRulesByAuthMethod(nil, am.Name)
+	require.NoError(err)
+	count := 0
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		count++
+	}
+	require.Equal(2, count)
+
+	require.NoError(state.DeleteACLBindingRules(1002, []string{rule1.ID}))
+	out, err = state.ACLBindingRuleByID(nil, rule1.ID)
+	require.NoError(err)
+	require.Nil(out)
+
+	index, err := state.Index("acl_binding_rules")
+	require.NoError(err)
+	require.Equal(uint64(1002), index)
+}
+
+func TestStateStore_RestoreACLAuthMethodsAndBindingRules(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	state := testStateStore(t)
+
+	am := mock.ACLAuthMethod()
+	rule := mock.ACLBindingRule()
+	rule.AuthMethod = am.Name
+
+	restore, err := state.Restore()
+	require.NoError(err)
+	require.NoError(restore.ACLAuthMethodRestore(am))
+	require.NoError(restore.ACLBindingRuleRestore(rule))
+	restore.Commit()
+
+	outMethod, err := state.ACLAuthMethodByName(nil, am.Name)
+	require.NoError(err)
+	require.Equal(am, outMethod)
+	outRule, err := state.ACLBindingRuleByID(nil, rule.ID)
+	require.NoError(err)
+	require.Equal(rule, outRule)
+}
+
+func TestStateStore_ACLRoles(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	state := testStateStore(t)
+
+	role1 := mock.ACLRole()
+	role2 := mock.ACLRole()
+
+	ws := memdb.NewWatchSet()
+	_, err := state.ACLRoleByName(ws, role1.Name)
+	require.NoError(err)
+
+	require.NoError(state.UpsertACLRoles(1000, []*structs.ACLRole{role1, role2}))
+	require.True(watchFired(ws))
+
+	out, err := state.ACLRoleByName(nil, role1.Name)
+	require.NoError(err)
+	require.Equal(role1, out)
+	require.Equal(uint64(1000), out.CreateIndex)
+
+	// Updating keeps the create index
+	role1 = role1.Copy()
+	role1.Policies = []string{"baz"}
+	role1.SetHash()
+	require.NoError(state.UpsertACLRoles(1001, []*structs.ACLRole{role1}))
+	out, err = state.ACLRoleByName(nil, role1.Name)
+	require.NoError(err)
+	require.Equal(uint64(1000), out.CreateIndex)
+	require.Equal(uint64(1001), out.ModifyIndex)
+	require.Equal([]string{"baz"}, out.Policies)
+
+	iter, err := state.ACLRoleByNamePrefix(nil, role2.Name[:12])
+	require.NoError(err)
+	count := 0
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		count++
+	}
+	require.NotZero(count)
+
+	require.NoError(state.DeleteACLRoles(1002, []string{role1.Name}))
+	out, err = state.ACLRoleByName(nil, role1.Name)
+	require.NoError(err)
+	require.Nil(out)
+
+	index, err := state.Index("acl_roles")
+	require.NoError(err)
+	require.Equal(uint64(1002), index)
+
+	iter, err = state.ACLRoles(nil)
+	require.NoError(err)
+	var names []string
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		names = append(names, raw.(*structs.ACLRole).Name)
+	}
+	require.Equal([]string{role2.Name}, names)
+
+	// Restoring a role
+	role3 := mock.ACLRole()
+	restore, err := state.Restore()
+	require.NoError(err)
+	require.NoError(restore.ACLRoleRestore(role3))
+	restore.Commit()
+	out, err = state.ACLRoleByName(nil, role3.Name)
+	require.NoError(err)
+	require.Equal(role3, out)
+}
+
 func TestStateStore_SchedulerConfig(t *testing.T) {
 	state := testStateStore(t)
 	schedConfig := &structs.SchedulerConfiguration{
@@ -6955,3 +7513,327 @@ func (n AllocIDSort) Less(i, j int) bool {
 func (n AllocIDSort) Swap(i, j int) {
 	n[i], n[j] = n[j], n[i]
 }
+
+func TestStateStore_Variables(t *testing.T) {
+	require := require.New(t)
+	state := testStateStore(t)
+
+	v := &structs.VariableEncrypted{
+		VariableMetadata: structs.VariableMetadata{
+			Namespace: structs.DefaultNamespace,
+			Path:      "project/web",
+		},
+		Data:  []byte("encrypted"),
+		KeyID: "key",
+	}
+
+	// Create watchsets so we can test that upsert fires the watch
+	ws := memdb.NewWatchSet()
+	_, err := state.VariableByPath(ws, v.Namespace, v.Path)
+	require.NoError(err)
+
+	// The variable must not exist with a check index of zero
+	require.NoError(state.UpsertVariable(1000, v, helper.Uint64ToPtr(0)))
+	require.True(watchFired(ws))
+
+	out, err := state.VariableByPath(nil, v.Namespace, v.Path)
+	require.NoError(err)
+	require.EqualValues(1000, out.CreateIndex)
+	require.EqualValues(1000, out.ModifyIndex)
+
+	// Check-and-set writes fail on a stale index
+	update := v.Copy()
+	err = state.UpsertVariable(1001, update, helper.Uint64ToPtr(999))
+	require.Error(err)
+	require.Contains(err.Error(), structs.ErrCASConflictPrefix)
+	require.NoError(state.UpsertVariable(1001, update, helper.Uint64ToPtr(1000)))
+
+	out, err = state.VariableByPath(nil, v.Namespace, v.Path)
+	require.NoError(err)
+	require.EqualValues(1000, out.CreateIndex)
+	require.EqualValues(1001, out.ModifyIndex)
+
+	// Variables can be looked up by prefix
+	other := v.Copy()
+	other.Path = "other/web"
+	require.NoError(state.UpsertVariable(1002, other, nil))
+	iter, err := state.VariablesByPathPrefix(nil, v.Namespace, "project/")
+	require.NoError(err)
+	var paths []string
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		paths = append(paths, raw.(*structs.VariableEncrypted).Path)
+	}
+	require.Equal([]string{"project/web"}, paths)
+
+	// Variables can't be in nonexistent namespaces
+	missing := v.Copy()
+	missing.Namespace = "missing"
+	require.Error(state.UpsertVariable(1003, missing, nil))
+
+	// Delete the variable
+	require.NoError(state.DeleteVariable(1004, v.Namespace, v.Path, nil))
+	out, err = state.VariableByPath(nil, v.Namespace, v.Path)
+	require.NoError(err)
+	require.Nil(out)
+
+	index, err := state.Index("variables")
+	require.NoError(err)
+	require.EqualValues(1004, index)
+}
+
+func TestStateStore_UpsertRootKey(t *testing.T) {
+	require := require.New(t)
+	state := testStateStore(t)
+
+	k1 := &structs.RootKey{KeyID: "k1", Algorithm: structs.RootKeyAlgorithmAES256GCM, Active: true}
+	require.NoError(state.UpsertRootKey(1000, k1))
+
+	active, err := state.ActiveRootKey(nil)
+	require.NoError(err)
+	require.Equal("k1", active.KeyID)
+
+	// Activating a new key deactivates the previous one
+	k2 := &structs.RootKey{KeyID: "k2", Algorithm: structs.RootKeyAlgorithmAES256GCM, Active: true}
+	require.NoError(state.UpsertRootKey(1001, k2))
+
+	active, err = state.ActiveRootKey(nil)
+	require.NoError(err)
+	require.Equal("k2", active.KeyID)
+
+	out, err := state.RootKeyByID(nil, "k1")
+	require.NoError(err)
+	require.False(out.Active)
+	require.EqualValues(1001, out.ModifyIndex)
+}
+
+func TestStateStore_DeleteRootKey(t *testing.T) {
+	require := require.New(t)
+	state := testStateStore(t)
+
+	k1 := &structs.RootKey{KeyID: "k1", Algorithm: structs.RootKeyAlgorithmAES256GCM, Active: true}
+	require.NoError(state.UpsertRootKey(1000, k1))
+	v := &structs.VariableEncrypted{
+		VariableMetadata: structs.VariableMetadata{Namespace: structs.DefaultNamespace, Path: "project/web"},
+		KeyID:            "k1",
+	}
+	require.NoError(state.UpsertVariable(1001, v, nil))
+
+	// The active key can't be deleted
+	err := state.DeleteRootKey(1002, "k1")
+	require.Error(err)
+	require.Contains(err.Error(), "active")
+
+	// Nor an inactive key variables are encrypted with
+	k2 := &structs.RootKey{KeyID: "k2", Algorithm: structs.RootKeyAlgorithmAES256GCM, Active: true}
+	require.NoError(state.UpsertRootKey(1002, k2))
+	err = state.DeleteRootKey(1003, "k1")
+	require.Error(err)
+	require.Contains(err.Error(), "in use")
+
+	update := v.Copy()
+	update.KeyID = "k2"
+	require.NoError(state.UpsertVariable(1003, update, nil))
+	require.NoError(state.DeleteRootKey(1004, "k1"))
+
+	out, err := state.RootKeyByID(nil, "k1")
+	require.NoError(err)
+	require.Nil(out)
+
+	index, err := state.Index("root_keys")
+	require.NoError(err)
+	require.EqualValues(1004, index)
+
+	require.Error(state.DeleteRootKey(1005, "k1"))
+}
+
+func TestStateStore_ServiceRegistrations(t *testing.T) {
+	require := require.New(t)
+	state := testStateStore(t)
+
+	alloc := mock.Alloc()
+	require.NoError(state.UpsertJob(999, alloc.Job))
+	require.NoError(state.UpsertAllocs(1000, []*structs.Allocation{alloc}))
+
+	web := &structs.ServiceRegistration{
+		ID:          "_nomad-task-web",
+		ServiceName: "web",
+		Namespace:   alloc.Namespace,
+		JobID:       alloc.JobID,
+		AllocID:     alloc.ID,
+		Task:        "web",
+	}
+	db := &structs.ServiceRegistration{
+		ID:          "_nomad-task-db",
+		ServiceName: "db",
+		Namespace:   alloc.Namespace,
+		JobID:       alloc.JobID,
+		AllocID:     alloc.ID,
+		Task:        "db",
+	}
+
+	// Create watchsets so we can test that upsert fires the watch
+	ws := memdb.NewWatchSet()
+	_, err := state.ServiceRegistrationsByServiceName(ws, alloc.Namespace, "web")
+	require.NoError(err)
+
+	require.NoError(state.UpsertServiceRegistrations(1001, []*structs.ServiceRegistration{web, db}))
+	require.True(watchFired(ws))
+
+	iter, err := state.ServiceRegistrationsByNamespace(nil, alloc.Namespace)
+	require.NoError(err)
+	var names []string
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		names = append(names, raw.(*structs.ServiceRegistration).ServiceName)
+	}
+	require.Equal([]string{"db", "web"}, names)
+
+	iter, err = state.ServiceRegistrationsByJobID(nil, alloc.Namespace, alloc.JobID)
+	require.NoError(err)
+	require.NotNil(iter.Next())
+
+	// Updating keeps the create index
+	require.NoError(state.UpsertServiceRegistrations(1002, []*structs.ServiceRegistration{web.Copy()}))
+	out, err := state.ServiceRegistrationByID(nil, web.ID)
+	require.NoError(err)
+	require.EqualValues(1001, out.CreateIndex)
+	require.EqualValues(1002, out.ModifyIndex)
+
+	// Deleting the registrations of a task keeps the others
+	require.NoError(state.DeleteServiceRegistrationsByTask(1003, alloc.ID, "web"))
+	out, err = state.ServiceRegistrationByID(nil, web.ID)
+	require.NoError(err)
+	require.Nil(out)
+	out, err = state.ServiceRegistrationByID(nil, db.ID)
+	require.NoError(err)
+	require.NotNil(out)
+
+	index, err := state.Index("service_registrations")
+	require.NoError(err)
+	require.EqualValues(1003, index)
+
+	// The registrations of terminal allocations are deleted
+	update := alloc.Copy()
+	update.ClientStatus = structs.AllocClientStatusComplete
+	require.NoError(state.UpdateAllocsFromClient(1004, []*structs.Allocation{update}))
+	out, err = state.ServiceRegistrationByID(nil, db.ID)
+	require.NoError(err)
+	require.Nil(out)
+
+	index, err = state.Index("service_registrations")
+	require.NoError(err)
+	require.EqualValues(1004, index)
+}
+
+func TestStateStore_Volumes(t *testing.T) {
+	require := require.New(t)
+	state := testStateStore(t)
+
+	db := mock.Volume()
+	db.ID = "db"
+	web := mock.Volume()
+	web.ID = "web"
+
+	// Create watchsets so we can test that upsert fires the watch
+	ws := memdb.NewWatchSet()
+	_, err := state.VolumeByID(ws, db.Namespace, db.ID)
+	require.NoError(err)
+
+	require.NoError(state.UpsertVolumes(1000, []*structs.Volume{db, web}))
+	require.True(watchFired(ws))
+
+	iter, err := state.VolumesByIDPrefix(nil, structs.DefaultNamespace, "d")
+	require.NoError(err)
+	var ids []string
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		ids = append(ids, raw.(*structs.Volume).ID)
+	}
+	require.Equal([]string{"db"}, ids)
+
+	// Claims are checked against the access mode
+	claim := &structs.VolumeClaim{AllocID: "a1", NodeID: "n1", Mode: structs.VolumeClaimWrite}
+	require.NoError(state.ClaimVolume(1001, db.Namespace, db.ID, claim))
+	other := &structs.VolumeClaim{AllocID: "a2", NodeID: "n2", Mode: structs.VolumeClaimRead}
+	require.EqualError(state.ClaimVolume(1002, db.Namespace, db.ID, other), `volume "db" is claimed on another node`)
+	require.EqualError(state.ClaimVolume(1002, db.Namespace, "missing", claim), `volume "missing" not found`)
+
+	// Updating keeps the create index and the claims
+	require.NoError(state.UpsertVolumes(1002, []*structs.Volume{db.Copy()}))
+	out, err := state.VolumeByID(nil, db.Namespace, db.ID)
+	require.NoError(err)
+	require.EqualValues(1000, out.CreateIndex)
+	require.EqualValues(1002, out.ModifyIndex)
+	require.Contains(out.Claims, "a1")
+
+	// Detaching releases the claims on the node
+	require.NoError(state.DetachVolume(1003, db.Namespace, db.ID, "n1"))
+	out, err = state.VolumeByID(nil, db.Namespace, db.ID)
+	require.NoError(err)
+	require.Empty(out.Claims)
+
+	require.NoError(state.DeleteVolumes(1004, structs.DefaultNamespace, []string{"db", "web"}))
+	out, err = state.VolumeByID(nil, db.Namespace, db.ID)
+	require.NoError(err)
+	require.Nil(out)
+	require.Error(state.DeleteVolumes(1005, structs.DefaultNamespace, []string{"db"}))
+
+	index, err := state.Index("volumes")
+	require.NoError(err)
+	require.EqualValues(1004, index)
+}
+
+func TestStateStore_RestoreVolume(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	state := testStateStore(t)
+
+	volume := mock.Volume()
+	restore, err := state.Restore()
+	require.NoError(err)
+	require.NoError(restore.VolumeRestore(volume))
+	restore.Commit()
+
+	out, err := state.VolumeByID(nil, volume.Namespace, volume.ID)
+	require.NoError(err)
+	require.Equal(volume, out)
+}
+
+func TestStateStore_UpsertScalingEvent(t *testing.T) {
+	require := require.New(t)
+	state := testStateStore(t)
+
+	job := mock.Job()
+	require.NoError(state.UpsertJob(1000, job))
+	group := job.TaskGroups[0].Name
+
+	// Only the most recent events are kept, the most recent first
+	for i := 0; i < structs.JobTrackedScalingEvents+5; i++ {
+		req := &structs.ScalingEventRegisterRequest{
+			Namespace: job.Namespace,
+			JobID:     job.ID,
+			TaskGroup: group,
+			ScalingEvent: &structs.ScalingEvent{
+				Time:    int64(i),
+				Message: fmt.Sprintf("event %d", i),
+			},
+		}
+		require.NoError(state.UpsertScalingEvent(uint64(1001+i), req))
+	}
+
+	events, index, err := state.ScalingEventsByJob(nil, job.Namespace, job.ID)
+	require.NoError(err)
+	require.Len(events[group], structs.JobTrackedScalingEvents)
+	last := uint64(1001 + structs.JobTrackedScalingEvents + 4)
+	require.Equal(last, index)
+	require.Equal(last, events[group][0].CreateIndex)
+	require.Equal(fmt.Sprintf("event %d", structs.JobTrackedScalingEvents+4), events[group][0].Message)
+
+	tableIndex, err := state.Index("scaling_event")
+	require.NoError(err)
+	require.Equal(last, tableIndex)
+
+	// Deleting the job deletes its scaling events
+	require.NoError(state.DeleteJob(last+1, job.Namespace, job.ID))
+	events, _, err = state.ScalingEventsByJob(nil, job.Namespace, job.ID)
+	require.NoError(err)
+	require.Nil(events)
+}
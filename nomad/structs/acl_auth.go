@@ -0,0 +1,608 @@
+package structs
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"time"
+
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/lib/auth"
+	"golang.org/x/crypto/blake2b"
+)
+
+const (
+	// ACLAuthMethodTypeOIDC is the type of the auth methods logging in users
+	// using the OIDC authorization code flow.
+	ACLAuthMethodTypeOIDC = "OIDC"
+
+	// ACLAuthMethodTypeJWT is the type of the auth methods logging in callers
+	// presenting a JSON Web Token signed by a trusted issuer, such as a CI
+	// system.
+	ACLAuthMethodTypeJWT = "JWT"
+
+	// ACLAuthMethodTokenLocalityLocal and ACLAuthMethodTokenLocalityGlobal
+	// are the localities of the tokens created by an auth method: local
+	// tokens are only valid in the region they're created in, while global
+	// tokens are created in the authoritative region and replicated.
+	ACLAuthMethodTokenLocalityLocal  = "local"
+	ACLAuthMethodTokenLocalityGlobal = "global"
+
+	// ACLBindingRuleBindTypePolicy binds the policy named by the rule to the
+	// tokens, ACLBindingRuleBindTypeRole binds the role named by the rule,
+	// and ACLBindingRuleBindTypeManagement creates management tokens.
+	ACLBindingRuleBindTypePolicy     = "policy"
+	ACLBindingRuleBindTypeRole       = "role"
+	ACLBindingRuleBindTypeManagement = "management"
+
+	// maxACLBindingRuleDescriptionLength limits a binding rule description
+	// length
+	maxACLBindingRuleDescriptionLength = 256
+)
+
+// ACLAuthMethod is a method users log in with to get an ACL token, the
+// policies of the token being determined by the binding rules of the method.
+type ACLAuthMethod struct {
+	Name string
+	Type string
+
+	// TokenLocality is the locality of the tokens created by the method,
+	// local or global.
+	TokenLocality string
+
+	// MaxTokenTTL is the duration the created tokens are valid for.
+	MaxTokenTTL time.Duration
+
+	// Default is whether the method is used when logging in without naming
+	// a method. Only one method can be the default.
+	Default bool
+
+	Config *ACLAuthMethodConfig
+
+	Hash        []byte
+	CreateTime  time.Time
+	ModifyTime  time.Time
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// ACLAuthMethodConfig is the configuration of the provider of an auth
+// method.
+type ACLAuthMethodConfig struct {
+	// OIDCDiscoveryURL is the issuer URL of the OIDC provider, the provider
+	// configuration being discovered from it.
+	OIDCDiscoveryURL string
+
+	// OIDCClientID and OIDCClientSecret are the credentials of Nomad with
+	// the OIDC provider.
+	OIDCClientID     string
+	OIDCClientSecret string
+
+	// OIDCScopes are the scopes requested in addition to "openid".
+	OIDCScopes []string
+
+	// BoundAudiences are the allowed audiences of the ID tokens, the client
+	// ID being used if empty. JWT auth methods require at least one.
+	BoundAudiences []string
+
+	// JWKSURL is the URL of the JSON Web Key Set the JWTs are verified with,
+	// and JWKSCaPem are PEM encoded CA certificates used to verify its TLS
+	// certificate.
+	JWKSURL   string
+	JWKSCaPem []string
+
+	// JWTValidationPubKeys are PEM encoded public keys the JWTs are verified
+	// with, used instead of a JWKS URL.
+	JWTValidationPubKeys []string
+
+	// BoundIssuer is the expected issuer of the JWTs.
+	BoundIssuer string
+
+	// ClockSkewLeeway is the clock skew allowed when validating the
+	// expiration of the JWTs.
+	ClockSkewLeeway time.Duration
+
+	// AllowedRedirectURIs are the URIs the OIDC provider may redirect to
+	// once the user is authenticated.
+	AllowedRedirectURIs []string
+
+	// DiscoveryCaPem are PEM encoded CA certificates used to verify the TLS
+	// certificate of the OIDC provider.
+	DiscoveryCaPem []string
+
+	// SigningAlgs are the allowed signing algorithms of the ID tokens.
+	SigningAlgs []string
+
+	// ClaimMappings and ListClaimMappings map the claims of the ID tokens to
+	// the names binding rule selectors refer to them with, as value.<name>
+	// and list.<name> respectively.
+	ClaimMappings     map[string]string
+	ListClaimMappings map[string]string
+}
+
+// Copy returns a deep copy of the auth method.
+func (a *ACLAuthMethod) Copy() *ACLAuthMethod {
+	if a == nil {
+		return nil
+	}
+	na := new(ACLAuthMethod)
+	*na = *a
+	na.Hash = append([]byte(nil), a.Hash...)
+	na.Config = a.Config.Copy()
+	return na
+}
+
+// Copy returns a deep copy of the auth method config.
+func (c *ACLAuthMethodConfig) Copy() *ACLAuthMethodConfig {
+	if c == nil {
+		return nil
+	}
+	nc := new(ACLAuthMethodConfig)
+	*nc = *c
+	nc.OIDCScopes = helper.CopySliceString(c.OIDCScopes)
+	nc.BoundAudiences = helper.CopySliceString(c.BoundAudiences)
+	nc.JWKSCaPem = helper.CopySliceString(c.JWKSCaPem)
+	nc.JWTValidationPubKeys = helper.CopySliceString(c.JWTValidationPubKeys)
+	nc.AllowedRedirectURIs = helper.CopySliceString(c.AllowedRedirectURIs)
+	nc.DiscoveryCaPem = helper.CopySliceString(c.DiscoveryCaPem)
+	nc.SigningAlgs = helper.CopySliceString(c.SigningAlgs)
+	nc.ClaimMappings = helper.CopyMapStringString(c.ClaimMappings)
+	nc.ListClaimMappings = helper.CopyMapStringString(c.ListClaimMappings)
+	return nc
+}
+
+// TokenLocalityIsGlobal returns whether the tokens created by the method are
+// global.
+func (a *ACLAuthMethod) TokenLocalityIsGlobal() bool {
+	return a.TokenLocality == ACLAuthMethodTokenLocalityGlobal
+}
+
+// SetHash is used to compute and set the hash of the auth method
+func (a *ACLAuthMethod) SetHash() []byte {
+	// Initialize a 256bit Blake2 hash (32 bytes)
+	hash, err := blake2b.New256(nil)
+	if err != nil {
+		panic(err)
+	}
+
+	// Write all the user set fields
+	hash.Write([]byte(a.Name))
+	hash.Write([]byte(a.Type))
+	hash.Write([]byte(a.TokenLocality))
+	hash.Write([]byte(a.MaxTokenTTL.String()))
+	if a.Default {
+		hash.Write([]byte("default"))
+	}
+	if c := a.Config; c != nil {
+		hash.Write([]byte(c.OIDCDiscoveryURL))
+		hash.Write([]byte(c.OIDCClientID))
+		hash.Write([]byte(c.OIDCClientSecret))
+		hash.Write([]byte(c.JWKSURL))
+		hash.Write([]byte(c.BoundIssuer))
+		hash.Write([]byte(c.ClockSkewLeeway.String()))
+		for _, lists := range [][]string{c.OIDCScopes, c.BoundAudiences,
+			c.AllowedRedirectURIs, c.DiscoveryCaPem, c.SigningAlgs,
+			c.JWKSCaPem, c.JWTValidationPubKeys} {
+			for _, v := range lists {
+				hash.Write([]byte(v))
+			}
+			hash.Write([]byte{0})
+		}
+		for _, m := range []map[string]string{c.ClaimMappings, c.ListClaimMappings} {
+			keys := make([]string, 0, len(m))
+			for k := range m {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				hash.Write([]byte(k))
+				hash.Write([]byte(m[k]))
+			}
+			hash.Write([]byte{0})
+		}
+	}
+
+	// Finalize the hash
+	hashVal := hash.Sum(nil)
+
+	// Set and return the hash
+	a.Hash = hashVal
+	return hashVal
+}
+
+// Validate is used to sanity check an auth method
+func (a *ACLAuthMethod) Validate() error {
+	var mErr multierror.Error
+	if !validPolicyName.MatchString(a.Name) {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid name '%s'", a.Name))
+	}
+	switch a.Type {
+	case ACLAuthMethodTypeOIDC, ACLAuthMethodTypeJWT:
+	default:
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid type '%s'", a.Type))
+	}
+	switch a.TokenLocality {
+	case ACLAuthMethodTokenLocalityLocal, ACLAuthMethodTokenLocalityGlobal:
+	default:
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid token locality '%s'", a.TokenLocality))
+	}
+	if a.MaxTokenTTL <= 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("max token TTL must be positive"))
+	}
+
+	c := a.Config
+	if c == nil {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("missing config"))
+		return mErr.ErrorOrNil()
+	}
+	if a.Type == ACLAuthMethodTypeJWT {
+		switch {
+		case c.JWKSURL == "" && len(c.JWTValidationPubKeys) == 0:
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("missing JWKS URL or JWT validation public keys"))
+		case c.JWKSURL != "" && len(c.JWTValidationPubKeys) != 0:
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("JWKS URL and JWT validation public keys are exclusive"))
+		case c.JWKSURL != "":
+			if u, err := url.Parse(c.JWKSURL); err != nil || u.Host == "" {
+				mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid JWKS URL '%s'", c.JWKSURL))
+			}
+		default:
+			if _, err := auth.ParsePublicKeys(c.JWTValidationPubKeys); err != nil {
+				mErr.Errors = append(mErr.Errors, err)
+			}
+		}
+		if len(c.BoundAudiences) == 0 {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("missing bound audiences"))
+		}
+		if c.ClockSkewLeeway < 0 {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("clock skew leeway cannot be negative"))
+		}
+	} else {
+		if u, err := url.Parse(c.OIDCDiscoveryURL); err != nil || u.Host == "" {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid OIDC discovery URL '%s'", c.OIDCDiscoveryURL))
+		}
+		if c.OIDCClientID == "" {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("missing OIDC client ID"))
+		}
+		if len(c.AllowedRedirectURIs) == 0 {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("missing allowed redirect URIs"))
+		}
+	}
+	if err := auth.ValidateSigningAlgs(c.SigningAlgs); err != nil {
+		mErr.Errors = append(mErr.Errors, err)
+	}
+	return mErr.ErrorOrNil()
+}
+
+// OIDCConfig returns the configuration of the OIDC provider of the method.
+func (a *ACLAuthMethod) OIDCConfig() *auth.OIDCConfig {
+	return &auth.OIDCConfig{
+		DiscoveryURL:   a.Config.OIDCDiscoveryURL,
+		ClientID:       a.Config.OIDCClientID,
+		ClientSecret:   a.Config.OIDCClientSecret,
+		Scopes:         a.Config.OIDCScopes,
+		BoundAudiences: a.Config.BoundAudiences,
+		DiscoveryCaPem: a.Config.DiscoveryCaPem,
+		SigningAlgs:    a.Config.SigningAlgs,
+	}
+}
+
+// JWTConfig returns the configuration of the validation of the JWTs of the
+// method.
+func (a *ACLAuthMethod) JWTConfig() *auth.JWTConfig {
+	return &auth.JWTConfig{
+		JWKSURL:           a.Config.JWKSURL,
+		JWKSCaPem:         a.Config.JWKSCaPem,
+		ValidationPubKeys: a.Config.JWTValidationPubKeys,
+		BoundIssuer:       a.Config.BoundIssuer,
+		BoundAudiences:    a.Config.BoundAudiences,
+		SigningAlgs:       a.Config.SigningAlgs,
+		ClockSkewLeeway:   a.Config.ClockSkewLeeway,
+	}
+}
+
+// RedirectURIAllowed returns whether the OIDC provider may redirect to the
+// URI.
+func (a *ACLAuthMethod) RedirectURIAllowed(uri string) bool {
+	for _, allowed := range a.Config.AllowedRedirectURIs {
+		if uri == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *ACLAuthMethod) Stub() *ACLAuthMethodListStub {
+	return &ACLAuthMethodListStub{
+		Name:        a.Name,
+		Type:        a.Type,
+		Default:     a.Default,
+		Hash:        a.Hash,
+		CreateIndex: a.CreateIndex,
+		ModifyIndex: a.ModifyIndex,
+	}
+}
+
+// ACLAuthMethodListStub is used for listing auth methods
+type ACLAuthMethodListStub struct {
+	Name        string
+	Type        string
+	Default     bool
+	Hash        []byte
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// ACLBindingRule binds the tokens created by logging in with an auth method
+// to a policy, or makes them management tokens, if the selector matches the
+// claims of the user.
+type ACLBindingRule struct {
+	ID          string
+	Description string
+
+	// AuthMethod is the name of the auth method of the rule.
+	AuthMethod string
+
+	// Selector is the expression matched against the claims of the user,
+	// the empty selector matching all users.
+	Selector string
+
+	// BindType is the type of the binding, policy, role or management, and
+	// BindName the name of the policy or role bound by the rule.
+	BindType string
+	BindName string
+
+	Hash        []byte
+	CreateTime  time.Time
+	ModifyTime  time.Time
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// Copy returns a copy of the binding rule.
+func (b *ACLBindingRule) Copy() *ACLBindingRule {
+	if b == nil {
+		return nil
+	}
+	nb := new(ACLBindingRule)
+	*nb = *b
+	nb.Hash = append([]byte(nil), b.Hash...)
+	return nb
+}
+
+// SetHash is used to compute and set the hash of the binding rule
+func (b *ACLBindingRule) SetHash() []byte {
+	// Initialize a 256bit Blake2 hash (32 bytes)
+	hash, err := blake2b.New256(nil)
+	if err != nil {
+		panic(err)
+	}
+
+	// Write all the user set fields
+	hash.Write([]byte(b.ID))
+	hash.Write([]byte(b.Description))
+	hash.Write([]byte(b.AuthMethod))
+	hash.Write([]byte(b.Selector))
+	hash.Write([]byte(b.BindType))
+	hash.Write([]byte(b.BindName))
+
+	// Finalize the hash
+	hashVal := hash.Sum(nil)
+
+	// Set and return the hash
+	b.Hash = hashVal
+	return hashVal
+}
+
+// Validate is used to sanity check a binding rule
+func (b *ACLBindingRule) Validate() error {
+	var mErr multierror.Error
+	if b.AuthMethod == "" {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("missing auth method"))
+	}
+	if len(b.Description) > maxACLBindingRuleDescriptionLength {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("description longer than %d", maxACLBindingRuleDescriptionLength))
+	}
+	switch b.BindType {
+	case ACLBindingRuleBindTypePolicy, ACLBindingRuleBindTypeRole:
+		if b.BindName == "" {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("%s binding missing bind name", b.BindType))
+		}
+	case ACLBindingRuleBindTypeManagement:
+		if b.BindName != "" {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("management binding cannot have a bind name"))
+		}
+	default:
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid bind type '%s'", b.BindType))
+	}
+	if _, err := auth.ParseSelector(b.Selector); err != nil {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid selector: %v", err))
+	}
+	return mErr.ErrorOrNil()
+}
+
+func (b *ACLBindingRule) Stub() *ACLBindingRuleListStub {
+	return &ACLBindingRuleListStub{
+		ID:          b.ID,
+		Description: b.Description,
+		AuthMethod:  b.AuthMethod,
+		Hash:        b.Hash,
+		CreateIndex: b.CreateIndex,
+		ModifyIndex: b.ModifyIndex,
+	}
+}
+
+// ACLBindingRuleListStub is used for listing binding rules
+type ACLBindingRuleListStub struct {
+	ID          string
+	Description string
+	AuthMethod  string
+	Hash        []byte
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// ACLAuthMethodUpsertRequest is used to upsert a set of auth methods
+type ACLAuthMethodUpsertRequest struct {
+	AuthMethods []*ACLAuthMethod
+	WriteRequest
+}
+
+// ACLAuthMethodUpsertResponse is used to return from an
+// ACLAuthMethodUpsertRequest
+type ACLAuthMethodUpsertResponse struct {
+	AuthMethods []*ACLAuthMethod
+	WriteMeta
+}
+
+// ACLAuthMethodDeleteRequest is used to delete a set of auth methods
+type ACLAuthMethodDeleteRequest struct {
+	Names []string
+	WriteRequest
+}
+
+// ACLAuthMethodListRequest is used to request a list of auth methods
+type ACLAuthMethodListRequest struct {
+	QueryOptions
+}
+
+// ACLAuthMethodListResponse is used for a list request
+type ACLAuthMethodListResponse struct {
+	AuthMethods []*ACLAuthMethodListStub
+	QueryMeta
+}
+
+// ACLAuthMethodGetRequest is used to query a specific auth method
+type ACLAuthMethodGetRequest struct {
+	Name string
+	QueryOptions
+}
+
+// ACLAuthMethodGetResponse is used to return a single auth method
+type ACLAuthMethodGetResponse struct {
+	AuthMethod *ACLAuthMethod
+	QueryMeta
+}
+
+// ACLAuthMethodsGetRequest is used to query a set of auth methods
+type ACLAuthMethodsGetRequest struct {
+	Names []string
+	QueryOptions
+}
+
+// ACLAuthMethodsGetResponse is used to return a set of auth methods
+type ACLAuthMethodsGetResponse struct {
+	AuthMethods map[string]*ACLAuthMethod
+	QueryMeta
+}
+
+// ACLBindingRulesUpsertRequest is used to upsert a set of binding rules
+type ACLBindingRulesUpsertRequest struct {
+	ACLBindingRules []*ACLBindingRule
+	WriteRequest
+}
+
+// ACLBindingRulesUpsertResponse is used to return from an
+// ACLBindingRulesUpsertRequest
+type ACLBindingRulesUpsertResponse struct {
+	ACLBindingRules []*ACLBindingRule
+	WriteMeta
+}
+
+// ACLBindingRulesDeleteRequest is used to delete a set of binding rules
+type ACLBindingRulesDeleteRequest struct {
+	ACLBindingRuleIDs []string
+	WriteRequest
+}
+
+// ACLBindingRulesListRequest is used to request a list of binding rules
+type ACLBindingRulesListRequest struct {
+	QueryOptions
+}
+
+// ACLBindingRulesListResponse is used for a list request
+type ACLBindingRulesListResponse struct {
+	ACLBindingRules []*ACLBindingRuleListStub
+	QueryMeta
+}
+
+// ACLBindingRuleRequest is used to query a specific binding rule
+type ACLBindingRuleRequest struct {
+	ACLBindingRuleID string
+	QueryOptions
+}
+
+// ACLBindingRuleResponse is used to return a single binding rule
+type ACLBindingRuleResponse struct {
+	ACLBindingRule *ACLBindingRule
+	QueryMeta
+}
+
+// ACLBindingRulesRequest is used to query a set of binding rules
+type ACLBindingRulesRequest struct {
+	ACLBindingRuleIDs []string
+	QueryOptions
+}
+
+// ACLBindingRulesResponse is used to return a set of binding rules
+type ACLBindingRulesResponse struct {
+	ACLBindingRules map[string]*ACLBindingRule
+	QueryMeta
+}
+
+// ACLOIDCAuthURLRequest is used to start logging in with an OIDC auth
+// method. The client generates the state and nonce, and verifies the state
+// of the redirect once the user is authenticated.
+type ACLOIDCAuthURLRequest struct {
+	// AuthMethodName is the method to log in with, the default method
+	// being used if empty.
+	AuthMethodName string
+
+	// RedirectURI is where the OIDC provider redirects to once the user is
+	// authenticated. It must be an allowed redirect URI of the method.
+	RedirectURI string
+
+	// ClientNonce is the nonce the ID token must contain, and State the
+	// state of the redirect.
+	ClientNonce string
+	State       string
+
+	WriteRequest
+}
+
+// ACLOIDCAuthURLResponse returns the URL of the OIDC provider the user
+// authenticates at.
+type ACLOIDCAuthURLResponse struct {
+	AuthURL string
+	WriteMeta
+}
+
+// ACLOIDCCompleteAuthRequest is used to complete logging in with an OIDC
+// auth method, exchanging the authorization code of the redirect for an ACL
+// token.
+type ACLOIDCCompleteAuthRequest struct {
+	AuthMethodName string
+	ClientNonce    string
+	Code           string
+	RedirectURI    string
+	WriteRequest
+}
+
+// ACLLoginRequest is used to log in with a JWT auth method, exchanging a
+// JWT signed by a trusted issuer for an ACL token.
+type ACLLoginRequest struct {
+	// AuthMethodName is the method to log in with, the default method
+	// being used if empty.
+	AuthMethodName string
+
+	// LoginToken is the JWT presented by the caller.
+	LoginToken string
+
+	WriteRequest
+}
+
+// ACLLoginResponse returns the ACL token created by logging in.
+type ACLLoginResponse struct {
+	ACLToken *ACLToken
+	WriteMeta
+}
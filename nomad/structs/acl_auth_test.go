@@ -0,0 +1,209 @@
+package structs
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestACLAuthMethod_Validate(t *testing.T) {
+	valid := func() *ACLAuthMethod {
+		return &ACLAuthMethod{
+			Name:          "okta",
+			Type:          ACLAuthMethodTypeOIDC,
+			TokenLocality: ACLAuthMethodTokenLocalityGlobal,
+			MaxTokenTTL:   time.Hour,
+			Config: &ACLAuthMethodConfig{
+				OIDCDiscoveryURL:    "https://example.okta.com",
+				OIDCClientID:        "nomad",
+				AllowedRedirectURIs: []string{"http://localhost:4649/oidc/callback"},
+			},
+		}
+	}
+
+	cases := []struct {
+		name   string
+		modify func(*ACLAuthMethod)
+		valid  bool
+	}{
+		{
+			name:   "valid",
+			modify: func(*ACLAuthMethod) {},
+			valid:  true,
+		},
+		{
+			name:   "invalid name",
+			modify: func(a *ACLAuthMethod) { a.Name = "okta/dev" },
+		},
+		{
+			name:   "invalid type",
+			modify: func(a *ACLAuthMethod) { a.Type = "LDAP" },
+		},
+		{
+			name:   "invalid locality",
+			modify: func(a *ACLAuthMethod) { a.TokenLocality = "region" },
+		},
+		{
+			name:   "missing TTL",
+			modify: func(a *ACLAuthMethod) { a.MaxTokenTTL = 0 },
+		},
+		{
+			name:   "missing config",
+			modify: func(a *ACLAuthMethod) { a.Config = nil },
+		},
+		{
+			name:   "invalid discovery URL",
+			modify: func(a *ACLAuthMethod) { a.Config.OIDCDiscoveryURL = "example.okta.com" },
+		},
+		{
+			name:   "missing client ID",
+			modify: func(a *ACLAuthMethod) { a.Config.OIDCClientID = "" },
+		},
+		{
+			name:   "missing redirect URIs",
+			modify: func(a *ACLAuthMethod) { a.Config.AllowedRedirectURIs = nil },
+		},
+		{
+			name:   "invalid signing algorithm",
+			modify: func(a *ACLAuthMethod) { a.Config.SigningAlgs = []string{"HS256"} },
+		},
+		{
+			name: "valid JWT",
+			modify: func(a *ACLAuthMethod) {
+				a.Type = ACLAuthMethodTypeJWT
+				a.Config = &ACLAuthMethodConfig{
+					JWKSURL:        "https://ci.example.com/keys",
+					BoundAudiences: []string{"nomad"},
+				}
+			},
+			valid: true,
+		},
+		{
+			name: "JWT missing bound audiences",
+			modify: func(a *ACLAuthMethod) {
+				a.Type = ACLAuthMethodTypeJWT
+				a.Config = &ACLAuthMethodConfig{JWKSURL: "https://ci.example.com/keys"}
+			},
+		},
+		{
+			name: "JWT missing keys",
+			modify: func(a *ACLAuthMethod) {
+				a.Type = ACLAuthMethodTypeJWT
+				a.Config = &ACLAuthMethodConfig{}
+			},
+		},
+		{
+			name: "JWT with both JWKS URL and public keys",
+			modify: func(a *ACLAuthMethod) {
+				a.Type = ACLAuthMethodTypeJWT
+				a.Config = &ACLAuthMethodConfig{
+					JWKSURL:              "https://ci.example.com/keys",
+					JWTValidationPubKeys: []string{"key"},
+				}
+			},
+		},
+		{
+			name: "JWT invalid public key",
+			modify: func(a *ACLAuthMethod) {
+				a.Type = ACLAuthMethodTypeJWT
+				a.Config = &ACLAuthMethodConfig{JWTValidationPubKeys: []string{"key"}}
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			method := valid()
+			c.modify(method)
+			err := method.Validate()
+			if c.valid {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestACLBindingRule_Validate(t *testing.T) {
+	cases := []struct {
+		name  string
+		rule  *ACLBindingRule
+		valid bool
+	}{
+		{
+			name: "valid policy",
+			rule: &ACLBindingRule{
+				AuthMethod: "okta",
+				Selector:   `"engineering" in list.groups`,
+				BindType:   ACLBindingRuleBindTypePolicy,
+				BindName:   "engineering-read",
+			},
+			valid: true,
+		},
+		{
+			name: "valid management",
+			rule: &ACLBindingRule{
+				AuthMethod: "okta",
+				BindType:   ACLBindingRuleBindTypeManagement,
+			},
+			valid: true,
+		},
+		{
+			name: "missing auth method",
+			rule: &ACLBindingRule{BindType: ACLBindingRuleBindTypeManagement},
+		},
+		{
+			name: "policy missing bind name",
+			rule: &ACLBindingRule{AuthMethod: "okta", BindType: ACLBindingRuleBindTypePolicy},
+		},
+		{
+			name: "role missing bind name",
+			rule: &ACLBindingRule{AuthMethod: "okta", BindType: ACLBindingRuleBindTypeRole},
+		},
+		{
+			name: "management with bind name",
+			rule: &ACLBindingRule{AuthMethod: "okta", BindType: ACLBindingRuleBindTypeManagement, BindName: "admin"},
+		},
+		{
+			name: "invalid bind type",
+			rule: &ACLBindingRule{AuthMethod: "okta", BindType: "group", BindName: "admin"},
+		},
+		{
+			name: "invalid selector",
+			rule: &ACLBindingRule{AuthMethod: "okta", BindType: ACLBindingRuleBindTypeManagement, Selector: "groups"},
+		},
+		{
+			name: "long description",
+			rule: &ACLBindingRule{
+				AuthMethod:  "okta",
+				BindType:    ACLBindingRuleBindTypeManagement,
+				Description: strings.Repeat("a", maxACLBindingRuleDescriptionLength+1),
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := c.rule.Validate()
+			if c.valid {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestACLToken_IsExpired(t *testing.T) {
+	now := time.Now()
+	token := &ACLToken{}
+	require.False(t, token.IsExpired(now))
+
+	expiration := now.Add(time.Minute)
+	token.ExpirationTime = &expiration
+	require.False(t, token.IsExpired(now))
+	require.True(t, token.IsExpired(now.Add(2*time.Minute)))
+}
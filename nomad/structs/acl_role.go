@@ -0,0 +1,155 @@
+package structs
+
+import (
+	"fmt"
+
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/helper"
+	"golang.org/x/crypto/blake2b"
+)
+
+const (
+	// maxACLRoleDescriptionLength limits a role description length
+	maxACLRoleDescriptionLength = 256
+)
+
+// ACLRole is a named bundle of policies. Tokens linked to a role are granted
+// the policies of the role in addition to their own, so that the policies
+// of many tokens can be updated at once.
+type ACLRole struct {
+	Name        string   // Unique name
+	Description string   // Human readable
+	Policies    []string // Policies bundled by the role
+	Hash        []byte
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// Copy returns a deep copy of the role.
+func (r *ACLRole) Copy() *ACLRole {
+	if r == nil {
+		return nil
+	}
+	nr := new(ACLRole)
+	*nr = *r
+	nr.Policies = helper.CopySliceString(r.Policies)
+	nr.Hash = append([]byte(nil), r.Hash...)
+	return nr
+}
+
+// SetHash is used to compute and set the hash of the role
+func (r *ACLRole) SetHash() []byte {
+	// Initialize a 256bit Blake2 hash (32 bytes)
+	hash, err := blake2b.New256(nil)
+	if err != nil {
+		panic(err)
+	}
+
+	// Write all the user set fields
+	hash.Write([]byte(r.Name))
+	hash.Write([]byte(r.Description))
+	for _, policyName := range r.Policies {
+		hash.Write([]byte(policyName))
+	}
+
+	// Finalize the hash
+	hashVal := hash.Sum(nil)
+
+	// Set and return the hash
+	r.Hash = hashVal
+	return hashVal
+}
+
+// Validate is used to sanity check a role
+func (r *ACLRole) Validate() error {
+	var mErr multierror.Error
+	if !validPolicyName.MatchString(r.Name) {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid name '%s'", r.Name))
+	}
+	if len(r.Description) > maxACLRoleDescriptionLength {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("description longer than %d", maxACLRoleDescriptionLength))
+	}
+	if len(r.Policies) == 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("missing policies"))
+	}
+	for _, policyName := range r.Policies {
+		if !validPolicyName.MatchString(policyName) {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid policy name '%s'", policyName))
+		}
+	}
+	return mErr.ErrorOrNil()
+}
+
+func (r *ACLRole) Stub() *ACLRoleListStub {
+	return &ACLRoleListStub{
+		Name:        r.Name,
+		Description: r.Description,
+		Policies:    r.Policies,
+		Hash:        r.Hash,
+		CreateIndex: r.CreateIndex,
+		ModifyIndex: r.ModifyIndex,
+	}
+}
+
+// ACLRoleListStub is used for listing roles
+type ACLRoleListStub struct {
+	Name        string
+	Description string
+	Policies    []string
+	Hash        []byte
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// ACLRolesUpsertRequest is used to upsert a set of roles
+type ACLRolesUpsertRequest struct {
+	Roles []*ACLRole
+	WriteRequest
+}
+
+// ACLRolesUpsertResponse is used to return from an ACLRolesUpsertRequest
+type ACLRolesUpsertResponse struct {
+	Roles []*ACLRole
+	WriteMeta
+}
+
+// ACLRolesDeleteRequest is used to delete a set of roles
+type ACLRolesDeleteRequest struct {
+	Names []string
+	WriteRequest
+}
+
+// ACLRolesListRequest is used to request a list of roles
+type ACLRolesListRequest struct {
+	QueryOptions
+}
+
+// ACLRolesListResponse is used for a list request
+type ACLRolesListResponse struct {
+	Roles []*ACLRoleListStub
+	QueryMeta
+}
+
+// ACLRoleRequest is used to query a specific role
+type ACLRoleRequest struct {
+	Name string
+	QueryOptions
+}
+
+// ACLRoleResponse is used to return a single role
+type ACLRoleResponse struct {
+	Role *ACLRole
+	QueryMeta
+}
+
+// ACLRolesRequest is used to query a set of roles
+type ACLRolesRequest struct {
+	Names []string
+	QueryOptions
+}
+
+// ACLRolesResponse is used to return a set of roles
+type ACLRolesResponse struct {
+	Roles map[string]*ACLRole
+	QueryMeta
+}
@@ -0,0 +1,124 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+)
+
+const (
+	// AdmissionWebhookTypeMutating is the type of the webhooks that may
+	// return a modified job.
+	AdmissionWebhookTypeMutating = "mutating"
+
+	// AdmissionWebhookTypeValidating is the type of the webhooks that may
+	// only accept or reject a job.
+	AdmissionWebhookTypeValidating = "validating"
+
+	// AdmissionFailurePolicyFail rejects the jobs when the webhook can't be
+	// reached or returns an invalid response.
+	AdmissionFailurePolicyFail = "fail"
+
+	// AdmissionFailurePolicyIgnore admits the jobs when the webhook can't be
+	// reached or returns an invalid response.
+	AdmissionFailurePolicyIgnore = "ignore"
+
+	// DefaultAdmissionWebhookTimeout is the default timeout of the requests
+	// to a webhook.
+	DefaultAdmissionWebhookTimeout = 10 * time.Second
+)
+
+// AdmissionWebhookConfig configures an external webhook called by the
+// servers when a job is registered, planned or validated, to mutate or
+// reject the job based on the policies of the organization.
+type AdmissionWebhookConfig struct {
+	// Name is the unique name of the webhook, reported in the warnings and
+	// errors of the jobs.
+	Name string `mapstructure:"-"`
+
+	// URL is the endpoint the jobs are posted to.
+	URL string `mapstructure:"url"`
+
+	// Type is either "mutating" or "validating". The mutating webhooks are
+	// called before the validating ones.
+	Type string `mapstructure:"type"`
+
+	// Timeout is the timeout of the requests to the webhook.
+	Timeout time.Duration `mapstructure:"timeout"`
+
+	// FailurePolicy is either "fail" or "ignore", and sets whether jobs are
+	// rejected when the webhook fails.
+	FailurePolicy string `mapstructure:"failure_policy"`
+}
+
+// Canonicalize sets the defaults of the webhook.
+func (a *AdmissionWebhookConfig) Canonicalize() {
+	if a.Type == "" {
+		a.Type = AdmissionWebhookTypeValidating
+	}
+	if a.Timeout == 0 {
+		a.Timeout = DefaultAdmissionWebhookTimeout
+	}
+	if a.FailurePolicy == "" {
+		a.FailurePolicy = AdmissionFailurePolicyFail
+	}
+}
+
+// Validate returns an error if the webhook is invalid. It must be
+// canonicalized first.
+func (a *AdmissionWebhookConfig) Validate() error {
+	if a.Name == "" {
+		return fmt.Errorf("admission webhook requires a name")
+	}
+	if u, err := url.Parse(a.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return fmt.Errorf("admission webhook %q requires an http or https url", a.Name)
+	}
+	switch a.Type {
+	case AdmissionWebhookTypeMutating, AdmissionWebhookTypeValidating:
+	default:
+		return fmt.Errorf("admission webhook %q has invalid type %q", a.Name, a.Type)
+	}
+	switch a.FailurePolicy {
+	case AdmissionFailurePolicyFail, AdmissionFailurePolicyIgnore:
+	default:
+		return fmt.Errorf("admission webhook %q has invalid failure policy %q", a.Name, a.FailurePolicy)
+	}
+	if a.Timeout < 0 {
+		return fmt.Errorf("admission webhook %q timeout must be positive", a.Name)
+	}
+	return nil
+}
+
+// Copy returns a copy of the webhook.
+func (a *AdmissionWebhookConfig) Copy() *AdmissionWebhookConfig {
+	if a == nil {
+		return nil
+	}
+	c := *a
+	return &c
+}
+
+// AdmissionWebhookSetMerge merges two sets of webhooks. The webhooks of the
+// second set replace the webhooks of the first set with the same name, the
+// order of the webhooks being kept.
+func AdmissionWebhookSetMerge(first, second []*AdmissionWebhookConfig) []*AdmissionWebhookConfig {
+	if len(first) == 0 && len(second) == 0 {
+		return nil
+	}
+
+	out := make([]*AdmissionWebhookConfig, 0, len(first)+len(second))
+	index := make(map[string]int, len(first))
+	for _, w := range first {
+		index[w.Name] = len(out)
+		out = append(out, w.Copy())
+	}
+	for _, w := range second {
+		if i, ok := index[w.Name]; ok {
+			out[i] = w.Copy()
+			continue
+		}
+		index[w.Name] = len(out)
+		out = append(out, w.Copy())
+	}
+	return out
+}
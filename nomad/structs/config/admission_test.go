@@ -0,0 +1,53 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdmissionWebhookConfig_Validate(t *testing.T) {
+	t.Parallel()
+
+	w := &AdmissionWebhookConfig{Name: "policy", URL: "https://policy.example.com/nomad"}
+	w.Canonicalize()
+	require.Equal(t, AdmissionWebhookTypeValidating, w.Type)
+	require.Equal(t, AdmissionFailurePolicyFail, w.FailurePolicy)
+	require.Equal(t, DefaultAdmissionWebhookTimeout, w.Timeout)
+	require.NoError(t, w.Validate())
+
+	invalid := []*AdmissionWebhookConfig{
+		{URL: "https://policy.example.com"},
+		{Name: "policy", URL: "policy.example.com"},
+		{Name: "policy", URL: "https://policy.example.com", Type: "other"},
+		{Name: "policy", URL: "https://policy.example.com", FailurePolicy: "retry"},
+	}
+	for _, w := range invalid {
+		w.Canonicalize()
+		require.Error(t, w.Validate(), "%#v", w)
+	}
+}
+
+func TestAdmissionWebhookSetMerge(t *testing.T) {
+	t.Parallel()
+
+	first := []*AdmissionWebhookConfig{
+		{Name: "a", URL: "http://a"},
+		{Name: "b", URL: "http://b"},
+	}
+	second := []*AdmissionWebhookConfig{
+		{Name: "c", URL: "http://c"},
+		{Name: "a", URL: "http://a2", Timeout: time.Second},
+	}
+
+	out := AdmissionWebhookSetMerge(first, second)
+	require.Len(t, out, 3)
+	require.Equal(t, "http://a2", out[0].URL)
+	require.Equal(t, time.Second, out[0].Timeout)
+	require.Equal(t, "b", out[1].Name)
+	require.Equal(t, "c", out[2].Name)
+	require.Equal(t, "http://a", first[0].URL)
+
+	require.Nil(t, AdmissionWebhookSetMerge(nil, nil))
+}
@@ -24,7 +24,11 @@ type AutopilotConfig struct {
 	// be behind before being considered unhealthy.
 	MaxTrailingLogs int `mapstructure:"max_trailing_logs"`
 
-	// (Enterprise-only) EnableRedundancyZones specifies whether to enable redundancy zones.
+	// MinQuorum is the minimum number of alive servers the cluster must have
+	// for dead servers to be removed.
+	MinQuorum int `mapstructure:"min_quorum"`
+
+	// EnableRedundancyZones specifies whether to enable redundancy zones.
 	EnableRedundancyZones *bool `mapstructure:"enable_redundancy_zones"`
 
 	// (Enterprise-only) DisableUpgradeMigration will disable Autopilot's upgrade migration
@@ -62,6 +66,9 @@ func (a *AutopilotConfig) Merge(b *AutopilotConfig) *AutopilotConfig {
 	if b.MaxTrailingLogs != 0 {
 		result.MaxTrailingLogs = b.MaxTrailingLogs
 	}
+	if b.MinQuorum != 0 {
+		result.MinQuorum = b.MinQuorum
+	}
 	if b.EnableRedundancyZones != nil {
 		result.EnableRedundancyZones = b.EnableRedundancyZones
 	}
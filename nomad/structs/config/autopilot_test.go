@@ -14,6 +14,7 @@ func TestAutopilotConfig_Merge(t *testing.T) {
 		ServerStabilizationTime: 1 * time.Second,
 		LastContactThreshold:    1 * time.Second,
 		MaxTrailingLogs:         1,
+		MinQuorum:               3,
 		EnableRedundancyZones:   &trueValue,
 		DisableUpgradeMigration: &falseValue,
 		EnableCustomUpgrades:    &trueValue,
@@ -24,6 +25,7 @@ func TestAutopilotConfig_Merge(t *testing.T) {
 		ServerStabilizationTime: 2 * time.Second,
 		LastContactThreshold:    2 * time.Second,
 		MaxTrailingLogs:         2,
+		MinQuorum:               5,
 		EnableRedundancyZones:   nil,
 		DisableUpgradeMigration: nil,
 		EnableCustomUpgrades:    nil,
@@ -34,6 +36,7 @@ func TestAutopilotConfig_Merge(t *testing.T) {
 		ServerStabilizationTime: 2 * time.Second,
 		LastContactThreshold:    2 * time.Second,
 		MaxTrailingLogs:         2,
+		MinQuorum:               5,
 		EnableRedundancyZones:   &trueValue,
 		DisableUpgradeMigration: &falseValue,
 		EnableCustomUpgrades:    &trueValue,
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// DefaultDrainDeadline is the default time a client draining on
+	// shutdown waits for its allocations to migrate before they are stopped.
+	DefaultDrainDeadline = time.Hour
+)
+
+// DrainConfig configures the drain a client performs on itself before it
+// shuts down.
+type DrainConfig struct {
+	// Deadline is the time allocations may take to migrate off the node.
+	// Allocations still running once it passes are stopped.
+	Deadline time.Duration `mapstructure:"deadline"`
+
+	// IgnoreSystemJobs leaves the allocations of system jobs running until
+	// the client stops.
+	IgnoreSystemJobs bool `mapstructure:"ignore_system_jobs"`
+}
+
+// Canonicalize sets the defaults of the drain.
+func (d *DrainConfig) Canonicalize() {
+	if d.Deadline == 0 {
+		d.Deadline = DefaultDrainDeadline
+	}
+}
+
+// Validate returns an error if the drain is invalid. It must be canonicalized
+// first.
+func (d *DrainConfig) Validate() error {
+	if d.Deadline < 0 {
+		return fmt.Errorf("drain deadline must be positive")
+	}
+	return nil
+}
+
+// Copy returns a copy of the drain.
+func (d *DrainConfig) Copy() *DrainConfig {
+	if d == nil {
+		return nil
+	}
+	c := *d
+	return &c
+}
+
+// Merge merges two drain configurations, values set in the second taking
+// precedence.
+func (d *DrainConfig) Merge(b *DrainConfig) *DrainConfig {
+	if d == nil {
+		return b.Copy()
+	}
+
+	result := *d
+	if b == nil {
+		return &result
+	}
+	if b.Deadline != 0 {
+		result.Deadline = b.Deadline
+	}
+	if b.IgnoreSystemJobs {
+		result.IgnoreSystemJobs = true
+	}
+	return &result
+}
@@ -0,0 +1,40 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDrainConfig_Validate(t *testing.T) {
+	t.Parallel()
+
+	d := &DrainConfig{}
+	d.Canonicalize()
+	require.Equal(t, DefaultDrainDeadline, d.Deadline)
+	require.NoError(t, d.Validate())
+
+	d = &DrainConfig{Deadline: -time.Second}
+	d.Canonicalize()
+	require.Error(t, d.Validate())
+}
+
+func TestDrainConfig_Merge(t *testing.T) {
+	t.Parallel()
+
+	a := &DrainConfig{Deadline: time.Minute}
+	b := &DrainConfig{IgnoreSystemJobs: true}
+
+	out := a.Merge(b)
+	require.Equal(t, time.Minute, out.Deadline)
+	require.True(t, out.IgnoreSystemJobs)
+	require.False(t, a.IgnoreSystemJobs)
+
+	out = a.Merge(&DrainConfig{Deadline: time.Hour})
+	require.Equal(t, time.Hour, out.Deadline)
+
+	var empty *DrainConfig
+	require.Equal(t, b, empty.Merge(b))
+	require.Nil(t, empty.Merge(nil))
+}
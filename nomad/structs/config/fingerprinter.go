@@ -0,0 +1,102 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/nomad/helper"
+)
+
+const (
+	// DefaultFingerprinterInterval is the default interval at which an
+	// external fingerprinter is run.
+	DefaultFingerprinterInterval = time.Minute
+
+	// DefaultFingerprinterTimeout is the default time an external
+	// fingerprinter may run before it is killed.
+	DefaultFingerprinterTimeout = 10 * time.Second
+)
+
+// FingerprinterConfig configures an external fingerprinter, a command run
+// periodically by the clients whose output contributes attributes and
+// resources to the node.
+type FingerprinterConfig struct {
+	// Name is the unique name of the fingerprinter. The attributes it
+	// reports are prefixed with it.
+	Name string `mapstructure:"-"`
+
+	// Command is the path of the executable to run.
+	Command string `mapstructure:"command"`
+
+	// Args are the arguments passed to the command.
+	Args []string `mapstructure:"args"`
+
+	// Interval is the interval at which the command is run.
+	Interval time.Duration `mapstructure:"interval"`
+
+	// Timeout is the time the command may run before it is killed.
+	Timeout time.Duration `mapstructure:"timeout"`
+}
+
+// Canonicalize sets the defaults of the fingerprinter.
+func (f *FingerprinterConfig) Canonicalize() {
+	if f.Interval == 0 {
+		f.Interval = DefaultFingerprinterInterval
+	}
+	if f.Timeout == 0 {
+		f.Timeout = DefaultFingerprinterTimeout
+	}
+}
+
+// Validate returns an error if the fingerprinter is invalid. It must be
+// canonicalized first.
+func (f *FingerprinterConfig) Validate() error {
+	if f.Name == "" {
+		return fmt.Errorf("fingerprinter requires a name")
+	}
+	if f.Command == "" {
+		return fmt.Errorf("fingerprinter %q requires a command", f.Name)
+	}
+	if f.Interval < 0 {
+		return fmt.Errorf("fingerprinter %q interval must be positive", f.Name)
+	}
+	if f.Timeout < 0 {
+		return fmt.Errorf("fingerprinter %q timeout must be positive", f.Name)
+	}
+	return nil
+}
+
+// Copy returns a copy of the fingerprinter.
+func (f *FingerprinterConfig) Copy() *FingerprinterConfig {
+	if f == nil {
+		return nil
+	}
+	c := *f
+	c.Args = helper.CopySliceString(f.Args)
+	return &c
+}
+
+// FingerprinterSetMerge merges two sets of fingerprinters. The
+// fingerprinters of the second set replace the fingerprinters of the first
+// set with the same name, the order of the fingerprinters being kept.
+func FingerprinterSetMerge(first, second []*FingerprinterConfig) []*FingerprinterConfig {
+	if len(first) == 0 && len(second) == 0 {
+		return nil
+	}
+
+	out := make([]*FingerprinterConfig, 0, len(first)+len(second))
+	index := make(map[string]int, len(first))
+	for _, f := range first {
+		index[f.Name] = len(out)
+		out = append(out, f.Copy())
+	}
+	for _, f := range second {
+		if i, ok := index[f.Name]; ok {
+			out[i] = f.Copy()
+			continue
+		}
+		index[f.Name] = len(out)
+		out = append(out, f.Copy())
+	}
+	return out
+}
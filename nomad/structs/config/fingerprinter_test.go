@@ -0,0 +1,51 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFingerprinterConfig_Validate(t *testing.T) {
+	t.Parallel()
+
+	f := &FingerprinterConfig{Name: "raid", Command: "/usr/local/bin/raid-health"}
+	f.Canonicalize()
+	require.Equal(t, DefaultFingerprinterInterval, f.Interval)
+	require.Equal(t, DefaultFingerprinterTimeout, f.Timeout)
+	require.NoError(t, f.Validate())
+
+	invalid := []*FingerprinterConfig{
+		{Command: "/usr/local/bin/raid-health"},
+		{Name: "raid"},
+		{Name: "raid", Command: "/usr/local/bin/raid-health", Interval: -time.Second},
+	}
+	for _, f := range invalid {
+		f.Canonicalize()
+		require.Error(t, f.Validate(), "%#v", f)
+	}
+}
+
+func TestFingerprinterSetMerge(t *testing.T) {
+	t.Parallel()
+
+	first := []*FingerprinterConfig{
+		{Name: "a", Command: "a", Args: []string{"-v"}},
+		{Name: "b", Command: "b"},
+	}
+	second := []*FingerprinterConfig{
+		{Name: "c", Command: "c"},
+		{Name: "a", Command: "a2", Interval: time.Second},
+	}
+
+	out := FingerprinterSetMerge(first, second)
+	require.Len(t, out, 3)
+	require.Equal(t, "a2", out[0].Command)
+	require.Equal(t, time.Second, out[0].Interval)
+	require.Equal(t, "b", out[1].Name)
+	require.Equal(t, "c", out[2].Name)
+	require.Equal(t, "a", first[0].Command)
+
+	require.Nil(t, FingerprinterSetMerge(nil, nil))
+}
@@ -0,0 +1,151 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// OPAEnforcementAdvisory, OPAEnforcementSoftMandatory and
+	// OPAEnforcementHardMandatory are the enforcement levels of the OPA
+	// policies, with the same semantics as the Sentinel policies.
+	OPAEnforcementAdvisory      = "advisory"
+	OPAEnforcementSoftMandatory = "soft-mandatory"
+	OPAEnforcementHardMandatory = "hard-mandatory"
+
+	// DefaultOPATimeout is the default timeout of the queries to OPA.
+	DefaultOPATimeout = 5 * time.Second
+)
+
+// OPAConfig is the configuration of the Open Policy Agent the jobs submitted
+// are evaluated against.
+type OPAConfig struct {
+	// Address is the address of the OPA REST API, such as
+	// "http://127.0.0.1:8181".
+	Address string `mapstructure:"address"`
+
+	// Token is the bearer token sent to OPA.
+	Token string `mapstructure:"token" json:"-"`
+
+	// Timeout is the timeout of the queries to OPA.
+	Timeout time.Duration `mapstructure:"timeout"`
+
+	// Policies are the policies the jobs are evaluated against.
+	Policies []*OPAPolicyConfig `mapstructure:"policy"`
+}
+
+// OPAPolicyConfig is a policy the jobs submitted are evaluated against.
+type OPAPolicyConfig struct {
+	// Name is the name of the policy, reported in the errors and warnings.
+	Name string `mapstructure:"-"`
+
+	// Path is the path of the rule to query in the OPA data API, such as
+	// "nomad/jobs/deny". The rule must evaluate to a boolean allowing the
+	// job, or to the list of the messages of the violations of the job.
+	Path string `mapstructure:"path"`
+
+	// EnforcementLevel is either "advisory", "soft-mandatory" or
+	// "hard-mandatory".
+	EnforcementLevel string `mapstructure:"enforcement_level"`
+}
+
+// IsEnabled returns whether the jobs are evaluated against OPA policies.
+func (o *OPAConfig) IsEnabled() bool {
+	return o != nil && o.Address != "" && len(o.Policies) != 0
+}
+
+// Canonicalize sets the defaults of the configuration.
+func (o *OPAConfig) Canonicalize() {
+	if o.Timeout == 0 {
+		o.Timeout = DefaultOPATimeout
+	}
+	for _, p := range o.Policies {
+		if p.EnforcementLevel == "" {
+			p.EnforcementLevel = OPAEnforcementHardMandatory
+		}
+		p.Path = strings.Trim(p.Path, "/")
+	}
+}
+
+// Validate returns an error if the configuration is invalid. It must be
+// canonicalized first.
+func (o *OPAConfig) Validate() error {
+	if u, err := url.Parse(o.Address); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return fmt.Errorf("opa address must be an http or https url")
+	}
+	if o.Timeout < 0 {
+		return fmt.Errorf("opa timeout must be positive")
+	}
+	for _, p := range o.Policies {
+		if p.Path == "" {
+			return fmt.Errorf("opa policy %q requires a path", p.Name)
+		}
+		switch p.EnforcementLevel {
+		case OPAEnforcementAdvisory, OPAEnforcementSoftMandatory, OPAEnforcementHardMandatory:
+		default:
+			return fmt.Errorf("opa policy %q has invalid enforcement level %q", p.Name, p.EnforcementLevel)
+		}
+	}
+	return nil
+}
+
+// Merge merges two OPA configurations together. The policies of the second
+// configuration replace the policies with the same name.
+func (o *OPAConfig) Merge(b *OPAConfig) *OPAConfig {
+	result := o.Copy()
+
+	if b.Address != "" {
+		result.Address = b.Address
+	}
+	if b.Token != "" {
+		result.Token = b.Token
+	}
+	if b.Timeout != 0 {
+		result.Timeout = b.Timeout
+	}
+	for _, p := range b.Policies {
+		replaced := false
+		for i, existing := range result.Policies {
+			if existing.Name == p.Name {
+				result.Policies[i] = p.Copy()
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			result.Policies = append(result.Policies, p.Copy())
+		}
+	}
+
+	return result
+}
+
+// Copy returns a copy of this OPA config.
+func (o *OPAConfig) Copy() *OPAConfig {
+	if o == nil {
+		return nil
+	}
+
+	nc := new(OPAConfig)
+	*nc = *o
+
+	if o.Policies != nil {
+		nc.Policies = make([]*OPAPolicyConfig, len(o.Policies))
+		for i, p := range o.Policies {
+			nc.Policies[i] = p.Copy()
+		}
+	}
+
+	return nc
+}
+
+// Copy returns a copy of the policy.
+func (p *OPAPolicyConfig) Copy() *OPAPolicyConfig {
+	if p == nil {
+		return nil
+	}
+	c := *p
+	return &c
+}
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOPAConfig_Merge(t *testing.T) {
+	c1 := &OPAConfig{
+		Address: "http://127.0.0.1:8181",
+		Timeout: time.Second,
+		Policies: []*OPAPolicyConfig{
+			{Name: "privileged", Path: "nomad/privileged", EnforcementLevel: OPAEnforcementHardMandatory},
+			{Name: "resources", Path: "nomad/resources", EnforcementLevel: OPAEnforcementAdvisory},
+		},
+	}
+
+	c2 := &OPAConfig{
+		Token: "secret",
+		Policies: []*OPAPolicyConfig{
+			{Name: "resources", Path: "nomad/resources", EnforcementLevel: OPAEnforcementSoftMandatory},
+		},
+	}
+
+	e := &OPAConfig{
+		Address: "http://127.0.0.1:8181",
+		Token:   "secret",
+		Timeout: time.Second,
+		Policies: []*OPAPolicyConfig{
+			{Name: "privileged", Path: "nomad/privileged", EnforcementLevel: OPAEnforcementHardMandatory},
+			{Name: "resources", Path: "nomad/resources", EnforcementLevel: OPAEnforcementSoftMandatory},
+		},
+	}
+
+	result := c1.Merge(c2)
+	require.Equal(t, e, result)
+	require.Equal(t, OPAEnforcementAdvisory, c1.Policies[1].EnforcementLevel)
+}
+
+func TestOPAConfig_Validate(t *testing.T) {
+	c := &OPAConfig{
+		Address:  "http://127.0.0.1:8181",
+		Policies: []*OPAPolicyConfig{{Name: "privileged", Path: "/nomad/privileged/"}},
+	}
+	c.Canonicalize()
+	require.True(t, c.IsEnabled())
+	require.Equal(t, DefaultOPATimeout, c.Timeout)
+	require.Equal(t, "nomad/privileged", c.Policies[0].Path)
+	require.Equal(t, OPAEnforcementHardMandatory, c.Policies[0].EnforcementLevel)
+	require.NoError(t, c.Validate())
+
+	c.Policies[0].EnforcementLevel = "mandatory"
+	require.Error(t, c.Validate())
+
+	c.Policies[0].EnforcementLevel = OPAEnforcementAdvisory
+	c.Address = "127.0.0.1:8181"
+	require.Error(t, c.Validate())
+
+	require.False(t, (*OPAConfig)(nil).IsEnabled())
+}
@@ -0,0 +1,195 @@
+package config
+
+import (
+	"time"
+
+	"github.com/hashicorp/nomad/helper"
+)
+
+// SnapshotAgentConfig is the configuration of the snapshot agent, which saves
+// snapshots of the state of the servers to storages periodically.
+type SnapshotAgentConfig struct {
+	// Enabled controls whether the leader saves snapshots.
+	Enabled *bool `mapstructure:"enabled"`
+
+	// Interval is the time between two snapshots.
+	Interval time.Duration `mapstructure:"interval"`
+
+	// Retain is the number of snapshots kept in each storage, the oldest
+	// ones being deleted.
+	Retain int `mapstructure:"retain"`
+
+	// NamePrefix is the prefix of the names of the snapshot files.
+	NamePrefix string `mapstructure:"name_prefix"`
+
+	// LocalStorage saves the snapshots in a directory of the leader.
+	LocalStorage *SnapshotLocalStorage `mapstructure:"local_storage"`
+
+	// S3Storage saves the snapshots in an Amazon S3 bucket.
+	S3Storage *SnapshotS3Storage `mapstructure:"s3_storage"`
+
+	// GCSStorage saves the snapshots in a Google Cloud Storage bucket.
+	GCSStorage *SnapshotGCSStorage `mapstructure:"gcs_storage"`
+
+	// AzureBlobStorage saves the snapshots in an Azure Blob Storage
+	// container.
+	AzureBlobStorage *SnapshotAzureBlobStorage `mapstructure:"azure_blob_storage"`
+}
+
+// SnapshotLocalStorage is the configuration of the local storage of the
+// snapshots.
+type SnapshotLocalStorage struct {
+	// Path is the directory the snapshots are saved in.
+	Path string `mapstructure:"path"`
+}
+
+// SnapshotS3Storage is the configuration of the Amazon S3 storage of the
+// snapshots.
+type SnapshotS3Storage struct {
+	// Bucket is the name of the bucket.
+	Bucket string `mapstructure:"bucket"`
+
+	// KeyPrefix is the prefix of the keys of the snapshots.
+	KeyPrefix string `mapstructure:"key_prefix"`
+
+	// Region is the region of the bucket.
+	Region string `mapstructure:"region"`
+
+	// Endpoint overrides the endpoint of S3, for S3 compatible storages.
+	Endpoint string `mapstructure:"endpoint"`
+
+	// ForcePathStyle addresses the bucket in the path of the requests
+	// rather than in the host name.
+	ForcePathStyle bool `mapstructure:"force_path_style"`
+
+	// AccessKeyID and SecretAccessKey are the credentials to use. The
+	// credentials of the environment or of the instance are used if unset.
+	AccessKeyID     string `mapstructure:"access_key_id"`
+	SecretAccessKey string `mapstructure:"secret_access_key"`
+
+	// ServerSideEncryption enables the AES-256 server-side encryption of
+	// the snapshots.
+	ServerSideEncryption bool `mapstructure:"server_side_encryption"`
+}
+
+// SnapshotGCSStorage is the configuration of the Google Cloud Storage storage
+// of the snapshots.
+type SnapshotGCSStorage struct {
+	// Bucket is the name of the bucket.
+	Bucket string `mapstructure:"bucket"`
+
+	// KeyPrefix is the prefix of the names of the snapshot objects.
+	KeyPrefix string `mapstructure:"key_prefix"`
+
+	// Endpoint overrides the endpoint of the XML API of Cloud Storage.
+	Endpoint string `mapstructure:"endpoint"`
+
+	// AccessKeyID and SecretAccessKey are the HMAC key of the service
+	// account to use.
+	AccessKeyID     string `mapstructure:"access_key_id"`
+	SecretAccessKey string `mapstructure:"secret_access_key"`
+}
+
+// SnapshotAzureBlobStorage is the configuration of the Azure Blob Storage
+// storage of the snapshots.
+type SnapshotAzureBlobStorage struct {
+	// AccountName and AccountKey are the storage account to use and its
+	// shared key.
+	AccountName string `mapstructure:"account_name"`
+	AccountKey  string `mapstructure:"account_key"`
+
+	// ContainerName is the name of the container.
+	ContainerName string `mapstructure:"container_name"`
+
+	// KeyPrefix is the prefix of the names of the snapshot blobs.
+	KeyPrefix string `mapstructure:"key_prefix"`
+
+	// Endpoint overrides the endpoint of the blob service of the account.
+	Endpoint string `mapstructure:"endpoint"`
+}
+
+// DefaultSnapshotAgentConfig returns the canonical defaults for the Nomad
+// `snapshot_agent` configuration.
+func DefaultSnapshotAgentConfig() *SnapshotAgentConfig {
+	return &SnapshotAgentConfig{
+		Interval:   time.Hour,
+		Retain:     30,
+		NamePrefix: "nomad",
+	}
+}
+
+// IsEnabled returns whether the snapshot agent is enabled.
+func (s *SnapshotAgentConfig) IsEnabled() bool {
+	return s != nil && s.Enabled != nil && *s.Enabled
+}
+
+// Merge merges two snapshot agent configurations together.
+func (s *SnapshotAgentConfig) Merge(b *SnapshotAgentConfig) *SnapshotAgentConfig {
+	result := s.Copy()
+
+	if b.Enabled != nil {
+		result.Enabled = helper.BoolToPtr(*b.Enabled)
+	}
+	if b.Interval != 0 {
+		result.Interval = b.Interval
+	}
+	if b.Retain != 0 {
+		result.Retain = b.Retain
+	}
+	if b.NamePrefix != "" {
+		result.NamePrefix = b.NamePrefix
+	}
+
+	// Storages are replaced rather than merged, as their fields depend on
+	// each other
+	if b.LocalStorage != nil {
+		local := *b.LocalStorage
+		result.LocalStorage = &local
+	}
+	if b.S3Storage != nil {
+		s3 := *b.S3Storage
+		result.S3Storage = &s3
+	}
+	if b.GCSStorage != nil {
+		gcs := *b.GCSStorage
+		result.GCSStorage = &gcs
+	}
+	if b.AzureBlobStorage != nil {
+		azure := *b.AzureBlobStorage
+		result.AzureBlobStorage = &azure
+	}
+
+	return result
+}
+
+// Copy returns a copy of this snapshot agent config.
+func (s *SnapshotAgentConfig) Copy() *SnapshotAgentConfig {
+	if s == nil {
+		return nil
+	}
+
+	nc := new(SnapshotAgentConfig)
+	*nc = *s
+
+	if s.Enabled != nil {
+		nc.Enabled = helper.BoolToPtr(*s.Enabled)
+	}
+	if s.LocalStorage != nil {
+		local := *s.LocalStorage
+		nc.LocalStorage = &local
+	}
+	if s.S3Storage != nil {
+		s3 := *s.S3Storage
+		nc.S3Storage = &s3
+	}
+	if s.GCSStorage != nil {
+		gcs := *s.GCSStorage
+		nc.GCSStorage = &gcs
+	}
+	if s.AzureBlobStorage != nil {
+		azure := *s.AzureBlobStorage
+		nc.AzureBlobStorage = &azure
+	}
+
+	return nc
+}
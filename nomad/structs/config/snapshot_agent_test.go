@@ -0,0 +1,53 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/helper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshotAgentConfig_Merge(t *testing.T) {
+	c1 := &SnapshotAgentConfig{
+		Enabled:      helper.BoolToPtr(false),
+		Interval:     time.Hour,
+		Retain:       10,
+		NamePrefix:   "nomad",
+		LocalStorage: &SnapshotLocalStorage{Path: "/tmp/snapshots"},
+		S3Storage:    &SnapshotS3Storage{Bucket: "backups", Region: "us-east-1"},
+	}
+
+	c2 := &SnapshotAgentConfig{
+		Enabled:   helper.BoolToPtr(true),
+		Retain:    5,
+		S3Storage: &SnapshotS3Storage{Bucket: "other"},
+		AzureBlobStorage: &SnapshotAzureBlobStorage{
+			AccountName:   "nomad",
+			ContainerName: "backups",
+		},
+	}
+
+	e := &SnapshotAgentConfig{
+		Enabled:      helper.BoolToPtr(true),
+		Interval:     time.Hour,
+		Retain:       5,
+		NamePrefix:   "nomad",
+		LocalStorage: &SnapshotLocalStorage{Path: "/tmp/snapshots"},
+		S3Storage:    &SnapshotS3Storage{Bucket: "other"},
+		AzureBlobStorage: &SnapshotAzureBlobStorage{
+			AccountName:   "nomad",
+			ContainerName: "backups",
+		},
+	}
+
+	result := c1.Merge(c2)
+	require.Equal(t, e, result)
+	require.True(t, result.IsEnabled())
+
+	// The merged config doesn't share the storages of the inputs
+	result.S3Storage.Bucket = "changed"
+	require.Equal(t, "other", c2.S3Storage.Bucket)
+	result.LocalStorage.Path = "changed"
+	require.Equal(t, "/tmp/snapshots", c1.LocalStorage.Path)
+}
@@ -8,6 +8,8 @@ import (
 	"io"
 	"os"
 	"sync"
+
+	"github.com/hashicorp/nomad/helper"
 )
 
 // TLSConfig provides TLS related configuration
@@ -27,6 +29,11 @@ type TLSConfig struct {
 	// existing clients.
 	VerifyServerHostname bool `mapstructure:"verify_server_hostname"`
 
+	// RegionServerNames overrides the server name verified for the servers of
+	// a region, which defaults to server.<region>.nomad. This allows federating
+	// with regions whose certificates are issued for other names.
+	RegionServerNames map[string]string `mapstructure:"region_server_names"`
+
 	// CAFile is a path to a certificate authority file. This is used with VerifyIncoming
 	// or VerifyOutgoing to verify the TLS connection.
 	CAFile string `mapstructure:"ca_file"`
@@ -156,6 +163,7 @@ func (t *TLSConfig) Copy() *TLSConfig {
 	new.EnableHTTP = t.EnableHTTP
 	new.EnableRPC = t.EnableRPC
 	new.VerifyServerHostname = t.VerifyServerHostname
+	new.RegionServerNames = helper.CopyMapStringString(t.RegionServerNames)
 	new.CAFile = t.CAFile
 	new.CertFile = t.CertFile
 
@@ -204,6 +212,14 @@ func (t *TLSConfig) Merge(b *TLSConfig) *TLSConfig {
 	if b.VerifyServerHostname {
 		result.VerifyServerHostname = true
 	}
+	if len(b.RegionServerNames) != 0 {
+		if result.RegionServerNames == nil {
+			result.RegionServerNames = make(map[string]string, len(b.RegionServerNames))
+		}
+		for region, name := range b.RegionServerNames {
+			result.RegionServerNames[region] = name
+		}
+	}
 	if b.CAFile != "" {
 		result.CAFile = b.CAFile
 	}
@@ -18,6 +18,7 @@ func TestTLSConfig_Merge(t *testing.T) {
 		EnableHTTP:                  true,
 		EnableRPC:                   true,
 		VerifyServerHostname:        true,
+		RegionServerNames:           map[string]string{"europe": "server.eu.example.com"},
 		CAFile:                      "test-ca-file-2",
 		CertFile:                    "test-cert-file-2",
 		RPCUpgradeMode:              true,
@@ -0,0 +1,103 @@
+package structs
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/nomad/lib/cpuset"
+)
+
+// copyCores returns a copy of the given set of cores.
+func copyCores(cores []uint16) []uint16 {
+	if cores == nil {
+		return nil
+	}
+	c := make([]uint16, len(cores))
+	copy(c, cores)
+	return c
+}
+
+// CoresEquals returns true if the two sets of cores are equal.
+func CoresEquals(c1, c2 []uint16) bool {
+	return cpuset.New(c1...).Equals(cpuset.New(c2...))
+}
+
+// CoreAccounter is used to account for the cores dedicated to tasks on a
+// node. Cores may only be dedicated among the reservable cores of the node
+// and a core is dedicated to at most one task.
+type CoreAccounter struct {
+	// Reservable is the set of cores of the node that may be dedicated
+	Reservable cpuset.CPUSet
+
+	// Used is the set of cores dedicated to tasks
+	Used cpuset.CPUSet
+}
+
+// NewCoreAccounter returns a new core accounter for the reservable cores of
+// the node.
+func NewCoreAccounter(n *Node) *CoreAccounter {
+	a := &CoreAccounter{
+		Reservable: cpuset.New(),
+		Used:       cpuset.New(),
+	}
+
+	// COMPAT(0.11): Remove in 0.11
+	if n.NodeResources == nil {
+		return a
+	}
+
+	a.Reservable = cpuset.New(n.NodeResources.Cpu.ReservableCpuCores...)
+	return a
+}
+
+// AddAllocs marks the cores dedicated to the tasks of the allocations as used
+// and returns whether any core is dedicated more than once or is not
+// reservable.
+func (a *CoreAccounter) AddAllocs(allocs []*Allocation) (collision bool) {
+	for _, alloc := range allocs {
+		if alloc.TerminalStatus() || alloc.AllocatedResources == nil {
+			continue
+		}
+
+		for _, tr := range alloc.AllocatedResources.Tasks {
+			if a.AddReserved(tr) {
+				collision = true
+			}
+		}
+	}
+	return collision
+}
+
+// AddReserved marks the cores dedicated to the task as used and returns
+// whether any of them was already used or is not reservable.
+func (a *CoreAccounter) AddReserved(tr *AllocatedTaskResources) (collision bool) {
+	if tr == nil || len(tr.Cpu.ReservedCores) == 0 {
+		return false
+	}
+
+	cores := cpuset.New(tr.Cpu.ReservedCores...)
+	if !cores.IsSubsetOf(a.Reservable) || cores.Intersect(a.Used).Size() != 0 {
+		collision = true
+	}
+	a.Used = a.Used.Union(cores)
+	return collision
+}
+
+// Assign returns n free cores to dedicate to a task. If a NUMA node is given,
+// the cores are chosen among the cores of that NUMA node. The lowest numbered
+// free cores are chosen. If not enough cores are free, an error is returned.
+func (a *CoreAccounter) Assign(n int, numaNode *NodeNumaNode) ([]uint16, error) {
+	free := a.Reservable.Difference(a.Used)
+	if numaNode != nil {
+		numaCores, err := cpuset.Parse(numaNode.Cores)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cores of NUMA node %d: %v", numaNode.ID, err)
+		}
+		free = free.Intersect(numaCores)
+	}
+
+	cores := free.ToSlice()
+	if len(cores) < n {
+		return nil, fmt.Errorf("not enough free reservable cores: %d requested, %d available", n, len(cores))
+	}
+	return cores[:n], nil
+}
@@ -0,0 +1,79 @@
+package structs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func coresAlloc(cores ...uint16) *Allocation {
+	return &Allocation{
+		AllocatedResources: &AllocatedResources{
+			Tasks: map[string]*AllocatedTaskResources{
+				"web": {
+					Cpu: AllocatedCpuResources{CpuShares: 1000, ReservedCores: cores},
+				},
+			},
+		},
+	}
+}
+
+func TestCoreAccounter_Assign(t *testing.T) {
+	require := require.New(t)
+
+	n := mockNumaNode()
+	n.NodeResources.Cpu.ReservableCpuCores = []uint16{1, 2, 3}
+
+	a := NewCoreAccounter(n)
+	require.False(a.AddAllocs([]*Allocation{coresAlloc(2)}))
+
+	// The lowest free reservable cores are assigned
+	cores, err := a.Assign(2, nil)
+	require.NoError(err)
+	require.Equal([]uint16{1, 3}, cores)
+
+	// Only the cores of the NUMA node are assigned
+	cores, err = a.Assign(1, n.NodeResources.NumaNodes[1])
+	require.NoError(err)
+	require.Equal([]uint16{3}, cores)
+
+	_, err = a.Assign(2, n.NodeResources.NumaNodes[1])
+	require.Error(err)
+	require.Contains(err.Error(), "1 available")
+
+	_, err = a.Assign(3, nil)
+	require.Error(err)
+}
+
+func TestCoreAccounter_AddAllocs_Collision(t *testing.T) {
+	require := require.New(t)
+
+	n := MockNode()
+	n.NodeResources.Cpu.ReservableCpuCores = []uint16{2, 3}
+
+	require.False(NewCoreAccounter(n).AddAllocs([]*Allocation{coresAlloc(2), coresAlloc(3)}))
+	require.True(NewCoreAccounter(n).AddAllocs([]*Allocation{coresAlloc(2), coresAlloc(2, 3)}))
+
+	// Cores that are not reservable can not be dedicated
+	require.True(NewCoreAccounter(n).AddAllocs([]*Allocation{coresAlloc(0)}))
+
+	// Terminal allocations do not use their cores
+	terminal := coresAlloc(2)
+	terminal.DesiredStatus = AllocDesiredStatusStop
+	require.False(NewCoreAccounter(n).AddAllocs([]*Allocation{terminal, coresAlloc(2)}))
+}
+
+func TestNodeResources_Copy_ReservableCores(t *testing.T) {
+	require := require.New(t)
+
+	n := MockNode()
+	n.NodeResources.Cpu.TotalCpuCores = 4
+	n.NodeResources.Cpu.ReservableCpuCores = []uint16{2, 3}
+
+	c := n.NodeResources.Copy()
+	require.True(c.Equals(n.NodeResources))
+
+	c.Cpu.ReservableCpuCores[0] = 1
+	require.Equal(uint16(2), n.NodeResources.Cpu.ReservableCpuCores[0])
+	require.False(c.Equals(n.NodeResources))
+}
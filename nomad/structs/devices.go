@@ -17,6 +17,37 @@ type DeviceAccounterInstance struct {
 	// Instances is a mapping of the device IDs to their usage.
 	// Only a value of 0 indicates that the instance is unused.
 	Instances map[string]int
+
+	// Shared is a mapping of the device IDs to the number of their usages
+	// that are time-shared. An instance whose usage is entirely shared may
+	// be assigned to additional shared requests.
+	Shared map[string]int
+}
+
+// Shareable returns whether the instance may be assigned to a shared device
+// request. This is the case if no exclusive usage of the instance exists.
+func (d *DeviceAccounterInstance) Shareable(id string) bool {
+	used, ok := d.Instances[id]
+	return ok && used == d.Shared[id]
+}
+
+// use marks the instance as used and returns whether the usage collides with
+// an existing usage of the instance.
+func (d *DeviceAccounterInstance) use(id string, shared bool) (collision bool) {
+	cur, ok := d.Instances[id]
+	if !ok {
+		return false
+	}
+
+	if shared {
+		collision = cur != d.Shared[id]
+		d.Shared[id]++
+	} else {
+		collision = cur != 0
+	}
+
+	d.Instances[id]++
+	return collision
 }
 
 // NewDeviceAccounter returns a new device accounter. The node is used to
@@ -41,6 +72,7 @@ func NewDeviceAccounter(n *Node) *DeviceAccounter {
 		d.Devices[id] = &DeviceAccounterInstance{
 			Device:    dev,
 			Instances: make(map[string]int, len(dev.Instances)),
+			Shared:    make(map[string]int),
 		}
 		for _, instance := range dev.Instances {
 			// Skip unhealthy devices as they aren't allocatable
@@ -86,13 +118,8 @@ func (d *DeviceAccounter) AddAllocs(allocs []*Allocation) (collision bool) {
 					// map if the device is no longer being fingerprinted, is
 					// unhealthy, etc.
 					if devInst, ok := d.Devices[*devID]; ok {
-						if i, ok := devInst.Instances[instanceID]; ok {
-							// Mark that the device is in use
-							devInst.Instances[instanceID]++
-
-							if i != 0 {
-								collision = true
-							}
+						if devInst.use(instanceID, device.Shared) {
+							collision = true
 						}
 					}
 				}
@@ -114,17 +141,9 @@ func (d *DeviceAccounter) AddReserved(res *AllocatedDeviceResource) (collision b
 
 	// For each reserved instance, mark it as used
 	for _, id := range res.DeviceIDs {
-		cur, ok := devInst.Instances[id]
-		if !ok {
-			continue
-		}
-
-		// It has already been used, so mark that there is a collision
-		if cur != 0 {
+		if devInst.use(id, res.Shared) {
 			collision = true
 		}
-
-		devInst.Instances[id]++
 	}
 
 	return
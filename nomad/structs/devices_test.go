@@ -214,3 +214,36 @@ func TestDeviceAccounter_AddReserved_Collision(t *testing.T) {
 	res.DeviceIDs = []string{nvidiaDev0ID}
 	require.True(d.AddReserved(res))
 }
+
+// Test that shared devices only collide with exclusive usage
+func TestDeviceAccounter_AddAllocs_Shared(t *testing.T) {
+	require := require.New(t)
+	n := devNode()
+	d := NewDeviceAccounter(n)
+	require.NotNil(d)
+
+	nvidiaDev0ID := n.NodeResources.Devices[0].Instances[0].ID
+
+	// Create two allocations sharing the same device
+	a1, a2 := nvidiaAlloc(), nvidiaAlloc()
+	for _, a := range []*Allocation{a1, a2} {
+		a.AllocatedResources.Tasks["web"].Devices[0].DeviceIDs = []string{nvidiaDev0ID}
+		a.AllocatedResources.Tasks["web"].Devices[0].Shared = true
+	}
+	require.False(d.AddAllocs([]*Allocation{a1, a2}))
+
+	nvidiaDevice := d.Devices[*n.NodeResources.Devices[0].ID()]
+	require.Equal(2, nvidiaDevice.Instances[nvidiaDev0ID])
+	require.True(nvidiaDevice.Shareable(nvidiaDev0ID))
+
+	// Reserving the device exclusively collides
+	res := nvidiaAllocatedDevice()
+	res.DeviceIDs = []string{nvidiaDev0ID}
+	require.True(d.AddReserved(res))
+	require.False(nvidiaDevice.Shareable(nvidiaDev0ID))
+
+	// Sharing a device used exclusively collides
+	d = NewDeviceAccounter(n)
+	require.False(d.AddReserved(res))
+	require.True(d.AddAllocs([]*Allocation{a1}))
+}
@@ -116,6 +116,11 @@ func (j *Job) Diff(other *Job, contextual bool) (*JobDiff, error) {
 		diff.Objects = append(diff.Objects, affinitiesDiff...)
 	}
 
+	// Spreads diff
+	if spreadDiffs := spreadDiffs(j.Spreads, other.Spreads, contextual); spreadDiffs != nil {
+		diff.Objects = append(diff.Objects, spreadDiffs...)
+	}
+
 	// Task groups diff
 	tgs, err := taskGroupDiffs(j.TaskGroups, other.TaskGroups, contextual)
 	if err != nil {
@@ -133,6 +138,16 @@ func (j *Job) Diff(other *Job, contextual bool) (*JobDiff, error) {
 		diff.Objects = append(diff.Objects, cDiff)
 	}
 
+	// Multiregion diff
+	if mDiff := multiregionDiff(j.Multiregion, other.Multiregion, contextual); mDiff != nil {
+		diff.Objects = append(diff.Objects, mDiff)
+	}
+
+	// GC diff
+	if gcDiff := primitiveObjectDiff(j.GC, other.GC, nil, "GC", contextual); gcDiff != nil {
+		diff.Objects = append(diff.Objects, gcDiff)
+	}
+
 	// Check to see if there is a diff. We don't use reflect because we are
 	// filtering quite a few fields that will change on each diff.
 	if diff.Type == DiffTypeNone {
@@ -225,6 +240,14 @@ func (tg *TaskGroup) Diff(other *TaskGroup, contextual bool) (*TaskGroupDiff, er
 		newPrimitiveFlat = flatmap.Flatten(other, filter, true)
 	}
 
+	// MaxClientDisconnect is a pointer and is skipped by Flatten
+	if oldPrimitiveFlat != nil && tg.MaxClientDisconnect != nil {
+		oldPrimitiveFlat["MaxClientDisconnect"] = fmt.Sprintf("%d", *tg.MaxClientDisconnect)
+	}
+	if newPrimitiveFlat != nil && other.MaxClientDisconnect != nil {
+		newPrimitiveFlat["MaxClientDisconnect"] = fmt.Sprintf("%d", *other.MaxClientDisconnect)
+	}
+
 	// Diff the primitive fields.
 	diff.Fields = fieldDiffs(oldPrimitiveFlat, newPrimitiveFlat, false)
 
@@ -250,6 +273,16 @@ func (tg *TaskGroup) Diff(other *TaskGroup, contextual bool) (*TaskGroupDiff, er
 		diff.Objects = append(diff.Objects, affinitiesDiff...)
 	}
 
+	// Spreads diff
+	if spreadDiffs := spreadDiffs(tg.Spreads, other.Spreads, contextual); spreadDiffs != nil {
+		diff.Objects = append(diff.Objects, spreadDiffs...)
+	}
+
+	// Networks diff
+	if nDiffs := networkResourceDiffs(tg.Networks, other.Networks, contextual); nDiffs != nil {
+		diff.Objects = append(diff.Objects, nDiffs...)
+	}
+
 	// Restart policy diff
 	rDiff := primitiveObjectDiff(tg.RestartPolicy, other.RestartPolicy, nil, "RestartPolicy", contextual)
 	if rDiff != nil {
@@ -475,6 +508,17 @@ func (t *Task) Diff(other *Task, contextual bool) (*TaskDiff, error) {
 		diff.Objects = append(diff.Objects, tmplDiffs...)
 	}
 
+	// Actions diff
+	actionDiffs := primitiveObjectSetDiff(
+		interfaceSlice(t.Actions),
+		interfaceSlice(other.Actions),
+		nil,
+		"Action",
+		contextual)
+	if actionDiffs != nil {
+		diff.Objects = append(diff.Objects, actionDiffs...)
+	}
+
 	return diff, nil
 }
 
@@ -616,6 +660,82 @@ func serviceDiffs(old, new []*Service, contextual bool) []*ObjectDiff {
 	return diffs
 }
 
+// spreadDiff returns the diff of two spread objects. If contextual diff is
+// enabled, all fields will be returned, even if no diff occurred.
+func spreadDiff(old, new *Spread, contextual bool) *ObjectDiff {
+	diff := &ObjectDiff{Type: DiffTypeNone, Name: "Spread"}
+	var oldPrimitiveFlat, newPrimitiveFlat map[string]string
+
+	if reflect.DeepEqual(old, new) {
+		return nil
+	} else if old == nil {
+		old = &Spread{}
+		diff.Type = DiffTypeAdded
+		newPrimitiveFlat = flatmap.Flatten(new, []string{"str"}, true)
+	} else if new == nil {
+		new = &Spread{}
+		diff.Type = DiffTypeDeleted
+		oldPrimitiveFlat = flatmap.Flatten(old, []string{"str"}, true)
+	} else {
+		diff.Type = DiffTypeEdited
+		oldPrimitiveFlat = flatmap.Flatten(old, []string{"str"}, true)
+		newPrimitiveFlat = flatmap.Flatten(new, []string{"str"}, true)
+	}
+
+	// Diff the primitive fields.
+	diff.Fields = fieldDiffs(oldPrimitiveFlat, newPrimitiveFlat, contextual)
+
+	// Spread targets diff
+	targetDiffs := primitiveObjectSetDiff(
+		interfaceSlice(old.SpreadTarget),
+		interfaceSlice(new.SpreadTarget),
+		[]string{"str"},
+		"SpreadTarget",
+		contextual)
+	if targetDiffs != nil {
+		diff.Objects = append(diff.Objects, targetDiffs...)
+	}
+
+	return diff
+}
+
+// spreadDiffs diffs a set of spreads. If contextual diff is enabled, unchanged
+// fields within the spreads will be returned.
+func spreadDiffs(old, new []*Spread, contextual bool) []*ObjectDiff {
+	makeSet := func(spreads []*Spread) map[string]*Spread {
+		spreadMap := make(map[string]*Spread, len(spreads))
+		for _, spread := range spreads {
+			hash, err := hashstructure.Hash(spread, nil)
+			if err != nil {
+				panic(err)
+			}
+			spreadMap[fmt.Sprintf("%d", hash)] = spread
+		}
+
+		return spreadMap
+	}
+
+	oldSet := makeSet(old)
+	newSet := makeSet(new)
+
+	var diffs []*ObjectDiff
+	for k, v := range oldSet {
+		// Deleted
+		if _, ok := newSet[k]; !ok {
+			diffs = append(diffs, spreadDiff(v, nil, contextual))
+		}
+	}
+	for k, v := range newSet {
+		// Added
+		if _, ok := oldSet[k]; !ok {
+			diffs = append(diffs, spreadDiff(nil, v, contextual))
+		}
+	}
+
+	sort.Sort(ObjectDiffs(diffs))
+	return diffs
+}
+
 // serviceCheckDiff returns the diff of two service check objects. If contextual
 // diff is enabled, all fields will be returned, even if no diff occurred.
 func serviceCheckDiff(old, new *ServiceCheck, contextual bool) *ObjectDiff {
@@ -810,6 +930,40 @@ func parameterizedJobDiff(old, new *ParameterizedJobConfig, contextual bool) *Ob
 	return diff
 }
 
+// multiregionDiff returns the diff of two multiregion objects. If contextual
+// diff is enabled, all fields will be returned, even if no diff occurred.
+func multiregionDiff(old, new *Multiregion, contextual bool) *ObjectDiff {
+	diff := &ObjectDiff{Type: DiffTypeEdited, Name: "Multiregion"}
+
+	if reflect.DeepEqual(old, new) {
+		return nil
+	} else if old == nil {
+		old = &Multiregion{}
+		diff.Type = DiffTypeAdded
+	} else if new == nil {
+		new = &Multiregion{}
+		diff.Type = DiffTypeDeleted
+	}
+
+	// Strategy diff
+	if sDiff := primitiveObjectDiff(old.Strategy, new.Strategy, nil, "Strategy", contextual); sDiff != nil {
+		diff.Objects = append(diff.Objects, sDiff)
+	}
+
+	// Regions diff
+	oldRegions := make([]interface{}, len(old.Regions))
+	for i, r := range old.Regions {
+		oldRegions[i] = r
+	}
+	newRegions := make([]interface{}, len(new.Regions))
+	for i, r := range new.Regions {
+		newRegions[i] = r
+	}
+	diff.Objects = append(diff.Objects, primitiveObjectSetDiff(oldRegions, newRegions, nil, "Region", contextual)...)
+
+	return diff
+}
+
 // Diff returns a diff of two resource objects. If contextual diff is enabled,
 // non-changed fields will still be returned.
 func (r *Resources) Diff(other *Resources, contextual bool) *ObjectDiff {
@@ -845,6 +999,24 @@ func (r *Resources) Diff(other *Resources, contextual bool) *ObjectDiff {
 		diff.Objects = append(diff.Objects, nDiffs...)
 	}
 
+	// NUMA diff
+	if nDiff := primitiveObjectDiff(r.NUMA, other.NUMA, nil, "NUMA", contextual); nDiff != nil {
+		diff.Objects = append(diff.Objects, nDiff)
+	}
+
+	// I/O limits diff
+	oldLimits := make([]interface{}, len(r.IOLimits))
+	for i, l := range r.IOLimits {
+		oldLimits[i] = l
+	}
+	newLimits := make([]interface{}, len(other.IOLimits))
+	for i, l := range other.IOLimits {
+		newLimits[i] = l
+	}
+	if lDiffs := primitiveObjectSetDiff(oldLimits, newLimits, nil, "IOLimit", contextual); lDiffs != nil {
+		diff.Objects = append(diff.Objects, lDiffs...)
+	}
+
 	return diff
 }
 
@@ -943,10 +1115,11 @@ func portDiffs(old, new []Port, dynamic bool, contextual bool) []*ObjectDiff {
 	oldPorts := makeSet(old)
 	newPorts := makeSet(new)
 
-	var filter []string
+	// The host IP of ports is set by the scheduler
+	filter := []string{"HostIP"}
 	name := "Static Port"
 	if dynamic {
-		filter = []string{"Value"}
+		filter = append(filter, "Value")
 		name = "Dynamic Port"
 	}
 
@@ -4,6 +4,8 @@ import (
 	"reflect"
 	"testing"
 	"time"
+
+	"github.com/hashicorp/nomad/helper"
 )
 
 func TestJobDiff(t *testing.T) {
@@ -753,6 +755,67 @@ func TestJobDiff(t *testing.T) {
 				},
 			},
 		},
+		{
+			// Spread added
+			Old: &Job{},
+			New: &Job{
+				Spreads: []*Spread{
+					{
+						Attribute: "${node.datacenter}",
+						Weight:    50,
+						SpreadTarget: []*SpreadTarget{
+							{
+								Value:   "dc1",
+								Percent: 70,
+							},
+						},
+					},
+				},
+			},
+			Expected: &JobDiff{
+				Type: DiffTypeEdited,
+				Objects: []*ObjectDiff{
+					{
+						Type: DiffTypeAdded,
+						Name: "Spread",
+						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeAdded,
+								Name: "Attribute",
+								Old:  "",
+								New:  "${node.datacenter}",
+							},
+							{
+								Type: DiffTypeAdded,
+								Name: "Weight",
+								Old:  "",
+								New:  "50",
+							},
+						},
+						Objects: []*ObjectDiff{
+							{
+								Type: DiffTypeAdded,
+								Name: "SpreadTarget",
+								Fields: []*FieldDiff{
+									{
+										Type: DiffTypeAdded,
+										Name: "Percent",
+										Old:  "",
+										New:  "70",
+									},
+									{
+										Type: DiffTypeAdded,
+										Name: "Value",
+										Old:  "",
+										New:  "dc1",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			// Affinities edited
 			Old: &Job{
@@ -1287,6 +1350,28 @@ func TestTaskGroupDiff(t *testing.T) {
 				},
 			},
 		},
+		{
+			// MaxClientDisconnect added
+			Old: &TaskGroup{
+				Name: "foo",
+			},
+			New: &TaskGroup{
+				Name:                "foo",
+				MaxClientDisconnect: helper.TimeToPtr(time.Hour),
+			},
+			Expected: &TaskGroupDiff{
+				Type: DiffTypeEdited,
+				Name: "foo",
+				Fields: []*FieldDiff{
+					{
+						Type: DiffTypeAdded,
+						Name: "MaxClientDisconnect",
+						Old:  "",
+						New:  "3600000000000",
+					},
+				},
+			},
+		},
 		{
 			// Map diff
 			Old: &TaskGroup{
@@ -1407,6 +1492,41 @@ func TestTaskGroupDiff(t *testing.T) {
 				},
 			},
 		},
+		{
+			// Spread deleted
+			Old: &TaskGroup{
+				Spreads: []*Spread{
+					{
+						Attribute: "${meta.rack}",
+						Weight:    100,
+					},
+				},
+			},
+			New: &TaskGroup{},
+			Expected: &TaskGroupDiff{
+				Type: DiffTypeEdited,
+				Objects: []*ObjectDiff{
+					{
+						Type: DiffTypeDeleted,
+						Name: "Spread",
+						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeDeleted,
+								Name: "Attribute",
+								Old:  "${meta.rack}",
+								New:  "",
+							},
+							{
+								Type: DiffTypeDeleted,
+								Name: "Weight",
+								Old:  "100",
+								New:  "",
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			// Affinities edited
 			Old: &TaskGroup{
@@ -1977,6 +2097,12 @@ func TestTaskGroupDiff(t *testing.T) {
 						Type: DiffTypeDeleted,
 						Name: "Update",
 						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeDeleted,
+								Name: "AutoPromote",
+								Old:  "false",
+								New:  "",
+							},
 							{
 								Type: DiffTypeDeleted,
 								Name: "AutoRevert",
@@ -2001,6 +2127,12 @@ func TestTaskGroupDiff(t *testing.T) {
 								Old:  "0",
 								New:  "",
 							},
+							{
+								Type: DiffTypeDeleted,
+								Name: "MaxParallelPercent",
+								Old:  "0",
+								New:  "",
+							},
 							{
 								Type: DiffTypeDeleted,
 								Name: "MinHealthyTime",
@@ -2033,6 +2165,12 @@ func TestTaskGroupDiff(t *testing.T) {
 						Type: DiffTypeAdded,
 						Name: "Update",
 						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeAdded,
+								Name: "AutoPromote",
+								Old:  "",
+								New:  "false",
+							},
 							{
 								Type: DiffTypeAdded,
 								Name: "AutoRevert",
@@ -2057,6 +2195,12 @@ func TestTaskGroupDiff(t *testing.T) {
 								Old:  "",
 								New:  "0",
 							},
+							{
+								Type: DiffTypeAdded,
+								Name: "MaxParallelPercent",
+								Old:  "",
+								New:  "0",
+							},
 							{
 								Type: DiffTypeAdded,
 								Name: "MinHealthyTime",
@@ -2184,6 +2328,12 @@ func TestTaskGroupDiff(t *testing.T) {
 						Type: DiffTypeEdited,
 						Name: "Update",
 						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeNone,
+								Name: "AutoPromote",
+								Old:  "false",
+								New:  "false",
+							},
 							{
 								Type: DiffTypeNone,
 								Name: "AutoRevert",
@@ -2214,6 +2364,12 @@ func TestTaskGroupDiff(t *testing.T) {
 								Old:  "5",
 								New:  "7",
 							},
+							{
+								Type: DiffTypeNone,
+								Name: "MaxParallelPercent",
+								Old:  "0",
+								New:  "0",
+							},
 							{
 								Type: DiffTypeNone,
 								Name: "MinHealthyTime",
@@ -2446,6 +2602,12 @@ func TestTaskGroupDiff(t *testing.T) {
 						Type: DiffTypeAdded,
 						Name: "bam",
 						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeAdded,
+								Name: "Checkpoint",
+								Old:  "",
+								New:  "false",
+							},
 							{
 								Type: DiffTypeAdded,
 								Name: "Driver",
@@ -2492,6 +2654,12 @@ func TestTaskGroupDiff(t *testing.T) {
 						Type: DiffTypeDeleted,
 						Name: "foo",
 						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeDeleted,
+								Name: "Checkpoint",
+								Old:  "false",
+								New:  "",
+							},
 							{
 								Type: DiffTypeDeleted,
 								Name: "Driver",
@@ -3225,6 +3393,80 @@ func TestTaskDiff(t *testing.T) {
 				},
 			},
 		},
+		{
+			Name: "Resources edited (io)",
+			Old: &Task{
+				Resources: &Resources{
+					IOWeight: 100,
+				},
+			},
+			New: &Task{
+				Resources: &Resources{
+					IOWeight: 200,
+					IOLimits: []*IOLimit{
+						{
+							Device:  "/dev/sda",
+							ReadBps: 1024,
+						},
+					},
+				},
+			},
+			Expected: &TaskDiff{
+				Type: DiffTypeEdited,
+				Objects: []*ObjectDiff{
+					{
+						Type: DiffTypeEdited,
+						Name: "Resources",
+						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeEdited,
+								Name: "IOWeight",
+								Old:  "100",
+								New:  "200",
+							},
+						},
+						Objects: []*ObjectDiff{
+							{
+								Type: DiffTypeAdded,
+								Name: "IOLimit",
+								Fields: []*FieldDiff{
+									{
+										Type: DiffTypeAdded,
+										Name: "Device",
+										Old:  "",
+										New:  "/dev/sda",
+									},
+									{
+										Type: DiffTypeAdded,
+										Name: "ReadBps",
+										Old:  "",
+										New:  "1024",
+									},
+									{
+										Type: DiffTypeAdded,
+										Name: "ReadIOps",
+										Old:  "",
+										New:  "0",
+									},
+									{
+										Type: DiffTypeAdded,
+										Name: "WriteBps",
+										Old:  "",
+										New:  "0",
+									},
+									{
+										Type: DiffTypeAdded,
+										Name: "WriteIOps",
+										Old:  "",
+										New:  "0",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			Name:       "Resources edited (no networks) with context",
 			Contextual: true,
@@ -3257,6 +3499,24 @@ func TestTaskDiff(t *testing.T) {
 								Old:  "100",
 								New:  "200",
 							},
+							{
+								Type: DiffTypeNone,
+								Name: "CPUCFSPeriod",
+								Old:  "0",
+								New:  "0",
+							},
+							{
+								Type: DiffTypeNone,
+								Name: "CPUHardLimit",
+								Old:  "false",
+								New:  "false",
+							},
+							{
+								Type: DiffTypeNone,
+								Name: "Cores",
+								Old:  "0",
+								New:  "0",
+							},
 							{
 								Type: DiffTypeEdited,
 								Name: "DiskMB",
@@ -3269,6 +3529,12 @@ func TestTaskDiff(t *testing.T) {
 								Old:  "100",
 								New:  "100",
 							},
+							{
+								Type: DiffTypeNone,
+								Name: "IOWeight",
+								Old:  "0",
+								New:  "0",
+							},
 							{
 								Type: DiffTypeNone,
 								Name: "MemoryMB",
@@ -3357,6 +3623,12 @@ func TestTaskDiff(t *testing.T) {
 												Old:  "",
 												New:  "foo",
 											},
+											{
+												Type: DiffTypeAdded,
+												Name: "To",
+												Old:  "",
+												New:  "0",
+											},
 											{
 												Type: DiffTypeAdded,
 												Name: "Value",
@@ -3375,6 +3647,12 @@ func TestTaskDiff(t *testing.T) {
 												Old:  "",
 												New:  "baz",
 											},
+											{
+												Type: DiffTypeAdded,
+												Name: "To",
+												Old:  "",
+												New:  "0",
+											},
 										},
 									},
 								},
@@ -3401,6 +3679,12 @@ func TestTaskDiff(t *testing.T) {
 												Old:  "foo",
 												New:  "",
 											},
+											{
+												Type: DiffTypeDeleted,
+												Name: "To",
+												Old:  "0",
+												New:  "",
+											},
 											{
 												Type: DiffTypeDeleted,
 												Name: "Value",
@@ -3419,6 +3703,12 @@ func TestTaskDiff(t *testing.T) {
 												Old:  "bar",
 												New:  "",
 											},
+											{
+												Type: DiffTypeDeleted,
+												Name: "To",
+												Old:  "0",
+												New:  "",
+											},
 										},
 									},
 								},
@@ -3501,6 +3791,12 @@ func TestTaskDiff(t *testing.T) {
 										Old:  "",
 										New:  "bam",
 									},
+									{
+										Type: DiffTypeAdded,
+										Name: "Shared",
+										Old:  "",
+										New:  "false",
+									},
 								},
 							},
 							{
@@ -3519,6 +3815,12 @@ func TestTaskDiff(t *testing.T) {
 										Old:  "baz",
 										New:  "",
 									},
+									{
+										Type: DiffTypeDeleted,
+										Name: "Shared",
+										Old:  "false",
+										New:  "",
+									},
 								},
 							},
 						},
@@ -3586,6 +3888,24 @@ func TestTaskDiff(t *testing.T) {
 								Old:  "100",
 								New:  "100",
 							},
+							{
+								Type: DiffTypeNone,
+								Name: "CPUCFSPeriod",
+								Old:  "0",
+								New:  "0",
+							},
+							{
+								Type: DiffTypeNone,
+								Name: "CPUHardLimit",
+								Old:  "false",
+								New:  "false",
+							},
+							{
+								Type: DiffTypeNone,
+								Name: "Cores",
+								Old:  "0",
+								New:  "0",
+							},
 							{
 								Type: DiffTypeNone,
 								Name: "DiskMB",
@@ -3598,6 +3918,12 @@ func TestTaskDiff(t *testing.T) {
 								Old:  "100",
 								New:  "100",
 							},
+							{
+								Type: DiffTypeNone,
+								Name: "IOWeight",
+								Old:  "0",
+								New:  "0",
+							},
 							{
 								Type: DiffTypeNone,
 								Name: "MemoryMB",
@@ -3622,6 +3948,12 @@ func TestTaskDiff(t *testing.T) {
 										Old:  "bar",
 										New:  "bar",
 									},
+									{
+										Type: DiffTypeNone,
+										Name: "Shared",
+										Old:  "false",
+										New:  "false",
+									},
 								},
 							},
 							{
@@ -3640,6 +3972,12 @@ func TestTaskDiff(t *testing.T) {
 										Old:  "",
 										New:  "bam",
 									},
+									{
+										Type: DiffTypeAdded,
+										Name: "Shared",
+										Old:  "",
+										New:  "false",
+									},
 								},
 							},
 							{
@@ -3658,6 +3996,12 @@ func TestTaskDiff(t *testing.T) {
 										Old:  "baz",
 										New:  "",
 									},
+									{
+										Type: DiffTypeDeleted,
+										Name: "Shared",
+										Old:  "false",
+										New:  "",
+									},
 								},
 							},
 						},
@@ -3857,12 +4201,30 @@ func TestTaskDiff(t *testing.T) {
 								Old:  "2",
 								New:  "2",
 							},
+							{
+								Type: DiffTypeNone,
+								Name: "boom.HostIP",
+								Old:  "",
+								New:  "",
+							},
+							{
+								Type: DiffTypeNone,
+								Name: "boom.HostNetwork",
+								Old:  "",
+								New:  "",
+							},
 							{
 								Type: DiffTypeNone,
 								Name: "boom.Label",
 								Old:  "boom_port",
 								New:  "boom_port",
 							},
+							{
+								Type: DiffTypeNone,
+								Name: "boom.To",
+								Old:  "0",
+								New:  "0",
+							},
 							{
 								Type: DiffTypeNone,
 								Name: "boom.Value",
@@ -4012,6 +4374,12 @@ func TestTaskDiff(t *testing.T) {
 								Old:  "foo",
 								New:  "bar",
 							},
+							{
+								Type: DiffTypeNone,
+								Name: "Provider",
+								Old:  "",
+								New:  "",
+							},
 						},
 					},
 				},
@@ -4105,6 +4473,10 @@ func TestTaskDiff(t *testing.T) {
 								Type: DiffTypeNone,
 								Name: "PortLabel",
 							},
+							{
+								Type: DiffTypeNone,
+								Name: "Provider",
+							},
 						},
 					},
 				},
@@ -4443,6 +4815,12 @@ func TestTaskDiff(t *testing.T) {
 								Old:  "",
 								New:  "",
 							},
+							{
+								Type: DiffTypeNone,
+								Name: "Provider",
+								Old:  "",
+								New:  "",
+							},
 						},
 						Objects: []*ObjectDiff{
 							{
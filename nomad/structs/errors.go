@@ -23,6 +23,8 @@ const (
 	ErrUnknownJobPrefix        = "Unknown job"
 	ErrUnknownEvaluationPrefix = "Unknown evaluation"
 	ErrUnknownDeploymentPrefix = "Unknown deployment"
+	ErrCASConflictPrefix       = "Check-and-set conflict"
+	ErrInvalidFilterPrefix     = "Invalid filter"
 )
 
 var (
@@ -99,6 +101,18 @@ func NewErrUnknownDeployment(deploymentID string) error {
 	return fmt.Errorf("%s %q", ErrUnknownDeploymentPrefix, deploymentID)
 }
 
+// NewErrInvalidFilter returns a new error caused by the filter expression of
+// a list query failing to parse or to evaluate.
+func NewErrInvalidFilter(err error) error {
+	return fmt.Errorf("%s: %v", ErrInvalidFilterPrefix, err)
+}
+
+// IsErrInvalidFilter returns whether the error is due to an invalid filter
+// expression.
+func IsErrInvalidFilter(err error) bool {
+	return err != nil && strings.Contains(err.Error(), ErrInvalidFilterPrefix)
+}
+
 // IsErrUnknownAllocation returns whether the error is due to an unknown
 // allocation.
 func IsErrUnknownAllocation(err error) bool {
@@ -0,0 +1,96 @@
+package structs
+
+// Topic is the topic of cluster events, such as Job or Node.
+type Topic string
+
+const (
+	TopicJob        Topic = "Job"
+	TopicAllocation Topic = "Allocation"
+	TopicDeployment Topic = "Deployment"
+	TopicEvaluation Topic = "Evaluation"
+	TopicNode       Topic = "Node"
+
+	// TopicAll matches the events of all topics.
+	TopicAll Topic = "*"
+)
+
+const (
+	TypeJobRegistered          = "JobRegistered"
+	TypeJobDeregistered        = "JobDeregistered"
+	TypeAllocationUpdated      = "AllocationUpdated"
+	TypeDeploymentStatusUpdate = "DeploymentStatusUpdate"
+	TypeDeploymentPromotion    = "DeploymentPromotion"
+	TypeEvaluationUpdated      = "EvaluationUpdated"
+	TypeNodeRegistration       = "NodeRegistration"
+	TypeNodeDeregistration     = "NodeDeregistration"
+	TypeNodeStatusUpdate       = "NodeStatusUpdate"
+	TypeNodeDrain              = "NodeDrain"
+)
+
+// Event is a change of the state of the cluster, emitted once the change is
+// applied to the state store.
+type Event struct {
+	// Topic is the topic of the event, such as Job.
+	Topic Topic
+
+	// Type is the type of the change, such as JobRegistered.
+	Type string
+
+	// Key is the ID of the object the event is about.
+	Key string
+
+	// Namespace is the namespace of the object, empty for objects such as
+	// nodes that aren't namespaced.
+	Namespace string
+
+	// FilterKeys are the other keys the event can be filtered on, such as
+	// the job ID of an allocation.
+	FilterKeys []string
+
+	// Index is the Raft index of the change.
+	Index uint64
+
+	// Payload holds the object the event is about, keyed by its kind.
+	Payload map[string]interface{}
+}
+
+// Events is the batch of events emitted by the change applied at Index.
+type Events struct {
+	Index  uint64
+	Events []*Event
+}
+
+// EventStreamRequest is used to stream the cluster events.
+type EventStreamRequest struct {
+	// Topics maps the topics to stream to the keys of the events to stream
+	// for each. The TopicAll topic and the "*" key match everything.
+	Topics map[Topic][]string
+
+	// Index is the index to resume the stream after. Only the events with a
+	// greater index are streamed.
+	Index uint64
+
+	QueryOptions
+}
+
+// Matches returns whether the event matches the topics of the request.
+func (r *EventStreamRequest) Matches(event *Event) bool {
+	keys, ok := r.Topics[event.Topic]
+	if !ok {
+		if keys, ok = r.Topics[TopicAll]; !ok {
+			return false
+		}
+	}
+
+	for _, key := range keys {
+		if key == "*" || key == event.Key {
+			return true
+		}
+		for _, fk := range event.FilterKeys {
+			if key == fk {
+				return true
+			}
+		}
+	}
+	return false
+}
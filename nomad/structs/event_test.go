@@ -0,0 +1,37 @@
+package structs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventStreamRequest_Matches(t *testing.T) {
+	event := &Event{
+		Topic:      TopicAllocation,
+		Key:        "alloc",
+		FilterKeys: []string{"job"},
+	}
+
+	cases := []struct {
+		name   string
+		topics map[Topic][]string
+		match  bool
+	}{
+		{"no topics", nil, false},
+		{"other topic", map[Topic][]string{TopicJob: {"*"}}, false},
+		{"all topics", map[Topic][]string{TopicAll: {"*"}}, true},
+		{"all keys", map[Topic][]string{TopicAllocation: {"*"}}, true},
+		{"key", map[Topic][]string{TopicAllocation: {"alloc"}}, true},
+		{"filter key", map[Topic][]string{TopicAllocation: {"job"}}, true},
+		{"other key", map[Topic][]string{TopicAllocation: {"other"}}, false},
+		{"topic over all", map[Topic][]string{TopicAllocation: {"other"}, TopicAll: {"*"}}, false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req := &EventStreamRequest{Topics: c.topics}
+			require.Equal(t, c.match, req.Matches(event))
+		})
+	}
+}
@@ -108,7 +108,7 @@ func TestAllocsFit_PortsOvercommitted_Old(t *testing.T) {
 						Device:        "eth0",
 						IP:            "10.0.0.1",
 						MBits:         50,
-						ReservedPorts: []Port{{"main", 8000}},
+						ReservedPorts: []Port{{Label: "main", Value: 8000}},
 					},
 				},
 			},
@@ -162,7 +162,7 @@ func TestAllocsFit_Old(t *testing.T) {
 					Device:        "eth0",
 					IP:            "10.0.0.1",
 					MBits:         50,
-					ReservedPorts: []Port{{"main", 80}},
+					ReservedPorts: []Port{{Label: "main", Value: 80}},
 				},
 			},
 		},
@@ -179,7 +179,7 @@ func TestAllocsFit_Old(t *testing.T) {
 					Device:        "eth0",
 					IP:            "10.0.0.1",
 					MBits:         50,
-					ReservedPorts: []Port{{"main", 8000}},
+					ReservedPorts: []Port{{Label: "main", Value: 8000}},
 				},
 			},
 		},
@@ -232,7 +232,7 @@ func TestAllocsFit_TerminalAlloc_Old(t *testing.T) {
 					Device:        "eth0",
 					IP:            "10.0.0.1",
 					MBits:         50,
-					ReservedPorts: []Port{{"main", 80}},
+					ReservedPorts: []Port{{Label: "main", Value: 80}},
 				},
 			},
 		},
@@ -249,7 +249,7 @@ func TestAllocsFit_TerminalAlloc_Old(t *testing.T) {
 					Device:        "eth0",
 					IP:            "10.0.0.1",
 					MBits:         50,
-					ReservedPorts: []Port{{"main", 8000}},
+					ReservedPorts: []Port{{Label: "main", Value: 8000}},
 				},
 			},
 		},
@@ -329,7 +329,7 @@ func TestAllocsFit(t *testing.T) {
 							Device:        "eth0",
 							IP:            "10.0.0.1",
 							MBits:         50,
-							ReservedPorts: []Port{{"main", 8000}},
+							ReservedPorts: []Port{{Label: "main", Value: 8000}},
 						},
 					},
 				},
@@ -413,7 +413,7 @@ func TestAllocsFit_TerminalAlloc(t *testing.T) {
 							Device:        "eth0",
 							IP:            "10.0.0.1",
 							MBits:         50,
-							ReservedPorts: []Port{{"main", 8000}},
+							ReservedPorts: []Port{{Label: "main", Value: 8000}},
 						},
 					},
 				},
@@ -511,6 +511,26 @@ func TestAllocsFit_Devices(t *testing.T) {
 	require.True(fit)
 }
 
+func TestAllocsFit_Numa(t *testing.T) {
+	require := require.New(t)
+
+	n := mockNumaNode()
+	a1 := numaAlloc(1, 1000, 1024)
+	a2 := numaAlloc(1, 1000, 1024)
+	a3 := numaAlloc(1, 500, 512)
+
+	// Both allocations fit on NUMA node 1
+	fit, _, _, err := AllocsFit(n, []*Allocation{a1, a2}, nil, false)
+	require.NoError(err)
+	require.True(fit)
+
+	// The node has capacity left but NUMA node 1 is full
+	fit, msg, _, err := AllocsFit(n, []*Allocation{a1, a2, a3}, nil, false)
+	require.NoError(err)
+	require.False(fit)
+	require.Equal("numa node oversubscribed", msg)
+}
+
 // COMPAT(0.11): Remove in 0.11
 func TestScoreFit_Old(t *testing.T) {
 	node := &Node{}
@@ -606,6 +626,10 @@ func TestScoreFit(t *testing.T) {
 	if score != 18.0 {
 		t.Fatalf("bad: %v", score)
 	}
+	score = ScoreFitSpread(node, util)
+	if score != 0.0 {
+		t.Fatalf("bad: %v", score)
+	}
 
 	// Test the worst fit
 	util = &ComparableResources{
@@ -622,6 +646,10 @@ func TestScoreFit(t *testing.T) {
 	if score != 0.0 {
 		t.Fatalf("bad: %v", score)
 	}
+	score = ScoreFitSpread(node, util)
+	if score != 18.0 {
+		t.Fatalf("bad: %v", score)
+	}
 
 	// Test a mid-case scenario
 	util = &ComparableResources{
@@ -638,6 +666,10 @@ func TestScoreFit(t *testing.T) {
 	if score < 10.0 || score > 16.0 {
 		t.Fatalf("bad: %v", score)
 	}
+	score = ScoreFitSpread(node, util)
+	if score < 2.0 || score > 8.0 {
+		t.Fatalf("bad: %v", score)
+	}
 }
 
 func TestACLPolicyListHash(t *testing.T) {
@@ -0,0 +1,172 @@
+package structs
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+)
+
+const (
+	// HostNetworkAttributePrefix prefixes the node attributes set for each
+	// host network resolved on a client. The attribute is set to the device
+	// of the host network.
+	HostNetworkAttributePrefix = "network.host."
+)
+
+// ClientHostNetworkConfig is a named host network declared by a client so
+// the ports of jobs can be bound to a specific interface of multi-homed
+// nodes. The network resolves to the first address of its interface, or of
+// any interface if unset, within its CIDR block.
+type ClientHostNetworkConfig struct {
+	// Name is the name ports reference the host network by.
+	Name string `mapstructure:"-"`
+
+	// CIDR restricts the addresses the host network may resolve to.
+	CIDR string `mapstructure:"cidr"`
+
+	// Interface is the name of the interface of the host network.
+	Interface string `mapstructure:"interface"`
+
+	// ReservedPorts are the ports reserved on the address of the host
+	// network, in the same format as the reserved ports of the client.
+	ReservedPorts string `mapstructure:"reserved_ports"`
+}
+
+// Copy returns a copy of the host network.
+func (n *ClientHostNetworkConfig) Copy() *ClientHostNetworkConfig {
+	if n == nil {
+		return nil
+	}
+	nn := *n
+	return &nn
+}
+
+// Validate returns an error if the host network is invalid.
+func (n *ClientHostNetworkConfig) Validate() error {
+	if n.Name == "" {
+		return errors.New("host network requires a name")
+	}
+	if n.CIDR == "" && n.Interface == "" {
+		return fmt.Errorf("host network %q requires a cidr or an interface", n.Name)
+	}
+	if n.CIDR != "" {
+		if _, _, err := net.ParseCIDR(n.CIDR); err != nil {
+			return fmt.Errorf("host network %q has an invalid cidr: %v", n.Name, err)
+		}
+	}
+	if _, err := ParsePortRanges(n.ReservedPorts); err != nil {
+		return fmt.Errorf("host network %q has invalid reserved ports: %v", n.Name, err)
+	}
+	return nil
+}
+
+// CopyMapClientHostNetworkConfig returns a copy of a map of host networks.
+func CopyMapClientHostNetworkConfig(m map[string]*ClientHostNetworkConfig) map[string]*ClientHostNetworkConfig {
+	if m == nil {
+		return nil
+	}
+
+	nm := make(map[string]*ClientHostNetworkConfig, len(m))
+	for k, v := range m {
+		nm[k] = v.Copy()
+	}
+	return nm
+}
+
+// HostNetworkSliceMerge merges two slices of host networks. The networks of
+// the second slice replace the networks of the first slice with the same
+// name.
+func HostNetworkSliceMerge(a, b []*ClientHostNetworkConfig) []*ClientHostNetworkConfig {
+	if len(a) == 0 && len(b) == 0 {
+		return nil
+	}
+
+	out := make([]*ClientHostNetworkConfig, 0, len(a)+len(b))
+	index := make(map[string]int, len(a))
+	for _, n := range a {
+		index[n.Name] = len(out)
+		out = append(out, n.Copy())
+	}
+	for _, n := range b {
+		if i, ok := index[n.Name]; ok {
+			out[i] = n.Copy()
+			continue
+		}
+		index[n.Name] = len(out)
+		out = append(out, n.Copy())
+	}
+	return out
+}
+
+// NodeHostNetwork is a host network of a node resolved to an address.
+type NodeHostNetwork struct {
+	// Name is the name of the host network.
+	Name string
+
+	// Device is the interface the address of the host network belongs to.
+	Device string
+
+	// IP is the address ports bound to the host network are assigned on.
+	IP string
+
+	// ReservedPorts are the ports reserved on the address of the host
+	// network.
+	ReservedPorts string
+}
+
+// Copy returns a copy of the host network.
+func (n *NodeHostNetwork) Copy() *NodeHostNetwork {
+	if n == nil {
+		return nil
+	}
+	nn := *n
+	return &nn
+}
+
+// HostNetworksEquals returns true if the two sets of host networks are equal.
+func HostNetworksEquals(n1, n2 []*NodeHostNetwork) bool {
+	if len(n1) != len(n2) {
+		return false
+	}
+	for i, n := range n1 {
+		if *n != *n2[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// HostNetworks returns the sorted names of the host networks the ports of
+// the task group are bound to.
+func (tg *TaskGroup) HostNetworks() []string {
+	seen := make(map[string]struct{})
+	add := func(networks Networks) {
+		for _, n := range networks {
+			for _, ports := range [][]Port{n.ReservedPorts, n.DynamicPorts} {
+				for _, p := range ports {
+					if p.HostNetwork != "" {
+						seen[p.HostNetwork] = struct{}{}
+					}
+				}
+			}
+		}
+	}
+
+	add(tg.Networks)
+	for _, task := range tg.Tasks {
+		if task.Resources != nil {
+			add(task.Resources.Networks)
+		}
+	}
+
+	if len(seen) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,71 @@
+package structs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientHostNetworkConfig_Validate(t *testing.T) {
+	t.Parallel()
+
+	n := &ClientHostNetworkConfig{
+		Name:          "public",
+		CIDR:          "203.0.113.0/24",
+		ReservedPorts: "22,8000-8100",
+	}
+	require.NoError(t, n.Validate())
+
+	n.CIDR = ""
+	err := n.Validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "requires a cidr or an interface")
+
+	n.Interface = "eth1"
+	require.NoError(t, n.Validate())
+
+	n.CIDR = "203.0.113.0"
+	err = n.Validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid cidr")
+
+	n.CIDR = ""
+	n.ReservedPorts = "22-"
+	err = n.Validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid reserved ports")
+}
+
+func TestTaskGroup_HostNetworks(t *testing.T) {
+	t.Parallel()
+
+	tg := &TaskGroup{
+		Networks: Networks{
+			{
+				ReservedPorts: []Port{{Label: "http", Value: 80, HostNetwork: "public"}},
+				DynamicPorts:  []Port{{Label: "metrics"}},
+			},
+		},
+		Tasks: []*Task{
+			{
+				Name: "web",
+				Resources: &Resources{
+					Networks: Networks{
+						{DynamicPorts: []Port{{Label: "admin", HostNetwork: "private"}}},
+					},
+				},
+			},
+			{
+				Name: "sidecar",
+				Resources: &Resources{
+					Networks: Networks{
+						{DynamicPorts: []Port{{Label: "stats", HostNetwork: "public"}}},
+					},
+				},
+			},
+		},
+	}
+	require.Equal(t, []string{"private", "public"}, tg.HostNetworks())
+
+	require.Nil(t, (&TaskGroup{}).HostNetworks())
+}
@@ -0,0 +1,146 @@
+package structs
+
+import (
+	"errors"
+	"fmt"
+
+	multierror "github.com/hashicorp/go-multierror"
+)
+
+// ClientHostVolumeConfig is a host volume exposed by a client to the task
+// groups running on it. Its health is checked periodically by the client.
+type ClientHostVolumeConfig struct {
+	// Name is the name the volume is requested by.
+	Name string `mapstructure:"-"`
+
+	// Path is the path of the volume on the host.
+	Path string `mapstructure:"path"`
+
+	// ReadOnly is whether the volume may only be mounted read only.
+	ReadOnly bool `mapstructure:"read_only"`
+
+	// Mountpoint is whether a filesystem must be mounted at the path for the
+	// volume to be healthy.
+	Mountpoint bool `mapstructure:"mountpoint"`
+
+	// Healthy is whether the volume passed its last health check, its path
+	// being present, mounted if it must be a mountpoint, and writable unless
+	// the volume is read only.
+	Healthy bool `mapstructure:"-"`
+
+	// HealthDescription describes the result of the last health check.
+	HealthDescription string `mapstructure:"-"`
+}
+
+// Copy returns a copy of the host volume.
+func (v *ClientHostVolumeConfig) Copy() *ClientHostVolumeConfig {
+	if v == nil {
+		return nil
+	}
+	nv := *v
+	return &nv
+}
+
+// Validate returns an error if the host volume is invalid.
+func (v *ClientHostVolumeConfig) Validate() error {
+	if v.Name == "" {
+		return errors.New("host volume requires a name")
+	}
+	if v.Path == "" {
+		return fmt.Errorf("host volume %q requires a path", v.Name)
+	}
+	return nil
+}
+
+// HashInclude is used to blacklist uniquely identifying host volume fields
+// from being included in the computed node class. The health of the volume is
+// excluded so that health checks don't change the class.
+func (v ClientHostVolumeConfig) HashInclude(field string, _ interface{}) (bool, error) {
+	switch field {
+	case "Name", "ReadOnly":
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+// CopyMapClientHostVolumeConfig returns a copy of a map of host volumes.
+func CopyMapClientHostVolumeConfig(m map[string]*ClientHostVolumeConfig) map[string]*ClientHostVolumeConfig {
+	if m == nil {
+		return nil
+	}
+
+	nm := make(map[string]*ClientHostVolumeConfig, len(m))
+	for k, v := range m {
+		nm[k] = v.Copy()
+	}
+	return nm
+}
+
+// HostVolumeSliceMerge merges two slices of host volumes. The volumes of the
+// second slice replace the volumes of the first slice with the same name.
+func HostVolumeSliceMerge(a, b []*ClientHostVolumeConfig) []*ClientHostVolumeConfig {
+	if len(a) == 0 && len(b) == 0 {
+		return nil
+	}
+
+	out := make([]*ClientHostVolumeConfig, 0, len(a)+len(b))
+	index := make(map[string]int, len(a))
+	for _, v := range a {
+		index[v.Name] = len(out)
+		out = append(out, v.Copy())
+	}
+	for _, v := range b {
+		if i, ok := index[v.Name]; ok {
+			out[i] = v.Copy()
+			continue
+		}
+		index[v.Name] = len(out)
+		out = append(out, v.Copy())
+	}
+	return out
+}
+
+// VolumeRequest is a volume required by a task group. Host volume requests
+// restrict the placements of the task group to the nodes exposing a healthy
+// host volume named after the source.
+type VolumeRequest struct {
+	Name     string
+	Type     string
+	Source   string
+	ReadOnly bool
+}
+
+// Copy returns a copy of the volume request.
+func (v *VolumeRequest) Copy() *VolumeRequest {
+	if v == nil {
+		return nil
+	}
+	nv := *v
+	return &nv
+}
+
+// Validate returns an error if the volume request is invalid.
+func (v *VolumeRequest) Validate() error {
+	var mErr multierror.Error
+	if v.Type != VolumeTypeHost {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("volume type must be %q, got %q", VolumeTypeHost, v.Type))
+	}
+	if v.Source == "" {
+		mErr.Errors = append(mErr.Errors, errors.New("volume requires a source"))
+	}
+	return mErr.ErrorOrNil()
+}
+
+// CopyMapVolumeRequest returns a copy of a map of volume requests.
+func CopyMapVolumeRequest(m map[string]*VolumeRequest) map[string]*VolumeRequest {
+	if m == nil {
+		return nil
+	}
+
+	nm := make(map[string]*VolumeRequest, len(m))
+	for k, v := range m {
+		nm[k] = v.Copy()
+	}
+	return nm
+}
@@ -0,0 +1,48 @@
+package structs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVolumeRequest_Validate(t *testing.T) {
+	t.Parallel()
+
+	req := &VolumeRequest{
+		Name:   "data",
+		Type:   VolumeTypeHost,
+		Source: "shared_data",
+	}
+	require.NoError(t, req.Validate())
+
+	req.Type = VolumeTypeCSI
+	req.Source = ""
+	err := req.Validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "volume type must be")
+	require.Contains(t, err.Error(), "requires a source")
+}
+
+func TestHostVolumeSliceMerge(t *testing.T) {
+	t.Parallel()
+
+	first := []*ClientHostVolumeConfig{
+		{Name: "a", Path: "/a"},
+		{Name: "b", Path: "/b"},
+	}
+	second := []*ClientHostVolumeConfig{
+		{Name: "c", Path: "/c"},
+		{Name: "a", Path: "/a2", ReadOnly: true},
+	}
+
+	out := HostVolumeSliceMerge(first, second)
+	require.Len(t, out, 3)
+	require.Equal(t, "/a2", out[0].Path)
+	require.True(t, out[0].ReadOnly)
+	require.Equal(t, "b", out[1].Name)
+	require.Equal(t, "c", out[2].Name)
+	require.Equal(t, "/a", first[0].Path)
+
+	require.Nil(t, HostVolumeSliceMerge(nil, nil))
+}
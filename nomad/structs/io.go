@@ -0,0 +1,79 @@
+package structs
+
+import (
+	"fmt"
+	"path/filepath"
+
+	multierror "github.com/hashicorp/go-multierror"
+)
+
+const (
+	// MinIOWeight and MaxIOWeight bound the relative block I/O weight a task
+	// may request. The range matches the blkio.weight of cgroups v1 and is
+	// scaled into io.weight on cgroups v2.
+	MinIOWeight = 10
+	MaxIOWeight = 1000
+)
+
+// IOLimit throttles the block I/O of a task against a single block device.
+// Zero values are left unlimited.
+type IOLimit struct {
+	// Device is the path of the block device on the client, such as
+	// "/dev/sda".
+	Device string
+
+	// ReadBps and WriteBps limit the bandwidth in bytes per second.
+	ReadBps  int64
+	WriteBps int64
+
+	// ReadIOps and WriteIOps limit the number of operations per second.
+	ReadIOps  int64
+	WriteIOps int64
+}
+
+// Copy returns a copy of the I/O limit.
+func (l *IOLimit) Copy() *IOLimit {
+	if l == nil {
+		return nil
+	}
+	nl := *l
+	return &nl
+}
+
+// Validate is used to sanity check the I/O limit.
+func (l *IOLimit) Validate() error {
+	var mErr multierror.Error
+	if l.Device == "" {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("missing device"))
+	} else if !filepath.IsAbs(l.Device) {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("device %q must be an absolute path", l.Device))
+	}
+
+	if l.ReadBps < 0 || l.WriteBps < 0 || l.ReadIOps < 0 || l.WriteIOps < 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("limits can't be negative"))
+	} else if l.ReadBps == 0 && l.WriteBps == 0 && l.ReadIOps == 0 && l.WriteIOps == 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("at least one limit must be set"))
+	}
+	return mErr.ErrorOrNil()
+}
+
+// validateIO checks the block I/O weight and limits of a task.
+func validateIO(weight int, limits []*IOLimit) error {
+	var mErr multierror.Error
+	if weight != 0 && (weight < MinIOWeight || weight > MaxIOWeight) {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("io_weight must be between %d and %d; got %d", MinIOWeight, MaxIOWeight, weight))
+	}
+
+	devices := make(map[string]struct{}, len(limits))
+	for i, l := range limits {
+		if err := l.Validate(); err != nil {
+			mErr.Errors = append(mErr.Errors, multierror.Prefix(err, fmt.Sprintf("io_limit %d:", i+1)))
+			continue
+		}
+		if _, ok := devices[l.Device]; ok {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("io_limit %d: duplicate device %q", i+1, l.Device))
+		}
+		devices[l.Device] = struct{}{}
+	}
+	return mErr.ErrorOrNil()
+}
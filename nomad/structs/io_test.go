@@ -0,0 +1,88 @@
+package structs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResources_Validate_IO(t *testing.T) {
+	cases := []struct {
+		name   string
+		weight int
+		limits []*IOLimit
+		err    string
+	}{
+		{
+			name: "unset",
+		},
+		{
+			name:   "valid",
+			weight: 500,
+			limits: []*IOLimit{
+				{Device: "/dev/sda", ReadBps: 1024, WriteIOps: 100},
+				{Device: "/dev/sdb", WriteBps: 1024},
+			},
+		},
+		{
+			name:   "weight too low",
+			weight: 5,
+			err:    "io_weight must be between 10 and 1000",
+		},
+		{
+			name:   "weight too high",
+			weight: 2000,
+			err:    "io_weight must be between 10 and 1000",
+		},
+		{
+			name:   "relative device",
+			limits: []*IOLimit{{Device: "sda", ReadBps: 1}},
+			err:    "must be an absolute path",
+		},
+		{
+			name:   "no limits",
+			limits: []*IOLimit{{Device: "/dev/sda"}},
+			err:    "at least one limit must be set",
+		},
+		{
+			name:   "negative",
+			limits: []*IOLimit{{Device: "/dev/sda", ReadBps: -1}},
+			err:    "limits can't be negative",
+		},
+		{
+			name: "duplicate device",
+			limits: []*IOLimit{
+				{Device: "/dev/sda", ReadBps: 1},
+				{Device: "/dev/sda", WriteBps: 1},
+			},
+			err: "duplicate device",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := DefaultResources()
+			r.IOWeight = c.weight
+			r.IOLimits = c.limits
+			err := r.Validate()
+			if c.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), c.err)
+			}
+		})
+	}
+}
+
+func TestResources_Copy_IO(t *testing.T) {
+	r := DefaultResources()
+	r.IOWeight = 500
+	r.IOLimits = []*IOLimit{{Device: "/dev/sda", ReadBps: 1024}}
+
+	c := r.Copy()
+	require.Equal(t, r, c)
+
+	c.IOLimits[0].ReadBps = 2048
+	require.Equal(t, int64(1024), r.IOLimits[0].ReadBps)
+}
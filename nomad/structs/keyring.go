@@ -0,0 +1,54 @@
+package structs
+
+// RootKeyMeta is the metadata of a root key, returned when listing the
+// keyring. It never holds the key material.
+type RootKeyMeta struct {
+	// KeyID is the unique ID of the key.
+	KeyID string
+
+	// Algorithm is the encryption algorithm of the key.
+	Algorithm string
+
+	// Active marks the key new variables are encrypted with.
+	Active bool
+
+	// Wrapped is whether the key is stored wrapped by the keyring encryption
+	// key of the servers.
+	Wrapped bool
+
+	// Raft Indexes and time
+	CreateIndex uint64
+	ModifyIndex uint64
+	CreateTime  int64
+}
+
+// KeyringListRootKeyMetaResponse is used to list the root keys.
+type KeyringListRootKeyMetaResponse struct {
+	Keys []*RootKeyMeta
+	QueryMeta
+}
+
+// KeyringRotateRootKeyRequest is used to create a new active root key.
+type KeyringRotateRootKeyRequest struct {
+	// Full rewraps the data keys of all the variables with the new root key,
+	// so that the previous root keys can be removed.
+	Full bool
+
+	WriteRequest
+}
+
+// KeyringRotateRootKeyResponse returns the new active root key.
+type KeyringRotateRootKeyResponse struct {
+	Key *RootKeyMeta
+
+	// Rewrapped is the number of variables rewrapped by a full rotation.
+	Rewrapped int
+
+	WriteMeta
+}
+
+// KeyringDeleteRootKeyRequest is used to remove an inactive root key.
+type KeyringDeleteRootKeyRequest struct {
+	KeyID string
+	WriteRequest
+}
@@ -0,0 +1,242 @@
+package structs
+
+import (
+	"fmt"
+
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/helper"
+)
+
+const (
+	// MultiregionOnFailureFailAll fails the deployments of every region when
+	// the deployment of one region fails.
+	MultiregionOnFailureFailAll = "fail_all"
+
+	// MultiregionOnFailureFailLocal only fails the deployment of the region
+	// that failed. The regions that have not started their deployment yet
+	// remain pending until they are unblocked by an operator.
+	MultiregionOnFailureFailLocal = "fail_local"
+)
+
+// Multiregion is used to register a job in multiple regions and to coordinate
+// the rollout of its deployments across the regions.
+type Multiregion struct {
+	// Strategy is the rollout strategy across the regions
+	Strategy *MultiregionStrategy
+
+	// Regions is the set of regions the job is registered in, in rollout
+	// order
+	Regions []*MultiregionRegion
+}
+
+// MultiregionStrategy is the rollout strategy of a multiregion job.
+type MultiregionStrategy struct {
+	// MaxParallel is the number of regions that deploy at the same time. Zero
+	// deploys all the regions in parallel.
+	MaxParallel int
+
+	// OnFailure is one of "fail_all" or "fail_local" and determines how the
+	// failure of the deployment of a region affects its peer regions.
+	OnFailure string
+}
+
+// MultiregionRegion describes a region of a multiregion job and the values
+// that override those of the job in the region.
+type MultiregionRegion struct {
+	// Name is the name of the region
+	Name string
+
+	// Count overrides the count of the task groups of the job if set
+	Count int
+
+	// Datacenters overrides the datacenters of the job if set
+	Datacenters []string
+
+	// Meta is merged into the meta of the job
+	Meta map[string]string
+}
+
+// Copy returns a deep copy of the multiregion configuration.
+func (m *Multiregion) Copy() *Multiregion {
+	if m == nil {
+		return nil
+	}
+	nm := new(Multiregion)
+	if m.Strategy != nil {
+		ns := *m.Strategy
+		nm.Strategy = &ns
+	}
+	if m.Regions != nil {
+		nm.Regions = make([]*MultiregionRegion, len(m.Regions))
+		for i, region := range m.Regions {
+			nr := *region
+			nr.Datacenters = helper.CopySliceString(region.Datacenters)
+			nr.Meta = helper.CopyMapStringString(region.Meta)
+			nm.Regions[i] = &nr
+		}
+	}
+	return nm
+}
+
+// Validate is used to sanity check the multiregion configuration.
+func (m *Multiregion) Validate() error {
+	if m == nil {
+		return nil
+	}
+
+	var mErr multierror.Error
+	if m.Strategy != nil {
+		if m.Strategy.MaxParallel < 0 {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Multiregion max_parallel must be non-negative"))
+		}
+		switch m.Strategy.OnFailure {
+		case "", MultiregionOnFailureFailAll, MultiregionOnFailureFailLocal:
+		default:
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Multiregion on_failure must be one of %q or %q",
+				MultiregionOnFailureFailAll, MultiregionOnFailureFailLocal))
+		}
+	}
+
+	if len(m.Regions) == 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("Multiregion must specify at least one region"))
+	}
+	seen := make(map[string]struct{}, len(m.Regions))
+	for i, region := range m.Regions {
+		if region.Name == "" {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Multiregion region %d missing name", i+1))
+			continue
+		}
+		if _, ok := seen[region.Name]; ok {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Multiregion region %q defined more than once", region.Name))
+		}
+		seen[region.Name] = struct{}{}
+		if region.Count < 0 {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Multiregion region %q count must be non-negative", region.Name))
+		}
+	}
+	return mErr.ErrorOrNil()
+}
+
+// OnFailure returns the failure mode of the rollout, defaulting to failing
+// only the local region.
+func (m *Multiregion) OnFailure() string {
+	if m.Strategy == nil || m.Strategy.OnFailure == "" {
+		return MultiregionOnFailureFailLocal
+	}
+	return m.Strategy.OnFailure
+}
+
+// maxParallel returns the number of regions that deploy at the same time or
+// zero if all the regions deploy in parallel.
+func (m *Multiregion) maxParallel() int {
+	if m.Strategy == nil {
+		return 0
+	}
+	return m.Strategy.MaxParallel
+}
+
+// regionIndex returns the position of the region in the rollout or -1 if the
+// region is not part of it.
+func (m *Multiregion) regionIndex(region string) int {
+	for i, r := range m.Regions {
+		if r.Name == region {
+			return i
+		}
+	}
+	return -1
+}
+
+// StartsPending returns whether the deployment of the given region must wait
+// for the deployments of the regions before it in the rollout.
+func (m *Multiregion) StartsPending(region string) bool {
+	max := m.maxParallel()
+	return max > 0 && m.regionIndex(region) >= max
+}
+
+// NextRegion returns the region whose deployment is started once the
+// deployment of the given region completes, or an empty string if there is
+// none.
+func (m *Multiregion) NextRegion(region string) string {
+	max := m.maxParallel()
+	idx := m.regionIndex(region)
+	if max == 0 || idx < 0 || idx+max >= len(m.Regions) {
+		return ""
+	}
+	return m.Regions[idx+max].Name
+}
+
+// Peers returns the names of the regions of the rollout other than the given
+// one.
+func (m *Multiregion) Peers(region string) []string {
+	peers := make([]string, 0, len(m.Regions))
+	for _, r := range m.Regions {
+		if r.Name != region {
+			peers = append(peers, r.Name)
+		}
+	}
+	return peers
+}
+
+// IsMultiregion returns whether the job is registered in multiple regions.
+func (j *Job) IsMultiregion() bool {
+	return j.Multiregion != nil && len(j.Multiregion.Regions) != 0
+}
+
+// RegionalJob returns the copy of the multiregion job that is registered in
+// the given region. The count, datacenters and meta of the region override
+// those of the job.
+func (j *Job) RegionalJob(region *MultiregionRegion) *Job {
+	rj := j.Copy()
+	rj.Region = region.Name
+	if len(region.Datacenters) != 0 {
+		rj.Datacenters = helper.CopySliceString(region.Datacenters)
+	}
+	if region.Count > 0 {
+		for _, tg := range rj.TaskGroups {
+			tg.Count = region.Count
+		}
+	}
+	if len(region.Meta) != 0 {
+		if rj.Meta == nil {
+			rj.Meta = make(map[string]string, len(region.Meta))
+		}
+		for k, v := range region.Meta {
+			rj.Meta[k] = v
+		}
+	}
+	return rj
+}
+
+// MultiregionOrigin identifies the fan-out of a request for a multiregion job
+// to its regions. The token is issued by the leader of the region the request
+// was submitted to and is only valid while the fan-out is in progress.
+type MultiregionOrigin struct {
+	// Region is the region the request was submitted to
+	Region string
+
+	// Token identifies the fan-out
+	Token string
+}
+
+// JobMultiregionVerifyRequest is used by a region to verify with the origin
+// region that a request for a multiregion job was fanned out by it.
+type JobMultiregionVerifyRequest struct {
+	// Token is the token of the fan-out
+	Token string
+
+	// JobID is the ID of the job of the request
+	JobID string
+
+	// CopyRegion is the region the copy of the request is applied to
+	CopyRegion string
+
+	QueryOptions
+}
+
+// JobMultiregionVerifyResponse is the response of a fan-out verification.
+type JobMultiregionVerifyResponse struct {
+	// Valid is whether the origin region is fanning out the request
+	Valid bool
+
+	QueryMeta
+}
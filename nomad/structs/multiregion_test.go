@@ -0,0 +1,120 @@
+package structs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func mockMultiregion(maxParallel int) *Multiregion {
+	return &Multiregion{
+		Strategy: &MultiregionStrategy{
+			MaxParallel: maxParallel,
+			OnFailure:   MultiregionOnFailureFailAll,
+		},
+		Regions: []*MultiregionRegion{
+			{Name: "east", Count: 3, Datacenters: []string{"east-1"}, Meta: map[string]string{"region_code": "E"}},
+			{Name: "west"},
+			{Name: "north"},
+		},
+	}
+}
+
+func TestMultiregion_Validate(t *testing.T) {
+	require := require.New(t)
+
+	var m *Multiregion
+	require.NoError(m.Validate())
+	require.NoError(mockMultiregion(1).Validate())
+
+	m = mockMultiregion(-1)
+	m.Strategy.OnFailure = "ignore"
+	m.Regions = append(m.Regions, &MultiregionRegion{Name: "east"}, &MultiregionRegion{Count: -1})
+	err := m.Validate()
+	require.Error(err)
+	require.Contains(err.Error(), "max_parallel must be non-negative")
+	require.Contains(err.Error(), "on_failure must be one of")
+	require.Contains(err.Error(), `region "east" defined more than once`)
+	require.Contains(err.Error(), "region 5 missing name")
+
+	err = (&Multiregion{}).Validate()
+	require.Error(err)
+	require.Contains(err.Error(), "at least one region")
+}
+
+func TestMultiregion_Rollout(t *testing.T) {
+	require := require.New(t)
+
+	// All the regions deploy in parallel by default
+	m := mockMultiregion(0)
+	for _, r := range m.Regions {
+		require.False(m.StartsPending(r.Name))
+		require.Empty(m.NextRegion(r.Name))
+	}
+
+	// One region at a time
+	m = mockMultiregion(1)
+	require.False(m.StartsPending("east"))
+	require.True(m.StartsPending("west"))
+	require.True(m.StartsPending("north"))
+	require.Equal("west", m.NextRegion("east"))
+	require.Equal("north", m.NextRegion("west"))
+	require.Empty(m.NextRegion("north"))
+	require.Empty(m.NextRegion("unknown"))
+
+	// Two regions at a time
+	m = mockMultiregion(2)
+	require.False(m.StartsPending("west"))
+	require.True(m.StartsPending("north"))
+	require.Equal("north", m.NextRegion("east"))
+	require.Empty(m.NextRegion("west"))
+
+	require.Equal([]string{"east", "north"}, m.Peers("west"))
+	require.Equal(MultiregionOnFailureFailAll, m.OnFailure())
+	m.Strategy = nil
+	require.Equal(MultiregionOnFailureFailLocal, m.OnFailure())
+}
+
+func TestJob_RegionalJob(t *testing.T) {
+	require := require.New(t)
+
+	job := MockJob()
+	job.Meta = map[string]string{"owner": "bar"}
+	job.Multiregion = mockMultiregion(1)
+	require.True(job.IsMultiregion())
+
+	east := job.RegionalJob(job.Multiregion.Regions[0])
+	require.Equal("east", east.Region)
+	require.Equal([]string{"east-1"}, east.Datacenters)
+	require.Equal(3, east.TaskGroups[0].Count)
+	require.Equal("E", east.Meta["region_code"])
+	require.Equal("bar", east.Meta["owner"])
+	require.NotContains(job.Meta, "region_code")
+
+	west := job.RegionalJob(job.Multiregion.Regions[1])
+	require.Equal("west", west.Region)
+	require.Equal(job.Datacenters, west.Datacenters)
+	require.Equal(job.TaskGroups[0].Count, west.TaskGroups[0].Count)
+}
+
+func TestNewDeployment_Multiregion(t *testing.T) {
+	require := require.New(t)
+
+	job := MockJob()
+	d := NewDeployment(job)
+	require.False(d.IsMultiregion)
+	require.Equal(DeploymentStatusRunning, d.Status)
+
+	job.Multiregion = mockMultiregion(1)
+	job.Region = "east"
+	d = NewDeployment(job)
+	require.True(d.IsMultiregion)
+	require.Equal(DeploymentStatusRunning, d.Status)
+
+	job.Region = "west"
+	d = NewDeployment(job)
+	require.True(d.IsMultiregion)
+	require.Equal(DeploymentStatusPending, d.Status)
+	require.Equal(DeploymentStatusDescriptionPendingForPeer, d.StatusDescription)
+	require.True(d.Active())
+}
@@ -0,0 +1,104 @@
+package structs
+
+import (
+	"fmt"
+	"regexp"
+
+	multierror "github.com/hashicorp/go-multierror"
+)
+
+const (
+	// maxNamespaceDescriptionLength limits a namespace description length
+	maxNamespaceDescriptionLength = 256
+)
+
+var (
+	// validNamespaceName is used to validate a namespace name
+	validNamespaceName = regexp.MustCompile("^[a-zA-Z0-9-]{1,128}$")
+)
+
+// Namespace allows logically grouping jobs and their associated objects. A
+// namespace may reference a quota specification that limits the resources
+// its allocations may consume.
+type Namespace struct {
+	// Name is the name of the namespace
+	Name string
+
+	// Description is a human readable description of the namespace
+	Description string
+
+	// Quota is the quota specification that the namespace should account
+	// against.
+	Quota string
+
+	// GC overrides the garbage collection thresholds of the server for the
+	// jobs of the namespace.
+	GC *GCConfig
+
+	// Raft Indexes
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// Validate is used to sanity check a namespace.
+func (n *Namespace) Validate() error {
+	var mErr multierror.Error
+	if !validNamespaceName.MatchString(n.Name) {
+		err := fmt.Errorf("invalid name %q. Must match regex %s", n.Name, validNamespaceName)
+		mErr.Errors = append(mErr.Errors, err)
+	}
+	if len(n.Description) > maxNamespaceDescriptionLength {
+		err := fmt.Errorf("description longer than %d", maxNamespaceDescriptionLength)
+		mErr.Errors = append(mErr.Errors, err)
+	}
+	if err := n.GC.Validate(); err != nil {
+		mErr.Errors = append(mErr.Errors, err)
+	}
+	return mErr.ErrorOrNil()
+}
+
+// Copy returns a copy of the namespace.
+func (n *Namespace) Copy() *Namespace {
+	if n == nil {
+		return nil
+	}
+	nn := new(Namespace)
+	*nn = *n
+	nn.GC = nn.GC.Copy()
+	return nn
+}
+
+// NamespaceListRequest is used to request a list of namespaces.
+type NamespaceListRequest struct {
+	QueryOptions
+}
+
+// NamespaceListResponse is used for a list request.
+type NamespaceListResponse struct {
+	Namespaces []*Namespace
+	QueryMeta
+}
+
+// NamespaceSpecificRequest is used to query a specific namespace.
+type NamespaceSpecificRequest struct {
+	Name string
+	QueryOptions
+}
+
+// SingleNamespaceResponse is used to return a single namespace.
+type SingleNamespaceResponse struct {
+	Namespace *Namespace
+	QueryMeta
+}
+
+// NamespaceUpsertRequest is used to upsert a set of namespaces.
+type NamespaceUpsertRequest struct {
+	Namespaces []*Namespace
+	WriteRequest
+}
+
+// NamespaceDeleteRequest is used to delete a set of namespaces.
+type NamespaceDeleteRequest struct {
+	Namespaces []string
+	WriteRequest
+}
@@ -33,10 +33,11 @@ var (
 // NetworkIndex is used to index the available network resources
 // and the used network resources on a machine given allocations
 type NetworkIndex struct {
-	AvailNetworks  []*NetworkResource // List of available networks
-	AvailBandwidth map[string]int     // Bandwidth by device
-	UsedPorts      map[string]Bitmap  // Ports by IP
-	UsedBandwidth  map[string]int     // Bandwidth by device
+	AvailNetworks  []*NetworkResource          // List of available networks
+	AvailBandwidth map[string]int              // Bandwidth by device
+	UsedPorts      map[string]Bitmap           // Ports by IP
+	UsedBandwidth  map[string]int              // Bandwidth by device
+	HostNetworks   map[string]*NodeHostNetwork // Host networks by name
 }
 
 // NewNetworkIndex is used to construct a new network index
@@ -45,6 +46,7 @@ func NewNetworkIndex() *NetworkIndex {
 		AvailBandwidth: make(map[string]int),
 		UsedPorts:      make(map[string]Bitmap),
 		UsedBandwidth:  make(map[string]int),
+		HostNetworks:   make(map[string]*NodeHostNetwork),
 	}
 }
 
@@ -88,6 +90,14 @@ func (idx *NetworkIndex) SetNode(node *Node) (collide bool) {
 		}
 	}
 
+	// Add the host networks
+	if node.NodeResources != nil {
+		for _, n := range node.NodeResources.HostNetworks {
+			idx.HostNetworks[n.Name] = n
+			idx.usedPorts(n.IP)
+		}
+	}
+
 	// COMPAT(0.11): Remove in 0.11
 	// Handle reserving ports, handling both new and old
 	if node.ReservedResources != nil && node.ReservedResources.Networks.ReservedHostPorts != "" {
@@ -100,6 +110,13 @@ func (idx *NetworkIndex) SetNode(node *Node) (collide bool) {
 		}
 	}
 
+	// Reserve the ports of the host networks on their address only
+	for _, n := range idx.HostNetworks {
+		if idx.addReservedPorts(idx.usedPorts(n.IP), n.ReservedPorts) {
+			collide = true
+		}
+	}
+
 	return
 }
 
@@ -113,6 +130,13 @@ func (idx *NetworkIndex) AddAllocs(allocs []*Allocation) (collide bool) {
 		}
 
 		if alloc.AllocatedResources != nil {
+			// The ports of the group network are shared by the tasks
+			for _, n := range alloc.AllocatedResources.Shared.Networks {
+				if idx.AddReserved(n) {
+					collide = true
+				}
+			}
+
 			for _, task := range alloc.AllocatedResources.Tasks {
 				if len(task.Networks) == 0 {
 					continue
@@ -142,25 +166,14 @@ func (idx *NetworkIndex) AddAllocs(allocs []*Allocation) (collide bool) {
 // if there is a port collision
 func (idx *NetworkIndex) AddReserved(n *NetworkResource) (collide bool) {
 	// Add the port usage
-	used := idx.UsedPorts[n.IP]
-	if used == nil {
-		// Try to get a bitmap from the pool, else create
-		raw := bitmapPool.Get()
-		if raw != nil {
-			used = raw.(Bitmap)
-			used.Clear()
-		} else {
-			used, _ = NewBitmap(maxValidPort)
-		}
-		idx.UsedPorts[n.IP] = used
-	}
-
+	idx.usedPorts(n.IP)
 	for _, ports := range [][]Port{n.ReservedPorts, n.DynamicPorts} {
 		for _, port := range ports {
 			// Guard against invalid port
 			if port.Value < 0 || port.Value >= maxValidPort {
 				return true
 			}
+			used := idx.usedPorts(port.IP(n))
 			if used.Check(uint(port.Value)) {
 				collide = true
 			} else {
@@ -178,45 +191,62 @@ func (idx *NetworkIndex) AddReserved(n *NetworkResource) (collide bool) {
 // interfaces. The port format is comma delimited, with spans given as n1-n2
 // (80,100-200,205)
 func (idx *NetworkIndex) AddReservedPortRange(ports string) (collide bool) {
+	// Ensure we create a bitmap for each available network
+	for _, n := range idx.AvailNetworks {
+		idx.usedPorts(n.IP)
+	}
+
+	for _, used := range idx.UsedPorts {
+		if idx.addReservedPorts(used, ports) {
+			collide = true
+		}
+	}
+
+	return
+}
+
+// addReservedPorts marks the ports given as reserved in the used ports of
+// an IP. The port format is the one of AddReservedPortRange.
+func (idx *NetworkIndex) addReservedPorts(used Bitmap, ports string) (collide bool) {
 	// Convert the ports into a slice of ints
 	resPorts, err := ParsePortRanges(ports)
 	if err != nil {
 		return
 	}
 
-	// Ensure we create a bitmap for each available network
-	for _, n := range idx.AvailNetworks {
-		used := idx.UsedPorts[n.IP]
-		if used == nil {
-			// Try to get a bitmap from the pool, else create
-			raw := bitmapPool.Get()
-			if raw != nil {
-				used = raw.(Bitmap)
-				used.Clear()
-			} else {
-				used, _ = NewBitmap(maxValidPort)
-			}
-			idx.UsedPorts[n.IP] = used
+	for _, port := range resPorts {
+		// Guard against invalid port
+		if port < 0 || port >= maxValidPort {
+			return true
 		}
-	}
-
-	for _, used := range idx.UsedPorts {
-		for _, port := range resPorts {
-			// Guard against invalid port
-			if port < 0 || port >= maxValidPort {
-				return true
-			}
-			if used.Check(uint(port)) {
-				collide = true
-			} else {
-				used.Set(uint(port))
-			}
+		if used.Check(uint(port)) {
+			collide = true
+		} else {
+			used.Set(uint(port))
 		}
 	}
 
 	return
 }
 
+// usedPorts returns the bitmap of the ports used on an IP, creating it if
+// needed.
+func (idx *NetworkIndex) usedPorts(ip string) Bitmap {
+	used := idx.UsedPorts[ip]
+	if used == nil {
+		// Try to get a bitmap from the pool, else create
+		raw := bitmapPool.Get()
+		if raw != nil {
+			used = raw.(Bitmap)
+			used.Clear()
+		} else {
+			used, _ = NewBitmap(maxValidPort)
+		}
+		idx.UsedPorts[ip] = used
+	}
+	return used
+}
+
 // yieldIP is used to iteratively invoke the callback with
 // an available IP
 func (idx *NetworkIndex) yieldIP(cb func(net *NetworkResource, ip net.IP) bool) {
@@ -258,25 +288,9 @@ func (idx *NetworkIndex) AssignNetwork(ask *NetworkResource) (out *NetworkResour
 			return
 		}
 
-		used := idx.UsedPorts[ipStr]
-
-		// Check if any of the reserved ports are in use
-		for _, port := range ask.ReservedPorts {
-			// Guard against invalid port
-			if port.Value < 0 || port.Value >= maxValidPort {
-				err = fmt.Errorf("invalid port %d (out of range)", port.Value)
-				return
-			}
-
-			// Check if in use
-			if used != nil && used.Check(uint(port.Value)) {
-				err = fmt.Errorf("reserved port collision")
-				return
-			}
-		}
-
 		// Create the offer
 		offer := &NetworkResource{
+			Mode:          ask.Mode,
 			Device:        n.Device,
 			IP:            ipStr,
 			MBits:         ask.MBits,
@@ -284,27 +298,10 @@ func (idx *NetworkIndex) AssignNetwork(ask *NetworkResource) (out *NetworkResour
 			DynamicPorts:  ask.DynamicPorts,
 		}
 
-		// Try to stochastically pick the dynamic ports as it is faster and
-		// lower memory usage.
-		var dynPorts []int
-		var dynErr error
-		dynPorts, dynErr = getDynamicPortsStochastic(used, ask)
-		if dynErr == nil {
-			goto BUILD_OFFER
-		}
-
-		// Fall back to the precise method if the random sampling failed.
-		dynPorts, dynErr = getDynamicPortsPrecise(used, ask)
-		if dynErr != nil {
-			err = dynErr
+		if err = idx.assignPorts(offer); err != nil {
 			return
 		}
 
-	BUILD_OFFER:
-		for i, port := range dynPorts {
-			offer.DynamicPorts[i].Value = port
-		}
-
 		// Stop, we have an offer!
 		out = offer
 		err = nil
@@ -313,6 +310,84 @@ func (idx *NetworkIndex) AssignNetwork(ask *NetworkResource) (out *NetworkResour
 	return
 }
 
+// assignPorts checks the reserved ports of an offer are free and picks its
+// dynamic ports. Ports bound to a host network are assigned on the address of
+// the host network, the other ports on the IP of the offer.
+func (idx *NetworkIndex) assignPorts(offer *NetworkResource) error {
+	// Group the ports by the IP they are assigned on
+	var ips []string
+	asks := make(map[string]*NetworkResource)
+	dynIndexes := make(map[string][]int)
+	portIP := func(p *Port) (string, error) {
+		ip := offer.IP
+		p.HostIP = ""
+		if p.HostNetwork != "" {
+			hostNetwork, ok := idx.HostNetworks[p.HostNetwork]
+			if !ok {
+				return "", fmt.Errorf("missing host network %q", p.HostNetwork)
+			}
+			ip = hostNetwork.IP
+			p.HostIP = ip
+		}
+		if _, ok := asks[ip]; !ok {
+			asks[ip] = &NetworkResource{}
+			ips = append(ips, ip)
+		}
+		return ip, nil
+	}
+
+	for i := range offer.ReservedPorts {
+		ip, err := portIP(&offer.ReservedPorts[i])
+		if err != nil {
+			return err
+		}
+		asks[ip].ReservedPorts = append(asks[ip].ReservedPorts, offer.ReservedPorts[i])
+	}
+	for i := range offer.DynamicPorts {
+		ip, err := portIP(&offer.DynamicPorts[i])
+		if err != nil {
+			return err
+		}
+		asks[ip].DynamicPorts = append(asks[ip].DynamicPorts, offer.DynamicPorts[i])
+		dynIndexes[ip] = append(dynIndexes[ip], i)
+	}
+
+	for _, ip := range ips {
+		ask := asks[ip]
+		used := idx.UsedPorts[ip]
+
+		// Check if any of the reserved ports are in use
+		for _, port := range ask.ReservedPorts {
+			// Guard against invalid port
+			if port.Value < 0 || port.Value >= maxValidPort {
+				return fmt.Errorf("invalid port %d (out of range)", port.Value)
+			}
+
+			// Check if in use
+			if used != nil && used.Check(uint(port.Value)) {
+				return fmt.Errorf("reserved port collision")
+			}
+		}
+
+		// Try to stochastically pick the dynamic ports as it is faster and
+		// lower memory usage. Fall back to the precise method if the random
+		// sampling failed.
+		dynPorts, err := getDynamicPortsStochastic(used, ask)
+		if err != nil {
+			dynPorts, err = getDynamicPortsPrecise(used, ask)
+			if err != nil {
+				return err
+			}
+		}
+
+		for i, port := range dynPorts {
+			offer.DynamicPorts[dynIndexes[ip][i]].Value = port
+		}
+	}
+
+	return nil
+}
+
 // getDynamicPortsPrecise takes the nodes used port bitmap which may be nil if
 // no ports have been allocated yet, the network ask and returns a set of unused
 // ports to fullfil the ask's DynamicPorts or an error if it failed. An error
@@ -5,6 +5,8 @@ import (
 	"net"
 	"reflect"
 	"testing"
+
+	"github.com/stretchr/testify/require"
 )
 
 func TestNetworkIndex_Overcommitted(t *testing.T) {
@@ -15,7 +17,7 @@ func TestNetworkIndex_Overcommitted(t *testing.T) {
 		Device:        "eth0",
 		IP:            "192.168.0.100",
 		MBits:         505,
-		ReservedPorts: []Port{{"one", 8000}, {"two", 9000}},
+		ReservedPorts: []Port{{Label: "one", Value: 8000}, {Label: "two", Value: 9000}},
 	}
 	collide := idx.AddReserved(reserved)
 	if collide {
@@ -96,7 +98,7 @@ func TestNetworkIndex_AddAllocs(t *testing.T) {
 								Device:        "eth0",
 								IP:            "192.168.0.100",
 								MBits:         20,
-								ReservedPorts: []Port{{"one", 8000}, {"two", 9000}},
+								ReservedPorts: []Port{{Label: "one", Value: 8000}, {Label: "two", Value: 9000}},
 							},
 						},
 					},
@@ -112,7 +114,7 @@ func TestNetworkIndex_AddAllocs(t *testing.T) {
 								Device:        "eth0",
 								IP:            "192.168.0.100",
 								MBits:         50,
-								ReservedPorts: []Port{{"one", 10000}},
+								ReservedPorts: []Port{{Label: "one", Value: 10000}},
 							},
 						},
 					},
@@ -146,7 +148,7 @@ func TestNetworkIndex_AddReserved(t *testing.T) {
 		Device:        "eth0",
 		IP:            "192.168.0.100",
 		MBits:         20,
-		ReservedPorts: []Port{{"one", 8000}, {"two", 9000}},
+		ReservedPorts: []Port{{Label: "one", Value: 8000}, {Label: "two", Value: 9000}},
 	}
 	collide := idx.AddReserved(reserved)
 	if collide {
@@ -224,7 +226,7 @@ func TestNetworkIndex_AssignNetwork(t *testing.T) {
 							Device:        "eth0",
 							IP:            "192.168.0.100",
 							MBits:         20,
-							ReservedPorts: []Port{{"one", 8000}, {"two", 9000}},
+							ReservedPorts: []Port{{Label: "one", Value: 8000}, {Label: "two", Value: 9000}},
 						},
 					},
 				},
@@ -238,7 +240,7 @@ func TestNetworkIndex_AssignNetwork(t *testing.T) {
 							Device:        "eth0",
 							IP:            "192.168.0.100",
 							MBits:         50,
-							ReservedPorts: []Port{{"main", 10000}},
+							ReservedPorts: []Port{{Label: "main", Value: 10000}},
 						},
 					},
 				},
@@ -249,7 +251,7 @@ func TestNetworkIndex_AssignNetwork(t *testing.T) {
 
 	// Ask for a reserved port
 	ask := &NetworkResource{
-		ReservedPorts: []Port{{"main", 8000}},
+		ReservedPorts: []Port{{Label: "main", Value: 8000}},
 	}
 	offer, err := idx.AssignNetwork(ask)
 	if err != nil {
@@ -261,14 +263,14 @@ func TestNetworkIndex_AssignNetwork(t *testing.T) {
 	if offer.IP != "192.168.0.101" {
 		t.Fatalf("bad: %#v", offer)
 	}
-	rp := Port{"main", 8000}
+	rp := Port{Label: "main", Value: 8000}
 	if len(offer.ReservedPorts) != 1 || offer.ReservedPorts[0] != rp {
 		t.Fatalf("bad: %#v", offer)
 	}
 
 	// Ask for dynamic ports
 	ask = &NetworkResource{
-		DynamicPorts: []Port{{"http", 0}, {"https", 0}, {"admin", 0}},
+		DynamicPorts: []Port{{Label: "http"}, {Label: "https"}, {Label: "admin"}},
 	}
 	offer, err = idx.AssignNetwork(ask)
 	if err != nil {
@@ -291,8 +293,8 @@ func TestNetworkIndex_AssignNetwork(t *testing.T) {
 
 	// Ask for reserved + dynamic ports
 	ask = &NetworkResource{
-		ReservedPorts: []Port{{"main", 2345}},
-		DynamicPorts:  []Port{{"http", 0}, {"https", 0}, {"admin", 0}},
+		ReservedPorts: []Port{{Label: "main", Value: 2345}},
+		DynamicPorts:  []Port{{Label: "http"}, {Label: "https"}, {Label: "admin"}},
 	}
 	offer, err = idx.AssignNetwork(ask)
 	if err != nil {
@@ -305,7 +307,7 @@ func TestNetworkIndex_AssignNetwork(t *testing.T) {
 		t.Fatalf("bad: %#v", offer)
 	}
 
-	rp = Port{"main", 2345}
+	rp = Port{Label: "main", Value: 2345}
 	if len(offer.ReservedPorts) != 1 || offer.ReservedPorts[0] != rp {
 		t.Fatalf("bad: %#v", offer)
 	}
@@ -350,7 +352,7 @@ func TestNetworkIndex_AssignNetwork_Dynamic_Contention(t *testing.T) {
 
 	// Ask for dynamic ports
 	ask := &NetworkResource{
-		DynamicPorts: []Port{{"http", 0}},
+		DynamicPorts: []Port{{Label: "http"}},
 	}
 	offer, err := idx.AssignNetwork(ask)
 	if err != nil {
@@ -370,6 +372,97 @@ func TestNetworkIndex_AssignNetwork_Dynamic_Contention(t *testing.T) {
 	}
 }
 
+// TestNetworkIndex_AssignNetwork_HostNetwork asserts ports bound to a host
+// network are assigned on its address and checked against its used ports.
+func TestNetworkIndex_AssignNetwork_HostNetwork(t *testing.T) {
+	require := require.New(t)
+
+	idx := NewNetworkIndex()
+	n := &Node{
+		NodeResources: &NodeResources{
+			Networks: []*NetworkResource{
+				{
+					Device: "eth0",
+					CIDR:   "192.168.0.100/32",
+					IP:     "192.168.0.100",
+					MBits:  1000,
+				},
+			},
+			HostNetworks: []*NodeHostNetwork{
+				{
+					Name:          "public",
+					Device:        "eth1",
+					IP:            "203.0.113.10",
+					ReservedPorts: "443",
+				},
+			},
+		},
+		ReservedResources: &NodeReservedResources{
+			Networks: NodeReservedNetworkResources{
+				ReservedHostPorts: "22",
+			},
+		},
+	}
+	require.False(idx.SetNode(n))
+
+	// The reserved ports of the node apply to the host networks, the reserved
+	// ports of a host network only to its address
+	require.True(idx.UsedPorts["203.0.113.10"].Check(22))
+	require.True(idx.UsedPorts["203.0.113.10"].Check(443))
+	require.False(idx.UsedPorts["192.168.0.100"].Check(443))
+
+	// Ports of allocations are used on their host network
+	require.False(idx.AddReserved(&NetworkResource{
+		Device:        "eth0",
+		IP:            "192.168.0.100",
+		ReservedPorts: []Port{{Label: "http", Value: 80, HostNetwork: "public", HostIP: "203.0.113.10"}},
+	}))
+	require.True(idx.UsedPorts["203.0.113.10"].Check(80))
+	require.False(idx.UsedPorts["192.168.0.100"].Check(80))
+
+	// A port used on the host network collides
+	ask := &NetworkResource{
+		ReservedPorts: []Port{{Label: "http", Value: 80, HostNetwork: "public"}},
+	}
+	_, err := idx.AssignNetwork(ask)
+	require.EqualError(err, "reserved port collision")
+
+	// The same port is free on the default network
+	ask = &NetworkResource{
+		ReservedPorts: []Port{{Label: "http", Value: 80}},
+	}
+	offer, err := idx.AssignNetwork(ask)
+	require.NoError(err)
+	require.Equal("192.168.0.100", offer.IP)
+	require.Empty(offer.ReservedPorts[0].HostIP)
+
+	// Reserved and dynamic ports are assigned on the address of their host
+	// network
+	ask = &NetworkResource{
+		ReservedPorts: []Port{{Label: "https", Value: 8443, HostNetwork: "public"}},
+		DynamicPorts: []Port{
+			{Label: "admin"},
+			{Label: "web", HostNetwork: "public"},
+		},
+	}
+	offer, err = idx.AssignNetwork(ask)
+	require.NoError(err)
+	require.Equal("192.168.0.100", offer.IP)
+	require.Equal("203.0.113.10", offer.ReservedPorts[0].HostIP)
+	require.Empty(offer.DynamicPorts[0].HostIP)
+	require.Equal("203.0.113.10", offer.DynamicPorts[1].HostIP)
+	for _, p := range offer.DynamicPorts {
+		require.NotZero(p.Value)
+	}
+
+	// Host networks missing on the node can't be satisfied
+	ask = &NetworkResource{
+		DynamicPorts: []Port{{Label: "admin", HostNetwork: "private"}},
+	}
+	_, err = idx.AssignNetwork(ask)
+	require.EqualError(err, `missing host network "private"`)
+}
+
 // COMPAT(0.11): Remove in 0.11
 func TestNetworkIndex_Overcommitted_Old(t *testing.T) {
 	idx := NewNetworkIndex()
@@ -379,7 +472,7 @@ func TestNetworkIndex_Overcommitted_Old(t *testing.T) {
 		Device:        "eth0",
 		IP:            "192.168.0.100",
 		MBits:         505,
-		ReservedPorts: []Port{{"one", 8000}, {"two", 9000}},
+		ReservedPorts: []Port{{Label: "one", Value: 8000}, {Label: "two", Value: 9000}},
 	}
 	collide := idx.AddReserved(reserved)
 	if collide {
@@ -431,7 +524,7 @@ func TestNetworkIndex_SetNode_Old(t *testing.T) {
 				{
 					Device:        "eth0",
 					IP:            "192.168.0.100",
-					ReservedPorts: []Port{{"ssh", 22}},
+					ReservedPorts: []Port{{Label: "ssh", Value: 22}},
 					MBits:         1,
 				},
 			},
@@ -468,7 +561,7 @@ func TestNetworkIndex_AddAllocs_Old(t *testing.T) {
 							Device:        "eth0",
 							IP:            "192.168.0.100",
 							MBits:         20,
-							ReservedPorts: []Port{{"one", 8000}, {"two", 9000}},
+							ReservedPorts: []Port{{Label: "one", Value: 8000}, {Label: "two", Value: 9000}},
 						},
 					},
 				},
@@ -482,7 +575,7 @@ func TestNetworkIndex_AddAllocs_Old(t *testing.T) {
 							Device:        "eth0",
 							IP:            "192.168.0.100",
 							MBits:         50,
-							ReservedPorts: []Port{{"one", 10000}},
+							ReservedPorts: []Port{{Label: "one", Value: 10000}},
 						},
 					},
 				},
@@ -526,7 +619,7 @@ func TestNetworkIndex_yieldIP_Old(t *testing.T) {
 				{
 					Device:        "eth0",
 					IP:            "192.168.0.100",
-					ReservedPorts: []Port{{"ssh", 22}},
+					ReservedPorts: []Port{{Label: "ssh", Value: 22}},
 					MBits:         1,
 				},
 			},
@@ -565,7 +658,7 @@ func TestNetworkIndex_AssignNetwork_Old(t *testing.T) {
 				{
 					Device:        "eth0",
 					IP:            "192.168.0.100",
-					ReservedPorts: []Port{{"ssh", 22}},
+					ReservedPorts: []Port{{Label: "ssh", Value: 22}},
 					MBits:         1,
 				},
 			},
@@ -582,7 +675,7 @@ func TestNetworkIndex_AssignNetwork_Old(t *testing.T) {
 							Device:        "eth0",
 							IP:            "192.168.0.100",
 							MBits:         20,
-							ReservedPorts: []Port{{"one", 8000}, {"two", 9000}},
+							ReservedPorts: []Port{{Label: "one", Value: 8000}, {Label: "two", Value: 9000}},
 						},
 					},
 				},
@@ -596,7 +689,7 @@ func TestNetworkIndex_AssignNetwork_Old(t *testing.T) {
 							Device:        "eth0",
 							IP:            "192.168.0.100",
 							MBits:         50,
-							ReservedPorts: []Port{{"main", 10000}},
+							ReservedPorts: []Port{{Label: "main", Value: 10000}},
 						},
 					},
 				},
@@ -607,7 +700,7 @@ func TestNetworkIndex_AssignNetwork_Old(t *testing.T) {
 
 	// Ask for a reserved port
 	ask := &NetworkResource{
-		ReservedPorts: []Port{{"main", 8000}},
+		ReservedPorts: []Port{{Label: "main", Value: 8000}},
 	}
 	offer, err := idx.AssignNetwork(ask)
 	if err != nil {
@@ -619,14 +712,14 @@ func TestNetworkIndex_AssignNetwork_Old(t *testing.T) {
 	if offer.IP != "192.168.0.101" {
 		t.Fatalf("bad: %#v", offer)
 	}
-	rp := Port{"main", 8000}
+	rp := Port{Label: "main", Value: 8000}
 	if len(offer.ReservedPorts) != 1 || offer.ReservedPorts[0] != rp {
 		t.Fatalf("bad: %#v", offer)
 	}
 
 	// Ask for dynamic ports
 	ask = &NetworkResource{
-		DynamicPorts: []Port{{"http", 0}, {"https", 0}, {"admin", 0}},
+		DynamicPorts: []Port{{Label: "http"}, {Label: "https"}, {Label: "admin"}},
 	}
 	offer, err = idx.AssignNetwork(ask)
 	if err != nil {
@@ -649,8 +742,8 @@ func TestNetworkIndex_AssignNetwork_Old(t *testing.T) {
 
 	// Ask for reserved + dynamic ports
 	ask = &NetworkResource{
-		ReservedPorts: []Port{{"main", 2345}},
-		DynamicPorts:  []Port{{"http", 0}, {"https", 0}, {"admin", 0}},
+		ReservedPorts: []Port{{Label: "main", Value: 2345}},
+		DynamicPorts:  []Port{{Label: "http"}, {Label: "https"}, {Label: "admin"}},
 	}
 	offer, err = idx.AssignNetwork(ask)
 	if err != nil {
@@ -663,7 +756,7 @@ func TestNetworkIndex_AssignNetwork_Old(t *testing.T) {
 		t.Fatalf("bad: %#v", offer)
 	}
 
-	rp = Port{"main", 2345}
+	rp = Port{Label: "main", Value: 2345}
 	if len(offer.ReservedPorts) != 1 || offer.ReservedPorts[0] != rp {
 		t.Fatalf("bad: %#v", offer)
 	}
@@ -716,7 +809,7 @@ func TestNetworkIndex_AssignNetwork_Dynamic_Contention_Old(t *testing.T) {
 
 	// Ask for dynamic ports
 	ask := &NetworkResource{
-		DynamicPorts: []Port{{"http", 0}},
+		DynamicPorts: []Port{{Label: "http"}},
 	}
 	offer, err := idx.AssignNetwork(ask)
 	if err != nil {
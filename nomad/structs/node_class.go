@@ -42,7 +42,7 @@ func (n *Node) ComputeClass() error {
 // included in the computed node class.
 func (n Node) HashInclude(field string, v interface{}) (bool, error) {
 	switch field {
-	case "Datacenter", "Attributes", "Meta", "NodeClass", "NodeResources":
+	case "Datacenter", "Attributes", "Meta", "DynamicMeta", "NodeClass", "NodeResources", "HostVolumes":
 		return true, nil
 	default:
 		return false, nil
@@ -58,8 +58,10 @@ func (n Node) HashIncludeMap(field string, k, v interface{}) (bool, error) {
 	}
 
 	switch field {
-	case "Meta", "Attributes":
+	case "Meta", "DynamicMeta", "Attributes":
 		return !IsUniqueNamespace(key), nil
+	case "HostVolumes":
+		return true, nil
 	default:
 		return false, fmt.Errorf("unexpected map field: %v", field)
 	}
@@ -126,6 +128,8 @@ func constraintTargetEscapes(target string) bool {
 		return true
 	case strings.HasPrefix(target, "${meta.unique."):
 		return true
+	case strings.HasPrefix(target, "${dynamic_meta.unique."):
+		return true
 	default:
 		return false
 	}
@@ -204,6 +204,63 @@ func TestNode_ComputedClass_Meta(t *testing.T) {
 	}
 }
 
+func TestNode_ComputedClass_DynamicMeta(t *testing.T) {
+	// Create a node and gets it computed class
+	n := testNode()
+	if err := n.ComputeClass(); err != nil {
+		t.Fatalf("ComputeClass() failed: %v", err)
+	}
+	old := n.ComputedClass
+
+	// Add a dynamic meta key and compute the class again.
+	n.DynamicMeta = map[string]string{"rack": "r1"}
+	if err := n.ComputeClass(); err != nil {
+		t.Fatalf("ComputeClass() failed: %v", err)
+	}
+	if old == n.ComputedClass {
+		t.Fatal("ComputeClass() ignored dynamic meta change")
+	}
+	old = n.ComputedClass
+
+	// Add a unique dynamic meta key and compute the class again.
+	n.DynamicMeta["unique.foo"] = "ignore"
+	if err := n.ComputeClass(); err != nil {
+		t.Fatalf("ComputeClass() failed: %v", err)
+	}
+	if old != n.ComputedClass {
+		t.Fatal("ComputeClass() didn't ignore unique dynamic meta key")
+	}
+}
+
+func TestNode_ComputedClass_HostVolumes(t *testing.T) {
+	require := require.New(t)
+
+	// Create a node and gets it computed class
+	n := testNode()
+	require.NoError(n.ComputeClass())
+	old := n.ComputedClass
+
+	// Add a host volume and compute the class again.
+	n.HostVolumes = map[string]*ClientHostVolumeConfig{
+		"data": {Name: "data", Path: "/srv/data", Healthy: true},
+	}
+	require.NoError(n.ComputeClass())
+	require.NotEqual(old, n.ComputedClass)
+	old = n.ComputedClass
+
+	// Changing the path or the health doesn't change the class
+	n.HostVolumes["data"].Path = "/srv/other"
+	n.HostVolumes["data"].Healthy = false
+	n.HostVolumes["data"].HealthDescription = "path is not writable"
+	require.NoError(n.ComputeClass())
+	require.Equal(old, n.ComputedClass)
+
+	// Making the volume read only does
+	n.HostVolumes["data"].ReadOnly = true
+	require.NoError(n.ComputeClass())
+	require.NotEqual(old, n.ComputedClass)
+}
+
 func TestNode_EscapedConstraints(t *testing.T) {
 	// Non-escaped constraints
 	ne1 := &Constraint{
@@ -0,0 +1,113 @@
+package structs
+
+import (
+	"fmt"
+	"regexp"
+
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/helper"
+)
+
+const (
+	// NodePoolDefault is the node pool that nodes and jobs that don't
+	// declare a node pool belong to.
+	NodePoolDefault = "default"
+
+	// maxNodePoolDescriptionLength limits a node pool description length
+	maxNodePoolDescriptionLength = 256
+)
+
+var (
+	// validNodePoolName is used to validate a node pool name
+	validNodePoolName = regexp.MustCompile("^[a-zA-Z0-9-_]{1,128}$")
+)
+
+// NodePool is used to partition client nodes into groups that jobs can
+// target. The scheduler only places a job's allocations on nodes in the
+// job's node pool.
+type NodePool struct {
+	// Name is the unique name of the node pool.
+	Name string
+
+	// Description is a human readable description of the node pool.
+	Description string
+
+	// Meta is used to associate arbitrary metadata with the node pool.
+	Meta map[string]string
+
+	// SchedulerAlgorithm overrides the cluster wide scheduler algorithm when
+	// placing allocations on the nodes of the pool. It is unset by default.
+	SchedulerAlgorithm SchedulerAlgorithm
+
+	// Raft Indexes
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// ValidNodePoolName returns whether the name is a valid node pool name.
+func ValidNodePoolName(name string) bool {
+	return validNodePoolName.MatchString(name)
+}
+
+// Validate is used to sanity check a node pool.
+func (n *NodePool) Validate() error {
+	var mErr multierror.Error
+	if !ValidNodePoolName(n.Name) {
+		err := fmt.Errorf("invalid name %q", n.Name)
+		mErr.Errors = append(mErr.Errors, err)
+	}
+	if len(n.Description) > maxNodePoolDescriptionLength {
+		err := fmt.Errorf("description longer than %d", maxNodePoolDescriptionLength)
+		mErr.Errors = append(mErr.Errors, err)
+	}
+	if err := n.SchedulerAlgorithm.Validate(); err != nil {
+		mErr.Errors = append(mErr.Errors, err)
+	}
+	return mErr.ErrorOrNil()
+}
+
+// Copy returns a deep copy of the node pool.
+func (n *NodePool) Copy() *NodePool {
+	if n == nil {
+		return nil
+	}
+	nn := new(NodePool)
+	*nn = *n
+	nn.Meta = helper.CopyMapStringString(nn.Meta)
+	return nn
+}
+
+// NodePoolListRequest is used to request a list of node pools.
+type NodePoolListRequest struct {
+	QueryOptions
+}
+
+// NodePoolListResponse is used for a list request.
+type NodePoolListResponse struct {
+	NodePools []*NodePool
+	QueryMeta
+}
+
+// NodePoolSpecificRequest is used to query a specific node pool.
+type NodePoolSpecificRequest struct {
+	Name string
+	QueryOptions
+}
+
+// SingleNodePoolResponse is used to return a single node pool.
+type SingleNodePoolResponse struct {
+	NodePool *NodePool
+	QueryMeta
+}
+
+// NodePoolUpsertRequest is used to upsert a set of node pools.
+type NodePoolUpsertRequest struct {
+	NodePools []*NodePool
+	WriteRequest
+}
+
+// NodePoolDeleteRequest is used to delete a set of node pools.
+type NodePoolDeleteRequest struct {
+	Names []string
+	WriteRequest
+}
@@ -0,0 +1,70 @@
+package structs
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodePool_Validate(t *testing.T) {
+	cases := []struct {
+		name  string
+		pool  *NodePool
+		valid bool
+	}{
+		{
+			name:  "valid",
+			pool:  &NodePool{Name: "gpu_large-1", Description: "nodes with GPUs"},
+			valid: true,
+		},
+		{
+			name: "missing name",
+			pool: &NodePool{},
+		},
+		{
+			name: "invalid name",
+			pool: &NodePool{Name: "gpu/large"},
+		},
+		{
+			name: "long description",
+			pool: &NodePool{Name: "gpu", Description: strings.Repeat("a", maxNodePoolDescriptionLength+1)},
+		},
+		{
+			name:  "spread algorithm",
+			pool:  &NodePool{Name: "gpu", SchedulerAlgorithm: SchedulerAlgorithmSpread},
+			valid: true,
+		},
+		{
+			name: "invalid algorithm",
+			pool: &NodePool{Name: "gpu", SchedulerAlgorithm: "random"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := c.pool.Validate()
+			if c.valid {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestNodePool_Canonicalize(t *testing.T) {
+	require := require.New(t)
+
+	node := &Node{}
+	node.Canonicalize()
+	require.Equal(NodePoolDefault, node.NodePool)
+
+	job := &Job{}
+	job.Canonicalize()
+	require.Equal(NodePoolDefault, job.NodePool)
+
+	job = &Job{NodePool: "gpu"}
+	job.Canonicalize()
+	require.Equal("gpu", job.NodePool)
+}
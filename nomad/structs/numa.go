@@ -0,0 +1,207 @@
+package structs
+
+import (
+	"fmt"
+	"sort"
+)
+
+const (
+	// NUMAAffinityNone places the task without regard to the NUMA topology
+	// of the node.
+	NUMAAffinityNone = "none"
+
+	// NUMAAffinityRequire requires the CPU and memory of the task to be
+	// provided by a single NUMA node.
+	NUMAAffinityRequire = "require"
+)
+
+// NUMA is the NUMA placement requested by a task.
+type NUMA struct {
+	// Affinity is one of "none" or "require".
+	Affinity string
+}
+
+// Copy returns a copy of the NUMA request.
+func (n *NUMA) Copy() *NUMA {
+	if n == nil {
+		return nil
+	}
+	nn := *n
+	return &nn
+}
+
+// Validate is used to sanity check the NUMA request.
+func (n *NUMA) Validate() error {
+	if n == nil {
+		return nil
+	}
+	switch n.Affinity {
+	case "", NUMAAffinityNone, NUMAAffinityRequire:
+		return nil
+	default:
+		return fmt.Errorf("invalid NUMA affinity %q, must be one of %q or %q", n.Affinity, NUMAAffinityNone, NUMAAffinityRequire)
+	}
+}
+
+// Required returns whether the task must be placed on a single NUMA node.
+func (n *NUMA) Required() bool {
+	return n != nil && n.Affinity == NUMAAffinityRequire
+}
+
+// NodeNumaNode describes the CPU and memory provided by a single NUMA node of
+// a client.
+type NodeNumaNode struct {
+	// ID is the ID of the NUMA node as reported by the operating system
+	ID int
+
+	// Cores is the set of CPU cores of the NUMA node in cpuset list format,
+	// such as "0-7,16-23"
+	Cores string
+
+	// CpuShares is the CPU shares provided by the cores of the NUMA node
+	CpuShares int64
+
+	// MemoryMB is the memory local to the NUMA node
+	MemoryMB int64
+}
+
+// Copy returns a copy of the NUMA node.
+func (n *NodeNumaNode) Copy() *NodeNumaNode {
+	if n == nil {
+		return nil
+	}
+	nn := *n
+	return &nn
+}
+
+// NumaNodesEquals returns true if the two sets of NUMA nodes are equal.
+func NumaNodesEquals(n1, n2 []*NodeNumaNode) bool {
+	if len(n1) != len(n2) {
+		return false
+	}
+	for i, n := range n1 {
+		if *n != *n2[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// AllocatedNumaResources captures the NUMA node a task is bound to.
+type AllocatedNumaResources struct {
+	// NodeID is the ID of the NUMA node providing the CPU and memory of the
+	// task
+	NodeID int
+}
+
+// Copy returns a copy of the allocated NUMA resources.
+func (a *AllocatedNumaResources) Copy() *AllocatedNumaResources {
+	if a == nil {
+		return nil
+	}
+	na := *a
+	return &na
+}
+
+// NumaAccounter is used to account for the CPU and memory of the tasks bound
+// to the NUMA nodes of a node. Only tasks requiring NUMA placement are
+// accounted so they can not oversubscribe a single NUMA node.
+type NumaAccounter struct {
+	// Nodes maps the NUMA node ID to its capacity and usage
+	Nodes map[int]*NumaAccounterNode
+}
+
+// NumaAccounterNode tracks the usage of a NUMA node.
+type NumaAccounterNode struct {
+	Node *NodeNumaNode
+
+	// CpuShares and MemoryMB are the resources used by the tasks bound to
+	// the NUMA node
+	CpuShares int64
+	MemoryMB  int64
+}
+
+// NewNumaAccounter returns a new NUMA accounter for the NUMA nodes of the
+// node.
+func NewNumaAccounter(n *Node) *NumaAccounter {
+	a := &NumaAccounter{
+		Nodes: make(map[int]*NumaAccounterNode),
+	}
+
+	// COMPAT(0.11): Remove in 0.11
+	if n.NodeResources == nil {
+		return a
+	}
+
+	for _, numaNode := range n.NodeResources.NumaNodes {
+		a.Nodes[numaNode.ID] = &NumaAccounterNode{Node: numaNode}
+	}
+	return a
+}
+
+// AddAllocs marks the resources of the tasks bound to NUMA nodes as used and
+// returns whether any NUMA node is oversubscribed.
+func (a *NumaAccounter) AddAllocs(allocs []*Allocation) (oversubscribed bool) {
+	for _, alloc := range allocs {
+		if alloc.TerminalStatus() || alloc.AllocatedResources == nil {
+			continue
+		}
+
+		for _, tr := range alloc.AllocatedResources.Tasks {
+			if a.AddReserved(tr) {
+				oversubscribed = true
+			}
+		}
+	}
+	return oversubscribed
+}
+
+// AddReserved marks the resources of the task as used if it is bound to a
+// NUMA node and returns whether the NUMA node is oversubscribed. A task bound
+// to an unknown NUMA node oversubscribes it.
+func (a *NumaAccounter) AddReserved(tr *AllocatedTaskResources) (oversubscribed bool) {
+	if tr == nil || tr.Numa == nil {
+		return false
+	}
+
+	node, ok := a.Nodes[tr.Numa.NodeID]
+	if !ok {
+		return true
+	}
+
+	node.CpuShares += tr.Cpu.CpuShares
+	node.MemoryMB += tr.Memory.MemoryMB
+	return node.CpuShares > node.Node.CpuShares || node.MemoryMB > node.Node.MemoryMB
+}
+
+// Assign returns the NUMA node that fits the given CPU and memory. Among the
+// fitting NUMA nodes, the one with the least free memory is chosen so that
+// larger tasks may still fit on the remaining NUMA nodes. If no NUMA node
+// fits, an error is returned explaining why.
+func (a *NumaAccounter) Assign(cpuShares, memoryMB int64) (*AllocatedNumaResources, error) {
+	if len(a.Nodes) == 0 {
+		return nil, fmt.Errorf("no NUMA topology")
+	}
+
+	ids := make([]int, 0, len(a.Nodes))
+	for id := range a.Nodes {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	var best *NumaAccounterNode
+	for _, id := range ids {
+		node := a.Nodes[id]
+		if node.CpuShares+cpuShares > node.Node.CpuShares || node.MemoryMB+memoryMB > node.Node.MemoryMB {
+			continue
+		}
+		if best == nil || node.Node.MemoryMB-node.MemoryMB < best.Node.MemoryMB-best.MemoryMB {
+			best = node
+		}
+	}
+
+	if best == nil {
+		return nil, fmt.Errorf("no NUMA node fits the task")
+	}
+	return &AllocatedNumaResources{NodeID: best.Node.ID}, nil
+}
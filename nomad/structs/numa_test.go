@@ -0,0 +1,107 @@
+package structs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func mockNumaNode() *Node {
+	n := MockNode()
+	n.NodeResources.NumaNodes = []*NodeNumaNode{
+		{
+			ID:        0,
+			Cores:     "0-1",
+			CpuShares: 2000,
+			MemoryMB:  4096,
+		},
+		{
+			ID:        1,
+			Cores:     "2-3",
+			CpuShares: 2000,
+			MemoryMB:  2048,
+		},
+	}
+	return n
+}
+
+func numaAlloc(nodeID int, cpu, mem int64) *Allocation {
+	return &Allocation{
+		AllocatedResources: &AllocatedResources{
+			Tasks: map[string]*AllocatedTaskResources{
+				"web": {
+					Cpu:    AllocatedCpuResources{CpuShares: cpu},
+					Memory: AllocatedMemoryResources{MemoryMB: mem},
+					Numa:   &AllocatedNumaResources{NodeID: nodeID},
+				},
+			},
+		},
+	}
+}
+
+func TestNUMA_Validate(t *testing.T) {
+	require := require.New(t)
+
+	var n *NUMA
+	require.NoError(n.Validate())
+	require.False(n.Required())
+
+	require.NoError((&NUMA{Affinity: NUMAAffinityNone}).Validate())
+	require.NoError((&NUMA{Affinity: NUMAAffinityRequire}).Validate())
+	require.True((&NUMA{Affinity: NUMAAffinityRequire}).Required())
+
+	err := (&NUMA{Affinity: "prefer"}).Validate()
+	require.Error(err)
+	require.Contains(err.Error(), "invalid NUMA affinity")
+}
+
+func TestNumaAccounter_Assign(t *testing.T) {
+	require := require.New(t)
+
+	a := NewNumaAccounter(mockNumaNode())
+
+	// The NUMA node with the least free memory that fits is chosen
+	numa, err := a.Assign(1000, 1024)
+	require.NoError(err)
+	require.Equal(1, numa.NodeID)
+
+	// Only the larger NUMA node fits
+	numa, err = a.Assign(1000, 3072)
+	require.NoError(err)
+	require.Equal(0, numa.NodeID)
+
+	// No NUMA node has enough CPU
+	_, err = a.Assign(3000, 1024)
+	require.Error(err)
+	require.Contains(err.Error(), "no NUMA node fits")
+
+	// Nodes without a NUMA topology can not host NUMA bound tasks
+	_, err = NewNumaAccounter(MockNode()).Assign(100, 100)
+	require.Error(err)
+	require.Contains(err.Error(), "no NUMA topology")
+}
+
+func TestNumaAccounter_AddAllocs(t *testing.T) {
+	require := require.New(t)
+
+	a := NewNumaAccounter(mockNumaNode())
+	require.False(a.AddAllocs([]*Allocation{numaAlloc(1, 1000, 1024)}))
+	require.Equal(int64(1000), a.Nodes[1].CpuShares)
+	require.Equal(int64(1024), a.Nodes[1].MemoryMB)
+
+	// NUMA node 1 no longer fits the task
+	numa, err := a.Assign(1000, 2048)
+	require.NoError(err)
+	require.Equal(0, numa.NodeID)
+
+	// Terminal allocations are not accounted
+	terminal := numaAlloc(1, 1000, 1024)
+	terminal.DesiredStatus = AllocDesiredStatusStop
+	require.False(a.AddAllocs([]*Allocation{terminal}))
+
+	// Oversubscribing a NUMA node is detected
+	require.True(a.AddAllocs([]*Allocation{numaAlloc(1, 1000, 1025)}))
+
+	// Binding to an unknown NUMA node oversubscribes it
+	require.True(NewNumaAccounter(mockNumaNode()).AddAllocs([]*Allocation{numaAlloc(2, 1, 1)}))
+}
@@ -1,6 +1,7 @@
 package structs
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/hashicorp/raft"
@@ -103,7 +104,11 @@ type AutopilotConfig struct {
 	// be behind before being considered unhealthy.
 	MaxTrailingLogs uint64
 
-	// (Enterprise-only) EnableRedundancyZones specifies whether to enable redundancy zones.
+	// MinQuorum is the minimum number of alive servers the cluster must have
+	// for dead servers to be removed. Zero disables the minimum.
+	MinQuorum uint
+
+	// EnableRedundancyZones specifies whether to enable redundancy zones.
 	EnableRedundancyZones bool
 
 	// (Enterprise-only) DisableUpgradeMigration will disable Autopilot's upgrade migration
@@ -120,17 +125,73 @@ type AutopilotConfig struct {
 	ModifyIndex uint64
 }
 
+// SchedulerAlgorithm is an enum string that encapsulates the valid options
+// for the algorithm used to score nodes for placement.
+type SchedulerAlgorithm string
+
+const (
+	// SchedulerAlgorithmBinpack scores nodes so that allocations are packed
+	// onto as few nodes as possible.
+	SchedulerAlgorithmBinpack SchedulerAlgorithm = "binpack"
+
+	// SchedulerAlgorithmSpread scores nodes so that allocations are spread
+	// across as many nodes as possible.
+	SchedulerAlgorithmSpread SchedulerAlgorithm = "spread"
+)
+
+// Validate returns an error if the scheduler algorithm is not known. The
+// empty algorithm is valid and means the default should be used.
+func (a SchedulerAlgorithm) Validate() error {
+	switch a {
+	case "", SchedulerAlgorithmBinpack, SchedulerAlgorithmSpread:
+		return nil
+	default:
+		return fmt.Errorf("invalid scheduler algorithm %q, must be one of %q or %q",
+			a, SchedulerAlgorithmBinpack, SchedulerAlgorithmSpread)
+	}
+}
+
 // SchedulerConfiguration is the config for controlling scheduler behavior
 type SchedulerConfiguration struct {
+	// SchedulerAlgorithm lets you select between available scheduling
+	// algorithms. Node pools may override the algorithm for their nodes.
+	SchedulerAlgorithm SchedulerAlgorithm
+
 	// PreemptionConfig specifies whether to enable eviction of lower
 	// priority jobs to place higher priority jobs.
 	PreemptionConfig PreemptionConfig
 
+	// NumSchedulers overrides the number of scheduling workers run by each
+	// server. Zero keeps the num_schedulers of each server's configuration.
+	// Servers configured to not run schedulers are not affected.
+	NumSchedulers int
+
+	// DisableEvalPriority dequeues ready evaluations in the order they were
+	// created instead of by the priority of their job.
+	DisableEvalPriority bool
+
 	// CreateIndex/ModifyIndex store the create/modify indexes of this configuration.
 	CreateIndex uint64
 	ModifyIndex uint64
 }
 
+// EffectiveSchedulerAlgorithm returns the scheduler algorithm to use,
+// defaulting to bin packing when none is set.
+func (s *SchedulerConfiguration) EffectiveSchedulerAlgorithm() SchedulerAlgorithm {
+	if s == nil || s.SchedulerAlgorithm == "" {
+		return SchedulerAlgorithmBinpack
+	}
+	return s.SchedulerAlgorithm
+}
+
+// Validate is used to sanity check the scheduler configuration.
+func (s *SchedulerConfiguration) Validate() error {
+	if s.NumSchedulers < 0 {
+		return fmt.Errorf("invalid num schedulers %d, must not be negative", s.NumSchedulers)
+	}
+	return s.SchedulerAlgorithm.Validate()
+}
+
 // SchedulerConfigurationResponse is the response object that wraps SchedulerConfiguration
 type SchedulerConfigurationResponse struct {
 	// SchedulerConfig contains scheduler config options
@@ -153,6 +214,12 @@ type SchedulerSetConfigurationResponse struct {
 type PreemptionConfig struct {
 	// SystemSchedulerEnabled specifies if preemption is enabled for system jobs
 	SystemSchedulerEnabled bool
+
+	// ServiceSchedulerEnabled specifies if preemption is enabled for service jobs
+	ServiceSchedulerEnabled bool
+
+	// BatchSchedulerEnabled specifies if preemption is enabled for batch jobs
+	BatchSchedulerEnabled bool
 }
 
 // SchedulerSetConfigRequest is used by the Operator endpoint to update the
@@ -167,3 +234,77 @@ type SchedulerSetConfigRequest struct {
 	// WriteRequest holds the ACL token to go along with this request.
 	WriteRequest
 }
+
+// SnapshotSaveRequest is used by the Operator endpoint to take a snapshot of
+// the state of the cluster.
+type SnapshotSaveRequest struct {
+	QueryOptions
+}
+
+// SnapshotSaveResponse is the header of the stream of a snapshot. It is
+// followed by the snapshot archive unless ErrorCode is set.
+type SnapshotSaveResponse struct {
+	// SnapshotChecksum is the checksum of the snapshot archive, formatted as
+	// a digest value.
+	SnapshotChecksum string
+
+	// ErrorCode and ErrorMsg are set if taking the snapshot failed.
+	ErrorCode int    `codec:",omitempty"`
+	ErrorMsg  string `codec:",omitempty"`
+
+	QueryMeta
+}
+
+// SnapshotRestoreRequest is used by the Operator endpoint to restore a
+// snapshot of the state of the cluster. It is followed by the snapshot
+// archive, streamed in chunks.
+type SnapshotRestoreRequest struct {
+	WriteRequest
+}
+
+// SnapshotRestoreResponse is the result of restoring a snapshot.
+type SnapshotRestoreResponse struct {
+	// ErrorCode and ErrorMsg are set if restoring the snapshot failed.
+	ErrorCode int    `codec:",omitempty"`
+	ErrorMsg  string `codec:",omitempty"`
+
+	QueryMeta
+}
+
+// EvalBrokerStats is the state of the eval broker of the leader, along with
+// the tunables it was configured with.
+type EvalBrokerStats struct {
+	TotalReady   int
+	TotalUnacked int
+	TotalBlocked int
+	TotalWaiting int
+	ByScheduler  map[string]*EvalBrokerSchedulerStats
+
+	// NackTimeout is how long a scheduler may work on an evaluation before
+	// it is Nacked
+	NackTimeout time.Duration
+
+	// DeliveryLimit is the number of deliveries of an evaluation before it
+	// is failed
+	DeliveryLimit int
+
+	// InitialNackReenqueueDelay and SubsequentNackReenqueueDelay are the
+	// delays before re-enqueuing Nacked evaluations
+	InitialNackReenqueueDelay    time.Duration
+	SubsequentNackReenqueueDelay time.Duration
+}
+
+// EvalBrokerSchedulerStats is the state of the queue of a scheduler in the
+// eval broker.
+type EvalBrokerSchedulerStats struct {
+	Ready           int
+	Unacked         int
+	Nacked          int
+	OldestReadyWait time.Duration
+}
+
+// EvalBrokerStatsResponse is used to return the eval broker stats
+type EvalBrokerStatsResponse struct {
+	Stats *EvalBrokerStats
+	QueryMeta
+}
@@ -0,0 +1,307 @@
+package structs
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/helper"
+	"golang.org/x/crypto/blake2b"
+)
+
+const (
+	// maxQuotaDescriptionLength limits a quota specification description
+	// length
+	maxQuotaDescriptionLength = 256
+)
+
+// QuotaSpec specifies the allowed resource usage of the namespaces that
+// reference it. Limits are specified per region.
+type QuotaSpec struct {
+	// Name is the name for the quota object
+	Name string
+
+	// Description is an optional description for the quota object
+	Description string
+
+	// Limits is the set of quota limits encapsulated by this quota object.
+	// Each limit applies quota in a particular region.
+	Limits []*QuotaLimit
+
+	// Raft indexes to track creation and modification
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// Validate is used to sanity check a quota specification.
+func (q *QuotaSpec) Validate() error {
+	var mErr multierror.Error
+	if !validNamespaceName.MatchString(q.Name) {
+		err := fmt.Errorf("invalid name %q. Must match regex %s", q.Name, validNamespaceName)
+		mErr.Errors = append(mErr.Errors, err)
+	}
+	if len(q.Description) > maxQuotaDescriptionLength {
+		err := fmt.Errorf("description longer than %d", maxQuotaDescriptionLength)
+		mErr.Errors = append(mErr.Errors, err)
+	}
+	if len(q.Limits) == 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("must provide at least one quota limit"))
+	}
+
+	regions := make(map[string]struct{}, len(q.Limits))
+	for idx, limit := range q.Limits {
+		if err := limit.Validate(); err != nil {
+			mErr.Errors = append(mErr.Errors, multierror.Prefix(err, fmt.Sprintf("limit %d:", idx)))
+			continue
+		}
+		if _, ok := regions[limit.Region]; ok {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("limit %d: region %q limited more than once", idx, limit.Region))
+		}
+		regions[limit.Region] = struct{}{}
+	}
+	return mErr.ErrorOrNil()
+}
+
+// SetHash sets the hash of all the quota limits of the specification.
+func (q *QuotaSpec) SetHash() {
+	for _, limit := range q.Limits {
+		limit.SetHash()
+	}
+}
+
+// RegionLimit returns the quota limit for the given region or nil if the
+// region is not limited.
+func (q *QuotaSpec) RegionLimit(region string) *QuotaLimit {
+	for _, limit := range q.Limits {
+		if limit.Region == region {
+			return limit
+		}
+	}
+	return nil
+}
+
+// Copy returns a deep copy of the quota specification.
+func (q *QuotaSpec) Copy() *QuotaSpec {
+	if q == nil {
+		return nil
+	}
+	nq := new(QuotaSpec)
+	*nq = *q
+	if q.Limits != nil {
+		nq.Limits = make([]*QuotaLimit, len(q.Limits))
+		for i, limit := range q.Limits {
+			nq.Limits[i] = limit.Copy()
+		}
+	}
+	return nq
+}
+
+// QuotaLimit describes the resource limit in a particular region. When used
+// to report usage, the fields hold the resources in use instead.
+type QuotaLimit struct {
+	// Region is the region in which this limit has affect
+	Region string
+
+	// RegionLimit is the quota limit that applies to any allocation within a
+	// referencing namespace in the region. A value of zero is treated as
+	// unlimited and a negative value is treated as fully disallowed. Only
+	// CPU, MemoryMB and DiskMB are accounted.
+	RegionLimit *Resources
+
+	// AllocLimit limits the number of non-terminal allocations within the
+	// referencing namespaces in the region. Zero is treated as unlimited and
+	// a negative value is treated as fully disallowed.
+	AllocLimit int
+
+	// Hash is the hash of the object and is used to match usages to limits.
+	Hash []byte
+}
+
+// Validate is used to sanity check a quota limit.
+func (q *QuotaLimit) Validate() error {
+	var mErr multierror.Error
+	if q.Region == "" {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("missing region"))
+	}
+	if q.RegionLimit == nil {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("missing region limit"))
+	} else if len(q.RegionLimit.Networks) != 0 || len(q.RegionLimit.Devices) != 0 || q.RegionLimit.IOPS != 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("region limit may only limit cpu, memory and disk"))
+	}
+	return mErr.ErrorOrNil()
+}
+
+// SetHash is used to compute and set the hash of the quota limit.
+func (q *QuotaLimit) SetHash() []byte {
+	// Initialize a 256bit Blake2 hash (32 bytes)
+	hash, err := blake2b.New256(nil)
+	if err != nil {
+		panic(err)
+	}
+
+	// Write all the user set fields
+	hash.Write([]byte(q.Region))
+	if q.RegionLimit != nil {
+		binary.Write(hash, binary.LittleEndian, int64(q.RegionLimit.CPU))
+		binary.Write(hash, binary.LittleEndian, int64(q.RegionLimit.MemoryMB))
+		binary.Write(hash, binary.LittleEndian, int64(q.RegionLimit.DiskMB))
+	}
+	binary.Write(hash, binary.LittleEndian, int64(q.AllocLimit))
+
+	// Finalize the hash
+	hashVal := hash.Sum(nil)
+
+	// Set and return the hash
+	q.Hash = hashVal
+	return hashVal
+}
+
+// HashKey returns the key the usage of the limit is stored under in a quota
+// usage.
+func (q *QuotaLimit) HashKey() string {
+	return base64.StdEncoding.EncodeToString(q.Hash)
+}
+
+// Copy returns a deep copy of the quota limit.
+func (q *QuotaLimit) Copy() *QuotaLimit {
+	if q == nil {
+		return nil
+	}
+	nq := new(QuotaLimit)
+	*nq = *q
+	nq.RegionLimit = q.RegionLimit.Copy()
+	if q.Hash != nil {
+		nq.Hash = make([]byte, len(q.Hash))
+		copy(nq.Hash, q.Hash)
+	}
+	return nq
+}
+
+// AddAlloc adds the resources of the allocation to the used quota limit.
+func (q *QuotaLimit) AddAlloc(alloc *Allocation) {
+	q.add(alloc.ComparableResources(), 1)
+}
+
+// SubtractAlloc removes the resources of the allocation from the used quota
+// limit.
+func (q *QuotaLimit) SubtractAlloc(alloc *Allocation) {
+	q.add(alloc.ComparableResources(), -1)
+}
+
+// AddResources adds the given resources as an additional allocation to the
+// used quota limit.
+func (q *QuotaLimit) AddResources(resources *ComparableResources) {
+	q.add(resources, 1)
+}
+
+func (q *QuotaLimit) add(resources *ComparableResources, sign int) {
+	if q.RegionLimit == nil {
+		q.RegionLimit = &Resources{}
+	}
+	q.RegionLimit.CPU += sign * int(resources.Flattened.Cpu.CpuShares)
+	q.RegionLimit.MemoryMB += sign * int(resources.Flattened.Memory.MemoryMB)
+	q.RegionLimit.DiskMB += sign * int(resources.Shared.DiskMB)
+	q.AllocLimit += sign
+}
+
+// Exceeded returns the dimensions of the limit that the used quota limit
+// exceeds. An empty result means the usage is within the limit.
+func (q *QuotaLimit) Exceeded(used *QuotaLimit) []string {
+	var exceeded []string
+	check := func(dimension string, limit, used int) {
+		if limit == 0 || (limit > 0 && used <= limit) || (limit < 0 && used <= 0) {
+			return
+		}
+		exceeded = append(exceeded, fmt.Sprintf("%s exhausted (%d needed > %d limit)", dimension, used, helper.IntMax(limit, 0)))
+	}
+
+	var usedResources Resources
+	if used.RegionLimit != nil {
+		usedResources = *used.RegionLimit
+	}
+	if q.RegionLimit != nil {
+		check("cpu", q.RegionLimit.CPU, usedResources.CPU)
+		check("memory", q.RegionLimit.MemoryMB, usedResources.MemoryMB)
+		check("disk", q.RegionLimit.DiskMB, usedResources.DiskMB)
+	}
+	check("allocs", q.AllocLimit, used.AllocLimit)
+	return exceeded
+}
+
+// QuotaUsage is the resource usage of a quota specification. Used is keyed by
+// the HashKey of the quota limit it reports usage for.
+type QuotaUsage struct {
+	Name        string
+	Used        map[string]*QuotaLimit
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// NewQuotaUsage returns an empty usage for the quota limit of the given
+// region.
+func NewQuotaUsage(spec *QuotaSpec, region string) *QuotaUsage {
+	usage := &QuotaUsage{
+		Name:        spec.Name,
+		Used:        make(map[string]*QuotaLimit, 1),
+		CreateIndex: spec.CreateIndex,
+		ModifyIndex: spec.ModifyIndex,
+	}
+	if limit := spec.RegionLimit(region); limit != nil {
+		usage.Used[limit.HashKey()] = &QuotaLimit{
+			Region:      region,
+			RegionLimit: &Resources{},
+			Hash:        limit.Hash,
+		}
+	}
+	return usage
+}
+
+// QuotaSpecListRequest is used to request a list of quota specifications or
+// their usages.
+type QuotaSpecListRequest struct {
+	QueryOptions
+}
+
+// QuotaSpecListResponse is used for a list request.
+type QuotaSpecListResponse struct {
+	Quotas []*QuotaSpec
+	QueryMeta
+}
+
+// QuotaUsageListResponse is used for a quota usage list request.
+type QuotaUsageListResponse struct {
+	Usages []*QuotaUsage
+	QueryMeta
+}
+
+// QuotaSpecSpecificRequest is used to query a specific quota specification
+// or its usage.
+type QuotaSpecSpecificRequest struct {
+	Name string
+	QueryOptions
+}
+
+// SingleQuotaSpecResponse is used to return a single quota specification.
+type SingleQuotaSpecResponse struct {
+	Quota *QuotaSpec
+	QueryMeta
+}
+
+// SingleQuotaUsageResponse is used to return a single quota usage.
+type SingleQuotaUsageResponse struct {
+	Usage *QuotaUsage
+	QueryMeta
+}
+
+// QuotaSpecUpsertRequest is used to upsert a set of quota specifications.
+type QuotaSpecUpsertRequest struct {
+	Quotas []*QuotaSpec
+	WriteRequest
+}
+
+// QuotaSpecDeleteRequest is used to delete a set of quota specifications.
+type QuotaSpecDeleteRequest struct {
+	Names []string
+	WriteRequest
+}
@@ -0,0 +1,127 @@
+package structs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuotaSpec_Validate(t *testing.T) {
+	cases := []struct {
+		name  string
+		spec  *QuotaSpec
+		valid bool
+	}{
+		{
+			name: "valid",
+			spec: &QuotaSpec{
+				Name: "team-a",
+				Limits: []*QuotaLimit{
+					{Region: "global", RegionLimit: &Resources{CPU: 2000, MemoryMB: 1024}},
+					{Region: "europe", RegionLimit: &Resources{DiskMB: 1000}, AllocLimit: 10},
+				},
+			},
+			valid: true,
+		},
+		{
+			name: "invalid name",
+			spec: &QuotaSpec{
+				Name:   "team/a",
+				Limits: []*QuotaLimit{{Region: "global", RegionLimit: &Resources{CPU: 100}}},
+			},
+		},
+		{
+			name: "no limits",
+			spec: &QuotaSpec{Name: "team-a"},
+		},
+		{
+			name: "missing region limit",
+			spec: &QuotaSpec{
+				Name:   "team-a",
+				Limits: []*QuotaLimit{{Region: "global"}},
+			},
+		},
+		{
+			name: "network limit",
+			spec: &QuotaSpec{
+				Name: "team-a",
+				Limits: []*QuotaLimit{{
+					Region:      "global",
+					RegionLimit: &Resources{Networks: []*NetworkResource{{MBits: 100}}},
+				}},
+			},
+		},
+		{
+			name: "duplicate region",
+			spec: &QuotaSpec{
+				Name: "team-a",
+				Limits: []*QuotaLimit{
+					{Region: "global", RegionLimit: &Resources{CPU: 100}},
+					{Region: "global", RegionLimit: &Resources{CPU: 200}},
+				},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := c.spec.Validate()
+			if c.valid {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestQuotaLimit_SetHash(t *testing.T) {
+	require := require.New(t)
+	l1 := &QuotaLimit{Region: "global", RegionLimit: &Resources{CPU: 100}}
+	l2 := &QuotaLimit{Region: "global", RegionLimit: &Resources{CPU: 100}}
+	require.Equal(l1.SetHash(), l2.SetHash())
+	require.Equal(l1.HashKey(), l2.HashKey())
+
+	l2.AllocLimit = 5
+	require.NotEqual(l1.Hash, l2.SetHash())
+}
+
+func TestQuotaLimit_Exceeded(t *testing.T) {
+	require := require.New(t)
+	limit := &QuotaLimit{
+		Region:      "global",
+		RegionLimit: &Resources{CPU: 1000, MemoryMB: 0, DiskMB: -1},
+		AllocLimit:  2,
+	}
+
+	used := &QuotaLimit{RegionLimit: &Resources{}}
+	require.Empty(limit.Exceeded(used))
+
+	// Unlimited memory never exceeds while disallowed disk always exceeds
+	alloc := &Allocation{
+		AllocatedResources: &AllocatedResources{
+			Tasks: map[string]*AllocatedTaskResources{
+				"web": {
+					Cpu:    AllocatedCpuResources{CpuShares: 600},
+					Memory: AllocatedMemoryResources{MemoryMB: 4096},
+				},
+			},
+			Shared: AllocatedSharedResources{DiskMB: 10},
+		},
+	}
+	used.AddAlloc(alloc)
+	require.Equal([]string{"disk exhausted (10 needed > 0 limit)"}, limit.Exceeded(used))
+
+	used.AddAlloc(alloc)
+	used.AddAlloc(alloc)
+	require.Equal([]string{
+		"cpu exhausted (1800 needed > 1000 limit)",
+		"disk exhausted (30 needed > 0 limit)",
+		"allocs exhausted (3 needed > 2 limit)",
+	}, limit.Exceeded(used))
+
+	used.SubtractAlloc(alloc)
+	used.SubtractAlloc(alloc)
+	used.SubtractAlloc(alloc)
+	require.Empty(limit.Exceeded(used))
+}
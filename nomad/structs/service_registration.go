@@ -0,0 +1,217 @@
+package structs
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/nomad/helper"
+)
+
+const (
+	// ServiceHealthPassing is the health of a service whose task is running
+	// and whose allocation isn't unhealthy.
+	ServiceHealthPassing = "passing"
+
+	// ServiceHealthCritical is the health of a service whose task isn't
+	// running or whose allocation is unhealthy.
+	ServiceHealthCritical = "critical"
+)
+
+// ServiceRegistration is the registration of an instance of a service using
+// the nomad provider. Registrations are created by the clients when the task
+// of the service starts and deleted when it stops.
+type ServiceRegistration struct {
+	// ID is the unique ID of the registration, derived from the allocation,
+	// task and service.
+	ID string
+
+	// ServiceName is the name of the service.
+	ServiceName string
+
+	// Namespace is the namespace of the job of the service.
+	Namespace string
+
+	// NodeID and Datacenter are the node the service runs on and its
+	// datacenter.
+	NodeID     string
+	Datacenter string
+
+	// JobID, AllocID and Task identify the task registering the service.
+	JobID   string
+	AllocID string
+	Task    string
+
+	// Tags are the tags of the service.
+	Tags []string
+
+	// Address and Port are where the service can be reached.
+	Address string
+	Port    int
+
+	// Health is the health of the service. It isn't stored but derived from
+	// the state of the allocation of the service when the registration is
+	// read.
+	Health string
+
+	// Raft Indexes
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// ServiceRegistrationID returns the ID of the registration of the service of
+// the task of an allocation.
+func ServiceRegistrationID(allocID, task string, service *Service) string {
+	return fmt.Sprintf("_nomad-task-%s-%s-%s-%s", allocID, task, service.Name, service.PortLabel)
+}
+
+// Copy returns a deep copy of the service registration.
+func (s *ServiceRegistration) Copy() *ServiceRegistration {
+	if s == nil {
+		return nil
+	}
+	ns := new(ServiceRegistration)
+	*ns = *s
+	ns.Tags = helper.CopySliceString(s.Tags)
+	return ns
+}
+
+// Validate is used to sanity check a service registration.
+func (s *ServiceRegistration) Validate() error {
+	switch {
+	case s.ID == "":
+		return fmt.Errorf("missing registration ID")
+	case s.ServiceName == "":
+		return fmt.Errorf("missing service name")
+	case s.AllocID == "":
+		return fmt.Errorf("missing allocation ID")
+	case s.Task == "":
+		return fmt.Errorf("missing task name")
+	}
+	return nil
+}
+
+// SetHealth sets the health of the registration from the state of its task
+// in the allocation, which may be nil if it doesn't exist anymore.
+func (s *ServiceRegistration) SetHealth(alloc *Allocation) {
+	s.Health = ServiceHealthCritical
+	if alloc == nil || alloc.ClientTerminalStatus() {
+		return
+	}
+	if alloc.DeploymentStatus.IsUnhealthy() {
+		return
+	}
+	if state := alloc.TaskStates[s.Task]; state != nil && state.State == TaskStateRunning {
+		s.Health = ServiceHealthPassing
+	}
+}
+
+// ServiceRegistrationListStub is the summary of the registrations of a
+// service, returned when listing services.
+type ServiceRegistrationListStub struct {
+	ServiceName string
+	Namespace   string
+
+	// Tags is the union of the tags of the instances of the service.
+	Tags []string
+
+	// Instances is the number of registered instances of the service, and
+	// Healthy the number of those that are passing.
+	Instances int
+	Healthy   int
+}
+
+// NewServiceRegistrationListStubs summarizes the registrations by namespace
+// and service name. The stubs are sorted by namespace and name.
+func NewServiceRegistrationListStubs(regs []*ServiceRegistration) []*ServiceRegistrationListStub {
+	type key struct{ namespace, name string }
+	stubs := make(map[key]*ServiceRegistrationListStub)
+	tags := make(map[key]map[string]bool)
+	var out []*ServiceRegistrationListStub
+	for _, reg := range regs {
+		k := key{reg.Namespace, reg.ServiceName}
+		stub, ok := stubs[k]
+		if !ok {
+			stub = &ServiceRegistrationListStub{
+				ServiceName: reg.ServiceName,
+				Namespace:   reg.Namespace,
+			}
+			stubs[k] = stub
+			tags[k] = make(map[string]bool)
+			out = append(out, stub)
+		}
+		stub.Instances++
+		if reg.Health == ServiceHealthPassing {
+			stub.Healthy++
+		}
+		for _, tag := range reg.Tags {
+			if !tags[k][tag] {
+				tags[k][tag] = true
+				stub.Tags = append(stub.Tags, tag)
+			}
+		}
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Namespace != out[j].Namespace {
+			return out[i].Namespace < out[j].Namespace
+		}
+		return out[i].ServiceName < out[j].ServiceName
+	})
+	return out
+}
+
+// ServiceRegistrationUpsertRequest is used by clients to register the
+// services of a task of an allocation running on the node.
+type ServiceRegistrationUpsertRequest struct {
+	NodeID   string
+	SecretID string
+	Services []*ServiceRegistration
+	WriteRequest
+}
+
+// ServiceRegistrationDeleteRequest is used by clients to delete the
+// registrations of the services of a task of an allocation running on the
+// node.
+type ServiceRegistrationDeleteRequest struct {
+	NodeID   string
+	SecretID string
+	AllocID  string
+	Task     string
+	WriteRequest
+}
+
+// ServiceRegistrationListRequest is used to list the services of a
+// namespace, optionally only those of a job.
+type ServiceRegistrationListRequest struct {
+	JobID string
+	QueryOptions
+}
+
+// ServiceRegistrationListResponse is used for a list request.
+type ServiceRegistrationListResponse struct {
+	Services []*ServiceRegistrationListStub
+	QueryMeta
+}
+
+// ServiceRegistrationByNameRequest is used to read the registered instances
+// of a service, optionally only those of a job.
+type ServiceRegistrationByNameRequest struct {
+	ServiceName string
+	JobID       string
+
+	// NodeID, SecretID and AllocID authenticate the requests made by clients
+	// for the tasks of an allocation running on the node, in place of an ACL
+	// token. Only the services of the namespace of the allocation are read.
+	NodeID   string
+	SecretID string
+	AllocID  string
+
+	QueryOptions
+}
+
+// ServiceRegistrationByNameResponse returns the registered instances of a
+// service.
+type ServiceRegistrationByNameResponse struct {
+	Services []*ServiceRegistration
+	QueryMeta
+}
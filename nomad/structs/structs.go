@@ -82,6 +82,32 @@ const (
 	NodeUpdateEligibilityRequestType
 	BatchNodeUpdateDrainRequestType
 	SchedulerConfigRequestType
+	NodePoolUpsertRequestType
+	NodePoolDeleteRequestType
+	NamespaceUpsertRequestType
+	NamespaceDeleteRequestType
+	QuotaSpecUpsertRequestType
+	QuotaSpecDeleteRequestType
+	NodeUpdateDynamicMetaRequestType
+	JobBatchRegisterRequestType
+	VariablesApplyRequestType
+	VariablesDeleteRequestType
+	RootKeyUpsertRequestType
+	ScalingEventRegisterRequestType
+	ServiceRegistrationUpsertRequestType
+	ServiceRegistrationDeleteRequestType
+	ACLAuthMethodsUpsertRequestType
+	ACLAuthMethodsDeleteRequestType
+	ACLBindingRulesUpsertRequestType
+	ACLBindingRulesDeleteRequestType
+	VolumeRegisterRequestType
+	VolumeDeleteRequestType
+	VolumeClaimRequestType
+	VolumeDetachRequestType
+	RootKeyDeleteRequestType
+	ACLRolesUpsertRequestType
+	ACLRolesDeleteRequestType
+	EvalUpdateBatchRequestType
 )
 
 const (
@@ -125,20 +151,28 @@ const (
 	DefaultNamespace            = "default"
 	DefaultNamespaceDescription = "Default shared namespace"
 
+	// AllNamespacesSentinel is the namespace of the list requests returning
+	// the objects of all the namespaces the token may access.
+	AllNamespacesSentinel = "*"
+
 	// JitterFraction is a the limit to the amount of jitter we apply
 	// to a user specified MaxQueryTime. We divide the specified time by
 	// the fraction. So 16 == 6.25% limit of jitter. This jitter is also
 	// applied to RPCHoldTimeout.
 	JitterFraction = 16
 
-	// MaxRetainedNodeEvents is the maximum number of node events that will be
-	// retained for a single node
+	// MaxRetainedNodeEvents is the default maximum number of node events that
+	// will be retained for a single node
 	MaxRetainedNodeEvents = 10
 
 	// MaxRetainedNodeScores is the number of top scoring nodes for which we
 	// retain scoring metadata
 	MaxRetainedNodeScores = 5
 
+	// MaxRetainedNodeFailures is the number of nodes rejected for a placement
+	// for which we retain the rejection reason
+	MaxRetainedNodeFailures = 20
+
 	// Normalized scorer name
 	NormScorerName = "normalized-score"
 )
@@ -155,6 +189,7 @@ const (
 	Nodes       Context = "nodes"
 	Namespaces  Context = "namespaces"
 	Quotas      Context = "quotas"
+	Plugins     Context = "plugins"
 	All         Context = "all"
 )
 
@@ -224,6 +259,18 @@ type QueryOptions struct {
 	// If set, used as prefix for resource list searches
 	Prefix string
 
+	// Filter is an expression the objects returned by list searches must
+	// match.
+	Filter string
+
+	// PerPage is the maximum number of objects returned by list searches. If
+	// zero, all the objects are returned.
+	PerPage int32
+
+	// NextToken is the token of the object to resume a paginated list search
+	// from, as returned in the QueryMeta of the previous page.
+	NextToken string
+
 	// AuthToken is secret portion of the ACL token used for the request
 	AuthToken string
 
@@ -297,6 +344,10 @@ type QueryMeta struct {
 
 	// Used to indicate if there is a known leader node
 	KnownLeader bool
+
+	// NextToken is the token of the next page of a paginated list search. It
+	// is empty on the last page.
+	NextToken string
 }
 
 // WriteMeta allows a write response to include potentially
@@ -401,6 +452,16 @@ type NodeUpdateEligibilityRequest struct {
 	WriteRequest
 }
 
+// NodeUpdateDynamicMetaRequest is used for updating the dynamic metadata of a
+// node. Keys in Set are added or overwritten and keys in Unset are removed.
+type NodeUpdateDynamicMetaRequest struct {
+	NodeID string
+	Set    map[string]string
+	Unset  []string
+
+	WriteRequest
+}
+
 // NodeEvaluateRequest is used to re-evaluate the node
 type NodeEvaluateRequest struct {
 	NodeID string
@@ -443,6 +504,43 @@ type SearchRequest struct {
 	QueryOptions
 }
 
+// FuzzySearchRequest is used to parameterize a fuzzy search, which returns
+// the objects whose name contains the text.
+type FuzzySearchRequest struct {
+	// Text is the case-insensitive substring the names of the objects are
+	// matched against.
+	Text string
+
+	// Context is the type of the objects to search, or all to search every
+	// type.
+	Context Context
+
+	QueryOptions
+}
+
+// FuzzyMatch is an object matched by a fuzzy search.
+type FuzzyMatch struct {
+	// ID is the matching name of the object
+	ID string
+
+	// Scope is the path of identifiers locating the object, such as the
+	// namespace and ID of an allocation matched by its name.
+	Scope []string `json:",omitempty"`
+}
+
+// FuzzySearchResponse is used to return the matches of a fuzzy search and
+// whether the matches of each context are truncated.
+type FuzzySearchResponse struct {
+	// Matches are the matching objects of each context
+	Matches map[Context][]FuzzyMatch
+
+	// Truncations indicates whether the matches for a particular context have
+	// been truncated
+	Truncations map[Context]bool
+
+	QueryMeta
+}
+
 // JobRegisterRequest is used for Job.Register endpoint
 // to register a job as being a schedulable entity.
 type JobRegisterRequest struct {
@@ -457,6 +555,11 @@ type JobRegisterRequest struct {
 	// PolicyOverride is set when the user is attempting to override any policies
 	PolicyOverride bool
 
+	// MultiregionOrigin is set by the server fanning out the registration of
+	// a multiregion job to each of its regions. The copy is verified with the
+	// origin region and is not registered in the other regions again.
+	MultiregionOrigin *MultiregionOrigin
+
 	WriteRequest
 }
 
@@ -470,6 +573,10 @@ type JobDeregisterRequest struct {
 	// garbage collector
 	Purge bool
 
+	// MultiregionOrigin is set by the server fanning out the deregistration
+	// of a multiregion job to each of its regions.
+	MultiregionOrigin *MultiregionOrigin
+
 	WriteRequest
 }
 
@@ -485,6 +592,18 @@ type JobBatchDeregisterRequest struct {
 	WriteRequest
 }
 
+// JobBatchRegisterRequest is used to register a set of jobs and upsert their
+// evaluations in a single transaction.
+type JobBatchRegisterRequest struct {
+	// Jobs is the set of jobs to register
+	Jobs []*Job
+
+	// Evals is the set of evaluations to create.
+	Evals []*Evaluation
+
+	WriteRequest
+}
+
 // JobDeregisterOptions configures how a job is deregistered.
 type JobDeregisterOptions struct {
 	// Purge controls whether the deregister purges the job from the system or
@@ -538,9 +657,80 @@ type JobDispatchRequest struct {
 	JobID   string
 	Payload []byte
 	Meta    map[string]string
+
+	// IdempotencyToken is an optional token that prevents the parameterized
+	// job from being dispatched more than once with the same token. A
+	// dispatch with the token of an existing dispatched job returns the
+	// existing job.
+	IdempotencyToken string
+
+	WriteRequest
+}
+
+// JobBatchDispatchRequest is used to dispatch a parameterized job multiple
+// times. The dispatched jobs are registered in a single transaction.
+type JobBatchDispatchRequest struct {
+	JobID      string
+	Dispatches []*JobDispatchItem
+	WriteRequest
+}
+
+// JobDispatchItem is a single dispatch of a batch dispatch request.
+type JobDispatchItem struct {
+	Payload          []byte
+	Meta             map[string]string
+	IdempotencyToken string
+}
+
+// JobScaleRequest is used by the Job.Scale endpoint to scale a task group of
+// a job. A request without a count only records a scaling event.
+type JobScaleRequest struct {
+	JobID string
+
+	// Target identifies what is scaled, the task group being set with the
+	// ScalingTargetGroup key.
+	Target map[string]string
+
+	// Count is the new count of the task group, if set.
+	Count *int64
+
+	// Message, Error and Meta describe the reason of the scaling and are
+	// recorded in the scaling event.
+	Message string
+	Error   bool
+	Meta    map[string]interface{}
+
 	WriteRequest
 }
 
+// Validate is used to validate the arguments of a scale request.
+func (r *JobScaleRequest) Validate() error {
+	var mErr multierror.Error
+	if r.JobID == "" {
+		mErr.Errors = append(mErr.Errors, errors.New("missing job ID"))
+	}
+	if r.Target[ScalingTargetGroup] == "" {
+		mErr.Errors = append(mErr.Errors, errors.New("missing task group name"))
+	}
+	if r.Count != nil {
+		if *r.Count < 0 {
+			mErr.Errors = append(mErr.Errors, errors.New("scaling count can't be negative"))
+		}
+		if r.Error {
+			mErr.Errors = append(mErr.Errors, errors.New("scaling errors can't set a count"))
+		}
+	} else if r.Message == "" {
+		mErr.Errors = append(mErr.Errors, errors.New("scaling events without a count require a message"))
+	}
+	return mErr.ErrorOrNil()
+}
+
+// JobScaleStatusRequest is used to get the scaling status of a job
+type JobScaleStatusRequest struct {
+	JobID string
+	QueryOptions
+}
+
 // JobValidateRequest is used to validate a job
 type JobValidateRequest struct {
 	Job *Job
@@ -590,6 +780,13 @@ type EvalUpdateRequest struct {
 	WriteRequest
 }
 
+// EvalUpdateBatchRequest is used for upserting the evaluations of several
+// EvalUpdateRequests in a single state store transaction.
+type EvalUpdateBatchRequest struct {
+	Updates []*EvalUpdateRequest
+	WriteRequest
+}
+
 // EvalDeleteRequest is used for deleting an evaluation.
 type EvalDeleteRequest struct {
 	Evals  []string
@@ -704,6 +901,12 @@ type AllocSpecificRequest struct {
 	QueryOptions
 }
 
+// AllocStopRequest is used to stop an allocation and reschedule it
+type AllocStopRequest struct {
+	AllocID string
+	WriteRequest
+}
+
 // AllocsGetRequest is used to query a set of allocations
 type AllocsGetRequest struct {
 	AllocIDs []string
@@ -878,6 +1081,20 @@ type DeploymentPauseRequest struct {
 	WriteRequest
 }
 
+// DeploymentRunRequest is used to start a pending deployment
+type DeploymentRunRequest struct {
+	DeploymentID string
+
+	WriteRequest
+}
+
+// DeploymentUnblockRequest is used to mark a blocked deployment as successful
+type DeploymentUnblockRequest struct {
+	DeploymentID string
+
+	WriteRequest
+}
+
 // DeploymentSpecificRequest is used to make a request specific to a particular
 // deployment
 type DeploymentSpecificRequest struct {
@@ -888,6 +1105,11 @@ type DeploymentSpecificRequest struct {
 // DeploymentFailRequest is used to fail a particular deployment
 type DeploymentFailRequest struct {
 	DeploymentID string
+
+	// PeerRegion is set when the deployment of a multiregion job is failed
+	// because the deployment of the job in the peer region failed
+	PeerRegion string
+
 	WriteRequest
 }
 
@@ -994,6 +1216,15 @@ type NodeEligibilityUpdateResponse struct {
 	WriteMeta
 }
 
+// NodeDynamicMetaUpdateResponse is used to respond to a node dynamic metadata
+// update
+type NodeDynamicMetaUpdateResponse struct {
+	NodeModifyIndex uint64
+	EvalIDs         []string
+	EvalCreateIndex uint64
+	WriteMeta
+}
+
 // NodeAllocsResponse is used to return allocs for a single node
 type NodeAllocsResponse struct {
 	Allocs []*Allocation
@@ -1043,6 +1274,41 @@ type JobDispatchResponse struct {
 	WriteMeta
 }
 
+// JobBatchDispatchResponse is used to respond to a batch dispatch. The
+// dispatches are in the order of the request.
+type JobBatchDispatchResponse struct {
+	Dispatches []*JobDispatchResponse
+	WriteMeta
+}
+
+// JobScaleStatusResponse is used to return the scaling status of a job
+type JobScaleStatusResponse struct {
+	JobScaleStatus *JobScaleStatus
+	QueryMeta
+}
+
+// JobScaleStatus is the scaling status of the task groups of a job
+type JobScaleStatus struct {
+	JobID          string
+	Namespace      string
+	JobCreateIndex uint64
+	JobModifyIndex uint64
+	JobStopped     bool
+	TaskGroups     map[string]*TaskGroupScaleStatus
+}
+
+// TaskGroupScaleStatus is the scaling status of a task group: its desired
+// count, the number of its allocations in each state and its most recent
+// scaling events.
+type TaskGroupScaleStatus struct {
+	Desired   int
+	Placed    int
+	Running   int
+	Healthy   int
+	Unhealthy int
+	Events    []*ScalingEvent
+}
+
 // JobListResponse is used for a list request
 type JobListResponse struct {
 	Jobs []*JobListStub
@@ -1101,6 +1367,15 @@ type SingleAllocResponse struct {
 	QueryMeta
 }
 
+// AllocStopResponse is the response to an AllocStopRequest
+type AllocStopResponse struct {
+	// EvalID is the ID of the evaluation created to reschedule the
+	// allocation
+	EvalID string
+
+	WriteMeta
+}
+
 // AllocsGetResponse is used to return a set of allocations
 type AllocsGetResponse struct {
 	Allocs []*Allocation
@@ -1234,10 +1509,12 @@ type EmitNodeEventsResponse struct {
 }
 
 const (
-	NodeEventSubsystemDrain     = "Drain"
-	NodeEventSubsystemDriver    = "Driver"
-	NodeEventSubsystemHeartbeat = "Heartbeat"
-	NodeEventSubsystemCluster   = "Cluster"
+	NodeEventSubsystemDrain       = "Drain"
+	NodeEventSubsystemDriver      = "Driver"
+	NodeEventSubsystemHeartbeat   = "Heartbeat"
+	NodeEventSubsystemCluster     = "Cluster"
+	NodeEventSubsystemFingerprint = "Fingerprint"
+	NodeEventSubsystemStorage     = "Storage"
 )
 
 // NodeEvent is a single unit representing a node’s state change
@@ -1468,10 +1745,20 @@ type Node struct {
 	// client. This is opaque to Nomad.
 	Meta map[string]string
 
+	// DynamicMeta is metadata set on the node at runtime through the API.
+	// Unlike Meta it is managed by the servers and retained when the client
+	// re-registers. Constraints and affinities target it using
+	// ${dynamic_meta.<key>}.
+	DynamicMeta map[string]string
+
 	// NodeClass is an opaque identifier used to group nodes
 	// together for the purpose of determining scheduling pressure.
 	NodeClass string
 
+	// NodePool is the node pool the node belongs to. Only jobs targeting
+	// the node pool are placed on the node.
+	NodePool string
+
 	// ComputedClass is a unique id that identifies nodes with a common set of
 	// attributes and capabilities.
 	ComputedClass string
@@ -1508,6 +1795,10 @@ type Node struct {
 	// Drivers is a map of driver names to current driver information
 	Drivers map[string]*DriverInfo
 
+	// HostVolumes is a map of host volume names to their configuration and
+	// health
+	HostVolumes map[string]*ClientHostVolumeConfig
+
 	// Raft Indexes
 	CreateIndex uint64
 	ModifyIndex uint64
@@ -1534,6 +1825,12 @@ func (n *Node) Canonicalize() {
 			n.SchedulingEligibility = NodeSchedulingEligible
 		}
 	}
+
+	// Nodes registered before node pools were introduced are in the
+	// default node pool
+	if n.NodePool == "" {
+		n.NodePool = NodePoolDefault
+	}
 }
 
 func (n *Node) Copy() *Node {
@@ -1549,9 +1846,11 @@ func (n *Node) Copy() *Node {
 	nn.ReservedResources = nn.ReservedResources.Copy()
 	nn.Links = helper.CopyMapStringString(nn.Links)
 	nn.Meta = helper.CopyMapStringString(nn.Meta)
+	nn.DynamicMeta = helper.CopyMapStringString(nn.DynamicMeta)
 	nn.Events = copyNodeEvents(n.Events)
 	nn.DrainStrategy = nn.DrainStrategy.Copy()
 	nn.Drivers = copyNodeDrivers(n.Drivers)
+	nn.HostVolumes = CopyMapClientHostVolumeConfig(n.HostVolumes)
 	return nn
 }
 
@@ -1663,6 +1962,7 @@ func (n *Node) Stub() *NodeListStub {
 		Datacenter:            n.Datacenter,
 		Name:                  n.Name,
 		NodeClass:             n.NodeClass,
+		NodePool:              n.NodePool,
 		Version:               n.Attributes["nomad.version"],
 		Drain:                 n.Drain,
 		SchedulingEligibility: n.SchedulingEligibility,
@@ -1682,6 +1982,7 @@ type NodeListStub struct {
 	Datacenter            string
 	Name                  string
 	NodeClass             string
+	NodePool              string
 	Version               string
 	Drain                 bool
 	SchedulingEligibility string
@@ -1701,10 +2002,35 @@ type Resources struct {
 	IOPS     int
 	Networks Networks
 	Devices  []*RequestedDevice
+	NUMA     *NUMA
+
+	// IOWeight is the relative block I/O weight of the task and IOLimits
+	// throttle its I/O against individual block devices.
+	IOWeight int
+	IOLimits []*IOLimit
+
+	// CPUHardLimit caps the CPU usage of the task at its share of the CPU
+	// using a CFS quota. CPUCFSPeriod is the CFS period in microseconds over
+	// which the quota is enforced; longer periods allow longer bursts.
+	CPUHardLimit bool
+	CPUCFSPeriod int
+
+	// Cores is the number of cores dedicated to the task. The cores are
+	// chosen by the scheduler among the reservable cores of the node and no
+	// other task runs on them. The CPU shares of the task are those of its
+	// cores.
+	Cores int
 }
 
 const (
 	BytesInMegabyte = 1024 * 1024
+
+	// MinCPUCFSPeriod and MaxCPUCFSPeriod bound the CFS period in
+	// microseconds a task may use when its CPU is hard limited, and
+	// DefaultCPUCFSPeriod is used when none is given.
+	MinCPUCFSPeriod     = 1000
+	MaxCPUCFSPeriod     = 1000000
+	DefaultCPUCFSPeriod = 100000
 )
 
 // DefaultResources is a small resources object that contains the
@@ -1754,6 +2080,28 @@ func (r *Resources) Validate() error {
 		}
 	}
 
+	if err := r.NUMA.Validate(); err != nil {
+		mErr.Errors = append(mErr.Errors, err)
+	}
+
+	if err := validateIO(r.IOWeight, r.IOLimits); err != nil {
+		mErr.Errors = append(mErr.Errors, err)
+	}
+
+	if r.Cores < 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("cores must not be negative; got %d", r.Cores))
+	}
+
+	if r.CPUCFSPeriod != 0 {
+		if !r.CPUHardLimit {
+			mErr.Errors = append(mErr.Errors, errors.New("cpu_cfs_period requires cpu_hard_limit to be set"))
+		}
+		if r.CPUCFSPeriod < MinCPUCFSPeriod || r.CPUCFSPeriod > MaxCPUCFSPeriod {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("cpu_cfs_period must be between %d and %d microseconds; got %d",
+				MinCPUCFSPeriod, MaxCPUCFSPeriod, r.CPUCFSPeriod))
+		}
+	}
+
 	return mErr.ErrorOrNil()
 }
 
@@ -1777,6 +2125,24 @@ func (r *Resources) Merge(other *Resources) {
 	if len(other.Devices) != 0 {
 		r.Devices = other.Devices
 	}
+	if other.NUMA != nil {
+		r.NUMA = other.NUMA
+	}
+	if other.IOWeight != 0 {
+		r.IOWeight = other.IOWeight
+	}
+	if len(other.IOLimits) != 0 {
+		r.IOLimits = other.IOLimits
+	}
+	if other.CPUHardLimit {
+		r.CPUHardLimit = other.CPUHardLimit
+	}
+	if other.CPUCFSPeriod != 0 {
+		r.CPUCFSPeriod = other.CPUCFSPeriod
+	}
+	if other.Cores != 0 {
+		r.Cores = other.Cores
+	}
 }
 
 func (r *Resources) Canonicalize() {
@@ -1788,6 +2154,9 @@ func (r *Resources) Canonicalize() {
 	if len(r.Devices) == 0 {
 		r.Devices = nil
 	}
+	if len(r.IOLimits) == 0 {
+		r.IOLimits = nil
+	}
 
 	for _, n := range r.Networks {
 		n.Canonicalize()
@@ -1844,6 +2213,16 @@ func (r *Resources) Copy() *Resources {
 		}
 	}
 
+	newR.NUMA = r.NUMA.Copy()
+
+	// Copy the I/O limits
+	if r.IOLimits != nil {
+		newR.IOLimits = make([]*IOLimit, len(r.IOLimits))
+		for i, l := range r.IOLimits {
+			newR.IOLimits[i] = l.Copy()
+		}
+	}
+
 	return newR
 }
 
@@ -1901,11 +2280,57 @@ func (r *Resources) GoString() string {
 type Port struct {
 	Label string
 	Value int
+
+	// To is the port inside the network namespace of the allocation the
+	// host port is mapped to in bridge networks. Zero maps the host port to
+	// the same port.
+	To int
+
+	// HostNetwork is the name of the host network of the node the port is
+	// bound to. Empty binds the port on the IP of its network.
+	HostNetwork string
+
+	// HostIP is the address of the host network the port was assigned on.
+	// It is set by the scheduler.
+	HostIP string
+}
+
+// IP returns the address the port is bound to in the given network.
+func (p Port) IP(n *NetworkResource) string {
+	if p.HostIP != "" {
+		return p.HostIP
+	}
+	return n.IP
 }
 
+const (
+	// NetworkModeHost is the default network mode in which the tasks share
+	// the network namespace of the host.
+	NetworkModeHost = "host"
+
+	// NetworkModeBridge is the network mode in which the tasks of an
+	// allocation share a network namespace attached to a bridge on the
+	// host, with the ports of the group network mapped from the host.
+	NetworkModeBridge = "bridge"
+
+	// NetworkModeCNIPrefix prefixes the network mode of the task groups whose
+	// allocations get their own network namespace, configured by the CNI
+	// network named after the prefix.
+	NetworkModeCNIPrefix = "cni/"
+
+	// CNINetworkAttributePrefix prefixes the node attributes set for each
+	// CNI network configured on a client.
+	CNINetworkAttributePrefix = "plugins.cni.network."
+
+	// BridgeNetworkAttribute is the node attribute set on clients with the
+	// CNI plugins required by bridge networks.
+	BridgeNetworkAttribute = "plugins.cni.bridge"
+)
+
 // NetworkResource is used to represent available network
 // resources
 type NetworkResource struct {
+	Mode          string // Mode of the network
 	Device        string // Name of the device
 	CIDR          string // CIDR block of addresses
 	IP            string // Host IP address
@@ -1915,6 +2340,10 @@ type NetworkResource struct {
 }
 
 func (nr *NetworkResource) Equals(other *NetworkResource) bool {
+	if nr.Mode != other.Mode {
+		return false
+	}
+
 	if nr.Device != other.Device {
 		return false
 	}
@@ -2011,6 +2440,70 @@ func (n *NetworkResource) GoString() string {
 	return fmt.Sprintf("*%#v", *n)
 }
 
+// CNINetwork returns the name of the CNI network of the network mode and
+// whether the network is configured by CNI.
+func (n *NetworkResource) CNINetwork() (string, bool) {
+	if !strings.HasPrefix(n.Mode, NetworkModeCNIPrefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(n.Mode, NetworkModeCNIPrefix), true
+}
+
+// validateGroupNetwork returns an error if the network can't be requested by
+// a task group. Task groups select the network mode of their allocations and
+// bridge networks may request ports mapped into the network namespace. The
+// ports and bandwidth of other modes are still requested by the tasks.
+func (n *NetworkResource) validateGroupNetwork() error {
+	var mErr multierror.Error
+	switch {
+	case n.Mode == "" || n.Mode == NetworkModeHost:
+	case n.Mode == NetworkModeBridge:
+		if err := n.validateMappedPorts(); err != nil {
+			mErr.Errors = append(mErr.Errors, err)
+		}
+	case strings.HasPrefix(n.Mode, NetworkModeCNIPrefix):
+		if name, _ := n.CNINetwork(); name == "" {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Network mode %q is missing the CNI network name", n.Mode))
+		}
+	default:
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("Unsupported network mode %q", n.Mode))
+	}
+
+	if n.Device != "" || n.CIDR != "" || n.IP != "" || n.MBits != 0 {
+		mErr.Errors = append(mErr.Errors, errors.New("Task group networks may only set the network mode and ports"))
+	}
+	if n.Mode != NetworkModeBridge && (len(n.ReservedPorts) != 0 || len(n.DynamicPorts) != 0) {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("Only %q task group networks may request ports", NetworkModeBridge))
+	}
+	return mErr.ErrorOrNil()
+}
+
+// validateMappedPorts returns an error if the ports of a bridge network have
+// duplicate labels or invalid port numbers.
+func (n *NetworkResource) validateMappedPorts() error {
+	var mErr multierror.Error
+	labels := make(map[string]struct{}, len(n.ReservedPorts)+len(n.DynamicPorts))
+	check := func(p Port) {
+		if _, ok := labels[p.Label]; ok {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Port label %q is duplicate", p.Label))
+		}
+		labels[p.Label] = struct{}{}
+		if p.Value < 0 || p.Value >= maxValidPort {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Port %q has invalid static port %d", p.Label, p.Value))
+		}
+		if p.To < 0 || p.To >= maxValidPort {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Port %q has invalid mapped port %d", p.Label, p.To))
+		}
+	}
+	for _, p := range n.ReservedPorts {
+		check(p)
+	}
+	for _, p := range n.DynamicPorts {
+		check(p)
+	}
+	return mErr.ErrorOrNil()
+}
+
 // PortLabels returns a map of port labels to their assigned host ports.
 func (n *NetworkResource) PortLabels() map[string]int {
 	num := len(n.ReservedPorts) + len(n.DynamicPorts)
@@ -2027,17 +2520,50 @@ func (n *NetworkResource) PortLabels() map[string]int {
 // Networks defined for a task on the Resources struct.
 type Networks []*NetworkResource
 
+// Copy returns a deep copy of the networks
+func (ns Networks) Copy() Networks {
+	if ns == nil {
+		return nil
+	}
+	out := make(Networks, len(ns))
+	for i, n := range ns {
+		out[i] = n.Copy()
+	}
+	return out
+}
+
+// CNINetwork returns the name of the CNI network of the networks and whether
+// one is configured by CNI.
+func (ns Networks) CNINetwork() (string, bool) {
+	for _, n := range ns {
+		if name, ok := n.CNINetwork(); ok {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// Bridge returns the network in bridge mode of the networks or nil if none is.
+func (ns Networks) Bridge() *NetworkResource {
+	for _, n := range ns {
+		if n.Mode == NetworkModeBridge {
+			return n
+		}
+	}
+	return nil
+}
+
 // Port assignment and IP for the given label or empty values.
 func (ns Networks) Port(label string) (string, int) {
 	for _, n := range ns {
 		for _, p := range n.ReservedPorts {
 			if p.Label == label {
-				return n.IP, p.Value
+				return p.IP(n), p.Value
 			}
 		}
 		for _, p := range n.DynamicPorts {
 			if p.Label == label {
-				return n.IP, p.Value
+				return p.IP(n), p.Value
 			}
 		}
 	}
@@ -2076,6 +2602,11 @@ type RequestedDevice struct {
 	// Affinities are a set of affinites to apply when selecting the device
 	// to use.
 	Affinities []*Affinity
+
+	// Shared allows the requested devices to be time-shared with other
+	// shared requests instead of being allocated exclusively. Only low
+	// priority batch jobs may share devices.
+	Shared bool
 }
 
 func (r *RequestedDevice) Copy() *RequestedDevice {
@@ -2155,6 +2686,14 @@ type NodeResources struct {
 	Disk     NodeDiskResources
 	Networks Networks
 	Devices  []*NodeDeviceResource
+
+	// NumaNodes is the NUMA topology of the node. It is empty if the
+	// topology could not be fingerprinted.
+	NumaNodes []*NodeNumaNode
+
+	// HostNetworks are the host networks of the client resolved to an
+	// address of the node.
+	HostNetworks []*NodeHostNetwork
 }
 
 func (n *NodeResources) Copy() *NodeResources {
@@ -2183,6 +2722,25 @@ func (n *NodeResources) Copy() *NodeResources {
 		}
 	}
 
+	// Copy the reservable cores
+	newN.Cpu.ReservableCpuCores = copyCores(n.Cpu.ReservableCpuCores)
+
+	// Copy the NUMA nodes
+	if n.NumaNodes != nil {
+		newN.NumaNodes = make([]*NodeNumaNode, len(n.NumaNodes))
+		for i, numaNode := range n.NumaNodes {
+			newN.NumaNodes[i] = numaNode.Copy()
+		}
+	}
+
+	// Copy the host networks
+	if n.HostNetworks != nil {
+		newN.HostNetworks = make([]*NodeHostNetwork, len(n.HostNetworks))
+		for i, hostNetwork := range n.HostNetworks {
+			newN.HostNetworks[i] = hostNetwork.Copy()
+		}
+	}
+
 	return newN
 }
 
@@ -2226,6 +2784,14 @@ func (n *NodeResources) Merge(o *NodeResources) {
 	if len(o.Devices) != 0 {
 		n.Devices = o.Devices
 	}
+
+	if len(o.NumaNodes) != 0 {
+		n.NumaNodes = o.NumaNodes
+	}
+
+	if len(o.HostNetworks) != 0 {
+		n.HostNetworks = o.HostNetworks
+	}
 }
 
 func (n *NodeResources) Equals(o *NodeResources) bool {
@@ -2261,6 +2827,16 @@ func (n *NodeResources) Equals(o *NodeResources) bool {
 		return false
 	}
 
+	// Check the NUMA topology
+	if !NumaNodesEquals(n.NumaNodes, o.NumaNodes) {
+		return false
+	}
+
+	// Check the host networks
+	if !HostNetworksEquals(n.HostNetworks, o.HostNetworks) {
+		return false
+	}
+
 	return true
 }
 
@@ -2287,6 +2863,13 @@ type NodeCpuResources struct {
 	// CpuShares is the CPU shares available. This is calculated by number of
 	// cores multiplied by the core frequency.
 	CpuShares int64
+
+	// TotalCpuCores is the number of cores of the node
+	TotalCpuCores uint16
+
+	// ReservableCpuCores is the set of cores that may be dedicated to tasks.
+	// Tasks without dedicated cores never run on these cores.
+	ReservableCpuCores []uint16
 }
 
 func (n *NodeCpuResources) Merge(o *NodeCpuResources) {
@@ -2297,6 +2880,21 @@ func (n *NodeCpuResources) Merge(o *NodeCpuResources) {
 	if o.CpuShares != 0 {
 		n.CpuShares = o.CpuShares
 	}
+	if o.TotalCpuCores != 0 {
+		n.TotalCpuCores = o.TotalCpuCores
+	}
+	if len(o.ReservableCpuCores) != 0 {
+		n.ReservableCpuCores = o.ReservableCpuCores
+	}
+}
+
+// SharesPerCore returns the CPU shares provided by a single core of the
+// node.
+func (n *NodeCpuResources) SharesPerCore() int64 {
+	if n.TotalCpuCores == 0 {
+		return 0
+	}
+	return n.CpuShares / int64(n.TotalCpuCores)
 }
 
 func (n *NodeCpuResources) Equals(o *NodeCpuResources) bool {
@@ -2311,6 +2909,12 @@ func (n *NodeCpuResources) Equals(o *NodeCpuResources) bool {
 	if n.CpuShares != o.CpuShares {
 		return false
 	}
+	if n.TotalCpuCores != o.TotalCpuCores {
+		return false
+	}
+	if !CoresEquals(n.ReservableCpuCores, o.ReservableCpuCores) {
+		return false
+	}
 
 	return true
 }
@@ -2692,6 +3296,7 @@ func (a *AllocatedResources) Copy() *AllocatedResources {
 		}
 		newA.Tasks = tr
 	}
+	newA.Shared = a.Shared.Copy()
 
 	return newA
 }
@@ -2732,6 +3337,10 @@ type AllocatedTaskResources struct {
 	Memory   AllocatedMemoryResources
 	Networks Networks
 	Devices  []*AllocatedDeviceResource
+
+	// Numa is the NUMA node the CPU and memory of the task are bound to. It
+	// is nil if the task does not require NUMA placement.
+	Numa *AllocatedNumaResources
 }
 
 func (a *AllocatedTaskResources) Copy() *AllocatedTaskResources {
@@ -2759,6 +3368,9 @@ func (a *AllocatedTaskResources) Copy() *AllocatedTaskResources {
 		}
 	}
 
+	newA.Numa = a.Numa.Copy()
+	newA.Cpu.ReservedCores = copyCores(a.Cpu.ReservedCores)
+
 	return newA
 }
 
@@ -2831,6 +3443,15 @@ func (a *AllocatedTaskResources) Subtract(delta *AllocatedTaskResources) {
 // AllocatedSharedResources are the set of resources allocated to a task group.
 type AllocatedSharedResources struct {
 	DiskMB int64
+
+	// Networks are the networks of the task group, with the ports of bridge
+	// networks assigned
+	Networks Networks
+}
+
+func (a AllocatedSharedResources) Copy() AllocatedSharedResources {
+	a.Networks = a.Networks.Copy()
+	return a
 }
 
 func (a *AllocatedSharedResources) Add(delta *AllocatedSharedResources) {
@@ -2852,6 +3473,9 @@ func (a *AllocatedSharedResources) Subtract(delta *AllocatedSharedResources) {
 // AllocatedCpuResources captures the allocated CPU resources.
 type AllocatedCpuResources struct {
 	CpuShares int64
+
+	// ReservedCores are the cores dedicated to the task
+	ReservedCores []uint16
 }
 
 func (a *AllocatedCpuResources) Add(delta *AllocatedCpuResources) {
@@ -2919,6 +3543,10 @@ type AllocatedDeviceResource struct {
 
 	// DeviceIDs is the set of allocated devices
 	DeviceIDs []string
+
+	// Shared marks that the devices are time-shared with other allocations
+	// and are not allocated exclusively.
+	Shared bool
 }
 
 func (a *AllocatedDeviceResource) ID() *DeviceIdTuple {
@@ -3014,10 +3642,11 @@ func (c *ComparableResources) NetIndex(n *NetworkResource) int {
 const (
 	// JobTypeNomad is reserved for internal system tasks and is
 	// always handled by the CoreScheduler.
-	JobTypeCore    = "_core"
-	JobTypeService = "service"
-	JobTypeBatch   = "batch"
-	JobTypeSystem  = "system"
+	JobTypeCore     = "_core"
+	JobTypeService  = "service"
+	JobTypeBatch    = "batch"
+	JobTypeSystem   = "system"
+	JobTypeSysBatch = "sysbatch"
 )
 
 const (
@@ -3045,8 +3674,83 @@ const (
 	// JobTrackedVersions is the number of historic job versions that are
 	// kept.
 	JobTrackedVersions = 6
+
+	// JobTrackedScalingEvents is the number of scaling events that are
+	// kept for each task group of a job.
+	JobTrackedScalingEvents = 20
 )
 
+const (
+	// ScalingTargetGroup is the key of the Target of a scale request
+	// identifying the task group to scale.
+	ScalingTargetGroup = "Group"
+)
+
+// ScalingEvent records a scaling request of a task group: when it happened,
+// who requested it and why.
+type ScalingEvent struct {
+	// Time is the time of the scaling request, in nanoseconds since the
+	// epoch.
+	Time int64
+
+	// Count is the new count of the task group, or nil if the event didn't
+	// change the count.
+	Count *int64
+
+	// PreviousCount is the count of the task group before the request.
+	PreviousCount int64
+
+	// Message, Error and Meta are the reason given by the requester.
+	Message string
+	Error   bool
+	Meta    map[string]interface{}
+
+	// AccessorID is the accessor of the ACL token of the requester, set
+	// if ACLs are enabled.
+	AccessorID string
+
+	// EvalID is the ID of the evaluation created to apply the new count.
+	EvalID *string
+
+	CreateIndex uint64
+}
+
+// JobScalingEvents are the scaling events of the task groups of a job, the
+// most recent first.
+type JobScalingEvents struct {
+	Namespace string
+	JobID     string
+
+	// ScalingEvents are the events of each task group
+	ScalingEvents map[string][]*ScalingEvent
+
+	ModifyIndex uint64
+}
+
+// Copy returns a copy of the scaling events. The events themselves are
+// shared as they are immutable.
+func (j *JobScalingEvents) Copy() *JobScalingEvents {
+	if j == nil {
+		return nil
+	}
+	c := *j
+	c.ScalingEvents = make(map[string][]*ScalingEvent, len(j.ScalingEvents))
+	for group, events := range j.ScalingEvents {
+		c.ScalingEvents[group] = append([]*ScalingEvent(nil), events...)
+	}
+	return &c
+}
+
+// ScalingEventRegisterRequest is used to record a scaling event of a task
+// group of a job.
+type ScalingEventRegisterRequest struct {
+	Namespace    string
+	JobID        string
+	TaskGroup    string
+	ScalingEvent *ScalingEvent
+	WriteRequest
+}
+
 // Job is the scope of a scheduling request to Nomad. It is the largest
 // scoped object, and is a named collection of task groups. Each task group
 // is further composed of tasks. A task group (TG) is the unit of scheduling
@@ -3093,6 +3797,10 @@ type Job struct {
 	// Datacenters contains all the datacenters this job is allowed to span
 	Datacenters []string
 
+	// NodePool is the node pool the job is placed in. Only nodes in the
+	// node pool are considered for placements.
+	NodePool string
+
 	// Constraints can be specified at a job level and apply to
 	// all the task groups and tasks.
 	Constraints []*Constraint
@@ -3119,10 +3827,22 @@ type Job struct {
 	// for dispatching.
 	ParameterizedJob *ParameterizedJobConfig
 
+	// Multiregion is used to register the job in multiple regions and to
+	// coordinate its deployments across them.
+	Multiregion *Multiregion
+
+	// GC overrides the garbage collection thresholds of the server and of
+	// the namespace for the job.
+	GC *GCConfig
+
 	// Dispatched is used to identify if the Job has been dispatched from a
 	// parameterized job.
 	Dispatched bool
 
+	// DispatchIdempotencyToken is the idempotency token the job was
+	// dispatched with, if any.
+	DispatchIdempotencyToken string
+
 	// Payload is the payload supplied when the job was dispatched.
 	Payload []byte
 
@@ -3188,6 +3908,11 @@ func (j *Job) Canonicalize() (warnings error) {
 		j.Namespace = DefaultNamespace
 	}
 
+	// Ensure the job is in a node pool.
+	if j.NodePool == "" {
+		j.NodePool = NodePoolDefault
+	}
+
 	for _, tg := range j.TaskGroups {
 		tg.Canonicalize(j)
 	}
@@ -3226,6 +3951,8 @@ func (j *Job) Copy() *Job {
 	nj.Periodic = nj.Periodic.Copy()
 	nj.Meta = helper.CopyMapStringString(nj.Meta)
 	nj.ParameterizedJob = nj.ParameterizedJob.Copy()
+	nj.Multiregion = nj.Multiregion.Copy()
+	nj.GC = nj.GC.Copy()
 	return nj
 }
 
@@ -3248,7 +3975,7 @@ func (j *Job) Validate() error {
 		mErr.Errors = append(mErr.Errors, errors.New("Job must be in a namespace"))
 	}
 	switch j.Type {
-	case JobTypeCore, JobTypeService, JobTypeBatch, JobTypeSystem:
+	case JobTypeCore, JobTypeService, JobTypeBatch, JobTypeSystem, JobTypeSysBatch:
 	case "":
 		mErr.Errors = append(mErr.Errors, errors.New("Missing job type"))
 	default:
@@ -3260,6 +3987,9 @@ func (j *Job) Validate() error {
 	if len(j.Datacenters) == 0 {
 		mErr.Errors = append(mErr.Errors, errors.New("Missing job datacenters"))
 	}
+	if j.NodePool != "" && !ValidNodePoolName(j.NodePool) {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("Invalid job node pool: %q", j.NodePool))
+	}
 	if len(j.TaskGroups) == 0 {
 		mErr.Errors = append(mErr.Errors, errors.New("Missing job task groups"))
 	}
@@ -3269,7 +3999,7 @@ func (j *Job) Validate() error {
 			mErr.Errors = append(mErr.Errors, outer)
 		}
 	}
-	if j.Type == JobTypeSystem {
+	if j.IsSystemType() {
 		if j.Affinities != nil {
 			mErr.Errors = append(mErr.Errors, fmt.Errorf("System jobs may not have an affinity stanza"))
 		}
@@ -3282,7 +4012,7 @@ func (j *Job) Validate() error {
 		}
 	}
 
-	if j.Type == JobTypeSystem {
+	if j.IsSystemType() {
 		if j.Spreads != nil {
 			mErr.Errors = append(mErr.Errors, fmt.Errorf("System jobs may not have a spread stanza"))
 		}
@@ -3306,10 +4036,10 @@ func (j *Job) Validate() error {
 			taskGroups[tg.Name] = idx
 		}
 
-		if j.Type == "system" && tg.Count > 1 {
+		if j.IsSystemType() && tg.Count > 1 {
 			mErr.Errors = append(mErr.Errors,
-				fmt.Errorf("Job task group %s has count %d. Count cannot exceed 1 with system scheduler",
-					tg.Name, tg.Count))
+				fmt.Errorf("Job task group %s has count %d. Count cannot exceed 1 with %s scheduler",
+					tg.Name, tg.Count, j.Type))
 		}
 	}
 
@@ -3321,11 +4051,11 @@ func (j *Job) Validate() error {
 		}
 	}
 
-	// Validate periodic is only used with batch jobs.
+	// Validate periodic is only used with batch and sysbatch jobs.
 	if j.IsPeriodic() && j.Periodic.Enabled {
-		if j.Type != JobTypeBatch {
+		if j.Type != JobTypeBatch && j.Type != JobTypeSysBatch {
 			mErr.Errors = append(mErr.Errors,
-				fmt.Errorf("Periodic can only be used with %q scheduler", JobTypeBatch))
+				fmt.Errorf("Periodic can only be used with %q or %q scheduler", JobTypeBatch, JobTypeSysBatch))
 		}
 
 		if err := j.Periodic.Validate(); err != nil {
@@ -3334,9 +4064,9 @@ func (j *Job) Validate() error {
 	}
 
 	if j.IsParameterized() {
-		if j.Type != JobTypeBatch {
+		if j.Type != JobTypeBatch && j.Type != JobTypeSysBatch {
 			mErr.Errors = append(mErr.Errors,
-				fmt.Errorf("Parameterized job can only be used with %q scheduler", JobTypeBatch))
+				fmt.Errorf("Parameterized job can only be used with %q or %q scheduler", JobTypeBatch, JobTypeSysBatch))
 		}
 
 		if err := j.ParameterizedJob.Validate(); err != nil {
@@ -3344,6 +4074,14 @@ func (j *Job) Validate() error {
 		}
 	}
 
+	if err := j.Multiregion.Validate(); err != nil {
+		mErr.Errors = append(mErr.Errors, err)
+	}
+
+	if err := j.GC.Validate(); err != nil {
+		mErr.Errors = append(mErr.Errors, err)
+	}
+
 	return mErr.ErrorOrNil()
 }
 
@@ -3429,6 +4167,7 @@ func (j *Job) HasUpdateStrategy() bool {
 func (j *Job) Stub(summary *JobSummary) *JobListStub {
 	return &JobListStub{
 		ID:                j.ID,
+		Namespace:         j.Namespace,
 		ParentID:          j.ParentID,
 		Name:              j.Name,
 		Type:              j.Type,
@@ -3446,6 +4185,12 @@ func (j *Job) Stub(summary *JobSummary) *JobListStub {
 	}
 }
 
+// IsSystemType returns whether the job is placed on every eligible node by
+// either the system or the sysbatch scheduler.
+func (j *Job) IsSystemType() bool {
+	return j.Type == JobTypeSystem || j.Type == JobTypeSysBatch
+}
+
 // IsPeriodic returns whether a job is periodic.
 func (j *Job) IsPeriodic() bool {
 	return j.Periodic != nil
@@ -3571,6 +4316,7 @@ func (j *Job) SetSubmitTime() {
 // for the job list
 type JobListStub struct {
 	ID                string
+	Namespace         string
 	ParentID          string
 	Name              string
 	Type              string
@@ -3646,6 +4392,7 @@ type TaskGroupSummary struct {
 	Running  int
 	Starting int
 	Lost     int
+	Unknown  int
 }
 
 const (
@@ -3687,6 +4434,10 @@ type UpdateStrategy struct {
 	// MaxParallel is how many updates can be done in parallel
 	MaxParallel int
 
+	// MaxParallelPercent is the percentage of the task group count that can
+	// be updated in parallel. If set, it takes precedence over MaxParallel.
+	MaxParallelPercent int
+
 	// HealthCheck specifies the mechanism in which allocations are marked
 	// healthy or unhealthy as part of a deployment.
 	HealthCheck string
@@ -3712,6 +4463,10 @@ type UpdateStrategy struct {
 	// stable version.
 	AutoRevert bool
 
+	// AutoPromote declares that the deployment should be promoted when all
+	// canaries are healthy
+	AutoPromote bool
+
 	// Canary is the number of canaries to deploy when a change to the task
 	// group is detected.
 	Canary int
@@ -3742,6 +4497,9 @@ func (u *UpdateStrategy) Validate() error {
 	if u.MaxParallel < 1 {
 		multierror.Append(&mErr, fmt.Errorf("Max parallel can not be less than one: %d < 1", u.MaxParallel))
 	}
+	if u.MaxParallelPercent < 0 || u.MaxParallelPercent > 100 {
+		multierror.Append(&mErr, fmt.Errorf("Max parallel percent must be between 0 and 100: %d", u.MaxParallelPercent))
+	}
 	if u.Canary < 0 {
 		multierror.Append(&mErr, fmt.Errorf("Canary count can not be less than zero: %d < 0", u.Canary))
 	}
@@ -3767,6 +4525,21 @@ func (u *UpdateStrategy) Validate() error {
 	return mErr.ErrorOrNil()
 }
 
+// MaxParallelFor returns how many allocations of a task group with the given
+// count can be updated in parallel. A percentage is rounded up so that at
+// least one allocation is updated at a time.
+func (u *UpdateStrategy) MaxParallelFor(count int) int {
+	if u.MaxParallelPercent == 0 {
+		return u.MaxParallel
+	}
+
+	limit := (count*u.MaxParallelPercent + 99) / 100
+	if limit < 1 {
+		return 1
+	}
+	return limit
+}
+
 // TODO(alexdadgar): Remove once no longer used by the scheduler.
 // Rolling returns if a rolling strategy should be used
 func (u *UpdateStrategy) Rolling() bool {
@@ -3994,6 +4767,53 @@ func (d *ParameterizedJobConfig) Copy() *ParameterizedJobConfig {
 	return nd
 }
 
+// GCConfig overrides the garbage collection thresholds of the server for the
+// jobs of a namespace, or for a single job. A zero threshold is not
+// overridden.
+type GCConfig struct {
+	// JobThreshold is how old a job must be to be garbage collected once
+	// dead, along with its evaluations and allocations.
+	JobThreshold time.Duration
+
+	// EvalThreshold is how old a terminal evaluation must be to be garbage
+	// collected, along with its allocations.
+	EvalThreshold time.Duration
+
+	// AllocThreshold is how old a terminal allocation must be to be garbage
+	// collected. It defaults to the threshold of the evaluation or job the
+	// allocation is collected with.
+	AllocThreshold time.Duration
+}
+
+// Validate is used to sanity check the GC thresholds.
+func (g *GCConfig) Validate() error {
+	if g == nil {
+		return nil
+	}
+
+	var mErr multierror.Error
+	if g.JobThreshold < 0 {
+		multierror.Append(&mErr, fmt.Errorf("GC job threshold must not be negative: %v", g.JobThreshold))
+	}
+	if g.EvalThreshold < 0 {
+		multierror.Append(&mErr, fmt.Errorf("GC eval threshold must not be negative: %v", g.EvalThreshold))
+	}
+	if g.AllocThreshold < 0 {
+		multierror.Append(&mErr, fmt.Errorf("GC alloc threshold must not be negative: %v", g.AllocThreshold))
+	}
+	return mErr.ErrorOrNil()
+}
+
+// Copy returns a copy of the GC thresholds.
+func (g *GCConfig) Copy() *GCConfig {
+	if g == nil {
+		return nil
+	}
+	ng := new(GCConfig)
+	*ng = *g
+	return ng
+}
+
 // DispatchedID returns an ID appropriate for a job dispatched against a
 // particular parameterized job
 func DispatchedID(templateID string, t time.Time) string {
@@ -4129,7 +4949,7 @@ func NewRestartPolicy(jobType string) *RestartPolicy {
 	case JobTypeService, JobTypeSystem:
 		rp := DefaultServiceJobRestartPolicy
 		return &rp
-	case JobTypeBatch:
+	case JobTypeBatch, JobTypeSysBatch:
 		rp := DefaultBatchJobRestartPolicy
 		return &rp
 	}
@@ -4450,6 +5270,20 @@ type TaskGroup struct {
 	// Spread can be specified at the task group level to express spreading
 	// allocations across a desired attribute, such as datacenter
 	Spreads []*Spread
+
+	// MaxClientDisconnect, if set, configures the client to allow placed
+	// allocations for tasks in this group to attempt to resume running
+	// without a restart. Allocations on a disconnected client are marked
+	// unknown and only replaced once the duration has elapsed.
+	MaxClientDisconnect *time.Duration
+
+	// Volumes is a map of the volumes required by the task group, keyed by
+	// the name the tasks refer to them by.
+	Volumes map[string]*VolumeRequest
+
+	// Networks are the networks shared by the tasks of the task group. Only
+	// the network mode may be set.
+	Networks Networks
 }
 
 func (tg *TaskGroup) Copy() *TaskGroup {
@@ -4464,6 +5298,8 @@ func (tg *TaskGroup) Copy() *TaskGroup {
 	ntg.ReschedulePolicy = ntg.ReschedulePolicy.Copy()
 	ntg.Affinities = CopySliceAffinities(ntg.Affinities)
 	ntg.Spreads = CopySliceSpreads(ntg.Spreads)
+	ntg.Volumes = CopyMapVolumeRequest(ntg.Volumes)
+	ntg.Networks = ntg.Networks.Copy()
 
 	if tg.Tasks != nil {
 		tasks := make([]*Task, len(ntg.Tasks))
@@ -4478,6 +5314,10 @@ func (tg *TaskGroup) Copy() *TaskGroup {
 	if tg.EphemeralDisk != nil {
 		ntg.EphemeralDisk = tg.EphemeralDisk.Copy()
 	}
+
+	if tg.MaxClientDisconnect != nil {
+		ntg.MaxClientDisconnect = helper.TimeToPtr(*tg.MaxClientDisconnect)
+	}
 	return ntg
 }
 
@@ -4531,7 +5371,7 @@ func (tg *TaskGroup) Validate(j *Job) error {
 			mErr.Errors = append(mErr.Errors, outer)
 		}
 	}
-	if j.Type == JobTypeSystem {
+	if j.IsSystemType() {
 		if tg.Affinities != nil {
 			mErr.Errors = append(mErr.Errors, fmt.Errorf("System jobs may not have an affinity stanza"))
 		}
@@ -4552,7 +5392,7 @@ func (tg *TaskGroup) Validate(j *Job) error {
 		mErr.Errors = append(mErr.Errors, fmt.Errorf("Task Group %v should have a restart policy", tg.Name))
 	}
 
-	if j.Type == JobTypeSystem {
+	if j.IsSystemType() {
 		if tg.Spreads != nil {
 			mErr.Errors = append(mErr.Errors, fmt.Errorf("System jobs may not have a spread stanza"))
 		}
@@ -4565,7 +5405,7 @@ func (tg *TaskGroup) Validate(j *Job) error {
 		}
 	}
 
-	if j.Type == JobTypeSystem {
+	if j.IsSystemType() {
 		if tg.ReschedulePolicy != nil {
 			mErr.Errors = append(mErr.Errors, fmt.Errorf("System jobs should not have a reschedule policy"))
 		}
@@ -4579,6 +5419,14 @@ func (tg *TaskGroup) Validate(j *Job) error {
 		}
 	}
 
+	if tg.MaxClientDisconnect != nil {
+		if j.IsSystemType() {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("System jobs may not set max_client_disconnect"))
+		} else if *tg.MaxClientDisconnect < 0 {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("max_client_disconnect cannot be negative"))
+		}
+	}
+
 	if tg.EphemeralDisk != nil {
 		if err := tg.EphemeralDisk.Validate(); err != nil {
 			mErr.Errors = append(mErr.Errors, err)
@@ -4587,6 +5435,25 @@ func (tg *TaskGroup) Validate(j *Job) error {
 		mErr.Errors = append(mErr.Errors, fmt.Errorf("Task Group %v should have an ephemeral disk object", tg.Name))
 	}
 
+	// Validate the volume requests
+	for name, volume := range tg.Volumes {
+		if err := volume.Validate(); err != nil {
+			outer := fmt.Errorf("Volume %q validation failed: %v", name, err)
+			mErr.Errors = append(mErr.Errors, outer)
+		}
+	}
+
+	// Validate the group networks
+	if len(tg.Networks) > 1 {
+		mErr.Errors = append(mErr.Errors, errors.New("Only one network resource is allowed per task group"))
+	}
+	for idx, net := range tg.Networks {
+		if err := net.validateGroupNetwork(); err != nil {
+			outer := fmt.Errorf("Network %d validation failed: %v", idx+1, err)
+			mErr.Errors = append(mErr.Errors, outer)
+		}
+	}
+
 	// Validate the update strategy
 	if u := tg.Update; u != nil {
 		switch j.Type {
@@ -4617,6 +5484,11 @@ func (tg *TaskGroup) Validate(j *Job) error {
 	// and no duplicated static ports
 	tasks := make(map[string]int)
 	staticPorts := make(map[int]string)
+	for _, net := range tg.Networks {
+		for _, port := range net.ReservedPorts {
+			staticPorts[port.Value] = fmt.Sprintf("group network:%s", port.Label)
+		}
+	}
 	leaderTasks := 0
 	for idx, task := range tg.Tasks {
 		if task.Name == "" {
@@ -4645,6 +5517,15 @@ func (tg *TaskGroup) Validate(j *Job) error {
 				}
 			}
 		}
+
+		// Time-sharing devices is only allowed for low priority batch jobs
+		for _, device := range task.Resources.Devices {
+			if device.Shared && (j.Type != JobTypeBatch || j.Priority >= JobDefaultPriority) {
+				err := fmt.Errorf("Task %s requests shared device %q: shared devices may only be used by %q jobs with a priority below %d",
+					task.Name, device.Name, JobTypeBatch, JobDefaultPriority)
+				mErr.Errors = append(mErr.Errors, err)
+			}
+		}
 	}
 
 	if leaderTasks > 1 {
@@ -4653,7 +5534,7 @@ func (tg *TaskGroup) Validate(j *Job) error {
 
 	// Validate the tasks
 	for _, task := range tg.Tasks {
-		if err := task.Validate(tg.EphemeralDisk, j.Type); err != nil {
+		if err := task.Validate(tg.EphemeralDisk, j.Type, tg.Networks); err != nil {
 			outer := fmt.Errorf("Task %s validation failed: %v", task.Name, err)
 			mErr.Errors = append(mErr.Errors, outer)
 		}
@@ -4669,10 +5550,22 @@ func (tg *TaskGroup) Warnings(j *Job) error {
 	// Validate the update strategy
 	if u := tg.Update; u != nil {
 		// Check the counts are appropriate
-		if u.MaxParallel > tg.Count {
+		if maxParallel := u.MaxParallelFor(tg.Count); maxParallel > tg.Count {
 			mErr.Errors = append(mErr.Errors,
 				fmt.Errorf("Update max parallel count is greater than task group count (%d > %d). "+
-					"A destructive change would result in the simultaneous replacement of all allocations.", u.MaxParallel, tg.Count))
+					"A destructive change would result in the simultaneous replacement of all allocations.", maxParallel, tg.Count))
+		}
+	}
+
+	// Checkpoints are stored in the task's local directory so they are only
+	// available on the node the task is migrated to if the disk migrates
+	if !tg.EphemeralDisk.migrates() {
+		for _, task := range tg.Tasks {
+			if task.Checkpoint {
+				mErr.Errors = append(mErr.Errors,
+					fmt.Errorf("Task %q enables checkpoint but the ephemeral disk is not sticky with migrate enabled. "+
+						"The task will not be checkpointed when migrated.", task.Name))
+			}
 		}
 	}
 
@@ -4935,6 +5828,14 @@ const (
 	AddressModeDriver = "driver"
 )
 
+const (
+	// ServiceProviderConsul registers services with Consul
+	ServiceProviderConsul = "consul"
+
+	// ServiceProviderNomad registers services with the Nomad servers
+	ServiceProviderNomad = "nomad"
+)
+
 // Service represents a Consul service definition in Nomad
 type Service struct {
 	// Name of the service registered with Consul. Consul defaults the
@@ -4951,6 +5852,10 @@ type Service struct {
 	// this service.
 	AddressMode string
 
+	// Provider is the service discovery the service is registered with,
+	// either Consul or the Nomad servers.
+	Provider string
+
 	Tags       []string        // List of tags for the service
 	CanaryTags []string        // List of tags for the service when it is a canary
 	Checks     []*ServiceCheck // List of checks associated with the service
@@ -4990,6 +5895,9 @@ func (s *Service) Canonicalize(job string, taskGroup string, task string) {
 	if len(s.Checks) == 0 {
 		s.Checks = nil
 	}
+	if s.Provider == "" {
+		s.Provider = ServiceProviderConsul
+	}
 
 	s.Name = args.ReplaceEnv(s.Name, map[string]string{
 		"JOB":       job,
@@ -5024,6 +5932,17 @@ func (s *Service) Validate() error {
 		mErr.Errors = append(mErr.Errors, fmt.Errorf("service address_mode must be %q, %q, or %q; not %q", AddressModeAuto, AddressModeHost, AddressModeDriver, s.AddressMode))
 	}
 
+	switch s.Provider {
+	case "", ServiceProviderConsul:
+		// OK
+	case ServiceProviderNomad:
+		if len(s.Checks) != 0 {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("service %q with provider %q does not support checks", s.Name, ServiceProviderNomad))
+		}
+	default:
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("service provider must be %q or %q; not %q", ServiceProviderConsul, ServiceProviderNomad, s.Provider))
+	}
+
 	for _, c := range s.Checks {
 		if s.PortLabel == "" && c.PortLabel == "" && c.RequiresPort() {
 			mErr.Errors = append(mErr.Errors, fmt.Errorf("check %s invalid: check requires a port but neither check nor service %+q have a port", c.Name, s.Name))
@@ -5174,6 +6093,10 @@ type Task struct {
 	// task exits, other tasks will be gracefully terminated.
 	Leader bool
 
+	// Checkpoint marks the task to be checkpointed by its driver when its
+	// allocation is migrated, and restored on the node it is migrated to.
+	Checkpoint bool
+
 	// ShutdownDelay is the duration of the delay between deregistering a
 	// task from Consul and sending it a signal to shutdown. See #2441
 	ShutdownDelay time.Duration
@@ -5183,6 +6106,10 @@ type Task struct {
 	// KillSignal is the kill signal to use for the task. This is an optional
 	// specification and defaults to SIGINT
 	KillSignal string
+
+	// Actions are the named commands that can be run on demand inside the
+	// task.
+	Actions []*Action
 }
 
 func (t *Task) Copy() *Task {
@@ -5231,6 +6158,14 @@ func (t *Task) Copy() *Task {
 		nt.Templates = templates
 	}
 
+	if t.Actions != nil {
+		actions := make([]*Action, len(t.Actions))
+		for i, a := range nt.Actions {
+			actions[i] = a.Copy()
+		}
+		nt.Actions = actions
+	}
+
 	return nt
 }
 
@@ -5277,8 +6212,9 @@ func (t *Task) GoString() string {
 	return fmt.Sprintf("*%#v", *t)
 }
 
-// Validate is used to sanity check a task
-func (t *Task) Validate(ephemeralDisk *EphemeralDisk, jobType string) error {
+// Validate is used to sanity check a task. The networks of its task group are
+// given to validate the ports its services use.
+func (t *Task) Validate(ephemeralDisk *EphemeralDisk, jobType string, tgNetworks Networks) error {
 	var mErr multierror.Error
 	if t.Name == "" {
 		mErr.Errors = append(mErr.Errors, errors.New("Missing task name"))
@@ -5325,7 +6261,7 @@ func (t *Task) Validate(ephemeralDisk *EphemeralDisk, jobType string) error {
 		}
 	}
 
-	if jobType == JobTypeSystem {
+	if jobType == JobTypeSystem || jobType == JobTypeSysBatch {
 		if t.Affinities != nil {
 			mErr.Errors = append(mErr.Errors, fmt.Errorf("System jobs may not have an affinity stanza"))
 		}
@@ -5339,7 +6275,7 @@ func (t *Task) Validate(ephemeralDisk *EphemeralDisk, jobType string) error {
 	}
 
 	// Validate Services
-	if err := validateServices(t); err != nil {
+	if err := validateServices(t, tgNetworks); err != nil {
 		mErr.Errors = append(mErr.Errors, err)
 	}
 
@@ -5387,12 +6323,27 @@ func (t *Task) Validate(ephemeralDisk *EphemeralDisk, jobType string) error {
 		}
 	}
 
+	actions := make(map[string]int, len(t.Actions))
+	for idx, a := range t.Actions {
+		if err := a.Validate(); err != nil {
+			outer := fmt.Errorf("Action %d validation failed: %s", idx+1, err)
+			mErr.Errors = append(mErr.Errors, outer)
+		}
+
+		if other, ok := actions[a.Name]; ok {
+			outer := fmt.Errorf("Action %d has same name as %d", idx+1, other)
+			mErr.Errors = append(mErr.Errors, outer)
+		} else {
+			actions[a.Name] = idx + 1
+		}
+	}
+
 	return mErr.ErrorOrNil()
 }
 
 // validateServices takes a task and validates the services within it are valid
 // and reference ports that exist.
-func validateServices(t *Task) error {
+func validateServices(t *Task, tgNetworks Networks) error {
 	var mErr multierror.Error
 
 	// Ensure that services don't ask for nonexistent ports and their names are
@@ -5479,14 +6430,17 @@ func validateServices(t *Task) error {
 		}
 	}
 
-	// Get the set of port labels.
+	// Get the set of port labels, including the ports of the group network
+	// shared by the tasks.
 	portLabels := make(map[string]struct{})
+	networks := tgNetworks
 	if t.Resources != nil {
-		for _, network := range t.Resources.Networks {
-			ports := network.PortLabels()
-			for portLabel := range ports {
-				portLabels[portLabel] = struct{}{}
-			}
+		networks = append(networks[:len(networks):len(networks)], t.Resources.Networks...)
+	}
+	for _, network := range networks {
+		ports := network.PortLabels()
+		for portLabel := range ports {
+			portLabels[portLabel] = struct{}{}
 		}
 	}
 
@@ -5674,6 +6628,39 @@ const (
 	TaskStateDead    = "dead"    // Terminal state of task.
 )
 
+// Action is a named command of a task that can be run on demand inside the
+// allocations of the task, for example to flush a cache.
+type Action struct {
+	// Name is the name of the action, unique within the task.
+	Name string
+
+	// Command is the command to execute and Args its arguments.
+	Command string
+	Args    []string
+}
+
+func (a *Action) Copy() *Action {
+	if a == nil {
+		return nil
+	}
+	na := new(Action)
+	*na = *a
+	na.Args = helper.CopySliceString(a.Args)
+	return na
+}
+
+// Validate returns an error if the action is invalid.
+func (a *Action) Validate() error {
+	var mErr multierror.Error
+	if a.Name == "" {
+		mErr.Errors = append(mErr.Errors, errors.New("Missing action name"))
+	}
+	if a.Command == "" {
+		mErr.Errors = append(mErr.Errors, errors.New("Missing action command"))
+	}
+	return mErr.ErrorOrNil()
+}
+
 // TaskState tracks the current state of a task and events that caused state
 // transitions.
 type TaskState struct {
@@ -5700,6 +6687,34 @@ type TaskState struct {
 
 	// Series of task events that transition the state of the task.
 	Events []*TaskEvent
+
+	// TaskHandle is the handle of a task running on a remote task driver. It
+	// is propagated to the replacement of a lost allocation so the task can
+	// be recovered instead of started again.
+	TaskHandle *TaskHandle
+}
+
+// TaskHandle is the portion of a driver's task handle needed to recover a
+// task running on a remote task driver from another client.
+type TaskHandle struct {
+	// Driver is the name of the driver running the task.
+	Driver string
+
+	// DriverState is the encoded state of the task in the driver.
+	DriverState []byte
+}
+
+func (h *TaskHandle) Copy() *TaskHandle {
+	if h == nil {
+		return nil
+	}
+
+	newTH := TaskHandle{
+		Driver:      h.Driver,
+		DriverState: make([]byte, len(h.DriverState)),
+	}
+	copy(newTH.DriverState, h.DriverState)
+	return &newTH
 }
 
 // NewTaskState returns a TaskState initialized in the Pending state.
@@ -5730,6 +6745,8 @@ func (ts *TaskState) Copy() *TaskState {
 			copy.Events[i] = e.Copy()
 		}
 	}
+
+	copy.TaskHandle = ts.TaskHandle.Copy()
 	return copy
 }
 
@@ -5824,6 +6841,23 @@ const (
 
 	// TaskLeaderDead indicates that the leader task within the has finished.
 	TaskLeaderDead = "Leader Task Dead"
+
+	// TaskCheckpointed indicates that the state of the task was checkpointed
+	// before it was stopped, so it can be restored where it is migrated to.
+	TaskCheckpointed = "Checkpointed"
+
+	// TaskCheckpointFailed indicates that checkpointing the task failed and
+	// it will be stopped without a checkpoint.
+	TaskCheckpointFailed = "Checkpoint Failed"
+
+	// TaskDetached indicates that a task running on a remote task driver was
+	// left running when its allocation stopped because the replacement of the
+	// lost allocation recovered it.
+	TaskDetached = "Detached"
+
+	// TaskOOMKilled indicates that the task was started and was killed by the
+	// OOM killer for exceeding its memory limit.
+	TaskOOMKilled = "OOM Killed"
 )
 
 // TaskEvent is an event that effects the state of a task and contains meta-data
@@ -5979,8 +7013,11 @@ func (event *TaskEvent) PopulateEventDisplayMessage() {
 		} else {
 			desc = "Task successfully killed"
 		}
-	case TaskTerminated:
+	case TaskTerminated, TaskOOMKilled:
 		var parts []string
+		if event.Type == TaskOOMKilled {
+			parts = append(parts, "Task exceeded its memory limit and was killed by the OOM killer")
+		}
 		parts = append(parts, fmt.Sprintf("Exit Code: %d", event.ExitCode))
 
 		if event.Signal != 0 {
@@ -6033,6 +7070,10 @@ func (event *TaskEvent) PopulateEventDisplayMessage() {
 		desc = event.DriverMessage
 	case TaskLeaderDead:
 		desc = "Leader Task in Group dead"
+	case TaskCheckpointed:
+		desc = "Task state checkpointed for migration"
+	case TaskDetached:
+		desc = "Task left running for the replacement allocation"
 	default:
 		desc = event.Message
 	}
@@ -6648,6 +7689,12 @@ func (d *EphemeralDisk) Validate() error {
 	return nil
 }
 
+// migrates returns whether the data of the disk is migrated along with the
+// allocation to another node.
+func (d *EphemeralDisk) migrates() bool {
+	return d != nil && d.Sticky && d.Migrate
+}
+
 // Copy copies the EphemeralDisk struct and returns a new one
 func (d *EphemeralDisk) Copy() *EphemeralDisk {
 	ld := new(EphemeralDisk)
@@ -6752,6 +7799,13 @@ const (
 	DeploymentStatusSuccessful = "successful"
 	DeploymentStatusCancelled  = "cancelled"
 
+	// DeploymentStatusPending is the status of the deployment of a
+	// multiregion job waiting for the deployments of the regions before it
+	// in the rollout, and DeploymentStatusBlocked the status of a deployment
+	// that is complete but waiting for the deployments of its peer regions.
+	DeploymentStatusPending = "pending"
+	DeploymentStatusBlocked = "blocked"
+
 	// DeploymentStatusDescriptions are the various descriptions of the states a
 	// deployment can be in.
 	DeploymentStatusDescriptionRunning               = "Deployment is running"
@@ -6763,6 +7817,9 @@ const (
 	DeploymentStatusDescriptionFailedAllocations     = "Failed due to unhealthy allocations"
 	DeploymentStatusDescriptionProgressDeadline      = "Failed due to progress deadline"
 	DeploymentStatusDescriptionFailedByUser          = "Deployment marked as failed"
+	DeploymentStatusDescriptionPendingForPeer        = "Deployment is pending, waiting for peer region"
+	DeploymentStatusDescriptionBlocked               = "Deployment is complete but waiting for peer region"
+	DeploymentStatusDescriptionFailedByPeer          = "Failed because of an error in peer region"
 )
 
 // DeploymentStatusDescriptionRollback is used to get the status description of
@@ -6822,13 +7879,17 @@ type Deployment struct {
 	// status.
 	StatusDescription string
 
+	// IsMultiregion marks the deployment of a multiregion job, which is
+	// coordinated with the deployments of the peer regions.
+	IsMultiregion bool
+
 	CreateIndex uint64
 	ModifyIndex uint64
 }
 
 // NewDeployment creates a new deployment given the job.
 func NewDeployment(job *Job) *Deployment {
-	return &Deployment{
+	d := &Deployment{
 		ID:                 uuid.Generate(),
 		Namespace:          job.Namespace,
 		JobID:              job.ID,
@@ -6840,6 +7901,17 @@ func NewDeployment(job *Job) *Deployment {
 		StatusDescription:  DeploymentStatusDescriptionRunning,
 		TaskGroups:         make(map[string]*DeploymentState, len(job.TaskGroups)),
 	}
+
+	// The deployment of a multiregion job waits for the regions before it in
+	// the rollout
+	if job.IsMultiregion() {
+		d.IsMultiregion = true
+		if job.Multiregion.StartsPending(job.Region) {
+			d.Status = DeploymentStatusPending
+			d.StatusDescription = DeploymentStatusDescriptionPendingForPeer
+		}
+	}
+	return d
 }
 
 func (d *Deployment) Copy() *Deployment {
@@ -6864,7 +7936,7 @@ func (d *Deployment) Copy() *Deployment {
 // Active returns whether the deployment is active or terminal.
 func (d *Deployment) Active() bool {
 	switch d.Status {
-	case DeploymentStatusRunning, DeploymentStatusPaused:
+	case DeploymentStatusRunning, DeploymentStatusPaused, DeploymentStatusPending, DeploymentStatusBlocked:
 		return true
 	default:
 		return false
@@ -6920,6 +7992,10 @@ type DeploymentState struct {
 	// reverted on failure
 	AutoRevert bool
 
+	// AutoPromote marks promotion triggered automatically by healthy canaries
+	// copied from TaskGroup UpdateStrategy in scheduler.reconcile
+	AutoPromote bool
+
 	// ProgressDeadline is the deadline by which an allocation must transition
 	// to healthy before the deployment is considered failed.
 	ProgressDeadline time.Duration
@@ -6960,6 +8036,7 @@ func (d *DeploymentState) GoString() string {
 	base += fmt.Sprintf("\n\tHealthy: %d", d.HealthyAllocs)
 	base += fmt.Sprintf("\n\tUnhealthy: %d", d.UnhealthyAllocs)
 	base += fmt.Sprintf("\n\tAutoRevert: %v", d.AutoRevert)
+	base += fmt.Sprintf("\n\tAutoPromote: %v", d.AutoPromote)
 	return base
 }
 
@@ -7101,6 +8178,7 @@ const (
 	AllocClientStatusComplete = "complete"
 	AllocClientStatusFailed   = "failed"
 	AllocClientStatusLost     = "lost"
+	AllocClientStatusUnknown  = "unknown"
 )
 
 // Allocation is used to allocate the placement of a task group to a node.
@@ -7200,6 +8278,14 @@ type Allocation struct {
 	// to stop running because it got preempted
 	PreemptedByAllocation string
 
+	// AllocStates track meaningful state changes made by the servers, such as
+	// when the allocation was marked unknown because its client disconnected
+	AllocStates []*AllocState
+
+	// NetworkStatus captures the network the client configured for the
+	// allocation when its task group isn't using the host network
+	NetworkStatus *AllocNetworkStatus
+
 	// Raft Indexes
 	CreateIndex uint64
 	ModifyIndex uint64
@@ -7216,6 +8302,21 @@ type Allocation struct {
 	ModifyTime int64
 }
 
+// AllocStateField is the field of an allocation whose change is recorded in
+// an AllocState.
+type AllocStateField uint8
+
+const (
+	AllocStateFieldClientStatus AllocStateField = iota
+)
+
+// AllocState records a single state change of an allocation.
+type AllocState struct {
+	Field AllocStateField
+	Value string
+	Time  time.Time
+}
+
 // Index returns the index of the allocation. If the allocation is from a task
 // group with count greater than 1, there will be multiple allocations for it.
 func (a *Allocation) Index() uint {
@@ -7275,9 +8376,38 @@ func (a *Allocation) copyImpl(job bool) *Allocation {
 
 	na.RescheduleTracker = a.RescheduleTracker.Copy()
 	na.PreemptedAllocations = helper.CopySliceString(a.PreemptedAllocations)
+	na.NetworkStatus = a.NetworkStatus.Copy()
+
+	if a.AllocStates != nil {
+		states := make([]*AllocState, len(a.AllocStates))
+		for i, s := range a.AllocStates {
+			ns := *s
+			states[i] = &ns
+		}
+		na.AllocStates = states
+	}
 	return na
 }
 
+// LastUnknown returns the last time the allocation was marked unknown, or the
+// zero time if it never was.
+func (a *Allocation) LastUnknown() time.Time {
+	var last time.Time
+	for _, s := range a.AllocStates {
+		if s.Field == AllocStateFieldClientStatus && s.Value == AllocClientStatusUnknown && s.Time.After(last) {
+			last = s.Time
+		}
+	}
+	return last
+}
+
+// DisconnectTimeout returns the time at which an allocation marked unknown
+// because of a disconnected client is considered lost, given the
+// max_client_disconnect of its task group.
+func (a *Allocation) DisconnectTimeout(maxDisconnect time.Duration) time.Time {
+	return a.LastUnknown().Add(maxDisconnect)
+}
+
 // TerminalStatus returns if the desired or actual status is terminal and
 // will no longer transition.
 func (a *Allocation) TerminalStatus() bool {
@@ -7680,6 +8810,11 @@ type AllocMetric struct {
 	// ScoreMetaData is a slice of top scoring nodes displayed in the CLI
 	ScoreMetaData []*NodeScoreMeta
 
+	// NodeFailures is the reason each node was rejected, in the order the
+	// nodes were evaluated. Only the first MaxRetainedNodeFailures nodes are
+	// retained, and only for failed placements.
+	NodeFailures []*NodeFailureMeta
+
 	// nodeScoreMeta is used to keep scores for a single node id. It is cleared out after
 	// we receive normalized score during the last step of the scoring stack.
 	nodeScoreMeta *NodeScoreMeta
@@ -7713,6 +8848,7 @@ func (a *AllocMetric) Copy() *AllocMetric {
 	na.QuotaExhausted = helper.CopySliceString(na.QuotaExhausted)
 	na.Scores = helper.CopyMapStringFloat64(na.Scores)
 	na.ScoreMetaData = CopySliceNodeScoreMeta(na.ScoreMetaData)
+	na.NodeFailures = CopySliceNodeFailureMeta(na.NodeFailures)
 	return na
 }
 
@@ -7734,6 +8870,7 @@ func (a *AllocMetric) FilterNode(node *Node, constraint string) {
 		}
 		a.ConstraintFiltered[constraint] += 1
 	}
+	a.addNodeFailure(node, &NodeFailureMeta{Constraint: constraint})
 }
 
 func (a *AllocMetric) ExhaustedNode(node *Node, dimension string) {
@@ -7750,6 +8887,18 @@ func (a *AllocMetric) ExhaustedNode(node *Node, dimension string) {
 		}
 		a.DimensionExhausted[dimension] += 1
 	}
+	a.addNodeFailure(node, &NodeFailureMeta{Dimension: dimension})
+}
+
+// addNodeFailure records the reason the node was rejected if fewer than
+// MaxRetainedNodeFailures nodes have been recorded.
+func (a *AllocMetric) addNodeFailure(node *Node, failure *NodeFailureMeta) {
+	if node == nil || len(a.NodeFailures) >= MaxRetainedNodeFailures {
+		return
+	}
+	failure.NodeID = node.ID
+	failure.NodeName = node.Name
+	a.NodeFailures = append(a.NodeFailures, failure)
 }
 
 func (a *AllocMetric) ExhaustQuota(dimensions []string) {
@@ -7812,6 +8961,36 @@ type NodeScoreMeta struct {
 	NormScore float64
 }
 
+// NodeFailureMeta captures why a node was rejected for a placement. Either
+// Constraint is set when the node was filtered or Dimension is set when the
+// node was exhausted of a resource.
+type NodeFailureMeta struct {
+	NodeID   string
+	NodeName string
+
+	// Constraint is the constraint or check that filtered the node
+	Constraint string
+
+	// Dimension is the resource dimension that was exhausted on the node
+	Dimension string
+}
+
+func (f *NodeFailureMeta) Copy() *NodeFailureMeta {
+	if f == nil {
+		return nil
+	}
+	nf := new(NodeFailureMeta)
+	*nf = *f
+	return nf
+}
+
+func (f *NodeFailureMeta) String() string {
+	if f.Dimension != "" {
+		return fmt.Sprintf("%s exhausted %q", f.NodeID, f.Dimension)
+	}
+	return fmt.Sprintf("%s filtered %q", f.NodeID, f.Constraint)
+}
+
 func (s *NodeScoreMeta) Copy() *NodeScoreMeta {
 	if s == nil {
 		return nil
@@ -7833,6 +9012,26 @@ func (s *NodeScoreMeta) Data() interface{} {
 	return s
 }
 
+// AllocNetworkStatus captures the network namespace the client configured for
+// an allocation and the address the network assigned it.
+type AllocNetworkStatus struct {
+	// InterfaceName is the name of the interface of the allocation in its
+	// network namespace
+	InterfaceName string
+
+	// Address is the IP address assigned to the allocation
+	Address string
+}
+
+func (a *AllocNetworkStatus) Copy() *AllocNetworkStatus {
+	if a == nil {
+		return nil
+	}
+	c := new(AllocNetworkStatus)
+	*c = *a
+	return c
+}
+
 // AllocDeploymentStatus captures the status of the allocation as part of the
 // deployment. This can include things like if the allocation has been marked as
 // healthy.
@@ -7914,6 +9113,7 @@ const (
 const (
 	EvalTriggerJobRegister       = "job-register"
 	EvalTriggerJobDeregister     = "job-deregister"
+	EvalTriggerScaling           = "job-scaling"
 	EvalTriggerPeriodicJob       = "periodic-job"
 	EvalTriggerNodeDrain         = "node-drain"
 	EvalTriggerNodeUpdate        = "node-update"
@@ -7925,6 +9125,8 @@ const (
 	EvalTriggerRetryFailedAlloc  = "alloc-failure"
 	EvalTriggerQueuedAllocs      = "queued-allocs"
 	EvalTriggerPreemption        = "preemption"
+	EvalTriggerAllocStop         = "alloc-stop"
+	EvalTriggerMaxDisconnect     = "max-disconnect-timeout"
 )
 
 const (
@@ -7950,6 +9152,16 @@ const (
 	// check if they are terminal. If so, we delete these out of the system.
 	CoreJobDeploymentGC = "deployment-gc"
 
+	// CoreJobLocalTokenExpiredGC is used for the garbage collection of
+	// expired local ACL tokens. We periodically scan the local tokens and
+	// delete the expired ones out of the system.
+	CoreJobLocalTokenExpiredGC = "local-token-expired-gc"
+
+	// CoreJobGlobalTokenExpiredGC is used for the garbage collection of
+	// expired global ACL tokens. It only runs in the authoritative region,
+	// the deletions being replicated to the other regions.
+	CoreJobGlobalTokenExpiredGC = "global-token-expired-gc"
+
 	// CoreJobForceGC is used to force garbage collection of all GCable objects.
 	CoreJobForceGC = "force-gc"
 )
@@ -8354,6 +9566,11 @@ func (p *Plan) AppendAlloc(alloc *Allocation) {
 	// Normalize the job
 	alloc.Job = nil
 
+	// The nodes rejected are only reported for failed placements
+	if alloc.Metrics != nil {
+		alloc.Metrics.NodeFailures = nil
+	}
+
 	p.NodeAllocation[node] = append(existing, alloc)
 }
 
@@ -8713,14 +9930,26 @@ type ACLPolicyUpsertRequest struct {
 
 // ACLToken represents a client token which is used to Authenticate
 type ACLToken struct {
-	AccessorID  string   // Public Accessor ID (UUID)
-	SecretID    string   // Secret ID, private (UUID)
-	Name        string   // Human friendly name
-	Type        string   // Client or Management
-	Policies    []string // Policies this token ties to
-	Global      bool     // Global or Region local
-	Hash        []byte
-	CreateTime  time.Time // Time of creation
+	AccessorID string   // Public Accessor ID (UUID)
+	SecretID   string   // Secret ID, private (UUID)
+	Name       string   // Human friendly name
+	Type       string   // Client or Management
+	Policies   []string // Policies this token ties to
+	Roles      []string // Roles this token ties to
+	Global     bool     // Global or Region local
+	Hash       []byte
+	CreateTime time.Time // Time of creation
+
+	// ExpirationTime is the time after which the token is no longer valid,
+	// nil if the token doesn't expire. Tokens created by logging in with an
+	// auth method expire after the max token TTL of the method.
+	ExpirationTime *time.Time
+
+	// ExpirationTTL is the duration the token is valid for once created,
+	// used to set its ExpirationTime. Zero creates a token that doesn't
+	// expire.
+	ExpirationTTL time.Duration
+
 	CreateIndex uint64
 	ModifyIndex uint64
 }
@@ -8738,13 +9967,17 @@ var (
 )
 
 type ACLTokenListStub struct {
-	AccessorID  string
-	Name        string
-	Type        string
-	Policies    []string
-	Global      bool
-	Hash        []byte
-	CreateTime  time.Time
+	AccessorID string
+	Name       string
+	Type       string
+	Policies   []string
+	Roles      []string
+	Global     bool
+	Hash       []byte
+	CreateTime time.Time
+
+	ExpirationTime *time.Time
+
 	CreateIndex uint64
 	ModifyIndex uint64
 }
@@ -8763,6 +9996,9 @@ func (a *ACLToken) SetHash() []byte {
 	for _, policyName := range a.Policies {
 		hash.Write([]byte(policyName))
 	}
+	for _, roleName := range a.Roles {
+		hash.Write([]byte("role:" + roleName))
+	}
 	if a.Global {
 		hash.Write([]byte("global"))
 	} else {
@@ -8779,18 +10015,28 @@ func (a *ACLToken) SetHash() []byte {
 
 func (a *ACLToken) Stub() *ACLTokenListStub {
 	return &ACLTokenListStub{
-		AccessorID:  a.AccessorID,
-		Name:        a.Name,
-		Type:        a.Type,
-		Policies:    a.Policies,
-		Global:      a.Global,
-		Hash:        a.Hash,
-		CreateTime:  a.CreateTime,
+		AccessorID: a.AccessorID,
+		Name:       a.Name,
+		Type:       a.Type,
+		Policies:   a.Policies,
+		Roles:      a.Roles,
+		Global:     a.Global,
+		Hash:       a.Hash,
+		CreateTime: a.CreateTime,
+
+		ExpirationTime: a.ExpirationTime,
+
 		CreateIndex: a.CreateIndex,
 		ModifyIndex: a.ModifyIndex,
 	}
 }
 
+// IsExpired returns whether the token has an expiration time at or before
+// the given time.
+func (a *ACLToken) IsExpired(t time.Time) bool {
+	return a.ExpirationTime != nil && !t.Before(*a.ExpirationTime)
+}
+
 // Validate is used to sanity check a token
 func (a *ACLToken) Validate() error {
 	var mErr multierror.Error
@@ -8799,16 +10045,22 @@ func (a *ACLToken) Validate() error {
 	}
 	switch a.Type {
 	case ACLClientToken:
-		if len(a.Policies) == 0 {
-			mErr.Errors = append(mErr.Errors, fmt.Errorf("client token missing policies"))
+		if len(a.Policies) == 0 && len(a.Roles) == 0 {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("client token missing policies or roles"))
 		}
 	case ACLManagementToken:
 		if len(a.Policies) != 0 {
 			mErr.Errors = append(mErr.Errors, fmt.Errorf("management token cannot be associated with policies"))
 		}
+		if len(a.Roles) != 0 {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("management token cannot be associated with roles"))
+		}
 	default:
 		mErr.Errors = append(mErr.Errors, fmt.Errorf("token type must be client or management"))
 	}
+	if a.ExpirationTTL < 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("expiration TTL cannot be negative"))
+	}
 	return mErr.ErrorOrNil()
 }
 
@@ -8830,6 +10082,24 @@ func (a *ACLToken) PolicySubset(policies []string) bool {
 	return true
 }
 
+// RoleSubset checks if a given set of roles is a subset of the token
+func (a *ACLToken) RoleSubset(roles []string) bool {
+	// Hot-path the management tokens, which can read all roles.
+	if a.Type == ACLManagementToken {
+		return true
+	}
+	associatedRoles := make(map[string]struct{}, len(a.Roles))
+	for _, role := range a.Roles {
+		associatedRoles[role] = struct{}{}
+	}
+	for _, role := range roles {
+		if _, ok := associatedRoles[role]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
 // ACLTokenListRequest is used to request a list of tokens
 type ACLTokenListRequest struct {
 	GlobalOnly bool
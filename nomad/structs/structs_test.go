@@ -10,6 +10,7 @@ import (
 
 	"github.com/hashicorp/consul/api"
 	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -133,6 +134,50 @@ func TestJob_Warnings(t *testing.T) {
 				},
 			},
 		},
+		{
+			Name:     "Checkpoint without migrating ephemeral disk",
+			Expected: []string{`Task "web" enables checkpoint`},
+			Job: &Job{
+				Type: JobTypeService,
+				TaskGroups: []*TaskGroup{
+					{
+						Name:  "foo",
+						Count: 1,
+						EphemeralDisk: &EphemeralDisk{
+							Sticky: true,
+						},
+						Tasks: []*Task{
+							{
+								Name:       "web",
+								Checkpoint: true,
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			Name: "Checkpoint with migrating ephemeral disk",
+			Job: &Job{
+				Type: JobTypeService,
+				TaskGroups: []*TaskGroup{
+					{
+						Name:  "foo",
+						Count: 1,
+						EphemeralDisk: &EphemeralDisk{
+							Sticky:  true,
+							Migrate: true,
+						},
+						Tasks: []*Task{
+							{
+								Name:       "web",
+								Checkpoint: true,
+							},
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for _, c := range cases {
@@ -422,6 +467,40 @@ func TestJob_SystemJob_Validate(t *testing.T) {
 
 }
 
+func TestJob_SysBatchJob_Validate(t *testing.T) {
+	j := testJob()
+	j.Type = JobTypeSysBatch
+	j.TaskGroups[0].ReschedulePolicy = nil
+	j.Canonicalize()
+
+	err := j.Validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Count cannot exceed 1 with sysbatch scheduler")
+
+	j.TaskGroups[0].Count = 1
+	require.NoError(t, j.Validate())
+
+	// Sysbatch jobs may be periodic and parameterized
+	j.Periodic = &PeriodicConfig{
+		Enabled:  true,
+		SpecType: PeriodicSpecCron,
+		Spec:     "0 3 * * *",
+	}
+	require.NoError(t, j.Validate())
+
+	j.Periodic = nil
+	j.ParameterizedJob = &ParameterizedJobConfig{
+		Payload: DispatchPayloadOptional,
+	}
+	require.NoError(t, j.Validate())
+
+	// Sysbatch jobs may not be rescheduled
+	j.TaskGroups[0].ReschedulePolicy = NewReschedulePolicy(JobTypeBatch)
+	err = j.Validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "should not have a reschedule policy")
+}
+
 func TestJob_VaultPolicies(t *testing.T) {
 	j0 := &Job{}
 	e0 := make(map[string]map[string]*Vault, 0)
@@ -770,12 +849,140 @@ func TestTaskGroup_Validate(t *testing.T) {
 	if !strings.Contains(err.Error(), "System jobs should not have a reschedule policy") {
 		t.Fatalf("err: %s", err)
 	}
+
+	// Shared devices are only allowed for low priority batch jobs
+	tg = &TaskGroup{
+		Name:  "web",
+		Count: 1,
+		Tasks: []*Task{
+			{
+				Name: "web",
+				Resources: &Resources{
+					Devices: []*RequestedDevice{
+						{Name: "nvidia/gpu", Count: 1, Shared: true},
+					},
+				},
+			},
+		},
+	}
+	for _, jobType := range []string{JobTypeService, JobTypeBatch} {
+		j.Type = jobType
+		j.Priority = JobDefaultPriority
+		err = tg.Validate(j)
+		if err == nil || !strings.Contains(err.Error(), "shared devices may only be used") {
+			t.Fatalf("err: %v", err)
+		}
+	}
+	j.Priority = JobDefaultPriority - 1
+	err = tg.Validate(j)
+	if err != nil && strings.Contains(err.Error(), "shared devices") {
+		t.Fatalf("err: %s", err)
+	}
+
+	// max_client_disconnect must not be negative or set on system jobs
+	tg.MaxClientDisconnect = helper.TimeToPtr(-1 * time.Second)
+	j.Type = JobTypeService
+	err = tg.Validate(j)
+	if err == nil || !strings.Contains(err.Error(), "max_client_disconnect cannot be negative") {
+		t.Fatalf("err: %v", err)
+	}
+	tg.MaxClientDisconnect = helper.TimeToPtr(time.Hour)
+	j.Type = JobTypeSystem
+	err = tg.Validate(j)
+	if err == nil || !strings.Contains(err.Error(), "System jobs may not set max_client_disconnect") {
+		t.Fatalf("err: %v", err)
+	}
+}
+
+func TestTaskGroup_Validate_Networks(t *testing.T) {
+	j := testJob()
+	tg := j.TaskGroups[0]
+
+	tg.Networks = Networks{{Mode: "cni/mynet"}}
+	require.NoError(t, tg.Validate(j))
+
+	tg.Networks = Networks{{
+		Mode:          "bridge",
+		ReservedPorts: []Port{{Label: "metrics", Value: 9100}},
+		DynamicPorts:  []Port{{Label: "api", To: 8080}},
+	}}
+	require.NoError(t, tg.Validate(j))
+
+	// Services may use the ports of the group network
+	tg.Tasks[0].Services[0].PortLabel = "api"
+	require.NoError(t, tg.Validate(j))
+	tg.Tasks[0].Services[0].PortLabel = "http"
+
+	cases := []struct {
+		Networks Networks
+		Err      string
+	}{
+		{
+			Networks: Networks{{Mode: "overlay"}},
+			Err:      `Unsupported network mode "overlay"`,
+		},
+		{
+			Networks: Networks{{Mode: "cni/"}},
+			Err:      "missing the CNI network name",
+		},
+		{
+			Networks: Networks{{Mode: "cni/mynet", MBits: 10}},
+			Err:      "may only set the network mode and ports",
+		},
+		{
+			Networks: Networks{{Mode: "cni/mynet", DynamicPorts: []Port{{Label: "api"}}}},
+			Err:      `Only "bridge" task group networks may request ports`,
+		},
+		{
+			Networks: Networks{{Mode: "bridge", DynamicPorts: []Port{{Label: "api"}, {Label: "api"}}}},
+			Err:      `Port label "api" is duplicate`,
+		},
+		{
+			Networks: Networks{{Mode: "bridge", DynamicPorts: []Port{{Label: "api", To: 70000}}}},
+			Err:      `Port "api" has invalid mapped port 70000`,
+		},
+		{
+			Networks: Networks{{Mode: "cni/mynet"}, {Mode: "host"}},
+			Err:      "Only one network resource is allowed",
+		},
+	}
+	for _, c := range cases {
+		tg.Networks = c.Networks
+		err := tg.Validate(j)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), c.Err)
+	}
+}
+
+func TestNetworkResource_CNINetwork(t *testing.T) {
+	name, ok := (&NetworkResource{Mode: "cni/mynet"}).CNINetwork()
+	require.True(t, ok)
+	require.Equal(t, "mynet", name)
+
+	_, ok = (&NetworkResource{Mode: NetworkModeHost}).CNINetwork()
+	require.False(t, ok)
+
+	name, ok = Networks{{}, {Mode: "cni/other"}}.CNINetwork()
+	require.True(t, ok)
+	require.Equal(t, "other", name)
+}
+
+func TestTaskGroup_Copy_MaxClientDisconnect(t *testing.T) {
+	tg := &TaskGroup{
+		Name:                "web",
+		MaxClientDisconnect: helper.TimeToPtr(time.Hour),
+	}
+	c := tg.Copy()
+	require.Equal(t, tg.MaxClientDisconnect, c.MaxClientDisconnect)
+
+	*c.MaxClientDisconnect = time.Minute
+	require.Equal(t, time.Hour, *tg.MaxClientDisconnect)
 }
 
 func TestTask_Validate(t *testing.T) {
 	task := &Task{}
 	ephemeralDisk := DefaultEphemeralDisk()
-	err := task.Validate(ephemeralDisk, JobTypeBatch)
+	err := task.Validate(ephemeralDisk, JobTypeBatch, nil)
 	mErr := err.(*multierror.Error)
 	if !strings.Contains(mErr.Errors[0].Error(), "task name") {
 		t.Fatalf("err: %s", err)
@@ -788,7 +995,7 @@ func TestTask_Validate(t *testing.T) {
 	}
 
 	task = &Task{Name: "web/foo"}
-	err = task.Validate(ephemeralDisk, JobTypeBatch)
+	err = task.Validate(ephemeralDisk, JobTypeBatch, nil)
 	mErr = err.(*multierror.Error)
 	if !strings.Contains(mErr.Errors[0].Error(), "slashes") {
 		t.Fatalf("err: %s", err)
@@ -805,7 +1012,7 @@ func TestTask_Validate(t *testing.T) {
 		LogConfig: DefaultLogConfig(),
 	}
 	ephemeralDisk.SizeMB = 200
-	err = task.Validate(ephemeralDisk, JobTypeBatch)
+	err = task.Validate(ephemeralDisk, JobTypeBatch, nil)
 	if err != nil {
 		t.Fatalf("err: %s", err)
 	}
@@ -819,7 +1026,7 @@ func TestTask_Validate(t *testing.T) {
 			LTarget: "${meta.rack}",
 		})
 
-	err = task.Validate(ephemeralDisk, JobTypeBatch)
+	err = task.Validate(ephemeralDisk, JobTypeBatch, nil)
 	mErr = err.(*multierror.Error)
 	if !strings.Contains(mErr.Errors[0].Error(), "task level: distinct_hosts") {
 		t.Fatalf("err: %s", err)
@@ -902,7 +1109,7 @@ func TestTask_Validate_Services(t *testing.T) {
 		},
 	}
 
-	err := task.Validate(ephemeralDisk, JobTypeService)
+	err := task.Validate(ephemeralDisk, JobTypeService, nil)
 	if err == nil {
 		t.Fatal("expected an error")
 	}
@@ -923,7 +1130,7 @@ func TestTask_Validate_Services(t *testing.T) {
 		t.Fatalf("err: %v", err)
 	}
 
-	if err = task1.Validate(ephemeralDisk, JobTypeService); err != nil {
+	if err = task1.Validate(ephemeralDisk, JobTypeService, nil); err != nil {
 		t.Fatalf("err : %v", err)
 	}
 }
@@ -982,7 +1189,7 @@ func TestTask_Validate_Service_AddressMode_Ok(t *testing.T) {
 	for _, service := range cases {
 		task := getTask(service)
 		t.Run(service.Name, func(t *testing.T) {
-			if err := task.Validate(ephemeralDisk, JobTypeService); err != nil {
+			if err := task.Validate(ephemeralDisk, JobTypeService, nil); err != nil {
 				t.Fatalf("unexpected err: %v", err)
 			}
 		})
@@ -1035,7 +1242,7 @@ func TestTask_Validate_Service_AddressMode_Bad(t *testing.T) {
 	for _, service := range cases {
 		task := getTask(service)
 		t.Run(service.Name, func(t *testing.T) {
-			err := task.Validate(ephemeralDisk, JobTypeService)
+			err := task.Validate(ephemeralDisk, JobTypeService, nil)
 			if err == nil {
 				t.Fatalf("expected an error")
 			}
@@ -1044,6 +1251,72 @@ func TestTask_Validate_Service_AddressMode_Bad(t *testing.T) {
 	}
 }
 
+func TestTask_Validate_Service_Provider(t *testing.T) {
+	require := require.New(t)
+
+	s := &Service{Name: "web", Provider: ServiceProviderNomad}
+	require.NoError(s.Validate())
+
+	s.Checks = []*ServiceCheck{{Name: "alive", Type: ServiceCheckTCP, Interval: time.Second, Timeout: time.Second}}
+	err := s.Validate()
+	require.Error(err)
+	require.Contains(err.Error(), "does not support checks")
+
+	s = &Service{Name: "web", Provider: "etcd"}
+	err = s.Validate()
+	require.Error(err)
+	require.Contains(err.Error(), "service provider must be")
+
+	s = &Service{Name: "web"}
+	s.Canonicalize("example", "cache", "redis")
+	require.Equal(ServiceProviderConsul, s.Provider)
+}
+
+func TestServiceRegistration_SetHealth(t *testing.T) {
+	require := require.New(t)
+
+	reg := &ServiceRegistration{Task: "web"}
+	reg.SetHealth(nil)
+	require.Equal(ServiceHealthCritical, reg.Health)
+
+	alloc := &Allocation{
+		ClientStatus: AllocClientStatusRunning,
+		TaskStates: map[string]*TaskState{
+			"web": {State: TaskStateRunning},
+		},
+	}
+	reg.SetHealth(alloc)
+	require.Equal(ServiceHealthPassing, reg.Health)
+
+	unhealthy := false
+	alloc.DeploymentStatus = &AllocDeploymentStatus{Healthy: &unhealthy}
+	reg.SetHealth(alloc)
+	require.Equal(ServiceHealthCritical, reg.Health)
+
+	alloc.DeploymentStatus = nil
+	alloc.TaskStates["web"].State = TaskStatePending
+	reg.SetHealth(alloc)
+	require.Equal(ServiceHealthCritical, reg.Health)
+}
+
+func TestNewServiceRegistrationListStubs(t *testing.T) {
+	require := require.New(t)
+
+	regs := []*ServiceRegistration{
+		{Namespace: "default", ServiceName: "web", Tags: []string{"a"}, Health: ServiceHealthPassing},
+		{Namespace: "default", ServiceName: "web", Tags: []string{"a", "b"}, Health: ServiceHealthCritical},
+		{Namespace: "default", ServiceName: "db", Health: ServiceHealthPassing},
+	}
+	stubs := NewServiceRegistrationListStubs(regs)
+	require.Len(stubs, 2)
+	require.Equal("db", stubs[0].ServiceName)
+	require.Equal(1, stubs[0].Instances)
+	require.Equal("web", stubs[1].ServiceName)
+	require.Equal([]string{"a", "b"}, stubs[1].Tags)
+	require.Equal(2, stubs[1].Instances)
+	require.Equal(1, stubs[1].Healthy)
+}
+
 func TestTask_Validate_Service_Check(t *testing.T) {
 
 	invalidCheck := ServiceCheck{
@@ -1288,7 +1561,7 @@ func TestTask_Validate_Service_Check_AddressMode(t *testing.T) {
 		tc := tc
 		task := getTask(tc.Service)
 		t.Run(tc.Service.Name, func(t *testing.T) {
-			err := validateServices(task)
+			err := validateServices(task, nil)
 			if err == nil && tc.ErrContains == "" {
 				// Ok!
 				return
@@ -1356,7 +1629,7 @@ func TestTask_Validate_LogConfig(t *testing.T) {
 		SizeMB: 1,
 	}
 
-	err := task.Validate(ephemeralDisk, JobTypeService)
+	err := task.Validate(ephemeralDisk, JobTypeService, nil)
 	mErr := err.(*multierror.Error)
 	if !strings.Contains(mErr.Errors[3].Error(), "log storage") {
 		t.Fatalf("err: %s", err)
@@ -1373,7 +1646,7 @@ func TestTask_Validate_Template(t *testing.T) {
 		SizeMB: 1,
 	}
 
-	err := task.Validate(ephemeralDisk, JobTypeService)
+	err := task.Validate(ephemeralDisk, JobTypeService, nil)
 	if !strings.Contains(err.Error(), "Template 1 validation failed") {
 		t.Fatalf("err: %s", err)
 	}
@@ -1386,7 +1659,7 @@ func TestTask_Validate_Template(t *testing.T) {
 	}
 
 	task.Templates = []*Template{good, good}
-	err = task.Validate(ephemeralDisk, JobTypeService)
+	err = task.Validate(ephemeralDisk, JobTypeService, nil)
 	if !strings.Contains(err.Error(), "same destination as") {
 		t.Fatalf("err: %s", err)
 	}
@@ -1399,7 +1672,7 @@ func TestTask_Validate_Template(t *testing.T) {
 		},
 	}
 
-	err = task.Validate(ephemeralDisk, JobTypeService)
+	err = task.Validate(ephemeralDisk, JobTypeService, nil)
 	if err == nil {
 		t.Fatalf("expected error from Template.Validate")
 	}
@@ -1408,6 +1681,32 @@ func TestTask_Validate_Template(t *testing.T) {
 	}
 }
 
+func TestTask_Validate_Actions(t *testing.T) {
+	ephemeralDisk := &EphemeralDisk{
+		SizeMB: 1,
+	}
+
+	task := &Task{
+		Actions: []*Action{{}},
+	}
+	err := task.Validate(ephemeralDisk, JobTypeService, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Action 1 validation failed")
+	require.Contains(t, err.Error(), "Missing action name")
+	require.Contains(t, err.Error(), "Missing action command")
+
+	// Have two actions that share the same name
+	action := &Action{
+		Name:    "flush",
+		Command: "redis-cli",
+		Args:    []string{"FLUSHALL"},
+	}
+	task.Actions = []*Action{action, action}
+	err = task.Validate(ephemeralDisk, JobTypeService, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Action 2 has same name as 1")
+}
+
 func TestTemplate_Validate(t *testing.T) {
 	cases := []struct {
 		Tmpl         *Template
@@ -1728,6 +2027,79 @@ func TestUpdateStrategy_Validate(t *testing.T) {
 	}
 }
 
+func TestUpdateStrategy_MaxParallelFor(t *testing.T) {
+	require := require.New(t)
+
+	u := &UpdateStrategy{MaxParallel: 2}
+	require.Equal(2, u.MaxParallelFor(10))
+
+	// Percentages are rounded up and update at least one allocation
+	u.MaxParallelPercent = 25
+	require.Equal(3, u.MaxParallelFor(10))
+	require.Equal(25, u.MaxParallelFor(100))
+	require.Equal(1, u.MaxParallelFor(1))
+	require.Equal(1, u.MaxParallelFor(0))
+
+	u.MaxParallelPercent = 101
+	err := u.Validate()
+	require.Error(err)
+	require.Contains(err.Error(), "Max parallel percent must be between 0 and 100")
+}
+
+func TestResources_Validate_CPUHardLimit(t *testing.T) {
+	cases := []struct {
+		name      string
+		hardLimit bool
+		period    int
+		err       string
+	}{
+		{
+			name: "unset",
+		},
+		{
+			name:      "default period",
+			hardLimit: true,
+		},
+		{
+			name:      "valid period",
+			hardLimit: true,
+			period:    500000,
+		},
+		{
+			name:   "period without hard limit",
+			period: 500000,
+			err:    "cpu_cfs_period requires cpu_hard_limit",
+		},
+		{
+			name:      "period too low",
+			hardLimit: true,
+			period:    100,
+			err:       "cpu_cfs_period must be between 1000 and 1000000",
+		},
+		{
+			name:      "period too high",
+			hardLimit: true,
+			period:    2000000,
+			err:       "cpu_cfs_period must be between 1000 and 1000000",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := DefaultResources()
+			r.CPUHardLimit = c.hardLimit
+			r.CPUCFSPeriod = c.period
+			err := r.Validate()
+			if c.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), c.err)
+			}
+		})
+	}
+}
+
 func TestResource_NetIndex(t *testing.T) {
 	r := &Resources{
 		Networks: []*NetworkResource{
@@ -1785,7 +2157,7 @@ func TestResource_Add(t *testing.T) {
 			{
 				CIDR:          "10.0.0.0/8",
 				MBits:         100,
-				ReservedPorts: []Port{{"ssh", 22}},
+				ReservedPorts: []Port{{Label: "ssh", Value: 22}},
 			},
 		},
 	}
@@ -1798,7 +2170,7 @@ func TestResource_Add(t *testing.T) {
 			{
 				IP:            "10.0.0.1",
 				MBits:         50,
-				ReservedPorts: []Port{{"web", 80}},
+				ReservedPorts: []Port{{Label: "web", Value: 80}},
 			},
 		},
 	}
@@ -1817,7 +2189,7 @@ func TestResource_Add(t *testing.T) {
 			{
 				CIDR:          "10.0.0.0/8",
 				MBits:         150,
-				ReservedPorts: []Port{{"ssh", 22}, {"web", 80}},
+				ReservedPorts: []Port{{Label: "ssh", Value: 22}, {Label: "web", Value: 80}},
 			},
 		},
 	}
@@ -1833,7 +2205,7 @@ func TestResource_Add_Network(t *testing.T) {
 		Networks: []*NetworkResource{
 			{
 				MBits:        50,
-				DynamicPorts: []Port{{"http", 0}, {"https", 0}},
+				DynamicPorts: []Port{{Label: "http"}, {Label: "https"}},
 			},
 		},
 	}
@@ -1841,7 +2213,7 @@ func TestResource_Add_Network(t *testing.T) {
 		Networks: []*NetworkResource{
 			{
 				MBits:        25,
-				DynamicPorts: []Port{{"admin", 0}},
+				DynamicPorts: []Port{{Label: "admin"}},
 			},
 		},
 	}
@@ -1859,7 +2231,7 @@ func TestResource_Add_Network(t *testing.T) {
 		Networks: []*NetworkResource{
 			{
 				MBits:        75,
-				DynamicPorts: []Port{{"http", 0}, {"https", 0}, {"admin", 0}},
+				DynamicPorts: []Port{{Label: "http"}, {Label: "https"}, {Label: "admin"}},
 			},
 		},
 	}
@@ -1882,7 +2254,7 @@ func TestComparableResources_Subtract(t *testing.T) {
 				{
 					CIDR:          "10.0.0.0/8",
 					MBits:         100,
-					ReservedPorts: []Port{{"ssh", 22}},
+					ReservedPorts: []Port{{Label: "ssh", Value: 22}},
 				},
 			},
 		},
@@ -1903,7 +2275,7 @@ func TestComparableResources_Subtract(t *testing.T) {
 				{
 					CIDR:          "10.0.0.0/8",
 					MBits:         20,
-					ReservedPorts: []Port{{"ssh", 22}},
+					ReservedPorts: []Port{{Label: "ssh", Value: 22}},
 				},
 			},
 		},
@@ -1925,7 +2297,7 @@ func TestComparableResources_Subtract(t *testing.T) {
 				{
 					CIDR:          "10.0.0.0/8",
 					MBits:         100,
-					ReservedPorts: []Port{{"ssh", 22}},
+					ReservedPorts: []Port{{Label: "ssh", Value: 22}},
 				},
 			},
 		},
@@ -2566,6 +2938,31 @@ func TestReschedulePolicy_Validate(t *testing.T) {
 	}
 }
 
+func TestAllocMetric_NodeFailures(t *testing.T) {
+	require := require.New(t)
+	metric := &AllocMetric{}
+
+	node := MockNode()
+	metric.FilterNode(node, "missing drivers")
+	metric.ExhaustedNode(node, "memory")
+	require.Equal([]*NodeFailureMeta{
+		{NodeID: node.ID, NodeName: node.Name, Constraint: "missing drivers"},
+		{NodeID: node.ID, NodeName: node.Name, Dimension: "memory"},
+	}, metric.NodeFailures)
+
+	// Only a limited number of node failures are retained
+	for i := 0; i < 2*MaxRetainedNodeFailures; i++ {
+		metric.FilterNode(MockNode(), "missing drivers")
+	}
+	require.Len(metric.NodeFailures, MaxRetainedNodeFailures)
+	require.Equal(2*MaxRetainedNodeFailures+1, metric.NodesFiltered)
+
+	// Copies do not share the node failures
+	copied := metric.Copy()
+	copied.NodeFailures[0].Constraint = "foo"
+	require.Equal("missing drivers", metric.NodeFailures[0].Constraint)
+}
+
 func TestAllocation_Index(t *testing.T) {
 	a1 := Allocation{
 		Name:      "example.cache[1]",
@@ -2614,6 +3011,24 @@ func TestTaskArtifact_Validate_Dest(t *testing.T) {
 	}
 }
 
+func TestAllocation_LastUnknown(t *testing.T) {
+	now := time.Now()
+	alloc := &Allocation{}
+	require.True(t, alloc.LastUnknown().IsZero())
+
+	alloc.AllocStates = []*AllocState{
+		{Field: AllocStateFieldClientStatus, Value: AllocClientStatusUnknown, Time: now.Add(-time.Hour)},
+		{Field: AllocStateFieldClientStatus, Value: AllocClientStatusUnknown, Time: now},
+		{Field: AllocStateFieldClientStatus, Value: AllocClientStatusRunning, Time: now.Add(time.Minute)},
+	}
+	require.Equal(t, now, alloc.LastUnknown())
+	require.Equal(t, now.Add(time.Hour), alloc.DisconnectTimeout(time.Hour))
+
+	c := alloc.Copy()
+	c.AllocStates[0].Value = AllocClientStatusLost
+	require.Equal(t, AllocClientStatusUnknown, alloc.AllocStates[0].Value)
+}
+
 func TestAllocation_ShouldMigrate(t *testing.T) {
 	alloc := Allocation{
 		PreviousAllocation: "123",
@@ -3576,6 +3991,27 @@ func TestParameterizedJobConfig_Canonicalize(t *testing.T) {
 	}
 }
 
+func TestGCConfig_Validate(t *testing.T) {
+	job := testJob()
+	job.GC = &GCConfig{
+		JobThreshold:   24 * time.Hour,
+		AllocThreshold: -1 * time.Hour,
+	}
+
+	err := job.Validate()
+	if err == nil || !strings.Contains(err.Error(), "alloc threshold") {
+		t.Fatalf("Expected negative alloc threshold error: %v", err)
+	}
+	if strings.Contains(err.Error(), "job threshold") {
+		t.Fatalf("Unexpected job threshold error: %v", err)
+	}
+
+	job.GC.AllocThreshold = 0
+	if err := job.Validate(); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+}
+
 func TestDispatchPayloadConfig_Validate(t *testing.T) {
 	d := &DispatchPayloadConfig{
 		File: "foo",
@@ -3790,12 +4226,12 @@ func TestNetworkResourcesEquals(t *testing.T) {
 				{
 					IP:            "10.0.0.1",
 					MBits:         50,
-					ReservedPorts: []Port{{"web", 80}},
+					ReservedPorts: []Port{{Label: "web", Value: 80}},
 				},
 				{
 					IP:            "10.0.0.1",
 					MBits:         50,
-					ReservedPorts: []Port{{"web", 80}},
+					ReservedPorts: []Port{{Label: "web", Value: 80}},
 				},
 			},
 			true,
@@ -3806,12 +4242,12 @@ func TestNetworkResourcesEquals(t *testing.T) {
 				{
 					IP:            "10.0.0.0",
 					MBits:         50,
-					ReservedPorts: []Port{{"web", 80}},
+					ReservedPorts: []Port{{Label: "web", Value: 80}},
 				},
 				{
 					IP:            "10.0.0.1",
 					MBits:         50,
-					ReservedPorts: []Port{{"web", 80}},
+					ReservedPorts: []Port{{Label: "web", Value: 80}},
 				},
 			},
 			false,
@@ -3822,12 +4258,12 @@ func TestNetworkResourcesEquals(t *testing.T) {
 				{
 					IP:            "10.0.0.1",
 					MBits:         40,
-					ReservedPorts: []Port{{"web", 80}},
+					ReservedPorts: []Port{{Label: "web", Value: 80}},
 				},
 				{
 					IP:            "10.0.0.1",
 					MBits:         50,
-					ReservedPorts: []Port{{"web", 80}},
+					ReservedPorts: []Port{{Label: "web", Value: 80}},
 				},
 			},
 			false,
@@ -3838,12 +4274,12 @@ func TestNetworkResourcesEquals(t *testing.T) {
 				{
 					IP:            "10.0.0.1",
 					MBits:         50,
-					ReservedPorts: []Port{{"web", 80}},
+					ReservedPorts: []Port{{Label: "web", Value: 80}},
 				},
 				{
 					IP:            "10.0.0.1",
 					MBits:         50,
-					ReservedPorts: []Port{{"web", 80}, {"web", 80}},
+					ReservedPorts: []Port{{Label: "web", Value: 80}, {Label: "web", Value: 80}},
 				},
 			},
 			false,
@@ -3854,7 +4290,7 @@ func TestNetworkResourcesEquals(t *testing.T) {
 				{
 					IP:            "10.0.0.1",
 					MBits:         50,
-					ReservedPorts: []Port{{"web", 80}},
+					ReservedPorts: []Port{{Label: "web", Value: 80}},
 				},
 				{
 					IP:            "10.0.0.1",
@@ -3870,12 +4306,12 @@ func TestNetworkResourcesEquals(t *testing.T) {
 				{
 					IP:            "10.0.0.1",
 					MBits:         50,
-					ReservedPorts: []Port{{"web", 80}},
+					ReservedPorts: []Port{{Label: "web", Value: 80}},
 				},
 				{
 					IP:            "10.0.0.1",
 					MBits:         50,
-					ReservedPorts: []Port{{"notweb", 80}},
+					ReservedPorts: []Port{{Label: "notweb", Value: 80}},
 				},
 			},
 			false,
@@ -3886,12 +4322,12 @@ func TestNetworkResourcesEquals(t *testing.T) {
 				{
 					IP:           "10.0.0.1",
 					MBits:        50,
-					DynamicPorts: []Port{{"web", 80}},
+					DynamicPorts: []Port{{Label: "web", Value: 80}},
 				},
 				{
 					IP:           "10.0.0.1",
 					MBits:        50,
-					DynamicPorts: []Port{{"web", 80}, {"web", 80}},
+					DynamicPorts: []Port{{Label: "web", Value: 80}, {Label: "web", Value: 80}},
 				},
 			},
 			false,
@@ -3902,7 +4338,7 @@ func TestNetworkResourcesEquals(t *testing.T) {
 				{
 					IP:           "10.0.0.1",
 					MBits:        50,
-					DynamicPorts: []Port{{"web", 80}},
+					DynamicPorts: []Port{{Label: "web", Value: 80}},
 				},
 				{
 					IP:           "10.0.0.1",
@@ -3918,12 +4354,12 @@ func TestNetworkResourcesEquals(t *testing.T) {
 				{
 					IP:           "10.0.0.1",
 					MBits:        50,
-					DynamicPorts: []Port{{"web", 80}},
+					DynamicPorts: []Port{{Label: "web", Value: 80}},
 				},
 				{
 					IP:           "10.0.0.1",
 					MBits:        50,
-					DynamicPorts: []Port{{"notweb", 80}},
+					DynamicPorts: []Port{{Label: "notweb", Value: 80}},
 				},
 			},
 			false,
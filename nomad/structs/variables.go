@@ -0,0 +1,293 @@
+package structs
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/helper"
+)
+
+const (
+	// VariablesJobsPrefix is the path prefix of the variables tasks can read
+	// without an ACL token. A task can read the variables at the paths of its
+	// job, task group and task below the prefix.
+	VariablesJobsPrefix = "nomad/jobs"
+
+	// variablesReservedPrefix is the path prefix reserved by Nomad. Only the
+	// variables below VariablesJobsPrefix can be written in it.
+	variablesReservedPrefix = "nomad/"
+
+	// maxVariableSize limits the size of the encoded items of a variable
+	maxVariableSize = 16 * 1024
+
+	// RootKeyAlgorithmAES256GCM is the algorithm root keys use to encrypt
+	// variables.
+	RootKeyAlgorithmAES256GCM = "aes256-gcm"
+)
+
+var (
+	// validVariablePath is used to validate a variable path
+	validVariablePath = regexp.MustCompile("^[a-zA-Z0-9-_.~/]{1,128}$")
+)
+
+// VariableMetadata is the metadata of a variable. It is returned when
+// listing variables, which doesn't require decrypting them.
+type VariableMetadata struct {
+	// Namespace is the namespace of the variable.
+	Namespace string
+
+	// Path is the unique path of the variable in its namespace.
+	Path string
+
+	// Raft Indexes and times
+	CreateIndex uint64
+	ModifyIndex uint64
+	CreateTime  int64
+	ModifyTime  int64
+}
+
+// VariableDecrypted is a variable with its items in plain text. It is never
+// stored in the state store.
+type VariableDecrypted struct {
+	VariableMetadata
+
+	// Items are the key/value pairs of the variable.
+	Items map[string]string
+}
+
+// VariableEncrypted is a variable with its items encrypted by a root key, as
+// stored in the state store.
+type VariableEncrypted struct {
+	VariableMetadata
+
+	// Data is the encrypted encoding of the items of the variable.
+	Data []byte
+
+	// KeyID is the ID of the root key the data was encrypted with.
+	KeyID string
+
+	// WrappedDataKey is the data key the data was encrypted with, wrapped by
+	// the root key. It is empty if the data was encrypted directly with the
+	// root key.
+	WrappedDataKey []byte
+}
+
+// ValidVariablePath returns whether the path is a valid variable path.
+func ValidVariablePath(path string) bool {
+	if !validVariablePath.MatchString(path) {
+		return false
+	}
+	for _, part := range strings.Split(path, "/") {
+		if part == "" || part == "." || part == ".." {
+			return false
+		}
+	}
+	return true
+}
+
+// Validate is used to sanity check a variable.
+func (v *VariableDecrypted) Validate() error {
+	var mErr multierror.Error
+	if !ValidVariablePath(v.Path) {
+		err := fmt.Errorf("invalid path %q", v.Path)
+		mErr.Errors = append(mErr.Errors, err)
+	} else if strings.HasPrefix(v.Path, variablesReservedPrefix) &&
+		v.Path != VariablesJobsPrefix && !strings.HasPrefix(v.Path, VariablesJobsPrefix+"/") {
+		err := fmt.Errorf("path %q is reserved, only paths below %q can be written", v.Path, VariablesJobsPrefix)
+		mErr.Errors = append(mErr.Errors, err)
+	}
+
+	if len(v.Items) == 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("variable must have at least one item"))
+	}
+	size := 0
+	for k, val := range v.Items {
+		if k == "" {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("item keys must not be empty"))
+		}
+		size += len(k) + len(val)
+	}
+	if size > maxVariableSize {
+		err := fmt.Errorf("items larger than %d bytes", maxVariableSize)
+		mErr.Errors = append(mErr.Errors, err)
+	}
+	return mErr.ErrorOrNil()
+}
+
+// Copy returns a deep copy of the variable.
+func (v *VariableDecrypted) Copy() *VariableDecrypted {
+	if v == nil {
+		return nil
+	}
+	nv := new(VariableDecrypted)
+	*nv = *v
+	nv.Items = helper.CopyMapStringString(v.Items)
+	return nv
+}
+
+// Copy returns a deep copy of the variable.
+func (v *VariableEncrypted) Copy() *VariableEncrypted {
+	if v == nil {
+		return nil
+	}
+	nv := new(VariableEncrypted)
+	*nv = *v
+	nv.Data = append([]byte(nil), v.Data...)
+	nv.WrappedDataKey = append([]byte(nil), v.WrappedDataKey...)
+	return nv
+}
+
+// Metadata returns a copy of the metadata of the variable.
+func (v *VariableEncrypted) Metadata() *VariableMetadata {
+	meta := v.VariableMetadata
+	return &meta
+}
+
+// TaskVariablesPaths returns the paths of the variables a task can read
+// without an ACL token, from the least to the most specific.
+func TaskVariablesPaths(job, group, task string) []string {
+	jobPath := VariablesJobsPrefix + "/" + job
+	groupPath := jobPath + "/" + group
+	return []string{jobPath, groupPath, groupPath + "/" + task}
+}
+
+// RootKey is a key used to encrypt and decrypt variables.
+type RootKey struct {
+	// KeyID is the unique ID of the key.
+	KeyID string
+
+	// Algorithm is the encryption algorithm of the key.
+	Algorithm string
+
+	// Key is the key material. It is empty if the key is wrapped.
+	Key []byte
+
+	// WrappedKey is the key material wrapped by the keyring encryption key
+	// of the servers, so that it isn't readable from the state and the
+	// snapshots.
+	WrappedKey []byte
+
+	// Active marks the key new variables are encrypted with.
+	Active bool
+
+	// Raft Indexes and time
+	CreateIndex uint64
+	ModifyIndex uint64
+	CreateTime  int64
+}
+
+// Copy returns a deep copy of the root key.
+func (k *RootKey) Copy() *RootKey {
+	if k == nil {
+		return nil
+	}
+	nk := new(RootKey)
+	*nk = *k
+	nk.Key = append([]byte(nil), k.Key...)
+	nk.WrappedKey = append([]byte(nil), k.WrappedKey...)
+	return nk
+}
+
+// IsWrapped returns whether the key material is wrapped.
+func (k *RootKey) IsWrapped() bool {
+	return len(k.WrappedKey) != 0
+}
+
+// Meta returns the metadata of the root key, without its key material.
+func (k *RootKey) Meta() *RootKeyMeta {
+	return &RootKeyMeta{
+		KeyID:       k.KeyID,
+		Algorithm:   k.Algorithm,
+		Active:      k.Active,
+		Wrapped:     k.IsWrapped(),
+		CreateIndex: k.CreateIndex,
+		ModifyIndex: k.ModifyIndex,
+		CreateTime:  k.CreateTime,
+	}
+}
+
+// VariablesListRequest is used to list the variables of a namespace. The
+// prefix of the query options filters variables by path.
+type VariablesListRequest struct {
+	QueryOptions
+}
+
+// VariablesListResponse is used for a list request.
+type VariablesListResponse struct {
+	Data []*VariableMetadata
+	QueryMeta
+}
+
+// VariablesReadRequest is used to read a variable.
+type VariablesReadRequest struct {
+	Path string
+	QueryOptions
+}
+
+// VariablesReadResponse is used to return a single variable.
+type VariablesReadResponse struct {
+	Data *VariableDecrypted
+	QueryMeta
+}
+
+// VariablesUpsertRequest is used to create or update a variable.
+type VariablesUpsertRequest struct {
+	Data *VariableDecrypted
+
+	// CheckIndex makes the upsert a check-and-set operation if set. The
+	// variable is only written if its modify index is the check index, a
+	// check index of zero meaning the variable must not exist.
+	CheckIndex *uint64
+
+	WriteRequest
+}
+
+// VariablesUpsertResponse is used to return the written variable.
+type VariablesUpsertResponse struct {
+	Data *VariableDecrypted
+	WriteMeta
+}
+
+// VariablesApplyRequest is the Raft request used to write an encrypted
+// variable.
+type VariablesApplyRequest struct {
+	Data       *VariableEncrypted
+	CheckIndex *uint64
+	WriteRequest
+}
+
+// VariablesDeleteRequest is used to delete a variable.
+type VariablesDeleteRequest struct {
+	Path string
+
+	// CheckIndex makes the delete a check-and-set operation if set. The
+	// variable is only deleted if its modify index is the check index.
+	CheckIndex *uint64
+
+	WriteRequest
+}
+
+// VariablesTaskReadRequest is used by clients to read the variables of a task
+// of an allocation running on the node.
+type VariablesTaskReadRequest struct {
+	NodeID   string
+	SecretID string
+	AllocID  string
+	Task     string
+	QueryOptions
+}
+
+// VariablesTaskReadResponse returns the items of the variables of a task,
+// merged from the least to the most specific path.
+type VariablesTaskReadResponse struct {
+	Items map[string]string
+	QueryMeta
+}
+
+// RootKeyUpsertRequest is used to create or update a root key.
+type RootKeyUpsertRequest struct {
+	RootKey *RootKey
+	WriteRequest
+}
@@ -0,0 +1,341 @@
+package structs
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/helper"
+)
+
+const (
+	// VolumeTypeHost is the type of volumes backed by a path on the host of
+	// a single node.
+	VolumeTypeHost = "host"
+
+	// VolumeTypeCSI is the type of volumes backed by the external storage
+	// of a CSI plugin.
+	VolumeTypeCSI = "csi"
+)
+
+const (
+	// The access modes restrict the nodes and allocations that can claim a
+	// volume at the same time.
+	VolumeAccessModeSingleNodeReader = "single-node-reader-only"
+	VolumeAccessModeSingleNodeWriter = "single-node-writer"
+	VolumeAccessModeMultiNodeReader  = "multi-node-reader-only"
+	VolumeAccessModeMultiNodeSingle  = "multi-node-single-writer"
+	VolumeAccessModeMultiNodeMulti   = "multi-node-multi-writer"
+)
+
+const (
+	// VolumeAttachmentModeFilesystem mounts the volume as a file system.
+	VolumeAttachmentModeFilesystem = "file-system"
+
+	// VolumeAttachmentModeBlockDevice exposes the volume as a block device.
+	VolumeAttachmentModeBlockDevice = "block-device"
+)
+
+const (
+	// VolumeClaimRead and VolumeClaimWrite are the modes an allocation can
+	// claim a volume with.
+	VolumeClaimRead  = "read"
+	VolumeClaimWrite = "write"
+)
+
+var (
+	// validVolumeID is used to validate a volume ID
+	validVolumeID = regexp.MustCompile("^[a-zA-Z0-9-_.]{1,128}$")
+)
+
+// Volume is an external storage volume registered with Nomad. Volumes are
+// either registered after being provisioned out-of-band or created through
+// Nomad, and are claimed by the allocations using them.
+type Volume struct {
+	// ID is the unique ID of the volume within its namespace.
+	ID string
+
+	// Name is a human readable name of the volume.
+	Name string
+
+	// Namespace is the namespace of the volume.
+	Namespace string
+
+	// Type is the type of the volume, host or csi.
+	Type string
+
+	// PluginID is the CSI plugin providing the volume, set for CSI volumes.
+	PluginID string
+
+	// NodeID is the node the volume is located on, set for host volumes.
+	NodeID string
+
+	// ExternalID is the ID of the volume in the storage provider. For host
+	// volumes it is the path of the volume on the node.
+	ExternalID string
+
+	// AccessMode and AttachmentMode control how the volume can be claimed
+	// and mounted.
+	AccessMode     string
+	AttachmentMode string
+
+	// CapacityMin and CapacityMax are the range of the capacity of the
+	// volume in bytes. Zero means no limit.
+	CapacityMin int64
+	CapacityMax int64
+
+	// Parameters are passed to the storage provider when creating the
+	// volume and Context when mounting it.
+	Parameters map[string]string
+	Context    map[string]string
+
+	// Claims are the claims of the allocations using the volume, keyed by
+	// allocation ID.
+	Claims map[string]*VolumeClaim
+
+	// Raft Indexes
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// VolumeClaim is the claim of an allocation on a volume.
+type VolumeClaim struct {
+	AllocID string
+	NodeID  string
+	Mode    string
+}
+
+// Copy returns a deep copy of the volume.
+func (v *Volume) Copy() *Volume {
+	if v == nil {
+		return nil
+	}
+	nv := new(Volume)
+	*nv = *v
+	nv.Parameters = helper.CopyMapStringString(v.Parameters)
+	nv.Context = helper.CopyMapStringString(v.Context)
+	if v.Claims != nil {
+		nv.Claims = make(map[string]*VolumeClaim, len(v.Claims))
+		for id, claim := range v.Claims {
+			c := *claim
+			nv.Claims[id] = &c
+		}
+	}
+	return nv
+}
+
+// Canonicalize sets the default access and attachment modes.
+func (v *Volume) Canonicalize() {
+	if v.Namespace == "" {
+		v.Namespace = DefaultNamespace
+	}
+	if v.AccessMode == "" {
+		v.AccessMode = VolumeAccessModeSingleNodeWriter
+	}
+	if v.AttachmentMode == "" {
+		v.AttachmentMode = VolumeAttachmentModeFilesystem
+	}
+}
+
+// Validate is used to sanity check a volume.
+func (v *Volume) Validate() error {
+	var mErr multierror.Error
+	if !validVolumeID.MatchString(v.ID) {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid ID %q", v.ID))
+	}
+
+	switch v.Type {
+	case VolumeTypeHost:
+		if v.NodeID == "" {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("host volume must specify a node ID"))
+		}
+		if v.PluginID != "" {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("host volume can not specify a plugin ID"))
+		}
+		if v.AttachmentMode == VolumeAttachmentModeBlockDevice {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("host volume can not be attached as a block device"))
+		}
+		switch v.AccessMode {
+		case VolumeAccessModeSingleNodeReader, VolumeAccessModeSingleNodeWriter:
+		default:
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("host volume access mode must be single-node, not %q", v.AccessMode))
+		}
+	case VolumeTypeCSI:
+		if v.PluginID == "" {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("csi volume must specify a plugin ID"))
+		}
+		if v.NodeID != "" {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("csi volume can not specify a node ID"))
+		}
+	default:
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("type must be %q or %q, not %q", VolumeTypeHost, VolumeTypeCSI, v.Type))
+	}
+
+	switch v.AccessMode {
+	case VolumeAccessModeSingleNodeReader, VolumeAccessModeSingleNodeWriter,
+		VolumeAccessModeMultiNodeReader, VolumeAccessModeMultiNodeSingle, VolumeAccessModeMultiNodeMulti:
+	default:
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid access mode %q", v.AccessMode))
+	}
+
+	switch v.AttachmentMode {
+	case VolumeAttachmentModeFilesystem, VolumeAttachmentModeBlockDevice:
+	default:
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid attachment mode %q", v.AttachmentMode))
+	}
+
+	if v.CapacityMin < 0 || v.CapacityMax < 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("capacity can not be negative"))
+	} else if v.CapacityMax != 0 && v.CapacityMin > v.CapacityMax {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("minimum capacity %d greater than maximum capacity %d", v.CapacityMin, v.CapacityMax))
+	}
+	return mErr.ErrorOrNil()
+}
+
+// ClaimAllowed returns an error if the access mode of the volume doesn't
+// allow the claim alongside the existing claims of other allocations.
+func (v *Volume) ClaimAllowed(claim *VolumeClaim) error {
+	switch claim.Mode {
+	case VolumeClaimRead, VolumeClaimWrite:
+	default:
+		return fmt.Errorf("invalid claim mode %q", claim.Mode)
+	}
+
+	if v.Type == VolumeTypeHost && claim.NodeID != v.NodeID {
+		return fmt.Errorf("host volume %q is located on node %q", v.ID, v.NodeID)
+	}
+
+	if claim.Mode == VolumeClaimWrite {
+		switch v.AccessMode {
+		case VolumeAccessModeSingleNodeReader, VolumeAccessModeMultiNodeReader:
+			return fmt.Errorf("volume %q is read-only", v.ID)
+		}
+	}
+
+	for id, existing := range v.Claims {
+		if id == claim.AllocID {
+			continue
+		}
+		switch v.AccessMode {
+		case VolumeAccessModeSingleNodeReader, VolumeAccessModeSingleNodeWriter:
+			if existing.NodeID != claim.NodeID {
+				return fmt.Errorf("volume %q is claimed on another node", v.ID)
+			}
+		}
+		if claim.Mode == VolumeClaimWrite && existing.Mode == VolumeClaimWrite {
+			switch v.AccessMode {
+			case VolumeAccessModeSingleNodeWriter, VolumeAccessModeMultiNodeSingle:
+				return fmt.Errorf("volume %q already has a writer", v.ID)
+			}
+		}
+	}
+	return nil
+}
+
+// Stub returns a summary of the volume.
+func (v *Volume) Stub() *VolumeListStub {
+	stub := &VolumeListStub{
+		ID:             v.ID,
+		Name:           v.Name,
+		Namespace:      v.Namespace,
+		Type:           v.Type,
+		PluginID:       v.PluginID,
+		NodeID:         v.NodeID,
+		ExternalID:     v.ExternalID,
+		AccessMode:     v.AccessMode,
+		AttachmentMode: v.AttachmentMode,
+		CreateIndex:    v.CreateIndex,
+		ModifyIndex:    v.ModifyIndex,
+	}
+	for _, claim := range v.Claims {
+		if claim.Mode == VolumeClaimWrite {
+			stub.WriteClaims++
+		} else {
+			stub.ReadClaims++
+		}
+	}
+	return stub
+}
+
+// SortedClaims returns the claims of the volume sorted by allocation ID.
+func (v *Volume) SortedClaims() []*VolumeClaim {
+	claims := make([]*VolumeClaim, 0, len(v.Claims))
+	for _, claim := range v.Claims {
+		claims = append(claims, claim)
+	}
+	sort.Slice(claims, func(i, j int) bool { return claims[i].AllocID < claims[j].AllocID })
+	return claims
+}
+
+// VolumeListStub is the summary of a volume, returned when listing volumes.
+type VolumeListStub struct {
+	ID             string
+	Name           string
+	Namespace      string
+	Type           string
+	PluginID       string
+	NodeID         string
+	ExternalID     string
+	AccessMode     string
+	AttachmentMode string
+	ReadClaims     int
+	WriteClaims    int
+	CreateIndex    uint64
+	ModifyIndex    uint64
+}
+
+// VolumeRegisterRequest is used to register or create a set of volumes.
+type VolumeRegisterRequest struct {
+	Volumes []*Volume
+	WriteRequest
+}
+
+// VolumeDeleteRequest is used to delete a set of volumes of a namespace.
+type VolumeDeleteRequest struct {
+	VolumeIDs []string
+	WriteRequest
+}
+
+// VolumeClaimRequest is used by clients to claim a volume for an allocation
+// running on the node.
+type VolumeClaimRequest struct {
+	VolumeID string
+	NodeID   string
+	SecretID string
+	AllocID  string
+	Mode     string
+	WriteRequest
+}
+
+// VolumeDetachRequest is used to release the claims of a volume on a node.
+type VolumeDetachRequest struct {
+	VolumeID string
+	NodeID   string
+	WriteRequest
+}
+
+// VolumeListRequest is used to list the volumes of a namespace, optionally
+// only those of a type.
+type VolumeListRequest struct {
+	Type string
+	QueryOptions
+}
+
+// VolumeListResponse is used for a list request.
+type VolumeListResponse struct {
+	Volumes []*VolumeListStub
+	QueryMeta
+}
+
+// VolumeSpecificRequest is used to query a specific volume.
+type VolumeSpecificRequest struct {
+	VolumeID string
+	QueryOptions
+}
+
+// SingleVolumeResponse is used to return a single volume.
+type SingleVolumeResponse struct {
+	Volume *Volume
+	QueryMeta
+}
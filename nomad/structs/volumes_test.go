@@ -0,0 +1,190 @@
+package structs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVolume_Validate(t *testing.T) {
+	valid := func() *Volume {
+		v := &Volume{
+			ID:         "database",
+			Namespace:  DefaultNamespace,
+			Type:       VolumeTypeCSI,
+			PluginID:   "ebs",
+			ExternalID: "vol-0123456789",
+		}
+		v.Canonicalize()
+		return v
+	}
+
+	cases := []struct {
+		name   string
+		modify func(*Volume)
+		valid  bool
+	}{
+		{
+			name:   "valid csi",
+			modify: func(*Volume) {},
+			valid:  true,
+		},
+		{
+			name: "valid host",
+			modify: func(v *Volume) {
+				v.Type = VolumeTypeHost
+				v.PluginID = ""
+				v.NodeID = "node"
+			},
+			valid: true,
+		},
+		{
+			name:   "invalid ID",
+			modify: func(v *Volume) { v.ID = "data/base" },
+		},
+		{
+			name:   "invalid type",
+			modify: func(v *Volume) { v.Type = "nfs" },
+		},
+		{
+			name:   "csi missing plugin",
+			modify: func(v *Volume) { v.PluginID = "" },
+		},
+		{
+			name:   "csi with node",
+			modify: func(v *Volume) { v.NodeID = "node" },
+		},
+		{
+			name: "host missing node",
+			modify: func(v *Volume) {
+				v.Type = VolumeTypeHost
+				v.PluginID = ""
+			},
+		},
+		{
+			name: "host multi-node",
+			modify: func(v *Volume) {
+				v.Type = VolumeTypeHost
+				v.PluginID = ""
+				v.NodeID = "node"
+				v.AccessMode = VolumeAccessModeMultiNodeReader
+			},
+		},
+		{
+			name:   "invalid access mode",
+			modify: func(v *Volume) { v.AccessMode = "any" },
+		},
+		{
+			name:   "invalid attachment mode",
+			modify: func(v *Volume) { v.AttachmentMode = "nfs" },
+		},
+		{
+			name: "invalid capacity range",
+			modify: func(v *Volume) {
+				v.CapacityMin = 2 << 30
+				v.CapacityMax = 1 << 30
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			volume := valid()
+			c.modify(volume)
+			err := volume.Validate()
+			if c.valid {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestVolume_ClaimAllowed(t *testing.T) {
+	volume := func(mode string, claims ...*VolumeClaim) *Volume {
+		v := &Volume{ID: "database", Type: VolumeTypeCSI, AccessMode: mode}
+		for _, claim := range claims {
+			if v.Claims == nil {
+				v.Claims = make(map[string]*VolumeClaim)
+			}
+			v.Claims[claim.AllocID] = claim
+		}
+		return v
+	}
+	writer := &VolumeClaim{AllocID: "a1", NodeID: "n1", Mode: VolumeClaimWrite}
+
+	cases := []struct {
+		name   string
+		volume *Volume
+		claim  *VolumeClaim
+		err    string
+	}{
+		{
+			name:   "first writer",
+			volume: volume(VolumeAccessModeSingleNodeWriter),
+			claim:  writer,
+		},
+		{
+			name:   "reclaim by same alloc",
+			volume: volume(VolumeAccessModeSingleNodeWriter, writer),
+			claim:  writer,
+		},
+		{
+			name:   "invalid mode",
+			volume: volume(VolumeAccessModeSingleNodeWriter),
+			claim:  &VolumeClaim{AllocID: "a2", NodeID: "n1", Mode: "exclusive"},
+			err:    `invalid claim mode "exclusive"`,
+		},
+		{
+			name:   "write read-only",
+			volume: volume(VolumeAccessModeMultiNodeReader),
+			claim:  writer,
+			err:    `volume "database" is read-only`,
+		},
+		{
+			name:   "single node other node",
+			volume: volume(VolumeAccessModeSingleNodeWriter, writer),
+			claim:  &VolumeClaim{AllocID: "a2", NodeID: "n2", Mode: VolumeClaimRead},
+			err:    `volume "database" is claimed on another node`,
+		},
+		{
+			name:   "single node reader same node",
+			volume: volume(VolumeAccessModeSingleNodeWriter, writer),
+			claim:  &VolumeClaim{AllocID: "a2", NodeID: "n1", Mode: VolumeClaimRead},
+		},
+		{
+			name:   "second writer",
+			volume: volume(VolumeAccessModeMultiNodeSingle, writer),
+			claim:  &VolumeClaim{AllocID: "a2", NodeID: "n2", Mode: VolumeClaimWrite},
+			err:    `volume "database" already has a writer`,
+		},
+		{
+			name:   "multi writer",
+			volume: volume(VolumeAccessModeMultiNodeMulti, writer),
+			claim:  &VolumeClaim{AllocID: "a2", NodeID: "n2", Mode: VolumeClaimWrite},
+		},
+		{
+			name: "host volume other node",
+			volume: &Volume{
+				ID:         "database",
+				Type:       VolumeTypeHost,
+				NodeID:     "n1",
+				AccessMode: VolumeAccessModeSingleNodeWriter,
+			},
+			claim: &VolumeClaim{AllocID: "a2", NodeID: "n2", Mode: VolumeClaimRead},
+			err:   `host volume "database" is located on node "n1"`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := c.volume.ClaimAllowed(c.claim)
+			if c.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, c.err)
+			}
+		})
+	}
+}
@@ -71,6 +71,10 @@ func TestServer(t testing.T, cb func(*Config)) *Server {
 	config.ServerHealthInterval = 50 * time.Millisecond
 	config.AutopilotInterval = 100 * time.Millisecond
 
+	// Share a keyring encryption key so the servers of a test cluster can
+	// unwrap each other's root keys
+	config.KeyringEncryptionKey = make([]byte, KeyringEncryptionKeySize)
+
 	// Set the plugin loaders
 	config.PluginLoader = catalog.TestPluginLoader(t)
 	config.PluginSingletonLoader = singleton.NewSingletonLoader(config.Logger, config.PluginLoader)
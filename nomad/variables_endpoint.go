@@ -0,0 +1,302 @@
+package nomad
+
+import (
+	"fmt"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	log "github.com/hashicorp/go-hclog"
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// Variables endpoint is used for managing the encrypted variables of
+// namespaces
+type Variables struct {
+	srv    *Server
+	logger log.Logger
+}
+
+// List is used to list the metadata of the variables of a namespace
+func (v *Variables) List(args *structs.VariablesListRequest, reply *structs.VariablesListResponse) error {
+	if done, err := v.srv.forward("Variables.List", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "variables", "list"}, time.Now())
+
+	// Check variables list permissions
+	ns := args.RequestNamespace()
+	aclObj, err := v.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowVariableSearch(ns) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			iter, err := state.VariablesByPathPrefix(ws, ns, args.Prefix)
+			if err != nil {
+				return err
+			}
+
+			var variables []*structs.VariableMetadata
+			for {
+				raw := iter.Next()
+				if raw == nil {
+					break
+				}
+				variable := raw.(*structs.VariableEncrypted)
+				if aclObj != nil && !aclObj.AllowVariableOperation(ns, variable.Path, acl.VariablesCapabilityList) {
+					continue
+				}
+				variables = append(variables, variable.Metadata())
+			}
+			reply.Data = variables
+
+			// Use the last index that affected the variables table
+			return v.setVariablesIndex(state, &reply.QueryMeta)
+		}}
+	return v.srv.blockingRPC(&opts)
+}
+
+// Read is used to read and decrypt a variable
+func (v *Variables) Read(args *structs.VariablesReadRequest, reply *structs.VariablesReadResponse) error {
+	if done, err := v.srv.forward("Variables.Read", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "variables", "read"}, time.Now())
+
+	// Check variables read permissions
+	ns := args.RequestNamespace()
+	if aclObj, err := v.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowVariableOperation(ns, args.Path, acl.VariablesCapabilityRead) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			// Look for the variable
+			out, err := state.VariableByPath(ws, ns, args.Path)
+			if err != nil {
+				return err
+			}
+
+			// Setup the output
+			reply.Data = nil
+			if out == nil {
+				return v.setVariablesIndex(state, &reply.QueryMeta)
+			}
+
+			decrypted, err := v.srv.encrypter.Decrypt(state, out)
+			if err != nil {
+				return err
+			}
+			reply.Data = decrypted
+			reply.Index = out.ModifyIndex
+			return nil
+		}}
+	return v.srv.blockingRPC(&opts)
+}
+
+// Upsert is used to encrypt and create or update a variable
+func (v *Variables) Upsert(args *structs.VariablesUpsertRequest, reply *structs.VariablesUpsertResponse) error {
+	if done, err := v.srv.forward("Variables.Upsert", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "variables", "upsert"}, time.Now())
+
+	if args.Data == nil {
+		return fmt.Errorf("missing variable")
+	}
+
+	// Check variables write permissions
+	ns := args.RequestNamespace()
+	if aclObj, err := v.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowVariableOperation(ns, args.Data.Path, acl.VariablesCapabilityWrite) {
+		return structs.ErrPermissionDenied
+	}
+
+	if err := args.Data.Validate(); err != nil {
+		return fmt.Errorf("variable invalid: %v", err)
+	}
+
+	// Encrypt the items with the active root key
+	variable := args.Data.Copy()
+	now := time.Now().UnixNano()
+	variable.Namespace = ns
+	variable.CreateTime = now
+	variable.ModifyTime = now
+	encrypted, err := v.srv.encrypter.Encrypt(variable)
+	if err != nil {
+		return err
+	}
+
+	// Update via Raft
+	req := structs.VariablesApplyRequest{
+		Data:         encrypted,
+		CheckIndex:   args.CheckIndex,
+		WriteRequest: args.WriteRequest,
+	}
+	fsmErr, index, err := v.srv.raftApply(structs.VariablesApplyRequestType, req)
+	if err, ok := fsmErr.(error); ok && err != nil {
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	// Return the stored variable with its indexes
+	stored, err := v.srv.fsm.State().VariableByPath(nil, ns, variable.Path)
+	if err != nil {
+		return err
+	}
+	if stored != nil {
+		variable.VariableMetadata = stored.VariableMetadata
+	}
+	reply.Data = variable
+	reply.Index = index
+	return nil
+}
+
+// Delete is used to delete a variable
+func (v *Variables) Delete(args *structs.VariablesDeleteRequest, reply *structs.GenericResponse) error {
+	if done, err := v.srv.forward("Variables.Delete", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "variables", "delete"}, time.Now())
+
+	// Check variables destroy permissions
+	ns := args.RequestNamespace()
+	if aclObj, err := v.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowVariableOperation(ns, args.Path, acl.VariablesCapabilityDestroy) {
+		return structs.ErrPermissionDenied
+	}
+
+	if args.Path == "" {
+		return fmt.Errorf("missing variable path")
+	}
+
+	// Update via Raft
+	fsmErr, index, err := v.srv.raftApply(structs.VariablesDeleteRequestType, args)
+	if err, ok := fsmErr.(error); ok && err != nil {
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	// Update the index
+	reply.Index = index
+	return nil
+}
+
+// TaskRead is used by clients to read the variables of a task of an
+// allocation running on the node. The items of the variables at the paths of
+// the job, task group and task are merged, the most specific path taking
+// precedence.
+func (v *Variables) TaskRead(args *structs.VariablesTaskReadRequest, reply *structs.VariablesTaskReadResponse) error {
+	if done, err := v.srv.forward("Variables.TaskRead", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "variables", "task_read"}, time.Now())
+
+	// Verify the arguments
+	if args.NodeID == "" {
+		return fmt.Errorf("missing node ID")
+	}
+	if args.AllocID == "" {
+		return fmt.Errorf("missing allocation ID")
+	}
+	if args.Task == "" {
+		return fmt.Errorf("missing task name")
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			// Verify the node exists, has the correct SecretID and runs
+			// the allocation
+			node, err := state.NodeByID(ws, args.NodeID)
+			if err != nil {
+				return err
+			}
+			if node == nil {
+				return fmt.Errorf("Node %q does not exist", args.NodeID)
+			}
+			if node.SecretID != args.SecretID {
+				return structs.ErrPermissionDenied
+			}
+
+			alloc, err := state.AllocByID(ws, args.AllocID)
+			if err != nil {
+				return err
+			}
+			if alloc == nil {
+				return fmt.Errorf("Allocation %q does not exist", args.AllocID)
+			}
+			if alloc.NodeID != args.NodeID {
+				return fmt.Errorf("Allocation %q not running on Node %q", args.AllocID, args.NodeID)
+			}
+			if alloc.Job == nil {
+				return fmt.Errorf("Allocation %q does not have a job", args.AllocID)
+			}
+			tg := alloc.Job.LookupTaskGroup(alloc.TaskGroup)
+			if tg == nil || tg.LookupTask(args.Task) == nil {
+				return fmt.Errorf("Allocation %q does not have task %q", args.AllocID, args.Task)
+			}
+
+			// Merge the items of the variables of the task
+			items := make(map[string]string)
+			for _, path := range structs.TaskVariablesPaths(alloc.JobID, alloc.TaskGroup, args.Task) {
+				out, err := state.VariableByPath(ws, alloc.Namespace, path)
+				if err != nil {
+					return err
+				}
+				if out == nil {
+					continue
+				}
+				decrypted, err := v.srv.encrypter.Decrypt(state, out)
+				if err != nil {
+					return err
+				}
+				for k, val := range decrypted.Items {
+					items[k] = val
+				}
+			}
+			reply.Items = items
+
+			return v.setVariablesIndex(state, &reply.QueryMeta)
+		}}
+	return v.srv.blockingRPC(&opts)
+}
+
+// setVariablesIndex sets the index of the reply to the last index that
+// affected the variables table.
+func (v *Variables) setVariablesIndex(state *state.StateStore, reply *structs.QueryMeta) error {
+	index, err := state.Index("variables")
+	if err != nil {
+		return err
+	}
+
+	// Ensure we never set the index to zero, otherwise a blocking query cannot be used.
+	// We floor the index at one, since realistically the first write must have a higher index.
+	if index == 0 {
+		index = 1
+	}
+	reply.Index = index
+	return nil
+}
@@ -0,0 +1,218 @@
+package nomad
+
+import (
+	"testing"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVariablesEndpoint_UpsertReadDelete(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	variable := &structs.VariableDecrypted{
+		VariableMetadata: structs.VariableMetadata{Path: "project/web"},
+		Items:            map[string]string{"user": "admin", "password": "hunter2"},
+	}
+	upsert := &structs.VariablesUpsertRequest{
+		Data:         variable,
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var upsertResp structs.VariablesUpsertResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Variables.Upsert", upsert, &upsertResp))
+	require.NotZero(upsertResp.Index)
+	require.Equal(structs.DefaultNamespace, upsertResp.Data.Namespace)
+	require.Equal(upsertResp.Index, upsertResp.Data.ModifyIndex)
+
+	// The variable is stored encrypted
+	out, err := s1.fsm.State().VariableByPath(nil, structs.DefaultNamespace, variable.Path)
+	require.NoError(err)
+	require.NotNil(out)
+	require.NotContains(string(out.Data), "hunter2")
+
+	// Read the decrypted variable
+	read := &structs.VariablesReadRequest{
+		Path:         variable.Path,
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var readResp structs.VariablesReadResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Variables.Read", read, &readResp))
+	require.Equal(variable.Items, readResp.Data.Items)
+	require.Equal(upsertResp.Index, readResp.Index)
+
+	// List the variables by prefix
+	list := &structs.VariablesListRequest{
+		QueryOptions: structs.QueryOptions{Region: "global", Prefix: "project/"},
+	}
+	var listResp structs.VariablesListResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Variables.List", list, &listResp))
+	require.Len(listResp.Data, 1)
+	require.Equal(variable.Path, listResp.Data[0].Path)
+
+	list.Prefix = "other/"
+	var listResp2 structs.VariablesListResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Variables.List", list, &listResp2))
+	require.Empty(listResp2.Data)
+
+	// Check-and-set upserts fail on a stale index
+	upsert.CheckIndex = helper.Uint64ToPtr(upsertResp.Index - 1)
+	err = msgpackrpc.CallWithCodec(codec, "Variables.Upsert", upsert, &upsertResp)
+	require.Error(err)
+	require.Contains(err.Error(), structs.ErrCASConflictPrefix)
+
+	// Invalid variables are rejected
+	upsert.CheckIndex = nil
+	upsert.Data = &structs.VariableDecrypted{
+		VariableMetadata: structs.VariableMetadata{Path: "nomad/secret"},
+		Items:            map[string]string{"foo": "bar"},
+	}
+	err = msgpackrpc.CallWithCodec(codec, "Variables.Upsert", upsert, &upsertResp)
+	require.Error(err)
+	require.Contains(err.Error(), "is reserved")
+
+	// Delete the variable
+	del := &structs.VariablesDeleteRequest{
+		Path:         variable.Path,
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var delResp structs.GenericResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Variables.Delete", del, &delResp))
+	require.NotZero(delResp.Index)
+
+	var readResp2 structs.VariablesReadResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Variables.Read", read, &readResp2))
+	require.Nil(readResp2.Data)
+}
+
+func TestVariablesEndpoint_ACL(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1, root := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	policy := `
+namespace "default" {
+  variables {
+    path "project/*" {
+      capabilities = ["list", "read"]
+    }
+  }
+}
+`
+	readToken := mock.CreatePolicyAndToken(t, state, 1001, "read", policy)
+
+	upsert := &structs.VariablesUpsertRequest{
+		Data: &structs.VariableDecrypted{
+			VariableMetadata: structs.VariableMetadata{Path: "project/web"},
+			Items:            map[string]string{"user": "admin"},
+		},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var upsertResp structs.VariablesUpsertResponse
+
+	// Try with a token only allowed to read
+	upsert.AuthToken = readToken.SecretID
+	err := msgpackrpc.CallWithCodec(codec, "Variables.Upsert", upsert, &upsertResp)
+	require.EqualError(err, structs.ErrPermissionDenied.Error())
+
+	// Try with a management token
+	upsert.AuthToken = root.SecretID
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Variables.Upsert", upsert, &upsertResp))
+	upsert.Data.Path = "other/web"
+	var upsertResp2 structs.VariablesUpsertResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Variables.Upsert", upsert, &upsertResp2))
+
+	// Read with the read token
+	read := &structs.VariablesReadRequest{
+		Path:         "project/web",
+		QueryOptions: structs.QueryOptions{Region: "global", AuthToken: readToken.SecretID},
+	}
+	var readResp structs.VariablesReadResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Variables.Read", read, &readResp))
+	require.Equal("admin", readResp.Data.Items["user"])
+
+	read.Path = "other/web"
+	var readResp2 structs.VariablesReadResponse
+	err = msgpackrpc.CallWithCodec(codec, "Variables.Read", read, &readResp2)
+	require.EqualError(err, structs.ErrPermissionDenied.Error())
+
+	// Only the variables the token can list are listed
+	list := &structs.VariablesListRequest{
+		QueryOptions: structs.QueryOptions{Region: "global", AuthToken: readToken.SecretID},
+	}
+	var listResp structs.VariablesListResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Variables.List", list, &listResp))
+	require.Len(listResp.Data, 1)
+	require.Equal("project/web", listResp.Data[0].Path)
+}
+
+func TestVariablesEndpoint_TaskRead(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	node := mock.Node()
+	alloc := mock.Alloc()
+	alloc.NodeID = node.ID
+	require.NoError(state.UpsertNode(1000, node))
+	require.NoError(state.UpsertJobSummary(1001, mock.JobSummary(alloc.JobID)))
+	require.NoError(state.UpsertAllocs(1002, []*structs.Allocation{alloc}))
+
+	task := alloc.Job.TaskGroups[0].Tasks[0].Name
+	paths := structs.TaskVariablesPaths(alloc.JobID, alloc.TaskGroup, task)
+	for i, items := range []map[string]string{
+		{"job": "1", "shared": "job"},
+		{"group": "2", "shared": "group"},
+		{"task": "3", "shared": "task"},
+	} {
+		upsert := &structs.VariablesUpsertRequest{
+			Data: &structs.VariableDecrypted{
+				VariableMetadata: structs.VariableMetadata{Path: paths[i]},
+				Items:            items,
+			},
+			WriteRequest: structs.WriteRequest{Region: "global"},
+		}
+		var resp structs.VariablesUpsertResponse
+		require.NoError(msgpackrpc.CallWithCodec(codec, "Variables.Upsert", upsert, &resp))
+	}
+
+	req := &structs.VariablesTaskReadRequest{
+		NodeID:       node.ID,
+		SecretID:     node.SecretID,
+		AllocID:      alloc.ID,
+		Task:         task,
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var resp structs.VariablesTaskReadResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Variables.TaskRead", req, &resp))
+	expected := map[string]string{"job": "1", "group": "2", "task": "3", "shared": "task"}
+	require.Equal(expected, resp.Items)
+
+	// The node SecretID must match
+	req.SecretID = "foo"
+	err := msgpackrpc.CallWithCodec(codec, "Variables.TaskRead", req, &resp)
+	require.EqualError(err, structs.ErrPermissionDenied.Error())
+
+	// The task must be in the allocation
+	req.SecretID = node.SecretID
+	req.Task = "missing"
+	err = msgpackrpc.CallWithCodec(codec, "Variables.TaskRead", req, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "does not have task")
+}
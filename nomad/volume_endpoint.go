@@ -0,0 +1,384 @@
+package nomad
+
+import (
+	"fmt"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	log "github.com/hashicorp/go-hclog"
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// Volume endpoint is used for managing the external storage volumes
+// registered with Nomad
+type Volume struct {
+	srv    *Server
+	logger log.Logger
+}
+
+// Register is used to register or update a set of volumes that were
+// provisioned out-of-band
+func (v *Volume) Register(args *structs.VolumeRegisterRequest, reply *structs.GenericResponse) error {
+	if done, err := v.srv.forward("Volume.Register", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "volume", "register"}, time.Now())
+
+	if err := v.validateVolumes(args); err != nil {
+		return err
+	}
+	for _, volume := range args.Volumes {
+		if volume.ExternalID == "" {
+			return fmt.Errorf("volume %q must specify an external ID", volume.ID)
+		}
+	}
+	return v.applyRegister(args, reply)
+}
+
+// Create is used to create a set of new volumes. Unlike Register, it fails
+// if any of the volumes already exists.
+func (v *Volume) Create(args *structs.VolumeRegisterRequest, reply *structs.GenericResponse) error {
+	if done, err := v.srv.forward("Volume.Create", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "volume", "create"}, time.Now())
+
+	if err := v.validateVolumes(args); err != nil {
+		return err
+	}
+
+	snap, err := v.srv.fsm.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	for _, volume := range args.Volumes {
+		existing, err := snap.VolumeByID(nil, volume.Namespace, volume.ID)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			return fmt.Errorf("volume %q already exists", volume.ID)
+		}
+	}
+	return v.applyRegister(args, reply)
+}
+
+// validateVolumes checks the write permissions and validates the volumes of
+// the request, setting their namespace to the one of the request.
+func (v *Volume) validateVolumes(args *structs.VolumeRegisterRequest) error {
+	// Check for write-volume permissions
+	ns := args.RequestNamespace()
+	if aclObj, err := v.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNsOp(ns, acl.NamespaceCapabilityWriteVolume) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Validate non-zero set of volumes
+	if len(args.Volumes) == 0 {
+		return fmt.Errorf("must specify at least one volume")
+	}
+	for i, volume := range args.Volumes {
+		volume = volume.Copy()
+		volume.Namespace = ns
+		volume.Canonicalize()
+		if err := volume.Validate(); err != nil {
+			return fmt.Errorf("volume %d invalid: %v", i, err)
+		}
+		args.Volumes[i] = volume
+	}
+	return nil
+}
+
+// applyRegister upserts the volumes of the request via Raft.
+func (v *Volume) applyRegister(args *structs.VolumeRegisterRequest, reply *structs.GenericResponse) error {
+	fsmErr, index, err := v.srv.raftApply(structs.VolumeRegisterRequestType, args)
+	if err, ok := fsmErr.(error); ok && err != nil {
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	reply.Index = index
+	return nil
+}
+
+// Delete is used to delete a set of volumes. Volumes that are still claimed
+// by non-terminal allocations can not be deleted.
+func (v *Volume) Delete(args *structs.VolumeDeleteRequest, reply *structs.GenericResponse) error {
+	if done, err := v.srv.forward("Volume.Delete", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "volume", "delete"}, time.Now())
+
+	// Check for write-volume permissions
+	ns := args.RequestNamespace()
+	if aclObj, err := v.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNsOp(ns, acl.NamespaceCapabilityWriteVolume) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Validate non-zero set of volumes
+	if len(args.VolumeIDs) == 0 {
+		return fmt.Errorf("must specify at least one volume")
+	}
+
+	snap, err := v.srv.fsm.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	for _, id := range args.VolumeIDs {
+		volume, err := snap.VolumeByID(nil, ns, id)
+		if err != nil {
+			return err
+		}
+		if volume == nil {
+			return fmt.Errorf("volume %q not found", id)
+		}
+		for _, claim := range volume.Claims {
+			if running, err := claimRunning(snap, claim); err != nil {
+				return err
+			} else if running {
+				return fmt.Errorf("volume %q is in use by allocation %q", id, claim.AllocID)
+			}
+		}
+	}
+
+	fsmErr, index, err := v.srv.raftApply(structs.VolumeDeleteRequestType, args)
+	if err, ok := fsmErr.(error); ok && err != nil {
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	reply.Index = index
+	return nil
+}
+
+// Claim is used by clients to claim a volume for an allocation running on
+// the node
+func (v *Volume) Claim(args *structs.VolumeClaimRequest, reply *structs.GenericResponse) error {
+	if done, err := v.srv.forward("Volume.Claim", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "volume", "claim"}, time.Now())
+
+	if args.VolumeID == "" {
+		return fmt.Errorf("missing volume ID")
+	}
+	if args.NodeID == "" {
+		return fmt.Errorf("missing node ID")
+	}
+
+	// Verify the node and that the allocation runs on it
+	snap, err := v.srv.fsm.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	node, err := snap.NodeByID(nil, args.NodeID)
+	if err != nil {
+		return err
+	}
+	if node == nil {
+		return fmt.Errorf("Node %q does not exist", args.NodeID)
+	}
+	if node.SecretID != args.SecretID {
+		return structs.ErrPermissionDenied
+	}
+	alloc, err := snap.AllocByID(nil, args.AllocID)
+	if err != nil {
+		return err
+	}
+	if alloc == nil {
+		return fmt.Errorf("Allocation %q does not exist", args.AllocID)
+	}
+	if alloc.NodeID != args.NodeID {
+		return fmt.Errorf("Allocation %q not running on Node %q", args.AllocID, args.NodeID)
+	}
+	if alloc.Namespace != args.RequestNamespace() {
+		return fmt.Errorf("Allocation %q not in namespace %q", args.AllocID, args.RequestNamespace())
+	}
+
+	// Update via Raft, without the secret of the node
+	req := *args
+	req.SecretID = ""
+	fsmErr, index, err := v.srv.raftApply(structs.VolumeClaimRequestType, &req)
+	if err, ok := fsmErr.(error); ok && err != nil {
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	reply.Index = index
+	return nil
+}
+
+// Detach is used to release the claims of a volume on a node. The claims of
+// allocations that are still running on the node can not be released.
+func (v *Volume) Detach(args *structs.VolumeDetachRequest, reply *structs.GenericResponse) error {
+	if done, err := v.srv.forward("Volume.Detach", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "volume", "detach"}, time.Now())
+
+	// Check for write-volume permissions
+	ns := args.RequestNamespace()
+	if aclObj, err := v.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNsOp(ns, acl.NamespaceCapabilityWriteVolume) {
+		return structs.ErrPermissionDenied
+	}
+
+	if args.VolumeID == "" {
+		return fmt.Errorf("missing volume ID")
+	}
+	if args.NodeID == "" {
+		return fmt.Errorf("missing node ID")
+	}
+
+	snap, err := v.srv.fsm.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	volume, err := snap.VolumeByID(nil, ns, args.VolumeID)
+	if err != nil {
+		return err
+	}
+	if volume == nil {
+		return fmt.Errorf("volume %q not found", args.VolumeID)
+	}
+	for _, claim := range volume.Claims {
+		if claim.NodeID != args.NodeID {
+			continue
+		}
+		if running, err := claimRunning(snap, claim); err != nil {
+			return err
+		} else if running {
+			return fmt.Errorf("volume %q is in use by allocation %q on node %q", args.VolumeID, claim.AllocID, args.NodeID)
+		}
+	}
+
+	fsmErr, index, err := v.srv.raftApply(structs.VolumeDetachRequestType, args)
+	if err, ok := fsmErr.(error); ok && err != nil {
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	reply.Index = index
+	return nil
+}
+
+// claimRunning returns whether the allocation of the claim exists and isn't
+// terminal on the client.
+func claimRunning(snap *state.StateSnapshot, claim *structs.VolumeClaim) (bool, error) {
+	alloc, err := snap.AllocByID(nil, claim.AllocID)
+	if err != nil {
+		return false, err
+	}
+	return alloc != nil && !alloc.ClientTerminalStatus(), nil
+}
+
+// List is used to list the volumes of a namespace
+func (v *Volume) List(args *structs.VolumeListRequest, reply *structs.VolumeListResponse) error {
+	if done, err := v.srv.forward("Volume.List", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "volume", "list"}, time.Now())
+
+	// Check for read-volume permissions
+	ns := args.RequestNamespace()
+	if aclObj, err := v.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNsOp(ns, acl.NamespaceCapabilityReadVolume) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			iter, err := state.VolumesByIDPrefix(ws, ns, args.QueryOptions.Prefix)
+			if err != nil {
+				return err
+			}
+
+			var volumes []*structs.VolumeListStub
+			for raw := iter.Next(); raw != nil; raw = iter.Next() {
+				volume := raw.(*structs.Volume)
+				if args.Type != "" && volume.Type != args.Type {
+					continue
+				}
+				volumes = append(volumes, volume.Stub())
+			}
+			reply.Volumes = volumes
+
+			// Use the last index that affected the volume table
+			return v.setIndex(state, &reply.QueryMeta)
+		}}
+	return v.srv.blockingRPC(&opts)
+}
+
+// GetVolume is used to get a specific volume
+func (v *Volume) GetVolume(args *structs.VolumeSpecificRequest, reply *structs.SingleVolumeResponse) error {
+	if done, err := v.srv.forward("Volume.GetVolume", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "volume", "get_volume"}, time.Now())
+
+	// Check for read-volume permissions
+	ns := args.RequestNamespace()
+	if aclObj, err := v.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNsOp(ns, acl.NamespaceCapabilityReadVolume) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			out, err := state.VolumeByID(ws, ns, args.VolumeID)
+			if err != nil {
+				return err
+			}
+
+			// Setup the output
+			reply.Volume = out
+			if out != nil {
+				reply.Index = out.ModifyIndex
+				return nil
+			}
+
+			// Use the last index that affected the volume table
+			return v.setIndex(state, &reply.QueryMeta)
+		}}
+	return v.srv.blockingRPC(&opts)
+}
+
+// setIndex sets the index of the reply to the last index that affected the
+// volume table.
+func (v *Volume) setIndex(state *state.StateStore, reply *structs.QueryMeta) error {
+	index, err := state.Index("volumes")
+	if err != nil {
+		return err
+	}
+
+	// Ensure we never set the index to zero, otherwise a blocking query cannot be used.
+	// We floor the index at one, since realistically the first write must have a higher index.
+	if index == 0 {
+		index = 1
+	}
+	reply.Index = index
+	return nil
+}
@@ -0,0 +1,195 @@
+package nomad
+
+import (
+	"testing"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVolumeEndpoint_RegisterCreateList(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Registering requires the external ID
+	volume := mock.Volume()
+	volume.AccessMode = ""
+	volume.ExternalID = ""
+	register := &structs.VolumeRegisterRequest{
+		Volumes:      []*structs.Volume{volume},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.GenericResponse
+	err := msgpackrpc.CallWithCodec(codec, "Volume.Register", register, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "must specify an external ID")
+
+	volume.ExternalID = "vol-0123456789"
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Volume.Register", register, &resp))
+	require.NotZero(resp.Index)
+
+	// Creating an existing volume fails
+	create := &structs.VolumeRegisterRequest{
+		Volumes:      []*structs.Volume{volume},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	err = msgpackrpc.CallWithCodec(codec, "Volume.Create", create, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "already exists")
+
+	created := mock.Volume()
+	created.ExternalID = ""
+	create.Volumes = []*structs.Volume{created}
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Volume.Create", create, &resp))
+
+	// Invalid volumes are rejected
+	invalid := mock.Volume()
+	invalid.Type = "nfs"
+	create.Volumes = []*structs.Volume{invalid}
+	require.Error(msgpackrpc.CallWithCodec(codec, "Volume.Create", create, &resp))
+
+	// Read the volume, defaulted when registered
+	get := &structs.VolumeSpecificRequest{
+		VolumeID:     volume.ID,
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var getResp structs.SingleVolumeResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Volume.GetVolume", get, &getResp))
+	require.NotNil(getResp.Volume)
+	require.Equal(structs.VolumeAccessModeSingleNodeWriter, getResp.Volume.AccessMode)
+
+	list := &structs.VolumeListRequest{
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var listResp structs.VolumeListResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Volume.List", list, &listResp))
+	require.Len(listResp.Volumes, 2)
+
+	list.Type = structs.VolumeTypeHost
+	var listResp2 structs.VolumeListResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Volume.List", list, &listResp2))
+	require.Empty(listResp2.Volumes)
+}
+
+func TestVolumeEndpoint_ClaimDetachDelete(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	node := mock.Node()
+	alloc := mock.Alloc()
+	alloc.NodeID = node.ID
+	alloc.ClientStatus = structs.AllocClientStatusRunning
+	volume := mock.Volume()
+	require.NoError(state.UpsertNode(1000, node))
+	require.NoError(state.UpsertJobSummary(1001, mock.JobSummary(alloc.JobID)))
+	require.NoError(state.UpsertAllocs(1002, []*structs.Allocation{alloc}))
+	require.NoError(state.UpsertVolumes(1003, []*structs.Volume{volume}))
+
+	// The node SecretID must match
+	claim := &structs.VolumeClaimRequest{
+		VolumeID:     volume.ID,
+		NodeID:       node.ID,
+		SecretID:     "foo",
+		AllocID:      alloc.ID,
+		Mode:         structs.VolumeClaimWrite,
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.GenericResponse
+	err := msgpackrpc.CallWithCodec(codec, "Volume.Claim", claim, &resp)
+	require.EqualError(err, structs.ErrPermissionDenied.Error())
+
+	claim.SecretID = node.SecretID
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Volume.Claim", claim, &resp))
+
+	// Volumes in use by running allocations can't be detached or deleted
+	detach := &structs.VolumeDetachRequest{
+		VolumeID:     volume.ID,
+		NodeID:       node.ID,
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	err = msgpackrpc.CallWithCodec(codec, "Volume.Detach", detach, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "is in use by allocation")
+
+	del := &structs.VolumeDeleteRequest{
+		VolumeIDs:    []string{volume.ID},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	err = msgpackrpc.CallWithCodec(codec, "Volume.Delete", del, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "is in use by allocation")
+
+	// Once the allocation is terminal the volume can be detached
+	update := alloc.Copy()
+	update.ClientStatus = structs.AllocClientStatusComplete
+	require.NoError(state.UpdateAllocsFromClient(1004, []*structs.Allocation{update}))
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Volume.Detach", detach, &resp))
+
+	out, err := state.VolumeByID(nil, volume.Namespace, volume.ID)
+	require.NoError(err)
+	require.Empty(out.Claims)
+
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Volume.Delete", del, &resp))
+	out, err = state.VolumeByID(nil, volume.Namespace, volume.ID)
+	require.NoError(err)
+	require.Nil(out)
+}
+
+func TestVolumeEndpoint_ACL(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1, root := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	readToken := mock.CreatePolicyAndToken(t, state, 1001, "read",
+		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityReadVolume}))
+	writeToken := mock.CreatePolicyAndToken(t, state, 1002, "write",
+		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityWriteVolume}))
+
+	register := &structs.VolumeRegisterRequest{
+		Volumes:      []*structs.Volume{mock.Volume()},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.GenericResponse
+
+	// Try without a token and with a read token
+	err := msgpackrpc.CallWithCodec(codec, "Volume.Register", register, &resp)
+	require.EqualError(err, structs.ErrPermissionDenied.Error())
+	register.AuthToken = readToken.SecretID
+	err = msgpackrpc.CallWithCodec(codec, "Volume.Register", register, &resp)
+	require.EqualError(err, structs.ErrPermissionDenied.Error())
+
+	// Try with a write token
+	register.AuthToken = writeToken.SecretID
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Volume.Register", register, &resp))
+
+	list := &structs.VolumeListRequest{
+		QueryOptions: structs.QueryOptions{Region: "global", AuthToken: writeToken.SecretID},
+	}
+	var listResp structs.VolumeListResponse
+	err = msgpackrpc.CallWithCodec(codec, "Volume.List", list, &listResp)
+	require.EqualError(err, structs.ErrPermissionDenied.Error())
+
+	// Try with a read token and a management token
+	list.AuthToken = readToken.SecretID
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Volume.List", list, &listResp))
+	require.Len(listResp.Volumes, 1)
+	list.AuthToken = root.SecretID
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Volume.List", list, &listResp))
+}
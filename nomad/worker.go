@@ -58,9 +58,14 @@ type Worker struct {
 	start  time.Time
 
 	paused    bool
+	stopped   bool
 	pauseLock sync.Mutex
 	pauseCond *sync.Cond
 
+	// stopCh is closed to stop the worker once its current evaluation is
+	// complete.
+	stopCh chan struct{}
+
 	failures uint
 
 	evalToken string
@@ -77,6 +82,7 @@ func NewWorker(srv *Server) (*Worker, error) {
 		srv:    srv,
 		logger: srv.logger.ResetNamed("worker"),
 		start:  time.Now(),
+		stopCh: make(chan struct{}),
 	}
 	w.pauseCond = sync.NewCond(&w.pauseLock)
 	go w.run()
@@ -93,15 +99,37 @@ func (w *Worker) SetPause(p bool) {
 	}
 }
 
+// Stop is used to stop the worker once its current evaluation is complete.
+// A stopped worker can not be restarted.
+func (w *Worker) Stop() {
+	w.pauseLock.Lock()
+	if !w.stopped {
+		w.stopped = true
+		close(w.stopCh)
+	}
+	w.pauseLock.Unlock()
+	w.pauseCond.Broadcast()
+}
+
 // checkPaused is used to park the worker when paused
 func (w *Worker) checkPaused() {
 	w.pauseLock.Lock()
-	for w.paused {
+	for w.paused && !w.stopped {
 		w.pauseCond.Wait()
 	}
 	w.pauseLock.Unlock()
 }
 
+// isStopped returns whether the worker has been stopped
+func (w *Worker) isStopped() bool {
+	select {
+	case <-w.stopCh:
+		return true
+	default:
+		return false
+	}
+}
+
 // run is the long-lived goroutine which is used to run the worker
 func (w *Worker) run() {
 	for {
@@ -156,6 +184,11 @@ REQ:
 	// Check if we are paused
 	w.checkPaused()
 
+	// Check if we were stopped
+	if w.isStopped() {
+		return nil, "", 0, true
+	}
+
 	// Make a blocking RPC
 	start := time.Now()
 	err := w.srv.RPC("Eval.Dequeue", &req, &resp)
@@ -187,7 +220,7 @@ REQ:
 	}
 
 	// Check for potential shutdown
-	if w.srv.IsShutdown() {
+	if w.srv.IsShutdown() || w.isStopped() {
 		return nil, "", 0, true
 	}
 	goto REQ
@@ -527,6 +560,8 @@ func (w *Worker) backoffErr(base, limit time.Duration) bool {
 		return false
 	case <-w.srv.shutdownCh:
 		return true
+	case <-w.stopCh:
+		return true
 	}
 }
 
@@ -3,7 +3,9 @@ package drivers
 import (
 	"context"
 	"errors"
+	"fmt"
 	"io"
+	"sync"
 	"time"
 
 	"github.com/LK4D4/joincontext"
@@ -21,6 +23,9 @@ import (
 )
 
 var _ DriverPlugin = &driverPluginClient{}
+var _ ExecTaskStreamingDriver = &driverPluginClient{}
+var _ CheckpointDriver = &driverPluginClient{}
+var _ DriverNetworkManager = &driverPluginClient{}
 
 type driverPluginClient struct {
 	*base.BasePluginClient
@@ -55,6 +60,10 @@ func (d *driverPluginClient) Capabilities() (*Capabilities, error) {
 	if resp.Capabilities != nil {
 		caps.SendSignals = resp.Capabilities.SendSignals
 		caps.Exec = resp.Capabilities.Exec
+		caps.Checkpoint = resp.Capabilities.Checkpoint
+		caps.RemoteTasks = resp.Capabilities.RemoteTasks
+		caps.NetworkIsolation = resp.Capabilities.NetworkIsolation
+		caps.MustCreateNetwork = resp.Capabilities.MustCreateNetwork
 
 		switch resp.Capabilities.FsIsolation {
 		case proto.DriverCapabilities_NONE:
@@ -357,3 +366,182 @@ func (d *driverPluginClient) ExecTask(taskID string, cmd []string, timeout time.
 	return result, nil
 
 }
+
+// ExecTaskStreaming will run the given command within the execution context of
+// the task, streaming its stdin, stdout and stderr over the plugin connection.
+// It blocks until the command exits and returns its exit information.
+func (d *driverPluginClient) ExecTaskStreaming(ctx context.Context, taskID string, opts *ExecOptions) (*ExitResult, error) {
+	// Join the passed context and the shutdown context
+	ctx, cancel := joincontext.Join(ctx, d.doneCtx)
+	defer cancel()
+
+	stream, err := d.client.ExecTaskStreaming(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// grpc streams may not be written to concurrently
+	var sendLock sync.Mutex
+	send := func(req *proto.ExecTaskStreamingRequest) error {
+		sendLock.Lock()
+		defer sendLock.Unlock()
+		return stream.Send(req)
+	}
+
+	setup := &proto.ExecTaskStreamingRequest{
+		Setup: &proto.ExecTaskStreamingRequest_Setup{
+			TaskId:  taskID,
+			Command: opts.Command,
+			Tty:     opts.Tty,
+		},
+	}
+	if err := send(setup); err != nil {
+		return nil, shared.HandleStreamErr(err, ctx, d.doneCtx)
+	}
+
+	if opts.Stdin != nil {
+		go d.execStreamingSendStdin(opts.Stdin, send)
+	}
+	if opts.ResizeCh != nil {
+		go d.execStreamingSendResizes(ctx, opts.ResizeCh, send)
+	}
+
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			return nil, fmt.Errorf("exec stream closed before command exited")
+		} else if err != nil {
+			return nil, shared.HandleStreamErr(err, ctx, d.doneCtx)
+		}
+
+		if err := execStreamingWrite(opts.Stdout, resp.Stdout); err != nil {
+			return nil, fmt.Errorf("failed to write stdout: %v", err)
+		}
+		if err := execStreamingWrite(opts.Stderr, resp.Stderr); err != nil {
+			return nil, fmt.Errorf("failed to write stderr: %v", err)
+		}
+
+		if resp.Exited {
+			return exitResultFromProto(resp.Result), nil
+		}
+	}
+}
+
+// execStreamingSendStdin forwards the stdin of an exec session to the plugin
+// until it is closed or the stream fails.
+func (d *driverPluginClient) execStreamingSendStdin(stdin io.Reader, send func(*proto.ExecTaskStreamingRequest) error) {
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := stdin.Read(buf)
+		if n > 0 {
+			data := make([]byte, n)
+			copy(data, buf[:n])
+			req := &proto.ExecTaskStreamingRequest{
+				Stdin: &proto.ExecTaskStreamingIOOperation{Data: data},
+			}
+			if err := send(req); err != nil {
+				d.logger.Debug("failed to send stdin of exec session", "error", err)
+				return
+			}
+		}
+
+		if err != nil {
+			if err != io.EOF {
+				d.logger.Debug("failed to read stdin of exec session", "error", err)
+			}
+
+			req := &proto.ExecTaskStreamingRequest{
+				Stdin: &proto.ExecTaskStreamingIOOperation{Close: true},
+			}
+			send(req)
+			return
+		}
+	}
+}
+
+// execStreamingSendResizes forwards terminal size changes of an exec session
+// to the plugin until the session ends.
+func (d *driverPluginClient) execStreamingSendResizes(ctx context.Context, ch <-chan TerminalSize,
+	send func(*proto.ExecTaskStreamingRequest) error) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case size, ok := <-ch:
+			if !ok {
+				return
+			}
+
+			req := &proto.ExecTaskStreamingRequest{
+				TtySize: &proto.ExecTaskStreamingRequest_TerminalSize{
+					Height: int32(size.Height),
+					Width:  int32(size.Width),
+				},
+			}
+			if err := send(req); err != nil {
+				d.logger.Debug("failed to send terminal size of exec session", "error", err)
+				return
+			}
+		}
+	}
+}
+
+// execStreamingWrite applies an IO operation received from an exec stream to
+// the given writer.
+func execStreamingWrite(w io.WriteCloser, op *proto.ExecTaskStreamingIOOperation) error {
+	if op == nil || w == nil {
+		return nil
+	}
+
+	if len(op.Data) > 0 {
+		if _, err := w.Write(op.Data); err != nil {
+			return err
+		}
+	}
+
+	if op.Close {
+		return w.Close()
+	}
+	return nil
+}
+
+// CheckpointTask will checkpoint the given task into its checkpoint directory,
+// returning whether a checkpoint was taken.
+func (d *driverPluginClient) CheckpointTask(taskID string) (bool, error) {
+	req := &proto.CheckpointTaskRequest{
+		TaskId: taskID,
+	}
+
+	resp, err := d.client.CheckpointTask(d.doneCtx, req)
+	if err != nil {
+		return false, err
+	}
+
+	return resp.Checkpointed, nil
+}
+
+// CreateNetwork creates the network namespace of the given allocation,
+// returning whether it was created or already existed.
+func (d *driverPluginClient) CreateNetwork(allocID string) (*NetworkIsolationSpec, bool, error) {
+	req := &proto.CreateNetworkRequest{
+		AllocId: allocID,
+	}
+
+	resp, err := d.client.CreateNetwork(d.doneCtx, req)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return networkIsolationFromProto(resp.IsolationSpec), resp.Created, nil
+}
+
+// DestroyNetwork destroys the network namespace of the given allocation.
+func (d *driverPluginClient) DestroyNetwork(allocID string, spec *NetworkIsolationSpec) error {
+	req := &proto.DestroyNetworkRequest{
+		AllocId:       allocID,
+		IsolationSpec: networkIsolationToProto(spec),
+	}
+
+	_, err := d.client.DestroyNetwork(d.doneCtx, req)
+	return err
+}
@@ -3,6 +3,7 @@ package drivers
 import (
 	"context"
 	"fmt"
+	"io"
 	"path/filepath"
 	"sort"
 	"time"
@@ -95,6 +96,29 @@ type Capabilities struct {
 
 	//FSIsolation indicates what kind of filesystem isolation the driver supports.
 	FSIsolation cstructs.FSIsolation
+
+	// Checkpoint marks the driver as being able to checkpoint running tasks
+	// and restore them when started again. Such drivers implement the
+	// CheckpointDriver interface.
+	Checkpoint bool
+
+	// RemoteTasks marks the driver as running tasks away from the client,
+	// for example on a cloud container service or a remote hypervisor. The
+	// client only supervises such tasks, so they keep running when the client
+	// is restarted and may be recovered by a replacement allocation on
+	// another client if this one is lost.
+	RemoteTasks bool
+
+	// NetworkIsolation marks the driver as being able to run tasks in the
+	// network namespace of their allocation, given by the NetworkIsolation
+	// of their configuration.
+	NetworkIsolation bool
+
+	// MustCreateNetwork marks the driver as having to create the network
+	// namespace of allocations itself because its tasks can't join one
+	// created by the client. Such drivers implement the DriverNetworkManager
+	// interface.
+	MustCreateNetwork bool
 }
 
 type TaskConfig struct {
@@ -112,6 +136,31 @@ type TaskConfig struct {
 	StdoutPath      string
 	StderrPath      string
 	AllocID         string
+
+	// NetworkIsolation is the network namespace the task must run in, set
+	// when its allocation has its own network. Only drivers with the
+	// NetworkIsolation capability are given tasks with it.
+	NetworkIsolation *NetworkIsolationSpec
+}
+
+// NetworkIsolationSpec is the network namespace of an allocation.
+type NetworkIsolationSpec struct {
+	// Path is the path of the network namespace
+	Path string
+
+	// Labels are set by the driver that created the network namespace to
+	// identify it
+	Labels map[string]string
+}
+
+func (n *NetworkIsolationSpec) Copy() *NetworkIsolationSpec {
+	if n == nil {
+		return nil
+	}
+	c := new(NetworkIsolationSpec)
+	*c = *n
+	c.Labels = helper.CopyMapStringString(n.Labels)
+	return c
 }
 
 func (tc *TaskConfig) Copy() *TaskConfig {
@@ -122,6 +171,7 @@ func (tc *TaskConfig) Copy() *TaskConfig {
 	*c = *tc
 	c.Env = helper.CopyMapStringString(c.Env)
 	c.Resources = tc.Resources.Copy()
+	c.NetworkIsolation = tc.NetworkIsolation.Copy()
 	return c
 }
 
@@ -144,6 +194,7 @@ func (tc *TaskConfig) TaskDir() *allocdir.TaskDir {
 		SharedTaskDir:  filepath.Join(taskDir, allocdir.SharedAllocName),
 		LocalDir:       filepath.Join(taskDir, allocdir.TaskLocal),
 		SecretsDir:     filepath.Join(taskDir, allocdir.TaskSecrets),
+		CheckpointDir:  filepath.Join(taskDir, allocdir.TaskLocal, allocdir.TaskCheckpoint),
 	}
 }
 
@@ -207,14 +258,36 @@ type LinuxResources struct {
 	// specific options are deprecated in favor of exposes CPUPeriod and
 	// CPUQuota at the task resource stanza.
 	PercentTicks float64
+
+	// IOWeight is the relative block I/O weight of the task and IOLimits
+	// throttle its I/O against individual block devices.
+	IOWeight int64
+	IOLimits []*IOLimit
 }
 
 func (r *LinuxResources) Copy() *LinuxResources {
 	res := new(LinuxResources)
 	*res = *r
+	if r.IOLimits != nil {
+		res.IOLimits = make([]*IOLimit, len(r.IOLimits))
+		for i, l := range r.IOLimits {
+			nl := *l
+			res.IOLimits[i] = &nl
+		}
+	}
 	return res
 }
 
+// IOLimit throttles the block I/O of a task against a single block device.
+// Zero values are left unlimited.
+type IOLimit struct {
+	Device    string
+	ReadBps   int64
+	WriteBps  int64
+	ReadIOps  int64
+	WriteIOps int64
+}
+
 type DeviceConfig struct {
 	TaskPath    string
 	HostPath    string
@@ -281,3 +354,61 @@ type ExecTaskResult struct {
 	Stderr     []byte
 	ExitResult *ExitResult
 }
+
+// ExecTaskStreamingDriver is an optional interface a driver may implement to
+// support interactive exec sessions, where the input and output of the
+// command are streamed rather than buffered until it exits.
+type ExecTaskStreamingDriver interface {
+	// ExecTaskStreaming runs the command described by opts within the
+	// execution context of the task and blocks until it exits or ctx is
+	// cancelled.
+	ExecTaskStreaming(ctx context.Context, taskID string, opts *ExecOptions) (*ExitResult, error)
+}
+
+// CheckpointDriver is an optional interface implemented by drivers that set
+// the Checkpoint capability.
+type CheckpointDriver interface {
+	// CheckpointTask stores the state of a running task in its checkpoint
+	// directory and stops it. The driver restores the task from the
+	// checkpoint the next time it is started. Tasks are only checkpointed if
+	// they enable it in their driver configuration, the returned bool is
+	// false otherwise.
+	CheckpointTask(taskID string) (bool, error)
+}
+
+// DriverNetworkManager is an optional interface implemented by drivers that
+// set the MustCreateNetwork capability.
+type DriverNetworkManager interface {
+	// CreateNetwork creates the network namespace of an allocation. The
+	// returned bool is false if the namespace already existed, for example
+	// when the client is restarted.
+	CreateNetwork(allocID string) (*NetworkIsolationSpec, bool, error)
+
+	// DestroyNetwork destroys a network namespace created by CreateNetwork.
+	DestroyNetwork(allocID string, spec *NetworkIsolationSpec) error
+}
+
+// ExecOptions holds the command and streams of a streaming exec session.
+type ExecOptions struct {
+	// Command is the command to run, including its arguments
+	Command []string
+
+	// Tty indicates whether a pseudo-terminal should be allocated
+	Tty bool
+
+	// Stdin, Stdout and Stderr are the streams of the command. Stdout and
+	// Stderr are closed once the command has exited.
+	Stdin  io.ReadCloser
+	Stdout io.WriteCloser
+	Stderr io.WriteCloser
+
+	// ResizeCh receives the new terminal size whenever the terminal of the
+	// caller is resized. It is only relevant if Tty is set.
+	ResizeCh <-chan TerminalSize
+}
+
+// TerminalSize is the size of a terminal in characters.
+type TerminalSize struct {
+	Height int
+	Width  int
+}
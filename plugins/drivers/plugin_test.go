@@ -3,6 +3,10 @@ package drivers
 import (
 	"bytes"
 	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -242,3 +246,149 @@ func TestBaseDriver_TaskEvents(t *testing.T) {
 	}
 
 }
+
+type closeBuffer struct {
+	bytes.Buffer
+	closed bool
+}
+
+func (b *closeBuffer) Close() error {
+	b.closed = true
+	return nil
+}
+
+func TestBaseDriver_ExecTaskStreaming(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	impl := &MockDriver{
+		ExecTaskStreamingF: func(ctx context.Context, taskID string, opts *ExecOptions) (*ExitResult, error) {
+			require.Equal("foo", taskID)
+			require.Equal([]string{"/bin/sh", "-i"}, opts.Command)
+			require.True(opts.Tty)
+
+			input, err := ioutil.ReadAll(opts.Stdin)
+			require.NoError(err)
+
+			select {
+			case size := <-opts.ResizeCh:
+				require.Equal(TerminalSize{Height: 40, Width: 120}, size)
+			case <-time.After(5 * time.Second):
+				return nil, context.DeadlineExceeded
+			}
+
+			io.WriteString(opts.Stdout, strings.ToUpper(string(input)))
+			io.WriteString(opts.Stderr, "done")
+			opts.Stdout.Close()
+			opts.Stderr.Close()
+
+			return &ExitResult{ExitCode: 3}, nil
+		},
+	}
+
+	harness := NewDriverHarness(t, impl)
+	defer harness.Kill()
+
+	driver, ok := harness.DriverPlugin.(ExecTaskStreamingDriver)
+	require.True(ok)
+
+	resizeCh := make(chan TerminalSize, 1)
+	resizeCh <- TerminalSize{Height: 40, Width: 120}
+
+	var stdout, stderr closeBuffer
+	opts := &ExecOptions{
+		Command:  []string{"/bin/sh", "-i"},
+		Tty:      true,
+		Stdin:    ioutil.NopCloser(strings.NewReader("hello world")),
+		Stdout:   &stdout,
+		Stderr:   &stderr,
+		ResizeCh: resizeCh,
+	}
+
+	result, err := driver.ExecTaskStreaming(context.Background(), "foo", opts)
+	require.NoError(err)
+	require.Equal(3, result.ExitCode)
+	require.Equal("HELLO WORLD", stdout.String())
+	require.Equal("done", stderr.String())
+	require.True(stdout.closed)
+	require.True(stderr.closed)
+}
+
+func TestBaseDriver_ExecTaskStreaming_Error(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	impl := &MockDriver{
+		ExecTaskStreamingF: func(ctx context.Context, taskID string, opts *ExecOptions) (*ExitResult, error) {
+			return nil, fmt.Errorf("task %q not found", taskID)
+		},
+	}
+
+	harness := NewDriverHarness(t, impl)
+	defer harness.Kill()
+
+	driver := harness.DriverPlugin.(ExecTaskStreamingDriver)
+	_, err := driver.ExecTaskStreaming(context.Background(), "foo", &ExecOptions{
+		Command: []string{"ls"},
+	})
+	require.Error(err)
+	require.Contains(err.Error(), "not found")
+}
+
+func TestBaseDriver_CheckpointTask(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	impl := &MockDriver{
+		CheckpointTaskF: func(taskID string) (bool, error) {
+			if taskID != "foo" {
+				return false, fmt.Errorf("task %q not found", taskID)
+			}
+			return true, nil
+		},
+	}
+
+	harness := NewDriverHarness(t, impl)
+	defer harness.Kill()
+
+	driver := harness.DriverPlugin.(CheckpointDriver)
+	checkpointed, err := driver.CheckpointTask("foo")
+	require.NoError(err)
+	require.True(checkpointed)
+
+	_, err = driver.CheckpointTask("bar")
+	require.Error(err)
+	require.Contains(err.Error(), "not found")
+}
+
+func TestBaseDriver_CreateDestroyNetwork(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	var destroyed *NetworkIsolationSpec
+	impl := &MockDriver{
+		CreateNetworkF: func(allocID string) (*NetworkIsolationSpec, bool, error) {
+			return &NetworkIsolationSpec{
+				Path:   "/proc/1/ns/net",
+				Labels: map[string]string{"alloc": allocID},
+			}, true, nil
+		},
+		DestroyNetworkF: func(allocID string, spec *NetworkIsolationSpec) error {
+			destroyed = spec
+			return nil
+		},
+	}
+
+	harness := NewDriverHarness(t, impl)
+	defer harness.Kill()
+
+	driver := harness.DriverPlugin.(DriverNetworkManager)
+	spec, created, err := driver.CreateNetwork("foo")
+	require.NoError(err)
+	require.True(created)
+	require.Equal("/proc/1/ns/net", spec.Path)
+	require.Equal(map[string]string{"alloc": "foo"}, spec.Labels)
+
+	require.NoError(driver.DestroyNetwork("foo", spec))
+	require.Equal(spec, destroyed)
+}
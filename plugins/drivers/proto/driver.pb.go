@@ -174,6 +174,8 @@ const (
 	MemoryUsage_MAX_USAGE        MemoryUsage_Fields = 2
 	MemoryUsage_KERNEL_USAGE     MemoryUsage_Fields = 3
 	MemoryUsage_KERNEL_MAX_USAGE MemoryUsage_Fields = 4
+	MemoryUsage_USAGE            MemoryUsage_Fields = 5
+	MemoryUsage_BALLOON_ACTUAL   MemoryUsage_Fields = 6
 )
 
 var MemoryUsage_Fields_name = map[int32]string{
@@ -182,6 +184,8 @@ var MemoryUsage_Fields_name = map[int32]string{
 	2: "MAX_USAGE",
 	3: "KERNEL_USAGE",
 	4: "KERNEL_MAX_USAGE",
+	5: "USAGE",
+	6: "BALLOON_ACTUAL",
 }
 var MemoryUsage_Fields_value = map[string]int32{
 	"RSS":              0,
@@ -189,6 +193,8 @@ var MemoryUsage_Fields_value = map[string]int32{
 	"MAX_USAGE":        2,
 	"KERNEL_USAGE":     3,
 	"KERNEL_MAX_USAGE": 4,
+	"USAGE":            5,
+	"BALLOON_ACTUAL":   6,
 }
 
 func (x MemoryUsage_Fields) String() string {
@@ -198,6 +204,35 @@ func (MemoryUsage_Fields) EnumDescriptor() ([]byte, []int) {
 	return fileDescriptor_driver_f8c1fd114dd6e6a4, []int{42, 0}
 }
 
+type IOUsage_Fields int32
+
+const (
+	IOUsage_READ_BYTES  IOUsage_Fields = 0
+	IOUsage_WRITE_BYTES IOUsage_Fields = 1
+	IOUsage_READ_OPS    IOUsage_Fields = 2
+	IOUsage_WRITE_OPS   IOUsage_Fields = 3
+)
+
+var IOUsage_Fields_name = map[int32]string{
+	0: "READ_BYTES",
+	1: "WRITE_BYTES",
+	2: "READ_OPS",
+	3: "WRITE_OPS",
+}
+var IOUsage_Fields_value = map[string]int32{
+	"READ_BYTES":  0,
+	"WRITE_BYTES": 1,
+	"READ_OPS":    2,
+	"WRITE_OPS":   3,
+}
+
+func (x IOUsage_Fields) String() string {
+	return proto.EnumName(IOUsage_Fields_name, int32(x))
+}
+func (IOUsage_Fields) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_driver_f8c1fd114dd6e6a4, []int{50, 0}
+}
+
 type TaskConfigSchemaRequest struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -1284,10 +1319,22 @@ type DriverCapabilities struct {
 	// in the task's execution environment.
 	Exec bool `protobuf:"varint,2,opt,name=exec,proto3" json:"exec,omitempty"`
 	// FsIsolation indicates what kind of filesystem isolation a driver supports.
-	FsIsolation          DriverCapabilities_FSIsolation `protobuf:"varint,3,opt,name=fs_isolation,json=fsIsolation,proto3,enum=hashicorp.nomad.plugins.drivers.proto.DriverCapabilities_FSIsolation" json:"fs_isolation,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}                       `json:"-"`
-	XXX_unrecognized     []byte                         `json:"-"`
-	XXX_sizecache        int32                          `json:"-"`
+	FsIsolation DriverCapabilities_FSIsolation `protobuf:"varint,3,opt,name=fs_isolation,json=fsIsolation,proto3,enum=hashicorp.nomad.plugins.drivers.proto.DriverCapabilities_FSIsolation" json:"fs_isolation,omitempty"`
+	// Checkpoint indicates that the driver supports checkpointing a running
+	// task so it can be restored later, possibly on another node.
+	Checkpoint bool `protobuf:"varint,4,opt,name=checkpoint,proto3" json:"checkpoint,omitempty"`
+	// RemoteTasks indicates that the driver runs tasks away from the client,
+	// which only supervises them.
+	RemoteTasks bool `protobuf:"varint,5,opt,name=remote_tasks,json=remoteTasks,proto3" json:"remote_tasks,omitempty"`
+	// NetworkIsolation indicates that the driver can run tasks in the network
+	// namespace given by their configuration.
+	NetworkIsolation bool `protobuf:"varint,6,opt,name=network_isolation,json=networkIsolation,proto3" json:"network_isolation,omitempty"`
+	// MustCreateNetwork indicates that the driver must create the network
+	// namespace of the allocations it runs tasks of.
+	MustCreateNetwork    bool     `protobuf:"varint,7,opt,name=must_create_network,json=mustCreateNetwork,proto3" json:"must_create_network,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *DriverCapabilities) Reset()         { *m = DriverCapabilities{} }
@@ -1335,6 +1382,34 @@ func (m *DriverCapabilities) GetFsIsolation() DriverCapabilities_FSIsolation {
 	return DriverCapabilities_NONE
 }
 
+func (m *DriverCapabilities) GetCheckpoint() bool {
+	if m != nil {
+		return m.Checkpoint
+	}
+	return false
+}
+
+func (m *DriverCapabilities) GetRemoteTasks() bool {
+	if m != nil {
+		return m.RemoteTasks
+	}
+	return false
+}
+
+func (m *DriverCapabilities) GetNetworkIsolation() bool {
+	if m != nil {
+		return m.NetworkIsolation
+	}
+	return false
+}
+
+func (m *DriverCapabilities) GetMustCreateNetwork() bool {
+	if m != nil {
+		return m.MustCreateNetwork
+	}
+	return false
+}
+
 type TaskConfig struct {
 	// Id of the task, recommended to the globally unique, must be unique to the driver.
 	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -1365,10 +1440,13 @@ type TaskConfig struct {
 	// JobName is the name of the job of which this task is part of
 	JobName string `protobuf:"bytes,13,opt,name=job_name,json=jobName,proto3" json:"job_name,omitempty"`
 	// AllocId is the ID of the associated allocation
-	AllocId              string   `protobuf:"bytes,14,opt,name=alloc_id,json=allocId,proto3" json:"alloc_id,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	AllocId string `protobuf:"bytes,14,opt,name=alloc_id,json=allocId,proto3" json:"alloc_id,omitempty"`
+	// NetworkIsolation is the network namespace the task runs in, if the
+	// allocation has its own
+	NetworkIsolation     *NetworkIsolationSpec `protobuf:"bytes,15,opt,name=network_isolation,json=networkIsolation,proto3" json:"network_isolation,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
 }
 
 func (m *TaskConfig) Reset()         { *m = TaskConfig{} }
@@ -1493,6 +1571,13 @@ func (m *TaskConfig) GetAllocId() string {
 	return ""
 }
 
+func (m *TaskConfig) GetNetworkIsolation() *NetworkIsolationSpec {
+	if m != nil {
+		return m.NetworkIsolation
+	}
+	return nil
+}
+
 type Resources struct {
 	// RawResources are the resources set for the task
 	RawResources *RawResources `protobuf:"bytes,1,opt,name=raw_resources,json=rawResources,proto3" json:"raw_resources,omitempty"`
@@ -1690,8 +1775,11 @@ func (m *NetworkResource) GetDynamicPorts() []*NetworkPort {
 }
 
 type NetworkPort struct {
-	Label                string   `protobuf:"bytes,1,opt,name=label,proto3" json:"label,omitempty"`
-	Value                int32    `protobuf:"varint,2,opt,name=value,proto3" json:"value,omitempty"`
+	Label string `protobuf:"bytes,1,opt,name=label,proto3" json:"label,omitempty"`
+	Value int32  `protobuf:"varint,2,opt,name=value,proto3" json:"value,omitempty"`
+	// HostIP is the address of the host network the port is bound to, empty
+	// if the port is bound to the IP of its network
+	HostIp               string   `protobuf:"bytes,3,opt,name=host_ip,json=hostIp,proto3" json:"host_ip,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -1735,6 +1823,13 @@ func (m *NetworkPort) GetValue() int32 {
 	return 0
 }
 
+func (m *NetworkPort) GetHostIp() string {
+	if m != nil {
+		return m.HostIp
+	}
+	return ""
+}
+
 type LinuxResources struct {
 	// CPU CFS (Completely Fair Scheduler) period. Default: 0 (not specified)
 	CpuPeriod int64 `protobuf:"varint,1,opt,name=cpu_period,json=cpuPeriod,proto3" json:"cpu_period,omitempty"`
@@ -1751,10 +1846,14 @@ type LinuxResources struct {
 	// CpusetMems constrains the allowed set of memory nodes. Default: "" (not specified)
 	CpusetMems string `protobuf:"bytes,7,opt,name=cpuset_mems,json=cpusetMems,proto3" json:"cpuset_mems,omitempty"`
 	// PercentTicks is a compatibility option for docker and should not be used
-	PercentTicks         float64  `protobuf:"fixed64,8,opt,name=PercentTicks,proto3" json:"PercentTicks,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	PercentTicks float64 `protobuf:"fixed64,8,opt,name=PercentTicks,proto3" json:"PercentTicks,omitempty"`
+	// IoWeight is the relative block I/O weight. Default: 0 (not specified)
+	IoWeight int64 `protobuf:"varint,9,opt,name=io_weight,json=ioWeight,proto3" json:"io_weight,omitempty"`
+	// IoLimits throttle the block I/O against individual devices
+	IoLimits             []*IOLimit `protobuf:"bytes,10,rep,name=io_limits,json=ioLimits,proto3" json:"io_limits,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
 }
 
 func (m *LinuxResources) Reset()         { *m = LinuxResources{} }
@@ -1837,6 +1936,20 @@ func (m *LinuxResources) GetPercentTicks() float64 {
 	return 0
 }
 
+func (m *LinuxResources) GetIoWeight() int64 {
+	if m != nil {
+		return m.IoWeight
+	}
+	return 0
+}
+
+func (m *LinuxResources) GetIoLimits() []*IOLimit {
+	if m != nil {
+		return m.IoLimits
+	}
+	return nil
+}
+
 type Mount struct {
 	// TaskPath is the file path within the task directory to mount to
 	TaskPath string `protobuf:"bytes,1,opt,name=task_path,json=taskPath,proto3" json:"task_path,omitempty"`
@@ -2327,10 +2440,12 @@ type TaskResourceUsage struct {
 	// CPU usage stats
 	Cpu *CPUUsage `protobuf:"bytes,1,opt,name=cpu,proto3" json:"cpu,omitempty"`
 	// Memory usage stats
-	Memory               *MemoryUsage `protobuf:"bytes,2,opt,name=memory,proto3" json:"memory,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
-	XXX_unrecognized     []byte       `json:"-"`
-	XXX_sizecache        int32        `json:"-"`
+	Memory *MemoryUsage `protobuf:"bytes,2,opt,name=memory,proto3" json:"memory,omitempty"`
+	// Block I/O usage stats
+	Io                   *IOUsage `protobuf:"bytes,3,opt,name=io,proto3" json:"io,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *TaskResourceUsage) Reset()         { *m = TaskResourceUsage{} }
@@ -2371,6 +2486,13 @@ func (m *TaskResourceUsage) GetMemory() *MemoryUsage {
 	return nil
 }
 
+func (m *TaskResourceUsage) GetIo() *IOUsage {
+	if m != nil {
+		return m.Io
+	}
+	return nil
+}
+
 type CPUUsage struct {
 	SystemMode       float64 `protobuf:"fixed64,1,opt,name=system_mode,json=systemMode,proto3" json:"system_mode,omitempty"`
 	UserMode         float64 `protobuf:"fixed64,2,opt,name=user_mode,json=userMode,proto3" json:"user_mode,omitempty"`
@@ -2464,6 +2586,8 @@ type MemoryUsage struct {
 	MaxUsage       uint64 `protobuf:"varint,3,opt,name=max_usage,json=maxUsage,proto3" json:"max_usage,omitempty"`
 	KernelUsage    uint64 `protobuf:"varint,4,opt,name=kernel_usage,json=kernelUsage,proto3" json:"kernel_usage,omitempty"`
 	KernelMaxUsage uint64 `protobuf:"varint,5,opt,name=kernel_max_usage,json=kernelMaxUsage,proto3" json:"kernel_max_usage,omitempty"`
+	Usage          uint64 `protobuf:"varint,7,opt,name=usage,proto3" json:"usage,omitempty"`
+	BalloonActual  uint64 `protobuf:"varint,8,opt,name=balloon_actual,json=balloonActual,proto3" json:"balloon_actual,omitempty"`
 	// MeasuredFields indicates which fields were actually sampled
 	MeasuredFields       []MemoryUsage_Fields `protobuf:"varint,6,rep,packed,name=measured_fields,json=measuredFields,proto3,enum=hashicorp.nomad.plugins.drivers.proto.MemoryUsage_Fields" json:"measured_fields,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
@@ -2530,6 +2654,20 @@ func (m *MemoryUsage) GetKernelMaxUsage() uint64 {
 	return 0
 }
 
+func (m *MemoryUsage) GetUsage() uint64 {
+	if m != nil {
+		return m.Usage
+	}
+	return 0
+}
+
+func (m *MemoryUsage) GetBalloonActual() uint64 {
+	if m != nil {
+		return m.BalloonActual
+	}
+	return 0
+}
+
 func (m *MemoryUsage) GetMeasuredFields() []MemoryUsage_Fields {
 	if m != nil {
 		return m.MeasuredFields
@@ -2603,181 +2741,922 @@ func (m *DriverTaskEvent) GetAnnotations() map[string]string {
 	return nil
 }
 
-func init() {
-	proto.RegisterType((*TaskConfigSchemaRequest)(nil), "hashicorp.nomad.plugins.drivers.proto.TaskConfigSchemaRequest")
-	proto.RegisterType((*TaskConfigSchemaResponse)(nil), "hashicorp.nomad.plugins.drivers.proto.TaskConfigSchemaResponse")
-	proto.RegisterType((*CapabilitiesRequest)(nil), "hashicorp.nomad.plugins.drivers.proto.CapabilitiesRequest")
-	proto.RegisterType((*CapabilitiesResponse)(nil), "hashicorp.nomad.plugins.drivers.proto.CapabilitiesResponse")
-	proto.RegisterType((*FingerprintRequest)(nil), "hashicorp.nomad.plugins.drivers.proto.FingerprintRequest")
-	proto.RegisterType((*FingerprintResponse)(nil), "hashicorp.nomad.plugins.drivers.proto.FingerprintResponse")
-	proto.RegisterMapType((map[string]*proto1.Attribute)(nil), "hashicorp.nomad.plugins.drivers.proto.FingerprintResponse.AttributesEntry")
-	proto.RegisterType((*RecoverTaskRequest)(nil), "hashicorp.nomad.plugins.drivers.proto.RecoverTaskRequest")
-	proto.RegisterType((*RecoverTaskResponse)(nil), "hashicorp.nomad.plugins.drivers.proto.RecoverTaskResponse")
-	proto.RegisterType((*StartTaskRequest)(nil), "hashicorp.nomad.plugins.drivers.proto.StartTaskRequest")
-	proto.RegisterType((*StartTaskResponse)(nil), "hashicorp.nomad.plugins.drivers.proto.StartTaskResponse")
-	proto.RegisterType((*WaitTaskRequest)(nil), "hashicorp.nomad.plugins.drivers.proto.WaitTaskRequest")
-	proto.RegisterType((*WaitTaskResponse)(nil), "hashicorp.nomad.plugins.drivers.proto.WaitTaskResponse")
-	proto.RegisterType((*StopTaskRequest)(nil), "hashicorp.nomad.plugins.drivers.proto.StopTaskRequest")
-	proto.RegisterType((*StopTaskResponse)(nil), "hashicorp.nomad.plugins.drivers.proto.StopTaskResponse")
-	proto.RegisterType((*DestroyTaskRequest)(nil), "hashicorp.nomad.plugins.drivers.proto.DestroyTaskRequest")
-	proto.RegisterType((*DestroyTaskResponse)(nil), "hashicorp.nomad.plugins.drivers.proto.DestroyTaskResponse")
-	proto.RegisterType((*InspectTaskRequest)(nil), "hashicorp.nomad.plugins.drivers.proto.InspectTaskRequest")
-	proto.RegisterType((*InspectTaskResponse)(nil), "hashicorp.nomad.plugins.drivers.proto.InspectTaskResponse")
-	proto.RegisterType((*TaskStatsRequest)(nil), "hashicorp.nomad.plugins.drivers.proto.TaskStatsRequest")
-	proto.RegisterType((*TaskStatsResponse)(nil), "hashicorp.nomad.plugins.drivers.proto.TaskStatsResponse")
-	proto.RegisterType((*TaskEventsRequest)(nil), "hashicorp.nomad.plugins.drivers.proto.TaskEventsRequest")
-	proto.RegisterType((*SignalTaskRequest)(nil), "hashicorp.nomad.plugins.drivers.proto.SignalTaskRequest")
-	proto.RegisterType((*SignalTaskResponse)(nil), "hashicorp.nomad.plugins.drivers.proto.SignalTaskResponse")
-	proto.RegisterType((*ExecTaskRequest)(nil), "hashicorp.nomad.plugins.drivers.proto.ExecTaskRequest")
-	proto.RegisterType((*ExecTaskResponse)(nil), "hashicorp.nomad.plugins.drivers.proto.ExecTaskResponse")
-	proto.RegisterType((*DriverCapabilities)(nil), "hashicorp.nomad.plugins.drivers.proto.DriverCapabilities")
-	proto.RegisterType((*TaskConfig)(nil), "hashicorp.nomad.plugins.drivers.proto.TaskConfig")
-	proto.RegisterMapType((map[string]string)(nil), "hashicorp.nomad.plugins.drivers.proto.TaskConfig.EnvEntry")
-	proto.RegisterType((*Resources)(nil), "hashicorp.nomad.plugins.drivers.proto.Resources")
-	proto.RegisterType((*RawResources)(nil), "hashicorp.nomad.plugins.drivers.proto.RawResources")
-	proto.RegisterType((*NetworkResource)(nil), "hashicorp.nomad.plugins.drivers.proto.NetworkResource")
-	proto.RegisterType((*NetworkPort)(nil), "hashicorp.nomad.plugins.drivers.proto.NetworkPort")
-	proto.RegisterType((*LinuxResources)(nil), "hashicorp.nomad.plugins.drivers.proto.LinuxResources")
-	proto.RegisterType((*Mount)(nil), "hashicorp.nomad.plugins.drivers.proto.Mount")
-	proto.RegisterType((*Device)(nil), "hashicorp.nomad.plugins.drivers.proto.Device")
-	proto.RegisterType((*TaskHandle)(nil), "hashicorp.nomad.plugins.drivers.proto.TaskHandle")
-	proto.RegisterType((*NetworkOverride)(nil), "hashicorp.nomad.plugins.drivers.proto.NetworkOverride")
-	proto.RegisterMapType((map[string]int32)(nil), "hashicorp.nomad.plugins.drivers.proto.NetworkOverride.PortMapEntry")
-	proto.RegisterType((*ExitResult)(nil), "hashicorp.nomad.plugins.drivers.proto.ExitResult")
-	proto.RegisterType((*TaskStatus)(nil), "hashicorp.nomad.plugins.drivers.proto.TaskStatus")
-	proto.RegisterType((*TaskDriverStatus)(nil), "hashicorp.nomad.plugins.drivers.proto.TaskDriverStatus")
-	proto.RegisterMapType((map[string]string)(nil), "hashicorp.nomad.plugins.drivers.proto.TaskDriverStatus.AttributesEntry")
-	proto.RegisterType((*TaskStats)(nil), "hashicorp.nomad.plugins.drivers.proto.TaskStats")
-	proto.RegisterMapType((map[string]*TaskResourceUsage)(nil), "hashicorp.nomad.plugins.drivers.proto.TaskStats.ResourceUsageByPidEntry")
-	proto.RegisterType((*TaskResourceUsage)(nil), "hashicorp.nomad.plugins.drivers.proto.TaskResourceUsage")
-	proto.RegisterType((*CPUUsage)(nil), "hashicorp.nomad.plugins.drivers.proto.CPUUsage")
-	proto.RegisterType((*MemoryUsage)(nil), "hashicorp.nomad.plugins.drivers.proto.MemoryUsage")
-	proto.RegisterType((*DriverTaskEvent)(nil), "hashicorp.nomad.plugins.drivers.proto.DriverTaskEvent")
-	proto.RegisterMapType((map[string]string)(nil), "hashicorp.nomad.plugins.drivers.proto.DriverTaskEvent.AnnotationsEntry")
-	proto.RegisterEnum("hashicorp.nomad.plugins.drivers.proto.TaskState", TaskState_name, TaskState_value)
-	proto.RegisterEnum("hashicorp.nomad.plugins.drivers.proto.FingerprintResponse_HealthState", FingerprintResponse_HealthState_name, FingerprintResponse_HealthState_value)
-	proto.RegisterEnum("hashicorp.nomad.plugins.drivers.proto.StartTaskResponse_Result", StartTaskResponse_Result_name, StartTaskResponse_Result_value)
-	proto.RegisterEnum("hashicorp.nomad.plugins.drivers.proto.DriverCapabilities_FSIsolation", DriverCapabilities_FSIsolation_name, DriverCapabilities_FSIsolation_value)
-	proto.RegisterEnum("hashicorp.nomad.plugins.drivers.proto.CPUUsage_Fields", CPUUsage_Fields_name, CPUUsage_Fields_value)
-	proto.RegisterEnum("hashicorp.nomad.plugins.drivers.proto.MemoryUsage_Fields", MemoryUsage_Fields_name, MemoryUsage_Fields_value)
+type ExecTaskStreamingIOOperation struct {
+	// Data is the raw bytes written to or read from the stream
+	Data []byte `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	// Close indicates that the stream has been closed and no further data
+	// will be sent
+	Close                bool     `protobuf:"varint,2,opt,name=close,proto3" json:"close,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConn
-
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
-
-// DriverClient is the client API for Driver service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type DriverClient interface {
-	// TaskConfigSchema returns the schema for parsing the driver
-	// configuration of a task.
-	TaskConfigSchema(ctx context.Context, in *TaskConfigSchemaRequest, opts ...grpc.CallOption) (*TaskConfigSchemaResponse, error)
-	// Capabilities returns a set of features which the driver implements. Some
-	// RPCs are not possible to implement on some runtimes, this allows the
-	// driver to indicate if it doesn't support these RPCs and features.
-	Capabilities(ctx context.Context, in *CapabilitiesRequest, opts ...grpc.CallOption) (*CapabilitiesResponse, error)
-	// Fingerprint starts a stream which emits information about the driver
-	// including whether the driver healthy and able to function in the
-	// existing environment.
-	//
-	// The driver should immediately stream a FingerprintResponse when the RPC
-	// is initially called, then send any additional responses if there is a
-	// change in the driver's state.
-	Fingerprint(ctx context.Context, in *FingerprintRequest, opts ...grpc.CallOption) (Driver_FingerprintClient, error)
-	// RecoverTask is used when a task has been started but the driver may not
-	// know about it. Such is the case if the driver restarts or is upgraded.
-	RecoverTask(ctx context.Context, in *RecoverTaskRequest, opts ...grpc.CallOption) (*RecoverTaskResponse, error)
-	// StartTask starts and tracks the task on the implemented runtime
-	StartTask(ctx context.Context, in *StartTaskRequest, opts ...grpc.CallOption) (*StartTaskResponse, error)
-	// WaitTask blocks until the given task exits, returning the result of the
-	// task. It may be called after the task has exited, but before the task is
-	// destroyed.
-	WaitTask(ctx context.Context, in *WaitTaskRequest, opts ...grpc.CallOption) (*WaitTaskResponse, error)
-	// StopTask stops a given task by sending the desired signal to the process.
-	// If the task does not exit on its own within the given timeout, it will be
-	// forcefully killed.
-	StopTask(ctx context.Context, in *StopTaskRequest, opts ...grpc.CallOption) (*StopTaskResponse, error)
-	// DestroyTask removes the task from the driver's internal state and cleans
-	// up any additional resources created by the driver. It cannot be called
-	// on a running task, unless force is set to true.
-	DestroyTask(ctx context.Context, in *DestroyTaskRequest, opts ...grpc.CallOption) (*DestroyTaskResponse, error)
-	// InspectTask returns detailed information for the given task
-	InspectTask(ctx context.Context, in *InspectTaskRequest, opts ...grpc.CallOption) (*InspectTaskResponse, error)
-	// TaskStats collects and returns runtime metrics for the given task
-	TaskStats(ctx context.Context, in *TaskStatsRequest, opts ...grpc.CallOption) (*TaskStatsResponse, error)
-	// TaskEvents starts a streaming RPC where all task events emitted by the
-	// driver are streamed to the caller.
-	TaskEvents(ctx context.Context, in *TaskEventsRequest, opts ...grpc.CallOption) (Driver_TaskEventsClient, error)
-	// SignalTask sends a signal to the task
-	SignalTask(ctx context.Context, in *SignalTaskRequest, opts ...grpc.CallOption) (*SignalTaskResponse, error)
-	// ExecTask executes a command inside the tasks execution context
-	ExecTask(ctx context.Context, in *ExecTaskRequest, opts ...grpc.CallOption) (*ExecTaskResponse, error)
+func (m *ExecTaskStreamingIOOperation) Reset()         { *m = ExecTaskStreamingIOOperation{} }
+func (m *ExecTaskStreamingIOOperation) String() string { return proto.CompactTextString(m) }
+func (*ExecTaskStreamingIOOperation) ProtoMessage()    {}
+func (*ExecTaskStreamingIOOperation) Descriptor() ([]byte, []int) {
+	return fileDescriptor_driver_f8c1fd114dd6e6a4, []int{44}
 }
-
-type driverClient struct {
-	cc *grpc.ClientConn
+func (m *ExecTaskStreamingIOOperation) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExecTaskStreamingIOOperation.Unmarshal(m, b)
 }
-
-func NewDriverClient(cc *grpc.ClientConn) DriverClient {
-	return &driverClient{cc}
+func (m *ExecTaskStreamingIOOperation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExecTaskStreamingIOOperation.Marshal(b, m, deterministic)
 }
-
-func (c *driverClient) TaskConfigSchema(ctx context.Context, in *TaskConfigSchemaRequest, opts ...grpc.CallOption) (*TaskConfigSchemaResponse, error) {
-	out := new(TaskConfigSchemaResponse)
-	err := c.cc.Invoke(ctx, "/hashicorp.nomad.plugins.drivers.proto.Driver/TaskConfigSchema", in, out, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
+func (dst *ExecTaskStreamingIOOperation) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExecTaskStreamingIOOperation.Merge(dst, src)
+}
+func (m *ExecTaskStreamingIOOperation) XXX_Size() int {
+	return xxx_messageInfo_ExecTaskStreamingIOOperation.Size(m)
+}
+func (m *ExecTaskStreamingIOOperation) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExecTaskStreamingIOOperation.DiscardUnknown(m)
 }
 
-func (c *driverClient) Capabilities(ctx context.Context, in *CapabilitiesRequest, opts ...grpc.CallOption) (*CapabilitiesResponse, error) {
-	out := new(CapabilitiesResponse)
-	err := c.cc.Invoke(ctx, "/hashicorp.nomad.plugins.drivers.proto.Driver/Capabilities", in, out, opts...)
-	if err != nil {
-		return nil, err
+var xxx_messageInfo_ExecTaskStreamingIOOperation proto.InternalMessageInfo
+
+func (m *ExecTaskStreamingIOOperation) GetData() []byte {
+	if m != nil {
+		return m.Data
 	}
-	return out, nil
+	return nil
 }
 
-func (c *driverClient) Fingerprint(ctx context.Context, in *FingerprintRequest, opts ...grpc.CallOption) (Driver_FingerprintClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_Driver_serviceDesc.Streams[0], "/hashicorp.nomad.plugins.drivers.proto.Driver/Fingerprint", opts...)
-	if err != nil {
-		return nil, err
-	}
-	x := &driverFingerprintClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
-	}
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
+func (m *ExecTaskStreamingIOOperation) GetClose() bool {
+	if m != nil {
+		return m.Close
 	}
-	return x, nil
+	return false
 }
 
-type Driver_FingerprintClient interface {
-	Recv() (*FingerprintResponse, error)
-	grpc.ClientStream
+type ExecTaskStreamingRequest struct {
+	// Setup is sent with the first message only and describes the command
+	Setup *ExecTaskStreamingRequest_Setup `protobuf:"bytes,1,opt,name=setup,proto3" json:"setup,omitempty"`
+	// TtySize is sent when the terminal of the caller has been resized
+	TtySize *ExecTaskStreamingRequest_TerminalSize `protobuf:"bytes,2,opt,name=tty_size,json=ttySize,proto3" json:"tty_size,omitempty"`
+	// Stdin carries data for the standard input of the command
+	Stdin                *ExecTaskStreamingIOOperation `protobuf:"bytes,3,opt,name=stdin,proto3" json:"stdin,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                      `json:"-"`
+	XXX_unrecognized     []byte                        `json:"-"`
+	XXX_sizecache        int32                         `json:"-"`
 }
 
-type driverFingerprintClient struct {
-	grpc.ClientStream
+func (m *ExecTaskStreamingRequest) Reset()         { *m = ExecTaskStreamingRequest{} }
+func (m *ExecTaskStreamingRequest) String() string { return proto.CompactTextString(m) }
+func (*ExecTaskStreamingRequest) ProtoMessage()    {}
+func (*ExecTaskStreamingRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_driver_f8c1fd114dd6e6a4, []int{45}
+}
+func (m *ExecTaskStreamingRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExecTaskStreamingRequest.Unmarshal(m, b)
+}
+func (m *ExecTaskStreamingRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExecTaskStreamingRequest.Marshal(b, m, deterministic)
+}
+func (dst *ExecTaskStreamingRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExecTaskStreamingRequest.Merge(dst, src)
+}
+func (m *ExecTaskStreamingRequest) XXX_Size() int {
+	return xxx_messageInfo_ExecTaskStreamingRequest.Size(m)
+}
+func (m *ExecTaskStreamingRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExecTaskStreamingRequest.DiscardUnknown(m)
 }
 
-func (x *driverFingerprintClient) Recv() (*FingerprintResponse, error) {
-	m := new(FingerprintResponse)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
-		return nil, err
+var xxx_messageInfo_ExecTaskStreamingRequest proto.InternalMessageInfo
+
+func (m *ExecTaskStreamingRequest) GetSetup() *ExecTaskStreamingRequest_Setup {
+	if m != nil {
+		return m.Setup
 	}
-	return m, nil
+	return nil
 }
 
-func (c *driverClient) RecoverTask(ctx context.Context, in *RecoverTaskRequest, opts ...grpc.CallOption) (*RecoverTaskResponse, error) {
+func (m *ExecTaskStreamingRequest) GetTtySize() *ExecTaskStreamingRequest_TerminalSize {
+	if m != nil {
+		return m.TtySize
+	}
+	return nil
+}
+
+func (m *ExecTaskStreamingRequest) GetStdin() *ExecTaskStreamingIOOperation {
+	if m != nil {
+		return m.Stdin
+	}
+	return nil
+}
+
+type ExecTaskStreamingRequest_Setup struct {
+	// TaskId is the ID of the target task
+	TaskId string `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	// Command is the command to execute
+	Command []string `protobuf:"bytes,2,rep,name=command,proto3" json:"command,omitempty"`
+	// Tty indicates whether a pseudo-terminal should be allocated
+	Tty                  bool     `protobuf:"varint,3,opt,name=tty,proto3" json:"tty,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ExecTaskStreamingRequest_Setup) Reset()         { *m = ExecTaskStreamingRequest_Setup{} }
+func (m *ExecTaskStreamingRequest_Setup) String() string { return proto.CompactTextString(m) }
+func (*ExecTaskStreamingRequest_Setup) ProtoMessage()    {}
+func (*ExecTaskStreamingRequest_Setup) Descriptor() ([]byte, []int) {
+	return fileDescriptor_driver_f8c1fd114dd6e6a4, []int{45, 0}
+}
+func (m *ExecTaskStreamingRequest_Setup) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExecTaskStreamingRequest_Setup.Unmarshal(m, b)
+}
+func (m *ExecTaskStreamingRequest_Setup) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExecTaskStreamingRequest_Setup.Marshal(b, m, deterministic)
+}
+func (dst *ExecTaskStreamingRequest_Setup) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExecTaskStreamingRequest_Setup.Merge(dst, src)
+}
+func (m *ExecTaskStreamingRequest_Setup) XXX_Size() int {
+	return xxx_messageInfo_ExecTaskStreamingRequest_Setup.Size(m)
+}
+func (m *ExecTaskStreamingRequest_Setup) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExecTaskStreamingRequest_Setup.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExecTaskStreamingRequest_Setup proto.InternalMessageInfo
+
+func (m *ExecTaskStreamingRequest_Setup) GetTaskId() string {
+	if m != nil {
+		return m.TaskId
+	}
+	return ""
+}
+
+func (m *ExecTaskStreamingRequest_Setup) GetCommand() []string {
+	if m != nil {
+		return m.Command
+	}
+	return nil
+}
+
+func (m *ExecTaskStreamingRequest_Setup) GetTty() bool {
+	if m != nil {
+		return m.Tty
+	}
+	return false
+}
+
+type ExecTaskStreamingRequest_TerminalSize struct {
+	Height               int32    `protobuf:"varint,1,opt,name=height,proto3" json:"height,omitempty"`
+	Width                int32    `protobuf:"varint,2,opt,name=width,proto3" json:"width,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ExecTaskStreamingRequest_TerminalSize) Reset()         { *m = ExecTaskStreamingRequest_TerminalSize{} }
+func (m *ExecTaskStreamingRequest_TerminalSize) String() string { return proto.CompactTextString(m) }
+func (*ExecTaskStreamingRequest_TerminalSize) ProtoMessage()    {}
+func (*ExecTaskStreamingRequest_TerminalSize) Descriptor() ([]byte, []int) {
+	return fileDescriptor_driver_f8c1fd114dd6e6a4, []int{45, 1}
+}
+func (m *ExecTaskStreamingRequest_TerminalSize) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExecTaskStreamingRequest_TerminalSize.Unmarshal(m, b)
+}
+func (m *ExecTaskStreamingRequest_TerminalSize) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExecTaskStreamingRequest_TerminalSize.Marshal(b, m, deterministic)
+}
+func (dst *ExecTaskStreamingRequest_TerminalSize) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExecTaskStreamingRequest_TerminalSize.Merge(dst, src)
+}
+func (m *ExecTaskStreamingRequest_TerminalSize) XXX_Size() int {
+	return xxx_messageInfo_ExecTaskStreamingRequest_TerminalSize.Size(m)
+}
+func (m *ExecTaskStreamingRequest_TerminalSize) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExecTaskStreamingRequest_TerminalSize.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExecTaskStreamingRequest_TerminalSize proto.InternalMessageInfo
+
+func (m *ExecTaskStreamingRequest_TerminalSize) GetHeight() int32 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+func (m *ExecTaskStreamingRequest_TerminalSize) GetWidth() int32 {
+	if m != nil {
+		return m.Width
+	}
+	return 0
+}
+
+type ExecTaskStreamingResponse struct {
+	// Stdout carries data written by the command to its standard output
+	Stdout *ExecTaskStreamingIOOperation `protobuf:"bytes,1,opt,name=stdout,proto3" json:"stdout,omitempty"`
+	// Stderr carries data written by the command to its standard error
+	Stderr *ExecTaskStreamingIOOperation `protobuf:"bytes,2,opt,name=stderr,proto3" json:"stderr,omitempty"`
+	// Exited is set with the final message, once the command has exited
+	Exited bool `protobuf:"varint,3,opt,name=exited,proto3" json:"exited,omitempty"`
+	// Result is the exit information of the command, set if Exited is true
+	Result               *ExitResult `protobuf:"bytes,4,opt,name=result,proto3" json:"result,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *ExecTaskStreamingResponse) Reset()         { *m = ExecTaskStreamingResponse{} }
+func (m *ExecTaskStreamingResponse) String() string { return proto.CompactTextString(m) }
+func (*ExecTaskStreamingResponse) ProtoMessage()    {}
+func (*ExecTaskStreamingResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_driver_f8c1fd114dd6e6a4, []int{46}
+}
+func (m *ExecTaskStreamingResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExecTaskStreamingResponse.Unmarshal(m, b)
+}
+func (m *ExecTaskStreamingResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExecTaskStreamingResponse.Marshal(b, m, deterministic)
+}
+func (dst *ExecTaskStreamingResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExecTaskStreamingResponse.Merge(dst, src)
+}
+func (m *ExecTaskStreamingResponse) XXX_Size() int {
+	return xxx_messageInfo_ExecTaskStreamingResponse.Size(m)
+}
+func (m *ExecTaskStreamingResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExecTaskStreamingResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExecTaskStreamingResponse proto.InternalMessageInfo
+
+func (m *ExecTaskStreamingResponse) GetStdout() *ExecTaskStreamingIOOperation {
+	if m != nil {
+		return m.Stdout
+	}
+	return nil
+}
+
+func (m *ExecTaskStreamingResponse) GetStderr() *ExecTaskStreamingIOOperation {
+	if m != nil {
+		return m.Stderr
+	}
+	return nil
+}
+
+func (m *ExecTaskStreamingResponse) GetExited() bool {
+	if m != nil {
+		return m.Exited
+	}
+	return false
+}
+
+func (m *ExecTaskStreamingResponse) GetResult() *ExitResult {
+	if m != nil {
+		return m.Result
+	}
+	return nil
+}
+
+type CheckpointTaskRequest struct {
+	// TaskId is the ID of the target task
+	TaskId               string   `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CheckpointTaskRequest) Reset()         { *m = CheckpointTaskRequest{} }
+func (m *CheckpointTaskRequest) String() string { return proto.CompactTextString(m) }
+func (*CheckpointTaskRequest) ProtoMessage()    {}
+func (*CheckpointTaskRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_driver_f8c1fd114dd6e6a4, []int{47}
+}
+func (m *CheckpointTaskRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CheckpointTaskRequest.Unmarshal(m, b)
+}
+func (m *CheckpointTaskRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CheckpointTaskRequest.Marshal(b, m, deterministic)
+}
+func (dst *CheckpointTaskRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CheckpointTaskRequest.Merge(dst, src)
+}
+func (m *CheckpointTaskRequest) XXX_Size() int {
+	return xxx_messageInfo_CheckpointTaskRequest.Size(m)
+}
+func (m *CheckpointTaskRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CheckpointTaskRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CheckpointTaskRequest proto.InternalMessageInfo
+
+func (m *CheckpointTaskRequest) GetTaskId() string {
+	if m != nil {
+		return m.TaskId
+	}
+	return ""
+}
+
+type CheckpointTaskResponse struct {
+	// Checkpointed is true if a checkpoint was taken. Drivers only checkpoint
+	// tasks that have enabled it in their configuration.
+	Checkpointed         bool     `protobuf:"varint,1,opt,name=checkpointed,proto3" json:"checkpointed,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CheckpointTaskResponse) Reset()         { *m = CheckpointTaskResponse{} }
+func (m *CheckpointTaskResponse) String() string { return proto.CompactTextString(m) }
+func (*CheckpointTaskResponse) ProtoMessage()    {}
+func (*CheckpointTaskResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_driver_f8c1fd114dd6e6a4, []int{48}
+}
+func (m *CheckpointTaskResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CheckpointTaskResponse.Unmarshal(m, b)
+}
+func (m *CheckpointTaskResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CheckpointTaskResponse.Marshal(b, m, deterministic)
+}
+func (dst *CheckpointTaskResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CheckpointTaskResponse.Merge(dst, src)
+}
+func (m *CheckpointTaskResponse) XXX_Size() int {
+	return xxx_messageInfo_CheckpointTaskResponse.Size(m)
+}
+func (m *CheckpointTaskResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CheckpointTaskResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CheckpointTaskResponse proto.InternalMessageInfo
+
+func (m *CheckpointTaskResponse) GetCheckpointed() bool {
+	if m != nil {
+		return m.Checkpointed
+	}
+	return false
+}
+
+type IOLimit struct {
+	// Device is the path of the block device on the host
+	Device string `protobuf:"bytes,1,opt,name=device,proto3" json:"device,omitempty"`
+	// ReadBps and WriteBps limit the bandwidth in bytes per second
+	ReadBps  int64 `protobuf:"varint,2,opt,name=read_bps,json=readBps,proto3" json:"read_bps,omitempty"`
+	WriteBps int64 `protobuf:"varint,3,opt,name=write_bps,json=writeBps,proto3" json:"write_bps,omitempty"`
+	// ReadIops and WriteIops limit the number of operations per second
+	ReadIops             int64    `protobuf:"varint,4,opt,name=read_iops,json=readIops,proto3" json:"read_iops,omitempty"`
+	WriteIops            int64    `protobuf:"varint,5,opt,name=write_iops,json=writeIops,proto3" json:"write_iops,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *IOLimit) Reset()         { *m = IOLimit{} }
+func (m *IOLimit) String() string { return proto.CompactTextString(m) }
+func (*IOLimit) ProtoMessage()    {}
+func (*IOLimit) Descriptor() ([]byte, []int) {
+	return fileDescriptor_driver_f8c1fd114dd6e6a4, []int{49}
+}
+func (m *IOLimit) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_IOLimit.Unmarshal(m, b)
+}
+func (m *IOLimit) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_IOLimit.Marshal(b, m, deterministic)
+}
+func (dst *IOLimit) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_IOLimit.Merge(dst, src)
+}
+func (m *IOLimit) XXX_Size() int {
+	return xxx_messageInfo_IOLimit.Size(m)
+}
+func (m *IOLimit) XXX_DiscardUnknown() {
+	xxx_messageInfo_IOLimit.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_IOLimit proto.InternalMessageInfo
+
+func (m *IOLimit) GetDevice() string {
+	if m != nil {
+		return m.Device
+	}
+	return ""
+}
+
+func (m *IOLimit) GetReadBps() int64 {
+	if m != nil {
+		return m.ReadBps
+	}
+	return 0
+}
+
+func (m *IOLimit) GetWriteBps() int64 {
+	if m != nil {
+		return m.WriteBps
+	}
+	return 0
+}
+
+func (m *IOLimit) GetReadIops() int64 {
+	if m != nil {
+		return m.ReadIops
+	}
+	return 0
+}
+
+func (m *IOLimit) GetWriteIops() int64 {
+	if m != nil {
+		return m.WriteIops
+	}
+	return 0
+}
+
+type IOUsage struct {
+	ReadBytes  uint64 `protobuf:"varint,1,opt,name=read_bytes,json=readBytes,proto3" json:"read_bytes,omitempty"`
+	WriteBytes uint64 `protobuf:"varint,2,opt,name=write_bytes,json=writeBytes,proto3" json:"write_bytes,omitempty"`
+	ReadOps    uint64 `protobuf:"varint,3,opt,name=read_ops,json=readOps,proto3" json:"read_ops,omitempty"`
+	WriteOps   uint64 `protobuf:"varint,4,opt,name=write_ops,json=writeOps,proto3" json:"write_ops,omitempty"`
+	// MeasuredFields indicates which fields were actually sampled
+	MeasuredFields       []IOUsage_Fields `protobuf:"varint,5,rep,packed,name=measured_fields,json=measuredFields,proto3,enum=hashicorp.nomad.plugins.drivers.proto.IOUsage_Fields" json:"measured_fields,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *IOUsage) Reset()         { *m = IOUsage{} }
+func (m *IOUsage) String() string { return proto.CompactTextString(m) }
+func (*IOUsage) ProtoMessage()    {}
+func (*IOUsage) Descriptor() ([]byte, []int) {
+	return fileDescriptor_driver_f8c1fd114dd6e6a4, []int{50}
+}
+func (m *IOUsage) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_IOUsage.Unmarshal(m, b)
+}
+func (m *IOUsage) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_IOUsage.Marshal(b, m, deterministic)
+}
+func (dst *IOUsage) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_IOUsage.Merge(dst, src)
+}
+func (m *IOUsage) XXX_Size() int {
+	return xxx_messageInfo_IOUsage.Size(m)
+}
+func (m *IOUsage) XXX_DiscardUnknown() {
+	xxx_messageInfo_IOUsage.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_IOUsage proto.InternalMessageInfo
+
+func (m *IOUsage) GetReadBytes() uint64 {
+	if m != nil {
+		return m.ReadBytes
+	}
+	return 0
+}
+
+func (m *IOUsage) GetWriteBytes() uint64 {
+	if m != nil {
+		return m.WriteBytes
+	}
+	return 0
+}
+
+func (m *IOUsage) GetReadOps() uint64 {
+	if m != nil {
+		return m.ReadOps
+	}
+	return 0
+}
+
+func (m *IOUsage) GetWriteOps() uint64 {
+	if m != nil {
+		return m.WriteOps
+	}
+	return 0
+}
+
+func (m *IOUsage) GetMeasuredFields() []IOUsage_Fields {
+	if m != nil {
+		return m.MeasuredFields
+	}
+	return nil
+}
+
+type NetworkIsolationSpec struct {
+	// Path is the path of the network namespace
+	Path string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	// Labels are set by the driver that created the network namespace to
+	// identify it
+	Labels               map[string]string `protobuf:"bytes,2,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *NetworkIsolationSpec) Reset()         { *m = NetworkIsolationSpec{} }
+func (m *NetworkIsolationSpec) String() string { return proto.CompactTextString(m) }
+func (*NetworkIsolationSpec) ProtoMessage()    {}
+func (*NetworkIsolationSpec) Descriptor() ([]byte, []int) {
+	return fileDescriptor_driver_f8c1fd114dd6e6a4, []int{51}
+}
+func (m *NetworkIsolationSpec) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NetworkIsolationSpec.Unmarshal(m, b)
+}
+func (m *NetworkIsolationSpec) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NetworkIsolationSpec.Marshal(b, m, deterministic)
+}
+func (dst *NetworkIsolationSpec) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NetworkIsolationSpec.Merge(dst, src)
+}
+func (m *NetworkIsolationSpec) XXX_Size() int {
+	return xxx_messageInfo_NetworkIsolationSpec.Size(m)
+}
+func (m *NetworkIsolationSpec) XXX_DiscardUnknown() {
+	xxx_messageInfo_NetworkIsolationSpec.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NetworkIsolationSpec proto.InternalMessageInfo
+
+func (m *NetworkIsolationSpec) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *NetworkIsolationSpec) GetLabels() map[string]string {
+	if m != nil {
+		return m.Labels
+	}
+	return nil
+}
+
+type CreateNetworkRequest struct {
+	// AllocId is the ID of the allocation the network is created for
+	AllocId              string   `protobuf:"bytes,1,opt,name=alloc_id,json=allocId,proto3" json:"alloc_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CreateNetworkRequest) Reset()         { *m = CreateNetworkRequest{} }
+func (m *CreateNetworkRequest) String() string { return proto.CompactTextString(m) }
+func (*CreateNetworkRequest) ProtoMessage()    {}
+func (*CreateNetworkRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_driver_f8c1fd114dd6e6a4, []int{52}
+}
+func (m *CreateNetworkRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateNetworkRequest.Unmarshal(m, b)
+}
+func (m *CreateNetworkRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateNetworkRequest.Marshal(b, m, deterministic)
+}
+func (dst *CreateNetworkRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateNetworkRequest.Merge(dst, src)
+}
+func (m *CreateNetworkRequest) XXX_Size() int {
+	return xxx_messageInfo_CreateNetworkRequest.Size(m)
+}
+func (m *CreateNetworkRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateNetworkRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateNetworkRequest proto.InternalMessageInfo
+
+func (m *CreateNetworkRequest) GetAllocId() string {
+	if m != nil {
+		return m.AllocId
+	}
+	return ""
+}
+
+type CreateNetworkResponse struct {
+	// IsolationSpec is the network namespace of the allocation
+	IsolationSpec *NetworkIsolationSpec `protobuf:"bytes,1,opt,name=isolation_spec,json=isolationSpec,proto3" json:"isolation_spec,omitempty"`
+	// Created is false if the network namespace already existed
+	Created              bool     `protobuf:"varint,2,opt,name=created,proto3" json:"created,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CreateNetworkResponse) Reset()         { *m = CreateNetworkResponse{} }
+func (m *CreateNetworkResponse) String() string { return proto.CompactTextString(m) }
+func (*CreateNetworkResponse) ProtoMessage()    {}
+func (*CreateNetworkResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_driver_f8c1fd114dd6e6a4, []int{53}
+}
+func (m *CreateNetworkResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateNetworkResponse.Unmarshal(m, b)
+}
+func (m *CreateNetworkResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateNetworkResponse.Marshal(b, m, deterministic)
+}
+func (dst *CreateNetworkResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateNetworkResponse.Merge(dst, src)
+}
+func (m *CreateNetworkResponse) XXX_Size() int {
+	return xxx_messageInfo_CreateNetworkResponse.Size(m)
+}
+func (m *CreateNetworkResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateNetworkResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateNetworkResponse proto.InternalMessageInfo
+
+func (m *CreateNetworkResponse) GetIsolationSpec() *NetworkIsolationSpec {
+	if m != nil {
+		return m.IsolationSpec
+	}
+	return nil
+}
+
+func (m *CreateNetworkResponse) GetCreated() bool {
+	if m != nil {
+		return m.Created
+	}
+	return false
+}
+
+type DestroyNetworkRequest struct {
+	// AllocId is the ID of the allocation the network was created for
+	AllocId string `protobuf:"bytes,1,opt,name=alloc_id,json=allocId,proto3" json:"alloc_id,omitempty"`
+	// IsolationSpec is the network namespace returned by CreateNetwork
+	IsolationSpec        *NetworkIsolationSpec `protobuf:"bytes,2,opt,name=isolation_spec,json=isolationSpec,proto3" json:"isolation_spec,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *DestroyNetworkRequest) Reset()         { *m = DestroyNetworkRequest{} }
+func (m *DestroyNetworkRequest) String() string { return proto.CompactTextString(m) }
+func (*DestroyNetworkRequest) ProtoMessage()    {}
+func (*DestroyNetworkRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_driver_f8c1fd114dd6e6a4, []int{54}
+}
+func (m *DestroyNetworkRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DestroyNetworkRequest.Unmarshal(m, b)
+}
+func (m *DestroyNetworkRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DestroyNetworkRequest.Marshal(b, m, deterministic)
+}
+func (dst *DestroyNetworkRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DestroyNetworkRequest.Merge(dst, src)
+}
+func (m *DestroyNetworkRequest) XXX_Size() int {
+	return xxx_messageInfo_DestroyNetworkRequest.Size(m)
+}
+func (m *DestroyNetworkRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DestroyNetworkRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DestroyNetworkRequest proto.InternalMessageInfo
+
+func (m *DestroyNetworkRequest) GetAllocId() string {
+	if m != nil {
+		return m.AllocId
+	}
+	return ""
+}
+
+func (m *DestroyNetworkRequest) GetIsolationSpec() *NetworkIsolationSpec {
+	if m != nil {
+		return m.IsolationSpec
+	}
+	return nil
+}
+
+type DestroyNetworkResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DestroyNetworkResponse) Reset()         { *m = DestroyNetworkResponse{} }
+func (m *DestroyNetworkResponse) String() string { return proto.CompactTextString(m) }
+func (*DestroyNetworkResponse) ProtoMessage()    {}
+func (*DestroyNetworkResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_driver_f8c1fd114dd6e6a4, []int{55}
+}
+func (m *DestroyNetworkResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DestroyNetworkResponse.Unmarshal(m, b)
+}
+func (m *DestroyNetworkResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DestroyNetworkResponse.Marshal(b, m, deterministic)
+}
+func (dst *DestroyNetworkResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DestroyNetworkResponse.Merge(dst, src)
+}
+func (m *DestroyNetworkResponse) XXX_Size() int {
+	return xxx_messageInfo_DestroyNetworkResponse.Size(m)
+}
+func (m *DestroyNetworkResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DestroyNetworkResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DestroyNetworkResponse proto.InternalMessageInfo
+
+func init() {
+	proto.RegisterType((*TaskConfigSchemaRequest)(nil), "hashicorp.nomad.plugins.drivers.proto.TaskConfigSchemaRequest")
+	proto.RegisterType((*TaskConfigSchemaResponse)(nil), "hashicorp.nomad.plugins.drivers.proto.TaskConfigSchemaResponse")
+	proto.RegisterType((*CapabilitiesRequest)(nil), "hashicorp.nomad.plugins.drivers.proto.CapabilitiesRequest")
+	proto.RegisterType((*CapabilitiesResponse)(nil), "hashicorp.nomad.plugins.drivers.proto.CapabilitiesResponse")
+	proto.RegisterType((*FingerprintRequest)(nil), "hashicorp.nomad.plugins.drivers.proto.FingerprintRequest")
+	proto.RegisterType((*FingerprintResponse)(nil), "hashicorp.nomad.plugins.drivers.proto.FingerprintResponse")
+	proto.RegisterMapType((map[string]*proto1.Attribute)(nil), "hashicorp.nomad.plugins.drivers.proto.FingerprintResponse.AttributesEntry")
+	proto.RegisterType((*RecoverTaskRequest)(nil), "hashicorp.nomad.plugins.drivers.proto.RecoverTaskRequest")
+	proto.RegisterType((*RecoverTaskResponse)(nil), "hashicorp.nomad.plugins.drivers.proto.RecoverTaskResponse")
+	proto.RegisterType((*StartTaskRequest)(nil), "hashicorp.nomad.plugins.drivers.proto.StartTaskRequest")
+	proto.RegisterType((*StartTaskResponse)(nil), "hashicorp.nomad.plugins.drivers.proto.StartTaskResponse")
+	proto.RegisterType((*WaitTaskRequest)(nil), "hashicorp.nomad.plugins.drivers.proto.WaitTaskRequest")
+	proto.RegisterType((*WaitTaskResponse)(nil), "hashicorp.nomad.plugins.drivers.proto.WaitTaskResponse")
+	proto.RegisterType((*StopTaskRequest)(nil), "hashicorp.nomad.plugins.drivers.proto.StopTaskRequest")
+	proto.RegisterType((*StopTaskResponse)(nil), "hashicorp.nomad.plugins.drivers.proto.StopTaskResponse")
+	proto.RegisterType((*DestroyTaskRequest)(nil), "hashicorp.nomad.plugins.drivers.proto.DestroyTaskRequest")
+	proto.RegisterType((*DestroyTaskResponse)(nil), "hashicorp.nomad.plugins.drivers.proto.DestroyTaskResponse")
+	proto.RegisterType((*InspectTaskRequest)(nil), "hashicorp.nomad.plugins.drivers.proto.InspectTaskRequest")
+	proto.RegisterType((*InspectTaskResponse)(nil), "hashicorp.nomad.plugins.drivers.proto.InspectTaskResponse")
+	proto.RegisterType((*TaskStatsRequest)(nil), "hashicorp.nomad.plugins.drivers.proto.TaskStatsRequest")
+	proto.RegisterType((*TaskStatsResponse)(nil), "hashicorp.nomad.plugins.drivers.proto.TaskStatsResponse")
+	proto.RegisterType((*TaskEventsRequest)(nil), "hashicorp.nomad.plugins.drivers.proto.TaskEventsRequest")
+	proto.RegisterType((*SignalTaskRequest)(nil), "hashicorp.nomad.plugins.drivers.proto.SignalTaskRequest")
+	proto.RegisterType((*SignalTaskResponse)(nil), "hashicorp.nomad.plugins.drivers.proto.SignalTaskResponse")
+	proto.RegisterType((*ExecTaskRequest)(nil), "hashicorp.nomad.plugins.drivers.proto.ExecTaskRequest")
+	proto.RegisterType((*ExecTaskResponse)(nil), "hashicorp.nomad.plugins.drivers.proto.ExecTaskResponse")
+	proto.RegisterType((*DriverCapabilities)(nil), "hashicorp.nomad.plugins.drivers.proto.DriverCapabilities")
+	proto.RegisterType((*TaskConfig)(nil), "hashicorp.nomad.plugins.drivers.proto.TaskConfig")
+	proto.RegisterMapType((map[string]string)(nil), "hashicorp.nomad.plugins.drivers.proto.TaskConfig.EnvEntry")
+	proto.RegisterType((*Resources)(nil), "hashicorp.nomad.plugins.drivers.proto.Resources")
+	proto.RegisterType((*RawResources)(nil), "hashicorp.nomad.plugins.drivers.proto.RawResources")
+	proto.RegisterType((*NetworkResource)(nil), "hashicorp.nomad.plugins.drivers.proto.NetworkResource")
+	proto.RegisterType((*NetworkPort)(nil), "hashicorp.nomad.plugins.drivers.proto.NetworkPort")
+	proto.RegisterType((*LinuxResources)(nil), "hashicorp.nomad.plugins.drivers.proto.LinuxResources")
+	proto.RegisterType((*Mount)(nil), "hashicorp.nomad.plugins.drivers.proto.Mount")
+	proto.RegisterType((*Device)(nil), "hashicorp.nomad.plugins.drivers.proto.Device")
+	proto.RegisterType((*TaskHandle)(nil), "hashicorp.nomad.plugins.drivers.proto.TaskHandle")
+	proto.RegisterType((*NetworkOverride)(nil), "hashicorp.nomad.plugins.drivers.proto.NetworkOverride")
+	proto.RegisterMapType((map[string]int32)(nil), "hashicorp.nomad.plugins.drivers.proto.NetworkOverride.PortMapEntry")
+	proto.RegisterType((*ExitResult)(nil), "hashicorp.nomad.plugins.drivers.proto.ExitResult")
+	proto.RegisterType((*TaskStatus)(nil), "hashicorp.nomad.plugins.drivers.proto.TaskStatus")
+	proto.RegisterType((*TaskDriverStatus)(nil), "hashicorp.nomad.plugins.drivers.proto.TaskDriverStatus")
+	proto.RegisterMapType((map[string]string)(nil), "hashicorp.nomad.plugins.drivers.proto.TaskDriverStatus.AttributesEntry")
+	proto.RegisterType((*TaskStats)(nil), "hashicorp.nomad.plugins.drivers.proto.TaskStats")
+	proto.RegisterMapType((map[string]*TaskResourceUsage)(nil), "hashicorp.nomad.plugins.drivers.proto.TaskStats.ResourceUsageByPidEntry")
+	proto.RegisterType((*TaskResourceUsage)(nil), "hashicorp.nomad.plugins.drivers.proto.TaskResourceUsage")
+	proto.RegisterType((*CPUUsage)(nil), "hashicorp.nomad.plugins.drivers.proto.CPUUsage")
+	proto.RegisterType((*MemoryUsage)(nil), "hashicorp.nomad.plugins.drivers.proto.MemoryUsage")
+	proto.RegisterType((*DriverTaskEvent)(nil), "hashicorp.nomad.plugins.drivers.proto.DriverTaskEvent")
+	proto.RegisterMapType((map[string]string)(nil), "hashicorp.nomad.plugins.drivers.proto.DriverTaskEvent.AnnotationsEntry")
+	proto.RegisterType((*ExecTaskStreamingIOOperation)(nil), "hashicorp.nomad.plugins.drivers.proto.ExecTaskStreamingIOOperation")
+	proto.RegisterType((*ExecTaskStreamingRequest)(nil), "hashicorp.nomad.plugins.drivers.proto.ExecTaskStreamingRequest")
+	proto.RegisterType((*ExecTaskStreamingRequest_Setup)(nil), "hashicorp.nomad.plugins.drivers.proto.ExecTaskStreamingRequest.Setup")
+	proto.RegisterType((*ExecTaskStreamingRequest_TerminalSize)(nil), "hashicorp.nomad.plugins.drivers.proto.ExecTaskStreamingRequest.TerminalSize")
+	proto.RegisterType((*ExecTaskStreamingResponse)(nil), "hashicorp.nomad.plugins.drivers.proto.ExecTaskStreamingResponse")
+	proto.RegisterType((*CheckpointTaskRequest)(nil), "hashicorp.nomad.plugins.drivers.proto.CheckpointTaskRequest")
+	proto.RegisterType((*CheckpointTaskResponse)(nil), "hashicorp.nomad.plugins.drivers.proto.CheckpointTaskResponse")
+	proto.RegisterType((*IOLimit)(nil), "hashicorp.nomad.plugins.drivers.proto.IOLimit")
+	proto.RegisterType((*IOUsage)(nil), "hashicorp.nomad.plugins.drivers.proto.IOUsage")
+	proto.RegisterType((*NetworkIsolationSpec)(nil), "hashicorp.nomad.plugins.drivers.proto.NetworkIsolationSpec")
+	proto.RegisterMapType((map[string]string)(nil), "hashicorp.nomad.plugins.drivers.proto.NetworkIsolationSpec.LabelsEntry")
+	proto.RegisterType((*CreateNetworkRequest)(nil), "hashicorp.nomad.plugins.drivers.proto.CreateNetworkRequest")
+	proto.RegisterType((*CreateNetworkResponse)(nil), "hashicorp.nomad.plugins.drivers.proto.CreateNetworkResponse")
+	proto.RegisterType((*DestroyNetworkRequest)(nil), "hashicorp.nomad.plugins.drivers.proto.DestroyNetworkRequest")
+	proto.RegisterType((*DestroyNetworkResponse)(nil), "hashicorp.nomad.plugins.drivers.proto.DestroyNetworkResponse")
+	proto.RegisterEnum("hashicorp.nomad.plugins.drivers.proto.TaskState", TaskState_name, TaskState_value)
+	proto.RegisterEnum("hashicorp.nomad.plugins.drivers.proto.FingerprintResponse_HealthState", FingerprintResponse_HealthState_name, FingerprintResponse_HealthState_value)
+	proto.RegisterEnum("hashicorp.nomad.plugins.drivers.proto.StartTaskResponse_Result", StartTaskResponse_Result_name, StartTaskResponse_Result_value)
+	proto.RegisterEnum("hashicorp.nomad.plugins.drivers.proto.DriverCapabilities_FSIsolation", DriverCapabilities_FSIsolation_name, DriverCapabilities_FSIsolation_value)
+	proto.RegisterEnum("hashicorp.nomad.plugins.drivers.proto.CPUUsage_Fields", CPUUsage_Fields_name, CPUUsage_Fields_value)
+	proto.RegisterEnum("hashicorp.nomad.plugins.drivers.proto.MemoryUsage_Fields", MemoryUsage_Fields_name, MemoryUsage_Fields_value)
+	proto.RegisterEnum("hashicorp.nomad.plugins.drivers.proto.IOUsage_Fields", IOUsage_Fields_name, IOUsage_Fields_value)
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// DriverClient is the client API for Driver service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type DriverClient interface {
+	// TaskConfigSchema returns the schema for parsing the driver
+	// configuration of a task.
+	TaskConfigSchema(ctx context.Context, in *TaskConfigSchemaRequest, opts ...grpc.CallOption) (*TaskConfigSchemaResponse, error)
+	// Capabilities returns a set of features which the driver implements. Some
+	// RPCs are not possible to implement on some runtimes, this allows the
+	// driver to indicate if it doesn't support these RPCs and features.
+	Capabilities(ctx context.Context, in *CapabilitiesRequest, opts ...grpc.CallOption) (*CapabilitiesResponse, error)
+	// Fingerprint starts a stream which emits information about the driver
+	// including whether the driver healthy and able to function in the
+	// existing environment.
+	//
+	// The driver should immediately stream a FingerprintResponse when the RPC
+	// is initially called, then send any additional responses if there is a
+	// change in the driver's state.
+	Fingerprint(ctx context.Context, in *FingerprintRequest, opts ...grpc.CallOption) (Driver_FingerprintClient, error)
+	// RecoverTask is used when a task has been started but the driver may not
+	// know about it. Such is the case if the driver restarts or is upgraded.
+	RecoverTask(ctx context.Context, in *RecoverTaskRequest, opts ...grpc.CallOption) (*RecoverTaskResponse, error)
+	// StartTask starts and tracks the task on the implemented runtime
+	StartTask(ctx context.Context, in *StartTaskRequest, opts ...grpc.CallOption) (*StartTaskResponse, error)
+	// WaitTask blocks until the given task exits, returning the result of the
+	// task. It may be called after the task has exited, but before the task is
+	// destroyed.
+	WaitTask(ctx context.Context, in *WaitTaskRequest, opts ...grpc.CallOption) (*WaitTaskResponse, error)
+	// StopTask stops a given task by sending the desired signal to the process.
+	// If the task does not exit on its own within the given timeout, it will be
+	// forcefully killed.
+	StopTask(ctx context.Context, in *StopTaskRequest, opts ...grpc.CallOption) (*StopTaskResponse, error)
+	// DestroyTask removes the task from the driver's internal state and cleans
+	// up any additional resources created by the driver. It cannot be called
+	// on a running task, unless force is set to true.
+	DestroyTask(ctx context.Context, in *DestroyTaskRequest, opts ...grpc.CallOption) (*DestroyTaskResponse, error)
+	// InspectTask returns detailed information for the given task
+	InspectTask(ctx context.Context, in *InspectTaskRequest, opts ...grpc.CallOption) (*InspectTaskResponse, error)
+	// TaskStats collects and returns runtime metrics for the given task
+	TaskStats(ctx context.Context, in *TaskStatsRequest, opts ...grpc.CallOption) (*TaskStatsResponse, error)
+	// TaskEvents starts a streaming RPC where all task events emitted by the
+	// driver are streamed to the caller.
+	TaskEvents(ctx context.Context, in *TaskEventsRequest, opts ...grpc.CallOption) (Driver_TaskEventsClient, error)
+	// SignalTask sends a signal to the task
+	SignalTask(ctx context.Context, in *SignalTaskRequest, opts ...grpc.CallOption) (*SignalTaskResponse, error)
+	// ExecTask executes a command inside the tasks execution context
+	ExecTask(ctx context.Context, in *ExecTaskRequest, opts ...grpc.CallOption) (*ExecTaskResponse, error)
+	// ExecTaskStreaming executes a command inside the tasks execution context
+	// and streams back results. The first request message must contain the
+	// Setup field, subsequent messages carry stdin data or terminal resizes.
+	ExecTaskStreaming(ctx context.Context, opts ...grpc.CallOption) (Driver_ExecTaskStreamingClient, error)
+	// CheckpointTask checkpoints the state of a running task into its
+	// checkpoint directory, stopping the task. Restoring happens when a task
+	// is started and a checkpoint is found.
+	CheckpointTask(ctx context.Context, in *CheckpointTaskRequest, opts ...grpc.CallOption) (*CheckpointTaskResponse, error)
+	// CreateNetwork creates the network namespace of an allocation for
+	// drivers that must create it themselves.
+	CreateNetwork(ctx context.Context, in *CreateNetworkRequest, opts ...grpc.CallOption) (*CreateNetworkResponse, error)
+	// DestroyNetwork destroys a network namespace created by CreateNetwork.
+	DestroyNetwork(ctx context.Context, in *DestroyNetworkRequest, opts ...grpc.CallOption) (*DestroyNetworkResponse, error)
+}
+
+type driverClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewDriverClient(cc *grpc.ClientConn) DriverClient {
+	return &driverClient{cc}
+}
+
+func (c *driverClient) TaskConfigSchema(ctx context.Context, in *TaskConfigSchemaRequest, opts ...grpc.CallOption) (*TaskConfigSchemaResponse, error) {
+	out := new(TaskConfigSchemaResponse)
+	err := c.cc.Invoke(ctx, "/hashicorp.nomad.plugins.drivers.proto.Driver/TaskConfigSchema", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *driverClient) Capabilities(ctx context.Context, in *CapabilitiesRequest, opts ...grpc.CallOption) (*CapabilitiesResponse, error) {
+	out := new(CapabilitiesResponse)
+	err := c.cc.Invoke(ctx, "/hashicorp.nomad.plugins.drivers.proto.Driver/Capabilities", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *driverClient) Fingerprint(ctx context.Context, in *FingerprintRequest, opts ...grpc.CallOption) (Driver_FingerprintClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Driver_serviceDesc.Streams[0], "/hashicorp.nomad.plugins.drivers.proto.Driver/Fingerprint", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &driverFingerprintClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Driver_FingerprintClient interface {
+	Recv() (*FingerprintResponse, error)
+	grpc.ClientStream
+}
+
+type driverFingerprintClient struct {
+	grpc.ClientStream
+}
+
+func (x *driverFingerprintClient) Recv() (*FingerprintResponse, error) {
+	m := new(FingerprintResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *driverClient) RecoverTask(ctx context.Context, in *RecoverTaskRequest, opts ...grpc.CallOption) (*RecoverTaskResponse, error) {
 	out := new(RecoverTaskResponse)
 	err := c.cc.Invoke(ctx, "/hashicorp.nomad.plugins.drivers.proto.Driver/RecoverTask", in, out, opts...)
 	if err != nil {
@@ -2890,6 +3769,64 @@ func (c *driverClient) ExecTask(ctx context.Context, in *ExecTaskRequest, opts .
 	return out, nil
 }
 
+func (c *driverClient) ExecTaskStreaming(ctx context.Context, opts ...grpc.CallOption) (Driver_ExecTaskStreamingClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Driver_serviceDesc.Streams[2], "/hashicorp.nomad.plugins.drivers.proto.Driver/ExecTaskStreaming", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &driverExecTaskStreamingClient{stream}
+	return x, nil
+}
+
+type Driver_ExecTaskStreamingClient interface {
+	Send(*ExecTaskStreamingRequest) error
+	Recv() (*ExecTaskStreamingResponse, error)
+	grpc.ClientStream
+}
+
+type driverExecTaskStreamingClient struct {
+	grpc.ClientStream
+}
+
+func (x *driverExecTaskStreamingClient) Send(m *ExecTaskStreamingRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *driverExecTaskStreamingClient) Recv() (*ExecTaskStreamingResponse, error) {
+	m := new(ExecTaskStreamingResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *driverClient) CheckpointTask(ctx context.Context, in *CheckpointTaskRequest, opts ...grpc.CallOption) (*CheckpointTaskResponse, error) {
+	out := new(CheckpointTaskResponse)
+	err := c.cc.Invoke(ctx, "/hashicorp.nomad.plugins.drivers.proto.Driver/CheckpointTask", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *driverClient) CreateNetwork(ctx context.Context, in *CreateNetworkRequest, opts ...grpc.CallOption) (*CreateNetworkResponse, error) {
+	out := new(CreateNetworkResponse)
+	err := c.cc.Invoke(ctx, "/hashicorp.nomad.plugins.drivers.proto.Driver/CreateNetwork", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *driverClient) DestroyNetwork(ctx context.Context, in *DestroyNetworkRequest, opts ...grpc.CallOption) (*DestroyNetworkResponse, error) {
+	out := new(DestroyNetworkResponse)
+	err := c.cc.Invoke(ctx, "/hashicorp.nomad.plugins.drivers.proto.Driver/DestroyNetwork", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // DriverServer is the server API for Driver service.
 type DriverServer interface {
 	// TaskConfigSchema returns the schema for parsing the driver
@@ -2935,6 +3872,19 @@ type DriverServer interface {
 	SignalTask(context.Context, *SignalTaskRequest) (*SignalTaskResponse, error)
 	// ExecTask executes a command inside the tasks execution context
 	ExecTask(context.Context, *ExecTaskRequest) (*ExecTaskResponse, error)
+	// ExecTaskStreaming executes a command inside the tasks execution context
+	// and streams back results. The first request message must contain the
+	// Setup field, subsequent messages carry stdin data or terminal resizes.
+	ExecTaskStreaming(Driver_ExecTaskStreamingServer) error
+	// CheckpointTask checkpoints the state of a running task into its
+	// checkpoint directory, stopping the task. Restoring happens when a task
+	// is started and a checkpoint is found.
+	CheckpointTask(context.Context, *CheckpointTaskRequest) (*CheckpointTaskResponse, error)
+	// CreateNetwork creates the network namespace of an allocation for
+	// drivers that must create it themselves.
+	CreateNetwork(context.Context, *CreateNetworkRequest) (*CreateNetworkResponse, error)
+	// DestroyNetwork destroys a network namespace created by CreateNetwork.
+	DestroyNetwork(context.Context, *DestroyNetworkRequest) (*DestroyNetworkResponse, error)
 }
 
 func RegisterDriverServer(s *grpc.Server, srv DriverServer) {
@@ -3181,6 +4131,86 @@ func _Driver_ExecTask_Handler(srv interface{}, ctx context.Context, dec func(int
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Driver_ExecTaskStreaming_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(DriverServer).ExecTaskStreaming(&driverExecTaskStreamingServer{stream})
+}
+
+type Driver_ExecTaskStreamingServer interface {
+	Send(*ExecTaskStreamingResponse) error
+	Recv() (*ExecTaskStreamingRequest, error)
+	grpc.ServerStream
+}
+
+type driverExecTaskStreamingServer struct {
+	grpc.ServerStream
+}
+
+func (x *driverExecTaskStreamingServer) Send(m *ExecTaskStreamingResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *driverExecTaskStreamingServer) Recv() (*ExecTaskStreamingRequest, error) {
+	m := new(ExecTaskStreamingRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _Driver_CheckpointTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckpointTaskRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DriverServer).CheckpointTask(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hashicorp.nomad.plugins.drivers.proto.Driver/CheckpointTask",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DriverServer).CheckpointTask(ctx, req.(*CheckpointTaskRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Driver_CreateNetwork_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateNetworkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DriverServer).CreateNetwork(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hashicorp.nomad.plugins.drivers.proto.Driver/CreateNetwork",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DriverServer).CreateNetwork(ctx, req.(*CreateNetworkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Driver_DestroyNetwork_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DestroyNetworkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DriverServer).DestroyNetwork(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hashicorp.nomad.plugins.drivers.proto.Driver/DestroyNetwork",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DriverServer).DestroyNetwork(ctx, req.(*DestroyNetworkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Driver_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "hashicorp.nomad.plugins.drivers.proto.Driver",
 	HandlerType: (*DriverServer)(nil),
@@ -3229,6 +4259,18 @@ var _Driver_serviceDesc = grpc.ServiceDesc{
 			MethodName: "ExecTask",
 			Handler:    _Driver_ExecTask_Handler,
 		},
+		{
+			MethodName: "CheckpointTask",
+			Handler:    _Driver_CheckpointTask_Handler,
+		},
+		{
+			MethodName: "CreateNetwork",
+			Handler:    _Driver_CreateNetwork_Handler,
+		},
+		{
+			MethodName: "DestroyNetwork",
+			Handler:    _Driver_DestroyNetwork_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -3241,6 +4283,12 @@ var _Driver_serviceDesc = grpc.ServiceDesc{
 			Handler:       _Driver_TaskEvents_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "ExecTaskStreaming",
+			Handler:       _Driver_ExecTaskStreaming_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
 	},
 	Metadata: "plugins/drivers/proto/driver.proto",
 }
@@ -3250,184 +4298,232 @@ func init() {
 }
 
 var fileDescriptor_driver_f8c1fd114dd6e6a4 = []byte{
-	// 2858 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x59, 0xcd, 0x6f, 0x23, 0xc7,
-	0xb1, 0x17, 0x49, 0x91, 0x22, 0x8b, 0x14, 0x35, 0xdb, 0xbb, 0xfb, 0x4c, 0xd3, 0x78, 0xcf, 0xeb,
-	0x01, 0xfc, 0xb0, 0xb0, 0xbd, 0x94, 0x2d, 0xe3, 0x79, 0x3f, 0x1e, 0xfc, 0x41, 0x53, 0xb3, 0x92,
-	0xbc, 0x12, 0xa5, 0x34, 0x29, 0xac, 0x37, 0x89, 0x3d, 0x19, 0xcd, 0xb4, 0xc8, 0x59, 0x71, 0x3e,
-	0xdc, 0x3d, 0xa3, 0x95, 0x10, 0x04, 0x09, 0x12, 0x20, 0x48, 0x0e, 0x01, 0x72, 0x09, 0x02, 0xe4,
-	0x98, 0x9c, 0x82, 0x5c, 0x73, 0x4a, 0xe0, 0x4b, 0x80, 0xfc, 0x0f, 0x39, 0xe6, 0x12, 0x20, 0xd7,
-	0xfc, 0x07, 0x41, 0x7f, 0xcc, 0x70, 0x28, 0x69, 0xbd, 0x43, 0x6e, 0x4e, 0x9c, 0xae, 0xee, 0xfa,
-	0x75, 0xb1, 0xaa, 0xba, 0xaa, 0xba, 0x0b, 0xf4, 0x70, 0x12, 0x8f, 0x5c, 0x9f, 0xad, 0x3b, 0xd4,
-	0x3d, 0x25, 0x94, 0xad, 0x87, 0x34, 0x88, 0x02, 0x35, 0xea, 0x88, 0x01, 0x7a, 0x73, 0x6c, 0xb1,
-	0xb1, 0x6b, 0x07, 0x34, 0xec, 0xf8, 0x81, 0x67, 0x39, 0x1d, 0xc5, 0xd3, 0x51, 0x3c, 0x72, 0x59,
-	0xfb, 0x7f, 0x46, 0x41, 0x30, 0x9a, 0x10, 0x89, 0x70, 0x14, 0x1f, 0xaf, 0x3b, 0x31, 0xb5, 0x22,
-	0x37, 0xf0, 0xd5, 0xfc, 0xeb, 0x17, 0xe7, 0x23, 0xd7, 0x23, 0x2c, 0xb2, 0xbc, 0x50, 0x2d, 0xf8,
-	0x64, 0xe4, 0x46, 0xe3, 0xf8, 0xa8, 0x63, 0x07, 0xde, 0x7a, 0xba, 0xe5, 0xba, 0xd8, 0x72, 0x3d,
-	0x11, 0x93, 0x8d, 0x2d, 0x4a, 0x9c, 0xf5, 0xb1, 0x3d, 0x61, 0x21, 0xb1, 0xf9, 0xaf, 0xc9, 0x3f,
-	0x14, 0xc2, 0x56, 0x7e, 0x04, 0x16, 0xd1, 0xd8, 0x8e, 0x92, 0xff, 0x6b, 0x45, 0x11, 0x75, 0x8f,
-	0xe2, 0x88, 0x48, 0x20, 0xfd, 0x55, 0x78, 0x65, 0x68, 0xb1, 0x93, 0x5e, 0xe0, 0x1f, 0xbb, 0xa3,
-	0x81, 0x3d, 0x26, 0x9e, 0x85, 0xc9, 0x57, 0x31, 0x61, 0x91, 0xfe, 0x5d, 0x68, 0x5d, 0x9e, 0x62,
-	0x61, 0xe0, 0x33, 0x82, 0x3e, 0x81, 0x65, 0x2e, 0x4d, 0xab, 0x70, 0xab, 0x70, 0xbb, 0xbe, 0xf1,
-	0x4e, 0xe7, 0x79, 0x8a, 0x93, 0x32, 0x74, 0xd4, 0xbf, 0xe8, 0x0c, 0x42, 0x62, 0x63, 0xc1, 0xa9,
-	0xdf, 0x84, 0xeb, 0x3d, 0x2b, 0xb4, 0x8e, 0xdc, 0x89, 0x1b, 0xb9, 0x84, 0x25, 0x9b, 0xc6, 0x70,
-	0x63, 0x96, 0xac, 0x36, 0xfc, 0x02, 0x1a, 0x76, 0x86, 0xae, 0x36, 0xbe, 0xdf, 0xc9, 0x65, 0xb1,
-	0xce, 0xa6, 0x18, 0xcd, 0x00, 0xcf, 0xc0, 0xe9, 0x37, 0x00, 0x3d, 0x74, 0xfd, 0x11, 0xa1, 0x21,
-	0x75, 0xfd, 0x28, 0x11, 0xe6, 0xeb, 0x12, 0x5c, 0x9f, 0x21, 0x2b, 0x61, 0x9e, 0x02, 0xa4, 0x7a,
-	0xe4, 0xa2, 0x94, 0x6e, 0xd7, 0x37, 0x3e, 0xcb, 0x29, 0xca, 0x15, 0x78, 0x9d, 0x6e, 0x0a, 0x66,
-	0xf8, 0x11, 0x3d, 0xc7, 0x19, 0x74, 0xf4, 0x25, 0x54, 0xc6, 0xc4, 0x9a, 0x44, 0xe3, 0x56, 0xf1,
-	0x56, 0xe1, 0x76, 0x73, 0xe3, 0xe1, 0x4b, 0xec, 0xb3, 0x2d, 0x80, 0x06, 0x91, 0x15, 0x11, 0xac,
-	0x50, 0xd1, 0x1d, 0x40, 0xf2, 0xcb, 0x74, 0x08, 0xb3, 0xa9, 0x1b, 0x72, 0x47, 0x6e, 0x95, 0x6e,
-	0x15, 0x6e, 0xd7, 0xf0, 0x35, 0x39, 0xb3, 0x39, 0x9d, 0x68, 0x87, 0xb0, 0x76, 0x41, 0x5a, 0xa4,
-	0x41, 0xe9, 0x84, 0x9c, 0x0b, 0x8b, 0xd4, 0x30, 0xff, 0x44, 0x5b, 0x50, 0x3e, 0xb5, 0x26, 0x31,
-	0x11, 0x22, 0xd7, 0x37, 0xde, 0x7b, 0x91, 0x7b, 0x28, 0x17, 0x9d, 0xea, 0x01, 0x4b, 0xfe, 0x07,
-	0xc5, 0x7b, 0x05, 0xfd, 0x3e, 0xd4, 0x33, 0x72, 0xa3, 0x26, 0xc0, 0x61, 0x7f, 0xd3, 0x18, 0x1a,
-	0xbd, 0xa1, 0xb1, 0xa9, 0x2d, 0xa1, 0x55, 0xa8, 0x1d, 0xf6, 0xb7, 0x8d, 0xee, 0xee, 0x70, 0xfb,
-	0x89, 0x56, 0x40, 0x75, 0x58, 0x49, 0x06, 0x45, 0xfd, 0x0c, 0x10, 0x26, 0x76, 0x70, 0x4a, 0x28,
-	0x77, 0x64, 0x65, 0x55, 0xf4, 0x0a, 0xac, 0x44, 0x16, 0x3b, 0x31, 0x5d, 0x47, 0xc9, 0x5c, 0xe1,
-	0xc3, 0x1d, 0x07, 0xed, 0x40, 0x65, 0x6c, 0xf9, 0xce, 0xe4, 0xc5, 0x72, 0xcf, 0xaa, 0x9a, 0x83,
-	0x6f, 0x0b, 0x46, 0xac, 0x00, 0xb8, 0x77, 0xcf, 0xec, 0x2c, 0x0d, 0xa0, 0x3f, 0x01, 0x6d, 0x10,
-	0x59, 0x34, 0xca, 0x8a, 0x63, 0xc0, 0x32, 0xdf, 0x5f, 0x79, 0xf4, 0x3c, 0x7b, 0xca, 0x93, 0x89,
-	0x05, 0xbb, 0xfe, 0xaf, 0x22, 0x5c, 0xcb, 0x60, 0x2b, 0x4f, 0x7d, 0x0c, 0x15, 0x4a, 0x58, 0x3c,
-	0x89, 0x04, 0x7c, 0x73, 0xe3, 0xe3, 0x9c, 0xf0, 0x97, 0x90, 0x3a, 0x58, 0xc0, 0x60, 0x05, 0x87,
-	0x6e, 0x83, 0x26, 0x39, 0x4c, 0x42, 0x69, 0x40, 0x4d, 0x8f, 0x8d, 0x84, 0xd6, 0x6a, 0xb8, 0x29,
-	0xe9, 0x06, 0x27, 0xef, 0xb1, 0x51, 0x46, 0xab, 0xa5, 0x97, 0xd4, 0x2a, 0xb2, 0x40, 0xf3, 0x49,
-	0xf4, 0x2c, 0xa0, 0x27, 0x26, 0x57, 0x2d, 0x75, 0x1d, 0xd2, 0x5a, 0x16, 0xa0, 0x1f, 0xe4, 0x04,
-	0xed, 0x4b, 0xf6, 0x7d, 0xc5, 0x8d, 0xd7, 0xfc, 0x59, 0x82, 0xfe, 0x36, 0x54, 0xe4, 0x3f, 0xe5,
-	0x9e, 0x34, 0x38, 0xec, 0xf5, 0x8c, 0xc1, 0x40, 0x5b, 0x42, 0x35, 0x28, 0x63, 0x63, 0x88, 0xb9,
-	0x87, 0xd5, 0xa0, 0xfc, 0xb0, 0x3b, 0xec, 0xee, 0x6a, 0x45, 0xfd, 0x2d, 0x58, 0x7b, 0x6c, 0xb9,
-	0x51, 0x1e, 0xe7, 0xd2, 0x03, 0xd0, 0xa6, 0x6b, 0x95, 0x75, 0x76, 0x66, 0xac, 0x93, 0x5f, 0x35,
-	0xc6, 0x99, 0x1b, 0x5d, 0xb0, 0x87, 0x06, 0x25, 0x42, 0xa9, 0x32, 0x01, 0xff, 0xd4, 0x9f, 0xc1,
-	0xda, 0x20, 0x0a, 0xc2, 0x5c, 0x9e, 0xff, 0x3e, 0xac, 0xf0, 0x1c, 0x15, 0xc4, 0x91, 0x72, 0xfd,
-	0x57, 0x3b, 0x32, 0x87, 0x75, 0x92, 0x1c, 0xd6, 0xd9, 0x54, 0x39, 0x0e, 0x27, 0x2b, 0xd1, 0x7f,
-	0x41, 0x85, 0xb9, 0x23, 0xdf, 0x9a, 0xa8, 0x68, 0xa1, 0x46, 0x3a, 0xe2, 0x4e, 0x9e, 0x6c, 0xac,
-	0x1c, 0xbf, 0x07, 0x68, 0x93, 0xb0, 0x88, 0x06, 0xe7, 0xb9, 0xe4, 0xb9, 0x01, 0xe5, 0xe3, 0x80,
-	0xda, 0xf2, 0x20, 0x56, 0xb1, 0x1c, 0xf0, 0x43, 0x35, 0x03, 0xa2, 0xb0, 0xef, 0x00, 0xda, 0xf1,
-	0x79, 0x4e, 0xc9, 0x67, 0x88, 0x5f, 0x16, 0xe1, 0xfa, 0xcc, 0x7a, 0x65, 0x8c, 0xc5, 0xcf, 0x21,
-	0x0f, 0x4c, 0x31, 0x93, 0xe7, 0x10, 0xed, 0x43, 0x45, 0xae, 0x50, 0x9a, 0xbc, 0x3b, 0x07, 0x90,
-	0x4c, 0x53, 0x0a, 0x4e, 0xc1, 0x5c, 0xe9, 0xf4, 0xa5, 0xff, 0xb4, 0xd3, 0x6b, 0xc9, 0xff, 0x60,
-	0x2f, 0xd4, 0xdf, 0x77, 0xe0, 0x5a, 0x66, 0xb1, 0x52, 0xde, 0x43, 0x28, 0x33, 0x4e, 0x50, 0xda,
-	0x7b, 0x77, 0x4e, 0xed, 0x31, 0x2c, 0xd9, 0xf5, 0xeb, 0x12, 0xdc, 0x38, 0x25, 0x7e, 0x2a, 0x8a,
-	0xbe, 0x09, 0xd7, 0x06, 0xc2, 0xb5, 0x72, 0xf9, 0xce, 0xd4, 0x2d, 0x8b, 0x33, 0x6e, 0x79, 0x03,
-	0x50, 0x16, 0x45, 0x39, 0xcf, 0x39, 0xac, 0x19, 0x67, 0xc4, 0xce, 0x85, 0xdc, 0x82, 0x15, 0x3b,
-	0xf0, 0x3c, 0xcb, 0x77, 0x5a, 0xc5, 0x5b, 0xa5, 0xdb, 0x35, 0x9c, 0x0c, 0xb3, 0xe7, 0xa7, 0x94,
-	0xf7, 0xfc, 0xe8, 0xbf, 0x28, 0x80, 0x36, 0xdd, 0x5b, 0x29, 0x92, 0x4b, 0x1f, 0x39, 0x1c, 0x88,
-	0xef, 0xdd, 0xc0, 0x6a, 0xa4, 0xe8, 0xc9, 0x11, 0x97, 0x74, 0x42, 0x69, 0x26, 0x84, 0x94, 0x5e,
-	0x32, 0x84, 0xe8, 0xff, 0x28, 0x00, 0xba, 0x5c, 0x28, 0xa1, 0x37, 0xa0, 0xc1, 0x88, 0xef, 0x98,
-	0x52, 0x8d, 0xd2, 0xc2, 0x55, 0x5c, 0xe7, 0x34, 0xa9, 0x4f, 0x86, 0x10, 0x2c, 0x93, 0x33, 0x62,
-	0xab, 0xd3, 0x2a, 0xbe, 0xd1, 0x18, 0x1a, 0xc7, 0xcc, 0x74, 0x59, 0x30, 0xb1, 0xd2, 0x8a, 0xa2,
-	0xb9, 0x61, 0x2c, 0x5c, 0xb0, 0x75, 0x1e, 0x0e, 0x76, 0x12, 0x30, 0x5c, 0x3f, 0x66, 0xe9, 0x40,
-	0xef, 0x40, 0x3d, 0x33, 0x87, 0xaa, 0xb0, 0xdc, 0xdf, 0xef, 0x1b, 0xda, 0x12, 0x02, 0xa8, 0xf4,
-	0xb6, 0xf1, 0xfe, 0xfe, 0x50, 0x46, 0xed, 0x9d, 0xbd, 0xee, 0x96, 0xa1, 0x15, 0xf5, 0xdf, 0x94,
-	0x01, 0xa6, 0xe9, 0x13, 0x35, 0xa1, 0x98, 0x5a, 0xba, 0xe8, 0x3a, 0xfc, 0xcf, 0xf8, 0x96, 0x47,
-	0x94, 0xf7, 0x88, 0x6f, 0xb4, 0x01, 0x37, 0x3d, 0x36, 0x0a, 0x2d, 0xfb, 0xc4, 0x54, 0x59, 0xcf,
-	0x16, 0xcc, 0xe2, 0x5f, 0x35, 0xf0, 0x75, 0x35, 0xa9, 0xa4, 0x96, 0xb8, 0xbb, 0x50, 0x22, 0xfe,
-	0x69, 0x6b, 0x59, 0x54, 0x87, 0x0f, 0xe6, 0x4e, 0xeb, 0x1d, 0xc3, 0x3f, 0x95, 0xd5, 0x20, 0x87,
-	0x41, 0x7d, 0xa8, 0x51, 0xc2, 0x82, 0x98, 0xda, 0x84, 0xb5, 0xca, 0x73, 0x1d, 0x32, 0x9c, 0xf0,
-	0xe1, 0x29, 0x04, 0xda, 0x84, 0x8a, 0x17, 0xc4, 0x7e, 0xc4, 0x5a, 0x15, 0x21, 0xe0, 0x3b, 0x39,
-	0xc1, 0xf6, 0x38, 0x13, 0x56, 0xbc, 0x68, 0x0b, 0x56, 0x1c, 0x72, 0xea, 0x72, 0x99, 0x56, 0x04,
-	0xcc, 0x9d, 0xbc, 0xf6, 0x15, 0x5c, 0x38, 0xe1, 0xe6, 0x4a, 0x8f, 0x19, 0xa1, 0xad, 0xaa, 0x54,
-	0x3a, 0xff, 0x46, 0xaf, 0x41, 0xcd, 0x9a, 0x4c, 0x02, 0xdb, 0x74, 0x5c, 0xda, 0xaa, 0x89, 0x89,
-	0xaa, 0x20, 0x6c, 0xba, 0x14, 0xbd, 0x0e, 0x75, 0x79, 0x32, 0xcc, 0xd0, 0x8a, 0xc6, 0x2d, 0x10,
-	0xd3, 0x20, 0x49, 0x07, 0x56, 0x34, 0x56, 0x0b, 0x08, 0xa5, 0x72, 0x41, 0x3d, 0x5d, 0x40, 0x28,
-	0x15, 0x0b, 0xfe, 0x17, 0xd6, 0xc4, 0x31, 0x1f, 0xd1, 0x20, 0x0e, 0x4d, 0x61, 0xf2, 0x86, 0x58,
-	0xb4, 0xca, 0xc9, 0x5b, 0x9c, 0xda, 0xe7, 0xb6, 0x7f, 0x15, 0xaa, 0x4f, 0x83, 0x23, 0xb9, 0x60,
-	0x55, 0x2c, 0x58, 0x79, 0x1a, 0x1c, 0x25, 0x53, 0x52, 0x42, 0xd7, 0x69, 0x35, 0xe5, 0x94, 0x18,
-	0xef, 0x38, 0xed, 0x0f, 0xa0, 0x9a, 0x18, 0xf0, 0x8a, 0x02, 0xf9, 0x46, 0xb6, 0x40, 0xae, 0x65,
-	0xab, 0xdd, 0xbf, 0x16, 0xa0, 0x96, 0x1a, 0x0c, 0x7d, 0x0e, 0xab, 0xd4, 0x7a, 0x66, 0x4e, 0x2d,
-	0x2f, 0xc3, 0xeb, 0xfb, 0x79, 0x2d, 0x6f, 0x3d, 0x9b, 0x1a, 0xbf, 0x41, 0x33, 0x23, 0xf4, 0x25,
-	0xac, 0x4d, 0x5c, 0x3f, 0x3e, 0xcb, 0x60, 0xcb, 0x7c, 0xf5, 0x7f, 0x39, 0xb1, 0x77, 0x39, 0xf7,
-	0x14, 0xbd, 0x39, 0x99, 0x19, 0xeb, 0x7f, 0x2c, 0x40, 0x23, 0xbb, 0x3d, 0x57, 0x82, 0x1d, 0xc6,
-	0xe2, 0x0f, 0x94, 0x30, 0xff, 0xe4, 0x21, 0xcd, 0x23, 0x5e, 0x40, 0xcf, 0xc5, 0xce, 0x25, 0xac,
-	0x46, 0xdc, 0x17, 0x1c, 0x97, 0x9d, 0x88, 0xb3, 0x55, 0xc2, 0xe2, 0x9b, 0xd3, 0xdc, 0x20, 0x64,
-	0xa2, 0xda, 0x2b, 0x61, 0xf1, 0x8d, 0x30, 0x54, 0x55, 0x22, 0xe3, 0x27, 0xa2, 0x34, 0x7f, 0x42,
-	0x4c, 0x84, 0xc3, 0x29, 0x8e, 0xfe, 0xeb, 0x22, 0xac, 0x5d, 0x98, 0xe5, 0x72, 0x4a, 0x37, 0x4d,
-	0xd2, 0x81, 0x1c, 0x71, 0x99, 0x6c, 0xd7, 0x49, 0x6a, 0x2e, 0xf1, 0x2d, 0x82, 0x49, 0xa8, 0xea,
-	0xa1, 0xa2, 0x1b, 0x72, 0x43, 0x7b, 0x47, 0x6e, 0x24, 0x05, 0x2f, 0x63, 0x39, 0x40, 0x4f, 0xa0,
-	0x49, 0x09, 0x23, 0xf4, 0x94, 0x38, 0x66, 0x18, 0xd0, 0x28, 0x91, 0x7f, 0x63, 0x3e, 0xf9, 0x0f,
-	0x02, 0x1a, 0xe1, 0xd5, 0x04, 0x89, 0x8f, 0x18, 0x7a, 0x0c, 0xab, 0xce, 0xb9, 0x6f, 0x79, 0xae,
-	0xad, 0x90, 0x2b, 0x0b, 0x23, 0x37, 0x14, 0x90, 0x00, 0xe6, 0xd7, 0xb0, 0xcc, 0x24, 0xff, 0x63,
-	0x13, 0xeb, 0x88, 0x4c, 0x94, 0x4e, 0xe4, 0x60, 0xd6, 0xaf, 0xcb, 0xca, 0xaf, 0xf5, 0xdf, 0x15,
-	0xa1, 0x39, 0xeb, 0x2e, 0xe8, 0xbf, 0x01, 0xec, 0x30, 0x36, 0x43, 0x42, 0xdd, 0xc0, 0x51, 0x4e,
-	0x51, 0xb3, 0xc3, 0xf8, 0x40, 0x10, 0xf8, 0xd1, 0xe7, 0xd3, 0x5f, 0xc5, 0x41, 0x64, 0x29, 0xef,
-	0xa8, 0xda, 0x61, 0xfc, 0x2d, 0x3e, 0x4e, 0x78, 0xc5, 0xdd, 0x91, 0x29, 0x2f, 0xe1, 0xcb, 0x07,
-	0x82, 0x80, 0xde, 0x01, 0x24, 0x1d, 0xc9, 0x9c, 0xb8, 0x9e, 0x1b, 0x99, 0x47, 0xe7, 0xfc, 0x92,
-	0x2e, 0x1d, 0x47, 0x93, 0x33, 0xbb, 0x7c, 0xe2, 0x53, 0x4e, 0x47, 0x3a, 0xac, 0x06, 0x81, 0x67,
-	0x32, 0x3b, 0xa0, 0xc4, 0xb4, 0x9c, 0xa7, 0x22, 0xb6, 0x96, 0x70, 0x3d, 0x08, 0xbc, 0x01, 0xa7,
-	0x75, 0x9d, 0xa7, 0x3c, 0x94, 0xd8, 0x61, 0xcc, 0x48, 0x64, 0xf2, 0x9f, 0x56, 0x45, 0x86, 0x12,
-	0x49, 0xea, 0x85, 0x31, 0xcb, 0x2c, 0xf0, 0x88, 0xc7, 0x43, 0x61, 0x66, 0xc1, 0x1e, 0xf1, 0xf8,
-	0x2e, 0x8d, 0x03, 0x42, 0x6d, 0xe2, 0x47, 0x43, 0xd7, 0x3e, 0x61, 0x22, 0xcc, 0x15, 0xf0, 0x0c,
-	0x4d, 0xff, 0x02, 0xca, 0x22, 0xb8, 0xf2, 0x3f, 0x2f, 0x02, 0x93, 0x88, 0x5b, 0x52, 0xbd, 0x55,
-	0x4e, 0x10, 0x51, 0xeb, 0x35, 0xa8, 0x8d, 0x03, 0xa6, 0xa2, 0x9e, 0xf4, 0xbc, 0x2a, 0x27, 0x88,
-	0xc9, 0x36, 0x54, 0x29, 0xb1, 0x9c, 0xc0, 0x9f, 0x9c, 0x0b, 0xbd, 0x54, 0x71, 0x3a, 0xd6, 0xbf,
-	0x82, 0x8a, 0x0c, 0xba, 0x2f, 0x81, 0x7f, 0x07, 0x90, 0x2d, 0xc3, 0x65, 0x48, 0xa8, 0xe7, 0x32,
-	0xe6, 0x06, 0x3e, 0x4b, 0xde, 0x0a, 0xe4, 0xcc, 0xc1, 0x74, 0x42, 0xff, 0x4b, 0x41, 0x26, 0x5a,
-	0x79, 0x8b, 0xe3, 0xa5, 0x8a, 0xca, 0x9a, 0x0b, 0x5f, 0x75, 0x15, 0x40, 0x52, 0x6e, 0x12, 0xf5,
-	0x26, 0x32, 0x6f, 0xb9, 0x49, 0x64, 0xb9, 0x49, 0x78, 0x6d, 0xa3, 0xf2, 0xb9, 0x84, 0x93, 0xe9,
-	0xbc, 0xee, 0xa4, 0x75, 0x38, 0xd1, 0xff, 0x59, 0x48, 0x23, 0x42, 0x52, 0x2f, 0xa3, 0x2f, 0xa1,
-	0xca, 0x0f, 0x97, 0xe9, 0x59, 0xa1, 0x7a, 0xfd, 0xe9, 0x2d, 0x56, 0x8a, 0x77, 0xf8, 0x59, 0xda,
-	0xb3, 0x42, 0x99, 0xe8, 0x57, 0x42, 0x39, 0xe2, 0x91, 0xc5, 0x72, 0xa6, 0x91, 0x85, 0x7f, 0xa3,
-	0x37, 0xa1, 0x69, 0xc5, 0x51, 0x60, 0x5a, 0xce, 0x29, 0xa1, 0x91, 0xcb, 0x88, 0xb2, 0xf0, 0x2a,
-	0xa7, 0x76, 0x13, 0x62, 0xfb, 0x01, 0x34, 0xb2, 0x98, 0x2f, 0xca, 0x3d, 0xe5, 0x6c, 0xee, 0xf9,
-	0x1e, 0xc0, 0xb4, 0x2c, 0xe4, 0x9e, 0x40, 0xce, 0xdc, 0xc8, 0xb4, 0x03, 0x47, 0x46, 0xbe, 0x32,
-	0xae, 0x72, 0x42, 0x2f, 0x70, 0xc8, 0x85, 0x22, 0xbb, 0x9c, 0x14, 0xd9, 0xfc, 0x6c, 0xf2, 0xe3,
-	0x74, 0xe2, 0x4e, 0x26, 0xc4, 0x51, 0x12, 0xd6, 0x82, 0xc0, 0x7b, 0x24, 0x08, 0xfa, 0xd7, 0x45,
-	0xe9, 0x11, 0xf2, 0x8a, 0x93, 0xab, 0xf4, 0x4a, 0x4d, 0x5d, 0x7a, 0x39, 0x53, 0xdf, 0x07, 0x60,
-	0x91, 0x45, 0x23, 0xe2, 0x98, 0x56, 0xa4, 0x5e, 0x0d, 0xda, 0x97, 0xaa, 0xf4, 0x61, 0xf2, 0x52,
-	0x8b, 0x6b, 0x6a, 0x75, 0x37, 0x42, 0x1f, 0x42, 0xc3, 0x0e, 0xbc, 0x70, 0x42, 0x14, 0x73, 0xf9,
-	0x85, 0xcc, 0xf5, 0x74, 0x7d, 0x37, 0xca, 0x94, 0xe8, 0x95, 0x97, 0x2d, 0xd1, 0xff, 0x54, 0x90,
-	0x37, 0xb5, 0xec, 0x45, 0x11, 0x8d, 0xae, 0x78, 0x8d, 0xdc, 0x5a, 0xf0, 0xd6, 0xf9, 0x4d, 0x4f,
-	0x91, 0xed, 0x0f, 0xf3, 0xbc, 0xfd, 0x3d, 0xbf, 0xb4, 0xf9, 0x73, 0x09, 0x6a, 0xe9, 0x85, 0xef,
-	0x92, 0xed, 0xef, 0x41, 0x2d, 0x7d, 0x26, 0x57, 0xa5, 0xc8, 0x37, 0x9a, 0x27, 0x5d, 0x8c, 0x8e,
-	0x01, 0x59, 0xa3, 0x51, 0x5a, 0xc8, 0x98, 0x31, 0xb3, 0x46, 0xc9, 0x15, 0xf9, 0xde, 0x1c, 0x7a,
-	0x48, 0xb2, 0xd3, 0x21, 0xe7, 0xc7, 0x9a, 0x35, 0x1a, 0xcd, 0x50, 0xd0, 0xf7, 0xe1, 0xe6, 0xec,
-	0x1e, 0xe6, 0xd1, 0xb9, 0x19, 0xba, 0x8e, 0x2a, 0xf1, 0xb7, 0xe7, 0xbd, 0xf3, 0x76, 0x66, 0xe0,
-	0x3f, 0x3d, 0x3f, 0x70, 0x1d, 0xa9, 0x73, 0x44, 0x2f, 0x4d, 0xb4, 0x7f, 0x08, 0xaf, 0x3c, 0x67,
-	0xf9, 0x15, 0x36, 0xe8, 0xcf, 0xbe, 0xbf, 0x2e, 0xae, 0x84, 0x8c, 0xf5, 0x7e, 0x5b, 0x90, 0x57,
-	0xf3, 0x59, 0x9d, 0x74, 0xa7, 0x55, 0x5d, 0x7d, 0x63, 0x3d, 0xe7, 0x3e, 0xbd, 0x83, 0x43, 0x09,
-	0x2f, 0xca, 0xc0, 0xcf, 0x66, 0xca, 0xc0, 0xfc, 0xa5, 0xca, 0x9e, 0x60, 0x92, 0x40, 0x0a, 0x41,
-	0xff, 0x43, 0x09, 0xaa, 0x09, 0xba, 0xb8, 0x01, 0x9c, 0xb3, 0x88, 0x78, 0xa6, 0x97, 0x84, 0xb0,
-	0x02, 0x06, 0x49, 0xda, 0xe3, 0x41, 0xec, 0x35, 0xa8, 0xf1, 0x8b, 0x86, 0x9c, 0x2e, 0x8a, 0xe9,
-	0x2a, 0x27, 0x88, 0xc9, 0xd7, 0xa1, 0x1e, 0x05, 0x91, 0x35, 0x31, 0x23, 0x91, 0xb1, 0x4b, 0x92,
-	0x5b, 0x90, 0x44, 0xbe, 0x46, 0x6f, 0xc3, 0xb5, 0x68, 0x4c, 0x83, 0x28, 0x9a, 0xf0, 0x2a, 0x4e,
-	0xd4, 0x2d, 0xb2, 0xcc, 0x58, 0xc6, 0x5a, 0x3a, 0x21, 0xeb, 0x19, 0xc6, 0xa3, 0xf7, 0x74, 0x31,
-	0x77, 0x5d, 0x11, 0x44, 0x96, 0xf1, 0x6a, 0x4a, 0xe5, 0xae, 0x8d, 0x5a, 0xb0, 0x12, 0xca, 0x9a,
-	0x40, 0xc4, 0x8a, 0x02, 0x4e, 0x86, 0xc8, 0x84, 0x35, 0x8f, 0x58, 0x2c, 0xa6, 0xc4, 0x31, 0x8f,
-	0x5d, 0x32, 0x71, 0xe4, 0x8d, 0xab, 0x99, 0xbb, 0xe6, 0x4d, 0xd4, 0xd2, 0x79, 0x28, 0xb8, 0x71,
-	0x33, 0x81, 0x93, 0x63, 0x5e, 0x1f, 0xc8, 0x2f, 0xb4, 0x06, 0xf5, 0xc1, 0x93, 0xc1, 0xd0, 0xd8,
-	0x33, 0xf7, 0xf6, 0x37, 0x0d, 0xf5, 0xc4, 0x3e, 0x30, 0xb0, 0x1c, 0x16, 0xf8, 0xfc, 0x70, 0x7f,
-	0xd8, 0xdd, 0x35, 0x87, 0x3b, 0xbd, 0x47, 0x03, 0xad, 0x88, 0x6e, 0xc2, 0xb5, 0xe1, 0x36, 0xde,
-	0x1f, 0x0e, 0x77, 0x8d, 0x4d, 0xf3, 0xc0, 0xc0, 0x3b, 0xfb, 0x9b, 0x03, 0xad, 0x84, 0x10, 0x34,
-	0xa7, 0xe4, 0xe1, 0xce, 0x9e, 0xa1, 0x2d, 0xa3, 0x3a, 0xac, 0x1c, 0x18, 0xb8, 0x67, 0xf4, 0x87,
-	0x5a, 0x59, 0xff, 0x5b, 0x11, 0xea, 0x19, 0x2b, 0x72, 0x47, 0xa6, 0x4c, 0xde, 0x71, 0x96, 0x31,
-	0xff, 0xe4, 0xc1, 0xc4, 0xb6, 0xec, 0xb1, 0xb4, 0xce, 0x32, 0x96, 0x03, 0x6e, 0x37, 0xcf, 0x3a,
-	0xcb, 0x9c, 0xf3, 0x65, 0x5c, 0xf5, 0xac, 0x33, 0x09, 0xf2, 0x06, 0x34, 0x4e, 0x08, 0xf5, 0xc9,
-	0x44, 0xcd, 0x4b, 0x8b, 0xd4, 0x25, 0x4d, 0x2e, 0xb9, 0x0d, 0x9a, 0x5a, 0x32, 0x85, 0x91, 0xe6,
-	0x68, 0x4a, 0xfa, 0x5e, 0x02, 0x76, 0x74, 0x59, 0xeb, 0x15, 0xa1, 0xf5, 0xfb, 0xf3, 0x3b, 0xe9,
-	0xf3, 0x14, 0x3f, 0x48, 0x15, 0xbf, 0x02, 0x25, 0x9c, 0xbc, 0x36, 0xf7, 0xba, 0xbd, 0x6d, 0xae,
-	0xec, 0x55, 0xa8, 0xed, 0x75, 0x3f, 0x37, 0x0f, 0x07, 0xe2, 0xed, 0x02, 0x69, 0xd0, 0x78, 0x64,
-	0xe0, 0xbe, 0xb1, 0xab, 0x28, 0x25, 0x74, 0x03, 0x34, 0x45, 0x99, 0xae, 0x5b, 0xd6, 0x7f, 0x5f,
-	0x84, 0x35, 0x19, 0xd7, 0xd3, 0xe7, 0xb4, 0xe7, 0xbf, 0x6b, 0x2d, 0x1e, 0x7a, 0x5b, 0xb0, 0xe2,
-	0x11, 0x96, 0xda, 0xa1, 0x86, 0x93, 0x21, 0x72, 0xa1, 0x6e, 0xf9, 0x7e, 0x10, 0x89, 0x37, 0x19,
-	0xa6, 0x42, 0xe4, 0xd6, 0x5c, 0xaf, 0x3f, 0xa9, 0xe4, 0x9d, 0xee, 0x14, 0x49, 0x46, 0xc8, 0x2c,
-	0x76, 0xfb, 0x23, 0xd0, 0x2e, 0x2e, 0x98, 0x27, 0x2f, 0xbd, 0xf5, 0xde, 0x34, 0x2d, 0x11, 0xee,
-	0xa0, 0x87, 0xfd, 0x47, 0xfd, 0xfd, 0xc7, 0x7d, 0x6d, 0x89, 0x0f, 0xf0, 0x61, 0xbf, 0xbf, 0xd3,
-	0xdf, 0xd2, 0x0a, 0x08, 0xa0, 0x62, 0x7c, 0xbe, 0x33, 0x34, 0x36, 0xb5, 0xe2, 0xc6, 0xdf, 0x57,
-	0xa1, 0x22, 0x85, 0x44, 0xbf, 0x52, 0x29, 0x39, 0xdb, 0x26, 0x45, 0x1f, 0xcd, 0x5d, 0xda, 0xce,
-	0xb4, 0x5e, 0xdb, 0x1f, 0x2f, 0xcc, 0xaf, 0x9e, 0x35, 0x97, 0xd0, 0xcf, 0x0b, 0xd0, 0x98, 0x79,
-	0xc7, 0xcb, 0xfb, 0x04, 0x75, 0x45, 0x57, 0xb6, 0xfd, 0xff, 0x0b, 0xf1, 0xa6, 0xb2, 0xfc, 0xac,
-	0x00, 0xf5, 0x4c, 0x3f, 0x12, 0xdd, 0x5f, 0xa4, 0x87, 0x29, 0x25, 0x79, 0xb0, 0x78, 0xfb, 0x53,
-	0x5f, 0x7a, 0xb7, 0x80, 0x7e, 0x5a, 0x80, 0x7a, 0xa6, 0x33, 0x97, 0x5b, 0x94, 0xcb, 0x7d, 0xc4,
-	0xdc, 0xa2, 0x5c, 0xd5, 0x08, 0x5c, 0x42, 0x3f, 0x2a, 0x40, 0x2d, 0xed, 0xb2, 0xa1, 0xbb, 0xf3,
-	0xf7, 0xe5, 0xa4, 0x10, 0xf7, 0x16, 0x6d, 0xe8, 0xe9, 0x4b, 0xe8, 0x07, 0x50, 0x4d, 0x5a, 0x52,
-	0x28, 0x6f, 0x1a, 0xb9, 0xd0, 0xef, 0x6a, 0xdf, 0x9d, 0x9b, 0x2f, 0xbb, 0x7d, 0xd2, 0x27, 0xca,
-	0xbd, 0xfd, 0x85, 0x8e, 0x56, 0xfb, 0xee, 0xdc, 0x7c, 0xe9, 0xf6, 0xdc, 0x13, 0x32, 0xed, 0xa4,
-	0xdc, 0x9e, 0x70, 0xb9, 0x8f, 0x95, 0xdb, 0x13, 0xae, 0xea, 0x5e, 0x49, 0x41, 0x32, 0x0d, 0xa9,
-	0xdc, 0x82, 0x5c, 0x6e, 0x7a, 0xe5, 0x16, 0xe4, 0x8a, 0xfe, 0x97, 0x72, 0xc9, 0x69, 0x81, 0x7e,
-	0x77, 0xee, 0x1e, 0xce, 0x9c, 0x2e, 0x79, 0xa9, 0x8b, 0xa4, 0x2f, 0xa1, 0x1f, 0xab, 0x27, 0x03,
-	0xd9, 0x00, 0x42, 0xf3, 0x40, 0xcd, 0xf4, 0x8c, 0xda, 0x1f, 0x2c, 0x96, 0x6a, 0x44, 0x8c, 0xf8,
-	0x49, 0x01, 0x60, 0xda, 0x2a, 0xca, 0x2d, 0xc4, 0xa5, 0x1e, 0x55, 0xfb, 0xfe, 0x02, 0x9c, 0xd9,
-	0xe3, 0x91, 0x74, 0x87, 0x72, 0x1f, 0x8f, 0x0b, 0xad, 0xac, 0xdc, 0xc7, 0xe3, 0x62, 0x1b, 0x4a,
-	0x5f, 0xfa, 0x74, 0xe5, 0xdb, 0x65, 0x99, 0xfb, 0x2b, 0xe2, 0xe7, 0xfd, 0x7f, 0x07, 0x00, 0x00,
-	0xff, 0xff, 0xf3, 0x57, 0x35, 0x86, 0x41, 0x25, 0x00, 0x00,
+	// 3632 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xad, 0x1a, 0x5d, 0x6f, 0x1b, 0xc7,
+	0xd1, 0x14, 0x3f, 0x44, 0x0e, 0x25, 0x8a, 0x5e, 0xdb, 0x89, 0xcc, 0xb6, 0x49, 0x7b, 0x40, 0x0a,
+	0x23, 0x1f, 0x54, 0xa2, 0x20, 0xf1, 0x47, 0x9c, 0x0f, 0x9a, 0xa2, 0x2c, 0xc6, 0x12, 0xa9, 0x1e,
+	0x29, 0x38, 0xae, 0x9b, 0x5c, 0x4f, 0xbc, 0x33, 0x75, 0x36, 0xc9, 0xbb, 0xdc, 0x1d, 0x6d, 0xab,
+	0x45, 0xd1, 0xa2, 0x5f, 0x68, 0x81, 0x06, 0xed, 0x4b, 0xda, 0x97, 0xfe, 0x81, 0xa2, 0x6f, 0x45,
+	0x1e, 0x8a, 0x16, 0x79, 0x29, 0xd0, 0xf6, 0x7f, 0xf4, 0xa9, 0xaf, 0xfd, 0x07, 0x9d, 0xd9, 0xdd,
+	0x3b, 0xde, 0x89, 0x74, 0x7c, 0xa4, 0xfc, 0x22, 0xed, 0xcc, 0xee, 0xce, 0xce, 0xcd, 0xcc, 0xce,
+	0x17, 0x17, 0x14, 0x67, 0x30, 0xee, 0x5b, 0x23, 0x6f, 0xc3, 0x70, 0xad, 0x87, 0xa6, 0xeb, 0x6d,
+	0x38, 0xae, 0xed, 0xdb, 0x12, 0xaa, 0x72, 0x80, 0xbd, 0x74, 0xa4, 0x7b, 0x47, 0x56, 0xcf, 0x76,
+	0x9d, 0xea, 0xc8, 0x1e, 0xea, 0x46, 0x55, 0xee, 0xa9, 0xca, 0x3d, 0x62, 0x59, 0xe5, 0x85, 0xbe,
+	0x6d, 0xf7, 0x07, 0xa6, 0xa0, 0x70, 0x38, 0xbe, 0xb7, 0x61, 0x8c, 0x5d, 0xdd, 0xb7, 0xec, 0x91,
+	0x9c, 0x7f, 0xf1, 0xe4, 0xbc, 0x6f, 0x0d, 0x4d, 0xcf, 0xd7, 0x87, 0x8e, 0x5c, 0xf0, 0x41, 0xdf,
+	0xf2, 0x8f, 0xc6, 0x87, 0xd5, 0x9e, 0x3d, 0xdc, 0x08, 0x8f, 0xdc, 0xe0, 0x47, 0x6e, 0x04, 0x6c,
+	0x7a, 0x47, 0xba, 0x6b, 0x1a, 0x1b, 0x47, 0xbd, 0x81, 0xe7, 0x98, 0x3d, 0xfa, 0xaf, 0xd1, 0x40,
+	0x52, 0xb8, 0x99, 0x9c, 0x82, 0xe7, 0xbb, 0xe3, 0x9e, 0x1f, 0x7c, 0xaf, 0xee, 0xfb, 0xae, 0x75,
+	0x38, 0xf6, 0x4d, 0x41, 0x48, 0xb9, 0x08, 0xcf, 0x77, 0x75, 0xef, 0x41, 0xdd, 0x1e, 0xdd, 0xb3,
+	0xfa, 0x9d, 0xde, 0x91, 0x39, 0xd4, 0x55, 0xf3, 0xd3, 0x31, 0xb2, 0xab, 0x7c, 0x0f, 0xd6, 0xa7,
+	0xa7, 0x3c, 0xc7, 0x1e, 0x79, 0x26, 0xfb, 0x00, 0x32, 0xc4, 0xcd, 0x7a, 0xea, 0x9b, 0xa9, 0x4b,
+	0xc5, 0xcd, 0x57, 0xab, 0x4f, 0x12, 0x9c, 0xe0, 0xa1, 0x2a, 0xbf, 0xa2, 0xda, 0xc1, 0x3f, 0x2a,
+	0xdf, 0xa9, 0x5c, 0x80, 0x73, 0x75, 0xdd, 0xd1, 0x0f, 0xad, 0x81, 0xe5, 0x5b, 0xa6, 0x17, 0x1c,
+	0x3a, 0x86, 0xf3, 0x71, 0xb4, 0x3c, 0xf0, 0x63, 0x58, 0xe9, 0x45, 0xf0, 0xf2, 0xe0, 0xab, 0xd5,
+	0x44, 0x1a, 0xab, 0x6e, 0x71, 0x28, 0x46, 0x38, 0x46, 0x4e, 0x39, 0x0f, 0x6c, 0xdb, 0x1a, 0xf5,
+	0x4d, 0xd7, 0x71, 0xad, 0x91, 0x1f, 0x30, 0xf3, 0x65, 0x1a, 0xce, 0xc5, 0xd0, 0x92, 0x99, 0xfb,
+	0x00, 0xa1, 0x1c, 0x89, 0x95, 0x34, 0xb2, 0xf2, 0x61, 0x42, 0x56, 0x66, 0xd0, 0xab, 0xd6, 0x42,
+	0x62, 0x8d, 0x91, 0xef, 0x1e, 0xab, 0x11, 0xea, 0xec, 0x13, 0xc8, 0x1d, 0x99, 0xfa, 0xc0, 0x3f,
+	0x5a, 0x5f, 0xc2, 0x4f, 0x2e, 0x6d, 0x6e, 0x9f, 0xe2, 0x9c, 0x1d, 0x4e, 0xa8, 0xe3, 0xeb, 0xbe,
+	0xa9, 0x4a, 0xaa, 0xec, 0x35, 0x60, 0x62, 0xa4, 0x19, 0xa6, 0xd7, 0x73, 0x2d, 0x87, 0x0c, 0x79,
+	0x3d, 0x8d, 0x67, 0x15, 0xd4, 0xb3, 0x62, 0x66, 0x6b, 0x32, 0x51, 0x71, 0x60, 0xed, 0x04, 0xb7,
+	0xac, 0x0c, 0xe9, 0x07, 0xe6, 0x31, 0xd7, 0x48, 0x41, 0xa5, 0x21, 0xbb, 0x09, 0xd9, 0x87, 0xfa,
+	0x60, 0x6c, 0x72, 0x96, 0x8b, 0x9b, 0x6f, 0x3c, 0xcd, 0x3c, 0xa4, 0x89, 0x4e, 0xe4, 0xa0, 0x8a,
+	0xfd, 0xd7, 0x96, 0xae, 0xa4, 0x94, 0xab, 0x50, 0x8c, 0xf0, 0xcd, 0x4a, 0x00, 0x07, 0xad, 0xad,
+	0x46, 0xb7, 0x51, 0xef, 0x36, 0xb6, 0xca, 0x67, 0xd8, 0x2a, 0x14, 0x0e, 0x5a, 0x3b, 0x8d, 0xda,
+	0x6e, 0x77, 0xe7, 0x4e, 0x39, 0xc5, 0x8a, 0xb0, 0x1c, 0x00, 0x4b, 0xca, 0x63, 0x60, 0xaa, 0xd9,
+	0xb3, 0x51, 0x2a, 0x64, 0xc8, 0x52, 0xab, 0xec, 0x79, 0x58, 0xf6, 0x11, 0xd4, 0x2c, 0x43, 0xf2,
+	0x9c, 0x23, 0xb0, 0x69, 0xb0, 0x26, 0x8a, 0x5a, 0x1f, 0x19, 0x83, 0xa7, 0xf3, 0x1d, 0x17, 0x35,
+	0x11, 0xdf, 0xe1, 0x1b, 0x55, 0x49, 0x80, 0xac, 0x3b, 0x76, 0xb2, 0x50, 0x80, 0x72, 0x07, 0xca,
+	0xf8, 0x15, 0xae, 0x1f, 0x65, 0xa7, 0x01, 0x19, 0x3a, 0x5f, 0x5a, 0xf4, 0x3c, 0x67, 0x8a, 0x9b,
+	0xa9, 0xf2, 0xed, 0xca, 0xff, 0x96, 0xe0, 0x6c, 0x84, 0xb6, 0xb4, 0xd4, 0xdb, 0x90, 0x73, 0x4d,
+	0x6f, 0x3c, 0xf0, 0x39, 0xf9, 0xd2, 0xe6, 0xfb, 0x09, 0xc9, 0x4f, 0x51, 0xaa, 0xaa, 0x9c, 0x8c,
+	0x2a, 0xc9, 0xb1, 0x4b, 0x50, 0x16, 0x3b, 0x34, 0xd3, 0x75, 0x6d, 0x57, 0x1b, 0x7a, 0x7d, 0x2e,
+	0xb5, 0x82, 0x5a, 0x12, 0xf8, 0x06, 0xa1, 0xf7, 0xbc, 0x7e, 0x44, 0xaa, 0xe9, 0x53, 0x4a, 0x95,
+	0xe9, 0x50, 0x1e, 0x99, 0xfe, 0x23, 0xdb, 0x7d, 0xa0, 0x91, 0x68, 0x5d, 0xcb, 0x30, 0xd7, 0x33,
+	0x9c, 0xe8, 0xdb, 0x09, 0x89, 0xb6, 0xc4, 0xf6, 0xb6, 0xdc, 0xad, 0xae, 0x8d, 0xe2, 0x08, 0xe5,
+	0x15, 0xc8, 0x89, 0x2f, 0x25, 0x4b, 0xea, 0x1c, 0xd4, 0xeb, 0x8d, 0x4e, 0x07, 0xad, 0xac, 0x00,
+	0x59, 0xb5, 0xd1, 0x55, 0xc9, 0xc2, 0x70, 0xb8, 0x5d, 0xeb, 0xd6, 0x76, 0xd1, 0xbe, 0x5e, 0x86,
+	0xb5, 0xdb, 0xba, 0xe5, 0x27, 0x31, 0x2e, 0xc5, 0x86, 0xf2, 0x64, 0xad, 0xd4, 0x4e, 0x33, 0xa6,
+	0x9d, 0xe4, 0xa2, 0x69, 0x3c, 0xb6, 0xfc, 0x13, 0xfa, 0xc0, 0x4b, 0x88, 0x5f, 0x20, 0x55, 0x40,
+	0x43, 0xe5, 0x11, 0xac, 0x75, 0x7c, 0xdb, 0x49, 0x64, 0xf9, 0x6f, 0xe2, 0x04, 0xc6, 0x28, 0x7b,
+	0xec, 0x4b, 0xd3, 0xbf, 0x58, 0x15, 0x31, 0xac, 0x1a, 0xc4, 0xb0, 0xea, 0x96, 0x8c, 0x71, 0x6a,
+	0xb0, 0x92, 0x3d, 0x07, 0x39, 0xcf, 0xea, 0x8f, 0xf4, 0x81, 0xf4, 0x16, 0x12, 0x52, 0x18, 0x19,
+	0x79, 0x70, 0xb0, 0x34, 0xfc, 0x3a, 0x30, 0xf4, 0x22, 0xbe, 0x6b, 0x1f, 0x27, 0xe2, 0xe7, 0x3c,
+	0x64, 0xef, 0xd9, 0x6e, 0x4f, 0x5c, 0xc4, 0xbc, 0x2a, 0x00, 0xba, 0x54, 0x31, 0x22, 0x92, 0x36,
+	0x7a, 0xb0, 0xe6, 0x88, 0x62, 0x4a, 0x32, 0x45, 0xfc, 0x6e, 0x09, 0xce, 0xc5, 0xd6, 0x4b, 0x65,
+	0x2c, 0x7e, 0x0f, 0xc9, 0x31, 0x8d, 0x3d, 0x71, 0x0f, 0x59, 0x1b, 0x72, 0x62, 0x85, 0x94, 0xe4,
+	0xe5, 0x39, 0x08, 0x89, 0x30, 0x25, 0xc9, 0x49, 0x32, 0x33, 0x8d, 0x3e, 0xfd, 0xac, 0x8d, 0xbe,
+	0x1c, 0x7c, 0x87, 0xf7, 0x54, 0xf9, 0xdd, 0x85, 0xb3, 0x91, 0xc5, 0x52, 0x78, 0xdb, 0x90, 0xf5,
+	0x08, 0x21, 0xa5, 0xf7, 0xfa, 0x9c, 0xd2, 0xf3, 0x54, 0xb1, 0x5d, 0x39, 0x27, 0x88, 0x37, 0x1e,
+	0x9a, 0xa3, 0x90, 0x15, 0x65, 0x0b, 0x3d, 0x1b, 0x37, 0xad, 0x44, 0xb6, 0x33, 0x31, 0xcb, 0xa5,
+	0x98, 0x59, 0x62, 0x88, 0x8f, 0x52, 0x91, 0xc6, 0x73, 0x0c, 0x6b, 0x8d, 0xc7, 0x66, 0x2f, 0x11,
+	0xe5, 0x75, 0x58, 0xc6, 0x7c, 0x6b, 0x88, 0xbe, 0x08, 0x49, 0xa7, 0x71, 0x22, 0x00, 0xa3, 0xf7,
+	0x27, 0x9d, 0xf4, 0xfe, 0x28, 0x9f, 0xa5, 0xa0, 0x3c, 0x39, 0x5b, 0x0a, 0x92, 0xb8, 0xf7, 0x0d,
+	0x22, 0x44, 0x67, 0xaf, 0xa8, 0x12, 0x92, 0xf8, 0xe0, 0x8a, 0x0b, 0x3c, 0x42, 0x11, 0x17, 0x92,
+	0x3e, 0xa5, 0x0b, 0x51, 0x7e, 0x91, 0xc6, 0x4b, 0x3a, 0x95, 0x28, 0xb1, 0x6f, 0xc1, 0x8a, 0x67,
+	0x8e, 0x0c, 0x4d, 0x88, 0x51, 0x68, 0x38, 0xaf, 0x16, 0x09, 0x27, 0xe4, 0xe9, 0x31, 0x06, 0x19,
+	0x13, 0x3f, 0x44, 0xde, 0x56, 0x3e, 0x66, 0x47, 0xb0, 0x72, 0xcf, 0xd3, 0x2c, 0xcf, 0x1e, 0xe8,
+	0x61, 0x46, 0x51, 0xda, 0x6c, 0x2c, 0x9c, 0xb0, 0x55, 0xb7, 0x3b, 0xcd, 0x80, 0x98, 0x5a, 0xbc,
+	0xe7, 0x85, 0x00, 0x7b, 0x01, 0x00, 0x93, 0xd3, 0xde, 0x03, 0xc7, 0xc6, 0x5c, 0x87, 0xc7, 0x83,
+	0xbc, 0x1a, 0xc1, 0xd0, 0x07, 0xb8, 0xe6, 0xd0, 0xf6, 0x4d, 0x8d, 0xf4, 0xe8, 0xad, 0x67, 0xc5,
+	0x07, 0x08, 0x1c, 0x09, 0xdf, 0x63, 0xaf, 0xc0, 0xd9, 0xe0, 0x8e, 0x4d, 0x38, 0xce, 0xf1, 0x75,
+	0xc1, 0xe5, 0x9b, 0x9c, 0x57, 0x85, 0x73, 0xc3, 0xb1, 0xe7, 0x6b, 0x3d, 0xd7, 0xc4, 0x84, 0x44,
+	0x93, 0xf3, 0xeb, 0xcb, 0x7c, 0xf9, 0x59, 0x9a, 0xaa, 0xf3, 0x19, 0x79, 0xed, 0x94, 0x2a, 0x14,
+	0x23, 0xbc, 0xb3, 0x3c, 0x64, 0x5a, 0xed, 0x56, 0x03, 0x83, 0x0a, 0x40, 0xae, 0xbe, 0xa3, 0xb6,
+	0xdb, 0x5d, 0x11, 0x55, 0x9a, 0x7b, 0xb5, 0x9b, 0x0d, 0x8c, 0x2a, 0x3f, 0xcf, 0x01, 0x4c, 0xc2,
+	0x3b, 0x26, 0x3c, 0x4b, 0xa1, 0x25, 0xe2, 0x88, 0x84, 0x3d, 0xd2, 0x87, 0xa6, 0xb4, 0x6e, 0x3e,
+	0x66, 0x9b, 0x70, 0x01, 0x03, 0xb0, 0xa3, 0xf7, 0x1e, 0x68, 0x32, 0x2a, 0xf7, 0xf8, 0x66, 0x2e,
+	0xf5, 0x15, 0xf5, 0x9c, 0x9c, 0x94, 0x52, 0x15, 0x74, 0x77, 0x31, 0x62, 0x8c, 0x1e, 0xa2, 0xbc,
+	0x28, 0x7b, 0xbd, 0x36, 0x77, 0xda, 0x51, 0x6d, 0x8c, 0x1e, 0x8a, 0x6c, 0x95, 0xc8, 0xb0, 0x16,
+	0x14, 0xd0, 0x8c, 0xec, 0x31, 0xfa, 0x69, 0x21, 0xe1, 0xe4, 0x4e, 0x40, 0x0d, 0xf6, 0xa9, 0x13,
+	0x12, 0x6c, 0x0b, 0x72, 0x43, 0x7b, 0x8c, 0x4e, 0x00, 0xd5, 0x90, 0xfe, 0xca, 0x12, 0x23, 0x4e,
+	0x6c, 0x8f, 0x36, 0xa9, 0x72, 0x2f, 0x26, 0xa2, 0xcb, 0x86, 0xf9, 0xd0, 0x22, 0x9e, 0x96, 0x39,
+	0x99, 0xd7, 0x92, 0xda, 0x1f, 0xdf, 0xa5, 0x06, 0xbb, 0x49, 0xe8, 0x63, 0x0f, 0x7d, 0x7a, 0x5e,
+	0x08, 0x9d, 0xc6, 0xec, 0x6b, 0x50, 0xd0, 0x07, 0x03, 0xbb, 0xa7, 0x19, 0x96, 0xbb, 0x5e, 0xe0,
+	0x13, 0x79, 0x8e, 0xd8, 0xb2, 0x5c, 0xf6, 0x22, 0x14, 0xc5, 0xcd, 0xd5, 0x1c, 0x1d, 0x73, 0x77,
+	0xe0, 0xd3, 0x20, 0x50, 0xfb, 0x88, 0x91, 0x0b, 0xf0, 0x0a, 0x8b, 0x05, 0xc5, 0x70, 0x01, 0xa2,
+	0xf8, 0x82, 0x6f, 0xc3, 0x1a, 0x77, 0x43, 0x7d, 0xd7, 0x1e, 0x3b, 0x1a, 0x57, 0xf9, 0x0a, 0x5f,
+	0xb4, 0x4a, 0xe8, 0x9b, 0x84, 0x6d, 0x91, 0xee, 0x2f, 0x42, 0xfe, 0xbe, 0x7d, 0x28, 0x16, 0xac,
+	0xf2, 0x05, 0xcb, 0x08, 0x07, 0x53, 0x82, 0x43, 0x34, 0xa0, 0x92, 0x98, 0xe2, 0x30, 0xfa, 0xb2,
+	0xa3, 0x59, 0x16, 0xbf, 0xc6, 0xf5, 0xf6, 0xce, 0x7c, 0x61, 0x25, 0xb4, 0x6c, 0x5e, 0xdc, 0x4d,
+	0x5d, 0x97, 0xca, 0xdb, 0x90, 0x0f, 0x4c, 0x65, 0x46, 0xa9, 0x70, 0x3e, 0x5a, 0x2a, 0x14, 0xa2,
+	0x79, 0xff, 0x3f, 0x53, 0x50, 0x08, 0x4d, 0x83, 0x7d, 0x04, 0xab, 0xae, 0xfe, 0x48, 0x9b, 0xd8,
+	0x98, 0x08, 0x34, 0x6f, 0x26, 0xb5, 0x31, 0xfd, 0xd1, 0xc4, 0xcc, 0x56, 0xdc, 0x08, 0x84, 0x05,
+	0xd6, 0xda, 0xc0, 0x1a, 0x8d, 0x1f, 0x47, 0x68, 0x8b, 0xc8, 0xfd, 0x56, 0x42, 0xda, 0xbb, 0xb4,
+	0x7b, 0x42, 0xbd, 0x34, 0x88, 0xc1, 0xca, 0x17, 0x29, 0x58, 0x89, 0x1e, 0x4f, 0x42, 0xe8, 0x39,
+	0x63, 0xfe, 0x01, 0x69, 0x95, 0x86, 0xe4, 0xdc, 0x87, 0xe8, 0x8d, 0xdc, 0x63, 0x7e, 0x72, 0x5a,
+	0x95, 0x10, 0x59, 0x9d, 0x61, 0x61, 0x4a, 0x92, 0xe6, 0x58, 0x3e, 0x26, 0x9c, 0x65, 0x3b, 0x1e,
+	0xf7, 0x73, 0x88, 0xa3, 0x31, 0x53, 0x21, 0x2f, 0xc5, 0x4e, 0x77, 0x2f, 0x3d, 0x7f, 0x6a, 0x10,
+	0x30, 0xa7, 0x86, 0x74, 0x94, 0x3f, 0x2c, 0xc1, 0xda, 0x89, 0x59, 0xe2, 0x53, 0x5c, 0x88, 0x20,
+	0x30, 0x0a, 0x88, 0x78, 0xea, 0x59, 0x46, 0x90, 0x7d, 0xf2, 0x31, 0x77, 0x5b, 0x8e, 0xcc, 0x0c,
+	0x71, 0x44, 0x8a, 0x1e, 0x1e, 0x5a, 0xbe, 0x60, 0x3c, 0xab, 0x0a, 0x80, 0xdd, 0x81, 0x12, 0x8a,
+	0xdd, 0x74, 0x1f, 0x9a, 0x86, 0xe6, 0xd8, 0xae, 0x1f, 0xf0, 0xbf, 0x39, 0x1f, 0xff, 0xfb, 0xb8,
+	0x55, 0x5d, 0x0d, 0x28, 0x11, 0xe4, 0x61, 0xe9, 0xb3, 0x6a, 0x1c, 0xe3, 0xad, 0xb0, 0x7a, 0x92,
+	0x72, 0x6e, 0x61, 0xca, 0x2b, 0x92, 0x10, 0x27, 0xac, 0xa8, 0x50, 0x8c, 0x4c, 0xd2, 0x87, 0x0d,
+	0xf4, 0x43, 0x73, 0x20, 0x65, 0x22, 0x80, 0xb8, 0x5d, 0x67, 0xa5, 0x5d, 0x53, 0x6a, 0x71, 0x64,
+	0x63, 0xe8, 0x08, 0x25, 0x93, 0x23, 0xb0, 0xe9, 0x28, 0x9f, 0xa5, 0xa1, 0x14, 0xb7, 0x23, 0xf6,
+	0x0d, 0x0c, 0x6b, 0xce, 0x58, 0x73, 0x4c, 0xd7, 0xb2, 0x0d, 0x69, 0x2d, 0x05, 0xc4, 0xec, 0x73,
+	0x04, 0x79, 0x1f, 0x9a, 0xfe, 0x74, 0x6c, 0xfb, 0xba, 0x34, 0x9b, 0x3c, 0x22, 0xbe, 0x43, 0x70,
+	0xb0, 0x97, 0x97, 0xd7, 0x9e, 0x34, 0x1f, 0x5a, 0xde, 0xe1, 0x08, 0xf6, 0x2a, 0x30, 0x61, 0x61,
+	0xda, 0xc0, 0x1a, 0x5a, 0xbe, 0x76, 0x78, 0x4c, 0x7d, 0x0c, 0x61, 0x51, 0x65, 0x31, 0xb3, 0x4b,
+	0x13, 0x37, 0x08, 0xcf, 0x14, 0x58, 0xb5, 0xed, 0xa1, 0xe6, 0xa1, 0xc8, 0x4c, 0x4d, 0x37, 0xee,
+	0x73, 0xf7, 0x9e, 0x56, 0x8b, 0x88, 0xec, 0x10, 0xae, 0x66, 0xdc, 0x27, 0x6f, 0x86, 0xe4, 0x3d,
+	0x13, 0xa3, 0x22, 0xfe, 0xe3, 0xa1, 0x13, 0xbd, 0x99, 0x40, 0xd5, 0xf1, 0x6f, 0x64, 0x01, 0xd2,
+	0xf7, 0x78, 0xb0, 0x0c, 0x17, 0xec, 0x21, 0x06, 0x4f, 0x59, 0xc1, 0x2f, 0xeb, 0x61, 0xde, 0xd7,
+	0xb5, 0x7a, 0x68, 0xc7, 0xe4, 0x69, 0x53, 0x6a, 0x0c, 0x47, 0xdf, 0x6c, 0xd9, 0xda, 0x23, 0xd3,
+	0xea, 0x1f, 0xf9, 0xdc, 0xe3, 0xe2, 0x37, 0x5b, 0xf6, 0x6d, 0x0e, 0xb3, 0x5b, 0x7c, 0x92, 0x7f,
+	0x90, 0x87, 0xfe, 0x96, 0x74, 0x5d, 0x4d, 0xa8, 0xeb, 0x66, 0x9b, 0x7f, 0x2e, 0x11, 0xe3, 0x03,
+	0x4f, 0xf9, 0x18, 0xb2, 0x3c, 0x92, 0xd0, 0x91, 0xdc, 0x0b, 0x73, 0x27, 0x2d, 0x34, 0x9c, 0x27,
+	0x04, 0x77, 0xd1, 0x38, 0xc9, 0xd5, 0xc9, 0x27, 0x85, 0xf1, 0xe7, 0x09, 0xc1, 0x27, 0x2b, 0x90,
+	0xc7, 0x34, 0xc0, 0xb0, 0x47, 0x83, 0x63, 0xae, 0x81, 0xbc, 0x1a, 0xc2, 0xca, 0xa7, 0x90, 0x13,
+	0x11, 0xe6, 0x14, 0xf4, 0xb1, 0xec, 0xe9, 0x89, 0xd8, 0x80, 0x26, 0x32, 0xb4, 0x3c, 0x0f, 0x9d,
+	0xad, 0x17, 0x34, 0x6e, 0xc4, 0xcc, 0xfe, 0x64, 0x42, 0xf9, 0x47, 0x4a, 0x64, 0x15, 0xa2, 0xa4,
+	0xa6, 0xbc, 0x51, 0xa6, 0x08, 0x0b, 0xf7, 0x1d, 0x24, 0x81, 0x20, 0xf7, 0x37, 0x65, 0x83, 0x6a,
+	0xde, 0xdc, 0xdf, 0x14, 0xb9, 0xbf, 0x49, 0x79, 0x9a, 0x4c, 0x5e, 0x04, 0x39, 0x91, 0xbb, 0x14,
+	0x8d, 0xb0, 0x28, 0x32, 0x95, 0xff, 0xa6, 0x42, 0xa7, 0x14, 0x14, 0x2f, 0xe8, 0xbf, 0xf3, 0x74,
+	0xbf, 0xb5, 0xa1, 0xee, 0xc8, 0x56, 0x5c, 0x7d, 0xb1, 0xba, 0xa8, 0x4a, 0xd7, 0x79, 0x4f, 0x77,
+	0x44, 0x56, 0xb3, 0xec, 0x08, 0x88, 0x9c, 0x9b, 0x6e, 0x4c, 0x9c, 0x1b, 0x8d, 0xd9, 0x4b, 0x50,
+	0xd2, 0xc7, 0xbe, 0x8d, 0xb7, 0x01, 0xf7, 0xfa, 0x96, 0x67, 0x4a, 0x0d, 0xaf, 0x12, 0xb6, 0x16,
+	0x20, 0x2b, 0xd7, 0xd0, 0xa6, 0x23, 0x34, 0x9f, 0x16, 0xfe, 0xb2, 0xd1, 0xf0, 0xf7, 0x7d, 0x80,
+	0x49, 0x8e, 0x4e, 0x96, 0x60, 0x22, 0x84, 0x69, 0x9d, 0x21, 0x9c, 0x6f, 0x56, 0xcd, 0x13, 0xa2,
+	0x8e, 0xf0, 0x89, 0x8a, 0x27, 0x1b, 0x54, 0x3c, 0xe4, 0x05, 0xe8, 0xe2, 0x3e, 0xb0, 0x06, 0x03,
+	0xd3, 0x90, 0x1c, 0x16, 0x10, 0x73, 0x8b, 0x23, 0x94, 0x2f, 0x97, 0x84, 0x45, 0x88, 0x7a, 0x33,
+	0x51, 0x9e, 0x19, 0xaa, 0x3a, 0x7d, 0x3a, 0x55, 0x5f, 0x05, 0xcc, 0x74, 0x74, 0xd7, 0x47, 0xaf,
+	0xaf, 0xfb, 0xb2, 0x85, 0x53, 0x99, 0x2a, 0x99, 0xba, 0x41, 0xdb, 0x5c, 0x2d, 0xc8, 0xd5, 0x35,
+	0x9f, 0xbd, 0x0b, 0x2b, 0x58, 0x75, 0x39, 0x03, 0x53, 0x6e, 0xce, 0x3e, 0x75, 0x73, 0x31, 0x5c,
+	0x8f, 0xdb, 0x27, 0xf5, 0x52, 0xee, 0xb4, 0xf5, 0xd2, 0xdf, 0x52, 0xa2, 0x6c, 0x8e, 0x56, 0xed,
+	0xac, 0x3f, 0xa3, 0x35, 0x7c, 0x73, 0xc1, 0x16, 0xc0, 0x57, 0xf5, 0x85, 0x2b, 0xef, 0x26, 0x69,
+	0xc4, 0x3e, 0x39, 0xbb, 0xfa, 0x7b, 0x1a, 0x0a, 0x61, 0xf5, 0x3d, 0xa5, 0xfb, 0x2b, 0xe8, 0x95,
+	0x02, 0xf9, 0xc9, 0x6c, 0xe8, 0x2b, 0xd5, 0x13, 0x2e, 0x66, 0xf7, 0x80, 0xe9, 0xfd, 0x7e, 0x98,
+	0x4b, 0x69, 0x63, 0x4f, 0xef, 0x07, 0xfd, 0x8a, 0x2b, 0x73, 0xc8, 0x21, 0x88, 0x83, 0x07, 0xb4,
+	0x5f, 0x2d, 0x23, 0xcd, 0x18, 0x86, 0xfd, 0x10, 0x2e, 0xc4, 0xcf, 0xc0, 0x20, 0xa6, 0x39, 0xf8,
+	0x11, 0xa2, 0x9e, 0xd9, 0x99, 0xb7, 0x01, 0x51, 0x8d, 0x91, 0xbf, 0x71, 0xbc, 0x6f, 0x19, 0x42,
+	0xe6, 0xcc, 0x9d, 0x9a, 0xa8, 0xfc, 0x18, 0x9e, 0x7f, 0xc2, 0xf2, 0x19, 0x3a, 0x68, 0xc5, 0x9b,
+	0xe1, 0x8b, 0x0b, 0x21, 0xa2, 0xbd, 0xff, 0xa4, 0x44, 0x9f, 0x24, 0x2e, 0x93, 0xda, 0x24, 0xb1,
+	0x2c, 0x6e, 0x6e, 0x24, 0x3c, 0xa7, 0xbe, 0x7f, 0x20, 0xc8, 0xf3, 0x4c, 0xf4, 0xc3, 0x58, 0x26,
+	0x9a, 0x3c, 0x5b, 0xda, 0xe3, 0x9b, 0x04, 0xa1, 0x20, 0x7b, 0x7d, 0x0f, 0x8d, 0xca, 0x96, 0xaa,
+	0x4f, 0x1e, 0x89, 0x05, 0x0d, 0xdc, 0xa9, 0xfc, 0x39, 0x0d, 0xf9, 0x80, 0x3b, 0x5e, 0x2e, 0x1d,
+	0x7b, 0xbe, 0x39, 0xd4, 0x86, 0x81, 0x0b, 0x4c, 0x61, 0xb9, 0xc4, 0x51, 0x7b, 0xe4, 0x04, 0xd1,
+	0x43, 0x52, 0x55, 0x26, 0xa6, 0x97, 0xf8, 0x74, 0x9e, 0x10, 0x7c, 0x12, 0x77, 0xfb, 0x98, 0x17,
+	0x0d, 0x34, 0x9f, 0xe7, 0x16, 0x69, 0xb1, 0x9b, 0xa3, 0x44, 0x66, 0xf1, 0x0a, 0x9c, 0xf5, 0x8f,
+	0x90, 0x03, 0x7f, 0x40, 0x89, 0x28, 0xcf, 0xb0, 0x44, 0x42, 0x94, 0x51, 0xcb, 0xe1, 0x84, 0xc8,
+	0xbc, 0x3c, 0xf2, 0xfe, 0x93, 0xc5, 0x64, 0xfa, 0xdc, 0x09, 0x65, 0xb0, 0x30, 0x0b, 0xb0, 0x74,
+	0x35, 0xa8, 0x5d, 0xe4, 0x88, 0xec, 0x85, 0xfb, 0x9a, 0x94, 0x1a, 0x80, 0x4c, 0x83, 0xb5, 0xa1,
+	0xa9, 0x7b, 0x63, 0x17, 0xf7, 0xdf, 0xb3, 0xcc, 0x81, 0x21, 0xca, 0xd3, 0x52, 0xe2, 0xb4, 0x3d,
+	0x10, 0x4b, 0x75, 0x9b, 0xef, 0x56, 0x4b, 0x01, 0x39, 0x01, 0x53, 0x7e, 0x21, 0x46, 0x6c, 0x0d,
+	0x8a, 0x9d, 0x3b, 0x9d, 0x6e, 0x63, 0x4f, 0xdb, 0x6b, 0x6f, 0x35, 0xe4, 0xef, 0x25, 0x9d, 0x86,
+	0x2a, 0xc0, 0x14, 0xcd, 0x77, 0xdb, 0xdd, 0xda, 0xae, 0xd6, 0x6d, 0xd6, 0x6f, 0x75, 0xca, 0x4b,
+	0xec, 0x02, 0x5a, 0xd6, 0x8e, 0xda, 0xee, 0x76, 0x77, 0x1b, 0x5b, 0xda, 0x7e, 0x43, 0x6d, 0xb6,
+	0xb7, 0x3a, 0xe5, 0x34, 0x46, 0x83, 0xd2, 0x04, 0xdd, 0x6d, 0xee, 0x35, 0xca, 0x19, 0xea, 0x90,
+	0xe3, 0x82, 0x7a, 0xa3, 0xd5, 0x2d, 0x67, 0x95, 0x2f, 0xd2, 0x50, 0x8c, 0x58, 0x01, 0x5d, 0x04,
+	0xd7, 0x13, 0x65, 0x5a, 0x46, 0xa5, 0x21, 0x39, 0xa3, 0x9e, 0xde, 0x3b, 0x12, 0xda, 0xc9, 0xa8,
+	0x02, 0x20, 0xbd, 0x0d, 0xf5, 0xc7, 0x11, 0x3f, 0x91, 0x51, 0xf3, 0x88, 0x10, 0x44, 0x30, 0x25,
+	0x78, 0x60, 0xba, 0x23, 0x73, 0x20, 0xe7, 0x85, 0x46, 0x8a, 0x02, 0x27, 0x96, 0x5c, 0x82, 0xb2,
+	0x5c, 0x32, 0x21, 0x23, 0xd4, 0x51, 0x12, 0xf8, 0xbd, 0x80, 0x18, 0x9e, 0x2f, 0xa6, 0x97, 0xc5,
+	0xf9, 0x1c, 0x20, 0x65, 0x1e, 0x52, 0x51, 0x6c, 0x8f, 0x34, 0xbd, 0xe7, 0x8f, 0x31, 0x88, 0xe6,
+	0x85, 0x32, 0x25, 0xb6, 0xc6, 0x91, 0xec, 0x70, 0x5a, 0x65, 0x39, 0xae, 0xb2, 0xab, 0xf3, 0xdf,
+	0x90, 0x27, 0x69, 0xcd, 0x0d, 0xb5, 0xb6, 0x0c, 0x69, 0x35, 0xf8, 0xdd, 0xa1, 0x5e, 0xab, 0xef,
+	0x90, 0xa6, 0x50, 0x71, 0x7b, 0xb5, 0x8f, 0xb4, 0x83, 0x0e, 0xef, 0x12, 0xa1, 0x7c, 0x57, 0x6e,
+	0x35, 0xd4, 0x56, 0x63, 0x57, 0x62, 0xd2, 0xf8, 0x7d, 0x65, 0x89, 0x99, 0xac, 0xcb, 0x10, 0x05,
+	0x31, 0xcc, 0x92, 0x0e, 0x6f, 0xd4, 0x76, 0x77, 0xdb, 0xed, 0x96, 0x56, 0xab, 0x77, 0x0f, 0x6a,
+	0xbb, 0xe5, 0x9c, 0xf2, 0x27, 0x2c, 0xf3, 0x44, 0xcc, 0x09, 0xfb, 0xae, 0x4f, 0x6e, 0x80, 0x2e,
+	0x1e, 0x16, 0xf0, 0x2e, 0xe0, 0x30, 0xd4, 0x71, 0x41, 0x0d, 0x40, 0x66, 0x41, 0x51, 0x1f, 0x8d,
+	0xf0, 0x26, 0xfa, 0x3c, 0x7f, 0xcd, 0xcc, 0x15, 0x31, 0x4f, 0x70, 0x5e, 0xad, 0x4d, 0x28, 0x09,
+	0xef, 0x1d, 0xa5, 0x5d, 0x79, 0x0f, 0xca, 0x27, 0x17, 0xcc, 0x15, 0x33, 0x77, 0xe0, 0xeb, 0x41,
+	0xbf, 0xb6, 0xe3, 0x63, 0x2e, 0x3f, 0xb4, 0x46, 0xfd, 0x66, 0xbb, 0x8d, 0xb7, 0x5a, 0x74, 0xf6,
+	0xa8, 0x5c, 0xd7, 0xb1, 0x1a, 0x13, 0x9d, 0x5b, 0x3e, 0xe6, 0x46, 0x3f, 0xb0, 0xbd, 0xf0, 0x97,
+	0x0c, 0x0e, 0x28, 0xff, 0x4e, 0xc3, 0xfa, 0x14, 0xa9, 0xa0, 0xff, 0x7c, 0x17, 0x93, 0x2c, 0xd3,
+	0x1f, 0x3b, 0xd2, 0x91, 0x37, 0x12, 0x67, 0x28, 0xb3, 0xe9, 0x55, 0x3b, 0x44, 0x4c, 0x15, 0x34,
+	0x31, 0x3f, 0xc9, 0xfb, 0xfe, 0xb1, 0xe6, 0x59, 0x3f, 0x08, 0x02, 0xd2, 0xee, 0x69, 0xe9, 0x77,
+	0xa9, 0xca, 0xc0, 0x7c, 0xb3, 0x83, 0x34, 0xd5, 0x65, 0xa4, 0x4e, 0x03, 0xac, 0xec, 0x31, 0xd7,
+	0x33, 0xac, 0x91, 0x0c, 0x00, 0xf5, 0x45, 0x4f, 0x89, 0x08, 0x58, 0x15, 0x14, 0x2b, 0xbb, 0x90,
+	0xe5, 0xdf, 0xb4, 0x48, 0xa7, 0x1e, 0xf5, 0x8d, 0x1c, 0xca, 0x64, 0x98, 0x86, 0x95, 0xeb, 0xb0,
+	0x12, 0xfd, 0x02, 0xca, 0xa6, 0x8f, 0x44, 0x85, 0x29, 0xf2, 0x6c, 0x09, 0x91, 0x26, 0x1f, 0x59,
+	0x86, 0x2c, 0xc4, 0x30, 0x55, 0xe7, 0x80, 0xf2, 0xd7, 0x25, 0xb8, 0x38, 0x43, 0x32, 0xb2, 0x9b,
+	0x7f, 0x37, 0xd6, 0xcd, 0x7f, 0x46, 0x52, 0x08, 0x7e, 0x12, 0xb8, 0x1b, 0xfb, 0x49, 0xe0, 0x19,
+	0x12, 0xa7, 0xdf, 0x15, 0x50, 0x0a, 0x54, 0x5f, 0x84, 0x75, 0x83, 0x84, 0x22, 0xf9, 0x73, 0xe6,
+	0xb4, 0xf9, 0xf3, 0xeb, 0x70, 0xa1, 0x1e, 0x76, 0xe9, 0x13, 0xfd, 0x74, 0x77, 0x1d, 0x9e, 0x3b,
+	0xb9, 0x43, 0x0a, 0x5a, 0xc1, 0xaa, 0x20, 0x9c, 0x31, 0x0d, 0xf9, 0x23, 0x45, 0x0c, 0xa7, 0x7c,
+	0x9e, 0x82, 0x65, 0x59, 0xe9, 0x3f, 0xb1, 0x93, 0x75, 0x51, 0x14, 0xed, 0xda, 0xa1, 0xe3, 0xc9,
+	0xa6, 0xca, 0x32, 0xc1, 0x37, 0x1c, 0xde, 0x7c, 0x78, 0xe4, 0xa2, 0x0c, 0xf8, 0x9c, 0x68, 0xa9,
+	0xe4, 0x39, 0x42, 0x4e, 0xf2, 0x7d, 0x91, 0xd6, 0x1c, 0x27, 0xd4, 0xa4, 0xf6, 0x1c, 0xd6, 0x61,
+	0x62, 0x27, 0x9f, 0x15, 0xdd, 0x13, 0x41, 0x8b, 0xa6, 0xc9, 0x05, 0x2f, 0xcb, 0xbc, 0x87, 0x96,
+	0x8a, 0xf3, 0x8f, 0x7d, 0x33, 0x08, 0x9e, 0x9c, 0xb2, 0x68, 0xc5, 0x60, 0x1e, 0x23, 0x79, 0xe0,
+	0xf3, 0x22, 0x90, 0x0a, 0xe2, 0x62, 0x41, 0xc0, 0xbf, 0x2d, 0x79, 0xcc, 0x08, 0xfe, 0xdb, 0x51,
+	0xfe, 0x03, 0x16, 0x33, 0x92, 0x7f, 0x9a, 0xfc, 0x64, 0x3a, 0xbc, 0x65, 0x79, 0x78, 0x7b, 0x6b,
+	0xbe, 0xc4, 0xed, 0x49, 0xa1, 0x6d, 0x3b, 0x0c, 0x6d, 0x25, 0x00, 0xb5, 0x51, 0xdb, 0xd2, 0x6e,
+	0xdc, 0xe9, 0x36, 0x28, 0xc2, 0x61, 0x02, 0x72, 0x5b, 0x6d, 0x76, 0x1b, 0x12, 0x91, 0x62, 0x2b,
+	0x90, 0xe7, 0x0b, 0xda, 0xfb, 0x94, 0x8e, 0x60, 0xd4, 0x13, 0xd3, 0x04, 0xa6, 0x95, 0x7f, 0xa5,
+	0xe0, 0xfc, 0xac, 0xbe, 0x33, 0xf9, 0xde, 0x48, 0x0b, 0x85, 0x8f, 0x31, 0xcd, 0xca, 0xf1, 0x66,
+	0x9c, 0xc7, 0x9d, 0x40, 0xf2, 0xa8, 0x32, 0xeb, 0x80, 0xea, 0x2e, 0xa7, 0x24, 0xa2, 0x8a, 0x24,
+	0x5b, 0xb9, 0x0a, 0xc5, 0x08, 0x7a, 0xae, 0x58, 0xf2, 0x06, 0x9c, 0x8f, 0xfd, 0x4a, 0x14, 0xd8,
+	0x7e, 0xb4, 0x65, 0x9f, 0x8a, 0xb5, 0xec, 0xc9, 0x7e, 0x2f, 0x9c, 0xd8, 0x23, 0xad, 0xff, 0x10,
+	0x4a, 0x61, 0x13, 0x5f, 0x8b, 0xbc, 0xcb, 0x3a, 0x55, 0x27, 0x7f, 0xd5, 0x8a, 0x09, 0x98, 0x5c,
+	0x2a, 0x3f, 0xdc, 0x90, 0xa1, 0x2c, 0x00, 0x95, 0xdf, 0x23, 0x5f, 0xf2, 0x77, 0xf9, 0xc4, 0x1f,
+	0x33, 0x83, 0xe5, 0xa5, 0x67, 0xcd, 0xb2, 0xb2, 0x0e, 0xcf, 0x9d, 0xe4, 0x4b, 0x08, 0xec, 0xe5,
+	0x37, 0x26, 0xc5, 0xaf, 0x49, 0x69, 0xec, 0x41, 0xeb, 0x56, 0xab, 0x7d, 0xbb, 0x85, 0xe6, 0x88,
+	0x80, 0x7a, 0xd0, 0x6a, 0x35, 0x5b, 0x37, 0xd1, 0x14, 0x01, 0x72, 0x8d, 0x8f, 0x9a, 0xf4, 0xce,
+	0x68, 0x69, 0xf3, 0x2f, 0x0c, 0x72, 0x22, 0xdd, 0x60, 0x9f, 0xcb, 0xc2, 0x3f, 0xfa, 0x32, 0x8e,
+	0xbd, 0x37, 0x77, 0x03, 0x2d, 0xf6, 0xda, 0xae, 0xf2, 0xfe, 0xc2, 0xfb, 0xe5, 0x2f, 0xd9, 0x67,
+	0xd8, 0xaf, 0x53, 0xb0, 0x12, 0xfb, 0xe9, 0x36, 0xe9, 0xaf, 0x7a, 0x33, 0x1e, 0xe2, 0x55, 0xde,
+	0x59, 0x68, 0x6f, 0xc8, 0xcb, 0xaf, 0x52, 0x50, 0x8c, 0x3c, 0x41, 0x63, 0x57, 0x17, 0x79, 0xb6,
+	0x26, 0x38, 0xb9, 0xb6, 0xf8, 0x8b, 0x37, 0xe5, 0xcc, 0xeb, 0x29, 0xf6, 0x4b, 0x64, 0x25, 0xf2,
+	0x18, 0x2b, 0x31, 0x2b, 0xd3, 0x4f, 0xc7, 0x12, 0xb3, 0x32, 0xeb, 0xed, 0xd7, 0x19, 0xf6, 0x93,
+	0x14, 0x14, 0xc2, 0x87, 0x55, 0xec, 0xf2, 0xfc, 0x4f, 0xb1, 0x04, 0x13, 0x57, 0x16, 0x7d, 0xc3,
+	0x85, 0x2c, 0xfc, 0x08, 0xf2, 0xc1, 0x2b, 0x24, 0x96, 0xb4, 0xd8, 0x3c, 0xf1, 0xc4, 0xa9, 0x72,
+	0x79, 0xee, 0x7d, 0xd1, 0xe3, 0x83, 0xa7, 0x41, 0x89, 0x8f, 0x3f, 0xf1, 0x88, 0xa9, 0x72, 0x79,
+	0xee, 0x7d, 0xe1, 0xf1, 0x64, 0x09, 0x91, 0x17, 0x44, 0x89, 0x2d, 0x61, 0xfa, 0xe9, 0x52, 0x62,
+	0x4b, 0x98, 0xf5, 0x60, 0x49, 0x30, 0x12, 0x79, 0x83, 0x94, 0x98, 0x91, 0xe9, 0x77, 0x4e, 0x89,
+	0x19, 0x99, 0xf1, 0xe4, 0x49, 0x9a, 0xe4, 0xa4, 0x0d, 0x78, 0x79, 0xee, 0x67, 0x3b, 0x73, 0x9a,
+	0xe4, 0xd4, 0xc3, 0x21, 0x64, 0xe1, 0xa7, 0xf2, 0x87, 0x09, 0xf1, 0xe6, 0x87, 0xcd, 0x43, 0x2a,
+	0xf6, 0x4c, 0xa8, 0xf2, 0xf6, 0x62, 0x45, 0x23, 0xf7, 0x11, 0x3f, 0x43, 0x26, 0x26, 0xaf, 0x83,
+	0x12, 0x33, 0x31, 0xf5, 0x2c, 0xa9, 0x72, 0x75, 0x81, 0x9d, 0xd1, 0xeb, 0x11, 0x24, 0xe7, 0x89,
+	0xaf, 0xc7, 0x89, 0xd7, 0x4b, 0x89, 0xaf, 0xc7, 0xc9, 0x97, 0x47, 0x78, 0xfc, 0x1f, 0x53, 0x70,
+	0x76, 0xaa, 0x38, 0x60, 0xef, 0x9f, 0xb2, 0x3e, 0xac, 0x7c, 0xb0, 0x38, 0x81, 0x80, 0xb5, 0x4b,
+	0x29, 0xd4, 0xd1, 0x6f, 0x53, 0x50, 0x8a, 0xa7, 0xff, 0xec, 0x7a, 0xd2, 0x20, 0x35, 0xab, 0xce,
+	0xa8, 0xbc, 0xbb, 0xe0, 0xee, 0x50, 0x60, 0xbf, 0x49, 0xc1, 0x6a, 0x2c, 0x23, 0x63, 0x89, 0xa3,
+	0xe6, 0x8c, 0xdc, 0xaf, 0x72, 0x7d, 0xb1, 0xcd, 0x21, 0x3b, 0x24, 0xa0, 0x78, 0xc2, 0x93, 0x58,
+	0x40, 0x33, 0xf3, 0xb7, 0xc4, 0x02, 0x9a, 0x9d, 0x65, 0x29, 0x67, 0x6e, 0x2c, 0x7f, 0x37, 0x2b,
+	0xfa, 0x42, 0x39, 0xfe, 0xef, 0xcd, 0xff, 0x03, 0x91, 0xec, 0x52, 0x7a, 0x86, 0x31, 0x00, 0x00,
 }
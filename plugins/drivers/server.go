@@ -3,6 +3,7 @@ package drivers
 import (
 	"fmt"
 	"io"
+	"sync"
 
 	"github.com/golang/protobuf/ptypes"
 	hclog "github.com/hashicorp/go-hclog"
@@ -42,8 +43,12 @@ func (b *driverPluginServer) Capabilities(ctx context.Context, req *proto.Capabi
 	}
 	resp := &proto.CapabilitiesResponse{
 		Capabilities: &proto.DriverCapabilities{
-			SendSignals: caps.SendSignals,
-			Exec:        caps.Exec,
+			SendSignals:       caps.SendSignals,
+			Exec:              caps.Exec,
+			Checkpoint:        caps.Checkpoint,
+			RemoteTasks:       caps.RemoteTasks,
+			NetworkIsolation:  caps.NetworkIsolation,
+			MustCreateNetwork: caps.MustCreateNetwork,
 		},
 	}
 
@@ -261,6 +266,198 @@ func (b *driverPluginServer) ExecTask(ctx context.Context, req *proto.ExecTaskRe
 	return resp, nil
 }
 
+func (b *driverPluginServer) ExecTaskStreaming(srv proto.Driver_ExecTaskStreamingServer) error {
+	impl, ok := b.impl.(ExecTaskStreamingDriver)
+	if !ok {
+		return status.Error(codes.Unimplemented, "ExecTaskStreaming is not supported by this driver")
+	}
+
+	msg, err := srv.Recv()
+	if err != nil {
+		return err
+	}
+	setup := msg.Setup
+	if setup == nil {
+		return status.Error(codes.InvalidArgument, "first exec stream message must contain setup")
+	}
+
+	ctx, cancel := context.WithCancel(srv.Context())
+	defer cancel()
+
+	// grpc streams may not be written to concurrently
+	var sendLock sync.Mutex
+	send := func(resp *proto.ExecTaskStreamingResponse) error {
+		sendLock.Lock()
+		defer sendLock.Unlock()
+		return srv.Send(resp)
+	}
+
+	stdinReader, stdinWriter := io.Pipe()
+	resizeCh := make(chan TerminalSize, 1)
+	go b.execStreamingRecv(ctx, srv, stdinWriter, resizeCh)
+
+	opts := &ExecOptions{
+		Command:  setup.Command,
+		Tty:      setup.Tty,
+		Stdin:    stdinReader,
+		Stdout:   &execStreamingWriter{send: send},
+		Stderr:   &execStreamingWriter{send: send, stderr: true},
+		ResizeCh: resizeCh,
+	}
+
+	result, err := impl.ExecTaskStreaming(ctx, setup.TaskId, opts)
+	stdinReader.Close()
+	if err != nil {
+		return err
+	}
+
+	return send(&proto.ExecTaskStreamingResponse{
+		Exited: true,
+		Result: exitResultToProto(result),
+	})
+}
+
+// execStdinBuffer is the number of stdin messages of an exec session queued
+// while the driver isn't reading stdin, beyond which the session stops
+// receiving messages.
+const execStdinBuffer = 32
+
+// execStreamingRecv consumes the requests of an exec session after the setup
+// message, passing stdin data and terminal resizes on to the driver. Writes to
+// stdin block until the driver reads them, so they are made by their own
+// goroutine to keep handling resizes meanwhile.
+func (b *driverPluginServer) execStreamingRecv(ctx context.Context, srv proto.Driver_ExecTaskStreamingServer,
+	stdin *io.PipeWriter, resizeCh chan TerminalSize) {
+
+	var recvErr error
+	stdinCh := make(chan *proto.ExecTaskStreamingIOOperation, execStdinBuffer)
+	defer close(stdinCh)
+	go func() {
+		for op := range stdinCh {
+			if len(op.Data) > 0 {
+				if _, err := stdin.Write(op.Data); err != nil {
+					// The session ended, drop the remaining stdin
+					b.logger.Trace("failed to write exec stdin", "error", err)
+				}
+			}
+			if op.Close {
+				stdin.Close()
+			}
+		}
+
+		// The stream ended, close stdin once the queued data is written
+		stdin.CloseWithError(recvErr)
+	}()
+
+	for {
+		msg, err := srv.Recv()
+		if err != nil {
+			if err != io.EOF {
+				b.logger.Debug("failed to receive exec stream message", "error", err)
+			}
+			recvErr = err
+			return
+		}
+
+		if msg.Stdin != nil {
+			select {
+			case stdinCh <- msg.Stdin:
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		if msg.TtySize != nil {
+			size := TerminalSize{
+				Height: int(msg.TtySize.Height),
+				Width:  int(msg.TtySize.Width),
+			}
+
+			// Only the latest size matters, so replace a size the driver
+			// has not consumed yet rather than blocking behind it.
+			select {
+			case <-resizeCh:
+			default:
+			}
+			resizeCh <- size
+		}
+	}
+}
+
+// execStreamingWriter sends the data written to it as stdout or stderr frames
+// of an exec session.
+type execStreamingWriter struct {
+	send   func(*proto.ExecTaskStreamingResponse) error
+	stderr bool
+}
+
+func (w *execStreamingWriter) Write(p []byte) (int, error) {
+	data := make([]byte, len(p))
+	copy(data, p)
+	if err := w.send(w.response(&proto.ExecTaskStreamingIOOperation{Data: data})); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (w *execStreamingWriter) Close() error {
+	return w.send(w.response(&proto.ExecTaskStreamingIOOperation{Close: true}))
+}
+
+func (w *execStreamingWriter) response(op *proto.ExecTaskStreamingIOOperation) *proto.ExecTaskStreamingResponse {
+	if w.stderr {
+		return &proto.ExecTaskStreamingResponse{Stderr: op}
+	}
+	return &proto.ExecTaskStreamingResponse{Stdout: op}
+}
+
+func (b *driverPluginServer) CheckpointTask(ctx context.Context, req *proto.CheckpointTaskRequest) (*proto.CheckpointTaskResponse, error) {
+	impl, ok := b.impl.(CheckpointDriver)
+	if !ok {
+		return nil, status.Error(codes.Unimplemented, "CheckpointTask is not supported by this driver")
+	}
+
+	checkpointed, err := impl.CheckpointTask(req.TaskId)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &proto.CheckpointTaskResponse{
+		Checkpointed: checkpointed,
+	}
+	return resp, nil
+}
+
+func (b *driverPluginServer) CreateNetwork(ctx context.Context, req *proto.CreateNetworkRequest) (*proto.CreateNetworkResponse, error) {
+	impl, ok := b.impl.(DriverNetworkManager)
+	if !ok {
+		return nil, status.Error(codes.Unimplemented, "CreateNetwork is not supported by this driver")
+	}
+
+	spec, created, err := impl.CreateNetwork(req.AllocId)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &proto.CreateNetworkResponse{
+		IsolationSpec: networkIsolationToProto(spec),
+		Created:       created,
+	}
+	return resp, nil
+}
+
+func (b *driverPluginServer) DestroyNetwork(ctx context.Context, req *proto.DestroyNetworkRequest) (*proto.DestroyNetworkResponse, error) {
+	impl, ok := b.impl.(DriverNetworkManager)
+	if !ok {
+		return nil, status.Error(codes.Unimplemented, "DestroyNetwork is not supported by this driver")
+	}
+
+	if err := impl.DestroyNetwork(req.AllocId, networkIsolationFromProto(req.IsolationSpec)); err != nil {
+		return nil, err
+	}
+	return &proto.DestroyNetworkResponse{}, nil
+}
+
 func (b *driverPluginServer) SignalTask(ctx context.Context, req *proto.SignalTaskRequest) (*proto.SignalTaskResponse, error) {
 	err := b.impl.SignalTask(req.TaskId, req.Signal)
 	if err != nil {
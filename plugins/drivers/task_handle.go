@@ -1,6 +1,7 @@
 package drivers
 
 import (
+	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/plugins/base"
 )
 
@@ -41,3 +42,33 @@ func (h *TaskHandle) Copy() *TaskHandle {
 	copy(handle.DriverState, h.DriverState)
 	return handle
 }
+
+// NewTaskHandleFromState returns the handle of a task running on a remote task
+// driver from its task state, or nil if the task state has no handle. The
+// handle's Config must be set before recovering the task.
+func NewTaskHandleFromState(ts *structs.TaskState) *TaskHandle {
+	if ts == nil || ts.TaskHandle == nil {
+		return nil
+	}
+
+	handle := NewTaskHandle(ts.TaskHandle.Driver)
+	handle.State = TaskStateRunning
+	handle.DriverState = make([]byte, len(ts.TaskHandle.DriverState))
+	copy(handle.DriverState, ts.TaskHandle.DriverState)
+	return handle
+}
+
+// StateHandle returns the portion of the handle stored in the task state of
+// tasks running on a remote task driver.
+func (h *TaskHandle) StateHandle() *structs.TaskHandle {
+	if h == nil {
+		return nil
+	}
+
+	th := &structs.TaskHandle{
+		Driver:      h.Driver,
+		DriverState: make([]byte, len(h.DriverState)),
+	}
+	copy(th.DriverState, h.DriverState)
+	return th
+}
@@ -187,19 +187,23 @@ func (h *DriverHarness) WaitUntilStarted(taskID string, timeout time.Duration) e
 // is passed through the base plugin layer.
 type MockDriver struct {
 	base.MockPlugin
-	TaskConfigSchemaF func() (*hclspec.Spec, error)
-	FingerprintF      func(context.Context) (<-chan *Fingerprint, error)
-	CapabilitiesF     func() (*Capabilities, error)
-	RecoverTaskF      func(*TaskHandle) error
-	StartTaskF        func(*TaskConfig) (*TaskHandle, *cstructs.DriverNetwork, error)
-	WaitTaskF         func(context.Context, string) (<-chan *ExitResult, error)
-	StopTaskF         func(string, time.Duration, string) error
-	DestroyTaskF      func(string, bool) error
-	InspectTaskF      func(string) (*TaskStatus, error)
-	TaskStatsF        func(string) (*cstructs.TaskResourceUsage, error)
-	TaskEventsF       func(context.Context) (<-chan *TaskEvent, error)
-	SignalTaskF       func(string, string) error
-	ExecTaskF         func(string, []string, time.Duration) (*ExecTaskResult, error)
+	TaskConfigSchemaF  func() (*hclspec.Spec, error)
+	FingerprintF       func(context.Context) (<-chan *Fingerprint, error)
+	CapabilitiesF      func() (*Capabilities, error)
+	RecoverTaskF       func(*TaskHandle) error
+	StartTaskF         func(*TaskConfig) (*TaskHandle, *cstructs.DriverNetwork, error)
+	WaitTaskF          func(context.Context, string) (<-chan *ExitResult, error)
+	StopTaskF          func(string, time.Duration, string) error
+	DestroyTaskF       func(string, bool) error
+	InspectTaskF       func(string) (*TaskStatus, error)
+	TaskStatsF         func(string) (*cstructs.TaskResourceUsage, error)
+	TaskEventsF        func(context.Context) (<-chan *TaskEvent, error)
+	SignalTaskF        func(string, string) error
+	ExecTaskF          func(string, []string, time.Duration) (*ExecTaskResult, error)
+	ExecTaskStreamingF func(context.Context, string, *ExecOptions) (*ExitResult, error)
+	CheckpointTaskF    func(string) (bool, error)
+	CreateNetworkF     func(string) (*NetworkIsolationSpec, bool, error)
+	DestroyNetworkF    func(string, *NetworkIsolationSpec) error
 }
 
 func (d *MockDriver) TaskConfigSchema() (*hclspec.Spec, error) { return d.TaskConfigSchemaF() }
@@ -233,3 +237,13 @@ func (d *MockDriver) SignalTask(taskID string, signal string) error {
 func (d *MockDriver) ExecTask(taskID string, cmd []string, timeout time.Duration) (*ExecTaskResult, error) {
 	return d.ExecTaskF(taskID, cmd, timeout)
 }
+func (d *MockDriver) ExecTaskStreaming(ctx context.Context, taskID string, opts *ExecOptions) (*ExitResult, error) {
+	return d.ExecTaskStreamingF(ctx, taskID, opts)
+}
+func (d *MockDriver) CheckpointTask(taskID string) (bool, error) { return d.CheckpointTaskF(taskID) }
+func (d *MockDriver) CreateNetwork(allocID string) (*NetworkIsolationSpec, bool, error) {
+	return d.CreateNetworkF(allocID)
+}
+func (d *MockDriver) DestroyNetwork(allocID string, spec *NetworkIsolationSpec) error {
+	return d.DestroyNetworkF(allocID, spec)
+}
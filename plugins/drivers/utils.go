@@ -50,20 +50,21 @@ func taskConfigFromProto(pb *proto.TaskConfig) *TaskConfig {
 		return &TaskConfig{}
 	}
 	return &TaskConfig{
-		ID:              pb.Id,
-		JobName:         pb.JobName,
-		TaskGroupName:   pb.TaskGroupName,
-		Name:            pb.Name,
-		Env:             pb.Env,
-		rawDriverConfig: pb.MsgpackDriverConfig,
-		Resources:       resourcesFromProto(pb.Resources),
-		Devices:         devicesFromProto(pb.Devices),
-		Mounts:          mountsFromProto(pb.Mounts),
-		User:            pb.User,
-		AllocDir:        pb.AllocDir,
-		StdoutPath:      pb.StdoutPath,
-		StderrPath:      pb.StderrPath,
-		AllocID:         pb.AllocId,
+		ID:               pb.Id,
+		JobName:          pb.JobName,
+		TaskGroupName:    pb.TaskGroupName,
+		Name:             pb.Name,
+		Env:              pb.Env,
+		rawDriverConfig:  pb.MsgpackDriverConfig,
+		Resources:        resourcesFromProto(pb.Resources),
+		Devices:          devicesFromProto(pb.Devices),
+		Mounts:           mountsFromProto(pb.Mounts),
+		User:             pb.User,
+		AllocDir:         pb.AllocDir,
+		StdoutPath:       pb.StdoutPath,
+		StderrPath:       pb.StderrPath,
+		AllocID:          pb.AllocId,
+		NetworkIsolation: networkIsolationFromProto(pb.NetworkIsolation),
 	}
 }
 
@@ -86,10 +87,31 @@ func taskConfigToProto(cfg *TaskConfig) *proto.TaskConfig {
 		StdoutPath:          cfg.StdoutPath,
 		StderrPath:          cfg.StderrPath,
 		AllocId:             cfg.AllocID,
+		NetworkIsolation:    networkIsolationToProto(cfg.NetworkIsolation),
 	}
 	return pb
 }
 
+func networkIsolationFromProto(pb *proto.NetworkIsolationSpec) *NetworkIsolationSpec {
+	if pb == nil {
+		return nil
+	}
+	return &NetworkIsolationSpec{
+		Path:   pb.Path,
+		Labels: pb.Labels,
+	}
+}
+
+func networkIsolationToProto(spec *NetworkIsolationSpec) *proto.NetworkIsolationSpec {
+	if spec == nil {
+		return nil
+	}
+	return &proto.NetworkIsolationSpec{
+		Path:   spec.Path,
+		Labels: spec.Labels,
+	}
+}
+
 func resourcesFromProto(pb *proto.Resources) *Resources {
 	var r Resources
 	if pb == nil {
@@ -112,14 +134,16 @@ func resourcesFromProto(pb *proto.Resources) *Resources {
 			n.MBits = int(network.Mbits)
 			for _, port := range network.ReservedPorts {
 				n.ReservedPorts = append(n.ReservedPorts, structs.Port{
-					Label: port.Label,
-					Value: int(port.Value),
+					Label:  port.Label,
+					Value:  int(port.Value),
+					HostIP: port.HostIp,
 				})
 			}
 			for _, port := range network.DynamicPorts {
 				n.DynamicPorts = append(n.DynamicPorts, structs.Port{
-					Label: port.Label,
-					Value: int(port.Value),
+					Label:  port.Label,
+					Value:  int(port.Value),
+					HostIP: port.HostIp,
 				})
 			}
 			r.NomadResources.Networks = append(r.NomadResources.Networks, &n)
@@ -136,6 +160,17 @@ func resourcesFromProto(pb *proto.Resources) *Resources {
 			CpusetCPUs:       pb.LinuxResources.CpusetCpus,
 			CpusetMems:       pb.LinuxResources.CpusetMems,
 			PercentTicks:     pb.LinuxResources.PercentTicks,
+			IOWeight:         pb.LinuxResources.IoWeight,
+		}
+
+		for _, l := range pb.LinuxResources.IoLimits {
+			r.LinuxResources.IOLimits = append(r.LinuxResources.IOLimits, &IOLimit{
+				Device:    l.Device,
+				ReadBps:   l.ReadBps,
+				WriteBps:  l.WriteBps,
+				ReadIOps:  l.ReadIops,
+				WriteIOps: l.WriteIops,
+			})
 		}
 	}
 
@@ -165,14 +200,16 @@ func resourcesToProto(r *Resources) *proto.Resources {
 			n.ReservedPorts = []*proto.NetworkPort{}
 			for _, port := range network.ReservedPorts {
 				n.ReservedPorts = append(n.ReservedPorts, &proto.NetworkPort{
-					Label: port.Label,
-					Value: int32(port.Value),
+					Label:  port.Label,
+					Value:  int32(port.Value),
+					HostIp: port.HostIP,
 				})
 			}
 			for _, port := range network.DynamicPorts {
 				n.DynamicPorts = append(n.DynamicPorts, &proto.NetworkPort{
-					Label: port.Label,
-					Value: int32(port.Value),
+					Label:  port.Label,
+					Value:  int32(port.Value),
+					HostIp: port.HostIP,
 				})
 			}
 			pb.RawResources.Networks[i] = &n
@@ -189,6 +226,17 @@ func resourcesToProto(r *Resources) *proto.Resources {
 			CpusetCpus:       r.LinuxResources.CpusetCPUs,
 			CpusetMems:       r.LinuxResources.CpusetMems,
 			PercentTicks:     r.LinuxResources.PercentTicks,
+			IoWeight:         r.LinuxResources.IOWeight,
+		}
+
+		for _, l := range r.LinuxResources.IOLimits {
+			pb.LinuxResources.IoLimits = append(pb.LinuxResources.IoLimits, &proto.IOLimit{
+				Device:    l.Device,
+				ReadBps:   l.ReadBps,
+				WriteBps:  l.WriteBps,
+				ReadIops:  l.ReadIOps,
+				WriteIops: l.WriteIOps,
+			})
 		}
 	}
 
@@ -454,12 +502,40 @@ func resourceUsageToProto(ru *cstructs.ResourceUsage) *proto.TaskResourceUsage {
 		case "Kernel Max Usage":
 			memory.KernelMaxUsage = ru.MemoryStats.KernelMaxUsage
 			memory.MeasuredFields = append(memory.MeasuredFields, proto.MemoryUsage_KERNEL_MAX_USAGE)
+		case "Usage":
+			memory.Usage = ru.MemoryStats.Usage
+			memory.MeasuredFields = append(memory.MeasuredFields, proto.MemoryUsage_USAGE)
+		case "Balloon Actual":
+			memory.BalloonActual = ru.MemoryStats.BalloonActual
+			memory.MeasuredFields = append(memory.MeasuredFields, proto.MemoryUsage_BALLOON_ACTUAL)
+		}
+	}
+
+	var io *proto.IOUsage
+	if ru.IOStats != nil {
+		io = &proto.IOUsage{}
+		for _, field := range ru.IOStats.Measured {
+			switch field {
+			case "Read Bytes":
+				io.ReadBytes = ru.IOStats.ReadBytes
+				io.MeasuredFields = append(io.MeasuredFields, proto.IOUsage_READ_BYTES)
+			case "Write Bytes":
+				io.WriteBytes = ru.IOStats.WriteBytes
+				io.MeasuredFields = append(io.MeasuredFields, proto.IOUsage_WRITE_BYTES)
+			case "Read Ops":
+				io.ReadOps = ru.IOStats.ReadOps
+				io.MeasuredFields = append(io.MeasuredFields, proto.IOUsage_READ_OPS)
+			case "Write Ops":
+				io.WriteOps = ru.IOStats.WriteOps
+				io.MeasuredFields = append(io.MeasuredFields, proto.IOUsage_WRITE_OPS)
+			}
 		}
 	}
 
 	return &proto.TaskResourceUsage{
 		Cpu:    cpu,
 		Memory: memory,
+		Io:     io,
 	}
 }
 
@@ -509,6 +585,33 @@ func resourceUsageFromProto(pb *proto.TaskResourceUsage) *cstructs.ResourceUsage
 			case proto.MemoryUsage_KERNEL_MAX_USAGE:
 				memory.KernelMaxUsage = pb.Memory.KernelMaxUsage
 				memory.Measured = append(memory.Measured, "Kernel Max Usage")
+			case proto.MemoryUsage_USAGE:
+				memory.Usage = pb.Memory.Usage
+				memory.Measured = append(memory.Measured, "Usage")
+			case proto.MemoryUsage_BALLOON_ACTUAL:
+				memory.BalloonActual = pb.Memory.BalloonActual
+				memory.Measured = append(memory.Measured, "Balloon Actual")
+			}
+		}
+	}
+
+	var io *cstructs.IOStats
+	if pb.Io != nil {
+		io = &cstructs.IOStats{}
+		for _, field := range pb.Io.MeasuredFields {
+			switch field {
+			case proto.IOUsage_READ_BYTES:
+				io.ReadBytes = pb.Io.ReadBytes
+				io.Measured = append(io.Measured, "Read Bytes")
+			case proto.IOUsage_WRITE_BYTES:
+				io.WriteBytes = pb.Io.WriteBytes
+				io.Measured = append(io.Measured, "Write Bytes")
+			case proto.IOUsage_READ_OPS:
+				io.ReadOps = pb.Io.ReadOps
+				io.Measured = append(io.Measured, "Read Ops")
+			case proto.IOUsage_WRITE_OPS:
+				io.WriteOps = pb.Io.WriteOps
+				io.Measured = append(io.Measured, "Write Ops")
 			}
 		}
 	}
@@ -516,6 +619,7 @@ func resourceUsageFromProto(pb *proto.TaskResourceUsage) *cstructs.ResourceUsage
 	return &cstructs.ResourceUsage{
 		CpuStats:    &cpu,
 		MemoryStats: &memory,
+		IOStats:     io,
 	}
 }
 
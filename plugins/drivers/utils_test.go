@@ -0,0 +1,43 @@
+package drivers
+
+import (
+	"testing"
+
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResourcesProto_IO(t *testing.T) {
+	require := require.New(t)
+
+	r := &Resources{
+		LinuxResources: &LinuxResources{
+			CPUShares: 100,
+			IOWeight:  500,
+			IOLimits: []*IOLimit{
+				{Device: "/dev/sda", ReadBps: 1024, WriteIOps: 10},
+			},
+		},
+	}
+
+	require.Equal(r, resourcesFromProto(resourcesToProto(r)))
+}
+
+func TestResourceUsageProto_IO(t *testing.T) {
+	require := require.New(t)
+
+	ru := &cstructs.ResourceUsage{
+		CpuStats:    &cstructs.CpuStats{},
+		MemoryStats: &cstructs.MemoryStats{},
+		IOStats: &cstructs.IOStats{
+			ReadBytes: 4096,
+			WriteOps:  3,
+			Measured:  []string{"Read Bytes", "Write Ops"},
+		},
+	}
+	require.Equal(ru, resourceUsageFromProto(resourceUsageToProto(ru)))
+
+	// Drivers that don't measure I/O report no I/O stats
+	ru.IOStats = nil
+	require.Nil(resourceUsageFromProto(resourceUsageToProto(ru)).IOStats)
+}
@@ -90,6 +90,10 @@ func (e *ExecutorRPC) Signal(s os.Signal) error {
 	return e.client.Call("Plugin.Signal", &s, new(interface{}))
 }
 
+func (e *ExecutorRPC) Checkpoint(dir string) error {
+	return e.client.Call("Plugin.Checkpoint", dir, new(interface{}))
+}
+
 func (e *ExecutorRPC) Exec(deadline time.Time, name string, args []string) ([]byte, int, error) {
 	req := ExecArgs{
 		Deadline: deadline,
@@ -153,6 +157,10 @@ func (e *ExecutorRPCServer) Signal(args os.Signal, resp *interface{}) error {
 	return e.Impl.Signal(args)
 }
 
+func (e *ExecutorRPCServer) Checkpoint(dir string, resp *interface{}) error {
+	return e.Impl.Checkpoint(dir)
+}
+
 func (e *ExecutorRPCServer) Exec(args ExecArgs, result *ExecReturn) error {
 	out, code, err := e.Impl.Exec(args.Deadline, args.Name, args.Args)
 	ret := &ExecReturn{
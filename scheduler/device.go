@@ -2,8 +2,8 @@ package scheduler
 
 import (
 	"fmt"
-
 	"math"
+	"sort"
 
 	"github.com/hashicorp/nomad/nomad/structs"
 )
@@ -46,16 +46,13 @@ func (d *deviceAllocator) AssignDevice(ask *structs.RequestedDevice) (out *struc
 	// Determine the devices that are feasible based on availability and
 	// constraints
 	for id, devInst := range d.Devices {
-		// Check if we have enough unused instances to use this
-		assignable := uint64(0)
-		for _, v := range devInst.Instances {
-			if v == 0 {
-				assignable++
-			}
-		}
+		// Check if we have enough unused instances to use this. Shared
+		// requests may also use instances that are only used by other shared
+		// requests.
+		assignable := assignableInstances(devInst, ask.Shared)
 
 		// This device doesn't have enough instances
-		if assignable < ask.Count {
+		if uint64(len(assignable)) < ask.Count {
 			continue
 		}
 
@@ -107,18 +104,8 @@ func (d *deviceAllocator) AssignDevice(ask *structs.RequestedDevice) (out *struc
 			Vendor:    id.Vendor,
 			Type:      id.Type,
 			Name:      id.Name,
-			DeviceIDs: make([]string, 0, ask.Count),
-		}
-
-		assigned := uint64(0)
-		for id, v := range devInst.Instances {
-			if v == 0 && assigned < ask.Count {
-				assigned++
-				offer.DeviceIDs = append(offer.DeviceIDs, id)
-				if assigned == ask.Count {
-					break
-				}
-			}
+			DeviceIDs: assignable[:ask.Count],
+			Shared:    ask.Shared,
 		}
 	}
 
@@ -129,3 +116,24 @@ func (d *deviceAllocator) AssignDevice(ask *structs.RequestedDevice) (out *struc
 
 	return offer, matchedWeights, nil
 }
+
+// assignableInstances returns the IDs of the device instances that may be
+// assigned. Exclusive requests may only use unused instances while shared
+// requests may use any instance without an exclusive usage. The least used
+// instances are returned first so that shared usage is spread across
+// instances.
+func assignableInstances(devInst *structs.DeviceAccounterInstance, shared bool) []string {
+	var assignable []string
+	for id, v := range devInst.Instances {
+		if v == 0 || (shared && devInst.Shareable(id)) {
+			assignable = append(assignable, id)
+		}
+	}
+
+	if shared {
+		sort.Slice(assignable, func(i, j int) bool {
+			return devInst.Instances[assignable[i]] < devInst.Instances[assignable[j]]
+		})
+	}
+	return assignable
+}
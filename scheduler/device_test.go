@@ -201,6 +201,22 @@ func TestDeviceAllocator_Allocate_Constraints(t *testing.T) {
 			},
 			ExpectedDevice: nvidia0,
 		},
+		{
+			Name: "nvidia/gpu",
+			Constraints: []*structs.Constraint{
+				{
+					LTarget: "${device.model}",
+					Operand: "=",
+					RTarget: "2080ti",
+				},
+				{
+					LTarget: "${device.attr.memory}",
+					Operand: ">=",
+					RTarget: "10 GiB",
+				},
+			},
+			ExpectedDevice: nvidia1,
+		},
 		{
 			Name:        "intel/gpu",
 			NoPlacement: true,
@@ -356,3 +372,40 @@ func TestDeviceAllocator_Allocate_Affinities(t *testing.T) {
 		})
 	}
 }
+
+// Test that shared requests time-share instances not used exclusively
+func TestDeviceAllocator_Allocate_Shared(t *testing.T) {
+	require := require.New(t)
+	_, ctx := testContext(t)
+	n := mock.NvidiaNode()
+	d := newDeviceAllocator(ctx, n)
+	require.NotNil(d)
+
+	// Use one of the two instances exclusively
+	out, _, err := d.AssignDevice(deviceRequest("nvidia/gpu", 1, nil, nil))
+	require.NoError(err)
+	require.False(out.Shared)
+	require.False(d.AddReserved(out))
+	exclusiveID := out.DeviceIDs[0]
+
+	// Shared requests may not use the exclusive instance
+	shared := deviceRequest("nvidia/gpu", 1, nil, nil)
+	shared.Shared = true
+	for i := 0; i < 3; i++ {
+		out, _, err = d.AssignDevice(shared)
+		require.NoError(err)
+		require.True(out.Shared)
+		require.Len(out.DeviceIDs, 1)
+		require.NotEqual(exclusiveID, out.DeviceIDs[0])
+		require.False(d.AddReserved(out))
+	}
+
+	// The shared instance can not be used exclusively
+	_, _, err = d.AssignDevice(deviceRequest("nvidia/gpu", 1, nil, nil))
+	require.Error(err)
+
+	// Only a single instance may be shared
+	shared.Count = 2
+	_, _, err = d.AssignDevice(shared)
+	require.Error(err)
+}
@@ -162,6 +162,110 @@ func (c *DriverChecker) hasDrivers(option *structs.Node) bool {
 	return true
 }
 
+// HostVolumeChecker is a FeasibilityChecker which returns whether a node has
+// the healthy host volumes required by a task group.
+type HostVolumeChecker struct {
+	ctx     Context
+	volumes map[string]*structs.VolumeRequest
+}
+
+// NewHostVolumeChecker creates a HostVolumeChecker
+func NewHostVolumeChecker(ctx Context) *HostVolumeChecker {
+	return &HostVolumeChecker{
+		ctx: ctx,
+	}
+}
+
+// SetVolumes sets the volumes required by the task group
+func (h *HostVolumeChecker) SetVolumes(volumes map[string]*structs.VolumeRequest) {
+	h.volumes = volumes
+}
+
+func (h *HostVolumeChecker) Feasible(option *structs.Node) bool {
+	if reason := h.hasVolumes(option); reason != "" {
+		h.ctx.Metrics().FilterNode(option, reason)
+		return false
+	}
+	return true
+}
+
+// hasVolumes returns the reason the node is missing one of the host volumes
+// of the task group, or an empty string if it has all of them.
+func (h *HostVolumeChecker) hasVolumes(option *structs.Node) string {
+	for _, req := range h.volumes {
+		if req.Type != structs.VolumeTypeHost {
+			continue
+		}
+
+		volume, ok := option.HostVolumes[req.Source]
+		if !ok || volume == nil {
+			return "missing host volumes"
+		}
+		if !volume.Healthy {
+			return "unhealthy host volumes"
+		}
+		if volume.ReadOnly && !req.ReadOnly {
+			return "read only host volumes"
+		}
+	}
+	return ""
+}
+
+// NetworkChecker is a FeasibilityChecker which returns whether a node supports
+// the network mode of a task group, either the CNI network it requests or
+// bridge networks.
+type NetworkChecker struct {
+	ctx          Context
+	networks     structs.Networks
+	hostNetworks []string
+}
+
+// NewNetworkChecker creates a NetworkChecker
+func NewNetworkChecker(ctx Context) *NetworkChecker {
+	return &NetworkChecker{
+		ctx: ctx,
+	}
+}
+
+// SetNetworks sets the networks of the task group
+func (n *NetworkChecker) SetNetworks(networks structs.Networks) {
+	n.networks = networks
+}
+
+// SetHostNetworks sets the names of the host networks the ports of the task
+// group are bound to
+func (n *NetworkChecker) SetHostNetworks(hostNetworks []string) {
+	n.hostNetworks = hostNetworks
+}
+
+func (n *NetworkChecker) Feasible(option *structs.Node) bool {
+	for _, name := range n.hostNetworks {
+		if _, ok := option.Attributes[structs.HostNetworkAttributePrefix+name]; !ok {
+			n.ctx.Metrics().FilterNode(option, "missing host network")
+			return false
+		}
+	}
+
+	if n.networks.Bridge() != nil {
+		if _, ok := option.Attributes[structs.BridgeNetworkAttribute]; !ok {
+			n.ctx.Metrics().FilterNode(option, "missing bridge network")
+			return false
+		}
+		return true
+	}
+
+	name, ok := n.networks.CNINetwork()
+	if !ok {
+		return true
+	}
+
+	if _, ok := option.Attributes[structs.CNINetworkAttributePrefix+name]; !ok {
+		n.ctx.Metrics().FilterNode(option, "missing CNI network")
+		return false
+	}
+	return true
+}
+
 // DistinctHostsIterator is a FeasibleIterator which returns nodes that pass the
 // distinct_hosts constraint. The constraint ensures that multiple allocations
 // do not exist on the same node.
@@ -438,6 +542,11 @@ func resolveTarget(target string, node *structs.Node) (interface{}, bool) {
 		val, ok := node.Meta[meta]
 		return val, ok
 
+	case strings.HasPrefix(target, "${dynamic_meta."):
+		meta := strings.TrimSuffix(strings.TrimPrefix(target, "${dynamic_meta."), "}")
+		val, ok := node.DynamicMeta[meta]
+		return val, ok
+
 	default:
 		return nil, false
 	}
@@ -699,23 +808,27 @@ func checkSetContainsAny(lVal, rVal interface{}) bool {
 // FeasibilityWrapper is a FeasibleIterator which wraps both job and task group
 // FeasibilityCheckers in which feasibility checking can be skipped if the
 // computed node class has previously been marked as eligible or ineligible.
+// The task group availability checkers depend on the state of the node rather
+// than its class and are run on every node of an eligible class.
 type FeasibilityWrapper struct {
 	ctx         Context
 	source      FeasibleIterator
 	jobCheckers []FeasibilityChecker
 	tgCheckers  []FeasibilityChecker
+	tgAvailable []FeasibilityChecker
 	tg          string
 }
 
 // NewFeasibilityWrapper returns a FeasibleIterator based on the passed source
 // and FeasibilityCheckers.
 func NewFeasibilityWrapper(ctx Context, source FeasibleIterator,
-	jobCheckers, tgCheckers []FeasibilityChecker) *FeasibilityWrapper {
+	jobCheckers, tgCheckers, tgAvailable []FeasibilityChecker) *FeasibilityWrapper {
 	return &FeasibilityWrapper{
 		ctx:         ctx,
 		source:      source,
 		jobCheckers: jobCheckers,
 		tgCheckers:  tgCheckers,
+		tgAvailable: tgAvailable,
 	}
 }
 
@@ -782,7 +895,10 @@ OUTER:
 			continue
 		case EvalComputedClassEligible:
 			// Fast path the eligible case
-			return option
+			if w.available(option) {
+				return option
+			}
+			continue
 		case EvalComputedClassEscaped:
 			tgEscaped = true
 		case EvalComputedClassUnknown:
@@ -808,10 +924,24 @@ OUTER:
 			evalElig.SetTaskGroupEligibility(true, w.tg, option.ComputedClass)
 		}
 
+		if !w.available(option) {
+			continue
+		}
 		return option
 	}
 }
 
+// available returns whether the node passes the task group availability
+// checks, whose result is not cached for its computed class.
+func (w *FeasibilityWrapper) available(option *structs.Node) bool {
+	for _, check := range w.tgAvailable {
+		if !check.Feasible(option) {
+			return false
+		}
+	}
+	return true
+}
+
 // DeviceChecker is a FeasibilityChecker which returns whether a node has the
 // devices necessary to scheduler a task group.
 type DeviceChecker struct {
@@ -951,6 +1081,12 @@ func resolveDeviceTarget(target string, d *structs.NodeDeviceResource) (*psstruc
 		return psstructs.ParseAttribute(target), true
 	}
 
+	// Devices may be referenced with either the "device" or the "driver"
+	// prefix
+	if strings.HasPrefix(target, "${device.") {
+		target = "${driver." + strings.TrimPrefix(target, "${device.")
+	}
+
 	// Handle the interpolations
 	switch {
 	case "${driver.model}" == target:
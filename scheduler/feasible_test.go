@@ -128,6 +128,108 @@ func TestDriverChecker(t *testing.T) {
 	}
 }
 
+func TestHostVolumeChecker(t *testing.T) {
+	_, ctx := testContext(t)
+	nodes := []*structs.Node{
+		mock.Node(),
+		mock.Node(),
+		mock.Node(),
+		mock.Node(),
+		mock.Node(),
+	}
+	nodes[1].HostVolumes = map[string]*structs.ClientHostVolumeConfig{
+		"foo": {Name: "foo", Healthy: true},
+	}
+	nodes[2].HostVolumes = map[string]*structs.ClientHostVolumeConfig{
+		"foo": {Name: "foo", Healthy: false},
+	}
+	nodes[3].HostVolumes = map[string]*structs.ClientHostVolumeConfig{
+		"foo": {Name: "foo", Healthy: true, ReadOnly: true},
+	}
+	nodes[4].HostVolumes = map[string]*structs.ClientHostVolumeConfig{
+		"bar": {Name: "bar", Healthy: true},
+	}
+
+	checker := NewHostVolumeChecker(ctx)
+	checker.SetVolumes(map[string]*structs.VolumeRequest{
+		"data": {Name: "data", Type: structs.VolumeTypeHost, Source: "foo"},
+	})
+	cases := []struct {
+		Node   *structs.Node
+		Result bool
+	}{
+		{
+			Node:   nodes[0],
+			Result: false,
+		},
+		{
+			Node:   nodes[1],
+			Result: true,
+		},
+		{
+			Node:   nodes[2],
+			Result: false,
+		},
+		{
+			Node:   nodes[3],
+			Result: false,
+		},
+		{
+			Node:   nodes[4],
+			Result: false,
+		},
+	}
+
+	for i, c := range cases {
+		if act := checker.Feasible(c.Node); act != c.Result {
+			t.Fatalf("case(%d) failed: got %v; want %v", i, act, c.Result)
+		}
+	}
+
+	// A read only request may use a read only volume
+	checker.SetVolumes(map[string]*structs.VolumeRequest{
+		"data": {Name: "data", Type: structs.VolumeTypeHost, Source: "foo", ReadOnly: true},
+	})
+	require.True(t, checker.Feasible(nodes[3]))
+
+	// No volumes are always feasible
+	checker.SetVolumes(nil)
+	require.True(t, checker.Feasible(nodes[0]))
+}
+
+func TestNetworkChecker(t *testing.T) {
+	_, ctx := testContext(t)
+	nodes := []*structs.Node{
+		mock.Node(),
+		mock.Node(),
+	}
+	nodes[1].Attributes[structs.CNINetworkAttributePrefix+"mynet"] = "0.4.0"
+
+	checker := NewNetworkChecker(ctx)
+	checker.SetNetworks(structs.Networks{{Mode: "cni/mynet"}})
+	require.False(t, checker.Feasible(nodes[0]))
+	require.True(t, checker.Feasible(nodes[1]))
+
+	// The host network is always feasible
+	checker.SetNetworks(structs.Networks{{Mode: structs.NetworkModeHost}})
+	require.True(t, checker.Feasible(nodes[0]))
+	checker.SetNetworks(nil)
+	require.True(t, checker.Feasible(nodes[0]))
+
+	// Bridge networks require the bridge plugins
+	nodes[0].Attributes[structs.BridgeNetworkAttribute] = "1"
+	checker.SetNetworks(structs.Networks{{Mode: structs.NetworkModeBridge}})
+	require.True(t, checker.Feasible(nodes[0]))
+	require.False(t, checker.Feasible(nodes[1]))
+
+	// Ports bound to a host network require the host network
+	nodes[1].Attributes[structs.HostNetworkAttributePrefix+"public"] = "eth1"
+	checker.SetNetworks(nil)
+	checker.SetHostNetworks([]string{"public"})
+	require.False(t, checker.Feasible(nodes[0]))
+	require.True(t, checker.Feasible(nodes[1]))
+}
+
 func Test_HealthChecks(t *testing.T) {
 	require := require.New(t)
 	_, ctx := testContext(t)
@@ -259,6 +361,7 @@ func TestResolveConstraintTarget(t *testing.T) {
 		result bool
 	}
 	node := mock.Node()
+	node.DynamicMeta = map[string]string{"rack": "r1"}
 	cases := []tcase{
 		{
 			target: "${node.unique.id}",
@@ -313,6 +416,18 @@ func TestResolveConstraintTarget(t *testing.T) {
 			val:    "",
 			result: false,
 		},
+		{
+			target: "${dynamic_meta.rack}",
+			node:   node,
+			val:    "r1",
+			result: true,
+		},
+		{
+			target: "${dynamic_meta.rand}",
+			node:   node,
+			val:    "",
+			result: false,
+		},
 	}
 
 	for _, tc := range cases {
@@ -1575,7 +1690,7 @@ func TestFeasibilityWrapper_JobIneligible(t *testing.T) {
 	nodes := []*structs.Node{mock.Node()}
 	static := NewStaticIterator(ctx, nodes)
 	mocked := newMockFeasibilityChecker(false)
-	wrapper := NewFeasibilityWrapper(ctx, static, []FeasibilityChecker{mocked}, nil)
+	wrapper := NewFeasibilityWrapper(ctx, static, []FeasibilityChecker{mocked}, nil, nil)
 
 	// Set the job to ineligible
 	ctx.Eligibility().SetJobEligibility(false, nodes[0].ComputedClass)
@@ -1593,7 +1708,7 @@ func TestFeasibilityWrapper_JobEscapes(t *testing.T) {
 	nodes := []*structs.Node{mock.Node()}
 	static := NewStaticIterator(ctx, nodes)
 	mocked := newMockFeasibilityChecker(false)
-	wrapper := NewFeasibilityWrapper(ctx, static, []FeasibilityChecker{mocked}, nil)
+	wrapper := NewFeasibilityWrapper(ctx, static, []FeasibilityChecker{mocked}, nil, nil)
 
 	// Set the job to escaped
 	cc := nodes[0].ComputedClass
@@ -1619,7 +1734,7 @@ func TestFeasibilityWrapper_JobAndTg_Eligible(t *testing.T) {
 	static := NewStaticIterator(ctx, nodes)
 	jobMock := newMockFeasibilityChecker(true)
 	tgMock := newMockFeasibilityChecker(false)
-	wrapper := NewFeasibilityWrapper(ctx, static, []FeasibilityChecker{jobMock}, []FeasibilityChecker{tgMock})
+	wrapper := NewFeasibilityWrapper(ctx, static, []FeasibilityChecker{jobMock}, []FeasibilityChecker{tgMock}, nil)
 
 	// Set the job to escaped
 	cc := nodes[0].ComputedClass
@@ -1641,7 +1756,7 @@ func TestFeasibilityWrapper_JobEligible_TgIneligible(t *testing.T) {
 	static := NewStaticIterator(ctx, nodes)
 	jobMock := newMockFeasibilityChecker(true)
 	tgMock := newMockFeasibilityChecker(false)
-	wrapper := NewFeasibilityWrapper(ctx, static, []FeasibilityChecker{jobMock}, []FeasibilityChecker{tgMock})
+	wrapper := NewFeasibilityWrapper(ctx, static, []FeasibilityChecker{jobMock}, []FeasibilityChecker{tgMock}, nil)
 
 	// Set the job to escaped
 	cc := nodes[0].ComputedClass
@@ -1663,7 +1778,7 @@ func TestFeasibilityWrapper_JobEligible_TgEscaped(t *testing.T) {
 	static := NewStaticIterator(ctx, nodes)
 	jobMock := newMockFeasibilityChecker(true)
 	tgMock := newMockFeasibilityChecker(true)
-	wrapper := NewFeasibilityWrapper(ctx, static, []FeasibilityChecker{jobMock}, []FeasibilityChecker{tgMock})
+	wrapper := NewFeasibilityWrapper(ctx, static, []FeasibilityChecker{jobMock}, []FeasibilityChecker{tgMock}, nil)
 
 	// Set the job to escaped
 	cc := nodes[0].ComputedClass
@@ -1684,6 +1799,32 @@ func TestFeasibilityWrapper_JobEligible_TgEscaped(t *testing.T) {
 	}
 }
 
+func TestFeasibilityWrapper_JobEligible_TgAvailable(t *testing.T) {
+	_, ctx := testContext(t)
+	nodes := []*structs.Node{mock.Node(), mock.Node()}
+	nodes[1].ComputedClass = nodes[0].ComputedClass
+	static := NewStaticIterator(ctx, nodes)
+	jobMock := newMockFeasibilityChecker(true, true)
+	tgMock := newMockFeasibilityChecker(true, true)
+	availMock := newMockFeasibilityChecker(false, true)
+	wrapper := NewFeasibilityWrapper(ctx, static, []FeasibilityChecker{jobMock}, []FeasibilityChecker{tgMock}, []FeasibilityChecker{availMock})
+	wrapper.SetTaskGroup("foo")
+
+	// Run the wrapper.
+	out := collectFeasible(wrapper)
+
+	// The availability checks run on every node and don't mark the class
+	// ineligible, so the second node of the class is feasible
+	if len(out) != 1 || out[0] != nodes[1] || tgMock.calls() != 1 || availMock.calls() != 2 {
+		t.Fatalf("bad: %#v %v %v", out, tgMock.calls(), availMock.calls())
+	}
+
+	cc := nodes[0].ComputedClass
+	if e, ok := ctx.Eligibility().taskGroups["foo"][cc]; !ok || e != EvalComputedClassEligible {
+		t.Fatalf("bad: %v %v", e, ok)
+	}
+}
+
 func TestSetContainsAny(t *testing.T) {
 	require.True(t, checkSetContainsAny("a", "a"))
 	require.True(t, checkSetContainsAny("a,b", "a"))
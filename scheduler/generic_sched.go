@@ -32,6 +32,10 @@ const (
 	// allocLost is the status used when an allocation is lost
 	allocLost = "alloc is lost since its node is down"
 
+	// allocUnknown is the status used when an allocation is unknown because
+	// its node is disconnected
+	allocUnknown = "alloc is unknown since its node is disconnected"
+
 	// allocInPlace is the status used when speculating on an in-place update
 	allocInPlace = "alloc updating in-place"
 
@@ -51,6 +55,11 @@ const (
 	// up evals for delayed rescheduling
 	reschedulingFollowupEvalDesc = "created for delayed rescheduling"
 
+	// disconnectTimeoutFollowupEvalDesc is the description used when creating
+	// follow up evals for allocations whose node may stay disconnected past
+	// the max_client_disconnect of their task group
+	disconnectTimeoutFollowupEvalDesc = "created for delayed disconnect timeout"
+
 	// maxPastRescheduleEvents is the maximum number of past reschedule event
 	// that we track when unlimited rescheduling is enabled
 	maxPastRescheduleEvents = 5
@@ -130,7 +139,8 @@ func (s *GenericScheduler) Process(eval *structs.Evaluation) error {
 		structs.EvalTriggerRollingUpdate, structs.EvalTriggerQueuedAllocs,
 		structs.EvalTriggerPeriodicJob, structs.EvalTriggerMaxPlans,
 		structs.EvalTriggerDeploymentWatcher, structs.EvalTriggerRetryFailedAlloc,
-		structs.EvalTriggerFailedFollowUp, structs.EvalTriggerPreemption:
+		structs.EvalTriggerFailedFollowUp, structs.EvalTriggerPreemption,
+		structs.EvalTriggerMaxDisconnect:
 	default:
 		desc := fmt.Sprintf("scheduler cannot handle '%s' evaluation reason",
 			eval.TriggeredBy)
@@ -382,6 +392,11 @@ func (s *GenericScheduler) computeJobAllocs() error {
 		s.ctx.Plan().AppendAlloc(update)
 	}
 
+	// Mark the allocations of disconnected nodes as unknown
+	for _, update := range results.disconnectUpdates {
+		s.ctx.Plan().AppendAlloc(update)
+	}
+
 	// Nothing remaining to do if placement is not required
 	if len(results.place)+len(results.destructiveUpdate) == 0 {
 		// If the job has been purged we don't have access to the job. Otherwise
@@ -420,7 +435,7 @@ func (s *GenericScheduler) computeJobAllocs() error {
 // destructive updates to place and the set of new placements to place.
 func (s *GenericScheduler) computePlacements(destructive, place []placementResult) error {
 	// Get the base nodes
-	nodes, byDC, err := readyNodesInDCs(s.state, s.job.Datacenters)
+	nodes, byDC, err := readyNodesInDCs(s.state, s.job.Datacenters, s.job.NodePool)
 	if err != nil {
 		return err
 	}
@@ -481,7 +496,8 @@ func (s *GenericScheduler) computePlacements(destructive, place []placementResul
 				resources := &structs.AllocatedResources{
 					Tasks: option.TaskResources,
 					Shared: structs.AllocatedSharedResources{
-						DiskMB: int64(tg.EphemeralDisk.SizeMB),
+						DiskMB:   int64(tg.EphemeralDisk.SizeMB),
+						Networks: option.AllocResources.Networks,
 					},
 				}
 
@@ -513,6 +529,12 @@ func (s *GenericScheduler) computePlacements(destructive, place []placementResul
 					if missing.IsRescheduling() {
 						updateRescheduleTracker(alloc, prevAllocation, now)
 					}
+
+					// Hand the remote tasks of a lost allocation over to
+					// its replacement
+					if missing.PreviousLost() {
+						propagateTaskHandles(alloc, prevAllocation)
+					}
 				}
 
 				// If we are placing a canary and we found a match, add the canary
@@ -527,6 +549,24 @@ func (s *GenericScheduler) computePlacements(destructive, place []placementResul
 					}
 				}
 
+				// If this placement involves preemption, set DesiredState to evict for those allocations
+				if option.PreemptedAllocs != nil {
+					var preemptedAllocIDs []string
+					for _, stop := range option.PreemptedAllocs {
+						s.plan.AppendPreemptedAlloc(stop, structs.AllocDesiredStatusEvict, alloc.ID)
+
+						preemptedAllocIDs = append(preemptedAllocIDs, stop.ID)
+						if s.eval.AnnotatePlan && s.plan.Annotations != nil {
+							s.plan.Annotations.PreemptedAllocs = append(s.plan.Annotations.PreemptedAllocs, stop.Stub())
+							if s.plan.Annotations.DesiredTGUpdates != nil {
+								desired := s.plan.Annotations.DesiredTGUpdates[tg.Name]
+								desired.Preemptions += 1
+							}
+						}
+					}
+					alloc.PreemptedAllocations = preemptedAllocIDs
+				}
+
 				// Track the placement
 				s.plan.AppendAlloc(alloc)
 
@@ -608,6 +648,25 @@ func updateRescheduleTracker(alloc *structs.Allocation, prev *structs.Allocation
 	alloc.RescheduleTracker = &structs.RescheduleTracker{Events: rescheduleEvents}
 }
 
+// propagateTaskHandles copies the handles of the tasks of a lost allocation
+// running on remote task drivers to its replacement, which recovers the tasks
+// instead of starting them again.
+func propagateTaskHandles(alloc *structs.Allocation, prev *structs.Allocation) {
+	for taskName, prevState := range prev.TaskStates {
+		if prevState.TaskHandle == nil {
+			continue
+		}
+
+		if alloc.TaskStates == nil {
+			alloc.TaskStates = make(map[string]*structs.TaskState, len(prev.TaskStates))
+		}
+
+		state := structs.NewTaskState()
+		state.TaskHandle = prevState.TaskHandle.Copy()
+		alloc.TaskStates[taskName] = state
+	}
+}
+
 // findPreferredNode finds the preferred node for an allocation
 func (s *GenericScheduler) findPreferredNode(place placementResult) (*structs.Node, error) {
 	if prev := place.PreviousAllocation(); prev != nil && place.TaskGroup().EphemeralDisk.Sticky == true {
@@ -976,6 +976,243 @@ func TestServiceSched_JobRegister_AllocFail(t *testing.T) {
 	h.AssertEvalStatus(t, structs.EvalStatusComplete)
 }
 
+func TestServiceSched_JobRegister_QuotaLimit(t *testing.T) {
+	require := require.New(t)
+	h := NewHarness(t)
+
+	// Create some nodes
+	for i := 0; i < 10; i++ {
+		node := mock.Node()
+		noErr(t, h.State.UpsertNode(h.NextIndex(), node))
+	}
+
+	// Limit the default namespace to the CPU of four allocations
+	spec := &structs.QuotaSpec{
+		Name: "small",
+		Limits: []*structs.QuotaLimit{{
+			Region:      "global",
+			RegionLimit: &structs.Resources{CPU: 2000},
+		}},
+	}
+	noErr(t, h.State.UpsertQuotaSpecs(h.NextIndex(), []*structs.QuotaSpec{spec}))
+	noErr(t, h.State.UpsertNamespaces(h.NextIndex(), []*structs.Namespace{{Name: structs.DefaultNamespace, Quota: spec.Name}}))
+
+	// Create a job
+	job := mock.Job()
+	noErr(t, h.State.UpsertJob(h.NextIndex(), job))
+
+	// Create a mock evaluation to register the job
+	eval := &structs.Evaluation{
+		Namespace:   structs.DefaultNamespace,
+		ID:          uuid.Generate(),
+		Priority:    job.Priority,
+		TriggeredBy: structs.EvalTriggerJobRegister,
+		JobID:       job.ID,
+		Status:      structs.EvalStatusPending,
+	}
+	noErr(t, h.State.UpsertEvals(h.NextIndex(), []*structs.Evaluation{eval}))
+
+	// Process the evaluation
+	require.NoError(h.Process(NewServiceScheduler, eval))
+
+	// Ensure only the allocations within the quota were placed
+	require.Len(h.Plans, 1)
+	var planned []*structs.Allocation
+	for _, allocList := range h.Plans[0].NodeAllocation {
+		planned = append(planned, allocList...)
+	}
+	require.Len(planned, 4)
+
+	// Ensure the follow up eval is blocked on the quota
+	require.Len(h.CreateEvals, 1)
+	blocked := h.CreateEvals[0]
+	require.Equal(structs.EvalStatusBlocked, blocked.Status)
+	require.Equal(spec.Name, blocked.QuotaLimitReached)
+
+	// Ensure the failed placements report the exhausted quota
+	require.Len(h.Evals, 1)
+	metrics, ok := h.Evals[0].FailedTGAllocs[job.TaskGroups[0].Name]
+	require.True(ok)
+	require.Len(metrics.QuotaExhausted, 1)
+	require.Contains(metrics.QuotaExhausted[0], "cpu exhausted")
+	require.EqualValues(5, metrics.CoalescedFailures)
+	h.AssertEvalStatus(t, structs.EvalStatusComplete)
+}
+
+func TestServiceSched_JobRegister_Preemption(t *testing.T) {
+	cases := []struct {
+		name    string
+		batch   bool
+		config  structs.PreemptionConfig
+		preempt bool
+	}{
+		{
+			name:    "service preemption disabled",
+			config:  structs.PreemptionConfig{SystemSchedulerEnabled: true},
+			preempt: false,
+		},
+		{
+			name:    "service preemption enabled",
+			config:  structs.PreemptionConfig{ServiceSchedulerEnabled: true},
+			preempt: true,
+		},
+		{
+			name:    "batch preemption disabled",
+			batch:   true,
+			config:  structs.PreemptionConfig{ServiceSchedulerEnabled: true},
+			preempt: false,
+		},
+		{
+			name:    "batch preemption enabled",
+			batch:   true,
+			config:  structs.PreemptionConfig{BatchSchedulerEnabled: true},
+			preempt: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			require := require.New(t)
+			h := NewHarness(t)
+
+			// Create a node
+			node := mock.Node()
+			require.NoError(h.State.UpsertNode(h.NextIndex(), node))
+
+			require.NoError(h.State.SchedulerSetConfig(h.NextIndex(), &structs.SchedulerConfiguration{
+				PreemptionConfig: tc.config,
+			}))
+
+			// Create a low priority job which consumes most of the node
+			lowJob := mock.Job()
+			lowJob.Priority = 20
+			lowJob.TaskGroups[0].Count = 1
+			lowJob.TaskGroups[0].Tasks[0].Resources.CPU = 3600
+			require.NoError(h.State.UpsertJob(h.NextIndex(), lowJob))
+
+			lowAlloc := mock.Alloc()
+			lowAlloc.Job = lowJob
+			lowAlloc.JobID = lowJob.ID
+			lowAlloc.NodeID = node.ID
+			lowAlloc.Name = "my-job.web[0]"
+			lowAlloc.AllocatedResources.Tasks["web"].Cpu.CpuShares = 3600
+			require.NoError(h.State.UpsertAllocs(h.NextIndex(), []*structs.Allocation{lowAlloc}))
+
+			// Create a high priority job that only fits if the low
+			// priority allocation is preempted
+			var job *structs.Job
+			factory := NewServiceScheduler
+			if tc.batch {
+				job = mock.BatchJob()
+				factory = NewBatchScheduler
+			} else {
+				job = mock.Job()
+			}
+			job.Priority = 70
+			job.TaskGroups[0].Count = 1
+			job.TaskGroups[0].Tasks[0].Resources.CPU = 3600
+			require.NoError(h.State.UpsertJob(h.NextIndex(), job))
+
+			eval := &structs.Evaluation{
+				Namespace:   structs.DefaultNamespace,
+				ID:          uuid.Generate(),
+				Priority:    job.Priority,
+				TriggeredBy: structs.EvalTriggerJobRegister,
+				JobID:       job.ID,
+				Status:      structs.EvalStatusPending,
+			}
+			require.NoError(h.State.UpsertEvals(h.NextIndex(), []*structs.Evaluation{eval}))
+			require.NoError(h.Process(factory, eval))
+
+			if !tc.preempt {
+				require.Len(h.Plans, 0)
+				return
+			}
+
+			require.Len(h.Plans, 1)
+			plan := h.Plans[0]
+			require.Len(plan.NodeAllocation[node.ID], 1)
+			require.Len(plan.NodePreemptions[node.ID], 1)
+
+			alloc := plan.NodeAllocation[node.ID][0]
+			preempted := plan.NodePreemptions[node.ID][0]
+			require.Equal(job.ID, alloc.JobID)
+			require.Equal(lowAlloc.ID, preempted.ID)
+			require.Equal(structs.AllocDesiredStatusEvict, preempted.DesiredStatus)
+			require.Equal(alloc.ID, preempted.PreemptedByAllocation)
+			require.Equal([]string{lowAlloc.ID}, alloc.PreemptedAllocations)
+		})
+	}
+}
+
+func TestServiceSched_JobRegister_NodeFailures(t *testing.T) {
+	h := NewHarness(t)
+	require := require.New(t)
+
+	// Create a node that fails the constraint of the job and one without
+	// enough memory
+	filtered := mock.Node()
+	filtered.Attributes["kernel.name"] = "windows"
+	filtered.ComputeClass()
+	require.NoError(h.State.UpsertNode(h.NextIndex(), filtered))
+
+	exhausted := mock.Node()
+	exhausted.NodeResources.Memory.MemoryMB = 128
+	exhausted.ComputeClass()
+	require.NoError(h.State.UpsertNode(h.NextIndex(), exhausted))
+
+	// Create a job
+	job := mock.Job()
+	job.TaskGroups[0].Count = 1
+	require.NoError(h.State.UpsertJob(h.NextIndex(), job))
+
+	// Create a mock evaluation to register the job
+	eval := &structs.Evaluation{
+		Namespace:   structs.DefaultNamespace,
+		ID:          uuid.Generate(),
+		Priority:    job.Priority,
+		TriggeredBy: structs.EvalTriggerJobRegister,
+		JobID:       job.ID,
+		Status:      structs.EvalStatusPending,
+	}
+	require.NoError(h.State.UpsertEvals(h.NextIndex(), []*structs.Evaluation{eval}))
+
+	// Process the evaluation
+	require.NoError(h.Process(NewServiceScheduler, eval))
+
+	// Ensure the reason each node was rejected is reported
+	require.Len(h.Evals, 1)
+	metrics, ok := h.Evals[0].FailedTGAllocs[job.TaskGroups[0].Name]
+	require.True(ok)
+	require.Len(metrics.NodeFailures, 2)
+
+	failures := make(map[string]*structs.NodeFailureMeta)
+	for _, failure := range metrics.NodeFailures {
+		failures[failure.NodeID] = failure
+	}
+	require.Contains(failures, filtered.ID)
+	require.Equal(filtered.Name, failures[filtered.ID].NodeName)
+	require.Equal("${attr.kernel.name} = linux", failures[filtered.ID].Constraint)
+	require.Empty(failures[filtered.ID].Dimension)
+	require.Contains(failures, exhausted.ID)
+	require.Equal("memory", failures[exhausted.ID].Dimension)
+	require.Empty(failures[exhausted.ID].Constraint)
+
+	// Ensure placed allocations don't retain the nodes rejected
+	node := mock.Node()
+	require.NoError(h.State.UpsertNode(h.NextIndex(), node))
+	eval2 := eval.Copy()
+	eval2.ID = uuid.Generate()
+	require.NoError(h.State.UpsertEvals(h.NextIndex(), []*structs.Evaluation{eval2}))
+	require.NoError(h.Process(NewServiceScheduler, eval2))
+
+	require.NotEmpty(h.Plans)
+	placed := h.Plans[len(h.Plans)-1].NodeAllocation[node.ID]
+	require.Len(placed, 1)
+	require.NotZero(placed[0].Metrics.NodesFiltered)
+	require.Empty(placed[0].Metrics.NodeFailures)
+}
+
 func TestServiceSched_JobRegister_CreateBlockedEval(t *testing.T) {
 	h := NewHarness(t)
 
@@ -4600,3 +4837,31 @@ func Test_updateRescheduleTracker(t *testing.T) {
 	}
 
 }
+
+func Test_propagateTaskHandles(t *testing.T) {
+	require := require.New(t)
+
+	prevAlloc := mock.Alloc()
+	prevAlloc.TaskStates = map[string]*structs.TaskState{
+		"web": {
+			State:      structs.TaskStateRunning,
+			TaskHandle: &structs.TaskHandle{Driver: "remote", DriverState: []byte("state")},
+		},
+		"sidecar": {
+			State: structs.TaskStateRunning,
+		},
+	}
+
+	alloc := mock.Alloc()
+	propagateTaskHandles(alloc, prevAlloc)
+
+	require.Len(alloc.TaskStates, 1)
+	state := alloc.TaskStates["web"]
+	require.NotNil(state)
+	require.Equal(structs.TaskStatePending, state.State)
+	require.Equal(prevAlloc.TaskStates["web"].TaskHandle, state.TaskHandle)
+
+	// The handle is copied
+	state.TaskHandle.DriverState[0] = 'x'
+	require.Equal([]byte("state"), prevAlloc.TaskStates["web"].TaskHandle.DriverState)
+}
@@ -0,0 +1,206 @@
+package scheduler
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// QuotaIterator is a FeasibleIterator which returns no nodes if placing the
+// task group would cause the quota attached to the job's namespace to be
+// exceeded. Existing allocations of the job and the allocations already
+// proposed by the plan are taken into account so that in-place updates and
+// replacements do not double count usage.
+type QuotaIterator struct {
+	ctx    Context
+	source FeasibleIterator
+
+	job *structs.Job
+	tg  *structs.TaskGroup
+
+	// quota is the name of the quota specification attached to the job's
+	// namespace and limit is its limit in the job's region.
+	quota string
+	limit *structs.QuotaLimit
+
+	// used is the usage of the quota at the time the job was set
+	used *structs.QuotaLimit
+
+	// existing is the set of non-terminal allocations of the job
+	existing map[string]*structs.Allocation
+
+	// checked and exhausted track whether the quota has been checked for the
+	// current placement and whether it was exhausted
+	checked   bool
+	exhausted bool
+}
+
+// NewQuotaIterator returns a FeasibleIterator that enforces the quota attached
+// to the namespace of the job.
+func NewQuotaIterator(ctx Context, source FeasibleIterator) FeasibleIterator {
+	return &QuotaIterator{
+		ctx:    ctx,
+		source: source,
+	}
+}
+
+func (iter *QuotaIterator) SetJob(job *structs.Job) {
+	iter.job = job
+	iter.quota = ""
+	iter.limit = nil
+	iter.used = nil
+	iter.existing = nil
+	iter.checked = false
+
+	if err := iter.loadQuota(); err != nil {
+		iter.ctx.Logger().Error("failed to load quota", "job", job.ID, "namespace", job.Namespace, "error", err)
+		iter.limit = nil
+	}
+}
+
+// loadQuota looks up the quota limit that applies to the job along with its
+// current usage.
+func (iter *QuotaIterator) loadQuota() error {
+	state := iter.ctx.State()
+	ns, err := state.NamespaceByName(nil, iter.job.Namespace)
+	if err != nil {
+		return err
+	}
+	if ns == nil || ns.Quota == "" {
+		return nil
+	}
+
+	spec, err := state.QuotaSpecByName(nil, ns.Quota)
+	if err != nil {
+		return err
+	}
+	if spec == nil {
+		return nil
+	}
+	limit := spec.RegionLimit(iter.job.Region)
+	if limit == nil {
+		return nil
+	}
+
+	usage, err := state.QuotaUsageByName(nil, iter.job.Region, spec.Name)
+	if err != nil {
+		return err
+	}
+	used, ok := usage.Used[limit.HashKey()]
+	if !ok {
+		return fmt.Errorf("quota %q has no usage for region %q", spec.Name, iter.job.Region)
+	}
+
+	allocs, err := state.AllocsByJob(nil, iter.job.Namespace, iter.job.ID, false)
+	if err != nil {
+		return err
+	}
+	existing := make(map[string]*structs.Allocation, len(allocs))
+	for _, alloc := range allocs {
+		if !alloc.TerminalStatus() {
+			existing[alloc.ID] = alloc
+		}
+	}
+
+	iter.quota = spec.Name
+	iter.limit = limit
+	iter.used = used
+	iter.existing = existing
+	return nil
+}
+
+func (iter *QuotaIterator) SetTaskGroup(tg *structs.TaskGroup) {
+	iter.tg = tg
+	iter.checked = false
+}
+
+func (iter *QuotaIterator) Next() *structs.Node {
+	if iter.limit == nil || iter.tg == nil {
+		return iter.source.Next()
+	}
+
+	if !iter.checked {
+		iter.checked = true
+		iter.exhausted = false
+		if exceeded := iter.limit.Exceeded(iter.proposedUsage()); len(exceeded) != 0 {
+			iter.exhausted = true
+			iter.ctx.Metrics().ExhaustQuota(exceeded)
+			iter.ctx.Eligibility().SetQuotaLimitReached(iter.quota)
+		}
+	}
+
+	if iter.exhausted {
+		return nil
+	}
+	return iter.source.Next()
+}
+
+// proposedUsage returns the usage of the quota if the plan were applied and
+// the task group placed.
+func (iter *QuotaIterator) proposedUsage() *structs.QuotaLimit {
+	proposed := iter.used.Copy()
+
+	// removeExisting removes the usage of an allocation of the job that is
+	// accounted in the current usage. An allocation is only removed once
+	// even if the plan references it multiple times.
+	removed := make(map[string]struct{})
+	removeExisting := func(allocID string) {
+		if _, ok := removed[allocID]; ok {
+			return
+		}
+		if existing, ok := iter.existing[allocID]; ok {
+			removed[allocID] = struct{}{}
+			proposed.SubtractAlloc(existing)
+		}
+	}
+
+	plan := iter.ctx.Plan()
+	for _, updates := range plan.NodeUpdate {
+		for _, alloc := range updates {
+			removeExisting(alloc.ID)
+		}
+	}
+	for _, preemptions := range plan.NodePreemptions {
+		for _, alloc := range preemptions {
+			if _, ok := iter.existing[alloc.ID]; ok {
+				removeExisting(alloc.ID)
+			} else if _, ok := removed[alloc.ID]; !ok && alloc.Namespace == iter.job.Namespace {
+				removed[alloc.ID] = struct{}{}
+				proposed.SubtractAlloc(alloc)
+			}
+		}
+	}
+	for _, allocs := range plan.NodeAllocation {
+		for _, alloc := range allocs {
+			removeExisting(alloc.ID)
+			if !alloc.TerminalStatus() {
+				proposed.AddAlloc(alloc)
+			}
+		}
+	}
+
+	proposed.AddResources(taskGroupQuotaAsk(iter.tg))
+	return proposed
+}
+
+// taskGroupQuotaAsk returns the resources a placement of the task group
+// accounts against a quota.
+func taskGroupQuotaAsk(tg *structs.TaskGroup) *structs.ComparableResources {
+	ask := &structs.ComparableResources{}
+	for _, task := range tg.Tasks {
+		if task.Resources == nil {
+			continue
+		}
+		ask.Flattened.Cpu.CpuShares += int64(task.Resources.CPU)
+		ask.Flattened.Memory.MemoryMB += int64(task.Resources.MemoryMB)
+	}
+	if tg.EphemeralDisk != nil {
+		ask.Shared.DiskMB = int64(tg.EphemeralDisk.SizeMB)
+	}
+	return ask
+}
+
+func (iter *QuotaIterator) Reset() {
+	iter.checked = false
+	iter.source.Reset()
+}
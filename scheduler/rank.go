@@ -22,6 +22,10 @@ type RankedNode struct {
 	Scores        []float64
 	TaskResources map[string]*structs.AllocatedTaskResources
 
+	// AllocResources are the resources shared by the tasks of the
+	// allocation, set by the BinPackIterator
+	AllocResources *structs.AllocatedSharedResources
+
 	// Allocs is used to cache the proposed allocations on the
 	// node. This can be shared between iterators that require it.
 	Proposed []*structs.Allocation
@@ -148,6 +152,7 @@ type BinPackIterator struct {
 	evict     bool
 	priority  int
 	taskGroup *structs.TaskGroup
+	scoreFit  func(*structs.Node, *structs.ComparableResources) float64
 }
 
 // NewBinPackIterator returns a BinPackIterator which tries to fit tasks
@@ -158,6 +163,7 @@ func NewBinPackIterator(ctx Context, source RankIterator, evict bool, priority i
 		source:   source,
 		evict:    evict,
 		priority: priority,
+		scoreFit: structs.ScoreFit,
 	}
 	return iter
 }
@@ -166,6 +172,16 @@ func (iter *BinPackIterator) SetPriority(p int) {
 	iter.priority = p
 }
 
+// SetSchedulerAlgorithm sets whether nodes are scored to pack allocations
+// onto as few nodes as possible or to spread them across nodes.
+func (iter *BinPackIterator) SetSchedulerAlgorithm(algorithm structs.SchedulerAlgorithm) {
+	if algorithm == structs.SchedulerAlgorithmSpread {
+		iter.scoreFit = structs.ScoreFitSpread
+	} else {
+		iter.scoreFit = structs.ScoreFit
+	}
+}
+
 func (iter *BinPackIterator) SetTaskGroup(taskGroup *structs.TaskGroup) {
 	iter.taskGroup = taskGroup
 }
@@ -195,6 +211,14 @@ OUTER:
 		devAllocator := newDeviceAllocator(iter.ctx, option.Node)
 		devAllocator.AddAllocs(proposed)
 
+		// Account for the tasks bound to NUMA nodes
+		numaAccounter := structs.NewNumaAccounter(option.Node)
+		numaAccounter.AddAllocs(proposed)
+
+		// Account for the cores dedicated to tasks
+		coreAccounter := structs.NewCoreAccounter(option.Node)
+		coreAccounter.AddAllocs(proposed)
+
 		// Track the affinities of the devices
 		totalDeviceAffinityWeight := 0.0
 		sumMatchingAffinities := 0.0
@@ -222,6 +246,22 @@ OUTER:
 		}
 		preemptor.SetPreemptions(currentPreemptions)
 
+		// Assign the ports of a bridge network, shared by the tasks
+		if bridge := iter.taskGroup.Networks.Bridge(); bridge != nil {
+			ask := bridge.Copy()
+			offer, err := netIdx.AssignNetwork(ask)
+			if offer == nil {
+				iter.ctx.Metrics().ExhaustedNode(option.Node, fmt.Sprintf("network: %s", err))
+				netIdx.Release()
+				continue OUTER
+			}
+
+			// Reserve this to prevent the tasks from colliding
+			netIdx.AddReserved(offer)
+			total.Shared.Networks = []*structs.NetworkResource{offer}
+		}
+		option.AllocResources = &total.Shared
+
 		for _, task := range iter.taskGroup.Tasks {
 			// Allocate the resources
 			taskResources := &structs.AllocatedTaskResources{
@@ -233,6 +273,11 @@ OUTER:
 				},
 			}
 
+			// Tasks with dedicated cores get the CPU shares of their cores
+			if task.Resources.Cores > 0 && option.Node.NodeResources != nil {
+				taskResources.Cpu.CpuShares = int64(task.Resources.Cores) * option.Node.NodeResources.Cpu.SharesPerCore()
+			}
+
 			// Check if we need a network resource
 			if len(task.Resources.Networks) > 0 {
 				ask := task.Resources.Networks[0].Copy()
@@ -302,6 +347,32 @@ OUTER:
 				}
 			}
 
+			// Bind the task to a single NUMA node if required
+			if task.Resources.NUMA.Required() {
+				numa, err := numaAccounter.Assign(taskResources.Cpu.CpuShares, taskResources.Memory.MemoryMB)
+				if err != nil {
+					iter.ctx.Metrics().ExhaustedNode(option.Node, fmt.Sprintf("numa: %s", err))
+					continue OUTER
+				}
+				taskResources.Numa = numa
+				numaAccounter.AddReserved(taskResources)
+			}
+
+			// Dedicate cores to the task, on its NUMA node if it is bound to one
+			if task.Resources.Cores > 0 {
+				var numaNode *structs.NodeNumaNode
+				if taskResources.Numa != nil {
+					numaNode = numaAccounter.Nodes[taskResources.Numa.NodeID].Node
+				}
+				cores, err := coreAccounter.Assign(task.Resources.Cores, numaNode)
+				if err != nil {
+					iter.ctx.Metrics().ExhaustedNode(option.Node, fmt.Sprintf("cores: %s", err))
+					continue OUTER
+				}
+				taskResources.Cpu.ReservedCores = cores
+				coreAccounter.AddReserved(taskResources)
+			}
+
 			// Store the task resource
 			option.SetTaskResources(task, taskResources)
 
@@ -346,7 +417,7 @@ OUTER:
 		}
 
 		// Score the fit normally otherwise
-		fitness := structs.ScoreFit(option.Node, util)
+		fitness := iter.scoreFit(option.Node, util)
 		normalizedFit := fitness / binPackingMaxFitScore
 		option.Scores = append(option.Scores, normalizedFit)
 		iter.ctx.Metrics().ScoreNode(option.Node, "binpack", normalizedFit)
@@ -489,6 +560,10 @@ type NodeAffinityIterator struct {
 	source        RankIterator
 	jobAffinities []*structs.Affinity
 	affinities    []*structs.Affinity
+
+	// sumWeight is the sum of the absolute weights of the merged affinities,
+	// used to normalize the affinity score into [-1, 1]
+	sumWeight float64
 }
 
 // NewNodeAffinityIterator is used to create a NodeAffinityIterator that
@@ -520,12 +595,19 @@ func (iter *NodeAffinityIterator) SetTaskGroup(tg *structs.TaskGroup) {
 			iter.affinities = append(iter.affinities, task.Affinities...)
 		}
 	}
+
+	// Calculate the normalizing weight once per task group
+	iter.sumWeight = 0.0
+	for _, affinity := range iter.affinities {
+		iter.sumWeight += math.Abs(affinity.Weight)
+	}
 }
 
 func (iter *NodeAffinityIterator) Reset() {
 	iter.source.Reset()
 	// This method is called between each task group, so only reset the merged list
 	iter.affinities = nil
+	iter.sumWeight = 0.0
 }
 
 func (iter *NodeAffinityIterator) hasAffinities() bool {
@@ -541,19 +623,13 @@ func (iter *NodeAffinityIterator) Next() *RankedNode {
 		iter.ctx.Metrics().ScoreNode(option.Node, "node-affinity", 0)
 		return option
 	}
-	// TODO(preetha): we should calculate normalized weights once and reuse it here
-	sumWeight := 0.0
-	for _, affinity := range iter.affinities {
-		sumWeight += math.Abs(affinity.Weight)
-	}
-
 	totalAffinityScore := 0.0
 	for _, affinity := range iter.affinities {
 		if matchesAffinity(iter.ctx, affinity, option.Node) {
 			totalAffinityScore += affinity.Weight
 		}
 	}
-	normScore := totalAffinityScore / sumWeight
+	normScore := totalAffinityScore / iter.sumWeight
 	if totalAffinityScore != 0.0 {
 		option.Scores = append(option.Scores, normScore)
 		iter.ctx.Metrics().ScoreNode(option.Node, "node-affinity", normScore)
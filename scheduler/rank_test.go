@@ -127,6 +127,240 @@ func TestBinPackIterator_NoExistingAlloc(t *testing.T) {
 	}
 }
 
+func TestBinPackIterator_SpreadAlgorithm(t *testing.T) {
+	_, ctx := testContext(t)
+	nodes := []*RankedNode{
+		{
+			Node: &structs.Node{
+				// Perfect fit
+				NodeResources: &structs.NodeResources{
+					Cpu: structs.NodeCpuResources{
+						CpuShares: 2048,
+					},
+					Memory: structs.NodeMemoryResources{
+						MemoryMB: 2048,
+					},
+				},
+				ReservedResources: &structs.NodeReservedResources{
+					Cpu: structs.NodeReservedCpuResources{
+						CpuShares: 1024,
+					},
+					Memory: structs.NodeReservedMemoryResources{
+						MemoryMB: 1024,
+					},
+				},
+			},
+		},
+		{
+			Node: &structs.Node{
+				// 25% fit
+				NodeResources: &structs.NodeResources{
+					Cpu: structs.NodeCpuResources{
+						CpuShares: 4096,
+					},
+					Memory: structs.NodeMemoryResources{
+						MemoryMB: 4096,
+					},
+				},
+			},
+		},
+	}
+	static := NewStaticRankIterator(ctx, nodes)
+
+	taskGroup := &structs.TaskGroup{
+		EphemeralDisk: &structs.EphemeralDisk{},
+		Tasks: []*structs.Task{
+			{
+				Name: "web",
+				Resources: &structs.Resources{
+					CPU:      1024,
+					MemoryMB: 1024,
+				},
+			},
+		},
+	}
+	binp := NewBinPackIterator(ctx, static, false, 0)
+	binp.SetSchedulerAlgorithm(structs.SchedulerAlgorithmSpread)
+	binp.SetTaskGroup(taskGroup)
+
+	scoreNorm := NewScoreNormalizationIterator(ctx, binp)
+
+	out := collectRanked(scoreNorm)
+	require := require.New(t)
+	require.Len(out, 2)
+
+	// The perfect fit is the worst option when spreading
+	require.Equal(0.0, out[0].FinalScore)
+	require.True(out[1].FinalScore > 0.5, "bad score: %v", out[1].FinalScore)
+}
+
+func TestBinPackIterator_Numa(t *testing.T) {
+	numaNode := func() *structs.Node {
+		n := mock.Node()
+		n.NodeResources.NumaNodes = []*structs.NodeNumaNode{
+			{ID: 0, Cores: "0-1", CpuShares: 2000, MemoryMB: 4096},
+			{ID: 1, Cores: "2-3", CpuShares: 2000, MemoryMB: 2048},
+		}
+		return n
+	}
+	numaTaskGroup := func(cpu, mem int) *structs.TaskGroup {
+		return &structs.TaskGroup{
+			EphemeralDisk: &structs.EphemeralDisk{},
+			Tasks: []*structs.Task{
+				{
+					Name: "web",
+					Resources: &structs.Resources{
+						CPU:      cpu,
+						MemoryMB: mem,
+						NUMA:     &structs.NUMA{Affinity: structs.NUMAAffinityRequire},
+					},
+				},
+			},
+		}
+	}
+
+	cases := []struct {
+		Name           string
+		Node           *structs.Node
+		TaskGroup      *structs.TaskGroup
+		NoPlace        bool
+		ExpectedNodeID int
+	}{
+		{
+			Name:           "smallest fitting numa node",
+			Node:           numaNode(),
+			TaskGroup:      numaTaskGroup(1000, 1024),
+			ExpectedNodeID: 1,
+		},
+		{
+			Name:           "only larger numa node fits",
+			Node:           numaNode(),
+			TaskGroup:      numaTaskGroup(1000, 3072),
+			ExpectedNodeID: 0,
+		},
+		{
+			Name:      "no numa node fits",
+			Node:      numaNode(),
+			TaskGroup: numaTaskGroup(1000, 5000),
+			NoPlace:   true,
+		},
+		{
+			Name:      "no numa topology",
+			Node:      mock.Node(),
+			TaskGroup: numaTaskGroup(1000, 1024),
+			NoPlace:   true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			require := require.New(t)
+			_, ctx := testContext(t)
+			static := NewStaticRankIterator(ctx, []*RankedNode{{Node: c.Node}})
+			binp := NewBinPackIterator(ctx, static, false, 0)
+			binp.SetTaskGroup(c.TaskGroup)
+
+			out := binp.Next()
+			if c.NoPlace {
+				require.Nil(out)
+				require.Equal(1, ctx.Metrics().NodesExhausted)
+				return
+			}
+
+			require.NotNil(out)
+			numa := out.TaskResources["web"].Numa
+			require.NotNil(numa)
+			require.Equal(c.ExpectedNodeID, numa.NodeID)
+		})
+	}
+}
+
+func TestBinPackIterator_Cores(t *testing.T) {
+	coresNode := func() *structs.Node {
+		n := mock.Node()
+		n.NodeResources.Cpu.TotalCpuCores = 4
+		n.NodeResources.Cpu.ReservableCpuCores = []uint16{1, 2, 3}
+		n.NodeResources.NumaNodes = []*structs.NodeNumaNode{
+			{ID: 0, Cores: "0-1", CpuShares: 2000, MemoryMB: 8192},
+			{ID: 1, Cores: "2-3", CpuShares: 2000, MemoryMB: 4096},
+		}
+		return n
+	}
+	coresTaskGroup := func(cores int, numa bool) *structs.TaskGroup {
+		r := &structs.Resources{
+			Cores:    cores,
+			MemoryMB: 1024,
+		}
+		if numa {
+			r.NUMA = &structs.NUMA{Affinity: structs.NUMAAffinityRequire}
+		}
+		return &structs.TaskGroup{
+			EphemeralDisk: &structs.EphemeralDisk{},
+			Tasks: []*structs.Task{
+				{
+					Name:      "web",
+					Resources: r,
+				},
+			},
+		}
+	}
+
+	cases := []struct {
+		Name          string
+		Node          *structs.Node
+		TaskGroup     *structs.TaskGroup
+		NoPlace       bool
+		ExpectedCores []uint16
+	}{
+		{
+			Name:          "lowest reservable cores",
+			Node:          coresNode(),
+			TaskGroup:     coresTaskGroup(2, false),
+			ExpectedCores: []uint16{1, 2},
+		},
+		{
+			Name:          "cores of the numa node",
+			Node:          coresNode(),
+			TaskGroup:     coresTaskGroup(2, true),
+			ExpectedCores: []uint16{2, 3},
+		},
+		{
+			Name:      "not enough reservable cores",
+			Node:      coresNode(),
+			TaskGroup: coresTaskGroup(4, false),
+			NoPlace:   true,
+		},
+		{
+			Name:      "no reservable cores",
+			Node:      mock.Node(),
+			TaskGroup: coresTaskGroup(1, false),
+			NoPlace:   true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			require := require.New(t)
+			_, ctx := testContext(t)
+			static := NewStaticRankIterator(ctx, []*RankedNode{{Node: c.Node}})
+			binp := NewBinPackIterator(ctx, static, false, 0)
+			binp.SetTaskGroup(c.TaskGroup)
+
+			out := binp.Next()
+			if c.NoPlace {
+				require.Nil(out)
+				require.Equal(1, ctx.Metrics().NodesExhausted)
+				return
+			}
+
+			require.NotNil(out)
+			cpu := out.TaskResources["web"].Cpu
+			require.Equal(c.ExpectedCores, cpu.ReservedCores)
+			require.Equal(int64(len(c.ExpectedCores))*1000, cpu.CpuShares)
+		})
+	}
+}
+
 func TestBinPackIterator_PlannedAlloc(t *testing.T) {
 	_, ctx := testContext(t)
 	nodes := []*RankedNode{
@@ -229,6 +463,75 @@ func TestBinPackIterator_PlannedAlloc(t *testing.T) {
 	}
 }
 
+// Tests that the ports of bridge networks are assigned once for the task group
+// and collide with the group ports of other allocations
+func TestBinPackIterator_BridgeNetwork(t *testing.T) {
+	require := require.New(t)
+	_, ctx := testContext(t)
+	nodes := []*RankedNode{
+		{Node: mock.Node()},
+		{Node: mock.Node()},
+	}
+	static := NewStaticRankIterator(ctx, nodes)
+
+	// Add a planned alloc to node1 using the static port
+	plan := ctx.Plan()
+	plan.NodeAllocation[nodes[0].Node.ID] = []*structs.Allocation{
+		{
+			AllocatedResources: &structs.AllocatedResources{
+				Shared: structs.AllocatedSharedResources{
+					Networks: structs.Networks{
+						{
+							Mode:          structs.NetworkModeBridge,
+							IP:            "192.168.0.100",
+							ReservedPorts: []structs.Port{{Label: "metrics", Value: 9100}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	taskGroup := &structs.TaskGroup{
+		EphemeralDisk: &structs.EphemeralDisk{},
+		Networks: structs.Networks{
+			{
+				Mode:          structs.NetworkModeBridge,
+				ReservedPorts: []structs.Port{{Label: "metrics", Value: 9100}},
+				DynamicPorts:  []structs.Port{{Label: "http", To: 8080}},
+			},
+		},
+		Tasks: []*structs.Task{
+			{
+				Name: "web",
+				Resources: &structs.Resources{
+					CPU:      1024,
+					MemoryMB: 1024,
+				},
+			},
+		},
+	}
+
+	binp := NewBinPackIterator(ctx, static, false, 0)
+	binp.SetTaskGroup(taskGroup)
+
+	out := collectRanked(binp)
+	require.Len(out, 1)
+	require.Equal(nodes[1], out[0])
+
+	networks := out[0].AllocResources.Networks
+	require.Len(networks, 1)
+	require.Equal(structs.NetworkModeBridge, networks[0].Mode)
+	require.Equal("192.168.0.100", networks[0].IP)
+	require.Equal(9100, networks[0].ReservedPorts[0].Value)
+	require.Equal(8080, networks[0].DynamicPorts[0].To)
+	require.NotZero(networks[0].DynamicPorts[0].Value)
+	require.Empty(out[0].TaskResources["web"].Networks)
+
+	// The ask of the task group is not modified
+	require.Zero(taskGroup.Networks[0].DynamicPorts[0].Value)
+}
+
 func TestBinPackIterator_ExistingAlloc(t *testing.T) {
 	state, ctx := testContext(t)
 	nodes := []*RankedNode{
@@ -1045,3 +1348,60 @@ func TestNodeAffinityIterator(t *testing.T) {
 	}
 
 }
+
+func TestNodeAffinityIterator_JobAndTask(t *testing.T) {
+	_, ctx := testContext(t)
+	nodes := []*RankedNode{
+		{Node: mock.Node()},
+		{Node: mock.Node()},
+	}
+	nodes[1].Node.Datacenter = "dc2"
+	nodes[1].Node.NodeClass = "large"
+
+	job := mock.Job()
+	job.Affinities = []*structs.Affinity{
+		{
+			Operand: "=",
+			LTarget: "${node.datacenter}",
+			RTarget: "dc2",
+			Weight:  50,
+		},
+	}
+	tg := job.TaskGroups[0]
+
+	// A negative weight on the task acts as an anti-affinity
+	tg.Tasks[0].Affinities = []*structs.Affinity{
+		{
+			Operand: "=",
+			LTarget: "${node.class}",
+			RTarget: "large",
+			Weight:  -150,
+		},
+	}
+
+	static := NewStaticRankIterator(ctx, nodes)
+	nodeAffinity := NewNodeAffinityIterator(ctx, static)
+	nodeAffinity.SetJob(job)
+	nodeAffinity.SetTaskGroup(tg)
+	scoreNorm := NewScoreNormalizationIterator(ctx, nodeAffinity)
+
+	out := collectRanked(scoreNorm)
+	require := require.New(t)
+	require.Len(out, 2)
+
+	// Total weight = 200
+	expectedScores := map[string]float64{
+		// Node 0 matches no affinities
+		nodes[0].Node.ID: 0,
+
+		// Node 1 matches the job affinity and the task anti-affinity
+		nodes[1].Node.ID: -0.5,
+	}
+	for _, n := range out {
+		require.Equal(expectedScores[n.Node.ID], n.FinalScore)
+	}
+
+	// Resetting clears the merged affinities of the previous task group
+	nodeAffinity.Reset()
+	require.False(nodeAffinity.hasAffinities())
+}
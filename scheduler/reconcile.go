@@ -112,6 +112,10 @@ type reconcileResults struct {
 	// jobspec change.
 	attributeUpdates map[string]*structs.Allocation
 
+	// disconnectUpdates are allocations on disconnected nodes that are marked
+	// unknown rather than lost.
+	disconnectUpdates map[string]*structs.Allocation
+
 	// desiredTGUpdates captures the desired set of changes to make for each
 	// task group.
 	desiredTGUpdates map[string]*structs.DesiredUpdates
@@ -173,6 +177,7 @@ func NewAllocReconciler(logger log.Logger, allocUpdateFn allocUpdateType, batch
 		result: &reconcileResults{
 			desiredTGUpdates:     make(map[string]*structs.DesiredUpdates),
 			desiredFollowupEvals: make(map[string][]*structs.Evaluation),
+			disconnectUpdates:    make(map[string]*structs.Allocation),
 		},
 	}
 }
@@ -193,10 +198,14 @@ func (a *allocReconciler) Compute() *reconcileResults {
 		return a.result
 	}
 
-	// Detect if the deployment is paused
+	// Detect if the deployment is paused. A pending deployment of a
+	// multiregion job is treated as paused until its peer regions start it.
 	if a.deployment != nil {
-		a.deploymentPaused = a.deployment.Status == structs.DeploymentStatusPaused
+		a.deploymentPaused = a.deployment.Status == structs.DeploymentStatusPaused ||
+			a.deployment.Status == structs.DeploymentStatusPending
 		a.deploymentFailed = a.deployment.Status == structs.DeploymentStatusFailed
+	} else if a.multiregionStartsPending() {
+		a.deploymentPaused = true
 	}
 
 	// Reconcile each group
@@ -206,13 +215,26 @@ func (a *allocReconciler) Compute() *reconcileResults {
 		complete = complete && groupComplete
 	}
 
-	// Mark the deployment as complete if possible
+	// Mark the deployment as complete if possible. The deployment of a
+	// multiregion job is blocked until the deployments of its peer regions
+	// complete as well.
 	if a.deployment != nil && complete {
-		a.result.deploymentUpdates = append(a.result.deploymentUpdates, &structs.DeploymentStatusUpdate{
-			DeploymentID:      a.deployment.ID,
-			Status:            structs.DeploymentStatusSuccessful,
-			StatusDescription: structs.DeploymentStatusDescriptionSuccessful,
-		})
+		if a.deployment.IsMultiregion {
+			if a.deployment.Status != structs.DeploymentStatusPending &&
+				a.deployment.Status != structs.DeploymentStatusBlocked {
+				a.result.deploymentUpdates = append(a.result.deploymentUpdates, &structs.DeploymentStatusUpdate{
+					DeploymentID:      a.deployment.ID,
+					Status:            structs.DeploymentStatusBlocked,
+					StatusDescription: structs.DeploymentStatusDescriptionBlocked,
+				})
+			}
+		} else {
+			a.result.deploymentUpdates = append(a.result.deploymentUpdates, &structs.DeploymentStatusUpdate{
+				DeploymentID:      a.deployment.ID,
+				Status:            structs.DeploymentStatusSuccessful,
+				StatusDescription: structs.DeploymentStatusDescriptionSuccessful,
+			})
+		}
 	}
 
 	// Set the description of a created deployment
@@ -225,6 +247,25 @@ func (a *allocReconciler) Compute() *reconcileResults {
 	return a.result
 }
 
+// multiregionStartsPending returns whether a deployment created for the job
+// would wait for the regions before it in the rollout of a multiregion job.
+// Only jobs with an update strategy create deployments and a job version is
+// only deployed once.
+func (a *allocReconciler) multiregionStartsPending() bool {
+	if !a.job.IsMultiregion() || !a.job.Multiregion.StartsPending(a.job.Region) {
+		return false
+	}
+	if d := a.oldDeployment; d != nil && d.JobCreateIndex == a.job.CreateIndex && d.JobVersion == a.job.Version {
+		return false
+	}
+	for _, tg := range a.job.TaskGroups {
+		if tg.Update != nil {
+			return true
+		}
+	}
+	return false
+}
+
 // cancelDeployments cancels any deployment that is not needed
 func (a *allocReconciler) cancelDeployments() {
 	// If the job is stopped and there is a non-terminal deployment, cancel it
@@ -327,6 +368,7 @@ func (a *allocReconciler) computeGroup(group string, all allocSet) bool {
 		dstate = &structs.DeploymentState{}
 		if tg.Update != nil {
 			dstate.AutoRevert = tg.Update.AutoRevert
+			dstate.AutoPromote = tg.Update.AutoPromote
 			dstate.ProgressDeadline = tg.Update.ProgressDeadline
 		}
 	}
@@ -343,6 +385,9 @@ func (a *allocReconciler) computeGroup(group string, all allocSet) bool {
 	// Determine what set of allocations are on tainted nodes
 	untainted, migrate, lost := all.filterByTainted(a.taintedNodes)
 
+	// Keep the allocations of disconnected nodes that may still reconnect
+	untainted, lost = a.handleDisconnected(tg, untainted, lost)
+
 	// Determine what set of terminal allocations need to be rescheduled
 	untainted, rescheduleNow, rescheduleLater := untainted.filterByRescheduleable(a.batch, a.now, a.evalID, a.deployment)
 
@@ -350,9 +395,13 @@ func (a *allocReconciler) computeGroup(group string, all allocSet) bool {
 	// reschedulable later and mark the allocations for in place updating
 	a.handleDelayedReschedules(rescheduleLater, all, tg.Name)
 
+	// Lost allocations with tasks on remote task drivers are replaced by
+	// allocations of the same name that recover the tasks
+	remoteLost := lost.filterByTaskHandles()
+
 	// Create a structure for choosing names. Seed with the taken names which is
 	// the union of untainted and migrating nodes (includes canaries)
-	nameIndex := newAllocNameIndex(a.jobID, group, tg.Count, untainted.union(migrate, rescheduleNow))
+	nameIndex := newAllocNameIndex(a.jobID, group, tg.Count, untainted.union(migrate, rescheduleNow, remoteLost))
 
 	// Stop any unneeded allocations and update the untainted set to not
 	// included stopped allocations.
@@ -406,7 +455,7 @@ func (a *allocReconciler) computeGroup(group string, all allocSet) bool {
 	// * The deployment is not paused or failed
 	// * Not placing any canaries
 	// * If there are any canaries that they have been promoted
-	place := a.computePlacements(tg, nameIndex, untainted, migrate, rescheduleNow)
+	place := a.computePlacements(tg, nameIndex, untainted, migrate, rescheduleNow, remoteLost)
 	if !existingDeployment {
 		dstate.DesiredTotal += len(place)
 	}
@@ -618,7 +667,7 @@ func (a *allocReconciler) computeLimit(group *structs.TaskGroup, untainted, dest
 	// If we have been promoted or there are no canaries, the limit is the
 	// configured MaxParallel minus any outstanding non-healthy alloc for the
 	// deployment
-	limit := group.Update.MaxParallel
+	limit := group.Update.MaxParallelFor(group.Count)
 	if a.deployment != nil {
 		partOf, _ := untainted.filterByDeployment(a.deployment.ID)
 		for _, alloc := range partOf {
@@ -645,7 +694,8 @@ func (a *allocReconciler) computeLimit(group *structs.TaskGroup, untainted, dest
 // computePlacement returns the set of allocations to place given the group
 // definition, the set of untainted, migrating and reschedule allocations for the group.
 func (a *allocReconciler) computePlacements(group *structs.TaskGroup,
-	nameIndex *allocNameIndex, untainted, migrate allocSet, reschedule allocSet) []allocPlaceResult {
+	nameIndex *allocNameIndex, untainted, migrate allocSet, reschedule allocSet,
+	remoteLost allocSet) []allocPlaceResult {
 
 	// Add rescheduled placement results
 	var place []allocPlaceResult
@@ -665,6 +715,23 @@ func (a *allocReconciler) computePlacements(group *structs.TaskGroup,
 		return place
 	}
 
+	// Replace the lost allocations running remote tasks up to the count so
+	// the replacements can recover their tasks
+	for _, alloc := range remoteLost {
+		if existing >= group.Count {
+			break
+		}
+
+		existing++
+		place = append(place, allocPlaceResult{
+			name:          alloc.Name,
+			taskGroup:     group,
+			previousAlloc: alloc,
+			canary:        alloc.DeploymentStatus.IsCanary(),
+			lost:          true,
+		})
+	}
+
 	// Add remaining placement results
 	if existing < group.Count {
 		for _, name := range nameIndex.Next(uint(group.Count - existing)) {
@@ -813,6 +880,65 @@ func (a *allocReconciler) computeUpdates(group *structs.TaskGroup, untainted all
 	return
 }
 
+// handleDisconnected keeps the lost allocations of a task group that
+// tolerates disconnected clients and returns the updated untainted and lost
+// sets. Allocations on a down node are marked unknown and a follow up
+// evaluation is created for when their max_client_disconnect expires. Once it
+// has expired, or if their node was garbage collected, they remain lost and
+// are replaced.
+func (a *allocReconciler) handleDisconnected(tg *structs.TaskGroup, untainted, lost allocSet) (allocSet, allocSet) {
+	if tg.MaxClientDisconnect == nil || len(lost) == 0 {
+		return untainted, lost
+	}
+
+	keep := make(map[string]*structs.Allocation)
+	marked := 0
+	for _, alloc := range lost {
+		node := a.taintedNodes[alloc.NodeID]
+		if node == nil || node.Status != structs.NodeStatusDown {
+			continue
+		}
+
+		if alloc.ClientStatus == structs.AllocClientStatusUnknown {
+			if !alloc.DisconnectTimeout(*tg.MaxClientDisconnect).After(a.now) {
+				continue
+			}
+		} else {
+			updated := alloc.Copy()
+			updated.ClientStatus = structs.AllocClientStatusUnknown
+			updated.ClientDescription = allocUnknown
+			updated.AllocStates = append(updated.AllocStates, &structs.AllocState{
+				Field: structs.AllocStateFieldClientStatus,
+				Value: structs.AllocClientStatusUnknown,
+				Time:  a.now.UTC(),
+			})
+			a.result.disconnectUpdates[updated.ID] = updated
+			marked++
+		}
+		keep[alloc.ID] = alloc
+	}
+
+	// Allocations marked unknown by an earlier evaluation already have a
+	// follow up evaluation
+	if marked > 0 {
+		eval := &structs.Evaluation{
+			ID:                uuid.Generate(),
+			Namespace:         a.job.Namespace,
+			Priority:          a.job.Priority,
+			Type:              a.job.Type,
+			TriggeredBy:       structs.EvalTriggerMaxDisconnect,
+			JobID:             a.job.ID,
+			JobModifyIndex:    a.job.ModifyIndex,
+			Status:            structs.EvalStatusPending,
+			StatusDescription: disconnectTimeoutFollowupEvalDesc,
+			WaitUntil:         a.now.Add(*tg.MaxClientDisconnect),
+		}
+		a.result.desiredFollowupEvals[tg.Name] = append(a.result.desiredFollowupEvals[tg.Name], eval)
+	}
+
+	return untainted.union(keep), lost.difference(keep)
+}
+
 // handleDelayedReschedules creates batched followup evaluations with the WaitUntil field set
 // for allocations that are eligible to be rescheduled later
 func (a *allocReconciler) handleDelayedReschedules(rescheduleLater []*delayedRescheduleInfo, all allocSet, tgName string) {
@@ -852,15 +978,16 @@ func (a *allocReconciler) handleDelayedReschedules(rescheduleLater []*delayedRes
 			nextReschedTime = allocReschedInfo.rescheduleTime
 			// Create a new eval for the new batch
 			eval = &structs.Evaluation{
-				ID:             uuid.Generate(),
-				Namespace:      a.job.Namespace,
-				Priority:       a.job.Priority,
-				Type:           a.job.Type,
-				TriggeredBy:    structs.EvalTriggerRetryFailedAlloc,
-				JobID:          a.job.ID,
-				JobModifyIndex: a.job.ModifyIndex,
-				Status:         structs.EvalStatusPending,
-				WaitUntil:      nextReschedTime,
+				ID:                uuid.Generate(),
+				Namespace:         a.job.Namespace,
+				Priority:          a.job.Priority,
+				Type:              a.job.Type,
+				TriggeredBy:       structs.EvalTriggerRetryFailedAlloc,
+				JobID:             a.job.ID,
+				JobModifyIndex:    a.job.ModifyIndex,
+				Status:            structs.EvalStatusPending,
+				StatusDescription: reschedulingFollowupEvalDesc,
+				WaitUntil:         nextReschedTime,
 			}
 			evals = append(evals, eval)
 			// Set the evalID for the first alloc in this new batch
@@ -868,7 +995,7 @@ func (a *allocReconciler) handleDelayedReschedules(rescheduleLater []*delayedRes
 		}
 	}
 
-	a.result.desiredFollowupEvals[tgName] = evals
+	a.result.desiredFollowupEvals[tgName] = append(a.result.desiredFollowupEvals[tgName], evals...)
 
 	// Initialize the annotations
 	if len(allocIDToFollowupEvalID) != 0 && a.result.attributeUpdates == nil {
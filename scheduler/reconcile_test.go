@@ -736,6 +736,131 @@ func TestReconciler_LostNode(t *testing.T) {
 	assertNamesHaveIndexes(t, intRange(0, 1), placeResultsToNames(r.place))
 }
 
+// Tests the reconciler marks allocations on disconnected nodes unknown instead
+// of replacing them when the group sets max_client_disconnect
+func TestReconciler_LostNode_MaxClientDisconnect(t *testing.T) {
+	job := mock.Job()
+	job.TaskGroups[0].MaxClientDisconnect = helper.TimeToPtr(time.Hour)
+
+	// Create 10 existing allocations
+	var allocs []*structs.Allocation
+	for i := 0; i < 10; i++ {
+		alloc := mock.Alloc()
+		alloc.Job = job
+		alloc.JobID = job.ID
+		alloc.NodeID = uuid.Generate()
+		alloc.Name = structs.AllocName(job.ID, job.TaskGroups[0].Name, uint(i))
+		allocs = append(allocs, alloc)
+	}
+
+	// Build a map of tainted nodes
+	tainted := make(map[string]*structs.Node, 2)
+	for i := 0; i < 2; i++ {
+		n := mock.Node()
+		n.ID = allocs[i].NodeID
+		n.Status = structs.NodeStatusDown
+		tainted[n.ID] = n
+	}
+
+	reconciler := NewAllocReconciler(testlog.HCLogger(t), allocUpdateFnIgnore, false, job.ID, job, nil, allocs, tainted, "")
+	now := reconciler.now
+	r := reconciler.Compute()
+
+	// Assert the correct results
+	assertResults(t, r, &resultExpectation{
+		createDeployment:  nil,
+		deploymentUpdates: nil,
+		place:             0,
+		inplace:           0,
+		stop:              0,
+		desiredTGUpdates: map[string]*structs.DesiredUpdates{
+			job.TaskGroups[0].Name: {
+				Ignore: 10,
+			},
+		},
+	})
+
+	require := require.New(t)
+	require.Len(r.disconnectUpdates, 2)
+	for i := 0; i < 2; i++ {
+		update := r.disconnectUpdates[allocs[i].ID]
+		require.NotNil(update)
+		require.Equal(structs.AllocClientStatusUnknown, update.ClientStatus)
+		require.Equal(now.UTC(), update.LastUnknown())
+	}
+
+	evals := r.desiredFollowupEvals[job.TaskGroups[0].Name]
+	require.Len(evals, 1)
+	require.Equal(structs.EvalTriggerMaxDisconnect, evals[0].TriggeredBy)
+	require.Equal(now.Add(time.Hour), evals[0].WaitUntil)
+}
+
+// Tests the reconciler keeps unknown allocations within max_client_disconnect
+// and replaces them once it has expired
+func TestReconciler_LostNode_MaxClientDisconnect_Unknown(t *testing.T) {
+	job := mock.Job()
+	job.TaskGroups[0].MaxClientDisconnect = helper.TimeToPtr(time.Hour)
+	now := time.Now()
+
+	// Create 10 existing allocations, the first two were marked unknown two
+	// hours and ten minutes ago
+	var allocs []*structs.Allocation
+	for i := 0; i < 10; i++ {
+		alloc := mock.Alloc()
+		alloc.Job = job
+		alloc.JobID = job.ID
+		alloc.NodeID = uuid.Generate()
+		alloc.Name = structs.AllocName(job.ID, job.TaskGroups[0].Name, uint(i))
+		allocs = append(allocs, alloc)
+	}
+	allocs[0].ClientStatus = structs.AllocClientStatusUnknown
+	allocs[0].AllocStates = []*structs.AllocState{{
+		Field: structs.AllocStateFieldClientStatus,
+		Value: structs.AllocClientStatusUnknown,
+		Time:  now.Add(-2 * time.Hour),
+	}}
+	allocs[1].ClientStatus = structs.AllocClientStatusUnknown
+	allocs[1].AllocStates = []*structs.AllocState{{
+		Field: structs.AllocStateFieldClientStatus,
+		Value: structs.AllocClientStatusUnknown,
+		Time:  now.Add(-10 * time.Minute),
+	}}
+
+	// Build a map of tainted nodes
+	tainted := make(map[string]*structs.Node, 2)
+	for i := 0; i < 2; i++ {
+		n := mock.Node()
+		n.ID = allocs[i].NodeID
+		n.Status = structs.NodeStatusDown
+		tainted[n.ID] = n
+	}
+
+	reconciler := NewAllocReconciler(testlog.HCLogger(t), allocUpdateFnIgnore, false, job.ID, job, nil, allocs, tainted, "")
+	reconciler.now = now
+	r := reconciler.Compute()
+
+	// Assert the correct results
+	assertResults(t, r, &resultExpectation{
+		createDeployment:  nil,
+		deploymentUpdates: nil,
+		place:             1,
+		inplace:           0,
+		stop:              1,
+		desiredTGUpdates: map[string]*structs.DesiredUpdates{
+			job.TaskGroups[0].Name: {
+				Place:  1,
+				Stop:   1,
+				Ignore: 9,
+			},
+		},
+	})
+
+	require.Empty(t, r.disconnectUpdates)
+	require.Empty(t, r.desiredFollowupEvals[job.TaskGroups[0].Name])
+	assertNamesHaveIndexes(t, intRange(0, 0), stopResultsToNames(r.stop))
+	assertNamesHaveIndexes(t, intRange(0, 0), placeResultsToNames(r.place))
+}
+
 // Tests the reconciler properly handles lost nodes with allocations while
 // scaling up
 func TestReconciler_LostNode_ScaleUp(t *testing.T) {
@@ -834,6 +959,72 @@ func TestReconciler_LostNode_ScaleDown(t *testing.T) {
 	assertNamesHaveIndexes(t, intRange(0, 1, 7, 9), stopResultsToNames(r.stop))
 }
 
+// Tests the reconciler replaces lost allocations running remote tasks with
+// allocations of the same name chained to them
+func TestReconciler_LostNode_RemoteTasks(t *testing.T) {
+	job := mock.Job()
+
+	// Create 10 existing allocations
+	var allocs []*structs.Allocation
+	for i := 0; i < 10; i++ {
+		alloc := mock.Alloc()
+		alloc.Job = job
+		alloc.JobID = job.ID
+		alloc.NodeID = uuid.Generate()
+		alloc.Name = structs.AllocName(job.ID, job.TaskGroups[0].Name, uint(i))
+		allocs = append(allocs, alloc)
+	}
+
+	// Give the first alloc a remote task
+	allocs[0].TaskStates = map[string]*structs.TaskState{
+		"web": {
+			State:      structs.TaskStateRunning,
+			TaskHandle: &structs.TaskHandle{Driver: "remote", DriverState: []byte("state")},
+		},
+	}
+
+	// Build a map of tainted nodes
+	tainted := make(map[string]*structs.Node, 2)
+	for i := 0; i < 2; i++ {
+		n := mock.Node()
+		n.ID = allocs[i].NodeID
+		n.Status = structs.NodeStatusDown
+		tainted[n.ID] = n
+	}
+
+	reconciler := NewAllocReconciler(testlog.HCLogger(t), allocUpdateFnIgnore, false, job.ID, job, nil, allocs, tainted, "")
+	r := reconciler.Compute()
+
+	// Assert the correct results
+	assertResults(t, r, &resultExpectation{
+		createDeployment:  nil,
+		deploymentUpdates: nil,
+		place:             2,
+		inplace:           0,
+		stop:              2,
+		desiredTGUpdates: map[string]*structs.DesiredUpdates{
+			job.TaskGroups[0].Name: {
+				Place:  2,
+				Stop:   2,
+				Ignore: 8,
+			},
+		},
+	})
+
+	assertNamesHaveIndexes(t, intRange(0, 1), stopResultsToNames(r.stop))
+	assertNamesHaveIndexes(t, intRange(0, 1), placeResultsToNames(r.place))
+	assertPlaceResultsHavePreviousAllocs(t, 1, r.place)
+
+	for _, p := range r.place {
+		if p.previousAlloc == nil {
+			continue
+		}
+		if p.previousAlloc.ID != allocs[0].ID || !p.PreviousLost() {
+			t.Fatalf("unexpected placement replacing %q", p.previousAlloc.ID)
+		}
+	}
+}
+
 // Tests the reconciler properly handles draining nodes with allocations
 func TestReconciler_DrainNode(t *testing.T) {
 	job := mock.Job()
@@ -1395,6 +1586,11 @@ func TestReconciler_RescheduleLaterWithBatchedEvals_Batch(t *testing.T) {
 	secondBatchDuration := delayDur + 10*time.Second
 	require.Equal(now.Add(secondBatchDuration), evals[1].WaitUntil)
 
+	// Every batch is described as a delayed reschedule
+	for _, eval := range evals {
+		require.Equal(reschedulingFollowupEvalDesc, eval.StatusDescription)
+	}
+
 	// Alloc 5 should not be replaced because it is terminal
 	assertResults(t, r, &resultExpectation{
 		createDeployment:  nil,
@@ -1425,6 +1621,61 @@ func TestReconciler_RescheduleLaterWithBatchedEvals_Batch(t *testing.T) {
 	}
 }
 
+// Tests that batches of delayed reschedules span the window from the first
+// allocation of the batch, so allocs failing at a steady pace still split into
+// several evals.
+func TestReconciler_RescheduleLaterWithBatchedEvals_Window(t *testing.T) {
+	require := require.New(t)
+
+	job := mock.Job()
+	job.TaskGroups[0].Count = 4
+	now := time.Now()
+
+	// Set up reschedule policy
+	delayDur := 15 * time.Second
+	job.TaskGroups[0].ReschedulePolicy = &structs.ReschedulePolicy{Attempts: 3, Interval: 24 * time.Hour, Delay: delayDur, DelayFunction: "constant"}
+	tgName := job.TaskGroups[0].Name
+
+	// Create 4 failed allocations, each failing 3 seconds after the previous
+	var allocs []*structs.Allocation
+	for i := 0; i < 4; i++ {
+		alloc := mock.Alloc()
+		alloc.Job = job
+		alloc.JobID = job.ID
+		alloc.NodeID = uuid.Generate()
+		alloc.Name = structs.AllocName(job.ID, job.TaskGroups[0].Name, uint(i))
+		alloc.ClientStatus = structs.AllocClientStatusFailed
+		alloc.TaskStates = map[string]*structs.TaskState{tgName: {State: "start",
+			StartedAt:  now.Add(-1 * time.Hour),
+			FinishedAt: now.Add(time.Duration(3*i) * time.Second)}}
+		allocs = append(allocs, alloc)
+	}
+
+	reconciler := NewAllocReconciler(testlog.HCLogger(t), allocUpdateFnIgnore, true, job.ID, job, nil, allocs, nil, uuid.Generate())
+	r := reconciler.Compute()
+
+	// Allocs 0 and 1 fall in the first window, allocs 2 and 3 start a second
+	evals := r.desiredFollowupEvals[tgName]
+	require.Len(evals, 2)
+	require.Equal(now.Add(delayDur), evals[0].WaitUntil)
+	require.Equal(now.Add(delayDur+6*time.Second), evals[1].WaitUntil)
+
+	for _, eval := range evals {
+		require.Equal(structs.EvalTriggerRetryFailedAlloc, eval.TriggeredBy)
+		require.Equal(structs.EvalStatusPending, eval.Status)
+		require.Equal(reschedulingFollowupEvalDesc, eval.StatusDescription)
+	}
+
+	assertNamesHaveIndexes(t, intRange(0, 3), attributeUpdatesToNames(r.attributeUpdates))
+	for _, alloc := range r.attributeUpdates {
+		if allocNameToIndex(alloc.Name) < 2 {
+			require.Equal(evals[0].ID, alloc.FollowupEvalID)
+		} else {
+			require.Equal(evals[1].ID, alloc.FollowupEvalID)
+		}
+	}
+}
+
 // Tests rescheduling failed batch allocations
 func TestReconciler_RescheduleNow_Batch(t *testing.T) {
 	require := require.New(t)
@@ -2564,6 +2815,49 @@ func TestReconciler_CreateDeployment_RollingUpgrade_Destructive(t *testing.T) {
 	assertNamesHaveIndexes(t, intRange(0, 3), destructiveResultsToNames(r.destructiveUpdate))
 }
 
+// Tests the reconciler interprets a percentage max_parallel against the group
+// count
+func TestReconciler_CreateDeployment_RollingUpgrade_MaxParallelPercent(t *testing.T) {
+	job := mock.Job()
+	job.TaskGroups[0].Update = noCanaryUpdate.Copy()
+	job.TaskGroups[0].Update.MaxParallelPercent = 25
+
+	// Create 10 allocations from the old job
+	var allocs []*structs.Allocation
+	for i := 0; i < 10; i++ {
+		alloc := mock.Alloc()
+		alloc.Job = job
+		alloc.JobID = job.ID
+		alloc.NodeID = uuid.Generate()
+		alloc.Name = structs.AllocName(job.ID, job.TaskGroups[0].Name, uint(i))
+		alloc.TaskGroup = job.TaskGroups[0].Name
+		allocs = append(allocs, alloc)
+	}
+
+	reconciler := NewAllocReconciler(testlog.HCLogger(t), allocUpdateFnDestructive, false, job.ID, job, nil, allocs, nil, "")
+	r := reconciler.Compute()
+
+	d := structs.NewDeployment(job)
+	d.TaskGroups[job.TaskGroups[0].Name] = &structs.DeploymentState{
+		DesiredTotal: 10,
+	}
+
+	// 25% of 10 allocations is rounded up to 3
+	assertResults(t, r, &resultExpectation{
+		createDeployment:  d,
+		deploymentUpdates: nil,
+		destructive:       3,
+		desiredTGUpdates: map[string]*structs.DesiredUpdates{
+			job.TaskGroups[0].Name: {
+				DestructiveUpdate: 3,
+				Ignore:            7,
+			},
+		},
+	})
+
+	assertNamesHaveIndexes(t, intRange(0, 2), destructiveResultsToNames(r.destructiveUpdate))
+}
+
 // Tests the reconciler creates a deployment for inplace updates
 func TestReconciler_CreateDeployment_RollingUpgrade_Inplace(t *testing.T) {
 	jobOld := mock.Job()
@@ -4188,6 +4482,117 @@ func TestReconciler_MarkDeploymentComplete(t *testing.T) {
 	})
 }
 
+// Tests the reconciler marks the complete deployment of a multiregion job as
+// blocked until its peer regions complete
+func TestReconciler_MarkDeploymentComplete_Multiregion(t *testing.T) {
+	job := mock.Job()
+	job.TaskGroups[0].Update = noCanaryUpdate
+	job.Multiregion = &structs.Multiregion{
+		Regions: []*structs.MultiregionRegion{{Name: "global"}, {Name: "west"}},
+	}
+
+	d := structs.NewDeployment(job)
+	d.TaskGroups[job.TaskGroups[0].Name] = &structs.DeploymentState{
+		DesiredTotal:  10,
+		PlacedAllocs:  10,
+		HealthyAllocs: 10,
+	}
+
+	var allocs []*structs.Allocation
+	for i := 0; i < 10; i++ {
+		alloc := mock.Alloc()
+		alloc.Job = job
+		alloc.JobID = job.ID
+		alloc.NodeID = uuid.Generate()
+		alloc.Name = structs.AllocName(job.ID, job.TaskGroups[0].Name, uint(i))
+		alloc.TaskGroup = job.TaskGroups[0].Name
+		alloc.DeploymentID = d.ID
+		alloc.DeploymentStatus = &structs.AllocDeploymentStatus{
+			Healthy: helper.BoolToPtr(true),
+		}
+		allocs = append(allocs, alloc)
+	}
+
+	reconciler := NewAllocReconciler(testlog.HCLogger(t), allocUpdateFnIgnore, false, job.ID, job, d, allocs, nil, "")
+	r := reconciler.Compute()
+
+	updates := []*structs.DeploymentStatusUpdate{
+		{
+			DeploymentID:      d.ID,
+			Status:            structs.DeploymentStatusBlocked,
+			StatusDescription: structs.DeploymentStatusDescriptionBlocked,
+		},
+	}
+
+	assertResults(t, r, &resultExpectation{
+		createDeployment:  nil,
+		deploymentUpdates: updates,
+		desiredTGUpdates: map[string]*structs.DesiredUpdates{
+			job.TaskGroups[0].Name: {
+				Ignore: 10,
+			},
+		},
+	})
+
+	// A blocked deployment is not updated again
+	d.Status = structs.DeploymentStatusBlocked
+	reconciler = NewAllocReconciler(testlog.HCLogger(t), allocUpdateFnIgnore, false, job.ID, job, d, allocs, nil, "")
+	r = reconciler.Compute()
+	assertResults(t, r, &resultExpectation{
+		createDeployment: nil,
+		desiredTGUpdates: map[string]*structs.DesiredUpdates{
+			job.TaskGroups[0].Name: {
+				Ignore: 10,
+			},
+		},
+	})
+}
+
+// Tests the reconciler creates a pending deployment without placing anything
+// for a multiregion job waiting for the regions before it in the rollout
+func TestReconciler_Multiregion_PendingDeployment(t *testing.T) {
+	job := mock.Job()
+	job.TaskGroups[0].Update = noCanaryUpdate
+	job.Region = "west"
+	job.Multiregion = &structs.Multiregion{
+		Strategy: &structs.MultiregionStrategy{MaxParallel: 1},
+		Regions:  []*structs.MultiregionRegion{{Name: "east"}, {Name: "west"}},
+	}
+
+	reconciler := NewAllocReconciler(testlog.HCLogger(t), allocUpdateFnIgnore, false, job.ID, job, nil, nil, nil, "")
+	r := reconciler.Compute()
+
+	d := structs.NewDeployment(job)
+	d.TaskGroups[job.TaskGroups[0].Name] = &structs.DeploymentState{
+		DesiredTotal: 10,
+	}
+
+	assertResults(t, r, &resultExpectation{
+		createDeployment:  d,
+		deploymentUpdates: nil,
+		place:             0,
+		desiredTGUpdates: map[string]*structs.DesiredUpdates{
+			job.TaskGroups[0].Name: {},
+		},
+	})
+	require.Equal(t, structs.DeploymentStatusPending, r.deployment.Status)
+
+	// Once the deployment runs, the allocations are placed
+	d = r.deployment
+	d.Status = structs.DeploymentStatusRunning
+	reconciler = NewAllocReconciler(testlog.HCLogger(t), allocUpdateFnIgnore, false, job.ID, job, d, nil, nil, "")
+	r = reconciler.Compute()
+	assertResults(t, r, &resultExpectation{
+		createDeployment: nil,
+		place:            10,
+		desiredTGUpdates: map[string]*structs.DesiredUpdates{
+			job.TaskGroups[0].Name: {
+				Place: 10,
+			},
+		},
+	})
+}
+
 // Tests the reconciler handles changing a job such that a deployment is created
 // while doing a scale up but as the second eval.
 func TestReconciler_JobChange_ScaleUp_SecondEval(t *testing.T) {
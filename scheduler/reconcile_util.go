@@ -34,6 +34,9 @@ type placementResult interface {
 	// StopPreviousAlloc returns whether the previous allocation should be
 	// stopped and if so the status description.
 	StopPreviousAlloc() (bool, string)
+
+	// PreviousLost returns whether the previous allocation was lost
+	PreviousLost() bool
 }
 
 // allocStopResult contains the information required to stop a single allocation
@@ -51,6 +54,7 @@ type allocPlaceResult struct {
 	taskGroup     *structs.TaskGroup
 	previousAlloc *structs.Allocation
 	reschedule    bool
+	lost          bool
 }
 
 func (a allocPlaceResult) TaskGroup() *structs.TaskGroup           { return a.taskGroup }
@@ -59,6 +63,7 @@ func (a allocPlaceResult) Canary() bool                            { return a.ca
 func (a allocPlaceResult) PreviousAllocation() *structs.Allocation { return a.previousAlloc }
 func (a allocPlaceResult) IsRescheduling() bool                    { return a.reschedule }
 func (a allocPlaceResult) StopPreviousAlloc() (bool, string)       { return false, "" }
+func (a allocPlaceResult) PreviousLost() bool                      { return a.lost }
 
 // allocDestructiveResult contains the information required to do a destructive
 // update. Destructive changes should be applied atomically, as in the old alloc
@@ -78,6 +83,7 @@ func (a allocDestructiveResult) IsRescheduling() bool                    { retur
 func (a allocDestructiveResult) StopPreviousAlloc() (bool, string) {
 	return true, a.stopStatusDescription
 }
+func (a allocDestructiveResult) PreviousLost() bool { return false }
 
 // allocMatrix is a mapping of task groups to their allocation set.
 type allocMatrix map[string]allocSet
@@ -370,6 +376,21 @@ func (a allocSet) filterByDeployment(id string) (match, nonmatch allocSet) {
 	return
 }
 
+// filterByTaskHandles returns the allocations with tasks running on a remote
+// task driver, identified by the task handles reported by their clients.
+func (a allocSet) filterByTaskHandles() allocSet {
+	remote := make(map[string]*structs.Allocation)
+	for _, alloc := range a {
+		for _, state := range alloc.TaskStates {
+			if state.TaskHandle != nil {
+				remote[alloc.ID] = alloc
+				break
+			}
+		}
+	}
+	return remote
+}
+
 // allocNameIndex is used to select allocation names for placement or removal
 // given an existing set of placed allocations.
 type allocNameIndex struct {
@@ -21,9 +21,10 @@ const (
 // BuiltinSchedulers contains the built in registered schedulers
 // which are available
 var BuiltinSchedulers = map[string]Factory{
-	"service": NewServiceScheduler,
-	"batch":   NewBatchScheduler,
-	"system":  NewSystemScheduler,
+	"service":  NewServiceScheduler,
+	"batch":    NewBatchScheduler,
+	"system":   NewSystemScheduler,
+	"sysbatch": NewSysBatchScheduler,
 }
 
 // NewScheduler is used to instantiate and return a new scheduler
@@ -91,6 +92,19 @@ type State interface {
 
 	// SchedulerConfig returns config options for the scheduler
 	SchedulerConfig() (uint64, *structs.SchedulerConfiguration, error)
+
+	// NodePoolByName is used to lookup a node pool by name
+	NodePoolByName(ws memdb.WatchSet, name string) (*structs.NodePool, error)
+
+	// NamespaceByName is used to lookup a namespace by name
+	NamespaceByName(ws memdb.WatchSet, name string) (*structs.Namespace, error)
+
+	// QuotaSpecByName is used to lookup a quota specification by name
+	QuotaSpecByName(ws memdb.WatchSet, name string) (*structs.QuotaSpec, error)
+
+	// QuotaUsageByName returns the usage of the quota specification in the
+	// given region
+	QuotaUsageByName(ws memdb.WatchSet, region, name string) (*structs.QuotaUsage, error)
 }
 
 // Planner interface is used to submit a task allocation plan.
@@ -39,16 +39,19 @@ type SelectOptions struct {
 // GenericStack is the Stack used for the Generic scheduler. It is
 // designed to make better placement decisions at the cost of performance.
 type GenericStack struct {
-	batch  bool
-	ctx    Context
-	source *StaticIterator
-
-	wrappedChecks       *FeasibilityWrapper
-	quota               FeasibleIterator
-	jobConstraint       *ConstraintChecker
-	taskGroupDrivers    *DriverChecker
-	taskGroupConstraint *ConstraintChecker
-	taskGroupDevices    *DeviceChecker
+	batch       bool
+	ctx         Context
+	source      *StaticIterator
+	schedConfig *structs.SchedulerConfiguration
+
+	wrappedChecks        *FeasibilityWrapper
+	quota                FeasibleIterator
+	jobConstraint        *ConstraintChecker
+	taskGroupDrivers     *DriverChecker
+	taskGroupConstraint  *ConstraintChecker
+	taskGroupDevices     *DeviceChecker
+	taskGroupHostVolumes *HostVolumeChecker
+	taskGroupNetwork     *NetworkChecker
 
 	distinctHostsConstraint    *DistinctHostsIterator
 	distinctPropertyConstraint *DistinctPropertyIterator
@@ -91,13 +94,21 @@ func NewGenericStack(batch bool, ctx Context) *GenericStack {
 	// Filter on task group devices
 	s.taskGroupDevices = NewDeviceChecker(ctx)
 
+	// Filter on task group host volumes
+	s.taskGroupHostVolumes = NewHostVolumeChecker(ctx)
+	s.taskGroupNetwork = NewNetworkChecker(ctx)
+
 	// Create the feasibility wrapper which wraps all feasibility checks in
 	// which feasibility checking can be skipped if the computed node class has
 	// previously been marked as eligible or ineligible. Generally this will be
 	// checks that only needs to examine the single node to determine feasibility.
 	jobs := []FeasibilityChecker{s.jobConstraint}
-	tgs := []FeasibilityChecker{s.taskGroupDrivers, s.taskGroupConstraint, s.taskGroupDevices}
-	s.wrappedChecks = NewFeasibilityWrapper(ctx, s.quota, jobs, tgs)
+	tgs := []FeasibilityChecker{s.taskGroupDrivers, s.taskGroupConstraint, s.taskGroupDevices, s.taskGroupNetwork}
+
+	// The health of host volumes is not part of the computed node class, so
+	// their checks are run on every node
+	avail := []FeasibilityChecker{s.taskGroupHostVolumes}
+	s.wrappedChecks = NewFeasibilityWrapper(ctx, s.quota, jobs, tgs, avail)
 
 	// Filter on distinct host constraints.
 	s.distinctHostsConstraint = NewDistinctHostsIterator(ctx, s.wrappedChecks)
@@ -109,9 +120,19 @@ func NewGenericStack(batch bool, ctx Context) *GenericStack {
 	rankSource := NewFeasibleRankIterator(ctx, s.distinctPropertyConstraint)
 
 	// Apply the bin packing, this depends on the resources needed
-	// by a particular task group.
-
-	s.binPack = NewBinPackIterator(ctx, rankSource, false, 0)
+	// by a particular task group. Preemption of lower priority allocations
+	// is enabled per scheduler type by the scheduler configuration.
+	_, schedConfig, _ := ctx.State().SchedulerConfig()
+	s.schedConfig = schedConfig
+	enablePreemption := false
+	if schedConfig != nil {
+		if batch {
+			enablePreemption = schedConfig.PreemptionConfig.BatchSchedulerEnabled
+		} else {
+			enablePreemption = schedConfig.PreemptionConfig.ServiceSchedulerEnabled
+		}
+	}
+	s.binPack = NewBinPackIterator(ctx, rankSource, enablePreemption, 0)
 
 	// Apply the job anti-affinity iterator. This is to avoid placing
 	// multiple allocations on the same node for this job.
@@ -160,6 +181,7 @@ func (s *GenericStack) SetJob(job *structs.Job) {
 	s.distinctHostsConstraint.SetJob(job)
 	s.distinctPropertyConstraint.SetJob(job)
 	s.binPack.SetPriority(job.Priority)
+	s.binPack.SetSchedulerAlgorithm(schedulerAlgorithm(s.ctx, s.schedConfig, job))
 	s.jobAntiAff.SetJob(job)
 	s.nodeAffinity.SetJob(job)
 	s.spread.SetJob(job)
@@ -170,6 +192,26 @@ func (s *GenericStack) SetJob(job *structs.Job) {
 	}
 }
 
+// schedulerAlgorithm returns the algorithm used to score nodes for the job.
+// The node pool of the job may override the cluster wide algorithm.
+func schedulerAlgorithm(ctx Context, schedConfig *structs.SchedulerConfiguration, job *structs.Job) structs.SchedulerAlgorithm {
+	algorithm := schedConfig.EffectiveSchedulerAlgorithm()
+
+	poolName := job.NodePool
+	if poolName == "" {
+		poolName = structs.NodePoolDefault
+	}
+	pool, err := ctx.State().NodePoolByName(nil, poolName)
+	if err != nil {
+		ctx.Logger().Error("failed to lookup node pool", "node_pool", poolName, "error", err)
+		return algorithm
+	}
+	if pool != nil && pool.SchedulerAlgorithm != "" {
+		algorithm = pool.SchedulerAlgorithm
+	}
+	return algorithm
+}
+
 func (s *GenericStack) Select(tg *structs.TaskGroup, options *SelectOptions) *RankedNode {
 
 	// This block handles trying to select from preferred nodes if options specify them
@@ -199,6 +241,9 @@ func (s *GenericStack) Select(tg *structs.TaskGroup, options *SelectOptions) *Ra
 	s.taskGroupDrivers.SetDrivers(tgConstr.drivers)
 	s.taskGroupConstraint.SetConstraints(tgConstr.constraints)
 	s.taskGroupDevices.SetTaskGroup(tg)
+	s.taskGroupHostVolumes.SetVolumes(tg.Volumes)
+	s.taskGroupNetwork.SetNetworks(tg.Networks)
+	s.taskGroupNetwork.SetHostNetworks(tg.HostNetworks())
 	s.distinctHostsConstraint.SetTaskGroup(tg)
 	s.distinctPropertyConstraint.SetTaskGroup(tg)
 	s.wrappedChecks.SetTaskGroup(tg.Name)
@@ -232,12 +277,14 @@ type SystemStack struct {
 	ctx    Context
 	source *StaticIterator
 
-	wrappedChecks       *FeasibilityWrapper
-	quota               FeasibleIterator
-	jobConstraint       *ConstraintChecker
-	taskGroupDrivers    *DriverChecker
-	taskGroupConstraint *ConstraintChecker
-	taskGroupDevices    *DeviceChecker
+	wrappedChecks        *FeasibilityWrapper
+	quota                FeasibleIterator
+	jobConstraint        *ConstraintChecker
+	taskGroupDrivers     *DriverChecker
+	taskGroupConstraint  *ConstraintChecker
+	taskGroupDevices     *DeviceChecker
+	taskGroupHostVolumes *HostVolumeChecker
+	taskGroupNetwork     *NetworkChecker
 
 	distinctPropertyConstraint *DistinctPropertyIterator
 	binPack                    *BinPackIterator
@@ -269,13 +316,21 @@ func NewSystemStack(ctx Context) *SystemStack {
 	// Filter on task group devices
 	s.taskGroupDevices = NewDeviceChecker(ctx)
 
+	// Filter on task group host volumes
+	s.taskGroupHostVolumes = NewHostVolumeChecker(ctx)
+	s.taskGroupNetwork = NewNetworkChecker(ctx)
+
 	// Create the feasibility wrapper which wraps all feasibility checks in
 	// which feasibility checking can be skipped if the computed node class has
 	// previously been marked as eligible or ineligible. Generally this will be
 	// checks that only needs to examine the single node to determine feasibility.
 	jobs := []FeasibilityChecker{s.jobConstraint}
-	tgs := []FeasibilityChecker{s.taskGroupDrivers, s.taskGroupConstraint, s.taskGroupDevices}
-	s.wrappedChecks = NewFeasibilityWrapper(ctx, s.quota, jobs, tgs)
+	tgs := []FeasibilityChecker{s.taskGroupDrivers, s.taskGroupConstraint, s.taskGroupDevices, s.taskGroupNetwork}
+
+	// The health of host volumes is not part of the computed node class, so
+	// their checks are run on every node
+	avail := []FeasibilityChecker{s.taskGroupHostVolumes}
+	s.wrappedChecks = NewFeasibilityWrapper(ctx, s.quota, jobs, tgs, avail)
 
 	// Filter on distinct property constraints.
 	s.distinctPropertyConstraint = NewDistinctPropertyIterator(ctx, s.wrappedChecks)
@@ -327,6 +382,9 @@ func (s *SystemStack) Select(tg *structs.TaskGroup, options *SelectOptions) *Ran
 	s.taskGroupDrivers.SetDrivers(tgConstr.drivers)
 	s.taskGroupConstraint.SetConstraints(tgConstr.constraints)
 	s.taskGroupDevices.SetTaskGroup(tg)
+	s.taskGroupHostVolumes.SetVolumes(tg.Volumes)
+	s.taskGroupNetwork.SetNetworks(tg.Networks)
+	s.taskGroupNetwork.SetHostNetworks(tg.HostNetworks())
 	s.wrappedChecks.SetTaskGroup(tg.Name)
 	s.distinctPropertyConstraint.SetTaskGroup(tg)
 	s.binPack.SetTaskGroup(tg)
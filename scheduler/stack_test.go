@@ -96,6 +96,30 @@ func TestServiceStack_SetJob(t *testing.T) {
 	}
 }
 
+func TestServiceStack_SchedulerAlgorithm(t *testing.T) {
+	require := require.New(t)
+	state, ctx := testContext(t)
+
+	// The algorithm defaults to bin packing
+	job := mock.Job()
+	require.Equal(structs.SchedulerAlgorithmBinpack, schedulerAlgorithm(ctx, nil, job))
+
+	// The cluster wide algorithm is used when the pool has none
+	schedConfig := &structs.SchedulerConfiguration{
+		SchedulerAlgorithm: structs.SchedulerAlgorithmSpread,
+	}
+	require.Equal(structs.SchedulerAlgorithmSpread, schedulerAlgorithm(ctx, schedConfig, job))
+
+	// The node pool of the job overrides the cluster wide algorithm
+	pool := &structs.NodePool{
+		Name:               "batch",
+		SchedulerAlgorithm: structs.SchedulerAlgorithmBinpack,
+	}
+	require.NoError(state.UpsertNodePools(1000, []*structs.NodePool{pool}))
+	job.NodePool = pool.Name
+	require.Equal(structs.SchedulerAlgorithmBinpack, schedulerAlgorithm(ctx, schedConfig, job))
+}
+
 func TestServiceStack_Select_Size(t *testing.T) {
 	_, ctx := testContext(t)
 	nodes := []*structs.Node{
@@ -16,12 +16,14 @@ const (
 	maxSystemScheduleAttempts = 5
 )
 
-// SystemScheduler is used for 'system' jobs. This scheduler is
-// designed for services that should be run on every client.
+// SystemScheduler is used for 'system' and 'sysbatch' jobs. This scheduler is
+// designed for services that should be run on every client and for batch
+// work that should run to completion once on every client.
 type SystemScheduler struct {
-	logger  log.Logger
-	state   State
-	planner Planner
+	logger   log.Logger
+	state    State
+	planner  Planner
+	sysbatch bool
 
 	eval       *structs.Evaluation
 	job        *structs.Job
@@ -49,6 +51,17 @@ func NewSystemScheduler(logger log.Logger, state State, planner Planner) Schedul
 	}
 }
 
+// NewSysBatchScheduler is a factory function to instantiate a new sysbatch
+// scheduler.
+func NewSysBatchScheduler(logger log.Logger, state State, planner Planner) Scheduler {
+	return &SystemScheduler{
+		logger:   logger.Named("sysbatch_sched"),
+		state:    state,
+		planner:  planner,
+		sysbatch: true,
+	}
+}
+
 // Process is used to handle a single evaluation.
 func (s *SystemScheduler) Process(eval *structs.Evaluation) error {
 	// Store the evaluation
@@ -62,6 +75,11 @@ func (s *SystemScheduler) Process(eval *structs.Evaluation) error {
 	case structs.EvalTriggerJobRegister, structs.EvalTriggerNodeUpdate, structs.EvalTriggerFailedFollowUp,
 		structs.EvalTriggerJobDeregister, structs.EvalTriggerRollingUpdate, structs.EvalTriggerPreemption,
 		structs.EvalTriggerDeploymentWatcher, structs.EvalTriggerNodeDrain:
+	case structs.EvalTriggerPeriodicJob:
+		if s.sysbatch {
+			break
+		}
+		fallthrough
 	default:
 		desc := fmt.Sprintf("scheduler cannot handle '%s' evaluation reason",
 			eval.TriggeredBy)
@@ -103,7 +121,7 @@ func (s *SystemScheduler) process() (bool, error) {
 
 	// Get the ready nodes in the required datacenters
 	if !s.job.Stopped() {
-		s.nodes, s.nodesByDC, err = readyNodesInDCs(s.state, s.job.Datacenters)
+		s.nodes, s.nodesByDC, err = readyNodesInDCs(s.state, s.job.Datacenters, s.job.NodePool)
 		if err != nil {
 			return false, fmt.Errorf("failed to get ready nodes: %v", err)
 		}
@@ -198,11 +216,21 @@ func (s *SystemScheduler) computeJobAllocs() error {
 	// nodes to lost
 	updateNonTerminalAllocsToLost(s.plan, tainted, allocs)
 
+	// Index the allocations that already ran on each node before the
+	// terminal allocations are filtered out
+	var completed map[string]map[string]*structs.Allocation
+	if s.sysbatch {
+		completed = completedAllocsByNode(s.job, allocs)
+	}
+
 	// Filter out the allocations in a terminal state
 	allocs, terminalAllocs := structs.FilterTerminalAllocs(allocs)
 
 	// Diff the required and existing allocations
 	diff := diffSystemAllocs(s.job, s.nodes, tainted, allocs, terminalAllocs)
+	if s.sysbatch {
+		ignoreCompletedPlacements(diff, completed)
+	}
 	s.logger.Debug("reconciled current state with desired state",
 		"place", len(diff.place), "update", len(diff.update),
 		"migrate", len(diff.migrate), "stop", len(diff.stop),
@@ -317,7 +345,8 @@ func (s *SystemScheduler) computePlacements(place []allocTuple) error {
 			resources := &structs.AllocatedResources{
 				Tasks: option.TaskResources,
 				Shared: structs.AllocatedSharedResources{
-					DiskMB: int64(missing.TaskGroup.EphemeralDisk.SizeMB),
+					DiskMB:   int64(missing.TaskGroup.EphemeralDisk.SizeMB),
+					Networks: option.AllocResources.Networks,
 				},
 			}
 
@@ -378,3 +407,49 @@ func (s *SystemScheduler) computePlacements(place []allocTuple) error {
 
 	return nil
 }
+
+// completedAllocsByNode returns the terminal allocations of the current
+// version of the job that ran on the client, indexed by node and name. Lost
+// allocations are not considered to have run.
+func completedAllocsByNode(job *structs.Job, allocs []*structs.Allocation) map[string]map[string]*structs.Allocation {
+	completed := make(map[string]map[string]*structs.Allocation)
+	for _, alloc := range allocs {
+		if !alloc.TerminalStatus() || alloc.Job.JobModifyIndex != job.JobModifyIndex {
+			continue
+		}
+
+		switch alloc.ClientStatus {
+		case structs.AllocClientStatusComplete, structs.AllocClientStatusFailed:
+		default:
+			continue
+		}
+
+		byName, ok := completed[alloc.NodeID]
+		if !ok {
+			byName = make(map[string]*structs.Allocation)
+			completed[alloc.NodeID] = byName
+		}
+		byName[alloc.Name] = alloc
+	}
+	return completed
+}
+
+// ignoreCompletedPlacements moves the placements on nodes where the task group
+// already ran for the current version of the job to the ignored set. Sysbatch
+// allocations run once on every node and only run again once the job is
+// updated.
+func ignoreCompletedPlacements(diff *diffResult, completed map[string]map[string]*structs.Allocation) {
+	place := diff.place[:0]
+	for _, tuple := range diff.place {
+		if alloc, ok := completed[tuple.Alloc.NodeID][tuple.Name]; ok {
+			diff.ignore = append(diff.ignore, allocTuple{
+				Name:      tuple.Name,
+				TaskGroup: tuple.TaskGroup,
+				Alloc:     alloc,
+			})
+			continue
+		}
+		place = append(place, tuple)
+	}
+	diff.place = place
+}
@@ -1884,3 +1884,104 @@ func TestSystemSched_Preemption(t *testing.T) {
 	h.AssertEvalStatus(t, structs.EvalStatusComplete)
 
 }
+
+func TestSysBatchSched_JobRegister(t *testing.T) {
+	require := require.New(t)
+	h := NewHarness(t)
+
+	// Create some nodes
+	for i := 0; i < 10; i++ {
+		node := mock.Node()
+		require.NoError(h.State.UpsertNode(h.NextIndex(), node))
+	}
+
+	// Create a job
+	job := mock.SysBatchJob()
+	require.NoError(h.State.UpsertJob(h.NextIndex(), job))
+
+	// Create a mock evaluation as if the periodic dispatcher launched the job
+	eval := &structs.Evaluation{
+		Namespace:   structs.DefaultNamespace,
+		ID:          uuid.Generate(),
+		Priority:    job.Priority,
+		TriggeredBy: structs.EvalTriggerPeriodicJob,
+		JobID:       job.ID,
+		Status:      structs.EvalStatusPending,
+	}
+	require.NoError(h.State.UpsertEvals(h.NextIndex(), []*structs.Evaluation{eval}))
+
+	// Process the evaluation
+	require.NoError(h.Process(NewSysBatchScheduler, eval))
+
+	// Ensure a single plan that places on every node
+	require.Len(h.Plans, 1)
+	require.Len(h.Plans[0].NodeAllocation, 10)
+
+	h.AssertEvalStatus(t, structs.EvalStatusComplete)
+}
+
+func TestSysBatchSched_CompletedAllocs(t *testing.T) {
+	require := require.New(t)
+	h := NewHarness(t)
+
+	// Create some nodes
+	var nodes []*structs.Node
+	for i := 0; i < 4; i++ {
+		node := mock.Node()
+		nodes = append(nodes, node)
+		require.NoError(h.State.UpsertNode(h.NextIndex(), node))
+	}
+
+	// Generate a fake job with allocations that completed, failed, were lost
+	// and ran an older version of the job on the first three nodes
+	job := mock.SysBatchJob()
+	require.NoError(h.State.UpsertJob(h.NextIndex(), job))
+
+	oldJob := job.Copy()
+	oldJob.JobModifyIndex--
+
+	var allocs []*structs.Allocation
+	for i, status := range []string{
+		structs.AllocClientStatusComplete,
+		structs.AllocClientStatusFailed,
+		structs.AllocClientStatusLost,
+		structs.AllocClientStatusComplete,
+	} {
+		alloc := mock.Alloc()
+		alloc.Job = job
+		alloc.JobID = job.ID
+		alloc.NodeID = nodes[i].ID
+		alloc.Name = "my-job.web[0]"
+		alloc.ClientStatus = status
+		if i == 3 {
+			alloc.Job = oldJob
+		}
+		allocs = append(allocs, alloc)
+	}
+	require.NoError(h.State.UpsertAllocs(h.NextIndex(), allocs))
+
+	// Create a mock evaluation to deal with a node update
+	eval := &structs.Evaluation{
+		Namespace:   structs.DefaultNamespace,
+		ID:          uuid.Generate(),
+		Priority:    50,
+		TriggeredBy: structs.EvalTriggerNodeUpdate,
+		JobID:       job.ID,
+		Status:      structs.EvalStatusPending,
+	}
+	require.NoError(h.State.UpsertEvals(h.NextIndex(), []*structs.Evaluation{eval}))
+
+	// Process the evaluation
+	require.NoError(h.Process(NewSysBatchScheduler, eval))
+
+	// Ensure the lost allocation and the allocation of the old job version
+	// are replaced while the ones that ran are left alone
+	require.Len(h.Plans, 1)
+	plan := h.Plans[0]
+	require.Empty(plan.NodeUpdate)
+	require.Len(plan.NodeAllocation, 2)
+	require.Contains(plan.NodeAllocation, nodes[2].ID)
+	require.Contains(plan.NodeAllocation, nodes[3].ID)
+
+	h.AssertEvalStatus(t, structs.EvalStatusComplete)
+}
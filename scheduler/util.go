@@ -4,10 +4,12 @@ import (
 	"fmt"
 	"math/rand"
 	"reflect"
+	"strings"
 
 	log "github.com/hashicorp/go-hclog"
 	memdb "github.com/hashicorp/go-memdb"
 	"github.com/hashicorp/nomad/nomad/structs"
+	glob "github.com/ryanuber/go-glob"
 )
 
 // allocTuple is a tuple of the allocation name and potential alloc ID
@@ -104,12 +106,12 @@ func diffAllocs(job *structs.Job, taintedNodes map[string]*structs.Node,
 		// If we are on a tainted node, we must migrate if we are a service or
 		// if the batch allocation did not finish
 		if node, ok := taintedNodes[exist.NodeID]; ok {
-			// If the job is batch and finished successfully, the fact that the
-			// node is tainted does not mean it should be migrated or marked as
-			// lost as the work was already successfully finished. However for
-			// service/system jobs, tasks should never complete. The check of
-			// batch type, defends against client bugs.
-			if exist.Job.Type == structs.JobTypeBatch && exist.RanSuccessfully() {
+			// If the job is batch or sysbatch and finished successfully, the
+			// fact that the node is tainted does not mean it should be migrated
+			// or marked as lost as the work was already successfully finished.
+			// However for service/system jobs, tasks should never complete. The
+			// check of batch type, defends against client bugs.
+			if (exist.Job.Type == structs.JobTypeBatch || exist.Job.Type == structs.JobTypeSysBatch) && exist.RanSuccessfully() {
 				goto IGNORE
 			}
 
@@ -219,12 +221,20 @@ func diffSystemAllocs(job *structs.Job, nodes []*structs.Node, taintedNodes map[
 	return result
 }
 
-// readyNodesInDCs returns all the ready nodes in the given datacenters and a
-// mapping of each data center to the count of ready nodes.
-func readyNodesInDCs(state State, dcs []string) ([]*structs.Node, map[string]int, error) {
-	// Index the DCs
+// readyNodesInDCs returns all the ready nodes of the given node pool in the
+// given datacenters and a mapping of each data center to the count of ready
+// nodes.
+func readyNodesInDCs(state State, dcs []string, pool string) ([]*structs.Node, map[string]int, error) {
+	// Index the DCs. Datacenters containing a wildcard are matched against
+	// the datacenter of each node instead, so only the datacenters with
+	// ready nodes are reported for them.
 	dcMap := make(map[string]int, len(dcs))
+	var dcGlobs []string
 	for _, dc := range dcs {
+		if strings.Contains(dc, glob.GLOB) {
+			dcGlobs = append(dcGlobs, dc)
+			continue
+		}
 		dcMap[dc] = 0
 	}
 
@@ -252,7 +262,10 @@ func readyNodesInDCs(state State, dcs []string) ([]*structs.Node, map[string]int
 		if node.SchedulingEligibility != structs.NodeSchedulingEligible {
 			continue
 		}
-		if _, ok := dcMap[node.Datacenter]; !ok {
+		if _, ok := dcMap[node.Datacenter]; !ok && !matchesAnyGlob(node.Datacenter, dcGlobs) {
+			continue
+		}
+		if !inNodePool(node, pool) {
 			continue
 		}
 		out = append(out, node)
@@ -261,6 +274,30 @@ func readyNodesInDCs(state State, dcs []string) ([]*structs.Node, map[string]int
 	return out, dcMap, nil
 }
 
+// matchesAnyGlob returns whether the datacenter matches any of the given
+// wildcard patterns.
+func matchesAnyGlob(dc string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if glob.Glob(pattern, dc) {
+			return true
+		}
+	}
+	return false
+}
+
+// inNodePool returns whether the node is in the given node pool. Nodes and
+// jobs that predate node pools are in the default node pool.
+func inNodePool(node *structs.Node, pool string) bool {
+	if pool == "" {
+		pool = structs.NodePoolDefault
+	}
+	nodePool := node.NodePool
+	if nodePool == "" {
+		nodePool = structs.NodePoolDefault
+	}
+	return nodePool == pool
+}
+
 // retryMax is used to retry a callback until it returns success or
 // a maximum number of attempts is reached. An optional reset function may be
 // passed which is called after each failed iteration. If the reset function is
@@ -351,6 +388,16 @@ func tasksUpdated(jobA, jobB *structs.Job, taskGroup string) bool {
 		return true
 	}
 
+	// Check the volumes
+	if !reflect.DeepEqual(a.Volumes, b.Volumes) {
+		return true
+	}
+
+	// Check the group network
+	if !reflect.DeepEqual(a.Networks, b.Networks) {
+		return true
+	}
+
 	// Check each task
 	for _, at := range a.Tasks {
 		bt := b.LookupTask(at.Name)
@@ -411,21 +458,39 @@ func tasksUpdated(jobA, jobB *structs.Job, taskGroup string) bool {
 			return true
 		} else if ar.IOPS != br.IOPS {
 			return true
+		} else if ar.IOWeight != br.IOWeight {
+			return true
+		} else if !reflect.DeepEqual(ar.IOLimits, br.IOLimits) {
+			return true
+		} else if ar.CPUHardLimit != br.CPUHardLimit || ar.CPUCFSPeriod != br.CPUCFSPeriod {
+			return true
+		} else if ar.Cores != br.Cores {
+			return true
+		} else if !reflect.DeepEqual(ar.NUMA, br.NUMA) {
+			return true
 		}
 	}
 	return false
 }
 
+// networkPort is the value and host network of a port, as compared by
+// networkPortMap.
+type networkPort struct {
+	Value       int
+	HostNetwork string
+}
+
 // networkPortMap takes a network resource and returns a map of port labels to
-// values. The value for dynamic ports is disregarded even if it is set. This
-// makes this function suitable for comparing two network resources for changes.
-func networkPortMap(n *structs.NetworkResource) map[string]int {
-	m := make(map[string]int, len(n.DynamicPorts)+len(n.ReservedPorts))
+// values and host networks. The value for dynamic ports is disregarded even if
+// it is set. This makes this function suitable for comparing two network
+// resources for changes.
+func networkPortMap(n *structs.NetworkResource) map[string]networkPort {
+	m := make(map[string]networkPort, len(n.DynamicPorts)+len(n.ReservedPorts))
 	for _, p := range n.ReservedPorts {
-		m[p.Label] = p.Value
+		m[p.Label] = networkPort{p.Value, p.HostNetwork}
 	}
 	for _, p := range n.DynamicPorts {
-		m[p.Label] = -1
+		m[p.Label] = networkPort{-1, p.HostNetwork}
 	}
 	return m
 }
@@ -541,6 +606,11 @@ func inplaceUpdate(ctx Context, eval *structs.Evaluation, job *structs.Job,
 			resources.Networks = networks
 		}
 
+		// Restore the group networks the same way
+		if update.Alloc.AllocatedResources != nil {
+			option.AllocResources.Networks = update.Alloc.AllocatedResources.Shared.Networks
+		}
+
 		// Create a shallow copy
 		newAlloc := new(structs.Allocation)
 		*newAlloc = *update.Alloc
@@ -552,7 +622,8 @@ func inplaceUpdate(ctx Context, eval *structs.Evaluation, job *structs.Job,
 		newAlloc.AllocatedResources = &structs.AllocatedResources{
 			Tasks: option.TaskResources,
 			Shared: structs.AllocatedSharedResources{
-				DiskMB: int64(update.TaskGroup.EphemeralDisk.SizeMB),
+				DiskMB:   int64(update.TaskGroup.EphemeralDisk.SizeMB),
+				Networks: option.AllocResources.Networks,
 			},
 		}
 		newAlloc.Metrics = ctx.Metrics()
@@ -817,6 +888,11 @@ func genericAllocUpdateFn(ctx Context, stack Stack, evalID string) allocUpdateTy
 			resources.Networks = networks
 		}
 
+		// Restore the group networks the same way
+		if existing.AllocatedResources != nil {
+			option.AllocResources.Networks = existing.AllocatedResources.Shared.Networks
+		}
+
 		// Create a shallow copy
 		newAlloc := new(structs.Allocation)
 		*newAlloc = *existing
@@ -828,7 +904,8 @@ func genericAllocUpdateFn(ctx Context, stack Stack, evalID string) allocUpdateTy
 		newAlloc.AllocatedResources = &structs.AllocatedResources{
 			Tasks: option.TaskResources,
 			Shared: structs.AllocatedSharedResources{
-				DiskMB: int64(newTG.EphemeralDisk.SizeMB),
+				DiskMB:   int64(newTG.EphemeralDisk.SizeMB),
+				Networks: option.AllocResources.Networks,
 			},
 		}
 		newAlloc.Metrics = ctx.Metrics()
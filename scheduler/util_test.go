@@ -306,13 +306,16 @@ func TestReadyNodesInDCs(t *testing.T) {
 	node3.Status = structs.NodeStatusDown
 	node4 := mock.Node()
 	node4.Drain = true
+	node5 := mock.Node()
+	node5.NodePool = "gpu"
 
 	noErr(t, state.UpsertNode(1000, node1))
 	noErr(t, state.UpsertNode(1001, node2))
 	noErr(t, state.UpsertNode(1002, node3))
 	noErr(t, state.UpsertNode(1003, node4))
+	noErr(t, state.UpsertNode(1004, node5))
 
-	nodes, dc, err := readyNodesInDCs(state, []string{"dc1", "dc2"})
+	nodes, dc, err := readyNodesInDCs(state, []string{"dc1", "dc2"}, "")
 	if err != nil {
 		t.Fatalf("err: %v", err)
 	}
@@ -329,6 +332,36 @@ func TestReadyNodesInDCs(t *testing.T) {
 	if count, ok := dc["dc2"]; !ok || count != 1 {
 		t.Fatalf("Bad: dc2 count %v", count)
 	}
+
+	// Only nodes of the node pool are returned
+	nodes, dc, err = readyNodesInDCs(state, []string{"dc1", "dc2"}, "gpu")
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if len(nodes) != 1 || nodes[0].ID != node5.ID {
+		t.Fatalf("bad: %v", nodes)
+	}
+	if count, ok := dc["dc1"]; !ok || count != 1 {
+		t.Fatalf("Bad: dc1 count %v", count)
+	}
+
+	// Wildcards match the datacenter of the nodes
+	nodes, dc, err = readyNodesInDCs(state, []string{"dc*", "us-*"}, "")
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if len(nodes) != 2 {
+		t.Fatalf("bad: %v", nodes)
+	}
+	if count, ok := dc["dc1"]; !ok || count != 1 {
+		t.Fatalf("Bad: dc1 count %v", count)
+	}
+	if count, ok := dc["dc2"]; !ok || count != 1 {
+		t.Fatalf("Bad: dc2 count %v", count)
+	}
+	if _, ok := dc["us-*"]; ok {
+		t.Fatalf("Bad: wildcard should not be counted: %v", dc)
+	}
 }
 
 func TestRetryMax(t *testing.T) {
@@ -570,6 +603,56 @@ func TestTasksUpdated(t *testing.T) {
 	if !tasksUpdated(j1, j18, name) {
 		t.Fatal("bad")
 	}
+
+	// Change block I/O resources
+	j19 := mock.Job()
+	j19.TaskGroups[0].Tasks[0].Resources.IOWeight = 500
+	if !tasksUpdated(j1, j19, name) {
+		t.Fatal("bad")
+	}
+
+	j20 := mock.Job()
+	j20.TaskGroups[0].Tasks[0].Resources.IOLimits = []*structs.IOLimit{
+		{Device: "/dev/sda", ReadBps: 1024},
+	}
+	if !tasksUpdated(j1, j20, name) {
+		t.Fatal("bad")
+	}
+
+	// Change CPU bandwidth limits
+	j21 := mock.Job()
+	j21.TaskGroups[0].Tasks[0].Resources.CPUHardLimit = true
+	if !tasksUpdated(j1, j21, name) {
+		t.Fatal("bad")
+	}
+
+	j22 := mock.Job()
+	j22.TaskGroups[0].Tasks[0].Resources.CPUHardLimit = true
+	j22.TaskGroups[0].Tasks[0].Resources.CPUCFSPeriod = 500000
+	if !tasksUpdated(j21, j22, name) {
+		t.Fatal("bad")
+	}
+
+	// Change reserved cores
+	j23 := mock.Job()
+	j23.TaskGroups[0].Tasks[0].Resources.Cores = 2
+	if !tasksUpdated(j1, j23, name) {
+		t.Fatal("bad")
+	}
+
+	// Change NUMA affinity
+	j24 := mock.Job()
+	j24.TaskGroups[0].Tasks[0].Resources.NUMA = &structs.NUMA{Affinity: structs.NUMAAffinityRequire}
+	if !tasksUpdated(j1, j24, name) {
+		t.Fatal("bad")
+	}
+
+	// Change the host network of a task port
+	j25 := mock.Job()
+	j25.TaskGroups[0].Tasks[0].Resources.Networks[0].DynamicPorts[0].HostNetwork = "public"
+	if !tasksUpdated(j1, j25, name) {
+		t.Fatal("bad")
+	}
 }
 
 func TestEvictAndPlace_LimitLessThanAllocs(t *testing.T) {